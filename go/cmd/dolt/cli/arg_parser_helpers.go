@@ -74,6 +74,7 @@ func CreateCommitArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(AllFlag, "a", "Adds all existing, changed tables (but not new tables) in the working set to the staged set.")
 	ap.SupportsFlag(UpperCaseAllFlag, "A", "Adds all tables (including new tables) in the working set to the staged set.")
 	ap.SupportsFlag(AmendFlag, "", "Amend previous commit")
+	ap.SupportsFlag(GpgSignFlag, "S", "Sign the commit with the key configured by {{.EmphasisLeft}}user.signingkey{{.EmphasisRight}}.")
 	return ap
 }
 
@@ -81,14 +82,12 @@ func CreateConflictsResolveArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithVariableArgs("conflicts resolve")
 	ap.SupportsFlag(OursFlag, "", "For all conflicts, take the version from our branch and resolve the conflict")
 	ap.SupportsFlag(TheirsFlag, "", "For all conflicts, take the version from their branch and resolve the conflict")
+	ap.SupportsFlag(CellMergeFlag, "", "For all conflicts, merge the columns changed on each branch, and resolve the conflicts where no column was changed on both")
 	return ap
 }
 
 func CreateMergeArgParser() *argparser.ArgParser {
-	ap := argparser.NewArgParserWithMaxArgs("merge", 1)
-	ap.TooManyArgsErrorFunc = func(receivedArgs []string) error {
-		return fmt.Errorf("Error: Dolt does not support merging from multiple commits. You probably meant to checkout one and then merge from the other.")
-	}
+	ap := argparser.NewArgParserWithVariableArgs("merge")
 	ap.SupportsFlag(NoFFParam, "", "Create a merge commit even when the merge resolves as a fast-forward.")
 	ap.SupportsFlag(SquashParam, "", "Merge changes to the working set without updating the commit history")
 	ap.SupportsString(MessageArg, "m", "msg", "Use the given {{.LessThan}}msg{{.GreaterThan}} as the commit message.")
@@ -97,6 +96,8 @@ func CreateMergeArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(NoCommitFlag, "", "Perform the merge and stop just before creating a merge commit. Note this will not prevent a fast-forward merge; use the --no-ff arg together with the --no-commit arg to prevent both fast-forwards and merge commits.")
 	ap.SupportsFlag(NoEditFlag, "", "Use an auto-generated commit message when creating a merge commit. The default for interactive CLI sessions is to open an editor.")
 	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	ap.SupportsFlag(NoRenamesFlag, "", "Merge tables and columns renamed on one side of the merge as if they were dropped and added, rather than matching them to their old names on the other side.")
+	ap.SupportsFlag(AutostashFlag, "", "Stash any uncommitted changes, including untracked tables, before merging, and apply them again once the merge is done. If applying them conflicts with the merged changes, or the merge itself has conflicts, they're kept in the stash.")
 
 	return ap
 }
@@ -105,6 +106,8 @@ func CreatePushArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs("push", 2)
 	ap.SupportsFlag(SetUpstreamFlag, "u", "For every branch that is up to date or successfully pushed, add upstream (tracking) reference, used by argument-less {{.EmphasisLeft}}dolt pull{{.EmphasisRight}} and other commands.")
 	ap.SupportsFlag(ForceFlag, "f", "Update the remote with local history, overwriting any conflicting history in the remote.")
+	ap.SupportsFlag(ForceWithLeaseFlag, "", "Like {{.EmphasisLeft}}--force{{.EmphasisRight}}, but only overwrite the remote branch if it still points at the commit of its remote tracking branch, so that history pushed by others since the last fetch isn't lost.")
+	ap.SupportsFlag(DeleteFlag, "d", "Delete the named branch or tag from the remote.")
 	return ap
 }
 
@@ -121,6 +124,8 @@ func CreateCloneArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs("clone", 2)
 	ap.SupportsString(RemoteParam, "", "name", "Name of the remote to be added to the cloned database. The default is 'origin'.")
 	ap.SupportsString(BranchParam, "b", "branch", "The branch to be cloned. If not specified all branches will be cloned.")
+	ap.SupportsInt(DepthParam, "", "depth", "Create a shallow clone of only the branch being cloned, with its history truncated to the specified number of commits.")
+	ap.SupportsStringList(TablesFlag, "", "tables", "Create a sparse clone holding the data of only the specified tables. The data of the other tables is fetched from the remote the first time it's read.")
 	ap.SupportsString(dbfactory.AWSRegionParam, "", "region", "")
 	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, dbfactory.AWSCredTypes))
 	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file.")
@@ -149,6 +154,12 @@ func CreateCleanArgParser() *argparser.ArgParser {
 	return ap
 }
 
+func CreateCopyTableArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("copy_table", 2)
+	ap.SupportsFlag(ForceFlag, "f", "If the table already exists on the destination branch, the force flag will allow it to be overwritten.")
+	return ap
+}
+
 func CreateCheckoutArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithVariableArgs("checkout")
 	ap.SupportsString(CheckoutCoBranch, "", "branch", "Create a new branch named {{.LessThan}}new_branch{{.GreaterThan}} and start it at {{.LessThan}}start_point{{.GreaterThan}}.")
@@ -158,11 +169,10 @@ func CreateCheckoutArgParser() *argparser.ArgParser {
 }
 
 func CreateCherryPickArgParser() *argparser.ArgParser {
-	ap := argparser.NewArgParserWithMaxArgs("cherrypick", 1)
+	ap := argparser.NewArgParserWithVariableArgs("cherrypick")
 	ap.SupportsFlag(AbortParam, "", "Abort the current conflict resolution process, and revert all changes from the in-process cherry-pick operation.")
-	ap.TooManyArgsErrorFunc = func(receivedArgs []string) error {
-		return fmt.Errorf("cherry-picking multiple commits is not supported yet.")
-	}
+	ap.SupportsFlag(ContinueFlag, "", "Commit the resolution of the conflicts of the commit the cherry-pick stopped at, and cherry-pick the rest of the commits.")
+	ap.SupportsFlag(SkipFlag, "", "Leave out the commit the cherry-pick stopped at, and cherry-pick the rest of the commits.")
 	return ap
 }
 
@@ -170,6 +180,7 @@ func CreateFetchArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithVariableArgs("fetch")
 	ap.SupportsString(UserFlag, "u", "user", "User name to use when authenticating with the remote. Gets password from the environment variable {{.EmphasisLeft}}DOLT_REMOTE_PASSWORD{{.EmphasisRight}}.")
 	ap.SupportsFlag(PruneFlag, "p", "After fetching, remove any remote-tracking references that don't exist on the remote.")
+	ap.SupportsInt(DeepenParam, "", "depth", "Fetch the specified number of additional commits of history into a shallow clone.")
 	return ap
 }
 
@@ -196,6 +207,15 @@ func CreatePullArgParser() *argparser.ArgParser {
 	return ap
 }
 
+func CreateSyncArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("sync", 2)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"remote", "The name of the remote to sync with."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"remoteBranch", "The name of the branch on the specified remote that the current branch is reset to. Defaults to the current branch's upstream."})
+	ap.SupportsFlag(ForceFlag, "f", "Discard any uncommitted changes to the tables of the current branch. Untracked tables are kept, as they are by {{.EmphasisLeft}}dolt reset --hard{{.EmphasisRight}}.")
+	ap.SupportsFlag(StashFlag, "", "Stash any uncommitted changes to the current branch, including untracked tables, before resetting it.")
+	return ap
+}
+
 func createTracklessBranchArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithVariableArgs("branch")
 	ap.SupportsFlag(ForceFlag, "f", branchForceFlagDesc)
@@ -203,6 +223,9 @@ func createTracklessBranchArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(MoveFlag, "m", "Move/rename a branch")
 	ap.SupportsFlag(DeleteFlag, "d", "Delete a branch. The branch must be fully merged in its upstream branch.")
 	ap.SupportsFlag(DeleteForceFlag, "", "Shortcut for {{.EmphasisLeft}}--delete --force{{.EmphasisRight}}.")
+	ap.SupportsString(ExpiresParam, "", "duration", "When creating a new branch, delete it once it is older than {{.LessThan}}duration{{.GreaterThan}}, e.g. 12h, 30d, or 2w.")
+	ap.SupportsString(DescribeParam, "", "description", "Set the description of an existing branch.")
+	ap.SupportsString(MetadataParam, "", "key=value", "Set comma separated metadata values on an existing branch. A key with an empty value is removed.")
 
 	return ap
 }
@@ -227,7 +250,58 @@ func CreateTagArgParser() *argparser.ArgParser {
 	ap.SupportsString(MessageArg, "m", "msg", "Use the given {{.LessThan}}msg{{.GreaterThan}} as the tag message.")
 	ap.SupportsFlag(VerboseFlag, "v", "list tags along with their metadata.")
 	ap.SupportsFlag(DeleteFlag, "d", "Delete a tag.")
+	ap.SupportsFlag(ForceFlag, "f", "When deleting a tag from a sql-server, delete it even if it matches {{.EmphasisLeft}}@@dolt_protected_tags{{.EmphasisRight}}. Requires the SUPER privilege.")
 	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	ap.SupportsFlag(SignFlag, "s", "Sign the tag with the key configured by {{.EmphasisLeft}}user.signingkey{{.EmphasisRight}}.")
+	return ap
+}
+
+func CreateApplyPatchArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("apply", 2)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"from_revision", "The revision the patch starts from."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"to_revision", "The revision the patch ends at."})
+	ap.SupportsString(BranchParam, "b", "branch", "Apply the patch to {{.LessThan}}branch{{.GreaterThan}} instead of the current branch.")
+	ap.SupportsString(MessageArg, "m", "msg", "Use the given {{.LessThan}}msg{{.GreaterThan}} as the commit message.")
+	return ap
+}
+
+func CreateProposalArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("proposal")
+	ap.SupportsString(MessageArg, "m", "msg", "Use the given {{.LessThan}}msg{{.GreaterThan}} as the proposal's description, or as the comment.")
+	ap.SupportsStringList(ReviewersParam, "", "users", "Comma separated list of users whose approval is needed before the proposal can be merged.")
+	return ap
+}
+
+func CreateRebaseArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("rebase", 1)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"upstream", "The commit to rebase the current branch onto."})
+	ap.SupportsFlag(InteractiveFlag, "i", "Review and edit the plan of the rebase, as a todo list, before starting it.")
+	ap.SupportsString(EditTodoParam, "", "todo", "Replace the steps of the rebase that haven't been replayed yet with the given todo list.")
+	ap.SupportsFlag(ContinueFlag, "", "Start an interactive rebase, or continue a rebase that stopped with conflicts once they are resolved.")
+	ap.SupportsFlag(AbortParam, "", "Abort the rebase, and reset the branch to where it was before the rebase started.")
+	return ap
+}
+
+func CreateBisectArgParser() *argparser.ArgParser {
+	return argparser.NewArgParserWithVariableArgs("bisect")
+}
+
+func CreateMaskArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("mask", 3)
+	ap.SupportsFlag(DeleteFlag, "d", "Remove the mask from the column.")
+	return ap
+}
+
+func CreateVectorIndexArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("vector_index", 3)
+	ap.SupportsFlag(DeleteFlag, "d", "Drop the vector index on the column.")
+	return ap
+}
+
+func CreateImportSchemaArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("import_schema", 1)
+	ap.SupportsFlag(PruneFlag, "", "Drop tables, views, triggers and procedures that are not in the schema bundle.")
+	ap.SupportsFlag(DryRunFlag, "", "Count the changes the schema bundle would make without modifying the working set.")
 	return ap
 }
 
@@ -260,8 +334,16 @@ func CreateLogArgParser(isTableFunction bool) *argparser.ArgParser {
 	ap.SupportsFlag(ParentsFlag, "", "Shows all parents of each commit in the log.")
 	ap.SupportsString(DecorateFlag, "", "decorate_fmt", "Shows refs next to commits. Valid options are short, full, no, and auto")
 	ap.SupportsStringList(NotFlag, "", "revision", "Excludes commits from revision.")
+	ap.SupportsFlag(ShowSignatureFlag, "", "Shows the result of verifying the signature of each signed commit.")
+	ap.SupportsFlag(GraphFlag, "", "Draws a text-based graph of the commit history beside the log.")
+	ap.SupportsString(PrettyParam, "", "format", "Shows each commit in the given format: {{.EmphasisLeft}}oneline{{.EmphasisRight}}, {{.EmphasisLeft}}medium{{.EmphasisRight}}, or {{.EmphasisLeft}}format:{{.LessThan}}string{{.GreaterThan}}{{.EmphasisRight}}, where the string has placeholders like %H for the commit hash, %an for the author name, %ad for the date and %s for the subject.")
+	ap.SupportsString(FormatParam, "", "format", "Equivalent to --pretty.")
 	if isTableFunction {
 		ap.SupportsStringList(TablesFlag, "t", "table", "Restricts the log to commits that modified the specified tables.")
+		ap.SupportsStringList(ColumnsFlag, "", "column", "Restricts the log to commits that modified the specified columns of the tables given by {{.EmphasisLeft}}--tables{{.EmphasisRight}}.")
+		ap.SupportsString(AuthorParam, "", "pattern", "Restricts the log to commits whose author name or email matches the regular expression {{.LessThan}}pattern{{.GreaterThan}}.")
+		ap.SupportsString(SinceParam, "", "date", "Restricts the log to commits made at or after {{.LessThan}}date{{.GreaterThan}}.")
+		ap.SupportsString(UntilParam, "", "date", "Restricts the log to commits made at or before {{.LessThan}}date{{.GreaterThan}}.")
 	} else {
 		ap.SupportsFlag(OneLineFlag, "", "Shows logs in a compact format.")
 	}
@@ -274,6 +356,13 @@ func CreateGCArgParser() *argparser.ArgParser {
 	return ap
 }
 
+func CreateVerifySignaturesArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("verify_signatures")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"revision", "A commit to verify, or a range {{.LessThan}}from{{.GreaterThan}}..{{.LessThan}}to{{.GreaterThan}} of the commits reachable from {{.LessThan}}to{{.GreaterThan}} but not from {{.LessThan}}from{{.GreaterThan}} to verify."})
+	ap.SupportsFlag(TagsFlag, "", "Verifies the signatures of the tags named, rather than of commits.")
+	return ap
+}
+
 func CreateCountCommitsArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs("gc", 0)
 	ap.SupportsString("from", "f", "commit id", "commit to start counting from")
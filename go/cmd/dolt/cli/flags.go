@@ -17,53 +17,79 @@ package cli
 // Constants for command line flags names. These tend to be used in multiple places, so defining
 // them low in the package dependency tree makes sense.
 const (
-	AbortParam       = "abort"
-	AllFlag          = "all"
-	AllowEmptyFlag   = "allow-empty"
-	AmendFlag        = "amend"
-	AuthorParam      = "author"
-	BranchParam      = "branch"
-	CachedFlag       = "cached"
-	CheckoutCoBranch = "b"
-	CommitFlag       = "commit"
-	CopyFlag         = "copy"
-	DateParam        = "date"
-	DecorateFlag     = "decorate"
-	DeleteFlag       = "delete"
-	DeleteForceFlag  = "D"
-	DryRunFlag       = "dry-run"
-	ForceFlag        = "force"
-	HardResetParam   = "hard"
-	HostFlag         = "host"
-	ListFlag         = "list"
-	MergesFlag       = "merges"
-	MessageArg       = "message"
-	MinParentsFlag   = "min-parents"
-	MoveFlag         = "move"
-	NoCommitFlag     = "no-commit"
-	NoEditFlag       = "no-edit"
-	NoFFParam        = "no-ff"
-	NoPrettyFlag     = "no-pretty"
-	NoTLSFlag        = "no-tls"
-	NotFlag          = "not"
-	NumberFlag       = "number"
-	OneLineFlag      = "oneline"
-	OursFlag         = "ours"
-	OutputOnlyFlag   = "output-only"
-	ParentsFlag      = "parents"
-	PasswordFlag     = "password"
-	PortFlag         = "port"
-	PruneFlag        = "prune"
-	RemoteParam      = "remote"
-	SetUpstreamFlag  = "set-upstream"
-	ShallowFlag      = "shallow"
-	ShowIgnoredFlag  = "ignored"
-	SkipEmptyFlag    = "skip-empty"
-	SoftResetParam   = "soft"
-	SquashParam      = "squash"
-	TablesFlag       = "tables"
-	TheirsFlag       = "theirs"
-	TrackFlag        = "track"
-	UpperCaseAllFlag = "ALL"
-	UserFlag         = "user"
+	AbortParam         = "abort"
+	AllFlag            = "all"
+	AllowEmptyFlag     = "allow-empty"
+	AmendFlag          = "amend"
+	AuthorParam        = "author"
+	AutostashFlag      = "autostash"
+	BranchParam        = "branch"
+	CachedFlag         = "cached"
+	CellMergeFlag      = "cell-merge"
+	CheckoutCoBranch   = "b"
+	CommitFlag         = "commit"
+	ContinueFlag       = "continue"
+	ColumnsFlag        = "columns"
+	CopyFlag           = "copy"
+	DateParam          = "date"
+	DecorateFlag       = "decorate"
+	DeepenParam        = "deepen"
+	DeleteFlag         = "delete"
+	DeleteForceFlag    = "D"
+	DepthParam         = "depth"
+	DescribeParam      = "describe"
+	DryRunFlag         = "dry-run"
+	EditTodoParam      = "edit-todo"
+	ExpiresParam       = "expires"
+	FindRenamesParam   = "find-renames"
+	ForceFlag          = "force"
+	FormatParam        = "format"
+	ForceWithLeaseFlag = "force-with-lease"
+	GpgSignFlag        = "gpg-sign"
+	GraphFlag          = "graph"
+	HardResetParam     = "hard"
+	HostFlag           = "host"
+	InteractiveFlag    = "interactive"
+	ListFlag           = "list"
+	MergesFlag         = "merges"
+	MetadataParam      = "meta"
+	MessageArg         = "message"
+	MinParentsFlag     = "min-parents"
+	MoveFlag           = "move"
+	NoCommitFlag       = "no-commit"
+	NoEditFlag         = "no-edit"
+	NoFFParam          = "no-ff"
+	NoPrettyFlag       = "no-pretty"
+	NoRenamesFlag      = "no-renames"
+	NoTLSFlag          = "no-tls"
+	NotFlag            = "not"
+	NumberFlag         = "number"
+	OneLineFlag        = "oneline"
+	OursFlag           = "ours"
+	OutputOnlyFlag     = "output-only"
+	ParentsFlag        = "parents"
+	PasswordFlag       = "password"
+	PortFlag           = "port"
+	PrettyParam        = "pretty"
+	PruneFlag          = "prune"
+	RemoteParam        = "remote"
+	ReviewersParam     = "reviewers"
+	SetUpstreamFlag    = "set-upstream"
+	ShallowFlag        = "shallow"
+	ShowIgnoredFlag    = "ignored"
+	ShowSignatureFlag  = "show-signature"
+	SignFlag           = "sign"
+	SinceParam         = "since"
+	SkipEmptyFlag      = "skip-empty"
+	SkipFlag           = "skip"
+	SoftResetParam     = "soft"
+	SquashParam        = "squash"
+	StashFlag          = "stash"
+	TablesFlag         = "tables"
+	TagsFlag           = "tags"
+	TheirsFlag         = "theirs"
+	TrackFlag          = "track"
+	UntilParam         = "until"
+	UpperCaseAllFlag   = "ALL"
+	UserFlag           = "user"
 )
@@ -0,0 +1,143 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+)
+
+// LogFormat is the format of Dolt's log output, and of the messages written to stderr by the cli output helpers.
+type LogFormat string
+
+const (
+	// LogFormatText is human-readable text, and the default.
+	LogFormatText LogFormat = "text"
+	// LogFormatJSON is one JSON object per line, with the fields described by JSONLogFormatter.
+	LogFormatJSON LogFormat = "json"
+)
+
+// The fields of JSON log records. Logrus fields that name the same thing in different subsystems are renamed to these.
+const (
+	LogFieldTime         = "time"
+	LogFieldLevel        = "level"
+	LogFieldMessage      = "msg"
+	LogFieldConnectionID = "connection_id"
+	LogFieldDatabase     = "db"
+	LogFieldBranch       = "branch"
+	LogFieldQuery        = "query"
+	LogFieldThread       = "thread"
+)
+
+// logFieldAliases maps the logrus fields used across Dolt and go-mysql-server to the JSON log record fields.
+var logFieldAliases = map[string]string{
+	sql.ConnectionIdLogField: LogFieldConnectionID,
+	sql.ConnectionDbLogField: LogFieldDatabase,
+	"database":               LogFieldDatabase,
+}
+
+var logFormat = LogFormatText
+
+// ParseLogFormat returns the LogFormat named by |s|.
+func ParseLogFormat(s string) (LogFormat, error) {
+	switch format := LogFormat(strings.ToLower(s)); format {
+	case LogFormatText, LogFormatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported log format '%s', expected '%s' or '%s'", s, LogFormatText, LogFormatJSON)
+	}
+}
+
+// SetLogFormat sets the format of the log output for this run of Dolt. With LogFormatJSON, the standard logrus
+// logger writes JSON records, and so do PrintErr, PrintErrln and PrintErrf.
+func SetLogFormat(format LogFormat) {
+	logFormat = format
+	if format == LogFormatJSON {
+		color.NoColor = true
+		logrus.SetFormatter(JSONLogFormatter{})
+	}
+}
+
+// GetLogFormat returns the format of the log output for this run of Dolt.
+func GetLogFormat() LogFormat {
+	return logFormat
+}
+
+// JSONLogFormatter is a logrus.Formatter that writes each entry as a JSON object on its own line. Every record has
+// the time, level and msg fields. The connection, database and branch an entry was logged for are written to the
+// connection_id, db and branch fields, and the other fields of the entry are written as they are.
+type JSONLogFormatter struct{}
+
+var _ logrus.Formatter = JSONLogFormatter{}
+
+// Format implements logrus.Formatter.
+func (JSONLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	record := make(map[string]interface{}, len(entry.Data)+3)
+	for k, v := range entry.Data {
+		if alias, ok := logFieldAliases[k]; ok {
+			k = alias
+		}
+		switch v := v.(type) {
+		case error:
+			record[k] = v.Error()
+		case time.Time:
+			record[k] = v.UTC().Format(time.RFC3339Nano)
+		default:
+			record[k] = v
+		}
+	}
+
+	// revision databases are logged by their qualified names, e.g. mydb/main
+	if db, ok := record[LogFieldDatabase].(string); ok {
+		if base, branch, ok := strings.Cut(db, "/"); ok {
+			record[LogFieldDatabase] = base
+			record[LogFieldBranch] = branch
+		}
+	}
+
+	record[LogFieldTime] = entry.Time.UTC().Format(time.RFC3339Nano)
+	record[LogFieldLevel] = entry.Level.String()
+	record[LogFieldMessage] = entry.Message
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal log entry to JSON: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// printErrRecord writes |msg| to CliErr as a JSON log record at the error level.
+func printErrRecord(msg string) {
+	msg = strings.TrimSpace(msg)
+	if msg == "" {
+		return
+	}
+	data, err := JSONLogFormatter{}.Format(&logrus.Entry{
+		Data:    logrus.Fields{},
+		Time:    time.Now(),
+		Level:   logrus.ErrorLevel,
+		Message: msg,
+	})
+	if err != nil {
+		return
+	}
+	CliErr.Write(data)
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogFormat(t *testing.T) {
+	format, err := ParseLogFormat("JSON")
+	require.NoError(t, err)
+	assert.Equal(t, LogFormatJSON, format)
+
+	format, err = ParseLogFormat("text")
+	require.NoError(t, err)
+	assert.Equal(t, LogFormatText, format)
+
+	_, err = ParseLogFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestJSONLogFormatter(t *testing.T) {
+	ts := time.Date(2023, 10, 2, 12, 30, 0, 0, time.UTC)
+	data, err := JSONLogFormatter{}.Format(&logrus.Entry{
+		Data: logrus.Fields{
+			sql.ConnectionIdLogField: uint32(7),
+			sql.ConnectionDbLogField: "mydb/feature",
+			"query":                  "select 1",
+			"thread":                 "Standby Replication - mydb to standby",
+			logrus.ErrorKey:          errors.New("remote unavailable"),
+		},
+		Time:    ts,
+		Level:   logrus.WarnLevel,
+		Message: "query failed",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, byte('\n'), data[len(data)-1])
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &record))
+	assert.Equal(t, map[string]interface{}{
+		LogFieldTime:         "2023-10-02T12:30:00Z",
+		LogFieldLevel:        "warning",
+		LogFieldMessage:      "query failed",
+		LogFieldConnectionID: float64(7),
+		LogFieldDatabase:     "mydb",
+		LogFieldBranch:       "feature",
+		LogFieldQuery:        "select 1",
+		LogFieldThread:       "Standby Replication - mydb to standby",
+		logrus.ErrorKey:      "remote unavailable",
+	}, record)
+}
+
+func TestPrintErrJSON(t *testing.T) {
+	oldErr, oldFormat := CliErr, logFormat
+	defer func() {
+		CliErr, logFormat = oldErr, oldFormat
+	}()
+
+	var b bytes.Buffer
+	CliErr = &b
+	logFormat = LogFormatJSON
+	PrintErrln("error: table not found:", "t1")
+	PrintErr("\n")
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &record))
+	assert.Equal(t, "error", record[LogFieldLevel])
+	assert.Equal(t, "error: table not found: t1", record[LogFieldMessage])
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// OutputFormat is the format the read-only commands, such as status, branch and log, write their output in.
+type OutputFormat string
+
+const (
+	// OutputFormatText is human-readable text, and the default.
+	OutputFormatText OutputFormat = "text"
+	// OutputFormatJSON is a JSON object with a "rows" array of objects, one for each row of the command's output.
+	OutputFormatJSON OutputFormat = "json"
+	// OutputFormatCSV is CSV with a header line.
+	OutputFormatCSV OutputFormat = "csv"
+)
+
+var outputFormat = OutputFormatText
+
+// ParseOutputFormat returns the OutputFormat named by |s|.
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch format := OutputFormat(strings.ToLower(s)); format {
+	case OutputFormatText, OutputFormatJSON, OutputFormatCSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported output format '%s', expected '%s', '%s' or '%s'", s, OutputFormatText, OutputFormatJSON, OutputFormatCSV)
+	}
+}
+
+// SetOutputFormat sets the format of the output of the read-only commands for this run of Dolt.
+func SetOutputFormat(format OutputFormat) {
+	outputFormat = format
+	if format != OutputFormatText {
+		color.NoColor = true
+	}
+}
+
+// GetOutputFormat returns the format of the output of the read-only commands for this run of Dolt.
+func GetOutputFormat() OutputFormat {
+	return outputFormat
+}
+
+// MachineReadableOutput returns whether the read-only commands write their output as JSON or CSV, rather than text.
+func MachineReadableOutput() bool {
+	return outputFormat != OutputFormatText
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	format, err := ParseOutputFormat("JSON")
+	require.NoError(t, err)
+	assert.Equal(t, OutputFormatJSON, format)
+
+	format, err = ParseOutputFormat("csv")
+	require.NoError(t, err)
+	assert.Equal(t, OutputFormatCSV, format)
+
+	format, err = ParseOutputFormat("text")
+	require.NoError(t, err)
+	assert.Equal(t, OutputFormatText, format)
+
+	_, err = ParseOutputFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestMachineReadableOutput(t *testing.T) {
+	old := outputFormat
+	defer func() {
+		outputFormat = old
+	}()
+
+	outputFormat = OutputFormatText
+	assert.False(t, MachineReadableOutput())
+	outputFormat = OutputFormatCSV
+	assert.True(t, MachineReadableOutput())
+}
@@ -121,6 +121,11 @@ func PrintErrln(a ...interface{}) {
 		return
 	}
 
+	if logFormat == LogFormatJSON {
+		printErrRecord(fmt.Sprintln(a...))
+		return
+	}
+
 	fmt.Fprintln(CliErr, a...)
 }
 
@@ -129,6 +134,11 @@ func PrintErr(a ...interface{}) {
 		return
 	}
 
+	if logFormat == LogFormatJSON {
+		printErrRecord(fmt.Sprint(a...))
+		return
+	}
+
 	fmt.Fprint(CliErr, a...)
 }
 
@@ -137,6 +147,11 @@ func PrintErrf(format string, a ...interface{}) {
 		return
 	}
 
+	if logFormat == LogFormatJSON {
+		printErrRecord(fmt.Sprintf(format, a...))
+		return
+	}
+
 	fmt.Fprintf(CliErr, format, a...)
 }
 
@@ -20,11 +20,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"regexp"
 	"strings"
+	"syscall"
 	"time"
 	"unicode"
 
@@ -36,21 +39,60 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/config"
 )
 
 var assistDocs = cli.CommandDocumentationContent{
 	ShortDesc: "Assists with dolt commands and queries.",
 	LongDesc: `Assists with dolt commands and queries. Can run dolt commands or SQL queries on your behalf based on your questions or instructions, as well as answer questions about your database.
 
-Powered by OpenAI's chat API. An API key is required. Please set the OPENAI_API_KEY environment variable. 
+Powered by OpenAI's chat API by default. An API key is required for OpenAI. Please set the OPENAI_API_KEY environment variable, or the {{.EmphasisLeft}}assist.api_key{{.EmphasisRight}} config value.
+
+To use a local or self-hosted model instead, give the URL of any OpenAI-compatible chat completions endpoint with {{.EmphasisLeft}}--url{{.EmphasisRight}} or the {{.EmphasisLeft}}assist.url{{.EmphasisRight}} config value, and the model to use with {{.EmphasisLeft}}--model{{.EmphasisRight}} or {{.EmphasisLeft}}assist.model{{.EmphasisRight}}. An API key is only sent if one is configured.
+
+With {{.EmphasisLeft}}--offline{{.EmphasisRight}}, or the {{.EmphasisLeft}}assist.offline{{.EmphasisRight}} config value set to true, the assistant refuses to start unless the endpoint is on this machine or a private network, and refuses to connect to any other address or through a proxy, so that your schema and data never leave your environment.
 `,
 	Synopsis: []string{
-		"[--debug] [--model {{.LessThan}}modelId{{.GreaterThan}}]",
+		"[--debug] [--model {{.LessThan}}modelId{{.GreaterThan}}] [--url {{.LessThan}}url{{.GreaterThan}}] [--offline]",
 	},
 }
 
+const (
+	assistModelFlag   = "model"
+	assistDebugFlag   = "debug"
+	assistUrlFlag     = "url"
+	assistOfflineFlag = "offline"
+
+	defaultAssistUrl   = "https://api.openai.com/v1/chat/completions"
+	defaultAssistModel = "gpt-3.5-turbo"
+)
+
 type Assist struct {
 	messages []string
+	client   *http.Client
+}
+
+// assistSettings are the settings of the assistant, from the command line or the assist.* config values.
+type assistSettings struct {
+	endpoint string
+	model    string
+	apiKey   string
+	offline  bool
+}
+
+// getAssistSettings returns the settings of the assistant. Command line arguments take precedence over config values,
+// and the OPENAI_API_KEY environment variable is used when no API key is configured.
+func getAssistSettings(apr *argparser.ArgParseResults, cfg config.ReadableConfig) assistSettings {
+	settings := assistSettings{
+		endpoint: apr.GetValueOrDefault(assistUrlFlag, cfg.GetStringOrDefault(env.AssistUrl, defaultAssistUrl)),
+		model:    apr.GetValueOrDefault(assistModelFlag, cfg.GetStringOrDefault(env.AssistModel, defaultAssistModel)),
+		apiKey:   cfg.GetStringOrDefault(env.AssistApiKey, ""),
+		offline:  apr.Contains(assistOfflineFlag) || strings.ToLower(cfg.GetStringOrDefault(env.AssistOffline, "false")) == "true",
+	}
+	if settings.apiKey == "" {
+		settings.apiKey = os.Getenv(dconfig.EnvOpenAiKey)
+	}
+	return settings
 }
 
 var _ cli.Command = &Assist{}
@@ -70,18 +112,26 @@ func (a Assist) Hidden() bool {
 func (a *Assist) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
 	a.messages = make([]string, 0)
 
-	apiKey, ok := os.LookupEnv(dconfig.EnvOpenAiKey)
-	if !ok {
-		cli.PrintErrln("Could not find OpenAI API key. Please set the OPENAI_API_KEY environment variable.")
-		return 1
-	}
-
 	ap := a.ArgParser()
-	helpPr, _ := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, addDocs, ap))
+	helpPr, _ := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, assistDocs, ap))
 	apr := cli.ParseArgsOrDie(ap, args, helpPr)
 
-	model := apr.GetValueOrDefault("model", "gpt-3.5-turbo")
-	debug := apr.Contains("debug")
+	settings := getAssistSettings(apr, dEnv.Config)
+	endpoint, model, apiKey, offline := settings.endpoint, settings.model, settings.apiKey, settings.offline
+	debug := apr.Contains(assistDebugFlag)
+
+	if offline {
+		if err := checkPrivateEndpoint(endpoint); err != nil {
+			cli.PrintErrln(err.Error())
+			return 1
+		}
+	}
+	a.client = newAssistClient(offline)
+
+	if apiKey == "" && endpoint == defaultAssistUrl {
+		cli.PrintErrln("Could not find OpenAI API key. Please set the OPENAI_API_KEY environment variable.")
+		return 1
+	}
 
 	sqlEng, dbName, err := engine.NewSqlEngineForEnv(ctx, dEnv)
 	if err != nil {
@@ -100,10 +150,14 @@ func (a *Assist) Exec(ctx context.Context, commandStr string, args []string, dEn
 	}
 
 	scanner := bufio.NewScanner(cli.InStream)
-	cli.Println("# Welcome to the Dolt Assistant, powered by ChatGPT.\n# Type your question or command, or exit to quit.")
+	if endpoint == defaultAssistUrl {
+		cli.Println("# Welcome to the Dolt Assistant, powered by ChatGPT.\n# Type your question or command, or exit to quit.")
+	} else {
+		cli.Printf("# Welcome to the Dolt Assistant, powered by %s.\n# Type your question or command, or exit to quit.\n", model)
+	}
 	cli.Println("")
 
-	if !agreeToTerms(scanner) {
+	if !offline && !agreeToTerms(scanner, endpoint) {
 		return 0
 	}
 
@@ -130,7 +184,7 @@ func (a *Assist) Exec(ctx context.Context, commandStr string, args []string, dEn
 			query = input
 		}
 
-		response, err := a.queryGpt(ctx, apiKey, model, query, debug)
+		response, err := a.queryGpt(ctx, endpoint, apiKey, model, query, debug)
 		if err != nil {
 			return 1
 		}
@@ -145,15 +199,19 @@ func (a *Assist) Exec(ctx context.Context, commandStr string, args []string, dEn
 	}
 }
 
-func agreeToTerms(scanner *bufio.Scanner) bool {
+func agreeToTerms(scanner *bufio.Scanner, endpoint string) bool {
 	_, ok := os.LookupEnv(dconfig.EnvDoltAssistAgree)
 	if ok {
 		return true
 	}
 
+	recipient := "OpenAI"
+	if endpoint != defaultAssistUrl {
+		recipient = endpoint
+	}
 	cli.Println(wordWrap("# ", "DISCLAIMER: Use of this tool may send information in your database, including schema, "+
-		"commit history, and rows to OpenAI. If this use of your database information is unacceptable to you, please do "+
-		"not use the tool."))
+		"commit history, and rows to "+recipient+". If this use of your database information is unacceptable to you, please do "+
+		"not use the tool, or use --offline with a model running in your environment."))
 	cli.Print("\nContinue? (y/n) > ")
 
 	scanner.Scan()
@@ -360,7 +418,7 @@ func wordWrap(linePrefix string, content string) string {
 	return sb.String()
 }
 
-func (a *Assist) queryGpt(ctx context.Context, apiKey, modelId, query string, debug bool) (string, error) {
+func (a *Assist) queryGpt(ctx context.Context, endpoint, apiKey, modelId, query string, debug bool) (string, error) {
 	prompt, err := a.getJsonPrompt(ctx, modelId, query)
 	if err != nil {
 		return "", err
@@ -370,16 +428,20 @@ func (a *Assist) queryGpt(ctx context.Context, apiKey, modelId, query string, de
 		cli.Println(prompt)
 	}
 
-	url := "https://api.openai.com/v1/chat/completions"
-	client := &http.Client{}
+	client := a.client
+	if client == nil {
+		client = newAssistClient(false)
+	}
 
-	req, err := http.NewRequest("POST", url, prompt)
+	req, err := http.NewRequest("POST", endpoint, prompt)
 	if err != nil {
 		return "", err
 	}
 
 	req.Header.Add("Content-Type", `application/json`)
-	req.Header.Add("Authorization", "Bearer "+apiKey)
+	if apiKey != "" {
+		req.Header.Add("Authorization", "Bearer "+apiKey)
+	}
 
 	respChan := make(chan string)
 	errChan := make(chan error)
@@ -421,6 +483,74 @@ func (a *Assist) queryGpt(ctx context.Context, apiKey, modelId, query string, de
 	}
 }
 
+// checkPrivateEndpoint returns an error unless |endpoint| is a valid URL whose host has only loopback or private
+// network addresses. It's checked before the assistant starts so that a public endpoint is refused up front, but the
+// host may resolve differently by the time it's connected to, so the addresses connected to are checked again by the
+// client of newAssistClient.
+func checkPrivateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid assistant url %s: %w", endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid assistant url %s: the scheme must be http or https", endpoint)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("invalid assistant url %s: no host", endpoint)
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("offline mode could not resolve the assistant host %s: %w", host, err)
+	}
+	for _, ip := range ips {
+		if !isPrivateIP(ip) {
+			return fmt.Errorf("offline mode only allows an assistant on this machine or a private network, but %s is at %s. "+
+				"Set the assist.url config value or use --url to choose a local endpoint.", host, ip)
+		}
+	}
+	return nil
+}
+
+// newAssistClient returns the HTTP client used to talk to the assistant. In offline mode, the client only connects to
+// loopback and private network addresses, and never through a proxy, since a proxy could forward requests anywhere.
+func newAssistClient(offline bool) *http.Client {
+	if !offline {
+		return &http.Client{}
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   privateDialControl,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Transport: transport}
+}
+
+// privateDialControl is the Control function of the dialer of an offline assistant client. It's called with the
+// resolved address of each connection before it's made, and refuses any address that isn't a loopback or private
+// network address, so that a host can't resolve to a private address when it's checked and a public one when it's
+// connected to.
+func privateDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPrivateIP(ip) {
+		return fmt.Errorf("offline mode refused to connect to %s, which is not on this machine or a private network", host)
+	}
+	return nil
+}
+
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate()
+}
+
 func (a *Assist) getJsonPrompt(ctx context.Context, modelId string, query string) (io.Reader, error) {
 	sb := strings.Builder{}
 
@@ -610,10 +740,13 @@ func (a Assist) Docs() *cli.CommandDocumentation {
 
 func (a Assist) ArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs(a.Name(), 0)
-	ap.SupportsString("model", "m", "open AI model id",
-		"The ID of the Open AI model to use for the assistant. Defaults to gpt-3.5-turbo. "+
-			"See https://platform.openai.com/docs/models/overview for a full list of models.")
-	ap.SupportsFlag("debug", "d", "log API requests to and from the assistant")
+	ap.SupportsString(assistModelFlag, "m", "open AI model id",
+		"The ID of the model to use for the assistant. Defaults to the assist.model config value, or gpt-3.5-turbo. "+
+			"See https://platform.openai.com/docs/models/overview for a full list of Open AI models.")
+	ap.SupportsFlag(assistDebugFlag, "d", "log API requests to and from the assistant")
+	ap.SupportsString(assistUrlFlag, "", "url",
+		"The URL of an OpenAI-compatible chat completions endpoint to use instead of OpenAI's. Defaults to the assist.url config value.")
+	ap.SupportsFlag(assistOfflineFlag, "", "refuse to send anything to an endpoint outside of this machine or private network")
 	return ap
 }
 
@@ -0,0 +1,144 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/config"
+)
+
+func TestGetAssistSettings(t *testing.T) {
+	t.Setenv(dconfig.EnvOpenAiKey, "env-key")
+
+	tests := []struct {
+		name     string
+		args     []string
+		cfg      map[string]string
+		expected assistSettings
+	}{
+		{
+			name:     "defaults",
+			expected: assistSettings{endpoint: defaultAssistUrl, model: defaultAssistModel, apiKey: "env-key"},
+		},
+		{
+			name: "config values",
+			cfg: map[string]string{
+				env.AssistUrl:     "http://localhost:8080/v1/chat/completions",
+				env.AssistModel:   "llama2",
+				env.AssistApiKey:  "config-key",
+				env.AssistOffline: "TRUE",
+			},
+			expected: assistSettings{endpoint: "http://localhost:8080/v1/chat/completions", model: "llama2", apiKey: "config-key", offline: true},
+		},
+		{
+			name: "arguments take precedence over config values",
+			args: []string{"--url", "http://10.0.0.5/v1/chat/completions", "--model", "mistral", "--offline"},
+			cfg: map[string]string{
+				env.AssistUrl:     "http://localhost:8080/v1/chat/completions",
+				env.AssistModel:   "llama2",
+				env.AssistOffline: "false",
+			},
+			expected: assistSettings{endpoint: "http://10.0.0.5/v1/chat/completions", model: "mistral", apiKey: "env-key", offline: true},
+		},
+		{
+			name:     "offline only when true",
+			cfg:      map[string]string{env.AssistOffline: "yes"},
+			expected: assistSettings{endpoint: defaultAssistUrl, model: defaultAssistModel, apiKey: "env-key"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			apr, err := Assist{}.ArgParser().Parse(test.args)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, getAssistSettings(apr, config.NewMapConfig(test.cfg)))
+		})
+	}
+}
+
+func TestCheckPrivateEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		err      string
+	}{
+		{endpoint: "http://localhost:8080/v1/chat/completions"},
+		{endpoint: "http://127.0.0.1:8080/v1/chat/completions"},
+		{endpoint: "https://[::1]/v1/chat/completions"},
+		{endpoint: "http://10.1.2.3/v1/chat/completions"},
+		{endpoint: "http://192.168.0.10:11434/v1/chat/completions"},
+		{endpoint: "https://8.8.8.8/v1/chat/completions", err: "offline mode only allows an assistant"},
+		{endpoint: "http://[2001:4860:4860::8888]/v1", err: "offline mode only allows an assistant"},
+		{endpoint: "localhost:8080", err: "the scheme must be http or https"},
+		{endpoint: "ftp://localhost/", err: "the scheme must be http or https"},
+		{endpoint: "http:///v1/chat/completions", err: "no host"},
+		{endpoint: "http://local host/", err: "invalid assistant url"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.endpoint, func(t *testing.T) {
+			err := checkPrivateEndpoint(test.endpoint)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.err)
+			}
+		})
+	}
+}
+
+func TestPrivateDialControl(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:80", "[::1]:443", "10.0.0.1:8080", "172.16.5.4:80", "192.168.1.1:80", "[fd00::1]:80"} {
+		assert.NoError(t, privateDialControl("tcp", address, nil), address)
+	}
+	for _, address := range []string{"8.8.8.8:443", "[2001:4860:4860::8888]:443", "100.64.0.1:80", "example.com:80"} {
+		assert.Error(t, privateDialControl("tcp", address, nil), address)
+	}
+}
+
+func TestOfflineAssistClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	client := newAssistClient(true)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, "ok", string(body))
+
+	// the connection is refused before it's made, whatever the host resolved to when it was checked
+	_, err = client.Get("http://8.8.8.8:9/v1/chat/completions")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "offline mode refused to connect to 8.8.8.8")
+
+	// a proxy could forward requests anywhere, so none is used
+	t.Setenv("HTTP_PROXY", server.URL)
+	_, err = newAssistClient(true).Get("http://8.8.8.8:9/v1/chat/completions")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "offline mode refused to connect to 8.8.8.8")
+}
@@ -19,6 +19,8 @@ import (
 	"encoding/json"
 	"strings"
 
+	"github.com/dolthub/go-mysql-server/sql"
+
 	"github.com/dolthub/dolt/go/store/types"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
@@ -208,6 +210,17 @@ func printBackups(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Ver
 		return errhand.BuildDError("Unable to get backups from the local directory").AddCause(err).Build()
 	}
 
+	if cli.MachineReadableOutput() {
+		var rows []sql.Row
+		for _, r := range sortedRemotes(backups) {
+			rows = append(rows, sql.Row{r.Name, r.Url, jsonParams(r.Params)})
+		}
+		if err := printOutputRows(backupOutputSchema, rows); err != nil {
+			return errhand.BuildDError("error: failed to print backups").AddCause(err).Build()
+		}
+		return nil
+	}
+
 	for _, r := range backups {
 		if apr.Contains(cli.VerboseFlag) {
 			paramStr := make([]byte, 0)
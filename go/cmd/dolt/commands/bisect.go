@@ -0,0 +1,272 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/util/outputpager"
+)
+
+var bisectDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Use binary search to find the commit that introduced a change",
+	LongDesc: `Searches the history of the current branch for the commit that introduced a change, such as a bug. Mark a commit known to have the change as bad, and a commit from before the change as good, and dolt picks a commit between them to test. Mark each commit it picks as good or bad until the first bad commit is found.
+
+The commit to test is not checked out. Instead, it can be queried as the revision database {{.EmphasisLeft}}{{.LessThan}}database{{.GreaterThan}}/{{.LessThan}}commit{{.GreaterThan}}{{.EmphasisRight}}, which is read only.
+
+{{.EmphasisLeft}}start [{{.LessThan}}bad{{.GreaterThan}} [{{.LessThan}}good{{.GreaterThan}}...]]{{.EmphasisRight}}
+Starts a bisect session on the current branch, optionally marking the commits given as bad and good.
+
+{{.EmphasisLeft}}bad [{{.LessThan}}commit{{.GreaterThan}}]{{.EmphasisRight}}, {{.EmphasisLeft}}good [{{.LessThan}}commit{{.GreaterThan}}...]{{.EmphasisRight}}
+Marks the commits given, or the commit being tested, as bad or good.
+
+{{.EmphasisLeft}}skip [{{.LessThan}}commit{{.GreaterThan}}...]{{.EmphasisRight}}
+Marks the commits given, or the commit being tested, as impossible to test. Another commit is picked instead.
+
+{{.EmphasisLeft}}status{{.EmphasisRight}}
+Shows the commit being tested, or the result of the session.
+
+{{.EmphasisLeft}}run {{.LessThan}}query{{.GreaterThan}}{{.EmphasisRight}}
+Tests commits automatically until the first bad commit is found. The query is run against each commit to test, and the first column of its first row decides whether the commit is good: true or non-zero marks it good, and false or zero marks it bad. A NULL value, or no rows, skips the commit. Marking a good and a bad commit is required first.
+
+{{.EmphasisLeft}}reset{{.EmphasisRight}}
+Ends the bisect session.
+
+The session is recorded in the database until it's reset, and can be driven through the {{.EmphasisLeft}}DOLT_BISECT(){{.EmphasisRight}} stored procedure as well.`,
+	Synopsis: []string{
+		"start [{{.LessThan}}bad{{.GreaterThan}} [{{.LessThan}}good{{.GreaterThan}}...]]",
+		"(bad | good | skip) [{{.LessThan}}commit{{.GreaterThan}}...]",
+		"run {{.LessThan}}query{{.GreaterThan}}",
+		"(status | reset)",
+	},
+}
+
+const bisectRunId = "run"
+
+type BisectCmd struct{}
+
+var _ cli.Command = BisectCmd{}
+
+// Name implements the interface cli.Command.
+func (cmd BisectCmd) Name() string {
+	return "bisect"
+}
+
+// Description implements the interface cli.Command.
+func (cmd BisectCmd) Description() string {
+	return "Use binary search to find the commit that introduced a change."
+}
+
+func (cmd BisectCmd) RequiresRepo() bool {
+	return false
+}
+
+func (cmd BisectCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(bisectDocs, ap)
+}
+
+func (cmd BisectCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateBisectArgParser()
+}
+
+// Exec implements the interface cli.Command.
+func (cmd BisectCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, bisectDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() == 0 {
+		usage()
+		return 1
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		cli.Println(err.Error())
+		return 1
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	if apr.Arg(0) == bisectRunId {
+		if apr.NArg() != 2 {
+			return HandleVErrAndExitCode(errhand.BuildDError("run takes a single query").SetPrintUsage().Build(), usage)
+		}
+		return HandleVErrAndExitCode(runBisect(queryist, sqlCtx, apr.Arg(1)), usage)
+	}
+
+	res, err := callBisect(queryist, sqlCtx, apr.Args...)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	return HandleVErrAndExitCode(printBisectResult(queryist, sqlCtx, res), usage)
+}
+
+// bisectResult is a row returned by DOLT_BISECT().
+type bisectResult struct {
+	next     string
+	firstBad string
+	message  string
+}
+
+// callBisect calls DOLT_BISECT() with the arguments given, and returns its result.
+func callBisect(queryist cli.Queryist, sqlCtx *sql.Context, args ...string) (bisectResult, error) {
+	params := make([]interface{}, len(args))
+	for i, arg := range args {
+		params[i] = arg
+	}
+	query, err := dbr.InterpolateForDialect("CALL DOLT_BISECT("+strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")+")", params, dialect.MySQL)
+	if err != nil {
+		return bisectResult{}, err
+	}
+
+	rows, err := GetRowsForSql(queryist, sqlCtx, query)
+	if err != nil {
+		return bisectResult{}, err
+	}
+	if len(rows) != 1 || len(rows[0]) != 3 {
+		return bisectResult{}, fmt.Errorf("unexpected result from DOLT_BISECT()")
+	}
+
+	var res bisectResult
+	if rows[0][0] != nil {
+		res.next = fmt.Sprint(rows[0][0])
+	}
+	if rows[0][1] != nil {
+		res.firstBad = fmt.Sprint(rows[0][1])
+	}
+	res.message = fmt.Sprint(rows[0][2])
+	return res, nil
+}
+
+// printBisectResult prints the result of a bisect step, along with a summary of the commit to test or the first bad
+// commit.
+func printBisectResult(queryist cli.Queryist, sqlCtx *sql.Context, res bisectResult) errhand.VerboseError {
+	cli.Println(res.message)
+
+	switch {
+	case res.next != "":
+		commit, err := getCommitInfo(queryist, sqlCtx, res.next)
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+		cli.Printf("[%s] %s\n", res.next, strings.SplitN(commit.commitMeta.Description, "\n", 2)[0])
+	case res.firstBad != "":
+		commit, err := getCommitInfo(queryist, sqlCtx, res.firstBad)
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+		cli.ExecuteWithStdioRestored(func() {
+			pager := outputpager.Start()
+			defer pager.Stop()
+
+			PrintCommitInfo(pager, 0, false, "auto", commit)
+		})
+	}
+	return nil
+}
+
+// runBisect tests the commits picked by the bisect session with |query| until the first bad commit is found, or only
+// skipped commits are left.
+func runBisect(queryist cli.Queryist, sqlCtx *sql.Context, query string) errhand.VerboseError {
+	dbName, ok := getDBFromSession(sqlCtx, queryist)
+	if !ok {
+		return errhand.BuildDError("error: failed to get the current database").Build()
+	}
+
+	res, err := callBisect(queryist, sqlCtx, "status")
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	if res.next == "" && res.firstBad == "" {
+		return errhand.BuildDError("error: bisect run cannot continue: %s", res.message).Build()
+	}
+
+	for res.next != "" {
+		verr := printBisectResult(queryist, sqlCtx, res)
+		if verr != nil {
+			return verr
+		}
+
+		mark, err := testBisectCommit(queryist, sqlCtx, dbName, res.next, query)
+		if err != nil {
+			return errhand.BuildDError("error: failed to test commit %s", res.next).AddCause(err).Build()
+		}
+		cli.Printf("%s: %s\n", res.next, mark)
+
+		res, err = callBisect(queryist, sqlCtx, mark, res.next)
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+	}
+
+	verr := printBisectResult(queryist, sqlCtx, res)
+	if verr != nil {
+		return verr
+	}
+	if res.firstBad == "" {
+		return errhand.BuildDError("error: bisect run failed to find the first bad commit").Build()
+	}
+	return nil
+}
+
+// testBisectCommit runs |query| against the revision database of |commit|, and returns how the commit should be
+// marked: good, bad or skip.
+func testBisectCommit(queryist cli.Queryist, sqlCtx *sql.Context, dbName, commit, query string) (mark string, err error) {
+	_, err = GetRowsForSql(queryist, sqlCtx, fmt.Sprintf("USE `%s/%s`", dbName, commit))
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_, useErr := GetRowsForSql(queryist, sqlCtx, fmt.Sprintf("USE `%s`", dbName))
+		if err == nil {
+			err = useErr
+		}
+	}()
+
+	rows, err := GetRowsForSql(queryist, sqlCtx, query)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 || len(rows[0]) == 0 || rows[0][0] == nil {
+		return "skip", nil
+	}
+
+	val := rows[0][0]
+	if b, ok := val.([]byte); ok {
+		val = string(b)
+	}
+	good, err := types.ConvertToBool(val)
+	if err != nil {
+		return "", err
+	}
+	if good {
+		return "good", nil
+	}
+	return "bad", nil
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
@@ -32,7 +33,8 @@ import (
 )
 
 const (
-	blameQueryTemplate = "SELECT * FROM dolt_blame_%s AS OF '%s'"
+	blameQueryTemplate     = "SELECT * FROM dolt_blame_%s AS OF '%s'"
+	headBlameQueryTemplate = "SELECT * FROM dolt_blame('%s')"
 )
 
 var blameDocs = cli.CommandDocumentationContent{
@@ -114,7 +116,8 @@ func (cmd BlameCmd) Exec(ctx context.Context, commandStr string, args []string,
 	var schema sql.Schema
 	var ri sql.RowIter
 	if apr.NArg() == 1 {
-		schema, ri, err = queryist.Query(sqlCtx, fmt.Sprintf(blameQueryTemplate, apr.Arg(0), "HEAD"))
+		// the dolt_blame table function only blames HEAD, but is much faster than the view on deep histories
+		schema, ri, err = queryist.Query(sqlCtx, fmt.Sprintf(headBlameQueryTemplate, strings.ReplaceAll(apr.Arg(0), "'", "''")))
 	} else {
 		// validate input
 		ref := apr.Arg(0)
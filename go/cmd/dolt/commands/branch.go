@@ -49,15 +49,20 @@ Note that this will create the new branch, but it will not switch the working tr
 
 With a {{.EmphasisLeft}}-m{{.EmphasisRight}}, {{.LessThan}}oldbranch{{.GreaterThan}} will be renamed to {{.LessThan}}newbranch{{.GreaterThan}}. If {{.LessThan}}newbranch{{.GreaterThan}} exists, -f must be used to force the rename to happen.
 
+With {{.EmphasisLeft}}--expires{{.EmphasisRight}}, the new branch is given a time-to-live such as {{.EmphasisLeft}}30d{{.EmphasisRight}}. Once it has expired, the branch's head is archived as the tag {{.EmphasisLeft}}archive/<branchname>{{.EmphasisRight}} and the branch is deleted the next time {{.EmphasisLeft}}dolt gc{{.EmphasisRight}} runs.
+
 The {{.EmphasisLeft}}-c{{.EmphasisRight}} options have the exact same semantics as {{.EmphasisLeft}}-m{{.EmphasisRight}}, except instead of the branch being renamed it will be copied to a new name.
 
-With a {{.EmphasisLeft}}-d{{.EmphasisRight}}, {{.LessThan}}branchname{{.GreaterThan}} will be deleted. You may specify more than one branch for deletion.`,
+With a {{.EmphasisLeft}}-d{{.EmphasisRight}}, {{.LessThan}}branchname{{.GreaterThan}} will be deleted. You may specify more than one branch for deletion.
+
+With {{.EmphasisLeft}}--describe{{.EmphasisRight}} and {{.EmphasisLeft}}--meta{{.EmphasisRight}}, the description and key/value metadata of {{.LessThan}}branchname{{.GreaterThan}}, or of the current branch if none is given, are set. Metadata is versioned along with the branch, is pushed and fetched with it, and is shown in the {{.EmphasisLeft}}dolt_branches{{.EmphasisRight}} system table.`,
 	Synopsis: []string{
 		`[--list] [-v] [-a] [-r]`,
-		`[-f] {{.LessThan}}branchname{{.GreaterThan}} [{{.LessThan}}start-point{{.GreaterThan}}]`,
+		`[-f] [--expires {{.LessThan}}duration{{.GreaterThan}}] {{.LessThan}}branchname{{.GreaterThan}} [{{.LessThan}}start-point{{.GreaterThan}}]`,
 		`-m [-f] [{{.LessThan}}oldbranch{{.GreaterThan}}] {{.LessThan}}newbranch{{.GreaterThan}}`,
 		`-c [-f] [{{.LessThan}}oldbranch{{.GreaterThan}}] {{.LessThan}}newbranch{{.GreaterThan}}`,
 		`-d [-f] [-r] {{.LessThan}}branchname{{.GreaterThan}}...`,
+		`[--describe {{.LessThan}}description{{.GreaterThan}}] [--meta {{.LessThan}}key=value{{.GreaterThan}},...] [{{.LessThan}}branchname{{.GreaterThan}}]`,
 	},
 }
 
@@ -131,6 +136,8 @@ func (cmd BranchCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return deleteBranches(sqlCtx, queryEngine, apr, args, usage)
 	case apr.Contains(cli.DeleteForceFlag):
 		return deleteBranches(sqlCtx, queryEngine, apr, args, usage)
+	case apr.Contains(cli.DescribeParam), apr.Contains(cli.MetadataParam):
+		return describeBranch(sqlCtx, queryEngine, apr, args, usage)
 	case apr.Contains(cli.ListFlag):
 		return printBranches(sqlCtx, queryEngine, apr, usage)
 	case apr.Contains(showCurrentFlag):
@@ -219,6 +226,20 @@ func printBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser
 		return branches[i].name < branches[j].name
 	})
 
+	if cli.MachineReadableOutput() {
+		var rows []sql.Row
+		for _, branch := range branches {
+			if branchSet.Size() > 0 && !branchSet.Contains(branch.name) {
+				continue
+			}
+			rows = append(rows, sql.Row{branch.name, branch.hash, branch.remote, !branch.remote && branch.name == currentBranch})
+		}
+		if err := printOutputRows(branchOutputSchema, rows); err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: failed to print branches").AddCause(err).Build(), nil)
+		}
+		return 0
+	}
+
 	for _, branch := range branches {
 		if branchSet.Size() > 0 && !branchSet.Contains(branch.name) {
 			continue
@@ -467,6 +488,25 @@ func deleteBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparse
 	return callStoredProcedure(sqlCtx, queryEngine, args)
 }
 
+func describeBranch(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser.ArgParseResults, args []string, usage cli.UsagePrinter) int {
+	if apr.NArg() > 1 {
+		usage()
+		return 1
+	}
+
+	if apr.Contains(cli.AllFlag) {
+		cli.PrintErrln("--all/-a can only be supplied when listing branches, not when describing branches")
+		return 1
+	}
+
+	if apr.Contains(cli.RemoteParam) {
+		cli.PrintErrln("--remote/-r can only be supplied when listing or deleting branches, not when describing branches")
+		return 1
+	}
+
+	return callStoredProcedure(sqlCtx, queryEngine, args)
+}
+
 func generateForceDeleteMessage(args []string) string {
 	newArgs := ""
 	for _, arg := range args {
@@ -36,21 +36,24 @@ import (
 var cherryPickDocs = cli.CommandDocumentationContent{
 	ShortDesc: `Apply the changes introduced by an existing commit.`,
 	LongDesc: `
-Applies the changes from an existing commit and creates a new commit from the current HEAD. This requires your working tree to be clean (no modifications from the HEAD commit).
+Applies the changes from one or more existing commits, creating a new commit from the current HEAD for each of them. This requires your working tree to be clean (no modifications from the HEAD commit).
+
+Commits can be named one by one, or as a range {{.LessThan}}from{{.GreaterThan}}..{{.LessThan}}to{{.GreaterThan}} of the commits reachable from {{.LessThan}}to{{.GreaterThan}} that aren't reachable from {{.LessThan}}from{{.GreaterThan}}, which are applied oldest first. Merge commits in a range are left out.
 
 Cherry-picking merge commits or commits with table drops/renames is not currently supported. 
 
-If any data conflicts, schema conflicts, or constraint violations are detected during cherry-picking, you can use Dolt's conflict resolution features to resolve them. For more information on resolving conflicts, see: https://docs.dolthub.com/concepts/dolt/git/conflicts.
+If any data conflicts, schema conflicts, or constraint violations are detected during cherry-picking, the cherry-pick stops at that commit, and you can use Dolt's conflict resolution features to resolve them. For more information on resolving conflicts, see: https://docs.dolthub.com/concepts/dolt/git/conflicts. Once they are resolved and staged with {{.EmphasisLeft}}dolt add{{.EmphasisRight}}, run {{.EmphasisLeft}}dolt cherry-pick --continue{{.EmphasisRight}} to commit them and apply the rest of the commits, {{.EmphasisLeft}}dolt cherry-pick --skip{{.EmphasisRight}} to leave the commit out instead, or {{.EmphasisLeft}}dolt cherry-pick --abort{{.EmphasisRight}} to put the branch back where it was before the cherry-pick.
 `,
 	Synopsis: []string{
-		`{{.LessThan}}commit{{.GreaterThan}}`,
+		`{{.LessThan}}commit{{.GreaterThan}}...`,
+		`(--continue | --skip | --abort)`,
 	},
 }
 
 var ErrCherryPickConflictsOrViolations = errors.NewKind("error: Unable to apply commit cleanly due to conflicts " +
 	"or constraint violations. Please resolve the conflicts and/or constraint violations, then use `dolt add` " +
-	"to add the tables to the staged set, and `dolt commit` to commit the changes and finish cherry-picking. \n" +
-	"To undo all changes from this cherry-pick operation, use `dolt cherry-pick --abort`.\n" +
+	"to add the tables to the staged set, and `dolt cherry-pick --continue` to commit the changes and continue cherry-picking. \n" +
+	"To leave this commit out, use `dolt cherry-pick --skip`. To undo all changes from this cherry-pick operation, use `dolt cherry-pick --abort`.\n" +
 	"For more information on handling conflicts, see: https://docs.dolthub.com/concepts/dolt/git/conflicts")
 
 type CherryPickCmd struct{}
@@ -105,12 +108,9 @@ func (cmd CherryPickCmd) Exec(ctx context.Context, commandStr string, args []str
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 
-	// TODO : support single commit cherry-pick only for now
-	if apr.NArg() == 0 {
+	if apr.NArg() == 0 && !apr.Contains(cli.ContinueFlag) && !apr.Contains(cli.SkipFlag) {
 		usage()
 		return 1
-	} else if apr.NArg() > 1 {
-		return HandleVErrAndExitCode(errhand.BuildDError("cherry-picking multiple commits is not supported yet").SetPrintUsage().Build(), usage)
 	}
 
 	err = cherryPick(queryist, sqlCtx, apr)
@@ -118,24 +118,34 @@ func (cmd CherryPickCmd) Exec(ctx context.Context, commandStr string, args []str
 }
 
 func cherryPick(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgParseResults) error {
-	cherryStr := apr.Arg(0)
-	if len(cherryStr) == 0 {
-		return fmt.Errorf("error: cannot cherry-pick empty string")
-	}
+	var args []string
+	switch {
+	case apr.Contains(cli.ContinueFlag):
+		args = []string{"--" + cli.ContinueFlag}
+	case apr.Contains(cli.SkipFlag):
+		args = []string{"--" + cli.SkipFlag}
+	default:
+		for _, cherryStr := range apr.Args {
+			if len(cherryStr) == 0 {
+				return fmt.Errorf("error: cannot cherry-pick empty string")
+			}
+		}
+		args = apr.Args
 
-	hasStagedChanges, hasUnstagedChanges, err := hasStagedAndUnstagedChanged(queryist, sqlCtx)
-	if err != nil {
-		return fmt.Errorf("error: failed to check for staged and unstaged changes: %w", err)
-	}
-	if hasStagedChanges {
-		return fmt.Errorf("Please commit your staged changes before using cherry-pick.")
-	}
-	if hasUnstagedChanges {
-		return fmt.Errorf(`error: your local changes would be overwritten by cherry-pick.
+		hasStagedChanges, hasUnstagedChanges, err := hasStagedAndUnstagedChanged(queryist, sqlCtx)
+		if err != nil {
+			return fmt.Errorf("error: failed to check for staged and unstaged changes: %w", err)
+		}
+		if hasStagedChanges {
+			return fmt.Errorf("Please commit your staged changes before using cherry-pick.")
+		}
+		if hasUnstagedChanges {
+			return fmt.Errorf(`error: your local changes would be overwritten by cherry-pick.
 hint: commit your changes (dolt commit -am \"<message>\") or reset them (dolt reset --hard) to proceed.`)
+		}
 	}
 
-	_, err = GetRowsForSql(queryist, sqlCtx, "set @@dolt_allow_commit_conflicts = 1")
+	_, err := GetRowsForSql(queryist, sqlCtx, "set @@dolt_allow_commit_conflicts = 1")
 	if err != nil {
 		return fmt.Errorf("error: failed to set @@dolt_allow_commit_conflicts: %w", err)
 	}
@@ -145,7 +155,11 @@ hint: commit your changes (dolt commit -am \"<message>\") or reset them (dolt re
 		return fmt.Errorf("error: failed to set @@dolt_force_transaction_commit: %w", err)
 	}
 
-	q, err := dbr.InterpolateForDialect("call dolt_cherry_pick(?)", []interface{}{cherryStr}, dialect.MySQL)
+	params := make([]interface{}, len(args))
+	for i, arg := range args {
+		params[i] = arg
+	}
+	q, err := dbr.InterpolateForDialect("call dolt_cherry_pick("+strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")+")", params, dialect.MySQL)
 	if err != nil {
 		return fmt.Errorf("error: failed to interpolate query: %w", err)
 	}
@@ -165,7 +179,7 @@ hint: commit your changes (dolt commit -am \"<message>\") or reset them (dolt re
 		return fmt.Errorf("error: unexpected number of rows returned from dolt_cherry_pick: %d", len(rows))
 	}
 
-	succeeded := false
+	hasConflicts := false
 	commitHash := ""
 	for _, row := range rows {
 		commitHash = row[0].(string)
@@ -182,13 +196,18 @@ hint: commit your changes (dolt commit -am \"<message>\") or reset them (dolt re
 			return fmt.Errorf("Unable to parse constraint_violations column: %w", err)
 		}
 
-		// if we have a hash and all 0s, then the cherry-pick succeeded
-		if len(commitHash) > 0 && dataConflicts == 0 && schemaConflicts == 0 && constraintViolations == 0 {
-			succeeded = true
+		if dataConflicts != 0 || schemaConflicts != 0 || constraintViolations != 0 {
+			hasConflicts = true
 		}
 	}
 
-	if succeeded {
+	if hasConflicts {
+		// this failure could only have been caused by constraint violations or conflicts during cherry-pick
+		return ErrCherryPickConflictsOrViolations.New()
+	} else if len(commitHash) == 0 {
+		// skipping the only commit being cherry-picked doesn't create a commit
+		return nil
+	} else {
 		// on success, print the commit info
 		commit, err := getCommitInfo(queryist, sqlCtx, commitHash)
 		if err != nil {
@@ -203,9 +222,6 @@ hint: commit your changes (dolt commit -am \"<message>\") or reset them (dolt re
 		})
 
 		return nil
-	} else {
-		// this failure could only have been caused by constraint violations or conflicts during cherry-pick
-		return ErrCherryPickConflictsOrViolations.New()
 	}
 }
 
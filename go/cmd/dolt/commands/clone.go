@@ -43,9 +43,13 @@ var cloneDocs = cli.CommandDocumentationContent{
 After the clone, a plain {{.EmphasisLeft}}dolt fetch{{.EmphasisRight}} without arguments will update all the remote-tracking branches, and a {{.EmphasisLeft}}dolt pull{{.EmphasisRight}} without arguments will in addition merge the remote branch into the current branch.
 
 This default configuration is achieved by creating references to the remote branch heads under {{.LessThan}}refs/remotes/origin{{.GreaterThan}}  and by creating a remote named 'origin'.
+
+If {{.EmphasisLeft}}--depth{{.EmphasisRight}} is supplied, a shallow clone is created. Only the branch being cloned is fetched, and only the specified number of its most recent commits. The oldest of them are treated as having no parents. More history can be fetched into a shallow clone later with {{.EmphasisLeft}}dolt fetch --deepen{{.EmphasisRight}}. Shallow clones are only supported for databases in the __DOLT__ format.
+
+If {{.EmphasisLeft}}--tables{{.EmphasisRight}} is supplied, a sparse clone is created. It holds the data of only the specified tables, in every commit of every branch, and fetches the data of the other tables from the remote the first time it's read. The tables are listed, one per line, in the sparse spec at {{.LessThan}}.dolt/sparse{{.GreaterThan}}, which later fetches and pulls also follow. Tables added to the spec have their data included in the commits pulled after that. Sparse clones are only supported for databases in the __DOLT__ format, and can't also be shallow.
 `,
 	Synopsis: []string{
-		"[-remote {{.LessThan}}remote{{.GreaterThan}}] [-branch {{.LessThan}}branch{{.GreaterThan}}]  [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] {{.LessThan}}remote-url{{.GreaterThan}} {{.LessThan}}new-dir{{.GreaterThan}}",
+		"[-remote {{.LessThan}}remote{{.GreaterThan}}] [-branch {{.LessThan}}branch{{.GreaterThan}}] [--depth {{.LessThan}}depth{{.GreaterThan}}] [--tables {{.LessThan}}table1{{.GreaterThan}},{{.LessThan}}table2{{.GreaterThan}}...]  [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] {{.LessThan}}remote-url{{.GreaterThan}} {{.LessThan}}new-dir{{.GreaterThan}}",
 	},
 }
 
@@ -98,6 +102,14 @@ func (cmd CloneCmd) Exec(ctx context.Context, commandStr string, args []string,
 func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEnv) errhand.VerboseError {
 	remoteName := apr.GetValueOrDefault(cli.RemoteParam, "origin")
 	branch := apr.GetValueOrDefault(cli.BranchParam, "")
+	depth := apr.GetIntOrDefault(cli.DepthParam, 0)
+	if apr.Contains(cli.DepthParam) && depth < 1 {
+		return errhand.BuildDError("error: --depth must be a positive number").Build()
+	}
+	tables, _ := apr.GetValueList(cli.TablesFlag)
+	if apr.Contains(cli.TablesFlag) && depth > 0 {
+		return errhand.BuildDError("error: --depth and --tables can't be used together").Build()
+	}
 	dir, urlStr, verr := parseArgs(apr)
 	if verr != nil {
 		return verr
@@ -143,7 +155,7 @@ func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEn
 	// Nil out the old Dolt env so we don't accidentally operate on the wrong database
 	dEnv = nil
 
-	err = actions.CloneRemote(ctx, srcDB, remoteName, branch, clonedEnv)
+	err = actions.CloneRemote(ctx, srcDB, remoteName, branch, depth, tables, clonedEnv)
 	if err != nil {
 		// If we're cloning into a directory that already exists do not erase it. Otherwise
 		// make best effort to delete the directory we created.
@@ -31,11 +31,19 @@ type AutoResolveStrategy int
 const (
 	AutoResolveStrategyOurs AutoResolveStrategy = iota
 	AutoResolveStrategyTheirs
+	AutoResolveStrategyCellMerge
 )
 
 // AutoResolveTables resolves all conflicts in the given tables according to the
 // given |strategy|.
 func AutoResolveTables(queryist cli.Queryist, sqlCtx *sql.Context, strategy AutoResolveStrategy, tbls []string) error {
+	if strategy == AutoResolveStrategyCellMerge {
+		// the conflicts with colliding columns are left unresolved
+		_, err := commands.GetRowsForSql(queryist, sqlCtx, "set @@dolt_allow_commit_conflicts = 1")
+		if err != nil {
+			return fmt.Errorf("failed to set @@dolt_allow_commit_conflicts: %w", err)
+		}
+	}
 
 	for _, tableName := range tbls {
 		resolveQuery := "CALL dolt_conflicts_resolve(?, ?)"
@@ -45,6 +53,8 @@ func AutoResolveTables(queryist cli.Queryist, sqlCtx *sql.Context, strategy Auto
 			resolveParams = []interface{}{"--ours", tableName}
 		case AutoResolveStrategyTheirs:
 			resolveParams = []interface{}{"--theirs", tableName}
+		case AutoResolveStrategyCellMerge:
+			resolveParams = []interface{}{"--cell-merge", tableName}
 		default:
 			return errors.New("invalid auto resolve strategy")
 		}
@@ -0,0 +1,420 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cnfcmds
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+)
+
+const (
+	conflictIdCol     = "dolt_conflict_id"
+	ourDiffTypeCol    = "our_diff_type"
+	theirDiffTypeCol  = "their_diff_type"
+	removedDiffType   = "removed"
+	maxCellValueWidth = 40
+)
+
+// errQuit is returned when the user quits before every conflict has been reviewed.
+var errQuit = errors.New("quit")
+
+// errAbort is returned when the user quits without saving, or input ends before the resolutions are saved.
+var errAbort = errors.New("aborted")
+
+// tableConflicts are the data conflicts of a table, as read from its dolt_conflicts_<table> table, and how the user
+// chose to resolve them.
+type tableConflicts struct {
+	name    string
+	sch     sql.Schema
+	rows    []sql.Row
+	cols    []string
+	pks     []string
+	keyless bool
+
+	// all is set when every conflict of the table is resolved with the same strategy
+	all      *AutoResolveStrategy
+	resolved []rowResolution
+}
+
+// rowResolution is how a conflicting row is resolved. Our version of the row is kept, except for the columns in
+// |copies|, which are given the value of another version of the column, or unless |deleteRow| is set, when their
+// deletion of the row is taken.
+type rowResolution struct {
+	id        interface{}
+	deleteRow bool
+	copies    [][2]string
+}
+
+// interactiveResolve walks the user through the data conflicts of |tbls|, row by row, and saves their choices to the
+// working set once they're done. Rows whose conflicts are skipped keep them.
+func interactiveResolve(queryist cli.Queryist, sqlCtx *sql.Context, tbls []string) error {
+	ms, err := getMergeStatus(queryist, sqlCtx)
+	if err != nil {
+		return fmt.Errorf("failed to get merge status: %w", err)
+	}
+	if len(tbls) == 1 && tbls[0] == "." {
+		tbls = ms.unmergedTables
+	}
+
+	var conflicts []*tableConflicts
+	for _, tbl := range tbls {
+		if tbl == "" {
+			continue
+		}
+		tc, err := loadTableConflicts(queryist, sqlCtx, tbl)
+		if err != nil {
+			return err
+		}
+		if len(tc.rows) > 0 {
+			conflicts = append(conflicts, tc)
+		}
+	}
+	if len(conflicts) == 0 {
+		cli.Println("There are no data conflicts to resolve.")
+		return nil
+	}
+
+	scanner := bufio.NewScanner(cli.InStream)
+	for _, tc := range conflicts {
+		err = reviewTableConflicts(scanner, tc)
+		if errors.Is(err, errQuit) {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+
+	count := 0
+	for _, tc := range conflicts {
+		if tc.all != nil {
+			count += len(tc.rows)
+		} else {
+			count += len(tc.resolved)
+		}
+	}
+	if count == 0 {
+		cli.Println("No conflicts were resolved.")
+		return nil
+	}
+
+	answer, err := prompt(scanner, fmt.Sprintf("Save the resolution of %d conflict(s)? [y/n] ", count), "y", "n")
+	if err != nil {
+		return err
+	}
+	if answer != "y" {
+		return errAbort
+	}
+
+	// each row is resolved by its own statements, which commit while the rest of the conflicts are still unresolved
+	if _, _, err = queryist.Query(sqlCtx, "set @@dolt_allow_commit_conflicts = 1"); err != nil {
+		return fmt.Errorf("failed to set @@dolt_allow_commit_conflicts: %w", err)
+	}
+	for _, tc := range conflicts {
+		if err = tc.save(queryist, sqlCtx); err != nil {
+			return fmt.Errorf("error resolving conflicts for table %s: %w", tc.name, err)
+		}
+	}
+	cli.Printf("Resolved %d conflict(s).\n", count)
+	return nil
+}
+
+// loadTableConflicts reads the data conflicts of the table |tbl|.
+func loadTableConflicts(queryist cli.Queryist, sqlCtx *sql.Context, tbl string) (*tableConflicts, error) {
+	q, err := dbr.InterpolateForDialect("SELECT * FROM ? LIMIT 0", []interface{}{dbr.I(tbl)}, dialect.MySQL)
+	if err != nil {
+		return nil, err
+	}
+	tblSch, iter, err := queryist.Query(sqlCtx, q)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = sql.RowIterToRows(sqlCtx, tblSch, iter); err != nil {
+		return nil, err
+	}
+
+	q, err = dbr.InterpolateForDialect("SELECT * FROM ?", []interface{}{dbr.I("dolt_conflicts_" + tbl)}, dialect.MySQL)
+	if err != nil {
+		return nil, err
+	}
+	sch, iter, err := queryist.Query(sqlCtx, q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := sql.RowIterToRows(sqlCtx, sch, iter)
+	if err != nil {
+		return nil, err
+	}
+
+	tc := &tableConflicts{name: tbl, sch: sch, rows: rows, keyless: true}
+	for _, col := range tblSch {
+		if col.PrimaryKey {
+			tc.pks = append(tc.pks, col.Name)
+			tc.keyless = false
+		}
+	}
+	for _, col := range sch {
+		if strings.HasPrefix(col.Name, ourPrefix) && col.Name != ourDiffTypeCol {
+			tc.cols = append(tc.cols, col.Name[len(ourPrefix):])
+		}
+	}
+	return tc, nil
+}
+
+// reviewTableConflicts asks the user how to resolve the conflicts of |tc|.
+func reviewTableConflicts(scanner *bufio.Scanner, tc *tableConflicts) error {
+	cli.Printf("\nTable %s has %d conflict(s).\n", tc.name, len(tc.rows))
+	if tc.keyless {
+		cli.Println("The rows of keyless tables can only be resolved all at once.")
+	}
+
+	for {
+		answer, err := prompt(scanner, "Take [o]urs or [t]heirs for all rows, [r]eview each row, [s]kip the table, [q]uit? ", "o", "t", "r", "s", "q")
+		if err != nil {
+			return err
+		}
+		switch answer {
+		case "o":
+			strategy := AutoResolveStrategyOurs
+			tc.all = &strategy
+			return nil
+		case "t":
+			strategy := AutoResolveStrategyTheirs
+			tc.all = &strategy
+			return nil
+		case "s":
+			return nil
+		case "q":
+			return errQuit
+		case "r":
+			if tc.keyless {
+				continue
+			}
+			return reviewRowConflicts(scanner, tc)
+		}
+	}
+}
+
+// reviewRowConflicts asks the user how to resolve each conflicting row of |tc|.
+func reviewRowConflicts(scanner *bufio.Scanner, tc *tableConflicts) error {
+	var rest string
+	for i, row := range tc.rows {
+		answer := rest
+		if answer == "" {
+			cli.Printf("\nConflict %d of %d in %s:\n", i+1, len(tc.rows), tc.name)
+			printConflictRow(tc, row)
+
+			var err error
+			for answer == "" {
+				answer, err = prompt(scanner, "Take [o]urs, [t]heirs, pick [c]ells, [s]kip, [O]urs or [T]heirs for the rest, [q]uit? ", "o", "t", "c", "s", "O", "T", "q")
+				if err != nil {
+					return err
+				}
+				if answer == "c" && (tc.isRemoved(row, ourDiffTypeCol) || tc.isRemoved(row, theirDiffTypeCol)) {
+					cli.Println("The row was deleted on one side, so its cells can't be picked.")
+					answer = ""
+				}
+			}
+			if answer == "O" || answer == "T" {
+				answer = strings.ToLower(answer)
+				rest = answer
+			}
+		}
+
+		switch answer {
+		case "o":
+			tc.resolved = append(tc.resolved, rowResolution{id: tc.value(row, conflictIdCol)})
+		case "t":
+			tc.resolved = append(tc.resolved, tc.takeTheirs(row))
+		case "c":
+			res, err := pickCells(scanner, tc, row)
+			if err != nil {
+				return err
+			}
+			tc.resolved = append(tc.resolved, res)
+		case "q":
+			return errQuit
+		}
+	}
+	return nil
+}
+
+// pickCells asks the user which version of each conflicting cell of |row| to take. Both sides must have the row.
+func pickCells(scanner *bufio.Scanner, tc *tableConflicts, row sql.Row) (rowResolution, error) {
+	res := rowResolution{id: tc.value(row, conflictIdCol)}
+	for _, col := range tc.cols {
+		ours, theirs := tc.cell(row, ourPrefix+col), tc.cell(row, theirPrefix+col)
+		if ours == theirs {
+			continue
+		}
+		base := tc.cell(row, basePrefix+col)
+		answer, err := prompt(scanner, fmt.Sprintf("%s: [o]urs %s, [t]heirs %s, [b]ase %s? ", col, ours, theirs, base), "o", "t", "b")
+		if err != nil {
+			return rowResolution{}, err
+		}
+		switch answer {
+		case "t":
+			res.copies = append(res.copies, [2]string{ourPrefix + col, theirPrefix + col})
+		case "b":
+			res.copies = append(res.copies, [2]string{ourPrefix + col, basePrefix + col})
+		}
+	}
+	return res, nil
+}
+
+func (tc *tableConflicts) takeTheirs(row sql.Row) rowResolution {
+	res := rowResolution{id: tc.value(row, conflictIdCol)}
+	if tc.isRemoved(row, theirDiffTypeCol) {
+		res.deleteRow = !tc.isRemoved(row, ourDiffTypeCol)
+		return res
+	}
+	for _, col := range tc.cols {
+		res.copies = append(res.copies, [2]string{ourPrefix + col, theirPrefix + col})
+	}
+	return res
+}
+
+// save applies the resolutions of |tc| to the working set, and removes the conflicts they resolve.
+func (tc *tableConflicts) save(queryist cli.Queryist, sqlCtx *sql.Context) error {
+	if tc.all != nil {
+		return AutoResolveTables(queryist, sqlCtx, *tc.all, []string{tc.name})
+	}
+
+	conflictsTbl := dbr.I("dolt_conflicts_" + tc.name)
+	for _, res := range tc.resolved {
+		if res.deleteRow {
+			// the conflicts table can't delete rows of the table, so the row is deleted by the primary key of our version
+			conds := make([]string, len(tc.pks))
+			params := make([]interface{}, 0, 2+3*len(tc.pks))
+			params = append(params, dbr.I(tc.name))
+			for i, pk := range tc.pks {
+				conds[i] = "? = (SELECT ? FROM ? WHERE ? = ?)"
+				params = append(params, dbr.I(pk), dbr.I(ourPrefix+pk), conflictsTbl, dbr.I(conflictIdCol), res.id)
+			}
+			if _, err := commands.InterpolateAndRunQuery(queryist, sqlCtx, "DELETE FROM ? WHERE "+strings.Join(conds, " AND "), params...); err != nil {
+				return err
+			}
+		} else if len(res.copies) > 0 {
+			sets := make([]string, len(res.copies))
+			params := make([]interface{}, 0, 3+2*len(res.copies))
+			params = append(params, conflictsTbl)
+			for i, c := range res.copies {
+				sets[i] = "? = ?"
+				params = append(params, dbr.I(c[0]), dbr.I(c[1]))
+			}
+			params = append(params, dbr.I(conflictIdCol), res.id)
+			if _, err := commands.InterpolateAndRunQuery(queryist, sqlCtx, "UPDATE ? SET "+strings.Join(sets, ", ")+" WHERE ? = ?", params...); err != nil {
+				return err
+			}
+		}
+
+		if _, err := commands.InterpolateAndRunQuery(queryist, sqlCtx, "DELETE FROM ? WHERE ? = ?", conflictsTbl, dbr.I(conflictIdCol), res.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tc *tableConflicts) value(row sql.Row, col string) interface{} {
+	if i := tc.sch.IndexOfColName(col); i >= 0 {
+		return row[i]
+	}
+	return nil
+}
+
+func (tc *tableConflicts) isRemoved(row sql.Row, diffTypeCol string) bool {
+	return fmt.Sprint(tc.value(row, diffTypeCol)) == removedDiffType
+}
+
+// cell returns the value of the column |col| of the conflict |row|, formatted to be printed.
+func (tc *tableConflicts) cell(row sql.Row, col string) string {
+	if tc.sch.IndexOfColName(col) < 0 {
+		return "-"
+	}
+	var s string
+	switch v := tc.value(row, col).(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		s = string(v)
+	default:
+		s = fmt.Sprint(v)
+	}
+	if len(s) > maxCellValueWidth {
+		s = s[:maxCellValueWidth-3] + "..."
+	}
+	return s
+}
+
+// printConflictRow prints the base, our and their versions of each column of the conflict |row|, marking the columns
+// whose values differ between ours and theirs with a *.
+func printConflictRow(tc *tableConflicts, row sql.Row) {
+	header := []string{"", "column", "base", "ours (" + tc.cell(row, ourDiffTypeCol) + ")", "theirs (" + tc.cell(row, theirDiffTypeCol) + ")"}
+	lines := [][]string{header}
+	for _, col := range tc.cols {
+		ours, theirs := tc.cell(row, ourPrefix+col), tc.cell(row, theirPrefix+col)
+		mark := ""
+		if ours != theirs {
+			mark = "*"
+		}
+		lines = append(lines, []string{mark, col, tc.cell(row, basePrefix+col), ours, theirs})
+	}
+
+	widths := make([]int, len(header))
+	for _, line := range lines {
+		for i, s := range line {
+			if len(s) > widths[i] {
+				widths[i] = len(s)
+			}
+		}
+	}
+	for _, line := range lines {
+		var sb strings.Builder
+		for i, s := range line {
+			sb.WriteString(fmt.Sprintf("%-*s  ", widths[i], s))
+		}
+		cli.Println(strings.TrimRight(sb.String(), " "))
+	}
+}
+
+// prompt prints |msg| and reads answers from |scanner| until one of |options| is given. Once input ends, errAbort is
+// returned.
+func prompt(scanner *bufio.Scanner, msg string, options ...string) (string, error) {
+	for {
+		cli.Print(msg)
+		if !scanner.Scan() {
+			cli.Println()
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", errAbort
+		}
+		answer := strings.TrimSpace(scanner.Text())
+		for _, o := range options {
+			if answer == o {
+				return answer, nil
+			}
+		}
+		cli.Printf("Please answer one of %s.\n", strings.Join(options, ", "))
+	}
+}
@@ -16,6 +16,7 @@ package cnfcmds
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -34,9 +35,14 @@ var resDocumentation = cli.CommandDocumentationContent{
 	When a merge finds conflicting changes, it documents them in the dolt_conflicts table. A conflict is between two versions: ours (the rows at the destination branch head) and theirs (the rows at the source branch head).
 
 	dolt conflicts resolve will automatically resolve the conflicts by taking either the ours or theirs versions for each row.
+
+	With {{.EmphasisLeft}}--cell-merge{{.EmphasisRight}}, the columns of each conflicting row are merged instead: a column changed on only one branch takes that branch's value. The conflicts of rows that merge this way are resolved, and only the rows with a column changed differently on both branches remain conflicts.
+
+	With {{.EmphasisLeft}}--interactive{{.EmphasisRight}}, the conflicts of each table are shown one row at a time, with the base, ours and theirs versions of each column, and can be resolved by taking ours or theirs for the row, or by picking the version of each conflicting cell. Every conflict of a table can also be resolved with ours or theirs at once. The chosen resolutions are saved to the working set once every conflict has been reviewed, or when quitting. Conflicts that are skipped remain.
 `,
 	Synopsis: []string{
-		`--ours|--theirs {{.LessThan}}table{{.GreaterThan}}...`,
+		`--ours|--theirs|--cell-merge {{.LessThan}}table{{.GreaterThan}}...`,
+		`--interactive {{.LessThan}}table{{.GreaterThan}}...`,
 	},
 }
 
@@ -46,8 +52,9 @@ const (
 )
 
 var autoResolveStrategies = map[string]AutoResolveStrategy{
-	oursFlag:   AutoResolveStrategyOurs,
-	theirsFlag: AutoResolveStrategyTheirs,
+	oursFlag:          AutoResolveStrategyOurs,
+	theirsFlag:        AutoResolveStrategyTheirs,
+	cli.CellMergeFlag: AutoResolveStrategyCellMerge,
 }
 
 var autoResolverParams []string
@@ -86,6 +93,8 @@ func (cmd ResolveCmd) ArgParser() *argparser.ArgParser {
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"table", "List of tables to be resolved. '.' can be used to resolve all tables."})
 	ap.SupportsFlag("ours", "", "For all conflicts, take the version from our branch and resolve the conflict")
 	ap.SupportsFlag("theirs", "", "For all conflicts, take the version from their branch and resolve the conflict")
+	ap.SupportsFlag(cli.CellMergeFlag, "", "For all conflicts, merge the columns changed on each branch, and resolve the conflicts where no column was changed on both")
+	ap.SupportsFlag(cli.InteractiveFlag, "i", "Review the conflicts row by row, and choose how to resolve each of them")
 	return ap
 }
 
@@ -104,7 +113,9 @@ func (cmd ResolveCmd) Exec(ctx context.Context, commandStr string, args []string
 	}
 
 	var verr errhand.VerboseError
-	if apr.ContainsAny(autoResolverParams...) {
+	if apr.Contains(cli.InteractiveFlag) {
+		verr = resolveInteractively(queryist, sqlCtx, apr)
+	} else if apr.ContainsAny(autoResolverParams...) {
 		verr = autoResolve(queryist, sqlCtx, apr)
 	} else {
 		verr = errhand.BuildDError("--ours or --theirs must be supplied").SetPrintUsage().Build()
@@ -135,3 +146,20 @@ func autoResolve(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgP
 	}
 	return nil
 }
+
+func resolveInteractively(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgParseResults) errhand.VerboseError {
+	if apr.ContainsAny(autoResolverParams...) {
+		return errhand.BuildDError("--interactive can't be used with --ours, --theirs or --cell-merge").SetPrintUsage().Build()
+	} else if apr.NArg() == 0 {
+		return errhand.BuildDError("specify at least one table to resolve conflicts").SetPrintUsage().Build()
+	}
+
+	err := interactiveResolve(queryist, sqlCtx, apr.Args)
+	if errors.Is(err, errAbort) {
+		cli.Println("No conflicts were resolved.")
+		return nil
+	} else if err != nil {
+		return errhand.BuildDError("error: failed to resolve").AddCause(err).Build()
+	}
+	return nil
+}
@@ -53,7 +53,9 @@ The content to be added can be specified by using dolt add to incrementally \"ad
 
 The log message can be added with the parameter {{.EmphasisLeft}}-m <msg>{{.EmphasisRight}}.  If the {{.LessThan}}-m{{.GreaterThan}} parameter is not provided an editor will be opened where you can review the commit and provide a log message.
 
-The commit timestamp can be modified using the --date parameter.  Dates can be specified in the formats {{.LessThan}}YYYY-MM-DD{{.GreaterThan}}, {{.LessThan}}YYYY-MM-DDTHH:MM:SS{{.GreaterThan}}, or {{.LessThan}}YYYY-MM-DDTHH:MM:SSZ07:00{{.GreaterThan}} (where {{.LessThan}}07:00{{.GreaterThan}} is the time zone offset)."`,
+The commit timestamp can be modified using the --date parameter.  Dates can be specified in the formats {{.LessThan}}YYYY-MM-DD{{.GreaterThan}}, {{.LessThan}}YYYY-MM-DDTHH:MM:SS{{.GreaterThan}}, or {{.LessThan}}YYYY-MM-DDTHH:MM:SSZ07:00{{.GreaterThan}} (where {{.LessThan}}07:00{{.GreaterThan}} is the time zone offset).
+
+The commit can be signed with {{.EmphasisLeft}}-S{{.EmphasisRight}}, using gpg or ssh-keygen and the key configured by {{.EmphasisLeft}}user.signingkey{{.EmphasisRight}}, as described by {{.EmphasisLeft}}dolt config{{.EmphasisRight}}. Signatures can be checked with {{.EmphasisLeft}}dolt verify-commit{{.EmphasisRight}} or {{.EmphasisLeft}}dolt log --show-signature{{.EmphasisRight}}."`,
 	Synopsis: []string{
 		"[options]",
 	},
@@ -265,6 +267,10 @@ func constructParametrizedDoltCommitQuery(msg string, apr *argparser.ArgParseRes
 		writeToBuffer("--skip-empty")
 	}
 
+	if apr.Contains(cli.GpgSignFlag) {
+		writeToBuffer("-S")
+	}
+
 	buffer.WriteString(")")
 	return buffer.String(), params, nil
 }
@@ -349,13 +355,7 @@ func getCommitMessageFromEditor(sqlCtx *sql.Context, queryist cli.Queryist, sugg
 		initialMsg = fmt.Sprintf("%s\n%s", amendString, initialMsg)
 	}
 
-	backupEd := "vim"
-	// try getting default editor on the user system
-	if ed, edSet := os.LookupEnv(dconfig.EnvEditor); edSet {
-		backupEd = ed
-	}
-	// try getting Dolt config core.editor
-	editorStr := cliCtx.Config().GetStringOrDefault(env.DoltEditor, backupEd)
+	editorStr := getEditorString(cliCtx)
 
 	cli.ExecuteWithStdioRestored(func() {
 		commitMsg, cErr := editor.OpenCommitEditor(editorStr, initialMsg)
@@ -372,6 +372,18 @@ func getCommitMessageFromEditor(sqlCtx *sql.Context, queryist cli.Queryist, sugg
 	return finalMsg, nil
 }
 
+// getEditorString returns the editor to open, from the Dolt config core.editor, the EDITOR environment variable, or
+// vim, in that order.
+func getEditorString(cliCtx cli.CliContext) string {
+	backupEd := "vim"
+	// try getting default editor on the user system
+	if ed, edSet := os.LookupEnv(dconfig.EnvEditor); edSet {
+		backupEd = ed
+	}
+	// try getting Dolt config core.editor
+	return cliCtx.Config().GetStringOrDefault(env.DoltEditor, backupEd)
+}
+
 func checkIsTerminal() bool {
 	isTerminal := false
 	cli.ExecuteWithStdioRestored(func() {
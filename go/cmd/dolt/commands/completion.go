@@ -0,0 +1,509 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+// The shells that dolt completion writes scripts for.
+const (
+	BashShell       = "bash"
+	FishShell       = "fish"
+	PowerShellShell = "powershell"
+)
+
+// The kinds of names the completion scripts fetch from the repository, with dolt completion __complete, when completing
+// the arguments of a command.
+const (
+	completeBranches = "branch"
+	completeTables   = "table"
+)
+
+// completionArgKinds are the kinds of names completed for the arguments of the commands whose arg parsers don't name
+// their arguments in ArgListHelp.
+var completionArgKinds = map[string][]string{
+	"dolt blame":       {completeTables},
+	"dolt branch":      {completeBranches},
+	"dolt checkout":    {completeBranches, completeTables},
+	"dolt cherry-pick": {completeBranches},
+	"dolt diff":        {completeBranches, completeTables},
+	"dolt log":         {completeBranches, completeTables},
+	"dolt merge":       {completeBranches},
+	"dolt merge-base":  {completeBranches},
+	"dolt reset":       {completeBranches, completeTables},
+	"dolt revert":      {completeBranches},
+	"dolt show":        {completeBranches},
+}
+
+// argListHelpKinds maps the argument names used in ArgListHelp to the kinds of names completed for them.
+var argListHelpKinds = map[string]string{
+	"table":       completeTables,
+	"oldtable":    completeTables,
+	"revision":    completeBranches,
+	"ref":         completeBranches,
+	"start-point": completeBranches,
+	"commit":      completeBranches,
+}
+
+var completionDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Generates a shell completion script for dolt",
+	LongDesc: `Writes a script to STDOUT that completes dolt commands, their flags, and branch and table names in the {{.LessThan}}shell{{.GreaterThan}} it is named for.
+
+To enable completion in bash, add this line to your .bashrc file:
+
+  source <(dolt completion bash)
+
+In fish, run:
+
+  dolt completion fish > ~/.config/fish/completions/dolt.fish
+
+In PowerShell, add this line to your profile:
+
+  dolt completion powershell | Out-String | Invoke-Expression
+`,
+}
+
+// GenCompletionCmd writes a completion script for Shell that covers every command of DoltCommand.
+type GenCompletionCmd struct {
+	DoltCommand cli.SubCommandHandler
+	Shell       string
+}
+
+var _ cli.Command = &GenCompletionCmd{}
+
+func (c *GenCompletionCmd) Name() string {
+	return c.Shell
+}
+
+func (c *GenCompletionCmd) Description() string {
+	return fmt.Sprintf("Generates a %s completion script for dolt.", c.Shell)
+}
+
+func (c *GenCompletionCmd) Docs() *cli.CommandDocumentation {
+	ap := c.ArgParser()
+	return cli.NewCommandDocumentation(completionDocs, ap)
+}
+
+func (c *GenCompletionCmd) ArgParser() *argparser.ArgParser {
+	return argparser.NewArgParserWithMaxArgs(c.Name(), 0)
+}
+
+// RequiresRepo should return false if this interface is implemented, and the command does not have the requirement
+// that it be run from within a data repository directory
+func (c *GenCompletionCmd) RequiresRepo() bool {
+	return false
+}
+
+func (c *GenCompletionCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := c.ArgParser()
+	help, _ := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, completionDocs, ap))
+	cli.ParseArgsOrDie(ap, args, help)
+
+	nodes := completionNodes(c.DoltCommand)
+
+	var err error
+	switch c.Shell {
+	case BashShell:
+		err = writeBashCompletion(cli.OutStream, dEnv.Version, nodes)
+	case FishShell:
+		err = writeFishCompletion(cli.OutStream, dEnv.Version, nodes)
+	case PowerShellShell:
+		err = writePowerShellCompletion(cli.OutStream, dEnv.Version, nodes)
+	default:
+		err = fmt.Errorf("unsupported shell '%s'", c.Shell)
+	}
+
+	if err != nil {
+		verr := errhand.BuildDError("error: Failed to write %s completion script.", c.Shell).AddCause(err).Build()
+		cli.PrintErrln(verr.Verbose())
+		return 1
+	}
+	return 0
+}
+
+// CompleteNamesCmd prints the branch or table names the completion scripts offer for the arguments of a command, one
+// per line. It is called by the scripts, and prints nothing when it isn't run in a repository.
+type CompleteNamesCmd struct{}
+
+var _ cli.Command = CompleteNamesCmd{}
+
+func (c CompleteNamesCmd) Name() string {
+	return "__complete"
+}
+
+func (c CompleteNamesCmd) Description() string {
+	return "Prints the branch and table names used by the completion scripts"
+}
+
+func (c CompleteNamesCmd) Docs() *cli.CommandDocumentation {
+	return nil
+}
+
+func (c CompleteNamesCmd) ArgParser() *argparser.ArgParser {
+	return argparser.NewArgParserWithVariableArgs(c.Name())
+}
+
+// Hidden should return true if this command should be hidden from the help text
+func (c CompleteNamesCmd) Hidden() bool {
+	return true
+}
+
+// RequiresRepo should return false if this interface is implemented, and the command does not have the requirement
+// that it be run from within a data repository directory
+func (c CompleteNamesCmd) RequiresRepo() bool {
+	return false
+}
+
+func (c CompleteNamesCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	if !dEnv.Valid() {
+		return 0
+	}
+
+	for _, kind := range args {
+		switch kind {
+		case completeBranches:
+			branches, err := dEnv.DoltDB.GetBranches(ctx)
+			if err != nil {
+				return 1
+			}
+			for _, b := range branches {
+				cli.Println(b.GetPath())
+			}
+		case completeTables:
+			root, err := dEnv.WorkingRoot(ctx)
+			if err != nil {
+				return 1
+			}
+			tblNames, err := doltdb.GetNonSystemTableNames(ctx, root)
+			if err != nil {
+				return 1
+			}
+			for _, tbl := range tblNames {
+				cli.Println(tbl)
+			}
+		}
+	}
+	return 0
+}
+
+// completionNode is a command in the command tree, with what the completion scripts offer after it.
+type completionNode struct {
+	// path is the command line that invokes the command, e.g. "dolt schema export"
+	path        string
+	subcommands []cli.Command
+	options     []*argparser.Option
+	// argKinds are the kinds of names completed for the command's arguments
+	argKinds []string
+}
+
+// completionNodes returns a node for every command in |doltCommand| that isn't hidden, parents before children.
+func completionNodes(doltCommand cli.SubCommandHandler) []completionNode {
+	return appendCompletionNodes(nil, doltCommand.Name(), doltCommand)
+}
+
+func appendCompletionNodes(nodes []completionNode, path string, command cli.Command) []completionNode {
+	node := completionNode{path: path}
+	if subCmdHandler, ok := command.(cli.SubCommandHandler); ok {
+		for _, sub := range subCmdHandler.Subcommands {
+			if hidCmd, ok := sub.(cli.HiddenCommand); ok && hidCmd.Hidden() {
+				continue
+			}
+			node.subcommands = append(node.subcommands, sub)
+		}
+		nodes = append(nodes, node)
+		for _, sub := range node.subcommands {
+			nodes = appendCompletionNodes(nodes, path+" "+sub.Name(), sub)
+		}
+		return nodes
+	}
+
+	ap := command.ArgParser()
+	kinds := completionArgKinds[path]
+	if ap != nil {
+		node.options = ap.Supported
+		for _, arg := range ap.ArgListHelp {
+			if kind, ok := argListHelpKinds[arg[0]]; ok {
+				kinds = append(kinds, kind)
+			}
+		}
+	}
+	for _, kind := range kinds {
+		if !containsString(node.argKinds, kind) {
+			node.argKinds = append(node.argKinds, kind)
+		}
+	}
+	return append(nodes, node)
+}
+
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
+
+// subcommandNames returns the names of the subcommands of |node|, separated by spaces.
+func (node completionNode) subcommandNames() string {
+	names := make([]string, len(node.subcommands))
+	for i, sub := range node.subcommands {
+		names[i] = sub.Name()
+	}
+	return strings.Join(names, " ")
+}
+
+// optionNames returns the long and short forms of the options of |node|, separated by spaces.
+func (node completionNode) optionNames() string {
+	var names []string
+	for _, opt := range node.options {
+		if opt.Name != "" {
+			names = append(names, "--"+opt.Name)
+		}
+		if opt.Abbrev != "" {
+			names = append(names, "-"+opt.Abbrev)
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+// completionDesc returns the first line of |desc|, without markdown.
+func completionDesc(desc string) string {
+	desc = markdownRegex.ReplaceAllString(desc, "")
+	if i := strings.Index(desc, "\n"); i >= 0 {
+		desc = desc[:i]
+	}
+	return strings.TrimSpace(desc)
+}
+
+const (
+	bashCompletionPreamble = `# Generated with dolt completion bash for dolt version %s
+#
+# To install, add this line to your .bashrc file:
+#
+# source <(dolt completion bash)
+
+`
+
+	bashCompletionFunc = `_dolt() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local path="dolt" word i
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        word="${COMP_WORDS[i]}"
+        if [[ " $(_dolt_subcommands "$path") " == *" $word "* ]]; then
+            path="$path $word"
+        fi
+    done
+
+    local candidates
+    if [[ "$cur" == -* ]]; then
+        candidates="$(_dolt_options "$path")"
+    else
+        candidates="$(_dolt_subcommands "$path")"
+        local kinds="$(_dolt_arg_kinds "$path")"
+        if [[ -n "$kinds" ]]; then
+            candidates="$candidates $(dolt completion __complete $kinds 2>/dev/null)"
+        fi
+    fi
+    COMPREPLY=($(compgen -W "$candidates" -- "$cur"))
+}
+
+complete -o default -F _dolt dolt
+`
+)
+
+// writeBashCompletion writes a bash completion script for |nodes|. Each node's subcommands, options and argument
+// kinds are looked up by its path in case statements.
+func writeBashCompletion(wr io.Writer, version string, nodes []completionNode) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, bashCompletionPreamble, version)
+
+	writeCase := func(funcName string, value func(completionNode) string) {
+		fmt.Fprintf(&sb, "%s() {\n    case \"$1\" in\n", funcName)
+		for _, node := range nodes {
+			if v := value(node); v != "" {
+				fmt.Fprintf(&sb, "        '%s') echo '%s' ;;\n", node.path, v)
+			}
+		}
+		sb.WriteString("    esac\n}\n\n")
+	}
+	writeCase("_dolt_subcommands", completionNode.subcommandNames)
+	writeCase("_dolt_options", completionNode.optionNames)
+	writeCase("_dolt_arg_kinds", func(node completionNode) string {
+		return strings.Join(node.argKinds, " ")
+	})
+
+	sb.WriteString(bashCompletionFunc)
+	_, err := wr.Write([]byte(sb.String()))
+	return err
+}
+
+const (
+	fishCompletionPreamble = `# Generated with dolt completion fish for dolt version %s
+#
+# To install, write this file to ~/.config/fish/completions/dolt.fish
+
+`
+
+	fishCompletionFuncs = `
+function __dolt_path
+    set -l words (commandline -opc)
+    set -e words[1]
+    set -l path dolt
+    for word in $words
+        if contains -- $word (__dolt_subcommands "$path")
+            set path "$path $word"
+        end
+    end
+    echo $path
+end
+
+function __dolt_path_is
+    test (__dolt_path) = "$argv[1]"
+end
+
+`
+)
+
+// writeFishCompletion writes a fish completion script for |nodes|. Each completion is conditioned on the command path
+// typed so far.
+func writeFishCompletion(wr io.Writer, version string, nodes []completionNode) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, fishCompletionPreamble, version)
+
+	sb.WriteString("function __dolt_subcommands\n    switch $argv[1]\n")
+	for _, node := range nodes {
+		if names := node.subcommandNames(); names != "" {
+			fmt.Fprintf(&sb, "        case '%s'\n            printf '%%s\\n' %s\n", node.path, names)
+		}
+	}
+	sb.WriteString("    end\nend\n")
+	sb.WriteString(fishCompletionFuncs)
+
+	for _, node := range nodes {
+		cond := fmt.Sprintf(`complete -c dolt -n '__dolt_path_is "%s"'`, node.path)
+		for _, sub := range node.subcommands {
+			fmt.Fprintf(&sb, "%s -f -a '%s' -d '%s'\n", cond, sub.Name(), fishQuote(completionDesc(sub.Description())))
+		}
+		for _, opt := range node.options {
+			var args []string
+			if opt.Name != "" {
+				args = append(args, "-l "+opt.Name)
+			}
+			if opt.Abbrev != "" {
+				args = append(args, "-s "+opt.Abbrev)
+			}
+			if opt.OptType == argparser.OptionalValue {
+				args = append(args, "-r")
+			}
+			if desc := completionDesc(opt.Desc); desc != "" {
+				args = append(args, fmt.Sprintf("-d '%s'", fishQuote(desc)))
+			}
+			fmt.Fprintf(&sb, "%s %s\n", cond, strings.Join(args, " "))
+		}
+		if len(node.argKinds) > 0 {
+			fmt.Fprintf(&sb, "%s -f -a '(dolt completion __complete %s 2>/dev/null)'\n", cond, strings.Join(node.argKinds, " "))
+		}
+	}
+
+	_, err := wr.Write([]byte(sb.String()))
+	return err
+}
+
+// fishQuote escapes |s| for use in a single quoted fish string.
+func fishQuote(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+}
+
+const (
+	powerShellCompletionPreamble = `# Generated with dolt completion powershell for dolt version %s
+#
+# To install, add this line to your PowerShell profile:
+#
+# dolt completion powershell | Out-String | Invoke-Expression
+
+Register-ArgumentCompleter -Native -CommandName dolt -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+`
+
+	powerShellCompletionFunc = `
+    $path = 'dolt'
+    foreach ($element in $commandAst.CommandElements | Select-Object -Skip 1) {
+        if ($element.Extent.EndOffset -ge $cursorPosition) {
+            break
+        }
+        $word = $element.ToString()
+        if ($subcommands.ContainsKey($path) -and $subcommands[$path] -contains $word) {
+            $path = "$path $word"
+        }
+    }
+
+    $candidates = @()
+    if ($wordToComplete.StartsWith('-')) {
+        if ($options.ContainsKey($path)) {
+            $candidates += $options[$path]
+        }
+    } else {
+        if ($subcommands.ContainsKey($path)) {
+            $candidates += $subcommands[$path]
+        }
+        if ($argKinds.ContainsKey($path)) {
+            $candidates += & dolt completion __complete @($argKinds[$path]) 2>$null
+        }
+    }
+
+    $candidates | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`
+)
+
+// writePowerShellCompletion writes a PowerShell argument completer for |nodes|. Each node's subcommands, options and
+// argument kinds are looked up by its path in hashtables.
+func writePowerShellCompletion(wr io.Writer, version string, nodes []completionNode) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, powerShellCompletionPreamble, version)
+
+	writeTable := func(varName string, values func(completionNode) string) {
+		fmt.Fprintf(&sb, "    $%s = @{\n", varName)
+		for _, node := range nodes {
+			if v := values(node); v != "" {
+				fmt.Fprintf(&sb, "        '%s' = @('%s')\n", node.path, strings.ReplaceAll(v, " ", "', '"))
+			}
+		}
+		sb.WriteString("    }\n")
+	}
+	writeTable("subcommands", completionNode.subcommandNames)
+	writeTable("options", completionNode.optionNames)
+	writeTable("argKinds", func(node completionNode) string {
+		return strings.Join(node.argKinds, " ")
+	})
+
+	sb.WriteString(powerShellCompletionFunc)
+	_, err := wr.Write([]byte(sb.String()))
+	return err
+}
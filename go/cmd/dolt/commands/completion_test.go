@@ -0,0 +1,70 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+)
+
+var testCompletionCommand = cli.NewSubCommandHandler("dolt", "it's git for data", []cli.Command{
+	AddCmd{},
+	CheckoutCmd{},
+	cli.NewSubCommandHandler("stash", "stash changes", []cli.Command{StatusCmd{}}),
+	GenZshCompCmd{},
+})
+
+func TestCompletionNodes(t *testing.T) {
+	nodes := completionNodes(testCompletionCommand)
+	paths := make([]string, len(nodes))
+	for i, node := range nodes {
+		paths[i] = node.path
+	}
+	assert.Equal(t, []string{"dolt", "dolt add", "dolt checkout", "dolt stash", "dolt stash status"}, paths)
+
+	assert.Equal(t, "add checkout stash", nodes[0].subcommandNames())
+	assert.Equal(t, []string{completeTables}, nodes[1].argKinds)
+	assert.Equal(t, []string{completeBranches, completeTables}, nodes[2].argKinds)
+	assert.Contains(t, nodes[1].optionNames(), "--all -A")
+	assert.Equal(t, "status", nodes[3].subcommandNames())
+}
+
+func TestWriteCompletion(t *testing.T) {
+	nodes := completionNodes(testCompletionCommand)
+
+	var b bytes.Buffer
+	require.NoError(t, writeBashCompletion(&b, "1.0.0", nodes))
+	assert.Contains(t, b.String(), "dolt version 1.0.0")
+	assert.Contains(t, b.String(), "        'dolt') echo 'add checkout stash' ;;\n")
+	assert.Contains(t, b.String(), "        'dolt checkout') echo 'branch table' ;;\n")
+	assert.Contains(t, b.String(), "complete -o default -F _dolt dolt\n")
+
+	b.Reset()
+	require.NoError(t, writeFishCompletion(&b, "1.0.0", nodes))
+	assert.Contains(t, b.String(), "        case 'dolt stash'\n            printf '%s\\n' status\n")
+	assert.Contains(t, b.String(), `complete -c dolt -n '__dolt_path_is "dolt"' -f -a 'add' -d 'Add table changes to the list of staged table changes.'`)
+	assert.Contains(t, b.String(), `complete -c dolt -n '__dolt_path_is "dolt checkout"' -f -a '(dolt completion __complete branch table 2>/dev/null)'`)
+
+	b.Reset()
+	require.NoError(t, writePowerShellCompletion(&b, "1.0.0", nodes))
+	assert.Contains(t, b.String(), "        'dolt' = @('add', 'checkout', 'stash')\n")
+	assert.Contains(t, b.String(), "        'dolt add' = @('table')\n")
+	assert.NotContains(t, b.String(), "gen-zsh")
+}
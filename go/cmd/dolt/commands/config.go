@@ -53,16 +53,30 @@ Valid configuration variables:
 
 	- doltlab.insecure - boolean flag used to authenticate a client against DoltLab.
 
+	- gpg.format - the format of the signatures made by 'commit -S' and 'tag -s': openpgp (the default), which signs with gpg, or ssh, which signs with ssh-keygen.
+
+	- gpg.program - the program used to make and verify signatures, in place of gpg or ssh-keygen.
+
+	- gpg.ssh.allowedsignersfile - the ssh-keygen allowed signers file listing the keys that ssh signatures are trusted from.
+
 	- init.defaultbranch - allows overriding the default branch name e.g. when initializing a new repository.
 
 	- metrics.disabled - boolean flag disables sending metrics when true.
 
+	- metrics.emitter - comma separated list of where usage metrics are sent: file (the default, sends them to DoltHub), statsd, otlp or none.
+
+	- metrics.statsd_address - host:port of the StatsD server used by the statsd emitter. Defaults to localhost:8125.
+
+	- metrics.otlp_endpoint - URL of the OpenTelemetry collector metrics endpoint used by the otlp emitter. Defaults to http://localhost:4318/v1/metrics.
+
 	- user.creds - sets user keypairs for authenticating with doltremoteapi.
 
 	- user.email - sets name used in the author and committer field of commit objects.
 
 	- user.name - sets email used in the author and committer field of commit objects.
 
+	- user.signingkey - the key signed with by 'commit -S' and 'tag -s': a key id for openpgp signatures, which defaults to gpg's default key, or the path to the key for ssh signatures.
+
 	- remotes.default_host - sets default host for authenticating with doltremoteapi.
 
 	- remotes.default_port - sets default port for authenticating with doltremoteapi.
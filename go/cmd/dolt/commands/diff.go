@@ -68,6 +68,7 @@ const (
 	MergeBase   = "merge-base"
 	DiffMode    = "diff-mode"
 	ReverseFlag = "reverse"
+	BinaryFlag  = "binary"
 )
 
 var diffDocs = cli.CommandDocumentationContent{
@@ -94,6 +95,12 @@ The diffs displayed can be limited to show the first N by providing the paramete
 
 To filter which data rows are displayed, use {{.EmphasisLeft}}--where <SQL expression>{{.EmphasisRight}}. Table column names in the filter expression must be prefixed with {{.EmphasisLeft}}from_{{.EmphasisRight}} or {{.EmphasisLeft}}to_{{.EmphasisRight}}, e.g. {{.EmphasisLeft}}to_COLUMN_NAME > 100{{.EmphasisRight}} or {{.EmphasisLeft}}from_COLUMN_NAME + to_COLUMN_NAME = 0{{.EmphasisRight}}.
 
+A renamed table is shown as a rename of the table, rather than as a dropped table and an added table. Tables renamed with {{.EmphasisLeft}}RENAME TABLE{{.EmphasisRight}} are always detected. A dropped table and an added table with the same columns, such as a table recreated under a new name, are also shown as a rename if at least half of their rows are the same. Use {{.EmphasisLeft}}--find-renames <similarity>{{.EmphasisRight}} to change the percentage of rows that must be the same, or {{.EmphasisLeft}}--no-renames{{.EmphasisRight}} to show renamed tables as dropped and added tables.
+
+With tabular output, the values of binary columns ({{.EmphasisLeft}}BINARY{{.EmphasisRight}}, {{.EmphasisLeft}}VARBINARY{{.EmphasisRight}} and {{.EmphasisLeft}}BLOB{{.EmphasisRight}} types) are shown as a summary of their size and SHA-256 hash. Use {{.EmphasisLeft}}--binary{{.EmphasisRight}} to show their contents instead.
+
+An external diff driver can be configured for each column type with the {{.EmphasisLeft}}diff.driver.<type>{{.EmphasisRight}} config key, e.g. {{.EmphasisLeft}}dolt config --global --add diff.driver.blob "cmp -l"{{.EmphasisRight}}. With tabular output, each changed cell of a modified row in a column of that type is written to a pair of temporary files, the old and the new value, and the driver is run with their paths as its last two arguments. Its output is shown after the table's rows.
+
 The {{.EmphasisLeft}}--diff-mode{{.EmphasisRight}} argument controls how modified rows are presented when the format output is set to {{.EmphasisLeft}}tabular{{.EmphasisRight}}. When set to {{.EmphasisLeft}}row{{.EmphasisRight}}, modified rows are presented as old and new rows. When set to {{.EmphasisLeft}}line{{.EmphasisRight}}, modified rows are presented as a single row, and changes are presented using "+" and "-" within the column. When set to {{.EmphasisLeft}}in-place{{.EmphasisRight}}, modified rows are presented as a single row, and changes are presented side-by-side with a color distinction (requires a color-enabled terminal). When set to {{.EmphasisLeft}}context{{.EmphasisRight}}, rows that contain at least one column that spans multiple lines uses {{.EmphasisLeft}}line{{.EmphasisRight}}, while all other rows use {{.EmphasisLeft}}row{{.EmphasisRight}}. The default value is {{.EmphasisLeft}}context{{.EmphasisRight}}.
 `,
 	Synopsis: []string{
@@ -109,6 +116,9 @@ type diffDisplaySettings struct {
 	limit      int
 	where      string
 	skinny     bool
+	showBinary bool
+	// config holds the external diff drivers configured for column types
+	config *env.DoltCliConfig
 }
 
 type diffDatasets struct {
@@ -173,6 +183,9 @@ func (cmd DiffCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(MergeBase, "", "Uses merge base of the first commit and second commit (or HEAD if not supplied) as the first commit")
 	ap.SupportsString(DiffMode, "", "diff mode", "Determines how to display modified rows with tabular output. Valid values are row, line, in-place, context. Defaults to context.")
 	ap.SupportsFlag(ReverseFlag, "R", "Reverses the direction of the diff.")
+	ap.SupportsFlag(BinaryFlag, "", "Shows the contents of binary columns with tabular output, instead of a summary of their size and hash.")
+	ap.SupportsInt(cli.FindRenamesParam, "M", "similarity", "Reports a dropped table and an added table with the same columns as a renamed table if at least {{.LessThan}}similarity{{.GreaterThan}} percent of their rows are the same. Defaults to 50.")
+	ap.SupportsFlag(cli.NoRenamesFlag, "", "Reports renamed tables as a dropped table and an added table.")
 	return ap
 }
 
@@ -199,10 +212,16 @@ func (cmd DiffCmd) Exec(ctx context.Context, commandStr string, args []string, d
 		defer closeFunc()
 	}
 
+	err = setDiffRenames(queryist, sqlCtx, apr)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
 	dArgs, err := parseDiffArgs(queryist, sqlCtx, apr)
 	if err != nil {
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
+	dArgs.config = cliCtx.Config()
 
 	verr = diffUserTables(queryist, sqlCtx, dArgs)
 	return HandleVErrAndExitCode(verr, usage)
@@ -215,6 +234,15 @@ func (cmd DiffCmd) validateArgs(apr *argparser.ArgParseResults) errhand.VerboseE
 		}
 	}
 
+	if similarity, ok := apr.GetInt(cli.FindRenamesParam); ok {
+		if apr.Contains(cli.NoRenamesFlag) {
+			return errhand.BuildDError("invalid Arguments: --find-renames cannot be combined with --no-renames").Build()
+		}
+		if similarity < 0 || similarity > 100 {
+			return errhand.BuildDError("invalid Arguments: --find-renames must be a percentage between 0 and 100").Build()
+		}
+	}
+
 	f, _ := apr.GetValue(FormatFlag)
 	switch strings.ToLower(f) {
 	case "tabular", "sql", "json", "":
@@ -225,6 +253,24 @@ func (cmd DiffCmd) validateArgs(apr *argparser.ArgParseResults) errhand.VerboseE
 	return nil
 }
 
+// setDiffRenames sets the session's @@dolt_diff_renames and @@dolt_diff_rename_similarity as --no-renames and
+// --find-renames configure, so that the diff queries detect renamed tables as asked.
+func setDiffRenames(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgParseResults) error {
+	if apr.Contains(cli.NoRenamesFlag) {
+		_, err := GetRowsForSql(queryist, sqlCtx, "set @@dolt_diff_renames = 0")
+		if err != nil {
+			return fmt.Errorf("error: failed to set @@dolt_diff_renames: %w", err)
+		}
+	}
+	if similarity, ok := apr.GetInt(cli.FindRenamesParam); ok {
+		_, err := GetRowsForSql(queryist, sqlCtx, fmt.Sprintf("set @@dolt_diff_rename_similarity = %d", similarity))
+		if err != nil {
+			return fmt.Errorf("error: failed to set @@dolt_diff_rename_similarity: %w", err)
+		}
+	}
+	return nil
+}
+
 func parseDiffDisplaySettings(apr *argparser.ArgParseResults) *diffDisplaySettings {
 	displaySettings := &diffDisplaySettings{}
 
@@ -240,6 +286,7 @@ func parseDiffDisplaySettings(apr *argparser.ArgParseResults) *diffDisplaySettin
 	}
 
 	displaySettings.skinny = apr.Contains(SkinnyFlag)
+	displaySettings.showBinary = apr.Contains(BinaryFlag)
 
 	f := apr.GetValueOrDefault(FormatFlag, "tabular")
 	switch strings.ToLower(f) {
@@ -745,7 +792,7 @@ func diffUserTables(queryist cli.Queryist, sqlCtx *sql.Context, dArgs *diffArgs)
 		return printDiffSummary(sqlCtx, deltas, dArgs)
 	}
 
-	dw, err := newDiffWriter(dArgs.diffOutput)
+	dw, err := newDiffWriter(dArgs.diffDisplaySettings)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
 	}
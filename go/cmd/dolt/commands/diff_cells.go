@@ -0,0 +1,268 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/fatih/color"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/tabular"
+	"github.com/dolthub/dolt/go/libraries/utils/editor"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+)
+
+// tabularRowDiffWriter writes the rows of a tabular diff. The values of binary columns are summarized by their size and
+// hash, unless they're asked to be shown, and the changed cells of columns with an external diff driver are diffed by
+// the driver, whose output is written after the table's rows.
+type tabularRowDiffWriter struct {
+	tableName  string
+	sch        sql.Schema
+	showBinary bool
+	// drivers holds the external diff driver of each column, if it has one
+	drivers []string
+	wr      diff.SqlRowDiffWriter
+	// oldRow is the old version of a modified row, until its new version is written
+	oldRow sql.Row
+	// driverOutput is the output of the external diff drivers run for the rows written so far
+	driverOutput bytes.Buffer
+}
+
+var _ diff.SqlRowDiffWriter = (*tabularRowDiffWriter)(nil)
+
+func newTabularRowDiffWriter(tableName string, sch sql.Schema, showBinary bool, config *env.DoltCliConfig) *tabularRowDiffWriter {
+	displaySch := sch
+	if !showBinary {
+		// binary columns are displayed as text summaries
+		displaySch = make(sql.Schema, len(sch))
+		for i, col := range sch {
+			displaySch[i] = col
+			if types.IsBinaryType(col.Type) {
+				textCol := *col
+				textCol.Type = types.LongText
+				displaySch[i] = &textCol
+			}
+		}
+	}
+
+	drivers := make([]string, len(sch))
+	if config != nil {
+		for i, col := range sch {
+			drivers[i] = strings.TrimSpace(config.GetStringOrDefault(env.DiffDriverPrefix+columnTypeName(col.Type), ""))
+		}
+	}
+
+	return &tabularRowDiffWriter{
+		tableName:  tableName,
+		sch:        sch,
+		showBinary: showBinary,
+		drivers:    drivers,
+		wr:         tabular.NewFixedWidthDiffTableWriter(displaySch, iohelp.NopWrCloser(cli.CliOut), 100),
+	}
+}
+
+func (w *tabularRowDiffWriter) WriteRow(ctx context.Context, row sql.Row, diffType diff.ChangeType, colDiffTypes []diff.ChangeType) error {
+	switch diffType {
+	case diff.ModifiedOld:
+		w.oldRow = row
+	case diff.ModifiedNew:
+		if w.oldRow != nil {
+			if err := w.runDrivers(w.oldRow, row); err != nil {
+				return err
+			}
+			w.oldRow = nil
+		}
+	}
+	return w.wr.WriteRow(ctx, w.displayRow(row), diffType, colDiffTypes)
+}
+
+func (w *tabularRowDiffWriter) WriteCombinedRow(ctx context.Context, oldRow, newRow sql.Row, mode diff.Mode) error {
+	if err := w.runDrivers(oldRow, newRow); err != nil {
+		return err
+	}
+	return w.wr.WriteCombinedRow(ctx, w.displayRow(oldRow), w.displayRow(newRow), mode)
+}
+
+func (w *tabularRowDiffWriter) Close(ctx context.Context) error {
+	if err := w.wr.Close(ctx); err != nil {
+		return err
+	}
+	if w.driverOutput.Len() > 0 {
+		cli.Print(w.driverOutput.String())
+	}
+	return nil
+}
+
+// displayRow returns |row| with the values of its binary columns replaced by a summary of their size and hash, unless
+// binary values are shown.
+func (w *tabularRowDiffWriter) displayRow(row sql.Row) sql.Row {
+	if w.showBinary {
+		return row
+	}
+	var display sql.Row
+	for i, v := range row {
+		if i >= len(w.sch) || v == nil || !types.IsBinaryType(w.sch[i].Type) {
+			continue
+		}
+		if display == nil {
+			display = make(sql.Row, len(row))
+			copy(display, row)
+		}
+		switch v := v.(type) {
+		case []byte:
+			display[i] = binarySummary(v)
+		case string:
+			display[i] = binarySummary([]byte(v))
+		}
+	}
+	if display == nil {
+		return row
+	}
+	return display
+}
+
+// binarySummary returns a summary of the binary value |b|: its size and the start of its SHA-256 hash.
+func binarySummary(b []byte) string {
+	sum := sha256.Sum256(b)
+	return fmt.Sprintf("<binary, %s, sha256 %s>", pluralize("byte", "bytes", uint64(len(b))), hex.EncodeToString(sum[:])[:16])
+}
+
+// runDrivers runs the external diff driver of each column of a modified row that has one and was changed, and buffers
+// their output.
+func (w *tabularRowDiffWriter) runDrivers(oldRow, newRow sql.Row) error {
+	for i, driver := range w.drivers {
+		if len(driver) == 0 || i >= len(oldRow) || i >= len(newRow) {
+			continue
+		}
+		oldVal, err := cellBytes(w.sch[i].Type, oldRow[i])
+		if err != nil {
+			return err
+		}
+		newVal, err := cellBytes(w.sch[i].Type, newRow[i])
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(oldVal, newVal) {
+			continue
+		}
+
+		colName := w.sch[i].Name
+		_, _ = color.New(color.Bold).Fprintf(&w.driverOutput, "diff --driver a/%s.%s b/%s.%s (%s)\n", w.tableName, colName, w.tableName, colName, w.rowKey(newRow))
+		if err = runDiffDriver(driver, oldVal, newVal, &w.driverOutput); err != nil {
+			return fmt.Errorf("error: diff driver for column %s failed: %w", colName, err)
+		}
+	}
+	return nil
+}
+
+// rowKey describes the primary key of |row|, e.g. "pk = 1".
+func (w *tabularRowDiffWriter) rowKey(row sql.Row) string {
+	var key []string
+	for i, col := range w.sch {
+		if col.PrimaryKey && i < len(row) {
+			str, err := sqlutil.SqlColToStr(col.Type, row[i])
+			if err != nil {
+				str = fmt.Sprint(row[i])
+			}
+			key = append(key, fmt.Sprintf("%s = %s", col.Name, str))
+		}
+	}
+	return strings.Join(key, ", ")
+}
+
+// runDiffDriver runs the external diff driver |driver| with the paths of files holding |oldVal| and |newVal| as its
+// last two arguments, and writes its output to |out|. Like diff, drivers may exit with a non-zero status when the
+// values differ, so only failing to run the driver is an error.
+func runDiffDriver(driver string, oldVal, newVal []byte, out *bytes.Buffer) error {
+	oldFile, err := writeTempCell(oldVal)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(oldFile)
+	newFile, err := writeTempCell(newVal)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(newFile)
+
+	name, args := editor.SplitCommand(driver)
+	cmd := exec.Command(name, append(args, oldFile, newFile)...)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil
+	}
+	return err
+}
+
+func writeTempCell(val []byte) (string, error) {
+	f, err := os.CreateTemp("", "dolt-diff-*")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(val)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// cellBytes returns the contents of the value |v| of a column of type |typ|, as written for an external diff driver.
+func cellBytes(typ sql.Type, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		str, err := sqlutil.SqlColToStr(typ, v)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(str), nil
+	}
+}
+
+// columnTypeName returns the name of the type |typ| used in diff.driver config keys, e.g. "varbinary" for
+// VARBINARY(16).
+func columnTypeName(typ sql.Type) string {
+	name := strings.ToLower(typ.String())
+	if i := strings.IndexAny(name, "( "); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
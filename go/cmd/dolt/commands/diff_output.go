@@ -28,13 +28,13 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/sqlexport"
-	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/tabular"
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
 )
 
@@ -57,17 +57,17 @@ type diffWriter interface {
 	Close(ctx context.Context) error
 }
 
-// newDiffWriter returns a diffWriter for the output format given
-func newDiffWriter(diffOutput diffOutput) (diffWriter, error) {
-	switch diffOutput {
+// newDiffWriter returns a diffWriter for the output format of the settings given
+func newDiffWriter(settings *diffDisplaySettings) (diffWriter, error) {
+	switch settings.diffOutput {
 	case TabularDiffOutput:
-		return tabularDiffWriter{}, nil
+		return tabularDiffWriter{showBinary: settings.showBinary, config: settings.config}, nil
 	case SQLDiffOutput:
 		return sqlDiffWriter{}, nil
 	case JsonDiffOutput:
 		return newJsonDiffWriter(iohelp.NopWrCloser(cli.CliOut))
 	default:
-		panic(fmt.Sprintf("unexpected diff output: %v", diffOutput))
+		panic(fmt.Sprintf("unexpected diff output: %v", settings.diffOutput))
 	}
 }
 
@@ -162,7 +162,12 @@ func pluralize(singular, plural string, n uint64) string {
 	return fmt.Sprintf("%s %s", humanize.Comma(int64(n)), noun)
 }
 
-type tabularDiffWriter struct{}
+type tabularDiffWriter struct {
+	// showBinary shows the contents of binary columns, rather than a summary of their size and hash
+	showBinary bool
+	// config holds the external diff drivers configured for column types
+	config *env.DoltCliConfig
+}
 
 var _ diffWriter = (*tabularDiffWriter)(nil)
 
@@ -221,7 +226,11 @@ func (t tabularDiffWriter) WriteViewDiff(ctx context.Context, viewName, oldDefn,
 }
 
 func (t tabularDiffWriter) RowWriter(fromTableInfo, toTableInfo *diff.TableInfo, tds diff.TableDeltaSummary, unionSch sql.Schema) (diff.SqlRowDiffWriter, error) {
-	return tabular.NewFixedWidthDiffTableWriter(unionSch, iohelp.NopWrCloser(cli.CliOut), 100), nil
+	tableName := tds.ToTableName
+	if len(tableName) == 0 {
+		tableName = tds.FromTableName
+	}
+	return newTabularRowDiffWriter(tableName, unionSch, t.showBinary, t.config), nil
 }
 
 type sqlDiffWriter struct{}
@@ -16,8 +16,10 @@ package docscmds
 
 import (
 	"context"
+	"strings"
 
 	textdiff "github.com/andreyvit/diff"
+	"github.com/fatih/color"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands"
@@ -29,9 +31,20 @@ import (
 
 var diffDocs = cli.CommandDocumentationContent{
 	ShortDesc: "Diffs Dolt Docs",
-	LongDesc:  "Diffs Dolt Docs",
+	LongDesc: `Shows the changes to a Dolt Doc line by line.
+
+{{.EmphasisLeft}}dolt docs diff {{.LessThan}}doc{{.GreaterThan}}{{.EmphasisRight}}
+   Shows the changes to the doc in the working set since the HEAD commit.
+
+{{.EmphasisLeft}}dolt docs diff {{.LessThan}}commit{{.GreaterThan}} {{.LessThan}}doc{{.GreaterThan}}{{.EmphasisRight}}
+   Shows the changes to the doc in the working set since {{.LessThan}}commit{{.GreaterThan}}.
+
+{{.EmphasisLeft}}dolt docs diff {{.LessThan}}commit{{.GreaterThan}} {{.LessThan}}commit{{.GreaterThan}} {{.LessThan}}doc{{.GreaterThan}}{{.EmphasisRight}}
+   Shows the changes to the doc between the two commits.
+
+After a merge, a doc that was changed on both branches contains conflict markers around the lines that were changed differently, which can be resolved by editing the doc and uploading it again.`,
 	Synopsis: []string{
-		"{{.LessThan}}doc{{.GreaterThan}}",
+		"[{{.LessThan}}commit{{.GreaterThan}} [{{.LessThan}}commit{{.GreaterThan}}]] {{.LessThan}}doc{{.GreaterThan}}",
 	},
 }
 
@@ -60,7 +73,8 @@ func (cmd DiffCmd) Docs() *cli.CommandDocumentation {
 
 // ArgParser implements cli.Command.
 func (cmd DiffCmd) ArgParser() *argparser.ArgParser {
-	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 1)
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 3)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"commit", "Commit to diff the doc from or to. Defaults to HEAD and the working set."})
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"doc", "Dolt doc to be diffed."})
 	return ap
 }
@@ -71,35 +85,59 @@ func (cmd DiffCmd) Exec(ctx context.Context, commandStr string, args []string, d
 	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, diffDocs, ap))
 	apr := cli.ParseArgsOrDie(ap, args, help)
 
-	if apr.NArg() != 1 {
-		verr := errhand.BuildDError("dolt docs diff takes exactly one argument").Build()
+	if apr.NArg() < 1 {
+		verr := errhand.BuildDError("dolt docs diff requires a doc name").Build()
 		return commands.HandleVErrAndExitCode(verr, usage)
 	}
 
+	// the working set is read when no |to| revision is given
+	from, to := "HEAD", ""
+	args = apr.Args
+	if len(args) > 1 {
+		from = args[0]
+	}
+	if len(args) > 2 {
+		to = args[1]
+	}
+	docName := args[len(args)-1]
+
 	var verr errhand.VerboseError
-	if err := diffDoltDoc(ctx, dEnv, apr.Arg(0)); err != nil {
+	if err := diffDoltDoc(ctx, dEnv, docName, from, to); err != nil {
 		verr = errhand.VerboseErrorFromError(err)
 	}
 
 	return commands.HandleVErrAndExitCode(verr, usage)
 }
 
-func diffDoltDoc(ctx context.Context, dEnv *env.DoltEnv, docName string) error {
+func diffDoltDoc(ctx context.Context, dEnv *env.DoltEnv, docName, from, to string) error {
 	eng, dbName, err := engine.NewSqlEngineForEnv(ctx, dEnv)
 	if err != nil {
 		return err
 	}
 
-	working, err := readDocFromTable(ctx, eng, dbName, docName)
+	toDoc, err := readDocFromTableAsOf(ctx, eng, dbName, docName, to)
 	if err != nil {
 		return err
 	}
 
-	head, err := readDocFromTableAsOf(ctx, eng, dbName, docName, "HEAD")
+	fromDoc, err := readDocFromTableAsOf(ctx, eng, dbName, docName, from)
 	if err != nil {
 		return err
 	}
 
-	cli.Print(textdiff.LineDiff(head, working))
+	if fromDoc == toDoc {
+		return nil
+	}
+
+	for _, line := range strings.Split(textdiff.LineDiff(fromDoc, toDoc), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			cli.Println(color.GreenString(line))
+		case strings.HasPrefix(line, "-"):
+			cli.Println(color.RedString(line))
+		default:
+			cli.Println(line)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,221 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/fatih/color"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/chunks"
+)
+
+const (
+	repairFlag = "repair"
+)
+
+var doctorDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Checks the integrity of the repository.",
+	LongDesc: `Checks the repository for corruption and inconsistencies, and reports any problems it finds. The checks are:
+
+Table files: every chunk of every table file is read, and checked against the table file's index, its checksum and its address.
+
+Refs: the value of every branch, tag, remote ref, workspace and working set is loaded, along with every commit reachable from the branches, tags, remote refs and workspaces, and the root value of each commit. A ref that points to a chunk missing from the chunk store is dangling.
+
+Repository state: the branch checked out in {{.EmphasisLeft}}repo_state.json{{.EmphasisRight}} must exist and have a working set, and the branches configured there must exist and track configured remotes.
+
+If the {{.EmphasisLeft}}--repair{{.EmphasisRight}} flag is supplied, dangling refs are deleted. Other problems are only reported.
+
+Exits with a non-zero status if any problems are found.`,
+	Synopsis: []string{
+		"[--repair]",
+	},
+}
+
+type DoctorCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd DoctorCmd) Name() string {
+	return "doctor"
+}
+
+// Description returns a description of the command
+func (cmd DoctorCmd) Description() string {
+	return doctorDocs.ShortDesc
+}
+
+// RequiresRepo should return false if this interface is implemented, and the command does not have the requirement
+// that it be run from within a data repository directory
+func (cmd DoctorCmd) RequiresRepo() bool {
+	return true
+}
+
+func (cmd DoctorCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(doctorDocs, ap)
+}
+
+func (cmd DoctorCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
+	ap.SupportsFlag(repairFlag, "", "Delete dangling refs.")
+	return ap
+}
+
+// Exec executes the command
+func (cmd DoctorCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, doctorDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	repair := apr.Contains(repairFlag)
+	if repair && dEnv.IsLocked() {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(env.ErrActiveServerLock.New(dEnv.LockFile())), help)
+	}
+
+	problems := 0
+
+	cli.Println("Checking table files...")
+	n, err := checkTableFiles(ctx, dEnv.DoltDB)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: failed to check table files").AddCause(err).Build(), usage)
+	}
+	problems += n
+
+	cli.Println("Checking refs...")
+	n, err = checkRefs(ctx, dEnv.DoltDB, repair)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: failed to check refs").AddCause(err).Build(), usage)
+	}
+	problems += n
+
+	cli.Println("Checking repository state...")
+	n, err = checkRepoState(ctx, dEnv)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: failed to check repository state").AddCause(err).Build(), usage)
+	}
+	problems += n
+
+	if problems > 0 {
+		cli.Println(color.RedString("Found %d problem(s).", problems))
+		return 1
+	}
+	cli.Println(color.GreenString("No problems found."))
+	return 0
+}
+
+// checkTableFiles validates the table files of |ddb|, and returns the number of invalid ones.
+func checkTableFiles(ctx context.Context, ddb *doltdb.DoltDB) (int, error) {
+	problems := 0
+	err := ddb.ValidateTableFiles(ctx, func(fileID string, numChunks int, err error) error {
+		if err != nil {
+			problems++
+			cli.Println(color.RedString("\t%s: %s", fileID, err.Error()))
+		} else {
+			cli.Printf("\t%s: %d chunks ok\n", fileID, numChunks)
+		}
+		return nil
+	})
+	if err == chunks.ErrUnsupportedOperation {
+		cli.Println("\tthis database does not support validating table files")
+		return 0, nil
+	}
+	return problems, err
+}
+
+// checkRefs checks that every ref of |ddb|, and the history reachable from it, can be loaded, and returns the number
+// of refs that can't be. If |repair| is set, the dangling refs are deleted, and aren't counted.
+func checkRefs(ctx context.Context, ddb *doltdb.DoltDB, repair bool) (int, error) {
+	refProblems, numCommits, err := ddb.CheckRefs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	cli.Printf("\t%d commits loaded\n", numCommits)
+
+	problems := 0
+	for _, p := range refProblems {
+		if p.Dangling && repair {
+			err := ddb.DeleteDanglingRef(ctx, p.Ref)
+			if err != nil {
+				return 0, fmt.Errorf("failed to delete dangling ref %s: %w", p.Ref, err)
+			}
+			cli.Println(color.YellowString("\t%s: deleted dangling ref", p.Ref))
+			continue
+		}
+
+		problems++
+		if p.Dangling {
+			cli.Println(color.RedString("\t%s: dangling: %s", p.Ref, p.Err.Error()))
+		} else {
+			cli.Println(color.RedString("\t%s: %s", p.Ref, p.Err.Error()))
+		}
+	}
+	return problems, nil
+}
+
+// checkRepoState checks that the branch checked out in the repo state of |dEnv| exists and has a working set, and that
+// the branches configured there exist and track configured remotes. It returns the number of problems found.
+func checkRepoState(ctx context.Context, dEnv *env.DoltEnv) (int, error) {
+	var msgs []string
+
+	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+	if err != nil {
+		msgs = append(msgs, fmt.Sprintf("failed to read HEAD: %s", err.Error()))
+	} else if ok, err := dEnv.DoltDB.HasRef(ctx, headRef); err != nil {
+		return 0, err
+	} else if !ok {
+		msgs = append(msgs, fmt.Sprintf("HEAD is %s, which does not exist", headRef.String()))
+	} else {
+		wsRef, err := ref.WorkingSetRefForHead(headRef)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := dEnv.DoltDB.ResolveWorkingSet(ctx, wsRef); err != nil {
+			msgs = append(msgs, fmt.Sprintf("failed to load the working set of HEAD %s: %s", headRef.String(), err.Error()))
+		}
+	}
+
+	names := make([]string, 0, len(dEnv.RepoState.Branches))
+	for name := range dEnv.RepoState.Branches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ok, err := dEnv.DoltDB.HasRef(ctx, ref.NewBranchRef(name))
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			msgs = append(msgs, fmt.Sprintf("branch %s is configured, but does not exist", name))
+		}
+		remote := dEnv.RepoState.Branches[name].Remote
+		if _, ok := dEnv.RepoState.Remotes[remote]; remote != "" && !ok {
+			msgs = append(msgs, fmt.Sprintf("branch %s tracks remote %s, which is not configured", name, remote))
+		}
+	}
+
+	for _, msg := range msgs {
+		cli.Println(color.RedString("\t%s", msg))
+	}
+	return len(msgs), nil
+}
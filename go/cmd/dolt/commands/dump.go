@@ -25,6 +25,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/plan"
 	"github.com/dolthub/go-mysql-server/sql/planbuilder"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
 	"github.com/fatih/color"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
@@ -51,7 +52,9 @@ const (
 	noBatchFlag      = "no-batch"
 	noAutocommitFlag = "no-autocommit"
 	schemaOnlyFlag   = "schema-only"
+	noDataFlag       = "no-data"
 	noCreateDbFlag   = "no-create-db"
+	dumpTablesFlag   = "tables"
 
 	sqlFileExt     = "sql"
 	csvFileExt     = "csv"
@@ -68,10 +71,21 @@ If a dump file already exists then the operation will fail, unless the {{.Emphas
 is provided. The force flag forces the existing dump file to be overwritten. The {{.EmphasisLeft}}-r{{.EmphasisRight}} flag 
 is used to support different file formats of the dump. In the case of non .sql files each table is written to a separate
 csv,json or parquet file. 
+
+SQL dumps include the views, triggers and stored procedures of the database. With {{.EmphasisLeft}}--schema-only{{.EmphasisRight}} or 
+{{.EmphasisLeft}}--no-data{{.EmphasisRight}}, only the schema of each table is written, along with those views, triggers and procedures.
+
+{{.EmphasisLeft}}--tables{{.EmphasisRight}} limits the dump to a comma separated list of tables and views. Only the triggers of the 
+listed tables are dumped, and stored procedures are not dumped.
+
+{{.EmphasisLeft}}--where{{.EmphasisRight}} limits the rows dumped to those matching a SQL condition. A condition can be given for each 
+table by prefixing it with the table name and a colon, and separating the conditions with semicolons, as in 
+{{.EmphasisLeft}}--where "users: created > '2023-01-01'; orders: status = 'open'"{{.EmphasisRight}}. A condition without a table name 
+applies to every table that doesn't have its own.
 `,
 
 	Synopsis: []string{
-		"[-f] [-r {{.LessThan}}result-format{{.GreaterThan}}] [-fn {{.LessThan}}file_name{{.GreaterThan}}]  [-d {{.LessThan}}directory{{.GreaterThan}}] [--batch] [--no-batch] [--no-autocommit] [--no-create-db] ",
+		"[-f] [-r {{.LessThan}}result-format{{.GreaterThan}}] [-fn {{.LessThan}}file_name{{.GreaterThan}}]  [-d {{.LessThan}}directory{{.GreaterThan}}] [--batch] [--no-batch] [--no-autocommit] [--no-create-db] [--schema-only | --no-data] [--tables {{.LessThan}}table{{.GreaterThan}}[,{{.LessThan}}table{{.GreaterThan}}...]] [--where {{.LessThan}}condition{{.GreaterThan}}]",
 	},
 }
 
@@ -103,7 +117,10 @@ func (cmd DumpCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(noBatchFlag, "", "Emit one row per statement, instead of batching multiple rows into each statement.")
 	ap.SupportsFlag(noAutocommitFlag, "na", "Turn off autocommit for each dumped table. Useful for speeding up loading of output SQL file.")
 	ap.SupportsFlag(schemaOnlyFlag, "", "Dump a table's schema, without including any data, to the output SQL file.")
+	ap.SupportsFlag(noDataFlag, "", "Same as --schema-only.")
 	ap.SupportsFlag(noCreateDbFlag, "", "Do not write `CREATE DATABASE` statements in SQL files.")
+	ap.SupportsStringList(dumpTablesFlag, "", "tables", "Only dump the tables and views given.")
+	ap.SupportsString(whereParam, "", "condition", "Only dump the rows that match the SQL condition. Conditions for specific tables are given as `table: condition`, separated by semicolons.")
 	return ap
 }
 
@@ -133,7 +150,7 @@ func (cmd DumpCmd) Exec(ctx context.Context, commandStr string, args []string, d
 	}
 
 	force := apr.Contains(forceParam)
-	schemaOnly := apr.Contains(schemaOnlyFlag)
+	schemaOnly := apr.Contains(schemaOnlyFlag) || apr.Contains(noDataFlag)
 	resFormat, _ := apr.GetValue(FormatFlag)
 	resFormat = strings.TrimPrefix(resFormat, ".")
 
@@ -142,6 +159,12 @@ func (cmd DumpCmd) Exec(ctx context.Context, commandStr string, args []string, d
 		return HandleVErrAndExitCode(vErr, usage)
 	}
 
+	filter, vErr := getDumpFilter(ctx, dEnv, apr, tblNames)
+	if vErr != nil {
+		return HandleVErrAndExitCode(vErr, usage)
+	}
+	tblNames = filter.filterTables(tblNames)
+
 	switch resFormat {
 	case emptyFileExt, sqlFileExt:
 		var defaultName string
@@ -183,18 +206,19 @@ func (cmd DumpCmd) Exec(ctx context.Context, commandStr string, args []string, d
 
 		for _, tbl := range tblNames {
 			tblOpts := newTableArgs(tbl, dumpOpts.dest, !apr.Contains(noBatchFlag), apr.Contains(noAutocommitFlag), schemaOnly)
+			tblOpts.where = filter.whereFor(tbl)
 			err = dumpTable(ctx, dEnv, tblOpts, fPath)
 			if err != nil {
 				return HandleVErrAndExitCode(err, usage)
 			}
 		}
 
-		err = dumpSchemaElements(ctx, dEnv, fPath)
+		err = dumpSchemaElements(ctx, dEnv, fPath, filter)
 		if err != nil {
 			return HandleVErrAndExitCode(err, usage)
 		}
 	case csvFileExt, jsonFileExt, parquetFileExt:
-		err = dumpNonSqlTables(ctx, root, dEnv, force, tblNames, resFormat, outputFileOrDirName, false)
+		err = dumpNonSqlTables(ctx, root, dEnv, force, tblNames, resFormat, outputFileOrDirName, false, filter)
 		if err != nil {
 			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 		}
@@ -207,8 +231,9 @@ func (cmd DumpCmd) Exec(ctx context.Context, commandStr string, args []string, d
 	return 0
 }
 
-// dumpSchemaElements writes the non-table schema elements (views, triggers, procedures) to the file path given
-func dumpSchemaElements(ctx context.Context, dEnv *env.DoltEnv, path string) errhand.VerboseError {
+// dumpSchemaElements writes the non-table schema elements (views, triggers, procedures) selected by |filter| to the
+// file path given
+func dumpSchemaElements(ctx context.Context, dEnv *env.DoltEnv, path string, filter *dumpFilter) errhand.VerboseError {
 	writer, err := dEnv.FS.OpenForWriteAppend(path, os.ModePerm)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
@@ -230,19 +255,22 @@ func dumpSchemaElements(ctx context.Context, dEnv *env.DoltEnv, path string) err
 		return errhand.VerboseErrorFromError(err)
 	}
 
-	err = dumpViews(sqlCtx, engine, root, writer)
+	err = dumpViews(sqlCtx, engine, root, writer, filter)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
 	}
 
-	err = dumpTriggers(sqlCtx, engine, root, writer)
+	err = dumpTriggers(sqlCtx, engine, root, writer, filter)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
 	}
 
-	err = dumpProcedures(sqlCtx, engine, root, writer)
-	if err != nil {
-		return errhand.VerboseErrorFromError(err)
+	// procedures don't belong to any table, so they're only dumped along with the whole database
+	if filter.tables == nil {
+		err = dumpProcedures(sqlCtx, engine, root, writer)
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
 	}
 
 	err = writer.Close()
@@ -323,7 +351,7 @@ func dumpProcedures(sqlCtx *sql.Context, engine *engine.SqlEngine, root *doltdb.
 	return nil
 }
 
-func dumpTriggers(sqlCtx *sql.Context, engine *engine.SqlEngine, root *doltdb.RootValue, writer io.WriteCloser) (rerr error) {
+func dumpTriggers(sqlCtx *sql.Context, engine *engine.SqlEngine, root *doltdb.RootValue, writer io.WriteCloser, filter *dumpFilter) (rerr error) {
 	_, _, ok, err := root.GetTableInsensitive(sqlCtx, doltdb.SchemasTableName)
 	if err != nil {
 		return err
@@ -366,6 +394,16 @@ func dumpTriggers(sqlCtx *sql.Context, engine *engine.SqlEngine, root *doltdb.Ro
 			sqlMode = s
 		}
 
+		if filter.tables != nil {
+			stmt, err := sqlparser.ParseWithOptions(row[fragColIdx].(string), sql.NewSqlModeFromString(sqlMode).ParserOptions())
+			if err != nil {
+				return err
+			}
+			if ddl, ok := stmt.(*sqlparser.DDL); ok && !filter.includes(ddl.Table.Name.String()) {
+				continue
+			}
+		}
+
 		modeChanged, err := changeSqlMode(sqlCtx, writer, sqlMode)
 		if err != nil {
 			return err
@@ -386,7 +424,7 @@ func dumpTriggers(sqlCtx *sql.Context, engine *engine.SqlEngine, root *doltdb.Ro
 	return nil
 }
 
-func dumpViews(ctx *sql.Context, engine *engine.SqlEngine, root *doltdb.RootValue, writer io.WriteCloser) (rerr error) {
+func dumpViews(ctx *sql.Context, engine *engine.SqlEngine, root *doltdb.RootValue, writer io.WriteCloser, filter *dumpFilter) (rerr error) {
 	_, _, ok, err := root.GetTableInsensitive(ctx, doltdb.SchemasTableName)
 	if err != nil {
 		return err
@@ -424,6 +462,9 @@ func dumpViews(ctx *sql.Context, engine *engine.SqlEngine, root *doltdb.RootValu
 		if row[typeColIdx] != "view" {
 			continue
 		}
+		if name, _ := row[nameColIdx].(string); !filter.includes(name) {
+			continue
+		}
 
 		sqlMode := ""
 		if s, ok := row[sqlModeIdx].(string); ok {
@@ -515,6 +556,7 @@ type dumpOptions struct {
 
 type tableOptions struct {
 	tableName     string
+	where         string
 	schemaOnly    bool
 	dest          mvdata.DataLocation
 	batched       bool
@@ -553,7 +595,7 @@ func (m dumpOptions) DumpDestName() string {
 
 // dumpTable dumps table in file given specific table and file location info
 func dumpTable(ctx context.Context, dEnv *env.DoltEnv, tblOpts *tableOptions, filePath string) errhand.VerboseError {
-	rd, err := mvdata.NewSqlEngineReader(ctx, dEnv, tblOpts.tableName)
+	rd, err := mvdata.NewFilteredSqlEngineReader(ctx, dEnv, tblOpts.tableName, tblOpts.where)
 	if err != nil {
 		return errhand.BuildDError("Error creating reader for %s.", tblOpts.SrcName()).AddCause(err).Build()
 	}
@@ -678,7 +720,11 @@ func validateDumpArgs(apr *argparser.ArgParseResults) (string, errhand.VerboseEr
 	rf = strings.TrimPrefix(rf, ".")
 	fn, fnOk := apr.GetValue(filenameFlag)
 	dn, dnOk := apr.GetValue(directoryFlag)
-	snOk := apr.Contains(schemaOnlyFlag)
+	snOk := apr.Contains(schemaOnlyFlag) || apr.Contains(noDataFlag)
+
+	if snOk && apr.Contains(whereParam) {
+		return emptyStr, errhand.BuildDError("cannot pass --%s with a schema only dump", whereParam).SetPrintUsage().Build()
+	}
 
 	if fnOk && dnOk {
 		return emptyStr, errhand.BuildDError("cannot pass both directory and file names").SetPrintUsage().Build()
@@ -702,6 +748,141 @@ func validateDumpArgs(apr *argparser.ArgParseResults) (string, errhand.VerboseEr
 	}
 }
 
+// dumpFilter selects the tables, views, triggers and rows written by a dump.
+type dumpFilter struct {
+	// tables are the lowercase names of the tables and views given with --tables, or nil to dump all of them
+	tables map[string]bool
+	// where maps lowercase table names to the condition rows of that table must match. The condition for tables
+	// without their own is keyed by the empty string.
+	where map[string]string
+}
+
+// getDumpFilter returns the dumpFilter for the --tables and --where arguments given, checking that every table they
+// name exists in |tblNames| or is a view.
+func getDumpFilter(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults, tblNames []string) (*dumpFilter, errhand.VerboseError) {
+	filter := &dumpFilter{where: make(map[string]string)}
+
+	existing := make(map[string]bool, len(tblNames))
+	for _, tbl := range tblNames {
+		existing[strings.ToLower(tbl)] = true
+	}
+
+	if names, ok := apr.GetValueList(dumpTablesFlag); ok {
+		views, err := getViewNames(ctx, dEnv)
+		if err != nil {
+			return nil, errhand.BuildDError("error: failed to get views").AddCause(err).Build()
+		}
+
+		filter.tables = make(map[string]bool)
+		for _, name := range names {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if !existing[name] && !views[name] {
+				return nil, errhand.BuildDError("error: table or view %s not found", name).Build()
+			}
+			filter.tables[name] = true
+		}
+	}
+
+	if where, ok := apr.GetValue(whereParam); ok {
+		for _, cond := range strings.Split(where, ";") {
+			cond = strings.TrimSpace(cond)
+			if cond == "" {
+				continue
+			}
+
+			tbl := ""
+			if i := strings.Index(cond, ":"); i > 0 && existing[strings.ToLower(strings.TrimSpace(cond[:i]))] {
+				tbl = strings.ToLower(strings.TrimSpace(cond[:i]))
+				cond = strings.TrimSpace(cond[i+1:])
+			}
+			if _, ok := filter.where[tbl]; ok {
+				if tbl == "" {
+					return nil, errhand.BuildDError("error: only one --%s condition can apply to all tables", whereParam).Build()
+				}
+				return nil, errhand.BuildDError("error: multiple --%s conditions for table %s", whereParam, tbl).Build()
+			}
+			filter.where[tbl] = cond
+		}
+	}
+
+	return filter, nil
+}
+
+// includes returns whether the table or view |name| is dumped.
+func (f *dumpFilter) includes(name string) bool {
+	return f.tables == nil || f.tables[strings.ToLower(name)]
+}
+
+// filterTables returns the tables in |tblNames| that are dumped.
+func (f *dumpFilter) filterTables(tblNames []string) []string {
+	var filtered []string
+	for _, tbl := range tblNames {
+		if f.includes(tbl) {
+			filtered = append(filtered, tbl)
+		}
+	}
+	return filtered
+}
+
+// whereFor returns the condition the dumped rows of |tbl| must match, or the empty string to dump every row.
+func (f *dumpFilter) whereFor(tbl string) string {
+	if cond, ok := f.where[strings.ToLower(tbl)]; ok {
+		return cond
+	}
+	return f.where[""]
+}
+
+// getViewNames returns the lowercase names of the views in the working set.
+func getViewNames(ctx context.Context, dEnv *env.DoltEnv) (views map[string]bool, rerr error) {
+	views = make(map[string]bool)
+
+	root, err := dEnv.WorkingRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, _, ok, err := root.GetTableInsensitive(ctx, doltdb.SchemasTableName)
+	if err != nil || !ok {
+		return views, err
+	}
+
+	eng, dbName, err := engine.NewSqlEngineForEnv(ctx, dEnv)
+	if err != nil {
+		return nil, err
+	}
+	sqlCtx, err := eng.NewLocalContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sqlCtx.SetCurrentDatabase(dbName)
+
+	_, iter, err := eng.Query(sqlCtx, fmt.Sprintf("select %s from %s where %s = 'view'", doltdb.SchemasTablesNameCol, doltdb.SchemasTableName, doltdb.SchemasTablesTypeCol))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err := iter.Close(sqlCtx)
+		if rerr == nil && err != nil {
+			rerr = err
+		}
+	}()
+
+	for {
+		row, err := iter.Next(sqlCtx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if name, ok := row[0].(string); ok {
+			views[strings.ToLower(name)] = true
+		}
+	}
+	return views, nil
+}
+
 // getDumpArgs returns dumpOptions of result format and dest file location corresponding to the input parameters
 func getDumpOptions(fileName string, rf string, schemaOnly bool) *dumpOptions {
 	fileLoc := getDumpDestination(fileName)
@@ -730,7 +911,7 @@ func newTableArgs(tblName string, destination mvdata.DataLocation, batched, auto
 
 // dumpNonSqlTables returns nil if all tables is dumped successfully, and it returns err if there is one.
 // It handles only csv and json file types(rf).
-func dumpNonSqlTables(ctx context.Context, root *doltdb.RootValue, dEnv *env.DoltEnv, force bool, tblNames []string, rf string, dirName string, batched bool) errhand.VerboseError {
+func dumpNonSqlTables(ctx context.Context, root *doltdb.RootValue, dEnv *env.DoltEnv, force bool, tblNames []string, rf string, dirName string, batched bool, filter *dumpFilter) errhand.VerboseError {
 	var fName string
 	if dirName == emptyStr {
 		dirName = "doltdump/"
@@ -750,6 +931,7 @@ func dumpNonSqlTables(ctx context.Context, root *doltdb.RootValue, dEnv *env.Dol
 		}
 
 		tblOpts := newTableArgs(tbl, dumpOpts.dest, batched, false, false)
+		tblOpts.where = filter.whereFor(tbl)
 
 		err = dumpTable(ctx, dEnv, tblOpts, fPath)
 		if err != nil {
@@ -16,6 +16,7 @@ package engine
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"runtime"
 	"strconv"
@@ -38,8 +39,14 @@ import (
 	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	dblr "github.com/dolthub/dolt/go/libraries/doltcore/sqle/binlogreplication"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/commithooks"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/formatupgrade"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/mysql_file_handler"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/resultcache"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/serverhooks"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/webhooks"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -50,6 +57,7 @@ type SqlEngine struct {
 	contextFactory contextFactory
 	dsessFactory   sessionFactory
 	engine         *gms.Engine
+	resultCache    *resultcache.Cache
 }
 
 type sessionFactory func(mysqlSess *sql.BaseSession, pro sql.DatabaseProvider) (*dsess.DoltSession, error)
@@ -74,6 +82,13 @@ type SqlEngineConfig struct {
 	ClusterController       *cluster.Controller
 	BinlogReplicaController binlogreplication.BinlogReplicaController
 	EventSchedulerStatus    eventscheduler.SchedulerStatus
+	ServerHooks             *serverhooks.Config
+	Webhooks                []webhooks.Config
+	CommitHooks             []commithooks.Config
+	FormatUpgrades          *formatupgrade.Tracker
+	// LoadConcurrency is how many databases are opened at once when the engine is created. Zero opens them one at
+	// a time.
+	LoadConcurrency int
 }
 
 // NewSqlEngine returns a SqlEngine
@@ -86,7 +101,7 @@ func NewSqlEngine(
 		config.IsServerLocked = true
 	}
 
-	dbs, locations, err := CollectDBs(ctx, mrEnv, config.Bulk)
+	dbs, locations, err := CollectDBs(ctx, mrEnv, config.Bulk, config.LoadConcurrency)
 	if err != nil {
 		return nil, err
 	}
@@ -126,25 +141,72 @@ func NewSqlEngine(
 		locations = append(locations, nil)
 	}
 
+	dispatcher := webhooks.NewDispatcher(config.Webhooks)
+	err = dispatcher.Start(bThreads)
+	if err != nil {
+		return nil, err
+	}
+	for _, db := range dbs {
+		err = dispatcher.InstallCommitHook(ctx, db.Name(), db.DbData().Ddb)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	hookRunner := commithooks.NewRunner(config.CommitHooks)
+	err = hookRunner.Start(bThreads)
+	if err != nil {
+		return nil, err
+	}
+	for _, db := range dbs {
+		hookRunner.InstallCommitHook(ctx, db.Name(), db.DbData().Ddb)
+	}
+
 	b := env.GetDefaultInitBranch(mrEnv.Config())
-	pro, err := dsqle.NewDoltDatabaseProviderWithDatabases(b, mrEnv.FileSystem(), all, locations)
+	proCfg := dsqle.NewDoltDatabaseProviderConfig(b, mrEnv.FileSystem()).
+		WithLazyDatabases(lazyDatabaseLoaders(lazyDatabaseEnv{
+			mrEnv:             mrEnv,
+			format:            nbf,
+			useBulkEditor:     config.Bulk,
+			bThreads:          bThreads,
+			clusterController: config.ClusterController,
+			webhooks:          dispatcher,
+			commitHooks:       hookRunner,
+		})).
+		WithRemoteDialer(mrEnv.RemoteDialProvider()).
+		WithServerHooks(config.ServerHooks).
+		WithWebhooks(dispatcher).
+		WithFormatUpgrades(config.FormatUpgrades)
+	pro, err := dsqle.NewDoltDatabaseProviderFromConfig(proCfg, all, locations)
 	if err != nil {
 		return nil, err
 	}
-	pro = pro.WithRemoteDialer(mrEnv.RemoteDialProvider())
+
+	// replication replaces a new database's commit hooks, so it's set up before the hooks that add to them
+	pro.AddInitDatabaseHook(dsqle.ConfigureReplicationDatabaseHook)
+	pro.AddInitDatabaseHook(dsqle.NewWebhooksInitDatabaseHook(dispatcher))
+	pro.AddInitDatabaseHook(dsqle.NewCommitHooksInitDatabaseHook(hookRunner))
 
 	config.ClusterController.RegisterStoredProcedures(pro)
-	pro.InitDatabaseHook = cluster.NewInitDatabaseHook(config.ClusterController, bThreads, pro.InitDatabaseHook)
-	pro.DropDatabaseHook = config.ClusterController.DropDatabaseHook
+	pro.AddInitDatabaseHook(cluster.NewInitDatabaseHook(config.ClusterController, bThreads))
+	pro.AddDropDatabaseHook(config.ClusterController.DropDatabaseHook())
 
 	// Create the engine
-	engine := gms.New(analyzer.NewBuilder(pro).WithParallelism(parallelism).Build(), &gms.Config{
+	resultCache := resultcache.New()
+	a := analyzer.NewBuilder(pro).
+		WithParallelism(parallelism).
+		AddPostValidationRule(dsqle.VectorSearchRuleId, dsqle.VectorSearchRule).
+		AddPostValidationRule(resultcache.RuleId, resultCache.Rule).
+		Build()
+	engine := gms.New(a, &gms.Config{
 		IsReadOnly:     config.IsReadOnly,
 		IsServerLocked: config.IsServerLocked,
 	}).WithBackgroundThreads(bThreads)
+	engine.Analyzer.Catalog.InfoSchema = dtables.NewInformationSchemaDatabase(engine.Analyzer.Catalog.InfoSchema)
+	pro.SetStatementRunner(engine)
 
 	config.ClusterController.SetIsStandbyCallback(func(isStandby bool) {
-		pro.SetIsStandby(isStandby)
+		pro.SetIsStandby("", isStandby)
 
 		// Standbys are read only, primarys are not.
 		// We only change this here if the server was not forced read
@@ -178,7 +240,7 @@ func NewSqlEngine(
 		"authentication_dolt_jwt": NewAuthenticateDoltJWTPlugin(config.JwksConfig),
 	})
 
-	engine.Analyzer.ExecBuilder = rowexec.DefaultBuilder
+	engine.Analyzer.ExecBuilder = dsqle.NewExecBuilder(rowexec.DefaultBuilder)
 
 	// Load MySQL Db information
 	if err = engine.Analyzer.Catalog.MySQLDb.LoadData(sql.NewEmptyContext(), data); err != nil {
@@ -228,6 +290,7 @@ func NewSqlEngine(
 		contextFactory: sqlContextFactory(),
 		dsessFactory:   sessFactory,
 		engine:         engine,
+		resultCache:    resultCache,
 	}, nil
 }
 
@@ -254,7 +317,27 @@ func (se *SqlEngine) Databases(ctx *sql.Context) []dsess.SqlDatabase {
 		dbs[i] = databases[i].(dsess.SqlDatabase)
 	}
 
-	return nil
+	return dbs
+}
+
+// LoadLazyDatabases opens the databases that haven't been opened yet, because the engine was created from a
+// MultiRepoEnv that loaded them lazily. See dsqle.DoltDatabaseProvider.LoadLazyDatabases.
+func (se *SqlEngine) LoadLazyDatabases(ctx *sql.Context) error {
+	pro, ok := se.provider.(*dsqle.DoltDatabaseProvider)
+	if !ok {
+		return nil
+	}
+	return pro.LoadLazyDatabases(ctx)
+}
+
+// SwapDatabase replaces the database named, which was loaded from |dEnv|, after |swap| rewrites its storage in place.
+// See dsqle.DoltDatabaseProvider.SwapDatabase.
+func (se *SqlEngine) SwapDatabase(ctx *sql.Context, name string, dEnv *env.DoltEnv, swap func() error) error {
+	pro, ok := se.provider.(*dsqle.DoltDatabaseProvider)
+	if !ok {
+		return fmt.Errorf("cannot swap database %s: unexpected database provider %T", name, se.provider)
+	}
+	return pro.SwapDatabase(ctx, name, dEnv, swap)
 }
 
 // NewContext returns a new sql.Context with the given session.
@@ -301,6 +384,11 @@ func (se *SqlEngine) GetUnderlyingEngine() *gms.Engine {
 	return se.engine
 }
 
+// ResultCache returns the cache of results for queries on commits and tags, or nil if the engine has none.
+func (se *SqlEngine) ResultCache() *resultcache.Cache {
+	return se.resultCache
+}
+
 func (se *SqlEngine) Close() error {
 	if se.engine != nil {
 		return se.engine.Close()
@@ -323,7 +411,7 @@ func configureBinlogReplicaController(config *SqlEngineConfig, engine *gms.Engin
 
 // configureEventScheduler configures the event scheduler with the |engine| for executing events, a |sessFactory|
 // for creating sessions, and a DoltDatabaseProvider, |pro|.
-func configureEventScheduler(config *SqlEngineConfig, engine *gms.Engine, sessFactory sessionFactory, pro dsqle.DoltDatabaseProvider) error {
+func configureEventScheduler(config *SqlEngineConfig, engine *gms.Engine, sessFactory sessionFactory, pro *dsqle.DoltDatabaseProvider) error {
 	// need to give correct user, use the definer as user to run the event definition queries
 	ctxFactory := sqlContextFactory()
 
@@ -383,7 +471,7 @@ func sqlContextFactory() contextFactory {
 }
 
 // doltSessionFactory returns a sessionFactory that creates a new DoltSession
-func doltSessionFactory(pro dsqle.DoltDatabaseProvider, config config.ReadWriteConfig, bc *branch_control.Controller, autocommit bool) sessionFactory {
+func doltSessionFactory(pro *dsqle.DoltDatabaseProvider, config config.ReadWriteConfig, bc *branch_control.Controller, autocommit bool) sessionFactory {
 	return func(mysqlSess *sql.BaseSession, provider sql.DatabaseProvider) (*dsess.DoltSession, error) {
 		doltSession, err := dsess.NewDoltSession(mysqlSess, pro, config, bc)
 		if err != nil {
@@ -16,38 +16,125 @@ package engine
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/dolthub/go-mysql-server/sql"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/commithooks"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/webhooks"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
 )
 
 // CollectDBs takes a MultiRepoEnv and creates Database objects from each environment and returns a slice of these
-// objects.
-func CollectDBs(ctx context.Context, mrEnv *env.MultiRepoEnv, useBulkEditor bool) ([]dsess.SqlDatabase, []filesys.Filesys, error) {
-	var dbs []dsess.SqlDatabase
-	var locations []filesys.Filesys
-	var db dsess.SqlDatabase
-
-	err := mrEnv.Iter(func(name string, dEnv *env.DoltEnv) (stop bool, err error) {
-		db, err = newDatabase(ctx, name, dEnv, useBulkEditor)
-		if err != nil {
-			return false, err
+// objects, in the same order as the environments. Up to |concurrency| databases are created at once, and the
+// per-database system variables of each are defined as it's created. Environments whose DoltDB hasn't been opened
+// are skipped; see lazyDatabaseLoaders.
+func CollectDBs(ctx context.Context, mrEnv *env.MultiRepoEnv, useBulkEditor bool, concurrency int) ([]dsess.SqlDatabase, []filesys.Filesys, error) {
+	var names []string
+	var dEnvs []*env.DoltEnv
+	_ = mrEnv.Iter(func(name string, dEnv *env.DoltEnv) (stop bool, err error) {
+		if dEnv.DBLoaded() {
+			names = append(names, name)
+			dEnvs = append(dEnvs, dEnv)
 		}
+		return false, nil
+	})
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	dbs := make([]dsess.SqlDatabase, len(dEnvs))
+	locations := make([]filesys.Filesys, len(dEnvs))
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(concurrency)
+	for i := range dEnvs {
+		i := i
+		eg.Go(func() error {
+			db, err := newDatabase(egCtx, names[i], dEnvs[i], useBulkEditor)
+			if err != nil {
+				return err
+			}
+			dsess.DefineSystemVariablesForDB(names[i])
+			dbs[i] = db
+			locations[i] = dEnvs[i].FS
+			return nil
+		})
+	}
+
+	err := eg.Wait()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dbs, locations, nil
+}
 
-		dbs = append(dbs, db)
-		locations = append(locations, dEnv.FS)
+// lazyDatabaseEnv holds what's needed to open the databases that a MultiRepoEnv loaded without their DoltDBs, the
+// same way the engine opened the others when it was created.
+type lazyDatabaseEnv struct {
+	mrEnv             *env.MultiRepoEnv
+	format            *types.NomsBinFormat
+	useBulkEditor     bool
+	bThreads          *sql.BackgroundThreads
+	clusterController *cluster.Controller
+	webhooks          *webhooks.Dispatcher
+	commitHooks       *commithooks.Runner
+}
 
+// lazyDatabaseLoaders returns a loader for each environment in |lazy.mrEnv| whose DoltDB hasn't been opened.
+func lazyDatabaseLoaders(lazy lazyDatabaseEnv) map[string]sqle.LazyDatabaseLoader {
+	loaders := make(map[string]sqle.LazyDatabaseLoader)
+	_ = lazy.mrEnv.Iter(func(name string, dEnv *env.DoltEnv) (stop bool, err error) {
+		if !dEnv.DBLoaded() {
+			name, dEnv := name, dEnv
+			loaders[name] = func(ctx *sql.Context) (dsess.SqlDatabase, filesys.Filesys, error) {
+				return lazy.load(ctx, name, dEnv)
+			}
+		}
 		return false, nil
 	})
+	return loaders
+}
 
+func (lazy lazyDatabaseEnv) load(ctx *sql.Context, name string, dEnv *env.DoltEnv) (dsess.SqlDatabase, filesys.Filesys, error) {
+	err := dEnv.LoadDB(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
+	ddb := dEnv.DoltDB
+	if ddb.Format().VersionString() != lazy.format.VersionString() {
+		return nil, nil, fmt.Errorf("incompatible format for database '%s'; expected '%s', found '%s'",
+			name, lazy.format.VersionString(), ddb.Format().VersionString())
+	}
 
-	return dbs, locations, nil
+	db, err := newDatabase(ctx, name, dEnv, lazy.useBulkEditor)
+	if err != nil {
+		return nil, nil, err
+	}
+	dbs, err := sqle.ApplyReplicationConfig(ctx, lazy.bThreads, lazy.mrEnv, cli.CliOut, db)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = lazy.clusterController.ApplyStandbyReplicationConfig(ctx, lazy.bThreads, lazy.mrEnv, dbs[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	err = lazy.webhooks.InstallCommitHook(ctx, name, ddb)
+	if err != nil {
+		return nil, nil, err
+	}
+	lazy.commitHooks.InstallCommitHook(ctx, name, ddb)
+	ddb.SetCommitHookLogger(ctx, cli.CliOut)
+
+	return dbs[0], dEnv.FS, nil
 }
 
 func newDatabase(ctx context.Context, name string, dEnv *env.DoltEnv, useBulkEditor bool) (sqle.Database, error) {
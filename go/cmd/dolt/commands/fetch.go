@@ -36,10 +36,12 @@ var fetchDocs = cli.CommandDocumentationContent{
 By default dolt will attempt to fetch from a remote named {{.EmphasisLeft}}origin{{.EmphasisRight}}.  The {{.LessThan}}remote{{.GreaterThan}} parameter allows you to specify the name of a different remote you wish to pull from by the remote's name.
 
 When no refspec(s) are specified on the command line, the fetch_specs for the default remote are used.
+
+If {{.EmphasisLeft}}--deepen{{.EmphasisRight}} is supplied, the repository must be a shallow clone, made with {{.EmphasisLeft}}dolt clone --depth{{.EmphasisRight}}. Its history is extended by the specified number of commits, before the refs are fetched.
 `,
 
 	Synopsis: []string{
-		"[{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}} ...]",
+		"[--deepen {{.LessThan}}depth{{.GreaterThan}}] [{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}} ...]",
 	},
 }
 
@@ -135,6 +137,11 @@ func constructInterpolatedDoltFetchQuery(apr *argparser.ArgParseResults) (string
 		args = append(args, "?")
 		params = append(params, user)
 	}
+	if deepen, hasDeepen := apr.GetValue(cli.DeepenParam); hasDeepen {
+		args = append(args, "'--deepen'")
+		args = append(args, "?")
+		params = append(params, deepen)
+	}
 	for _, arg := range apr.Args {
 		args = append(args, "?")
 		params = append(params, arg)
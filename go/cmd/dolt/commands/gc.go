@@ -17,6 +17,8 @@ package commands
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/fatih/color"
 
@@ -26,6 +28,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/chunks"
@@ -36,6 +39,8 @@ var gcDocs = cli.CommandDocumentationContent{
 	ShortDesc: "Cleans up unreferenced data from the repository.",
 	LongDesc: `Searches the repository for data that is no longer referenced and no longer needed.
 
+Branches created with {{.EmphasisLeft}}dolt branch --expires{{.EmphasisRight}} whose expiration has passed are deleted before collection begins. The head of each expired branch is archived as the tag {{.EmphasisLeft}}archive/<branchname>{{.EmphasisRight}}.
+
 If the {{.EmphasisLeft}}--shallow{{.EmphasisRight}} flag is supplied, a faster but less thorough garbage collection will be performed.`,
 	Synopsis: []string{
 		"[--shallow]",
@@ -92,7 +97,23 @@ func (cmd GarbageCollectionCmd) Exec(ctx context.Context, commandStr string, arg
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(env.ErrActiveServerLock.New(dEnv.LockFile())), help)
 	}
 
-	var err error
+	// There's no server running, so no other session can be using an expired branch
+	notify := func(branch actions.ExpiredBranch) {
+		msg := fmt.Sprintf("Deleting branch '%s', which expired at %s. Its head was archived as tag '%s'.",
+			branch.Name, branch.Expires.Local().Format(time.RFC1123), branch.Archive)
+		if branch.Owner != "" {
+			msg += fmt.Sprintf(" Its expiration was set by %s.", branch.Owner)
+		}
+		cli.PrintErrln(color.YellowString(msg))
+	}
+	_, err := actions.ExpireBranches(ctx, dEnv.DbData(), time.Now(), nil, notify, func(name string) error {
+		return actions.DeleteBranch(ctx, dEnv.DbData(), name, actions.DeleteOptions{Force: true}, dEnv, nil)
+	})
+	if err != nil {
+		verr = errhand.BuildDError("an error occurred while deleting expired branches").AddCause(err).Build()
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
 	if apr.Contains(cli.ShallowFlag) {
 		err = dEnv.DoltDB.ShallowGC(ctx)
 		if err != nil {
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
@@ -27,6 +28,7 @@ import (
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/commitfmt"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/store/util/outputpager"
@@ -46,6 +48,9 @@ The command takes options to control what is shown and how.
 	
 {{.EmphasisLeft}}dolt log [<revisions>...] -- <table>{{.EmphasisRight}}
   Lists commit logs starting from revisions, only including commits with changes to table.
+
+{{.EmphasisLeft}}dolt log [<revisions>...] -- <table> <column>...{{.EmphasisRight}}
+  Lists commit logs starting from revisions, only including commits that changed the values of the columns of table, by inserting or deleting rows, updating the columns, or changing their definitions.
 	
 {{.EmphasisLeft}}dolt log <revisionB>..<revisionA>{{.EmphasisRight}}
 {{.EmphasisLeft}}dolt log <revisionA> --not <revisionB>{{.EmphasisRight}}
@@ -54,9 +59,31 @@ The command takes options to control what is shown and how.
 	
 {{.EmphasisLeft}}dolt log <revisionB>...<revisionA>{{.EmphasisRight}}
 {{.EmphasisLeft}}dolt log <revisionA> <revisionB> --not $(dolt merge-base <revisionA> <revisionB>){{.EmphasisRight}}
-  Different ways to list three dot logs. These will list commit logs reachable by revisionA OR revisionB, while excluding commits reachable by BOTH revisionA AND revisionB.`,
+  Different ways to list three dot logs. These will list commit logs reachable by revisionA OR revisionB, while excluding commits reachable by BOTH revisionA AND revisionB.
+
+{{.EmphasisLeft}}dolt log --graph{{.EmphasisRight}}
+  Draws the history as a text-based graph beside the commits, showing where branches split off and are merged. The graph can't be drawn when commits are filtered, by table or by their number of parents.
+
+{{.EmphasisLeft}}dolt log --pretty=<format>{{.EmphasisRight}}
+{{.EmphasisLeft}}dolt log --format=<format>{{.EmphasisRight}}
+  Shows each commit in the given format: {{.EmphasisLeft}}oneline{{.EmphasisRight}}, the same as {{.EmphasisLeft}}--oneline{{.EmphasisRight}}, {{.EmphasisLeft}}medium{{.EmphasisRight}}, the default, or {{.EmphasisLeft}}format:<string>{{.EmphasisRight}}, which prints the string for each commit with these placeholders replaced:
+    %H: commit hash
+    %P: parent hashes
+    %an: author name
+    %ae: author email
+    %ad: author date
+    %at: author date, as a UNIX timestamp
+    %aI: author date, in strict ISO 8601 format
+    %s: subject, the first line of the commit message
+    %b: body, the rest of the commit message
+    %B: commit message
+    %d: refs, like " (HEAD -> main, tag: v1)"
+    %D: refs, without the parentheses
+    %n: newline
+    %%: a literal %
+  A format with placeholders can be given without {{.EmphasisLeft}}format:{{.EmphasisRight}}, as in {{.EmphasisLeft}}dolt log --format="%H %an %s"{{.EmphasisRight}}.`,
 	Synopsis: []string{
-		`[-n {{.LessThan}}num_commits{{.GreaterThan}}] [{{.LessThan}}revision-range{{.GreaterThan}}] [[--] {{.LessThan}}table{{.GreaterThan}}]`,
+		`[-n {{.LessThan}}num_commits{{.GreaterThan}}] [{{.LessThan}}revision-range{{.GreaterThan}}] [[--] {{.LessThan}}table{{.GreaterThan}} [{{.LessThan}}column{{.GreaterThan}}...]]`,
 	},
 }
 
@@ -100,6 +127,11 @@ func (cmd LogCmd) logWithLoggerFunc(ctx context.Context, commandStr string, args
 	help, _ := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, logDocs, ap))
 	apr := cli.ParseArgsOrDie(ap, args, help)
 
+	format, err := getLogFormat(apr)
+	if err != nil {
+		return handleErrAndExit(err)
+	}
+
 	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
 	if err != nil {
 		cli.PrintErrln(err)
@@ -109,7 +141,7 @@ func (cmd LogCmd) logWithLoggerFunc(ctx context.Context, commandStr string, args
 		defer closeFunc()
 	}
 
-	query, err := constructInterpolatedDoltLogQuery(apr, queryist, sqlCtx)
+	query, err := constructInterpolatedDoltLogQuery(apr, format, queryist, sqlCtx)
 	if err != nil {
 		return handleErrAndExit(err)
 	}
@@ -118,19 +150,51 @@ func (cmd LogCmd) logWithLoggerFunc(ctx context.Context, commandStr string, args
 		return handleErrAndExit(err)
 	}
 
-	return logCommits(apr, logRows, queryist, sqlCtx)
+	return logCommits(apr, format, logRows, queryist, sqlCtx)
+}
+
+// getLogFormat returns the pretty format the log is printed in: medium by default, or the one given by --oneline,
+// --pretty or --format.
+func getLogFormat(apr *argparser.ArgParseResults) (commitfmt.Format, error) {
+	format := commitfmt.Format{Name: commitfmt.PrettyMedium}
+	if apr.Contains(cli.OneLineFlag) {
+		format.Name = commitfmt.PrettyOneline
+	}
+	for _, param := range []string{cli.PrettyParam, cli.FormatParam} {
+		if formatStr, ok := apr.GetValue(param); ok {
+			var err error
+			format, err = commitfmt.ParseFormat(formatStr)
+			if err != nil {
+				return commitfmt.Format{}, err
+			}
+		}
+	}
+	return format, nil
+}
+
+// logQueryColumns returns the columns of DOLT_LOG() selected by the log query: the commit hash, and the columns the
+// options given need.
+func logQueryColumns(apr *argparser.ArgParseResults, format commitfmt.Format) []string {
+	columns := []string{"commit_hash"}
+	if apr.Contains(cli.ShowSignatureFlag) {
+		columns = append(columns, "signature")
+	}
+	if format.Name == "" {
+		columns = append(columns, "formatted")
+	}
+	return columns
 }
 
 // constructInterpolatedDoltLogQuery generates the sql query necessary to call the DOLT_LOG() function.
 // Also interpolates this query to prevent sql injection.
-func constructInterpolatedDoltLogQuery(apr *argparser.ArgParseResults, queryist cli.Queryist, sqlCtx *sql.Context) (string, error) {
+func constructInterpolatedDoltLogQuery(apr *argparser.ArgParseResults, format commitfmt.Format, queryist cli.Queryist, sqlCtx *sql.Context) (string, error) {
 	var params []interface{}
 
 	var buffer bytes.Buffer
 	var first bool
 	first = true
 
-	buffer.WriteString("select commit_hash from dolt_log(")
+	buffer.WriteString("select " + strings.Join(logQueryColumns(apr, format), ", ") + " from dolt_log(")
 
 	writeToBuffer := func(s string) {
 		if !first {
@@ -145,15 +209,20 @@ func constructInterpolatedDoltLogQuery(apr *argparser.ArgParseResults, queryist
 			writeToBuffer("?")
 			params = append(params, apr.Arg(i))
 		}
-		var tableNames []string
-		for i := apr.PositionalArgsSeparatorIndex; i < apr.NArg(); i++ {
-			tableNames = append(tableNames, apr.Arg(i))
+		tableNames, columnNames, err := getTablesAndColumns(apr.Args[:apr.PositionalArgsSeparatorIndex], apr.Args[apr.PositionalArgsSeparatorIndex:], queryist, sqlCtx)
+		if err != nil {
+			return "", err
 		}
 		if len(tableNames) > 0 {
 			params = append(params, strings.Join(tableNames, ","))
 			writeToBuffer("'--tables'")
 			writeToBuffer("?")
 		}
+		if len(columnNames) > 0 {
+			params = append(params, strings.Join(columnNames, ","))
+			writeToBuffer("'--columns'")
+			writeToBuffer("?")
+		}
 	} else {
 		var existingTables map[string]bool
 		seenRevs := make(map[string]bool, apr.NArg())
@@ -229,6 +298,20 @@ func constructInterpolatedDoltLogQuery(apr *argparser.ArgParseResults, queryist
 		}
 	}
 
+	if apr.Contains(cli.ShowSignatureFlag) {
+		writeToBuffer("'--show-signature'")
+	}
+
+	// the graph is drawn here, but it's included to check that the commits aren't filtered
+	if apr.Contains(cli.GraphFlag) {
+		writeToBuffer("'--graph'")
+	}
+
+	if format.Name == "" {
+		writeToBuffer("?")
+		params = append(params, "--pretty=format:"+format.Template)
+	}
+
 	// included to check for invalid --decorate options
 	if decorate, hasDecorate := apr.GetValue(cli.DecorateFlag); hasDecorate {
 		writeToBuffer("?")
@@ -253,6 +336,29 @@ func constructInterpolatedDoltLogQuery(apr *argparser.ArgParseResults, queryist
 	return interpolatedQuery, nil
 }
 
+// getTablesAndColumns splits the arguments after the -- separator into the tables and the columns of them that the
+// log is restricted to: the first argument is a table, as is any later one that's a table in the history of the
+// revisions given, and the rest are columns.
+func getTablesAndColumns(revisions []string, args []string, queryist cli.Queryist, sqlCtx *sql.Context) ([]string, []string, error) {
+	if len(args) <= 1 {
+		return args, nil, nil
+	}
+	existingTables, err := getExistingTables(revisions, queryist, sqlCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	tableNames := []string{args[0]}
+	var columnNames []string
+	for _, arg := range args[1:] {
+		if existingTables[arg] {
+			tableNames = append(tableNames, arg)
+		} else {
+			columnNames = append(columnNames, arg)
+		}
+	}
+	return tableNames, columnNames, nil
+}
+
 // getExistingTables returns a map of table names that exist in the commit history of the given revisions
 func getExistingTables(revisions []string, queryist cli.Queryist, sqlCtx *sql.Context) (map[string]bool, error) {
 	tableNames := make(map[string]bool)
@@ -274,8 +380,9 @@ func getExistingTables(revisions []string, queryist cli.Queryist, sqlCtx *sql.Co
 	return tableNames, nil
 }
 
-// logCommits takes a list of sql rows that have only 1 column, commit hash, and retrieves the commit info for each hash to be printed to std out
-func logCommits(apr *argparser.ArgParseResults, commitHashes []sql.Row, queryist cli.Queryist, sqlCtx *sql.Context) int {
+// logCommits takes a list of sql rows with the columns of logQueryColumns, and retrieves the commit info for each hash to be printed to std out
+func logCommits(apr *argparser.ArgParseResults, format commitfmt.Format, commitHashes []sql.Row, queryist cli.Queryist, sqlCtx *sql.Context) int {
+	columns := logQueryColumns(apr, format)
 	var commitsInfo []CommitInfo
 	for _, hash := range commitHashes {
 		cmHash := hash[0].(string)
@@ -283,57 +390,105 @@ func logCommits(apr *argparser.ArgParseResults, commitHashes []sql.Row, queryist
 		if err != nil {
 			return handleErrAndExit(err)
 		}
+		for i, col := range columns {
+			if hash[i] == nil {
+				continue
+			}
+			switch col {
+			case "signature":
+				commit.signature = hash[i].(string)
+			case "formatted":
+				commit.formatted = hash[i].(string)
+			}
+		}
 		commitsInfo = append(commitsInfo, *commit)
 	}
 
-	logToStdOut(apr, commitsInfo)
+	if cli.MachineReadableOutput() {
+		if err := printLogRows(apr, commitsInfo); err != nil {
+			return handleErrAndExit(err)
+		}
+		return 0
+	}
+
+	logToStdOut(apr, format, commitsInfo)
 
 	return 0
 }
 
-func logCompact(pager *outputpager.Pager, apr *argparser.ArgParseResults, commits []CommitInfo) {
+// printLogRows writes |commits| in the global --format, with their parents separated by commas.
+func printLogRows(apr *argparser.ArgParseResults, commits []CommitInfo) error {
+	minParents := apr.GetIntOrDefault(cli.MinParentsFlag, 0)
+	var rows []sql.Row
 	for _, comm := range commits {
-		if len(comm.parentHashes) < apr.GetIntOrDefault(cli.MinParentsFlag, 0) {
-			return
+		if len(comm.parentHashes) < minParents {
+			continue
 		}
+		meta := comm.commitMeta
+		rows = append(rows, sql.Row{comm.commitHash, meta.Name, meta.Email, meta.Time().UTC(), meta.Description, strings.Join(comm.parentHashes, ",")})
+	}
+	return printOutputRows(logOutputSchema, rows)
+}
 
-		chStr := comm.commitHash
-		if apr.Contains(cli.ParentsFlag) {
-			for _, h := range comm.parentHashes {
-				chStr += " " + h
-			}
+// writeCompact writes the given commit to |w| on one line, in the format used by log --oneline.
+func writeCompact(w io.Writer, apr *argparser.ArgParseResults, comm *CommitInfo) {
+	chStr := comm.commitHash
+	if apr.Contains(cli.ParentsFlag) {
+		for _, h := range comm.parentHashes {
+			chStr += " " + h
 		}
+	}
 
-		// TODO: use short hash instead
-		// Write commit hash
-		pager.Writer.Write([]byte(fmt.Sprintf("\033[33m%s \033[0m", chStr)))
+	printSignature(w, comm)
 
-		if decoration := apr.GetValueOrDefault(cli.DecorateFlag, "auto"); decoration != "no" {
-			printRefs(pager, &comm, decoration)
-		}
+	// TODO: use short hash instead
+	// Write commit hash
+	w.Write([]byte(fmt.Sprintf("\033[33m%s \033[0m", chStr)))
 
-		formattedDesc := strings.Replace(comm.commitMeta.Description, "\n", " ", -1) + "\n"
-		pager.Writer.Write([]byte(formattedDesc))
+	if decoration := apr.GetValueOrDefault(cli.DecorateFlag, "auto"); decoration != "no" {
+		printRefs(w, comm, decoration)
 	}
-}
 
-func logDefault(pager *outputpager.Pager, apr *argparser.ArgParseResults, commits []CommitInfo) {
-	for _, comm := range commits {
-		PrintCommitInfo(pager, apr.GetIntOrDefault(cli.MinParentsFlag, 0), apr.Contains(cli.ParentsFlag), apr.GetValueOrDefault(cli.DecorateFlag, "auto"), &comm)
-	}
+	formattedDesc := strings.Replace(comm.commitMeta.Description, "\n", " ", -1) + "\n"
+	w.Write([]byte(formattedDesc))
 }
 
-func logToStdOut(apr *argparser.ArgParseResults, commits []CommitInfo) {
+func logToStdOut(apr *argparser.ArgParseResults, format commitfmt.Format, commits []CommitInfo) {
 	if cli.ExecuteWithStdioRestored == nil {
 		return
 	}
 	cli.ExecuteWithStdioRestored(func() {
 		pager := outputpager.Start()
 		defer pager.Stop()
-		if apr.Contains(cli.OneLineFlag) {
-			logCompact(pager, apr, commits)
-		} else {
-			logDefault(pager, apr, commits)
+
+		var graph *commitfmt.Graph
+		if apr.Contains(cli.GraphFlag) {
+			graph = commitfmt.NewGraph()
+		}
+		minParents := apr.GetIntOrDefault(cli.MinParentsFlag, 0)
+		for _, comm := range commits {
+			if len(comm.parentHashes) < minParents {
+				continue
+			}
+
+			var buf bytes.Buffer
+			switch format.Name {
+			case commitfmt.PrettyOneline:
+				writeCompact(&buf, apr, &comm)
+			case commitfmt.PrettyMedium:
+				writeCommitInfo(&buf, apr.Contains(cli.ParentsFlag), apr.GetValueOrDefault(cli.DecorateFlag, "auto"), &comm)
+			default:
+				buf.WriteString(comm.formatted + "\n")
+			}
+
+			if graph == nil {
+				pager.Writer.Write(buf.Bytes())
+				continue
+			}
+			// the graph is drawn beside each line of the commit, and continues down any lines between commits
+			row := graph.Next(comm.commitHash, comm.parentHashes)
+			text := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			pager.Writer.Write([]byte(strings.Join(row.Prefix(text), "\n") + "\n"))
 		}
 	})
 }
@@ -20,6 +20,8 @@ import (
 	"io"
 	"sort"
 
+	"github.com/dolthub/go-mysql-server/sql"
+
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
@@ -98,7 +100,9 @@ func (cmd LsCmd) Exec(ctx context.Context, commandStr string, args []string, dEn
 		label, root, verr = getRootForCommitSpecStr(ctx, apr.Arg(0), dEnv)
 	}
 
-	if verr == nil {
+	if verr == nil && cli.MachineReadableOutput() {
+		verr = printTableRows(ctx, root, !apr.Contains(systemFlag) || apr.Contains(cli.AllFlag), apr.Contains(systemFlag) || apr.Contains(cli.AllFlag))
+	} else if verr == nil {
 		if !apr.Contains(systemFlag) || apr.Contains(cli.AllFlag) {
 			verr = printUserTables(ctx, root, label, apr.Contains(cli.VerboseFlag))
 			cli.Println()
@@ -175,6 +179,36 @@ func printUserTables(ctx context.Context, root *doltdb.RootValue, label string,
 	return nil
 }
 
+// printTableRows writes the user tables and system tables in |root| in the global --format.
+func printTableRows(ctx context.Context, root *doltdb.RootValue, userTables, systemTables bool) errhand.VerboseError {
+	var rows []sql.Row
+	if userTables {
+		tblNames, err := doltdb.GetNonSystemTableNames(ctx, root)
+		if err != nil {
+			return errhand.BuildDError("error: failed to get tables").AddCause(err).Build()
+		}
+		for _, tbl := range tblNames {
+			rows = append(rows, sql.Row{tbl, false})
+		}
+	}
+
+	if systemTables {
+		perSysTbls, err := doltdb.GetPersistedSystemTables(ctx, root)
+		if err != nil {
+			return errhand.BuildDError("error retrieving persisted table names").AddCause(err).Build()
+		}
+		genSysTbls, err := doltdb.GetGeneratedSystemTables(ctx, root)
+		if err != nil {
+			return errhand.BuildDError("error retrieving generated table names").AddCause(err).Build()
+		}
+		for _, tbl := range append(perSysTbls, genSysTbls...) {
+			rows = append(rows, sql.Row{tbl, true})
+		}
+	}
+
+	return errhand.VerboseErrorFromError(printOutputRows(lsOutputSchema, rows))
+}
+
 func listTableVerbose(ctx context.Context, tbl string, root *doltdb.RootValue) (string, errhand.VerboseError) {
 	h, _, err := root.GetTableHash(ctx, tbl)
 	if err != nil {
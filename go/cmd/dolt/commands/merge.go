@@ -36,6 +36,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/store/util/outputpager"
 )
@@ -46,12 +47,13 @@ var mergeDocs = cli.CommandDocumentationContent{
 
 The second syntax ({{.LessThan}}dolt merge --abort{{.GreaterThan}}) can only be run after the merge has resulted in conflicts. dolt merge {{.EmphasisLeft}}--abort{{.EmphasisRight}} will abort the merge process and try to reconstruct the pre-merge state. However, if there were uncommitted changes when the merge started (and especially if those changes were further modified after the merge was started), dolt merge {{.EmphasisLeft}}--abort{{.EmphasisRight}} will in some cases be unable to reconstruct the original (pre-merge) changes. Therefore: 
 
-{{.LessThan}}Warning{{.GreaterThan}}: Running dolt merge with non-trivial uncommitted changes is discouraged: while possible, it may leave you in a state that is hard to back out of in the case of a conflict.
+{{.LessThan}}Warning{{.GreaterThan}}: Running dolt merge with non-trivial uncommitted changes is discouraged: while possible, it may leave you in a state that is hard to back out of in the case of a conflict. Use {{.EmphasisLeft}}--autostash{{.EmphasisRight}} to stash them before merging, and apply them again once the merge is done.
 `,
 
 	Synopsis: []string{
 		"[--squash] {{.LessThan}}branch{{.GreaterThan}}",
 		"--no-ff [-m message] {{.LessThan}}branch{{.GreaterThan}}",
+		"[-m message] {{.LessThan}}branch{{.GreaterThan}} {{.LessThan}}branch{{.GreaterThan}}...",
 		"--abort",
 	},
 }
@@ -129,6 +131,16 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 		cli.Println(err.Error())
 		return 1
 	}
+	// the warnings of the merge say whether the stashed changes could be applied again
+	var autostashWarnings []string
+	if apr.Contains(cli.AutostashFlag) {
+		warnings := sqlCtx.Session.Warnings()
+		for i := len(warnings) - 1; i >= 0; i-- {
+			if warnings[i].Code == dprocedures.DoltMergeWarningCode {
+				autostashWarnings = append(autostashWarnings, warnings[i].Message)
+			}
+		}
+	}
 	// if merge is called with '--no-commit', we need to commit the sql transaction or the staged changes will be lost
 	_, _, err = queryist.Query(sqlCtx, "COMMIT")
 	if err != nil {
@@ -149,19 +161,22 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 	// calculate merge stats
 	if !apr.Contains(cli.AbortParam) {
-		//todo: refs with the `remotes/` prefix will fail to get a hash
-		mergeHash, mergeHashErr := getHashOf(queryist, sqlCtx, apr.Arg(0))
-		if mergeHashErr != nil {
-			cli.Println("merge finished, but failed to get hash of merge ref")
-			cli.Println(mergeHashErr.Error())
-		}
-		headHash, headhHashErr := getHashOf(queryist, sqlCtx, "HEAD")
-		if headhHashErr != nil {
-			cli.Println("merge finished, but failed to get hash of HEAD")
-			cli.Println(headhHashErr.Error())
-		}
-		if mergeHashErr == nil && headhHashErr == nil {
-			cli.Println("Updating", headHash+".."+mergeHash)
+		// an octopus merge has no single ref to report updating to
+		if apr.NArg() == 1 {
+			//todo: refs with the `remotes/` prefix will fail to get a hash
+			mergeHash, mergeHashErr := getHashOf(queryist, sqlCtx, apr.Arg(0))
+			if mergeHashErr != nil {
+				cli.Println("merge finished, but failed to get hash of merge ref")
+				cli.Println(mergeHashErr.Error())
+			}
+			headHash, headhHashErr := getHashOf(queryist, sqlCtx, "HEAD")
+			if headhHashErr != nil {
+				cli.Println("merge finished, but failed to get hash of HEAD")
+				cli.Println(headhHashErr.Error())
+			}
+			if mergeHashErr == nil && headhHashErr == nil {
+				cli.Println("Updating", headHash+".."+mergeHash)
+			}
 		}
 
 		if apr.Contains(cli.SquashParam) {
@@ -215,6 +230,9 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 		}
 
 		hasConflicts, hasConstraintViolations := printSuccessStats(mergeStats)
+		for _, warning := range autostashWarnings {
+			cli.Println(color.YellowString("warning: %s", warning))
+		}
 		return handleMergeErr(sqlCtx, queryist, nil, hasConflicts, hasConstraintViolations, usage)
 	}
 
@@ -244,7 +262,7 @@ func validateDoltMergeArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinte
 			return 1
 		}
 	} else if apr.Contains(cli.NoFFParam) {
-		if apr.NArg() == 0 || apr.NArg() > 2 {
+		if apr.NArg() == 0 {
 			usage()
 			return 1
 		}
@@ -309,6 +327,12 @@ func constructInterpolatedDoltMergeQuery(apr *argparser.ArgParseResults, cliCtx
 	if apr.Contains(cli.NoEditFlag) {
 		writeToBuffer("--no-edit", false)
 	}
+	if apr.Contains(cli.NoRenamesFlag) {
+		writeToBuffer("--no-renames", false)
+	}
+	if apr.Contains(cli.AutostashFlag) {
+		writeToBuffer("--autostash", false)
+	}
 
 	writeToBuffer("--author", false)
 	var author string
@@ -344,8 +368,10 @@ func constructInterpolatedDoltMergeQuery(apr *argparser.ArgParseResults, cliCtx
 	}
 
 	if !apr.Contains(cli.AbortParam) && !apr.Contains(cli.SquashParam) {
-		writeToBuffer("?", true)
-		params = append(params, apr.Arg(0))
+		for _, arg := range apr.Args {
+			writeToBuffer("?", true)
+			params = append(params, arg)
+		}
 	}
 
 	buffer.WriteString(")")
@@ -0,0 +1,108 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+)
+
+// The schemas the read-only commands write their output with when the global --format is json or csv. Scripts depend
+// on these, so columns may be added to them, but not renamed or removed.
+var (
+	statusOutputSchema = sql.Schema{
+		&sql.Column{Name: "table_name", Type: types.LongText},
+		&sql.Column{Name: "staged", Type: types.Boolean},
+		&sql.Column{Name: "status", Type: types.LongText},
+	}
+	branchOutputSchema = sql.Schema{
+		&sql.Column{Name: "name", Type: types.LongText},
+		&sql.Column{Name: "hash", Type: types.LongText},
+		&sql.Column{Name: "remote", Type: types.Boolean},
+		&sql.Column{Name: "current", Type: types.Boolean},
+	}
+	logOutputSchema = sql.Schema{
+		&sql.Column{Name: "commit_hash", Type: types.LongText},
+		&sql.Column{Name: "committer", Type: types.LongText},
+		&sql.Column{Name: "email", Type: types.LongText},
+		&sql.Column{Name: "date", Type: types.Datetime},
+		&sql.Column{Name: "message", Type: types.LongText},
+		&sql.Column{Name: "parents", Type: types.LongText},
+	}
+	tagOutputSchema = sql.Schema{
+		&sql.Column{Name: "tag_name", Type: types.LongText},
+		&sql.Column{Name: "tag_hash", Type: types.LongText},
+		&sql.Column{Name: "tagger", Type: types.LongText},
+		&sql.Column{Name: "email", Type: types.LongText},
+		&sql.Column{Name: "date", Type: types.Datetime},
+		&sql.Column{Name: "message", Type: types.LongText},
+	}
+	remoteOutputSchema = sql.Schema{
+		&sql.Column{Name: "name", Type: types.LongText},
+		&sql.Column{Name: "url", Type: types.LongText},
+		&sql.Column{Name: "fetch_specs", Type: types.JSON},
+		&sql.Column{Name: "params", Type: types.JSON},
+	}
+	backupOutputSchema = sql.Schema{
+		&sql.Column{Name: "name", Type: types.LongText},
+		&sql.Column{Name: "url", Type: types.LongText},
+		&sql.Column{Name: "params", Type: types.JSON},
+	}
+	lsOutputSchema = sql.Schema{
+		&sql.Column{Name: "table_name", Type: types.LongText},
+		&sql.Column{Name: "system", Type: types.Boolean},
+	}
+)
+
+// printOutputRows writes |rows|, which have the schema |sch|, to stdout in the global --format. Commands call it in
+// place of printing their human-readable output when cli.MachineReadableOutput() is true.
+func printOutputRows(sch sql.Schema, rows []sql.Row) error {
+	format := engine.FormatJson
+	if cli.GetOutputFormat() == cli.OutputFormatCSV {
+		format = engine.FormatCsv
+	} else if len(rows) == 0 {
+		// PrettyPrintResults writes an empty object when there are no rows
+		cli.Println(`{"rows": []}`)
+		return nil
+	}
+	return engine.PrettyPrintResults(sql.NewEmptyContext(), format, sch, sql.RowsToRowIter(rows...))
+}
+
+// jsonParams returns |params| as a JSON document, for the params columns of the remote and backup output.
+func jsonParams(params map[string]string) types.JSONDocument {
+	val := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		val[k] = v
+	}
+	return types.JSONDocument{Val: val}
+}
+
+// sortedRemotes returns |remotes| ordered by name.
+func sortedRemotes(remotes map[string]env.Remote) []env.Remote {
+	sorted := make([]env.Remote, 0, len(remotes))
+	for _, r := range remotes {
+		sorted = append(sorted, r)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package patchcmds
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+var applyDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Apply the changes between two revisions to a branch.",
+	LongDesc: `
+Applies the changes between {{.LessThan}}from_revision{{.GreaterThan}} and {{.LessThan}}to_revision{{.GreaterThan}}, the patch that {{.EmphasisLeft}}dolt_patch(){{.EmphasisRight}} would produce for them, to the current branch, or to the branch named with {{.EmphasisLeft}}--branch{{.EmphasisRight}}, and commits the result. The branch must not have uncommitted changes.
+
+The patch is applied as a whole or not at all. If any of its changes conflict with the branch, the command fails and the branch is left as it was.
+
+The commit message records the commits that the revisions resolved to, with {{.EmphasisLeft}}Patch-From{{.EmphasisRight}} and {{.EmphasisLeft}}Patch-To{{.EmphasisRight}} lines.
+`,
+	Synopsis: []string{
+		`[--branch {{.LessThan}}branch{{.GreaterThan}}] [-m {{.LessThan}}msg{{.GreaterThan}}] {{.LessThan}}from_revision{{.GreaterThan}} {{.LessThan}}to_revision{{.GreaterThan}}`,
+	},
+}
+
+type ApplyCmd struct{}
+
+var _ cli.Command = ApplyCmd{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd ApplyCmd) Name() string {
+	return "apply"
+}
+
+// Description returns a description of the command
+func (cmd ApplyCmd) Description() string {
+	return "Apply the changes between two revisions to a branch."
+}
+
+func (cmd ApplyCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(applyDocs, ap)
+}
+
+func (cmd ApplyCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateApplyPatchArgParser()
+}
+
+// Exec executes the command
+func (cmd ApplyCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, applyDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() != 2 {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("a from revision and a to revision must be supplied").SetPrintUsage().Build(), usage)
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	return commands.HandleVErrAndExitCode(applyPatch(queryist, sqlCtx, apr), usage)
+}
+
+func applyPatch(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgParseResults) errhand.VerboseError {
+	var params []interface{}
+	branch, hasBranch := apr.GetValue(cli.BranchParam)
+	if hasBranch {
+		params = append(params, "--"+cli.BranchParam, branch)
+	}
+	if msg, ok := apr.GetValue(cli.MessageArg); ok {
+		params = append(params, "--"+cli.MessageArg, msg)
+	}
+	params = append(params, apr.Arg(0), apr.Arg(1))
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(params)), ", ")
+	q, err := dbr.InterpolateForDialect(fmt.Sprintf("call dolt_apply_patch(%s)", placeholders), params, dialect.MySQL)
+	if err != nil {
+		return errhand.BuildDError("error: failed to interpolate query").AddCause(err).Build()
+	}
+	rows, err := commands.GetRowsForSql(queryist, sqlCtx, q)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	if len(rows) != 1 {
+		return errhand.BuildDError("error: unexpected number of rows returned from dolt_apply_patch: %d", len(rows)).Build()
+	}
+
+	if !hasBranch {
+		branch = "HEAD"
+	}
+	q, err = dbr.InterpolateForDialect("select hashof(?)", []interface{}{branch}, dialect.MySQL)
+	if err != nil {
+		return errhand.BuildDError("error: failed to interpolate query").AddCause(err).Build()
+	}
+	hashRows, err := commands.GetRowsForSql(queryist, sqlCtx, q)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	if len(hashRows) != 1 {
+		return errhand.BuildDError("error: could not load the commit of the applied patch").Build()
+	}
+
+	cli.Printf("Applied %v row changes in commit %v\n", rows[0][0], hashRows[0][0])
+	return nil
+}
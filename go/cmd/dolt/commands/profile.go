@@ -34,30 +34,49 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
+	"github.com/dolthub/dolt/go/libraries/utils/keychain"
 )
 
 var profileDocs = cli.CommandDocumentationContent{
 	ShortDesc: "Manage dolt profiles for CLI global options.",
-	LongDesc: `With no arguments, shows a list of existing profiles. Two subcommands are available to perform operations on the profiles.
+	LongDesc: `With no arguments, shows a list of existing profiles. Four subcommands are available to perform operations on the profiles.
+
+{{.EmphasisLeft}}list{{.EmphasisRight}}
+Shows a list of existing profiles, the same as running {{.EmphasisLeft}}dolt profile{{.EmphasisRight}} with no arguments.
+
+{{.EmphasisLeft}}show{{.EmphasisRight}}
+Shows the details of the profile named {{.LessThan}}name{{.GreaterThan}}.
 
 {{.EmphasisLeft}}add{{.EmphasisRight}}
 Adds a profile named {{.LessThan}}name{{.GreaterThan}}. Returns an error if the profile already exists.
 
 {{.EmphasisLeft}}remove{{.EmphasisRight}}, {{.EmphasisLeft}}rm{{.EmphasisRight}}
-Remove the profile named {{.LessThan}}name{{.GreaterThan}}.`,
+Remove the profile named {{.LessThan}}name{{.GreaterThan}}.
+
+By default, the password of a profile is stored in the global config in plain text. When adding a profile, {{.EmphasisLeft}}--keychain{{.EmphasisRight}} stores the password in the OS keychain instead: the macOS Keychain, the Windows Credential Manager, or the Secret Service on other systems, which requires {{.EmphasisLeft}}secret-tool{{.EmphasisRight}}. Alternatively, {{.EmphasisLeft}}--credential-helper{{.EmphasisRight}} names a program that stores and provides the password, using the protocol of git credential helpers: it is run with {{.EmphasisLeft}}get{{.EmphasisRight}}, {{.EmphasisLeft}}store{{.EmphasisRight}} or {{.EmphasisLeft}}erase{{.EmphasisRight}} as its last argument, and passed the profile, user, host and port as key=value lines on stdin. Given {{.EmphasisLeft}}get{{.EmphasisRight}}, it prints a password=value line on stdout. Passwords stored either way are only looked up when the profile is used.`,
 	Synopsis: []string{
 		"[-v | --verbose]",
-		"add [-u {{.LessThan}}user{{.GreaterThan}}] [-p {{.LessThan}}password{{.GreaterThan}}] [--host {{.LessThan}}host{{.GreaterThan}}] [--port {{.LessThan}}port{{.GreaterThan}}] [--no-tls] [--data-dir {{.LessThan}}directory{{.GreaterThan}}] [--doltcfg-dir {{.LessThan}}directory{{.GreaterThan}}] [--privilege-file {{.LessThan}}privilege file{{.GreaterThan}}] [--branch-control-file {{.LessThan}}branch control file{{.GreaterThan}}] [--use-db {{.LessThan}}database{{.GreaterThan}}] {{.LessThan}}name{{.GreaterThan}}",
-		"remove {{.LessThan}}name{{.GreaterThan}}",
+		"list [-v | --verbose]",
+		"show {{.LessThan}}name{{.GreaterThan}}",
+		"add [-u {{.LessThan}}user{{.GreaterThan}}] [-p {{.LessThan}}password{{.GreaterThan}}] [--keychain | --credential-helper {{.LessThan}}helper{{.GreaterThan}}] [--host {{.LessThan}}host{{.GreaterThan}}] [--port {{.LessThan}}port{{.GreaterThan}}] [--no-tls] [--data-dir {{.LessThan}}directory{{.GreaterThan}}] [--doltcfg-dir {{.LessThan}}directory{{.GreaterThan}}] [--privilege-file {{.LessThan}}privilege file{{.GreaterThan}}] [--branch-control-file {{.LessThan}}branch control file{{.GreaterThan}}] [--use-db {{.LessThan}}database{{.GreaterThan}}] {{.LessThan}}name{{.GreaterThan}}",
+		"remove | rm {{.LessThan}}name{{.GreaterThan}}",
 	},
 }
 
 const (
 	addProfileId          = "add"
 	removeProfileId       = "remove"
+	rmProfileId           = "rm"
+	listProfileId         = "list"
+	showProfileId         = "show"
+	keychainFlag          = "keychain"
+	credentialHelperFlag  = "credential-helper"
 	GlobalCfgProfileKey   = "profile"
 	DefaultProfileName    = "default"
 	defaultProfileWarning = "Default profile has been added. All dolt commands taking global arguments will use this default profile until it is removed.\nWARNING: This will alter the behavior of commands which specify no `--profile`.\nIf you are using dolt in contexts where you expect a `.dolt` directory to be accessed, the default profile will be used instead."
+
+	// ProfileKeychainService is the service profile passwords are stored under in the OS keychain
+	ProfileKeychainService = "dolt-profile"
 )
 
 type ProfileCmd struct{}
@@ -79,8 +98,10 @@ func (cmd ProfileCmd) Docs() *cli.CommandDocumentation {
 
 func (cmd ProfileCmd) ArgParser() *argparser.ArgParser {
 	ap := cli.CreateGlobalArgParser("profile")
-	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"name", "Defines the name of the profile to add or remove."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"name", "Defines the name of the profile to show, add or remove."})
 	ap.SupportsFlag(cli.VerboseFlag, "v", "Includes full details when printing list of profiles.")
+	ap.SupportsFlag(keychainFlag, "", "Stores the password of the profile in the OS keychain, rather than in the global config.")
+	ap.SupportsString(credentialHelperFlag, "", "helper", "A credential helper program that stores and provides the password of the profile.")
 	return ap
 }
 
@@ -104,9 +125,17 @@ func (cmd ProfileCmd) Exec(ctx context.Context, commandStr string, args []string
 	switch {
 	case apr.NArg() == 0:
 		verr = printProfiles(dEnv, apr)
+	case apr.Arg(0) == listProfileId:
+		if apr.NArg() != 1 {
+			verr = errhand.BuildDError("list takes no arguments").SetPrintUsage().Build()
+		} else {
+			verr = printProfiles(dEnv, apr)
+		}
+	case apr.Arg(0) == showProfileId:
+		verr = showProfile(dEnv, apr)
 	case apr.Arg(0) == addProfileId:
 		verr = addProfile(dEnv, apr)
-	case apr.Arg(0) == removeProfileId:
+	case apr.Arg(0) == removeProfileId, apr.Arg(0) == rmProfileId:
 		verr = removeProfile(dEnv, apr)
 	default:
 		verr = errhand.BuildDError("").SetPrintUsage().Build()
@@ -122,6 +151,13 @@ func addProfile(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Verbo
 
 	profileName := strings.TrimSpace(apr.Arg(1))
 
+	if apr.Contains(keychainFlag) && apr.Contains(credentialHelperFlag) {
+		return errhand.BuildDError("error: --%s and --%s cannot be used together", keychainFlag, credentialHelperFlag).Build()
+	}
+	if apr.Contains(keychainFlag) && !apr.Contains(cli.PasswordFlag) {
+		return errhand.BuildDError("error: --%s requires a password to store", keychainFlag).Build()
+	}
+
 	p := newProfile(apr)
 	profStr := p.String()
 
@@ -146,6 +182,13 @@ func addProfile(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Verbo
 		return errhand.BuildDError("error: profile %s already exists, please delete this profile and re-add it if you want to edit any values.", profileName).Build()
 	}
 
+	if password, ok := apr.GetValue(cli.PasswordFlag); ok {
+		err = storeProfilePassword(profileName, p, password)
+		if err != nil {
+			return errhand.BuildDError("error: failed to store password, %s", err).Build()
+		}
+	}
+
 	profilesJSON, err = sjson.SetRaw(profilesJSON, profileName, profStr)
 	if err != nil {
 		return errhand.BuildDError("error: failed to add profile, %s", err).Build()
@@ -189,6 +232,11 @@ func removeProfile(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Ve
 	if !profileExists {
 		return errhand.BuildDError("error: profile %s does not exist", profileName).Build()
 	}
+	var p Profile
+	err = json.Unmarshal([]byte(gjson.Get(profilesJSON, profileName).Raw), &p)
+	if err != nil {
+		return errhand.BuildDError("error: failed to unmarshal profile, %s", err).Build()
+	}
 
 	profilesJSON, err = sjson.Delete(profilesJSON, profileName)
 	if err != nil {
@@ -211,6 +259,11 @@ func removeProfile(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Ve
 		return errhand.BuildDError("error: failed to set permissions, %s", err).Build()
 	}
 
+	err = eraseProfilePassword(profileName, p)
+	if err != nil {
+		cli.PrintErrln(color.YellowString("warning: failed to remove the stored password of profile %s, %s", profileName, err))
+	}
+
 	return nil
 }
 
@@ -249,19 +302,123 @@ func printProfiles(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Ve
 	return nil
 }
 
+func showProfile(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
+	if apr.NArg() != 2 {
+		return errhand.BuildDError("Only one profile name can be specified").SetPrintUsage().Build()
+	}
+
+	profileName := strings.TrimSpace(apr.Arg(1))
+
+	cfg, ok := dEnv.Config.GetConfig(env.GlobalConfig)
+	if !ok {
+		return errhand.BuildDError("error: failed to get global config").Build()
+	}
+	encodedProfiles, err := cfg.GetString(GlobalCfgProfileKey)
+	if err != nil {
+		if err == config.ErrConfigParamNotFound {
+			return errhand.BuildDError("error: no existing profiles").Build()
+		}
+		return errhand.BuildDError("error: failed to get profiles, %s", err).Build()
+	}
+	profilesJSON, profileExists, err := decodeProfileAndCheckExists(profileName, encodedProfiles)
+	if err != nil {
+		return errhand.BuildDError("error: failed to decode profiles, %s", err).Build()
+	}
+	if !profileExists {
+		return errhand.BuildDError("error: profile %s does not exist", profileName).Build()
+	}
+
+	var p Profile
+	err = json.Unmarshal([]byte(gjson.Get(profilesJSON, profileName).Raw), &p)
+	if err != nil {
+		return errhand.BuildDError("error: failed to unmarshal profile, %s", err).Build()
+	}
+	prettyPrintProfile(profileName, p, true)
+
+	return nil
+}
+
 func prettyPrintProfile(profileName string, profile Profile, verbose bool) {
 	cli.Println(profileName)
 	if verbose {
-		if profile.HasPassword {
-			cli.Println(fmt.Sprintf("\tuser: %s\n\tpassword: %s\n\thost: %s\n\tport: %s\n\tno-tls: %t\n\tdata-dir: %s\n\tdoltcfg-dir: %s\n\tprivilege-file: %s\n\tbranch-control-file: %s\n\tuse-db: %s\n",
-				profile.User, profile.Password, profile.Host, profile.Port, profile.NoTLS, profile.DataDir, profile.DoltCfgDir, profile.PrivilegeFile, profile.BranchControl, profile.UseDB))
-		} else {
-			cli.Println(fmt.Sprintf("\tuser: %s\n\thost: %s\n\tport: %s\n\tno-tls: %t\n\tdata-dir: %s\n\tdoltcfg-dir: %s\n\tprivilege-file: %s\n\tbranch-control-file: %s\n\tuse-db: %s\n",
-				profile.User, profile.Host, profile.Port, profile.NoTLS, profile.DataDir, profile.DoltCfgDir, profile.PrivilegeFile, profile.BranchControl, profile.UseDB))
+		password := ""
+		switch {
+		case profile.Keychain:
+			password = "\tpassword: <stored in the OS keychain>\n"
+		case profile.CredentialHelper != "":
+			password = fmt.Sprintf("\tpassword: <provided by credential helper %s>\n", profile.CredentialHelper)
+		case profile.HasPassword:
+			password = fmt.Sprintf("\tpassword: %s\n", profile.Password)
 		}
+		cli.Println(fmt.Sprintf("\tuser: %s\n%s\thost: %s\n\tport: %s\n\tno-tls: %t\n\tdata-dir: %s\n\tdoltcfg-dir: %s\n\tprivilege-file: %s\n\tbranch-control-file: %s\n\tuse-db: %s\n",
+			profile.User, password, profile.Host, profile.Port, profile.NoTLS, profile.DataDir, profile.DoltCfgDir, profile.PrivilegeFile, profile.BranchControl, profile.UseDB))
 	}
 }
 
+// ProfilePassword returns the password of the profile |p| named |profileName|, looking it up in the OS keychain or
+// credential helper if it's stored in one. It returns false if the profile has no password.
+func ProfilePassword(profileName string, p Profile) (string, bool, error) {
+	switch {
+	case p.Keychain:
+		password, err := keychain.Get(ProfileKeychainService, profileName)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to get the password of profile %s from the keychain: %w", profileName, err)
+		}
+		return password, true, nil
+	case p.CredentialHelper != "":
+		password, ok, err := keychain.Helper(p.CredentialHelper).Get(profileHelperAttrs(profileName, p))
+		if err != nil {
+			return "", false, fmt.Errorf("failed to get the password of profile %s: %w", profileName, err)
+		}
+		return password, ok, nil
+	default:
+		return p.Password, p.HasPassword, nil
+	}
+}
+
+// storeProfilePassword stores |password| in the OS keychain or credential helper of the profile |p|, if it has one.
+func storeProfilePassword(profileName string, p Profile, password string) error {
+	switch {
+	case p.Keychain:
+		return keychain.Set(ProfileKeychainService, profileName, password)
+	case p.CredentialHelper != "":
+		return keychain.Helper(p.CredentialHelper).Store(profileHelperAttrs(profileName, p), password)
+	default:
+		return nil
+	}
+}
+
+// eraseProfilePassword removes the password of the profile |p| from its OS keychain or credential helper, if it has one.
+func eraseProfilePassword(profileName string, p Profile) error {
+	switch {
+	case p.Keychain:
+		err := keychain.Delete(ProfileKeychainService, profileName)
+		if err == keychain.ErrNotFound {
+			return nil
+		}
+		return err
+	case p.CredentialHelper != "":
+		return keychain.Helper(p.CredentialHelper).Erase(profileHelperAttrs(profileName, p))
+	default:
+		return nil
+	}
+}
+
+// profileHelperAttrs returns the attributes passed to the credential helper of the profile |p|
+func profileHelperAttrs(profileName string, p Profile) map[string]string {
+	attrs := map[string]string{"profile": profileName}
+	if p.User != "" {
+		attrs["user"] = p.User
+	}
+	if p.Host != "" {
+		attrs["host"] = p.Host
+	}
+	if p.Port != "" {
+		attrs["port"] = p.Port
+	}
+	return attrs
+}
+
 // setGlobalConfigPermissions sets permissions on global config file to 0600 to protect potentially sensitive information (credentials)
 func setGlobalConfigPermissions(dEnv *env.DoltEnv) error {
 	homeDir, err := env.GetCurrentUserHomeDir()
@@ -327,6 +484,10 @@ type Profile struct {
 	PrivilegeFile string `json:"privilege-file"`
 	BranchControl string `json:"branch-control-file"`
 	UseDB         string `json:"use-db"`
+	// Keychain is true when the password is stored in the OS keychain rather than in Password
+	Keychain bool `json:"keychain,omitempty"`
+	// CredentialHelper is the credential helper that provides the password, if any
+	CredentialHelper string `json:"credential-helper,omitempty"`
 }
 
 func (p Profile) String() string {
@@ -338,9 +499,14 @@ func (p Profile) String() string {
 }
 
 func newProfile(apr *argparser.ArgParseResults) Profile {
+	password := apr.GetValueOrDefault(cli.PasswordFlag, "")
+	if apr.Contains(keychainFlag) || apr.Contains(credentialHelperFlag) {
+		password = ""
+	}
+
 	return Profile{
 		User:          apr.GetValueOrDefault(cli.UserFlag, ""),
-		Password:      apr.GetValueOrDefault(cli.PasswordFlag, ""),
+		Password:      password,
 		HasPassword:   apr.Contains(cli.PasswordFlag),
 		Host:          apr.GetValueOrDefault(cli.HostFlag, ""),
 		Port:          apr.GetValueOrDefault(cli.PortFlag, ""),
@@ -350,5 +516,8 @@ func newProfile(apr *argparser.ArgParseResults) Profile {
 		PrivilegeFile: apr.GetValueOrDefault(PrivsFilePathFlag, ""),
 		BranchControl: apr.GetValueOrDefault(BranchCtrlPathFlag, ""),
 		UseDB:         apr.GetValueOrDefault(UseDbFlag, ""),
+
+		Keychain:         apr.Contains(keychainFlag),
+		CredentialHelper: apr.GetValueOrDefault(credentialHelperFlag, ""),
 	}
 }
@@ -45,13 +45,16 @@ When the command line does not specify where to push with the {{.LessThan}}remot
 
 When the command line does not specify what to push with {{.LessThan}}refspec{{.GreaterThan}}... then the current branch will be used.
 
-A remote's branch can be deleted by pushing an empty source ref: ` + "`dolt push origin :branch`" + `
+A remote's branch can be deleted by pushing an empty source ref: ` + "`dolt push origin :branch`" + `. Tags are deleted the same way, e.g. ` + "`dolt push origin :refs/tags/v1`" + `, or by name with ` + "`dolt push --delete origin v1`" + `. This is how a tag deleted locally with ` + "`dolt tag -d`" + ` is removed from the remote.
+
+{{.EmphasisLeft}}--force{{.EmphasisRight}} overwrites the remote branch even if the local branch doesn't descend from it, which can lose history pushed there by others. {{.EmphasisLeft}}--force-with-lease{{.EmphasisRight}} is a safer alternative: it only overwrites the remote branch if it still points at the commit of its remote tracking branch, i.e. where it was when it was last fetched, and fails otherwise.
 
 When neither the command-line does not specify what to push, the default behavior is used, which corresponds to the current branch being pushed to the corresponding upstream branch, but as a safety measure, the push is aborted if the upstream branch does not have the same name as the local one.
 `,
 
 	Synopsis: []string{
-		"[-u | --set-upstream] [{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}}]",
+		"[-u | --set-upstream] [-f | --force | --force-with-lease] [{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}}]",
+		"--delete {{.LessThan}}remote{{.GreaterThan}} {{.LessThan}}ref{{.GreaterThan}}",
 	},
 }
 
@@ -93,7 +96,7 @@ func (cmd PushCmd) Exec(ctx context.Context, commandStr string, args []string, d
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 
-	opts, err := env.NewPushOpts(ctx, apr, dEnv.RepoStateReader(), dEnv.DoltDB, apr.Contains(cli.ForceFlag), apr.Contains(cli.SetUpstreamFlag), pushAutoSetUpRemote)
+	opts, err := env.NewPushOpts(ctx, apr, dEnv.RepoStateReader(), dEnv.DoltDB, apr.Contains(cli.ForceFlag), apr.Contains(cli.ForceWithLeaseFlag), apr.Contains(cli.SetUpstreamFlag), pushAutoSetUpRemote, apr.Contains(cli.DeleteFlag))
 	if err != nil {
 		var verr errhand.VerboseError
 		switch err {
@@ -115,6 +118,8 @@ func (cmd PushCmd) Exec(ctx context.Context, commandStr string, args []string, d
 
 		case env.ErrInvalidSetUpstreamArgs:
 			verr = errhand.BuildDError("error: --set-upstream requires <remote> and <refspec> params.").SetPrintUsage().Build()
+		case env.ErrInvalidDeleteArgs:
+			verr = errhand.BuildDError("error: --delete requires <remote> and <ref> params.").SetPrintUsage().Build()
 		default:
 			verr = errhand.VerboseErrorFromError(err)
 		}
@@ -159,6 +164,14 @@ func printInfoForPushError(err error, remote env.Remote, destRef, remoteRef ref.
 		cli.Println("hint: its remote counterpart. Integrate the remote changes (e.g.")
 		cli.Println("hint: 'dolt pull ...') before pushing again.")
 		return errhand.BuildDError("").Build()
+	case actions.ErrStaleLease:
+		cli.Printf("To %s\n", remote.Url)
+		cli.Printf("! [rejected]          %s -> %s (stale info)\n", destRef.String(), remoteRef.String())
+		cli.Printf("error: failed to push some refs to '%s'\n", remote.Url)
+		cli.Println("hint: Updates were rejected because the remote branch has changed since it")
+		cli.Println("hint: was last fetched. Fetch and integrate the remote changes (e.g.")
+		cli.Println("hint: 'dolt pull ...') before pushing again.")
+		return errhand.BuildDError("").Build()
 	case actions.ErrUnknownPushErr:
 		status, ok := status.FromError(err)
 		if ok && status.Code() == codes.PermissionDenied {
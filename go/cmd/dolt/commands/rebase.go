@@ -0,0 +1,206 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/rebase"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/editor"
+	"github.com/dolthub/dolt/go/store/util/outputpager"
+)
+
+var rebaseDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Reapply the commits of the current branch on top of another commit",
+	LongDesc: `Replays the commits of the current branch that aren't reachable from {{.LessThan}}upstream{{.GreaterThan}} on top of it, oldest first, and moves the branch to the last commit replayed. Merge commits are left out. Each commit keeps its author, date and message.
+
+If the {{.EmphasisLeft}}--interactive{{.EmphasisRight}} flag is supplied, the commits to replay are listed in an editor first, one per line, each with an action:
+
+{{.EmphasisLeft}}pick{{.EmphasisRight}}: replay the commit.
+{{.EmphasisLeft}}reword{{.EmphasisRight}}: replay the commit with the message given after its subject.
+{{.EmphasisLeft}}squash{{.EmphasisRight}}: replay the commit, and combine it with the commit before it.
+{{.EmphasisLeft}}drop{{.EmphasisRight}}: leave the commit out.
+
+The lines can be reordered, and a line that's removed drops its commit. Removing every line aborts the rebase.
+
+If a commit can't be replayed cleanly, the rebase stops there. Resolve the conflicts as for {{.EmphasisLeft}}dolt cherry-pick{{.EmphasisRight}}, stage the resolved tables with {{.EmphasisLeft}}dolt add{{.EmphasisRight}}, and run {{.EmphasisLeft}}dolt rebase --continue{{.EmphasisRight}}, or run {{.EmphasisLeft}}dolt rebase --abort{{.EmphasisRight}} to put the branch back where it was.
+
+The rebase is recorded in the database until it's done or aborted, and can be driven through the {{.EmphasisLeft}}DOLT_REBASE(){{.EmphasisRight}} stored procedure as well.`,
+	Synopsis: []string{
+		"[-i] {{.LessThan}}upstream{{.GreaterThan}}",
+		"(--continue | --abort)",
+	},
+}
+
+type RebaseCmd struct{}
+
+var _ cli.Command = RebaseCmd{}
+
+// Name implements the interface cli.Command.
+func (cmd RebaseCmd) Name() string {
+	return "rebase"
+}
+
+// Description implements the interface cli.Command.
+func (cmd RebaseCmd) Description() string {
+	return "Reapply the commits of the current branch on top of another commit."
+}
+
+func (cmd RebaseCmd) RequiresRepo() bool {
+	return false
+}
+
+func (cmd RebaseCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(rebaseDocs, ap)
+}
+
+func (cmd RebaseCmd) ArgParser() *argparser.ArgParser {
+	return cli.CreateRebaseArgParser()
+}
+
+// Exec implements the interface cli.Command.
+func (cmd RebaseCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, rebaseDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.Contains(cli.EditTodoParam) {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: --%s is only supported by DOLT_REBASE()", cli.EditTodoParam).SetPrintUsage().Build(), usage)
+	}
+	if apr.NArg() == 0 && !apr.Contains(cli.ContinueFlag) && !apr.Contains(cli.AbortParam) {
+		usage()
+		return 1
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		cli.Println(err.Error())
+		return 1
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	// a step that stops with conflicts leaves them in the working set, to be resolved before continuing
+	for _, q := range []string{"set @@dolt_allow_commit_conflicts = 1", "set @@dolt_force_transaction_commit = 1"} {
+		if _, err = GetRowsForSql(queryist, sqlCtx, q); err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+	}
+
+	status, message, err := callRebase(queryist, sqlCtx, args...)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	// an interactive rebase waits for its todo list to be edited before it starts
+	if apr.Contains(cli.InteractiveFlag) && status == 1 {
+		status, message, err = editRebaseTodo(queryist, sqlCtx, cliCtx, message)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+	}
+
+	cli.Println(message)
+	if status != 0 || apr.Contains(cli.AbortParam) {
+		return status
+	}
+
+	commit, err := getCommitInfo(queryist, sqlCtx, "HEAD")
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	cli.ExecuteWithStdioRestored(func() {
+		pager := outputpager.Start()
+		defer pager.Stop()
+
+		PrintCommitInfo(pager, 0, false, "auto", commit)
+	})
+	return 0
+}
+
+// editRebaseTodo opens |todo| in the editor, and continues the rebase with the edited todo list. If every step is
+// removed from the todo list, the rebase is aborted.
+func editRebaseTodo(queryist cli.Queryist, sqlCtx *sql.Context, cliCtx cli.CliContext, todo string) (int, string, error) {
+	edited := todo
+	if cli.ExecuteWithStdioRestored != nil && checkIsTerminal() {
+		var err error
+		cli.ExecuteWithStdioRestored(func() {
+			edited, err = editor.OpenCommitEditor(getEditorString(cliCtx), todo)
+		})
+		if err != nil {
+			return 1, "", fmt.Errorf("Failed to open the editor: %v \n Check your `EDITOR` environment variable with `echo $EDITOR` or your dolt config with `dolt config --list` to ensure that your editor is valid", err)
+		}
+	}
+
+	steps, err := rebase.ParseTodo(edited)
+	if err != nil {
+		return 1, "", err
+	}
+	if len(steps) == 0 {
+		_, _, err = callRebase(queryist, sqlCtx, "--"+cli.AbortParam)
+		if err != nil {
+			return 1, "", err
+		}
+		return 1, "Nothing to do", nil
+	}
+
+	_, _, err = callRebase(queryist, sqlCtx, "--"+cli.EditTodoParam, edited)
+	if err != nil {
+		return 1, "", err
+	}
+	return callRebase(queryist, sqlCtx, "--"+cli.ContinueFlag)
+}
+
+// callRebase calls DOLT_REBASE() with the arguments given, and returns its status and message.
+func callRebase(queryist cli.Queryist, sqlCtx *sql.Context, args ...string) (int, string, error) {
+	params := make([]interface{}, len(args))
+	for i, arg := range args {
+		params[i] = arg
+	}
+	query, err := dbr.InterpolateForDialect("CALL DOLT_REBASE("+strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")+")", params, dialect.MySQL)
+	if err != nil {
+		return 1, "", err
+	}
+
+	rows, err := GetRowsForSql(queryist, sqlCtx, query)
+	if err != nil {
+		return 1, "", err
+	}
+	if len(rows) != 1 || len(rows[0]) != 2 {
+		return 1, "", fmt.Errorf("unexpected result from DOLT_REBASE()")
+	}
+
+	status, err := getInt64ColAsInt64(rows[0][0])
+	if err != nil {
+		return 1, "", fmt.Errorf("unable to parse the status of DOLT_REBASE(): %w", err)
+	}
+	var message string
+	if rows[0][1] != nil {
+		message = fmt.Sprint(rows[0][1])
+	}
+	return int(status), message, nil
+}
@@ -20,11 +20,15 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 )
 
@@ -52,12 +56,16 @@ GCP remote urls should be of the form gs://gcs-bucket/database and will use the
 The local filesystem can be used as a remote by providing a repository url in the format file://absolute path. See https://en.wikipedia.org/wiki/File_URI_scheme
 
 {{.EmphasisLeft}}remove{{.EmphasisRight}}, {{.EmphasisLeft}}rm{{.EmphasisRight}}
-Remove the remote named {{.LessThan}}name{{.GreaterThan}}. All remote-tracking branches and configuration settings for the remote are removed.`,
+Remove the remote named {{.LessThan}}name{{.GreaterThan}}. All remote-tracking branches and configuration settings for the remote are removed.
+
+{{.EmphasisLeft}}prune{{.EmphasisRight}}
+Deletes the remote-tracking branches of the remotes named {{.LessThan}}name{{.GreaterThan}} for branches that have been deleted on them, without fetching the branches that haven't. {{.EmphasisLeft}}dolt fetch --prune{{.EmphasisRight}} does the same as part of a fetch.`,
 
 	Synopsis: []string{
 		"[-v | --verbose]",
 		"add [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] {{.LessThan}}name{{.GreaterThan}} {{.LessThan}}url{{.GreaterThan}}",
 		"remove {{.LessThan}}name{{.GreaterThan}}",
+		"prune {{.LessThan}}name{{.GreaterThan}}...",
 	},
 }
 
@@ -65,6 +73,7 @@ const (
 	addRemoteId         = "add"
 	removeRemoteId      = "remove"
 	removeRemoteShortId = "rm"
+	pruneRemoteId       = "prune"
 )
 
 type RemoteCmd struct{}
@@ -120,6 +129,8 @@ func (cmd RemoteCmd) Exec(ctx context.Context, commandStr string, args []string,
 		verr = removeRemote(ctx, dEnv, apr)
 	case apr.Arg(0) == removeRemoteShortId:
 		verr = removeRemote(ctx, dEnv, apr)
+	case apr.Arg(0) == pruneRemoteId:
+		verr = pruneRemotes(ctx, dEnv, apr)
 	default:
 		verr = errhand.BuildDError("").SetPrintUsage().Build()
 	}
@@ -151,6 +162,38 @@ func removeRemote(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPars
 	}
 }
 
+func pruneRemotes(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
+	if apr.NArg() < 2 {
+		return errhand.BuildDError("").SetPrintUsage().Build()
+	}
+
+	remotes, err := dEnv.GetRemotes()
+	if err != nil {
+		return errhand.BuildDError("error: failed to read remotes").AddCause(err).Build()
+	}
+
+	for _, name := range apr.Args[1:] {
+		name = strings.TrimSpace(name)
+		r, ok := remotes[name]
+		if !ok {
+			return errhand.BuildDError("error: unknown remote: '%s' ", name).Build()
+		}
+
+		srcDB, err := r.GetRemoteDB(ctx, dEnv.DoltDB.Format(), dEnv)
+		if err != nil {
+			err = actions.HandleInitRemoteStorageClientErr(r.Name, r.Url, err)
+			return errhand.VerboseErrorFromError(err)
+		}
+
+		err = actions.PruneRemoteBranches(ctx, dEnv.DbData(), srcDB, r)
+		if err != nil {
+			return errhand.BuildDError("error: failed to prune remote '%s'", name).AddCause(err).Build()
+		}
+	}
+
+	return nil
+}
+
 func addRemote(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
 	if apr.NArg() != 3 {
 		return errhand.BuildDError("").SetPrintUsage().Build()
@@ -214,6 +257,21 @@ func printRemotes(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Ver
 		return errhand.BuildDError("Unable to get remotes from the local directory").AddCause(err).Build()
 	}
 
+	if cli.MachineReadableOutput() {
+		var rows []sql.Row
+		for _, r := range sortedRemotes(remotes) {
+			fetchSpecs := make([]interface{}, len(r.FetchSpecs))
+			for i, fs := range r.FetchSpecs {
+				fetchSpecs[i] = fs
+			}
+			rows = append(rows, sql.Row{r.Name, r.Url, types.JSONDocument{Val: fetchSpecs}, jsonParams(r.Params)})
+		}
+		if err := printOutputRows(remoteOutputSchema, rows); err != nil {
+			return errhand.BuildDError("error: failed to print remotes").AddCause(err).Build()
+		}
+		return nil
+	}
+
 	for _, r := range remotes {
 		if apr.Contains(cli.VerboseFlag) {
 			paramStr := make([]byte, 0)
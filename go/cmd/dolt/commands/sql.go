@@ -22,6 +22,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -41,6 +42,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
@@ -53,13 +55,15 @@ var sqlDocs = cli.CommandDocumentationContent{
 
 Multiple SQL statements must be separated by semicolons. Use {{.EmphasisLeft}}-b{{.EmphasisRight}} to enable batch mode to speed up large batches of INSERT / UPDATE statements. Pipe SQL files to dolt sql (no {{.EmphasisLeft}}-q{{.EmphasisRight}}) to execute a SQL import or update script. 
 
+When running a script, {{.EmphasisLeft}}--continue-on-error{{.EmphasisRight}} runs every statement even if some fail, and lists the failed statements at the end. {{.EmphasisLeft}}--single-transaction{{.EmphasisRight}} runs the whole script in one transaction, which is rolled back if any statement fails, and {{.EmphasisLeft}}--timing{{.EmphasisRight}} reports how long each statement took.
+
 By default this command uses the dolt database in the current working directory. If you would prefer to use a different directory, user the {{.EmphasisLeft}}--data-dir <directory>{{.EmphasisRight}} argument before the sql subcommand.
 
 If a server is running for the database in question, then the query will go through the server automatically. If connecting to a remote server is preferred, used the {{.EmphasisLeft}}--host <host>{{.EmphasisRight}} and {{.EmphasisLeft}}--port <port>{{.EmphasisRight}} global arguments. See 'dolt --help' for more information about global arguments.`,
 
 	Synopsis: []string{
 		"",
-		"< script.sql",
+		"[--continue-on-error] [--single-transaction] [--timing] < script.sql",
 		"-q {{.LessThan}}query{{.GreaterThan}} [-r {{.LessThan}}result format{{.GreaterThan}}] [-s {{.LessThan}}name{{.GreaterThan}} -m {{.LessThan}}message{{.GreaterThan}}] [-b]",
 		"-x {{.LessThan}}name{{.GreaterThan}}",
 		"--list-saved",
@@ -85,6 +89,9 @@ const (
 	DefaultPrivsName      = "privileges.db"
 	DefaultBranchCtrlName = "branch_control.db"
 	continueFlag          = "continue"
+	continueOnErrorFlag   = "continue-on-error"
+	singleTransactionFlag = "single-transaction"
+	timingFlag            = "timing"
 	fileInputFlag         = "file"
 	UserFlag              = "user"
 	DefaultUser           = "root"
@@ -94,7 +101,7 @@ const (
 
 	welcomeMsg = `# Welcome to the DoltSQL shell.
 # Statements must be terminated with ';'.
-# "exit" or "quit" (or Ctrl-D) to exit.`
+# "exit" or "quit" (or Ctrl-D) to exit. "\?" for help.`
 )
 
 // TODO: get rid of me, use a real integration point to define system variables
@@ -139,6 +146,9 @@ func (cmd SqlCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsString(messageFlag, "m", "saved query description", "Used with --query and --save, saves the query with the descriptive message given. See also `--name`.")
 	ap.SupportsFlag(BatchFlag, "b", "Use to enable more efficient batch processing for large SQL import scripts. This mode is no longer supported and this flag is a no-op. To speed up your SQL imports, use either LOAD DATA, or structure your SQL import script to insert many rows per statement.")
 	ap.SupportsFlag(continueFlag, "c", "Continue running queries on an error. Used for batch mode only.")
+	ap.SupportsFlag(continueOnErrorFlag, "", "Continue running statements after an error, and list every failed statement at the end. Same as `--continue`. Used for batch mode only.")
+	ap.SupportsFlag(singleTransactionFlag, "", "Run all statements in a single transaction, which is rolled back if any statement fails. Used for batch mode only.")
+	ap.SupportsFlag(timingFlag, "", "Print the time taken by each statement. Used for batch mode only.")
 	ap.SupportsString(fileInputFlag, "f", "input file", "Execute statements from the file given.")
 	return ap
 }
@@ -236,7 +246,7 @@ func (cmd SqlCmd) Exec(ctx context.Context, commandStr string, args []string, dE
 			isTty = fi.Mode()&os.ModeCharDevice != 0
 		}
 
-		_, continueOnError := apr.GetValue(continueFlag)
+		batchOpts := batchModeOptionsFromArgs(apr)
 
 		input := os.Stdin
 		if fileInput, ok := apr.GetValue(fileInputFlag); ok {
@@ -261,7 +271,7 @@ func (cmd SqlCmd) Exec(ctx context.Context, commandStr string, args []string, dE
 				return sqlHandleVErrAndExitCode(queryist, errhand.VerboseErrorFromError(err), usage)
 			}
 		} else {
-			err := execBatchMode(sqlCtx, queryist, input, continueOnError, format)
+			err := execBatchMode(sqlCtx, queryist, input, batchOpts, format)
 			if err != nil {
 				return sqlHandleVErrAndExitCode(queryist, errhand.VerboseErrorFromError(err), usage)
 			}
@@ -383,10 +393,8 @@ func queryMode(
 	usage cli.UsagePrinter,
 ) int {
 
-	_, continueOnError := apr.GetValue(continueFlag)
-
 	input := strings.NewReader(query)
-	err := execBatchMode(ctx, qryist, input, continueOnError, format)
+	err := execBatchMode(ctx, qryist, input, batchModeOptionsFromArgs(apr), format)
 	if err != nil {
 		return sqlHandleVErrAndExitCode(qryist, errhand.VerboseErrorFromError(err), usage)
 	}
@@ -605,8 +613,42 @@ func saveQuery(ctx *sql.Context, root *doltdb.RootValue, query string, name stri
 	return newRoot, nil
 }
 
+// batchModeOptions control how execBatchMode handles failed statements and reports on the statements it runs.
+type batchModeOptions struct {
+	// continueOnErr continues running statements after one fails, and reports all failed statements at the end.
+	continueOnErr bool
+	// singleTransaction runs all statements in a single transaction, which is rolled back if any statement fails.
+	singleTransaction bool
+	// timing reports the time taken by each statement.
+	timing bool
+}
+
+func batchModeOptionsFromArgs(apr *argparser.ArgParseResults) batchModeOptions {
+	return batchModeOptions{
+		continueOnErr:     apr.Contains(continueFlag) || apr.Contains(continueOnErrorFlag),
+		singleTransaction: apr.Contains(singleTransactionFlag),
+		timing:            apr.Contains(timingFlag),
+	}
+}
+
+// failedBatchStatement is a statement that failed in batch mode.
+type failedBatchStatement struct {
+	line int
+	err  error
+}
+
 // execBatchMode runs all the queries in the input reader
-func execBatchMode(ctx *sql.Context, qryist cli.Queryist, input io.Reader, continueOnErr bool, format engine.PrintResultFormat) error {
+func execBatchMode(ctx *sql.Context, qryist cli.Queryist, input io.Reader, opts batchModeOptions, format engine.PrintResultFormat) error {
+	if opts.singleTransaction {
+		if err := execBatchControlStatement(ctx, qryist, "START TRANSACTION"); err != nil {
+			return err
+		}
+	}
+
+	batchStart := time.Now()
+	statements := 0
+	var failed []failedBatchStatement
+
 	scanner := NewSqlStatementScanner(input)
 	var query string
 	for scanner.Scan() {
@@ -619,50 +661,25 @@ func execBatchMode(ctx *sql.Context, qryist cli.Queryist, input io.Reader, conti
 			continue
 		}
 
-		sqlMode := sql.LoadSqlMode(ctx)
-
-		sqlStatement, err := sqlparser.ParseWithOptions(query, sqlMode.ParserOptions())
-		if err == sqlparser.ErrEmpty {
+		start := time.Now()
+		ran, err := execBatchStatement(ctx, qryist, query, format)
+		if !ran {
 			continue
-		} else if err != nil {
-			err = buildBatchSqlErr(scanner.statementStartLine, query, err)
-			if !continueOnErr {
-				return err
-			} else {
-				cli.PrintErrln(err.Error())
-			}
 		}
-
-		// store start time for query
-		ctx.SetQueryTime(time.Now())
-		sqlSch, rowIter, err := processParsedQuery(ctx, query, qryist, sqlStatement)
+		statements++
+		if opts.timing {
+			cli.PrintErrf("Statement on line %d took %s\n", scanner.statementStartLine, time.Since(start).Round(time.Millisecond))
+		}
 		if err != nil {
 			err = buildBatchSqlErr(scanner.statementStartLine, query, err)
-			if !continueOnErr {
-				return err
-			} else {
-				cli.PrintErrln(err.Error())
-			}
-		}
-
-		if rowIter != nil {
-			switch sqlStatement.(type) {
-			case *sqlparser.Select, *sqlparser.Insert, *sqlparser.Update, *sqlparser.Delete,
-				*sqlparser.OtherRead, *sqlparser.Show, *sqlparser.Explain, *sqlparser.SetOp:
-				// For any statement that prints out result, print a newline to put the regular output on its own line
-				if fileReadProg != nil {
-					fileReadProg.printNewLineIfNeeded()
-				}
-			}
-			err = engine.PrettyPrintResults(ctx, format, sqlSch, rowIter)
-			if err != nil {
-				err = buildBatchSqlErr(scanner.statementStartLine, query, err)
-				if !continueOnErr {
-					return err
-				} else {
-					cli.PrintErrln(err.Error())
+			if !opts.continueOnErr {
+				if opts.singleTransaction {
+					_ = execBatchControlStatement(ctx, qryist, "ROLLBACK")
 				}
+				return err
 			}
+			cli.PrintErrln(err.Error())
+			failed = append(failed, failedBatchStatement{line: scanner.statementStartLine, err: err})
 		}
 		query = ""
 	}
@@ -671,9 +688,82 @@ func execBatchMode(ctx *sql.Context, qryist cli.Queryist, input io.Reader, conti
 		return buildBatchSqlErr(scanner.statementStartLine, query, err)
 	}
 
+	if opts.singleTransaction {
+		end := "COMMIT"
+		if len(failed) > 0 {
+			end = "ROLLBACK"
+		}
+		if err := execBatchControlStatement(ctx, qryist, end); err != nil {
+			return err
+		}
+	}
+	if opts.timing {
+		cli.PrintErrf("Ran %d statements in %s\n", statements, time.Since(batchStart).Round(time.Millisecond))
+	}
+
+	if len(failed) > 0 {
+		cli.PrintErrf("%d of %d statements failed:\n", len(failed), statements)
+		for _, f := range failed {
+			msg := f.err.Error()
+			if i := strings.IndexRune(msg, '\n'); i >= 0 {
+				msg = msg[:i]
+			}
+			cli.PrintErrf("  line %d: %s\n", f.line, msg)
+		}
+		if opts.singleTransaction {
+			cli.PrintErrln("The transaction was rolled back.")
+		}
+		return fmt.Errorf("%d of %d statements failed", len(failed), statements)
+	}
+
 	return nil
 }
 
+// execBatchStatement runs a single statement in batch mode and prints its results. Returns false if |query| is
+// empty and there was no statement to run.
+func execBatchStatement(ctx *sql.Context, qryist cli.Queryist, query string, format engine.PrintResultFormat) (bool, error) {
+	sqlMode := sql.LoadSqlMode(ctx)
+
+	sqlStatement, err := sqlparser.ParseWithOptions(query, sqlMode.ParserOptions())
+	if err == sqlparser.ErrEmpty {
+		return false, nil
+	} else if err != nil {
+		return true, err
+	}
+
+	// store start time for query
+	ctx.SetQueryTime(time.Now())
+	sqlSch, rowIter, err := processParsedQuery(ctx, query, qryist, sqlStatement)
+	if err != nil {
+		return true, err
+	}
+
+	if rowIter != nil {
+		switch sqlStatement.(type) {
+		case *sqlparser.Select, *sqlparser.Insert, *sqlparser.Update, *sqlparser.Delete,
+			*sqlparser.OtherRead, *sqlparser.Show, *sqlparser.Explain, *sqlparser.SetOp:
+			// For any statement that prints out result, print a newline to put the regular output on its own line
+			if fileReadProg != nil {
+				fileReadProg.printNewLineIfNeeded()
+			}
+		}
+		return true, engine.PrettyPrintResults(ctx, format, sqlSch, rowIter)
+	}
+	return true, nil
+}
+
+// execBatchControlStatement runs a statement that begins or ends the transaction of a batch, discarding its results.
+func execBatchControlStatement(ctx *sql.Context, qryist cli.Queryist, query string) error {
+	_, rowIter, err := processQuery(ctx, query, qryist)
+	if err != nil {
+		return err
+	}
+	if rowIter != nil {
+		_, err = sql.RowIterToRows(ctx, nil, rowIter)
+	}
+	return err
+}
+
 func buildBatchSqlErr(stmtStartLine int, query string, err error) error {
 	return formatQueryError(fmt.Sprintf("error on line %d for query %s", stmtStartLine, query), err)
 }
@@ -705,16 +795,16 @@ func execShell(sqlCtx *sql.Context, qryist cli.Queryist, format engine.PrintResu
 			"quit", "exit", "quit()", "exit()",
 		},
 		LineTerminator: ";",
-		MysqlShellCmds: verticalOutputLineTerminators,
+		MysqlShellCmds: append(MetaCommandTerminators(), verticalOutputLineTerminators...),
 	}
 
 	shell := ishell.NewUninterpreted(&shellConf)
 	shell.SetMultiPrompt(initialMultilinePrompt)
-	// TODO: update completer on create / drop / alter statements
-	completer, err := newCompleter(sqlCtx, qryist)
+	completer, err := NewSqlCompleter(sqlCtx, qryist)
 	if err != nil {
 		return err
 	}
+	state := &ShellState{}
 
 	shell.CustomCompleter(completer)
 
@@ -763,6 +853,16 @@ func execShell(sqlCtx *sql.Context, qryist cli.Queryist, format engine.PrintResu
 			query = strings.TrimSuffix(query, terminator)
 		}
 
+		metaQuery, isMeta, err := RunMetaCommand(query, state)
+		if err != nil {
+			shell.Println(color.RedString(err.Error()))
+			return
+		} else if isMeta && metaQuery == "" {
+			return
+		} else if isMeta {
+			query = metaQuery
+		}
+
 		var nextPrompt string
 		var sqlSch sql.Schema
 		var rowIter sql.RowIter
@@ -777,18 +877,26 @@ func execShell(sqlCtx *sql.Context, qryist cli.Queryist, format engine.PrintResu
 				verr := formatQueryError("", err)
 				shell.Println(verr.Verbose())
 			} else if rowIter != nil {
-				switch closureFormat {
-				case engine.FormatTabular, engine.FormatVertical:
-					err = engine.PrettyPrintResultsExtended(sqlCtx, closureFormat, sqlSch, rowIter)
-				default:
-					err = engine.PrettyPrintResults(sqlCtx, closureFormat, sqlSch, rowIter)
-				}
+				err = state.PrintResults(func() error {
+					switch closureFormat {
+					case engine.FormatTabular, engine.FormatVertical:
+						return engine.PrettyPrintResultsExtended(sqlCtx, closureFormat, sqlSch, rowIter)
+					default:
+						return engine.PrettyPrintResults(sqlCtx, closureFormat, sqlSch, rowIter)
+					}
+				})
 
 				if err != nil {
 					shell.Println(color.RedString(err.Error()))
 				}
 			}
 
+			if changesCompletions(query) {
+				if err := completer.Refresh(sqlCtx); err != nil {
+					shell.Println(color.RedString(err.Error()))
+				}
+			}
+
 			db, ok := getDBFromSession(sqlCtx, qryist)
 			if ok {
 				sqlCtx.SetCurrentDatabase(db)
@@ -838,20 +946,52 @@ func getDBFromSession(sqlCtx *sql.Context, qryist cli.Queryist) (db string, ok b
 	return db, true
 }
 
-// Returns a new auto completer with table names, column names, and SQL keywords.
-// TODO: update the completer on DDL, branch change, etc.
-func newCompleter(
-	ctx *sql.Context,
-	qryist cli.Queryist,
-) (completer *sqlCompleter, rerr error) {
+// NewSqlCompleter returns a new auto completer with table names, column names, branch names, dolt procedure names and
+// SQL keywords.
+func NewSqlCompleter(ctx *sql.Context, qryist cli.Queryist) (*SqlCompleter, error) {
+	c := &SqlCompleter{qryist: qryist, mu: &sync.Mutex{}}
+	if err := c.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SqlCompleter completes the words of SQL statements in the shell. Completions depend on the word before the one
+// being completed: tables follow FROM, JOIN and the like, dolt procedures follow CALL, columns follow a table name and
+// a '.', branches follow a quote, and meta commands start with a backslash.
+type SqlCompleter struct {
+	qryist cli.Queryist
+
+	mu           *sync.Mutex
+	allWords     []string
+	tableNames   []string
+	columnNames  []string
+	tableColumns map[string][]string
+	branchNames  []string
+}
+
+// tableKeywords are the words that are followed by a table name.
+var tableKeywords = map[string]struct{}{
+	"from":     {},
+	"join":     {},
+	"into":     {},
+	"update":   {},
+	"table":    {},
+	"describe": {},
+	"desc":     {},
+	`\d`:       {},
+}
+
+// Refresh reloads the table, column and branch names the completer suggests.
+func (c *SqlCompleter) Refresh(ctx *sql.Context) (rerr error) {
 	subCtx, stop := signal.NotifyContext(ctx.Context, os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
 	sqlCtx := sql.NewContext(subCtx, sql.WithSession(ctx.Session))
 
-	_, iter, err := qryist.Query(sqlCtx, "select table_schema, table_name, column_name from information_schema.columns;")
+	_, iter, err := c.qryist.Query(sqlCtx, "select table_schema, table_name, column_name from information_schema.columns;")
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	defer func(iter sql.RowIter, context *sql.Context) {
@@ -862,66 +1002,119 @@ func newCompleter(
 	}(iter, sqlCtx)
 
 	identifiers := make(map[string]struct{})
+	tables := make(map[string]struct{})
+	tableColumns := make(map[string][]string)
 	var columnNames []string
 	for {
 		r, err := iter.Next(sqlCtx)
 		if err == io.EOF {
 			break
 		} else if err != nil {
-			return nil, err
+			return err
 		}
 
-		identifiers[r[0].(string)] = struct{}{}
-		identifiers[r[1].(string)] = struct{}{}
-		identifiers[r[2].(string)] = struct{}{}
-		columnNames = append(columnNames, r[2].(string))
+		schemaName, tableName, columnName := fmt.Sprint(r[0]), fmt.Sprint(r[1]), fmt.Sprint(r[2])
+		identifiers[schemaName] = struct{}{}
+		identifiers[tableName] = struct{}{}
+		identifiers[columnName] = struct{}{}
+		tables[tableName] = struct{}{}
+		lowerTable := strings.ToLower(tableName)
+		tableColumns[lowerTable] = append(tableColumns[lowerTable], columnName)
+		columnNames = append(columnNames, columnName)
 	}
 
 	var completionWords []string
 	for k := range identifiers {
 		completionWords = append(completionWords, k)
 	}
+	var tableNames []string
+	for k := range tables {
+		tableNames = append(tableNames, k)
+	}
 
-	completionWords = append(completionWords, dsqle.CommonKeywords...)
+	procedureNames := make([]string, len(dprocedures.DoltProcedures))
+	for i, p := range dprocedures.DoltProcedures {
+		procedureNames[i] = p.Name
+	}
 
-	return &sqlCompleter{
-		allWords:    completionWords,
-		columnNames: columnNames,
-	}, nil
+	completionWords = append(completionWords, dsqle.CommonKeywords...)
+	completionWords = append(completionWords, procedureNames...)
+
+	// Branches are only known for dolt databases, so other servers and databases just don't complete them
+	branchNames, _ := c.queryNames(sqlCtx, "select name from dolt_branches")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.allWords = completionWords
+	c.tableNames = tableNames
+	c.columnNames = columnNames
+	c.tableColumns = tableColumns
+	c.branchNames = branchNames
+	return nil
 }
 
-type sqlCompleter struct {
-	allWords    []string
-	columnNames []string
+// queryNames returns the first column of the results of |query|.
+func (c *SqlCompleter) queryNames(ctx *sql.Context, query string) (names []string, rerr error) {
+	_, iter, err := c.qryist.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err := iter.Close(ctx)
+		if err != nil && rerr == nil {
+			rerr = err
+		}
+	}()
+
+	for {
+		r, err := iter.Next(ctx)
+		if err == io.EOF {
+			return names, nil
+		} else if err != nil {
+			return nil, err
+		}
+		names = append(names, fmt.Sprint(r[0]))
+	}
 }
 
 // Do function for autocompletion, defined by the Readline library. Mostly stolen from ishell.
-func (c *sqlCompleter) Do(line []rune, pos int) (newLine [][]rune, length int) {
+func (c *SqlCompleter) Do(line []rune, pos int) (newLine [][]rune, length int) {
 	var words []string
-	if w, err := shlex.Split(string(line)); err == nil {
+	if w, err := shlex.Split(string(line[:pos])); err == nil {
 		words = w
 	} else {
 		// fall back
-		words = strings.Fields(string(line))
+		words = strings.Fields(string(line[:pos]))
 	}
 
-	var cWords []string
 	prefix := ""
 	lastWord := ""
+	prevWord := ""
 	if len(words) > 0 && pos > 0 && line[pos-1] != ' ' {
 		lastWord = words[len(words)-1]
+		if len(words) > 1 {
+			prevWord = words[len(words)-2]
+		}
+		// A word that follows punctuation, like a procedure argument, is completed on its own
+		if i := strings.LastIndexAny(lastWord, "(,="); i >= 0 {
+			prevWord, lastWord = lastWord[:i], lastWord[i+1:]
+		}
 		prefix = strings.ToLower(lastWord)
 	} else if len(words) > 0 {
-		lastWord = words[len(words)-1]
+		prevWord = words[len(words)-1]
 	}
 
-	cWords = c.getWords(lastWord)
+	cWords, keepCase := c.getWords(lastWord, prevWord)
 
 	var suggestions [][]rune
 	for _, w := range cWords {
 		lowered := strings.ToLower(w)
 		if strings.HasPrefix(lowered, prefix) {
-			suggestions = append(suggestions, []rune(strings.TrimPrefix(lowered, prefix)))
+			if keepCase {
+				suggestions = append(suggestions, []rune(w[len(prefix):]))
+			} else {
+				suggestions = append(suggestions, []rune(strings.TrimPrefix(lowered, prefix)))
+			}
 		}
 	}
 	if len(suggestions) == 1 && prefix != "" && string(suggestions[0]) == "" {
@@ -931,16 +1124,43 @@ func (c *sqlCompleter) Do(line []rune, pos int) (newLine [][]rune, length int) {
 	return suggestions, len(prefix)
 }
 
-// Simple suggestion function. Returns column name suggestions if the last word in the input has exactly one '.' in it,
-// otherwise returns all tables, columns, and reserved words.
-func (c *sqlCompleter) getWords(lastWord string) (s []string) {
+// getWords returns the suggestions for |lastWord|, which follows |prevWord|, and whether they should keep their case.
+// Column names are suggested if the last word in the input has exactly one '.' in it, and are limited to the columns
+// of the table before the '.' when it names one.
+func (c *SqlCompleter) getWords(lastWord, prevWord string) (s []string, keepCase bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if strings.HasPrefix(lastWord, `\`) {
+		return MetaCommandTerminators(), false
+	}
+
+	if strings.HasPrefix(lastWord, "'") || strings.HasPrefix(lastWord, `"`) || strings.HasPrefix(lastWord, "`") {
+		return prepend(lastWord[:1], c.branchNames), true
+	}
+
 	lastDot := strings.LastIndex(lastWord, ".")
 	if lastDot > 0 && strings.Count(lastWord, ".") == 1 {
 		alias := lastWord[:lastDot]
-		return prepend(alias+".", c.columnNames)
+		if columns, ok := c.tableColumns[strings.ToLower(alias)]; ok {
+			return prepend(alias+".", columns), false
+		}
+		return prepend(alias+".", c.columnNames), false
+	}
+
+	prev := strings.ToLower(prevWord)
+	if prev == "call" {
+		names := make([]string, len(dprocedures.DoltProcedures))
+		for i, p := range dprocedures.DoltProcedures {
+			names[i] = p.Name
+		}
+		return names, false
+	}
+	if _, ok := tableKeywords[prev]; ok {
+		return c.tableNames, false
 	}
 
-	return c.allWords
+	return c.allWords, false
 }
 
 func prepend(s string, ss []string) []string {
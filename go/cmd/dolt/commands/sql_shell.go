@@ -0,0 +1,182 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
+	"github.com/dolthub/dolt/go/store/util/outputpager"
+)
+
+// ShellState holds the settings of an interactive SQL shell that can be changed with meta commands.
+type ShellState struct {
+	// Pager is whether results are shown through a pager
+	Pager bool
+}
+
+// shellMetaCommand is a backslash command understood by the interactive SQL shells, like \d or \branches.
+type shellMetaCommand struct {
+	name    string
+	args    string
+	desc    string
+	maxArgs int
+	// run returns the query that shows what the command asked for, or the empty string for commands that only change
+	// the shell's state.
+	run func(state *ShellState, args []string) (string, error)
+}
+
+var shellMetaCommands []shellMetaCommand
+
+func init() {
+	shellMetaCommands = []shellMetaCommand{
+		{
+			name: `\?`,
+			desc: "Show this list of meta commands.",
+			run: func(state *ShellState, args []string) (string, error) {
+				cli.Println(metaCommandHelp())
+				return "", nil
+			},
+		},
+		{
+			name:    `\d`,
+			args:    "[table]",
+			desc:    "Describe a table, or list tables if none is given.",
+			maxArgs: 1,
+			run: func(state *ShellState, args []string) (string, error) {
+				if len(args) == 0 {
+					return "SHOW TABLES", nil
+				}
+				return "DESCRIBE " + sqlfmt.QuoteIdentifier(args[0]), nil
+			},
+		},
+		{
+			name: `\dt`,
+			desc: "List tables.",
+			run: func(state *ShellState, args []string) (string, error) {
+				return "SHOW FULL TABLES", nil
+			},
+		},
+		{
+			name: `\branches`,
+			desc: "List branches.",
+			run: func(state *ShellState, args []string) (string, error) {
+				return "SELECT name, hash, latest_committer, latest_commit_date, latest_commit_message FROM dolt_branches", nil
+			},
+		},
+		{
+			name: `\pager`,
+			desc: "Show results through a pager, which scrolls wide results instead of wrapping them.",
+			run: func(state *ShellState, args []string) (string, error) {
+				state.Pager = true
+				cli.Println("Pager enabled")
+				return "", nil
+			},
+		},
+		{
+			name: `\nopager`,
+			desc: "Print results directly.",
+			run: func(state *ShellState, args []string) (string, error) {
+				state.Pager = false
+				cli.Println("Pager disabled")
+				return "", nil
+			},
+		},
+	}
+}
+
+// MetaCommandTerminators returns the meta commands that can end a statement in the shell the way \G does, so that they
+// run without a trailing ';'. Meta commands with arguments must be terminated with ';'.
+func MetaCommandTerminators() []string {
+	var terminators []string
+	for _, c := range shellMetaCommands {
+		terminators = append(terminators, c.name)
+	}
+	return terminators
+}
+
+// RunMetaCommand runs |line| if it's a meta command, returning whether it was one. Meta commands that show data return
+// the query to run for them.
+func RunMetaCommand(line string, state *ShellState) (query string, isMeta bool, err error) {
+	fields := strings.Fields(strings.TrimSpace(line))
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], `\`) {
+		return "", false, nil
+	}
+
+	for _, c := range shellMetaCommands {
+		if c.name != fields[0] {
+			continue
+		}
+		args := fields[1:]
+		if len(args) > c.maxArgs {
+			return "", true, fmt.Errorf("too many arguments for %s, usage: %s %s", c.name, c.name, c.args)
+		}
+		query, err = c.run(state, args)
+		return query, true, err
+	}
+
+	return "", true, fmt.Errorf(`unknown meta command %s, use \? to list meta commands`, fields[0])
+}
+
+func metaCommandHelp() string {
+	var sb strings.Builder
+	for i, c := range shellMetaCommands {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("%-20s %s", strings.TrimSpace(c.name+" "+c.args), c.desc))
+	}
+	return sb.String()
+}
+
+// PrintResults calls |print|, which writes to cli.CliOut, sending the output through a pager if one is enabled.
+func (s *ShellState) PrintResults(print func() error) error {
+	if !s.Pager || cli.ExecuteWithStdioRestored == nil {
+		return print()
+	}
+
+	var err error
+	cli.ExecuteWithStdioRestored(func() {
+		pager := outputpager.Start()
+		defer pager.Stop()
+
+		out := cli.CliOut
+		cli.CliOut = pager.Writer
+		defer func() {
+			cli.CliOut = out
+		}()
+
+		err = print()
+	})
+	return err
+}
+
+// changesCompletions returns whether |query| can change the tables, columns or branches the shell completes.
+func changesCompletions(query string) bool {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return false
+	}
+	switch stmt.(type) {
+	case *sqlparser.DDL, *sqlparser.DBDDL, *sqlparser.AlterTable, *sqlparser.Use, *sqlparser.Call:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+)
+
+// databaseWarmer opens the databases that a server loaded lazily in the background, one at a time, so that the first
+// session to use each doesn't have to wait for it to be opened.
+type databaseWarmer struct {
+	sqlEngine *engine.SqlEngine
+	lgr       *logrus.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newDatabaseWarmer(sqlEngine *engine.SqlEngine, lgr *logrus.Logger) *databaseWarmer {
+	return &databaseWarmer{
+		sqlEngine: sqlEngine,
+		lgr:       lgr,
+	}
+}
+
+// Start opens the databases that haven't been opened yet in the background.
+func (w *databaseWarmer) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		sqlCtx, err := w.sqlEngine.NewLocalContext(ctx)
+		if err != nil {
+			w.lgr.Warnf("error loading databases in the background: %v", err)
+			return
+		}
+		start := time.Now()
+		err = w.sqlEngine.LoadLazyDatabases(sqlCtx)
+		if err != nil {
+			return
+		}
+		w.lgr.Infof("loaded databases in the background in %v", time.Since(start))
+	}()
+}
+
+// Stop stops opening databases, and waits for the database being opened to finish.
+func (w *databaseWarmer) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
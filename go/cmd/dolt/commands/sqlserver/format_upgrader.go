@@ -0,0 +1,181 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/migrate"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/formatupgrade"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// maxFormatUpgradeAttempts is the number of times a database is migrated before its upgrade fails, when it's written
+// to during every migration.
+const maxFormatUpgradeAttempts = 3
+
+var errDatabaseChangedDuringUpgrade = errors.New("database was written to while it was migrated")
+
+// formatUpgrader migrates the databases that a server loads in the old storage format to the current one in the
+// background. Databases are migrated one at a time while they go on serving queries. When a database's migration
+// finishes, the migrated database is swapped in for it, unless it was written to in the meantime, in which case it's
+// migrated again. The progress of each upgrade is recorded in |tracker|, which is shown by the dolt_format_upgrades
+// system table.
+type formatUpgrader struct {
+	sqlEngine *engine.SqlEngine
+	mrEnv     *env.MultiRepoEnv
+	tracker   *formatupgrade.Tracker
+	lgr       *logrus.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newFormatUpgrader(sqlEngine *engine.SqlEngine, mrEnv *env.MultiRepoEnv, tracker *formatupgrade.Tracker, lgr *logrus.Logger) *formatUpgrader {
+	return &formatUpgrader{
+		sqlEngine: sqlEngine,
+		mrEnv:     mrEnv,
+		tracker:   tracker,
+		lgr:       lgr,
+	}
+}
+
+// Start records an upgrade of every database in the old storage format and runs them in the background. It must be
+// called after the running server is set, since every session's state for a database is reset when it's swapped.
+func (u *formatUpgrader) Start() {
+	var names []string
+	_ = u.mrEnv.Iter(func(name string, dEnv *env.DoltEnv) (stop bool, err error) {
+		if dEnv.DoltDB != nil && !types.IsFormat_DOLT(dEnv.DoltDB.Format()) {
+			u.tracker.Add(name, dEnv.DoltDB.Format().VersionString(), types.Format_DOLT.VersionString())
+			names = append(names, name)
+		}
+		return false, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	u.cancel = cancel
+	u.wg.Add(1)
+	go func() {
+		defer u.wg.Done()
+		for _, name := range names {
+			if ctx.Err() != nil {
+				return
+			}
+			err := u.upgrade(ctx, name, u.mrEnv.GetEnv(name))
+			if err != nil && ctx.Err() != nil {
+				// the server is stopping, so the upgrade is left pending for the next time it starts
+				u.tracker.Update(name, func(up *formatupgrade.Upgrade) {
+					up.Status = formatupgrade.Pending
+				})
+				return
+			} else if err != nil {
+				u.lgr.Warnf("error upgrading the storage format of database %s: %v", name, err)
+				u.tracker.Update(name, func(up *formatupgrade.Upgrade) {
+					up.Status = formatupgrade.Failed
+					up.Error = err.Error()
+				})
+				continue
+			}
+			u.lgr.Infof("upgraded the storage format of database %s", name)
+			u.tracker.Update(name, func(up *formatupgrade.Upgrade) {
+				up.Status = formatupgrade.Done
+			})
+		}
+	}()
+}
+
+// Stop cancels the upgrade in progress, leaving its database in the old format and its upgrade pending, and waits for
+// it to finish.
+func (u *formatUpgrader) Stop() {
+	if u.cancel != nil {
+		u.cancel()
+	}
+	u.wg.Wait()
+}
+
+func (u *formatUpgrader) upgrade(ctx context.Context, name string, dEnv *env.DoltEnv) error {
+	// a database that pushes to a remote can't be upgraded by itself, since its remote can't take the new format
+	if _, remote, _ := sql.SystemVariables.GetGlobal(dsess.ReplicateToRemote); remote != "" {
+		return errors.New("databases that replicate to a remote must be migrated along with their remote")
+	}
+
+	sqlCtx, err := u.sqlEngine.NewLocalContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		u.tracker.Update(name, func(up *formatupgrade.Upgrade) {
+			up.Status = formatupgrade.Migrating
+			up.Attempts = attempt
+		})
+
+		err = u.migrate(sqlCtx, name, dEnv)
+		if !errors.Is(err, errDatabaseChangedDuringUpgrade) || attempt == maxFormatUpgradeAttempts {
+			return err
+		}
+	}
+}
+
+// migrate migrates the database named into a temporary directory, and swaps it in for the database if the database
+// hasn't changed since the migration began.
+func (u *formatUpgrader) migrate(ctx *sql.Context, name string, dEnv *env.DoltEnv) error {
+	before, err := dEnv.DoltDB.NomsRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	menv, err := migrate.NewEnvironment(ctx, dEnv)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if dir, err := menv.Migration.FS.Abs("."); err == nil {
+			_ = filesys.LocalFS.Delete(dir, true)
+		}
+	}()
+
+	err = migrate.MigrateHistory(ctx, menv, dEnv.DoltDB, menv.Migration.DoltDB)
+	if err != nil {
+		_ = menv.Migration.DoltDB.Close()
+		return err
+	}
+	err = menv.Migration.DoltDB.Close()
+	if err != nil {
+		return err
+	}
+
+	return u.sqlEngine.SwapDatabase(ctx, name, dEnv, func() error {
+		return migrate.SwapChunkStoresIf(ctx, menv, func() error {
+			after, err := dEnv.DoltDB.NomsRoot(ctx)
+			if err != nil {
+				return err
+			}
+			if after != before {
+				return errDatabaseChangedDuringUpgrade
+			}
+			return nil
+		})
+	})
+}
@@ -0,0 +1,337 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/sirupsen/logrus"
+)
+
+// sqlListener is a source of SQL connections with its own TLS and PROXY protocol settings.
+type sqlListener struct {
+	net.Listener
+	tlsConfig     *tls.Config
+	requireSecure bool
+	proxyProtocol bool
+}
+
+// protocolListenerFunc returns a server.ProtocolListenerFunc that serves the main listener created by the server along
+// with the additional listeners in |serverConfig|, or nil if the main listener is all that's needed. All listeners
+// are served by a single vitess listener so that connection ids are unique across them.
+func protocolListenerFunc(serverConfig ServerConfig, serverConf server.Config) (server.ProtocolListenerFunc, error) {
+	additional := serverConfig.AdditionalListeners()
+	if len(additional) == 0 && !serverConfig.ProxyProtocol() {
+		return nil, nil
+	}
+
+	// TLS configs are loaded up front so that bad certificates are reported before anything starts listening
+	tlsConfigs := make([]*tls.Config, len(additional))
+	for i, l := range additional {
+		if l.TLSKey == "" && l.TLSCert == "" {
+			continue
+		}
+		c, err := tls.LoadX509KeyPair(l.TLSCert, l.TLSKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfigs[i] = &tls.Config{Certificates: []tls.Certificate{c}}
+	}
+
+	return func(cfg mysql.ListenerConfig) (server.ProtocolListener, error) {
+		listeners := []*sqlListener{{
+			Listener:      cfg.Listener,
+			tlsConfig:     serverConf.TLSConfig,
+			requireSecure: serverConf.RequireSecureTransport,
+			proxyProtocol: serverConfig.ProxyProtocol(),
+		}}
+
+		for i, l := range additional {
+			opened, err := listen(l)
+			if err != nil {
+				for _, sl := range listeners[1:] {
+					sl.Close()
+				}
+				return nil, err
+			}
+			for _, nl := range opened {
+				listeners = append(listeners, &sqlListener{
+					Listener:      nl,
+					tlsConfig:     tlsConfigs[i],
+					requireSecure: l.RequireSecureTransport,
+					proxyProtocol: l.ProxyProtocol,
+				})
+			}
+		}
+
+		ml := newMultiListener(listeners)
+		cfg.Listener = ml
+		cfg.AuthServer = secureTransportAuthServer{AuthServer: cfg.AuthServer}
+
+		vl, err := mysql.NewListenerWithConfig(cfg)
+		if err != nil {
+			ml.Close()
+			return nil, err
+		}
+		if serverConf.Version != "" {
+			vl.ServerVersion = serverConf.Version
+		}
+		if ml.hasTLS() {
+			vl.TLSConfig = &tls.Config{GetConfigForClient: tlsConfigForClient}
+		}
+		// secure transport is required per listener, by |secureTransportAuthServer|
+		vl.RequireSecureTransport = false
+
+		return multiProtocolListener{vl}, nil
+	}, nil
+}
+
+// listen opens the network listeners for |l|.
+func listen(l ListenerConfig) ([]net.Listener, error) {
+	var listeners []net.Listener
+	if l.Port != 0 {
+		tcpl, err := net.Listen("tcp", net.JoinHostPort(l.Host, strconv.Itoa(l.Port)))
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, tcpl)
+	}
+	if l.Socket != "" {
+		if runtime.GOOS == "windows" {
+			return nil, fmt.Errorf("unable to create unix socket listener on Windows")
+		}
+		unixl, err := net.ListenUnix("unix", &net.UnixAddr{Name: l.Socket, Net: "unix"})
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, unixl)
+	}
+	return listeners, nil
+}
+
+// multiProtocolListener wraps the vitess listener serving a multiListener. It isn't a *mysql.Listener, so the server
+// leaves the TLS settings made for each listener alone.
+type multiProtocolListener struct {
+	*mysql.Listener
+}
+
+var _ server.ProtocolListener = multiProtocolListener{}
+
+type acceptResult struct {
+	conn net.Conn
+	err  error
+}
+
+// multiListener is a net.Listener that accepts connections from several sqlListeners.
+type multiListener struct {
+	listeners []*sqlListener
+	conns     chan acceptResult
+	shutdown  chan struct{}
+	once      *sync.Once
+	wg        *sync.WaitGroup
+}
+
+var _ net.Listener = (*multiListener)(nil)
+
+func newMultiListener(listeners []*sqlListener) *multiListener {
+	ml := &multiListener{
+		listeners: listeners,
+		conns:     make(chan acceptResult),
+		shutdown:  make(chan struct{}),
+		once:      &sync.Once{},
+		wg:        &sync.WaitGroup{},
+	}
+
+	for _, l := range listeners {
+		l := l
+		ml.wg.Add(1)
+		go func() {
+			defer ml.wg.Done()
+			ml.acceptLoop(l)
+		}()
+	}
+
+	return ml
+}
+
+func (ml *multiListener) acceptLoop(l *sqlListener) {
+	for {
+		conn, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		} else if err != nil {
+			// the vitess listener stops accepting connections on any error, so only closing the listener ends the loop
+			logrus.Warnf("error accepting connection on %s: %v", l.Addr(), err)
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+
+		select {
+		case <-ml.shutdown:
+			conn.Close()
+			return
+		case ml.conns <- acceptResult{conn: newListenerConn(conn, l)}:
+		}
+	}
+}
+
+// Accept implements net.Listener.
+func (ml *multiListener) Accept() (net.Conn, error) {
+	select {
+	case <-ml.shutdown:
+		return nil, net.ErrClosed
+	case res := <-ml.conns:
+		return res.conn, res.err
+	}
+}
+
+// Close implements net.Listener.
+func (ml *multiListener) Close() error {
+	var err error
+	for _, l := range ml.listeners {
+		if cerr := l.Close(); cerr != nil && !errors.Is(cerr, net.ErrClosed) && err == nil {
+			err = cerr
+		}
+	}
+	ml.once.Do(func() {
+		close(ml.shutdown)
+	})
+	ml.wg.Wait()
+	return err
+}
+
+// Addr implements net.Listener. It returns the address of the main listener.
+func (ml *multiListener) Addr() net.Addr {
+	return ml.listeners[0].Addr()
+}
+
+func (ml *multiListener) hasTLS() bool {
+	for _, l := range ml.listeners {
+		if l.tlsConfig != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// tlsConfigForClient returns the TLS config of the listener that accepted the connection |hello| is from.
+func tlsConfigForClient(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+	addr, ok := hello.Conn.RemoteAddr().(*listenerAddr)
+	if !ok || addr.listener.tlsConfig == nil {
+		return nil, errors.New("TLS is not configured for this listener")
+	}
+	addr.secure.Store(true)
+	return addr.listener.tlsConfig, nil
+}
+
+// listenerAddr is the remote address of a connection accepted by a multiListener. It's the address of the client,
+// which for connections behind a proxy comes from the PROXY protocol header.
+type listenerAddr struct {
+	net.Addr
+	listener *sqlListener
+	secure   atomic.Bool
+}
+
+// listenerConn is a connection accepted by a multiListener.
+type listenerConn struct {
+	net.Conn
+	addr *listenerAddr
+	// proxy reads the PROXY protocol header, if the listener expects one, before the rest of the connection is read
+	proxy *proxyProtocolReader
+}
+
+func newListenerConn(conn net.Conn, l *sqlListener) *listenerConn {
+	c := &listenerConn{
+		Conn: conn,
+		addr: &listenerAddr{Addr: conn.RemoteAddr(), listener: l},
+	}
+	if l.proxyProtocol {
+		c.proxy = newProxyProtocolReader(conn)
+	}
+	return c
+}
+
+// Read implements net.Conn.
+func (c *listenerConn) Read(b []byte) (int, error) {
+	if c.proxy == nil {
+		return c.Conn.Read(b)
+	}
+	if err := c.readProxyHeader(); err != nil {
+		return 0, err
+	}
+	return c.proxy.Read(b)
+}
+
+// RemoteAddr implements net.Conn.
+func (c *listenerConn) RemoteAddr() net.Addr {
+	if c.proxy != nil {
+		// an unreadable header is reported by Read, which closes the connection
+		_ = c.readProxyHeader()
+	}
+	return c.addr
+}
+
+func (c *listenerConn) readProxyHeader() error {
+	src, err := c.proxy.readHeader()
+	if err != nil {
+		return err
+	}
+	if src != nil {
+		c.addr.Addr = src
+	}
+	return nil
+}
+
+// secureTransportAuthServer turns away connections that don't use TLS from listeners that require it.
+type secureTransportAuthServer struct {
+	mysql.AuthServer
+}
+
+// ValidateHash implements mysql.AuthServer.
+func (s secureTransportAuthServer) ValidateHash(salt []byte, user string, authResponse []byte, remoteAddr net.Addr) (mysql.Getter, error) {
+	if err := checkSecureTransport(remoteAddr); err != nil {
+		return nil, err
+	}
+	return s.AuthServer.ValidateHash(salt, user, authResponse, remoteAddr)
+}
+
+// Negotiate implements mysql.AuthServer.
+func (s secureTransportAuthServer) Negotiate(c *mysql.Conn, user string, remoteAddr net.Addr) (mysql.Getter, error) {
+	if err := checkSecureTransport(remoteAddr); err != nil {
+		return nil, err
+	}
+	return s.AuthServer.Negotiate(c, user, remoteAddr)
+}
+
+func checkSecureTransport(remoteAddr net.Addr) error {
+	addr, ok := remoteAddr.(*listenerAddr)
+	if ok && addr.listener.requireSecure && !addr.secure.Load() {
+		return mysql.NewSQLError(mysql.ERAccessDeniedError, mysql.SSAccessDeniedError, "server does not allow insecure connections, client must use SSL/TLS")
+	}
+	return nil
+}
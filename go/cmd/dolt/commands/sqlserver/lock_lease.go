@@ -0,0 +1,137 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+const (
+	// lockLeaseDuration is how long a server's lease lock holds its databases without being renewed. Servers on hosts
+	// whose clocks differ by more than this can open the same databases.
+	lockLeaseDuration = 30 * time.Second
+	// lockLeaseRenewInterval is how often a server renews its lease lock.
+	lockLeaseRenewInterval = 10 * time.Second
+)
+
+// validateDataDirFilesystem returns an error if the data dir |dataDirFS| is on a network filesystem that the server
+// can't safely run on with |serverConfig|.
+func validateDataDirFilesystem(serverConfig ServerConfig, dataDirFS filesys.Filesys) error {
+	if _, ok := dataDirFS.(*filesys.InMemFS); ok {
+		return nil
+	}
+	dataDir, err := dataDirFS.Abs("")
+	if err != nil {
+		return err
+	}
+
+	fsType, err := filesys.NetworkFilesystemType(dataDir)
+	if err != nil {
+		return err
+	} else if fsType == "" {
+		return nil
+	}
+
+	if !serverConfig.LeaseLock() {
+		return fmt.Errorf("data dir %s is on a network filesystem (%s), where the sql-server lock can't stop servers on "+
+			"other hosts from opening its databases; set behavior.lease_lock to true to run a sql-server on it", dataDir, fsType)
+	}
+	if fsType == filesys.NetworkFilesystemSMB && os.Getenv(dconfig.EnvDisableChunkJournal) == "" {
+		return fmt.Errorf("data dir %s is on an SMB filesystem, where the client can reorder writes to the chunk "+
+			"journal; set %s to run a sql-server on it", dataDir, dconfig.EnvDisableChunkJournal)
+	}
+	return nil
+}
+
+// lockLeaseHolder is an environment that a lease lock is renewed on.
+type lockLeaseHolder interface {
+	RenewLockLease(lock *env.DBLock, lease time.Duration) error
+}
+
+// lockLeaseRenewer renews a server's lease lock on its environments until it's stopped. If the lease is lost, because
+// another server took it over after it expired, |onLost| is called to stop the server.
+type lockLeaseRenewer struct {
+	lock   *env.DBLock
+	lgr    *logrus.Logger
+	onLost func()
+
+	mu      sync.Mutex
+	holders []lockLeaseHolder
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newLockLeaseRenewer(lock *env.DBLock, lgr *logrus.Logger, onLost func()) *lockLeaseRenewer {
+	return &lockLeaseRenewer{
+		lock:   lock,
+		lgr:    lgr,
+		onLost: onLost,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Add renews the lease on |holder|, as well as the environments added before it.
+func (r *lockLeaseRenewer) Add(holder lockLeaseHolder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.holders = append(r.holders, holder)
+}
+
+func (r *lockLeaseRenewer) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(lockLeaseRenewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				if err := r.renew(); err != nil {
+					r.lgr.Errorf("stopping the server, since it can't renew its lock: %v", err)
+					r.onLost()
+					return
+				}
+			}
+		}
+	}()
+}
+
+func (r *lockLeaseRenewer) Stop() {
+	close(r.stop)
+	r.wg.Wait()
+}
+
+func (r *lockLeaseRenewer) renew() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, holder := range r.holders {
+		if err := holder.RenewLockLease(r.lock, lockLeaseDuration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/utils/earl"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas/pull"
+)
+
+// snapshottingServerConfig is implemented by server configs for in-memory servers whose databases should be written to
+// disk when the server shuts down.
+type snapshottingServerConfig interface {
+	ServerConfig
+	// memorySnapshotDir returns the directory that databases are written to on shutdown, or the empty string if they
+	// should be discarded.
+	memorySnapshotDir() string
+}
+
+// newInMemoryEnv returns an environment with an empty in-memory filesystem, so that every database the server creates
+// lives in memory only. The configuration of |dEnv| is kept so that commits are attributed to the current user.
+func newInMemoryEnv(ctx context.Context, dEnv *env.DoltEnv, version string) *env.DoltEnv {
+	memEnv := env.Load(ctx, env.GetCurrentUserHomeDir, filesys.EmptyInMemFS(""), doltdb.InMemDoltDB, version)
+	memEnv.Config = dEnv.Config
+	memEnv.IgnoreLockFile = dEnv.IgnoreLockFile
+	return memEnv
+}
+
+// snapshotDatabases writes every database served by |sqlEngine| to a subdirectory of |dir| named after the database.
+// Each snapshot has the layout of a backup, and can be restored with `dolt backup restore file://<dir>/<db> <db>`.
+func snapshotDatabases(ctx context.Context, sqlEngine *engine.SqlEngine, dir string) error {
+	sqlCtx, err := sqlEngine.NewDefaultContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "dolt-memory-snapshot")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for _, db := range sqlEngine.Databases(sqlCtx) {
+		ddb := db.DbData().Ddb
+		if ddb == nil {
+			continue
+		}
+
+		destDir := filepath.Join(absDir, db.Name())
+		if err = os.MkdirAll(destDir, os.ModePerm); err != nil {
+			return err
+		}
+
+		destUrl := earl.FileUrlFromPath(filepath.ToSlash(destDir), os.PathSeparator)
+		destDb, err := doltdb.LoadDoltDB(ctx, ddb.Format(), destUrl, filesys.LocalFS)
+		if err != nil {
+			return fmt.Errorf("error creating snapshot of database %s: %w", db.Name(), err)
+		}
+
+		err = actions.SyncRoots(ctx, ddb, destDb, tmpDir, startSnapshotProgress, stopSnapshotProgress)
+		if err != nil && err != pull.ErrDBUpToDate {
+			return fmt.Errorf("error creating snapshot of database %s: %w", db.Name(), err)
+		}
+
+		cli.PrintErrf("Wrote snapshot of database %s to %s\n", db.Name(), destDir)
+	}
+
+	return nil
+}
+
+// startSnapshotProgress drains the progress of a snapshot, which is not reported.
+func startSnapshotProgress(ctx context.Context) (*sync.WaitGroup, chan pull.Stats) {
+	statsCh := make(chan pull.Stats)
+	wg := &sync.WaitGroup{}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range statsCh {
+		}
+	}()
+
+	return wg, statsCh
+}
+
+func stopSnapshotProgress(cancel context.CancelFunc, wg *sync.WaitGroup, statsCh chan pull.Stats) {
+	cancel()
+	close(statsCh)
+	wg.Wait()
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/clusterdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/resultcache"
 	"github.com/dolthub/dolt/go/libraries/utils/version"
 )
 
@@ -48,17 +49,39 @@ type metricsListener struct {
 	gaugeVersion           prometheus.Gauge
 
 	// replication metrics
-	isReplicaGauges      *prometheus.GaugeVec
-	replicationLagGauges *prometheus.GaugeVec
+	isReplicaGauges           *prometheus.GaugeVec
+	replicationLagGauges      *prometheus.GaugeVec
+	retryAttemptsGauges       *prometheus.GaugeVec
+	bytesPendingGauges        *prometheus.GaugeVec
+	cntReplicationFailures    *prometheus.CounterVec
+	cntRoleTransitions        prometheus.Counter
+	replicationMetricsVectors []metricVec
+
+	// result cache metrics
+	resultCacheCollectors []prometheus.Collector
 
 	// used in updating cluster metrics
 	clusterStatus  clusterdb.ClusterStatusProvider
 	mu             *sync.Mutex
 	done           bool
 	clusterSeenDbs map[string]struct{}
+	// seenFailures is the number of failed replication attempts last reported for each database and remote, and
+	// seenEventSeq is the sequence number of the last replication event counted.
+	seenFailures map[replicaKey]uint64
+	seenEventSeq int64
+}
+
+type replicaKey struct {
+	database, remote string
 }
 
-func newMetricsListener(labels prometheus.Labels, versionStr string, clusterStatus clusterdb.ClusterStatusProvider) (*metricsListener, error) {
+// metricVec is a labeled metric whose series are deleted along with their database.
+type metricVec interface {
+	prometheus.Collector
+	DeletePartialMatch(labels prometheus.Labels) int
+}
+
+func newMetricsListener(labels prometheus.Labels, versionStr string, clusterStatus clusterdb.ClusterStatusProvider, resultCache *resultcache.Cache) (*metricsListener, error) {
 	ml := &metricsListener{
 		labels: labels,
 		cntConnections: prometheus.NewCounter(prometheus.CounterOpts{
@@ -102,9 +125,41 @@ func newMetricsListener(labels prometheus.Labels, versionStr string, clusterStat
 			Help:        "one if the server is currently in this role, zero otherwise",
 			ConstLabels: labels,
 		}, []string{dbLabel}),
+		retryAttemptsGauges: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "dss_replication_retry_attempts",
+			Help:        "The number of attempts to replicate to the given standby that have failed since the last one that succeeded.",
+			ConstLabels: labels,
+		}, []string{dbLabel, remoteLabel}),
+		bytesPendingGauges: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "dss_replication_bytes_pending",
+			Help:        "The number of bytes of the running replication to the given standby that have not been sent yet.",
+			ConstLabels: labels,
+		}, []string{dbLabel, remoteLabel}),
+		cntReplicationFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "dss_replication_failures",
+			Help:        "Count of failed attempts to replicate to the given standby",
+			ConstLabels: labels,
+		}, []string{dbLabel, remoteLabel}),
+		cntRoleTransitions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "dss_cluster_role_transitions",
+			Help:        "Count of the changes of this server's cluster role or epoch",
+			ConstLabels: labels,
+		}),
 		clusterStatus:  clusterStatus,
 		mu:             &sync.Mutex{},
 		clusterSeenDbs: make(map[string]struct{}),
+		seenFailures:   make(map[replicaKey]uint64),
+	}
+	ml.replicationMetricsVectors = []metricVec{
+		ml.isReplicaGauges,
+		ml.replicationLagGauges,
+		ml.retryAttemptsGauges,
+		ml.bytesPendingGauges,
+		ml.cntReplicationFailures,
+	}
+
+	if resultCache != nil {
+		ml.resultCacheCollectors = resultCacheCollectors(labels, resultCache)
 	}
 
 	u32Version, err := version.Encode(versionStr)
@@ -124,8 +179,13 @@ func newMetricsListener(labels prometheus.Labels, versionStr string, clusterStat
 	prometheus.MustRegister(ml.gaugeConcurrentConn)
 	prometheus.MustRegister(ml.gaugeConcurrentQueries)
 	prometheus.MustRegister(ml.histQueryDur)
-	prometheus.MustRegister(ml.replicationLagGauges)
-	prometheus.MustRegister(ml.isReplicaGauges)
+	for _, v := range ml.replicationMetricsVectors {
+		prometheus.MustRegister(v)
+	}
+	prometheus.MustRegister(ml.cntRoleTransitions)
+	for _, c := range ml.resultCacheCollectors {
+		prometheus.MustRegister(c)
+	}
 
 	go func() {
 		for ml.updateReplMetrics() {
@@ -145,16 +205,38 @@ func (ml *metricsListener) updateReplMetrics() bool {
 		return false
 	}
 
+	for _, e := range ml.clusterStatus.GetReplicationEvents() {
+		if e.Seq <= ml.seenEventSeq {
+			continue
+		}
+		ml.seenEventSeq = e.Seq
+		if e.Event == cluster.ReplicationEventRoleChange {
+			ml.cntRoleTransitions.Inc()
+		}
+	}
+
 	perDbStatus := ml.clusterStatus.GetClusterStatus()
 	if perDbStatus == nil {
 		return true
 	}
 
 	dbNames := make(map[string]struct{})
+	replicas := make(map[replicaKey]uint64)
 	for _, status := range perDbStatus {
 		dbName := status.Database
 		dbNames[dbName] = struct{}{}
 
+		key := replicaKey{status.Database, status.Remote}
+		replicas[key] = status.FailedAttempts
+		// the count starts over when a database is dropped and created again
+		if seen := ml.seenFailures[key]; status.FailedAttempts >= seen {
+			ml.cntReplicationFailures.WithLabelValues(status.Database, status.Remote).Add(float64(status.FailedAttempts - seen))
+		} else {
+			ml.cntReplicationFailures.WithLabelValues(status.Database, status.Remote).Add(float64(status.FailedAttempts))
+		}
+		ml.retryAttemptsGauges.WithLabelValues(status.Database, status.Remote).Set(float64(status.RetryAttempts))
+		ml.bytesPendingGauges.WithLabelValues(status.Database, status.Remote).Set(float64(status.BytesPending))
+
 		if status.Role == string(cluster.RolePrimary) {
 			ml.isReplicaGauges.WithLabelValues(status.Database).Set(0.0)
 
@@ -172,11 +254,13 @@ func (ml *metricsListener) updateReplMetrics() bool {
 	// deregister metrics for deleted databases
 	for db := range ml.clusterSeenDbs {
 		if _, ok := dbNames[db]; !ok {
-			ml.isReplicaGauges.DeletePartialMatch(prometheus.Labels{"database": db})
-			ml.replicationLagGauges.DeletePartialMatch(prometheus.Labels{"database": db})
+			for _, v := range ml.replicationMetricsVectors {
+				v.DeletePartialMatch(prometheus.Labels{"database": db})
+			}
 		}
 	}
 	ml.clusterSeenDbs = dbNames
+	ml.seenFailures = replicas
 
 	return true
 }
@@ -207,16 +291,61 @@ func (ml *metricsListener) Close() {
 	prometheus.Unregister(ml.gaugeConcurrentConn)
 	prometheus.Unregister(ml.gaugeConcurrentQueries)
 	prometheus.Unregister(ml.histQueryDur)
+	for _, c := range ml.resultCacheCollectors {
+		prometheus.Unregister(c)
+	}
 
 	ml.closeReplicationMetrics()
 }
 
+func resultCacheCollectors(labels prometheus.Labels, cache *resultcache.Cache) []prometheus.Collector {
+	return []prometheus.Collector{
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "dss_result_cache_hits",
+			Help:        "Count of queries on commits and tags answered from the result cache",
+			ConstLabels: labels,
+		}, func() float64 {
+			return float64(cache.Stats().Hits)
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "dss_result_cache_misses",
+			Help:        "Count of cacheable queries whose results weren't in the result cache",
+			ConstLabels: labels,
+		}, func() float64 {
+			return float64(cache.Stats().Misses)
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "dss_result_cache_evictions",
+			Help:        "Count of results evicted from the result cache to make room for others",
+			ConstLabels: labels,
+		}, func() float64 {
+			return float64(cache.Stats().Evictions)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "dss_result_cache_entries",
+			Help:        "Number of results in the result cache",
+			ConstLabels: labels,
+		}, func() float64 {
+			return float64(cache.Stats().Entries)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "dss_result_cache_bytes",
+			Help:        "Estimated size in bytes of the results in the result cache",
+			ConstLabels: labels,
+		}, func() float64 {
+			return float64(cache.Stats().Bytes)
+		}),
+	}
+}
+
 func (ml *metricsListener) closeReplicationMetrics() {
 	ml.mu.Lock()
 	defer ml.mu.Unlock()
 
-	prometheus.Unregister(ml.replicationLagGauges)
-	prometheus.Unregister(ml.isReplicaGauges)
+	for _, v := range ml.replicationMetricsVectors {
+		prometheus.Unregister(v)
+	}
+	prometheus.Unregister(ml.cntRoleTransitions)
 
 	ml.done = true
 }
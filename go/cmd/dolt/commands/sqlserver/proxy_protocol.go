@@ -0,0 +1,186 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The PROXY protocol is described in https://www.haproxy.org/download/2.8/doc/proxy-protocol.txt
+
+var proxyV2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+const (
+	proxyV1Prefix       = "PROXY "
+	proxyV1MaxLen       = 107
+	proxyV2HeaderLen    = 16
+	proxyV2Version      = 0x20
+	proxyV2CmdLocal     = 0x00
+	proxyV2CmdProxy     = 0x01
+	proxyV2FamilyInet   = 0x10
+	proxyV2FamilyInet6  = 0x20
+	proxyV2AddrLenInet  = 12
+	proxyV2AddrLenInet6 = 36
+	proxyHeaderTimeout  = 5 * time.Second
+)
+
+var errNoProxyHeader = errors.New("connection did not begin with a PROXY protocol header")
+
+// proxyProtocolReader reads the PROXY protocol header from the beginning of a connection, and the rest of the
+// connection after it.
+type proxyProtocolReader struct {
+	conn net.Conn
+	br   *bufio.Reader
+	once *sync.Once
+	src  net.Addr
+	err  error
+}
+
+func newProxyProtocolReader(conn net.Conn) *proxyProtocolReader {
+	return &proxyProtocolReader{
+		conn: conn,
+		br:   bufio.NewReader(conn),
+		once: &sync.Once{},
+	}
+}
+
+// readHeader reads the header the first time it's called, and returns the source address it contains. The address is
+// nil for health checks by the proxy, which carry no address.
+func (r *proxyProtocolReader) readHeader() (net.Addr, error) {
+	r.once.Do(func() {
+		if err := r.conn.SetReadDeadline(time.Now().Add(proxyHeaderTimeout)); err != nil {
+			r.err = err
+			return
+		}
+		r.src, r.err = parseProxyHeader(r.br)
+		if err := r.conn.SetReadDeadline(time.Time{}); err != nil && r.err == nil {
+			r.err = err
+		}
+	})
+	return r.src, r.err
+}
+
+// Read reads the connection after the header.
+func (r *proxyProtocolReader) Read(b []byte) (int, error) {
+	return r.br.Read(b)
+}
+
+// parseProxyHeader parses a version 1 or version 2 PROXY protocol header.
+func parseProxyHeader(br *bufio.Reader) (net.Addr, error) {
+	sig, err := br.Peek(len(proxyV2Signature))
+	if err != nil {
+		return nil, errNoProxyHeader
+	}
+	if bytes.Equal(sig, proxyV2Signature) {
+		return parseProxyV2Header(br)
+	}
+	if bytes.HasPrefix(sig, []byte(proxyV1Prefix)) {
+		return parseProxyV1Header(br)
+	}
+	return nil, errNoProxyHeader
+}
+
+func parseProxyV2Header(br *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, proxyV2HeaderLen)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, err
+	}
+
+	verCmd, family := header[12], header[13]
+	length := int(binary.BigEndian.Uint16(header[14:16]))
+	if verCmd&0xF0 != proxyV2Version {
+		return nil, fmt.Errorf("unsupported PROXY protocol version %d", verCmd>>4)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(br, payload); err != nil {
+		return nil, err
+	}
+
+	switch verCmd & 0x0F {
+	case proxyV2CmdLocal:
+		return nil, nil
+	case proxyV2CmdProxy:
+	default:
+		return nil, fmt.Errorf("unsupported PROXY protocol command %d", verCmd&0x0F)
+	}
+
+	// the low bits of |family| are the transport protocol, which is always a stream for a SQL connection
+	switch family & 0xF0 {
+	case proxyV2FamilyInet:
+		if length < proxyV2AddrLenInet {
+			return nil, errors.New("PROXY protocol header is too short for an IPv4 address")
+		}
+		ip := net.IP(payload[0:4])
+		port := binary.BigEndian.Uint16(payload[8:10])
+		return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+	case proxyV2FamilyInet6:
+		if length < proxyV2AddrLenInet6 {
+			return nil, errors.New("PROXY protocol header is too short for an IPv6 address")
+		}
+		ip := net.IP(payload[0:16])
+		port := binary.BigEndian.Uint16(payload[32:34])
+		return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+	default:
+		// unix and unspecified addresses carry nothing useful for grants, so the proxy's address is kept
+		return nil, nil
+	}
+}
+
+// parseProxyV1Header parses a text header like "PROXY TCP4 192.168.0.1 192.168.0.11 56324 3306\r\n".
+func parseProxyV1Header(br *bufio.Reader) (net.Addr, error) {
+	var line []byte
+	for len(line) < proxyV1MaxLen {
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, errors.New("PROXY protocol header is not terminated")
+	}
+
+	fields := strings.Fields(strings.TrimSuffix(string(line), "\r\n"))
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, fmt.Errorf("malformed PROXY protocol header: %q", string(line))
+	}
+
+	ip := net.ParseIP(fields[2])
+	if ip == nil {
+		return nil, fmt.Errorf("malformed PROXY protocol source address: %s", fields[2])
+	}
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("malformed PROXY protocol source port: %s", fields[4])
+	}
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
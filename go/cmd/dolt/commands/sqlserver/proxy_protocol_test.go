@@ -0,0 +1,100 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func proxyV2Header(cmd byte, family byte, payload []byte) []byte {
+	header := append([]byte{}, proxyV2Signature...)
+	header = append(header, proxyV2Version|cmd, family, byte(len(payload)>>8), byte(len(payload)))
+	return append(header, payload...)
+}
+
+func TestParseProxyHeader(t *testing.T) {
+	inet := []byte{
+		10, 0, 0, 1, // source
+		10, 0, 0, 2, // destination
+		0xD4, 0x31, // source port 54321
+		0x0C, 0xEA, // destination port 3306
+	}
+
+	tests := []struct {
+		name     string
+		input    []byte
+		expected net.Addr
+		err      bool
+	}{
+		{
+			name:     "v2 ipv4",
+			input:    proxyV2Header(proxyV2CmdProxy, proxyV2FamilyInet|0x01, inet),
+			expected: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 54321},
+		},
+		{
+			name:  "v2 local",
+			input: proxyV2Header(proxyV2CmdLocal, 0x00, nil),
+		},
+		{
+			name:  "v2 truncated address",
+			input: proxyV2Header(proxyV2CmdProxy, proxyV2FamilyInet|0x01, inet[:6]),
+			err:   true,
+		},
+		{
+			name:     "v1 tcp4",
+			input:    []byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 3306\r\n"),
+			expected: &net.TCPAddr{IP: net.ParseIP("192.168.0.1"), Port: 56324},
+		},
+		{
+			name:  "v1 unknown",
+			input: []byte("PROXY UNKNOWN\r\n"),
+		},
+		{
+			name:  "no header",
+			input: []byte("\x4a\x00\x00\x00\x0a8.0.33\x00\x00\x00"),
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rest := []byte("after the header")
+			br := bufio.NewReader(bytes.NewReader(append(append([]byte{}, test.input...), rest...)))
+			addr, err := parseProxyHeader(br)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if test.expected == nil {
+				assert.Nil(t, addr)
+			} else {
+				require.NotNil(t, addr)
+				assert.Equal(t, test.expected.String(), addr.String())
+			}
+
+			remaining, err := io.ReadAll(br)
+			require.NoError(t, err)
+			assert.Equal(t, rest, remaining)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	sdNotifySocketEnv   = "NOTIFY_SOCKET"
+	sdWatchdogUsecEnv   = "WATCHDOG_USEC"
+	sdWatchdogPidEnv    = "WATCHDOG_PID"
+	sdNotifyReady       = "READY=1"
+	sdNotifyStopping    = "STOPPING=1"
+	sdNotifyWatchdog    = "WATCHDOG=1"
+	sdNotifyStatusFmt   = "STATUS=%s"
+	sdNotifySocketProto = "unixgram"
+)
+
+// sdNotify sends |state| to the service manager over the socket named by $NOTIFY_SOCKET, as described by
+// sd_notify(3). It returns false without error when the server isn't running under a service manager that expects
+// notifications, e.g. a systemd unit with Type=notify.
+func sdNotify(state string) (bool, error) {
+	socketAddr := os.Getenv(sdNotifySocketEnv)
+	if socketAddr == "" {
+		return false, nil
+	}
+
+	addr := &net.UnixAddr{Name: socketAddr, Net: sdNotifySocketProto}
+	conn, err := net.DialUnix(sdNotifySocketProto, nil, addr)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// sdWatchdogInterval returns the interval at which the service manager expects watchdog notifications, or false if
+// the watchdog isn't enabled for this process.
+func sdWatchdogInterval() (time.Duration, bool) {
+	usecStr := os.Getenv(sdWatchdogUsecEnv)
+	if usecStr == "" {
+		return 0, false
+	}
+	usec, err := strconv.ParseInt(usecStr, 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+
+	// WATCHDOG_PID is set when the watchdog is meant for a specific process, which might not be this one
+	if pidStr := os.Getenv(sdWatchdogPidEnv); pidStr != "" {
+		pid, err := strconv.Atoi(pidStr)
+		if err != nil || pid != os.Getpid() {
+			return 0, false
+		}
+	}
+
+	return time.Duration(usec) * time.Microsecond, true
+}
+
+// startSdWatchdog sends watchdog notifications to the service manager at half the interval it requires, until the
+// returned function is called. It does nothing if the watchdog isn't enabled for this process.
+func startSdWatchdog(lgr *logrus.Logger) (stop func()) {
+	interval, ok := sdWatchdogInterval()
+	if !ok {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if _, err := sdNotify(sdNotifyWatchdog); err != nil {
+					lgr.Warnf("error sending watchdog notification to service manager: %v", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
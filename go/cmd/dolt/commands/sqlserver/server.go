@@ -47,7 +47,9 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
 	_ "github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/formatupgrade"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqlserver"
+	"github.com/dolthub/dolt/go/libraries/events"
 )
 
 const (
@@ -100,7 +102,11 @@ func Serve(
 		}
 		logrus.SetLevel(level)
 	}
-	logrus.SetFormatter(LogFormat{})
+	if serverConfig.LogFormat() == cli.LogFormatJSON {
+		logrus.SetFormatter(cli.JSONLogFormatter{})
+	} else {
+		logrus.SetFormatter(LogFormat{})
+	}
 
 	sql.SystemVariables.AddSystemVariables([]sql.SystemVariable{
 		{
@@ -142,13 +148,33 @@ func Serve(
 		dEnv.FS = fs
 	}
 
-	serverLock, startError := acquireGlobalSqlServerLock(serverConfig.Port(), dEnv)
+	if startError = validateDataDirFilesystem(serverConfig, fs); startError != nil {
+		return
+	}
+
+	serverLock, startError := acquireGlobalSqlServerLock(serverConfig.Port(), serverConfig.LeaseLock(), dEnv)
 	if startError != nil {
 		return
 	}
 	defer dEnv.FS.Delete(dEnv.LockFile(), false)
 
-	mrEnv, err = env.MultiEnvForDirectory(ctx, dEnv.Config.WriteableConfig(), fs, dEnv.Version, dEnv.IgnoreLockFile, dEnv)
+	var leaseRenewer *lockLeaseRenewer
+	if serverLock.IsLease() {
+		leaseRenewer = newLockLeaseRenewer(serverLock, lgr, serverController.StopServer)
+		leaseRenewer.Add(dEnv)
+		leaseRenewer.Start()
+		defer leaseRenewer.Stop()
+	}
+
+	discoveryOpts := []env.MultiEnvOption{
+		env.WithDiscoveryDepth(serverConfig.DataDirDiscoveryDepth()),
+		env.WithDiscoveryIgnore(serverConfig.DataDirDiscoveryIgnore()),
+		env.WithLoadConcurrency(serverConfig.DataDirLoadConcurrency()),
+	}
+	if serverConfig.LazyLoadDatabases() {
+		discoveryOpts = append(discoveryOpts, env.WithLazyLoad())
+	}
+	mrEnv, err = env.MultiEnvForDirectory(ctx, dEnv.Config.WriteableConfig(), fs, dEnv.Version, dEnv.IgnoreLockFile, dEnv, discoveryOpts...)
 	if err != nil {
 		return err, nil
 	}
@@ -180,6 +206,13 @@ func Serve(
 		SystemVariables:         serverConfig.SystemVars(),
 		ClusterController:       clusterController,
 		BinlogReplicaController: binlogreplication.DoltBinlogReplicaController,
+		ServerHooks:             serverConfig.Hooks(),
+		Webhooks:                serverConfig.Webhooks(),
+		CommitHooks:             serverConfig.CommitHooks(),
+		LoadConcurrency:         serverConfig.DataDirLoadConcurrency(),
+	}
+	if serverConfig.AutoUpgradeFormat() {
+		config.FormatUpgrades = formatupgrade.NewTracker()
 	}
 	esStatus, err := getEventSchedulerStatus(serverConfig.EventSchedulerStatus())
 	if err != nil {
@@ -197,6 +230,19 @@ func Serve(
 	}
 	defer sqlEngine.Close()
 
+	// Databases of an in-memory server are snapshotted before the engine is closed. |ctx| has usually been canceled by
+	// the time the server shuts down, so the snapshot doesn't use it.
+	if v, ok := serverConfig.(snapshottingServerConfig); ok && v.memorySnapshotDir() != "" {
+		defer func() {
+			if err := snapshotDatabases(context.Background(), sqlEngine, v.memorySnapshotDir()); err != nil {
+				cli.PrintErrln(err)
+				if closeError == nil {
+					closeError = err
+				}
+			}
+		}()
+	}
+
 	// Add superuser if specified user exists; add root superuser if no user specified and no existing privileges
 	userSpecified := config.ServerUser != ""
 
@@ -215,15 +261,36 @@ func Serve(
 	}
 	ed.Close()
 
+	if v, ok := serverConfig.(eventEmittingServerConfig); ok {
+		if emitterCfg, ok := v.eventEmitterConfig(); ok {
+			events.SetEmitterConfig(emitterCfg)
+		}
+	}
+
 	labels := serverConfig.MetricsLabels()
 
 	var listener *metricsListener
-	listener, startError = newMetricsListener(labels, version, clusterController)
+	listener, startError = newMetricsListener(labels, version, clusterController, sqlEngine.ResultCache())
 	if startError != nil {
 		cli.Println(startError)
 		return
 	}
 	defer listener.Close()
+	reaper := newWorkspaceReaper(listener, sqlEngine, lgr)
+
+	listenerFunc, startError := protocolListenerFunc(serverConfig, serverConf)
+	if startError != nil {
+		cli.PrintErr(startError)
+		return
+	}
+	if listenerFunc != nil {
+		// servers keep the protocol listener they were created with, so the default can be restored right away
+		defaultListenerFunc := server.DefaultProtocolListenerFunc
+		server.DefaultProtocolListenerFunc = listenerFunc
+		defer func() {
+			server.DefaultProtocolListenerFunc = defaultListenerFunc
+		}()
+	}
 
 	v, ok := serverConfig.(validatingServerConfig)
 	if ok && v.goldenMysqlConnectionString() != "" {
@@ -231,7 +298,7 @@ func Serve(
 			serverConf,
 			sqlEngine.GetUnderlyingEngine(),
 			newSessionBuilder(sqlEngine, serverConfig),
-			listener,
+			reaper,
 			v.goldenMysqlConnectionString(),
 		)
 	} else {
@@ -239,7 +306,7 @@ func Serve(
 			serverConf,
 			sqlEngine.GetUnderlyingEngine(),
 			newSessionBuilder(sqlEngine, serverConfig),
-			listener,
+			reaper,
 		)
 	}
 
@@ -252,6 +319,18 @@ func Serve(
 	}
 
 	sqlserver.SetRunningServer(mySQLServer, serverLock)
+	reaper.Start()
+	defer reaper.Stop()
+	if config.FormatUpgrades != nil {
+		upgrader := newFormatUpgrader(sqlEngine, mrEnv, config.FormatUpgrades, lgr)
+		upgrader.Start()
+		defer upgrader.Stop()
+	}
+	if serverConfig.LazyLoadDatabases() {
+		warmer := newDatabaseWarmer(sqlEngine, lgr)
+		warmer.Start()
+		defer warmer.Stop()
+	}
 
 	ed = mysqlDb.Editor()
 	mysqlDb.AddSuperUser(ed, LocalConnectionUser, "localhost", serverLock.Secret)
@@ -314,6 +393,7 @@ func Serve(
 	}
 
 	var clusterRemoteSrv *remotesrv.Server
+	var readRouter *cluster.ReadRouter
 	if clusterController != nil {
 		if remoteSrvSqlCtx, err := sqlEngine.NewDefaultContext(ctx); err == nil {
 			args := clusterController.RemoteSrvServerArgs(remoteSrvSqlCtx, remotesrv.ServerArgs{
@@ -351,6 +431,16 @@ func Serve(
 				sqlEngine.GetUnderlyingEngine().ProcessList.Kill,
 				mySQLServer.SessionManager().KillConnection,
 			)
+
+			readRouter, err = clusterController.NewReadRouter(serverConf.Address, serverConfig.ProxyProtocol())
+			if err != nil {
+				lgr.Errorf("error starting read routing listener for cluster config: %v", err)
+				startError = err
+				return
+			}
+			if readRouter != nil {
+				go readRouter.Serve()
+			}
 		} else {
 			lgr.Errorf("error creating SQL engine context for remotesapi server: %v", err)
 			startError = err
@@ -368,6 +458,9 @@ func Serve(
 		startError = err
 		return
 	}
+	if leaseRenewer != nil {
+		leaseRenewer.Add(mrEnv)
+	}
 
 	serverController.registerCloseFunction(startError, func() error {
 		if metSrv != nil {
@@ -379,13 +472,27 @@ func Serve(
 		if clusterRemoteSrv != nil {
 			clusterRemoteSrv.GracefulStop()
 		}
+		if readRouter != nil {
+			readRouter.Close()
+		}
 		if clusterController != nil {
 			clusterController.GracefulStop()
 		}
+		if _, err := sdNotify(sdNotifyStopping); err != nil {
+			lgr.Warnf("error notifying service manager of shutdown: %v", err)
+		}
 
 		return mySQLServer.Close()
 	})
 
+	// The listener is already bound, so a service manager waiting on readiness can send connections right away
+	status := fmt.Sprintf(sdNotifyStatusFmt, fmt.Sprintf("Accepting connections on %s", serverConf.Address))
+	if _, err := sdNotify(sdNotifyReady + "\n" + status); err != nil {
+		lgr.Warnf("error notifying service manager of readiness: %v", err)
+	}
+	stopWatchdog := startSdWatchdog(lgr)
+	defer stopWatchdog()
+
 	closeError = mySQLServer.Start()
 	if closeError != nil {
 		cli.PrintErr(closeError)
@@ -398,7 +505,7 @@ func Serve(
 }
 
 // acquireGlobalSqlServerLock attempts to acquire a global lock on the SQL server. If no error is returned, then the lock was acquired.
-func acquireGlobalSqlServerLock(port int, dEnv *env.DoltEnv) (*env.DBLock, error) {
+func acquireGlobalSqlServerLock(port int, leaseLock bool, dEnv *env.DoltEnv) (*env.DBLock, error) {
 	locked, _, err := dEnv.GetLock()
 	if err != nil {
 		return nil, err
@@ -410,6 +517,9 @@ func acquireGlobalSqlServerLock(port int, dEnv *env.DoltEnv) (*env.DBLock, error
 	}
 
 	lck := env.NewDBLock(port)
+	if leaseLock {
+		lck = env.NewDBLeaseLock(port, lockLeaseDuration)
+	}
 	err = dEnv.Lock(&lck)
 	if err != nil {
 		err = fmt.Errorf("Server can not start. Failed to acquire lock: %s", err.Error())
@@ -444,20 +554,10 @@ func (r *remotesapiAuth) Authenticate(creds *remotesrv.RequestCredentials) bool
 	return r.rawDb.UserHasPrivileges(ctx, privOp)
 }
 
+// LoadClusterTLSConfig returns the TLS config of the cluster's remotesapi server. Its certificates are loaded again
+// when their files change; see cluster.ServerTLSConfig.
 func LoadClusterTLSConfig(cfg cluster.Config) (*tls.Config, error) {
-	rcfg := cfg.RemotesAPIConfig()
-	if rcfg.TLSKey() == "" && rcfg.TLSCert() == "" {
-		return nil, nil
-	}
-	c, err := tls.LoadX509KeyPair(rcfg.TLSCert(), rcfg.TLSKey())
-	if err != nil {
-		return nil, err
-	}
-	return &tls.Config{
-		Certificates: []tls.Certificate{
-			c,
-		},
-	}, nil
+	return cluster.ServerTLSConfig(cfg)
 }
 
 func portInUse(hostPort string) bool {
@@ -21,9 +21,15 @@ import (
 	"net"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/commithooks"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/serverhooks"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/webhooks"
+	"github.com/dolthub/dolt/go/libraries/events"
 )
 
 // LogLevel defines the available levels of logging for the server.
@@ -53,6 +59,9 @@ const (
 	defaultPersistenceBahavior     = loadPerisistentGlobals
 	defaultDataDir                 = "."
 	defaultCfgDir                  = ".doltcfg"
+	defaultDataDirDiscoveryDepth   = 1
+	defaultDataDirLoadConcurrency  = 8
+	defaultReadRoutingMaxLag       = time.Second
 	defaultPrivilegeFilePath       = "privileges.db"
 	defaultBranchControlFilePath   = "branch_control.db"
 	defaultMetricsHost             = ""
@@ -106,6 +115,9 @@ type ServerConfig interface {
 	ReadOnly() bool
 	// LogLevel returns the level of logging that the server will use.
 	LogLevel() LogLevel
+	// LogFormat returns the format of the server's log output. Defaults to the format given by the global
+	// --log-format flag.
+	LogFormat() cli.LogFormat
 	// Autocommit defines the value of the @@autocommit session variable used on every connection
 	AutoCommit() bool
 	// DoltTransactionCommit defines the value of the @@dolt_transaction_commit session variable that enables Dolt
@@ -167,6 +179,49 @@ type ServerConfig interface {
 	ClusterConfig() cluster.Config
 	// EventSchedulerStatus is the configuration for enabling or disabling the event scheduler in this server.
 	EventSchedulerStatus() string
+	// AutoUpgradeFormat is true if the server migrates databases in the old storage format to the current one in the
+	// background.
+	AutoUpgradeFormat() bool
+	// LeaseLock is true if the server locks its databases with a lease that it renews while it runs, rather than with
+	// its pid, so that servers on other hosts respect the lock.
+	LeaseLock() bool
+	// LazyLoadDatabases is true if the server opens the databases in its data directory's subdirectories the first
+	// time they're used, and in the background, rather than when it starts.
+	LazyLoadDatabases() bool
+	// Hooks returns the server hooks run on commit, merge and push, or nil if there are none.
+	Hooks() *serverhooks.Config
+	// Webhooks returns the webhooks fired when branches and tags change.
+	Webhooks() []webhooks.Config
+	// CommitHooks returns the user-defined commit hooks run when branches get new commits.
+	CommitHooks() []commithooks.Config
+	// ProxyProtocol is true if connections to the main listener begin with a PROXY protocol header, which is used for
+	// the address of the client.
+	ProxyProtocol() bool
+	// AdditionalListeners returns the listeners that accept SQL connections in addition to the main listener.
+	AdditionalListeners() []ListenerConfig
+	// DataDirDiscoveryDepth is how many levels of directories under the data dir are searched for databases.
+	DataDirDiscoveryDepth() int
+	// DataDirDiscoveryIgnore returns the patterns of directories under the data dir that aren't searched for databases.
+	DataDirDiscoveryIgnore() []string
+	// DataDirLoadConcurrency is how many of the databases in the data dir are opened at once when the server starts.
+	DataDirLoadConcurrency() int
+}
+
+// ListenerConfig is the configuration of a listener that accepts SQL connections in addition to the main listener.
+type ListenerConfig struct {
+	// Host is the address the listener binds to.
+	Host string
+	// Port is the TCP port of the listener, or 0 if the listener only accepts connections on a unix socket.
+	Port int
+	// Socket is the path of a unix socket file that the listener accepts connections on.
+	Socket string
+	// TLSKey and TLSCert are the TLS key and certificate used for connections to this listener.
+	TLSKey  string
+	TLSCert string
+	// RequireSecureTransport turns away connections to this listener that don't use TLS.
+	RequireSecureTransport bool
+	// ProxyProtocol is true if connections to this listener begin with a PROXY protocol header.
+	ProxyProtocol bool
 }
 
 type validatingServerConfig interface {
@@ -176,6 +231,13 @@ type validatingServerConfig interface {
 	goldenMysqlConnectionString() string
 }
 
+// eventEmittingServerConfig is implemented by server configs that configure where usage events are sent.
+type eventEmittingServerConfig interface {
+	ServerConfig
+	// eventEmitterConfig returns the configuration of the event emitters, or false if the defaults should be used.
+	eventEmitterConfig() (events.EmitterConfig, bool)
+}
+
 type commandLineServerConfig struct {
 	host                    string
 	port                    int
@@ -203,9 +265,11 @@ type commandLineServerConfig struct {
 	remotesapiPort          *int
 	goldenMysqlConn         string
 	eventSchedulerStatus    string
+	memorySnapshot          string
 }
 
 var _ ServerConfig = (*commandLineServerConfig)(nil)
+var _ snapshottingServerConfig = (*commandLineServerConfig)(nil)
 
 // Host returns the domain that the server will run on. Accepts an IPv4 or IPv6 address, in addition to localhost.
 func (cfg *commandLineServerConfig) Host() string {
@@ -247,6 +311,11 @@ func (cfg *commandLineServerConfig) LogLevel() LogLevel {
 	return cfg.logLevel
 }
 
+// LogFormat returns the format of the server's log output, which is given by the global --log-format flag.
+func (cfg *commandLineServerConfig) LogFormat() cli.LogFormat {
+	return cli.GetLogFormat()
+}
+
 // AutoCommit defines the value of the @@autocommit session variable used on every connection
 func (cfg *commandLineServerConfig) AutoCommit() bool {
 	return cfg.autoCommit
@@ -330,6 +399,50 @@ func (cfg *commandLineServerConfig) ClusterConfig() cluster.Config {
 	return nil
 }
 
+func (cfg *commandLineServerConfig) AutoUpgradeFormat() bool {
+	return false
+}
+
+func (cfg *commandLineServerConfig) LeaseLock() bool {
+	return false
+}
+
+func (cfg *commandLineServerConfig) LazyLoadDatabases() bool {
+	return false
+}
+
+func (cfg *commandLineServerConfig) Hooks() *serverhooks.Config {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) Webhooks() []webhooks.Config {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) CommitHooks() []commithooks.Config {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) ProxyProtocol() bool {
+	return false
+}
+
+func (cfg *commandLineServerConfig) AdditionalListeners() []ListenerConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) DataDirDiscoveryDepth() int {
+	return defaultDataDirDiscoveryDepth
+}
+
+func (cfg *commandLineServerConfig) DataDirDiscoveryIgnore() []string {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) DataDirLoadConcurrency() int {
+	return defaultDataDirLoadConcurrency
+}
+
 // PrivilegeFilePath returns the path to the file which contains all needed privilege information in the form of a
 // JSON string.
 func (cfg *commandLineServerConfig) PrivilegeFilePath() string {
@@ -484,6 +597,15 @@ func (cfg *commandLineServerConfig) withGoldenMysqlConnectionString(cs string) *
 	return cfg
 }
 
+func (cfg *commandLineServerConfig) memorySnapshotDir() string {
+	return cfg.memorySnapshot
+}
+
+func (cfg *commandLineServerConfig) withMemorySnapshotDir(dir string) *commandLineServerConfig {
+	cfg.memorySnapshot = dir
+	return cfg
+}
+
 func (cfg *commandLineServerConfig) EventSchedulerStatus() string {
 	switch cfg.eventSchedulerStatus {
 	case "", "1":
@@ -536,12 +658,56 @@ func ValidateConfig(config ServerConfig) error {
 	if config.LogLevel().String() == "unknown" {
 		return fmt.Errorf("loglevel is invalid: %v\n", string(config.LogLevel()))
 	}
+	if _, err := cli.ParseLogFormat(string(config.LogFormat())); err != nil {
+		return fmt.Errorf("log_format is invalid: %w", err)
+	}
 	if config.RequireSecureTransport() && config.TLSCert() == "" && config.TLSKey() == "" {
 		return fmt.Errorf("require_secure_transport can only be `true` when a tls_key and tls_cert are provided.")
 	}
+	for _, l := range config.AdditionalListeners() {
+		if err := validateListenerConfig(l); err != nil {
+			return err
+		}
+	}
+	if err := config.Hooks().Validate(); err != nil {
+		return err
+	}
+	if err := webhooks.Validate(config.Webhooks()); err != nil {
+		return err
+	}
+	if err := commithooks.Validate(config.CommitHooks()); err != nil {
+		return err
+	}
+	if config.DataDirDiscoveryDepth() < 1 {
+		return fmt.Errorf("data_dir_discovery.depth must be at least 1: %v", config.DataDirDiscoveryDepth())
+	}
+	if config.DataDirLoadConcurrency() < 1 {
+		return fmt.Errorf("data_dir_discovery.load_concurrency must be at least 1: %v", config.DataDirLoadConcurrency())
+	}
+	if config.AutoUpgradeFormat() && config.ClusterConfig() != nil {
+		return fmt.Errorf("auto_upgrade_format cannot be used with cluster configuration, since standbys can't replicate a database in a different format")
+	}
 	return ValidateClusterConfig(config.ClusterConfig())
 }
 
+func validateListenerConfig(l ListenerConfig) error {
+	if l.Port == 0 && l.Socket == "" {
+		return fmt.Errorf("listeners must have a port or a socket")
+	}
+	if l.Port != 0 {
+		if l.Host != "localhost" && net.ParseIP(l.Host) == nil {
+			return fmt.Errorf("listener address is not a valid IP: %v", l.Host)
+		}
+		if l.Port < 1024 || l.Port > 65535 {
+			return fmt.Errorf("listener port is not in the range between 1024-65535: %v", l.Port)
+		}
+	}
+	if l.RequireSecureTransport && l.TLSCert == "" && l.TLSKey == "" {
+		return fmt.Errorf("require_secure_transport can only be `true` for a listener when a tls_key and tls_cert are provided.")
+	}
+	return nil
+}
+
 func ValidateClusterConfig(config cluster.Config) error {
 	if config == nil {
 		return nil
@@ -558,8 +724,8 @@ func ValidateClusterConfig(config cluster.Config) error {
 			return fmt.Errorf("cluster: standby_remotes[%d]: remote_url_template: is \"%s\" but must include the {database} template parameter", i, remotes[i].RemoteURLTemplate())
 		}
 	}
-	if config.BootstrapRole() != "" && config.BootstrapRole() != "primary" && config.BootstrapRole() != "standby" {
-		return fmt.Errorf("cluster: boostrap_role: is \"%s\" but must be \"primary\" or \"standby\"", config.BootstrapRole())
+	if config.BootstrapRole() != "" && config.BootstrapRole() != "primary" && config.BootstrapRole() != "standby" && config.BootstrapRole() != "observer" {
+		return fmt.Errorf("cluster: boostrap_role: is \"%s\" but must be \"primary\", \"standby\" or \"observer\"", config.BootstrapRole())
 	}
 	if config.BootstrapEpoch() < 0 {
 		return fmt.Errorf("cluster: boostrap_epoch: is %d but must be >= 0", config.BootstrapEpoch())
@@ -573,6 +739,92 @@ func ValidateClusterConfig(config cluster.Config) error {
 	if config.RemotesAPIConfig().TLSKey() != "" && config.RemotesAPIConfig().TLSCert() == "" {
 		return fmt.Errorf("cluster: remotesapi: tls_cert: must supply a tls_cert if you supply a tls_key")
 	}
+	if config.RemotesAPIConfig().TLSClientKey() == "" && config.RemotesAPIConfig().TLSClientCert() != "" {
+		return fmt.Errorf("cluster: remotesapi: tls_client_key: must supply a tls_client_key if you supply a tls_client_cert")
+	}
+	if config.RemotesAPIConfig().TLSClientKey() != "" && config.RemotesAPIConfig().TLSClientCert() == "" {
+		return fmt.Errorf("cluster: remotesapi: tls_client_cert: must supply a tls_client_cert if you supply a tls_client_key")
+	}
+	if config.RemotesAPIConfig().TLSClientCA() != "" && config.RemotesAPIConfig().TLSCert() == "" {
+		return fmt.Errorf("cluster: remotesapi: tls_client_ca: requires a tls_cert and tls_key, since client certificates are only requested over tls")
+	}
+	if readRouting := config.ReadRoutingConfig(); readRouting != nil {
+		if readRouting.Port() < 1 || readRouting.Port() > 65535 {
+			return fmt.Errorf("cluster: read_routing: port: is not in range 1-65535: %d", readRouting.Port())
+		}
+		if readRouting.MaxLag() < 0 {
+			return fmt.Errorf("cluster: read_routing: max_lag_millis: must be >= 0")
+		}
+		routable := false
+		for i := range remotes {
+			routable = routable || remotes[i].SQLAddress() != ""
+		}
+		if !routable {
+			return fmt.Errorf("cluster: read_routing: requires a standby_remote with a sql_address")
+		}
+	}
+	if throttle := config.ReplicationThrottleConfig(); throttle != nil {
+		if throttle.MaxBytesPerSec() < 0 {
+			return fmt.Errorf("cluster: replication_throttle: max_bytes_per_sec: must be >= 0")
+		}
+		if throttle.ChunksPerBatch() < 0 {
+			return fmt.Errorf("cluster: replication_throttle: chunks_per_batch: must be >= 0")
+		}
+	}
+	if heartbeat := config.ReplicationHeartbeatConfig(); heartbeat != nil {
+		if heartbeat.Interval() <= 0 {
+			return fmt.Errorf("cluster: replication_heartbeat: interval_millis: must be > 0")
+		}
+		if heartbeat.Timeout() <= 0 {
+			return fmt.Errorf("cluster: replication_heartbeat: timeout_millis: must be > 0")
+		}
+		if heartbeat.FailureThreshold() < 1 {
+			return fmt.Errorf("cluster: replication_heartbeat: failure_threshold: must be >= 1")
+		}
+	}
+	if breaker := config.ReplicationCircuitBreakerConfig(); breaker != nil {
+		if breaker.FailureThreshold() < 1 {
+			return fmt.Errorf("cluster: replication_circuit_breaker: failure_threshold: must be >= 1")
+		}
+		if breaker.ProbeInterval() < 0 {
+			return fmt.Errorf("cluster: replication_circuit_breaker: probe_interval_millis: must be >= 0")
+		}
+	}
+	if failover := config.AutomaticFailoverConfig(); failover != nil {
+		if len(remotes) < 2 {
+			return fmt.Errorf("cluster: automatic_failover: requires at least two standby_remotes, so that a majority of the cluster can elect a primary")
+		}
+		if failover.LeaseDuration() < cluster.MinFailoverLeaseDuration {
+			return fmt.Errorf("cluster: automatic_failover: lease_duration_millis: must be >= %d", cluster.MinFailoverLeaseDuration.Milliseconds())
+		}
+		if failover.Priority() < 0 {
+			return fmt.Errorf("cluster: automatic_failover: priority: must be >= 0")
+		}
+		// a primary's lease is only renewed by its heartbeats once it's caught up, so it has to span a few of them
+		if heartbeat := config.ReplicationHeartbeatConfig(); heartbeat != nil && heartbeat.Interval()*3 > failover.LeaseDuration() {
+			return fmt.Errorf("cluster: replication_heartbeat: interval_millis: must be at most a third of automatic_failover: lease_duration_millis")
+		}
+	}
+	if config.RequiredAcks() < 0 || config.RequiredAcks() > len(remotes) {
+		return fmt.Errorf("cluster: required_acks: is not in range 0-%d, the number of standby_remotes: %d", len(remotes), config.RequiredAcks())
+	}
+	if tmpl := config.StandbyBootstrapURLTemplate(); tmpl != "" && strings.Index(tmpl, "{database}") == -1 {
+		return fmt.Errorf("cluster: standby_bootstrap_url_template: is \"%s\" but must include the {database} template parameter", tmpl)
+	}
+	if bo := config.ReplicationBackoffConfig(); bo != nil {
+		if bo.InitialInterval() <= 0 {
+			return fmt.Errorf("cluster: replication_backoff: initial_interval_millis: must be > 0")
+		}
+		if bo.Multiplier() < 1 {
+			return fmt.Errorf("cluster: replication_backoff: multiplier: must be >= 1")
+		}
+		if bo.MaxInterval() < bo.InitialInterval() {
+			return fmt.Errorf("cluster: replication_backoff: max_interval_millis: must be >= initial_interval_millis")
+		}
+		if bo.Jitter() < 0 || bo.Jitter() >= 1 {
+			return fmt.Errorf("cluster: replication_backoff: jitter: must be >= 0 and < 1")
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,250 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const (
+	serviceNameFlag    = "name"
+	defaultServiceName = "dolt-sql-server"
+)
+
+var errServicesUnsupported = errors.New("running sql-server as a service is only supported on Windows. " +
+	"On Linux, run it from a systemd unit with Type=notify, which sql-server supports natively")
+
+// ServiceCommands are the commands for managing a sql-server that runs as a Windows service.
+var ServiceCommands = cli.NewSubCommandHandler("sql-server-service", "Commands for running sql-server as a Windows service.", []cli.Command{
+	ServiceInstallCmd{},
+	ServiceUninstallCmd{},
+	ServiceStartCmd{},
+	ServiceStopCmd{},
+})
+
+var serviceInstallDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Install sql-server as a Windows service.",
+	LongDesc: `Registers a Windows service that runs {{.EmphasisLeft}}dolt sql-server{{.EmphasisRight}} with the given configuration file, or on the databases in the given data directory, and starts automatically with the system. Server logs are written to the Windows event log under the name of the service.
+
+The service is registered to run the dolt binary used to install it.`,
+	Synopsis: []string{
+		"[--name {{.LessThan}}service{{.GreaterThan}}] [--config {{.LessThan}}file{{.GreaterThan}}] [--data-dir {{.LessThan}}directory{{.GreaterThan}}]",
+	},
+}
+
+type ServiceInstallCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd ServiceInstallCmd) Name() string {
+	return "install"
+}
+
+// Description returns a description of the command
+func (cmd ServiceInstallCmd) Description() string {
+	return serviceInstallDocs.ShortDesc
+}
+
+func (cmd ServiceInstallCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(serviceInstallDocs, ap)
+}
+
+func (cmd ServiceInstallCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
+	ap.SupportsString(serviceNameFlag, "", "service", "The name of the service. Defaults to `"+defaultServiceName+"`.")
+	ap.SupportsString(configFileFlag, "", "file", "The yaml config file the server is started with.")
+	ap.SupportsString(commands.DataDirFlag, "", "directory", "The directory of the databases to serve, if no config file is given. Defaults to the current directory.")
+	return ap
+}
+
+// RequiresRepo should return false if this interface is implemented, and the command does not have the requirement
+// that it be run from within a data repository directory
+func (cmd ServiceInstallCmd) RequiresRepo() bool {
+	return false
+}
+
+// Exec executes the command
+func (cmd ServiceInstallCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, serviceInstallDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	// Services don't start in the directory they're installed from, so every path is made absolute
+	var serverArgs []string
+	if cfgFile, ok := apr.GetValue(configFileFlag); ok {
+		path, err := filepath.Abs(cfgFile)
+		if err != nil {
+			return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		serverArgs = append(serverArgs, "--"+configFileFlag, path)
+	} else {
+		dataDir := apr.GetValueOrDefault(commands.DataDirFlag, ".")
+		path, err := filepath.Abs(dataDir)
+		if err != nil {
+			return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		serverArgs = append(serverArgs, "--"+commands.DataDirFlag, path)
+	}
+
+	name := apr.GetValueOrDefault(serviceNameFlag, defaultServiceName)
+	if err := installService(name, serverArgs); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error installing service %s", name).AddCause(err).Build(), usage)
+	}
+
+	cli.Printf("Installed service %s\n", name)
+	return 0
+}
+
+var serviceUninstallDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Remove a sql-server Windows service.",
+	LongDesc:  `Removes a Windows service installed with {{.EmphasisLeft}}dolt sql-server-service install{{.EmphasisRight}}, along with its event log source. A running service is stopped first.`,
+	Synopsis:  []string{"[--name {{.LessThan}}service{{.GreaterThan}}]"},
+}
+
+type ServiceUninstallCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd ServiceUninstallCmd) Name() string {
+	return "uninstall"
+}
+
+// Description returns a description of the command
+func (cmd ServiceUninstallCmd) Description() string {
+	return serviceUninstallDocs.ShortDesc
+}
+
+func (cmd ServiceUninstallCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(serviceUninstallDocs, ap)
+}
+
+func (cmd ServiceUninstallCmd) ArgParser() *argparser.ArgParser {
+	return serviceNameArgParser(cmd.Name())
+}
+
+// RequiresRepo should return false if this interface is implemented, and the command does not have the requirement
+// that it be run from within a data repository directory
+func (cmd ServiceUninstallCmd) RequiresRepo() bool {
+	return false
+}
+
+// Exec executes the command
+func (cmd ServiceUninstallCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	return execServiceAction(commandStr, args, cmd.ArgParser(), serviceUninstallDocs, "Removed", removeService)
+}
+
+var serviceStartDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Start a sql-server Windows service.",
+	LongDesc:  `Starts a Windows service installed with {{.EmphasisLeft}}dolt sql-server-service install{{.EmphasisRight}}.`,
+	Synopsis:  []string{"[--name {{.LessThan}}service{{.GreaterThan}}]"},
+}
+
+type ServiceStartCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd ServiceStartCmd) Name() string {
+	return "start"
+}
+
+// Description returns a description of the command
+func (cmd ServiceStartCmd) Description() string {
+	return serviceStartDocs.ShortDesc
+}
+
+func (cmd ServiceStartCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(serviceStartDocs, ap)
+}
+
+func (cmd ServiceStartCmd) ArgParser() *argparser.ArgParser {
+	return serviceNameArgParser(cmd.Name())
+}
+
+// RequiresRepo should return false if this interface is implemented, and the command does not have the requirement
+// that it be run from within a data repository directory
+func (cmd ServiceStartCmd) RequiresRepo() bool {
+	return false
+}
+
+// Exec executes the command
+func (cmd ServiceStartCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	return execServiceAction(commandStr, args, cmd.ArgParser(), serviceStartDocs, "Started", startService)
+}
+
+var serviceStopDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Stop a sql-server Windows service.",
+	LongDesc:  `Stops a running Windows service installed with {{.EmphasisLeft}}dolt sql-server-service install{{.EmphasisRight}}, and waits for the server to shut down.`,
+	Synopsis:  []string{"[--name {{.LessThan}}service{{.GreaterThan}}]"},
+}
+
+type ServiceStopCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd ServiceStopCmd) Name() string {
+	return "stop"
+}
+
+// Description returns a description of the command
+func (cmd ServiceStopCmd) Description() string {
+	return serviceStopDocs.ShortDesc
+}
+
+func (cmd ServiceStopCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(serviceStopDocs, ap)
+}
+
+func (cmd ServiceStopCmd) ArgParser() *argparser.ArgParser {
+	return serviceNameArgParser(cmd.Name())
+}
+
+// RequiresRepo should return false if this interface is implemented, and the command does not have the requirement
+// that it be run from within a data repository directory
+func (cmd ServiceStopCmd) RequiresRepo() bool {
+	return false
+}
+
+// Exec executes the command
+func (cmd ServiceStopCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	return execServiceAction(commandStr, args, cmd.ArgParser(), serviceStopDocs, "Stopped", stopService)
+}
+
+func serviceNameArgParser(name string) *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(name, 0)
+	ap.SupportsString(serviceNameFlag, "", "service", "The name of the service. Defaults to `"+defaultServiceName+"`.")
+	return ap
+}
+
+// execServiceAction runs |action| on the service named by the arguments given, and reports the result.
+func execServiceAction(commandStr string, args []string, ap *argparser.ArgParser, docs cli.CommandDocumentationContent, done string, action func(name string) error) int {
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, docs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	name := apr.GetValueOrDefault(serviceNameFlag, defaultServiceName)
+	if err := action(name); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error managing service %s", name).AddCause(err).Build(), usage)
+	}
+
+	cli.Printf("%s service %s\n", done, name)
+	return 0
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package sqlserver
+
+import "context"
+
+func runningAsService() bool {
+	return false
+}
+
+func runAsService(ctx context.Context, controller *ServerController, run func(ctx context.Context) int) int {
+	return run(ctx)
+}
+
+func installService(name string, serverArgs []string) error {
+	return errServicesUnsupported
+}
+
+func removeService(name string) error {
+	return errServicesUnsupported
+}
+
+func startService(name string) error {
+	return errServicesUnsupported
+}
+
+func stopService(name string) error {
+	return errServicesUnsupported
+}
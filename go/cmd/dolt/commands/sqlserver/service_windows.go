@@ -0,0 +1,250 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+// +build windows
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+const (
+	serviceEventId     = 1
+	serviceStopTimeout = 30 * time.Second
+)
+
+// runningAsService returns whether this process was started by the Windows service control manager.
+func runningAsService() bool {
+	isService, err := svc.IsWindowsService()
+	return err == nil && isService
+}
+
+// runAsService runs the server with |run| under the service control manager, stopping it through |controller| when
+// the service is stopped. Server logs are written to the event log source registered for the service.
+func runAsService(ctx context.Context, controller *ServerController, run func(ctx context.Context) int) int {
+	handler := &serviceHandler{ctx: ctx, controller: controller, run: run}
+	// The name is ignored for services that run in their own process, which is how sql-server is installed
+	if err := svc.Run(defaultServiceName, handler); err != nil {
+		return 1
+	}
+	return handler.exitCode
+}
+
+// serviceHandler implements svc.Handler for the sql-server.
+type serviceHandler struct {
+	ctx        context.Context
+	controller *ServerController
+	run        func(ctx context.Context) int
+	exitCode   int
+}
+
+// Execute implements svc.Handler.
+func (h *serviceHandler) Execute(args []string, r <-chan svc.ChangeRequest, s chan<- svc.Status) (bool, uint32) {
+	s <- svc.Status{State: svc.StartPending}
+
+	// The first argument is the name the service was started with, which is also the name of its event log source
+	if len(args) > 0 {
+		if elog, err := eventlog.Open(args[0]); err == nil {
+			defer elog.Close()
+			logrus.AddHook(eventLogHook{elog: elog})
+		}
+	}
+
+	done := make(chan int, 1)
+	go func() {
+		done <- h.run(h.ctx)
+	}()
+
+	if err := h.controller.WaitForStart(); err != nil {
+		h.exitCode = <-done
+		return false, uint32(h.exitCode)
+	}
+
+	const accepted = svc.AcceptStop | svc.AcceptShutdown
+	s <- svc.Status{State: svc.Running, Accepts: accepted}
+
+	for {
+		select {
+		case code := <-done:
+			h.exitCode = code
+			return false, uint32(code)
+		case c := <-r:
+			switch c.Cmd {
+			case svc.Interrogate:
+				s <- c.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				s <- svc.Status{State: svc.StopPending}
+				h.controller.StopServer()
+				h.exitCode = <-done
+				return false, uint32(h.exitCode)
+			}
+		}
+	}
+}
+
+// eventLogHook is a logrus hook that writes log entries to the Windows event log.
+type eventLogHook struct {
+	elog *eventlog.Log
+}
+
+// Levels implements logrus.Hook.
+func (h eventLogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.
+func (h eventLogHook) Fire(entry *logrus.Entry) error {
+	msg, err := entry.String()
+	if err != nil {
+		return err
+	}
+
+	switch entry.Level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		return h.elog.Error(serviceEventId, msg)
+	case logrus.WarnLevel:
+		return h.elog.Warning(serviceEventId, msg)
+	default:
+		return h.elog.Info(serviceEventId, msg)
+	}
+}
+
+// installService registers a service named |name| that runs sql-server with |serverArgs|, along with an event log
+// source of the same name.
+func installService(name string, serverArgs []string) error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err == nil {
+		s.Close()
+		return fmt.Errorf("service %s already exists", name)
+	}
+
+	args := append([]string{"sql-server"}, serverArgs...)
+	s, err = m.CreateService(name, exePath, mgr.Config{
+		DisplayName: name,
+		Description: "Dolt SQL server",
+		StartType:   mgr.StartAutomatic,
+	}, args...)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	err = eventlog.InstallAsEventCreate(name, eventlog.Error|eventlog.Warning|eventlog.Info)
+	if err != nil {
+		_ = s.Delete()
+		return fmt.Errorf("error registering event log source: %w", err)
+	}
+	return nil
+}
+
+// removeService stops and deletes the service named |name|, and removes its event log source.
+func removeService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	defer s.Close()
+
+	if status, err := s.Query(); err == nil && status.State != svc.Stopped {
+		if err = controlService(s, svc.Stop, svc.Stopped); err != nil {
+			return err
+		}
+	}
+
+	if err = s.Delete(); err != nil {
+		return err
+	}
+	return eventlog.Remove(name)
+}
+
+// startService starts the service named |name|.
+func startService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	defer s.Close()
+
+	return s.Start()
+}
+
+// stopService stops the service named |name| and waits for it to exit.
+func stopService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	defer s.Close()
+
+	return controlService(s, svc.Stop, svc.Stopped)
+}
+
+// controlService sends |c| to |s| and waits for it to reach state |to|.
+func controlService(s *mgr.Service, c svc.Cmd, to svc.State) error {
+	status, err := s.Control(c)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(serviceStopTimeout)
+	for status.State != to {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for service to reach state %d", to)
+		}
+		time.Sleep(300 * time.Millisecond)
+		if status, err = s.Query(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
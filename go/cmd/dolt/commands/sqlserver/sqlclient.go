@@ -301,12 +301,18 @@ func (cmd SqlClientCmd) Exec(ctx context.Context, commandStr string, args []stri
 			"quit", "exit", "quit()", "exit()",
 		},
 		LineTerminator: ";",
-		MysqlShellCmds: []string{},
+		MysqlShellCmds: commands.MetaCommandTerminators(),
 	}
 
 	shell := ishell.NewUninterpreted(&shellConf)
 	shell.SetMultiPrompt(multilinePrompt)
 
+	// Completions are a convenience, so a server that can't provide them doesn't stop the client
+	if completer, err := commands.NewSqlCompleter(sql.NewContext(ctx), ConnectionQueryist{connection: conn}); err == nil {
+		shell.CustomCompleter(completer)
+	}
+	state := &commands.ShellState{}
+
 	shell.EOF(func(c *ishell.Context) {
 		c.Stop()
 	})
@@ -325,6 +331,16 @@ func (cmd SqlClientCmd) Exec(ctx context.Context, commandStr string, args []stri
 			return
 		}
 
+		metaQuery, isMeta, err := commands.RunMetaCommand(strings.TrimSuffix(query, shell.LineTerminator()), state)
+		if err != nil {
+			shell.Println(color.RedString(err.Error()))
+			return
+		} else if isMeta && metaQuery == "" {
+			return
+		} else if isMeta {
+			query = metaQuery
+		}
+
 		// grab time for query timing
 		startTime := time.Now()
 
@@ -342,7 +358,9 @@ func (cmd SqlClientCmd) Exec(ctx context.Context, commandStr string, args []stri
 			if wrapper.HasMoreRows() {
 				sqlCtx := sql.NewContext(ctx)
 				sqlCtx.SetQueryTime(startTime)
-				err = engine.PrettyPrintResultsExtended(sqlCtx, engine.FormatTabular, wrapper.Schema(), wrapper)
+				err = state.PrintResults(func() error {
+					return engine.PrettyPrintResultsExtended(sqlCtx, engine.FormatTabular, wrapper.Schema(), wrapper)
+				})
 				if err != nil {
 					shell.Println(color.RedString(err.Error()))
 					return
@@ -49,6 +49,8 @@ const (
 	remotesapiPortFlag          = "remotesapi-port"
 	goldenMysqlConn             = "golden"
 	eventSchedulerStatus        = "event-scheduler"
+	memoryFlag                  = "memory"
+	memorySnapshotFlag          = "memory-snapshot"
 )
 
 func indentLines(s string) string {
@@ -80,6 +82,8 @@ SUPPORTED CONFIG FILE FIELDS:
 
 {{.EmphasisLeft}}log_level{{.EmphasisRight}}: Level of logging provided. Options are: {{.EmphasisLeft}}trace{{.EmphasisRight}}, {{.EmphasisLeft}}debug{{.EmphasisRight}}, {{.EmphasisLeft}}info{{.EmphasisRight}}, {{.EmphasisLeft}}warning{{.EmphasisRight}}, {{.EmphasisLeft}}error{{.EmphasisRight}}, and {{.EmphasisLeft}}fatal{{.EmphasisRight}}.
 
+{{.EmphasisLeft}}log_format{{.EmphasisRight}}: Format of the server's log output. Options are: {{.EmphasisLeft}}text{{.EmphasisRight}} and {{.EmphasisLeft}}json{{.EmphasisRight}}, which writes each log entry as a JSON object with consistent fields, such as {{.EmphasisLeft}}db{{.EmphasisRight}}, {{.EmphasisLeft}}branch{{.EmphasisRight}} and {{.EmphasisLeft}}connection_id{{.EmphasisRight}}. Defaults to the global {{.EmphasisLeft}}--log-format{{.EmphasisRight}}, which is {{.EmphasisLeft}}text{{.EmphasisRight}} unless given.
+
 {{.EmphasisLeft}}privilege_file{{.EmphasisRight}}: "Path to a file to load and store users and grants. Defaults to {{.EmphasisLeft}}$doltcfg-dir/privileges.db{{.EmphasisRight}}. Will be created as needed.
 
 {{.EmphasisLeft}}branch_control_file{{.EmphasisRight}}: Path to a file to load and store branch control permissions. Defaults to {{.EmphasisLeft}}$doltcfg-dir/branch_control.db{{.EmphasisRight}}. Will be created as needed.
@@ -106,13 +110,21 @@ SUPPORTED CONFIG FILE FIELDS:
 
 {{.EmphasisLeft}}listener.write_timeout_millis{{.EmphasisRight}}: The number of milliseconds that the server will wait for a write operation
 
+{{.EmphasisLeft}}listener.proxy_protocol{{.EmphasisRight}}: If true, connections must begin with a PROXY protocol (version 1 or 2) header, as sent by HAProxy and other load balancers. The client address in the header is used for logging and host-based grants.
+
+{{.EmphasisLeft}}listeners{{.EmphasisRight}}: A list of listeners that accept connections in addition to the main listener. Each has a {{.EmphasisLeft}}host{{.EmphasisRight}} and {{.EmphasisLeft}}port{{.EmphasisRight}} and / or a unix {{.EmphasisLeft}}socket{{.EmphasisRight}}, and its own {{.EmphasisLeft}}tls_key{{.EmphasisRight}}, {{.EmphasisLeft}}tls_cert{{.EmphasisRight}}, {{.EmphasisLeft}}require_secure_transport{{.EmphasisRight}} and {{.EmphasisLeft}}proxy_protocol{{.EmphasisRight}} settings.
+
 {{.EmphasisLeft}}remotesapi.port{{.EmphasisRight}}: A port to listen for remote API operations on. If set to a positive integer, this server will accept connections from clients to clone, pull, etc. databases being served.
 
 {{.EmphasisLeft}}user_session_vars{{.EmphasisRight}}: A map of user name to a map of session variables to set on connection for each session.
 
 {{.EmphasisLeft}}cluster{{.EmphasisRight}}: Settings related to running this server in a replicated cluster. For information on setting these values, see https://docs.dolthub.com/sql-reference/server/replication
 
-If a config file is not provided many of these settings may be configured on the command line.`,
+If a config file is not provided many of these settings may be configured on the command line.
+
+When run from a systemd unit with {{.EmphasisLeft}}Type=notify{{.EmphasisRight}}, the server notifies systemd once it is accepting connections and when it begins shutting down, and sends watchdog notifications if {{.EmphasisLeft}}WatchdogSec{{.EmphasisRight}} is set. On Windows, use {{.EmphasisLeft}}dolt sql-server-service{{.EmphasisRight}} to install the server as a service.
+
+With {{.EmphasisLeft}}--memory{{.EmphasisRight}} the server keeps all of its state in memory and starts without any databases, which is useful for tests and CI pipelines. Databases are discarded when the server exits unless {{.EmphasisLeft}}--memory-snapshot <directory>{{.EmphasisRight}} is given, in which case each database is written to a subdirectory of that directory on shutdown. A snapshot can be restored with {{.EmphasisLeft}}dolt backup restore file://<directory>/<database> <database>{{.EmphasisRight}}.`,
 	Synopsis: []string{
 		"--config {{.LessThan}}file{{.GreaterThan}}",
 		"[-H {{.LessThan}}host{{.GreaterThan}}] [-P {{.LessThan}}port{{.GreaterThan}}] [-u {{.LessThan}}user{{.GreaterThan}}] [-p {{.LessThan}}password{{.GreaterThan}}] [-t {{.LessThan}}timeout{{.GreaterThan}}] [-l {{.LessThan}}loglevel{{.GreaterThan}}] [--data-dir {{.LessThan}}directory{{.GreaterThan}}] [-r]",
@@ -168,6 +180,8 @@ func (cmd SqlServerCmd) ArgParserWithName(name string) *argparser.ArgParser {
 	ap.SupportsUint(remotesapiPortFlag, "", "remotesapi port", "Sets the port for a server which can expose the databases in this sql-server over remotesapi, so that clients can clone or pull from this server.")
 	ap.SupportsString(goldenMysqlConn, "", "mysql connection string", "Provides a connection string to a MySQL instance to be used to validate query results")
 	ap.SupportsString(eventSchedulerStatus, "", "status", "Determines whether the Event Scheduler is enabled and running on the server. It has one of the following values: 'ON', 'OFF' or 'DISABLED'.")
+	ap.SupportsFlag(memoryFlag, "", "Serve databases that are held entirely in memory. The server starts with no databases, and nothing is written to disk unless `--memory-snapshot` is given.")
+	ap.SupportsString(memorySnapshotFlag, "", "directory", "When used with `--memory`, writes each database to a subdirectory of this directory when the server shuts down. Snapshots can be restored with `dolt backup restore`.")
 	return ap
 }
 
@@ -193,7 +207,13 @@ func (cmd SqlServerCmd) Exec(ctx context.Context, commandStr string, args []stri
 		controller.StopServer()
 		cancelF()
 	}()
-	return startServer(newCtx, cmd.VersionStr, commandStr, args, dEnv, controller)
+	run := func(ctx context.Context) int {
+		return startServer(ctx, cmd.VersionStr, commandStr, args, dEnv, controller)
+	}
+	if runningAsService() {
+		return runAsService(newCtx, controller, run)
+	}
+	return run(newCtx)
 }
 
 func validateSqlServerArgs(apr *argparser.ArgParseResults) error {
@@ -205,6 +225,13 @@ func validateSqlServerArgs(apr *argparser.ArgParseResults) error {
 	if multiDbDir {
 		cli.PrintErrln("WARNING: --multi-db-dir is deprecated, use --data-dir instead")
 	}
+	if apr.Contains(memoryFlag) {
+		if apr.Contains(commands.DataDirFlag) || apr.Contains(commands.MultiDBDirFlag) {
+			return fmt.Errorf("error: --%s cannot be used with --%s", memoryFlag, commands.DataDirFlag)
+		}
+	} else if apr.Contains(memorySnapshotFlag) {
+		return fmt.Errorf("error: --%s requires --%s", memorySnapshotFlag, memoryFlag)
+	}
 	return nil
 }
 
@@ -217,8 +244,12 @@ func startServer(ctx context.Context, versionStr, commandStr string, args []stri
 
 	apr := cli.ParseArgsOrDie(ap, args, help)
 	if err := validateSqlServerArgs(apr); err != nil {
+		cli.PrintErrln(err.Error())
 		return 1
 	}
+	if apr.Contains(memoryFlag) {
+		dEnv = newInMemoryEnv(ctx, dEnv, versionStr)
+	}
 	serverConfig, err := GetServerConfig(dEnv.FS, apr)
 	if err != nil {
 		if serverController != nil {
@@ -282,6 +313,10 @@ func GetServerConfig(cwdFS filesys.Filesys, apr *argparser.ArgParseResults) (Ser
 		yamlCfg.GoldenMysqlConn = &connStr
 	}
 
+	if dir, ok := apr.GetValue(memorySnapshotFlag); ok {
+		yamlCfg.MemorySnapshotDir = &dir
+	}
+
 	return yamlCfg, nil
 }
 
@@ -490,6 +525,10 @@ func getCommandLineConfig(creds *cli.UserPassword, apr *argparser.ArgParseResult
 		config.withGoldenMysqlConnectionString(connStr)
 	}
 
+	if dir, ok := apr.GetValue(memorySnapshotFlag); ok {
+		config.withMemorySnapshotDir(dir)
+	}
+
 	if esStatus, ok := apr.GetValue(eventSchedulerStatus); ok {
 		// make sure to assign eventSchedulerStatus first here
 		config.withEventScheduler(strings.ToUpper(esStatus))
@@ -0,0 +1,110 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// workspaceReapInterval is how often the workspace reaper runs when no clients disconnect, to catch any workspaces
+// whose session ended before the disconnect that signaled a reap.
+const workspaceReapInterval = time.Minute
+
+var _ server.ServerEventListener = (*workspaceReaper)(nil)
+
+// workspaceReaper discards the session workspaces that clients leave behind when they disconnect without publishing
+// or discarding them. It wraps the server's event listener so that it's signaled every time a client disconnects. It
+// also reaps when it starts, which discards the workspaces left behind by a previous run of the server.
+type workspaceReaper struct {
+	server.ServerEventListener
+	sqlEngine *engine.SqlEngine
+	lgr       *logrus.Logger
+
+	signal chan struct{}
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newWorkspaceReaper(listener server.ServerEventListener, sqlEngine *engine.SqlEngine, lgr *logrus.Logger) *workspaceReaper {
+	return &workspaceReaper{
+		ServerEventListener: listener,
+		sqlEngine:           sqlEngine,
+		lgr:                 lgr,
+		signal:              make(chan struct{}, 1),
+		stop:                make(chan struct{}),
+	}
+}
+
+// ClientDisconnected implements server.ServerEventListener
+func (r *workspaceReaper) ClientDisconnected() {
+	r.ServerEventListener.ClientDisconnected()
+	select {
+	case r.signal <- struct{}{}:
+	default:
+	}
+}
+
+// Start starts reaping in the background. It must be called after the running server is set, since workspaces are
+// only reaped when the sessions that are still connected can be listed.
+func (r *workspaceReaper) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(workspaceReapInterval)
+		defer ticker.Stop()
+		for {
+			r.reap()
+			select {
+			case <-r.stop:
+				return
+			case <-r.signal:
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops reaping and waits for any reap in progress to finish.
+func (r *workspaceReaper) Stop() {
+	close(r.stop)
+	r.wg.Wait()
+}
+
+func (r *workspaceReaper) reap() {
+	sqlCtx, err := r.sqlEngine.NewLocalContext(context.Background())
+	if err != nil {
+		r.lgr.Warnf("error reaping orphaned workspaces: %v", err)
+		return
+	}
+
+	for _, db := range r.sqlEngine.Databases(sqlCtx) {
+		if _, rev := dsess.SplitRevisionDbName(db.Name()); rev != "" || db.DbData().Ddb == nil {
+			continue
+		}
+		err = dprocedures.ReapOrphanedWorkspaces(sqlCtx, db.Name(), db.DbData().Ddb)
+		if err != nil {
+			r.lgr.Warnf("error reaping orphaned workspaces of database %s: %v", db.Name(), err)
+		}
+	}
+}
@@ -17,13 +17,19 @@ package sqlserver
 import (
 	"path/filepath"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/commithooks"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/serverhooks"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/webhooks"
+	"github.com/dolthub/dolt/go/libraries/events"
 )
 
 func strPtr(s string) *string {
@@ -37,6 +43,15 @@ func nillableStrPtr(s string) *string {
 	return &s
 }
 
+// logFormatPtr returns a pointer to |format|, or nil for the default text format.
+func logFormatPtr(format cli.LogFormat) *string {
+	if format == cli.LogFormatText {
+		return nil
+	}
+	s := string(format)
+	return &s
+}
+
 func nillableBoolPtr(b bool) *bool {
 	if b == false {
 		return nil
@@ -81,6 +96,13 @@ type BehaviorYAMLConfig struct {
 	DoltTransactionCommit *bool `yaml:"dolt_transaction_commit"`
 
 	EventSchedulerStatus *string `yaml:"event_scheduler,omitempty" minver:"1.17.0"`
+	// AutoUpgradeFormat migrates databases in the old storage format to the current one in the background.
+	AutoUpgradeFormat *bool `yaml:"auto_upgrade_format,omitempty" minver:"1.18.0"`
+	// LeaseLock locks databases with a lease that the server renews, which is required on network filesystems.
+	LeaseLock *bool `yaml:"lease_lock,omitempty" minver:"1.18.0"`
+	// LazyLoadDatabases opens the databases in the data directory's subdirectories the first time they're used, and in
+	// the background, rather than when the server starts.
+	LazyLoadDatabases *bool `yaml:"lazy_load_databases,omitempty" minver:"1.18.0"`
 }
 
 // UserYAMLConfig contains server configuration regarding the user account clients must use to connect
@@ -112,6 +134,35 @@ type ListenerYAMLConfig struct {
 	AllowCleartextPasswords *bool `yaml:"allow_cleartext_passwords"`
 	// Socket is unix socket file path
 	Socket *string `yaml:"socket,omitempty"`
+	// ProxyProtocol expects connections to begin with a PROXY protocol header, as sent by HAProxy and other load
+	// balancers, and uses the client address it contains.
+	ProxyProtocol *bool `yaml:"proxy_protocol,omitempty" minver:"1.18.0"`
+}
+
+// DataDirDiscoveryYAMLConfig configures how databases are discovered in the data directory
+type DataDirDiscoveryYAMLConfig struct {
+	// Depth is how many levels of directories under the data directory are searched for databases.
+	Depth *int `yaml:"depth,omitempty"`
+	// Ignore are patterns of directories under the data directory that aren't searched.
+	Ignore []string `yaml:"ignore,omitempty"`
+	// LoadConcurrency is how many databases are opened at once when the server starts.
+	LoadConcurrency *int `yaml:"load_concurrency,omitempty"`
+}
+
+// AdditionalListenerYAMLConfig configures a listener for SQL connections in addition to the main listener.
+type AdditionalListenerYAMLConfig struct {
+	HostStr    *string `yaml:"host,omitempty"`
+	PortNumber *int    `yaml:"port,omitempty"`
+	// Socket is unix socket file path
+	Socket *string `yaml:"socket,omitempty"`
+	// TLSKey is a file system path to an unencrypted private TLS key in PEM format.
+	TLSKey *string `yaml:"tls_key,omitempty"`
+	// TLSCert is a file system path to a TLS certificate chain in PEM format.
+	TLSCert *string `yaml:"tls_cert,omitempty"`
+	// RequireSecureTransport can enable a mode where non-TLS connections are turned away.
+	RequireSecureTransport *bool `yaml:"require_secure_transport,omitempty"`
+	// ProxyProtocol expects connections to begin with a PROXY protocol header.
+	ProxyProtocol *bool `yaml:"proxy_protocol,omitempty"`
 }
 
 // PerformanceYAMLConfig contains configuration parameters for performance tweaking
@@ -123,6 +174,10 @@ type MetricsYAMLConfig struct {
 	Labels map[string]string `yaml:"labels"`
 	Host   *string           `yaml:"host"`
 	Port   *int              `yaml:"port"`
+	// Emitters are the types of emitters usage events are sent to, overriding the metrics.emitter config
+	Emitters      []string `yaml:"emitters,omitempty" minver:"1.18.0"`
+	StatsdAddress *string  `yaml:"statsd_address,omitempty" minver:"1.18.0"`
+	OtlpEndpoint  *string  `yaml:"otlp_endpoint,omitempty" minver:"1.18.0"`
 }
 
 type RemotesapiYAMLConfig struct {
@@ -141,6 +196,7 @@ type UserSessionVars struct {
 // YAMLConfig is a ServerConfig implementation which is read from a yaml file
 type YAMLConfig struct {
 	LogLevelStr       *string               `yaml:"log_level,omitempty"`
+	LogFormatStr      *string               `yaml:"log_format,omitempty" minver:"1.18.0"`
 	MaxQueryLenInLogs *int                  `yaml:"max_logged_query_len,omitempty"`
 	EncodeLoggedQuery *bool                 `yaml:"encode_logged_query,omitempty"`
 	BehaviorConfig    BehaviorYAMLConfig    `yaml:"behavior"`
@@ -155,14 +211,22 @@ type YAMLConfig struct {
 	PrivilegeFile     *string               `yaml:"privilege_file,omitempty"`
 	BranchControlFile *string               `yaml:"branch_control_file,omitempty"`
 	// TODO: Rename to UserVars_
-	Vars            []UserSessionVars       `yaml:"user_session_vars"`
-	SystemVars_     *engine.SystemVariables `yaml:"system_variables,omitempty" minver:"1.11.1"`
-	Jwks            []engine.JwksConfig     `yaml:"jwks"`
-	GoldenMysqlConn *string                 `yaml:"golden_mysql_conn,omitempty"`
+	Vars              []UserSessionVars              `yaml:"user_session_vars"`
+	SystemVars_       *engine.SystemVariables        `yaml:"system_variables,omitempty" minver:"1.11.1"`
+	Jwks              []engine.JwksConfig            `yaml:"jwks"`
+	GoldenMysqlConn   *string                        `yaml:"golden_mysql_conn,omitempty"`
+	Hooks_            *serverhooks.Config            `yaml:"hooks,omitempty" minver:"1.18.0"`
+	Webhooks_         []webhooks.Config              `yaml:"webhooks,omitempty" minver:"1.18.0"`
+	CommitHooks_      []commithooks.Config           `yaml:"commit_hooks,omitempty" minver:"1.18.0"`
+	Listeners_        []AdditionalListenerYAMLConfig `yaml:"listeners,omitempty" minver:"1.18.0"`
+	DataDirDiscovery_ *DataDirDiscoveryYAMLConfig    `yaml:"data_dir_discovery,omitempty" minver:"1.18.0"`
+	// MemorySnapshotDir is only set from the command line, for servers started with --memory
+	MemorySnapshotDir *string `yaml:"-"`
 }
 
 var _ ServerConfig = YAMLConfig{}
 var _ validatingServerConfig = YAMLConfig{}
+var _ snapshottingServerConfig = YAMLConfig{}
 
 func NewYamlConfig(configFileData []byte) (YAMLConfig, error) {
 	var cfg YAMLConfig
@@ -171,12 +235,17 @@ func NewYamlConfig(configFileData []byte) (YAMLConfig, error) {
 		loglevel := strings.ToLower(*cfg.LogLevelStr)
 		cfg.LogLevelStr = &loglevel
 	}
+	if cfg.LogFormatStr != nil {
+		logFormat := strings.ToLower(*cfg.LogFormatStr)
+		cfg.LogFormatStr = &logFormat
+	}
 	return cfg, err
 }
 
 func serverConfigAsYAMLConfig(cfg ServerConfig) YAMLConfig {
 	return YAMLConfig{
 		LogLevelStr:       strPtr(string(cfg.LogLevel())),
+		LogFormatStr:      logFormatPtr(cfg.LogFormat()),
 		MaxQueryLenInLogs: nillableIntPtr(cfg.MaxLoggedQueryLen()),
 		EncodeLoggedQuery: nillableBoolPtr(cfg.ShouldEncodeLoggedQuery()),
 		BehaviorConfig: BehaviorYAMLConfig{
@@ -186,6 +255,9 @@ func serverConfigAsYAMLConfig(cfg ServerConfig) YAMLConfig {
 			boolPtr(cfg.DisableClientMultiStatements()),
 			boolPtr(cfg.DoltTransactionCommit()),
 			strPtr(cfg.EventSchedulerStatus()),
+			nillableBoolPtr(cfg.AutoUpgradeFormat()),
+			nillableBoolPtr(cfg.LeaseLock()),
+			nillableBoolPtr(cfg.LazyLoadDatabases()),
 		},
 		UserConfig: UserYAMLConfig{
 			Name:     strPtr(cfg.User()),
@@ -202,6 +274,7 @@ func serverConfigAsYAMLConfig(cfg ServerConfig) YAMLConfig {
 			nillableBoolPtr(cfg.RequireSecureTransport()),
 			nillableBoolPtr(cfg.AllowCleartextPasswords()),
 			nillableStrPtr(cfg.Socket()),
+			nillableBoolPtr(cfg.ProxyProtocol()),
 		},
 		PerformanceConfig: PerformanceYAMLConfig{
 			QueryParallelism: nillableIntPtr(cfg.QueryParallelism()),
@@ -221,6 +294,9 @@ func serverConfigAsYAMLConfig(cfg ServerConfig) YAMLConfig {
 		BranchControlFile: strPtr(cfg.BranchControlFilePath()),
 		Vars:              cfg.UserVars(),
 		Jwks:              cfg.JwksConfig(),
+		Hooks_:            cfg.Hooks(),
+		Webhooks_:         cfg.Webhooks(),
+		CommitHooks_:      cfg.CommitHooks(),
 	}
 }
 
@@ -241,7 +317,12 @@ func clusterConfigAsYAMLConfig(config cluster.Config) *ClusterYAMLConfig {
 			TLSCA_:     config.RemotesAPIConfig().TLSCA(),
 			URLMatches: config.RemotesAPIConfig().ServerNameURLMatches(),
 			DNSMatches: config.RemotesAPIConfig().ServerNameDNSMatches(),
+
+			TLSClientCert_: config.RemotesAPIConfig().TLSClientCert(),
+			TLSClientKey_:  config.RemotesAPIConfig().TLSClientKey(),
+			TLSClientCA_:   config.RemotesAPIConfig().TLSClientCA(),
 		},
+		RequiredAcks_: config.RequiredAcks(),
 	}
 }
 
@@ -359,6 +440,36 @@ func (cfg YAMLConfig) DoltTransactionCommit() bool {
 	return *cfg.BehaviorConfig.DoltTransactionCommit
 }
 
+// AutoUpgradeFormat is true if the server migrates databases in the old storage format to the current one in the
+// background.
+func (cfg YAMLConfig) AutoUpgradeFormat() bool {
+	if cfg.BehaviorConfig.AutoUpgradeFormat == nil {
+		return false
+	}
+
+	return *cfg.BehaviorConfig.AutoUpgradeFormat
+}
+
+// LeaseLock is true if the server locks its databases with a lease that it renews while it runs, rather than with its
+// pid, so that servers on other hosts respect the lock.
+func (cfg YAMLConfig) LeaseLock() bool {
+	if cfg.BehaviorConfig.LeaseLock == nil {
+		return false
+	}
+
+	return *cfg.BehaviorConfig.LeaseLock
+}
+
+// LazyLoadDatabases is true if the server opens the databases in its data directory's subdirectories the first time
+// they're used, and in the background, rather than when it starts.
+func (cfg YAMLConfig) LazyLoadDatabases() bool {
+	if cfg.BehaviorConfig.LazyLoadDatabases == nil {
+		return false
+	}
+
+	return *cfg.BehaviorConfig.LazyLoadDatabases
+}
+
 // LogLevel returns the level of logging that the server will use.
 func (cfg YAMLConfig) LogLevel() LogLevel {
 	if cfg.LogLevelStr == nil {
@@ -368,6 +479,16 @@ func (cfg YAMLConfig) LogLevel() LogLevel {
 	return LogLevel(*cfg.LogLevelStr)
 }
 
+// LogFormat returns the format of the server's log output. Defaults to the format given by the global --log-format
+// flag.
+func (cfg YAMLConfig) LogFormat() cli.LogFormat {
+	if cfg.LogFormatStr == nil {
+		return cli.GetLogFormat()
+	}
+
+	return cli.LogFormat(*cfg.LogFormatStr)
+}
+
 // MaxConnections returns the maximum number of simultaneous connections the server will allow.  The default is 1
 func (cfg YAMLConfig) MaxConnections() uint64 {
 	if cfg.ListenerConfig.MaxConnections == nil {
@@ -409,6 +530,28 @@ func (cfg YAMLConfig) MetricsPort() int {
 	return *cfg.MetricsConfig.Port
 }
 
+// eventEmitterConfig returns the configuration of the emitters usage events are sent to, or false if the config file
+// doesn't configure any.
+func (cfg YAMLConfig) eventEmitterConfig() (events.EmitterConfig, bool) {
+	if len(cfg.MetricsConfig.Emitters) == 0 {
+		return events.EmitterConfig{}, false
+	}
+
+	emitterCfg := events.EmitterConfig{
+		Types:         events.ParseEmitterTypes(strings.Join(cfg.MetricsConfig.Emitters, ",")),
+		StatsdAddress: events.DefaultStatsdAddress,
+		OtlpEndpoint:  events.DefaultOtlpEndpoint,
+		Labels:        cfg.MetricsConfig.Labels,
+	}
+	if cfg.MetricsConfig.StatsdAddress != nil {
+		emitterCfg.StatsdAddress = *cfg.MetricsConfig.StatsdAddress
+	}
+	if cfg.MetricsConfig.OtlpEndpoint != nil {
+		emitterCfg.OtlpEndpoint = *cfg.MetricsConfig.OtlpEndpoint
+	}
+	return emitterCfg, true
+}
+
 func (cfg YAMLConfig) RemotesapiPort() *int {
 	return cfg.RemotesapiConfig.Port_
 }
@@ -530,6 +673,30 @@ func (cfg YAMLConfig) DataDir() string {
 	return defaultDataDir
 }
 
+// DataDirDiscoveryDepth is how many levels of directories under the data dir are searched for databases.
+func (cfg YAMLConfig) DataDirDiscoveryDepth() int {
+	if cfg.DataDirDiscovery_ == nil || cfg.DataDirDiscovery_.Depth == nil {
+		return defaultDataDirDiscoveryDepth
+	}
+	return *cfg.DataDirDiscovery_.Depth
+}
+
+// DataDirDiscoveryIgnore returns the patterns of directories under the data dir that aren't searched for databases.
+func (cfg YAMLConfig) DataDirDiscoveryIgnore() []string {
+	if cfg.DataDirDiscovery_ == nil {
+		return nil
+	}
+	return cfg.DataDirDiscovery_.Ignore
+}
+
+// DataDirLoadConcurrency is how many of the databases in the data dir are opened at once when the server starts.
+func (cfg YAMLConfig) DataDirLoadConcurrency() int {
+	if cfg.DataDirDiscovery_ == nil || cfg.DataDirDiscovery_.LoadConcurrency == nil {
+		return defaultDataDirLoadConcurrency
+	}
+	return *cfg.DataDirDiscovery_.LoadConcurrency
+}
+
 // CfgDir is the path to a directory to use to store the dolt configuration files.
 func (cfg YAMLConfig) CfgDir() string {
 	if cfg.CfgDirStr != nil {
@@ -557,6 +724,13 @@ func (cfg YAMLConfig) goldenMysqlConnectionString() (s string) {
 	return
 }
 
+func (cfg YAMLConfig) memorySnapshotDir() (s string) {
+	if cfg.MemorySnapshotDir != nil {
+		s = *cfg.MemorySnapshotDir
+	}
+	return
+}
+
 func (cfg YAMLConfig) ClusterConfig() cluster.Config {
 	if cfg.ClusterCfg == nil {
 		return nil
@@ -564,6 +738,63 @@ func (cfg YAMLConfig) ClusterConfig() cluster.Config {
 	return cfg.ClusterCfg
 }
 
+// Hooks returns the server hooks run on commit, merge and push.
+func (cfg YAMLConfig) Hooks() *serverhooks.Config {
+	return cfg.Hooks_
+}
+
+// Webhooks returns the webhooks fired when branches and tags change.
+func (cfg YAMLConfig) Webhooks() []webhooks.Config {
+	return cfg.Webhooks_
+}
+
+// CommitHooks returns the user-defined commit hooks run when branches get new commits.
+func (cfg YAMLConfig) CommitHooks() []commithooks.Config {
+	return cfg.CommitHooks_
+}
+
+// ProxyProtocol is true if connections to the main listener begin with a PROXY protocol header.
+func (cfg YAMLConfig) ProxyProtocol() bool {
+	if cfg.ListenerConfig.ProxyProtocol == nil {
+		return false
+	}
+	return *cfg.ListenerConfig.ProxyProtocol
+}
+
+// AdditionalListeners returns the listeners that accept SQL connections in addition to the main listener.
+func (cfg YAMLConfig) AdditionalListeners() []ListenerConfig {
+	if len(cfg.Listeners_) == 0 {
+		return nil
+	}
+
+	listeners := make([]ListenerConfig, len(cfg.Listeners_))
+	for i, l := range cfg.Listeners_ {
+		listeners[i] = ListenerConfig{Host: defaultHost}
+		if l.HostStr != nil {
+			listeners[i].Host = *l.HostStr
+		}
+		if l.PortNumber != nil {
+			listeners[i].Port = *l.PortNumber
+		}
+		if l.Socket != nil {
+			listeners[i].Socket = *l.Socket
+		}
+		if l.TLSKey != nil {
+			listeners[i].TLSKey = *l.TLSKey
+		}
+		if l.TLSCert != nil {
+			listeners[i].TLSCert = *l.TLSCert
+		}
+		if l.RequireSecureTransport != nil {
+			listeners[i].RequireSecureTransport = *l.RequireSecureTransport
+		}
+		if l.ProxyProtocol != nil {
+			listeners[i].ProxyProtocol = *l.ProxyProtocol
+		}
+	}
+	return listeners
+}
+
 func (cfg YAMLConfig) EventSchedulerStatus() string {
 	if cfg.BehaviorConfig.EventSchedulerStatus == nil {
 		return "ON"
@@ -583,11 +814,35 @@ type ClusterYAMLConfig struct {
 	BootstrapRole_  string                      `yaml:"bootstrap_role"`
 	BootstrapEpoch_ int                         `yaml:"bootstrap_epoch"`
 	RemotesAPI      ClusterRemotesAPIYAMLConfig `yaml:"remotesapi"`
+	// ReadRouting_ configures a listener on the primary that routes connections for reads to caught-up standbys.
+	ReadRouting_ *ClusterReadRoutingYAMLConfig `yaml:"read_routing,omitempty" minver:"1.18.0"`
+	// ReplicationBackoff_ configures how standby replication retries after it fails.
+	ReplicationBackoff_ *ClusterBackoffYAMLConfig `yaml:"replication_backoff,omitempty" minver:"1.18.0"`
+	// ReplicationThrottle_ limits the bandwidth standby replication uses.
+	ReplicationThrottle_ *ClusterThrottleYAMLConfig `yaml:"replication_throttle,omitempty" minver:"1.18.0"`
+	// ReplicationHeartbeat_ configures the heartbeats the primary sends to standbys it's caught up with.
+	ReplicationHeartbeat_ *ClusterHeartbeatYAMLConfig `yaml:"replication_heartbeat,omitempty" minver:"1.18.0"`
+	// ReplicationCircuitBreaker_ configures when commits stop waiting for standbys that aren't acking them.
+	ReplicationCircuitBreaker_ *ClusterCircuitBreakerYAMLConfig `yaml:"replication_circuit_breaker,omitempty" minver:"1.18.0"`
+	// AutomaticFailover_ enables electing a standby primary when the primary stops replicating to it.
+	AutomaticFailover_ *ClusterFailoverYAMLConfig `yaml:"automatic_failover,omitempty" minver:"1.18.0"`
+	// RequiredAcks_ is the number of standbys a commit has to be replicated to before it returns. If it's unset,
+	// commits wait for every standby.
+	RequiredAcks_ int `yaml:"required_acks,omitempty" minver:"1.18.0"`
+	// StandbyBootstrapURLTemplate_ is the URL of a backup of each database, which a standby restores a database from
+	// when the primary first replicates it.
+	StandbyBootstrapURLTemplate_ string `yaml:"standby_bootstrap_url_template,omitempty" minver:"1.18.0"`
 }
 
 type StandbyRemoteYAMLConfig struct {
 	Name_              string `yaml:"name"`
 	RemoteURLTemplate_ string `yaml:"remote_url_template"`
+	// SQLAddress_ is the host:port of the standby's sql-server, which is required to route reads to it.
+	SQLAddress_ string `yaml:"sql_address,omitempty" minver:"1.18.0"`
+}
+
+func (c StandbyRemoteYAMLConfig) SQLAddress() string {
+	return c.SQLAddress_
 }
 
 func (c StandbyRemoteYAMLConfig) Name() string {
@@ -618,6 +873,205 @@ func (c *ClusterYAMLConfig) RemotesAPIConfig() cluster.RemotesAPIConfig {
 	return c.RemotesAPI
 }
 
+func (c *ClusterYAMLConfig) ReadRoutingConfig() cluster.ReadRoutingConfig {
+	if c.ReadRouting_ == nil {
+		return nil
+	}
+	return c.ReadRouting_
+}
+
+func (c *ClusterYAMLConfig) ReplicationBackoffConfig() cluster.BackoffConfig {
+	if c.ReplicationBackoff_ == nil {
+		return nil
+	}
+	return c.ReplicationBackoff_
+}
+
+func (c *ClusterYAMLConfig) ReplicationThrottleConfig() cluster.ThrottleConfig {
+	if c.ReplicationThrottle_ == nil {
+		return nil
+	}
+	return c.ReplicationThrottle_
+}
+
+func (c *ClusterYAMLConfig) ReplicationHeartbeatConfig() cluster.HeartbeatConfig {
+	if c.ReplicationHeartbeat_ == nil {
+		return nil
+	}
+	return c.ReplicationHeartbeat_
+}
+
+// ClusterHeartbeatYAMLConfig configures the heartbeats the primary sends to standbys it's caught up with. Unset fields
+// take their defaults, which heartbeat every second, time out after five seconds, and report a standby as unreachable
+// after three heartbeats fail in a row.
+type ClusterHeartbeatYAMLConfig struct {
+	IntervalMillis_   *int `yaml:"interval_millis,omitempty"`
+	TimeoutMillis_    *int `yaml:"timeout_millis,omitempty"`
+	FailureThreshold_ *int `yaml:"failure_threshold,omitempty"`
+}
+
+func (c *ClusterHeartbeatYAMLConfig) Interval() time.Duration {
+	if c.IntervalMillis_ == nil {
+		return cluster.DefaultHeartbeatInterval
+	}
+	return time.Duration(*c.IntervalMillis_) * time.Millisecond
+}
+
+func (c *ClusterHeartbeatYAMLConfig) Timeout() time.Duration {
+	if c.TimeoutMillis_ == nil {
+		return cluster.DefaultHeartbeatTimeout
+	}
+	return time.Duration(*c.TimeoutMillis_) * time.Millisecond
+}
+
+func (c *ClusterHeartbeatYAMLConfig) FailureThreshold() int {
+	if c.FailureThreshold_ == nil {
+		return cluster.DefaultHeartbeatFailureThreshold
+	}
+	return *c.FailureThreshold_
+}
+
+func (c *ClusterYAMLConfig) ReplicationCircuitBreakerConfig() cluster.CircuitBreakerConfig {
+	if c.ReplicationCircuitBreaker_ == nil {
+		return nil
+	}
+	return c.ReplicationCircuitBreaker_
+}
+
+// ClusterCircuitBreakerYAMLConfig configures the circuit breaker of the replication to each standby. Unset fields take
+// their defaults, which open the breaker after one commit times out, and keep it open until the standby catches up.
+type ClusterCircuitBreakerYAMLConfig struct {
+	FailureThreshold_    *int `yaml:"failure_threshold,omitempty"`
+	ProbeIntervalMillis_ int  `yaml:"probe_interval_millis,omitempty"`
+}
+
+func (c *ClusterCircuitBreakerYAMLConfig) FailureThreshold() int {
+	if c.FailureThreshold_ == nil {
+		return cluster.DefaultCircuitBreakerFailureThreshold
+	}
+	return *c.FailureThreshold_
+}
+
+func (c *ClusterCircuitBreakerYAMLConfig) ProbeInterval() time.Duration {
+	return time.Duration(c.ProbeIntervalMillis_) * time.Millisecond
+}
+
+// ClusterThrottleYAMLConfig limits the bandwidth standby replication uses. Unset fields don't limit it.
+type ClusterThrottleYAMLConfig struct {
+	MaxBytesPerSec_ int64 `yaml:"max_bytes_per_sec,omitempty"`
+	ChunksPerBatch_ int   `yaml:"chunks_per_batch,omitempty"`
+}
+
+func (c *ClusterThrottleYAMLConfig) MaxBytesPerSec() int64 {
+	return c.MaxBytesPerSec_
+}
+
+func (c *ClusterThrottleYAMLConfig) ChunksPerBatch() int {
+	return c.ChunksPerBatch_
+}
+
+func (c *ClusterYAMLConfig) AutomaticFailoverConfig() cluster.FailoverConfig {
+	if c.AutomaticFailover_ == nil {
+		return nil
+	}
+	return c.AutomaticFailover_
+}
+
+// ClusterFailoverYAMLConfig configures automatic failover. An unset lease_duration_millis takes the default of ten
+// seconds.
+type ClusterFailoverYAMLConfig struct {
+	LeaseDurationMillis_ *int `yaml:"lease_duration_millis,omitempty"`
+	Priority_            int  `yaml:"priority,omitempty"`
+}
+
+func (c *ClusterFailoverYAMLConfig) LeaseDuration() time.Duration {
+	if c.LeaseDurationMillis_ == nil {
+		return cluster.DefaultFailoverLeaseDuration
+	}
+	return time.Duration(*c.LeaseDurationMillis_) * time.Millisecond
+}
+
+func (c *ClusterFailoverYAMLConfig) Priority() int {
+	return c.Priority_
+}
+
+func (c *ClusterYAMLConfig) RequiredAcks() int {
+	return c.RequiredAcks_
+}
+
+func (c *ClusterYAMLConfig) StandbyBootstrapURLTemplate() string {
+	return c.StandbyBootstrapURLTemplate_
+}
+
+// ClusterBackoffYAMLConfig is an exponential backoff policy. Unset fields take their defaults, which retry every
+// second.
+type ClusterBackoffYAMLConfig struct {
+	InitialIntervalMillis_ *int     `yaml:"initial_interval_millis,omitempty"`
+	Multiplier_            *float64 `yaml:"multiplier,omitempty"`
+	MaxIntervalMillis_     *int     `yaml:"max_interval_millis,omitempty"`
+	Jitter_                *float64 `yaml:"jitter,omitempty"`
+}
+
+func (c *ClusterBackoffYAMLConfig) InitialInterval() time.Duration {
+	if c.InitialIntervalMillis_ == nil {
+		return cluster.DefaultBackoffInitialInterval
+	}
+	return time.Duration(*c.InitialIntervalMillis_) * time.Millisecond
+}
+
+func (c *ClusterBackoffYAMLConfig) Multiplier() float64 {
+	if c.Multiplier_ == nil {
+		return cluster.DefaultBackoffMultiplier
+	}
+	return *c.Multiplier_
+}
+
+func (c *ClusterBackoffYAMLConfig) MaxInterval() time.Duration {
+	if c.MaxIntervalMillis_ == nil {
+		// a policy that only sets its initial interval never waits less than it
+		if c.InitialInterval() > cluster.DefaultBackoffMaxInterval {
+			return c.InitialInterval()
+		}
+		return cluster.DefaultBackoffMaxInterval
+	}
+	return time.Duration(*c.MaxIntervalMillis_) * time.Millisecond
+}
+
+func (c *ClusterBackoffYAMLConfig) Jitter() float64 {
+	if c.Jitter_ == nil {
+		return cluster.DefaultBackoffJitter
+	}
+	return *c.Jitter_
+}
+
+type ClusterReadRoutingYAMLConfig struct {
+	Addr_         string `yaml:"address"`
+	Port_         int    `yaml:"port"`
+	MaxLagMillis_ *int   `yaml:"max_lag_millis,omitempty"`
+	// ProxyProtocol_ sends a PROXY protocol header on the connections routed to standbys, whose listeners at their
+	// sql_address must then have proxy_protocol enabled.
+	ProxyProtocol_ *bool `yaml:"proxy_protocol,omitempty"`
+}
+
+func (c *ClusterReadRoutingYAMLConfig) Address() string {
+	return c.Addr_
+}
+
+func (c *ClusterReadRoutingYAMLConfig) Port() int {
+	return c.Port_
+}
+
+func (c *ClusterReadRoutingYAMLConfig) MaxLag() time.Duration {
+	if c.MaxLagMillis_ == nil {
+		return defaultReadRoutingMaxLag
+	}
+	return time.Duration(*c.MaxLagMillis_) * time.Millisecond
+}
+
+func (c *ClusterReadRoutingYAMLConfig) ProxyProtocol() bool {
+	return c.ProxyProtocol_ != nil && *c.ProxyProtocol_
+}
+
 type ClusterRemotesAPIYAMLConfig struct {
 	Addr_      string   `yaml:"address"`
 	Port_      int      `yaml:"port"`
@@ -626,6 +1080,12 @@ type ClusterRemotesAPIYAMLConfig struct {
 	TLSCA_     string   `yaml:"tls_ca"`
 	URLMatches []string `yaml:"server_name_urls"`
 	DNSMatches []string `yaml:"server_name_dns"`
+	// TLSClientCert_ and TLSClientKey_ are the certificate and key presented to standbys when replicating to them.
+	TLSClientCert_ string `yaml:"tls_client_cert,omitempty" minver:"1.18.0"`
+	TLSClientKey_  string `yaml:"tls_client_key,omitempty" minver:"1.18.0"`
+	// TLSClientCA_ is the CA bundle that client certificates are verified against. If it's set, clients must present
+	// a certificate signed by one of its CAs.
+	TLSClientCA_ string `yaml:"tls_client_ca,omitempty" minver:"1.18.0"`
 }
 
 func (c ClusterRemotesAPIYAMLConfig) Address() string {
@@ -648,6 +1108,18 @@ func (c ClusterRemotesAPIYAMLConfig) TLSCA() string {
 	return c.TLSCA_
 }
 
+func (c ClusterRemotesAPIYAMLConfig) TLSClientCert() string {
+	return c.TLSClientCert_
+}
+
+func (c ClusterRemotesAPIYAMLConfig) TLSClientKey() string {
+	return c.TLSClientKey_
+}
+
+func (c ClusterRemotesAPIYAMLConfig) TLSClientCA() string {
+	return c.TLSClientCA_
+}
+
 func (c ClusterRemotesAPIYAMLConfig) ServerNameURLMatches() []string {
 	return c.URLMatches
 }
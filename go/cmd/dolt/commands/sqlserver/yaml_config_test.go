@@ -15,13 +15,24 @@
 package sqlserver
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/commithooks"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/serverhooks"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/webhooks"
+	"github.com/dolthub/dolt/go/libraries/events"
 )
 
 var trueValue = true
@@ -159,6 +170,214 @@ remotesapi:
 	require.Equal(t, 8000, *config.RemotesapiPort())
 }
 
+func TestUnmarshallMetricsEmitters(t *testing.T) {
+	testStr := `
+metrics:
+  labels:
+    env: test
+  emitters: [file, otlp]
+  otlp_endpoint: http://collector:4318/v1/metrics
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	emitterCfg, ok := config.eventEmitterConfig()
+	require.True(t, ok)
+	assert.Equal(t, events.EmitterConfig{
+		Types:         []string{events.EmitterTypeFile, events.EmitterTypeOtlp},
+		StatsdAddress: events.DefaultStatsdAddress,
+		OtlpEndpoint:  "http://collector:4318/v1/metrics",
+		Labels:        map[string]string{"env": "test"},
+	}, emitterCfg)
+
+	config, err = NewYamlConfig([]byte("metrics:\n  host: localhost\n"))
+	require.NoError(t, err)
+	_, ok = config.eventEmitterConfig()
+	assert.False(t, ok)
+}
+
+func TestUnmarshallHooks(t *testing.T) {
+	testStr := `
+hooks:
+  pre_commit:
+  - command: [/usr/local/bin/check_commit, --strict]
+  - procedure: check_quality
+    user: hooks
+  post_receive:
+  - command: [/usr/local/bin/notify]
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NoError(t, ValidateConfig(config))
+	require.Equal(t, &serverhooks.Config{
+		PreCommit: []serverhooks.Hook{
+			{Command: []string{"/usr/local/bin/check_commit", "--strict"}},
+			{Procedure: "check_quality", User: "hooks"},
+		},
+		PostReceive: []serverhooks.Hook{
+			{Command: []string{"/usr/local/bin/notify"}},
+		},
+	}, config.Hooks())
+
+	testStr = `
+hooks:
+  pre_merge:
+  - command: [/usr/local/bin/check_merge]
+    procedure: check_merge
+`
+	config, err = NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.Error(t, ValidateConfig(config))
+}
+
+func TestUnmarshallCommitHooks(t *testing.T) {
+	testStr := `
+commit_hooks:
+- name: notify
+  command: [/usr/local/bin/notify]
+  databases: [mydb]
+  branches: [main, release/*]
+  mode: sync
+- name: index
+  grpc_endpoint: indexer:50051
+  on_failure: retry
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NoError(t, ValidateConfig(config))
+	require.Equal(t, []commithooks.Config{
+		{
+			Name:      "notify",
+			Command:   []string{"/usr/local/bin/notify"},
+			Databases: []string{"mydb"},
+			Branches:  []string{"main", "release/*"},
+			Mode:      commithooks.Sync,
+		},
+		{
+			Name:         "index",
+			GrpcEndpoint: "indexer:50051",
+			OnFailure:    commithooks.FailureRetry,
+		},
+	}, config.CommitHooks())
+
+	config, err = NewYamlConfig([]byte("commit_hooks:\n- name: notify\n  mode: later\n  command: [notify]\n"))
+	require.NoError(t, err)
+	require.Error(t, ValidateConfig(config))
+}
+
+func TestUnmarshallWebhooks(t *testing.T) {
+	testStr := `
+webhooks:
+- url: https://ci.example.com/dolt
+  events: [branch_created, branch_updated]
+  databases: [mydb]
+  secret: s3cr3t
+  max_attempts: 3
+- url: https://chat.example.com/hook
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NoError(t, ValidateConfig(config))
+	attempts := 3
+	require.Equal(t, []webhooks.Config{
+		{
+			URL:         "https://ci.example.com/dolt",
+			Events:      []webhooks.Event{webhooks.BranchCreated, webhooks.BranchUpdated},
+			Databases:   []string{"mydb"},
+			Secret:      "s3cr3t",
+			MaxAttempts: &attempts,
+		},
+		{
+			URL: "https://chat.example.com/hook",
+		},
+	}, config.Webhooks())
+
+	config, err = NewYamlConfig([]byte("webhooks:\n- url: https://ci.example.com/dolt\n  events: [branch_renamed]\n"))
+	require.NoError(t, err)
+	require.Error(t, ValidateConfig(config))
+}
+
+func TestUnmarshallAutoUpgradeFormat(t *testing.T) {
+	config, err := NewYamlConfig([]byte("behavior:\n  auto_upgrade_format: true\n"))
+	require.NoError(t, err)
+	require.NoError(t, ValidateConfig(config))
+	require.True(t, config.AutoUpgradeFormat())
+
+	config, err = NewYamlConfig([]byte("behavior:\n  read_only: true\n"))
+	require.NoError(t, err)
+	require.False(t, config.AutoUpgradeFormat())
+
+	testStr := `
+behavior:
+  auto_upgrade_format: true
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://doltdb-1.doltdb:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+`
+	config, err = NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.Error(t, ValidateConfig(config))
+}
+
+func TestUnmarshallLeaseLock(t *testing.T) {
+	config, err := NewYamlConfig([]byte("behavior:\n  lease_lock: true\n"))
+	require.NoError(t, err)
+	require.NoError(t, ValidateConfig(config))
+	require.True(t, config.LeaseLock())
+	require.True(t, serverConfigAsYAMLConfig(config).LeaseLock())
+
+	config, err = NewYamlConfig([]byte("behavior:\n  read_only: true\n"))
+	require.NoError(t, err)
+	require.False(t, config.LeaseLock())
+}
+
+func TestUnmarshallLazyLoadDatabases(t *testing.T) {
+	config, err := NewYamlConfig([]byte("behavior:\n  lazy_load_databases: true\n"))
+	require.NoError(t, err)
+	require.NoError(t, ValidateConfig(config))
+	require.True(t, config.LazyLoadDatabases())
+	require.True(t, serverConfigAsYAMLConfig(config).LazyLoadDatabases())
+
+	config, err = NewYamlConfig([]byte("behavior:\n  read_only: true\n"))
+	require.NoError(t, err)
+	require.False(t, config.LazyLoadDatabases())
+}
+
+func TestUnmarshallDataDirDiscovery(t *testing.T) {
+	testStr := `
+data_dir_discovery:
+  depth: 3
+  ignore:
+  - archive
+  - team/old*
+  load_concurrency: 32
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NoError(t, ValidateConfig(config))
+	assert.Equal(t, 3, config.DataDirDiscoveryDepth())
+	assert.Equal(t, []string{"archive", "team/old*"}, config.DataDirDiscoveryIgnore())
+	assert.Equal(t, 32, config.DataDirLoadConcurrency())
+
+	config, err = NewYamlConfig([]byte("data_dir: .\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, config.DataDirDiscoveryDepth())
+	assert.Nil(t, config.DataDirDiscoveryIgnore())
+	assert.Equal(t, defaultDataDirLoadConcurrency, config.DataDirLoadConcurrency())
+
+	config, err = NewYamlConfig([]byte("data_dir_discovery:\n  load_concurrency: 0\n"))
+	require.NoError(t, err)
+	require.Error(t, ValidateConfig(config))
+
+	config, err = NewYamlConfig([]byte("data_dir_discovery:\n  depth: 0\n"))
+	require.NoError(t, err)
+	require.Error(t, ValidateConfig(config))
+}
+
 func TestUnmarshallCluster(t *testing.T) {
 	testStr := `
 cluster:
@@ -180,6 +399,234 @@ cluster:
 	require.Equal(t, 0, config.ClusterConfig().BootstrapEpoch())
 	require.Equal(t, "standby", config.ClusterConfig().StandbyRemotes()[0].Name())
 	require.Equal(t, "http://doltdb-1.doltdb:50051/{database}", config.ClusterConfig().StandbyRemotes()[0].RemoteURLTemplate())
+	require.Equal(t, "", config.ClusterConfig().StandbyRemotes()[0].SQLAddress())
+	require.Nil(t, config.ClusterConfig().ReadRoutingConfig())
+}
+
+func TestUnmarshallClusterReadRouting(t *testing.T) {
+	testStr := `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://doltdb-1.doltdb:50051/{database}
+    sql_address: doltdb-1.doltdb:3306
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  read_routing:
+    port: 3307
+    max_lag_millis: 250
+    proxy_protocol: true
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NoError(t, ValidateClusterConfig(config.ClusterConfig()))
+	require.Equal(t, "doltdb-1.doltdb:3306", config.ClusterConfig().StandbyRemotes()[0].SQLAddress())
+	readRouting := config.ClusterConfig().ReadRoutingConfig()
+	require.NotNil(t, readRouting)
+	require.Equal(t, "", readRouting.Address())
+	require.Equal(t, 3307, readRouting.Port())
+	require.Equal(t, 250*time.Millisecond, readRouting.MaxLag())
+	require.True(t, readRouting.ProxyProtocol())
+}
+
+func TestUnmarshallClusterReplicationBackoff(t *testing.T) {
+	testStr := `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://doltdb-1.doltdb:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  replication_backoff:
+    initial_interval_millis: 500
+    multiplier: 2
+    max_interval_millis: 30000
+    jitter: 0.2
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NoError(t, ValidateClusterConfig(config.ClusterConfig()))
+	bo := config.ClusterConfig().ReplicationBackoffConfig()
+	require.NotNil(t, bo)
+	require.Equal(t, 500*time.Millisecond, bo.InitialInterval())
+	require.Equal(t, 2.0, bo.Multiplier())
+	require.Equal(t, 30*time.Second, bo.MaxInterval())
+	require.Equal(t, 0.2, bo.Jitter())
+
+	config, err = NewYamlConfig([]byte(`
+cluster:
+  replication_backoff:
+    initial_interval_millis: 5000
+`))
+	require.NoError(t, err)
+	bo = config.ClusterConfig().ReplicationBackoffConfig()
+	require.Equal(t, 1.0, bo.Multiplier())
+	require.Equal(t, 5*time.Second, bo.MaxInterval())
+	require.Equal(t, 0.0, bo.Jitter())
+}
+
+func TestUnmarshallClusterReplicationThrottle(t *testing.T) {
+	testStr := `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://doltdb-1.doltdb:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  replication_throttle:
+    max_bytes_per_sec: 10485760
+    chunks_per_batch: 4096
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NoError(t, ValidateClusterConfig(config.ClusterConfig()))
+	throttle := config.ClusterConfig().ReplicationThrottleConfig()
+	require.NotNil(t, throttle)
+	require.Equal(t, int64(10485760), throttle.MaxBytesPerSec())
+	require.Equal(t, 4096, throttle.ChunksPerBatch())
+}
+
+func TestUnmarshallClusterReplicationHeartbeat(t *testing.T) {
+	testStr := `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://doltdb-1.doltdb:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  replication_heartbeat:
+    interval_millis: 10000
+    timeout_millis: 2000
+    failure_threshold: 5
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NoError(t, ValidateClusterConfig(config.ClusterConfig()))
+	heartbeat := config.ClusterConfig().ReplicationHeartbeatConfig()
+	require.NotNil(t, heartbeat)
+	require.Equal(t, 10*time.Second, heartbeat.Interval())
+	require.Equal(t, 2*time.Second, heartbeat.Timeout())
+	require.Equal(t, 5, heartbeat.FailureThreshold())
+
+	config, err = NewYamlConfig([]byte(`
+cluster:
+  replication_heartbeat: {}
+`))
+	require.NoError(t, err)
+	heartbeat = config.ClusterConfig().ReplicationHeartbeatConfig()
+	require.Equal(t, cluster.DefaultHeartbeatInterval, heartbeat.Interval())
+	require.Equal(t, cluster.DefaultHeartbeatTimeout, heartbeat.Timeout())
+	require.Equal(t, cluster.DefaultHeartbeatFailureThreshold, heartbeat.FailureThreshold())
+}
+
+func TestUnmarshallClusterReplicationCircuitBreaker(t *testing.T) {
+	testStr := `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://doltdb-1.doltdb:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  replication_circuit_breaker:
+    failure_threshold: 3
+    probe_interval_millis: 30000
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NoError(t, ValidateClusterConfig(config.ClusterConfig()))
+	breaker := config.ClusterConfig().ReplicationCircuitBreakerConfig()
+	require.NotNil(t, breaker)
+	require.Equal(t, 3, breaker.FailureThreshold())
+	require.Equal(t, 30*time.Second, breaker.ProbeInterval())
+
+	config, err = NewYamlConfig([]byte(`
+cluster:
+  replication_circuit_breaker: {}
+`))
+	require.NoError(t, err)
+	breaker = config.ClusterConfig().ReplicationCircuitBreakerConfig()
+	require.Equal(t, cluster.DefaultCircuitBreakerFailureThreshold, breaker.FailureThreshold())
+	require.Equal(t, time.Duration(0), breaker.ProbeInterval())
+}
+
+func TestUnmarshallClusterAutomaticFailover(t *testing.T) {
+	testStr := `
+cluster:
+  standby_remotes:
+  - name: standby1
+    remote_url_template: http://doltdb-1.doltdb:50051/{database}
+  - name: standby2
+    remote_url_template: http://doltdb-2.doltdb:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  automatic_failover:
+    lease_duration_millis: 5000
+    priority: 1
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NoError(t, ValidateClusterConfig(config.ClusterConfig()))
+	failover := config.ClusterConfig().AutomaticFailoverConfig()
+	require.NotNil(t, failover)
+	require.Equal(t, 5*time.Second, failover.LeaseDuration())
+	require.Equal(t, 1, failover.Priority())
+
+	config, err = NewYamlConfig([]byte(`
+cluster:
+  automatic_failover: {}
+`))
+	require.NoError(t, err)
+	require.Equal(t, cluster.DefaultFailoverLeaseDuration, config.ClusterConfig().AutomaticFailoverConfig().LeaseDuration())
+}
+
+func TestUnmarshallClusterRequiredAcks(t *testing.T) {
+	testStr := `
+cluster:
+  standby_remotes:
+  - name: standby1
+    remote_url_template: http://doltdb-1.doltdb:50051/{database}
+  - name: standby2
+    remote_url_template: http://doltdb-2.doltdb:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  required_acks: 1
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NoError(t, ValidateClusterConfig(config.ClusterConfig()))
+	require.Equal(t, 1, config.ClusterConfig().RequiredAcks())
+}
+
+func TestUnmarshallClusterStandbyBootstrap(t *testing.T) {
+	testStr := `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://doltdb-1.doltdb:50051/{database}
+  bootstrap_role: standby
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  standby_bootstrap_url_template: file:///backups/{database}
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NoError(t, ValidateClusterConfig(config.ClusterConfig()))
+	require.Equal(t, "file:///backups/{database}", config.ClusterConfig().StandbyBootstrapURLTemplate())
 }
 
 func TestValidateClusterConfig(t *testing.T) {
@@ -204,6 +651,20 @@ cluster:
   bootstrap_epoch: 0
   remotesapi:
     port: 50051
+`,
+			Error: false,
+		},
+		{
+			Name: "observer bootstrap_role",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+  bootstrap_role: observer
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
 `,
 			Error: false,
 		},
@@ -263,6 +724,232 @@ cluster:
 `,
 			Error: true,
 		},
+		{
+			Name: "read_routing without a standby sql_address",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  read_routing:
+    port: 3307
+`,
+			Error: true,
+		},
+		{
+			Name: "read_routing with a bad port",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+    sql_address: localhost:3308
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  read_routing:
+    port: 0
+`,
+			Error: true,
+		},
+		{
+			Name: "replication_backoff with a multiplier below 1",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  replication_backoff:
+    multiplier: 0.5
+`,
+			Error: true,
+		},
+		{
+			Name: "replication_throttle with a negative rate",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  replication_throttle:
+    max_bytes_per_sec: -1
+`,
+			Error: true,
+		},
+		{
+			Name: "automatic_failover with one standby_remote",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  automatic_failover: {}
+`,
+			Error: true,
+		},
+		{
+			Name: "required_acks more than the standby_remotes",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  required_acks: 2
+`,
+			Error: true,
+		},
+		{
+			Name: "standby_bootstrap_url_template without {database}",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+  bootstrap_role: standby
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  standby_bootstrap_url_template: file:///backups
+`,
+			Error: true,
+		},
+		{
+			Name: "replication_heartbeat with a failure_threshold of 0",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  replication_heartbeat:
+    failure_threshold: 0
+`,
+			Error: true,
+		},
+		{
+			Name: "replication_heartbeat slower than the automatic_failover lease",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby1
+    remote_url_template: http://localhost:50051/{database}
+  - name: standby2
+    remote_url_template: http://localhost:50052/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  replication_heartbeat:
+    interval_millis: 5000
+  automatic_failover:
+    lease_duration_millis: 10000
+`,
+			Error: true,
+		},
+		{
+			Name: "replication_circuit_breaker with a failure_threshold of 0",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  replication_circuit_breaker:
+    failure_threshold: 0
+`,
+			Error: true,
+		},
+		{
+			Name: "replication_backoff with too much jitter",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+  replication_backoff:
+    jitter: 1.5
+`,
+			Error: true,
+		},
+		{
+			Name: "tls_client_cert without tls_client_key",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+    tls_client_cert: testdata/chain_cert.pem
+`,
+			Error: true,
+		},
+		{
+			Name: "tls_client_ca without tls_cert",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+    tls_client_ca: testdata/chain_cert.pem
+`,
+			Error: true,
+		},
+		{
+			Name: "mutual tls",
+			Config: `
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: https://localhost:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 0
+  remotesapi:
+    port: 50051
+    tls_cert: testdata/chain_cert.pem
+    tls_key: testdata/chain_key.pem
+    tls_client_cert: testdata/chain_cert.pem
+    tls_client_key: testdata/chain_key.pem
+    tls_client_ca: testdata/chain_cert.pem
+`,
+		},
 		{
 			Name: "no standby remotes",
 			Config: `
@@ -416,3 +1103,47 @@ listener:
 	err = ValidateConfig(cfg)
 	assert.Error(t, err)
 }
+
+var minVerPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+// TestMinVer checks that every config field with a minver tag names a release, and that the fields and their versions
+// match testdata/minver_validation.txt, so that a field added without a minver tag, or with the wrong one, shows up in
+// review.
+func TestMinVer(t *testing.T) {
+	var lines []string
+	visited := make(map[reflect.Type]bool)
+	var walk func(typ reflect.Type, path string)
+	walk = func(typ reflect.Type, path string) {
+		for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct || visited[typ] {
+			return
+		}
+		visited[typ] = true
+		defer delete(visited, typ)
+
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			} else if len(name) == 0 {
+				name = strings.ToLower(field.Name)
+			}
+			if len(path) > 0 {
+				name = path + "." + name
+			}
+			if minVer, ok := field.Tag.Lookup("minver"); ok {
+				assert.Regexp(t, minVerPattern, minVer, "field %s has an invalid minver", name)
+				lines = append(lines, name+" "+minVer)
+			}
+			walk(field.Type, name)
+		}
+	}
+	walk(reflect.TypeOf(YAMLConfig{}), "")
+
+	expected, err := os.ReadFile(filepath.Join("testdata", "minver_validation.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(expected)), strings.Join(lines, "\n"))
+}
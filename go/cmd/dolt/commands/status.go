@@ -111,6 +111,14 @@ func (cmd StatusCmd) Exec(ctx context.Context, commandStr string, args []string,
 		defer closeFunc()
 	}
 
+	if cli.MachineReadableOutput() {
+		err = printStatusRows(queryist, sqlCtx, showIgnoredTables)
+		if err != nil {
+			return handleStatusVErr(err)
+		}
+		return 0
+	}
+
 	// get status information from the database
 	pd, err := createPrintData(err, queryist, sqlCtx, showIgnoredTables)
 	if err != nil {
@@ -125,6 +133,47 @@ func (cmd StatusCmd) Exec(ctx context.Context, commandStr string, args []string,
 	return 0
 }
 
+// printStatusRows writes the rows of dolt_status in the global --format. Like the text output, it leaves out unstaged
+// tables that are ignored by dolt_ignore, unless |showIgnoredTables| is set.
+func printStatusRows(queryist cli.Queryist, sqlCtx *sql.Context, showIgnoredTables bool) error {
+	ignorePatterns, err := getIgnoredTablePatternsFromSql(queryist, sqlCtx)
+	if err != nil {
+		return err
+	}
+
+	statusRows, err := GetRowsForSql(queryist, sqlCtx, "select table_name,staged,status from dolt_status;")
+	if err != nil {
+		return err
+	}
+
+	var rows []sql.Row
+	for _, row := range statusRows {
+		tableName := row[0].(string)
+		if doltdb.IsFullTextTable(tableName) {
+			continue
+		}
+
+		isStaged, err := GetTinyIntColAsBool(row[1])
+		if err != nil {
+			return err
+		}
+
+		if !isStaged && !showIgnoredTables {
+			ignored, err := ignorePatterns.IsTableNameIgnored(tableName)
+			if err != nil && doltdb.AsDoltIgnoreInConflict(err) == nil {
+				return err
+			}
+			if ignored == doltdb.Ignore {
+				continue
+			}
+		}
+
+		rows = append(rows, sql.Row{tableName, isStaged, row[2].(string)})
+	}
+
+	return printOutputRows(statusOutputSchema, rows)
+}
+
 func createPrintData(err error, queryist cli.Queryist, sqlCtx *sql.Context, showIgnoredTables bool) (*printData, error) {
 	branchName, err := getActiveBranchName(sqlCtx, queryist)
 	if err != nil {
@@ -45,12 +45,12 @@ var tagDocs = cli.CommandDocumentationContent{
 	ShortDesc: `Create, list, delete tags.`,
 	LongDesc: `If there are no non-option arguments, existing tags are listed.
 
-The command's second form creates a new tag named {{.LessThan}}tagname{{.GreaterThan}} which points to the current {{.EmphasisLeft}}HEAD{{.EmphasisRight}}, or {{.LessThan}}ref{{.GreaterThan}} if given. Optionally, a tag message can be passed using the {{.EmphasisLeft}}-m{{.EmphasisRight}} option. 
+The command's second form creates a new tag named {{.LessThan}}tagname{{.GreaterThan}} which points to the current {{.EmphasisLeft}}HEAD{{.EmphasisRight}}, or {{.LessThan}}ref{{.GreaterThan}} if given. Optionally, a tag message can be passed using the {{.EmphasisLeft}}-m{{.EmphasisRight}} option, and the tag can be signed with the {{.EmphasisLeft}}-s{{.EmphasisRight}} option, using the key configured by {{.EmphasisLeft}}user.signingkey{{.EmphasisRight}}. Signatures of tags can be checked with {{.EmphasisLeft}}dolt verify-tag{{.EmphasisRight}}.
 
 With a {{.EmphasisLeft}}-d{{.EmphasisRight}}, {{.LessThan}}tagname{{.GreaterThan}} will be deleted.`,
 	Synopsis: []string{
 		`[-v]`,
-		`[-m {{.LessThan}}message{{.GreaterThan}}] {{.LessThan}}tagname{{.GreaterThan}} [{{.LessThan}}ref{{.GreaterThan}}]`,
+		`[-s] [-m {{.LessThan}}message{{.GreaterThan}}] {{.LessThan}}tagname{{.GreaterThan}} [{{.LessThan}}ref{{.GreaterThan}}]`,
 		`-d {{.LessThan}}tagname{{.GreaterThan}}`,
 	},
 }
@@ -127,25 +127,20 @@ func createTag(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgPar
 	message, _ := apr.GetValue(cli.MessageArg)
 	author, _ := apr.GetValue(cli.AuthorParam)
 
-	var query string
-	var params []interface{}
-	if len(message) == 0 {
-		if len(author) == 0 {
-			query = "call dolt_tag(?, ?)"
-			params = []interface{}{tagName, startPoint}
-		} else {
-			query = "call dolt_tag(?, ?, '--author', ?)"
-			params = []interface{}{tagName, startPoint, author}
-		}
-	} else {
-		if len(author) == 0 {
-			query = "call dolt_tag('-m', ?, ?, ?)"
-			params = []interface{}{message, tagName, startPoint}
-		} else {
-			query = "call dolt_tag('-m', ?, ?, ?, '--author', ?)"
-			params = []interface{}{message, tagName, startPoint, author}
-		}
+	query := "call dolt_tag(?, ?"
+	params := []interface{}{tagName, startPoint}
+	if len(message) > 0 {
+		query += ", '-m', ?"
+		params = append(params, message)
 	}
+	if len(author) > 0 {
+		query += ", '--author', ?"
+		params = append(params, author)
+	}
+	if apr.Contains(cli.SignFlag) {
+		query += ", '-s'"
+	}
+	query += ")"
 
 	_, err := InterpolateAndRunQuery(queryist, sqlCtx, query, params...)
 	if err != nil {
@@ -182,6 +177,14 @@ func listTags(queryist cli.Queryist, sqlCtx *sql.Context, apr *argparser.ArgPars
 		return fmt.Errorf("error: failed to list tags: %w", err)
 	}
 
+	if cli.MachineReadableOutput() {
+		rows := make([]sql.Row, len(tagInfos))
+		for i, tag := range tagInfos {
+			rows[i] = sql.Row{tag.Name, tag.Hash, tag.Tagger, tag.Email, time.UnixMilli(int64(tag.Timestamp)).UTC(), tag.Message}
+		}
+		return printOutputRows(tagOutputSchema, rows)
+	}
+
 	for _, tag := range tagInfos {
 		if apr.Contains(cli.VerboseFlag) {
 			verboseTagPrint(tag)
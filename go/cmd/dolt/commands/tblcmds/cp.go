@@ -18,6 +18,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands"
@@ -30,12 +34,15 @@ var tblCpDocs = cli.CommandDocumentationContent{
 	ShortDesc: "Makes a copy of a table",
 	LongDesc: `The dolt table cp command makes a copy of a table at a given commit. If a commit is not specified the copy is made of the table from the current working set.
 
+Tables can be copied between branches by qualifying their names with a branch, as in {{.EmphasisLeft}}dolt table cp main.customers feature.customers{{.EmphasisRight}}. The source can also be qualified with a tag or commit. The schema and data of the table are copied exactly, and the copy is staged on the destination branch, ready to be committed. A table copied between branches keeps its name.
+
 If a table exists at the target location this command will fail unless the {{.EmphasisLeft}}--force|-f{{.EmphasisRight}} flag is provided.  In this case the table at the target location will be overwritten with the copied table.
 
 All changes will be applied to the working tables and will need to be staged using {{.EmphasisLeft}}dolt add{{.EmphasisRight}} and committed using {{.EmphasisLeft}}dolt commit{{.EmphasisRight}}.
 `,
 	Synopsis: []string{
 		"[-f] {{.LessThan}}oldtable{{.GreaterThan}} {{.LessThan}}newtable{{.GreaterThan}}",
+		"[-f] {{.LessThan}}revision{{.GreaterThan}}.{{.LessThan}}table{{.GreaterThan}} {{.LessThan}}branch{{.GreaterThan}}.{{.LessThan}}table{{.GreaterThan}}",
 	},
 }
 
@@ -83,11 +90,26 @@ func (cmd CpCmd) Exec(ctx context.Context, commandStr string, args []string, dEn
 	oldTbl, newTbl := apr.Arg(0), apr.Arg(1)
 
 	queryStr := ""
-	if force := apr.Contains(forceParam); force {
-		queryStr = fmt.Sprintf("DROP TABLE IF EXISTS `%s`;", newTbl)
+	if strings.Contains(oldTbl, ".") || strings.Contains(newTbl, ".") {
+		// copies between revisions are made by dolt_copy_table
+		params := []interface{}{oldTbl, newTbl}
+		if apr.Contains(forceParam) {
+			params = append(params, "--force")
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(params)), ", ")
+		var err error
+		queryStr, err = dbr.InterpolateForDialect("CALL DOLT_COPY_TABLE("+placeholders+");", params, dialect.MySQL)
+		if err != nil {
+			cli.PrintErrln(err.Error())
+			return 1
+		}
+	} else {
+		if force := apr.Contains(forceParam); force {
+			queryStr = fmt.Sprintf("DROP TABLE IF EXISTS `%s`;", newTbl)
+		}
+		queryStr = fmt.Sprintf("%sCREATE TABLE `%s` LIKE `%s`;", queryStr, newTbl, oldTbl)
+		queryStr = fmt.Sprintf("%sINSERT INTO `%s` SELECT * FROM `%s`;", queryStr, newTbl, oldTbl)
 	}
-	queryStr = fmt.Sprintf("%sCREATE TABLE `%s` LIKE `%s`;", queryStr, newTbl, oldTbl)
-	queryStr = fmt.Sprintf("%sINSERT INTO `%s` SELECT * FROM `%s`;", queryStr, newTbl, oldTbl)
 
 	cli.CliOut = io.Discard // display nothing on success
 	return commands.SqlCmd{}.Exec(ctx, "", []string{
@@ -18,6 +18,7 @@ import (
 	"context"
 	"crypto/sha1"
 	"fmt"
+	"io"
 	"net"
 	"path/filepath"
 	"strconv"
@@ -51,6 +52,10 @@ type CommitInfo struct {
 	localBranchNames  []string
 	remoteBranchNames []string
 	tagNames          []string
+	// signature is the result of verifying the commit's signature, if it's shown
+	signature string
+	// formatted is the commit in the custom pretty format of the log, if it has one
+	formatted string
 }
 
 var fwtStageName = "fwt"
@@ -525,39 +530,53 @@ func PrintCommitInfo(pager *outputpager.Pager, minParents int, showParents bool,
 	if len(comm.parentHashes) < minParents {
 		return
 	}
+	writeCommitInfo(pager.Writer, showParents, decoration, comm)
+}
 
+// writeCommitInfo writes the given commit to |w| in the format used by log and show.
+func writeCommitInfo(w io.Writer, showParents bool, decoration string, comm *CommitInfo) {
 	chStr := comm.commitHash
 	if showParents {
 		chStr = strings.Join(append([]string{chStr}, comm.parentHashes...), " ")
 	}
 
 	// Write commit hash
-	pager.Writer.Write([]byte(fmt.Sprintf("\033[33mcommit %s \033[0m", chStr))) // Use Dim Yellow (33m)
+	w.Write([]byte(fmt.Sprintf("\033[33mcommit %s \033[0m", chStr))) // Use Dim Yellow (33m)
 
 	// Show decoration
 	if decoration != "no" {
-		printRefs(pager, comm, decoration)
+		printRefs(w, comm, decoration)
 	}
 
 	if len(comm.parentHashes) > 1 {
-		pager.Writer.Write([]byte(fmt.Sprintf("\nMerge:")))
+		w.Write([]byte(fmt.Sprintf("\nMerge:")))
 		for _, h := range comm.parentHashes {
-			pager.Writer.Write([]byte(fmt.Sprintf(" " + h)))
+			w.Write([]byte(fmt.Sprintf(" " + h)))
 		}
 	}
 
-	pager.Writer.Write([]byte(fmt.Sprintf("\nAuthor: %s <%s>", comm.commitMeta.Name, comm.commitMeta.Email)))
+	w.Write([]byte("\n"))
+	printSignature(w, comm)
+	w.Write([]byte(fmt.Sprintf("Author: %s <%s>", comm.commitMeta.Name, comm.commitMeta.Email)))
 
 	timeStr := comm.commitMeta.FormatTS()
-	pager.Writer.Write([]byte(fmt.Sprintf("\nDate:  %s", timeStr)))
+	w.Write([]byte(fmt.Sprintf("\nDate:  %s", timeStr)))
 
 	formattedDesc := "\n\n\t" + strings.Replace(comm.commitMeta.Description, "\n", "\n\t", -1) + "\n\n"
-	pager.Writer.Write([]byte(fmt.Sprintf("%s", formattedDesc)))
+	w.Write([]byte(fmt.Sprintf("%s", formattedDesc)))
+
+}
 
+// printSignature prints the result of verifying the signature of the commit, if it's shown, on lines of its own.
+func printSignature(w io.Writer, comm *CommitInfo) {
+	if comm.signature == "" {
+		return
+	}
+	w.Write([]byte(comm.signature + "\n"))
 }
 
 // printRefs prints the refs associated with the commit in the formatting used by log and show.
-func printRefs(pager *outputpager.Pager, comm *CommitInfo, decoration string) {
+func printRefs(w io.Writer, comm *CommitInfo, decoration string) {
 	// Do nothing if no associate branchNames
 	if len(comm.localBranchNames) == 0 && len(comm.remoteBranchNames) == 0 && len(comm.tagNames) == 0 {
 		return
@@ -590,12 +609,12 @@ func printRefs(pager *outputpager.Pager, comm *CommitInfo, decoration string) {
 		references = append(references, tagName)
 	}
 
-	pager.Writer.Write([]byte("\033[33m(\033[0m"))
+	w.Write([]byte("\033[33m(\033[0m"))
 	if comm.isHead {
-		pager.Writer.Write([]byte("\033[36;1mHEAD -> \033[0m"))
+		w.Write([]byte("\033[36;1mHEAD -> \033[0m"))
 	}
-	pager.Writer.Write([]byte(strings.Join(references, "\033[33m, \033[0m"))) // Separate with Dim Yellow comma
-	pager.Writer.Write([]byte("\033[33m) \033[0m"))
+	w.Write([]byte(strings.Join(references, "\033[33m, \033[0m"))) // Separate with Dim Yellow comma
+	w.Write([]byte("\033[33m) \033[0m"))
 }
 
 // getCommitInfo returns the commit info for the given ref.
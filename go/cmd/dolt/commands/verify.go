@@ -0,0 +1,144 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+var verifyCommitDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Check the signatures of commits.`,
+	LongDesc: `Verifies the signatures of the commits given, made with {{.EmphasisLeft}}dolt commit -S{{.EmphasisRight}}, and prints the output of the program that verified them.
+
+OpenPGP signatures are verified with gpg, against the keys in its keyring. SSH signatures are verified with ssh-keygen, against the keys listed in the file configured by {{.EmphasisLeft}}gpg.ssh.allowedsignersfile{{.EmphasisRight}}. The command fails if any of the commits isn't signed, or if its signature isn't a good signature by a trusted key.`,
+	Synopsis: []string{
+		`{{.LessThan}}commit{{.GreaterThan}}...`,
+	},
+}
+
+var verifyTagDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Check the signatures of tags.`,
+	LongDesc: `Verifies the signatures of the tags given, made with {{.EmphasisLeft}}dolt tag -s{{.EmphasisRight}}, and prints the output of the program that verified them.
+
+Signatures are verified as by {{.EmphasisLeft}}dolt verify-commit{{.EmphasisRight}}. The command fails if any of the tags isn't signed, or if its signature isn't a good signature by a trusted key.`,
+	Synopsis: []string{
+		`{{.LessThan}}tagname{{.GreaterThan}}...`,
+	},
+}
+
+type VerifyCommitCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd VerifyCommitCmd) Name() string {
+	return "verify-commit"
+}
+
+// Description returns a description of the command
+func (cmd VerifyCommitCmd) Description() string {
+	return verifyCommitDocs.ShortDesc
+}
+
+func (cmd VerifyCommitCmd) Docs() *cli.CommandDocumentation {
+	return cli.NewCommandDocumentation(verifyCommitDocs, cmd.ArgParser())
+}
+
+func (cmd VerifyCommitCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs(cmd.Name())
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"commit", "A commit whose signature is verified."})
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd VerifyCommitCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd VerifyCommitCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	return verifySignatures(ctx, commandStr, args, cliCtx, verifyCommitDocs, cmd.ArgParser(), "call dolt_verify_signatures(?)")
+}
+
+type VerifyTagCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd VerifyTagCmd) Name() string {
+	return "verify-tag"
+}
+
+// Description returns a description of the command
+func (cmd VerifyTagCmd) Description() string {
+	return verifyTagDocs.ShortDesc
+}
+
+func (cmd VerifyTagCmd) Docs() *cli.CommandDocumentation {
+	return cli.NewCommandDocumentation(verifyTagDocs, cmd.ArgParser())
+}
+
+func (cmd VerifyTagCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs(cmd.Name())
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"tagname", "A tag whose signature is verified."})
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd VerifyTagCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd VerifyTagCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	return verifySignatures(ctx, commandStr, args, cliCtx, verifyTagDocs, cmd.ArgParser(), "call dolt_verify_signatures('--tags', ?)")
+}
+
+// verifySignatures runs |query| for each of the commits or tags named in |args|, printing the output of verifying their
+// signatures, and returns the exit code: 1 if any of them isn't signed or has a bad signature.
+func verifySignatures(ctx context.Context, commandStr string, args []string, cliCtx cli.CliContext, docs cli.CommandDocumentationContent, ap *argparser.ArgParser, query string) int {
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, docs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+	if apr.NArg() == 0 {
+		usage()
+		return 1
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		cli.PrintErrln(err)
+		return 1
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	status := 0
+	for _, arg := range apr.Args {
+		rows, err := InterpolateAndRunQuery(queryist, sqlCtx, query, arg)
+		if err != nil {
+			cli.PrintErrln(err.Error())
+			status = 1
+			continue
+		}
+		for _, row := range rows {
+			if msg, ok := row[2].(string); ok && msg != "" {
+				cli.PrintErrln(msg)
+			}
+		}
+	}
+	return status
+}
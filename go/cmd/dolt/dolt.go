@@ -18,24 +18,19 @@ import (
 	"context"
 	crand "crypto/rand"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"math/rand"
-	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/fatih/color"
-	"github.com/pkg/profile"
 	"github.com/tidwall/gjson"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/jaeger"
-	"go.opentelemetry.io/otel/sdk/resource"
-	tracesdk "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands"
@@ -45,6 +40,7 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/cvcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/docscmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/indexcmds"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/patchcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/schcmds"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/sqlserver"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/stashcmds"
@@ -69,6 +65,15 @@ const (
 
 var dumpDocsCommand = &commands.DumpDocsCmd{}
 var dumpZshCommand = &commands.GenZshCompCmd{}
+var bashCompletionCommand = &commands.GenCompletionCmd{Shell: commands.BashShell}
+var fishCompletionCommand = &commands.GenCompletionCmd{Shell: commands.FishShell}
+var powerShellCompletionCommand = &commands.GenCompletionCmd{Shell: commands.PowerShellShell}
+var completionCommands = cli.NewSubCommandHandler("completion", "Generates shell completion scripts for dolt.", []cli.Command{
+	bashCompletionCommand,
+	fishCompletionCommand,
+	powerShellCompletionCommand,
+	commands.CompleteNamesCmd{},
+})
 
 var doltSubCommands = []cli.Command{
 	commands.InitCmd{},
@@ -82,6 +87,7 @@ var doltSubCommands = []cli.Command{
 	admin.Commands,
 	sqlserver.SqlServerCmd{VersionStr: Version},
 	sqlserver.SqlClientCmd{VersionStr: Version},
+	sqlserver.ServiceCommands,
 	commands.LogCmd{},
 	commands.ShowCmd{},
 	commands.BranchCmd{},
@@ -89,7 +95,10 @@ var doltSubCommands = []cli.Command{
 	commands.MergeCmd{},
 	cnfcmds.Commands,
 	commands.CherryPickCmd{},
+	patchcmds.Commands,
 	commands.RevertCmd{},
+	commands.BisectCmd{},
+	commands.RebaseCmd{},
 	commands.CloneCmd{},
 	commands.FetchCmd{},
 	commands.PullCmd{},
@@ -103,6 +112,8 @@ var doltSubCommands = []cli.Command{
 	schcmds.Commands,
 	tblcmds.Commands,
 	commands.TagCmd{},
+	commands.VerifyCommitCmd{},
+	commands.VerifyTagCmd{},
 	commands.BlameCmd{},
 	cvcmds.Commands,
 	commands.SendMetricsCmd{},
@@ -110,6 +121,7 @@ var doltSubCommands = []cli.Command{
 	indexcmds.Commands,
 	commands.ReadTablesCmd{},
 	commands.GarbageCollectionCmd{},
+	commands.DoctorCmd{},
 	commands.FilterBranchCmd{},
 	commands.MergeBaseCmd{},
 	commands.RootsCmd{},
@@ -118,6 +130,7 @@ var doltSubCommands = []cli.Command{
 	commands.InspectCmd{},
 	dumpDocsCommand,
 	dumpZshCommand,
+	completionCommands,
 	docscmds.Commands,
 	stashcmds.StashCommands,
 	&commands.Assist{},
@@ -129,6 +142,7 @@ var commandsWithoutCliCtx = []cli.Command{
 	admin.Commands,
 	sqlserver.SqlServerCmd{VersionStr: Version},
 	sqlserver.SqlClientCmd{VersionStr: Version},
+	sqlserver.ServiceCommands,
 	commands.CloneCmd{},
 	commands.PushCmd{},
 	commands.RemoteCmd{},
@@ -143,6 +157,7 @@ var commandsWithoutCliCtx = []cli.Command{
 	indexcmds.Commands,
 	commands.ReadTablesCmd{},
 	commands.GarbageCollectionCmd{},
+	commands.DoctorCmd{},
 	commands.FilterBranchCmd{},
 	commands.MergeBaseCmd{},
 	commands.RootsCmd{},
@@ -151,6 +166,7 @@ var commandsWithoutCliCtx = []cli.Command{
 	commands.InspectCmd{},
 	dumpDocsCommand,
 	dumpZshCommand,
+	completionCommands,
 	docscmds.Commands,
 	&commands.Assist{},
 	commands.ProfileCmd{},
@@ -166,6 +182,7 @@ var commandsWithoutGlobalArgSupport = []cli.Command{
 	credcmds.Commands,
 	sqlserver.SqlServerCmd{VersionStr: Version},
 	sqlserver.SqlClientCmd{VersionStr: Version},
+	sqlserver.ServiceCommands,
 	commands.VersionCmd{VersionStr: Version},
 	commands.ConfigCmd{},
 }
@@ -202,13 +219,26 @@ func init() {
 	dumpDocsCommand.GlobalDocs = globalDocs
 	dumpDocsCommand.GlobalSpecialMsg = globalSpecialMsg
 	dumpZshCommand.DoltCommand = doltCommand
+	bashCompletionCommand.DoltCommand = doltCommand
+	fishCompletionCommand.DoltCommand = doltCommand
+	powerShellCompletionCommand.DoltCommand = doltCommand
 	dfunctions.VersionString = Version
 }
 
 const pprofServerFlag = "--pprof-server"
+const pprofAddrFlag = "--pprof-addr"
 const chdirFlag = "--chdir"
 const jaegerFlag = "--jaeger"
+const otlpEndpointFlag = "--otlp-endpoint"
+const otlpProtocolFlag = "--otlp-protocol"
+const otlpHeaderFlag = "--otlp-header"
+const otlpInsecureFlag = "--otlp-insecure"
+const traceSampleRateFlag = "--trace-sample-rate"
+const logFormatFlag = "--log-format"
+const outputFormatFlag = "--format"
 const profFlag = "--prof"
+const profThresholdFlag = "--prof-threshold"
+const profDirFlag = "--prof-dir"
 const csMetricsFlag = "--csmetrics"
 const stdInFlag = "--stdin"
 const stdOutFlag = "--stdout"
@@ -242,57 +272,53 @@ func runMain() int {
 		nbs.TableIndexGCFinalizerWithStackTrace = false
 	}
 
+	tracing, err := tracingConfigFromEnv()
+	if err != nil {
+		cli.PrintErrln(err.Error())
+		return 1
+	}
+
+	profiling := newProfilingConfig()
 	csMetrics := false
 	ignoreLockFile := false
 	verboseEngineSetup := false
+	logFormat := cli.LogFormatText
+	outputFormat := cli.OutputFormatText
 	if len(args) > 0 {
 		var doneDebugFlags bool
 		for !doneDebugFlags && len(args) > 0 {
+			// --log-format and --format may also be given as --log-format=<format> and --format=<format>
+			if flag, value, ok := strings.Cut(args[0], "="); ok && (flag == logFormatFlag || flag == outputFormatFlag) {
+				args = append([]string{flag, value}, args[1:]...)
+			}
+
 			switch args[0] {
-			case profFlag:
-				switch args[1] {
-				case cpuProf:
-					cli.Println("cpu profiling enabled.")
-					defer profile.Start(profile.CPUProfile, profile.NoShutdownHook).Stop()
-				case memProf:
-					cli.Println("mem profiling enabled.")
-					defer profile.Start(profile.MemProfile, profile.NoShutdownHook).Stop()
-				case blockingProf:
-					cli.Println("block profiling enabled")
-					defer profile.Start(profile.BlockProfile, profile.NoShutdownHook).Stop()
-				case traceProf:
-					cli.Println("trace profiling enabled")
-					defer profile.Start(profile.TraceProfile, profile.NoShutdownHook).Stop()
-				default:
-					panic("Unexpected prof flag: " + args[1])
+			case profFlag, pprofAddrFlag, profThresholdFlag, profDirFlag:
+				if len(args) < 2 {
+					cli.PrintErrln(fmt.Sprintf("missing argument for the %s flag", args[0]))
+					return 1
+				}
+
+				var err error
+				switch args[0] {
+				case profFlag:
+					err = profiling.setMode(args[1])
+				case pprofAddrFlag:
+					err = profiling.setPprofServerAddr(args[1])
+				case profThresholdFlag:
+					err = profiling.setThreshold(args[1])
+				case profDirFlag:
+					profiling.dir = args[1]
+				}
+				if err != nil {
+					cli.PrintErrln(fmt.Sprintf("invalid argument for the %s flag: %s", args[0], err.Error()))
+					return 1
 				}
+
 				args = args[2:]
 
 			case pprofServerFlag:
-				// serve the pprof endpoints setup in the init function run when "net/http/pprof" is imported
-				go func() {
-					cyanStar := color.CyanString("*")
-					cli.Println(cyanStar, "Starting pprof server on port 6060.")
-					cli.Println(cyanStar, "Go to", color.CyanString("http://localhost:6060/debug/pprof"), "in a browser to see supported endpoints.")
-					cli.Println(cyanStar)
-					cli.Println(cyanStar, "Known endpoints are:")
-					cli.Println(cyanStar, "  /allocs: A sampling of all past memory allocations")
-					cli.Println(cyanStar, "  /block: Stack traces that led to blocking on synchronization primitives")
-					cli.Println(cyanStar, "  /cmdline: The command line invocation of the current program")
-					cli.Println(cyanStar, "  /goroutine: Stack traces of all current goroutines")
-					cli.Println(cyanStar, "  /heap: A sampling of memory allocations of live objects. You can specify the gc GET parameter to run GC before taking the heap sample.")
-					cli.Println(cyanStar, "  /mutex: Stack traces of holders of contended mutexes")
-					cli.Println(cyanStar, "  /profile: CPU profile. You can specify the duration in the seconds GET parameter. After you get the profile file, use the go tool pprof command to investigate the profile.")
-					cli.Println(cyanStar, "  /threadcreate: Stack traces that led to the creation of new OS threads")
-					cli.Println(cyanStar, "  /trace: A trace of execution of the current program. You can specify the duration in the seconds GET parameter. After you get the trace file, use the go tool trace command to investigate the trace.")
-					cli.Println()
-
-					err := http.ListenAndServe("0.0.0.0:6060", nil)
-
-					if err != nil {
-						cli.Println(color.YellowString("pprof server exited with error: %v", err))
-					}
-				}()
+				profiling.pprofServer = true
 				args = args[1:]
 
 			// Enable a global jaeger tracer for this run of Dolt,
@@ -311,22 +337,41 @@ func runMain() int {
 			//    jaegertracing/all-in-one:1.21
 			// and browse to http://localhost:16686
 			case jaegerFlag:
-				cli.Println("running with jaeger tracing reporting to localhost")
-				exp, err := jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint("http://localhost:14268/api/traces")))
+				tracing.jaeger = true
+				args = args[1:]
+
+			// Export traces to an OpenTelemetry collector, such as Tempo
+			// or Honeycomb, e.g.
+			// dolt --otlp-endpoint https://api.honeycomb.io \
+			//    --otlp-header x-honeycomb-team=<API KEY> sql-server
+			case otlpEndpointFlag, otlpProtocolFlag, otlpHeaderFlag, traceSampleRateFlag:
+				if len(args) < 2 {
+					cli.PrintErrln(fmt.Sprintf("missing argument for the %s flag", args[0]))
+					return 1
+				}
+
+				var err error
+				switch args[0] {
+				case otlpEndpointFlag:
+					tracing.otlpEndpoint = args[1]
+				case otlpProtocolFlag:
+					err = tracing.setOtlpProtocol(args[1])
+				case otlpHeaderFlag:
+					err = tracing.addOtlpHeader(args[1])
+				case traceSampleRateFlag:
+					err = tracing.setSampleRate(args[1])
+				}
 				if err != nil {
-					cli.Println(color.YellowString("could not create jaeger collector: %v", err))
-				} else {
-					tp := tracesdk.NewTracerProvider(
-						tracesdk.WithBatcher(exp),
-						tracesdk.WithResource(resource.NewWithAttributes(
-							semconv.SchemaURL,
-							semconv.ServiceNameKey.String("dolt"),
-						)),
-					)
-					otel.SetTracerProvider(tp)
-					defer tp.Shutdown(context.Background())
-					args = args[1:]
+					cli.PrintErrln(fmt.Sprintf("invalid argument for the %s flag: %s", args[0], err.Error()))
+					return 1
 				}
+
+				args = args[2:]
+
+			case otlpInsecureFlag:
+				tracing.otlpInsecure = true
+				args = args[1:]
+
 			// Currently goland doesn't support running with a different working directory when using go modules.
 			// This is a hack that allows a different working directory to be set after the application starts using
 			// chdir=<DIR>.  The syntax is not flexible and must match exactly this.
@@ -404,12 +449,64 @@ func runMain() int {
 			case verboseEngineSetupFlag:
 				verboseEngineSetup = true
 				args = args[1:]
+
+			// Write logs, and the messages the cli output helpers write to
+			// stderr, as JSON records for log aggregators to ingest.
+			case logFormatFlag:
+				if len(args) < 2 {
+					cli.PrintErrln("missing argument for the --log-format flag")
+					return 1
+				}
+
+				var err error
+				logFormat, err = cli.ParseLogFormat(args[1])
+				if err != nil {
+					cli.PrintErrln(err.Error())
+					return 1
+				}
+
+				args = args[2:]
+
+			// Write the output of the read-only commands, such as status and
+			// branch, as JSON or CSV for scripts to consume.
+			case outputFormatFlag:
+				if len(args) < 2 {
+					cli.PrintErrln("missing argument for the --format flag")
+					return 1
+				}
+
+				var err error
+				outputFormat, err = cli.ParseOutputFormat(args[1])
+				if err != nil {
+					cli.PrintErrln(err.Error())
+					return 1
+				}
+
+				args = args[2:]
+
 			default:
 				doneDebugFlags = true
 			}
 		}
 	}
 
+	cli.SetLogFormat(logFormat)
+	cli.SetOutputFormat(outputFormat)
+
+	stopProfiling, err := profiling.start()
+	if err != nil {
+		cli.PrintErrln(color.RedString("Failed to start profiling: %v", err))
+		return 1
+	}
+	defer stopProfiling()
+
+	shutdownTracing, err := tracing.start(context.Background())
+	if err != nil {
+		cli.PrintErrln(color.RedString("Failed to start tracing: %v", err))
+		return 1
+	}
+	defer shutdownTracing(context.Background())
+
 	seedGlobalRand()
 
 	restoreIO := cli.InitIO()
@@ -472,8 +569,6 @@ func runMain() int {
 		return 1
 	}
 
-	emitter := events.NewFileEmitter(root, dbfactory.DoltDir)
-
 	defer func() {
 		ces := events.GlobalCollector.Close()
 		// events.WriterEmitter{cli.CliOut}.LogEvents(Version, ces)
@@ -490,12 +585,29 @@ func runMain() int {
 			return
 		}
 
+		emitterCfg, ok := events.EmitterConfigOverride()
+		if !ok {
+			emitterCfg = events.EmitterConfig{
+				Types:         events.ParseEmitterTypes(dEnv.Config.GetStringOrDefault(env.MetricsEmitter, events.EmitterTypeFile)),
+				StatsdAddress: dEnv.Config.GetStringOrDefault(env.MetricsStatsdAddress, events.DefaultStatsdAddress),
+				OtlpEndpoint:  dEnv.Config.GetStringOrDefault(env.MetricsOtlpEndpoint, events.DefaultOtlpEndpoint),
+			}
+		}
+
+		emitter, err := events.NewEmitter(emitterCfg, root, dbfactory.DoltDir)
+		if err != nil {
+			// log.Print(err)
+			return
+		}
+
 		// write events
 		_ = emitter.LogEvents(Version, ces)
 
-		// flush events
-		if err := processEventsDir(args, dEnv); err != nil {
-			// log.Print(err)
+		// flush events written to the events dir
+		if emitterCfg.HasType(events.EmitterTypeFile) {
+			if err := processEventsDir(args, dEnv); err != nil {
+				// log.Print(err)
+			}
 		}
 	}()
 
@@ -690,6 +802,9 @@ If you're interested in running this command against a remote host, hit us up on
 		return nil, err
 	}
 	if isLocked {
+		if lock.IsRemote() {
+			return nil, fmt.Errorf("database is locked by a sql-server on host %s; connect to it with dolt --host %s --port %d", lock.Host, lock.Host, lock.Port)
+		}
 		if verbose {
 			cli.Println("verbose: starting remote mode")
 		}
@@ -710,7 +825,11 @@ If you're interested in running this command against a remote host, hit us up on
 // otherwise you only see these docs if you specify a nonsense argument before the `sql` subcommand.
 var doc = cli.CommandDocumentationContent{
 	ShortDesc: "Dolt is git for data",
-	LongDesc:  `Dolt comprises of multiple subcommands that allow users to import, export, update, and manipulate data with SQL.`,
+	LongDesc: `Dolt comprises of multiple subcommands that allow users to import, export, update, and manipulate data with SQL.
+
+The status, branch, log, tag, remote, backup and ls commands write their output as JSON or CSV instead of text when {{.EmphasisLeft}}--format json{{.EmphasisRight}} or {{.EmphasisLeft}}--format csv{{.EmphasisRight}} is given before the subcommand.
+
+The global arguments {{.EmphasisLeft}}--host{{.EmphasisRight}}, {{.EmphasisLeft}}--port{{.EmphasisRight}}, {{.EmphasisLeft}}--user{{.EmphasisRight}}, {{.EmphasisLeft}}--password{{.EmphasisRight}}, {{.EmphasisLeft}}--use-db{{.EmphasisRight}} and {{.EmphasisLeft}}--data-dir{{.EmphasisRight}} can also be set with the environment variables {{.EmphasisLeft}}DOLT_HOST{{.EmphasisRight}}, {{.EmphasisLeft}}DOLT_PORT{{.EmphasisRight}}, {{.EmphasisLeft}}DOLT_USER{{.EmphasisRight}}, {{.EmphasisLeft}}DOLT_PASSWORD{{.EmphasisRight}}, {{.EmphasisLeft}}DOLT_USE_DB{{.EmphasisRight}} and {{.EmphasisLeft}}DOLT_DATA_DIR{{.EmphasisRight}}. A value given on the command line takes precedence over the environment, which takes precedence over the profile in use.`,
 
 	Synopsis: []string{
 		"<--data-dir=<path>> subcommand <subcommand arguments>",
@@ -762,7 +881,8 @@ func interceptSendMetrics(ctx context.Context, args []string) (bool, int) {
 	return true, doltCommand.Exec(ctx, "dolt", args, dEnv, nil)
 }
 
-// parseGlobalArgsAndSubCommandName parses the global arguments, including a profile if given or a default profile if exists. Also returns the subcommand name.
+// parseGlobalArgsAndSubCommandName parses the global arguments, including those set by environment variables, and a
+// profile if given or a default profile if exists. Also returns the subcommand name.
 func parseGlobalArgsAndSubCommandName(globalConfig config.ReadWriteConfig, args []string) (apr *argparser.ArgParseResults, remaining []string, subcommandName string, err error) {
 	apr, remaining, err = globalArgParser.ParseGlobalArgs(args)
 	if err != nil {
@@ -771,6 +891,17 @@ func parseGlobalArgsAndSubCommandName(globalConfig config.ReadWriteConfig, args
 
 	subcommandName = remaining[0]
 
+	if supportsGlobalArgs(subcommandName) {
+		envArgs := globalArgsFromEnv(apr)
+		if len(envArgs) > 0 {
+			args = append(envArgs, args...)
+			apr, remaining, err = globalArgParser.ParseGlobalArgs(args)
+			if err != nil {
+				return nil, nil, "", err
+			}
+		}
+	}
+
 	useDefaultProfile := false
 	profileName, hasProfile := apr.GetValue(commands.ProfileFlag)
 	encodedProfiles, err := globalConfig.GetString(commands.GlobalCfgProfileKey)
@@ -818,19 +949,46 @@ func parseGlobalArgsAndSubCommandName(globalConfig config.ReadWriteConfig, args
 	return
 }
 
+// globalArgEnvVars are the environment variables which set global arguments that aren't given on the command line.
+// They take precedence over the values of a profile.
+var globalArgEnvVars = []struct {
+	flag   string
+	envVar string
+}{
+	{cli.HostFlag, dconfig.EnvDoltHost},
+	{cli.PortFlag, dconfig.EnvDoltPort},
+	{cli.UserFlag, dconfig.EnvDoltUser},
+	{cli.PasswordFlag, dconfig.EnvDoltPassword},
+	{commands.UseDbFlag, dconfig.EnvDoltUseDb},
+	{commands.DataDirFlag, dconfig.EnvDoltDataDir},
+}
+
+// globalArgsFromEnv returns the args (as flags) and values set by |globalArgEnvVars| for the global arguments that
+// aren't in |apr|. Empty values are ignored, except for the password, which may be empty.
+func globalArgsFromEnv(apr *argparser.ArgParseResults) (result []string) {
+	for _, arg := range globalArgEnvVars {
+		if apr.Contains(arg.flag) {
+			continue
+		}
+		val, ok := os.LookupEnv(arg.envVar)
+		if !ok || (val == "" && arg.flag != cli.PasswordFlag) {
+			continue
+		}
+		result = append(result, "--"+arg.flag, val)
+	}
+	return result
+}
+
 // getProfile retrieves the given profile from the provided list of profiles and returns the args (as flags) and values
 // for that profile in a []string. If the profile is not found, an error is returned.
 func getProfile(apr *argparser.ArgParseResults, profileName, profiles string) (result []string, err error) {
 	prof := gjson.Get(profiles, profileName)
 	if prof.Exists() {
-		hasPassword := false
-		password := ""
 		for flag, value := range prof.Map() {
 			if !apr.Contains(flag) {
-				if flag == cli.PasswordFlag {
-					password = value.Str
-				} else if flag == "has-password" {
-					hasPassword = value.Bool()
+				if flag == cli.PasswordFlag || flag == "has-password" || flag == "keychain" || flag == "credential-helper" {
+					// the password is resolved below, only if it isn't given on the command line
+					continue
 				} else if flag == cli.NoTLSFlag {
 					if value.Bool() {
 						result = append(result, "--"+flag)
@@ -843,8 +1001,19 @@ func getProfile(apr *argparser.ArgParseResults, profileName, profiles string) (r
 				}
 			}
 		}
-		if !apr.Contains(cli.PasswordFlag) && hasPassword {
-			result = append(result, "--"+cli.PasswordFlag, password)
+		if !apr.Contains(cli.PasswordFlag) {
+			var p commands.Profile
+			err := json.Unmarshal([]byte(prof.Raw), &p)
+			if err != nil {
+				return nil, err
+			}
+			password, hasPassword, err := commands.ProfilePassword(profileName, p)
+			if err != nil {
+				return nil, err
+			}
+			if hasPassword {
+				result = append(result, "--"+cli.PasswordFlag, password)
+			}
 		}
 		return result, nil
 	} else {
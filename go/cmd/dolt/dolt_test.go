@@ -0,0 +1,71 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+	"github.com/dolthub/dolt/go/libraries/utils/config"
+)
+
+func TestGlobalArgsPrecedence(t *testing.T) {
+	profile := commands.Profile{
+		User:        "profileUser",
+		Password:    "profilePassword",
+		HasPassword: true,
+		Host:        "profileHost",
+		Port:        "1111",
+		UseDB:       "profileDb",
+	}
+	profiles := `{"test":` + profile.String() + `}`
+	cfg := config.NewMapConfig(map[string]string{
+		commands.GlobalCfgProfileKey: base64.StdEncoding.EncodeToString([]byte(profiles)),
+	})
+
+	t.Setenv(dconfig.EnvDoltHost, "envHost")
+	t.Setenv(dconfig.EnvDoltUser, "envUser")
+	t.Setenv(dconfig.EnvDoltUseDb, "")
+
+	apr, remaining, subcommandName, err := parseGlobalArgsAndSubCommandName(cfg, []string{"--profile", "test", "--user", "flagUser", "sql", "-q", "show tables"})
+	require.NoError(t, err)
+	assert.Equal(t, "sql", subcommandName)
+	assert.Equal(t, []string{"sql", "-q", "show tables"}, remaining)
+
+	// the flag takes precedence over the environment, which takes precedence over the profile
+	assert.Equal(t, "flagUser", apr.MustGetValue(cli.UserFlag))
+	assert.Equal(t, "envHost", apr.MustGetValue(cli.HostFlag))
+	assert.Equal(t, "1111", apr.MustGetValue(cli.PortFlag))
+	assert.Equal(t, "profilePassword", apr.MustGetValue(cli.PasswordFlag))
+	// empty environment variables are ignored
+	assert.Equal(t, "profileDb", apr.MustGetValue(commands.UseDbFlag))
+
+	t.Setenv(dconfig.EnvDoltPassword, "")
+	apr, _, _, err = parseGlobalArgsAndSubCommandName(cfg, []string{"--profile", "test", "sql"})
+	require.NoError(t, err)
+	assert.Equal(t, "envUser", apr.MustGetValue(cli.UserFlag))
+	assert.Equal(t, "", apr.MustGetValue(cli.PasswordFlag))
+
+	// commands that don't support global arguments ignore the environment
+	apr, _, _, err = parseGlobalArgsAndSubCommandName(config.NewMapConfig(map[string]string{}), []string{"init"})
+	require.NoError(t, err)
+	assert.False(t, apr.Contains(cli.HostFlag))
+}
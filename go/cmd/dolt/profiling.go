@@ -0,0 +1,237 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pkg/profile"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+)
+
+const defaultPprofServerAddr = "0.0.0.0:6060"
+
+// profilingConfig is how this run of Dolt profiles itself. With --prof, a profile of the whole run is written. With
+// --pprof-server or --pprof-addr, the pprof endpoints are served over HTTP. With --prof-threshold, CPU and heap
+// profiles are written only if the command runs for longer than the threshold. Profiles are written to --prof-dir.
+type profilingConfig struct {
+	// mode is the kind of profile written for the whole run, one of cpu, mem, blocking or trace.
+	mode string
+
+	pprofServer bool
+	// pprofServerAddr is the host and port the pprof server listens on.
+	pprofServerAddr string
+
+	// threshold is how long a command runs before its profiles are written, or zero to never write them.
+	threshold time.Duration
+	// dir is the directory profiles are written to. Defaults to a temporary directory for --prof, and to the
+	// system's temporary directory for --prof-threshold.
+	dir string
+}
+
+func newProfilingConfig() profilingConfig {
+	return profilingConfig{pprofServerAddr: defaultPprofServerAddr}
+}
+
+func (cfg *profilingConfig) setMode(mode string) error {
+	switch mode {
+	case cpuProf, memProf, blockingProf, traceProf:
+		cfg.mode = mode
+	default:
+		return fmt.Errorf("unsupported profile '%s', expected one of %s, %s, %s or %s", mode, cpuProf, memProf, blockingProf, traceProf)
+	}
+	return nil
+}
+
+// setPprofServerAddr sets the address the pprof server listens on, given as host:port, or as a port to listen on all
+// interfaces.
+func (cfg *profilingConfig) setPprofServerAddr(addr string) error {
+	if _, err := strconv.Atoi(addr); err == nil {
+		addr = net.JoinHostPort("0.0.0.0", addr)
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return fmt.Errorf("address '%s' is not of the form host:port", addr)
+	}
+	cfg.pprofServer = true
+	cfg.pprofServerAddr = addr
+	return nil
+}
+
+func (cfg *profilingConfig) setThreshold(threshold string) error {
+	d, err := time.ParseDuration(threshold)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("threshold '%s' is not a positive duration, such as 30s or 5m", threshold)
+	}
+	cfg.threshold = d
+	return nil
+}
+
+// start starts the configured profiling, and returns a function that stops it and writes the profiles.
+func (cfg profilingConfig) start() (func(), error) {
+	if cfg.mode == cpuProf && cfg.threshold > 0 {
+		return nil, fmt.Errorf("--prof %s can't be used with --prof-threshold, which also profiles the CPU", cpuProf)
+	}
+	if cfg.dir != "" {
+		if err := os.MkdirAll(cfg.dir, os.ModePerm); err != nil {
+			return nil, fmt.Errorf("failed to create the profile directory: %w", err)
+		}
+	}
+
+	var stops []func()
+	if cfg.mode != "" {
+		stops = append(stops, cfg.startProfile())
+	}
+	if cfg.pprofServer {
+		go servePprof(cfg.pprofServerAddr)
+	}
+	if cfg.threshold > 0 {
+		stop, err := cfg.startThresholdProfile()
+		if err != nil {
+			return nil, err
+		}
+		stops = append(stops, stop)
+	}
+
+	return func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}, nil
+}
+
+func (cfg profilingConfig) startProfile() func() {
+	opts := []func(*profile.Profile){profile.NoShutdownHook}
+	if cfg.dir != "" {
+		opts = append(opts, profile.ProfilePath(cfg.dir))
+	}
+
+	switch cfg.mode {
+	case cpuProf:
+		cli.Println("cpu profiling enabled.")
+		opts = append(opts, profile.CPUProfile)
+	case memProf:
+		cli.Println("mem profiling enabled.")
+		opts = append(opts, profile.MemProfile)
+	case blockingProf:
+		cli.Println("block profiling enabled")
+		opts = append(opts, profile.BlockProfile)
+	case traceProf:
+		cli.Println("trace profiling enabled")
+		opts = append(opts, profile.TraceProfile)
+	}
+	return profile.Start(opts...).Stop
+}
+
+// startThresholdProfile starts profiling the CPU to a temporary file, and writes a heap profile once the command has
+// run for |cfg.threshold|. The returned function stops profiling the CPU, and keeps the CPU profile only if the heap
+// profile was written.
+func (cfg profilingConfig) startThresholdProfile() (func(), error) {
+	dir := cfg.dir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	prefix := filepath.Join(dir, fmt.Sprintf("dolt-%s-%d", time.Now().Format("20060102T150405"), os.Getpid()))
+
+	cpuFile, err := os.CreateTemp(dir, ".dolt-cpu-*.pprof")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(cpuFile); err != nil {
+		cpuFile.Close()
+		os.Remove(cpuFile.Name())
+		return nil, fmt.Errorf("failed to start profiling the CPU: %w", err)
+	}
+
+	exceeded := make(chan struct{})
+	timer := time.AfterFunc(cfg.threshold, func() {
+		defer close(exceeded)
+		heapPath := prefix + "-heap.pprof"
+		if err := writeHeapProfile(heapPath); err != nil {
+			cli.PrintErrln(color.YellowString("failed to write the heap profile: %v", err))
+			return
+		}
+		cli.PrintErrln(color.YellowString("dolt has run for longer than %v, wrote a heap profile to %s", cfg.threshold, heapPath))
+	})
+
+	return func() {
+		pprof.StopCPUProfile()
+		cpuFile.Close()
+
+		if timer.Stop() {
+			os.Remove(cpuFile.Name())
+			return
+		}
+		<-exceeded
+
+		cpuPath := prefix + "-cpu.pprof"
+		if err := os.Rename(cpuFile.Name(), cpuPath); err != nil {
+			cli.PrintErrln(color.YellowString("failed to write the CPU profile: %v", err))
+			return
+		}
+		cli.PrintErrln(color.YellowString("wrote a CPU profile to %s", cpuPath))
+	}, nil
+}
+
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = pprof.Lookup("heap").WriteTo(f, 0)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// servePprof serves the pprof endpoints setup in the init function run when "net/http/pprof" is imported on |addr|.
+func servePprof(addr string) {
+	host, port, _ := net.SplitHostPort(addr)
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	endpoint := "http://" + net.JoinHostPort(host, port) + "/debug/pprof"
+
+	cyanStar := color.CyanString("*")
+	cli.Println(cyanStar, "Starting pprof server on", addr+".")
+	cli.Println(cyanStar, "Go to", color.CyanString(endpoint), "in a browser to see supported endpoints.")
+	cli.Println(cyanStar)
+	cli.Println(cyanStar, "Known endpoints are:")
+	cli.Println(cyanStar, "  /allocs: A sampling of all past memory allocations")
+	cli.Println(cyanStar, "  /block: Stack traces that led to blocking on synchronization primitives")
+	cli.Println(cyanStar, "  /cmdline: The command line invocation of the current program")
+	cli.Println(cyanStar, "  /goroutine: Stack traces of all current goroutines")
+	cli.Println(cyanStar, "  /heap: A sampling of memory allocations of live objects. You can specify the gc GET parameter to run GC before taking the heap sample.")
+	cli.Println(cyanStar, "  /mutex: Stack traces of holders of contended mutexes")
+	cli.Println(cyanStar, "  /profile: CPU profile. You can specify the duration in the seconds GET parameter. After you get the profile file, use the go tool pprof command to investigate the profile.")
+	cli.Println(cyanStar, "  /threadcreate: Stack traces that led to the creation of new OS threads")
+	cli.Println(cyanStar, "  /trace: A trace of execution of the current program. You can specify the duration in the seconds GET parameter. After you get the trace file, use the go tool trace command to investigate the trace.")
+	cli.Println()
+
+	err := http.ListenAndServe(addr, nil)
+
+	if err != nil {
+		cli.Println(color.YellowString("pprof server exited with error: %v", err))
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfilingConfig(t *testing.T) {
+	cfg := newProfilingConfig()
+	assert.False(t, cfg.pprofServer)
+	assert.Equal(t, defaultPprofServerAddr, cfg.pprofServerAddr)
+
+	require.NoError(t, cfg.setPprofServerAddr("localhost:7070"))
+	assert.True(t, cfg.pprofServer)
+	assert.Equal(t, "localhost:7070", cfg.pprofServerAddr)
+	require.NoError(t, cfg.setPprofServerAddr("7071"))
+	assert.Equal(t, "0.0.0.0:7071", cfg.pprofServerAddr)
+	assert.Error(t, cfg.setPprofServerAddr("localhost"))
+
+	require.NoError(t, cfg.setThreshold("30s"))
+	assert.Equal(t, 30*time.Second, cfg.threshold)
+	assert.Error(t, cfg.setThreshold("0s"))
+	assert.Error(t, cfg.setThreshold("thirty"))
+
+	require.NoError(t, cfg.setMode(memProf))
+	assert.Error(t, cfg.setMode("disk"))
+	require.NoError(t, cfg.setMode(cpuProf))
+	_, err := cfg.start()
+	assert.Error(t, err)
+}
+
+func TestThresholdProfile(t *testing.T) {
+	cfg := newProfilingConfig()
+	cfg.dir = filepath.Join(t.TempDir(), "profiles")
+	cfg.threshold = time.Hour
+
+	// a command that runs for less than the threshold leaves no profiles behind
+	stop, err := cfg.start()
+	require.NoError(t, err)
+	stop()
+	entries, err := os.ReadDir(cfg.dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	cfg.threshold = time.Millisecond
+	stop, err = cfg.start()
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	stop()
+
+	cpu, err := filepath.Glob(filepath.Join(cfg.dir, "dolt-*-cpu.pprof"))
+	require.NoError(t, err)
+	assert.Len(t, cpu, 1)
+	heap, err := filepath.Glob(filepath.Join(cfg.dir, "dolt-*-heap.pprof"))
+	require.NoError(t, err)
+	assert.Len(t, heap, 1)
+}
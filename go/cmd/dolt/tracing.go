@@ -0,0 +1,236 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+)
+
+const (
+	otlpProtocolGRPC = "grpc"
+	otlpProtocolHTTP = "http"
+
+	jaegerCollectorEndpoint = "http://localhost:14268/api/traces"
+)
+
+// tracingConfig is how this run of Dolt exports its traces. Traces are exported to a Jaeger collector running on
+// localhost with --jaeger, and to an OpenTelemetry collector with --otlp-endpoint or DOLT_OTLP_ENDPOINT. The
+// --otlp-* and --trace-sample-rate flags take precedence over their environment variables.
+type tracingConfig struct {
+	jaeger bool
+
+	// otlpEndpoint is the collector's URL. Its scheme is http for a plaintext connection, and https for TLS. Without
+	// a scheme, it's the collector's host and port, and connections use TLS unless otlpInsecure is set.
+	otlpEndpoint string
+	// otlpProtocol is the protocol traces are exported to the collector with, "grpc" or "http".
+	otlpProtocol string
+	otlpHeaders  map[string]string
+	otlpInsecure bool
+
+	// sampleRate is the fraction of traces that are sampled, between 0 and 1. Spans whose parent was sampled are
+	// always sampled.
+	sampleRate float64
+}
+
+// tracingConfigFromEnv returns the tracing configured by the environment.
+func tracingConfigFromEnv() (tracingConfig, error) {
+	cfg := tracingConfig{
+		otlpEndpoint: os.Getenv(dconfig.EnvOtlpEndpoint),
+		otlpProtocol: otlpProtocolGRPC,
+		otlpHeaders:  make(map[string]string),
+		sampleRate:   1,
+	}
+	if protocol := os.Getenv(dconfig.EnvOtlpProtocol); protocol != "" {
+		if err := cfg.setOtlpProtocol(protocol); err != nil {
+			return tracingConfig{}, fmt.Errorf("invalid %s: %w", dconfig.EnvOtlpProtocol, err)
+		}
+	}
+	if headers := os.Getenv(dconfig.EnvOtlpHeaders); headers != "" {
+		for _, header := range strings.Split(headers, ",") {
+			if err := cfg.addOtlpHeader(header); err != nil {
+				return tracingConfig{}, fmt.Errorf("invalid %s: %w", dconfig.EnvOtlpHeaders, err)
+			}
+		}
+	}
+	if insecure := os.Getenv(dconfig.EnvOtlpInsecure); insecure != "" {
+		b, err := strconv.ParseBool(insecure)
+		if err != nil {
+			return tracingConfig{}, fmt.Errorf("invalid %s: %w", dconfig.EnvOtlpInsecure, err)
+		}
+		cfg.otlpInsecure = b
+	}
+	if rate := os.Getenv(dconfig.EnvTraceSampleRate); rate != "" {
+		if err := cfg.setSampleRate(rate); err != nil {
+			return tracingConfig{}, fmt.Errorf("invalid %s: %w", dconfig.EnvTraceSampleRate, err)
+		}
+	}
+	return cfg, nil
+}
+
+func (cfg *tracingConfig) setOtlpProtocol(protocol string) error {
+	switch strings.ToLower(protocol) {
+	case otlpProtocolGRPC:
+		cfg.otlpProtocol = otlpProtocolGRPC
+	case otlpProtocolHTTP, "http/protobuf":
+		cfg.otlpProtocol = otlpProtocolHTTP
+	default:
+		return fmt.Errorf("unsupported OTLP protocol '%s', expected '%s' or '%s'", protocol, otlpProtocolGRPC, otlpProtocolHTTP)
+	}
+	return nil
+}
+
+// addOtlpHeader adds a header, given as key=value, that's sent to the collector with each export.
+func (cfg *tracingConfig) addOtlpHeader(header string) error {
+	key, value, ok := strings.Cut(header, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("header '%s' is not of the form key=value", header)
+	}
+	cfg.otlpHeaders[key] = strings.TrimSpace(value)
+	return nil
+}
+
+func (cfg *tracingConfig) setSampleRate(rate string) error {
+	f, err := strconv.ParseFloat(rate, 64)
+	if err != nil || f < 0 || f > 1 {
+		return fmt.Errorf("sample rate '%s' is not a number between 0 and 1", rate)
+	}
+	cfg.sampleRate = f
+	return nil
+}
+
+func (cfg tracingConfig) enabled() bool {
+	return cfg.jaeger || cfg.otlpEndpoint != ""
+}
+
+// start sets the global tracer provider to one that exports to the configured collectors, and returns a function
+// that flushes and shuts it down. Exporters that can't be created are skipped with a warning.
+func (cfg tracingConfig) start(ctx context.Context) (func(context.Context) error, error) {
+	if !cfg.enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []tracesdk.TracerProviderOption{
+		tracesdk.WithSampler(tracesdk.ParentBased(tracesdk.TraceIDRatioBased(cfg.sampleRate))),
+		tracesdk.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String("dolt"),
+			semconv.ServiceVersionKey.String(Version),
+		)),
+	}
+
+	if cfg.jaeger {
+		cli.Println("running with jaeger tracing reporting to localhost")
+		exp, err := jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(jaegerCollectorEndpoint)))
+		if err != nil {
+			cli.Println(color.YellowString("could not create jaeger collector: %v", err))
+		} else {
+			opts = append(opts, tracesdk.WithBatcher(exp))
+		}
+	}
+
+	if cfg.otlpEndpoint != "" {
+		exp, err := cfg.newOtlpExporter(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, tracesdk.WithBatcher(exp))
+	}
+
+	tp := tracesdk.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+func (cfg tracingConfig) newOtlpExporter(ctx context.Context) (tracesdk.SpanExporter, error) {
+	endpoint, path, insecure, err := parseOtlpEndpoint(cfg.otlpEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	insecure = insecure || cfg.otlpInsecure
+
+	switch cfg.otlpProtocol {
+	case otlpProtocolHTTP:
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(endpoint),
+			otlptracehttp.WithHeaders(cfg.otlpHeaders),
+		}
+		if path != "" {
+			opts = append(opts, otlptracehttp.WithURLPath(path))
+		}
+		if insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		} else {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(&tls.Config{}))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(endpoint),
+			otlptracegrpc.WithHeaders(cfg.otlpHeaders),
+		}
+		if insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		} else {
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, "")))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	}
+}
+
+// parseOtlpEndpoint returns the host and port of the collector at |endpoint|, the URL path traces are exported to
+// over OTLP/HTTP, and whether the endpoint's scheme is plaintext http.
+func parseOtlpEndpoint(endpoint string) (hostPort, path string, insecure bool, err error) {
+	if !strings.Contains(endpoint, "://") {
+		return endpoint, "", false, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", false, fmt.Errorf("invalid OTLP endpoint '%s': %w", endpoint, err)
+	}
+	switch u.Scheme {
+	case "http":
+		insecure = true
+	case "https":
+	default:
+		return "", "", false, fmt.Errorf("invalid OTLP endpoint '%s': unsupported scheme '%s'", endpoint, u.Scheme)
+	}
+	if u.Host == "" {
+		return "", "", false, fmt.Errorf("invalid OTLP endpoint '%s': missing host", endpoint)
+	}
+	if u.Path != "" && u.Path != "/" {
+		path = u.Path
+	}
+	return u.Host, path, insecure, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+)
+
+func TestParseOtlpEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		hostPort string
+		path     string
+		insecure bool
+		err      bool
+	}{
+		{endpoint: "localhost:4317", hostPort: "localhost:4317"},
+		{endpoint: "http://localhost:4318", hostPort: "localhost:4318", insecure: true},
+		{endpoint: "https://api.honeycomb.io", hostPort: "api.honeycomb.io"},
+		{endpoint: "https://tempo.example.com:4318/otlp/v1/traces", hostPort: "tempo.example.com:4318", path: "/otlp/v1/traces"},
+		{endpoint: "grpc://localhost:4317", err: true},
+		{endpoint: "https://", err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.endpoint, func(t *testing.T) {
+			hostPort, path, insecure, err := parseOtlpEndpoint(test.endpoint)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.hostPort, hostPort)
+			assert.Equal(t, test.path, path)
+			assert.Equal(t, test.insecure, insecure)
+		})
+	}
+}
+
+func TestTracingConfigFromEnv(t *testing.T) {
+	t.Setenv(dconfig.EnvOtlpEndpoint, "https://api.honeycomb.io")
+	t.Setenv(dconfig.EnvOtlpProtocol, "http/protobuf")
+	t.Setenv(dconfig.EnvOtlpHeaders, "x-honeycomb-team=abc, x-honeycomb-dataset=dolt")
+	t.Setenv(dconfig.EnvTraceSampleRate, "0.25")
+
+	cfg, err := tracingConfigFromEnv()
+	require.NoError(t, err)
+	assert.True(t, cfg.enabled())
+	assert.Equal(t, otlpProtocolHTTP, cfg.otlpProtocol)
+	assert.Equal(t, map[string]string{"x-honeycomb-team": "abc", "x-honeycomb-dataset": "dolt"}, cfg.otlpHeaders)
+	assert.Equal(t, 0.25, cfg.sampleRate)
+
+	// flags take precedence
+	require.NoError(t, cfg.setOtlpProtocol("grpc"))
+	require.NoError(t, cfg.addOtlpHeader("x-honeycomb-team=def"))
+	assert.Equal(t, otlpProtocolGRPC, cfg.otlpProtocol)
+	assert.Equal(t, "def", cfg.otlpHeaders["x-honeycomb-team"])
+
+	assert.Error(t, cfg.setOtlpProtocol("thrift"))
+	assert.Error(t, cfg.addOtlpHeader("novalue"))
+	assert.Error(t, cfg.setSampleRate("1.5"))
+
+	t.Setenv(dconfig.EnvTraceSampleRate, "all")
+	_, err = tracingConfigFromEnv()
+	assert.Error(t, err)
+}
@@ -19,6 +19,7 @@ import (
 	goerrors "errors"
 	"fmt"
 	"os"
+	"strings"
 	"sync/atomic"
 
 	flatbuffers "github.com/dolthub/flatbuffers/v23/go"
@@ -324,6 +325,34 @@ func CanDeleteBranch(ctx context.Context, branchName string) error {
 	return ErrCannotDeleteBranch.New(user, host, branchName)
 }
 
+// CanModifyBranch returns whether the given context can change the branch with the given name without checking it
+// out, such as by editing its metadata. As with CanDeleteBranch, contexts without a session are always allowed.
+func CanModifyBranch(ctx context.Context, branchName string) error {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	// A nil session means we're not in the SQL context, so we allow the operation
+	if branchAwareSession == nil {
+		return nil
+	}
+	controller := branchAwareSession.GetController()
+	// Any context that has a non-nil session should always have a non-nil controller, so this is an error
+	if controller == nil {
+		return ErrMissingController.New()
+	}
+	controller.Access.RWMutex.RLock()
+	defer controller.Access.RWMutex.RUnlock()
+
+	user := branchAwareSession.GetUser()
+	host := branchAwareSession.GetHost()
+	database := branchAwareSession.GetCurrentDatabase()
+	// Get the permissions for the branch, user, and host combination
+	_, perms := controller.Access.Match(database, branchName, user, host)
+	// If the user has the write or admin flags, then we allow access
+	if (perms&Permissions_Write == Permissions_Write) || (perms&Permissions_Admin == Permissions_Admin) {
+		return nil
+	}
+	return ErrIncorrectPermissions.New(user, host, branchName)
+}
+
 // AddAdminForContext adds an entry in the access table for the user represented by the given context. If the
 // context is missing some functionality that is needed to perform the addition, such as a user or the Controller, then
 // this simply returns.
@@ -353,6 +382,73 @@ func AddAdminForContext(ctx context.Context, branchName string) error {
 	return SaveData(ctx)
 }
 
+// CopyBranchPermissions copies the access entries that name |oldBranch| exactly, rather than through a pattern, so that
+// they also apply to |newBranch|. Only entries for the context's current database are copied. If |move| is true, the
+// entries for |oldBranch| are removed as well. If the context is missing some functionality that is needed to perform
+// the copy, such as a user or the Controller, then this simply returns.
+func CopyBranchPermissions(ctx context.Context, oldBranch, newBranch string, move bool) error {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return nil
+	}
+	controller := branchAwareSession.GetController()
+	if controller == nil {
+		return nil
+	}
+
+	database := strings.ToLower(FoldExpression(branchAwareSession.GetCurrentDatabase()))
+	oldBranch = strings.ToLower(FoldExpression(oldBranch))
+
+	controller.Access.RWMutex.Lock()
+	var rows []AccessRow
+	iter := controller.Access.Iter()
+	for row, ok := iter.Next(); ok; row, ok = iter.Next() {
+		if row.Database == database && row.Branch == oldBranch {
+			rows = append(rows, row)
+		}
+	}
+	for _, row := range rows {
+		controller.Access.Insert(row.Database, newBranch, row.User, row.Host, row.Permissions)
+		if move {
+			controller.Access.Delete(row.Database, row.Branch, row.User, row.Host)
+		}
+	}
+	controller.Access.RWMutex.Unlock()
+
+	if len(rows) == 0 {
+		return nil
+	}
+	return SaveData(ctx)
+}
+
+// BranchAdmins returns the users, formatted as user@host, that were granted admin permissions on exactly the branch
+// given in the current database. These are the users that own the branch, such as the user that created it.
+func BranchAdmins(ctx context.Context, branchName string) []string {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return nil
+	}
+	controller := branchAwareSession.GetController()
+	if controller == nil {
+		return nil
+	}
+
+	database := strings.ToLower(FoldExpression(branchAwareSession.GetCurrentDatabase()))
+	branchName = strings.ToLower(FoldExpression(branchName))
+
+	controller.Access.RWMutex.RLock()
+	defer controller.Access.RWMutex.RUnlock()
+
+	var admins []string
+	iter := controller.Access.Iter()
+	for row, ok := iter.Next(); ok; row, ok = iter.Next() {
+		if row.Database == database && row.Branch == branchName && row.Permissions&Permissions_Admin == Permissions_Admin {
+			admins = append(admins, fmt.Sprintf("%s@%s", row.User, row.Host))
+		}
+	}
+	return admins
+}
+
 // GetBranchAwareSession returns the session contained within the context. If the context does NOT contain a session,
 // then nil is returned.
 func GetBranchAwareSession(ctx context.Context) Context {
@@ -366,6 +462,20 @@ func GetBranchAwareSession(ctx context.Context) Context {
 	return nil
 }
 
+// HasSuperPrivilege returns whether the given context's user holds the global SUPER privilege. Contexts without a
+// session, such as those of local CLI commands, are always considered to be super users.
+func HasSuperPrivilege(ctx context.Context) bool {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	if branchAwareSession == nil {
+		return true
+	}
+	privSet, counter := branchAwareSession.GetPrivilegeSet()
+	if counter == 0 {
+		return false
+	}
+	return privSet.Has(sql.PrivilegeType_Super)
+}
+
 // HasDatabasePrivileges returns whether the given context's user has the correct privileges to modify any table entries
 // that match the given database. The following are the required privileges:
 //
@@ -0,0 +1,164 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitfmt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+func drawGraph(history [][]string) string {
+	g := NewGraph()
+	var lines []string
+	for _, c := range history {
+		row := g.Next(c[0], c[1:])
+		lines = append(lines, row.Prefix([]string{c[0]})...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestGraph(t *testing.T) {
+	t.Run("linear", func(t *testing.T) {
+		assert.Equal(t, "* c\n* b\n* a", drawGraph([][]string{{"c", "b"}, {"b", "a"}, {"a"}}))
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		history := [][]string{
+			{"merge", "main", "feature"},
+			{"feature", "base"},
+			{"main", "base"},
+			{"base", "root"},
+			{"root"},
+		}
+		expected := "" +
+			"*   merge\n" +
+			"|\\\n" +
+			"| * feature\n" +
+			"* | main\n" +
+			"|/\n" +
+			"* base\n" +
+			"* root"
+		assert.Equal(t, expected, drawGraph(history))
+	})
+
+	t.Run("unmerged branches", func(t *testing.T) {
+		history := [][]string{
+			{"main", "base"},
+			{"b2", "b1"},
+			{"b1", "base"},
+			{"base"},
+		}
+		expected := "" +
+			"* main\n" +
+			"| * b2\n" +
+			"| * b1\n" +
+			"|/\n" +
+			"* base"
+		assert.Equal(t, expected, drawGraph(history))
+	})
+
+	t.Run("join across columns", func(t *testing.T) {
+		history := [][]string{
+			{"a", "base"},
+			{"b", "x"},
+			{"c", "base"},
+			{"x"},
+			{"base"},
+		}
+		expected := "" +
+			"* a\n" +
+			"| * b\n" +
+			"| | * c\n" +
+			"|_|/\n" +
+			"| * x\n" +
+			"* base"
+		assert.Equal(t, expected, drawGraph(history))
+	})
+
+	t.Run("root with columns after it", func(t *testing.T) {
+		history := [][]string{
+			{"a"},
+			{"b"},
+		}
+		assert.Equal(t, "* a\n* b", drawGraph(history))
+
+		history = [][]string{
+			{"a", "x"},
+			{"b", "y"},
+			{"x"},
+			{"y"},
+		}
+		expected := "" +
+			"* a\n" +
+			"| * b\n" +
+			"* | x\n" +
+			" /\n" +
+			"* y"
+		assert.Equal(t, expected, drawGraph(history))
+	})
+
+	t.Run("padding", func(t *testing.T) {
+		g := NewGraph()
+		row := g.Next("merge", []string{"main", "feature"})
+		assert.Equal(t, []string{"*   commit merge", "|\\  Merge: main feature", "| | Author: me", "| |"}, row.Prefix([]string{"commit merge", "Merge: main feature", "Author: me", ""}))
+		row = g.Next("feature", []string{"main"})
+		assert.Equal(t, []string{"| * feature", "|/"}, row.Prefix([]string{"feature"}))
+	})
+}
+
+func TestFormat(t *testing.T) {
+	meta := &datas.CommitMeta{
+		Name:          "Jane Doe",
+		Email:         "jane@example.com",
+		Description:   "subject line\n\nbody line 1\nbody line 2",
+		UserTimestamp: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC).UnixMilli(),
+	}
+	c := Commit{Hash: "h1", Parents: []string{"p1", "p2"}, Meta: meta, Refs: "HEAD -> main, tag: v1"}
+
+	f, err := ParseFormat("format:%H %P|%an <%ae>|%at|%s|%b|%d|%D%n100%%|%x")
+	require.NoError(t, err)
+	assert.Equal(t, "h1 p1 p2|Jane Doe <jane@example.com>|1672628645|subject line|body line 1\nbody line 2| (HEAD -> main, tag: v1)|HEAD -> main, tag: v1\n100%|%x", f.Expand(c))
+
+	f, err = ParseFormat("%B%")
+	require.NoError(t, err)
+	assert.Equal(t, meta.Description+"%", f.Expand(c))
+
+	f, err = ParseFormat("tformat:%s")
+	require.NoError(t, err)
+	assert.Equal(t, "subject line", f.Expand(c))
+
+	f, err = ParseFormat(PrettyOneline)
+	require.NoError(t, err)
+	assert.Equal(t, "h1 (HEAD -> main, tag: v1) subject line  body line 1 body line 2", f.Expand(c))
+
+	f, err = ParseFormat(PrettyMedium)
+	require.NoError(t, err)
+	expected := "commit h1 (HEAD -> main, tag: v1)\n" +
+		"Merge: p1 p2\n" +
+		"Author: Jane Doe <jane@example.com>\n" +
+		"Date:  " + meta.FormatTS() + "\n" +
+		"\n" +
+		"\tsubject line\n\t\n\tbody line 1\n\tbody line 2"
+	assert.Equal(t, expected, f.Expand(c))
+
+	_, err = ParseFormat("fuller")
+	assert.Error(t, err)
+}
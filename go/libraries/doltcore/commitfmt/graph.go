@@ -0,0 +1,210 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package commitfmt formats commits for logs: the ASCII graph of their history, and the pretty formats that describe
+// each of them.
+package commitfmt
+
+import (
+	"strings"
+)
+
+// Graph draws the ASCII graph of a history, one commit at a time, in the style of `git log --graph`: a "*" for each
+// commit, in a column of "|" for each line of history, with "\" where a merge commit's lines split off and "/" where
+// lines join again.
+//
+// Each column of the graph is a line of history, which ends at the commit it's waiting for. Commits must be added in
+// an order in which every commit comes before its parents, such as the reverse topological order of a log.
+type Graph struct {
+	// columns are the hashes of the commits that each column is waiting for
+	columns []string
+}
+
+// Row is the part of a graph drawn for one commit.
+type Row struct {
+	// Lines are the lines of the graph for the commit: the line with the commit itself, followed by any lines that
+	// connect its columns to the commits after it.
+	Lines []string
+	// Padding is the line of the graph drawn beside any text about the commit beyond its Lines.
+	Padding string
+}
+
+// NewGraph returns an empty graph.
+func NewGraph() *Graph {
+	return &Graph{}
+}
+
+// Next adds the commit |h|, whose parents are |parents|, to the graph, and returns the row drawn for it.
+func (g *Graph) Next(h string, parents []string) Row {
+	idx := -1
+	for i, col := range g.columns {
+		if col == h {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		// a commit no column is waiting for starts a new column, like a branch head
+		g.columns = append(g.columns, h)
+		idx = len(g.columns) - 1
+	}
+
+	line := make([]string, len(g.columns))
+	for i := range g.columns {
+		line[i] = "|"
+	}
+	line[idx] = "*"
+	lines := []string{strings.Join(line, " ")}
+
+	if len(parents) == 0 {
+		// the column ends at a root commit, and the columns after it move left to take its place
+		lines = append(lines, g.shiftLeft(idx)...)
+		g.columns = append(g.columns[:idx], g.columns[idx+1:]...)
+	} else {
+		g.columns[idx] = parents[0]
+		for i, p := range parents[1:] {
+			lines = append(lines, g.split(idx+i))
+			g.columns = append(g.columns[:idx+i+1], append([]string{p}, g.columns[idx+i+1:]...)...)
+		}
+	}
+	lines = append(lines, g.collapse()...)
+
+	return Row{Lines: lines, Padding: g.padding()}
+}
+
+// split returns the line that splits column |idx| in two, like a merge commit's line does for its second parent, with
+// the columns after it moving right to make room.
+func (g *Graph) split(idx int) string {
+	line := newLine(len(g.columns) + 1)
+	for i := range g.columns {
+		switch {
+		case i < idx:
+			line.set(2*i, '|')
+		case i == idx:
+			line.set(2*i, '|')
+			line.set(2*i+1, '\\')
+		default:
+			line.set(2*i+1, '\\')
+		}
+	}
+	return line.String()
+}
+
+// shiftLeft returns the lines that move the columns after |idx| left, to take the place of column |idx| once it ends.
+func (g *Graph) shiftLeft(idx int) []string {
+	if idx+1 >= len(g.columns) {
+		return nil
+	}
+	line := newLine(len(g.columns))
+	for i := range g.columns {
+		if i < idx {
+			line.set(2*i, '|')
+		} else if i > idx {
+			line.set(2*i-1, '/')
+		}
+	}
+	return []string{line.String()}
+}
+
+// collapse merges the columns waiting for the same commit, which the lines of history join at, into the leftmost of
+// them, and returns the lines that join them.
+func (g *Graph) collapse() []string {
+	var lines []string
+	for {
+		dup, into := -1, -1
+		for j := 1; j < len(g.columns) && dup < 0; j++ {
+			for i := 0; i < j; i++ {
+				if g.columns[i] == g.columns[j] {
+					dup, into = j, i
+					break
+				}
+			}
+		}
+		if dup < 0 {
+			return lines
+		}
+
+		line := newLine(len(g.columns))
+		for i := range g.columns {
+			switch {
+			case i <= into:
+				line.set(2*i, '|')
+			case i < dup:
+				line.set(2*i, '|')
+				line.set(2*i-1, '_')
+			default:
+				line.set(2*i-1, '/')
+			}
+		}
+		lines = append(lines, line.String())
+		g.columns = append(g.columns[:dup], g.columns[dup+1:]...)
+	}
+}
+
+func (g *Graph) padding() string {
+	line := make([]string, len(g.columns))
+	for i := range g.columns {
+		line[i] = "|"
+	}
+	return strings.Join(line, " ")
+}
+
+// Prefix returns the lines of |text| about the commit with the row drawn beside them. The graph lines continue past
+// the end of the text if the row has more lines than it.
+func (r Row) Prefix(text []string) []string {
+	width := len(r.Padding)
+	for _, l := range r.Lines {
+		if len(l) > width {
+			width = len(l)
+		}
+	}
+	n := len(text)
+	if len(r.Lines) > n {
+		n = len(r.Lines)
+	}
+
+	lines := make([]string, n)
+	for i := range lines {
+		graph := r.Padding
+		if i < len(r.Lines) {
+			graph = r.Lines[i]
+		}
+		if i < len(text) && text[i] != "" {
+			lines[i] = graph + strings.Repeat(" ", width-len(graph)) + " " + text[i]
+		} else {
+			lines[i] = graph
+		}
+	}
+	return lines
+}
+
+type graphLine []byte
+
+func newLine(columns int) graphLine {
+	line := make(graphLine, 2*columns)
+	for i := range line {
+		line[i] = ' '
+	}
+	return line
+}
+
+func (l graphLine) set(i int, c byte) {
+	if i >= 0 && i < len(l) {
+		l[i] = c
+	}
+}
+
+func (l graphLine) String() string {
+	return strings.TrimRight(string(l), " ")
+}
@@ -0,0 +1,174 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitfmt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+const (
+	// PrettyOneline describes each commit on one line: its hash, refs and message.
+	PrettyOneline = "oneline"
+	// PrettyMedium describes each commit as the log does by default: its hash and refs, its author, date and message.
+	PrettyMedium = "medium"
+)
+
+// Commit is a commit described by a pretty format.
+type Commit struct {
+	Hash    string
+	Parents []string
+	Meta    *datas.CommitMeta
+	// Refs are the names of the refs that point at the commit, separated by commas, as shown by %D.
+	Refs string
+}
+
+// Format is a pretty format, which describes each commit of a log as text. It's either one of the built-in formats, or
+// a custom format whose template has placeholders for the fields of the commit:
+//
+//	%H   commit hash
+//	%P   parent hashes, separated by spaces
+//	%an  author name
+//	%ae  author email
+//	%ad  author date
+//	%at  author date, as a UNIX timestamp
+//	%aI  author date, in strict ISO 8601 format
+//	%s   subject, the first line of the message
+//	%b   body, the rest of the message
+//	%B   message
+//	%d   refs, like " (HEAD -> main, tag: v1)"
+//	%D   refs, without the parentheses
+//	%n   newline
+//	%%   a literal %
+type Format struct {
+	// Name is the name of the built-in format, or empty for a custom format.
+	Name string
+	// Template is the template of a custom format.
+	Template string
+}
+
+// ParseFormat parses the argument of --pretty or --format: the name of a built-in format, or a custom format given
+// as "format:<template>", or as a template with placeholders.
+func ParseFormat(s string) (Format, error) {
+	switch {
+	case s == PrettyOneline || s == PrettyMedium:
+		return Format{Name: s}, nil
+	case strings.HasPrefix(s, "format:"):
+		return Format{Template: strings.TrimPrefix(s, "format:")}, nil
+	case strings.HasPrefix(s, "tformat:"):
+		return Format{Template: strings.TrimPrefix(s, "tformat:")}, nil
+	case strings.Contains(s, "%"):
+		return Format{Template: s}, nil
+	default:
+		return Format{}, fmt.Errorf("invalid pretty format: %s, expected %s, %s or format:<template>", s, PrettyOneline, PrettyMedium)
+	}
+}
+
+// Expand returns the description of |c| in the format, without a trailing newline.
+func (f Format) Expand(c Commit) string {
+	switch f.Name {
+	case PrettyOneline:
+		return c.Hash + decoration(c.Refs) + " " + strings.Replace(c.Meta.Description, "\n", " ", -1)
+	case PrettyMedium:
+		var sb strings.Builder
+		sb.WriteString("commit " + c.Hash + decoration(c.Refs) + "\n")
+		if len(c.Parents) > 1 {
+			sb.WriteString("Merge: " + strings.Join(c.Parents, " ") + "\n")
+		}
+		sb.WriteString(fmt.Sprintf("Author: %s <%s>\n", c.Meta.Name, c.Meta.Email))
+		sb.WriteString(fmt.Sprintf("Date:  %s\n\n", c.Meta.FormatTS()))
+		sb.WriteString("\t" + strings.Replace(c.Meta.Description, "\n", "\n\t", -1))
+		return sb.String()
+	}
+
+	var sb strings.Builder
+	tmpl := f.Template
+	for len(tmpl) > 0 {
+		i := strings.IndexByte(tmpl, '%')
+		if i < 0 {
+			sb.WriteString(tmpl)
+			break
+		}
+		sb.WriteString(tmpl[:i])
+		tmpl = tmpl[i:]
+
+		val, n := placeholder(c, tmpl)
+		if n == 0 {
+			// not a placeholder, so it's written as it is
+			sb.WriteByte('%')
+			tmpl = tmpl[1:]
+			continue
+		}
+		sb.WriteString(val)
+		tmpl = tmpl[n:]
+	}
+	return sb.String()
+}
+
+// placeholder returns the value of the placeholder that |tmpl| starts with for |c|, and its length, or a length of 0
+// if it doesn't start with one.
+func placeholder(c Commit, tmpl string) (string, int) {
+	if len(tmpl) >= 3 && tmpl[1] == 'a' {
+		switch tmpl[2] {
+		case 'n':
+			return c.Meta.Name, 3
+		case 'e':
+			return c.Meta.Email, 3
+		case 'd':
+			return c.Meta.FormatTS(), 3
+		case 't':
+			return strconv.FormatInt(c.Meta.Time().Unix(), 10), 3
+		case 'I':
+			return c.Meta.Time().In(datas.CommitLoc).Format(time.RFC3339), 3
+		}
+	}
+	if len(tmpl) < 2 {
+		return "", 0
+	}
+	switch tmpl[1] {
+	case 'H':
+		return c.Hash, 2
+	case 'P':
+		return strings.Join(c.Parents, " "), 2
+	case 's':
+		subject, _, _ := strings.Cut(c.Meta.Description, "\n")
+		return subject, 2
+	case 'b':
+		_, body, _ := strings.Cut(c.Meta.Description, "\n")
+		return strings.TrimLeft(body, "\n"), 2
+	case 'B':
+		return c.Meta.Description, 2
+	case 'd':
+		return decoration(c.Refs), 2
+	case 'D':
+		return c.Refs, 2
+	case 'n':
+		return "\n", 2
+	case '%':
+		return "%", 2
+	}
+	return "", 0
+}
+
+func decoration(refs string) string {
+	if refs == "" {
+		return ""
+	}
+	return " (" + refs + ")"
+}
@@ -66,6 +66,10 @@ type singletonDB struct {
 var singletonLock = new(sync.Mutex)
 var singletons = make(map[string]singletonDB)
 
+// singletonPathLocks holds a lock for each path that a database has been opened at, so that only one database is
+// opened at a path, while databases at different paths are opened in parallel.
+var singletonPathLocks sync.Map
+
 func CloseAllLocalDatabases() (err error) {
 	singletonLock.Lock()
 	defer singletonLock.Unlock()
@@ -112,10 +116,14 @@ func (fact FileFactory) PrepareDB(ctx context.Context, nbf *types.NomsBinFormat,
 
 // CreateDB creates a local filesys backed database
 func (fact FileFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) (datas.Database, types.ValueReadWriter, tree.NodeStore, error) {
-	singletonLock.Lock()
-	defer singletonLock.Unlock()
+	pathLock, _ := singletonPathLocks.LoadOrStore(urlObj.Path, &sync.Mutex{})
+	pathLock.(*sync.Mutex).Lock()
+	defer pathLock.(*sync.Mutex).Unlock()
 
-	if s, ok := singletons[urlObj.Path]; ok {
+	singletonLock.Lock()
+	s, ok := singletons[urlObj.Path]
+	singletonLock.Unlock()
+	if ok {
 		return s.ddb, s.vrw, s.ns, nil
 	}
 
@@ -175,11 +183,13 @@ func (fact FileFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat,
 	ns := tree.NewNodeStore(st)
 	ddb := datas.NewTypesDatabase(vrw, ns)
 
+	singletonLock.Lock()
 	singletons[urlObj.Path] = singletonDB{
 		ddb: ddb,
 		vrw: vrw,
 		ns:  ns,
 	}
+	singletonLock.Unlock()
 
 	return ddb, vrw, ns, nil
 }
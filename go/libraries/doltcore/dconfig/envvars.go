@@ -40,4 +40,15 @@ const (
 	EnvDoltAssistAgree               = "DOLT_ASSIST_AGREE"
 	EnvDoltAuthorDate                = "DOLT_AUTHOR_DATE"
 	EnvDoltCommitterDate             = "DOLT_COMMITTER_DATE"
+	EnvOtlpEndpoint                  = "DOLT_OTLP_ENDPOINT"
+	EnvOtlpProtocol                  = "DOLT_OTLP_PROTOCOL"
+	EnvOtlpHeaders                   = "DOLT_OTLP_HEADERS"
+	EnvOtlpInsecure                  = "DOLT_OTLP_INSECURE"
+	EnvTraceSampleRate               = "DOLT_TRACE_SAMPLE_RATE"
+	EnvDoltHost                      = "DOLT_HOST"
+	EnvDoltPort                      = "DOLT_PORT"
+	EnvDoltUser                      = "DOLT_USER"
+	EnvDoltPassword                  = "DOLT_PASSWORD"
+	EnvDoltUseDb                     = "DOLT_USE_DB"
+	EnvDoltDataDir                   = "DOLT_DATA_DIR"
 )
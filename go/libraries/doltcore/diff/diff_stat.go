@@ -97,6 +97,60 @@ func StatForTableDelta(ctx context.Context, ch chan DiffStatProgress, td TableDe
 	}
 }
 
+// EstimateStatForTableDelta returns an estimated diff stat for the table delta given, computed with
+// tree.EstimateDiff from the cardinality of the subtrees that differ rather than by visiting every changed row. Cell
+// changes are not counted. Along with the stat, it returns whether the counts turned out to be exact, and whether an
+// estimate could be made at all: only keyed tables in the __DOLT__ format can be estimated, and callers should fall
+// back to StatForTableDelta for the others.
+func EstimateStatForTableDelta(ctx context.Context, td TableDelta, budget int) (stat DiffStatProgress, exact bool, ok bool, err error) {
+	if !types.IsFormat_DOLT(td.Format()) {
+		return DiffStatProgress{}, false, false, nil
+	}
+
+	fromSch, toSch, err := td.GetSchemas(ctx)
+	if err != nil {
+		return DiffStatProgress{}, false, false, errhand.BuildDError("cannot retrieve schema for table %s", td.ToName).AddCause(err).Build()
+	}
+	if !schema.ArePrimaryKeySetsDiffable(td.Format(), fromSch, toSch) {
+		return DiffStatProgress{}, false, false, fmt.Errorf("failed to compute diff stat for table %s: %w", td.CurName(), ErrPrimaryKeySetChanged)
+	}
+
+	keyless, err := td.IsKeyless(ctx)
+	if err != nil || keyless {
+		return DiffStatProgress{}, false, false, err
+	}
+
+	fromRows, toRows, err := td.GetRowData(ctx)
+	if err != nil {
+		return DiffStatProgress{}, false, false, err
+	}
+	fc, err := fromRows.Count()
+	if err != nil {
+		return DiffStatProgress{}, false, false, err
+	}
+	tc, err := toRows.Count()
+	if err != nil {
+		return DiffStatProgress{}, false, false, err
+	}
+
+	f := durable.ProllyMapFromIndex(fromRows)
+	t := durable.ProllyMapFromIndex(toRows)
+	est, err := tree.EstimateDiff(ctx, f.NodeStore(), t.NodeStore(), f.Node(), t.Node(), budget)
+	if err != nil {
+		return DiffStatProgress{}, false, false, err
+	}
+
+	return DiffStatProgress{
+		Adds:        est.Added,
+		Removes:     est.Removed,
+		Changes:     est.Modified,
+		OldRowSize:  fc,
+		NewRowSize:  tc,
+		OldCellSize: uint64(len(fromSch.GetAllCols().GetColumns())) * fc,
+		NewCellSize: uint64(len(toSch.GetAllCols().GetColumns())) * tc,
+	}, est.Exact, true, nil
+}
+
 func diffProllyTrees(ctx context.Context, ch chan DiffStatProgress, keyless bool, from, to durable.Index, fromSch, toSch schema.Schema) error {
 	_, vMapping, err := schema.MapSchemaBasedOnTagAndName(fromSch, toSch)
 	if err != nil {
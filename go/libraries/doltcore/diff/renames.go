@@ -0,0 +1,266 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// DefaultRenameSimilarity is the similarity, as a percentage of rows in common, that a dropped table and an added
+// table must have to be reported as a rename when renames are found by content.
+const DefaultRenameSimilarity = 50
+
+// RenameOptions configure how renamed tables are detected when diffing two roots. A table is always matched to a table
+// of another name that shares some of its column tags, as it does when it's renamed with RENAME TABLE. The zero value
+// matches renamed tables by their tags only.
+type RenameOptions struct {
+	// Disabled reports every renamed table as a dropped table and an added table.
+	Disabled bool
+	// Similarity is the percentage of rows that a dropped table and an added table which share no column tags, such as
+	// a table recreated under a new name, must have in common to be reported as a rename. Only tables with the same
+	// columns are compared. A Similarity of 0 doesn't match tables by their content.
+	Similarity int
+}
+
+// GetTableDeltasWithRenames returns the TableDeltas of the tables that changed between |fromRoot| and |toRoot|, like
+// GetTableDeltas, with renamed tables detected as |opts| configure.
+func GetTableDeltasWithRenames(ctx context.Context, fromRoot, toRoot *doltdb.RootValue, opts RenameOptions) ([]TableDelta, error) {
+	deltas, err := GetTableDeltas(ctx, fromRoot, toRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Disabled {
+		deltas = splitRenamedTableDeltas(deltas)
+	} else if opts.Similarity > 0 {
+		deltas, err = matchSimilarTableDeltas(ctx, deltas, opts.Similarity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].FromName == deltas[j].FromName {
+			return deltas[i].ToName < deltas[j].ToName
+		}
+		return deltas[i].FromName < deltas[j].FromName
+	})
+	return deltas, nil
+}
+
+// splitRenamedTableDeltas replaces each renamed table in |deltas| with a dropped table and an added table.
+func splitRenamedTableDeltas(deltas []TableDelta) []TableDelta {
+	split := make([]TableDelta, 0, len(deltas))
+	for _, td := range deltas {
+		if !td.IsRename() {
+			split = append(split, td)
+			continue
+		}
+
+		dropped := td
+		dropped.ToName, dropped.ToTable, dropped.ToSch, dropped.ToFks, dropped.ToFksParentSch = "", nil, nil, nil, nil
+		added := td
+		added.FromName, added.FromTable, added.FromSch, added.FromFks, added.FromFksParentSch = "", nil, nil, nil, nil
+		split = append(split, dropped, added)
+	}
+	return split
+}
+
+type renameCandidate struct {
+	from, to   int
+	similarity int
+}
+
+// matchSimilarTableDeltas matches the dropped and added tables in |deltas| that have at least |similarity| percent of
+// their rows in common, best matches first, and returns the deltas with each match as a renamed table.
+func matchSimilarTableDeltas(ctx context.Context, deltas []TableDelta, similarity int) ([]TableDelta, error) {
+	var dropped, added []int
+	for i, td := range deltas {
+		if td.IsDrop() {
+			dropped = append(dropped, i)
+		} else if td.IsAdd() {
+			added = append(added, i)
+		}
+	}
+	if len(dropped) == 0 || len(added) == 0 {
+		return deltas, nil
+	}
+
+	var candidates []renameCandidate
+	for _, f := range dropped {
+		for _, t := range added {
+			s, err := tableSimilarity(ctx, deltas[f], deltas[t])
+			if err != nil {
+				return nil, err
+			}
+			if s > 0 && s >= similarity {
+				candidates = append(candidates, renameCandidate{from: f, to: t, similarity: s})
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].similarity > candidates[j].similarity
+	})
+
+	matched := make(map[int]bool)
+	var renamed []TableDelta
+	for _, c := range candidates {
+		if matched[c.from] || matched[c.to] {
+			continue
+		}
+		matched[c.from], matched[c.to] = true, true
+
+		td := deltas[c.from]
+		t := deltas[c.to]
+		// the tables' columns differ only in their tags, so the dropped table is described with the added table's tags
+		// to diff their rows
+		sch, err := retagSchema(td.FromSch, t.ToSch)
+		if err != nil {
+			return nil, err
+		}
+		td.FromSch = sch
+		td.ToName, td.ToTable, td.ToSch, td.ToFks, td.ToFksParentSch = t.ToName, t.ToTable, t.ToSch, t.ToFks, t.ToFksParentSch
+		renamed = append(renamed, td)
+	}
+
+	result := make([]TableDelta, 0, len(deltas))
+	for i, td := range deltas {
+		if !matched[i] {
+			result = append(result, td)
+		}
+	}
+	return append(result, renamed...), nil
+}
+
+// tableSimilarity returns the percentage of rows that the table dropped in |from| and the table added in |to| have in
+// common, or 0 if their columns differ, either table is empty, or the tables aren't stored in the __DOLT__ format.
+func tableSimilarity(ctx context.Context, from, to TableDelta) (int, error) {
+	if !types.IsFormat_DOLT(from.FromTable.Format()) || !types.IsFormat_DOLT(to.ToTable.Format()) {
+		return 0, nil
+	}
+	if !sameColumns(from.FromSch, to.ToSch) {
+		return 0, nil
+	}
+
+	fromIdx, err := from.FromTable.GetRowData(ctx)
+	if err != nil {
+		return 0, err
+	}
+	toIdx, err := to.ToTable.GetRowData(ctx)
+	if err != nil {
+		return 0, err
+	}
+	fromRows, toRows := durable.ProllyMapFromIndex(fromIdx), durable.ProllyMapFromIndex(toIdx)
+
+	fromCount, err := fromRows.Count()
+	if err != nil {
+		return 0, err
+	}
+	toCount, err := toRows.Count()
+	if err != nil {
+		return 0, err
+	}
+	if fromCount == 0 || toCount == 0 {
+		// there's nothing to compare, and empty tables with the same columns aren't likely to be the same table
+		return 0, nil
+	}
+	if fromRows.HashOf() == toRows.HashOf() {
+		return 100, nil
+	}
+
+	// each row of the dropped table that was removed or modified isn't in the added table
+	changed := 0
+	err = prolly.DiffMaps(ctx, fromRows, toRows, func(ctx context.Context, diff tree.Diff) error {
+		if diff.Type != tree.AddedDiff {
+			changed++
+		}
+		return nil
+	})
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+
+	most := fromCount
+	if toCount > most {
+		most = toCount
+	}
+	return (fromCount - changed) * 100 / most, nil
+}
+
+// sameColumns returns whether |from| and |to| have columns of the same names and types, in the same order, and the
+// same primary key, so that their rows can be compared.
+func sameColumns(from, to schema.Schema) bool {
+	if !from.GetKeyDescriptor().Equals(to.GetKeyDescriptor()) || !from.GetValueDescriptor().Equals(to.GetValueDescriptor()) {
+		return false
+	}
+	fromCols, toCols := from.GetAllCols().GetColumns(), to.GetAllCols().GetColumns()
+	if len(fromCols) != len(toCols) {
+		return false
+	}
+	for i := range fromCols {
+		if !strings.EqualFold(fromCols[i].Name, toCols[i].Name) || fromCols[i].IsPartOfPK != toCols[i].IsPartOfPK {
+			return false
+		}
+	}
+	return true
+}
+
+// retagSchema returns a copy of |sch| with the tags of the columns of |like|, which must have the same columns.
+func retagSchema(sch, like schema.Schema) (schema.Schema, error) {
+	tags := make(map[uint64]uint64)
+	likeCols := like.GetAllCols().GetColumns()
+	cols := sch.GetAllCols().GetColumns()
+	for i := range cols {
+		tags[cols[i].Tag] = likeCols[i].Tag
+		cols[i].Tag = likeCols[i].Tag
+	}
+	cc := schema.NewColCollection(cols...)
+	retagged, err := schema.NewSchema(cc, sch.GetPkOrdinals(), sch.GetCollation(), nil, sch.Checks())
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := schema.NewIndexCollection(cc, retagged.GetPKCols())
+	for _, idx := range sch.Indexes().AllIndexes() {
+		idxTags := make([]uint64, 0, len(idx.IndexedColumnTags()))
+		for _, tag := range idx.IndexedColumnTags() {
+			idxTags = append(idxTags, tags[tag])
+		}
+		_, err := indexes.UnsafeAddIndexByColTags(idx.Name(), idxTags, idx.PrefixLengths(), schema.IndexProperties{
+			IsUnique:           idx.IsUnique(),
+			IsSpatial:          idx.IsSpatial(),
+			IsFullText:         idx.IsFullText(),
+			IsUserDefined:      idx.IsUserDefined(),
+			Comment:            idx.Comment(),
+			FullTextProperties: idx.FullTextProperties(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return schema.NewSchema(cc, sch.GetPkOrdinals(), sch.GetCollation(), indexes, sch.Checks())
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// bisectRefPrefix is the prefix of the internal refs that record bisect sessions. The rest of the ref is the name of
+// the branch the session was started on.
+const bisectRefPrefix = "bisect/"
+
+// BisectState is the state of a bisect session: the commits marked good, bad and skipped, and the commit to be tested
+// next. Commits are recorded as hash strings.
+type BisectState struct {
+	Bad     string   `json:"bad,omitempty"`
+	Good    []string `json:"good,omitempty"`
+	Skip    []string `json:"skip,omitempty"`
+	Current string   `json:"current,omitempty"`
+}
+
+// BisectRef returns the internal ref that records the bisect session of the branch given.
+func BisectRef(branch string) ref.DoltRef {
+	return ref.NewInternalRef(bisectRefPrefix + branch)
+}
+
+// GetBisectState returns the state of the bisect session started on the branch given, or nil if there isn't one.
+func (ddb *DoltDB) GetBisectState(ctx context.Context, branch string) (*BisectState, error) {
+	ds, err := ddb.db.GetDataset(ctx, BisectRef(branch).String())
+	if err != nil {
+		return nil, err
+	}
+	if !ds.HasHead() || !ds.IsTag() {
+		return nil, nil
+	}
+
+	meta, _, err := ds.HeadTag()
+	if err != nil {
+		return nil, err
+	}
+
+	var state BisectState
+	err = json.Unmarshal([]byte(meta.Description), &state)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SetBisectState records the state of the bisect session started on the branch given. The state is recorded as a tag
+// of the commit at |addr|, which keeps it from being collected while the session is in progress.
+func (ddb *DoltDB) SetBisectState(ctx context.Context, branch string, state BisectState, addr hash.Hash, meta *datas.TagMeta, replicationStatus *ReplicationStatusController) error {
+	desc, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	meta.Description = string(desc)
+	for {
+		ds, err := ddb.db.GetDataset(ctx, BisectRef(branch).String())
+		if err != nil {
+			return err
+		}
+		err = ddb.setInternalTag(ctx, ds, addr, meta, replicationStatus)
+		if !errors.Is(err, datas.ErrMergeNeeded) {
+			return err
+		}
+	}
+}
+
+// DeleteBisectState ends the bisect session started on the branch given, if there is one.
+func (ddb *DoltDB) DeleteBisectState(ctx context.Context, branch string, replicationStatus *ReplicationStatusController) error {
+	ds, err := ddb.db.GetDataset(ctx, BisectRef(branch).String())
+	if err != nil || !ds.HasHead() {
+		return err
+	}
+	_, err = ddb.db.withReplicationStatusController(replicationStatus).Delete(ctx, ds)
+	return err
+}
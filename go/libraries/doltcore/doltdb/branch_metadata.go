@@ -0,0 +1,153 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// branchMetadataRefPrefix is the prefix of the internal refs that record branch metadata. The rest of the ref is the
+// branch's own ref without its leading "refs/", which keeps local and remote tracking branches apart.
+const branchMetadataRefPrefix = "branch_metadata/"
+
+// BranchMetadata is the description and arbitrary key/value metadata attached to a branch, e.g. its owner or the
+// ticket it was created for.
+type BranchMetadata struct {
+	Description string            `json:"description,omitempty"`
+	Values      map[string]string `json:"values,omitempty"`
+}
+
+// IsEmpty returns whether there is no metadata to record.
+func (md BranchMetadata) IsEmpty() bool {
+	return md.Description == "" && len(md.Values) == 0
+}
+
+// BranchMetadataRef returns the internal ref that records the metadata of the branch given.
+func BranchMetadataRef(branch ref.DoltRef) ref.DoltRef {
+	return ref.NewInternalRef(branchMetadataRefPrefix + strings.TrimPrefix(branch.String(), "refs/"))
+}
+
+// GetBranchMetadata returns the metadata recorded for the branch given, along with the meta of the user who last
+// changed it. The meta is nil if the branch has no metadata.
+func (ddb *DoltDB) GetBranchMetadata(ctx context.Context, branch ref.DoltRef) (BranchMetadata, *datas.TagMeta, error) {
+	ds, err := ddb.db.GetDataset(ctx, BranchMetadataRef(branch).String())
+	if err != nil {
+		return BranchMetadata{}, nil, err
+	}
+	return branchMetadataFromDataset(ds)
+}
+
+// GetBranchMetadataByNomsRoot is like GetBranchMetadata, but reads the metadata as of the noms root given.
+func (ddb *DoltDB) GetBranchMetadataByNomsRoot(ctx context.Context, branch ref.DoltRef, nomsRoot hash.Hash) (BranchMetadata, *datas.TagMeta, error) {
+	ds, err := ddb.db.GetDatasetByRootHash(ctx, BranchMetadataRef(branch).String(), nomsRoot)
+	if err != nil {
+		return BranchMetadata{}, nil, err
+	}
+	return branchMetadataFromDataset(ds)
+}
+
+func branchMetadataFromDataset(ds datas.Dataset) (BranchMetadata, *datas.TagMeta, error) {
+	if !ds.HasHead() || !ds.IsTag() {
+		return BranchMetadata{}, nil, nil
+	}
+
+	meta, _, err := ds.HeadTag()
+	if err != nil {
+		return BranchMetadata{}, nil, err
+	}
+
+	var md BranchMetadata
+	err = json.Unmarshal([]byte(meta.Description), &md)
+	if err != nil {
+		return BranchMetadata{}, nil, err
+	}
+
+	return md, meta, nil
+}
+
+// SetBranchMetadata records the metadata given for the branch given, replacing any existing metadata in a single
+// write. Like the default branch, the metadata lives in the database as a tag of the branch's head, so that it's
+// carried along by clones, pushes and replication. Recording empty metadata removes the record, and |meta| may be nil.
+func (ddb *DoltDB) SetBranchMetadata(ctx context.Context, branch ref.DoltRef, md BranchMetadata, meta *datas.TagMeta, replicationStatus *ReplicationStatusController) error {
+	if md.IsEmpty() {
+		return ddb.deleteBranchMetadata(ctx, branch, replicationStatus)
+	}
+
+	cm, err := ddb.ResolveCommitRef(ctx, branch)
+	if err != nil {
+		return err
+	}
+	commitAddr, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+
+	desc, err := json.Marshal(md)
+	if err != nil {
+		return err
+	}
+
+	meta.Description = string(desc)
+	for {
+		ds, err := ddb.db.GetDataset(ctx, BranchMetadataRef(branch).String())
+		if err != nil {
+			return err
+		}
+		err = ddb.setInternalTag(ctx, ds, commitAddr, meta, replicationStatus)
+		if !errors.Is(err, datas.ErrMergeNeeded) {
+			return err
+		}
+	}
+}
+
+func (ddb *DoltDB) deleteBranchMetadata(ctx context.Context, branch ref.DoltRef, replicationStatus *ReplicationStatusController) error {
+	ds, err := ddb.db.GetDataset(ctx, BranchMetadataRef(branch).String())
+	if err != nil || !ds.HasHead() {
+		return err
+	}
+	_, err = ddb.db.withReplicationStatusController(replicationStatus).Delete(ctx, ds)
+	return err
+}
+
+// CopyBranchMetadata copies the metadata of |srcBranch| in |srcDB| to |destBranch| in |destDB|, which may be the same
+// database. The destination branch must already exist in |destDB|. Nothing is written if the metadata is unchanged.
+func CopyBranchMetadata(ctx context.Context, srcDB *DoltDB, srcBranch ref.DoltRef, destDB *DoltDB, destBranch ref.DoltRef, replicationStatus *ReplicationStatusController) error {
+	md, meta, err := srcDB.GetBranchMetadata(ctx, srcBranch)
+	if err != nil {
+		return err
+	}
+
+	destMd, destMeta, err := destDB.GetBranchMetadata(ctx, destBranch)
+	if err != nil {
+		return err
+	}
+	if (meta == nil) == (destMeta == nil) && reflect.DeepEqual(md, destMd) {
+		return nil
+	}
+
+	if meta != nil {
+		// keep the original author and timestamp of the change
+		meta = &datas.TagMeta{Name: meta.Name, Email: meta.Email, Timestamp: meta.Timestamp, UserTimestamp: meta.UserTimestamp}
+	}
+	return destDB.SetBranchMetadata(ctx, destBranch, md, meta, replicationStatus)
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestBranchMetadata(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	defer ddb.Close()
+	err = ddb.WriteEmptyRepo(ctx, "main", "Bill Billerson", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	cs, _ := NewCommitSpec("main")
+	cm, err := ddb.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	branch := ref.NewBranchRef("feature")
+	require.NoError(t, ddb.NewBranchAtCommit(ctx, branch, cm, nil))
+
+	setMetadata := func(md BranchMetadata) {
+		meta := datas.NewTagMeta("Bill Billerson", "bigbillieb@fake.horse", "")
+		require.NoError(t, ddb.SetBranchMetadata(ctx, branch, md, meta, nil))
+	}
+
+	// setting metadata replaces whatever was recorded before
+	setMetadata(BranchMetadata{Description: "first"})
+	setMetadata(BranchMetadata{Description: "second", Values: map[string]string{"owner": "bill"}})
+	md, meta, err := ddb.GetBranchMetadata(ctx, branch)
+	require.NoError(t, err)
+	require.NotNil(t, meta)
+	assert.Equal(t, BranchMetadata{Description: "second", Values: map[string]string{"owner": "bill"}}, md)
+
+	// deleting the branch deletes its metadata
+	require.NoError(t, ddb.DeleteBranch(ctx, branch, nil))
+	_, meta, err = ddb.GetBranchMetadata(ctx, branch)
+	require.NoError(t, err)
+	assert.Nil(t, meta)
+
+	// and a new branch of the same name doesn't inherit a record left behind
+	require.NoError(t, ddb.NewBranchAtCommit(ctx, branch, cm, nil))
+	setMetadata(BranchMetadata{Description: "stale"})
+	ds, err := ddb.db.GetDataset(ctx, branch.String())
+	require.NoError(t, err)
+	_, err = ddb.db.Delete(ctx, ds)
+	require.NoError(t, err)
+	require.NoError(t, ddb.NewBranchAtCommit(ctx, branch, cm, nil))
+	_, meta, err = ddb.GetBranchMetadata(ctx, branch)
+	require.NoError(t, err)
+	assert.Nil(t, meta)
+}
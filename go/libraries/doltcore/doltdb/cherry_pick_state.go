@@ -0,0 +1,105 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// cherryPickRefPrefix is the prefix of the internal refs that record cherry-picks of several commits in progress. The
+// rest of the ref is the name of the branch the commits are being cherry-picked onto.
+const cherryPickRefPrefix = "cherry-pick/"
+
+// CherryPickState is the state of a cherry-pick of several commits that stopped at a commit with conflicts. Commits
+// are recorded as hash strings.
+type CherryPickState struct {
+	// OrigHead is the head of the branch before the cherry-pick, which it's reset to if the cherry-pick is aborted.
+	OrigHead string `json:"orig_head"`
+	// Commits are the commits to cherry-pick, oldest first.
+	Commits []string `json:"commits"`
+	// Next is the index of the commit the cherry-pick stopped at.
+	Next int `json:"next,omitempty"`
+}
+
+// CherryPickRef returns the internal ref that records the cherry-pick in progress on the branch given.
+func CherryPickRef(branch string) ref.DoltRef {
+	return ref.NewInternalRef(cherryPickRefPrefix + branch)
+}
+
+// GetCherryPickState returns the state of the cherry-pick of several commits in progress on the branch given, or nil
+// if there isn't one.
+func (ddb *DoltDB) GetCherryPickState(ctx context.Context, branch string) (*CherryPickState, error) {
+	ds, err := ddb.db.GetDataset(ctx, CherryPickRef(branch).String())
+	if err != nil {
+		return nil, err
+	}
+	if !ds.HasHead() || !ds.IsTag() {
+		return nil, nil
+	}
+
+	meta, _, err := ds.HeadTag()
+	if err != nil {
+		return nil, err
+	}
+
+	var state CherryPickState
+	err = json.Unmarshal([]byte(meta.Description), &state)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SetCherryPickState records the state of the cherry-pick of several commits in progress on the branch given. Like a
+// rebase, the state is recorded as a tag of the branch's original head.
+func (ddb *DoltDB) SetCherryPickState(ctx context.Context, branch string, state CherryPickState, meta *datas.TagMeta, replicationStatus *ReplicationStatusController) error {
+	addr, ok := hash.MaybeParse(state.OrigHead)
+	if !ok {
+		return errors.New("invalid original head in cherry-pick state: " + state.OrigHead)
+	}
+
+	desc, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	meta.Description = string(desc)
+	for {
+		ds, err := ddb.db.GetDataset(ctx, CherryPickRef(branch).String())
+		if err != nil {
+			return err
+		}
+		err = ddb.setInternalTag(ctx, ds, addr, meta, replicationStatus)
+		if !errors.Is(err, datas.ErrMergeNeeded) {
+			return err
+		}
+	}
+}
+
+// DeleteCherryPickState forgets the cherry-pick of several commits in progress on the branch given, if there is one.
+func (ddb *DoltDB) DeleteCherryPickState(ctx context.Context, branch string, replicationStatus *ReplicationStatusController) error {
+	ds, err := ddb.db.GetDataset(ctx, CherryPickRef(branch).String())
+	if err != nil || !ds.HasHead() {
+		return err
+	}
+	_, err = ddb.db.withReplicationStatusController(replicationStatus).Delete(ctx, ds)
+	return err
+}
@@ -0,0 +1,179 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// columnMasksRefPath is the path of the internal ref that records the database's column masking policies.
+const columnMasksRefPath = "column_masks"
+
+// MaskKind is how the values of a masked column are shown to users who can't unmask them.
+type MaskKind string
+
+const (
+	// MaskNull shows every value as NULL.
+	MaskNull MaskKind = "null"
+	// MaskRedact shows string values as asterisks, and other values as NULL.
+	MaskRedact MaskKind = "redact"
+	// MaskHash shows string values as the hex encoded HMAC-SHA-256 of the value, keyed with the database's
+	// ColumnMasks.Key, so that equal values can still be told apart from different ones, and other values as NULL.
+	MaskHash MaskKind = "hash"
+)
+
+// ParseMaskKind returns the MaskKind named by |s|.
+func ParseMaskKind(s string) (MaskKind, error) {
+	switch k := MaskKind(strings.ToLower(s)); k {
+	case MaskNull, MaskRedact, MaskHash:
+		return k, nil
+	default:
+		return "", fmt.Errorf("unknown mask '%s', expected one of: null, redact, hash", s)
+	}
+}
+
+// ColumnMask is the masking policy of a single column. Policies are kept outside of any branch's history, so that a
+// column masked today is masked in every commit, including those made before the policy was added.
+type ColumnMask struct {
+	// Table and Column are the names of the column and its table when it was masked.
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	// Tag is the tag of the masked column. Masks follow columns by their tags, rather than their names, so that
+	// renaming a column or its table doesn't unmask it, and a new table created with a masked table's name isn't
+	// masked.
+	Tag  uint64   `json:"tag"`
+	Kind MaskKind `json:"kind"`
+}
+
+// ColumnMasks are the column masking policies of a database.
+type ColumnMasks struct {
+	Masks []ColumnMask `json:"masks"`
+	// Key is the secret that MaskHash values are keyed with, so that a user who sees a hashed value can't find the
+	// value by hashing guesses of it. It's created along with the database's first policy.
+	Key []byte `json:"key"`
+}
+
+// MaskKind returns the mask of the column with the tag |tag|, if it's masked.
+func (m ColumnMasks) MaskKind(tag uint64) (MaskKind, bool) {
+	for _, mask := range m.Masks {
+		if mask.Tag == tag {
+			return mask.Kind, true
+		}
+	}
+	return "", false
+}
+
+// columnMaskKeyLen is the length of the key that masked values are hashed with.
+const columnMaskKeyLen = 32
+
+// ColumnMasksRef returns the internal ref that records the database's column masking policies.
+func ColumnMasksRef() ref.DoltRef {
+	return ref.NewInternalRef(columnMasksRefPath)
+}
+
+// GetColumnMasks returns the column masking policies of this database, ordered by table and column.
+func (ddb *DoltDB) GetColumnMasks(ctx context.Context) (ColumnMasks, error) {
+	ds, err := ddb.db.GetDataset(ctx, ColumnMasksRef().String())
+	if err != nil {
+		return ColumnMasks{}, err
+	}
+	if !ds.HasHead() || !ds.IsTag() {
+		return ColumnMasks{}, nil
+	}
+
+	meta, _, err := ds.HeadTag()
+	if err != nil {
+		return ColumnMasks{}, err
+	}
+
+	var masks ColumnMasks
+	err = json.Unmarshal([]byte(meta.Description), &masks)
+	if err != nil {
+		return ColumnMasks{}, err
+	}
+	return masks, nil
+}
+
+// ColumnMasksHash returns a hash that changes whenever the column masking policies of this database change, or the
+// empty hash if it has none.
+func (ddb *DoltDB) ColumnMasksHash(ctx context.Context) (hash.Hash, error) {
+	ds, err := ddb.db.GetDataset(ctx, ColumnMasksRef().String())
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	addr, _ := ds.MaybeHeadAddr()
+	return addr, nil
+}
+
+// SetColumnMasks records |masks| as the column masking policies of this database, replacing any existing policies.
+// Like branch metadata, the record is a tag, so that policies are carried along by clones, pushes and replication. The
+// tag points at the head of |branch|. The key of |masks| is kept if it's set, and created otherwise.
+func (ddb *DoltDB) SetColumnMasks(ctx context.Context, masks ColumnMasks, branch ref.DoltRef, meta *datas.TagMeta, replicationStatus *ReplicationStatusController) error {
+	ds, err := ddb.db.GetDataset(ctx, ColumnMasksRef().String())
+	if err != nil {
+		return err
+	}
+
+	db := ddb.db.withReplicationStatusController(replicationStatus)
+	// tags can't be moved, so the old record has to be deleted before the new one is written
+	if ds.HasHead() {
+		ds, err = db.Delete(ctx, ds)
+		if err != nil {
+			return err
+		}
+	}
+	if len(masks.Masks) == 0 {
+		return nil
+	}
+	if len(masks.Key) == 0 {
+		masks.Key = make([]byte, columnMaskKeyLen)
+		if _, err = rand.Read(masks.Key); err != nil {
+			return err
+		}
+	}
+
+	cm, err := ddb.ResolveCommitRef(ctx, branch)
+	if err != nil {
+		return err
+	}
+	commitAddr, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(masks.Masks, func(i, j int) bool {
+		if masks.Masks[i].Table != masks.Masks[j].Table {
+			return masks.Masks[i].Table < masks.Masks[j].Table
+		}
+		return masks.Masks[i].Column < masks.Masks[j].Column
+	})
+	desc, err := json.Marshal(masks)
+	if err != nil {
+		return err
+	}
+
+	meta.Description = string(desc)
+	_, err = db.Tag(ctx, ds, commitAddr, datas.TagOptions{Meta: meta})
+	return err
+}
@@ -81,6 +81,12 @@ func (c *Commit) GetCommitMeta(ctx context.Context) (*datas.CommitMeta, error) {
 	return datas.GetCommitMeta(ctx, c.dCommit.NomsValue())
 }
 
+// GetSignature returns the signature of the commit, and the signing payload it was made for. The signature is empty if
+// the commit isn't signed.
+func (c *Commit) GetSignature() ([]byte, string, error) {
+	return datas.GetCommitSignature(c.dCommit)
+}
+
 // DatasParents returns the []*datas.Commit of the commit parents.
 func (c *Commit) DatasParents() []*datas.Commit {
 	return c.parents
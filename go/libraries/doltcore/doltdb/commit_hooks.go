@@ -60,7 +60,7 @@ func pushDataset(ctx context.Context, destDB, srcDB datas.Database, ds datas.Dat
 		return err
 	}
 
-	err := pullHash(ctx, destDB, srcDB, []hash.Hash{addr}, tmpDir, nil)
+	err := pullHash(ctx, destDB, srcDB, []hash.Hash{addr}, nil, tmpDir, nil, PullOptions{})
 	if err != nil {
 		return err
 	}
@@ -104,6 +104,8 @@ type PushArg struct {
 	ds   datas.Dataset
 	db   datas.Database
 	hash hash.Hash
+	// safepoint keeps |hash| from being garbage collected until it's been pushed, in case |ds| moves on before then
+	safepoint *GCSafepoint
 }
 
 type AsyncPushOnWriteHook struct {
@@ -136,12 +138,20 @@ func (*AsyncPushOnWriteHook) ExecuteForWorkingSets() bool {
 
 // Execute implements CommitHook, replicates head updates to the destDb field
 func (ah *AsyncPushOnWriteHook) Execute(ctx context.Context, ds datas.Dataset, db datas.Database) (func(context.Context) error, error) {
-	addr, _ := ds.MaybeHeadAddr()
+	addr, ok := ds.MaybeHeadAddr()
+	var safepoint *GCSafepoint
+	if ok {
+		var err error
+		safepoint, err = registerGCSafepoint(ctx, db, addr)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	select {
-	case ah.ch <- PushArg{ds: ds, db: db, hash: addr}:
+	case ah.ch <- PushArg{ds: ds, db: db, hash: addr, safepoint: safepoint}:
 	case <-ctx.Done():
-		ah.ch <- PushArg{ds: ds, db: db, hash: addr}
+		ah.ch <- PushArg{ds: ds, db: db, hash: addr, safepoint: safepoint}
 		return nil, ctx.Err()
 	}
 	return nil, nil
@@ -206,6 +216,8 @@ func RunAsyncReplicationThreads(bThreads *sql.BackgroundThreads, ch chan PushArg
 
 	updateHead := func(p PushArg) {
 		mu.Lock()
+		// a head that's superseded before it's pushed is never pushed, so it no longer needs to be kept
+		newHeads[p.ds.ID()].safepoint.Release()
 		newHeads[p.ds.ID()] = p
 		mu.Unlock()
 	}
@@ -269,6 +281,7 @@ func RunAsyncReplicationThreads(bThreads *sql.BackgroundThreads, ch chan PushArg
 					latestHeads[id] = newCm.hash
 				}
 			}
+			newCm.safepoint.Release()
 		}
 	}
 
@@ -0,0 +1,241 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+)
+
+// commitMetaCacheSize is the number of commits whose metadata is kept in memory by a CommitMetaIndex.
+const commitMetaCacheSize = 64 * 1024
+
+// CommitMetaIndex indexes the metadata of commits (author, email and dates) by commit hash, so that the history of a
+// commit can be filtered by author or date without loading and decoding every commit in it. The history of a commit
+// is read from its commit closure, which lists all of its ancestors along with their heights, rather than by walking
+// the parents of each commit. Commits are immutable, so the metadata of each commit is only decoded the first time
+// it's indexed. It's persisted by chunk stores that implement chunks.CommitMetaStore, and the metadata of the commits
+// used most recently is kept in memory.
+type CommitMetaIndex struct {
+	cache *lru.Cache[hash.Hash, *datas.CommitMeta]
+}
+
+// CommitMetaEntry is a commit in a history, along with its height and its metadata. The metadata holds the author
+// and dates of the commit, but not its description.
+type CommitMetaEntry struct {
+	Hash   hash.Hash
+	Height uint64
+	Meta   *datas.CommitMeta
+}
+
+func newCommitMetaIndex() *CommitMetaIndex {
+	cache, err := lru.New[hash.Hash, *datas.CommitMeta](commitMetaCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &CommitMetaIndex{cache: cache}
+}
+
+// CommitMetaIndex returns the commit metadata index of this DoltDB.
+func (ddb *DoltDB) CommitMetaIndex() *CommitMetaIndex {
+	return ddb.metaIndex
+}
+
+// History returns an entry for |head| and for each of its ancestors whose metadata matches |filter|, which may be
+// nil to match every commit. Entries are ordered the same way as the commits of a topological walk of the history:
+// by height, descending, then by user timestamp, descending.
+func (idx *CommitMetaIndex) History(ctx context.Context, ddb *DoltDB, head *Commit, filter func(*datas.CommitMeta) (bool, error)) ([]CommitMetaEntry, error) {
+	headHash, err := head.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	headHeight, err := head.Height()
+	if err != nil {
+		return nil, err
+	}
+
+	history := []CommitMetaEntry{{Hash: headHash, Height: headHeight}}
+	if head.NumParents() > 0 {
+		closure, err := head.GetCommitClosure(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !closure.IsEmpty() {
+			itr, err := closure.IterAllReverse(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for {
+				k, _, err := itr.Next(ctx)
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					return nil, err
+				}
+				height, h := prolly.DecodeCommitClosureKey(k)
+				history = append(history, CommitMetaEntry{Hash: h, Height: height})
+			}
+		}
+	}
+
+	// the closure of a shallow clone's commit lists ancestors that were never fetched
+	missing, err := ddb.missingCommits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if missing.Size() > 0 {
+		fetched := history[:0]
+		for _, e := range history {
+			if !missing.Has(e.Hash) {
+				fetched = append(fetched, e)
+			}
+		}
+		history = fetched
+	}
+
+	hashes := make([]hash.Hash, len(history))
+	for i, e := range history {
+		hashes[i] = e.Hash
+	}
+	metas, err := idx.metas(ctx, ddb, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []CommitMetaEntry
+	for i, e := range history {
+		e.Meta = metas[i]
+		if filter != nil {
+			ok, err := filter(e.Meta)
+			if err != nil {
+				return nil, err
+			} else if !ok {
+				continue
+			}
+		}
+		matches = append(matches, e)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Height != matches[j].Height {
+			return matches[i].Height > matches[j].Height
+		}
+		return matches[i].Meta.UserTimestamp > matches[j].Meta.UserTimestamp
+	})
+	return matches, nil
+}
+
+// metas returns the metadata of the commits of |hashes|. Metadata is read from memory, then from the index persisted by
+// the chunk store, and last from the commits themselves, which are then added to the persisted index.
+func (idx *CommitMetaIndex) metas(ctx context.Context, ddb *DoltDB, hashes []hash.Hash) ([]*datas.CommitMeta, error) {
+	metas := make([]*datas.CommitMeta, len(hashes))
+	var uncached []hash.Hash
+	for i, h := range hashes {
+		if meta, ok := idx.cache.Get(h); ok {
+			metas[i] = meta
+		} else {
+			uncached = append(uncached, h)
+		}
+	}
+	if len(uncached) == 0 {
+		return metas, nil
+	}
+
+	store, _ := datas.ChunkStoreFromDatabase(ddb.db).(chunks.CommitMetaStore)
+	persisted := make(map[hash.Hash]chunks.CommitMetaRecord)
+	if store != nil {
+		var err error
+		persisted, err = store.GetCommitMetas(ctx, uncached)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var added []chunks.CommitMetaRecord
+	for i, h := range hashes {
+		if metas[i] != nil {
+			continue
+		}
+		rec, ok := persisted[h]
+		if !ok {
+			cm, err := ddb.ReadCommit(ctx, h)
+			if err != nil {
+				return nil, err
+			}
+			meta, err := cm.GetCommitMeta(ctx)
+			if err != nil {
+				return nil, err
+			}
+			rec = chunks.CommitMetaRecord{Addr: h, Name: meta.Name, Email: meta.Email, Timestamp: meta.Timestamp, UserTimestamp: meta.UserTimestamp}
+			added = append(added, rec)
+			persisted[h] = rec
+		}
+		metas[i] = &datas.CommitMeta{Name: rec.Name, Email: rec.Email, Timestamp: rec.Timestamp, UserTimestamp: rec.UserTimestamp}
+		idx.cache.Add(h, metas[i])
+	}
+
+	if store != nil && len(added) > 0 {
+		err := store.PutCommitMetas(ctx, added)
+		if err != nil && !errors.Is(err, chunks.ErrUnsupportedOperation) {
+			return nil, err
+		}
+	}
+	return metas, nil
+}
+
+// CommitMetaEntryItr is a CommitItr over a list of entries of a commit metadata index.
+type CommitMetaEntryItr struct {
+	ddb     *DoltDB
+	entries []CommitMetaEntry
+	curr    int
+}
+
+var _ CommitItr = (*CommitMetaEntryItr)(nil)
+
+// NewCommitMetaEntryItr returns a CommitItr over the commits of |entries|, in order. Each commit is only loaded when
+// it's returned.
+func NewCommitMetaEntryItr(ddb *DoltDB, entries []CommitMetaEntry) *CommitMetaEntryItr {
+	return &CommitMetaEntryItr{ddb: ddb, entries: entries}
+}
+
+// Next implements CommitItr
+func (itr *CommitMetaEntryItr) Next(ctx context.Context) (hash.Hash, *Commit, error) {
+	if itr.curr >= len(itr.entries) {
+		return hash.Hash{}, nil, io.EOF
+	}
+	h := itr.entries[itr.curr].Hash
+	itr.curr++
+
+	cm, err := itr.ddb.ReadCommit(ctx, h)
+	if err != nil {
+		return hash.Hash{}, nil, err
+	}
+	return h, cm, nil
+}
+
+// Reset implements CommitItr
+func (itr *CommitMetaEntryItr) Reset(context.Context) error {
+	itr.curr = 0
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateSpecFormats are the formats of the dates accepted in date specs. Dates without a time zone are in UTC.
+var dateSpecFormats = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+}
+
+// SplitDateSpec splits a date spec such as main@{2023-06-01}, which names the last commit on a branch made at or
+// before a time, into its ref name and time. Returns false if the string given isn't a date spec, and an error if it
+// is but its date can't be parsed.
+func SplitDateSpec(s string) (string, time.Time, bool, error) {
+	cleanStr := strings.TrimSpace(s)
+	if !strings.HasSuffix(cleanStr, "}") {
+		return "", time.Time{}, false, nil
+	}
+
+	idx := strings.LastIndex(cleanStr, "@{")
+	if idx <= 0 {
+		return "", time.Time{}, false, nil
+	}
+
+	refName, dateStr := cleanStr[:idx], cleanStr[idx+2:len(cleanStr)-1]
+	for _, format := range dateSpecFormats {
+		t, err := time.ParseInLocation(format, strings.TrimSpace(dateStr), time.UTC)
+		if err == nil {
+			return refName, t, true, nil
+		}
+	}
+
+	return "", time.Time{}, true, fmt.Errorf("invalid date spec '%s': '%s' is not a date", s, dateStr)
+}
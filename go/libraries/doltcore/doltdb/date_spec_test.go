@@ -0,0 +1,54 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitDateSpec(t *testing.T) {
+	tests := []struct {
+		inputStr     string
+		expectedRef  string
+		expectedTime time.Time
+		isDateSpec   bool
+		expectErr    bool
+	}{
+		{"main", "", time.Time{}, false, false},
+		{"main~2", "", time.Time{}, false, false},
+		{"@{2023-06-01}", "", time.Time{}, false, false},
+		{"main@{2023-06-01}", "main", time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), true, false},
+		{"feature/x@{2023-06-01 12:30:00}", "feature/x", time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC), true, false},
+		{"main@{2023-06-01T12:30:00-07:00}", "main", time.Date(2023, 6, 1, 19, 30, 0, 0, time.UTC), true, false},
+		{"main@{yesterday}", "", time.Time{}, true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.inputStr, func(t *testing.T) {
+			ref, tm, ok, err := SplitDateSpec(test.inputStr)
+			assert.Equal(t, test.isDateSpec, ok)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedRef, ref)
+			assert.True(t, test.expectedTime.Equal(tm))
+		})
+	}
+}
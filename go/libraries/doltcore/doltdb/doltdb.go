@@ -21,10 +21,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"golang.org/x/time/rate"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
@@ -53,6 +55,11 @@ const (
 const (
 	CreationBranch = "create"
 
+	// DefaultBranchRefName is the name of the internal ref that records a database's default branch. Because it lives
+	// in the database rather than in the repo state, it's carried along by clones, backups, read replicas and cluster
+	// replication.
+	DefaultBranchRefName = "default_branch"
+
 	defaultChunksPerTF = 256 * 1024
 )
 
@@ -72,6 +79,10 @@ type DoltDB struct {
 	db  hooksDatabase
 	vrw types.ValueReadWriter
 	ns  tree.NodeStore
+
+	metaIndex *CommitMetaIndex
+	// sparse is the sparse spec of a sparse clone, or nil
+	sparse *sparseSpec
 }
 
 // DoltDBFromCS creates a DoltDB from a noms chunks.ChunkStore
@@ -80,7 +91,7 @@ func DoltDBFromCS(cs chunks.ChunkStore) *DoltDB {
 	ns := tree.NewNodeStore(cs)
 	db := datas.NewTypesDatabase(vrw, ns)
 
-	return &DoltDB{hooksDatabase{Database: db}, vrw, ns}
+	return &DoltDB{hooksDatabase{Database: db}, vrw, ns, newCommitMetaIndex(), nil}
 }
 
 // HackDatasDatabaseFromDoltDB unwraps a DoltDB to a datas.Database.
@@ -122,7 +133,7 @@ func LoadDoltDBWithParams(ctx context.Context, nbf *types.NomsBinFormat, urlStr
 	if err != nil {
 		return nil, err
 	}
-	return &DoltDB{hooksDatabase{Database: db}, vrw, ns}, nil
+	return &DoltDB{hooksDatabase{Database: db}, vrw, ns, newCommitMetaIndex(), nil}, nil
 }
 
 // NomsRoot returns the hash of the noms dataset map
@@ -629,6 +640,23 @@ func (ddb *DoltDB) SetHeadToCommit(ctx context.Context, ref ref.DoltRef, cm *Com
 	return ddb.SetHead(ctx, ref, addr)
 }
 
+// SetHeadToCommitIfUnchanged sets the given ref to point at the given commit, as long as it still points at |curr|, or
+// doesn't exist if |curr| is empty. It returns datas.ErrMergeNeeded if the ref has changed.
+func (ddb *DoltDB) SetHeadToCommitIfUnchanged(ctx context.Context, ref ref.DoltRef, cm *Commit, curr hash.Hash) error {
+	addr, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+
+	ds, err := ddb.db.GetDataset(ctx, ref.String())
+	if err != nil {
+		return err
+	}
+
+	_, err = ddb.db.SetHeadIfUnchanged(ctx, ds, addr, curr)
+	return err
+}
+
 func (ddb *DoltDB) SetHead(ctx context.Context, ref ref.DoltRef, addr hash.Hash) error {
 	ds, err := ddb.db.GetDataset(ctx, ref.String())
 
@@ -997,6 +1025,53 @@ func (ddb *DoltDB) VisitRefsOfTypeByNomsRoot(ctx context.Context, refTypeFilter
 	return visitDatasets(ctx, refTypeFilter, visit, dss)
 }
 
+// RefUpdate is a branch or tag that differs between two noms roots. OldHash is empty for a created ref, and NewHash is
+// empty for a deleted one.
+type RefUpdate struct {
+	Ref     ref.DoltRef
+	OldHash hash.Hash
+	NewHash hash.Hash
+}
+
+var branchesAndTagsRefFilter = map[ref.RefType]struct{}{ref.BranchRefType: {}, ref.TagRefType: {}}
+
+// RefUpdatesByNomsRoot returns the branches and tags that were created, moved or deleted between the noms roots
+// |oldRoot| and |newRoot|, ordered by ref.
+func (ddb *DoltDB) RefUpdatesByNomsRoot(ctx context.Context, oldRoot, newRoot hash.Hash) ([]RefUpdate, error) {
+	updates := make(map[string]RefUpdate)
+	if !oldRoot.IsEmpty() {
+		err := ddb.VisitRefsOfTypeByNomsRoot(ctx, branchesAndTagsRefFilter, oldRoot, func(r ref.DoltRef, addr hash.Hash) error {
+			updates[r.String()] = RefUpdate{Ref: r, OldHash: addr}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := ddb.VisitRefsOfTypeByNomsRoot(ctx, branchesAndTagsRefFilter, newRoot, func(r ref.DoltRef, addr hash.Hash) error {
+		u, ok := updates[r.String()]
+		if ok && u.OldHash == addr {
+			delete(updates, r.String())
+			return nil
+		}
+		updates[r.String()] = RefUpdate{Ref: r, OldHash: u.OldHash, NewHash: addr}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]RefUpdate, 0, len(updates))
+	for _, u := range updates {
+		res = append(res, u)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Ref.String() < res[j].Ref.String()
+	})
+	return res, nil
+}
+
 func visitDatasets(ctx context.Context, refTypeFilter map[ref.RefType]struct{}, visit func(r ref.DoltRef, addr hash.Hash) error, dss datas.DatasetsMap) error {
 	return dss.IterAll(ctx, func(key string, addr hash.Hash) error {
 		keyStr := key
@@ -1090,6 +1165,14 @@ func (ddb *DoltDB) NewBranchAtCommit(ctx context.Context, branchRef ref.DoltRef,
 		return err
 	}
 
+	if !ds.HasHead() {
+		// a new branch doesn't inherit any metadata left behind by a deleted branch of the same name
+		err = ddb.deleteBranchMetadata(ctx, branchRef, replicationStatus)
+		if err != nil {
+			return err
+		}
+	}
+
 	_, err = ddb.db.SetHead(ctx, ds, addr)
 	if err != nil {
 		return err
@@ -1154,9 +1237,17 @@ func (ddb *DoltDB) CopyWorkingSet(ctx context.Context, fromWSRef ref.WorkingSetR
 	return ddb.UpdateWorkingSet(ctx, toWSRef, ws, currWsHash, TodoWorkingSetMeta(), nil)
 }
 
-// DeleteBranch deletes the branch given, returning an error if it doesn't exist.
+// DeleteBranch deletes the branch given, along with its metadata, returning an error if it doesn't exist.
 func (ddb *DoltDB) DeleteBranch(ctx context.Context, branch ref.DoltRef, replicationStatus *ReplicationStatusController) error {
-	return ddb.deleteRef(ctx, branch, replicationStatus)
+	err := ddb.deleteRef(ctx, branch, replicationStatus)
+	if err != nil {
+		return err
+	}
+
+	// The branch is gone once its ref is deleted, so removing its metadata is only cleanup. A record left behind by a
+	// failure here isn't shown for any branch, and is removed when a branch of the same name is created again.
+	_ = ddb.deleteBranchMetadata(ctx, branch, replicationStatus)
+	return nil
 }
 
 func (ddb *DoltDB) deleteRef(ctx context.Context, dref ref.DoltRef, replicationStatus *ReplicationStatusController) error {
@@ -1186,6 +1277,11 @@ func (ddb *DoltDB) deleteRef(ctx context.Context, dref ref.DoltRef, replicationS
 
 // NewTagAtCommit create a new tag at the commit given.
 func (ddb *DoltDB) NewTagAtCommit(ctx context.Context, tagRef ref.DoltRef, c *Commit, meta *datas.TagMeta) error {
+	return ddb.NewSignedTagAtCommit(ctx, tagRef, c, meta, nil)
+}
+
+// NewSignedTagAtCommit creates a new tag at the commit given, signed by |signer| if it isn't nil.
+func (ddb *DoltDB) NewSignedTagAtCommit(ctx context.Context, tagRef ref.DoltRef, c *Commit, meta *datas.TagMeta, signer datas.Signer) error {
 	if !IsValidTagRef(tagRef) {
 		panic(fmt.Sprintf("invalid tag name %s, use IsValidUserTagName check", tagRef.String()))
 	}
@@ -1205,13 +1301,70 @@ func (ddb *DoltDB) NewTagAtCommit(ctx context.Context, tagRef ref.DoltRef, c *Co
 		return err
 	}
 
-	tag := datas.TagOptions{Meta: meta}
+	tag := datas.TagOptions{Meta: meta, Signer: signer}
 
 	ds, err = ddb.db.Tag(ctx, ds, commitAddr, tag)
 
 	return err
 }
 
+// GetDefaultBranch returns the default branch recorded in this database by SetDefaultBranch, or the empty string if none
+// has been recorded.
+func (ddb *DoltDB) GetDefaultBranch(ctx context.Context) (string, error) {
+	ds, err := ddb.db.GetDataset(ctx, ref.NewInternalRef(DefaultBranchRefName).String())
+	if err != nil {
+		return "", err
+	}
+
+	if !ds.HasHead() || !ds.IsTag() {
+		return "", nil
+	}
+
+	meta, _, err := ds.HeadTag()
+	if err != nil {
+		return "", err
+	}
+
+	return meta.Description, nil
+}
+
+// SetDefaultBranch records the branch given as the default branch of this database. The branch must exist. The record
+// is a tag of the branch's current head, with the branch name as its description.
+func (ddb *DoltDB) SetDefaultBranch(ctx context.Context, branchName string, meta *datas.TagMeta, replicationStatus *ReplicationStatusController) error {
+	cm, err := ddb.ResolveCommitRef(ctx, ref.NewBranchRef(branchName))
+	if err != nil {
+		return err
+	}
+	commitAddr, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+
+	meta.Description = branchName
+	for {
+		ds, err := ddb.db.GetDataset(ctx, ref.NewInternalRef(DefaultBranchRefName).String())
+		if err != nil {
+			return err
+		}
+		err = ddb.setInternalTag(ctx, ds, commitAddr, meta, replicationStatus)
+		if !errors.Is(err, datas.ErrMergeNeeded) {
+			return err
+		}
+	}
+}
+
+// setInternalTag records a tag of |commitAddr| with the meta given as the head of |ds|, an internal dataset that
+// records something about the database, such as its default branch. Keeping such records as tags lets them be carried
+// along by clones, pushes and replication. Tags can't be moved, so any tag already recorded is replaced in a single
+// compare-and-set of the dataset's head. If the dataset has changed since |ds| was read, this returns
+// datas.ErrMergeNeeded and nothing is written. Commit hooks don't run for internal records, so they reach standbys
+// along with the next change to the database.
+func (ddb *DoltDB) setInternalTag(ctx context.Context, ds datas.Dataset, commitAddr hash.Hash, meta *datas.TagMeta, replicationStatus *ReplicationStatusController) error {
+	db := ddb.db.withReplicationStatusController(replicationStatus)
+	_, err := db.SetTag(ctx, ds, commitAddr, datas.TagOptions{Meta: meta})
+	return err
+}
+
 type ReplicationStatusController struct {
 	// A slice of funcs which can be called to wait for the replication
 	// associated with a commithook to complete. Must return if the
@@ -1422,6 +1575,44 @@ func (ddb *DoltDB) GC(ctx context.Context, safepointF func() error) error {
 	return collector.GC(ctx, oldGen, newGen, safepointF)
 }
 
+// GCSafepoint is a set of roots that a session or long-running operation still needs. Garbage collection keeps all the
+// chunks reachable from them, even once they're no longer reachable from any ref, until the safepoint is released.
+type GCSafepoint struct {
+	unpin func()
+}
+
+// Release lets garbage collection remove the chunks that only this safepoint's roots reference. It's safe to call more
+// than once, and on a nil safepoint.
+func (sp *GCSafepoint) Release() {
+	if sp != nil && sp.unpin != nil {
+		sp.unpin()
+	}
+}
+
+// RegisterGCSafepoint registers the roots in |roots|, such as the addresses of commits or root values, that the caller
+// will go on reading after they might stop being reachable from any ref. A garbage collection running concurrently
+// keeps them, as does every later one until the returned safepoint is released. Roots must be registered while they're
+// still reachable, or held by another safepoint. Databases that don't support garbage collection return a safepoint
+// that does nothing.
+func (ddb *DoltDB) RegisterGCSafepoint(ctx context.Context, roots ...hash.Hash) (*GCSafepoint, error) {
+	return registerGCSafepoint(ctx, ddb.db.Database, roots...)
+}
+
+func registerGCSafepoint(ctx context.Context, db datas.Database, roots ...hash.Hash) (*GCSafepoint, error) {
+	if hdb, ok := db.(hooksDatabase); ok {
+		db = hdb.Database
+	}
+	pinner, ok := db.(datas.GCRootPinner)
+	if !ok {
+		return &GCSafepoint{}, nil
+	}
+	unpin, err := pinner.PinGCRoots(ctx, hash.NewHashSet(roots...))
+	if err != nil {
+		return nil, err
+	}
+	return &GCSafepoint{unpin: unpin}, nil
+}
+
 func (ddb *DoltDB) ShallowGC(ctx context.Context) error {
 	return datas.PruneTableFiles(ctx, ddb.db)
 }
@@ -1470,27 +1661,99 @@ func (ddb *DoltDB) PullChunks(
 	targetHashes []hash.Hash,
 	statsCh chan pull.Stats,
 ) error {
-	return pullHash(ctx, ddb.db, srcDB.db, targetHashes, tempDir, statsCh)
+	return ddb.PullChunksWithOptions(ctx, tempDir, srcDB, targetHashes, statsCh, PullOptions{})
+}
+
+// PullOptions control how PullChunksWithOptions sends chunks to the database pulled into.
+type PullOptions struct {
+	// ChunksPerTF is the most chunks sent in each table file, or 0 for the default.
+	ChunksPerTF int
+	// SendLimiter, if it's set, limits the rate in bytes per second that table files are sent at.
+	SendLimiter *rate.Limiter
+}
+
+// PullChunksWithOptions is PullChunks, sending the chunks as |opts| allows.
+func (ddb *DoltDB) PullChunksWithOptions(
+	ctx context.Context,
+	tempDir string,
+	srcDB *DoltDB,
+	targetHashes []hash.Hash,
+	statsCh chan pull.Stats,
+	opts PullOptions,
+) error {
+	// a pull into a sparse clone leaves out the tables it doesn't hold the data of
+	exclude, err := ddb.sparseExclusions(ctx, srcDB, targetHashes)
+	if err != nil {
+		return err
+	}
+	err = pullHash(ctx, ddb.db, srcDB.db, targetHashes, exclude, tempDir, statsCh, opts)
+	if err != nil {
+		return err
+	}
+	return ddb.updateSparse(ctx, exclude)
 }
 
 func pullHash(
 	ctx context.Context,
 	destDB, srcDB datas.Database,
 	targetHashes []hash.Hash,
+	exclude hash.HashSet,
 	tempDir string,
 	statsCh chan pull.Stats,
+	opts PullOptions,
+) error {
+	// a pull into a shallow clone doesn't fetch the commits it's missing, unless they're pulled explicitly
+	missing, err := missingCommits(ctx, destDB)
+	if err != nil {
+		return err
+	}
+	if missing.Size() == 0 {
+		return pullHashExcluding(ctx, destDB, srcDB, targetHashes, exclude, tempDir, statsCh, opts)
+	}
+
+	stillMissing := missing.Copy()
+	for _, h := range targetHashes {
+		stillMissing.Remove(h)
+	}
+	skip := stillMissing.Copy()
+	skip.InsertAll(exclude)
+	err = pullHashExcluding(ctx, destDB, srcDB, targetHashes, skip, tempDir, statsCh, opts)
+	if err != nil {
+		return err
+	}
+	if stillMissing.Size() == missing.Size() {
+		return nil
+	}
+	return datas.ChunkStoreFromDatabase(destDB).(chunks.ShallowChunkStore).SetMissingCommits(ctx, stillMissing)
+}
+
+// pullHashExcluding pulls the chunks reachable from |targetHashes| into |destDB|, without walking through the chunks
+// in |exclude|.
+func pullHashExcluding(
+	ctx context.Context,
+	destDB, srcDB datas.Database,
+	targetHashes []hash.Hash,
+	exclude hash.HashSet,
+	tempDir string,
+	statsCh chan pull.Stats,
+	opts PullOptions,
 ) error {
 	srcCS := datas.ChunkStoreFromDatabase(srcDB)
 	destCS := datas.ChunkStoreFromDatabase(destDB)
-	waf := types.WalkAddrsForNBF(srcDB.Format())
+	waf := skipAddrs(types.WalkAddrsForNBF(srcDB.Format()), exclude)
 
 	if datas.CanUsePuller(srcDB) && datas.CanUsePuller(destDB) {
-		puller, err := pull.NewPuller(ctx, tempDir, defaultChunksPerTF, srcCS, destCS, waf, targetHashes, statsCh)
+		chunksPerTF := defaultChunksPerTF
+		if opts.ChunksPerTF > 0 {
+			chunksPerTF = opts.ChunksPerTF
+		}
+		puller, err := pull.NewPuller(ctx, tempDir, chunksPerTF, srcCS, destCS, waf, targetHashes, statsCh)
 		if err == pull.ErrDBUpToDate {
 			return nil
 		} else if err != nil {
 			return err
 		}
+		puller.SetSendLimiter(opts.SendLimiter)
 
 		return puller.Pull(ctx)
 	} else {
@@ -1530,11 +1793,22 @@ func (ddb *DoltDB) SetCommitHooks(ctx context.Context, postHooks []CommitHook) *
 	return ddb
 }
 
+// CommitHooks returns the commit hooks set on this database.
+func (ddb *DoltDB) CommitHooks() []CommitHook {
+	return ddb.db.PostCommitHooks()
+}
+
 func (ddb *DoltDB) PrependCommitHook(ctx context.Context, hook CommitHook) *DoltDB {
 	ddb.db = ddb.db.SetCommitHooks(ctx, append([]CommitHook{hook}, ddb.db.PostCommitHooks()...))
 	return ddb
 }
 
+// AppendCommitHook adds |hook| after the commit hooks already set on this database.
+func (ddb *DoltDB) AppendCommitHook(ctx context.Context, hook CommitHook) *DoltDB {
+	ddb.db = ddb.db.SetCommitHooks(ctx, append(ddb.db.PostCommitHooks(), hook))
+	return ddb
+}
+
 func (ddb *DoltDB) SetCommitHookLogger(ctx context.Context, wr io.Writer) *DoltDB {
 	if ddb.db.Database != nil {
 		ddb.db = ddb.db.SetCommitHookLogger(ctx, wr)
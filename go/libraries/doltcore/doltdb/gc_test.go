@@ -50,6 +50,7 @@ func TestGarbageCollection(t *testing.T) {
 	}
 
 	t.Run("HasCacheDataCorruption", testGarbageCollectionHasCacheDataCorruptionBugFix)
+	t.Run("Safepoints", testGarbageCollectionSafepoints)
 }
 
 type stage struct {
@@ -234,6 +235,57 @@ func testGarbageCollectionHasCacheDataCorruptionBugFix(t *testing.T) {
 	require.True(t, errors.Is(err, nbs.ErrDanglingRef), "committing a reference to c2, which was erased with the ErrDanglingRef above, must also fail with ErrDanglingRef")
 }
 
+// testGarbageCollectionSafepoints checks that chunks which aren't reachable from any ref survive GC while a safepoint
+// holds them, and are collected once it's released.
+func testGarbageCollectionSafepoints(t *testing.T) {
+	ctx := context.Background()
+
+	d, err := os.MkdirTemp(t.TempDir(), "safepointtest-")
+	require.NoError(t, err)
+
+	ddb, err := doltdb.LoadDoltDB(ctx, types.Format_DOLT, "file://"+d, filesys.LocalFS)
+	require.NoError(t, err)
+	defer ddb.Close()
+
+	err = ddb.WriteEmptyRepo(ctx, "main", "Aaron Son", "aaron@dolthub.com")
+	require.NoError(t, err)
+
+	root, err := ddb.NomsRoot(ctx)
+	require.NoError(t, err)
+
+	ns := ddb.NodeStore()
+
+	c1 := newIntMap(t, ctx, ns, 1, 1)
+	_, err = ns.Write(ctx, c1.Node())
+	require.NoError(t, err)
+	success, err := ddb.CommitRoot(ctx, root, root)
+	require.NoError(t, err)
+	require.True(t, success)
+
+	safepoint, err := ddb.RegisterGCSafepoint(ctx, c1.HashOf())
+	require.NoError(t, err)
+
+	err = ddb.GC(ctx, nil)
+	require.NoError(t, err)
+
+	r1 := newAddrMap(t, ctx, ns, "r1", c1.HashOf())
+	_, err = ns.Write(ctx, r1.Node())
+	require.NoError(t, err)
+	success, err = ddb.CommitRoot(ctx, root, root)
+	require.NoError(t, err, "c1 is held by a safepoint, so it must survive GC")
+	require.True(t, success)
+
+	safepoint.Release()
+	err = ddb.GC(ctx, nil)
+	require.NoError(t, err)
+
+	r2 := newAddrMap(t, ctx, ns, "r2", c1.HashOf())
+	_, err = ns.Write(ctx, r2.Node())
+	require.NoError(t, err)
+	_, err = ddb.CommitRoot(ctx, root, root)
+	require.True(t, errors.Is(err, nbs.ErrDanglingRef), "c1 was released, so it must be collected")
+}
+
 func newIntMap(t *testing.T, ctx context.Context, ns tree.NodeStore, k, v int8) prolly.Map {
 	desc := val.NewTupleDescriptor(val.Type{
 		Enc:      val.Int8Enc,
@@ -19,6 +19,7 @@ import (
 	"io"
 	"sync"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
@@ -163,6 +164,14 @@ func (db hooksDatabase) SetHead(ctx context.Context, ds datas.Dataset, newHeadAd
 	return ds, err
 }
 
+func (db hooksDatabase) SetHeadIfUnchanged(ctx context.Context, ds datas.Dataset, newHeadAddr, currHeadAddr hash.Hash) (datas.Dataset, error) {
+	ds, err := db.Database.SetHeadIfUnchanged(ctx, ds, newHeadAddr, currHeadAddr)
+	if err == nil {
+		db.ExecuteCommitHooks(ctx, ds, false)
+	}
+	return ds, err
+}
+
 func (db hooksDatabase) FastForward(ctx context.Context, ds datas.Dataset, newHeadAddr hash.Hash) (datas.Dataset, error) {
 	ds, err := db.Database.FastForward(ctx, ds, newHeadAddr)
 	if err == nil {
@@ -171,6 +180,25 @@ func (db hooksDatabase) FastForward(ctx context.Context, ds datas.Dataset, newHe
 	return ds, err
 }
 
+// Tag runs the commit hooks for user tags only. Tags are also used for internal records, such as the default branch,
+// which aren't changes to the database that hooks should be told about.
+func (db hooksDatabase) Tag(ctx context.Context, ds datas.Dataset, commitAddr hash.Hash, opts datas.TagOptions) (datas.Dataset, error) {
+	ds, err := db.Database.Tag(ctx, ds, commitAddr, opts)
+	if err == nil && isUserTag(ds.ID()) {
+		db.ExecuteCommitHooks(ctx, ds, false)
+	}
+	return ds, err
+}
+
+// isUserTag returns whether the dataset named is a tag created by a user, rather than an internal record.
+func isUserTag(datasetID string) bool {
+	if !ref.IsRef(datasetID) {
+		return false
+	}
+	r, err := ref.Parse(datasetID)
+	return err == nil && r.GetType() == ref.TagRefType
+}
+
 func (db hooksDatabase) Delete(ctx context.Context, ds datas.Dataset) (datas.Dataset, error) {
 	ds, err := db.Database.Delete(ctx, ds)
 	if err == nil {
@@ -153,11 +153,11 @@ func compilePattern(pattern string) (*regexp.Regexp, error) {
 // match pattern B, but not vice versa.)
 func getMoreSpecificPatterns(lessSpecific string) (*regexp.Regexp, error) {
 	pattern := "^" + regexp.QuoteMeta(lessSpecific) + "$"
-	// A ? can expand to any character except for a * or %, since that also has special meaning in patterns.
-
-	pattern = strings.Replace(pattern, "\\?", "[^\\*%]", -1)
 	pattern = strings.Replace(pattern, "\\*", ".*", -1)
 	pattern = strings.Replace(pattern, "%", ".*", -1)
+	// A ? can expand to any character except for a * or %, since that also has special meaning in patterns. It's
+	// replaced last so that the * and % in its character class aren't replaced too.
+	pattern = strings.Replace(pattern, "\\?", "[^\\*%]", -1)
 	return regexp.Compile(pattern)
 }
 
@@ -0,0 +1,158 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// RefProblem is a ref found by CheckRefs whose value, or the history reachable from it, can't be loaded.
+type RefProblem struct {
+	// Ref is the name of the ref's dataset, e.g. refs/heads/main or workingSets/heads/main
+	Ref string
+	// Addr is the address the ref points to
+	Addr hash.Hash
+	// Dangling is true when the chunk the ref points to is missing from the chunk store. A dangling ref can't be
+	// loaded, only deleted with DeleteDanglingRef.
+	Dangling bool
+	Err      error
+}
+
+// ValidateTableFiles validates the table files of the database's chunk store, as described by
+// chunks.TableFileValidator. It returns chunks.ErrUnsupportedOperation if the chunk store can't validate them.
+func (ddb *DoltDB) ValidateTableFiles(ctx context.Context, cb func(fileID string, numChunks int, err error) error) error {
+	validator, ok := datas.ChunkStoreFromDatabase(ddb.db).(chunks.TableFileValidator)
+	if !ok {
+		return chunks.ErrUnsupportedOperation
+	}
+	return validator.ValidateTableFiles(ctx, cb)
+}
+
+// CheckRefs loads the value of every ref and working set in the database. For refs to commits and tags, it also loads
+// every commit reachable from the ref, and the root value of each. It returns the refs it found problems with, and the
+// number of commits it loaded.
+func (ddb *DoltDB) CheckRefs(ctx context.Context) ([]RefProblem, int, error) {
+	dss, err := ddb.db.Datasets(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var refs []RefProblem
+	err = dss.IterAll(ctx, func(id string, addr hash.Hash) error {
+		refs = append(refs, RefProblem{Ref: id, Addr: addr})
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	visited := hash.NewHashSet()
+	var problems []RefProblem
+	for _, r := range refs {
+		ok, err := ddb.Has(ctx, r.Addr)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if !ok {
+			r.Dangling = true
+			r.Err = fmt.Errorf("%s points to %s, which is missing from the chunk store", r.Ref, r.Addr.String())
+		} else {
+			r.Err = ddb.checkRef(ctx, r.Ref, r.Addr, visited)
+		}
+
+		if r.Err != nil {
+			problems = append(problems, r)
+		}
+	}
+
+	return problems, visited.Size(), nil
+}
+
+// checkRef loads the value of the ref named |id|, and the commits reachable from it that aren't in |visited|.
+func (ddb *DoltDB) checkRef(ctx context.Context, id string, addr hash.Hash, visited hash.HashSet) error {
+	if ref.IsWorkingSet(id) {
+		_, err := ddb.ResolveWorkingSet(ctx, ref.NewWorkingSetRef(id))
+		return err
+	} else if !ref.IsRef(id) {
+		return nil
+	}
+
+	dref, err := ref.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	if dref.GetType() == ref.TagRefType {
+		tag, err := ddb.ResolveTag(ctx, ref.NewTagRef(dref.GetPath()))
+		if err != nil {
+			return err
+		}
+		addr, err = tag.Commit.HashOf()
+		if err != nil {
+			return err
+		}
+	} else if _, ok := ref.HeadRefTypes[dref.GetType()]; !ok {
+		return nil
+	}
+
+	return ddb.checkCommits(ctx, addr, visited)
+}
+
+// checkCommits loads the commit at |h| and every commit reachable from it that isn't in |visited|, along with their
+// root values.
+func (ddb *DoltDB) checkCommits(ctx context.Context, h hash.Hash, visited hash.HashSet) error {
+	pending := []hash.Hash{h}
+	for len(pending) > 0 {
+		h := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if visited.Has(h) {
+			continue
+		}
+		visited.Insert(h)
+
+		cm, err := ddb.ReadCommit(ctx, h)
+		if err != nil {
+			return fmt.Errorf("failed to load commit %s: %w", h.String(), err)
+		}
+		if _, err := cm.GetRootValue(ctx); err != nil {
+			return fmt.Errorf("failed to load the root value of commit %s: %w", h.String(), err)
+		}
+
+		parents, err := cm.ParentHashes(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load the parents of commit %s: %w", h.String(), err)
+		}
+		pending = append(pending, parents...)
+	}
+	return nil
+}
+
+// DeleteDanglingRef deletes the ref whose dataset is named |id|, without loading its value. It's used to remove the
+// dangling refs found by CheckRefs, which can't be deleted by DeleteBranch and the like.
+func (ddb *DoltDB) DeleteDanglingRef(ctx context.Context, id string) error {
+	ds, err := ddb.db.GetDataset(ctx, id)
+	if err != nil {
+		return err
+	}
+	_, err = ddb.db.Delete(ctx, ds)
+	return err
+}
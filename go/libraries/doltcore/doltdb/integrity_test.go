@@ -0,0 +1,54 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestCheckRefs(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	defer ddb.Close()
+
+	err = ddb.WriteEmptyRepo(ctx, "main", "Bill Billerson", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	cs, _ := NewCommitSpec("main")
+	cm, err := ddb.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	err = ddb.NewBranchAtCommit(ctx, ref.NewBranchRef("other"), cm, nil)
+	require.NoError(t, err)
+
+	problems, numCommits, err := ddb.CheckRefs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+	assert.Equal(t, 1, numCommits)
+
+	err = ddb.ValidateTableFiles(ctx, func(fileID string, numChunks int, err error) error {
+		assert.NoError(t, err)
+		return nil
+	})
+	require.NoError(t, err)
+}
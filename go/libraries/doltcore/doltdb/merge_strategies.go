@@ -0,0 +1,160 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// MergeStrategy is how a merge resolves the rows of a table that were changed differently on each side.
+type MergeStrategy string
+
+const (
+	// MergeStrategyManual records conflicting changes as conflicts to be resolved by the user. This is the default.
+	MergeStrategyManual MergeStrategy = "manual"
+	// MergeStrategyOurs keeps our version of each conflicting row.
+	MergeStrategyOurs MergeStrategy = "ours"
+	// MergeStrategyTheirs takes their version of each conflicting row, including their deletes.
+	MergeStrategyTheirs MergeStrategy = "theirs"
+	// MergeStrategyUnion keeps the rows that were deleted on one side and modified on the other, as modified. Rows
+	// modified differently on each side are still recorded as conflicts.
+	MergeStrategyUnion MergeStrategy = "union"
+)
+
+// ParseMergeStrategy returns the MergeStrategy named |s|, or an error if there is no such strategy.
+func ParseMergeStrategy(s string) (MergeStrategy, error) {
+	switch ms := MergeStrategy(strings.ToLower(s)); ms {
+	case MergeStrategyManual, MergeStrategyOurs, MergeStrategyTheirs, MergeStrategyUnion:
+		return ms, nil
+	default:
+		return "", fmt.Errorf("invalid merge strategy '%s', expected one of 'ours', 'theirs', 'union' or 'manual'", s)
+	}
+}
+
+// MergeStrategyPattern is a row of the dolt_merge_strategies table, which declares the strategy used to merge the
+// tables whose names match a dolt_ignore style pattern.
+type MergeStrategyPattern struct {
+	Pattern  string
+	Strategy MergeStrategy
+}
+
+type MergeStrategies []MergeStrategyPattern
+
+// GetMergeStrategies returns the merge strategies declared in the dolt_merge_strategies table of |root|.
+func GetMergeStrategies(ctx context.Context, root *RootValue) (MergeStrategies, error) {
+	var strategies MergeStrategies
+	table, found, err := root.GetTable(ctx, MergeStrategiesTableName)
+	if err != nil {
+		return nil, err
+	}
+	if !found || table.Format() == types.Format_LD_1 {
+		// dolt_merge_strategies is not supported for the legacy storage format.
+		return strategies, nil
+	}
+
+	index, err := table.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sch, err := table.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keyDesc, valueDesc := sch.GetMapDescriptors()
+
+	if !keyDesc.Equals(val.NewTupleDescriptor(val.Type{Enc: val.StringEnc})) {
+		return nil, fmt.Errorf("dolt_merge_strategies had unexpected key type, this should never happen")
+	}
+	if !valueDesc.Equals(val.NewTupleDescriptor(val.Type{Enc: val.StringEnc, Nullable: true})) {
+		return nil, fmt.Errorf("dolt_merge_strategies had unexpected value type, this should never happen")
+	}
+
+	iter, err := durable.ProllyMapFromIndex(index).IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		keyTuple, valueTuple, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		pattern, ok := keyDesc.GetString(0, keyTuple)
+		if !ok {
+			return nil, fmt.Errorf("could not read pattern")
+		}
+		s, _ := valueDesc.GetString(0, valueTuple)
+		strategy, err := ParseMergeStrategy(s)
+		if err != nil {
+			return nil, fmt.Errorf("dolt_merge_strategies pattern '%s': %w", pattern, err)
+		}
+		strategies = append(strategies, MergeStrategyPattern{Pattern: pattern, Strategy: strategy})
+	}
+	return strategies, nil
+}
+
+// StrategyForTable returns the strategy used to merge the table |tableName|. As with dolt_ignore, if several patterns
+// match the table, more specific patterns override less specific ones. Tables that don't match any pattern are merged
+// with MergeStrategyManual.
+func (ms MergeStrategies) StrategyForTable(tableName string) (MergeStrategy, error) {
+	var matches []MergeStrategyPattern
+	for _, p := range ms {
+		re, err := compilePattern(p.Pattern)
+		if err != nil {
+			return "", err
+		}
+		if re.MatchString(tableName) {
+			matches = append(matches, p)
+		}
+	}
+	if len(matches) == 0 {
+		return MergeStrategyManual, nil
+	}
+
+	var strategy MergeStrategy
+	var patterns []string
+	for _, p := range matches {
+		moreSpecific, err := getMoreSpecificPatterns(p.Pattern)
+		if err != nil {
+			return "", err
+		}
+		overridden := false
+		for _, other := range matches {
+			if normalizePattern(other.Pattern) != normalizePattern(p.Pattern) && moreSpecific.MatchString(other.Pattern) {
+				overridden = true
+				break
+			}
+		}
+		if overridden {
+			continue
+		}
+		if strategy != "" && strategy != p.Strategy {
+			return "", fmt.Errorf("table '%s' matches dolt_merge_strategies patterns with different strategies: %s", tableName, strings.Join(append(patterns, p.Pattern), ", "))
+		}
+		strategy = p.Strategy
+		patterns = append(patterns, p.Pattern)
+	}
+	return strategy, nil
+}
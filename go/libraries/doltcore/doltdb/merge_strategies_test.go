@@ -0,0 +1,61 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrategyForTable(t *testing.T) {
+	strategies := MergeStrategies{
+		{Pattern: "data_*", Strategy: MergeStrategyTheirs},
+		{Pattern: "data_b", Strategy: MergeStrategyOurs},
+		{Pattern: "log_%", Strategy: MergeStrategyUnion},
+		{Pattern: "log_?", Strategy: MergeStrategyOurs},
+		{Pattern: "%_c", Strategy: MergeStrategyOurs},
+	}
+
+	for table, expected := range map[string]MergeStrategy{
+		"data_a": MergeStrategyTheirs,
+		"data_b": MergeStrategyOurs,
+		"log_a":  MergeStrategyOurs,
+		"log_ab": MergeStrategyUnion,
+		"t":      MergeStrategyManual,
+	} {
+		strategy, err := strategies.StrategyForTable(table)
+		require.NoError(t, err)
+		assert.Equal(t, expected, strategy, table)
+	}
+
+	// neither pattern is more specific than the other
+	_, err := strategies.StrategyForTable("data_c")
+	assert.Error(t, err)
+
+	strategy, err := MergeStrategies(nil).StrategyForTable("data_a")
+	require.NoError(t, err)
+	assert.Equal(t, MergeStrategyManual, strategy)
+}
+
+func TestParseMergeStrategy(t *testing.T) {
+	strategy, err := ParseMergeStrategy("Theirs")
+	require.NoError(t, err)
+	assert.Equal(t, MergeStrategyTheirs, strategy)
+
+	_, err = ParseMergeStrategy("mine")
+	assert.Error(t, err)
+}
@@ -0,0 +1,266 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var ErrProposalNotFound = errors.New("proposal not found")
+
+// proposalRefPrefix is the prefix of the internal refs that record change proposals. The rest of the ref is the
+// proposal's id.
+const proposalRefPrefix = "proposals/"
+
+// ProposalStatus is the state of a change proposal.
+type ProposalStatus string
+
+const (
+	ProposalOpen   ProposalStatus = "open"
+	ProposalMerged ProposalStatus = "merged"
+	ProposalClosed ProposalStatus = "closed"
+)
+
+// Proposal is a request to merge one branch into another, along with its review. Proposals are identified by a
+// sequential id, and are kept in the database after they are merged or closed.
+type Proposal struct {
+	ID          uint64            `json:"id"`
+	Source      string            `json:"source"`
+	Target      string            `json:"target"`
+	Description string            `json:"description,omitempty"`
+	Status      ProposalStatus    `json:"status"`
+	Author      string            `json:"author"`
+	Reviewers   []string          `json:"reviewers,omitempty"`
+	Approvals   []string          `json:"approvals,omitempty"`
+	Comments    []ProposalComment `json:"comments,omitempty"`
+	// MergeCommit is the hash of the commit the proposal was merged as, once it's merged
+	MergeCommit string    `json:"merge_commit,omitempty"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+}
+
+// ProposalComment is a single comment left on a proposal.
+type ProposalComment struct {
+	Author  string    `json:"author"`
+	Comment string    `json:"comment"`
+	Created time.Time `json:"created"`
+}
+
+// IsReviewer returns whether the user given was asked to review the proposal.
+func (p Proposal) IsReviewer(user string) bool {
+	for _, r := range p.Reviewers {
+		if r == user {
+			return true
+		}
+	}
+	return false
+}
+
+// PendingReviewers returns the reviewers of the proposal who have not yet approved it. A proposal can only be merged
+// once every reviewer has approved it.
+func (p Proposal) PendingReviewers() []string {
+	var pending []string
+	for _, r := range p.Reviewers {
+		approved := false
+		for _, a := range p.Approvals {
+			if a == r {
+				approved = true
+				break
+			}
+		}
+		if !approved {
+			pending = append(pending, r)
+		}
+	}
+	return pending
+}
+
+// ProposalRef returns the internal ref that records the proposal with the id given.
+func ProposalRef(id uint64) ref.DoltRef {
+	return ref.NewInternalRef(proposalRefPrefix + strconv.FormatUint(id, 10))
+}
+
+var internalRefFilter = map[ref.RefType]struct{}{ref.InternalRefType: {}}
+
+// GetProposals returns every proposal recorded in this database, ordered by id.
+func (ddb *DoltDB) GetProposals(ctx context.Context) ([]Proposal, error) {
+	var proposals []Proposal
+	err := ddb.VisitRefsOfType(ctx, internalRefFilter, func(r ref.DoltRef, _ hash.Hash) error {
+		if !strings.HasPrefix(r.GetPath(), proposalRefPrefix) {
+			return nil
+		}
+		ds, err := ddb.db.GetDataset(ctx, r.String())
+		if err != nil {
+			return err
+		}
+		p, ok, err := proposalFromDataset(ds)
+		if err != nil || !ok {
+			return err
+		}
+		proposals = append(proposals, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(proposals, func(i, j int) bool {
+		return proposals[i].ID < proposals[j].ID
+	})
+	return proposals, nil
+}
+
+// GetProposal returns the proposal with the id given, or ErrProposalNotFound if there is none.
+func (ddb *DoltDB) GetProposal(ctx context.Context, id uint64) (Proposal, error) {
+	ds, err := ddb.db.GetDataset(ctx, ProposalRef(id).String())
+	if err != nil {
+		return Proposal{}, err
+	}
+	p, ok, err := proposalFromDataset(ds)
+	if err != nil {
+		return Proposal{}, err
+	}
+	if !ok {
+		return Proposal{}, ErrProposalNotFound
+	}
+	return p, nil
+}
+
+func (ddb *DoltDB) nextProposalID(ctx context.Context) (uint64, error) {
+	var maxID uint64
+	err := ddb.VisitRefsOfType(ctx, internalRefFilter, func(r ref.DoltRef, _ hash.Hash) error {
+		if !strings.HasPrefix(r.GetPath(), proposalRefPrefix) {
+			return nil
+		}
+		id, err := strconv.ParseUint(strings.TrimPrefix(r.GetPath(), proposalRefPrefix), 10, 64)
+		if err == nil && id > maxID {
+			maxID = id
+		}
+		return nil
+	})
+	return maxID + 1, err
+}
+
+func proposalFromDataset(ds datas.Dataset) (Proposal, bool, error) {
+	if !ds.HasHead() || !ds.IsTag() {
+		return Proposal{}, false, nil
+	}
+
+	meta, _, err := ds.HeadTag()
+	if err != nil {
+		return Proposal{}, false, err
+	}
+
+	var p Proposal
+	err = json.Unmarshal([]byte(meta.Description), &p)
+	if err != nil {
+		return Proposal{}, false, err
+	}
+	return p, true, nil
+}
+
+// CreateProposal records the new proposal given, and sets its id to the next one not in use. Each id is claimed by
+// writing the proposal's record only if no record with that id exists yet, so proposals opened concurrently are never
+// given the same id.
+func (ddb *DoltDB) CreateProposal(ctx context.Context, p *Proposal, meta *datas.TagMeta, replicationStatus *ReplicationStatusController) error {
+	id, err := ddb.nextProposalID(ctx)
+	if err != nil {
+		return err
+	}
+
+	for ; ; id++ {
+		ds, err := ddb.db.GetDataset(ctx, ProposalRef(id).String())
+		if err != nil {
+			return err
+		}
+		if ds.HasHead() {
+			continue
+		}
+
+		p.ID = id
+		err = ddb.writeProposal(ctx, ds, *p, meta, replicationStatus)
+		if !errors.Is(err, datas.ErrMergeNeeded) {
+			return err
+		}
+	}
+}
+
+// UpdateProposal applies |update| to the proposal with the id given and records the result, returning the updated
+// proposal. If the proposal is changed concurrently, |update| is applied again to the new record, so no change is
+// lost. Returns ErrProposalNotFound if there is no proposal with the id given.
+func (ddb *DoltDB) UpdateProposal(ctx context.Context, id uint64, update func(p *Proposal) error, meta *datas.TagMeta, replicationStatus *ReplicationStatusController) (Proposal, error) {
+	for {
+		ds, err := ddb.db.GetDataset(ctx, ProposalRef(id).String())
+		if err != nil {
+			return Proposal{}, err
+		}
+		p, ok, err := proposalFromDataset(ds)
+		if err != nil {
+			return Proposal{}, err
+		}
+		if !ok {
+			return Proposal{}, ErrProposalNotFound
+		}
+
+		err = update(&p)
+		if err != nil {
+			return Proposal{}, err
+		}
+		err = ddb.writeProposal(ctx, ds, p, meta, replicationStatus)
+		if !errors.Is(err, datas.ErrMergeNeeded) {
+			return p, err
+		}
+	}
+}
+
+// writeProposal records |p| as the head of |ds|, as long as |ds| is still current. Like branch metadata, the record is
+// a tag, so that proposals are carried along by clones, pushes and replication. The tag points at the head of the
+// proposal's source branch, which keeps the proposed commits from being garbage collected if the source branch is
+// deleted. If the source branch no longer exists, the record keeps pointing at the commit it did before.
+func (ddb *DoltDB) writeProposal(ctx context.Context, ds datas.Dataset, p Proposal, meta *datas.TagMeta, replicationStatus *ReplicationStatusController) error {
+	var commitAddr hash.Hash
+	cm, err := ddb.ResolveCommitRef(ctx, ref.NewBranchRef(p.Source))
+	if err == nil {
+		commitAddr, err = cm.HashOf()
+		if err != nil {
+			return err
+		}
+	} else if errors.Is(err, ErrBranchNotFound) && ds.HasHead() && ds.IsTag() {
+		_, commitAddr, err = ds.HeadTag()
+		if err != nil {
+			return err
+		}
+	} else {
+		return err
+	}
+
+	desc, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	meta.Description = string(desc)
+	return ddb.setInternalTag(ctx, ds, commitAddr, meta, replicationStatus)
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestProposals(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	defer ddb.Close()
+	err = ddb.WriteEmptyRepo(ctx, "main", "Bill Billerson", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	newMeta := func() *datas.TagMeta {
+		return datas.NewTagMeta("Bill Billerson", "bigbillieb@fake.horse", "")
+	}
+
+	t.Run("concurrently opened proposals get distinct ids", func(t *testing.T) {
+		const n = 8
+		ids := make([]uint64, n)
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				p := Proposal{Source: "main", Target: "other", Status: ProposalOpen}
+				assert.NoError(t, ddb.CreateProposal(ctx, &p, newMeta(), nil))
+				ids[i] = p.ID
+			}(i)
+		}
+		wg.Wait()
+
+		assert.ElementsMatch(t, []uint64{1, 2, 3, 4, 5, 6, 7, 8}, ids)
+		proposals, err := ddb.GetProposals(ctx)
+		require.NoError(t, err)
+		assert.Len(t, proposals, n)
+	})
+
+	t.Run("concurrent updates are all kept", func(t *testing.T) {
+		const n = 8
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := ddb.UpdateProposal(ctx, 1, func(p *Proposal) error {
+					p.Comments = append(p.Comments, ProposalComment{Author: "bill", Comment: "lgtm"})
+					return nil
+				}, newMeta(), nil)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		p, err := ddb.GetProposal(ctx, 1)
+		require.NoError(t, err)
+		assert.Len(t, p.Comments, n)
+	})
+
+	t.Run("updating a missing proposal", func(t *testing.T) {
+		_, err := ddb.UpdateProposal(ctx, 100, func(p *Proposal) error {
+			return nil
+		}, newMeta(), nil)
+		assert.ErrorIs(t, err, ErrProposalNotFound)
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// ParseTagPatterns splits a comma separated list of tag name patterns, such as "v*,release/*", dropping empty entries.
+func ParseTagPatterns(patterns string) []string {
+	var res []string
+	for _, p := range strings.Split(patterns, ",") {
+		p = strings.TrimSpace(p)
+		if len(p) > 0 {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// IsProtectedTag returns whether the tag named matches any of the glob patterns given. Patterns use the syntax of
+// path.Match, so '*' does not match a '/'.
+func IsProtectedTag(patterns []string, tagName string) bool {
+	for _, p := range patterns {
+		if ok, err := path.Match(p, tagName); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckProtectedTags returns ErrProtectedTag if moving the database from |oldRoot| to |newRoot| would delete any tag
+// matching |patterns|, or point it somewhere else. Protected tags may still be created.
+func (ddb *DoltDB) CheckProtectedTags(ctx context.Context, patterns []string, oldRoot, newRoot hash.Hash) error {
+	if len(patterns) == 0 || oldRoot.IsEmpty() {
+		return nil
+	}
+
+	protected := make(map[string]hash.Hash)
+	err := ddb.VisitRefsOfTypeByNomsRoot(ctx, tagsRefFilter, oldRoot, func(r ref.DoltRef, addr hash.Hash) error {
+		if IsProtectedTag(patterns, r.GetPath()) {
+			protected[r.GetPath()] = addr
+		}
+		return nil
+	})
+	if err != nil || len(protected) == 0 {
+		return err
+	}
+
+	err = ddb.VisitRefsOfTypeByNomsRoot(ctx, tagsRefFilter, newRoot, func(r ref.DoltRef, addr hash.Hash) error {
+		if oldAddr, ok := protected[r.GetPath()]; ok {
+			if oldAddr != addr {
+				return fmt.Errorf("%w: cannot move tag '%s'", ErrProtectedTag, r.GetPath())
+			}
+			delete(protected, r.GetPath())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for name := range protected {
+		return fmt.Errorf("%w: cannot delete tag '%s'", ErrProtectedTag, name)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestIsProtectedTag(t *testing.T) {
+	patterns := ParseTagPatterns(" v*, release/*,,")
+	assert.Equal(t, []string{"v*", "release/*"}, patterns)
+
+	assert.True(t, IsProtectedTag(patterns, "v1.0.0"))
+	assert.True(t, IsProtectedTag(patterns, "release/2023-06"))
+	assert.False(t, IsProtectedTag(patterns, "release/2023/06"))
+	assert.False(t, IsProtectedTag(patterns, "nightly"))
+	assert.False(t, IsProtectedTag(nil, "v1.0.0"))
+}
+
+func TestCheckProtectedTags(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	defer ddb.Close()
+	err = ddb.WriteEmptyRepo(ctx, "main", "Bill Billerson", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	cs, _ := NewCommitSpec("main")
+	cm, err := ddb.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	meta := datas.NewTagMeta("Bill Billerson", "bigbillieb@fake.horse", "")
+	require.NoError(t, ddb.NewTagAtCommit(ctx, ref.NewTagRef("v1"), cm, meta))
+	require.NoError(t, ddb.NewTagAtCommit(ctx, ref.NewTagRef("nightly"), cm, meta))
+	before, err := ddb.NomsRoot(ctx)
+	require.NoError(t, err)
+
+	patterns := []string{"v*"}
+
+	// creating a protected tag is allowed
+	require.NoError(t, ddb.NewTagAtCommit(ctx, ref.NewTagRef("v2"), cm, meta))
+	after, err := ddb.NomsRoot(ctx)
+	require.NoError(t, err)
+	assert.NoError(t, ddb.CheckProtectedTags(ctx, patterns, before, after))
+
+	// deleting an unprotected tag is allowed
+	require.NoError(t, ddb.DeleteTag(ctx, ref.NewTagRef("nightly")))
+	after, err = ddb.NomsRoot(ctx)
+	require.NoError(t, err)
+	assert.NoError(t, ddb.CheckProtectedTags(ctx, patterns, before, after))
+
+	// deleting a protected tag is not
+	require.NoError(t, ddb.DeleteTag(ctx, ref.NewTagRef("v1")))
+	after, err = ddb.NomsRoot(ctx)
+	require.NoError(t, err)
+	assert.ErrorIs(t, ddb.CheckProtectedTags(ctx, patterns, before, after), ErrProtectedTag)
+	assert.NoError(t, ddb.CheckProtectedTags(ctx, nil, before, after))
+
+	// and neither is moving one
+	meta = datas.NewTagMeta("Bill Billerson", "bigbillieb@fake.horse", "moved")
+	require.NoError(t, ddb.NewTagAtCommit(ctx, ref.NewTagRef("v1"), cm, meta))
+	after, err = ddb.NomsRoot(ctx)
+	require.NoError(t, err)
+	assert.ErrorIs(t, ddb.CheckProtectedTags(ctx, patterns, before, after), ErrProtectedTag)
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// rebaseRefPrefix is the prefix of the internal refs that record rebases in progress. The rest of the ref is the name
+// of the branch being rebased.
+const rebaseRefPrefix = "rebase/"
+
+// The actions of the steps of a rebase.
+const (
+	RebaseActionPick   = "pick"
+	RebaseActionReword = "reword"
+	RebaseActionSquash = "squash"
+	RebaseActionDrop   = "drop"
+)
+
+// RebaseStep is a step of a rebase plan: a commit to replay, and what to do with it.
+type RebaseStep struct {
+	Action string `json:"action"`
+	Commit string `json:"commit"`
+	// Message is the new commit message of a reword step.
+	Message string `json:"message,omitempty"`
+}
+
+// RebaseState is the state of a rebase in progress. Commits are recorded as hash strings.
+type RebaseState struct {
+	// Upstream is the commit the branch is being rebased onto.
+	Upstream string `json:"upstream"`
+	// OrigHead is the head of the branch before the rebase, which it's reset to if the rebase is aborted.
+	OrigHead string       `json:"orig_head"`
+	Plan     []RebaseStep `json:"plan"`
+	// Started is set once the branch has been reset to Upstream. An interactive rebase isn't started until its plan
+	// has been reviewed.
+	Started bool `json:"started,omitempty"`
+	// Next is the index of the next step of the plan to replay. When Paused is set, that step stopped with conflicts.
+	Next   int  `json:"next,omitempty"`
+	Paused bool `json:"paused,omitempty"`
+}
+
+// RebaseRef returns the internal ref that records the rebase in progress on the branch given.
+func RebaseRef(branch string) ref.DoltRef {
+	return ref.NewInternalRef(rebaseRefPrefix + branch)
+}
+
+// GetRebaseState returns the state of the rebase in progress on the branch given, or nil if there isn't one.
+func (ddb *DoltDB) GetRebaseState(ctx context.Context, branch string) (*RebaseState, error) {
+	ds, err := ddb.db.GetDataset(ctx, RebaseRef(branch).String())
+	if err != nil {
+		return nil, err
+	}
+	if !ds.HasHead() || !ds.IsTag() {
+		return nil, nil
+	}
+
+	meta, _, err := ds.HeadTag()
+	if err != nil {
+		return nil, err
+	}
+
+	var state RebaseState
+	err = json.Unmarshal([]byte(meta.Description), &state)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SetRebaseState records the state of the rebase in progress on the branch given. The state is recorded as a tag of
+// the branch's original head, which keeps the commits being replayed from being collected until the rebase is done.
+func (ddb *DoltDB) SetRebaseState(ctx context.Context, branch string, state RebaseState, meta *datas.TagMeta, replicationStatus *ReplicationStatusController) error {
+	addr, ok := hash.MaybeParse(state.OrigHead)
+	if !ok {
+		return errors.New("invalid original head in rebase state: " + state.OrigHead)
+	}
+
+	desc, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	meta.Description = string(desc)
+	for {
+		ds, err := ddb.db.GetDataset(ctx, RebaseRef(branch).String())
+		if err != nil {
+			return err
+		}
+		err = ddb.setInternalTag(ctx, ds, addr, meta, replicationStatus)
+		if !errors.Is(err, datas.ErrMergeNeeded) {
+			return err
+		}
+	}
+}
+
+// DeleteRebaseState forgets the rebase in progress on the branch given, if there is one.
+func (ddb *DoltDB) DeleteRebaseState(ctx context.Context, branch string, replicationStatus *ReplicationStatusController) error {
+	ds, err := ddb.db.GetDataset(ctx, RebaseRef(branch).String())
+	if err != nil || !ds.HasHead() {
+		return err
+	}
+	_, err = ddb.db.withReplicationStatusController(replicationStatus).Delete(ctx, ds)
+	return err
+}
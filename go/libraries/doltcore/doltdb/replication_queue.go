@@ -0,0 +1,329 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// ReplicationQueueFile is the name of the file in a database's .dolt directory that persists its queue of pushes to
+// its replication remote.
+const ReplicationQueueFile = "replication_queue.json"
+
+const (
+	replicationQueueWorker      = "async_push_replication_queue"
+	replicationQueueMinBackoff  = time.Second
+	replicationQueueMaxBackoff  = time.Minute
+	replicationQueueFileVersion = 1
+)
+
+// QueuedPush is a push of a ref to the replication remote that hasn't succeeded yet. A push of a ref that's queued
+// again before it succeeds replaces the queued push.
+type QueuedPush struct {
+	Ref string `json:"ref"`
+	// Hash is the head of the ref when the push was queued, or the empty hash if the ref was deleted.
+	Hash        string    `json:"hash"`
+	EnqueuedAt  time.Time `json:"enqueued_at"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error,omitempty"`
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+type replicationQueueFile struct {
+	Version int          `json:"version"`
+	Pushes  []QueuedPush `json:"pushes"`
+}
+
+// ReplicationQueue is a queue of pushes to a replication remote that's persisted to a file, so that the pushes that
+// haven't succeeded when the server stops are retried when it starts again.
+type ReplicationQueue struct {
+	fs   filesys.ReadWriteFS
+	path string
+
+	mu     sync.Mutex
+	pushes map[string]QueuedPush
+	// lastPush is when a queued push last succeeded. It isn't persisted.
+	lastPush time.Time
+	// wake is signalled when a push is queued.
+	wake chan struct{}
+}
+
+// LoadReplicationQueue loads the queue persisted at |path| in |fs|, or returns an empty queue if there isn't one.
+func LoadReplicationQueue(fs filesys.ReadWriteFS, path string) (*ReplicationQueue, error) {
+	q := &ReplicationQueue{
+		fs:     fs,
+		path:   path,
+		pushes: make(map[string]QueuedPush),
+		wake:   make(chan struct{}, 1),
+	}
+	if exists, _ := fs.Exists(path); !exists {
+		return q, nil
+	}
+	var f replicationQueueFile
+	if err := filesys.UnmarshalJSONFile(fs, path, &f); err != nil {
+		return nil, fmt.Errorf("could not load replication queue %s: %w", path, err)
+	}
+	if f.Version != replicationQueueFileVersion {
+		return nil, fmt.Errorf("could not load replication queue %s: unsupported version %d", path, f.Version)
+	}
+	for _, p := range f.Pushes {
+		q.pushes[p.Ref] = p
+	}
+	return q, nil
+}
+
+// Pushes returns the queued pushes, ordered by ref.
+func (q *ReplicationQueue) Pushes() []QueuedPush {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.sortedPushes()
+}
+
+// LastPush returns when a queued push last succeeded, or the zero time if none has since the server started.
+func (q *ReplicationQueue) LastPush() time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.lastPush
+}
+
+// called with q.mu locked
+func (q *ReplicationQueue) sortedPushes() []QueuedPush {
+	pushes := make([]QueuedPush, 0, len(q.pushes))
+	for _, p := range q.pushes {
+		pushes = append(pushes, p)
+	}
+	sort.Slice(pushes, func(i, j int) bool {
+		return pushes[i].Ref < pushes[j].Ref
+	})
+	return pushes
+}
+
+// called with q.mu locked
+func (q *ReplicationQueue) save() error {
+	if len(q.pushes) == 0 {
+		if exists, _ := q.fs.Exists(q.path); exists {
+			return q.fs.DeleteFile(q.path)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(replicationQueueFile{Version: replicationQueueFileVersion, Pushes: q.sortedPushes()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return q.fs.WriteFile(q.path, data)
+}
+
+// enqueue queues a push of |ref| at |h|, replacing any push of |ref| that's already queued.
+func (q *ReplicationQueue) enqueue(ref string, h hash.Hash, now time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	p := QueuedPush{Ref: ref, EnqueuedAt: now, NextAttempt: now}
+	if !h.IsEmpty() {
+		p.Hash = h.String()
+	}
+	q.pushes[ref] = p
+	err := q.save()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return err
+}
+
+// due returns the queued pushes whose next attempt is at or before |now|.
+func (q *ReplicationQueue) due(now time.Time) []QueuedPush {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var due []QueuedPush
+	for _, p := range q.sortedPushes() {
+		if !p.NextAttempt.After(now) {
+			due = append(due, p)
+		}
+	}
+	return due
+}
+
+// done removes |p| from the queue after it was pushed. It isn't removed if its ref was queued again since.
+func (q *ReplicationQueue) done(p QueuedPush) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.lastPush = time.Now()
+	if cur, ok := q.pushes[p.Ref]; !ok || !cur.EnqueuedAt.Equal(p.EnqueuedAt) {
+		return nil
+	}
+	delete(q.pushes, p.Ref)
+	return q.save()
+}
+
+// failed records that pushing |p| failed with |err|, and backs off its next attempt exponentially.
+func (q *ReplicationQueue) failed(p QueuedPush, err error, now time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	cur, ok := q.pushes[p.Ref]
+	if !ok || !cur.EnqueuedAt.Equal(p.EnqueuedAt) {
+		return nil
+	}
+	cur.Attempts++
+	cur.LastError = err.Error()
+	cur.NextAttempt = now.Add(replicationQueueBackoff(cur.Attempts))
+	q.pushes[p.Ref] = cur
+	return q.save()
+}
+
+func replicationQueueBackoff(attempts int) time.Duration {
+	backoff := replicationQueueMinBackoff
+	for i := 1; i < attempts && backoff < replicationQueueMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > replicationQueueMaxBackoff {
+		backoff = replicationQueueMaxBackoff
+	}
+	return backoff
+}
+
+// QueuedPushOnWriteHook is a CommitHook that queues pushes of the heads it's executed for to a ReplicationQueue, and
+// returns without waiting for them. A background worker drains the queue, retrying pushes that fail. Like
+// AsyncPushOnWriteHook, it pushes the latest head of each ref, so a ref that moves several times before it's pushed
+// is only pushed once.
+type QueuedPushOnWriteHook struct {
+	queue  *ReplicationQueue
+	srcDB  *DoltDB
+	destDB *DoltDB
+	tmpDir string
+	out    io.Writer
+}
+
+var _ CommitHook = (*QueuedPushOnWriteHook)(nil)
+
+// NewQueuedPushOnWriteHook creates a QueuedPushOnWriteHook that pushes the refs of |srcDB| to |destDB|, and starts
+// its worker in |bThreads|. Pushes left in |queue| when the server last stopped are retried first.
+func NewQueuedPushOnWriteHook(bThreads *sql.BackgroundThreads, queue *ReplicationQueue, srcDB, destDB *DoltDB, tmpDir string, logger io.Writer) (*QueuedPushOnWriteHook, error) {
+	h := &QueuedPushOnWriteHook{
+		queue:  queue,
+		srcDB:  srcDB,
+		destDB: destDB,
+		tmpDir: tmpDir,
+		out:    logger,
+	}
+	err := bThreads.Add(replicationQueueWorker, h.run)
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Queue returns the queue of this hook's pushes.
+func (h *QueuedPushOnWriteHook) Queue() *ReplicationQueue {
+	return h.queue
+}
+
+func (*QueuedPushOnWriteHook) ExecuteForWorkingSets() bool {
+	return false
+}
+
+// Execute implements CommitHook, queues a push of the new head of |ds|
+func (h *QueuedPushOnWriteHook) Execute(ctx context.Context, ds datas.Dataset, db datas.Database) (func(context.Context) error, error) {
+	addr, _ := ds.MaybeHeadAddr()
+	return nil, h.queue.enqueue(ds.ID(), addr, time.Now())
+}
+
+// HandleError implements CommitHook
+func (h *QueuedPushOnWriteHook) HandleError(ctx context.Context, err error) error {
+	if h.out != nil {
+		h.out.Write([]byte(err.Error()))
+	}
+	return nil
+}
+
+// SetLogger implements CommitHook
+func (h *QueuedPushOnWriteHook) SetLogger(ctx context.Context, wr io.Writer) error {
+	h.out = wr
+	return nil
+}
+
+// run drains the queue until |ctx| is canceled, and then attempts the pushes that are due one last time. Pushes that
+// are still queued then stay persisted until the server starts again.
+func (h *QueuedPushOnWriteHook) run(ctx context.Context) {
+	ticker := time.NewTicker(asyncPushInterval)
+	defer ticker.Stop()
+	for {
+		h.drain(ctx)
+		select {
+		case <-ctx.Done():
+			// use background context to drain after sql context is canceled
+			h.drain(context.Background())
+			return
+		case <-ticker.C:
+		case <-h.queue.wake:
+		}
+	}
+}
+
+// drain attempts the pushes that are due.
+func (h *QueuedPushOnWriteHook) drain(ctx context.Context) {
+	for _, p := range h.queue.due(time.Now()) {
+		if ctx.Err() != nil {
+			return
+		}
+		err := h.push(ctx, p)
+		if err == nil {
+			err = h.queue.done(p)
+		} else {
+			h.logf("replication of %s failed, attempt %d: %v\n", p.Ref, p.Attempts+1, err)
+			err = h.queue.failed(p, err, time.Now())
+		}
+		if err != nil {
+			h.logf("could not save replication queue: %v\n", err)
+		}
+	}
+}
+
+// push pushes the current head of |p|'s ref, which is |p|'s hash or a head that's queued after it.
+func (h *QueuedPushOnWriteHook) push(ctx context.Context, p QueuedPush) error {
+	ds, err := h.srcDB.db.GetDataset(ctx, p.Ref)
+	if err != nil {
+		return err
+	}
+	return pushDataset(ctx, h.destDB.db, h.srcDB.db, ds, h.tmpDir)
+}
+
+func (h *QueuedPushOnWriteHook) logf(format string, args ...interface{}) {
+	if h.out != nil {
+		h.out.Write([]byte(fmt.Sprintf(format, args...)))
+	}
+}
+
+// ReplicationQueue returns the queue of pushes to this database's replication remote, or nil if it doesn't replicate
+// through one.
+func (ddb *DoltDB) ReplicationQueue() *ReplicationQueue {
+	for _, h := range ddb.CommitHooks() {
+		if qh, ok := h.(*QueuedPushOnWriteHook); ok {
+			return qh.queue
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestReplicationQueue(t *testing.T) {
+	fs := filesys.EmptyInMemFS("/")
+	path := "/.dolt/" + ReplicationQueueFile
+	q, err := LoadReplicationQueue(fs, path)
+	require.NoError(t, err)
+	assert.Empty(t, q.Pushes())
+
+	now := time.Now().UTC()
+	first, second := hash.Of([]byte("first")), hash.Of([]byte("second"))
+	require.NoError(t, q.enqueue("refs/heads/main", first, now))
+	require.NoError(t, q.enqueue("refs/heads/main", second, now.Add(time.Millisecond)))
+	require.NoError(t, q.enqueue("refs/heads/other", hash.Hash{}, now))
+
+	// pushes of the same ref are coalesced
+	pushes := q.due(now.Add(time.Second))
+	require.Len(t, pushes, 2)
+	assert.Equal(t, second.String(), pushes[0].Hash)
+	assert.Equal(t, "", pushes[1].Hash)
+
+	// failed pushes back off
+	require.NoError(t, q.failed(pushes[0], errors.New("remote unavailable"), now))
+	require.NoError(t, q.failed(pushes[0], errors.New("remote unavailable"), now))
+	assert.Len(t, q.due(now.Add(time.Second)), 1)
+	assert.Len(t, q.due(now.Add(2*time.Second)), 2)
+
+	// the queue survives restarts
+	q, err = LoadReplicationQueue(fs, path)
+	require.NoError(t, err)
+	pushes = q.Pushes()
+	require.Len(t, pushes, 2)
+	assert.Equal(t, 2, pushes[0].Attempts)
+	assert.Equal(t, "remote unavailable", pushes[0].LastError)
+
+	// a push that's superseded while it's in flight stays queued
+	require.NoError(t, q.enqueue("refs/heads/other", first, now.Add(time.Second)))
+	require.NoError(t, q.done(pushes[1]))
+	require.NoError(t, q.done(pushes[0]))
+	pushes = q.Pushes()
+	require.Len(t, pushes, 1)
+	assert.Equal(t, first.String(), pushes[0].Hash)
+
+	require.NoError(t, q.done(pushes[0]))
+	exists, _ := fs.Exists(path)
+	assert.False(t, exists)
+}
+
+func TestReplicationQueueBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, replicationQueueBackoff(1))
+	assert.Equal(t, 2*time.Second, replicationQueueBackoff(2))
+	assert.Equal(t, 32*time.Second, replicationQueueBackoff(6))
+	assert.Equal(t, time.Minute, replicationQueueBackoff(7))
+	assert.Equal(t, time.Minute, replicationQueueBackoff(100))
+}
@@ -672,6 +672,31 @@ func (root *RootValue) GetTableByColTag(ctx context.Context, tag uint64) (tbl *T
 	return tbl, name, found, nil
 }
 
+// GetTableOrRenamed retrieves the table named |tName|, or if there's no such table, the table that has any of the
+// column tags of |sch|, which is how a table that was renamed after |sch| was read is found. Returns the name of the
+// table found.
+func (root *RootValue) GetTableOrRenamed(ctx context.Context, tName string, sch schema.Schema) (tbl *Table, name string, found bool, err error) {
+	tbl, found, err = root.GetTable(ctx, tName)
+	if err != nil || found {
+		return tbl, tName, found, err
+	}
+
+	err = root.IterTables(ctx, func(tn string, t *Table, s schema.Schema) (bool, error) {
+		for _, tag := range sch.GetAllCols().Tags {
+			if _, found = s.GetAllCols().GetByTag(tag); found {
+				name, tbl = tn, t
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	return tbl, name, found, nil
+}
+
 // GetTableNames retrieves the lists of all tables for a RootValue
 func (root *RootValue) GetTableNames(ctx context.Context) ([]string, error) {
 	tableMap, err := root.getTableMap(ctx)
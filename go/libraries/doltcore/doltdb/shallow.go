@@ -0,0 +1,281 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/datas/pull"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrShallowUnsupported is returned when a shallow clone is requested of, or into, a database that can't hold one.
+var ErrShallowUnsupported = errors.New("shallow clones are only supported for local databases in the __DOLT__ format")
+
+// ErrNotShallow is returned when deepening a database that isn't a shallow clone.
+var ErrNotShallow = errors.New("the database is not a shallow clone")
+
+// A shallow clone holds only the most recent commits of the history it was cloned from. The commits it is missing
+// are recorded by its chunk store, as described by chunks.ShallowChunkStore. The commits whose parents are missing
+// are treated as having no parents, and chunks referencing missing commits, like the commit closures, are left
+// dangling, so pulls into a shallow clone skip the missing commits, and garbage collection doesn't keep them.
+
+// IsShallow returns whether the database is a shallow clone.
+func (ddb *DoltDB) IsShallow(ctx context.Context) (bool, error) {
+	missing, err := ddb.missingCommits(ctx)
+	if err != nil {
+		return false, err
+	}
+	return missing.Size() > 0, nil
+}
+
+// missingCommits returns the commits missing from the database, which are only recorded for shallow clones.
+func (ddb *DoltDB) missingCommits(ctx context.Context) (hash.HashSet, error) {
+	return missingCommits(ctx, ddb.db)
+}
+
+func missingCommits(ctx context.Context, db datas.Database) (hash.HashSet, error) {
+	scs, ok := datas.ChunkStoreFromDatabase(db).(chunks.ShallowChunkStore)
+	if !ok {
+		return hash.NewHashSet(), nil
+	}
+	return scs.MissingCommits(ctx)
+}
+
+// ShallowPull pulls the commits at |heads| from |srcDB|, along with their ancestors up to |depth| commits deep, into
+// this database, and records the rest of their ancestors as missing.
+func (ddb *DoltDB) ShallowPull(ctx context.Context, tempDir string, srcDB *DoltDB, heads []hash.Hash, depth int, statsCh chan pull.Stats) error {
+	if !srcDB.Format().UsesFlatbuffers() {
+		return ErrShallowUnsupported
+	}
+	scs, ok := datas.ChunkStoreFromDatabase(ddb.db).(chunks.ShallowChunkStore)
+	if !ok {
+		return ErrShallowUnsupported
+	}
+
+	kept, frontier, err := srcDB.walkDepth(ctx, heads, depth)
+	if err != nil {
+		return err
+	}
+
+	exclude := hash.NewHashSet()
+	for _, h := range frontier {
+		exclude.Insert(h)
+		cm, err := srcDB.ReadCommit(ctx, h)
+		if err != nil {
+			return err
+		}
+		err = insertClosure(ctx, cm, exclude)
+		if err != nil {
+			return err
+		}
+	}
+	for h := range kept {
+		exclude.Remove(h)
+	}
+
+	err = pullHashExcluding(ctx, ddb.db, srcDB.db, heads, exclude, tempDir, statsCh, PullOptions{})
+	if err != nil {
+		return err
+	}
+	if exclude.Size() == 0 {
+		return nil
+	}
+	return scs.SetMissingCommits(ctx, exclude)
+}
+
+// Deepen pulls up to |depth| more commits of history from |srcDB| into this shallow clone, starting from the parents
+// of its oldest commits. It returns ErrNotShallow if the database isn't a shallow clone.
+func (ddb *DoltDB) Deepen(ctx context.Context, tempDir string, srcDB *DoltDB, depth int, statsCh chan pull.Stats) error {
+	scs, ok := datas.ChunkStoreFromDatabase(ddb.db).(chunks.ShallowChunkStore)
+	if !ok {
+		return ErrNotShallow
+	}
+	missing, err := scs.MissingCommits(ctx)
+	if err != nil {
+		return err
+	}
+	if missing.Size() == 0 {
+		return ErrNotShallow
+	}
+
+	boundary, err := ddb.missingParents(ctx, missing)
+	if err != nil {
+		return err
+	}
+
+	kept, _, err := srcDB.walkDepth(ctx, boundary, depth)
+	if err != nil {
+		return err
+	}
+
+	remaining := missing.Copy()
+	for h := range kept {
+		remaining.Remove(h)
+	}
+
+	err = pullHashExcluding(ctx, ddb.db, srcDB.db, boundary, remaining, tempDir, statsCh, PullOptions{})
+	if err != nil {
+		return err
+	}
+	return scs.SetMissingCommits(ctx, remaining)
+}
+
+// walkDepth walks the history of |heads| in |ddb| breadth first, to |depth| commits deep. It returns the commits it
+// walked, and the parents of the deepest of them, which it didn't.
+func (ddb *DoltDB) walkDepth(ctx context.Context, heads []hash.Hash, depth int) (hash.HashSet, []hash.Hash, error) {
+	walked := hash.NewHashSet()
+	level := heads
+	for i := 0; i < depth && len(level) > 0; i++ {
+		var next []hash.Hash
+		for _, h := range level {
+			if walked.Has(h) {
+				continue
+			}
+			walked.Insert(h)
+
+			cm, err := ddb.ReadCommit(ctx, h)
+			if err != nil {
+				return nil, nil, err
+			}
+			parents, err := cm.ParentHashes(ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+			next = append(next, parents...)
+		}
+		level = next
+	}
+
+	var frontier []hash.Hash
+	seen := hash.NewHashSet()
+	for _, h := range level {
+		if !walked.Has(h) && !seen.Has(h) {
+			seen.Insert(h)
+			frontier = append(frontier, h)
+		}
+	}
+	return walked, frontier, nil
+}
+
+// insertClosure inserts the addresses of the ancestors of |cm| listed in its commit closure into |hs|.
+func insertClosure(ctx context.Context, cm *Commit, hs hash.HashSet) error {
+	if cm.NumParents() == 0 {
+		return nil
+	}
+	closure, err := cm.GetCommitClosure(ctx)
+	if err != nil {
+		return err
+	}
+	if closure.IsEmpty() {
+		return nil
+	}
+	itr, err := closure.IterAllReverse(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		k, _, err := itr.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		hs.Insert(k.Addr())
+	}
+}
+
+// shallowRefFilter matches the refs whose histories a shallow clone holds.
+var shallowRefFilter = map[ref.RefType]struct{}{
+	ref.BranchRefType:    {},
+	ref.RemoteRefType:    {},
+	ref.TagRefType:       {},
+	ref.WorkspaceRefType: {},
+}
+
+// missingParents returns the missing commits that are parents of the commits in the database, which are the commits a
+// shallow clone is deepened from.
+func (ddb *DoltDB) missingParents(ctx context.Context, missing hash.HashSet) ([]hash.Hash, error) {
+	var pending []hash.Hash
+	err := ddb.VisitRefsOfType(ctx, shallowRefFilter, func(r ref.DoltRef, addr hash.Hash) error {
+		if r.GetType() == ref.TagRefType {
+			tag, err := ddb.ResolveTag(ctx, ref.NewTagRef(r.GetPath()))
+			if err != nil {
+				return err
+			}
+			addr, err = tag.Commit.HashOf()
+			if err != nil {
+				return err
+			}
+		}
+		pending = append(pending, addr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var parents []hash.Hash
+	visited := hash.NewHashSet()
+	for len(pending) > 0 {
+		h := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if visited.Has(h) {
+			continue
+		}
+		visited.Insert(h)
+
+		if missing.Has(h) {
+			parents = append(parents, h)
+			continue
+		}
+		cm, err := datas.LoadCommitAddr(ctx, ddb.vrw, h)
+		if err != nil {
+			return nil, err
+		}
+		sm, ok := cm.NomsValue().(types.SerialMessage)
+		if !ok {
+			return nil, ErrShallowUnsupported
+		}
+		addrs, err := types.SerialCommitParentAddrs(ddb.Format(), sm)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, addrs...)
+	}
+	return parents, nil
+}
+
+// skipAddrs returns a walk function that walks the addresses that |waf| does, except for those in |skip|.
+func skipAddrs(waf pull.WalkAddrs, skip hash.HashSet) pull.WalkAddrs {
+	if skip.Size() == 0 {
+		return waf
+	}
+	return func(c chunks.Chunk, cb func(hash.Hash, bool) error) error {
+		return waf(c, func(h hash.Hash, leaf bool) error {
+			if skip.Has(h) {
+				return nil
+			}
+			return cb(h, leaf)
+		})
+	}
+}
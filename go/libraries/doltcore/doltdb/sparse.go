@@ -0,0 +1,277 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/datas/pull"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrSparseUnsupported is returned when a sparse clone is requested of, or into, a database that can't hold one.
+var ErrSparseUnsupported = errors.New("sparse clones are only supported for local databases in the __DOLT__ format")
+
+// ErrSparseFetch is returned when the data of a table missing from a sparse clone can't be fetched from its remote.
+var ErrSparseFetch = errors.New("failed to fetch table data missing from sparse clone")
+
+// A sparse clone holds the data of only some of the tables of the database it was cloned from, named by its sparse
+// spec. Pulls into it leave out the data of the other tables, their rows and indexes, but not the tables themselves or
+// their schemas, so that they can still be listed and described without the remote. The addresses of the data left out
+// are recorded by its chunk store, as described by chunks.SparseChunkStore, so that it's fetched from the remote the
+// first time it's read. Tables are matched by name in every commit pulled, so a table that's renamed is treated as a new
+// one.
+
+// sparseSpec is the sparse spec of a database, set by SetSparseSpec.
+type sparseSpec struct {
+	// tables holds the lower-cased names of the tables whose data is pulled
+	tables map[string]struct{}
+}
+
+// IsSparse returns whether the database is a sparse clone that's missing the data of some tables.
+func (ddb *DoltDB) IsSparse(ctx context.Context) (bool, error) {
+	scs, ok := datas.ChunkStoreFromDatabase(ddb.db).(chunks.SparseChunkStore)
+	if !ok {
+		return false, nil
+	}
+	sparse, err := scs.SparseChunks(ctx)
+	if err != nil {
+		return false, err
+	}
+	return sparse.Size() > 0, nil
+}
+
+// SetSparseSpec makes this database a sparse clone holding the data of only the tables named in |tables|. The data of
+// the other tables is left out of pulls into it, and fetched from the database returned by |remoteDB| the first time
+// it's read.
+func (ddb *DoltDB) SetSparseSpec(tables []string, tempDir string, remoteDB func(ctx context.Context) (*DoltDB, error)) error {
+	if !ddb.Format().UsesFlatbuffers() {
+		return ErrSparseUnsupported
+	}
+	scs, ok := datas.ChunkStoreFromDatabase(ddb.db).(chunks.SparseChunkStore)
+	if !ok {
+		return ErrSparseUnsupported
+	}
+
+	ddb.sparse = &sparseSpec{tables: sparseTableSet(tables)}
+	scs.SetSparseFetcher(func(ctx context.Context, addrs hash.HashSet) error {
+		srcDB, err := remoteDB(ctx)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrSparseFetch, err.Error())
+		}
+		targets := make([]hash.Hash, 0, addrs.Size())
+		for h := range addrs {
+			targets = append(targets, h)
+		}
+		err = pullHashExcluding(ctx, ddb.db, srcDB.db, targets, nil, tempDir, nil, PullOptions{})
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrSparseFetch, err.Error())
+		}
+		return nil
+	})
+	return nil
+}
+
+// SparsePull pulls the commits at |heads| from |srcDB| into this database, with the data of only the tables named in
+// |tables|, and records the other tables as missing.
+func (ddb *DoltDB) SparsePull(ctx context.Context, tempDir string, srcDB *DoltDB, heads []hash.Hash, tables []string, statsCh chan pull.Stats) error {
+	if !srcDB.Format().UsesFlatbuffers() {
+		return ErrSparseUnsupported
+	}
+	if _, ok := datas.ChunkStoreFromDatabase(ddb.db).(chunks.SparseChunkStore); !ok {
+		return ErrSparseUnsupported
+	}
+
+	exclude, err := srcDB.sparseTableAddrs(ctx, heads, sparseTableSet(tables), nil)
+	if err != nil {
+		return err
+	}
+	err = pullHashExcluding(ctx, ddb.db, srcDB.db, heads, exclude, tempDir, statsCh, PullOptions{})
+	if err != nil {
+		return err
+	}
+	return ddb.updateSparse(ctx, exclude)
+}
+
+// sparseExclusions returns the addresses of the table data left out of the sparse spec of this database in the commits
+// reachable from |targetHashes| in |srcDB| that this database doesn't have yet, or nil if the database isn't sparse.
+func (ddb *DoltDB) sparseExclusions(ctx context.Context, srcDB *DoltDB, targetHashes []hash.Hash) (hash.HashSet, error) {
+	if ddb.sparse == nil {
+		return nil, nil
+	}
+	cs := datas.ChunkStoreFromDatabase(ddb.db)
+	return srcDB.sparseTableAddrs(ctx, targetHashes, ddb.sparse.tables, func(h hash.Hash) (bool, error) {
+		return cs.Has(ctx, h)
+	})
+}
+
+// sparseTableAddrs walks the commits reachable from |heads| in |ddb|, and returns the addresses of the data of the
+// tables in them that aren't named in |tables|, other than those shared with tables that are. The history of the commits that |skip| returns
+// true for isn't walked. Addresses that aren't commits are ignored.
+func (ddb *DoltDB) sparseTableAddrs(ctx context.Context, heads []hash.Hash, tables map[string]struct{}, skip func(hash.Hash) (bool, error)) (hash.HashSet, error) {
+	exclude, keep := hash.NewHashSet(), hash.NewHashSet()
+	visited := hash.NewHashSet()
+	pending := append([]hash.Hash(nil), heads...)
+	for len(pending) > 0 {
+		h := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if visited.Has(h) {
+			continue
+		}
+		visited.Insert(h)
+
+		if skip != nil {
+			ok, err := skip(h)
+			if err != nil {
+				return nil, err
+			} else if ok {
+				continue
+			}
+		}
+
+		// the parents of the oldest commits of a shallow clone aren't there
+		v, err := ddb.vrw.ReadValue(ctx, h)
+		if err != nil {
+			return nil, err
+		} else if v == nil {
+			continue
+		}
+		if isCommit, err := datas.IsCommit(v); err != nil {
+			return nil, err
+		} else if !isCommit {
+			continue
+		}
+
+		cm, err := ddb.ReadCommit(ctx, h)
+		if err != nil {
+			return nil, err
+		}
+		root, err := cm.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tm, err := root.getTableMap(ctx)
+		if err != nil {
+			return nil, err
+		}
+		err = tmIterAll(ctx, tm, func(name string, addr hash.Hash) {
+			if _, ok := tables[strings.ToLower(name)]; ok {
+				keep.Insert(addr)
+			} else {
+				exclude.Insert(addr)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		parents, err := cm.ParentHashes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, parents...)
+	}
+
+	data, err := ddb.tableData(ctx, exclude, keep)
+	if err != nil {
+		return nil, err
+	}
+	kept, err := ddb.tableData(ctx, keep, nil)
+	if err != nil {
+		return nil, err
+	}
+	for h := range kept {
+		data.Remove(h)
+	}
+	return data, nil
+}
+
+// tableData returns the addresses referenced by the tables at |addrs| other than those of their schemas, skipping the
+// tables in |skip|.
+func (ddb *DoltDB) tableData(ctx context.Context, addrs, skip hash.HashSet) (hash.HashSet, error) {
+	cs := datas.ChunkStoreFromDatabase(ddb.db)
+	walk := types.WalkAddrsForNBF(ddb.Format())
+	data := hash.NewHashSet()
+	for addr := range addrs {
+		if skip.Has(addr) {
+			continue
+		}
+		tbl, err := durable.TableFromAddr(ctx, ddb.vrw, ddb.ns, addr)
+		if err != nil {
+			return nil, err
+		}
+		schAddr, err := tbl.GetSchemaHash(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c, err := cs.Get(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+		err = walk(c, func(h hash.Hash, _ bool) error {
+			if h != schAddr {
+				data.Insert(h)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// updateSparse records the addresses in |add| as missing from this database, along with those recorded already, if
+// they're still missing.
+func (ddb *DoltDB) updateSparse(ctx context.Context, add hash.HashSet) error {
+	cs := datas.ChunkStoreFromDatabase(ddb.db)
+	scs, ok := cs.(chunks.SparseChunkStore)
+	if !ok {
+		return nil
+	}
+	sparse, err := scs.SparseChunks(ctx)
+	if err != nil {
+		return err
+	}
+	if sparse.Size() == 0 && add.Size() == 0 {
+		return nil
+	}
+
+	candidates := sparse.Copy()
+	candidates.InsertAll(add)
+	absent, err := cs.HasMany(ctx, candidates)
+	if err != nil {
+		return err
+	}
+	if absent.Equals(sparse) {
+		return nil
+	}
+	return scs.SetSparseChunks(ctx, absent)
+}
+
+func sparseTableSet(tables []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(tables))
+	for _, t := range tables {
+		set[strings.ToLower(t)] = struct{}{}
+	}
+	return set
+}
@@ -70,10 +70,23 @@ func IsFullTextTable(name string) bool {
 		strings.HasSuffix(name, "_fts_row_count"))
 }
 
+// IsVectorIndexTable returns a boolean stating whether the given table is one of the pseudo-index tables used by vector
+// indexes.
+func IsVectorIndexTable(name string) bool {
+	lwr := strings.ToLower(name)
+	return lwr == VectorIndexesTableName || strings.HasPrefix(lwr, VectorIndexListsTablePrefix)
+}
+
+// VectorIndexListsTableName returns the name of the pseudo-index table that holds the lists of the vector indexes of
+// the table |tableName|.
+func VectorIndexListsTableName(tableName string) string {
+	return VectorIndexListsTablePrefix + tableName
+}
+
 // IsReadOnlySystemTable returns whether the table name given is a system table that should not be included in command line
 // output (e.g. dolt status) by default.
 func IsReadOnlySystemTable(name string) bool {
-	return HasDoltPrefix(name) && !set.NewStrSet(writeableSystemTables).Contains(name) && !IsFullTextTable(name)
+	return HasDoltPrefix(name) && !set.NewStrSet(writeableSystemTables).Contains(name) && !IsFullTextTable(name) && !IsVectorIndexTable(name)
 }
 
 // IsNonAlterableSystemTable returns whether the table name given is a system table that cannot be dropped or altered
@@ -161,6 +174,7 @@ var writeableSystemTables = []string{
 	SchemasTableName,
 	ProceduresTableName,
 	IgnoreTableName,
+	MergeStrategiesTableName,
 }
 
 var persistedSystemTables = []string{
@@ -169,6 +183,7 @@ var persistedSystemTables = []string{
 	SchemasTableName,
 	ProceduresTableName,
 	IgnoreTableName,
+	MergeStrategiesTableName,
 }
 
 var generatedSystemTables = []string{
@@ -290,6 +305,9 @@ const (
 	// TableOfTablesWithViolationsName is the constraint violations system table name
 	TableOfTablesWithViolationsName = "dolt_constraint_violations"
 
+	// ConstraintViolationSourcesTableName is the constraint violation sources system table name
+	ConstraintViolationSourcesTableName = "dolt_constraint_violation_sources"
+
 	// SchemaConflictsTableName is the schema conflicts system table name
 	SchemaConflictsTableName = "dolt_schema_conflicts"
 
@@ -317,7 +335,43 @@ const (
 	// TagsTableName is the tags table name
 	TagsTableName = "dolt_tags"
 
+	// WebhookDeliveriesTableName is the name of the table that logs the server's recent webhook deliveries
+	WebhookDeliveriesTableName = "dolt_webhook_deliveries"
+
+	// FormatUpgradesTableName is the name of the table that shows the progress of the server's storage format upgrades
+	FormatUpgradesTableName = "dolt_format_upgrades"
+
+	// ReplicationStatusTableName is the name of the table that shows a read replica's attempts to clone databases
+	ReplicationStatusTableName = "dolt_replication_status"
+
+	// ReplicationQueueTableName is the name of the table that shows the pushes queued for a database's replication remote
+	ReplicationQueueTableName = "dolt_replication_queue"
+
+	// SessionCacheStatusTableName is the name of the table that shows the statistics of the session's database cache
+	SessionCacheStatusTableName = "dolt_session_cache_status"
+
+	// ProposalsTableName is the name of the table that lists change proposals
+	ProposalsTableName = "dolt_proposals"
+
+	// ProposalCommentsTableName is the name of the table that lists the comments left on change proposals
+	ProposalCommentsTableName = "dolt_proposal_comments"
+
+	// PatchRejectsTableName is the name of the table that lists the hunks rejected by the last dolt_apply_patch() call
+	PatchRejectsTableName = "dolt_patch_rejects"
+
+	// ColumnMasksTableName is the name of the table that lists the column masking policies of a database
+	ColumnMasksTableName = "dolt_column_masks"
+
+	// VectorIndexesTableName is the name of the pseudo-index table that holds the centroids of vector indexes
+	VectorIndexesTableName = "dolt_vector_indexes"
+
+	// VectorIndexListsTablePrefix is the name prefix of the pseudo-index tables that hold the lists of vector indexes
+	VectorIndexListsTablePrefix = "dolt_vector_lists_"
+
 	IgnoreTableName = "dolt_ignore"
+
+	// MergeStrategiesTableName is the name of the table that declares how merges resolve conflicts in matching tables
+	MergeStrategiesTableName = "dolt_merge_strategies"
 )
 
 const (
@@ -67,3 +67,12 @@ func (t *Tag) GetAddr() (hash.Hash, error) {
 func (t *Tag) GetDoltRef() ref.DoltRef {
 	return ref.NewTagRef(t.Name)
 }
+
+// GetSignature returns the signature of this Tag, and the signing payload it was made for. The signature is empty if
+// the tag isn't signed.
+func (t *Tag) GetSignature() ([]byte, string) {
+	if t.Meta == nil || t.Meta.Signature == "" {
+		return nil, ""
+	}
+	return datas.TagSigningPayload(t.GetDoltRef().String(), t.Commit.dCommit.Addr(), t.Meta), t.Meta.Signature
+}
@@ -103,6 +103,21 @@ func (m MergeState) MergedTables() []string {
 	return m.mergedTables
 }
 
+// getRenamedTable returns the table in |root| that |tbl|, named |name|, has another name for.
+func getRenamedTable(ctx context.Context, root *RootValue, name string, tbl *Table) (*Table, error) {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	renamed, _, ok, err := root.GetTableOrRenamed(ctx, name, sch)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrTableNotFound, name)
+	}
+	return renamed, nil
+}
+
 func (m MergeState) IterSchemaConflicts(ctx context.Context, ddb *DoltDB, cb SchemaConflictFn) (err error) {
 	var to, from *RootValue
 
@@ -131,14 +146,25 @@ func (m MergeState) IterSchemaConflicts(ctx context.Context, ddb *DoltDB, cb Sch
 
 	for _, name := range m.unmergableTables {
 		var sc SchemaConflict
-		if sc.toTbl, _, err = to.GetTable(ctx, name); err != nil {
+		var toOk, fromOk bool
+		if sc.toTbl, toOk, err = to.GetTable(ctx, name); err != nil {
 			return err
 		}
-		// todo: handle schema conflicts for renamed tables
-		if sc.fromTbl, _, err = from.GetTable(ctx, name); err != nil {
+		if sc.fromTbl, fromOk, err = from.GetTable(ctx, name); err != nil {
 			return err
 		}
 
+		// a table renamed on one side of the merge has its old name on the other
+		if !toOk && fromOk {
+			if sc.toTbl, err = getRenamedTable(ctx, to, name, sc.fromTbl); err != nil {
+				return err
+			}
+		} else if toOk && !fromOk {
+			if sc.fromTbl, err = getRenamedTable(ctx, from, name, sc.toTbl); err != nil {
+				return err
+			}
+		}
+
 		if sc.ToSch, err = sc.toTbl.GetSchema(ctx); err != nil {
 			return err
 		}
@@ -0,0 +1,152 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// BisectStep is the next step of a bisect session, as computed by NextBisectStep. At most one of Next, FirstBad and
+// Candidates is set. None are set while the session is waiting for a good or a bad commit to be marked.
+type BisectStep struct {
+	// Next is the commit to test next.
+	Next *doltdb.Commit
+	// Remaining is the number of commits that may be left to test after Next.
+	Remaining int
+	// FirstBad is the first bad commit, once it has been found.
+	FirstBad *doltdb.Commit
+	// Candidates are the commits that could be the first bad commit, when only skipped commits are left to test.
+	Candidates []*doltdb.Commit
+}
+
+// Steps returns roughly how many more commits will need to be tested after Next.
+func (s BisectStep) Steps() int {
+	return bits.Len(uint(s.Remaining))
+}
+
+// NextBisectStep computes the next step of the bisect session |state|. The commits that could be the first bad commit
+// are those reachable from the bad commit that aren't reachable from any good commit. The commit to test next is the
+// one that best halves them: whether it's good or bad, as few candidates as possible are left. Skipped commits are
+// never chosen.
+func NextBisectStep(ctx context.Context, ddb *doltdb.DoltDB, state doltdb.BisectState) (BisectStep, error) {
+	if state.Bad == "" || len(state.Good) == 0 {
+		return BisectStep{}, nil
+	}
+
+	bad, err := parseBisectHashes(state.Bad)
+	if err != nil {
+		return BisectStep{}, err
+	}
+	good, err := parseBisectHashes(state.Good...)
+	if err != nil {
+		return BisectStep{}, err
+	}
+	skip, err := parseBisectHashes(state.Skip...)
+	if err != nil {
+		return BisectStep{}, err
+	}
+
+	// the candidates are ordered children first, and always begin with the bad commit
+	candidates, err := commitwalk.GetDotDotRevisions(ctx, ddb, bad, ddb, good, -1)
+	if err != nil {
+		return BisectStep{}, err
+	}
+	if len(candidates) == 0 {
+		return BisectStep{}, fmt.Errorf("error: the bad commit %s is an ancestor of a good commit", state.Bad)
+	}
+	if len(candidates) == 1 {
+		return BisectStep{FirstBad: candidates[0]}, nil
+	}
+
+	n := len(candidates)
+	index := make(map[hash.Hash]int, n)
+	for i, cm := range candidates {
+		h, err := cm.HashOf()
+		if err != nil {
+			return BisectStep{}, err
+		}
+		index[h] = i
+	}
+
+	skipped := hash.NewHashSet(skip...)
+	best, bestScore, bestCount := -1, -1, 0
+	reachable := make([][]uint64, n)
+	for i := n - 1; i >= 0; i-- {
+		// parents come after their children, so the candidates reachable from each parent are already known
+		set := make([]uint64, (n+63)/64)
+		set[i/64] |= 1 << (i % 64)
+		parents, err := candidates[i].ParentHashes(ctx)
+		if err != nil {
+			return BisectStep{}, err
+		}
+		for _, p := range parents {
+			if j, ok := index[p]; ok {
+				for w := range set {
+					set[w] |= reachable[j][w]
+				}
+			}
+		}
+		reachable[i] = set
+
+		h, err := candidates[i].HashOf()
+		if err != nil {
+			return BisectStep{}, err
+		}
+		if i == 0 || skipped.Has(h) {
+			continue
+		}
+
+		// if the commit is bad, the candidates reachable from it are left, otherwise the others are
+		count := 0
+		for _, w := range set {
+			count += bits.OnesCount64(w)
+		}
+		score := count
+		if n-count < score {
+			score = n - count
+		}
+		if score >= bestScore {
+			best, bestScore, bestCount = i, score, count
+		}
+	}
+
+	if best < 0 {
+		return BisectStep{Candidates: candidates}, nil
+	}
+	// one of the candidates left is the commit already known to be bad
+	remaining := bestCount
+	if n-bestCount > remaining {
+		remaining = n - bestCount
+	}
+	return BisectStep{Next: candidates[best], Remaining: remaining - 1}, nil
+}
+
+func parseBisectHashes(strs ...string) ([]hash.Hash, error) {
+	hashes := make([]hash.Hash, len(strs))
+	for i, s := range strs {
+		h, ok := hash.MaybeParse(s)
+		if !ok {
+			return nil, fmt.Errorf("invalid commit hash in bisect state: %s", s)
+		}
+		hashes[i] = h
+	}
+	return hashes, nil
+}
@@ -34,16 +34,47 @@ var ErrUnmergedBranch = errorKinds.NewKind("branch '%s' is not fully merged")
 var ErrWorkingSetsOnBothBranches = errors.New("checkout would overwrite uncommitted changes on target branch")
 
 func RenameBranch(ctx context.Context, dbData env.DbData, oldBranch, newBranch string, remoteDbPro env.RemoteDbProvider, force bool, rsc *doltdb.ReplicationStatusController) error {
+	// TODO: This function smears the branch updates across multiple commits of the datas.Database.
+
+	err := CopyBranchWithState(ctx, dbData, oldBranch, newBranch, force, rsc)
+	if err != nil {
+		return err
+	}
+
+	err = dbData.Rsw.RemoveBranch(oldBranch)
+	if err != nil {
+		return err
+	}
+
+	// a renamed branch keeps its expiration, while a copied one doesn't
+	expirations, err := dbData.Rsr.GetBranchExpirations()
+	if err != nil {
+		return err
+	}
+	err = dbData.Rsw.SetBranchExpiration(newBranch, expirations[oldBranch])
+	if err != nil {
+		return err
+	}
+
+	return DeleteBranch(ctx, dbData, oldBranch, DeleteOptions{Force: true, AllowDeletingCurrentBranch: true}, remoteDbPro, rsc)
+}
+
+// CopyBranchWithState copies the branch named to a new branch, along with the state that hangs off of it: its working
+// set, including any uncommitted changes, its metadata, and its upstream tracking configuration.
+func CopyBranchWithState(ctx context.Context, dbData env.DbData, oldBranch, newBranch string, force bool, rsc *doltdb.ReplicationStatusController) error {
 	oldRef := ref.NewBranchRef(oldBranch)
 	newRef := ref.NewBranchRef(newBranch)
 
-	// TODO: This function smears the branch updates across multiple commits of the datas.Database.
-
 	err := CopyBranchOnDB(ctx, dbData.Ddb, oldBranch, newBranch, force, rsc)
 	if err != nil {
 		return err
 	}
 
+	err = doltdb.CopyBranchMetadata(ctx, dbData.Ddb, oldRef, dbData.Ddb, newRef, rsc)
+	if err != nil {
+		return err
+	}
+
 	fromWSRef, err := ref.WorkingSetRefForHead(oldRef)
 	if err != nil {
 		if !errors.Is(err, ref.ErrWorkingSetUnsupported) {
@@ -63,11 +94,28 @@ func RenameBranch(ctx context.Context, dbData env.DbData, oldBranch, newBranch s
 		}
 	}
 
-	return DeleteBranch(ctx, dbData, oldBranch, DeleteOptions{Force: true, AllowDeletingCurrentBranch: true}, remoteDbPro, rsc)
+	branches, err := dbData.Rsr.GetBranches()
+	if err != nil {
+		return err
+	}
+	if config, ok := branches[oldBranch]; ok {
+		return dbData.Rsw.UpdateBranch(newBranch, config)
+	} else if _, ok := branches[newBranch]; ok {
+		// an overwritten branch shouldn't keep tracking its old upstream
+		return dbData.Rsw.RemoveBranch(newBranch)
+	}
+
+	return nil
 }
 
+// CopyBranch copies the branch named in |dEnv| to a new branch, along with its working set and tracking configuration.
 func CopyBranch(ctx context.Context, dEnv *env.DoltEnv, oldBranch, newBranch string, force bool) error {
-	return CopyBranchOnDB(ctx, dEnv.DoltDB, oldBranch, newBranch, force, nil)
+	return CopyBranchWithState(ctx, dEnv.DbData(), oldBranch, newBranch, force, nil)
+}
+
+// MoveBranch renames the branch named in |dEnv|, carrying its working set and tracking configuration to its new name.
+func MoveBranch(ctx context.Context, dEnv *env.DoltEnv, oldBranch, newBranch string, force bool) error {
+	return RenameBranch(ctx, dEnv.DbData(), oldBranch, newBranch, dEnv, force, nil)
 }
 
 func CopyBranchOnDB(ctx context.Context, ddb *doltdb.DoltDB, oldBranch, newBranch string, force bool, rsc *doltdb.ReplicationStatusController) error {
@@ -175,6 +223,19 @@ func DeleteBranchOnDB(ctx context.Context, dbdata env.DbData, branchRef ref.Dolt
 		}
 	}
 
+	if !opts.Remote {
+		expirations, err := dbdata.Rsr.GetBranchExpirations()
+		if err != nil {
+			return err
+		}
+		if _, ok := expirations[branchRef.GetPath()]; ok {
+			err = dbdata.Rsw.SetBranchExpiration(branchRef.GetPath(), env.BranchExpiration{})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	return ddb.DeleteBranch(ctx, branchRef, rsc)
 }
 
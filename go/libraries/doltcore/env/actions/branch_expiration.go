@@ -0,0 +1,188 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+// ArchivedBranchTagPrefix is the prefix of the tags that preserve the heads of expired branches.
+const ArchivedBranchTagPrefix = "archive/"
+
+// ExpiredBranch describes a branch deleted by ExpireBranches.
+type ExpiredBranch struct {
+	Name    string
+	Expires time.Time
+	// Owner is the user that set the branch's expiration, if it was recorded.
+	Owner string
+	// Archive is the name of the tag that now points at the branch's former head.
+	Archive string
+}
+
+// ParseBranchTTL parses a branch time-to-live. In addition to the units understood by time.ParseDuration, a TTL may be
+// given in days ("30d") or weeks ("2w").
+func ParseBranchTTL(ttl string) (time.Duration, error) {
+	var unit time.Duration
+	switch {
+	case strings.HasSuffix(ttl, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(ttl, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+
+	var d time.Duration
+	if unit != 0 {
+		n, err := strconv.ParseUint(ttl[:len(ttl)-1], 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid branch expiration '%s'", ttl)
+		}
+		d = time.Duration(n) * unit
+	} else {
+		var err error
+		d, err = time.ParseDuration(ttl)
+		if err != nil {
+			return 0, fmt.Errorf("invalid branch expiration '%s'", ttl)
+		}
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid branch expiration '%s': must be positive", ttl)
+	}
+	return d, nil
+}
+
+// ExpireBranches deletes every branch whose expiration is at or before |now| by calling |deleteBranch|, which must
+// delete the branch the same way a forced `dolt branch -D` does. Before each branch is deleted, its head is archived as
+// a tag named with ArchivedBranchTagPrefix so that its commits remain reachable, and |notify| is called with it so that
+// its owner can be told. The currently checked out branch is never deleted, and neither is any branch for which |inUse|
+// returns true; they're left to expire on a later call. |inUse| and |notify| may be nil. Returns the branches that were
+// expired, ordered by name.
+func ExpireBranches(ctx context.Context, dbData env.DbData, now time.Time, inUse func(name string) (bool, error), notify func(branch ExpiredBranch), deleteBranch func(name string) error) ([]ExpiredBranch, error) {
+	expirations, err := dbData.Rsr.GetBranchExpirations()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(expirations))
+	for name, expiration := range expirations {
+		if !expiration.Expires.After(now) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	headRef, err := dbData.Rsr.CWBHeadRef()
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []ExpiredBranch
+	for _, name := range names {
+		branchRef := ref.NewBranchRef(name)
+		if ref.Equals(headRef, branchRef) {
+			continue
+		}
+		if inUse != nil {
+			used, err := inUse(name)
+			if err != nil {
+				return nil, err
+			}
+			if used {
+				continue
+			}
+		}
+
+		hasRef, err := dbData.Ddb.HasRef(ctx, branchRef)
+		if err != nil {
+			return nil, err
+		}
+		if !hasRef {
+			// the branch was deleted out from under us, just forget about it
+			err = dbData.Rsw.SetBranchExpiration(name, env.BranchExpiration{})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		archive, err := archiveBranch(ctx, dbData.Ddb, name, expirations[name].Expires)
+		if err != nil {
+			return nil, err
+		}
+
+		branch := ExpiredBranch{Name: name, Expires: expirations[name].Expires, Owner: expirations[name].Owner, Archive: archive}
+		if notify != nil {
+			notify(branch)
+		}
+
+		err = deleteBranch(name)
+		if err != nil {
+			// don't leave an archive behind for a branch that still exists
+			_ = dbData.Ddb.DeleteTag(ctx, ref.NewTagRef(archive))
+			return nil, err
+		}
+		err = dbData.Rsw.RemoveBranch(name)
+		if err != nil {
+			return nil, err
+		}
+
+		expired = append(expired, branch)
+	}
+
+	return expired, nil
+}
+
+// archiveBranch tags the head of the branch named so that it survives the branch's deletion, and returns the name of
+// the tag. If the obvious tag name is taken, a numeric suffix is added.
+func archiveBranch(ctx context.Context, ddb *doltdb.DoltDB, name string, expires time.Time) (string, error) {
+	cs, err := doltdb.NewCommitSpec(name)
+	if err != nil {
+		return "", err
+	}
+	cm, err := ddb.Resolve(ctx, cs, nil)
+	if err != nil {
+		return "", err
+	}
+
+	tagName := ArchivedBranchTagPrefix + name
+	for i := 1; ; i++ {
+		hasRef, err := ddb.HasRef(ctx, ref.NewTagRef(tagName))
+		if err != nil {
+			return "", err
+		}
+		if !hasRef {
+			break
+		}
+		tagName = fmt.Sprintf("%s%s-%d", ArchivedBranchTagPrefix, name, i)
+	}
+
+	meta := datas.NewTagMeta(env.DefaultName, env.DefaultEmail, fmt.Sprintf("branch '%s' expired at %s", name, expires.Format(time.RFC3339)))
+	err = ddb.NewTagAtCommit(ctx, ref.NewTagRef(tagName), cm, meta)
+	if err != nil {
+		return "", err
+	}
+
+	return tagName, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+)
+
+func TestExpireBranches(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+	newEnv := func(t *testing.T) *env.DoltEnv {
+		dEnv := dtestutils.CreateTestEnv()
+		for name, expires := range map[string]time.Time{
+			"expired":  now.Add(-time.Hour),
+			"expiring": now,
+			"inuse":    now.Add(-time.Hour),
+			"later":    now.Add(time.Hour),
+		} {
+			require.NoError(t, CreateBranchWithStartPt(ctx, dEnv.DbData(), name, "HEAD", false, nil))
+			require.NoError(t, dEnv.SetBranchExpiration(name, env.BranchExpiration{Expires: expires, Owner: "owner <owner@example.com>"}))
+		}
+		require.NoError(t, dEnv.SetBranchExpiration(env.DefaultInitBranch, env.BranchExpiration{Expires: now.Add(-time.Hour)}))
+		return dEnv
+	}
+	deleteBranch := func(dEnv *env.DoltEnv) func(string) error {
+		return func(name string) error {
+			return DeleteBranch(ctx, dEnv.DbData(), name, DeleteOptions{Force: true}, dEnv, nil)
+		}
+	}
+	branchNames := func(t *testing.T, dEnv *env.DoltEnv) []string {
+		branches, err := dEnv.DoltDB.GetBranches(ctx)
+		require.NoError(t, err)
+		var names []string
+		for _, b := range branches {
+			names = append(names, b.GetPath())
+		}
+		return names
+	}
+
+	t.Run("deletes expired branches that aren't in use", func(t *testing.T) {
+		dEnv := newEnv(t)
+		defer dEnv.DoltDB.Close()
+
+		inUse := func(name string) (bool, error) {
+			return name == "inuse", nil
+		}
+		// owners are notified while the branch still exists
+		var notified []ExpiredBranch
+		notify := func(branch ExpiredBranch) {
+			assert.Contains(t, branchNames(t, dEnv), branch.Name)
+			notified = append(notified, branch)
+		}
+		expired, err := ExpireBranches(ctx, dEnv.DbData(), now, inUse, notify, deleteBranch(dEnv))
+		require.NoError(t, err)
+		assert.Equal(t, []ExpiredBranch{
+			{Name: "expired", Expires: now.Add(-time.Hour), Owner: "owner <owner@example.com>", Archive: "archive/expired"},
+			{Name: "expiring", Expires: now, Owner: "owner <owner@example.com>", Archive: "archive/expiring"},
+		}, expired)
+		assert.Equal(t, expired, notified)
+
+		assert.ElementsMatch(t, []string{"inuse", "later", env.DefaultInitBranch}, branchNames(t, dEnv))
+		for _, tag := range []string{"archive/expired", "archive/expiring"} {
+			ok, err := dEnv.DoltDB.HasRef(ctx, ref.NewTagRef(tag))
+			require.NoError(t, err)
+			assert.True(t, ok, tag)
+		}
+
+		expirations, err := dEnv.GetBranchExpirations()
+		require.NoError(t, err)
+		assert.NotContains(t, expirations, "expired")
+		assert.NotContains(t, expirations, "expiring")
+		assert.Contains(t, expirations, "inuse")
+	})
+
+	t.Run("a failed delete keeps the branch and drops its archive", func(t *testing.T) {
+		dEnv := newEnv(t)
+		defer dEnv.DoltDB.Close()
+
+		errDelete := errors.New("cannot delete")
+		_, err := ExpireBranches(ctx, dEnv.DbData(), now, nil, nil, func(string) error {
+			return errDelete
+		})
+		require.ErrorIs(t, err, errDelete)
+
+		assert.Contains(t, branchNames(t, dEnv), "expired")
+		ok, err := dEnv.DoltDB.HasRef(ctx, ref.NewTagRef("archive/expired"))
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
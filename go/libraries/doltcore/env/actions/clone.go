@@ -35,6 +35,7 @@ import (
 	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/datas/pull"
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -156,24 +157,32 @@ func sortedKeys(m map[string]iohelp.ReadStats) []string {
 	return keys
 }
 
-func CloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch string, dEnv *env.DoltEnv) error {
-	eventCh := make(chan pull.TableFileEvent, 128)
-
-	wg := &sync.WaitGroup{}
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		cloneProg(eventCh)
-	}()
-
-	err := Clone(ctx, srcDB, dEnv.DoltDB, eventCh)
-	close(eventCh)
-
-	wg.Wait()
+func CloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch string, depth int, tables []string, dEnv *env.DoltEnv) error {
+	var err error
+	if depth > 0 {
+		branch, err = shallowClone(ctx, srcDB, branch, depth, dEnv)
+	} else if len(tables) > 0 {
+		err = sparseClone(ctx, srcDB, tables, dEnv)
+	} else {
+		eventCh := make(chan pull.TableFileEvent, 128)
+
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cloneProg(eventCh)
+		}()
+
+		err = Clone(ctx, srcDB, dEnv.DoltDB, eventCh)
+		close(eventCh)
+
+		wg.Wait()
+	}
 
 	if err != nil {
 		if err == pull.ErrNoData {
-			err = ErrNoDataAtRemote
+			// wrapped so that callers can tell an empty remote from a failed clone
+			return fmt.Errorf("%w; %w", ErrCloneFailed, ErrNoDataAtRemote)
 		}
 		return fmt.Errorf("%w; %s", ErrCloneFailed, err.Error())
 	}
@@ -183,6 +192,20 @@ func CloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch s
 		return fmt.Errorf("%w; %s", ErrFailedToListBranches, err.Error())
 	}
 
+	if branch == "" {
+		// prefer the default branch recorded in the remote database, if there is one
+		defaultBranch, err := srcDB.GetDefaultBranch(ctx)
+		if err != nil {
+			return err
+		}
+		for _, b := range branches {
+			if b.GetPath() == defaultBranch {
+				branch = defaultBranch
+				break
+			}
+		}
+	}
+
 	if branch == "" {
 		branch = env.GetDefaultBranch(dEnv, branches)
 	}
@@ -264,6 +287,99 @@ func CloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch s
 	return nil
 }
 
+// shallowClone pulls the head of |branch| in |srcDB|, or of its default branch if |branch| is empty, along with its
+// ancestors up to |depth| commits deep, into |dEnv|, and creates the branch there. It returns the name of the branch
+// cloned, and pull.ErrNoData if |srcDB| has no branches.
+func shallowClone(ctx context.Context, srcDB *doltdb.DoltDB, branch string, depth int, dEnv *env.DoltEnv) (string, error) {
+	srcBranches, err := srcDB.GetBranchesWithHashes(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(srcBranches) == 0 {
+		return "", pull.ErrNoData
+	}
+
+	branches := make([]ref.DoltRef, len(srcBranches))
+	for i, b := range srcBranches {
+		branches[i] = b.Ref
+	}
+	if branch == "" {
+		defaultBranch, err := srcDB.GetDefaultBranch(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, b := range branches {
+			if b.GetPath() == defaultBranch {
+				branch = defaultBranch
+				break
+			}
+		}
+	}
+	if branch == "" {
+		branch = env.GetDefaultBranch(dEnv, branches)
+	}
+
+	var head hash.Hash
+	found := false
+	for _, b := range srcBranches {
+		if b.Ref.GetPath() == branch {
+			head, found = b.Hash, true
+			break
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrFailedToGetBranch, branch)
+	}
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	if err != nil {
+		return "", err
+	}
+	err = dEnv.DoltDB.ShallowPull(ctx, tmpDir, srcDB, []hash.Hash{head}, depth, nil)
+	if err != nil {
+		return "", err
+	}
+	return branch, dEnv.DoltDB.SetHead(ctx, ref.NewBranchRef(branch), head)
+}
+
+// sparseClone pulls the heads of the branches of |srcDB| into |dEnv|, with the data of only the tables in |tables|, and
+// creates the branches there. The data of the other tables is fetched from the remote the first time it's read. It
+// returns pull.ErrNoData if |srcDB| has no branches.
+func sparseClone(ctx context.Context, srcDB *doltdb.DoltDB, tables []string, dEnv *env.DoltEnv) error {
+	srcBranches, err := srcDB.GetBranchesWithHashes(ctx)
+	if err != nil {
+		return err
+	}
+	if len(srcBranches) == 0 {
+		return pull.ErrNoData
+	}
+
+	err = dEnv.SetSparseTables(tables)
+	if err != nil {
+		return err
+	}
+	tmpDir, err := dEnv.TempTableFilesDir()
+	if err != nil {
+		return err
+	}
+
+	heads := make([]hash.Hash, len(srcBranches))
+	for i, b := range srcBranches {
+		heads[i] = b.Hash
+	}
+	err = dEnv.DoltDB.SparsePull(ctx, tmpDir, srcDB, heads, tables, nil)
+	if err != nil {
+		return err
+	}
+	for _, b := range srcBranches {
+		err = dEnv.DoltDB.SetHead(ctx, b.Ref, b.Hash)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // InitEmptyClonedRepo inits an empty, newly cloned repo. This would be unnecessary if we properly initialized the
 // storage for a repository when we created it on dolthub. If we do that, this code can be removed.
 func InitEmptyClonedRepo(ctx context.Context, dEnv *env.DoltEnv) error {
@@ -32,6 +32,8 @@ type CommitStagedProps struct {
 	Force      bool
 	Name       string
 	Email      string
+	// Signer, if set, signs the commit
+	Signer datas.Signer
 }
 
 // GetCommitStaged returns a new pending commit with the roots and commit properties given.
@@ -107,5 +109,10 @@ func GetCommitStaged(
 		return nil, err
 	}
 
-	return db.NewPendingCommit(ctx, roots, mergeParents, meta)
+	pendingCommit, err := db.NewPendingCommit(ctx, roots, mergeParents, meta)
+	if err != nil {
+		return nil, err
+	}
+	pendingCommit.CommitOptions.Signer = props.Signer
+	return pendingCommit, nil
 }
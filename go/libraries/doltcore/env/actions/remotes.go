@@ -44,6 +44,7 @@ var ErrFailedToGetRemoteDb = errors.New("failed to get remote db")
 var ErrFailedToDeleteBackup = errors.New("failed to delete backup")
 var ErrFailedToGetBackupDb = errors.New("failed to get backup db")
 var ErrUnknownPushErr = errors.New("unknown push error")
+var ErrStaleLease = errors.New("the remote branch has changed since it was last fetched")
 
 type ProgStarter func(ctx context.Context) (*sync.WaitGroup, chan pull.Stats)
 type ProgStopper func(cancel context.CancelFunc, wg *sync.WaitGroup, statsCh chan pull.Stats)
@@ -52,7 +53,8 @@ type ProgStopper func(cancel context.CancelFunc, wg *sync.WaitGroup, statsCh cha
 // This is accomplished first by verifying that the remote tracking reference for the source database can be updated to
 // the given commit via a fast forward merge.  If this is the case, an attempt will be made to update the branch in the
 // destination db to the given commit via fast forward move.  If that succeeds the tracking branch is updated in the
-// source db.
+// source db. With a lease, a force update is only made if the branch in the destination db is still at the commit of the
+// remote tracking reference.
 func Push(ctx context.Context, tempTableDir string, mode ref.UpdateMode, destRef ref.BranchRef, remoteRef ref.RemoteRef, srcDB, destDB *doltdb.DoltDB, commit *doltdb.Commit, statsCh chan pull.Stats) error {
 	var err error
 	if mode == ref.FastForwardOnly {
@@ -83,6 +85,19 @@ func Push(ctx context.Context, tempTableDir string, mode ref.UpdateMode, destRef
 			return err
 		}
 		err = srcDB.SetHeadToCommit(ctx, remoteRef, commit)
+	case ref.ForceUpdateWithLease:
+		var lease hash.Hash
+		lease, err = remoteTrackingHash(ctx, srcDB, remoteRef)
+		if err != nil {
+			return err
+		}
+		err = destDB.SetHeadToCommitIfUnchanged(ctx, destRef, commit, lease)
+		if err == datas.ErrMergeNeeded {
+			return ErrStaleLease
+		} else if err != nil {
+			return err
+		}
+		err = srcDB.SetHeadToCommit(ctx, remoteRef, commit)
 	case ref.FastForwardOnly:
 		err = destDB.FastForward(ctx, destRef, commit)
 		if err != nil {
@@ -94,6 +109,18 @@ func Push(ctx context.Context, tempTableDir string, mode ref.UpdateMode, destRef
 	return err
 }
 
+// remoteTrackingHash returns the commit hash of the remote tracking reference given, or an empty hash if it doesn't
+// exist.
+func remoteTrackingHash(ctx context.Context, db *doltdb.DoltDB, remoteRef ref.RemoteRef) (hash.Hash, error) {
+	cm, err := db.ResolveCommitRef(ctx, remoteRef)
+	if err == doltdb.ErrBranchNotFound {
+		return hash.Hash{}, nil
+	} else if err != nil {
+		return hash.Hash{}, err
+	}
+	return cm.HashOf()
+}
+
 func DoPush(ctx context.Context, rsr env.RepoStateReader, rsw env.RepoStateWriter, srcDB, destDB *doltdb.DoltDB, tempTableDir string, opts *env.PushOpts, progStarter ProgStarter, progStopper ProgStopper) error {
 	var err error
 
@@ -105,7 +132,11 @@ func DoPush(ctx context.Context, rsr env.RepoStateReader, rsw env.RepoStateWrite
 			err = PushToRemoteBranch(ctx, rsr, tempTableDir, opts.Mode, opts.SrcRef, opts.DestRef, opts.RemoteRef, srcDB, destDB, opts.Remote, progStarter, progStopper)
 		}
 	case ref.TagRefType:
-		err = pushTagToRemote(ctx, tempTableDir, opts.SrcRef, opts.DestRef, srcDB, destDB, progStarter, progStopper)
+		if opts.SrcRef == ref.EmptyTagRef {
+			err = deleteRemoteTag(ctx, opts.DestRef, destDB, opts.Remote)
+		} else {
+			err = pushTagToRemote(ctx, tempTableDir, opts.SrcRef, opts.DestRef, srcDB, destDB, progStarter, progStopper)
+		}
 	default:
 		err = fmt.Errorf("%w: %s of type %s", ErrCannotPushRef, opts.SrcRef.String(), opts.SrcRef.GetType())
 	}
@@ -146,7 +177,34 @@ func PushTag(ctx context.Context, tempTableDir string, destRef ref.TagRef, srcDB
 }
 
 func deleteRemoteBranch(ctx context.Context, toDelete, remoteRef ref.DoltRef, localDB, remoteDB *doltdb.DoltDB, remote env.Remote) error {
-	err := DeleteRemoteBranch(ctx, toDelete.(ref.BranchRef), remoteRef.(ref.RemoteRef), localDB, remoteDB)
+	// A tag that has already been deleted locally can't be disambiguated from a branch, so if the remote has no branch
+	// with this name, but does have a tag, delete the tag instead.
+	hasBranch, err := remoteDB.HasRef(ctx, toDelete)
+	if err != nil {
+		return fmt.Errorf("%w; '%s' from remote '%s'; %s", ErrFailedToDeleteRemote, toDelete.String(), remote.Name, err)
+	}
+	if !hasBranch {
+		tagRef := ref.NewTagRef(toDelete.GetPath())
+		hasTag, err := remoteDB.HasRef(ctx, tagRef)
+		if err != nil {
+			return fmt.Errorf("%w; '%s' from remote '%s'; %s", ErrFailedToDeleteRemote, toDelete.String(), remote.Name, err)
+		}
+		if hasTag {
+			return deleteRemoteTag(ctx, tagRef, remoteDB, remote)
+		}
+	}
+
+	err = DeleteRemoteBranch(ctx, toDelete.(ref.BranchRef), remoteRef.(ref.RemoteRef), localDB, remoteDB)
+
+	if err != nil {
+		return fmt.Errorf("%w; '%s' from remote '%s'; %s", ErrFailedToDeleteRemote, toDelete.String(), remote.Name, err)
+	}
+
+	return nil
+}
+
+func deleteRemoteTag(ctx context.Context, toDelete ref.DoltRef, remoteDB *doltdb.DoltDB, remote env.Remote) error {
+	err := remoteDB.DeleteTag(ctx, toDelete)
 
 	if err != nil {
 		return fmt.Errorf("%w; '%s' from remote '%s'; %s", ErrFailedToDeleteRemote, toDelete.String(), remote.Name, err)
@@ -183,17 +241,40 @@ func PushToRemoteBranch(ctx context.Context, rsr env.RepoStateReader, tempTableD
 	err = Push(ctx, tempTableDir, mode, destRef.(ref.BranchRef), remoteRef.(ref.RemoteRef), localDB, remoteDB, cm, statsCh)
 	progStopper(cancelFunc, wg, statsCh)
 
+	if err == nil || err == doltdb.ErrUpToDate {
+		// branch metadata can change without the branch moving, so it's synced even when there are no new commits
+		mdErr := pushBranchMetadata(ctx, srcRef, destRef, remoteRef, localDB, remoteDB)
+		if mdErr != nil {
+			return fmt.Errorf("%w; %s", ErrUnknownPushErr, mdErr.Error())
+		}
+	}
+
 	switch err {
 	case nil:
 		cli.Println()
 		return nil
-	case doltdb.ErrUpToDate, doltdb.ErrIsAhead, ErrCantFF, datas.ErrMergeNeeded:
+	case doltdb.ErrUpToDate, doltdb.ErrIsAhead, ErrCantFF, datas.ErrMergeNeeded, ErrStaleLease:
 		return err
 	default:
 		return fmt.Errorf("%w; %s", ErrUnknownPushErr, err.Error())
 	}
 }
 
+// pushBranchMetadata copies the metadata of the local branch |srcRef| to the branch |destRef| it was pushed to, and to
+// the remote tracking branch |remoteRef| that mirrors it.
+func pushBranchMetadata(ctx context.Context, srcRef, destRef, remoteRef ref.DoltRef, localDB, remoteDB *doltdb.DoltDB) error {
+	err := doltdb.CopyBranchMetadata(ctx, localDB, srcRef, remoteDB, destRef, nil)
+	if err != nil {
+		return err
+	}
+
+	hasRef, err := localDB.HasRef(ctx, remoteRef)
+	if err != nil || !hasRef {
+		return err
+	}
+	return doltdb.CopyBranchMetadata(ctx, localDB, srcRef, localDB, remoteRef, nil)
+}
+
 func pushTagToRemote(ctx context.Context, tempTableDir string, srcRef, destRef ref.DoltRef, localDB, remoteDB *doltdb.DoltDB, progStarter ProgStarter, progStopper ProgStopper) error {
 	tg, err := localDB.ResolveTag(ctx, srcRef.(ref.TagRef))
 
@@ -413,6 +494,8 @@ func FetchRefSpecs(
 
 	var toFetch []hash.Hash
 	var newHeads []doltdb.RefWithHash
+	// the remote branch that each of newHeads is fetched from
+	var fetchedRefs []ref.DoltRef
 
 	for _, rs := range refSpecs {
 		rsSeen := false
@@ -425,6 +508,7 @@ func FetchRefSpecs(
 
 				toFetch = append(toFetch, branchRef.Hash)
 				newHeads = append(newHeads, doltdb.RefWithHash{Ref: remoteTrackRef, Hash: branchRef.Hash})
+				fetchedRefs = append(fetchedRefs, branchRef.Ref)
 			}
 		}
 		if !rsSeen {
@@ -460,7 +544,7 @@ func FetchRefSpecs(
 		return err
 	}
 
-	for _, newHead := range newHeads {
+	for i, newHead := range newHeads {
 		commit, err := dbData.Ddb.ReadCommit(ctx, newHead.Hash)
 		if err != nil {
 			return err
@@ -494,6 +578,11 @@ func FetchRefSpecs(
 				return fmt.Errorf("%w: %s", ErrCantFF, err.Error())
 			}
 		}
+
+		err = doltdb.CopyBranchMetadata(ctx, srcDB, fetchedRefs[i], dbData.Ddb, remoteTrackRef, nil)
+		if err != nil {
+			return err
+		}
 	}
 
 	if mode.Prune {
@@ -511,6 +600,63 @@ func FetchRefSpecs(
 	return nil
 }
 
+// DeepenShallowClone fetches up to |depth| more commits of history from |srcDB| into the shallow clone of |dbData|,
+// starting from the parents of its oldest commits. It returns doltdb.ErrNotShallow if the database isn't a shallow
+// clone.
+func DeepenShallowClone(
+	ctx context.Context,
+	dbData env.DbData,
+	srcDB *doltdb.DoltDB,
+	depth int,
+	progStarter ProgStarter,
+	progStopper ProgStopper,
+) error {
+	tmpDir, err := dbData.Rsw.TempTableFilesDir()
+	if err != nil {
+		return err
+	}
+
+	newCtx := ctx
+	var statsCh chan pull.Stats
+	if progStarter != nil && progStopper != nil {
+		var cancelFunc func()
+		newCtx, cancelFunc = context.WithCancel(ctx)
+		var wg *sync.WaitGroup
+		wg, statsCh = progStarter(newCtx)
+		defer progStopper(cancelFunc, wg, statsCh)
+	}
+
+	err = dbData.Ddb.Deepen(ctx, tmpDir, srcDB, depth, statsCh)
+	if err == pull.ErrDBUpToDate {
+		err = nil
+	}
+	return err
+}
+
+// PruneRemoteBranches deletes the remote tracking branches of |remote| for the branches that no longer exist on it,
+// given by |srcDB|, without fetching the branches that still do.
+func PruneRemoteBranches(ctx context.Context, dbData env.DbData, srcDB *doltdb.DoltDB, remote env.Remote) error {
+	refSpecs, err := env.GetRefSpecs(dbData.Rsr, remote.Name)
+	if err != nil {
+		return err
+	}
+
+	var remoteRefs []doltdb.RefWithHash
+	err = srcDB.VisitRefsOfType(ctx, ref.HeadRefTypes, func(r ref.DoltRef, addr hash.Hash) error {
+		for _, rs := range refSpecs {
+			if remoteTrackRef := rs.DestRef(r); remoteTrackRef != nil {
+				remoteRefs = append(remoteRefs, doltdb.RefWithHash{Ref: remoteTrackRef, Hash: addr})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", env.ErrFailedToReadDb, err.Error())
+	}
+
+	return pruneBranches(ctx, dbData, remote, remoteRefs)
+}
+
 func pruneBranches(ctx context.Context, dbData env.DbData, remote env.Remote, remoteRefs []doltdb.RefWithHash) error {
 	remoteRefTypes := map[ref.RefType]struct{}{
 		ref.RemoteRefType: {},
@@ -29,6 +29,8 @@ type TagProps struct {
 	TaggerName  string
 	TaggerEmail string
 	Description string
+	// Signer, if set, signs the tag
+	Signer datas.Signer
 }
 
 func CreateTag(ctx context.Context, dEnv *env.DoltEnv, tagName, startPoint string, props TagProps) error {
@@ -70,7 +72,7 @@ func CreateTagOnDB(ctx context.Context, ddb *doltdb.DoltDB, tagName, startPoint
 
 	meta := datas.NewTagMeta(props.TaggerName, props.TaggerEmail, props.Description)
 
-	return ddb.NewTagAtCommit(ctx, tagRef, cm, meta)
+	return ddb.NewSignedTagAtCommit(ctx, tagRef, cm, meta, props.Signer)
 }
 
 func DeleteTags(ctx context.Context, dEnv *env.DoltEnv, tagNames ...string) error {
@@ -23,6 +23,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/libraries/utils/signing"
 	"github.com/dolthub/dolt/go/store/datas"
 )
 
@@ -30,8 +31,9 @@ const (
 	localConfigName  = "local"
 	globalConfigName = "global"
 
-	UserEmailKey = "user.email"
-	UserNameKey  = "user.name"
+	UserEmailKey   = "user.email"
+	UserNameKey    = "user.name"
+	UserSigningKey = "user.signingkey"
 
 	// should be able to have remote specific creds?
 	UserCreds = "user.creds"
@@ -51,7 +53,23 @@ const (
 	MetricsPort     = "metrics.port"
 	MetricsInsecure = "metrics.insecure"
 
+	MetricsEmitter       = "metrics.emitter"
+	MetricsStatsdAddress = "metrics.statsd_address"
+	MetricsOtlpEndpoint  = "metrics.otlp_endpoint"
+
 	PushAutoSetupRemote = "push.autosetupremote"
+
+	GPGFormat                = "gpg.format"
+	GPGProgram               = "gpg.program"
+	GPGSSHAllowedSignersFile = "gpg.ssh.allowedsignersfile"
+
+	AssistUrl     = "assist.url"
+	AssistModel   = "assist.model"
+	AssistApiKey  = "assist.api_key"
+	AssistOffline = "assist.offline"
+
+	// DiffDriverPrefix prefixes the config keys that set the external diff driver of a column type, e.g. diff.driver.blob
+	DiffDriverPrefix = "diff.driver."
 )
 
 var LocalConfigWhitelist = set.NewStrSet([]string{UserNameKey, UserEmailKey})
@@ -225,6 +243,16 @@ func GetNameAndEmail(cfg config.ReadableConfig) (string, string, error) {
 	return name, email, nil
 }
 
+// GetSigningConfig returns the configuration of signing and verifying commits and tags from the supplied config
+func GetSigningConfig(cfg config.ReadableConfig) signing.Config {
+	return signing.Config{
+		Format:             GetStringOrDefault(cfg, GPGFormat, ""),
+		Program:            GetStringOrDefault(cfg, GPGProgram, ""),
+		Key:                GetStringOrDefault(cfg, UserSigningKey, ""),
+		AllowedSignersFile: GetStringOrDefault(cfg, GPGSSHAllowedSignersFile, ""),
+	}
+}
+
 // writeableLocalDoltCliConfig is an extension to DoltCliConfig that reads values from the hierarchy but writes to
 // local config.
 type writeableLocalDoltCliConfig struct {
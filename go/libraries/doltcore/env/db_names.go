@@ -0,0 +1,222 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// DBNamesFile is the file at the root of a data directory that records the directories of the databases whose names
+// can't be used as their directory's name.
+const DBNamesFile = ".dolt_db_names.json"
+
+// DBNames maps the names of databases in a data directory to the directories they're stored in, for databases whose
+// names can't be used as directory names, or wouldn't be loaded with the same name from one. It's persisted in
+// DBNamesFile at the root of the data directory. Databases that aren't in it are stored in a directory of the same
+// name. Names are matched case-insensitively, as are directories, so that a data directory can be moved to a
+// case-insensitive filesystem. A nil *DBNames records nothing.
+type DBNames struct {
+	mu sync.Mutex
+	fs filesys.Filesys
+	// dirs maps the names of databases to the directories they're stored in
+	dirs map[string]string
+}
+
+type dbNamesFile struct {
+	Databases map[string]string `json:"databases"`
+}
+
+// LoadDBNames loads the database names recorded in the data directory |fs|. A data directory without any has an
+// empty mapping.
+func LoadDBNames(fs filesys.Filesys) (*DBNames, error) {
+	names := &DBNames{fs: fs, dirs: make(map[string]string)}
+	if exists, _ := fs.Exists(DBNamesFile); !exists {
+		return names, nil
+	}
+
+	data, err := fs.ReadFile(DBNamesFile)
+	if err != nil {
+		return nil, err
+	}
+	var file dbNamesFile
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, err
+	}
+	for name, dir := range file.Databases {
+		names.dirs[name] = dir
+	}
+	return names, nil
+}
+
+// NameForDir returns the name of the database stored in the directory |dir|, if it's recorded.
+func (n *DBNames) NameForDir(dir string) (string, bool) {
+	if n == nil {
+		return "", false
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for name, d := range n.dirs {
+		if strings.EqualFold(d, dir) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// DirForName returns the directory that the database named is stored in. Databases that aren't recorded are stored in
+// a directory of the same name.
+func (n *DBNames) DirForName(name string) string {
+	if n == nil {
+		return name
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if dir, ok := n.dirForName(name); ok {
+		return dir
+	}
+	return name
+}
+
+func (n *DBNames) dirForName(name string) (string, bool) {
+	for recorded, dir := range n.dirs {
+		if strings.EqualFold(recorded, name) {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+// DirForNewDatabase returns the directory to create the database named in, and whether it has to be recorded with
+// Add once the database is created. A name that can be used as a directory name is used as one. Otherwise, the
+// directory is named for the database with the characters that can't be used replaced, and made unique among the
+// directories in the data directory, ignoring case.
+func (n *DBNames) DirForNewDatabase(name string) (dir string, record bool) {
+	if n == nil {
+		return name, false
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if IsValidDBDirName(name) {
+		return name, false
+	}
+
+	base := dbNameToDir(name)
+	dir = base
+	for i := 2; n.dirInUse(dir); i++ {
+		dir = base + "_" + strconv.Itoa(i)
+	}
+	return dir, true
+}
+
+// dirInUse returns whether |dir| exists in the data directory, or is recorded for a database, ignoring case.
+func (n *DBNames) dirInUse(dir string) bool {
+	for _, d := range n.dirs {
+		if strings.EqualFold(d, dir) {
+			return true
+		}
+	}
+	inUse := false
+	_ = n.fs.Iter(".", false, func(path string, size int64, isDir bool) (stop bool) {
+		if strings.EqualFold(filepath.Base(path), dir) {
+			inUse = true
+		}
+		return inUse
+	})
+	return inUse
+}
+
+// Add records that the database named is stored in |dir|.
+func (n *DBNames) Add(name, dir string) error {
+	if n == nil {
+		return nil
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.dirs[name] = dir
+	return n.save()
+}
+
+// Remove removes the record of the database named, if there is one.
+func (n *DBNames) Remove(name string) error {
+	if n == nil {
+		return nil
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	removed := false
+	for recorded := range n.dirs {
+		if strings.EqualFold(recorded, name) {
+			delete(n.dirs, recorded)
+			removed = true
+		}
+	}
+	if !removed {
+		return nil
+	}
+	return n.save()
+}
+
+func (n *DBNames) save() error {
+	if len(n.dirs) == 0 {
+		if exists, _ := n.fs.Exists(DBNamesFile); exists {
+			return n.fs.DeleteFile(DBNamesFile)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(dbNamesFile{Databases: n.dirs}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return n.fs.WriteFile(DBNamesFile, data)
+}
+
+// IsValidDBDirName returns whether the database named can be stored in a directory of the same name, and be loaded
+// with that name from it.
+func IsValidDBDirName(name string) bool {
+	if name == "" || strings.HasPrefix(name, ".") || dirToDBName(name) != name {
+		return false
+	}
+	return strings.IndexFunc(name, invalidDirRune) < 0
+}
+
+// dbNameToDir returns the name of a directory for the database named, with the characters that can't be used in a
+// directory name replaced.
+func dbNameToDir(name string) string {
+	dir := strings.Map(func(r rune) rune {
+		if invalidDirRune(r) {
+			return '_'
+		}
+		return r
+	}, name)
+	dir = dirToDBName(strings.TrimLeft(dir, "."))
+	if dir == "" || dir == "_" {
+		dir = "db"
+	}
+	return dir
+}
+
+// invalidDirRune returns whether |r| can't be used in a directory name on any of the platforms Dolt supports.
+func invalidDirRune(r rune) bool {
+	return strings.ContainsRune(`/\:*?"<>|`, r) || unicode.IsControl(r)
+}
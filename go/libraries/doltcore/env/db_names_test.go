@@ -0,0 +1,76 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func TestDBNames(t *testing.T) {
+	fs := filesys.EmptyInMemFS("/data")
+	require.NoError(t, fs.MkDirs("my_db"))
+
+	names, err := LoadDBNames(fs)
+	require.NoError(t, err)
+
+	// names that can be directory names are used as them, and aren't recorded
+	dir, record := names.DirForNewDatabase("mydb")
+	assert.Equal(t, "mydb", dir)
+	assert.False(t, record)
+
+	// others are stored in a directory that isn't in use, ignoring case
+	dir, record = names.DirForNewDatabase("my-db")
+	assert.Equal(t, "my_db_2", dir)
+	assert.True(t, record)
+	require.NoError(t, names.Add("my-db", dir))
+	dir, _ = names.DirForNewDatabase("MY-DB")
+	assert.Equal(t, "MY_DB_3", dir)
+
+	// the records are persisted
+	names, err = LoadDBNames(fs)
+	require.NoError(t, err)
+	name, ok := names.NameForDir("MY_DB_2")
+	assert.True(t, ok)
+	assert.Equal(t, "my-db", name)
+	assert.Equal(t, "my_db_2", names.DirForName("My-Db"))
+	assert.Equal(t, "other", names.DirForName("other"))
+
+	// and the file is removed along with the last of them
+	require.NoError(t, names.Remove("MY-DB"))
+	exists, _ := fs.Exists(DBNamesFile)
+	assert.False(t, exists)
+	_, ok = names.NameForDir("my_db_2")
+	assert.False(t, ok)
+}
+
+func TestIsValidDBDirName(t *testing.T) {
+	tests := map[string]bool{
+		"irs":     true,
+		"my_db":   true,
+		"my-db":   false,
+		"my db":   false,
+		".hidden": false,
+		"a/b":     false,
+		"":        false,
+	}
+	for name, expected := range tests {
+		assert.Equal(t, expected, IsValidDBDirName(name), name)
+	}
+}
@@ -80,6 +80,9 @@ var ErrRemoteAddressConflict = errors.New("address conflict with a remote")
 var ErrDoltRepositoryNotFound = errors.New("can no longer find .dolt dir on disk")
 var ErrFailedToAccessDB = goerrors.NewKind("failed to access '%s' database: can no longer find .dolt dir on disk")
 
+// ErrLockLeaseLost is returned when a lease lock is renewed after another process took it over.
+var ErrLockLeaseLost = goerrors.NewKind("the lease on '%s' was lost to another sql-server")
+
 // DoltEnv holds the state of the current environment used by the cli.
 type DoltEnv struct {
 	Version string
@@ -158,12 +161,31 @@ func LoadWithoutDB(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys,
 // Load loads the DoltEnv for the .dolt directory determined by resolving the specified urlStr with the specified Filesys.
 func Load(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys, urlStr string, version string) *DoltEnv {
 	dEnv := LoadWithoutDB(ctx, hdp, fs, version)
+	dEnv.urlStr = urlStr
+	_ = dEnv.LoadDB(ctx)
+	return dEnv
+}
+
+// loadWithoutDBFromURL loads the DoltEnv for the .dolt directory determined by resolving |urlStr| with |fs|, but
+// doesn't open its DoltDB until LoadDB is called.
+func loadWithoutDBFromURL(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys, urlStr string, version string) *DoltEnv {
+	dEnv := LoadWithoutDB(ctx, hdp, fs, version)
+	dEnv.urlStr = urlStr
+	return dEnv
+}
 
-	ddb, dbLoadErr := doltdb.LoadDoltDB(ctx, types.Format_Default, urlStr, fs)
+// DBLoaded returns whether this environment's DoltDB has been opened.
+func (dEnv *DoltEnv) DBLoaded() bool {
+	return dEnv.DoltDB != nil
+}
+
+// LoadDB opens the DoltDB of an environment that was loaded without it, and returns DBLoadError.
+func (dEnv *DoltEnv) LoadDB(ctx context.Context) error {
+	fs := dEnv.FS
+	ddb, dbLoadErr := doltdb.LoadDoltDB(ctx, types.Format_Default, dEnv.urlStr, fs)
 
 	dEnv.DoltDB = ddb
 	dEnv.DBLoadError = dbLoadErr
-	dEnv.urlStr = urlStr
 
 	if dbLoadErr == nil && dEnv.HasDoltDir() {
 		if !dEnv.HasDoltTempTableDir() {
@@ -197,6 +219,10 @@ func Load(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys, urlStr s
 		}
 	}
 
+	if dbLoadErr == nil && dEnv.DBLoadError == nil && dEnv.HasDoltDir() {
+		dEnv.DBLoadError = dEnv.loadSparseSpec()
+	}
+
 	if dEnv.RSLoadErr == nil && dbLoadErr == nil {
 		// If the working set isn't present in the DB, create it from the repo state. This step can be removed post 1.0.
 		_, err := dEnv.WorkingSet(ctx)
@@ -207,7 +233,7 @@ func Load(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys, urlStr s
 		}
 	}
 
-	return dEnv
+	return dEnv.DBLoadError
 }
 
 func GetDefaultInitBranch(cfg config.ReadableConfig) string {
@@ -412,6 +438,17 @@ func (dEnv *DoltEnv) InitRepoWithNoData(ctx context.Context, nbf *types.NomsBinF
 	return err
 }
 
+// ReloadDoltDB loads this environment's DoltDB again, after its storage has been rewritten on disk. The DoltDB it
+// replaces must already be closed and removed from the singleton cache.
+func (dEnv *DoltEnv) ReloadDoltDB(ctx context.Context) error {
+	ddb, err := doltdb.LoadDoltDB(ctx, types.Format_Default, dEnv.urlStr, dEnv.FS)
+	if err != nil {
+		return err
+	}
+	dEnv.DoltDB = ddb
+	return nil
+}
+
 func (dEnv *DoltEnv) createDirectories(dir string) (string, error) {
 	absPath, err := dEnv.FS.Abs(dir)
 
@@ -1008,6 +1045,45 @@ func (dEnv *DoltEnv) UpdateBranch(name string, new BranchConfig) error {
 	return nil
 }
 
+func (dEnv *DoltEnv) RemoveBranch(name string) error {
+	if dEnv.RSLoadErr != nil {
+		return dEnv.RSLoadErr
+	}
+
+	if _, ok := dEnv.RepoState.Branches[name]; !ok {
+		return nil
+	}
+	delete(dEnv.RepoState.Branches, name)
+
+	err := dEnv.RepoState.Save(dEnv.FS)
+	if err != nil {
+		return ErrFailedToWriteRepoState
+	}
+	return nil
+}
+
+func (dEnv *DoltEnv) GetBranchExpirations() (map[string]BranchExpiration, error) {
+	if dEnv.RSLoadErr != nil {
+		return nil, dEnv.RSLoadErr
+	}
+
+	return dEnv.RepoState.GetBranchExpirations(), nil
+}
+
+func (dEnv *DoltEnv) SetBranchExpiration(name string, expiration BranchExpiration) error {
+	if dEnv.RSLoadErr != nil {
+		return dEnv.RSLoadErr
+	}
+
+	dEnv.RepoState.SetBranchExpiration(name, expiration)
+
+	err := dEnv.RepoState.Save(dEnv.FS)
+	if err != nil {
+		return ErrFailedToWriteRepoState
+	}
+	return nil
+}
+
 var ErrNotACred = errors.New("not a valid credential key id or public key")
 
 func (dEnv *DoltEnv) FindCreds(credsDir, pubKeyOrId string) (string, error) {
@@ -1204,6 +1280,11 @@ type DBLock struct {
 	Pid    int
 	Port   int
 	Secret string
+	// Host and LeaseExpires are set for lease locks, which are held by the server on Host until LeaseExpires unless
+	// it renews them. Unlike the pid in the lock, they can be checked by processes on other hosts, which see the
+	// same lock file when the database is on a network filesystem.
+	Host         string
+	LeaseExpires time.Time
 }
 
 // DBLock constructor
@@ -1211,7 +1292,35 @@ func NewDBLock(port int) DBLock {
 	return DBLock{Pid: os.Getpid(), Port: port, Secret: uuid.New().String()}
 }
 
-// Lock writes this database's lockfile with the pid of the calling process or errors if it already exists
+// NewDBLeaseLock returns a lease lock for a server on |port|, which expires after |lease| unless it's renewed.
+func NewDBLeaseLock(port int, lease time.Duration) DBLock {
+	lock := NewDBLock(port)
+	lock.Host = hostname()
+	lock.LeaseExpires = time.Now().Add(lease)
+	return lock
+}
+
+// IsLease returns whether this is a lease lock.
+func (lock *DBLock) IsLease() bool {
+	return !lock.LeaseExpires.IsZero()
+}
+
+// IsRemote returns whether this is a lease lock taken by a server on another host.
+func (lock *DBLock) IsRemote() bool {
+	return lock.IsLease() && lock.Host != hostname()
+}
+
+func hostname() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "localhost"
+	}
+	return host
+}
+
+// Lock writes this database's lockfile with the pid of the calling process or errors if it already exists. The lock
+// file is created atomically, and read back before Lock returns, so that of two servers racing to lock the database
+// only one succeeds.
 func (dEnv *DoltEnv) Lock(lock *DBLock) error {
 	if dEnv.IgnoreLockFile {
 		return nil
@@ -1221,7 +1330,26 @@ func (dEnv *DoltEnv) Lock(lock *DBLock) error {
 		return ErrActiveServerLock.New(dEnv.LockFile())
 	}
 
-	return WriteLockfile(dEnv.FS, lock)
+	lockFile := dEnv.LockFile()
+	err := dEnv.FS.MkDirs(filepath.Dir(lockFile))
+	if err != nil {
+		return err
+	}
+
+	// A lock file that's left behind is stale, since the database isn't locked. It's only replaced if it still holds
+	// the stale lock, so that a server that took it over since it was read keeps it.
+	if stale, loadErr := LoadDBLockFile(dEnv.FS, lockFile); loadErr == nil {
+		err = replaceLockfile(dEnv.FS, lockFile, stale.Secret, lock)
+	} else {
+		err = createLockfile(dEnv.FS, lockFile, lock)
+	}
+	if err == nil {
+		err = verifyLockfile(dEnv.FS, lockFile, lock)
+	}
+	if errors.Is(err, errLockfileTaken) {
+		return ErrActiveServerLock.New(lockFile)
+	}
+	return err
 }
 
 func LoadDBLockFile(fs filesys.Filesys, lockFilePath string) (lock *DBLock, err error) {
@@ -1229,6 +1357,7 @@ func LoadDBLockFile(fs filesys.Filesys, lockFilePath string) (lock *DBLock, err
 	if err != nil {
 		return nil, err
 	}
+	defer rd.Close()
 
 	b := make([]byte, 256)
 	n, err := rd.Read(b)
@@ -1247,7 +1376,7 @@ func LoadDBLockFile(fs filesys.Filesys, lockFilePath string) (lock *DBLock, err
 		}
 		return &DBLock{Pid: pid, Port: -1, Secret: ""}, nil
 	}
-	if len(parts) != 3 {
+	if len(parts) != 3 && len(parts) != 5 {
 		return nil, fmt.Errorf("invalid lock file format")
 	}
 
@@ -1263,7 +1392,37 @@ func LoadDBLockFile(fs filesys.Filesys, lockFilePath string) (lock *DBLock, err
 		}
 	}
 	secret := parts[2]
-	return &DBLock{Pid: pid, Port: port, Secret: secret}, nil
+	lock = &DBLock{Pid: pid, Port: port, Secret: secret}
+	if len(parts) == 5 {
+		expires, err := strconv.ParseInt(parts[4], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		lock.Host = parts[3]
+		lock.LeaseExpires = time.UnixMilli(expires)
+	}
+	return lock, nil
+}
+
+// RenewLockLease extends the lease of |lock|, which must be this database's lock, by |lease|.
+func (dEnv *DoltEnv) RenewLockLease(lock *DBLock, lease time.Duration) error {
+	if dEnv.IgnoreLockFile {
+		return nil
+	}
+
+	// The lock file is replaced only if it still holds |lock|, so that renewing a lease that another server took over
+	// after it expired doesn't overwrite the other server's lock.
+	lockFile := dEnv.LockFile()
+	renewed := *lock
+	renewed.LeaseExpires = time.Now().Add(lease)
+	err := replaceLockfile(dEnv.FS, lockFile, lock.Secret, &renewed)
+	if err == nil {
+		err = verifyLockfile(dEnv.FS, lockFile, &renewed)
+	}
+	if errors.Is(err, errLockfileTaken) {
+		return ErrLockLeaseLost.New(lockFile)
+	}
+	return err
 }
 
 // Unlock deletes this database's lockfile
@@ -1286,11 +1445,6 @@ func WriteLockfile(fs filesys.Filesys, lock *DBLock) error {
 
 	lockFile, _ := fs.Abs(filepath.Join(dbfactory.DoltDir, ServerLockFile))
 
-	portStr := strconv.Itoa(lock.Port)
-	if lock.Port < 0 {
-		portStr = "-"
-	}
-
 	if reflect.TypeOf(fs) == reflect.TypeOf(filesys.LocalFS) {
 		_, err := os.Create(lockFile)
 		if err != nil {
@@ -1302,11 +1456,109 @@ func WriteLockfile(fs filesys.Filesys, lock *DBLock) error {
 		}
 	}
 
-	err = fs.WriteFile(lockFile, []byte(fmt.Sprintf("%d:%s:%s", lock.Pid, portStr, lock.Secret)))
+	err = fs.WriteFile(lockFile, lockfileContents(lock))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// errLockfileTaken is returned when a lock file is held by another process.
+var errLockfileTaken = errors.New("lock file is held by another process")
+
+func lockfileContents(lock *DBLock) []byte {
+	portStr := strconv.Itoa(lock.Port)
+	if lock.Port < 0 {
+		portStr = "-"
+	}
+	contents := fmt.Sprintf("%d:%s:%s", lock.Pid, portStr, lock.Secret)
+	if lock.IsLease() {
+		contents += fmt.Sprintf(":%s:%d", lock.Host, lock.LeaseExpires.UnixMilli())
+	}
+	return []byte(contents)
+}
+
+// createLockfile creates the lock file |lockFile| holding |lock|, or returns errLockfileTaken if it already exists. On
+// the local filesystem, the lock is written to a file of its own that's then hard linked to |lockFile|. Unlike
+// O_EXCL, linking fails when the file exists on network filesystems too.
+func createLockfile(fs filesys.Filesys, lockFile string, lock *DBLock) error {
+	if reflect.TypeOf(fs) != reflect.TypeOf(filesys.LocalFS) {
+		// other filesystems are in memory, and only seen by this process
+		if ok, _ := fs.Exists(lockFile); ok {
+			return errLockfileTaken
+		}
+		return fs.WriteFile(lockFile, lockfileContents(lock))
+	}
+
+	tmpFile := lockFile + "." + lock.Secret
+	err := os.WriteFile(tmpFile, lockfileContents(lock), 0600)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile)
+
+	err = os.Link(tmpFile, lockFile)
+	if os.IsExist(err) {
+		return errLockfileTaken
+	}
+	return err
+}
+
+// replaceLockfile replaces the lock file |lockFile|, which must hold the lock whose secret is |secret|, with one holding
+// |lock|. The lock file is moved aside before it's checked, so that another process can't replace it between the check
+// and the replacement. If it holds another lock, it's moved back and errLockfileTaken is returned.
+func replaceLockfile(fs filesys.Filesys, lockFile, secret string, lock *DBLock) error {
+	movedFile := lockFile + "." + lock.Secret + ".old"
+	err := fs.MoveFile(lockFile, movedFile)
 	if err != nil {
+		if ok, _ := fs.Exists(lockFile); !ok {
+			// another process removed the lock file, or is replacing it
+			return errLockfileTaken
+		}
 		return err
 	}
 
+	moved, err := LoadDBLockFile(fs, movedFile)
+	if err != nil || moved.Secret != secret {
+		// If another process locks the database before the lock file is moved back, the lock moved aside is lost.
+		// Its owner finds out when it next reads the lock file.
+		_ = restoreLockfile(fs, movedFile, lockFile)
+		return errLockfileTaken
+	}
+
+	err = fs.DeleteFile(movedFile)
+	if err != nil {
+		return err
+	}
+	return createLockfile(fs, lockFile, lock)
+}
+
+// restoreLockfile moves |movedFile| back to |lockFile|, unless |lockFile| has been created since it was moved aside.
+func restoreLockfile(fs filesys.Filesys, movedFile, lockFile string) error {
+	if reflect.TypeOf(fs) != reflect.TypeOf(filesys.LocalFS) {
+		if ok, _ := fs.Exists(lockFile); ok {
+			return fs.DeleteFile(movedFile)
+		}
+		return fs.MoveFile(movedFile, lockFile)
+	}
+	defer os.Remove(movedFile)
+	return os.Link(movedFile, lockFile)
+}
+
+// verifyLockfile reads the lock file |lockFile| back, and returns errLockfileTaken if it no longer holds the secret of
+// |lock|.
+func verifyLockfile(fs filesys.Filesys, lockFile string, lock *DBLock) error {
+	current, err := LoadDBLockFile(fs, lockFile)
+	if err != nil {
+		if ok, _ := fs.Exists(lockFile); !ok {
+			return errLockfileTaken
+		}
+		return err
+	}
+	if current.Secret != lock.Secret {
+		return errLockfileTaken
+	}
 	return nil
 }
 
@@ -1327,6 +1579,14 @@ func fsIsLocked(fs filesys.Filesys) (locked bool, lock *DBLock, err error) {
 		return true, nil, err
 	}
 
+	// The pid of a lease lock taken on another host means nothing here, so the lock is held until its lease expires.
+	if loadedLock.IsRemote() {
+		if time.Now().Before(loadedLock.LeaseExpires) {
+			return true, loadedLock, nil
+		}
+		return false, nil, nil
+	}
+
 	// If the PID is for this process, then ignore the lock file. This happens frequently with docker containers
 	// https://github.com/dolthub/dolt/issues/6183.
 	if os.Getpid() == loadedLock.Pid {
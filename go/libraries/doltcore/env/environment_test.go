@@ -17,9 +17,11 @@ package env
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -255,3 +257,69 @@ func TestBestEffortDelete(t *testing.T) {
 		t.Error("Dir should be empty after delete.")
 	}
 }
+
+func TestLeaseLock(t *testing.T) {
+	dEnv, fs := createTestEnv(true, true)
+
+	// a lease taken on another host holds the lock until it expires, whatever its pid
+	other := DBLock{Pid: os.Getpid() + 1, Port: 3306, Secret: "secret", Host: "not-" + hostname(), LeaseExpires: time.Now().Add(time.Minute)}
+	require.NoError(t, WriteLockfile(fs, &other))
+	locked, lock, err := dEnv.GetLock()
+	require.NoError(t, err)
+	require.True(t, locked)
+	assert.Equal(t, other.Host, lock.Host)
+	assert.Equal(t, other.LeaseExpires.UnixMilli(), lock.LeaseExpires.UnixMilli())
+
+	other.LeaseExpires = time.Now().Add(-time.Second)
+	require.NoError(t, WriteLockfile(fs, &other))
+	assert.False(t, dEnv.IsLocked())
+
+	// renewing a lease that was taken over fails
+	ours := NewDBLeaseLock(3306, time.Minute)
+	assert.True(t, ErrLockLeaseLost.Is(dEnv.RenewLockLease(&ours, time.Minute)))
+
+	require.NoError(t, WriteLockfile(fs, &ours))
+	require.NoError(t, dEnv.RenewLockLease(&ours, time.Hour))
+	lock, err = LoadDBLockFile(fs, dEnv.LockFile())
+	require.NoError(t, err)
+	assert.Equal(t, ours.Secret, lock.Secret)
+	assert.True(t, lock.LeaseExpires.After(ours.LeaseExpires))
+}
+
+func TestLockfileRace(t *testing.T) {
+	fs, err := filesys.LocalFS.WithWorkingDir(t.TempDir())
+	require.NoError(t, err)
+	dEnv := &DoltEnv{FS: fs}
+	lockFile := dEnv.LockFile()
+
+	// the lock file can only be created once, even if the other server checked for it first
+	ours := NewDBLeaseLock(3306, time.Minute)
+	theirs := NewDBLeaseLock(3307, time.Minute)
+	require.NoError(t, dEnv.Lock(&ours))
+	assert.True(t, errors.Is(createLockfile(fs, lockFile, &theirs), errLockfileTaken))
+	lock, err := LoadDBLockFile(fs, lockFile)
+	require.NoError(t, err)
+	assert.Equal(t, ours.Secret, lock.Secret)
+
+	// a stale lock is only replaced if it's the lock that was read, and otherwise it's left in place
+	assert.True(t, errors.Is(replaceLockfile(fs, lockFile, "stale", &theirs), errLockfileTaken))
+	lock, err = LoadDBLockFile(fs, lockFile)
+	require.NoError(t, err)
+	assert.Equal(t, ours.Secret, lock.Secret)
+
+	// once another server takes the lease over, renewing it fails without overwriting the other server's lock
+	require.NoError(t, replaceLockfile(fs, lockFile, ours.Secret, &theirs))
+	assert.True(t, ErrLockLeaseLost.Is(dEnv.RenewLockLease(&ours, time.Minute)))
+	lock, err = LoadDBLockFile(fs, lockFile)
+	require.NoError(t, err)
+	assert.Equal(t, theirs.Secret, lock.Secret)
+	require.NoError(t, dEnv.RenewLockLease(&theirs, time.Hour))
+
+	// nothing but the lock file is left behind
+	var files []string
+	require.NoError(t, fs.Iter(filepath.Dir(lockFile), false, func(path string, size int64, isDir bool) bool {
+		files = append(files, filepath.Base(path))
+		return false
+	}))
+	assert.Equal(t, []string{ServerLockFile}, files)
+}
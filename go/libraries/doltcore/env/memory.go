@@ -223,6 +223,18 @@ func (m MemoryRepoState) UpdateBranch(name string, new BranchConfig) error {
 	return nil
 }
 
+func (m MemoryRepoState) RemoveBranch(name string) error {
+	return nil
+}
+
+func (m MemoryRepoState) GetBranchExpirations() (map[string]BranchExpiration, error) {
+	return make(map[string]BranchExpiration), nil
+}
+
+func (m MemoryRepoState) SetBranchExpiration(name string, expiration BranchExpiration) error {
+	return nil
+}
+
 func (m MemoryRepoState) RemoveRemote(ctx context.Context, name string) error {
 	return fmt.Errorf("cannot delete a remote from a memory database")
 }
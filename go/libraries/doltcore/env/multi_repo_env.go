@@ -21,6 +21,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 	"unicode"
 
 	"github.com/sirupsen/logrus"
@@ -64,6 +66,55 @@ func MultiEnvForSingleEnv(ctx context.Context, env *DoltEnv) (*MultiRepoEnv, err
 	return MultiEnvForDirectory(ctx, env.Config.WriteableConfig(), env.FS, env.Version, env.IgnoreLockFile, env)
 }
 
+// MultiEnvOption configures how MultiEnvForDirectory discovers the databases in its directory.
+type MultiEnvOption func(*multiEnvOptions)
+
+type multiEnvOptions struct {
+	depth       int
+	ignore      []string
+	lazy        bool
+	concurrency int
+	// names records the names of the databases whose directories aren't named for them
+	names *DBNames
+}
+
+// WithDiscoveryDepth sets how many levels of directories under the data directory are searched for databases. The
+// default is 1, which only finds databases in the data directory's subdirectories. Databases found further down are
+// named for their path from the data directory, e.g. the database in team/project/db is named team_project_db. The
+// directories of databases are never searched.
+func WithDiscoveryDepth(depth int) MultiEnvOption {
+	return func(opts *multiEnvOptions) {
+		opts.depth = depth
+	}
+}
+
+// WithDiscoveryIgnore skips the directories under the data directory that match any of |patterns|, like the patterns
+// in dolt_ignore do for tables. A pattern is matched with filepath.Match against both the name of a directory and its
+// slash-separated path from the data directory, so "archive" skips every directory named archive, and "team/old*"
+// skips only those in team.
+func WithDiscoveryIgnore(patterns []string) MultiEnvOption {
+	return func(opts *multiEnvOptions) {
+		opts.ignore = patterns
+	}
+}
+
+// WithLazyLoad doesn't open the DoltDBs of the databases discovered in the data directory's subdirectories, so that
+// a data directory with many databases loads quickly. Each must be opened with DoltEnv.LoadDB before it's used. Since
+// their storage formats aren't known until they're opened, they aren't checked against the other databases' formats.
+func WithLazyLoad() MultiEnvOption {
+	return func(opts *multiEnvOptions) {
+		opts.lazy = true
+	}
+}
+
+// WithLoadConcurrency sets how many of the databases discovered in the data directory are loaded at once. The default
+// is 1, which loads them one at a time.
+func WithLoadConcurrency(concurrency int) MultiEnvOption {
+	return func(opts *multiEnvOptions) {
+		opts.concurrency = concurrency
+	}
+}
+
 // MultiEnvForDirectory returns a MultiRepoEnv for the directory rooted at the file system given. The doltEnv from the
 // invoking context is included. If it's non-nil and valid, it will be included in the returned MultiRepoEnv, and will
 // be the first database in all iterations.
@@ -74,7 +125,13 @@ func MultiEnvForDirectory(
 	version string,
 	ignoreLockFile bool,
 	dEnv *DoltEnv,
+	opts ...MultiEnvOption,
 ) (*MultiRepoEnv, error) {
+	options := multiEnvOptions{depth: 1, concurrency: 1}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	// Load current dataDirFS and put into mr env
 	var dbName string = "dolt"
 	var newDEnv *DoltEnv = dEnv
@@ -105,31 +162,20 @@ func MultiEnvForDirectory(
 		envSet[dbName] = newDEnv
 	}
 
-	// If there are other directories in the directory, try to load them as additional databases
-	dataDirFS.Iter(".", false, func(path string, size int64, isDir bool) (stop bool) {
-		if !isDir {
-			return false
-		}
-
-		dir := filepath.Base(path)
-
-		newFs, err := dataDirFS.WithWorkingDir(dir)
-		if err != nil {
-			return false
-		}
+	// TODO: get rid of version altogether
+	envVersion := ""
+	if dEnv != nil {
+		envVersion = dEnv.Version
+	}
 
-		// TODO: get rid of version altogether
-		version := ""
-		if dEnv != nil {
-			version = dEnv.Version
-		}
+	names, err := LoadDBNames(dataDirFS)
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %w", DBNamesFile, err)
+	}
+	options.names = names
 
-		newEnv := Load(ctx, GetCurrentUserHomeDir, newFs, doltdb.LocalDirDoltDB, version)
-		if newEnv.Valid() {
-			envSet[dirToDBName(dir)] = newEnv
-		}
-		return false
-	})
+	// If there are other directories in the directory, try to load them as additional databases
+	discoverEnvs(ctx, dataDirFS, nil, options, envVersion, envSet)
 
 	enforceSingleFormat(envSet)
 
@@ -152,6 +198,111 @@ func MultiEnvForDirectory(
 	return mrEnv, nil
 }
 
+// discoverEnvs loads the databases in the subdirectories of |fs|, which is at |path| under the data directory, into
+// |envSet|, and searches the subdirectories that aren't databases for more, down to the discovery depth.
+func discoverEnvs(ctx context.Context, fs filesys.Filesys, path []string, options multiEnvOptions, version string, envSet map[string]*DoltEnv) {
+	var dirs []string
+	fs.Iter(".", false, func(p string, size int64, isDir bool) (stop bool) {
+		if isDir {
+			dirs = append(dirs, filepath.Base(p))
+		}
+		return false
+	})
+	sort.Strings(dirs)
+
+	var dirPaths [][]string
+	var dirFss []filesys.Filesys
+	for _, dir := range dirs {
+		dirPath := append(append([]string{}, path...), dir)
+		if ignoreDir(dirPath, options.ignore) {
+			continue
+		}
+
+		newFs, err := fs.WithWorkingDir(dir)
+		if err != nil {
+			continue
+		}
+		dirPaths = append(dirPaths, dirPath)
+		dirFss = append(dirFss, newFs)
+	}
+
+	envs := loadEnvs(ctx, dirFss, options, version)
+
+	var subdirs []string
+	for i, newEnv := range envs {
+		dirPath := dirPaths[i]
+		dir := dirPath[len(dirPath)-1]
+		if !newEnv.Valid() {
+			// hidden directories, like .dolt and .doltcfg, are never searched for databases
+			if len(dirPath) < options.depth && !strings.HasPrefix(dir, ".") {
+				subdirs = append(subdirs, dir)
+			}
+			continue
+		}
+
+		dbName := dirToDBName(strings.Join(dirPath, "_"))
+		if name, ok := options.names.NameForDir(dir); ok && len(dirPath) == 1 {
+			dbName = name
+		}
+		if _, ok := envSet[dbName]; ok && len(dirPath) > 1 {
+			logrus.Warnf("not loading the database in %s, because another database is named %s", strings.Join(dirPath, "/"), dbName)
+			continue
+		}
+		envSet[dbName] = newEnv
+	}
+
+	// databases closer to the data directory take their names before ones further down
+	for _, dir := range subdirs {
+		newFs, err := fs.WithWorkingDir(dir)
+		if err != nil {
+			continue
+		}
+		discoverEnvs(ctx, newFs, append(append([]string{}, path...), dir), options, version, envSet)
+	}
+}
+
+// loadEnvs loads an environment from each of |fss|, with up to |options.concurrency| of them loading at once. The
+// environments are returned in the same order as |fss|.
+func loadEnvs(ctx context.Context, fss []filesys.Filesys, options multiEnvOptions, version string) []*DoltEnv {
+	envs := make([]*DoltEnv, len(fss))
+	concurrency := options.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, fs := range fss {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, fs filesys.Filesys) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if options.lazy {
+				envs[i] = loadWithoutDBFromURL(ctx, GetCurrentUserHomeDir, fs, doltdb.LocalDirDoltDB, version)
+			} else {
+				envs[i] = Load(ctx, GetCurrentUserHomeDir, fs, doltdb.LocalDirDoltDB, version)
+			}
+		}(i, fs)
+	}
+	wg.Wait()
+	return envs
+}
+
+// ignoreDir returns whether the directory at |path| under the data directory matches any of |patterns|.
+func ignoreDir(path []string, patterns []string) bool {
+	for _, pattern := range patterns {
+		for _, name := range []string{path[len(path)-1], strings.Join(path, "/")} {
+			if ok, err := filepath.Match(pattern, name); err == nil && ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // MultiEnvForPaths takes a variable list of EnvNameAndPath objects loads each of the environments, and returns a new
 // MultiRepoEnv
 func MultiEnvForPaths(
@@ -312,10 +463,27 @@ func (mrEnv *MultiRepoEnv) Lock(lck *DBLock) (err error) {
 		return ErrActiveServerLock.New(f)
 	}
 
-	for _, e := range mrEnv.envs {
+	for i, e := range mrEnv.envs {
 		err = e.env.Lock(lck)
 		if err != nil {
-			mrEnv.Unlock()
+			// only the envs locked here are unlocked, since the env that failed may be locked by another server
+			for _, locked := range mrEnv.envs[:i] {
+				locked.env.Unlock()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// RenewLockLease extends the lease of |lck| on all child envs by |lease|.
+func (mrEnv *MultiRepoEnv) RenewLockLease(lck *DBLock, lease time.Duration) error {
+	if mrEnv.ignoreLockFile {
+		return nil
+	}
+
+	for _, e := range mrEnv.envs {
+		if err := e.env.RenewLockLease(lck, lease); err != nil {
 			return err
 		}
 	}
@@ -380,7 +548,9 @@ func getRepoRootDir(path, pathSeparator string) string {
 func enforceSingleFormat(envSet map[string]*DoltEnv) {
 	formats := set.NewEmptyStrSet()
 	for _, dEnv := range envSet {
-		formats.Add(dEnv.DoltDB.Format().VersionString())
+		if dEnv.DBLoaded() {
+			formats.Add(dEnv.DoltDB.Format().VersionString())
+		}
 	}
 
 	var nbf string
@@ -390,12 +560,17 @@ func enforceSingleFormat(envSet map[string]*DoltEnv) {
 	} else {
 		// otherwise, pick an arbitrary format
 		for _, dEnv := range envSet {
-			nbf = dEnv.DoltDB.Format().VersionString()
+			if dEnv.DBLoaded() {
+				nbf = dEnv.DoltDB.Format().VersionString()
+			}
 		}
 	}
 
 	template := "incompatible format for database '%s'; expected '%s', found '%s'"
 	for name, dEnv := range envSet {
+		if !dEnv.DBLoaded() {
+			continue
+		}
 		found := dEnv.DoltDB.Format().VersionString()
 		if found != nbf {
 			logrus.Infof(template, name, nbf, found)
@@ -16,8 +16,10 @@ package env
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -190,3 +192,107 @@ func TestLoadMultiEnv(t *testing.T) {
 		assert.NotNil(t, e)
 	}
 }
+
+func TestMultiEnvForDirectoryWithDiscoveryOptions(t *testing.T) {
+	rootPath, err := test.ChangeToTestDir("TestDoltEnvAsMultiEnvWithDiscoveryOptions")
+	require.NoError(t, err)
+
+	hdp := func() (string, error) { return rootPath, nil }
+	envPath := filepath.Join(rootPath, "root")
+	dEnv := initRepoWithRelativePath(t, envPath, hdp)
+	topEnv := initRepoWithRelativePath(t, filepath.Join(envPath, "abc"), hdp)
+	nestedEnv := initRepoWithRelativePath(t, filepath.Join(envPath, "team", "project", "db"), hdp)
+	archivedEnv := initRepoWithRelativePath(t, filepath.Join(envPath, "team", "archive", "old"), hdp)
+	initRepoWithRelativePath(t, filepath.Join(envPath, "abc", "inside"), hdp)
+	initRepoWithRelativePath(t, filepath.Join(envPath, "a", "b", "c", "too_deep"), hdp)
+
+	envNames := func(mrEnv *MultiRepoEnv) map[string]string {
+		actual := make(map[string]string)
+		for _, env := range mrEnv.envs {
+			actual[env.name] = env.env.GetDoltDir()
+		}
+		return actual
+	}
+
+	// by default, only the first level is searched
+	mrEnv, err := MultiEnvForDirectory(context.Background(), dEnv.Config.WriteableConfig(), dEnv.FS, dEnv.Version, dEnv.IgnoreLockFile, dEnv)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"root": dEnv.GetDoltDir(),
+		"abc":  topEnv.GetDoltDir(),
+	}, envNames(mrEnv))
+
+	mrEnv, err = MultiEnvForDirectory(context.Background(), dEnv.Config.WriteableConfig(), dEnv.FS, dEnv.Version, dEnv.IgnoreLockFile, dEnv,
+		WithDiscoveryDepth(3), WithDiscoveryIgnore([]string{"archive"}))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"root":            dEnv.GetDoltDir(),
+		"abc":             topEnv.GetDoltDir(),
+		"team_project_db": nestedEnv.GetDoltDir(),
+	}, envNames(mrEnv))
+
+	// patterns can match the path of a directory, as well as its name
+	mrEnv, err = MultiEnvForDirectory(context.Background(), dEnv.Config.WriteableConfig(), dEnv.FS, dEnv.Version, dEnv.IgnoreLockFile, dEnv,
+		WithDiscoveryDepth(3), WithDiscoveryIgnore([]string{"team/p*"}))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"root":             dEnv.GetDoltDir(),
+		"abc":              topEnv.GetDoltDir(),
+		"team_archive_old": archivedEnv.GetDoltDir(),
+	}, envNames(mrEnv))
+}
+
+func TestMultiEnvForDirectoryWithLazyLoad(t *testing.T) {
+	rootPath, err := test.ChangeToTestDir("TestDoltEnvAsMultiEnvWithLazyLoad")
+	require.NoError(t, err)
+
+	hdp := func() (string, error) { return rootPath, nil }
+	envPath := filepath.Join(rootPath, "root")
+	dEnv := initRepoWithRelativePath(t, envPath, hdp)
+	initRepoWithRelativePath(t, filepath.Join(envPath, "abc"), hdp)
+
+	mrEnv, err := MultiEnvForDirectory(context.Background(), dEnv.Config.WriteableConfig(), dEnv.FS, dEnv.Version, dEnv.IgnoreLockFile, dEnv, WithLazyLoad())
+	require.NoError(t, err)
+
+	// the data directory's own database is always opened
+	assert.True(t, mrEnv.GetEnv("root").DBLoaded())
+
+	abcEnv := mrEnv.GetEnv("abc")
+	require.NotNil(t, abcEnv)
+	assert.False(t, abcEnv.DBLoaded())
+	require.NoError(t, abcEnv.LoadDB(context.Background()))
+	assert.True(t, abcEnv.DBLoaded())
+	_, err = abcEnv.WorkingRoot(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestMultiEnvForDirectoryWithLoadConcurrency(t *testing.T) {
+	rootPath, err := test.ChangeToTestDir("TestDoltEnvAsMultiEnvWithLoadConcurrency")
+	require.NoError(t, err)
+
+	hdp := func() (string, error) { return rootPath, nil }
+	envPath := filepath.Join(rootPath, "root")
+	dEnv := initRepoWithRelativePath(t, envPath, hdp)
+	expected := map[string]string{"root": dEnv.GetDoltDir()}
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("db%d", i)
+		expected[name] = initRepoWithRelativePath(t, filepath.Join(envPath, name), hdp).GetDoltDir()
+	}
+	expected["team_nested"] = initRepoWithRelativePath(t, filepath.Join(envPath, "team", "nested"), hdp).GetDoltDir()
+
+	mrEnv, err := MultiEnvForDirectory(context.Background(), dEnv.Config.WriteableConfig(), dEnv.FS, dEnv.Version, dEnv.IgnoreLockFile, dEnv,
+		WithDiscoveryDepth(2), WithLoadConcurrency(4))
+	require.NoError(t, err)
+
+	// databases loaded in parallel are named and ordered the same as those loaded one at a time
+	actual := make(map[string]string)
+	var names []string
+	for _, env := range mrEnv.envs {
+		actual[env.name] = env.env.GetDoltDir()
+		names = append(names, env.name)
+		assert.True(t, env.env.DBLoaded())
+	}
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, "root", names[0])
+	assert.True(t, sort.StringsAreSorted(names[1:]))
+}
@@ -45,6 +45,8 @@ var ErrCannotSetUpstreamForTag = errors.New("cannot set upstream for tag")
 var ErrCannotPushRef = errors.New("cannot push ref")
 var ErrNoRefSpecForRemote = errors.New("no refspec for remote")
 var ErrInvalidSetUpstreamArgs = errors.New("invalid set-upstream arguments")
+var ErrInvalidDeleteArgs = errors.New("--delete requires a remote and the name of the ref to delete")
+var ErrInvalidForceArgs = errors.New("--force and --force-with-lease can't be used together")
 var ErrInvalidFetchSpec = errors.New("invalid fetch spec")
 var ErrPullWithRemoteNoUpstream = errors.New("You asked to pull from the remote '%s', but did not specify a branch. Because this is not the default configured remote for your current branch, you must specify a branch.")
 var ErrPullWithNoRemoteAndNoUpstream = errors.New("There is no tracking information for the current branch.\nPlease specify which branch you want to merge with.\n\n\tdolt pull <remote> <branch>\n\nIf you wish to set tracking information for this branch you can do so with:\n\n\t dolt push --set-upstream <remote> <branch>\n")
@@ -123,16 +125,28 @@ type PushOpts struct {
 	SetUpstream bool
 }
 
-func NewPushOpts(ctx context.Context, apr *argparser.ArgParseResults, rsr RepoStateReader, ddb *doltdb.DoltDB, force bool, setUpstream bool, pushAutoSetupRemote bool) (*PushOpts, error) {
+func NewPushOpts(ctx context.Context, apr *argparser.ArgParseResults, rsr RepoStateReader, ddb *doltdb.DoltDB, force bool, forceWithLease bool, setUpstream bool, pushAutoSetupRemote bool, deleteRef bool) (*PushOpts, error) {
 	var err error
 	remotes, err := rsr.GetRemotes()
 	if err != nil {
 		return nil, err
 	}
 
+	if force && forceWithLease {
+		return nil, ErrInvalidForceArgs
+	}
+
 	remoteName := "origin"
 
 	args := apr.Args
+	if deleteRef {
+		// pushing nothing to a ref deletes it from the remote
+		if len(args) != 2 || setUpstream {
+			return nil, ErrInvalidDeleteArgs
+		}
+		args = []string{args[0], ":" + strings.TrimPrefix(args[1], ":")}
+	}
+
 	if len(args) == 1 {
 		if _, ok := remotes[args[0]]; ok {
 			remoteName = args[0]
@@ -234,7 +248,8 @@ func NewPushOpts(ctx context.Context, apr *argparser.ArgParseResults, rsr RepoSt
 		RemoteRef: remoteRef,
 		Remote:    remote,
 		Mode: ref.UpdateMode{
-			Force: force,
+			Force: force || forceWithLease,
+			Lease: forceWithLease,
 		},
 		SetUpstream: setUpstream,
 	}
@@ -315,6 +330,14 @@ func ParseRSFromArgs(remName string, args []string) ([]ref.RemoteRefSpec, error)
 // if possible, convert refs to full spec names. prefer branches over tags.
 // eg "main" -> "refs/heads/main", "v1" -> "refs/tags/v1"
 func disambiguateRefSpecStr(ctx context.Context, ddb *doltdb.DoltDB, refSpecStr string) (string, error) {
+	if strings.HasPrefix(refSpecStr, ":") {
+		dest, err := disambiguateRefSpecStr(ctx, ddb, refSpecStr[1:])
+		if err != nil {
+			return "", err
+		}
+		return ":" + dest, nil
+	}
+
 	brachRefs, err := ddb.GetBranches(ctx)
 
 	if err != nil {
@@ -17,6 +17,7 @@ package env
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
@@ -32,6 +33,8 @@ type RepoStateReader interface {
 	GetRemotes() (map[string]Remote, error)
 	GetBackups() (map[string]Remote, error)
 	GetBranches() (map[string]BranchConfig, error)
+	// GetBranchExpirations returns the expiration of every branch that has one, keyed by branch name.
+	GetBranchExpirations() (map[string]BranchExpiration, error)
 }
 
 type RepoStateWriter interface {
@@ -43,6 +46,11 @@ type RepoStateWriter interface {
 	RemoveBackup(ctx context.Context, name string) error
 	TempTableFilesDir() (string, error)
 	UpdateBranch(name string, new BranchConfig) error
+	// RemoveBranch removes the tracking configuration for the branch named, if there is any.
+	RemoveBranch(name string) error
+	// SetBranchExpiration records when the branch named expires, and who set its expiration. An expiration with a zero
+	// time clears any expiration.
+	SetBranchExpiration(name string, expiration BranchExpiration) error
 }
 
 type RepoStateReadWriter interface {
@@ -61,6 +69,13 @@ type DbData struct {
 	Rsr RepoStateReader
 }
 
+// BranchExpiration is when an ephemeral branch expires, and who set its expiration.
+type BranchExpiration struct {
+	Expires time.Time
+	// Owner is the user that set the expiration, as "name <email>", who is notified when the branch expires.
+	Owner string
+}
+
 type BranchConfig struct {
 	Merge  ref.MarshalableRef `json:"head"`
 	Remote string             `json:"remote"`
@@ -71,6 +86,11 @@ type RepoState struct {
 	Remotes  map[string]Remote       `json:"remotes"`
 	Backups  map[string]Remote       `json:"backups"`
 	Branches map[string]BranchConfig `json:"branches"`
+	// BranchExpirations records the time at which ephemeral branches expire. Expired branches are removed by
+	// actions.ExpireBranches.
+	BranchExpirations map[string]time.Time `json:"branch_expirations,omitempty"`
+	// BranchOwners records the users that set the expirations of ephemeral branches.
+	BranchOwners map[string]string `json:"branch_owners,omitempty"`
 	// |staged|, |working|, and |merge| are legacy fields left over from when Dolt repos stored this info in the repo
 	// state file, not in the DB directly. They're still here so that we can migrate existing repositories forward to the
 	// new storage format, but they should be used only for this purpose and are no longer written.
@@ -89,6 +109,9 @@ type repoStateLegacy struct {
 	Staged   string                  `json:"staged,omitempty"`
 	Working  string                  `json:"working,omitempty"`
 	Merge    *mergeState             `json:"merge,omitempty"`
+
+	BranchExpirations map[string]time.Time `json:"branch_expirations,omitempty"`
+	BranchOwners      map[string]string    `json:"branch_owners,omitempty"`
 }
 
 // repoStateLegacyFromRepoState creates a new repoStateLegacy from a RepoState file. Only for testing.
@@ -101,6 +124,9 @@ func repoStateLegacyFromRepoState(rs *RepoState) *repoStateLegacy {
 		Staged:   rs.staged,
 		Working:  rs.working,
 		Merge:    rs.merge,
+
+		BranchExpirations: rs.BranchExpirations,
+		BranchOwners:      rs.BranchOwners,
 	}
 }
 
@@ -118,6 +144,9 @@ func (rs *repoStateLegacy) toRepoState() *RepoState {
 		staged:   rs.Staged,
 		working:  rs.Working,
 		merge:    rs.Merge,
+
+		BranchExpirations: rs.BranchExpirations,
+		BranchOwners:      rs.BranchOwners,
 	}
 }
 
@@ -226,3 +255,34 @@ func (rs *RepoState) AddBackup(r Remote) {
 func (rs *RepoState) RemoveBackup(r Remote) {
 	delete(rs.Backups, r.Name)
 }
+
+// GetBranchExpirations returns the expirations of the branches that have one. The map returned is a copy, so callers
+// can delete branches while iterating over it.
+func (rs *RepoState) GetBranchExpirations() map[string]BranchExpiration {
+	expirations := make(map[string]BranchExpiration, len(rs.BranchExpirations))
+	for name, expires := range rs.BranchExpirations {
+		expirations[name] = BranchExpiration{Expires: expires, Owner: rs.BranchOwners[name]}
+	}
+	return expirations
+}
+
+// SetBranchExpiration records the expiration of the branch named, or clears it if its time is zero.
+func (rs *RepoState) SetBranchExpiration(name string, expiration BranchExpiration) {
+	if expiration.Expires.IsZero() {
+		delete(rs.BranchExpirations, name)
+		delete(rs.BranchOwners, name)
+		return
+	}
+	if rs.BranchExpirations == nil {
+		rs.BranchExpirations = make(map[string]time.Time)
+	}
+	rs.BranchExpirations[name] = expiration.Expires.UTC()
+	if expiration.Owner == "" {
+		delete(rs.BranchOwners, name)
+		return
+	}
+	if rs.BranchOwners == nil {
+		rs.BranchOwners = make(map[string]string)
+	}
+	rs.BranchOwners[name] = expiration.Owner
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+// SparseFile is the file in the .dolt directory of a sparse clone that lists the tables it holds the data of, one per
+// line. Blank lines and lines starting with '#' are ignored.
+const SparseFile = "sparse"
+
+func getSparseFile() string {
+	return filepath.Join(dbfactory.DoltDir, SparseFile)
+}
+
+// SparseTables returns the tables listed in the sparse spec of the environment, or nil if it isn't a sparse clone.
+func (dEnv *DoltEnv) SparseTables() ([]string, error) {
+	if exists, _ := dEnv.FS.Exists(getSparseFile()); !exists {
+		return nil, nil
+	}
+	data, err := dEnv.FS.ReadFile(getSparseFile())
+	if err != nil {
+		return nil, err
+	}
+
+	tables := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tables = append(tables, line)
+	}
+	return tables, nil
+}
+
+// SetSparseTables writes the sparse spec of the environment, making it a sparse clone that holds the data of only the
+// tables in |tables|.
+func (dEnv *DoltEnv) SetSparseTables(tables []string) error {
+	err := dEnv.FS.WriteFile(getSparseFile(), []byte(strings.Join(tables, "\n")+"\n"))
+	if err != nil {
+		return err
+	}
+	return dEnv.loadSparseSpec()
+}
+
+// loadSparseSpec sets the sparse spec of the environment on its database, if it has one, so that the data of the tables
+// it leaves out is fetched from the default remote the first time it's read.
+func (dEnv *DoltEnv) loadSparseSpec() error {
+	tables, err := dEnv.SparseTables()
+	if err != nil || tables == nil {
+		return err
+	}
+	tmpDir, err := dEnv.TempTableFilesDir()
+	if err != nil {
+		return err
+	}
+
+	return dEnv.DoltDB.SetSparseSpec(tables, tmpDir, func(ctx context.Context) (*doltdb.DoltDB, error) {
+		remote, err := GetDefaultRemote(dEnv.RepoStateReader())
+		if err != nil {
+			return nil, err
+		}
+		return remote.GetRemoteDB(ctx, dEnv.DoltDB.Format(), NewGRPCDialProviderFromDoltEnv(dEnv))
+	})
+}
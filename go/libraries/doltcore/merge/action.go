@@ -44,6 +44,7 @@ type MergeSpec struct {
 	NoCommit        bool
 	NoEdit          bool
 	Force           bool
+	NoRenames       bool
 	Email           string
 	Name            string
 	Date            time.Time
@@ -75,6 +76,12 @@ func WithForce(force bool) MergeSpecOpt {
 	}
 }
 
+func WithNoRenames(noRenames bool) MergeSpecOpt {
+	return func(ms *MergeSpec) {
+		ms.NoRenames = noRenames
+	}
+}
+
 func WithSquash(squash bool) MergeSpecOpt {
 	return func(ms *MergeSpec) {
 		ms.Squash = squash
@@ -233,7 +240,7 @@ func ExecuteMerge(ctx *sql.Context, dEnv *env.DoltEnv, spec *MergeSpec) (map[str
 		return nil, err
 	}
 	opts := editor.Options{Deaf: dEnv.BulkDbEaFactory(), Tempdir: tmpDir}
-	result, err := MergeCommits(ctx, spec.HeadC, spec.MergeC, opts)
+	result, err := MergeCommits(ctx, spec.HeadC, spec.MergeC, opts, spec.NoRenames)
 	if err != nil {
 		switch err {
 		case doltdb.ErrUpToDate:
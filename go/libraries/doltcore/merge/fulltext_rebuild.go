@@ -15,9 +15,9 @@
 package merge
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/fulltext"
@@ -27,11 +27,20 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
 )
 
-// rebuildFullTextIndexes scans the entire root and rebuilds all of the pseudo-index tables. This is not the most
-// efficient way to go about it, but it at least produces the correct result.
-func rebuildFullTextIndexes(ctx *sql.Context, root *doltdb.RootValue) (*doltdb.RootValue, error) {
+// rebuildFullTextIndexes rebuilds the pseudo-index tables of every table on the merged root that declares a Full-Text
+// index. |ourRoot| is the root that was merged into, whose pseudo-index tables the merged root starts with. When a
+// table's schema is unchanged from |ourRoot| and its pseudo-index tables weren't touched by the merge, its indexes are
+// updated incrementally by applying the diff between our rows and the merged rows. Otherwise, the pseudo-index tables
+// are purged and rebuilt from the table's entire contents.
+//
+// Spatial indexes don't need any handling here, as they're stored as secondary indexes of their table, and are merged
+// along with its other secondary indexes.
+func rebuildFullTextIndexes(ctx *sql.Context, root *doltdb.RootValue, ourRoot *doltdb.RootValue) (*doltdb.RootValue, error) {
 	// Grab a list of all tables on the root
 	allTableNames, err := root.GetTableNames(ctx)
 	if err != nil {
@@ -40,173 +49,332 @@ func rebuildFullTextIndexes(ctx *sql.Context, root *doltdb.RootValue) (*doltdb.R
 	// Create a set that we'll check later to remove any orphaned pseudo-index tables.
 	// These may appear when a table is renamed on another branch and the index was recreated before merging.
 	foundTables := make(map[string]struct{})
-	// We'll purge the data from every Full-Text table so that we may rewrite their contents
+	// Only look at tables that declare a Full-Text index
 	for _, tblName := range allTableNames {
-		if !doltdb.IsFullTextTable(tblName) {
-			continue
-		} else if strings.HasSuffix(tblName, "config") {
-			// We don't want to purge the config table, we'll just roll with whatever is there for now
+		if doltdb.IsFullTextTable(tblName) {
 			continue
 		}
+		// Add this table to the found tables, since it's not a pseudo-index table.
+		foundTables[tblName] = struct{}{}
 		tbl, ok, err := root.GetTable(ctx, tblName)
 		if err != nil {
 			return nil, err
 		}
 		if !ok {
-			return nil, fmt.Errorf("attempted to purge `%s` during Full-Text merge but it could not be found", tblName)
+			return nil, fmt.Errorf("attempted to load `%s` during Full-Text merge but it could not be found", tblName)
 		}
 		sch, err := tbl.GetSchema(ctx)
 		if err != nil {
 			return nil, err
 		}
-		rows, err := durable.NewEmptyIndex(ctx, tbl.ValueReadWriter(), tbl.NodeStore(), sch)
-		if err != nil {
-			return nil, err
+		if !sch.Indexes().ContainsFullTextIndex() {
+			continue
 		}
-		tbl, err = tbl.UpdateRows(ctx, rows)
+
+		// The config table is shared by all indexes, and is never rebuilt
+		var configTableName string
+		var indexTableNames []string
+		for _, idx := range sch.Indexes().AllIndexes() {
+			if !idx.IsFullText() {
+				continue
+			}
+			props := idx.FullTextProperties()
+			configTableName = props.ConfigTable
+			indexTableNames = append(indexTableNames, props.PositionTable, props.DocCountTable, props.GlobalCountTable, props.RowCountTable)
+		}
+		// Add all of the pseudo-index tables as found tables
+		foundTables[configTableName] = struct{}{}
+		for _, name := range indexTableNames {
+			foundTables[name] = struct{}{}
+		}
+
+		ourRows, incremental, err := fullTextIncrementalBase(ctx, tblName, tbl, root, ourRoot, indexTableNames)
 		if err != nil {
 			return nil, err
 		}
-		root, err = root.PutTable(ctx, tblName, tbl)
+		if incremental {
+			rowsHash, err := tbl.GetRowDataHash(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if ourRowsHash, err := ourRows.HashOf(); err != nil {
+				return nil, err
+			} else if ourRowsHash == rowsHash {
+				// The merge didn't change the table's rows, so our indexes are already up to date
+				continue
+			}
+		} else {
+			// We'll purge the data from the pseudo-index tables so that we may rewrite their contents
+			root, err = purgeFullTextTables(ctx, root, indexTableNames)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		root, err = updateFullTextIndexes(ctx, root, tblName, tbl, sch, ourRows, incremental)
 		if err != nil {
 			return nil, err
 		}
 	}
-	// Loop again, this time only looking at tables that declare a Full-Text index
+	// Our last loop removes any orphaned pseudo-index tables
 	for _, tblName := range allTableNames {
-		if doltdb.IsFullTextTable(tblName) {
+		if _, found := foundTables[tblName]; found || !doltdb.IsFullTextTable(tblName) {
 			continue
 		}
-		// Add this table to the found tables, since it's not a pseudo-index table.
-		foundTables[tblName] = struct{}{}
+		root, err = root.RemoveTables(ctx, true, true, tblName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+// fullTextIncrementalBase returns our rows of the table |tblName|, and whether its Full-Text indexes, whose
+// pseudo-index tables other than the config table are |indexTableNames|, may be updated incrementally from them. This
+// requires that the table has the same schema on both roots, and that the pseudo-index tables on the merged |root| are
+// still ours.
+func fullTextIncrementalBase(ctx *sql.Context, tblName string, tbl *doltdb.Table, root, ourRoot *doltdb.RootValue, indexTableNames []string) (durable.Index, bool, error) {
+	ourTbl, ok, err := ourRoot.GetTable(ctx, tblName)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	ourSchHash, err := ourTbl.GetSchemaHash(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	schHash, err := tbl.GetSchemaHash(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if ourSchHash != schHash {
+		return nil, false, nil
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if schema.IsKeyless(sch) {
+		return nil, false, nil
+	}
+
+	for _, name := range indexTableNames {
+		h, ok, err := root.GetTableHash(ctx, name)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		ourHash, ok, err := ourRoot.GetTableHash(ctx, name)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		if h != ourHash {
+			return nil, false, nil
+		}
+	}
+
+	ourRows, err := ourTbl.GetRowData(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	return ourRows, true, nil
+}
+
+// purgeFullTextTables removes all rows from the pseudo-index tables given.
+func purgeFullTextTables(ctx *sql.Context, root *doltdb.RootValue, tblNames []string) (*doltdb.RootValue, error) {
+	for _, tblName := range tblNames {
 		tbl, ok, err := root.GetTable(ctx, tblName)
 		if err != nil {
 			return nil, err
 		}
 		if !ok {
-			return nil, fmt.Errorf("attempted to load `%s` during Full-Text merge but it could not be found", tblName)
+			return nil, fmt.Errorf("attempted to purge `%s` during Full-Text merge but it could not be found", tblName)
 		}
 		sch, err := tbl.GetSchema(ctx)
 		if err != nil {
 			return nil, err
 		}
-		if !sch.Indexes().ContainsFullTextIndex() {
-			continue
+		rows, err := durable.NewEmptyIndex(ctx, tbl.ValueReadWriter(), tbl.NodeStore(), sch)
+		if err != nil {
+			return nil, err
+		}
+		tbl, err = tbl.UpdateRows(ctx, rows)
+		if err != nil {
+			return nil, err
 		}
-		parentTable, err := createFulltextTable(ctx, tblName, root)
+		root, err = root.PutTable(ctx, tblName, tbl)
 		if err != nil {
 			return nil, err
 		}
+	}
+	return root, nil
+}
 
-		var configTable *fulltextTable
-		var tableSet []fulltext.TableSet
-		allFTDoltTables := make(map[string]*fulltextTable)
-		for _, idx := range sch.Indexes().AllIndexes() {
-			if !idx.IsFullText() {
-				continue
-			}
-			props := idx.FullTextProperties()
-			// Add all of the pseudo-index tables as found tables
-			foundTables[props.ConfigTable] = struct{}{}
-			foundTables[props.PositionTable] = struct{}{}
-			foundTables[props.DocCountTable] = struct{}{}
-			foundTables[props.GlobalCountTable] = struct{}{}
-			foundTables[props.RowCountTable] = struct{}{}
-			// The config table is shared, and it's not written to during this process
-			if configTable == nil {
-				configTable, err = createFulltextTable(ctx, props.ConfigTable, root)
-				if err != nil {
-					return nil, err
-				}
-				allFTDoltTables[props.ConfigTable] = configTable
-			}
-			positionTable, err := createFulltextTable(ctx, props.PositionTable, root)
-			if err != nil {
-				return nil, err
-			}
-			docCountTable, err := createFulltextTable(ctx, props.DocCountTable, root)
-			if err != nil {
-				return nil, err
-			}
-			globalCountTable, err := createFulltextTable(ctx, props.GlobalCountTable, root)
-			if err != nil {
-				return nil, err
-			}
-			rowCountTable, err := createFulltextTable(ctx, props.RowCountTable, root)
-			if err != nil {
-				return nil, err
-			}
-			allFTDoltTables[props.PositionTable] = positionTable
-			allFTDoltTables[props.DocCountTable] = docCountTable
-			allFTDoltTables[props.GlobalCountTable] = globalCountTable
-			allFTDoltTables[props.RowCountTable] = rowCountTable
-			ftIndex, err := index.ConvertFullTextToSql(ctx, "", tblName, sch, idx)
+// updateFullTextIndexes writes the rows of the table |tblName| to the pseudo-index tables of its Full-Text indexes.
+// If |incremental| is true, only the differences between |ourRows| and the table's rows are written, and otherwise
+// every row of the table is written to the (purged) pseudo-index tables.
+func updateFullTextIndexes(ctx *sql.Context, root *doltdb.RootValue, tblName string, tbl *doltdb.Table, sch schema.Schema, ourRows durable.Index, incremental bool) (*doltdb.RootValue, error) {
+	parentTable, err := createFulltextTable(ctx, tblName, root)
+	if err != nil {
+		return nil, err
+	}
+
+	var configTable *fulltextTable
+	var tableSet []fulltext.TableSet
+	allFTDoltTables := make(map[string]*fulltextTable)
+	for _, idx := range sch.Indexes().AllIndexes() {
+		if !idx.IsFullText() {
+			continue
+		}
+		props := idx.FullTextProperties()
+		// The config table is shared, and it's not written to during this process
+		if configTable == nil {
+			configTable, err = createFulltextTable(ctx, props.ConfigTable, root)
 			if err != nil {
 				return nil, err
 			}
-			tableSet = append(tableSet, fulltext.TableSet{
-				Index:       ftIndex.(fulltext.Index),
-				Position:    positionTable,
-				DocCount:    docCountTable,
-				GlobalCount: globalCountTable,
-				RowCount:    rowCountTable,
-			})
+			allFTDoltTables[props.ConfigTable] = configTable
+		}
+		positionTable, err := createFulltextTable(ctx, props.PositionTable, root)
+		if err != nil {
+			return nil, err
+		}
+		docCountTable, err := createFulltextTable(ctx, props.DocCountTable, root)
+		if err != nil {
+			return nil, err
+		}
+		globalCountTable, err := createFulltextTable(ctx, props.GlobalCountTable, root)
+		if err != nil {
+			return nil, err
+		}
+		rowCountTable, err := createFulltextTable(ctx, props.RowCountTable, root)
+		if err != nil {
+			return nil, err
+		}
+		allFTDoltTables[props.PositionTable] = positionTable
+		allFTDoltTables[props.DocCountTable] = docCountTable
+		allFTDoltTables[props.GlobalCountTable] = globalCountTable
+		allFTDoltTables[props.RowCountTable] = rowCountTable
+		ftIndex, err := index.ConvertFullTextToSql(ctx, "", tblName, sch, idx)
+		if err != nil {
+			return nil, err
+		}
+		tableSet = append(tableSet, fulltext.TableSet{
+			Index:       ftIndex.(fulltext.Index),
+			Position:    positionTable,
+			DocCount:    docCountTable,
+			GlobalCount: globalCountTable,
+			RowCount:    rowCountTable,
+		})
+	}
+
+	ftEditor, err := fulltext.CreateEditor(ctx, parentTable, configTable, tableSet...)
+	if err != nil {
+		return nil, err
+	}
+	err = func() error {
+		defer ftEditor.Close(ctx)
+		ftEditor.StatementBegin(ctx)
+		defer ftEditor.StatementComplete(ctx)
+
+		if incremental {
+			return applyFullTextDiff(ctx, ftEditor, tbl, sch, ourRows)
 		}
 
 		// We'll write the entire contents of our table into the Full-Text editor
-		ftEditor, err := fulltext.CreateEditor(ctx, parentTable, configTable, tableSet...)
+		rowIter, err := createRowIterForTable(ctx, tblName, tbl, sch)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		err = func() error {
-			defer ftEditor.Close(ctx)
-			ftEditor.StatementBegin(ctx)
-			defer ftEditor.StatementComplete(ctx)
+		defer rowIter.Close(ctx)
 
-			rowIter, err := createRowIterForTable(ctx, tblName, tbl, sch)
-			if err != nil {
+		row, err := rowIter.Next(ctx)
+		for ; err == nil; row, err = rowIter.Next(ctx) {
+			if err = ftEditor.Insert(ctx, row); err != nil {
 				return err
 			}
-			defer rowIter.Close(ctx)
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+		return nil
+	}()
+	if err != nil {
+		return nil, err
+	}
 
-			row, err := rowIter.Next(ctx)
-			for ; err == nil; row, err = rowIter.Next(ctx) {
-				if err = ftEditor.Insert(ctx, row); err != nil {
-					return err
-				}
-			}
-			if err != nil && err != io.EOF {
-				return err
-			}
-			return nil
-		}()
+	// Update the root with all of the new tables' contents
+	for _, ftTable := range allFTDoltTables {
+		newTbl, err := ftTable.ApplyToTable(ctx)
+		if err != nil {
+			return nil, err
+		}
+		root, err = root.PutTable(ctx, ftTable.Name(), newTbl)
 		if err != nil {
 			return nil, err
 		}
+	}
+	return root, nil
+}
 
-		// Update the root with all of the new tables' contents
-		for _, ftTable := range allFTDoltTables {
-			newTbl, err := ftTable.ApplyToTable(ctx)
+// applyFullTextDiff writes the differences between |ourRows| and the rows of |tbl| to |ftEditor|.
+func applyFullTextDiff(ctx *sql.Context, ftEditor fulltext.TableEditor, tbl *doltdb.Table, sch schema.Schema, ourRows durable.Index) error {
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	from := durable.ProllyMapFromIndex(ourRows)
+	to := durable.ProllyMapFromIndex(rowData)
+	kd, vd := to.Descriptors()
+
+	err = prolly.DiffMaps(ctx, from, to, func(_ context.Context, diff tree.Diff) error {
+		switch diff.Type {
+		case tree.AddedDiff:
+			row, err := fullTextRowFromTuples(ctx, sch, kd, vd, val.Tuple(diff.Key), val.Tuple(diff.To), to.NodeStore())
 			if err != nil {
-				return nil, err
+				return err
 			}
-			root, err = root.PutTable(ctx, ftTable.Name(), newTbl)
+			return ftEditor.Insert(ctx, row)
+		case tree.RemovedDiff:
+			row, err := fullTextRowFromTuples(ctx, sch, kd, vd, val.Tuple(diff.Key), val.Tuple(diff.From), from.NodeStore())
 			if err != nil {
-				return nil, err
+				return err
+			}
+			return ftEditor.Delete(ctx, row)
+		case tree.ModifiedDiff:
+			oldRow, err := fullTextRowFromTuples(ctx, sch, kd, vd, val.Tuple(diff.Key), val.Tuple(diff.From), from.NodeStore())
+			if err != nil {
+				return err
+			}
+			newRow, err := fullTextRowFromTuples(ctx, sch, kd, vd, val.Tuple(diff.Key), val.Tuple(diff.To), to.NodeStore())
+			if err != nil {
+				return err
 			}
+			return ftEditor.Update(ctx, oldRow, newRow)
+		default:
+			return fmt.Errorf("unexpected diff type: %d", diff.Type)
 		}
+	})
+	if err != nil && err != io.EOF {
+		return err
 	}
-	// Our last loop removes any orphaned pseudo-index tables
-	for _, tblName := range allTableNames {
-		if _, found := foundTables[tblName]; found || !doltdb.IsFullTextTable(tblName) {
-			continue
+	return nil
+}
+
+// fullTextRowFromTuples returns the row of a table with the schema |sch| stored as the tuples |key| and |value|.
+func fullTextRowFromTuples(ctx *sql.Context, sch schema.Schema, kd, vd val.TupleDesc, key, value val.Tuple, ns tree.NodeStore) (sql.Row, error) {
+	pkCols, nonPkCols := sch.GetPKCols(), sch.GetNonPKCols()
+	row := make(sql.Row, sch.GetAllCols().Size())
+	for i, tag := range sch.GetAllCols().Tags {
+		var err error
+		if idx, ok := pkCols.TagToIdx[tag]; ok {
+			row[i], err = index.GetField(ctx, kd, idx, key, ns)
+		} else {
+			row[i], err = index.GetField(ctx, vd, nonPkCols.TagToIdx[tag], value, ns)
 		}
-		root, err = root.RemoveTables(ctx, true, true, tblName)
 		if err != nil {
 			return nil, err
 		}
 	}
-	return root, nil
+	return row, nil
 }
 
 func createRowIterForTable(ctx *sql.Context, name string, t *doltdb.Table, sch schema.Schema) (sql.RowIter, error) {
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	goerrors "gopkg.in/src-d/go-errors.v1"
@@ -46,7 +47,9 @@ var ErrMultipleViolationsForRow = errors.New("multiple violations for row not su
 
 var ErrSameTblAddedTwice = goerrors.NewKind("table with same name '%s' added in 2 commits can't be merged")
 
-func MergeCommits(ctx *sql.Context, commit, mergeCommit *doltdb.Commit, opts editor.Options) (*Result, error) {
+// MergeCommits three-way merges |mergeCommit| into |commit|, using their common ancestor. Tables and columns renamed on
+// one side of the merge are matched to their old names on the other, unless |noRenames| is set.
+func MergeCommits(ctx *sql.Context, commit, mergeCommit *doltdb.Commit, opts editor.Options, noRenames bool) (*Result, error) {
 	ancCommit, err := doltdb.GetCommitAncestor(ctx, commit, mergeCommit)
 	if err != nil {
 		return nil, err
@@ -70,6 +73,7 @@ func MergeCommits(ctx *sql.Context, commit, mergeCommit *doltdb.Commit, opts edi
 	mo := MergeOpts{
 		IsCherryPick:        false,
 		KeepSchemaConflicts: true,
+		NoRenames:           noRenames,
 	}
 	return MergeRoots(ctx, ourRoot, theirRoot, ancRoot, mergeCommit, ancCommit, opts, mo)
 }
@@ -179,8 +183,17 @@ func MergeRoots(
 		}
 	}
 
+	if !mergeOpts.NoRenames {
+		ourRoot, theirRoot, ancRoot, err = matchRenamedTables(ctx, ourRoot, theirRoot, ancRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Make sure to pass in ourRoot as the first RootValue so that ourRoot's table names will be merged first.
-	// This helps to avoid non-deterministic error result for table rename cases. Renaming a table creates two changes:
+	// This helps to avoid non-deterministic error result for table rename cases that matchRenamedTables leaves as they
+	// are, such as a table renamed differently on each side, or any rename when renames aren't matched. Renaming a table
+	// creates two changes:
 	// 1. dropping the old name table
 	// 2. adding the new name table
 	// Dropping the old name table will trigger delete/modify conflict, which is the preferred error case over
@@ -195,13 +208,18 @@ func MergeRoots(
 
 	mergedRoot := ourRoot
 
-	// Merge tables one at a time. This is done based on name. With table names from ourRoot being merged first,
-	// renaming a table will return delete/modify conflict error consistently.
-	// TODO: merge based on a more durable table identity that persists across renames
+	// Merge tables one at a time. This is done based on name, after matchRenamedTables has matched the names of tables
+	// renamed on one side. With table names from ourRoot being merged first, any other rename will return
+	// delete/modify conflict error consistently.
 	merger, err := NewMerger(ourRoot, theirRoot, ancRoot, theirs, ancestor, ourRoot.VRW(), ourRoot.NodeStore())
 	if err != nil {
 		return nil, err
 	}
+	// conflicting changes are resolved as our dolt_merge_strategies declares
+	merger.strategies, err = doltdb.GetMergeStrategies(ctx, ourRoot)
+	if err != nil {
+		return nil, err
+	}
 
 	var schConflicts []SchemaConflict
 	for _, tblName := range tblNames {
@@ -261,7 +279,7 @@ func MergeRoots(
 		}
 	}
 
-	mergedRoot, err = rebuildFullTextIndexes(ctx, mergedRoot)
+	mergedRoot, err = rebuildFullTextIndexes(ctx, mergedRoot, ourRoot)
 	if err != nil {
 		return nil, err
 	}
@@ -271,7 +289,11 @@ func MergeRoots(
 		return nil, err
 	}
 	if len(conflicts) > 0 {
-		return nil, fmt.Errorf("foreign key conflicts")
+		descs := make([]string, len(conflicts))
+		for i, c := range conflicts {
+			descs[i] = c.String()
+		}
+		return nil, fmt.Errorf("foreign key conflicts: %s", strings.Join(descs, "; "))
 	}
 
 	mergedRoot, err = mergedRoot.PutForeignKeyCollection(ctx, mergedFKColl)
@@ -0,0 +1,285 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+const (
+	conflictMarkerOurs   = "<<<<<<< ours\n"
+	conflictMarkerSep    = "=======\n"
+	conflictMarkerTheirs = ">>>>>>> theirs\n"
+
+	// maxLineMatchCells bounds the size of the table used to match the changed lines of two versions of a document.
+	// Documents with more changes than that are merged as a single conflicting chunk.
+	maxLineMatchCells = 1 << 24
+)
+
+// docMerger merges concurrent changes to a document in the dolt_docs table line by line, the way text files are
+// merged, rather than reporting any two changes to the same document as a conflict.
+type docMerger struct {
+	vm        *valueMerger
+	textIdx   int
+	rightDesc val.TupleDesc
+	baseDesc  val.TupleDesc
+	ns        tree.NodeStore
+}
+
+// newDocMerger returns a docMerger for |tm|, or nil if |tm| isn't merging the dolt_docs table.
+func newDocMerger(tm *TableMerger, vm *valueMerger, finalSch schema.Schema) *docMerger {
+	if tm.name != doltdb.DocTableName || vm.keyless || !vm.leftMapping.IsIdentityMapping() {
+		return nil
+	}
+
+	textIdx := -1
+	for i, col := range finalSch.GetNonPKCols().GetColumns() {
+		if col.Name == doltdb.DocTextColumnName {
+			textIdx = i
+		}
+	}
+	if textIdx < 0 || vm.rightMapping[textIdx] < 0 || vm.baseMapping[textIdx] < 0 {
+		return nil
+	}
+
+	return &docMerger{
+		vm:        vm,
+		textIdx:   textIdx,
+		rightDesc: tm.rightSch.GetValueDescriptor(),
+		baseDesc:  tm.ancSch.GetValueDescriptor(),
+		ns:        tm.ns,
+	}
+}
+
+// merge merges the versions of the document in |diff|, a conflicting change to a row of dolt_docs. It returns the
+// merged row and whether the document merged cleanly. Lines changed differently on each side are surrounded by
+// conflict markers in the merged row. A nil row is returned for conflicts that can't be merged as text, like a
+// document deleted on one side and changed on the other.
+func (m *docMerger) merge(ctx context.Context, diff tree.ThreeWayDiff) (val.Tuple, bool, error) {
+	if diff.Op != tree.DiffOpDivergentModifyConflict || diff.Base == nil || diff.Left == nil || diff.Right == nil {
+		return nil, false, nil
+	}
+
+	left, err := m.text(ctx, m.vm.vD, m.textIdx, diff.Left)
+	if err != nil {
+		return nil, false, err
+	}
+	right, err := m.text(ctx, m.rightDesc, m.vm.rightMapping[m.textIdx], diff.Right)
+	if err != nil {
+		return nil, false, err
+	}
+	base, err := m.text(ctx, m.baseDesc, m.vm.baseMapping[m.textIdx], diff.Base)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Any other columns changed differently on each side are still a conflict
+	for i := 0; i < m.vm.numCols; i++ {
+		if i == m.textIdx {
+			continue
+		}
+		if _, isConflict := m.vm.processColumn(i, diff.Left, diff.Right, diff.Base); isConflict {
+			return nil, false, nil
+		}
+	}
+
+	merged, clean := mergeText(base, left, right)
+
+	tb := val.NewTupleBuilder(m.vm.vD)
+	for i := 0; i < m.vm.numCols; i++ {
+		if i == m.textIdx {
+			err = index.PutField(ctx, m.ns, tb, i, merged)
+			if err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+		v, _ := m.vm.processColumn(i, diff.Left, diff.Right, diff.Base)
+		tb.PutRaw(i, v)
+	}
+	return tb.Build(m.vm.syncPool), clean, nil
+}
+
+func (m *docMerger) text(ctx context.Context, desc val.TupleDesc, i int, tup val.Tuple) (string, error) {
+	v, err := index.GetField(ctx, desc, i, tup, m.ns)
+	if err != nil || v == nil {
+		return "", err
+	}
+	s, _ := v.(string)
+	return s, nil
+}
+
+// mergeText merges the changes made to |base| in |left| and |right| line by line, returning the merged text and
+// whether it merged cleanly. Chunks of lines changed differently on each side are written with both versions
+// between conflict markers, with the |left| version first.
+func mergeText(base, left, right string) (string, bool) {
+	if left == right || right == base {
+		return left, true
+	} else if left == base {
+		return right, true
+	}
+
+	b, l, r := splitLines(base), splitLines(left), splitLines(right)
+	leftMatches, okLeft := matchLines(b, l)
+	rightMatches, okRight := matchLines(b, r)
+	if !okLeft || !okRight {
+		var sb strings.Builder
+		writeConflict(&sb, l, r)
+		return sb.String(), false
+	}
+
+	var sb strings.Builder
+	clean := true
+	bi, li, ri := 0, 0, 0
+	for {
+		// Find the next base line that's unchanged on both sides. Everything before it is a chunk that changed on
+		// at least one side.
+		k := bi
+		for k < len(b) && (leftMatches[k] < 0 || rightMatches[k] < 0) {
+			k++
+		}
+		le, re := len(l), len(r)
+		if k < len(b) {
+			le, re = leftMatches[k], rightMatches[k]
+		}
+
+		baseChunk, leftChunk, rightChunk := b[bi:k], l[li:le], r[ri:re]
+		switch {
+		case linesEqual(leftChunk, rightChunk), linesEqual(rightChunk, baseChunk):
+			writeLines(&sb, leftChunk)
+		case linesEqual(leftChunk, baseChunk):
+			writeLines(&sb, rightChunk)
+		default:
+			writeConflict(&sb, leftChunk, rightChunk)
+			clean = false
+		}
+
+		if k == len(b) {
+			break
+		}
+		sb.WriteString(b[k])
+		bi, li, ri = k+1, le+1, re+1
+	}
+
+	return sb.String(), clean
+}
+
+// matchLines matches the lines of |a| and |b| that are part of their longest common subsequence, returning the
+// index of the line in |b| matched to each line of |a|, or -1 for lines that aren't matched. It returns false if the
+// documents are too different to match efficiently.
+func matchLines(a, b []string) ([]int, bool) {
+	matches := make([]int, len(a))
+	for i := range matches {
+		matches[i] = -1
+	}
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		matches[prefix] = prefix
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		matches[len(a)-1-suffix] = len(b) - 1 - suffix
+		suffix++
+	}
+
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(a)*len(b) > maxLineMatchCells {
+		return nil, false
+	}
+
+	// lengths[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lengths := make([][]int32, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		if a[i] == b[j] {
+			matches[prefix+i] = prefix + j
+			i++
+			j++
+		} else if lengths[i+1][j] >= lengths[i][j+1] {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return matches, true
+}
+
+// splitLines splits |s| into lines, keeping the line endings.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func linesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeLines(sb *strings.Builder, lines []string) {
+	for _, line := range lines {
+		sb.WriteString(line)
+	}
+}
+
+func writeConflict(sb *strings.Builder, ours, theirs []string) {
+	sb.WriteString(conflictMarkerOurs)
+	writeConflictSide(sb, ours)
+	sb.WriteString(conflictMarkerSep)
+	writeConflictSide(sb, theirs)
+	sb.WriteString(conflictMarkerTheirs)
+}
+
+// writeConflictSide writes one side of a conflict, ending it with a newline so the marker after it is on its own
+// line.
+func writeConflictSide(sb *strings.Builder, lines []string) {
+	writeLines(sb, lines)
+	if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		sb.WriteString("\n")
+	}
+}
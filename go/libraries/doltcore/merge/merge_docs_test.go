@@ -0,0 +1,83 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeText(t *testing.T) {
+	tests := []struct {
+		name                string
+		base, left, right   string
+		expected            string
+		expectedConflicting bool
+	}{
+		{
+			name:     "only right changed",
+			base:     "a\nb\nc\n",
+			left:     "a\nb\nc\n",
+			right:    "a\nB\nc\n",
+			expected: "a\nB\nc\n",
+		},
+		{
+			name:     "different lines changed",
+			base:     "a\nb\nc\nd\n",
+			left:     "A\nb\nc\nd\n",
+			right:    "a\nb\nc\nD\n",
+			expected: "A\nb\nc\nD\n",
+		},
+		{
+			name:     "same change on both sides",
+			base:     "a\nb\nc\n",
+			left:     "x\nb\nc\n",
+			right:    "x\nb\nC\n",
+			expected: "x\nb\nC\n",
+		},
+		{
+			name:     "line deleted on one side",
+			base:     "a\nb\nc\n",
+			left:     "b\nc\n",
+			right:    "a\nb\nC\n",
+			expected: "b\nC\n",
+		},
+		{
+			name:                "same line changed differently",
+			base:                "a\nb\nc\n",
+			left:                "a\nX\nc\n",
+			right:               "a\nY\nc\n",
+			expected:            "a\n<<<<<<< ours\nX\n=======\nY\n>>>>>>> theirs\nc\n",
+			expectedConflicting: true,
+		},
+		{
+			name:                "lines added at the end on both sides",
+			base:                "a\n",
+			left:                "a\nb",
+			right:               "a\nc",
+			expected:            "a\n<<<<<<< ours\nb\n=======\nc\n>>>>>>> theirs\n",
+			expectedConflicting: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, clean := mergeText(test.base, test.left, test.right)
+			assert.Equal(t, test.expected, merged)
+			assert.Equal(t, !test.expectedConflicting, clean)
+		})
+	}
+}
@@ -176,6 +176,9 @@ func mergeProllyTableData(ctx *sql.Context, tm *TableMerger, finalSch schema.Sch
 		return nil, nil, err
 	}
 
+	docs := newDocMerger(tm, valueMerger, finalSch)
+	strategy := newStrategyResolver(tm, valueMerger, finalSch)
+
 	s := &MergeStats{
 		Operation: TableModified,
 	}
@@ -186,6 +189,18 @@ func mergeProllyTableData(ctx *sql.Context, tm *TableMerger, finalSch schema.Sch
 		} else if err != nil {
 			return nil, nil, err
 		}
+
+		if strategy != nil && (diff.Op == tree.DiffOpDivergentModifyConflict || diff.Op == tree.DiffOpDivergentDeleteConflict) {
+			var keepOurs bool
+			diff, keepOurs, err = strategy.resolve(ctx, diff)
+			if err != nil {
+				return nil, nil, err
+			}
+			if keepOurs {
+				continue
+			}
+		}
+
 		cnt, err := uniq.validateDiff(ctx, diff)
 		if err != nil {
 			return nil, nil, err
@@ -210,7 +225,31 @@ func mergeProllyTableData(ctx *sql.Context, tm *TableMerger, finalSch schema.Sch
 		switch diff.Op {
 		case tree.DiffOpDivergentModifyConflict, tree.DiffOpDivergentDeleteConflict:
 			// In this case, a modification or delete was made to one side, and a conflicting delete or modification
-			// was made to the other side, so these cannot be automatically resolved.
+			// was made to the other side, so these cannot be automatically resolved. The exception is documents in
+			// dolt_docs, which are merged line by line like text files.
+			if docs != nil {
+				merged, clean, err := docs.merge(ctx, diff)
+				if err != nil {
+					return nil, nil, err
+				}
+				if merged != nil {
+					// A document that doesn't merge cleanly keeps its conflict markers in the working set, in
+					// addition to the conflict recorded below.
+					resolved := diff
+					resolved.Op = tree.DiffOpDivergentModifyResolved
+					resolved.Merged = merged
+					if err = pri.merge(ctx, resolved, nil); err != nil {
+						return nil, nil, err
+					}
+					if err = sec.merge(ctx, resolved, nil); err != nil {
+						return nil, nil, err
+					}
+					if clean {
+						s.Modifications++
+						break
+					}
+				}
+			}
 			s.DataConflicts++
 			err = conflicts.merge(ctx, diff, nil)
 			if err != nil {
@@ -37,6 +37,9 @@ type MergeOpts struct {
 	// KeepSchemaConflicts if schema conflicts should be
 	// stored, otherwise we end the merge with an error.
 	KeepSchemaConflicts bool
+	// NoRenames merges tables and columns renamed on one side of the merge as if they had been dropped and recreated,
+	// rather than matching them to their old names on the other side.
+	NoRenames bool
 }
 
 type TableMerger struct {
@@ -53,6 +56,9 @@ type TableMerger struct {
 	rightSrc    doltdb.Rootish
 	ancestorSrc doltdb.Rootish
 
+	// strategy is how rows changed differently on each side are resolved, as declared in dolt_merge_strategies
+	strategy doltdb.MergeStrategy
+
 	vrw types.ValueReadWriter
 	ns  tree.NodeStore
 }
@@ -84,6 +90,8 @@ type RootMerger struct {
 	rightSrc doltdb.Rootish
 	ancSrc   doltdb.Rootish
 
+	strategies doltdb.MergeStrategies
+
 	vrw types.ValueReadWriter
 	ns  tree.NodeStore
 }
@@ -170,6 +178,11 @@ func (rm *RootMerger) makeTableMerger(ctx context.Context, tblName string) (*Tab
 	var ok bool
 	var err error
 
+	tm.strategy, err = rm.strategies.StrategyForTable(tblName)
+	if err != nil {
+		return nil, err
+	}
+
 	tm.leftTbl, ok, err = rm.left.GetTable(ctx, tblName)
 	if err != nil {
 		return nil, err
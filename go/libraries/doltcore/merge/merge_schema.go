@@ -18,7 +18,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -35,12 +34,10 @@ type conflictKind byte
 const (
 	TagCollision conflictKind = iota
 	NameCollision
-	ColumnCheckCollision
 	InvalidCheckCollision
 	DeletedCheckCollision
-	// DuplicateIndexColumnSet represent a schema conflict where multiple indexes cover the same set of columns, and
-	// we're unable to accurately match them up on each side of the merge, so the user has to manually resolve.
-	DuplicateIndexColumnSet
+	// DeletedIndexCollision represents an index that was deleted on one side of the merge and changed on the other.
+	DeletedIndexCollision
 )
 
 var ErrUnmergeableNewColumn = errorkinds.NewKind("Unable to merge new column `%s` in table `%s` because it is not-nullable and has no default value, so existing rows can't be updated automatically. To complete this merge, either manually add this new column to the target branch of the merge and update any existing rows, or change the column's definition on the other branch of the merge so that it is nullable or has a default value.")
@@ -115,18 +112,62 @@ type IdxConflict struct {
 
 func (c IdxConflict) String() string {
 	switch c.Kind {
-	case DuplicateIndexColumnSet:
-		return fmt.Sprintf("multiple indexes covering the same column set cannot be merged: '%s' and '%s'", c.Ours.Name(), c.Theirs.Name())
+	case NameCollision:
+		return fmt.Sprintf("two indexes with the name '%s' but different definitions: our %s and their %s", c.Ours.Name(), indexDefinition(c.Ours), indexDefinition(c.Theirs))
+	case DeletedIndexCollision:
+		if c.Theirs == nil {
+			return fmt.Sprintf("index '%s' was deleted in theirs but modified in ours: %s", c.Ours.Name(), indexDefinition(c.Ours))
+		}
+		return fmt.Sprintf("index '%s' was deleted in ours but modified in theirs: %s", c.Theirs.Name(), indexDefinition(c.Theirs))
 	default:
 		return ""
 	}
 }
 
+// indexDefinition returns the definition of |idx| as it appears in a CREATE TABLE statement.
+func indexDefinition(idx schema.Index) string {
+	var b strings.Builder
+	switch {
+	case idx.IsUnique():
+		b.WriteString("UNIQUE ")
+	case idx.IsSpatial():
+		b.WriteString("SPATIAL ")
+	case idx.IsFullText():
+		b.WriteString("FULLTEXT ")
+	}
+	b.WriteString(fmt.Sprintf("KEY `%s` (", idx.Name()))
+	prefixes := idx.PrefixLengths()
+	for i, col := range idx.ColumnNames() {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(fmt.Sprintf("`%s`", col))
+		if i < len(prefixes) && prefixes[i] > 0 {
+			b.WriteString(fmt.Sprintf("(%d)", prefixes[i]))
+		}
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
 type FKConflict struct {
 	Kind         conflictKind
 	Ours, Theirs doltdb.ForeignKey
 }
 
+func (c FKConflict) String() string {
+	switch c.Kind {
+	case NameCollision:
+		return fmt.Sprintf("two foreign keys with the name '%s' but different definitions", c.Ours.Name)
+	case TagCollision:
+		if c.Ours.Name == c.Theirs.Name {
+			return fmt.Sprintf("foreign key '%s' on table '%s' was changed differently on each side of the merge", c.Ours.Name, c.Ours.TableName)
+		}
+		return fmt.Sprintf("our foreign key '%s' and their foreign key '%s' on table '%s' reference the same columns but have different definitions", c.Ours.Name, c.Theirs.Name, c.Ours.TableName)
+	}
+	return ""
+}
+
 type ChkConflict struct {
 	Kind         conflictKind
 	Ours, Theirs schema.Check
@@ -135,9 +176,7 @@ type ChkConflict struct {
 func (c ChkConflict) String() string {
 	switch c.Kind {
 	case NameCollision:
-		return fmt.Sprintf("two checks with the name '%s' but different definitions", c.Ours.Name())
-	case ColumnCheckCollision:
-		return fmt.Sprintf("our check '%s' and their check '%s' both reference the same column(s)", c.Ours.Name(), c.Theirs.Name())
+		return fmt.Sprintf("two checks with the name '%s' but different definitions: our %s and their %s", c.Ours.Name(), c.Ours.Expression(), c.Theirs.Expression())
 	case InvalidCheckCollision:
 		return fmt.Sprintf("check '%s' references a column that will be deleted after merge", c.Ours.Name())
 	case DeletedCheckCollision:
@@ -176,7 +215,7 @@ func SchemaMerge(ctx context.Context, format *storetypes.NomsBinFormat, ourSch,
 		return nil, sc, tableRewrite, nil
 	}
 
-	var mergedIdxs schema.IndexCollection
+	var mergedIdxs []schema.Index
 	mergedIdxs, sc.IdxConflicts = mergeIndexes(mergedCC, ourSch, theirSch, ancSch)
 	if len(sc.IdxConflicts) > 0 {
 		return nil, sc, tableRewrite, nil
@@ -198,10 +237,20 @@ func SchemaMerge(ctx context.Context, format *storetypes.NomsBinFormat, ourSch,
 		return nil, sc, false, err
 	}
 
-	_ = mergedIdxs.Iter(func(index schema.Index) (stop bool, err error) {
-		sch.Indexes().AddIndex(index)
-		return false, nil
-	})
+	// indexes are added without replacing other indexes over the same columns, which the merge may have kept
+	for _, idx := range mergedIdxs {
+		_, err = sch.Indexes().UnsafeAddIndexByColTags(idx.Name(), idx.IndexedColumnTags(), idx.PrefixLengths(), schema.IndexProperties{
+			IsUnique:           idx.IsUnique(),
+			IsSpatial:          idx.IsSpatial(),
+			IsFullText:         idx.IsFullText(),
+			IsUserDefined:      idx.IsUserDefined(),
+			Comment:            idx.Comment(),
+			FullTextProperties: idx.FullTextProperties(),
+		})
+		if err != nil {
+			return nil, sc, false, err
+		}
+	}
 
 	// Merge checks
 	var mergedChks []schema.Check
@@ -415,9 +464,24 @@ func mergeColumns(tblName string, format *storetypes.NomsBinFormat, ourCC, their
 				oursChanged := !anc.Equals(*ours)
 				theirsChanged := !anc.Equals(*theirs)
 				if oursChanged && theirsChanged {
+					// If one side only renamed the column, the other side's changes are merged into the renamed column.
 					// If both columns changed in the same way, the modifications converge, so accept the column.
 					// If not, don't report a conflict, since this case is already handled in checkForColumnConflicts.
-					if ours.Equals(*theirs) {
+					if merged, from, renamed := mergeRenamedColumn(*anc, *ours, *theirs); renamed {
+						compatible, rewrite := compatChecker.IsTypeChangeCompatible(from.TypeInfo, merged.TypeInfo)
+						if rewrite {
+							tableRewrite = true
+						}
+						if compatible {
+							mergedColumns = append(mergedColumns, merged)
+						} else {
+							conflicts = append(conflicts, ColConflict{
+								Kind:   NameCollision,
+								Ours:   *ours,
+								Theirs: *theirs,
+							})
+						}
+					} else if ours.Equals(*theirs) {
 						mergedColumns = append(mergedColumns, *theirs)
 					}
 				} else if theirsChanged {
@@ -475,6 +539,32 @@ func mergeColumns(tblName string, format *storetypes.NomsBinFormat, ourCC, their
 	return schema.NewColCollection(mergedColumns...), nil, tableRewrite, nil
 }
 
+// mergeRenamedColumn merges the changes to a column that was only renamed on one side of a merge, relative to |anc|,
+// and changed in other ways on the other side. It returns the merged column, which is the other side's column with the
+// new name, along with the renamed side's column, whose type the merged column's type replaces. If the column wasn't
+// changed that way, false is returned.
+func mergeRenamedColumn(anc, ours, theirs schema.Column) (merged, from schema.Column, ok bool) {
+	onlyRenamed := func(col schema.Column) bool {
+		if col.Name == anc.Name {
+			return false
+		}
+		col.Name = anc.Name
+		return col.Equals(anc)
+	}
+
+	switch {
+	case onlyRenamed(ours) && theirs.Name == anc.Name && !theirs.Equals(anc):
+		merged = theirs
+		merged.Name = ours.Name
+		return merged, ours, true
+	case onlyRenamed(theirs) && ours.Name == anc.Name && !ours.Equals(anc):
+		merged = ours
+		merged.Name = theirs.Name
+		return merged, theirs, true
+	}
+	return schema.Column{}, schema.Column{}, false
+}
+
 // checkForColumnConflicts iterates over |mergedColumns|, checks for duplicate column names or column tags, and returns
 // a slice of ColConflicts for any conflicts found.
 func checkForColumnConflicts(mergedColumns []schema.Column) []ColConflict {
@@ -536,7 +626,11 @@ func checkSchemaConflicts(columnMappings columnMappings) ([]ColConflict, error)
 				}
 			case theirs != nil && anc != nil:
 				// Column exists on their side and in ancestor
-				// If the column differs from the ancestor on both sides, then we have a conflict
+				// If the column differs from the ancestor on both sides, then we have a conflict, unless one side only
+				// renamed the column
+				if _, _, renamed := mergeRenamedColumn(*anc, *ours, *theirs); renamed {
+					continue
+				}
 				if !anc.Equals(*ours) && !anc.Equals(*theirs) {
 					conflicts = append(conflicts, ColConflict{
 						Kind:   TagCollision,
@@ -687,166 +781,80 @@ func mapColumns(ourCC, theirCC, ancCC *schema.ColCollection) (columnMappings, er
 }
 
 // assumes indexes are unique over their column sets
-func mergeIndexes(mergedCC *schema.ColCollection, ourSch, theirSch, ancSch schema.Schema) (merged schema.IndexCollection, conflicts []IdxConflict) {
-	merged, conflicts = indexesInCommon(mergedCC, ourSch.Indexes(), theirSch.Indexes(), ancSch.Indexes())
-
-	ourNewIdxs := indexCollSetDifference(ourSch.Indexes(), ancSch.Indexes(), mergedCC)
-	theirNewIdxs := indexCollSetDifference(theirSch.Indexes(), ancSch.Indexes(), mergedCC)
-
-	// check for conflicts between indexes added on each branch since the ancestor
-	_ = ourNewIdxs.Iter(func(ourIdx schema.Index) (stop bool, err error) {
-		theirIdx, ok := theirNewIdxs.GetByNameCaseInsensitive(ourIdx.Name())
-		// If both indexes are exactly equal then there isn't a conflict
-		if ok && !ourIdx.DeepEquals(theirIdx) {
+// mergeIndexes merges the secondary indexes of |ourSch| and |theirSch|. Indexes are matched by name on each side of
+// the merge, so the indexes added on each side are all kept, even if they cover the same columns as another index.
+// An index changed or dropped on only one side takes that side's version. Indexes with the same name but different
+// definitions on each side, or that were dropped on one side and changed on the other, are conflicts. Indexes over
+// columns dropped by the merge are dropped.
+func mergeIndexes(mergedCC *schema.ColCollection, ourSch, theirSch, ancSch schema.Schema) (merged []schema.Index, conflicts []IdxConflict) {
+	ours, theirs, anc := ourSch.Indexes(), theirSch.Indexes(), ancSch.Indexes()
+
+	mergeIndex := func(name string) {
+		ourIdx, _ := ours.GetByNameCaseInsensitive(name)
+		theirIdx, _ := theirs.GetByNameCaseInsensitive(name)
+		ancIdx, _ := anc.GetByNameCaseInsensitive(name)
+
+		var idx schema.Index
+		switch {
+		case indexesEqual(ourIdx, theirIdx):
+			idx = ourIdx
+		case indexesEqual(ancIdx, theirIdx):
+			// index changed or dropped on our branch
+			idx = ourIdx
+		case indexesEqual(ancIdx, ourIdx):
+			// index changed or dropped on their branch
+			idx = theirIdx
+		case ourIdx == nil || theirIdx == nil:
 			conflicts = append(conflicts, IdxConflict{
-				Kind:   NameCollision,
+				Kind:   DeletedIndexCollision,
 				Ours:   ourIdx,
 				Theirs: theirIdx,
 			})
-		}
-		return false, nil
-	})
-
-	merged.AddIndex(ourNewIdxs.AllIndexes()...)
-	merged.AddIndex(theirNewIdxs.AllIndexes()...)
-
-	return merged, conflicts
-}
-
-func indexesInCommon(mergedCC *schema.ColCollection, ours, theirs, anc schema.IndexCollection) (common schema.IndexCollection, conflicts []IdxConflict) {
-	common = schema.NewIndexCollection(mergedCC, nil)
-	_ = ours.Iter(func(ourIdx schema.Index) (stop bool, err error) {
-		idxTags := ourIdx.IndexedColumnTags()
-		for _, t := range idxTags {
-			// if column doesn't exist anymore, drop index
-			// however, it shouldn't be possible for an index
-			// over a dropped column to exist in the intersection
-			if _, ok := mergedCC.GetByTag(t); !ok {
-				return false, nil
-			}
-		}
-
-		// Check that there aren't multiple indexes covering the same columns on "theirs"
-		theirIdx, idxConflict := findIndexInCollectionByTags(ourIdx, theirs)
-		if theirIdx == nil && idxConflict == nil {
-			return false, nil
-		} else if idxConflict != nil {
-			conflicts = append(conflicts, *idxConflict)
-			return true, nil
-		}
-
-		// Check that there aren't multiple indexes covering the same columns on "ours"
-		_, idxConflict = findIndexInCollectionByTags(ourIdx, ours)
-		if idxConflict != nil {
-			conflicts = append(conflicts, *idxConflict)
-			return true, nil
-		}
-
-		if ourIdx.Equals(theirIdx) {
-			common.AddIndex(ourIdx)
-			return false, nil
-		}
-
-		ancIdx, ok := anc.GetIndexByTags(idxTags...)
-
-		if !ok {
-			// index added on our branch and their branch with different defs, conflict
+			return
+		default:
+			// index added or changed on our branch and their branch with different defs
 			conflicts = append(conflicts, IdxConflict{
-				Kind:   TagCollision,
+				Kind:   NameCollision,
 				Ours:   ourIdx,
 				Theirs: theirIdx,
 			})
-			return false, nil
+			return
 		}
-
-		if ancIdx.Equals(theirIdx) {
-			// index modified on our branch
-			idx, ok := common.GetByNameCaseInsensitive(ourIdx.Name())
-			if ok {
-				conflicts = append(conflicts, IdxConflict{
-					Kind:   NameCollision,
-					Ours:   ourIdx,
-					Theirs: idx,
-				})
-			} else {
-				common.AddIndex(ourIdx)
-			}
-			return false, nil
+		if idx == nil {
+			return
 		}
 
-		if ancIdx.Equals(ourIdx) {
-			// index modified on their branch
-			idx, ok := common.GetByNameCaseInsensitive(theirIdx.Name())
-			if ok {
-				conflicts = append(conflicts, IdxConflict{
-					Kind:   NameCollision,
-					Ours:   idx,
-					Theirs: theirIdx,
-				})
-			} else {
-				common.AddIndex(theirIdx)
+		for _, t := range idx.IndexedColumnTags() {
+			// if column doesn't exist anymore, drop index
+			if _, ok := mergedCC.GetByTag(t); !ok {
+				return
 			}
-			return false, nil
 		}
+		merged = append(merged, idx)
+	}
 
-		// index modified on our branch and their branch, conflict
-		conflicts = append(conflicts, IdxConflict{
-			Kind:   TagCollision,
-			Ours:   ourIdx,
-			Theirs: theirIdx,
-		})
-		return false, nil
-	})
-	return common, conflicts
-}
-
-// findIndexInCollectionByTags searches for a single index in |idxColl| that matches the same tags |idx| covers. If a
-// single matching index is found, then it is returned, along with no IdxConflict. If no matching index is found, then
-// nil is returned for both params. If multiple indexes are found that cover the same set of columns, a nil Index is
-// returned along with an IdxConflict that describes the conflict.
-//
-// Dolt allows you to add multiple indexes that cover the same set of columns, but in this situation, we aren't able
-// to always accurately match up the indexes between ours/theirs/anc in a merge. The set of column tags an
-// index covers was being used as a unique ID for the index, but as our index support has grown and in order to match
-// MySQL's behavior, this isn't guaranteed to be a unique identifier anymore.
-func findIndexInCollectionByTags(idx schema.Index, idxColl schema.IndexCollection) (schema.Index, *IdxConflict) {
-	theirIdxs := idxColl.GetIndexesByTags(idx.IndexedColumnTags()...)
-	switch len(theirIdxs) {
-	case 0:
-		return nil, nil
-	case 1:
-		return theirIdxs[0], nil
-	default:
-		sort.Slice(theirIdxs, func(i, j int) bool {
-			return theirIdxs[i].Name() < theirIdxs[j].Name()
-		})
-
-		return nil, &IdxConflict{
-			Kind:   DuplicateIndexColumnSet,
-			Ours:   theirIdxs[0],
-			Theirs: theirIdxs[1],
+	for _, idx := range ours.AllIndexes() {
+		mergeIndex(idx.Name())
+	}
+	for _, idx := range theirs.AllIndexes() {
+		if !ours.Contains(idx.Name()) {
+			mergeIndex(idx.Name())
 		}
 	}
-}
-
-func indexCollSetDifference(left, right schema.IndexCollection, cc *schema.ColCollection) (d schema.IndexCollection) {
-	d = schema.NewIndexCollection(cc, nil)
-	_ = left.Iter(func(idx schema.Index) (stop bool, err error) {
-		idxTags := idx.IndexedColumnTags()
-		for _, t := range idxTags {
-			// if column doesn't exist anymore, drop index
-			if _, ok := cc.GetByTag(t); !ok {
-				return false, nil
-			}
+	for _, idx := range anc.AllIndexes() {
+		if !ours.Contains(idx.Name()) && !theirs.Contains(idx.Name()) {
+			mergeIndex(idx.Name())
 		}
+	}
+	return merged, conflicts
+}
 
-		_, ok := right.GetIndexByTags(idxTags...)
-		if !ok {
-			d.AddIndex(idx)
-		}
-		return false, nil
-	})
-	return d
+// indexesEqual returns whether |left| and |right| are both nil, or are equal indexes.
+func indexesEqual(left, right schema.Index) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	return left.Equals(right)
 }
 
 func foreignKeysInCommon(ourFKs, theirFKs, ancFKs *doltdb.ForeignKeyCollection, ancSchs map[string]schema.Schema) (common *doltdb.ForeignKeyCollection, conflicts []FKConflict, err error) {
@@ -871,6 +879,7 @@ func foreignKeysInCommon(ourFKs, theirFKs, ancFKs *doltdb.ForeignKeyCollection,
 				Ours:   ours,
 				Theirs: theirs,
 			})
+			return false, nil
 		}
 
 		if theirs.EqualDefs(anc) {
@@ -1127,97 +1136,18 @@ func mergeTableCollation(_ context.Context, tblName string, ancSch, ourSch, thei
 
 // mergeChecks attempts to combine ourChks, theirChks, and ancChks into a single collection, or gathers the conflicts
 func mergeChecks(ctx context.Context, ourChks, theirChks, ancChks schema.CheckCollection) ([]schema.Check, []ChkConflict, error) {
-	// Handles modifications
+	// Handles modifications, and CHECKs added on both sides with the same name
 	common, conflicts := checksInCommon(ourChks.AllChecks(), theirChks.AllChecks(), ancChks.AllChecks())
 
-	// Get all new checks
-	ourNewChks := chkCollectionSetDifference(ourChks.AllChecks(), ancChks.AllChecks())
-	theirNewChks := chkCollectionSetDifference(theirChks.AllChecks(), ancChks.AllChecks())
-
-	// Create map for fast lookup
-	theirNewChksMap := make(map[string]schema.Check)
-	for _, chk := range theirNewChks {
-		theirNewChksMap[chk.Name()] = chk
-	}
-
-	// Compare CHECKs with the same name
-	for _, ourChk := range ourNewChks {
-		theirChk, ok := theirNewChksMap[ourChk.Name()]
-		// CONFLICT: our and their CHECK have the same name, but different definitions
-		if ok && ourChk != theirChk {
-			conflicts = append(conflicts, ChkConflict{
-				Kind:   NameCollision,
-				Ours:   ourChk,
-				Theirs: theirChk,
-			})
-		}
-	}
-
 	// There are conflicts, don't merge
 	if len(conflicts) > 0 {
 		return nil, conflicts, nil
 	}
 
-	// Create a map from each column to any CHECKs that reference that column
-	theirNewChkColsMap := make(map[string]map[schema.Check]bool)
-	for _, chk := range theirNewChks {
-		// Extract columns referenced by CHECK
-		chkDef := sql.CheckDefinition{
-			Name:            chk.Name(),
-			CheckExpression: chk.Expression(),
-			Enforced:        chk.Enforced(),
-		}
-		colNames, err := ColumnsFromCheckDefinition(sql.NewContext(ctx), &chkDef)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		// Mark that col as referenced by CHECK
-		for _, col := range colNames {
-			if _, ok := theirNewChkColsMap[col]; !ok {
-				theirNewChkColsMap[col] = make(map[schema.Check]bool)
-			}
-			theirNewChkColsMap[col][chk] = true
-		}
-	}
-
-	// Look for overlapping columns between our new CHECKs and their new CHECKs
-	for _, ourChk := range ourNewChks {
-		// Extract columns referenced by CHECK
-		chkDef := sql.CheckDefinition{
-			Name:            ourChk.Name(),
-			CheckExpression: ourChk.Expression(),
-			Enforced:        ourChk.Enforced(),
-		}
-		colNames, err := ColumnsFromCheckDefinition(sql.NewContext(ctx), &chkDef)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		// TODO: redundant for checks that are defined exactly the same
-		for _, col := range colNames {
-			// See if this column is referenced in their new CHECKs
-			if _, ok := theirNewChkColsMap[col]; ok {
-				// CONFLICT: our and their CHECK reference the same column and are not the same CHECK
-				if _, ok := theirNewChkColsMap[col][ourChk]; !ok {
-					for k := range theirNewChkColsMap[col] {
-						conflicts = append(conflicts, ChkConflict{
-							Kind:   ColumnCheckCollision,
-							Ours:   ourChk,
-							Theirs: k,
-						})
-					}
-					// Finding one column collision is enough
-					break
-				}
-			}
-		}
-	}
-
-	// There are conflicts, don't merge
-	if len(conflicts) > 0 {
-		return nil, conflicts, nil
-	}
+	// Get all new checks. New CHECKs with different names are all kept, even if they reference the same columns,
+	// since a row has to satisfy each of them.
+	ourNewChks := chkCollectionSetDifference(ourChks.AllChecks(), ancChks.AllChecks())
+	theirNewChks := chkCollectionSetDifference(theirChks.AllChecks(), ancChks.AllChecks())
 
 	// CONFLICT: deleted constraint in ours that is modified in theirs
 	ourDeletedChks := chkCollectionSetDifference(ancChks.AllChecks(), ourChks.AllChecks())
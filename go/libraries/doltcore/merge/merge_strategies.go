@@ -0,0 +1,94 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+)
+
+// strategyResolver resolves the conflicting changes to the rows of a table as the table's strategy in
+// dolt_merge_strategies declares, rather than recording them as conflicts.
+type strategyResolver struct {
+	strategy doltdb.MergeStrategy
+	tm       *TableMerger
+	vm       *valueMerger
+	finalSch schema.Schema
+}
+
+// newStrategyResolver returns a strategyResolver for |tm|, or nil if its conflicts are left to the user.
+func newStrategyResolver(tm *TableMerger, vm *valueMerger, finalSch schema.Schema) *strategyResolver {
+	switch tm.strategy {
+	case doltdb.MergeStrategyOurs, doltdb.MergeStrategyTheirs, doltdb.MergeStrategyUnion:
+		return &strategyResolver{strategy: tm.strategy, tm: tm, vm: vm, finalSch: finalSch}
+	default:
+		return nil
+	}
+}
+
+// resolve resolves the conflicting change |diff|. If their version of the row is taken, it returns the diff that
+// applies it to our side. If our version is kept, there's nothing to apply, and |keepOurs| is true. A conflict the
+// strategy doesn't resolve is returned as it is.
+func (r *strategyResolver) resolve(ctx *sql.Context, diff tree.ThreeWayDiff) (resolved tree.ThreeWayDiff, keepOurs bool, err error) {
+	switch r.strategy {
+	case doltdb.MergeStrategyOurs:
+		return diff, true, nil
+	case doltdb.MergeStrategyTheirs:
+		return r.takeTheirs(ctx, diff)
+	case doltdb.MergeStrategyUnion:
+		if diff.Op != tree.DiffOpDivergentDeleteConflict {
+			return diff, false, nil
+		}
+		if diff.Left == nil {
+			// we deleted the row and they modified it
+			return r.takeTheirs(ctx, diff)
+		}
+		return diff, true, nil
+	default:
+		return diff, false, nil
+	}
+}
+
+func (r *strategyResolver) takeTheirs(ctx *sql.Context, diff tree.ThreeWayDiff) (tree.ThreeWayDiff, bool, error) {
+	resolved := diff
+	if diff.Right == nil {
+		// they deleted the row, which is applied as a delete of our version of it
+		resolved.Op = tree.DiffOpRightDelete
+		resolved.Base = diff.Left
+		return resolved, false, nil
+	}
+	resolved.Op = tree.DiffOpDivergentModifyResolved
+
+	if r.vm.keyless {
+		if !r.vm.rightMapping.IsIdentityMapping() {
+			return tree.ThreeWayDiff{}, false, fmt.Errorf("cannot merge keyless tables with reordered columns")
+		}
+		resolved.Merged = diff.Right
+		return resolved, false, nil
+	}
+
+	merged, err := remapTupleWithColumnDefaults(ctx, diff.Key, diff.Right, r.tm.rightSch.GetValueDescriptor(),
+		r.vm.rightMapping, r.tm, r.finalSch, r.vm.syncPool, true)
+	if err != nil {
+		return tree.ThreeWayDiff{}, false, err
+	}
+	resolved.Merged = merged
+	return resolved, false, nil
+}
@@ -0,0 +1,207 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+)
+
+// matchRenamedTables gives the tables renamed on one side of a merge their new names on the other side and in the
+// ancestor, so that the tables are merged with themselves by name, rather than merging a deleted table with a modified
+// one. A table is renamed if it shares column tags with a table of the ancestor that no longer exists under its old
+// name. Tables renamed to different names on each side are left as they are.
+func matchRenamedTables(ctx context.Context, ourRoot, theirRoot, ancRoot *doltdb.RootValue) (ours, theirs, anc *doltdb.RootValue, err error) {
+	ourRenames, err := findRenamedTables(ctx, ancRoot, ourRoot)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	theirRenames, err := findRenamedTables(ctx, ancRoot, theirRoot)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(ourRenames) == 0 && len(theirRenames) == 0 {
+		return ourRoot, theirRoot, ancRoot, nil
+	}
+
+	ours, theirs, anc = ourRoot, theirRoot, ancRoot
+	for _, oldName := range sortedKeys(ourRenames) {
+		newName := ourRenames[oldName]
+		if theirName, ok := theirRenames[oldName]; ok {
+			if theirName == newName {
+				// renamed the same way on both sides
+				if anc, err = renameTable(ctx, anc, oldName, newName); err != nil {
+					return nil, nil, nil, err
+				}
+			}
+			continue
+		}
+
+		if ok, err := canRenameTable(ctx, theirs, oldName, newName); err != nil {
+			return nil, nil, nil, err
+		} else if !ok {
+			continue
+		}
+		if theirs, err = renameTable(ctx, theirs, oldName, newName); err != nil {
+			return nil, nil, nil, err
+		}
+		if anc, err = renameTable(ctx, anc, oldName, newName); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	for _, oldName := range sortedKeys(theirRenames) {
+		if _, ok := ourRenames[oldName]; ok {
+			continue
+		}
+		newName := theirRenames[oldName]
+
+		if ok, err := canRenameTable(ctx, ours, oldName, newName); err != nil {
+			return nil, nil, nil, err
+		} else if !ok {
+			continue
+		}
+		if ours, err = renameTable(ctx, ours, oldName, newName); err != nil {
+			return nil, nil, nil, err
+		}
+		if anc, err = renameTable(ctx, anc, oldName, newName); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return ours, theirs, anc, nil
+}
+
+// findRenamedTables returns the new names of the tables of |ancRoot| that were renamed in |root|, by their old names.
+func findRenamedTables(ctx context.Context, ancRoot, root *doltdb.RootValue) (map[string]string, error) {
+	ancNames, err := ancRoot.GetTableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names, err := root.GetTableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dropped := set.NewStrSet(ancNames)
+	dropped.Remove(names...)
+	added := set.NewStrSet(names)
+	added.Remove(ancNames...)
+	if dropped.Size() == 0 || added.Size() == 0 {
+		return nil, nil
+	}
+
+	addedSchs := make(map[string]schema.Schema)
+	for _, name := range added.AsSortedSlice() {
+		if doltdb.IsFullTextTable(name) {
+			continue
+		}
+		tbl, _, err := root.GetTable(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if addedSchs[name], err = tbl.GetSchema(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	renames := make(map[string]string)
+	for _, oldName := range dropped.AsSortedSlice() {
+		if doltdb.IsFullTextTable(oldName) {
+			continue
+		}
+		tbl, _, err := ancRoot.GetTable(ctx, oldName)
+		if err != nil {
+			return nil, err
+		}
+		sch, err := tbl.GetSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, newName := range added.AsSortedSlice() {
+			if newSch, ok := addedSchs[newName]; ok && sharesColumnTags(sch, newSch) {
+				renames[oldName] = newName
+				delete(addedSchs, newName)
+				break
+			}
+		}
+	}
+	return renames, nil
+}
+
+// canRenameTable returns whether the table |oldName| of |root| can be given the name |newName|, which it can't if it
+// doesn't exist or if another table has that name.
+func canRenameTable(ctx context.Context, root *doltdb.RootValue, oldName, newName string) (bool, error) {
+	if ok, err := root.HasTable(ctx, oldName); err != nil || !ok {
+		return false, err
+	}
+	ok, err := root.HasTable(ctx, newName)
+	return !ok, err
+}
+
+// renameTable renames the table |oldName| of |root| to |newName|, along with the foreign keys that refer to it.
+func renameTable(ctx context.Context, root *doltdb.RootValue, oldName, newName string) (*doltdb.RootValue, error) {
+	root, err := root.RenameTable(ctx, oldName, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	fkc, err := root.GetForeignKeyCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fks := fkc.AllKeys()
+	renamed := false
+	for i := range fks {
+		if fks[i].TableName == oldName {
+			fks[i].TableName, renamed = newName, true
+		}
+		if fks[i].ReferencedTableName == oldName {
+			fks[i].ReferencedTableName, renamed = newName, true
+		}
+	}
+	if !renamed {
+		return root, nil
+	}
+
+	fkc, err = doltdb.NewForeignKeyCollection(fks...)
+	if err != nil {
+		return nil, err
+	}
+	return root.PutForeignKeyCollection(ctx, fkc)
+}
+
+func sharesColumnTags(sch, other schema.Schema) bool {
+	for _, tag := range sch.GetAllCols().Tags {
+		if _, ok := other.GetAllCols().GetByTag(tag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -358,7 +358,7 @@ var mergeSchemaConflictTests = []mergeSchemaConflictTest{
 		},
 	},
 	{
-		name: "index definition collision",
+		name: "indexes over the same columns with different names",
 		setup: []testCommand{
 			{commands.SqlCmd{}, []string{"-q", "create index c3_idx on test(c3);"}},
 			{commands.AddCmd{}, []string{"."}},
@@ -371,13 +371,22 @@ var mergeSchemaConflictTests = []mergeSchemaConflictTest{
 		},
 		expConflict: merge.SchemaConflict{
 			TableName: "test",
-			IdxConflicts: []merge.IdxConflict{
-				{
-					Kind:   merge.TagCollision,
-					Ours:   schema.NewIndex("c3_idx", []uint64{4696}, []uint64{4696, 3228}, nil, schema.IndexProperties{IsUserDefined: true}),
-					Theirs: schema.NewIndex("c3_index", []uint64{4696}, []uint64{4696, 3228}, nil, schema.IndexProperties{IsUserDefined: true}),
-				},
-			},
+		},
+	},
+	{
+		name: "checks over the same column with different names",
+		setup: []testCommand{
+			{commands.SqlCmd{}, []string{"-q", "alter table test add constraint chk1 check (c3 > 0);"}},
+			{commands.AddCmd{}, []string{"."}},
+			{commands.CommitCmd{}, []string{"-m", "modified branch main"}},
+			{commands.CheckoutCmd{}, []string{"other"}},
+			{commands.SqlCmd{}, []string{"-q", "alter table test add constraint chk2 check (c3 < 100);"}},
+			{commands.AddCmd{}, []string{"."}},
+			{commands.CommitCmd{}, []string{"-m", "modified branch other"}},
+			{commands.CheckoutCmd{}, []string{env.DefaultInitBranch}},
+		},
+		expConflict: merge.SchemaConflict{
+			TableName: "test",
 		},
 	},
 	{
@@ -395,11 +404,6 @@ var mergeSchemaConflictTests = []mergeSchemaConflictTest{
 		expConflict: merge.SchemaConflict{
 			TableName: "test",
 			ChkConflicts: []merge.ChkConflict{
-				{
-					Kind:   merge.TagCollision,
-					Ours:   schema.NewCheck("chk", "(c3 > 0)", true),
-					Theirs: schema.NewCheck("chk", "(c3 < 0)", true),
-				},
 				{
 					Kind:   merge.NameCollision,
 					Ours:   schema.NewCheck("chk", "(c3 > 0)", true),
@@ -438,11 +442,6 @@ var mergeSchemaConflictTests = []mergeSchemaConflictTest{
 		expConflict: merge.SchemaConflict{
 			TableName: "test",
 			ChkConflicts: []merge.ChkConflict{
-				{
-					Kind:   merge.TagCollision,
-					Ours:   schema.NewCheck("chk", "(c3 > 10)", true),
-					Theirs: schema.NewCheck("chk", "(c3 < 10)", true),
-				},
 				{
 					Kind:   merge.NameCollision,
 					Ours:   schema.NewCheck("chk", "(c3 > 10)", true),
@@ -438,6 +438,13 @@ var secondaryIndexTests = []schemaMergeTest{
 		skipOldFmt:          true,
 		skipFlipOnOldFormat: true,
 	},
+	{
+		name:     "index adds over the same column with different names",
+		ancestor: tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b float)                                 "), row(1, "2", float32(3.0))),
+		left:     tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b float, INDEX a_idx (a))                "), row(1, "2", float32(3.0))),
+		right:    tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b float, INDEX key_a (a))                "), row(1, "2", float32(3.0))),
+		merged:   tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b float, INDEX a_idx (a), INDEX key_a (a))"), row(1, "2", float32(3.0))),
+	},
 }
 
 var simpleConflictTests = []schemaMergeTest{
@@ -461,21 +468,6 @@ var simpleConflictTests = []schemaMergeTest{
 		right:      tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b float, UNIQUE INDEX idx (a))")),
 		conflict:   true,
 	},
-	{
-		// TODO: This test case does NOT generate a conflict; the merge gets short circuited, because the table's
-		//       right/left/anc hashes are all the same. This is an issue with the test framework, not with Dolt.
-		//       The code we use in these tests to create a schema (sqlutil.ParseCreateTableStatement) silently
-		//       drops index and check constraint definitions.
-		skipNewFmt: true,
-		skipOldFmt: true,
-		// TODO: multiple indexes can exist for the same column set, so this shouldn't actually be a conflict;
-		//       Dolt does report this as a schema conflict today, but we could merge the two indexes together.
-		name:     "conflicting index adds: same column different names",
-		ancestor: tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b float)                 ")),
-		left:     tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b float, INDEX a_idx (a))")),
-		right:    tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b float, INDEX key_a (a))")),
-		conflict: true,
-	},
 	{
 		// TODO: This test case does NOT generate a conflict; the merge gets short circuited, because the table's
 		//       right/left/anc hashes are all the same. This is an issue with the test framework, not with Dolt.
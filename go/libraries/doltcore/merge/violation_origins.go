@@ -0,0 +1,179 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// ViolationID identifies a constraint violation of a table by the key of the violating row and the type of the
+// violation, independently of the commit or working set that introduced it.
+func ViolationID(key val.Tuple, artType prolly.ArtifactType) string {
+	return fmt.Sprintf("%d:%s", artType, string(key))
+}
+
+// TableViolationIDs returns the ids of the constraint violations of |tblName| in |root|. Only supported for the DOLT
+// format.
+func TableViolationIDs(ctx context.Context, root *doltdb.RootValue, tblName string) (*set.StrSet, error) {
+	if !types.IsFormat_DOLT(root.VRW().Format()) {
+		return nil, fmt.Errorf("constraint violation history is not supported for this storage format")
+	}
+
+	ids := set.NewStrSet(nil)
+
+	tbl, ok, err := root.GetTable(ctx, tblName)
+	if err != nil || !ok {
+		return ids, err
+	}
+	arts, err := tbl.GetArtifacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	itr, err := durable.ProllyMapFromArtifactIndex(arts).IterAllCVs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		art, err := itr.Next(ctx)
+		if err == io.EOF {
+			return ids, nil
+		} else if err != nil {
+			return nil, err
+		}
+		ids.Add(ViolationID(art.SourceKey, art.ArtType))
+	}
+}
+
+// ViolationReplay replays the history of the commit whose changes introduced a table's constraint violations, to find
+// the commit in which each violation first became possible.
+type ViolationReplay struct {
+	// TableName is the table with the violations.
+	TableName string
+	// Source is the commit whose changes introduced the violations.
+	Source *doltdb.Commit
+	// Ours and OurRoot are the commit and root that |Source| was merged into. If Ours is nil, the violations were
+	// found by verifying the constraints of |Source| rather than by a merge.
+	Ours    *doltdb.Commit
+	OurRoot *doltdb.RootValue
+	Opts    editor.Options
+}
+
+// FindOrigins returns the commit in which each of the violations with the ids |pending| first became possible, keyed
+// by violation id. Starting at the source commit, each commit of its first-parent history is merged into our root
+// again, or has its constraints verified, until none of the remaining violations is reproduced. A merge replay stops
+// at the commits that are already part of our history. Violations that can't be reproduced by replaying the source
+// commit have no origin.
+func (r ViolationReplay) FindOrigins(ctx *sql.Context, pending *set.StrSet) (map[string]*doltdb.Commit, error) {
+	origins := make(map[string]*doltdb.Commit)
+	remaining := set.NewStrSet(pending.AsSlice())
+
+	cm := r.Source
+	for remaining.Size() > 0 {
+		if r.Ours != nil {
+			anc, err := doltdb.GetCommitAncestor(ctx, r.Ours, cm)
+			if err != nil {
+				return nil, err
+			}
+			if isSameCommit(anc, cm) {
+				break
+			}
+		}
+
+		ids, err := r.replay(ctx, cm)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range remaining.AsSlice() {
+			if ids.Contains(id) {
+				origins[id] = cm
+			} else {
+				remaining.Remove(id)
+			}
+		}
+
+		if cm.NumParents() == 0 {
+			break
+		}
+		cm, err = cm.GetParent(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return origins, nil
+}
+
+// replay returns the ids of the violations of the table that exist after merging |cm| into our root, or after
+// verifying the constraints of |cm|.
+func (r ViolationReplay) replay(ctx *sql.Context, cm *doltdb.Commit) (*set.StrSet, error) {
+	theirRoot, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Ours == nil {
+		h, err := cm.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		emptyRoot, err := doltdb.EmptyRootValue(ctx, theirRoot.VRW(), theirRoot.NodeStore())
+		if err != nil {
+			return nil, err
+		}
+		verifiedRoot, _, err := AddForeignKeyViolations(ctx, theirRoot, emptyRoot, set.NewStrSet([]string{r.TableName}), h)
+		if err != nil {
+			return nil, err
+		}
+		return TableViolationIDs(ctx, verifiedRoot, r.TableName)
+	}
+
+	anc, err := doltdb.GetCommitAncestor(ctx, r.Ours, cm)
+	if err != nil {
+		return nil, err
+	}
+	ancRoot, err := anc.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := MergeRoots(ctx, r.OurRoot, theirRoot, ancRoot, cm, anc, r.Opts, MergeOpts{KeepSchemaConflicts: true})
+	if err != nil {
+		return nil, err
+	}
+	return TableViolationIDs(ctx, result.Root, r.TableName)
+}
+
+func isSameCommit(a, b *doltdb.Commit) bool {
+	ah, err := a.HashOf()
+	if err != nil {
+		return false
+	}
+	bh, err := b.HashOf()
+	if err != nil {
+		return false
+	}
+	return ah == bh
+}
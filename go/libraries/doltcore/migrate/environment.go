@@ -171,6 +171,13 @@ func initMigrationDB(ctx context.Context, existing *env.DoltEnv, src, dest files
 
 // SwapChunkStores atomically swaps the ChunkStores of |menv.Migration| and |menv.Existing|.
 func SwapChunkStores(ctx context.Context, menv Environment) error {
+	return SwapChunkStoresIf(ctx, menv, nil)
+}
+
+// SwapChunkStoresIf atomically swaps the ChunkStores of |menv.Migration| and |menv.Existing|, if |check| returns no
+// error. |check| runs once the migrated table files are copied, just before the manifests are swapped, so that it can
+// make sure |menv.Existing| hasn't changed since it was migrated.
+func SwapChunkStoresIf(ctx context.Context, menv Environment, check func() error) error {
 	src, dest := menv.Migration.FS, menv.Existing.FS
 
 	absSrc, err := src.Abs(filepath.Join(doltDir, nomsDir))
@@ -210,6 +217,12 @@ func SwapChunkStores(ctx context.Context, menv Environment) error {
 		return cpErr
 	}
 
+	if check != nil {
+		if err = check(); err != nil {
+			return err
+		}
+	}
+
 	return swapManifests(ctx, src, dest)
 }
 
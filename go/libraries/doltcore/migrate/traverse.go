@@ -25,8 +25,23 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 )
 
-// TraverseDAG traverses |old|, migrating values to |new|.
+// TraverseDAG traverses |old|, migrating values to |new|, and closes both databases.
 func TraverseDAG(ctx context.Context, menv Environment, old, new *doltdb.DoltDB) (err error) {
+	if err = MigrateHistory(ctx, menv, old, new); err != nil {
+		return err
+	}
+	if err = old.Close(); err != nil {
+		return err
+	}
+	if err = new.Close(); err != nil {
+		return err
+	}
+	return
+}
+
+// MigrateHistory traverses |old|, migrating values to |new|. Unlike TraverseDAG, it leaves both databases open, so
+// that |old| can go on serving while it's migrated.
+func MigrateHistory(ctx context.Context, menv Environment, old, new *doltdb.DoltDB) (err error) {
 	var heads []ref.DoltRef
 	var prog *progress
 
@@ -58,17 +73,7 @@ func TraverseDAG(ctx context.Context, menv Environment, old, new *doltdb.DoltDB)
 	if err != nil {
 		return err
 	}
-	if err = persistMigratedCommitMapping(ctx, new, m); err != nil {
-		return err
-	}
-
-	if err = old.Close(); err != nil {
-		return err
-	}
-	if err = new.Close(); err != nil {
-		return err
-	}
-	return
+	return persistMigratedCommitMapping(ctx, new, m)
 }
 
 func traverseRefHistory(ctx context.Context, menv Environment, r ref.DoltRef, old, new *doltdb.DoltDB, prog *progress) error {
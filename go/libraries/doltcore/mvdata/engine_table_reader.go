@@ -42,6 +42,12 @@ type sqlEngineTableReader struct {
 }
 
 func NewSqlEngineReader(ctx context.Context, dEnv *env.DoltEnv, tableName string) (*sqlEngineTableReader, error) {
+	return NewFilteredSqlEngineReader(ctx, dEnv, tableName, "")
+}
+
+// NewFilteredSqlEngineReader returns a reader for the rows of |tableName| that match the SQL condition |where|. All
+// rows are read if |where| is empty.
+func NewFilteredSqlEngineReader(ctx context.Context, dEnv *env.DoltEnv, tableName, where string) (*sqlEngineTableReader, error) {
 	mrEnv, err := env.MultiEnvForDirectory(ctx, dEnv.Config.WriteableConfig(), dEnv.FS, dEnv.Version, dEnv.IgnoreLockFile, dEnv)
 	if err != nil {
 		return nil, err
@@ -76,7 +82,11 @@ func NewSqlEngineReader(ctx context.Context, dEnv *env.DoltEnv, tableName string
 		return nil, fmt.Errorf("expected *plan.ShowCreate table, found %T", ret)
 	}
 
-	_, iter, err := se.Query(sqlCtx, fmt.Sprintf("SELECT * FROM `%s`", tableName))
+	query := fmt.Sprintf("SELECT * FROM `%s`", tableName)
+	if where != "" {
+		query = fmt.Sprintf("%s WHERE %s", query, where)
+	}
+	_, iter, err := se.Query(sqlCtx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,116 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rebase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+var todoActions = map[string]string{
+	"p":                       doltdb.RebaseActionPick,
+	doltdb.RebaseActionPick:   doltdb.RebaseActionPick,
+	"r":                       doltdb.RebaseActionReword,
+	doltdb.RebaseActionReword: doltdb.RebaseActionReword,
+	"s":                       doltdb.RebaseActionSquash,
+	doltdb.RebaseActionSquash: doltdb.RebaseActionSquash,
+	"d":                       doltdb.RebaseActionDrop,
+	doltdb.RebaseActionDrop:   doltdb.RebaseActionDrop,
+}
+
+const todoHelp = `#
+# Commands:
+# p, pick <commit> = use commit
+# r, reword <commit> <message> = use commit, but replace its commit message with <message>
+# s, squash <commit> = use commit, but meld it into the previous commit
+# d, drop <commit> = remove commit
+#
+# These lines are replayed from top to bottom, and can be reordered.
+# Removing a line drops its commit. Removing every line aborts the rebase.
+`
+
+// FormatTodo returns the todo list of a rebase plan, which can be edited and read back with ParseTodo. Each step is
+// followed by the subject of its commit, found in |subjects| by the commit's hash, unless it's a reword, which is
+// followed by its new message. The list ends with |header| and instructions, in comments.
+func FormatTodo(plan []doltdb.RebaseStep, subjects map[string]string, header string) string {
+	var sb strings.Builder
+	for _, step := range plan {
+		text := subjects[step.Commit]
+		if step.Action == doltdb.RebaseActionReword {
+			text = step.Message
+		}
+		sb.WriteString(strings.TrimSpace(fmt.Sprintf("%s %s %s", step.Action, step.Commit, text)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	for _, line := range strings.Split(header, "\n") {
+		sb.WriteString("# ")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString(todoHelp)
+	return sb.String()
+}
+
+// ParseTodo reads a rebase plan from a todo list in the format written by FormatTodo. Blank lines and comments, which
+// start with #, are ignored. The commits of the steps are returned as written.
+func ParseTodo(todo string) ([]doltdb.RebaseStep, error) {
+	var plan []doltdb.RebaseStep
+	for i, line := range strings.Split(todo, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		action, ok := todoActions[strings.ToLower(fields[0])]
+		if !ok {
+			return nil, fmt.Errorf("error: line %d of the rebase todo list: unknown action '%s'", i+1, fields[0])
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("error: line %d of the rebase todo list: %s needs a commit", i+1, action)
+		}
+
+		step := doltdb.RebaseStep{Action: action, Commit: fields[1]}
+		if action == doltdb.RebaseActionReword {
+			// the message is the rest of the line after the commit
+			step.Message = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(line, fields[0])), fields[1]))
+			if step.Message == "" {
+				return nil, fmt.Errorf("error: line %d of the rebase todo list: reword needs a commit message", i+1)
+			}
+		}
+		plan = append(plan, step)
+	}
+	return plan, nil
+}
+
+// ValidatePlan checks that every squash step of |plan| has a previous commit to meld into.
+func ValidatePlan(plan []doltdb.RebaseStep) error {
+	picked := false
+	for _, step := range plan {
+		switch step.Action {
+		case doltdb.RebaseActionSquash:
+			if !picked {
+				return fmt.Errorf("error: cannot squash %s without a previous commit", step.Commit)
+			}
+		case doltdb.RebaseActionDrop:
+		default:
+			picked = true
+		}
+	}
+	return nil
+}
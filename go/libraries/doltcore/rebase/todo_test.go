@@ -0,0 +1,64 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rebase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+func TestTodo(t *testing.T) {
+	plan := []doltdb.RebaseStep{
+		{Action: doltdb.RebaseActionPick, Commit: "a"},
+		{Action: doltdb.RebaseActionReword, Commit: "b", Message: "new message"},
+		{Action: doltdb.RebaseActionSquash, Commit: "c"},
+		{Action: doltdb.RebaseActionDrop, Commit: "d"},
+	}
+	todo := FormatTodo(plan, map[string]string{"a": "first", "b": "second", "c": "third"}, "Rebase a..d onto u")
+	assert.Contains(t, todo, "pick a first\nreword b new message\nsquash c third\ndrop d\n\n# Rebase a..d onto u\n#\n")
+
+	parsed, err := ParseTodo(todo)
+	require.NoError(t, err)
+	assert.Equal(t, plan, parsed)
+	assert.NoError(t, ValidatePlan(parsed))
+
+	parsed, err = ParseTodo("  p  a first\n\n# comment\nr b   new  message \ns c\nd d\nPICK e")
+	require.NoError(t, err)
+	assert.Equal(t, []doltdb.RebaseStep{
+		{Action: doltdb.RebaseActionPick, Commit: "a"},
+		{Action: doltdb.RebaseActionReword, Commit: "b", Message: "new  message"},
+		{Action: doltdb.RebaseActionSquash, Commit: "c"},
+		{Action: doltdb.RebaseActionDrop, Commit: "d"},
+		{Action: doltdb.RebaseActionPick, Commit: "e"},
+	}, parsed)
+
+	parsed, err = ParseTodo("# only comments\n")
+	require.NoError(t, err)
+	assert.Empty(t, parsed)
+
+	_, err = ParseTodo("pick a\nedit b")
+	assert.EqualError(t, err, "error: line 2 of the rebase todo list: unknown action 'edit'")
+	_, err = ParseTodo("pick")
+	assert.EqualError(t, err, "error: line 1 of the rebase todo list: pick needs a commit")
+	_, err = ParseTodo("reword a")
+	assert.EqualError(t, err, "error: line 1 of the rebase todo list: reword needs a commit message")
+
+	err = ValidatePlan([]doltdb.RebaseStep{{Action: doltdb.RebaseActionDrop, Commit: "a"}, {Action: doltdb.RebaseActionSquash, Commit: "b"}})
+	assert.EqualError(t, err, "error: cannot squash b without a previous commit")
+}
@@ -79,10 +79,13 @@ func PrefixForType(refType RefType) string {
 type UpdateMode struct {
 	Force bool
 	Prune bool
+	// Lease limits a forced update of a remote branch to when it's still where its remote tracking branch says it is
+	Lease bool
 }
 
-var ForceUpdate = UpdateMode{true, false}
-var FastForwardOnly = UpdateMode{false, false}
+var ForceUpdate = UpdateMode{Force: true}
+var ForceUpdateWithLease = UpdateMode{Force: true, Lease: true}
+var FastForwardOnly = UpdateMode{}
 
 // DoltRef is a reference to a commit.
 type DoltRef interface {
@@ -65,12 +65,17 @@ func ParseRefSpecForRemote(remote, refSpecStr string) (RefSpec, error) {
 	if len(refSpecStr) == 0 {
 		return nil, ErrInvalidRefSpec
 	} else if refSpecStr[0] == ':' {
-		fromRef = EmptyBranchRef
 		toRef, err = Parse(refSpecStr[1:])
 
 		if err != nil {
 			return nil, ErrInvalidRefSpec
 		}
+
+		if toRef.GetType() == TagRefType {
+			fromRef = EmptyTagRef
+		} else {
+			fromRef = EmptyBranchRef
+		}
 	} else {
 		tokens := strings.Split(refSpecStr, ":")
 
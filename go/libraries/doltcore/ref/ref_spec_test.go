@@ -123,3 +123,15 @@ func TestRefSpec(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteRefSpec(t *testing.T) {
+	refSpec, err := ParseRefSpec(":refs/tags/v1")
+	require.NoError(t, err)
+	assert.Equal(t, EmptyTagRef, refSpec.SrcRef(nil))
+	assert.Equal(t, NewTagRef("v1"), refSpec.DestRef(EmptyTagRef))
+
+	refSpec, err = ParseRefSpec(":refs/heads/main")
+	require.NoError(t, err)
+	assert.Equal(t, EmptyBranchRef, refSpec.SrcRef(nil))
+	assert.Equal(t, NewBranchRef("main"), refSpec.DestRef(EmptyBranchRef))
+}
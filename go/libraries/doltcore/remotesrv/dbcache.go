@@ -15,6 +15,8 @@
 package remotesrv
 
 import (
+	"context"
+
 	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/nbs"
@@ -34,3 +36,15 @@ type RemoteSrvStore interface {
 
 var _ RemoteSrvStore = &nbs.NomsBlockStore{}
 var _ RemoteSrvStore = &nbs.GenerationalNBS{}
+
+// CommitValidator may be implemented by a RemoteSrvStore that needs to vet the new root proposed by a push before the
+// server accepts it. An error returned by ValidateCommit is returned to the client and the commit is not applied.
+type CommitValidator interface {
+	ValidateCommit(ctx context.Context, last, current hash.Hash) error
+}
+
+// CommitListener may be implemented by a RemoteSrvStore that needs to act on pushes it has accepted. CommitApplied is
+// called after the new root has been committed, so it cannot fail the push.
+type CommitListener interface {
+	CommitApplied(ctx context.Context, last, current hash.Hash)
+}
@@ -485,6 +485,14 @@ func (rs *RemoteChunkStore) Commit(ctx context.Context, req *remotesapi.CommitRe
 	currHash := hash.New(req.Current)
 	lastHash := hash.New(req.Last)
 
+	if v, ok := cs.(CommitValidator); ok {
+		err = v.ValidateCommit(ctx, lastHash, currHash)
+		if err != nil {
+			logger.WithError(err).Info("commit rejected")
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+
 	var ok bool
 	ok, err = cs.Commit(ctx, currHash, lastHash)
 	if err != nil {
@@ -495,6 +503,10 @@ func (rs *RemoteChunkStore) Commit(ctx context.Context, req *remotesapi.CommitRe
 		return nil, status.Errorf(codes.Internal, "failed to commit: %v", err)
 	}
 
+	if l, isListener := cs.(CommitListener); ok && isListener {
+		l.CommitApplied(ctx, lastHash, currHash)
+	}
+
 	logger.Tracef("Commit success; moved from %s -> %s", lastHash.String(), currHash.String())
 	return &remotesapi.CommitResponse{Success: ok}, nil
 }
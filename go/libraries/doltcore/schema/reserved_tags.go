@@ -115,3 +115,9 @@ const (
 	DoltIgnorePatternTag = iota + SystemTableReservedMin + uint64(8000)
 	DoltIgnoreIgnoredTag
 )
+
+// Tags for the dolt_merge_strategies table
+const (
+	DoltMergeStrategiesPatternTag = iota + SystemTableReservedMin + uint64(9000)
+	DoltMergeStrategiesStrategyTag
+)
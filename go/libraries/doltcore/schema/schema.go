@@ -285,8 +285,9 @@ func ArePrimaryKeySetsDiffable(format *types.NomsBinFormat, fromSch, toSch Schem
 
 // MapSchemaBasedOnTagAndName can be used to map column values from one schema
 // to another schema. A primary key column in |inSch| is mapped to |outSch| if
-// they share the same tag. A non-primary key column in |inSch| is mapped to
-// |outSch| purely based on the name. It returns ordinal mappings that can be
+// they share the same tag, or else if they share the same name, as the columns
+// of a table matched to a renamed copy of itself do. A non-primary key column
+// in |inSch| is mapped to |outSch| purely based on the name. It returns ordinal mappings that can be
 // use to map key, value val.Tuple's of schema |inSch| to |outSch|. The first
 // ordinal map is for keys, and the second is for values. If a column of |inSch|
 // is missing in |outSch| then that column's index in the ordinal map holds -1.
@@ -301,7 +302,11 @@ func MapSchemaBasedOnTagAndName(inSch, outSch Schema) ([]int, []int, error) {
 
 	err := inSch.GetPKCols().Iter(func(tag uint64, col Column) (stop bool, err error) {
 		i := inSch.GetPKCols().TagToIdx[tag]
-		if foundCol, ok := outSch.GetPKCols().GetByTag(tag); ok {
+		foundCol, ok := outSch.GetPKCols().GetByTag(tag)
+		if !ok {
+			foundCol, ok = outSch.GetPKCols().GetByName(col.Name)
+		}
+		if ok {
 			j := outSch.GetPKCols().TagToIdx[foundCol.Tag]
 			keyMapping[i] = j
 		} else {
@@ -0,0 +1,103 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ackQuorum is shared by the commithooks replicating a database to each of the standbys, when a commit only has to be
+// replicated to |required| of them before it returns. The wait functions returned by the commithooks' Execute all
+// succeed together, once enough of the standbys have committed a root hash that contains the commit.
+type ackQuorum struct {
+	required int
+
+	mu    sync.Mutex
+	hooks []*commithook
+	// changed is closed, and replaced, whenever one of |hooks| replicates a new head or changes its role.
+	changed chan struct{}
+}
+
+func newAckQuorum(required int) *ackQuorum {
+	return &ackQuorum{
+		required: required,
+		changed:  make(chan struct{}),
+	}
+}
+
+// add makes |h| one of the commithooks whose standbys count towards the quorum. It must be called before |h| runs.
+func (q *ackQuorum) add(h *commithook) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.hooks = append(q.hooks, h)
+	h.quorum = q
+}
+
+// notify wakes the wait functions, which check whether the quorum has been reached.
+func (q *ackQuorum) notify() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// waitF returns a function that waits until |required| standbys have committed a root hash of |dbname| that was
+// read from the local database at or after |since|. It fails fast when so many of the standbys have their circuit
+// breakers open that the others can't make up the quorum.
+func (q *ackQuorum) waitF(dbname string, since time.Time) func(context.Context) error {
+	return func(ctx context.Context) error {
+		for {
+			q.mu.Lock()
+			changed := q.changed
+			hooks := make([]*commithook, len(q.hooks))
+			copy(hooks, q.hooks)
+			q.mu.Unlock()
+
+			acked, available := q.count(hooks, since)
+			if acked >= q.required {
+				return nil
+			}
+			if acked+available < q.required {
+				return fmt.Errorf("circuit breakers for replication of %s are open on %d of %d standbys. this commit did not necessarily replicate successfully to %d of them.", dbname, len(hooks)-acked-available, len(hooks), q.required)
+			}
+
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// count returns the number of |hooks| whose standbys have acked the root hashes read since |since|, and the number
+// of the rest that still might.
+func (q *ackQuorum) count(hooks []*commithook, since time.Time) (acked, available int) {
+	for _, h := range hooks {
+		h.mu.Lock()
+		if h.role == RolePrimary {
+			if !h.lastPushedReadTime.Before(since) {
+				acked++
+			} else if !h.circuitBreakerOpen() {
+				available++
+			}
+		}
+		h.mu.Unlock()
+	}
+	return acked, available
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAckQuorum(t *testing.T) {
+	newHooks := func(q *ackQuorum, n int) []*commithook {
+		hooks := make([]*commithook, n)
+		for i := range hooks {
+			hooks[i] = &commithook{role: RolePrimary}
+			q.add(hooks[i])
+		}
+		return hooks
+	}
+	push := func(q *ackQuorum, h *commithook, readTime time.Time) {
+		h.mu.Lock()
+		h.lastPushedReadTime = readTime
+		h.mu.Unlock()
+		q.notify()
+	}
+
+	t.Run("SucceedsOnceEnoughStandbysAck", func(t *testing.T) {
+		q := newAckQuorum(2)
+		hooks := newHooks(q, 3)
+		since := time.Now()
+		done := make(chan error)
+		go func() {
+			done <- q.waitF("mydb", since)(context.Background())
+		}()
+
+		push(q, hooks[0], since)
+		// a head read before the commit doesn't contain it
+		push(q, hooks[1], since.Add(-time.Second))
+		select {
+		case err := <-done:
+			t.Fatalf("wait returned before the quorum acked: %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		push(q, hooks[2], since.Add(time.Second))
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("wait didn't return after the quorum acked")
+		}
+	})
+
+	t.Run("ReturnsWhenCanceled", func(t *testing.T) {
+		q := newAckQuorum(1)
+		newHooks(q, 2)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, q.waitF("mydb", time.Now())(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("FailsFastWithOpenCircuitBreakers", func(t *testing.T) {
+		q := newAckQuorum(2)
+		hooks := newHooks(q, 3)
+		hooks[0].fastFailReplicationWait = true
+		hooks[1].fastFailReplicationWait = true
+		err := q.waitF("mydb", time.Now())(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "circuit breakers")
+
+		// a standby that's already acked counts, even if its breaker is open
+		since := time.Now()
+		push(q, hooks[0], since)
+		push(q, hooks[2], since)
+		assert.NoError(t, q.waitF("mydb", since)(context.Background()))
+	})
+}
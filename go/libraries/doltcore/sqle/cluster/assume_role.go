@@ -17,6 +17,7 @@ package cluster
 import (
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
@@ -38,7 +39,7 @@ func newAssumeRoleProcedure(controller *Controller) sql.ExternalStoredProcedureD
 		},
 		Function: func(ctx *sql.Context, role string, epoch int) (sql.RowIter, error) {
 			if role == string(RoleDetectedBrokenConfig) {
-				return nil, errors.New("cannot set role to detected_broken_config; valid values are 'primary' and 'standby'")
+				return nil, errors.New("cannot set role to detected_broken_config; valid values are 'primary', 'standby' and 'observer'")
 			}
 			saveConnID := int(ctx.Session.ID())
 			res, err := controller.setRoleAndEpoch(role, epoch, roleTransitionOptions{
@@ -84,6 +85,11 @@ func newTransitionToStandbyProcedure(controller *Controller) sql.ExternalStoredP
 				Type:     types.LongText,
 				Nullable: false,
 			},
+			&sql.Column{
+				Name:     "aborted_transactions",
+				Type:     types.Int64,
+				Nullable: false,
+			},
 		},
 		Function: func(ctx *sql.Context, epoch, minCaughtUpStandbys int) (sql.RowIter, error) {
 			saveConnID := int(ctx.Session.ID())
@@ -111,6 +117,7 @@ func newTransitionToStandbyProcedure(controller *Controller) sql.ExternalStoredP
 						r.database,
 						r.remote,
 						r.remoteUrl,
+						int64(r.abortedTransactions),
 					}
 				}
 				return sql.RowsToRowIter(rows...), nil
@@ -120,3 +127,122 @@ func newTransitionToStandbyProcedure(controller *Controller) sql.ExternalStoredP
 		},
 	}
 }
+
+// roleChangeSchema is the schema of the results of dolt_cluster_promote() and dolt_cluster_demote(): one row with the
+// role and epoch of this server before and after the call, and the databases that were replicated to every standby
+// before a primary was demoted.
+var roleChangeSchema = sql.Schema{
+	&sql.Column{
+		Name:     "previous_role",
+		Type:     types.LongText,
+		Nullable: false,
+	},
+	&sql.Column{
+		Name:     "previous_epoch",
+		Type:     types.Int64,
+		Nullable: false,
+	},
+	&sql.Column{
+		Name:     "role",
+		Type:     types.LongText,
+		Nullable: false,
+	},
+	&sql.Column{
+		Name:     "epoch",
+		Type:     types.Int64,
+		Nullable: false,
+	},
+	&sql.Column{
+		Name:     "caught_up_databases",
+		Type:     types.JSON,
+		Nullable: false,
+	},
+}
+
+func roleChangeRow(previousRole Role, previousEpoch int, role Role, epoch int, caughtUpDatabases []string) sql.Row {
+	dbs := make([]interface{}, len(caughtUpDatabases))
+	for i, db := range caughtUpDatabases {
+		dbs[i] = db
+	}
+	return sql.Row{
+		string(previousRole),
+		int64(previousEpoch),
+		string(role),
+		int64(epoch),
+		types.JSONDocument{Val: dbs},
+	}
+}
+
+// caughtUpDatabases returns the names of the databases that were caught up on every standby in |results|, sorted.
+func caughtUpDatabases(results []graceTransitionResult) []string {
+	caughtUp := make(map[string]bool)
+	for _, r := range results {
+		if prev, ok := caughtUp[r.database]; !ok || prev {
+			caughtUp[r.database] = r.caughtUp
+		}
+	}
+	ret := make([]string, 0, len(caughtUp))
+	for db, ok := range caughtUp {
+		if ok {
+			ret = append(ret, db)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// newPromoteProcedure returns dolt_cluster_promote(expected_epoch, epoch), which makes this server primary at
+// |epoch|, but only if it's a standby at |expected_epoch|. Orchestration scripts pass the epoch they last saw the
+// server at, so that they don't promote a server whose role changed since.
+func newPromoteProcedure(controller *Controller) sql.ExternalStoredProcedureDetails {
+	return sql.ExternalStoredProcedureDetails{
+		Name:   "dolt_cluster_promote",
+		Schema: roleChangeSchema,
+		Function: func(ctx *sql.Context, expectedEpoch, epoch int) (sql.RowIter, error) {
+			saveConnID := int(ctx.Session.ID())
+			_, err := controller.setRoleAndEpoch(string(RolePrimary), epoch, roleTransitionOptions{
+				graceful:      true,
+				saveConnID:    &saveConnID,
+				expectedEpoch: &expectedEpoch,
+				expectedRole:  RoleStandby,
+			})
+			if err != nil {
+				return nil, err
+			}
+			// We transitioned, make sure we do not run anymore queries on this session.
+			ctx.Session.SetTransaction(nil)
+			dsess.DSessFromSess(ctx.Session).SetValidateErr(ErrServerTransitionedRolesErr)
+			return sql.RowsToRowIter(roleChangeRow(RoleStandby, expectedEpoch, RolePrimary, epoch, nil)), nil
+		},
+		ReadOnly: true,
+	}
+}
+
+// newDemoteProcedure returns dolt_cluster_demote(expected_epoch, epoch, wait_for_catch_up), which makes this server
+// standby at |epoch|, but only if it's primary at |expected_epoch|. If |wait_for_catch_up| is true, it transitions
+// gracefully, and fails unless every database was replicated to every standby, as dolt_assume_cluster_role does.
+// Otherwise it transitions immediately, without waiting for the standbys.
+func newDemoteProcedure(controller *Controller) sql.ExternalStoredProcedureDetails {
+	return sql.ExternalStoredProcedureDetails{
+		Name:   "dolt_cluster_demote",
+		Schema: roleChangeSchema,
+		Function: func(ctx *sql.Context, expectedEpoch, epoch int, waitForCatchUp bool) (sql.RowIter, error) {
+			saveConnID := int(ctx.Session.ID())
+			res, err := controller.setRoleAndEpoch(string(RoleStandby), epoch, roleTransitionOptions{
+				graceful:      waitForCatchUp,
+				saveConnID:    &saveConnID,
+				expectedEpoch: &expectedEpoch,
+				expectedRole:  RolePrimary,
+			})
+			if err != nil {
+				// We did not transition, no need to set our session to read-only, etc.
+				return nil, err
+			}
+			// We transitioned, make sure we do not run anymore queries on this session.
+			ctx.Session.SetTransaction(nil)
+			dsess.DSessFromSess(ctx.Session).SetValidateErr(ErrServerTransitionedRolesErr)
+			return sql.RowsToRowIter(roleChangeRow(RolePrimary, expectedEpoch, RoleStandby, epoch, caughtUpDatabases(res.gracefulTransitionResults))), nil
+		},
+		ReadOnly: true,
+	}
+}
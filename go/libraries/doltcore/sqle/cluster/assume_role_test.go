@@ -0,0 +1,33 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaughtUpDatabases(t *testing.T) {
+	assert.Empty(t, caughtUpDatabases(nil))
+	assert.Equal(t, []string{"db1", "db3"}, caughtUpDatabases([]graceTransitionResult{
+		{database: "db3", remote: "standby1", caughtUp: true},
+		{database: "db2", remote: "standby1", caughtUp: true},
+		{database: "db1", remote: "standby1", caughtUp: true},
+		{database: "db2", remote: "standby2", caughtUp: false},
+		{database: "db1", remote: "standby2", caughtUp: true},
+		{database: "db3", remote: "standby2", caughtUp: true},
+	}))
+}
@@ -0,0 +1,108 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// bootstrapStandbyDatabase creates the database |name| on this server, a standby, when the primary first replicates
+// it here, and restores it from its backup at the URL of c.cfg.StandbyBootstrapURLTemplate(). The primary then only
+// pushes the chunks that aren't in the backup. It does nothing if the database already exists, this server isn't a
+// standby or an observer, or no bootstrap URL is configured. If the restore fails, the database is dropped again, so
+// that it's bootstrapped again the next time the primary replicates it.
+func (c *Controller) bootstrapStandbyDatabase(ctx *sql.Context, name string) error {
+	if c == nil || c.cfg.StandbyBootstrapURLTemplate() == "" {
+		return nil
+	}
+	if role, _ := c.roleAndEpoch(); role != RoleStandby && role != RoleObserver {
+		return nil
+	}
+	// the primary makes many requests to replicate a database, and only the first one bootstraps it
+	c.bootstrapMu.Lock()
+	defer c.bootstrapMu.Unlock()
+
+	provider := dsess.DSessFromSess(ctx.Session).Provider()
+	if provider.HasDatabase(ctx, name) {
+		return nil
+	}
+	if err := provider.CreateDatabase(ctx, name); err != nil {
+		return err
+	}
+	db, ok := provider.BaseDatabase(ctx, name)
+	if !ok {
+		return sql.ErrDatabaseNotFound.New(name)
+	}
+	tempDir, err := db.DbData().Rsw.TempTableFilesDir()
+	if err != nil {
+		return err
+	}
+
+	url := strings.Replace(c.cfg.StandbyBootstrapURLTemplate(), dsess.URLTemplateDatabasePlaceholder, name, -1)
+	c.lgr.Infof("cluster/controller: bootstrapping standby database %s from %s.", name, url)
+	err = restoreFromBackup(ctx, db.DbData().Ddb, url, tempDir)
+	if err != nil {
+		c.lgr.Warnf("cluster/controller: could not bootstrap standby database %s from %s: %v", name, url, err)
+		if dropErr := provider.DropDatabase(ctx, name); dropErr != nil {
+			c.lgr.Warnf("cluster/controller: could not drop standby database %s after failing to bootstrap it: %v", name, dropErr)
+		}
+		return fmt.Errorf("could not bootstrap standby database %s from %s: %w", name, url, err)
+	}
+	c.lgr.Infof("cluster/controller: bootstrapped standby database %s.", name)
+	return nil
+}
+
+// restoreFromBackup copies every chunk of the backup at |url| into |ddb|, and sets the root of |ddb| to the root of
+// the backup.
+func restoreFromBackup(ctx context.Context, ddb *doltdb.DoltDB, url, tempDir string) error {
+	backup, err := doltdb.LoadDoltDB(ctx, ddb.Format(), url, filesys.LocalFS)
+	if err != nil {
+		return err
+	}
+	defer backup.Close()
+
+	root, err := backup.NomsRoot(ctx)
+	if err != nil {
+		return err
+	}
+	if root.IsEmpty() {
+		return errors.New("the backup is empty")
+	}
+	last, err := ddb.NomsRoot(ctx)
+	if err != nil {
+		return err
+	}
+	err = ddb.PullChunks(ctx, tempDir, backup, []hash.Hash{root}, nil)
+	if err != nil {
+		return err
+	}
+	ok, err := ddb.CommitRoot(ctx, root, last)
+	if err != nil {
+		return err
+	} else if !ok {
+		return errDestDBRootHashMoved
+	}
+	return nil
+}
@@ -144,6 +144,7 @@ func (r *branchControlReplica) setRole(role Role) {
 	defer r.mu.Unlock()
 	r.role = role
 	r.nextAttempt = time.Time{}
+	r.backoff.Reset()
 	r.cond.Broadcast()
 }
 
@@ -0,0 +1,68 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCircuitBreakerFailureThreshold is the number of commits that time out waiting for a standby before its
+// circuit breaker opens when it isn't configured.
+const DefaultCircuitBreakerFailureThreshold = 1
+
+// replicationCircuitBreaker is the policy of the circuit breakers of the commithooks. A commithook's circuit breaker
+// opens once |failureThreshold| commits in a row time out waiting for its standby to ack them. While it's open,
+// commits fail fast instead of waiting for the standby, until the standby catches up. If |probeInterval| is set, the
+// breaker is half-open that long after it opened: commits wait for the standby again, and the next one that times out
+// opens it again. It's shared by all of the commithooks, and fast fail can be disabled at runtime with
+// dolt_cluster_replication_fast_fail.
+type replicationCircuitBreaker struct {
+	failureThreshold int
+	probeInterval    time.Duration
+	fastFail         atomic.Bool
+}
+
+// newReplicationCircuitBreaker returns the policy configured by |cfg|. A nil |cfg| opens a breaker after a single
+// timeout, and keeps it open until the standby catches up.
+func newReplicationCircuitBreaker(cfg CircuitBreakerConfig) *replicationCircuitBreaker {
+	b := &replicationCircuitBreaker{failureThreshold: DefaultCircuitBreakerFailureThreshold}
+	if cfg != nil {
+		b.failureThreshold = cfg.FailureThreshold()
+		b.probeInterval = cfg.ProbeInterval()
+	}
+	b.fastFail.Store(true)
+	return b
+}
+
+// opens returns true if a commithook's breaker opens after |failures| commits in a row timed out.
+func (b *replicationCircuitBreaker) opens(failures int) bool {
+	if b == nil {
+		return failures >= DefaultCircuitBreakerFailureThreshold
+	}
+	return failures >= b.failureThreshold
+}
+
+// failsFast returns true if commits fail fast while a breaker that opened at |openedAt| is open. They don't when
+// fast fail is disabled, or when the breaker is half-open because a probe is due.
+func (b *replicationCircuitBreaker) failsFast(openedAt time.Time) bool {
+	if b == nil {
+		return true
+	}
+	if !b.fastFail.Load() {
+		return false
+	}
+	return b.probeInterval <= 0 || time.Since(openedAt) < b.probeInterval
+}
@@ -23,11 +23,14 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/sirupsen/logrus"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/clusterdb"
 	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/datas/pull"
 	"github.com/dolthub/dolt/go/store/hash"
 )
 
@@ -52,6 +55,50 @@ type commithook struct {
 	currentError         *string
 	cancelReplicate      func()
 
+	// nextHeadReadTime is the last time |nextHead| was read as the root of |srcDB|, and lastPushedReadTime is the
+	// same time for |lastPushedHead|. Every commit made before one of these times is in the corresponding head.
+	nextHeadReadTime   time.Time
+	lastPushedReadTime time.Time
+
+	// lastContact is the last time a push or a heartbeat to the standby succeeded in the current role.
+	lastContact time.Time
+
+	// heartbeat is when and how the commithook heartbeats to the standby once it's caught up. |nextHeartbeat| is
+	// when the next heartbeat is due, and |heartbeatFailures| counts the heartbeats that have failed since the last
+	// contact with the standby.
+	heartbeat         heartbeatPolicy
+	nextHeartbeat     time.Time
+	heartbeatFailures int
+
+	// quorum is shared with the commithooks replicating the same database to the other standbys when a commit only
+	// has to be replicated to some of them, or nil if it has to be replicated to all of them.
+	quorum *ackQuorum
+
+	// backoff is the delay before the next attempt to replicate after a failed one. |retries| counts the attempts
+	// that have failed since the last one that succeeded, and |currentBackoff| is the delay after the last of them.
+	backoff        backoff.BackOff
+	retries        int
+	currentBackoff time.Duration
+
+	// bytesPending is the number of bytes the running push has written to table files but not yet sent.
+	bytesPending uint64
+
+	// failedAttempts counts every attempt to replicate that has failed since the commithook was created.
+	failedAttempts uint64
+
+	// events records the changes in the state of replication to the standby, and is shared with the controller.
+	events *replicationEventLog
+
+	// throttle limits the bandwidth used to push to the standby, and is shared with every other commithook.
+	throttle *replicationThrottle
+
+	// breaker is the policy of the circuit breaker that makes commits fail fast when the standby isn't acking them,
+	// and is shared with every other commithook. |waitFailures| counts the commits that have timed out waiting for
+	// the standby since it was last caught up, and |breakerOpenedAt| is when the circuit breaker last opened.
+	breaker         *replicationCircuitBreaker
+	waitFailures    int
+	breakerOpenedAt time.Time
+
 	// waitNotify is set by controller when it needs to track whether the
 	// commithooks are caught up with replicating to the standby.
 	waitNotify func()
@@ -64,9 +111,10 @@ type commithook struct {
 	// 4. If you read a channel out of |successChs|, that channel will be closed on the next successful replication attempt. It will not be closed before then.
 	successChs []chan struct{}
 
-	// If this is true, the waitF returned by Execute() will fast fail if
-	// we are not already caught up, instead of blocking on a successCh
-	// actually indicated we are caught up. This is set to by a call to
+	// If this is true, the circuit breaker is open, and the waitF returned
+	// by Execute() will fast fail if we are not already caught up, instead
+	// of blocking on a successCh actually indicated we are caught up,
+	// unless |breaker| says otherwise. This is set by calls to
 	// NotifyWaitFailed(), an optional interface on CommitHook.
 	fastFailReplicationWait bool
 
@@ -89,7 +137,55 @@ var errDestDBRootHashMoved error = errors.New("cluster/commithook: standby repli
 const logFieldThread = "thread"
 const logFieldRole = "role"
 
-func newCommitHook(lgr *logrus.Logger, remotename, remoteurl, dbname string, role Role, destDBF func(context.Context) (*doltdb.DoltDB, error), srcDB *doltdb.DoltDB, tempDir string) *commithook {
+// Defaults of the replication backoff policy, which retry every second
+const (
+	DefaultBackoffInitialInterval = time.Second
+	DefaultBackoffMultiplier      = 1.0
+	DefaultBackoffMaxInterval     = time.Second
+	DefaultBackoffJitter          = 0.0
+)
+
+// Defaults of the heartbeats a primary sends to a standby it's caught up with.
+const (
+	DefaultHeartbeatInterval         = time.Second
+	DefaultHeartbeatTimeout          = 5 * time.Second
+	DefaultHeartbeatFailureThreshold = 3
+)
+
+type heartbeatPolicy struct {
+	interval         time.Duration
+	timeout          time.Duration
+	failureThreshold int
+}
+
+// newHeartbeatPolicy returns the heartbeats configured by |cfg|, or the defaults if it's nil.
+func newHeartbeatPolicy(cfg HeartbeatConfig) heartbeatPolicy {
+	if cfg == nil {
+		return heartbeatPolicy{DefaultHeartbeatInterval, DefaultHeartbeatTimeout, DefaultHeartbeatFailureThreshold}
+	}
+	return heartbeatPolicy{cfg.Interval(), cfg.Timeout(), cfg.FailureThreshold()}
+}
+
+// newReplicationBackOff returns the backoff configured by |cfg|, which never stops retrying. A nil |cfg| retries
+// every second.
+func newReplicationBackOff(cfg BackoffConfig) backoff.BackOff {
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = DefaultBackoffInitialInterval
+	bo.Multiplier = DefaultBackoffMultiplier
+	bo.MaxInterval = DefaultBackoffMaxInterval
+	bo.RandomizationFactor = DefaultBackoffJitter
+	if cfg != nil {
+		bo.InitialInterval = cfg.InitialInterval()
+		bo.Multiplier = cfg.Multiplier()
+		bo.MaxInterval = cfg.MaxInterval()
+		bo.RandomizationFactor = cfg.Jitter()
+	}
+	bo.MaxElapsedTime = 0
+	bo.Reset()
+	return bo
+}
+
+func newCommitHook(lgr *logrus.Logger, remotename, remoteurl, dbname string, role Role, backoffCfg BackoffConfig, heartbeatCfg HeartbeatConfig, throttle *replicationThrottle, breaker *replicationCircuitBreaker, events *replicationEventLog, destDBF func(context.Context) (*doltdb.DoltDB, error), srcDB *doltdb.DoltDB, tempDir string) *commithook {
 	var ret commithook
 	ret.rootLgr = lgr.WithField(logFieldThread, "Standby Replication - "+dbname+" to "+remotename)
 	ret.lgr.Store(ret.rootLgr.WithField(logFieldRole, string(role)))
@@ -100,6 +196,14 @@ func newCommitHook(lgr *logrus.Logger, remotename, remoteurl, dbname string, rol
 	ret.destDBF = destDBF
 	ret.srcDB = srcDB
 	ret.tempDir = tempDir
+	ret.backoff = newReplicationBackOff(backoffCfg)
+	ret.heartbeat = newHeartbeatPolicy(heartbeatCfg)
+	ret.throttle = throttle
+	if ret.throttle == nil {
+		ret.throttle = newReplicationThrottle(nil)
+	}
+	ret.breaker = breaker
+	ret.events = events
 	ret.cond = sync.NewCond(&ret.mu)
 	return &ret
 }
@@ -111,12 +215,11 @@ func (h *commithook) Run(bt *sql.BackgroundThreads) error {
 func (h *commithook) run(ctx context.Context) {
 	// The hook comes up attempting to replicate the current head.
 	h.logger().Tracef("cluster/commithook: background thread: running.")
-	h.wg.Add(2)
+	h.wg.Add(1)
 	go h.replicate(ctx)
-	go h.tick(ctx)
 	<-ctx.Done()
 	h.logger().Tracef("cluster/commithook: background thread: requested shutdown, signaling replication thread.")
-	h.cond.Signal()
+	h.wake()
 	h.wg.Wait()
 	h.logger().Tracef("cluster/commithook: background thread: completed.")
 }
@@ -126,7 +229,6 @@ func (h *commithook) replicate(ctx context.Context) {
 	defer h.logger().Tracef("cluster/commithook: background thread: replicate: shutdown.")
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	shouldHeartbeat := false
 	for !h.shutdown.Load() {
 		lgr := h.logger()
 		// Shutdown for context canceled.
@@ -145,6 +247,7 @@ func (h *commithook) replicate(ctx context.Context) {
 			datasDB := doltdb.HackDatasDatabaseFromDoltDB(h.srcDB)
 			cs := datas.ChunkStoreFromDatabase(datasDB)
 			var err error
+			h.nextHeadReadTime = time.Now()
 			h.nextHead, err = cs.Root(ctx)
 			if err != nil {
 				// TODO: if err != nil, something is really wrong; should shutdown or backoff.
@@ -157,26 +260,30 @@ func (h *commithook) replicate(ctx context.Context) {
 			h.nextHeadIncomingTime = time.Now()
 		} else if h.shouldReplicate() {
 			h.attemptReplicate(ctx)
-			shouldHeartbeat = false
 		} else {
 			lgr.Tracef("cluster/commithook: background thread: waiting for signal.")
 			if h.waitNotify != nil {
 				h.waitNotify()
 			}
 			caughtUp := h.isCaughtUp()
-			if len(h.successChs) != 0 && caughtUp {
+			if caughtUp {
 				for _, ch := range h.successChs {
 					close(ch)
 				}
 				h.successChs = nil
 				h.fastFailReplicationWait = false
+				h.waitFailures = 0
 			}
-			if shouldHeartbeat {
+			if caughtUp && h.heartbeatDue() {
+				// h.mu is released while heartbeating, so check for new work before waiting.
 				h.attemptHeartbeat(ctx)
-			} else if caughtUp {
-				shouldHeartbeat = true
+				continue
 			}
+			wakeup := h.scheduleWakeup()
 			h.cond.Wait()
+			if wakeup != nil {
+				wakeup.Stop()
+			}
 			lgr.Tracef("cluster/commithook: background thread: woken up.")
 		}
 	}
@@ -203,29 +310,66 @@ func (h *commithook) isCaughtUp() bool {
 	return h.nextHead == h.lastPushedHead
 }
 
+// caughtUp is isCaughtUp, for callers that don't hold h.mu.
+func (h *commithook) caughtUp() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.isCaughtUp()
+}
+
 // called with h.mu locked.
 func (h *commithook) primaryNeedsInit() bool {
 	return h.role == RolePrimary && h.nextHead == (hash.Hash{})
 }
 
+// called with h.mu locked. Returns true if the replicate thread should heartbeat to the standby, which it does while
+// it's a caught up primary that has pushed to the standby before.
+func (h *commithook) heartbeatDue() bool {
+	if h.role != RolePrimary || h.lastPushedHead.IsEmpty() || h.destDB == nil {
+		return false
+	}
+	return !time.Now().Before(h.nextHeartbeat)
+}
+
+// called with h.mu locked, by the replicate thread before it waits for a signal. Only a primary has outstanding work
+// that no one else signals: the next attempt to replicate after a failed one, or the next heartbeat once it's caught
+// up. Returns a timer that wakes the replicate thread when it's due, or nil if there isn't any.
+func (h *commithook) scheduleWakeup() *time.Timer {
+	if h.role != RolePrimary {
+		return nil
+	}
+	var at time.Time
+	if !h.isCaughtUp() {
+		at = h.nextPushAttempt
+	} else if !h.lastPushedHead.IsEmpty() && h.destDB != nil {
+		at = h.nextHeartbeat
+	}
+	if at == (time.Time{}) {
+		return nil
+	}
+	return time.AfterFunc(time.Until(at), h.wake)
+}
+
+// wake signals the replicate thread. It takes h.mu, so that the signal isn't lost while the replicate thread is
+// deciding whether to wait for one.
+func (h *commithook) wake() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cond.Signal()
+}
+
 // Called by the replicate thread to periodically heartbeat liveness to a
 // standby if we are a primary. These heartbeats are best effort and currently
-// do not affect the data plane much.
+// do not affect the data plane much. Once |h.heartbeat.failureThreshold| of
+// them fail in a row, the standby is reported as unreachable in the current
+// error until it's contacted again.
 //
-// preconditions: h.mu is locked and shouldReplicate() returned false.
+// preconditions: h.mu is locked and heartbeatDue() returned true.
 func (h *commithook) attemptHeartbeat(ctx context.Context) {
-	if h.role != RolePrimary {
-		return
-	}
 	head := h.lastPushedHead
-	if head.IsEmpty() {
-		return
-	}
 	destDB := h.destDB
-	if destDB == nil {
-		return
-	}
-	ctx, h.cancelReplicate = context.WithTimeout(ctx, 5*time.Second)
+	h.nextHeartbeat = time.Now().Add(h.heartbeat.interval)
+	ctx, h.cancelReplicate = context.WithTimeout(ctx, h.heartbeat.timeout)
 	defer func() {
 		if h.cancelReplicate != nil {
 			h.cancelReplicate()
@@ -235,8 +379,33 @@ func (h *commithook) attemptHeartbeat(ctx context.Context) {
 	h.mu.Unlock()
 	datasDB := doltdb.HackDatasDatabaseFromDoltDB(destDB)
 	cs := datas.ChunkStoreFromDatabase(datasDB)
-	cs.Commit(ctx, head, head)
+	_, err := cs.Commit(ctx, head, head)
 	h.mu.Lock()
+	if h.role != RolePrimary || head != h.lastPushedHead {
+		return
+	}
+	if err == nil {
+		h.lastContact = time.Now()
+		h.resetHeartbeatFailures()
+		return
+	}
+	h.heartbeatFailures++
+	h.logger().Tracef("cluster/commithook: heartbeat to standby failed: %v", err)
+	if h.heartbeatFailures == h.heartbeat.failureThreshold {
+		msg := fmt.Sprintf("standby is unreachable: %d heartbeats failed in a row, the last with: %v", h.heartbeatFailures, err)
+		h.logger().Warnf("cluster/commithook: %s", msg)
+		h.recordEvent(ReplicationEventError, msg)
+		h.currentError = &msg
+	}
+}
+
+// called with h.mu locked, when the standby was contacted. Clears the error reported once heartbeats to it failed.
+func (h *commithook) resetHeartbeatFailures() {
+	if h.heartbeatFailures > 0 && h.heartbeatFailures >= h.heartbeat.failureThreshold && h.currentError != nil {
+		h.recordEvent(ReplicationEventRecovered, "standby is reachable again")
+		h.currentError = nil
+	}
+	h.heartbeatFailures = 0
 }
 
 // Called by the replicate thread to push the nextHead to the destDB and set
@@ -248,6 +417,7 @@ func (h *commithook) attemptReplicate(ctx context.Context) {
 	lgr := h.logger()
 	toPush := h.nextHead
 	incomingTime := h.nextHeadIncomingTime
+	readTime := h.nextHeadReadTime
 	destDB := h.destDB
 	ctx, h.cancelReplicate = context.WithCancel(ctx)
 	defer func() {
@@ -270,13 +440,11 @@ func (h *commithook) attemptReplicate(ctx context.Context) {
 		var err error
 		destDB, err = h.destDBF(ctx)
 		if err != nil {
-			h.currentError = new(string)
-			*h.currentError = fmt.Sprintf("could not replicate to standby: error fetching destDB: %v", err)
 			lgr.Warnf("cluster/commithook: could not replicate to standby: error fetching destDB: %v.", err)
 			h.mu.Lock()
-			// TODO: We could add some backoff here.
+			h.recordFailure(fmt.Sprintf("could not replicate to standby: error fetching destDB: %v", err))
 			if toPush == h.nextHead {
-				h.nextPushAttempt = time.Now().Add(1 * time.Second)
+				h.backOff()
 			}
 			h.cancelReplicate = nil
 			return
@@ -288,7 +456,15 @@ func (h *commithook) attemptReplicate(ctx context.Context) {
 	}
 
 	lgr.Tracef("cluster/commithook: pushing chunks for root hash %v to destDB", toPush.String())
-	err := destDB.PullChunks(ctx, h.tempDir, h.srcDB, []hash.Hash{toPush}, nil)
+	statsCh := make(chan pull.Stats)
+	statsDone := make(chan struct{})
+	go func() {
+		defer close(statsDone)
+		h.recordPushProgress(statsCh)
+	}()
+	err := destDB.PullChunksWithOptions(ctx, h.tempDir, h.srcDB, []hash.Hash{toPush}, statsCh, h.throttle.pullOptions())
+	close(statsCh)
+	<-statsDone
 	if err == nil {
 		lgr.Tracef("cluster/commithook: successfully pushed chunks, setting root")
 		datasDB := doltdb.HackDatasDatabaseFromDoltDB(destDB)
@@ -308,11 +484,26 @@ func (h *commithook) attemptReplicate(ctx context.Context) {
 	h.mu.Lock()
 	if h.role == RolePrimary {
 		if err == nil {
+			if h.currentError != nil {
+				h.recordEvent(ReplicationEventRecovered, "replication succeeded after "+*h.currentError)
+			}
 			h.currentError = nil
 			lgr.Tracef("cluster/commithook: successfully Committed chunks on destDB")
 			h.lastPushedHead = toPush
 			h.lastSuccess = incomingTime
-			h.nextPushAttempt = time.Time{}
+			if toPush == h.nextHead {
+				// the head may have been read again while we were pushing it
+				readTime = h.nextHeadReadTime
+			}
+			h.lastPushedReadTime = readTime
+			h.publishEvent(ReplicationEventReplicated, "replicated root hash "+toPush.String())
+			h.lastContact = time.Now()
+			h.heartbeatFailures = 0
+			h.nextHeartbeat = h.lastContact.Add(h.heartbeat.interval)
+			h.resetBackOff()
+			if h.quorum != nil {
+				h.quorum.notify()
+			}
 			if len(successChs) != 0 {
 				for _, ch := range successChs {
 					close(ch)
@@ -320,16 +511,118 @@ func (h *commithook) attemptReplicate(ctx context.Context) {
 				successChs = nil
 			}
 		} else {
-			h.currentError = new(string)
-			*h.currentError = fmt.Sprintf("failed to commit chunks on destDB: %v", err)
+			h.recordFailure(fmt.Sprintf("failed to commit chunks on destDB: %v", err))
 			lgr.Warnf("cluster/commithook: failed to commit chunks on destDB: %v", err)
 			// add some delay if a new head didn't come in while we were pushing.
 			if toPush == h.nextHead {
-				// TODO: We could add some backoff here.
-				h.nextPushAttempt = time.Now().Add(1 * time.Second)
+				h.backOff()
+			}
+		}
+	}
+}
+
+// recordPushProgress records the bytes of a push that are waiting to be sent, and records contact with the standby
+// whenever the stats of the push show more bytes sent to it, so that a long push counts as contact for automatic
+// failover.
+func (h *commithook) recordPushProgress(statsCh <-chan pull.Stats) {
+	var sent uint64
+	for s := range statsCh {
+		h.mu.Lock()
+		h.bytesPending = 0
+		if s.BufferedSendBytes > s.FinishedSendBytes {
+			h.bytesPending = s.BufferedSendBytes - s.FinishedSendBytes
+		}
+		if s.FinishedSendBytes > sent {
+			sent = s.FinishedSendBytes
+			if h.role == RolePrimary {
+				h.lastContact = time.Now()
 			}
 		}
+		h.mu.Unlock()
+	}
+	h.mu.Lock()
+	h.bytesPending = 0
+	h.mu.Unlock()
+}
+
+// called with h.mu locked, after an attempt to replicate failed with |msg|. Sets the current error, recording an event
+// if it's a new one.
+func (h *commithook) recordFailure(msg string) {
+	h.failedAttempts++
+	if h.currentError == nil || *h.currentError != msg {
+		h.recordEvent(ReplicationEventError, msg)
 	}
+	h.currentError = &msg
+}
+
+// called with h.mu locked.
+func (h *commithook) recordEvent(event, msg string) {
+	h.events.record(h.newEvent(event, msg))
+}
+
+// called with h.mu locked. Sends an event to the watchers of the database without recording it.
+func (h *commithook) publishEvent(event, msg string) {
+	h.events.publish(h.newEvent(event, msg))
+}
+
+func (h *commithook) newEvent(event, msg string) clusterdb.ReplicationEvent {
+	dbname, remotename := h.dbname, h.remotename
+	return clusterdb.ReplicationEvent{
+		Database: &dbname,
+		Remote:   &remotename,
+		Event:    event,
+		Message:  msg,
+	}
+}
+
+// called with h.mu locked. Delays the next attempt to replicate after a failed one.
+func (h *commithook) backOff() {
+	h.retries++
+	h.currentBackoff = h.backoff.NextBackOff()
+	h.nextPushAttempt = time.Now().Add(h.currentBackoff)
+}
+
+// called with h.mu locked.
+func (h *commithook) resetBackOff() {
+	h.retries = 0
+	h.currentBackoff = 0
+	h.nextPushAttempt = time.Time{}
+	h.backoff.Reset()
+}
+
+// backoffStatus returns the number of attempts to replicate that have failed since the last one that succeeded, and
+// when the next attempt will be made if it's been delayed.
+func (h *commithook) backoffStatus() (retries int, nextAttempt *time.Time, currentBackoff *time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	retries = h.retries
+	if h.role == RolePrimary && h.nextPushAttempt != (time.Time{}) {
+		nextAttempt = new(time.Time)
+		*nextAttempt = h.nextPushAttempt
+		currentBackoff = new(time.Duration)
+		*currentBackoff = h.currentBackoff
+	}
+	return
+}
+
+// pushStatus returns the head waiting to be pushed to the standby, the last head pushed to it, and the number of
+// bytes of the running push that haven't been sent yet. The heads are nil when this server is a standby, or when
+// there isn't one yet.
+func (h *commithook) pushStatus() (nextHead, lastPushedHead *string, bytesPending uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.role != RolePrimary {
+		return nil, nil, 0
+	}
+	if h.nextHead != (hash.Hash{}) {
+		nextHead = new(string)
+		*nextHead = h.nextHead.String()
+	}
+	if h.lastPushedHead != (hash.Hash{}) {
+		lastPushedHead = new(string)
+		*lastPushedHead = h.lastPushedHead.String()
+	}
+	return nextHead, lastPushedHead, h.bytesPending
 }
 
 func (h *commithook) status() (replicationLag *time.Duration, lastUpdate *time.Time, currentErr *string) {
@@ -367,30 +660,30 @@ func (h *commithook) logger() *logrus.Entry {
 	return h.lgr.Load().(*logrus.Entry)
 }
 
-// TODO: Would be more efficient to only tick when we have outstanding work...
-func (h *commithook) tick(ctx context.Context) {
-	defer h.wg.Done()
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-	for !h.shutdown.Load() {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			h.cond.Signal()
-		}
-	}
+// totalFailedAttempts returns the number of attempts to replicate to the standby that have failed since the
+// commithook was created.
+func (h *commithook) totalFailedAttempts() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.failedAttempts
+}
+
+// contactedSince returns true if a push or a heartbeat to the standby succeeded after |t|.
+func (h *commithook) contactedSince(t time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lastContact.After(t)
 }
 
 func (h *commithook) databaseWasDropped() {
 	h.shutdown.Store(true)
-	h.cond.Signal()
+	h.wake()
 }
 
 func (h *commithook) recordSuccessfulRemoteSrvCommit() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	if h.role != RoleStandby {
+	if h.role != RoleStandby && h.role != RoleObserver {
 		return
 	}
 	h.lastSuccess = time.Now()
@@ -406,8 +699,16 @@ func (h *commithook) setRole(role Role) {
 	h.nextHead = hash.Hash{}
 	h.lastPushedHead = hash.Hash{}
 	h.lastSuccess = time.Time{}
-	h.nextPushAttempt = time.Time{}
+	h.nextHeadReadTime = time.Time{}
+	h.lastPushedReadTime = time.Time{}
+	h.lastContact = time.Time{}
+	h.nextHeartbeat = time.Time{}
+	h.heartbeatFailures = 0
+	h.resetBackOff()
 	h.role = role
+	if h.quorum != nil {
+		h.quorum.notify()
+	}
 	h.lgr.Store(h.rootLgr.WithField(logFieldRole, string(role)))
 	if h.cancelReplicate != nil {
 		h.cancelReplicate()
@@ -440,6 +741,7 @@ func (h *commithook) Execute(ctx context.Context, ds datas.Dataset, db datas.Dat
 	lgr := h.logger()
 	lgr.Tracef("cluster/commithook: Execute called post commit")
 	cs := datas.ChunkStoreFromDatabase(db)
+	readTime := time.Now()
 	root, err := cs.Root(ctx)
 	if err != nil {
 		lgr.Errorf("cluster/commithook: Execute: error retrieving local database root: %v", err)
@@ -456,12 +758,21 @@ func (h *commithook) Execute(ctx context.Context, ds datas.Dataset, db datas.Dat
 		lgr.Tracef("signaling replication thread to push new head: %v", root.String())
 		h.nextHeadIncomingTime = time.Now()
 		h.nextHead = root
+		h.nextHeadReadTime = readTime
 		h.nextPushAttempt = time.Time{}
 		h.cond.Signal()
+	} else if readTime.After(h.nextHeadReadTime) {
+		h.nextHeadReadTime = readTime
+	}
+	if root == h.lastPushedHead && readTime.After(h.lastPushedReadTime) {
+		h.lastPushedReadTime = readTime
+	}
+	if h.quorum != nil {
+		return h.quorum.waitF(h.dbname, readTime), nil
 	}
 	var waitF func(context.Context) error
 	if !h.isCaughtUp() {
-		if h.fastFailReplicationWait {
+		if h.circuitBreakerOpen() {
 			waitF = func(ctx context.Context) error {
 				return fmt.Errorf("circuit breaker for replication to %s/%s is open. this commit did not necessarily replicate successfully.", h.remotename, h.dbname)
 			}
@@ -483,10 +794,21 @@ func (h *commithook) Execute(ctx context.Context, ds datas.Dataset, db datas.Dat
 	return waitF, nil
 }
 
+// NotifyWaitFailed is called when a commit timed out waiting for the standby. It opens the circuit breaker once enough
+// commits have, or opens it again if it's half-open.
 func (h *commithook) NotifyWaitFailed() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	h.fastFailReplicationWait = true
+	h.waitFailures++
+	if h.fastFailReplicationWait || h.breaker.opens(h.waitFailures) {
+		h.fastFailReplicationWait = true
+		h.breakerOpenedAt = time.Now()
+	}
+}
+
+// called with h.mu locked. Returns true if commits that wait for the standby should fail fast.
+func (h *commithook) circuitBreakerOpen() bool {
+	return h.fastFailReplicationWait && h.breaker.failsFast(h.breakerOpenedAt)
 }
 
 func (h *commithook) HandleError(ctx context.Context, err error) error {
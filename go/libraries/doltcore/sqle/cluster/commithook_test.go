@@ -16,13 +16,17 @@ package cluster
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 func TestCommitHookStartsNotCaughtUp(t *testing.T) {
@@ -35,9 +39,155 @@ func TestCommitHookStartsNotCaughtUp(t *testing.T) {
 		destEnv.DoltDB.Close()
 	})
 
-	hook := newCommitHook(logrus.StandardLogger(), "origin", "https://localhost:50051/mydb", "mydb", RolePrimary, func(context.Context) (*doltdb.DoltDB, error) {
+	hook := newCommitHook(logrus.StandardLogger(), "origin", "https://localhost:50051/mydb", "mydb", RolePrimary, nil, nil, nil, nil, nil, func(context.Context) (*doltdb.DoltDB, error) {
 		return destEnv.DoltDB, nil
 	}, srcEnv.DoltDB, t.TempDir())
 
 	require.False(t, hook.isCaughtUp())
 }
+
+type testBackoffConfig struct{}
+
+func (testBackoffConfig) InitialInterval() time.Duration { return 100 * time.Millisecond }
+func (testBackoffConfig) Multiplier() float64            { return 2 }
+func (testBackoffConfig) MaxInterval() time.Duration     { return 300 * time.Millisecond }
+func (testBackoffConfig) Jitter() float64                { return 0 }
+
+func TestCommitHookBackOff(t *testing.T) {
+	hook := &commithook{role: RolePrimary, backoff: newReplicationBackOff(testBackoffConfig{})}
+
+	// each failed attempt waits longer, up to the max interval
+	var delays []time.Duration
+	for i := 0; i < 4; i++ {
+		start := time.Now()
+		hook.backOff()
+		delays = append(delays, hook.nextPushAttempt.Sub(start).Round(100*time.Millisecond))
+	}
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}, delays)
+	retries, next, current := hook.backoffStatus()
+	assert.Equal(t, 4, retries)
+	assert.NotNil(t, next)
+	if assert.NotNil(t, current) {
+		assert.Equal(t, 300*time.Millisecond, *current)
+	}
+
+	// a successful attempt starts over
+	hook.resetBackOff()
+	retries, next, current = hook.backoffStatus()
+	assert.Equal(t, 0, retries)
+	assert.Nil(t, next)
+	assert.Nil(t, current)
+	start := time.Now()
+	hook.backOff()
+	assert.Equal(t, 100*time.Millisecond, hook.nextPushAttempt.Sub(start).Round(100*time.Millisecond))
+
+	// by default, attempts are retried every second
+	bo := newReplicationBackOff(nil)
+	assert.Equal(t, time.Second, bo.NextBackOff())
+	assert.Equal(t, time.Second, bo.NextBackOff())
+}
+
+func TestCommitHookReplicationEvents(t *testing.T) {
+	events := newReplicationEventLog(RolePrimary, 1)
+	hook := &commithook{dbname: "mydb", remotename: "standby", role: RolePrimary, events: events}
+
+	// repeating the same error doesn't record another event
+	hook.recordFailure("failed to commit chunks on destDB: connection refused")
+	hook.recordFailure("failed to commit chunks on destDB: connection refused")
+	hook.recordFailure("failed to commit chunks on destDB: timed out")
+	assert.Equal(t, uint64(3), hook.totalFailedAttempts())
+	got := events.get()
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, ReplicationEventError, got[0].Event)
+		assert.Equal(t, "mydb", *got[0].Database)
+		assert.Equal(t, "standby", *got[0].Remote)
+		assert.Equal(t, "primary", got[0].Role)
+		assert.Equal(t, 1, got[0].Epoch)
+		assert.Equal(t, "failed to commit chunks on destDB: timed out", got[1].Message)
+	}
+
+	events.setRole(RoleStandby, 2)
+	got = events.get()
+	if assert.Len(t, got, 3) {
+		assert.Equal(t, ReplicationEventRoleChange, got[2].Event)
+		assert.Nil(t, got[2].Database)
+		assert.Equal(t, "standby", got[2].Role)
+		assert.Equal(t, 2, got[2].Epoch)
+	}
+}
+
+func TestCommitHookScheduleWakeup(t *testing.T) {
+	hook := &commithook{role: RoleStandby, heartbeat: newHeartbeatPolicy(nil)}
+	hook.cond = sync.NewCond(&hook.mu)
+
+	// a standby never has outstanding work
+	assert.Nil(t, hook.scheduleWakeup())
+
+	// a primary wakes up to retry a failed attempt to replicate
+	hook.role = RolePrimary
+	hook.nextHead = hash.Of([]byte("next"))
+	hook.nextPushAttempt = time.Now().Add(time.Hour)
+	if wakeup := hook.scheduleWakeup(); assert.NotNil(t, wakeup) {
+		wakeup.Stop()
+	}
+
+	// but not to heartbeat to a standby it hasn't replicated to yet
+	hook.lastPushedHead = hook.nextHead
+	assert.True(t, hook.isCaughtUp())
+	assert.Nil(t, hook.scheduleWakeup())
+	assert.False(t, hook.heartbeatDue())
+}
+
+func TestCommitHookHeartbeatFailures(t *testing.T) {
+	events := newReplicationEventLog(RolePrimary, 1)
+	hook := &commithook{dbname: "mydb", remotename: "standby", role: RolePrimary, events: events, heartbeat: newHeartbeatPolicy(nil)}
+
+	// contact without failed heartbeats doesn't record anything
+	hook.resetHeartbeatFailures()
+	assert.Empty(t, events.get())
+
+	msg := "standby is unreachable"
+	hook.heartbeatFailures = DefaultHeartbeatFailureThreshold
+	hook.currentError = &msg
+	hook.resetHeartbeatFailures()
+	assert.Nil(t, hook.currentError)
+	assert.Equal(t, 0, hook.heartbeatFailures)
+	got := events.get()
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, ReplicationEventRecovered, got[0].Event)
+	}
+}
+
+type testCircuitBreakerConfig struct{}
+
+func (testCircuitBreakerConfig) FailureThreshold() int        { return 2 }
+func (testCircuitBreakerConfig) ProbeInterval() time.Duration { return 50 * time.Millisecond }
+
+func TestCommitHookCircuitBreaker(t *testing.T) {
+	breaker := newReplicationCircuitBreaker(testCircuitBreakerConfig{})
+	hook := &commithook{role: RolePrimary, breaker: breaker}
+
+	// the breaker opens once enough commits in a row time out
+	hook.NotifyWaitFailed()
+	assert.False(t, hook.circuitBreakerOpen())
+	hook.NotifyWaitFailed()
+	assert.True(t, hook.circuitBreakerOpen())
+
+	// it's half-open after the probe interval, and a commit that times out then opens it again
+	time.Sleep(60 * time.Millisecond)
+	assert.False(t, hook.circuitBreakerOpen())
+	hook.NotifyWaitFailed()
+	assert.True(t, hook.circuitBreakerOpen())
+
+	// commits always wait when fast fail is disabled
+	breaker.fastFail.Store(false)
+	assert.False(t, hook.circuitBreakerOpen())
+	breaker.fastFail.Store(true)
+
+	// by default, the breaker opens after one timeout and stays open
+	hook = &commithook{role: RolePrimary, breaker: newReplicationCircuitBreaker(nil)}
+	hook.NotifyWaitFailed()
+	assert.True(t, hook.circuitBreakerOpen())
+	hook.breakerOpenedAt = time.Now().Add(-time.Hour)
+	assert.True(t, hook.circuitBreakerOpen())
+}
@@ -14,11 +14,36 @@
 
 package cluster
 
+import "time"
+
 type Config interface {
 	StandbyRemotes() []StandbyRemoteConfig
 	BootstrapRole() string
 	BootstrapEpoch() int
 	RemotesAPIConfig() RemotesAPIConfig
+	// ReadRoutingConfig is the configuration of the listener that routes connections for reads to standbys, or nil
+	// if there isn't one.
+	ReadRoutingConfig() ReadRoutingConfig
+	// ReplicationBackoffConfig is the policy standby replication retries with after it fails, or nil for the default.
+	ReplicationBackoffConfig() BackoffConfig
+	// ReplicationThrottleConfig limits the bandwidth standby replication uses, or is nil if it isn't limited.
+	ReplicationThrottleConfig() ThrottleConfig
+	// ReplicationHeartbeatConfig is how a primary heartbeats to the standbys it's caught up with, or nil for the
+	// default.
+	ReplicationHeartbeatConfig() HeartbeatConfig
+	// ReplicationCircuitBreakerConfig is when commits stop waiting for standbys that aren't acking them, or nil for
+	// the default.
+	ReplicationCircuitBreakerConfig() CircuitBreakerConfig
+	// AutomaticFailoverConfig enables automatic failover between the servers in the cluster, or is nil if roles only
+	// change when dolt_assume_cluster_role is called.
+	AutomaticFailoverConfig() FailoverConfig
+	// RequiredAcks is the number of standbys a commit has to be replicated to before it returns, or 0 for all of them.
+	RequiredAcks() int
+	// StandbyBootstrapURLTemplate is the URL of a backup of each database, with {database} in place of its name. When
+	// the primary first replicates a database to this server as a standby, the database is restored from its backup,
+	// so that the primary only has to push what was committed since the backup. If it's empty, the primary pushes the
+	// database's entire history.
+	StandbyBootstrapURLTemplate() string
 }
 
 type RemotesAPIConfig interface {
@@ -27,6 +52,12 @@ type RemotesAPIConfig interface {
 	TLSKey() string
 	TLSCert() string
 	TLSCA() string
+	// TLSClientCert and TLSClientKey are the certificate and key presented to standbys when replicating to them.
+	TLSClientCert() string
+	TLSClientKey() string
+	// TLSClientCA is the CA bundle that the certificates clients present to this server are verified against. If
+	// it's set, clients must present one.
+	TLSClientCA() string
 	ServerNameURLMatches() []string
 	ServerNameDNSMatches() []string
 }
@@ -34,4 +65,68 @@ type RemotesAPIConfig interface {
 type StandbyRemoteConfig interface {
 	Name() string
 	RemoteURLTemplate() string
+	// SQLAddress is the host:port of the standby's sql-server, which connections for reads are routed to.
+	SQLAddress() string
+}
+
+// BackoffConfig is an exponential backoff policy. After each failed attempt, the delay before the next one is
+// multiplied by Multiplier, up to MaxInterval, and then randomly varied by up to Jitter times itself.
+type BackoffConfig interface {
+	InitialInterval() time.Duration
+	Multiplier() float64
+	MaxInterval() time.Duration
+	Jitter() float64
+}
+
+// HeartbeatConfig configures the heartbeats a primary sends to each standby once it has replicated everything to it.
+// Heartbeats count as contact with the standby for automatic failover.
+type HeartbeatConfig interface {
+	// Interval is how long the primary waits after it last contacted the standby before it heartbeats to it.
+	Interval() time.Duration
+	// Timeout is how long the primary waits for a heartbeat to succeed.
+	Timeout() time.Duration
+	// FailureThreshold is the number of heartbeats that fail in a row before the standby is reported as unreachable.
+	FailureThreshold() int
+}
+
+// CircuitBreakerConfig configures the circuit breaker of the replication to each standby. While it's open, commits fail
+// fast instead of waiting for the standby to ack them, until the standby catches up. Fast fail can be disabled at
+// runtime with the dolt_cluster_replication_fast_fail system variable.
+type CircuitBreakerConfig interface {
+	// FailureThreshold is the number of commits in a row that time out waiting for the standby before the breaker
+	// opens.
+	FailureThreshold() int
+	// ProbeInterval is how long the breaker stays open before it's half-open, and commits wait for the standby again
+	// until one times out. If it's 0, the breaker stays open until the standby catches up.
+	ProbeInterval() time.Duration
+}
+
+// ThrottleConfig limits the bandwidth standby replication uses. Both settings can be changed at runtime with the
+// dolt_cluster_replication_max_bytes_per_sec and dolt_cluster_replication_chunks_per_batch system variables.
+type ThrottleConfig interface {
+	// MaxBytesPerSec is the most bytes per second sent to all of the standbys together, or 0 for no limit.
+	MaxBytesPerSec() int64
+	// ChunksPerBatch is the most chunks sent to a standby in each table file, or 0 for the default.
+	ChunksPerBatch() int
+}
+
+// FailoverConfig configures automatic failover, in which a standby is elected primary when the primary stops
+// replicating to it. Failover requires at least three servers in the cluster, so that a majority of them can elect a
+// primary.
+type FailoverConfig interface {
+	// LeaseDuration is how long a primary keeps accepting writes after it last replicated to a majority of the
+	// cluster. Standbys stand for election after they haven't heard from a primary for twice as long.
+	LeaseDuration() time.Duration
+	// Priority delays when this server stands for election by that many lease durations. Servers that should be
+	// preferred as primary have lower priorities.
+	Priority() int
+}
+
+type ReadRoutingConfig interface {
+	Address() string
+	Port() int
+	// MaxLag is how far a standby's replication can lag behind before connections are no longer routed to it.
+	MaxLag() time.Duration
+	// ProxyProtocol is true if the standbys' SQL listeners expect connections to begin with a PROXY protocol header.
+	ProxyProtocol() bool
 }
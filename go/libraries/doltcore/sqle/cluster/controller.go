@@ -24,7 +24,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -58,6 +57,10 @@ type Role string
 
 const RolePrimary Role = "primary"
 const RoleStandby Role = "standby"
+
+// RoleObserver is a standby that's never promoted to primary: it doesn't stand for election in automatic failover,
+// and dolt_assume_cluster_role doesn't make it primary until it's made a standby first.
+const RoleObserver Role = "observer"
 const RoleDetectedBrokenConfig Role = "detected_broken_config"
 
 const PersistentConfigPrefix = "sqlserver.cluster"
@@ -70,6 +73,16 @@ type Controller struct {
 	systemVars    sqlvars
 	mu            sync.Mutex
 	commithooks   []*commithook
+	throttle      *replicationThrottle
+	breaker       *replicationCircuitBreaker
+	events        *replicationEventLog
+	// bootstrapMu is held while standby databases are bootstrapped from their backups.
+	bootstrapMu sync.Mutex
+	// transitionPhase is the phase of the graceful transition to standby in progress, or empty if there isn't one.
+	transitionPhase string
+	// elector is set when automatic failover is enabled.
+	elector       *elector
+	stopElections chan struct{}
 	sinterceptor  serverinterceptor
 	cinterceptor  clientinterceptor
 	lgr           *logrus.Logger
@@ -130,6 +143,9 @@ func NewController(lgr *logrus.Logger, cfg Config, pCfg config.ReadWriteConfig)
 		role:          role,
 		epoch:         epoch,
 		commithooks:   make([]*commithook, 0),
+		throttle:      newReplicationThrottle(cfg.ReplicationThrottleConfig()),
+		breaker:       newReplicationCircuitBreaker(cfg.ReplicationCircuitBreakerConfig()),
+		events:        newReplicationEventLog(role, epoch),
 		lgr:           lgr,
 	}
 	roleSetter := func(role string, epoch int) {
@@ -144,6 +160,13 @@ func NewController(lgr *logrus.Logger, cfg Config, pCfg config.ReadWriteConfig)
 	ret.cinterceptor.setRole(role, epoch)
 	ret.cinterceptor.roleSetter = roleSetter
 
+	if fcfg := cfg.AutomaticFailoverConfig(); fcfg != nil {
+		ret.elector = newElector(fcfg)
+		ret.stopElections = make(chan struct{})
+		ret.sinterceptor.elector = ret.elector
+		ret.cinterceptor.failover = true
+	}
+
 	ret.tlsCfg, err = ret.outboundTlsConfig()
 	if err != nil {
 		return nil, err
@@ -206,6 +229,13 @@ func (c *Controller) Run() {
 		defer wg.Done()
 		c.bcReplication.Run()
 	}()
+	if c.elector != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.runElections()
+		}()
+	}
 	wg.Wait()
 }
 
@@ -213,6 +243,9 @@ func (c *Controller) GracefulStop() error {
 	c.jwks.GracefulStop()
 	c.mysqlDbPersister.GracefulStop()
 	c.bcReplication.GracefulStop()
+	if c.stopElections != nil {
+		close(c.stopElections)
+	}
 	return nil
 }
 
@@ -224,6 +257,8 @@ func (c *Controller) ManageSystemVariables(variables sqlvars) {
 	defer c.mu.Unlock()
 	c.systemVars = variables
 	c.refreshSystemVars()
+	c.systemVars.AddSystemVariables(c.throttleSystemVars())
+	c.systemVars.AddSystemVariables(c.circuitBreakerSystemVars())
 }
 
 func (c *Controller) ApplyStandbyReplicationConfig(ctx context.Context, bt *sql.BackgroundThreads, mrEnv *env.MultiRepoEnv, dbs ...dsess.SqlDatabase) error {
@@ -281,14 +316,18 @@ func (c *Controller) applyCommitHooks(ctx context.Context, name string, bt *sql.
 	}
 	dialprovider := c.gRPCDialProvider(denv)
 	var hooks []*commithook
+	quorum := c.ackQuorum()
 	for _, r := range c.cfg.StandbyRemotes() {
 		remote, ok := remotes[r.Name()]
 		if !ok {
 			return nil, fmt.Errorf("sqle: cluster: standby replication: destination remote %s does not exist on database %s", r.Name(), name)
 		}
-		commitHook := newCommitHook(c.lgr, r.Name(), remote.Url, name, c.role, func(ctx context.Context) (*doltdb.DoltDB, error) {
+		commitHook := newCommitHook(c.lgr, r.Name(), remote.Url, name, c.role, c.cfg.ReplicationBackoffConfig(), c.cfg.ReplicationHeartbeatConfig(), c.throttle, c.breaker, c.events, func(ctx context.Context) (*doltdb.DoltDB, error) {
 			return remote.GetRemoteDB(ctx, types.Format_Default, dialprovider)
 		}, denv.DoltDB, ttfdir)
+		if quorum != nil {
+			quorum.add(commitHook)
+		}
 		denv.DoltDB.PrependCommitHook(ctx, commitHook)
 		if err := commitHook.Run(bt); err != nil {
 			return nil, err
@@ -298,6 +337,16 @@ func (c *Controller) applyCommitHooks(ctx context.Context, name string, bt *sql.
 	return hooks, nil
 }
 
+// ackQuorum returns the quorum shared by the commithooks of a database when commits only have to be replicated to
+// some of the standbys, or nil if they have to be replicated to all of them.
+func (c *Controller) ackQuorum() *ackQuorum {
+	required := c.cfg.RequiredAcks()
+	if required <= 0 || required >= len(c.cfg.StandbyRemotes()) {
+		return nil
+	}
+	return newAckQuorum(required)
+}
+
 func (c *Controller) gRPCDialProvider(denv *env.DoltEnv) dbfactory.GRPCDialProvider {
 	return grpcDialProvider{env.NewGRPCDialProviderFromDoltEnv(denv), &c.cinterceptor, c.tlsCfg, c.grpcCreds}
 }
@@ -308,12 +357,23 @@ func (c *Controller) RegisterStoredProcedures(store procedurestore) {
 	}
 	store.Register(newAssumeRoleProcedure(c))
 	store.Register(newTransitionToStandbyProcedure(c))
+	store.Register(newPromoteProcedure(c))
+	store.Register(newDemoteProcedure(c))
 }
 
-func (c *Controller) DropDatabaseHook(dbname string) {
+// DropDatabaseHook returns a sqle.DropDatabaseHook that stops replicating dropped databases, or nil if there's no
+// controller.
+func (c *Controller) DropDatabaseHook() sqle.DropDatabaseHook {
 	if c == nil {
-		return
+		return nil
 	}
+	return func(dbname string) error {
+		c.dropDatabase(dbname)
+		return nil
+	}
+}
+
+func (c *Controller) dropDatabase(dbname string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -370,6 +430,53 @@ func (c *Controller) refreshSystemVars() {
 	c.systemVars.AddSystemVariables(vars)
 }
 
+// throttleSystemVars returns the system variables that change the settings of |c.throttle| at runtime. They're
+// added once, so that refreshing the role variables doesn't reset them.
+func (c *Controller) throttleSystemVars() []sql.SystemVariable {
+	return []sql.SystemVariable{
+		{
+			Name:    dsess.DoltClusterReplicationMaxBytesPerSec,
+			Dynamic: true,
+			Scope:   sql.SystemVariableScope_Global,
+			Type:    gmstypes.NewSystemIntType(dsess.DoltClusterReplicationMaxBytesPerSec, 0, 9223372036854775807, false),
+			Default: c.throttle.maxBytesPerSec(),
+			NotifyChanged: func(_ sql.SystemVariableScope, v sql.SystemVarValue) error {
+				c.throttle.setMaxBytesPerSec(v.Val.(int64))
+				return nil
+			},
+		},
+		{
+			Name:    dsess.DoltClusterReplicationChunksPerBatch,
+			Dynamic: true,
+			Scope:   sql.SystemVariableScope_Global,
+			Type:    gmstypes.NewSystemIntType(dsess.DoltClusterReplicationChunksPerBatch, 0, 2147483647, false),
+			Default: c.throttle.chunksPerBatch.Load(),
+			NotifyChanged: func(_ sql.SystemVariableScope, v sql.SystemVarValue) error {
+				c.throttle.setChunksPerBatch(v.Val.(int64))
+				return nil
+			},
+		},
+	}
+}
+
+// circuitBreakerSystemVars returns the system variables that change the settings of |c.breaker| at runtime. Like the
+// throttle's, they're added once.
+func (c *Controller) circuitBreakerSystemVars() []sql.SystemVariable {
+	return []sql.SystemVariable{
+		{
+			Name:    dsess.DoltClusterReplicationFastFail,
+			Dynamic: true,
+			Scope:   sql.SystemVariableScope_Global,
+			Type:    gmstypes.NewSystemBoolType(dsess.DoltClusterReplicationFastFail),
+			Default: int8(1),
+			NotifyChanged: func(_ sql.SystemVariableScope, v sql.SystemVarValue) error {
+				c.breaker.fastFail.Store(v.Val.(int8) == 1)
+				return nil
+			},
+		},
+	}
+}
+
 func (c *Controller) persistVariables() error {
 	toset := make(map[string]string)
 	toset[dsess.DoltClusterRoleVariable] = string(c.role)
@@ -402,10 +509,10 @@ func applyBootstrapClusterConfig(lgr *logrus.Logger, cfg Config, pCfg config.Rea
 	} else {
 		lgr.Tracef("cluster/controller: persisted cluster role epoch is %s", persistentEpoch)
 	}
-	if persistentRole != string(RolePrimary) && persistentRole != string(RoleStandby) {
+	if persistentRole != string(RolePrimary) && persistentRole != string(RoleStandby) && persistentRole != string(RoleObserver) {
 		isallowed := persistentRole == string(RoleDetectedBrokenConfig) && roleFromPersistentConfig
 		if !isallowed {
-			return "", 0, fmt.Errorf("persisted role %s.%s = %s must be \"primary\" or \"secondary\"", PersistentConfigPrefix, dsess.DoltClusterRoleVariable, persistentRole)
+			return "", 0, fmt.Errorf("persisted role %s.%s = %s must be \"primary\", \"standby\" or \"observer\"", PersistentConfigPrefix, dsess.DoltClusterRoleVariable, persistentRole)
 		}
 	}
 	epochi, err := strconv.Atoi(persistentEpoch)
@@ -435,6 +542,19 @@ type roleTransitionOptions struct {
 	// If non-nil, this connection will be saved if and when the connection
 	// process needs to terminate existing connections.
 	saveConnID *int
+
+	// If true, this server is transitioning to primary as a candidate in an
+	// automatic failover election, and stays read only until a majority of
+	// the cluster accepts it.
+	candidate bool
+
+	// If non-nil, this server must be at this epoch, and in |expectedRole|
+	// if it's set, for the transition to happen. They are the fencing
+	// token of dolt_cluster_promote() and dolt_cluster_demote(), which
+	// refuse to act on a server whose role changed since the caller last
+	// looked at it.
+	expectedEpoch *int
+	expectedRole  Role
 }
 
 type roleTransitionResult struct {
@@ -455,12 +575,22 @@ func (c *Controller) setRoleAndEpoch(role string, epoch int, opts roleTransition
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if opts.expectedEpoch != nil && (*opts.expectedEpoch != c.epoch || (opts.expectedRole != "" && opts.expectedRole != c.role)) {
+		expected := fmt.Sprintf("epoch %d", *opts.expectedEpoch)
+		if opts.expectedRole != "" {
+			expected = fmt.Sprintf("role '%s' at %s", opts.expectedRole, expected)
+		}
+		return roleTransitionResult{false, nil}, fmt.Errorf("error assuming role '%s' at epoch %d; expected this server to be in %s, but it is in role '%s' at epoch %d", role, epoch, expected, c.role, c.epoch)
+	}
 	if epoch == c.epoch && role == string(c.role) {
 		return roleTransitionResult{false, nil}, nil
 	}
 
-	if role != string(RolePrimary) && role != string(RoleStandby) && role != string(RoleDetectedBrokenConfig) {
-		return roleTransitionResult{false, nil}, fmt.Errorf("error assuming role '%s'; valid roles are 'primary' and 'standby'", role)
+	if role != string(RolePrimary) && role != string(RoleStandby) && role != string(RoleObserver) && role != string(RoleDetectedBrokenConfig) {
+		return roleTransitionResult{false, nil}, fmt.Errorf("error assuming role '%s'; valid roles are 'primary', 'standby' and 'observer'", role)
+	}
+	if role == string(RolePrimary) && c.role == RoleObserver {
+		return roleTransitionResult{false, nil}, fmt.Errorf("error assuming role '%s' at epoch %d; this server is an observer, which is never promoted to primary. it must assume the role 'standby' first", role, epoch)
 	}
 
 	if epoch < c.epoch {
@@ -480,7 +610,7 @@ func (c *Controller) setRoleAndEpoch(role string, epoch int, opts roleTransition
 
 	if changedrole {
 		var err error
-		if role == string(RoleStandby) {
+		if role == string(RoleStandby) || role == string(RoleObserver) {
 			if graceful {
 				beforeRole, beforeEpoch := c.role, c.epoch
 				gracefulResults, err = c.gracefulTransitionToStandby(saveConnID, opts.minCaughtUpStandbys)
@@ -498,6 +628,8 @@ func (c *Controller) setRoleAndEpoch(role string, epoch int, opts roleTransition
 			}
 		} else if role == string(RoleDetectedBrokenConfig) {
 			c.immediateTransitionToStandby()
+		} else if opts.candidate {
+			c.killRunningQueries(saveConnID)
 		} else {
 			c.transitionToPrimary(saveConnID)
 		}
@@ -505,10 +637,19 @@ func (c *Controller) setRoleAndEpoch(role string, epoch int, opts roleTransition
 
 	c.role = Role(role)
 	c.epoch = epoch
+	c.events.setRole(c.role, c.epoch)
 
 	c.refreshSystemVars()
 	c.cinterceptor.setRole(c.role, c.epoch)
 	c.sinterceptor.setRole(c.role, c.epoch)
+	if c.elector != nil {
+		c.elector.roleChanged(c.role, opts.candidate)
+		electionID := ""
+		if c.role == RolePrimary && opts.candidate {
+			electionID = c.elector.id
+		}
+		c.cinterceptor.setElectionID(electionID)
+	}
 	if changedrole {
 		for _, h := range c.commithooks {
 			h.setRole(c.role)
@@ -541,12 +682,15 @@ func (c *Controller) GetClusterStatus() []clusterdb.ReplicaStatus {
 	}
 	c.mu.Lock()
 	epoch, role := c.epoch, c.role
+	phase := c.transitionPhase
 	commithooks := make([]*commithook, len(c.commithooks))
 	copy(commithooks, c.commithooks)
 	c.mu.Unlock()
 	ret := make([]clusterdb.ReplicaStatus, len(commithooks))
 	for i, c := range commithooks {
 		lag, lastUpdate, currentErrorStr := c.status()
+		retries, nextRetry, currentBackoff := c.backoffStatus()
+		nextHead, lastPushedHead, bytesPending := c.pushStatus()
 		ret[i] = clusterdb.ReplicaStatus{
 			Database:       c.dbname,
 			Remote:         c.remotename,
@@ -555,6 +699,23 @@ func (c *Controller) GetClusterStatus() []clusterdb.ReplicaStatus {
 			ReplicationLag: lag,
 			LastUpdate:     lastUpdate,
 			CurrentError:   currentErrorStr,
+			RetryAttempts:  retries,
+			NextRetry:      nextRetry,
+			NextHead:       nextHead,
+			LastPushedHead: lastPushedHead,
+			CurrentBackoff: currentBackoff,
+			BytesPending:   bytesPending,
+			FailedAttempts: c.totalFailedAttempts(),
+		}
+		if phase != "" {
+			state := phase
+			if phase == transitionPhaseReplicating {
+				state = "catching_up"
+				if c.caughtUp() {
+					state = "caught_up"
+				}
+			}
+			ret[i].TransitionState = &state
 		}
 	}
 	return ret
@@ -579,7 +740,7 @@ func (c *Controller) RemoteSrvServerArgs(ctx *sql.Context, args remotesrv.Server
 	args.GrpcListenAddr = listenaddr
 	args.Options = c.ServerOptions()
 	args = sqle.RemoteSrvServerArgs(ctx, args)
-	args.DBCache = remotesrvStoreCache{args.DBCache, c}
+	args.DBCache = remotesrvStoreCache{args.DBCache, c, ctx}
 
 	keyID := creds.PubKeyToKID(c.pub)
 	keyIDStr := creds.B32CredsEncoding.EncodeToString(keyID)
@@ -640,21 +801,38 @@ func (c *Controller) RegisterGrpcServices(srv *grpc.Server) {
 		branchControl:        c.branchControlController,
 		branchControlFilesys: c.branchControlFilesys,
 		lgr:                  c.lgr.WithFields(logrus.Fields{}),
+		roleAndEpoch:         c.roleAndEpoch,
 	})
 }
 
 // TODO: make the deadline here configurable or something.
 const waitForHooksToReplicateTimeout = 10 * time.Second
 
+// drainTransactionsTimeout is how long a graceful transition to standby waits for the transactions open when it
+// starts to finish, before it kills the connections they're open on.
+const drainTransactionsTimeout = 5 * time.Second
+
+// The phases of a graceful transition to standby, reported in dolt_cluster_status.
+const (
+	// waiting for open transactions to finish
+	transitionPhaseDraining = "draining"
+	// waiting for the commithooks to replicate to their standbys
+	transitionPhaseReplicating = "replicating"
+)
+
 type graceTransitionResult struct {
 	caughtUp  bool
 	database  string
 	remote    string
 	remoteUrl string
+	// abortedTransactions is the number of transactions that were still open when the drain timed out, and whose
+	// connections were killed.
+	abortedTransactions int
 }
 
 // The order of operations is:
 // * Set all databases in database_provider to read-only.
+// * Wait, up to drainTransactionsTimeout, for open transactions to finish.
 // * Kill all running queries in GMS.
 // * Replicate all databases to their standby remotes.
 //   - If success, return success.
@@ -667,11 +845,21 @@ type graceTransitionResult struct {
 //
 // called with c.mu held
 func (c *Controller) gracefulTransitionToStandby(saveConnID, minCaughtUpStandbys int) ([]graceTransitionResult, error) {
+	defer func() {
+		c.transitionPhase = ""
+	}()
 	c.setProviderIsStandby(true)
+
+	// drainTransactions and waitForHooksToReplicate will release
+	// the lock while they block, but will return with the lock held.
+	c.transitionPhase = transitionPhaseDraining
+	aborted := c.drainTransactions(saveConnID, drainTransactionsTimeout)
+	if aborted > 0 {
+		c.lgr.Warnf("cluster/controller: %d transactions were still open after waiting %v for them to finish. killing their connections.", aborted, drainTransactionsTimeout)
+	}
 	c.killRunningQueries(saveConnID)
 
-	// waitForHooksToReplicate will release the lock while it
-	// blocks, but will return with the lock held.
+	c.transitionPhase = transitionPhaseReplicating
 	states, err := c.waitForHooksToReplicate(waitForHooksToReplicateTimeout)
 	if err != nil {
 		return nil, err
@@ -686,10 +874,11 @@ func (c *Controller) gracefulTransitionToStandby(saveConnID, minCaughtUpStandbys
 	for i := range states {
 		hook := c.commithooks[i]
 		res[i] = graceTransitionResult{
-			caughtUp:  states[i],
-			database:  hook.dbname,
-			remote:    hook.remotename,
-			remoteUrl: hook.remoteurl,
+			caughtUp:            states[i],
+			database:            hook.dbname,
+			remote:              hook.remotename,
+			remoteUrl:           hook.remoteurl,
+			abortedTransactions: aborted,
 		}
 	}
 
@@ -766,6 +955,34 @@ func (c *Controller) transitionToPrimary(saveConnID int) error {
 	return nil
 }
 
+// Waits until no session other than |saveConnID| has an open transaction, or
+// until |timeout| passes, and returns the number of sessions that still do.
+// New transactions can't write once the provider is standby, so the sessions
+// with open transactions only ever finish them.
+//
+// called with c.mu held. Releases it while it waits.
+func (c *Controller) drainTransactions(saveConnID int, timeout time.Duration) int {
+	if c.iterSessions == nil {
+		return 0
+	}
+	c.mu.Unlock()
+	defer c.mu.Lock()
+	deadline := time.Now().Add(timeout)
+	for {
+		open := 0
+		c.iterSessions(func(session sql.Session) (stop bool, err error) {
+			if int(session.ID()) != saveConnID && session.GetTransaction() != nil {
+				open++
+			}
+			return
+		})
+		if open == 0 || !time.Now().Before(deadline) {
+			return open
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 // Kills all running queries in the managed GMS engine.
 // called with c.mu held
 func (c *Controller) killRunningQueries(saveConnID int) {
@@ -874,39 +1091,33 @@ func (c *Controller) waitForHooksToReplicate(timeout time.Duration) ([]bool, err
 // for outbound https connections on the URLs that the GRPC services return.
 func (c *Controller) outboundTlsConfig() (*tls.Config, error) {
 	tlsCA := c.cfg.RemotesAPIConfig().TLSCA()
+	clientCert, clientKey := c.cfg.RemotesAPIConfig().TLSClientCert(), c.cfg.RemotesAPIConfig().TLSClientKey()
+	var clientCerts *certReloader
+	if clientCert != "" || clientKey != "" {
+		var err error
+		clientCerts, err = newCertReloader(clientCert, clientKey)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if tlsCA == "" {
-		return nil, nil
+		if clientCerts == nil {
+			return nil, nil
+		}
+		// default verification, presenting our client certificate
+		return &tls.Config{
+			GetClientCertificate: clientCerts.GetClientCertificate,
+			NextProtos:           []string{"h2"},
+		}, nil
 	}
 	urlmatches := c.cfg.RemotesAPIConfig().ServerNameURLMatches()
 	dnsmatches := c.cfg.RemotesAPIConfig().ServerNameDNSMatches()
-	pem, err := os.ReadFile(tlsCA)
+	roots, err := newCAReloader(tlsCA)
 	if err != nil {
 		return nil, err
 	}
-	roots := x509.NewCertPool()
-	if ok := roots.AppendCertsFromPEM(pem); !ok {
-		return nil, errors.New("error loading ca roots from " + tlsCA)
-	}
 	verifyFunc := func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
-		certs := make([]*x509.Certificate, len(rawCerts))
-		var err error
-		for i, asn1Data := range rawCerts {
-			certs[i], err = x509.ParseCertificate(asn1Data)
-			if err != nil {
-				return err
-			}
-		}
-		keyUsages := []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
-		opts := x509.VerifyOptions{
-			Roots:         roots,
-			CurrentTime:   time.Now(),
-			Intermediates: x509.NewCertPool(),
-			KeyUsages:     keyUsages,
-		}
-		for _, cert := range certs[1:] {
-			opts.Intermediates.AddCert(cert)
-		}
-		_, err = certs[0].Verify(opts)
+		certs, err := roots.verifyChain(rawCerts, x509.ExtKeyUsageServerAuth)
 		if err != nil {
 			return err
 		}
@@ -946,7 +1157,7 @@ func (c *Controller) outboundTlsConfig() (*tls.Config, error) {
 		}
 		return nil
 	}
-	return &tls.Config{
+	ret := &tls.Config{
 		// We have to InsecureSkipVerify because ServerName is always
 		// set by the grpc dial provider and golang tls.Config does not
 		// have good support for performing certificate validation
@@ -956,7 +1167,11 @@ func (c *Controller) outboundTlsConfig() (*tls.Config, error) {
 		VerifyPeerCertificate: verifyFunc,
 
 		NextProtos: []string{"h2"},
-	}, nil
+	}
+	if clientCerts != nil {
+		ret.GetClientCertificate = clientCerts.GetClientCertificate
+	}
+	return ret, nil
 }
 
 func (c *Controller) standbyRemotesJWKS() *jwtauth.MultiJWKS {
@@ -0,0 +1,170 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTransaction struct{}
+
+func (testTransaction) String() string {
+	return "test transaction"
+}
+
+func (testTransaction) IsReadOnly() bool {
+	return false
+}
+
+// newTestController returns a Controller managing |sessions|, with a standby commithook for a single database, which
+// records the connections it kills in |killed|.
+func newTestController(sessions []*sql.BaseSession, killed *[]uint32) *Controller {
+	c := &Controller{
+		lgr:              logrus.New(),
+		role:             RolePrimary,
+		mysqlDbPersister: &replicatingMySQLDbPersister{},
+		bcReplication:    &branchControlReplication{},
+		commithooks: []*commithook{
+			{dbname: "mydb", remotename: "standby", remoteurl: "http://localhost:50051/mydb", role: RoleStandby},
+		},
+	}
+	c.ManageQueryConnections(func(f func(sql.Session) (bool, error)) error {
+		for _, session := range sessions {
+			if stop, err := f(session); stop || err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func(uint32) {}, func(id uint32) error {
+		*killed = append(*killed, id)
+		return nil
+	})
+	return c
+}
+
+func newTestSessions(n int) []*sql.BaseSession {
+	sessions := make([]*sql.BaseSession, n)
+	for i := range sessions {
+		sessions[i] = sql.NewBaseSessionWithClientServer("localhost", sql.Client{}, uint32(i+1))
+		sessions[i].SetTransaction(testTransaction{})
+	}
+	return sessions
+}
+
+func TestDrainTransactions(t *testing.T) {
+	t.Run("NoSessions", func(t *testing.T) {
+		c := &Controller{lgr: logrus.New()}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		assert.Equal(t, 0, c.drainTransactions(-1, time.Second))
+	})
+	t.Run("TransactionsFinish", func(t *testing.T) {
+		var killed []uint32
+		sessions := newTestSessions(2)
+		c := newTestController(sessions, &killed)
+		go func() {
+			for _, session := range sessions {
+				time.Sleep(20 * time.Millisecond)
+				session.SetTransaction(nil)
+			}
+		}()
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		start := time.Now()
+		assert.Equal(t, 0, c.drainTransactions(-1, 10*time.Second))
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+	t.Run("SavedConnectionIsExempt", func(t *testing.T) {
+		var killed []uint32
+		c := newTestController(newTestSessions(1), &killed)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		assert.Equal(t, 0, c.drainTransactions(1, 10*time.Second))
+	})
+	t.Run("Timeout", func(t *testing.T) {
+		var killed []uint32
+		sessions := newTestSessions(3)
+		sessions[1].SetTransaction(nil)
+		c := newTestController(sessions, &killed)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		start := time.Now()
+		assert.Equal(t, 1, c.drainTransactions(1, 50*time.Millisecond))
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+}
+
+func TestGracefulTransitionToStandby(t *testing.T) {
+	// transition runs a graceful transition to standby in the background, saving the first session's connection,
+	// and waits for it to report that it's draining transactions.
+	transition := func(t *testing.T, c *Controller) <-chan []graceTransitionResult {
+		done := make(chan []graceTransitionResult)
+		go func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			res, err := c.gracefulTransitionToStandby(1, 0)
+			assert.NoError(t, err)
+			done <- res
+		}()
+		require.Eventually(t, func() bool {
+			status := c.GetClusterStatus()
+			return len(status) == 1 && status[0].TransitionState != nil && *status[0].TransitionState == transitionPhaseDraining
+		}, 5*time.Second, 10*time.Millisecond)
+		return done
+	}
+
+	t.Run("TransactionsFinish", func(t *testing.T) {
+		var killed []uint32
+		sessions := newTestSessions(3)
+		c := newTestController(sessions, &killed)
+		done := transition(t, c)
+		sessions[1].SetTransaction(nil)
+		sessions[2].SetTransaction(nil)
+		res := <-done
+		require.Len(t, res, 1)
+		assert.Equal(t, graceTransitionResult{
+			caughtUp:  true,
+			database:  "mydb",
+			remote:    "standby",
+			remoteUrl: "http://localhost:50051/mydb",
+		}, res[0])
+		assert.Equal(t, []uint32{2, 3}, killed)
+		assert.Nil(t, c.GetClusterStatus()[0].TransitionState)
+	})
+	t.Run("OpenTransactionsAreAborted", func(t *testing.T) {
+		var killed []uint32
+		sessions := newTestSessions(3)
+		c := newTestController(sessions, &killed)
+		done := transition(t, c)
+		sessions[1].SetTransaction(nil)
+		var res []graceTransitionResult
+		select {
+		case res = <-done:
+		case <-time.After(drainTransactionsTimeout + waitForHooksToReplicateTimeout):
+			require.FailNow(t, "graceful transition to standby did not finish")
+		}
+		require.Len(t, res, 1)
+		assert.Equal(t, 1, res[0].abortedTransactions)
+		assert.True(t, res[0].caughtUp)
+		assert.Equal(t, []uint32{2, 3}, killed)
+		assert.Nil(t, c.GetClusterStatus()[0].TransitionState)
+	})
+}
@@ -0,0 +1,249 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// clusterElectionHeader is sent with the requests of a server standing for election, with its server id as the
+// value. Standbys only accept one candidate for each epoch, and refuse every candidate while they're hearing from a
+// primary.
+const clusterElectionHeader = "x-dolt-cluster-election"
+
+// DefaultFailoverLeaseDuration is the lease duration when automatic failover is enabled without one.
+const DefaultFailoverLeaseDuration = 10 * time.Second
+
+// MinFailoverLeaseDuration is the shortest lease duration. Primaries heartbeat to their standbys every second by
+// default, so a lease has to span a few heartbeats.
+const MinFailoverLeaseDuration = 3 * time.Second
+
+// elector implements automatic failover. Elections are lease-based:
+//
+//   - A primary holds its lease for |lease| after it last heard from a majority of the servers in the cluster,
+//     counting itself. A primary that doesn't renew its lease transitions to standby, and stops accepting writes.
+//   - A standby that hasn't heard from a primary for twice the lease duration, plus its priority times the lease
+//     duration, stands for election. It becomes primary at the next epoch, but stays read only as a candidate until
+//     it has replicated to a majority of the cluster. A candidate that doesn't within a lease duration transitions
+//     back to standby.
+//   - A standby accepts a candidate by accepting its replication requests. It refuses candidates while it's hearing
+//     from a primary, and accepts only one candidate in each epoch. A primary refuses candidates while it holds its
+//     lease.
+//
+// Once a standby has accepted a primary at some epoch, it refuses replication from primaries at earlier epochs, so a
+// primary that was failed over from can't replicate to it when it comes back. Observers accept candidates and follow
+// primaries as standbys do, but never stand for election themselves.
+type elector struct {
+	lease    time.Duration
+	priority int
+	// id identifies this server to the standbys it asks to accept it as a candidate.
+	id string
+
+	mu sync.Mutex
+	// roleSince is when this server last changed roles or epochs.
+	roleSince time.Time
+	// candidate is true while this server is a primary that hasn't yet been accepted by a majority of the cluster.
+	candidate bool
+	// leaseRenewed is the last time this server, as primary, heard from a majority of the cluster.
+	leaseRenewed time.Time
+	// lastPrimaryContact is the last time this server, as a standby, heard from a primary at its epoch or later.
+	lastPrimaryContact time.Time
+	// votes are the ids of the candidates this server accepted, by epoch.
+	votes map[int]string
+	// jitter is added to the election timeout, so that standbys with the same priority don't stand for election at
+	// the same time.
+	jitter time.Duration
+}
+
+func newElector(cfg FailoverConfig) *elector {
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	lease := cfg.LeaseDuration()
+	if lease <= 0 {
+		lease = DefaultFailoverLeaseDuration
+	}
+	e := &elector{
+		lease:     lease,
+		priority:  cfg.Priority(),
+		id:        hex.EncodeToString(id[:]),
+		roleSince: time.Now(),
+		votes:     make(map[int]string),
+	}
+	e.resetJitter()
+	return e
+}
+
+// called with e.mu held
+func (e *elector) resetJitter() {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(e.lease/2)))
+	if err == nil {
+		e.jitter = time.Duration(n.Int64())
+	}
+}
+
+// roleChanged records that this server became |role|, as a |candidate| if it's standing for election.
+func (e *elector) roleChanged(role Role, candidate bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.roleSince = time.Now()
+	e.candidate = role == RolePrimary && candidate
+	e.leaseRenewed = time.Time{}
+	e.resetJitter()
+}
+
+// primaryContacted records that this server, as a standby, heard from a primary at its epoch or later.
+func (e *elector) primaryContacted() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastPrimaryContact = time.Now()
+}
+
+// refusesCandidate returns true if this server, which is |role| at |epoch|, refuses the candidate |id| standing for
+// election at |candidateEpoch|. If it doesn't, a standby records its vote for the candidate.
+func (e *elector) refusesCandidate(role Role, epoch int, id string, candidateEpoch int) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := time.Now()
+	switch role {
+	case RolePrimary:
+		if e.candidate {
+			// a competing candidate at a later epoch wins
+			return candidateEpoch <= epoch
+		}
+		return now.Sub(e.leaseRenewed) < e.lease
+	case RoleStandby, RoleObserver:
+		if now.Sub(e.lastPrimaryContact) < e.lease {
+			return true
+		}
+		if voted, ok := e.votes[candidateEpoch]; ok && voted != id {
+			return true
+		}
+		e.votes[candidateEpoch] = id
+		return false
+	default:
+		return true
+	}
+}
+
+// leaseAction is what a server does when it checks its lease.
+type leaseAction int
+
+const (
+	leaseActionNone leaseAction = iota
+	// the primary's lease expired, or a candidate wasn't accepted in time, and it transitions to standby
+	leaseActionStepDown
+	// the candidate was accepted by a majority, and starts accepting writes
+	leaseActionConfirm
+	// the standby stands for election
+	leaseActionStandForElection
+)
+
+// checkLease returns what this server, which is |role|, should do now that it has heard from |contacted| of the
+// |peers| other servers in the cluster within the last lease duration.
+func (e *elector) checkLease(role Role, contacted, peers int) leaseAction {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := time.Now()
+	majority := (peers+1)/2 + 1
+	switch role {
+	case RolePrimary:
+		if contacted+1 >= majority {
+			e.leaseRenewed = now
+			if e.candidate {
+				e.candidate = false
+				return leaseActionConfirm
+			}
+			return leaseActionNone
+		}
+		// a new primary has a lease duration to reach the cluster
+		if now.Sub(e.roleSince) >= e.lease && now.Sub(e.leaseRenewed) >= e.lease {
+			return leaseActionStepDown
+		}
+	case RoleStandby:
+		lastContact := e.lastPrimaryContact
+		if e.roleSince.After(lastContact) {
+			lastContact = e.roleSince
+		}
+		timeout := 2*e.lease + time.Duration(e.priority)*e.lease + e.jitter
+		if now.Sub(lastContact) >= timeout {
+			return leaseActionStandForElection
+		}
+	}
+	return leaseActionNone
+}
+
+// runElections checks this server's lease until the controller is stopped, acting on it as automatic failover
+// requires.
+func (c *Controller) runElections() {
+	peers := len(c.cfg.StandbyRemotes())
+	if peers < 2 {
+		c.lgr.Warnf("cluster/controller: automatic failover requires at least three servers in the cluster, but there are %d. it is disabled.", peers+1)
+		return
+	}
+	ticker := time.NewTicker(c.elector.lease / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stopElections:
+			return
+		case <-ticker.C:
+			c.checkLease(peers)
+		}
+	}
+}
+
+// checkLease renews or gives up this server's lease as primary, confirms it as primary once a majority of the
+// cluster accepts it as a candidate, and stands for election when it hasn't heard from a primary in too long.
+func (c *Controller) checkLease(peers int) {
+	role, epoch := c.roleAndEpoch()
+	since := time.Now().Add(-c.elector.lease)
+	switch c.elector.checkLease(role, c.contactedPeers(since), peers) {
+	case leaseActionStepDown:
+		c.lgr.Warnf("cluster/controller: primary at epoch %d could not reach a majority of the cluster within its lease. transitioning to standby.", epoch)
+		c.setRoleAndEpoch(string(RoleStandby), epoch, roleTransitionOptions{})
+	case leaseActionConfirm:
+		c.lgr.Infof("cluster/controller: a majority of the cluster accepted this server as primary at epoch %d. accepting writes.", epoch)
+		c.mu.Lock()
+		if c.role == RolePrimary && c.epoch == epoch {
+			c.cinterceptor.setElectionID("")
+			c.setProviderIsStandby(false)
+		}
+		c.mu.Unlock()
+	case leaseActionStandForElection:
+		c.lgr.Infof("cluster/controller: standby at epoch %d has not heard from a primary. standing for election at epoch %d.", epoch, epoch+1)
+		if _, err := c.setRoleAndEpoch(string(RolePrimary), epoch+1, roleTransitionOptions{candidate: true}); err != nil {
+			c.lgr.Warnf("cluster/controller: could not stand for election: %v", err)
+		}
+	}
+}
+
+// contactedPeers returns the number of standbys this server replicated to, or heartbeated to, after |t|.
+func (c *Controller) contactedPeers(t time.Time) int {
+	c.mu.Lock()
+	commithooks := make([]*commithook, len(c.commithooks))
+	copy(commithooks, c.commithooks)
+	c.mu.Unlock()
+	contacted := make(map[string]struct{})
+	for _, h := range commithooks {
+		if _, ok := contacted[h.remotename]; !ok && h.contactedSince(t) {
+			contacted[h.remotename] = struct{}{}
+		}
+	}
+	return len(contacted)
+}
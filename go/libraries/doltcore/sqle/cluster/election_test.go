@@ -0,0 +1,123 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testFailoverConfig struct {
+	lease    time.Duration
+	priority int
+}
+
+func (c testFailoverConfig) LeaseDuration() time.Duration { return c.lease }
+func (c testFailoverConfig) Priority() int                { return c.priority }
+
+func TestElectorCheckLease(t *testing.T) {
+	const lease = 4 * time.Second
+
+	t.Run("PrimaryRenewsItsLeaseWithAMajority", func(t *testing.T) {
+		e := newElector(testFailoverConfig{lease: lease})
+		e.roleSince = time.Now().Add(-2 * lease)
+		// two of four peers, and the primary itself, are a majority of five
+		assert.Equal(t, leaseActionNone, e.checkLease(RolePrimary, 2, 4))
+		assert.False(t, e.leaseRenewed.IsZero())
+	})
+
+	t.Run("PrimaryStepsDownWithoutAMajority", func(t *testing.T) {
+		e := newElector(testFailoverConfig{lease: lease})
+		// a new primary has a lease duration to reach the cluster
+		assert.Equal(t, leaseActionNone, e.checkLease(RolePrimary, 0, 2))
+		e.roleSince = time.Now().Add(-2 * lease)
+		e.leaseRenewed = time.Now().Add(-lease / 2)
+		assert.Equal(t, leaseActionNone, e.checkLease(RolePrimary, 0, 2))
+		e.leaseRenewed = time.Now().Add(-2 * lease)
+		assert.Equal(t, leaseActionStepDown, e.checkLease(RolePrimary, 0, 2))
+	})
+
+	t.Run("CandidateIsConfirmedByAMajority", func(t *testing.T) {
+		e := newElector(testFailoverConfig{lease: lease})
+		e.roleChanged(RolePrimary, true)
+		assert.Equal(t, leaseActionNone, e.checkLease(RolePrimary, 0, 2))
+		assert.Equal(t, leaseActionConfirm, e.checkLease(RolePrimary, 1, 2))
+		assert.False(t, e.candidate)
+		assert.Equal(t, leaseActionNone, e.checkLease(RolePrimary, 1, 2))
+	})
+
+	t.Run("StandbyStandsForElection", func(t *testing.T) {
+		e := newElector(testFailoverConfig{lease: lease, priority: 1})
+		e.jitter = 0
+		e.roleSince = time.Now().Add(-10 * lease)
+		e.lastPrimaryContact = time.Now().Add(-2 * lease)
+		// a standby with priority 1 waits three lease durations
+		assert.Equal(t, leaseActionNone, e.checkLease(RoleStandby, 0, 2))
+		e.lastPrimaryContact = time.Now().Add(-3 * lease)
+		assert.Equal(t, leaseActionStandForElection, e.checkLease(RoleStandby, 0, 2))
+	})
+
+	t.Run("ObserverNeverStandsForElection", func(t *testing.T) {
+		e := newElector(testFailoverConfig{lease: lease})
+		e.roleSince = time.Now().Add(-10 * lease)
+		assert.Equal(t, leaseActionNone, e.checkLease(RoleObserver, 0, 2))
+	})
+}
+
+func TestElectorRefusesCandidate(t *testing.T) {
+	const lease = 4 * time.Second
+
+	t.Run("StandbyHearingFromAPrimary", func(t *testing.T) {
+		e := newElector(testFailoverConfig{lease: lease})
+		e.primaryContacted()
+		assert.True(t, e.refusesCandidate(RoleStandby, 1, "a", 2))
+		e.lastPrimaryContact = time.Now().Add(-2 * lease)
+		assert.False(t, e.refusesCandidate(RoleStandby, 1, "a", 2))
+	})
+
+	t.Run("StandbyVotesOncePerEpoch", func(t *testing.T) {
+		e := newElector(testFailoverConfig{lease: lease})
+		assert.False(t, e.refusesCandidate(RoleStandby, 1, "a", 2))
+		assert.False(t, e.refusesCandidate(RoleStandby, 2, "a", 2))
+		assert.True(t, e.refusesCandidate(RoleStandby, 2, "b", 2))
+		assert.False(t, e.refusesCandidate(RoleStandby, 2, "b", 3))
+	})
+
+	t.Run("ObserverVotesLikeAStandby", func(t *testing.T) {
+		e := newElector(testFailoverConfig{lease: lease})
+		e.primaryContacted()
+		assert.True(t, e.refusesCandidate(RoleObserver, 1, "a", 2))
+		e.lastPrimaryContact = time.Now().Add(-2 * lease)
+		assert.False(t, e.refusesCandidate(RoleObserver, 1, "a", 2))
+		assert.True(t, e.refusesCandidate(RoleObserver, 2, "b", 2))
+	})
+
+	t.Run("PrimaryHoldingItsLease", func(t *testing.T) {
+		e := newElector(testFailoverConfig{lease: lease})
+		e.leaseRenewed = time.Now()
+		assert.True(t, e.refusesCandidate(RolePrimary, 1, "a", 2))
+		e.leaseRenewed = time.Now().Add(-2 * lease)
+		assert.False(t, e.refusesCandidate(RolePrimary, 1, "a", 2))
+	})
+
+	t.Run("CompetingCandidates", func(t *testing.T) {
+		e := newElector(testFailoverConfig{lease: lease})
+		e.roleChanged(RolePrimary, true)
+		assert.True(t, e.refusesCandidate(RolePrimary, 2, "a", 2))
+		assert.False(t, e.refusesCandidate(RolePrimary, 2, "a", 3))
+	})
+}
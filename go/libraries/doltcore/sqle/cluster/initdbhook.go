@@ -27,17 +27,13 @@ import (
 	"github.com/dolthub/dolt/go/store/types"
 )
 
-func NewInitDatabaseHook(controller *Controller, bt *sql.BackgroundThreads, orig sqle.InitDatabaseHook) sqle.InitDatabaseHook {
+// NewInitDatabaseHook returns a sqle.InitDatabaseHook that replicates newly created databases to |controller|'s
+// standby remotes, or nil if there's no |controller|.
+func NewInitDatabaseHook(controller *Controller, bt *sql.BackgroundThreads) sqle.InitDatabaseHook {
 	if controller == nil {
-		return orig
+		return nil
 	}
-	return func(ctx *sql.Context, pro sqle.DoltDatabaseProvider, name string, denv *env.DoltEnv) error {
-		var err error
-		err = orig(ctx, pro, name, denv)
-		if err != nil {
-			return err
-		}
-
+	return func(ctx *sql.Context, pro *sqle.DoltDatabaseProvider, name string, denv *env.DoltEnv) error {
 		dialprovider := controller.gRPCDialProvider(denv)
 		var remoteDBs []func(context.Context) (*doltdb.DoltDB, error)
 		var remoteUrls []string
@@ -60,12 +56,16 @@ func NewInitDatabaseHook(controller *Controller, bt *sql.BackgroundThreads, orig
 		}
 
 		role, _ := controller.roleAndEpoch()
+		quorum := controller.ackQuorum()
 		for i, r := range controller.cfg.StandbyRemotes() {
 			ttfdir, err := denv.TempTableFilesDir()
 			if err != nil {
 				return err
 			}
-			commitHook := newCommitHook(controller.lgr, r.Name(), remoteUrls[i], name, role, remoteDBs[i], denv.DoltDB, ttfdir)
+			commitHook := newCommitHook(controller.lgr, r.Name(), remoteUrls[i], name, role, controller.cfg.ReplicationBackoffConfig(), controller.cfg.ReplicationHeartbeatConfig(), controller.throttle, controller.breaker, controller.events, remoteDBs[i], denv.DoltDB, ttfdir)
+			if quorum != nil {
+				quorum.add(commitHook)
+			}
 			denv.DoltDB.PrependCommitHook(ctx, commitHook)
 			controller.registerCommitHook(commitHook)
 			if err := commitHook.Run(bt); err != nil {
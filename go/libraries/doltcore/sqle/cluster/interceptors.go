@@ -16,6 +16,7 @@ package cluster
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"strings"
 	"sync"
@@ -77,6 +78,11 @@ type clientinterceptor struct {
 	epoch      int
 	mu         sync.Mutex
 	roleSetter func(role string, epoch int)
+
+	// failover is true when automatic failover is enabled, in which case a primary transitions to standby when a
+	// standby it replicates to is at a later epoch. |electionID| is this server's id while it's standing for election.
+	failover   bool
+	electionID string
 }
 
 func (ci *clientinterceptor) setRole(role Role, epoch int) {
@@ -92,17 +98,37 @@ func (ci *clientinterceptor) getRole() (Role, int) {
 	return ci.role, ci.epoch
 }
 
+// setElectionID sets the id sent with every request while this server stands for election, or stops sending it if
+// |id| is empty.
+func (ci *clientinterceptor) setElectionID(id string) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+	ci.electionID = id
+}
+
+// outgoingContext adds this server's role and epoch, and its election id if it's standing for election, to |ctx|.
+func (ci *clientinterceptor) outgoingContext(ctx context.Context, role Role, epoch int) context.Context {
+	ci.mu.Lock()
+	electionID := ci.electionID
+	ci.mu.Unlock()
+	ctx = metadata.AppendToOutgoingContext(ctx, clusterRoleHeader, string(role), clusterRoleEpochHeader, strconv.Itoa(epoch))
+	if electionID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, clusterElectionHeader, electionID)
+	}
+	return ctx
+}
+
 func (ci *clientinterceptor) Stream() grpc.StreamClientInterceptor {
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
 		role, epoch := ci.getRole()
 		ci.lgr.Tracef("cluster: clientinterceptor: processing request to %s, role %s", method, string(role))
-		if role == RoleStandby {
+		if role == RoleStandby || role == RoleObserver {
 			return nil, status.Error(codes.FailedPrecondition, "cluster: clientinterceptor: this server is a standby and is not currently replicating to its standby")
 		}
 		if role == RoleDetectedBrokenConfig {
 			return nil, status.Error(codes.FailedPrecondition, "cluster: clientinterceptor: this server is in detected_broken_config and is not currently replicating to its standby")
 		}
-		ctx = metadata.AppendToOutgoingContext(ctx, clusterRoleHeader, string(role), clusterRoleEpochHeader, strconv.Itoa(epoch))
+		ctx = ci.outgoingContext(ctx, role, epoch)
 		var header metadata.MD
 		stream, err := streamer(ctx, desc, cc, method, append(opts, grpc.Header(&header))...)
 		ci.handleResponseHeaders(header, err)
@@ -114,13 +140,13 @@ func (ci *clientinterceptor) Unary() grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 		role, epoch := ci.getRole()
 		ci.lgr.Tracef("cluster: clientinterceptor: processing request to %s, role %s", method, string(role))
-		if role == RoleStandby {
+		if role == RoleStandby || role == RoleObserver {
 			return status.Error(codes.FailedPrecondition, "cluster: clientinterceptor: this server is a standby and is not currently replicating to its standby")
 		}
 		if role == RoleDetectedBrokenConfig {
 			return status.Error(codes.FailedPrecondition, "cluster: clientinterceptor: this server is in detected_broken_config and is not currently replicating to its standby")
 		}
-		ctx = metadata.AppendToOutgoingContext(ctx, clusterRoleHeader, string(role), clusterRoleEpochHeader, strconv.Itoa(epoch))
+		ctx = ci.outgoingContext(ctx, role, epoch)
 		var header metadata.MD
 		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Header(&header))...)
 		ci.handleResponseHeaders(header, err)
@@ -152,6 +178,10 @@ func (ci *clientinterceptor) handleResponseHeaders(header metadata.MD, err error
 			} else if respRole == string(RoleDetectedBrokenConfig) && respEpoch >= epoch {
 				ci.lgr.Errorf("cluster: clientinterceptor: this server learned from its standby that the standby is in detected_broken_config at the same or higher epoch. force transitioning to detected_broken_config.")
 				ci.roleSetter(string(RoleDetectedBrokenConfig), respEpoch)
+			} else if (respRole == string(RoleStandby) || respRole == string(RoleObserver)) && respEpoch > epoch && ci.failover {
+				// Another server was elected primary at a later epoch while we couldn't reach the cluster.
+				ci.lgr.Warnf("cluster: clientinterceptor: this server is primary at epoch %d. a server it attempted to replicate to is standby at epoch %d. force transitioning to standby.", epoch, respEpoch)
+				ci.roleSetter(string(RoleStandby), respEpoch)
 			}
 		} else {
 			ci.lgr.Errorf("cluster: clientinterceptor: failed to parse epoch in response header; something is wrong: %v", err)
@@ -200,13 +230,22 @@ type serverinterceptor struct {
 
 	keyProvider jwtauth.KeyProvider
 	jwtExpected jwt.Expected
+
+	// elector is set when automatic failover is enabled.
+	elector *elector
 }
 
 func (si *serverinterceptor) Stream() grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		fromStandby := false
+		fromStandby, fromPrimary := false, false
+		var failoverErr error
 		if md, ok := metadata.FromIncomingContext(ss.Context()); ok {
-			fromStandby = si.handleRequestHeaders(md)
+			fromPrimary, failoverErr = si.handleFailoverHeaders(md)
+			if failoverErr == nil {
+				fromStandby = si.handleRequestHeaders(md)
+			} else {
+				fromStandby = true
+			}
 		}
 		if fromStandby {
 			if err := si.authenticate(ss.Context()); err != nil {
@@ -217,6 +256,12 @@ func (si *serverinterceptor) Stream() grpc.StreamServerInterceptor {
 			if err := grpc.SetHeader(ss.Context(), metadata.Pairs(clusterRoleHeader, string(role), clusterRoleEpochHeader, strconv.Itoa(epoch))); err != nil {
 				return err
 			}
+			if failoverErr != nil {
+				return failoverErr
+			}
+			if fromPrimary && (role == RoleStandby || role == RoleObserver) {
+				si.elector.primaryContacted()
+			}
 			if role == RolePrimary {
 				// As a primary, we do not accept replication requests.
 				return status.Error(codes.FailedPrecondition, "this server is a primary and is not currently accepting replication")
@@ -236,9 +281,15 @@ func (si *serverinterceptor) Stream() grpc.StreamServerInterceptor {
 
 func (si *serverinterceptor) Unary() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		fromStandby := false
+		fromStandby, fromPrimary := false, false
+		var failoverErr error
 		if md, ok := metadata.FromIncomingContext(ctx); ok {
-			fromStandby = si.handleRequestHeaders(md)
+			fromPrimary, failoverErr = si.handleFailoverHeaders(md)
+			if failoverErr == nil {
+				fromStandby = si.handleRequestHeaders(md)
+			} else {
+				fromStandby = true
+			}
 		}
 		if fromStandby {
 			if err := si.authenticate(ctx); err != nil {
@@ -249,6 +300,12 @@ func (si *serverinterceptor) Unary() grpc.UnaryServerInterceptor {
 			if err := grpc.SetHeader(ctx, metadata.Pairs(clusterRoleHeader, string(role), clusterRoleEpochHeader, strconv.Itoa(epoch))); err != nil {
 				return nil, err
 			}
+			if failoverErr != nil {
+				return nil, failoverErr
+			}
+			if fromPrimary && (role == RoleStandby || role == RoleObserver) {
+				si.elector.primaryContacted()
+			}
 			if role == RolePrimary {
 				// As a primary, we do not accept replication requests.
 				return nil, status.Error(codes.FailedPrecondition, "this server is a primary and is not currently accepting replication")
@@ -294,6 +351,40 @@ func (si *serverinterceptor) handleRequestHeaders(header metadata.MD) bool {
 	return false
 }
 
+// handleFailoverHeaders applies the rules of automatic failover to a request with |header|, if it's enabled. It
+// returns true if the request is from a primary at this server's epoch or later, and an error if the request is
+// refused: because it's from a primary at an earlier epoch, or from a candidate this server doesn't accept.
+func (si *serverinterceptor) handleFailoverHeaders(header metadata.MD) (bool, error) {
+	if si.elector == nil {
+		return false, nil
+	}
+	epochs := header.Get(clusterRoleEpochHeader)
+	roles := header.Get(clusterRoleHeader)
+	if len(epochs) == 0 || len(roles) == 0 || roles[0] != string(RolePrimary) {
+		return false, nil
+	}
+	reqepoch, err := strconv.Atoi(epochs[0])
+	if err != nil {
+		return false, nil
+	}
+	role, epoch := si.getRole()
+	if reqepoch < epoch {
+		return false, status.Error(codes.FailedPrecondition, fmt.Sprintf("this server is at epoch %d and does not accept replication from a primary at epoch %d", epoch, reqepoch))
+	}
+	if ids := header.Get(clusterElectionHeader); len(ids) > 0 {
+		if si.elector.refusesCandidate(role, epoch, ids[0], reqepoch) {
+			si.lgr.Infof("cluster: serverinterceptor: refusing a candidate for primary at epoch %d.", reqepoch)
+			return false, status.Error(codes.FailedPrecondition, fmt.Sprintf("this server does not accept the candidate for primary at epoch %d", reqepoch))
+		}
+	}
+	if reqepoch > epoch && (role == RoleStandby || role == RoleObserver) {
+		// A standby follows the primary to its epoch, so that it refuses replication from earlier primaries.
+		si.lgr.Infof("cluster: serverinterceptor: this server is %s at epoch %d. the server replicating to it is primary at epoch %d. transitioning to its epoch.", role, epoch, reqepoch)
+		si.roleSetter(string(role), reqepoch)
+	}
+	return true, nil
+}
+
 func (si *serverinterceptor) Options() []grpc.ServerOption {
 	return []grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(si.Unary()),
@@ -0,0 +1,375 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/clusterdb"
+)
+
+// readRouterDialTimeout is how long the read router waits to connect to a standby before it serves the connection
+// locally instead.
+const readRouterDialTimeout = 5 * time.Second
+
+// The parts of the MySQL protocol the read router follows to find where a routed connection's transactions begin.
+const (
+	mysqlClientCompress = 0x00000020
+	mysqlClientSSL      = 0x00000800
+	mysqlComQuit        = 0x01
+	mysqlComQuery       = 0x03
+	// mysqlErQueryInterrupted is the error returned for a command on a connection whose standby fell behind
+	mysqlErQueryInterrupted = 1317
+)
+
+// errStandbyLagging ends a routed connection whose standby fell behind the primary.
+var errStandbyLagging = errors.New("standby replication is lagging")
+
+// ReadRouter accepts SQL connections from clients that only read, and forwards each one to a standby whose
+// replication is caught up, so that a cluster's reads are spread over its standbys without clients choosing between
+// them. Connections are forwarded before the MySQL handshake, so clients authenticate with the standby, which has the
+// same users as the primary. When no standby is caught up, or this server isn't the primary, connections are
+// forwarded to this server's own SQL listener.
+//
+// A standby is checked again before each transaction of a connection routed to it. If it has fallen behind, the
+// transaction's first command fails and the connection is closed, so that the client reconnects and is routed again.
+// Connections are forwarded with a PROXY protocol header when the listener they're forwarded to expects one, so that
+// the server sees the client's address rather than the router's.
+type ReadRouter struct {
+	controller *Controller
+	listener   net.Listener
+	localAddr  string
+	// localProxyProtocol is true if the local SQL listener expects connections to begin with a PROXY protocol header
+	localProxyProtocol bool
+	lgr                *logrus.Entry
+
+	next  atomic.Uint64
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+// NewReadRouter returns a ReadRouter listening on the configured read routing address, which serves connections
+// locally by forwarding them to |localAddr|, with a PROXY protocol header if |localProxyProtocol| is true. It returns
+// nil if read routing isn't configured.
+func (c *Controller) NewReadRouter(localAddr string, localProxyProtocol bool) (*ReadRouter, error) {
+	if c == nil || c.cfg.ReadRoutingConfig() == nil {
+		return nil, nil
+	}
+	cfg := c.cfg.ReadRoutingConfig()
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", cfg.Address(), cfg.Port()))
+	if err != nil {
+		return nil, err
+	}
+	return &ReadRouter{
+		controller: c,
+		listener:   listener,
+		localAddr:  localAddr,
+		lgr:        c.lgr.WithField("component", "read_router"),
+		conns:      make(map[net.Conn]struct{}),
+
+		localProxyProtocol: localProxyProtocol,
+	}, nil
+}
+
+// Serve accepts connections until the router is closed.
+func (r *ReadRouter) Serve() {
+	for {
+		conn, err := r.listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		} else if err != nil {
+			r.lgr.Warnf("error accepting connection: %v", err)
+			continue
+		}
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.route(conn)
+		}()
+	}
+}
+
+// Close stops accepting connections, closes the connections being forwarded and waits for them to finish.
+func (r *ReadRouter) Close() error {
+	err := r.listener.Close()
+	r.mu.Lock()
+	for conn := range r.conns {
+		conn.Close()
+	}
+	r.conns = nil
+	r.mu.Unlock()
+	r.wg.Wait()
+	return err
+}
+
+func (r *ReadRouter) route(conn net.Conn) {
+	if !r.track(conn) {
+		return
+	}
+	defer r.untrack(conn)
+
+	var upstream net.Conn
+	standby, addr := r.nextStandby()
+	if addr != "" {
+		var err error
+		upstream, err = r.dial(conn, addr, r.controller.cfg.ReadRoutingConfig().ProxyProtocol())
+		if err != nil {
+			r.lgr.Warnf("error connecting to standby at %s, serving the connection locally: %v", addr, err)
+			standby = ""
+		}
+	}
+	if upstream == nil {
+		var err error
+		upstream, err = r.dial(conn, r.localAddr, r.localProxyProtocol)
+		if err != nil {
+			r.lgr.Warnf("error connecting to the local server at %s: %v", r.localAddr, err)
+			return
+		}
+	}
+	if !r.track(upstream) {
+		return
+	}
+	defer r.untrack(upstream)
+
+	done := make(chan struct{}, 2)
+	forward := func(dst, src net.Conn, pump func() error) {
+		pump()
+		// a closed side ends the connection both ways
+		dst.Close()
+		src.Close()
+		done <- struct{}{}
+	}
+	go forward(upstream, conn, func() error {
+		if standby == "" {
+			_, err := io.Copy(upstream, conn)
+			return err
+		}
+		return r.forwardCommands(upstream, conn, standby)
+	})
+	go forward(conn, upstream, func() error {
+		_, err := io.Copy(conn, upstream)
+		return err
+	})
+	<-done
+	<-done
+}
+
+// dial connects to the SQL listener at |addr| to forward |conn| to it, and writes a PROXY protocol header for |conn|
+// first if |proxyProtocol| is true.
+func (r *ReadRouter) dial(conn net.Conn, addr string, proxyProtocol bool) (net.Conn, error) {
+	upstream, err := net.DialTimeout("tcp", addr, readRouterDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if proxyProtocol {
+		if err = writeProxyHeader(upstream, conn.RemoteAddr(), conn.LocalAddr()); err != nil {
+			upstream.Close()
+			return nil, err
+		}
+	}
+	return upstream, nil
+}
+
+// writeProxyHeader writes a version 1 PROXY protocol header for a connection from |src| to |dst|.
+func writeProxyHeader(w io.Writer, src, dst net.Addr) error {
+	srcAddr, srcOk := src.(*net.TCPAddr)
+	dstAddr, dstOk := dst.(*net.TCPAddr)
+	if !srcOk || !dstOk {
+		_, err := io.WriteString(w, "PROXY UNKNOWN\r\n")
+		return err
+	}
+	proto, srcIP, dstIP := "TCP4", srcAddr.IP.String(), dstAddr.IP.String()
+	if srcAddr.IP.To4() == nil || dstAddr.IP.To4() == nil {
+		proto, srcIP, dstIP = "TCP6", ipv6String(srcAddr.IP), ipv6String(dstAddr.IP)
+	}
+	_, err := fmt.Fprintf(w, "PROXY %s %s %s %d %d\r\n", proto, srcIP, dstIP, srcAddr.Port, dstAddr.Port)
+	return err
+}
+
+// ipv6String formats |ip| as an IPv6 address, mapping IPv4 addresses into IPv6.
+func ipv6String(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return "::ffff:" + ip4.String()
+	}
+	return ip.String()
+}
+
+// forwardCommands forwards the MySQL protocol packets the client sends on |conn| to |upstream|, the standby named
+// |standby|, checking that the standby is still caught up before each transaction the client begins. If it isn't,
+// the command fails, and errStandbyLagging is returned to close the connection. The packets of connections that use
+// TLS or compression can't be read after the handshake, so they're only checked when they're routed.
+func (r *ReadRouter) forwardCommands(upstream, conn net.Conn, standby string) error {
+	br := bufio.NewReader(conn)
+	var tx routedTransaction
+	handshake := true
+	for {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(br, header); err != nil {
+			return err
+		}
+		payload := make([]byte, int(header[0])|int(header[1])<<8|int(header[2])<<16)
+		if _, err := io.ReadFull(br, payload); err != nil {
+			return err
+		}
+		// every command begins a new sequence of packets, which continue through the server's response
+		command := header[3] == 0 && len(payload) > 0
+
+		if handshake {
+			handshake = false
+			// the handshake response, or the SSL request sent in its place, begins with the client's capabilities
+			if len(payload) >= 4 && binary.LittleEndian.Uint32(payload)&(mysqlClientSSL|mysqlClientCompress) != 0 {
+				if _, err := upstream.Write(append(header, payload...)); err != nil {
+					return err
+				}
+				_, err := io.Copy(upstream, br)
+				return err
+			}
+		} else if command && payload[0] != mysqlComQuit && !tx.active() && !r.caughtUp(standby) {
+			r.lgr.Infof("standby %s fell behind, closing a connection routed to it", standby)
+			msg := fmt.Sprintf("standby %s fell behind the primary; reconnect to be routed again", standby)
+			writeErrPacket(conn, header[3]+1, mysqlErQueryInterrupted, "70100", msg)
+			return errStandbyLagging
+		}
+
+		if command && payload[0] == mysqlComQuery {
+			tx.query(string(payload[1:]))
+		}
+		if _, err := upstream.Write(append(header, payload...)); err != nil {
+			return err
+		}
+	}
+}
+
+// writeErrPacket writes a MySQL protocol ERR packet with the sequence number |seq|.
+func writeErrPacket(w io.Writer, seq byte, code uint16, state, msg string) error {
+	payload := []byte{0xff, byte(code), byte(code >> 8), '#'}
+	payload = append(payload, state...)
+	payload = append(payload, msg...)
+	packet := []byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), seq}
+	_, err := w.Write(append(packet, payload...))
+	return err
+}
+
+var setAutocommitRegex = regexp.MustCompile(`^set\s+(?:session\s+|@@session\.|@@)?autocommit\s*=\s*(\S+?)\s*;?$`)
+
+// routedTransaction follows whether a routed connection is in a transaction from the text of the queries it sends,
+// so that a transaction isn't failed after it has begun.
+type routedTransaction struct {
+	inTransaction bool
+	noAutocommit  bool
+}
+
+func (t *routedTransaction) query(q string) {
+	q = strings.ToLower(strings.TrimSpace(q))
+	switch {
+	case strings.HasPrefix(q, "begin"), strings.HasPrefix(q, "start transaction"):
+		t.inTransaction = true
+	case strings.HasPrefix(q, "commit"), strings.HasPrefix(q, "rollback") && !strings.HasPrefix(q, "rollback to"):
+		t.inTransaction = false
+	default:
+		if m := setAutocommitRegex.FindStringSubmatch(q); m != nil {
+			v := strings.Trim(m[1], "'\"")
+			t.noAutocommit = v == "0" || v == "off" || v == "false"
+			if !t.noAutocommit {
+				// enabling autocommit commits the open transaction
+				t.inTransaction = false
+			}
+		} else if t.noAutocommit {
+			// without autocommit, every statement begins a transaction if one isn't open
+			t.inTransaction = true
+		}
+	}
+}
+
+func (t *routedTransaction) active() bool {
+	return t.inTransaction
+}
+
+// nextStandby returns the name and SQL address of the next caught-up standby to route a connection to, or "" if the
+// connection should be served locally.
+func (r *ReadRouter) nextStandby() (string, string) {
+	role, _ := r.controller.roleAndEpoch()
+	if role != RolePrimary {
+		return "", ""
+	}
+	caughtUp := caughtUpStandbys(r.controller.GetClusterStatus(), r.controller.cfg.ReadRoutingConfig().MaxLag())
+
+	var routable []StandbyRemoteConfig
+	for _, remote := range r.controller.cfg.StandbyRemotes() {
+		if caughtUp[remote.Name()] && remote.SQLAddress() != "" {
+			routable = append(routable, remote)
+		}
+	}
+	if len(routable) == 0 {
+		return "", ""
+	}
+	remote := routable[r.next.Add(1)%uint64(len(routable))]
+	return remote.Name(), remote.SQLAddress()
+}
+
+// caughtUp returns whether the standby named is still caught up, and this server is still the primary.
+func (r *ReadRouter) caughtUp(standby string) bool {
+	role, _ := r.controller.roleAndEpoch()
+	if role != RolePrimary {
+		return false
+	}
+	return caughtUpStandbys(r.controller.GetClusterStatus(), r.controller.cfg.ReadRoutingConfig().MaxLag())[standby]
+}
+
+// caughtUpStandbys returns the names of the standby remotes that every database in |statuses| is replicated to
+// within |maxLag|.
+func caughtUpStandbys(statuses []clusterdb.ReplicaStatus, maxLag time.Duration) map[string]bool {
+	caughtUp := make(map[string]bool)
+	for _, status := range statuses {
+		ok := status.ReplicationLag != nil && *status.ReplicationLag <= maxLag && status.CurrentError == nil
+		if prev, seen := caughtUp[status.Remote]; seen {
+			ok = ok && prev
+		}
+		caughtUp[status.Remote] = ok
+	}
+	return caughtUp
+}
+
+// track records |conn| to be closed when the router closes, or closes it if the router is already closed.
+func (r *ReadRouter) track(conn net.Conn) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conns == nil {
+		conn.Close()
+		return false
+	}
+	r.conns[conn] = struct{}{}
+	return true
+}
+
+func (r *ReadRouter) untrack(conn net.Conn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	conn.Close()
+	delete(r.conns, conn)
+}
@@ -0,0 +1,267 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+type testStandbyRemote struct {
+	name, sqlAddr string
+}
+
+func (r testStandbyRemote) Name() string              { return r.name }
+func (r testStandbyRemote) RemoteURLTemplate() string { return "" }
+func (r testStandbyRemote) SQLAddress() string        { return r.sqlAddr }
+
+type testReadRoutingConfig struct {
+	proxyProtocol bool
+}
+
+func (testReadRoutingConfig) Address() string       { return "127.0.0.1" }
+func (testReadRoutingConfig) Port() int             { return 0 }
+func (testReadRoutingConfig) MaxLag() time.Duration { return time.Second }
+func (c testReadRoutingConfig) ProxyProtocol() bool { return c.proxyProtocol }
+
+type testRoutingClusterConfig struct {
+	remotes       []StandbyRemoteConfig
+	proxyProtocol bool
+}
+
+func (c testRoutingClusterConfig) StandbyRemotes() []StandbyRemoteConfig { return c.remotes }
+func (c testRoutingClusterConfig) BootstrapRole() string                 { return string(RolePrimary) }
+func (c testRoutingClusterConfig) BootstrapEpoch() int                   { return 0 }
+func (c testRoutingClusterConfig) RemotesAPIConfig() RemotesAPIConfig    { return nil }
+func (c testRoutingClusterConfig) ReadRoutingConfig() ReadRoutingConfig {
+	return testReadRoutingConfig{proxyProtocol: c.proxyProtocol}
+}
+func (c testRoutingClusterConfig) ReplicationBackoffConfig() BackoffConfig               { return nil }
+func (c testRoutingClusterConfig) ReplicationThrottleConfig() ThrottleConfig             { return nil }
+func (c testRoutingClusterConfig) ReplicationHeartbeatConfig() HeartbeatConfig           { return nil }
+func (c testRoutingClusterConfig) ReplicationCircuitBreakerConfig() CircuitBreakerConfig { return nil }
+func (c testRoutingClusterConfig) AutomaticFailoverConfig() FailoverConfig               { return nil }
+func (c testRoutingClusterConfig) RequiredAcks() int                                     { return 0 }
+func (c testRoutingClusterConfig) StandbyBootstrapURLTemplate() string                   { return "" }
+
+// nameServer accepts connections on a local port and writes |name| to each one.
+func nameServer(t *testing.T, name string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte(name))
+			conn.Close()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestReadRouter(t *testing.T) {
+	standbyAddr := nameServer(t, "standby")
+	localAddr := nameServer(t, "local")
+
+	head := hash.Of([]byte("head"))
+	hook := &commithook{remotename: "standby", dbname: "db", role: RolePrimary, nextHead: head, lastPushedHead: head}
+	c := &Controller{
+		cfg: testRoutingClusterConfig{remotes: []StandbyRemoteConfig{
+			testStandbyRemote{name: "standby", sqlAddr: standbyAddr},
+			testStandbyRemote{name: "unrouted"},
+		}},
+		role:        RolePrimary,
+		commithooks: []*commithook{hook},
+		lgr:         logrus.StandardLogger(),
+	}
+	router, err := c.NewReadRouter(localAddr, false)
+	require.NoError(t, err)
+	go router.Serve()
+	defer router.Close()
+
+	routedTo := func() string {
+		conn, err := net.Dial("tcp", router.listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+		name, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		return string(name)
+	}
+
+	assert.Equal(t, "standby", routedTo())
+
+	// a standby that's failing to replicate doesn't get reads
+	errStr := "failed to push"
+	hook.mu.Lock()
+	hook.currentError = &errStr
+	hook.mu.Unlock()
+	assert.Equal(t, "local", routedTo())
+
+	// and neither does one that's lagging
+	hook.mu.Lock()
+	hook.currentError = nil
+	hook.nextHead = hash.Of([]byte("next"))
+	hook.lastSuccess = time.Now().Add(-time.Minute)
+	hook.mu.Unlock()
+	assert.Equal(t, "local", routedTo())
+
+	// a standby serves its own reads
+	hook.mu.Lock()
+	hook.nextHead = head
+	hook.mu.Unlock()
+	c.mu.Lock()
+	c.role = RoleStandby
+	c.mu.Unlock()
+	assert.Equal(t, "local", routedTo())
+}
+
+// recordingServer accepts a single connection on a local port, and sends everything read from it to the returned
+// channel.
+func recordingServer(t *testing.T) (string, <-chan []byte) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	received := make(chan []byte, 16)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			buf := make([]byte, 1024)
+			n, err := conn.Read(buf)
+			if err != nil {
+				close(received)
+				return
+			}
+			received <- buf[:n]
+		}
+	}()
+	return l.Addr().String(), received
+}
+
+func mysqlPacket(seq byte, payload ...byte) []byte {
+	return append([]byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), seq}, payload...)
+}
+
+func TestReadRouterRechecksLag(t *testing.T) {
+	standbyAddr, received := recordingServer(t)
+	localAddr := nameServer(t, "local")
+
+	head := hash.Of([]byte("head"))
+	hook := &commithook{remotename: "standby", dbname: "db", role: RolePrimary, nextHead: head, lastPushedHead: head}
+	c := &Controller{
+		cfg: testRoutingClusterConfig{
+			remotes:       []StandbyRemoteConfig{testStandbyRemote{name: "standby", sqlAddr: standbyAddr}},
+			proxyProtocol: true,
+		},
+		role:        RolePrimary,
+		commithooks: []*commithook{hook},
+		lgr:         logrus.StandardLogger(),
+	}
+	router, err := c.NewReadRouter(localAddr, false)
+	require.NoError(t, err)
+	go router.Serve()
+	defer router.Close()
+
+	conn, err := net.Dial("tcp", router.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	readAll := func(n int) string {
+		var got []byte
+		for len(got) < n {
+			b, ok := <-received
+			require.True(t, ok)
+			got = append(got, b...)
+		}
+		return string(got)
+	}
+
+	// the standby sees the client's address
+	header := fmt.Sprintf("PROXY TCP4 127.0.0.1 127.0.0.1 %d %d\r\n", conn.LocalAddr().(*net.TCPAddr).Port, conn.RemoteAddr().(*net.TCPAddr).Port)
+	assert.Equal(t, header, readAll(len(header)))
+
+	handshake := mysqlPacket(1, 0x0d, 0xa2, 0, 0)
+	_, err = conn.Write(handshake)
+	require.NoError(t, err)
+	assert.Equal(t, string(handshake), readAll(len(handshake)))
+
+	begin := mysqlPacket(0, append([]byte{mysqlComQuery}, "BEGIN"...)...)
+	_, err = conn.Write(begin)
+	require.NoError(t, err)
+	assert.Equal(t, string(begin), readAll(len(begin)))
+
+	// a transaction that has begun continues on a standby that falls behind
+	hook.mu.Lock()
+	hook.nextHead = hash.Of([]byte("next"))
+	hook.lastSuccess = time.Now().Add(-time.Minute)
+	hook.mu.Unlock()
+	selectOne := mysqlPacket(0, append([]byte{mysqlComQuery}, "SELECT 1"...)...)
+	_, err = conn.Write(selectOne)
+	require.NoError(t, err)
+	assert.Equal(t, string(selectOne), readAll(len(selectOne)))
+
+	commit := mysqlPacket(0, append([]byte{mysqlComQuery}, "COMMIT"...)...)
+	_, err = conn.Write(commit)
+	require.NoError(t, err)
+	assert.Equal(t, string(commit), readAll(len(commit)))
+
+	// but the next one fails, and the connection is closed
+	_, err = conn.Write(selectOne)
+	require.NoError(t, err)
+	resp, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	require.Greater(t, len(resp), 5)
+	assert.Equal(t, byte(0xff), resp[4])
+	assert.Contains(t, string(resp), "standby standby fell behind the primary")
+	_, ok := <-received
+	assert.False(t, ok)
+}
+
+func TestRoutedTransaction(t *testing.T) {
+	var tx routedTransaction
+	assert.False(t, tx.active())
+	tx.query("start transaction read only")
+	assert.True(t, tx.active())
+	tx.query("select 1")
+	assert.True(t, tx.active())
+	tx.query("rollback to savepoint s")
+	assert.True(t, tx.active())
+	tx.query("rollback")
+	assert.False(t, tx.active())
+
+	tx.query("SET autocommit = 0")
+	assert.False(t, tx.active())
+	tx.query("select 1")
+	assert.True(t, tx.active())
+	tx.query("commit")
+	assert.False(t, tx.active())
+	tx.query("set @@autocommit='ON'")
+	tx.query("select 1")
+	assert.False(t, tx.active())
+}
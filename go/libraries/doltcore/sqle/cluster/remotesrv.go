@@ -17,6 +17,8 @@ package cluster
 import (
 	"context"
 
+	"github.com/dolthub/go-mysql-server/sql"
+
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotesrv"
 	"github.com/dolthub/dolt/go/store/hash"
 )
@@ -24,9 +26,14 @@ import (
 type remotesrvStoreCache struct {
 	remotesrv.DBCache
 	controller *Controller
+	// ctx is used to create the standby databases bootstrapped from backups.
+	ctx *sql.Context
 }
 
 func (s remotesrvStoreCache) Get(path, nbfVerStr string) (remotesrv.RemoteSrvStore, error) {
+	if err := s.controller.bootstrapStandbyDatabase(s.ctx, path); err != nil {
+		return nil, err
+	}
 	rss, err := s.DBCache.Get(path, nbfVerStr)
 	if err != nil {
 		return nil, err
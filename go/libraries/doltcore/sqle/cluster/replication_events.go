@@ -0,0 +1,197 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/clusterdb"
+)
+
+// ReplicationEventLogSize is the number of events kept in dolt_cluster_replication_events.
+const ReplicationEventLogSize = 128
+
+// ReplicationWatchBufferSize is the number of events buffered for each channel returned by
+// Controller.WatchReplicationState. Events sent to a channel whose buffer is full are dropped, so that a slow
+// receiver never holds up replication.
+const ReplicationWatchBufferSize = 64
+
+// The events recorded in dolt_cluster_replication_events.
+const (
+	// this server changed roles or epochs
+	ReplicationEventRoleChange = "role_change"
+	// replication of a database to a standby failed, after succeeding or failing with a different error
+	ReplicationEventError = "replication_error"
+	// replication of a database to a standby succeeded after failing
+	ReplicationEventRecovered = "replication_recovered"
+	// a new head of a database was replicated to a standby. these are only sent to the channels returned by
+	// Controller.WatchReplicationState, and aren't kept in dolt_cluster_replication_events.
+	ReplicationEventReplicated = "replicated"
+)
+
+// replicationEventLog keeps the last ReplicationEventLogSize events of the cluster's replication. It's shared by the
+// controller and every commithook.
+type replicationEventLog struct {
+	mu sync.Mutex
+	// role and epoch are this server's, and are recorded with every event.
+	role   Role
+	epoch  int
+	events []clusterdb.ReplicationEvent
+	// next is the index in |events| the next event is recorded at, once |events| is full.
+	next int
+	// seq is the sequence number of the last event recorded.
+	seq int64
+	// watchers are sent every event of the databases they watch as it happens.
+	watchers map[*replicationWatcher]struct{}
+}
+
+type replicationWatcher struct {
+	// dbname is the database whose events are sent to |ch|, or empty for every database.
+	dbname string
+	ch     chan clusterdb.ReplicationEvent
+}
+
+func (w *replicationWatcher) watches(e clusterdb.ReplicationEvent) bool {
+	return w.dbname == "" || e.Database == nil || *e.Database == w.dbname
+}
+
+func newReplicationEventLog(role Role, epoch int) *replicationEventLog {
+	return &replicationEventLog{
+		role:     role,
+		epoch:    epoch,
+		events:   make([]clusterdb.ReplicationEvent, 0, ReplicationEventLogSize),
+		watchers: make(map[*replicationWatcher]struct{}),
+	}
+}
+
+// setRole records that this server changed roles, from the role and epoch it was, to |role| at |epoch|.
+func (l *replicationEventLog) setRole(role Role, epoch int) {
+	l.mu.Lock()
+	from, fromEpoch := l.role, l.epoch
+	l.role, l.epoch = role, epoch
+	l.mu.Unlock()
+	l.record(clusterdb.ReplicationEvent{
+		Event:   ReplicationEventRoleChange,
+		Message: fmt.Sprintf("changed role from %s at epoch %d to %s at epoch %d", from, fromEpoch, role, epoch),
+	})
+}
+
+// record adds |e| to the log, replacing the oldest event if it's full. A nil log doesn't record anything.
+func (l *replicationEventLog) record(e clusterdb.ReplicationEvent) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seq++
+	e.Seq = l.seq
+	l.fill(&e)
+	l.notify(e)
+	if len(l.events) < ReplicationEventLogSize {
+		l.events = append(l.events, e)
+		return
+	}
+	l.events[l.next] = e
+	l.next = (l.next + 1) % ReplicationEventLogSize
+}
+
+// publish sends |e| to the watchers of its database without adding it to the log. Its Seq is 0. A nil log doesn't
+// send anything.
+func (l *replicationEventLog) publish(e clusterdb.ReplicationEvent) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.watchers) == 0 {
+		return
+	}
+	l.fill(&e)
+	l.notify(e)
+}
+
+// called with l.mu locked.
+func (l *replicationEventLog) fill(e *clusterdb.ReplicationEvent) {
+	e.Role = string(l.role)
+	e.Epoch = l.epoch
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+}
+
+// called with l.mu locked.
+func (l *replicationEventLog) notify(e clusterdb.ReplicationEvent) {
+	for w := range l.watchers {
+		if w.watches(e) {
+			select {
+			case w.ch <- e:
+			default:
+			}
+		}
+	}
+}
+
+// watch returns a channel that's sent the events of |dbname|, or of every database if it's empty, until |ctx| is
+// done, when it's closed.
+func (l *replicationEventLog) watch(ctx context.Context, dbname string) <-chan clusterdb.ReplicationEvent {
+	w := &replicationWatcher{dbname: dbname, ch: make(chan clusterdb.ReplicationEvent, ReplicationWatchBufferSize)}
+	l.mu.Lock()
+	l.watchers[w] = struct{}{}
+	l.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.watchers, w)
+		close(w.ch)
+	}()
+	return w.ch
+}
+
+// get returns the events in the log, oldest first.
+func (l *replicationEventLog) get() []clusterdb.ReplicationEvent {
+	if l == nil {
+		return []clusterdb.ReplicationEvent{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ret := make([]clusterdb.ReplicationEvent, 0, len(l.events))
+	ret = append(ret, l.events[l.next:]...)
+	ret = append(ret, l.events[:l.next]...)
+	return ret
+}
+
+func (c *Controller) GetReplicationEvents() []clusterdb.ReplicationEvent {
+	if c == nil {
+		return []clusterdb.ReplicationEvent{}
+	}
+	return c.events.get()
+}
+
+// WatchReplicationState returns a channel that's sent the replication events of the database |dbName| as they
+// happen: every successful replication of it to a standby, every failure and recovery, and every role change of this
+// server. An empty |dbName| watches every database. The channel is closed once |ctx| is done. Events are dropped,
+// rather than holding up replication, when the receiver falls ReplicationWatchBufferSize events behind.
+func (c *Controller) WatchReplicationState(ctx context.Context, dbName string) <-chan clusterdb.ReplicationEvent {
+	if c == nil {
+		ch := make(chan clusterdb.ReplicationEvent)
+		close(ch)
+		return ch
+	}
+	return c.events.watch(ctx, dbName)
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/clusterdb"
+)
+
+func TestReplicationEventLog(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		assert.Empty(t, newReplicationEventLog(RolePrimary, 1).get())
+		var l *replicationEventLog
+		l.record(clusterdb.ReplicationEvent{Event: ReplicationEventError})
+		assert.Empty(t, l.get())
+	})
+	t.Run("KeepsTheLastEvents", func(t *testing.T) {
+		l := newReplicationEventLog(RolePrimary, 1)
+		for i := 0; i < ReplicationEventLogSize+10; i++ {
+			l.record(clusterdb.ReplicationEvent{Event: ReplicationEventError})
+		}
+		events := l.get()
+		assert.Len(t, events, ReplicationEventLogSize)
+		for i, e := range events {
+			assert.Equal(t, int64(i+11), e.Seq)
+			assert.False(t, e.Time.IsZero())
+		}
+	})
+}
+
+func TestReplicationEventLogWatch(t *testing.T) {
+	l := newReplicationEventLog(RolePrimary, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	mydb := l.watch(ctx, "mydb")
+	all := l.watch(ctx, "")
+
+	db1, db2 := "mydb", "otherdb"
+	l.record(clusterdb.ReplicationEvent{Database: &db1, Event: ReplicationEventError})
+	l.publish(clusterdb.ReplicationEvent{Database: &db2, Event: ReplicationEventReplicated})
+	l.setRole(RoleStandby, 2)
+
+	e := <-mydb
+	assert.Equal(t, ReplicationEventError, e.Event)
+	assert.Equal(t, int64(1), e.Seq)
+	e = <-mydb
+	assert.Equal(t, ReplicationEventRoleChange, e.Event)
+	assert.Equal(t, "standby", e.Role)
+
+	assert.Equal(t, ReplicationEventError, (<-all).Event)
+	e = <-all
+	assert.Equal(t, ReplicationEventReplicated, e.Event)
+	assert.Equal(t, int64(0), e.Seq)
+	assert.Equal(t, "primary", e.Role)
+	assert.Equal(t, ReplicationEventRoleChange, (<-all).Event)
+
+	// published events aren't kept in the log
+	assert.Len(t, l.get(), 2)
+
+	// a slow receiver misses events instead of blocking
+	for i := 0; i < ReplicationWatchBufferSize+10; i++ {
+		l.publish(clusterdb.ReplicationEvent{Database: &db1, Event: ReplicationEventReplicated})
+	}
+	assert.Len(t, mydb, ReplicationWatchBufferSize)
+
+	cancel()
+	for range mydb {
+	}
+	for range all {
+	}
+}
@@ -16,10 +16,15 @@ package cluster
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/mysql_db"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	replicationapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/replicationapi/v1alpha1"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
@@ -38,9 +43,39 @@ type replicationServiceServer struct {
 
 	branchControl        BranchControlPersistence
 	branchControlFilesys filesys.Filesys
+
+	// roleAndEpoch returns the current role and epoch of this server.
+	roleAndEpoch func() (Role, int)
+}
+
+// acceptsUpdate returns an error if this server should not overwrite its users and grants, or its branch control
+// permissions, with the ones in a request with |ctx|. The server interceptor already refuses requests when this
+// server is a primary, but its role can change while a request is in flight. Once this server is a primary, or has
+// followed a primary to a later epoch than the request's, its own state wins, and the new primary replicates it to
+// the rest of the cluster.
+func (s *replicationServiceServer) acceptsUpdate(ctx context.Context) error {
+	if s.roleAndEpoch == nil {
+		return nil
+	}
+	role, epoch := s.roleAndEpoch()
+	if role == RolePrimary || role == RoleDetectedBrokenConfig {
+		return status.Error(codes.FailedPrecondition, fmt.Sprintf("this server is %s and is not currently accepting replication", role))
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if epochs := md.Get(clusterRoleEpochHeader); len(epochs) > 0 {
+			if reqepoch, err := strconv.Atoi(epochs[0]); err == nil && reqepoch < epoch {
+				return status.Error(codes.FailedPrecondition, fmt.Sprintf("this server is at epoch %d and does not accept replication from a primary at epoch %d", epoch, reqepoch))
+			}
+		}
+	}
+	return nil
 }
 
 func (s *replicationServiceServer) UpdateUsersAndGrants(ctx context.Context, req *replicationapi.UpdateUsersAndGrantsRequest) (*replicationapi.UpdateUsersAndGrantsResponse, error) {
+	if err := s.acceptsUpdate(ctx); err != nil {
+		s.lgr.Warnf("cluster: replicationServiceServer: not applying replicated users and grants: %v", err)
+		return nil, err
+	}
 	sqlCtx := sql.NewContext(ctx)
 	ed := s.mysqlDb.Editor()
 	defer ed.Close()
@@ -56,6 +91,10 @@ func (s *replicationServiceServer) UpdateUsersAndGrants(ctx context.Context, req
 }
 
 func (s *replicationServiceServer) UpdateBranchControl(ctx context.Context, req *replicationapi.UpdateBranchControlRequest) (*replicationapi.UpdateBranchControlResponse, error) {
+	if err := s.acceptsUpdate(ctx); err != nil {
+		s.lgr.Warnf("cluster: replicationServiceServer: not applying replicated branch control permissions: %v", err)
+		return nil, err
+	}
 	err := s.branchControl.LoadData(req.SerializedContents /* isFirstLoad */, false)
 	if err != nil {
 		return nil, err
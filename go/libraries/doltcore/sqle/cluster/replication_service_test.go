@@ -0,0 +1,51 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	replicationapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/replicationapi/v1alpha1"
+)
+
+func TestReplicationServiceRefusesUpdatesAfterRoleSwitch(t *testing.T) {
+	role, epoch := RoleStandby, 2
+	srv := &replicationServiceServer{
+		lgr:          logrus.StandardLogger().WithFields(logrus.Fields{}),
+		roleAndEpoch: func() (Role, int) { return role, epoch },
+	}
+	fromPrimaryAt := func(epoch string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(clusterRoleHeader, string(RolePrimary), clusterRoleEpochHeader, epoch))
+	}
+
+	assert.NoError(t, srv.acceptsUpdate(fromPrimaryAt("2")))
+	assert.NoError(t, srv.acceptsUpdate(fromPrimaryAt("3")))
+
+	// a standby that followed a later primary keeps its users and grants
+	_, err := srv.UpdateUsersAndGrants(fromPrimaryAt("1"), &replicationapi.UpdateUsersAndGrantsRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// as does a server that became primary while the request was in flight
+	role = RolePrimary
+	_, err = srv.UpdateBranchControl(fromPrimaryAt("2"), &replicationapi.UpdateBranchControlRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"math"
+	"sync/atomic"
+
+	"golang.org/x/time/rate"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+// replicationThrottle limits the bandwidth standby replication uses. It's shared by all of the commithooks, so the
+// limit applies to the chunks sent to every standby together. Its settings can be changed while it's in use.
+type replicationThrottle struct {
+	limiter        *rate.Limiter
+	chunksPerBatch atomic.Int64
+}
+
+// newReplicationThrottle returns a throttle with the settings in |cfg|. A nil |cfg| doesn't limit replication.
+func newReplicationThrottle(cfg ThrottleConfig) *replicationThrottle {
+	t := &replicationThrottle{limiter: rate.NewLimiter(rate.Inf, 0)}
+	if cfg != nil {
+		t.setMaxBytesPerSec(cfg.MaxBytesPerSec())
+		t.setChunksPerBatch(int64(cfg.ChunksPerBatch()))
+	}
+	return t
+}
+
+// setMaxBytesPerSec limits replication to |n| bytes per second, or doesn't limit it if |n| is 0.
+func (t *replicationThrottle) setMaxBytesPerSec(n int64) {
+	if n <= 0 {
+		t.limiter.SetLimit(rate.Inf)
+		return
+	}
+	// a second's worth of bytes can be sent at once
+	burst := n
+	if burst > math.MaxInt32 {
+		burst = math.MaxInt32
+	}
+	t.limiter.SetBurst(int(burst))
+	t.limiter.SetLimit(rate.Limit(n))
+}
+
+// setChunksPerBatch sets the most chunks sent to a standby in each table file, or the default if |n| is 0.
+func (t *replicationThrottle) setChunksPerBatch(n int64) {
+	t.chunksPerBatch.Store(n)
+}
+
+func (t *replicationThrottle) maxBytesPerSec() int64 {
+	if l := t.limiter.Limit(); l != rate.Inf {
+		return int64(l)
+	}
+	return 0
+}
+
+// pullOptions returns the options replication pulls chunks into a standby with.
+func (t *replicationThrottle) pullOptions() doltdb.PullOptions {
+	opts := doltdb.PullOptions{ChunksPerTF: int(t.chunksPerBatch.Load())}
+	if t.limiter.Limit() != rate.Inf {
+		opts.SendLimiter = t.limiter
+	}
+	return opts
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+type testThrottleConfig struct {
+	maxBytesPerSec int64
+	chunksPerBatch int
+}
+
+func (c testThrottleConfig) MaxBytesPerSec() int64 { return c.maxBytesPerSec }
+func (c testThrottleConfig) ChunksPerBatch() int   { return c.chunksPerBatch }
+
+func TestReplicationThrottle(t *testing.T) {
+	// by default, replication isn't limited
+	throttle := newReplicationThrottle(nil)
+	opts := throttle.pullOptions()
+	assert.Equal(t, 0, opts.ChunksPerTF)
+	assert.Nil(t, opts.SendLimiter)
+	assert.Equal(t, int64(0), throttle.maxBytesPerSec())
+
+	throttle = newReplicationThrottle(testThrottleConfig{maxBytesPerSec: 1 << 20, chunksPerBatch: 1024})
+	opts = throttle.pullOptions()
+	assert.Equal(t, 1024, opts.ChunksPerTF)
+	if assert.NotNil(t, opts.SendLimiter) {
+		assert.Equal(t, rate.Limit(1<<20), opts.SendLimiter.Limit())
+		assert.Equal(t, 1<<20, opts.SendLimiter.Burst())
+	}
+	assert.Equal(t, int64(1<<20), throttle.maxBytesPerSec())
+
+	// the settings can be changed at runtime, and apply to pushes already using the limiter
+	limiter := opts.SendLimiter
+	throttle.setMaxBytesPerSec(4096)
+	throttle.setChunksPerBatch(0)
+	assert.Equal(t, rate.Limit(4096), limiter.Limit())
+	assert.Equal(t, 4096, limiter.Burst())
+	assert.Equal(t, 0, throttle.pullOptions().ChunksPerTF)
+
+	throttle.setMaxBytesPerSec(0)
+	assert.Nil(t, throttle.pullOptions().SendLimiter)
+	assert.Equal(t, int64(0), throttle.maxBytesPerSec())
+}
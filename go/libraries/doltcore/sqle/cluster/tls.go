@@ -0,0 +1,206 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// certReloader loads a TLS certificate and its key from files, and loads them again when either file changes, so that
+// certificates can be rotated without restarting the server. If the files can't be loaded after they change, for
+// example because only one of them has been replaced so far, the certificate that was loaded last is used.
+type certReloader struct {
+	certPath string
+	keyPath  string
+
+	mu      sync.Mutex
+	modTime time.Time
+	cert    *tls.Certificate
+}
+
+func newCertReloader(certPath, keyPath string) (*certReloader, error) {
+	r := &certReloader{certPath: certPath, keyPath: keyPath}
+	if _, err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) load() (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	modTime, err := latestModTime(r.certPath, r.keyPath)
+	if err != nil {
+		if r.cert != nil {
+			return r.cert, nil
+		}
+		return nil, err
+	}
+	if r.cert != nil && modTime.Equal(r.modTime) {
+		return r.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
+	if err != nil {
+		if r.cert != nil {
+			return r.cert, nil
+		}
+		return nil, err
+	}
+	r.cert, r.modTime = &cert, modTime
+	return r.cert, nil
+}
+
+// GetCertificate is a tls.Config.GetCertificate for servers that present the certificate.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.load()
+}
+
+// GetClientCertificate is a tls.Config.GetClientCertificate for clients that present the certificate.
+func (r *certReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.load()
+}
+
+// caReloader loads a bundle of CA certificates from a file, and loads it again when the file changes.
+type caReloader struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	pool    *x509.CertPool
+}
+
+func newCAReloader(path string) (*caReloader, error) {
+	r := &caReloader{path: path}
+	if _, err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *caReloader) load() (*x509.CertPool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	modTime, err := latestModTime(r.path)
+	if err != nil {
+		if r.pool != nil {
+			return r.pool, nil
+		}
+		return nil, err
+	}
+	if r.pool != nil && modTime.Equal(r.modTime) {
+		return r.pool, nil
+	}
+	pem, err := os.ReadFile(r.path)
+	if err != nil {
+		if r.pool != nil {
+			return r.pool, nil
+		}
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if ok := pool.AppendCertsFromPEM(pem); !ok {
+		if r.pool != nil {
+			return r.pool, nil
+		}
+		return nil, errors.New("error loading ca roots from " + r.path)
+	}
+	r.pool, r.modTime = pool, modTime
+	return r.pool, nil
+}
+
+// verifyChain verifies that the leaf of |rawCerts| chains to one of the CAs, through the rest of |rawCerts|, and that
+// it's valid for |keyUsage|.
+func (r *caReloader) verifyChain(rawCerts [][]byte, keyUsage x509.ExtKeyUsage) ([]*x509.Certificate, error) {
+	if len(rawCerts) == 0 {
+		return nil, errors.New("no certificate presented")
+	}
+	roots, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, asn1Data := range rawCerts {
+		certs[i], err = x509.ParseCertificate(asn1Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		CurrentTime:   time.Now(),
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{keyUsage},
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err = certs[0].Verify(opts)
+	if err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+// latestModTime returns the latest modification time of the files at |paths|.
+func latestModTime(paths ...string) (time.Time, error) {
+	var latest time.Time
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// ServerTLSConfig returns the TLS config of the remotesapi server that standbys are replicated to, or nil if it
+// doesn't use TLS. The server's certificate, and the CAs that client certificates are verified against, are loaded
+// again when their files change. If tls_client_ca is configured, clients must present a certificate signed by one of
+// its CAs.
+func ServerTLSConfig(cfg Config) (*tls.Config, error) {
+	rcfg := cfg.RemotesAPIConfig()
+	if rcfg.TLSKey() == "" && rcfg.TLSCert() == "" {
+		return nil, nil
+	}
+	certs, err := newCertReloader(rcfg.TLSCert(), rcfg.TLSKey())
+	if err != nil {
+		return nil, err
+	}
+	ret := &tls.Config{
+		GetCertificate: certs.GetCertificate,
+	}
+	if rcfg.TLSClientCA() != "" {
+		clientCAs, err := newCAReloader(rcfg.TLSClientCA())
+		if err != nil {
+			return nil, err
+		}
+		// The client certificate is verified by VerifyPeerCertificate, rather than through ClientCAs, so that the
+		// CAs can be rotated.
+		ret.ClientAuth = tls.RequireAnyClientCert
+		ret.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			_, err := clientCAs.verifyChain(rawCerts, x509.ExtKeyUsageClientAuth)
+			return err
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,238 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns the PEM encoded certificate and key of a leaf signed by |ca|.
+func (ca testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+// writeRotated writes |contents| to |path| with a modification time later than any it had, as rotating the file does.
+func writeRotated(t *testing.T, path string, contents []byte, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, contents, 0600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	ca := newTestCA(t, "ca")
+	certPEM, keyPEM := ca.issue(t, "first", x509.ExtKeyUsageServerAuth)
+	start := time.Now().Add(-time.Minute)
+	writeRotated(t, certPath, certPEM, start)
+	writeRotated(t, keyPath, keyPEM, start)
+
+	_, err := newCertReloader(filepath.Join(dir, "missing.pem"), keyPath)
+	require.Error(t, err)
+
+	r, err := newCertReloader(certPath, keyPath)
+	require.NoError(t, err)
+	leafName := func() string {
+		cert, err := r.GetCertificate(nil)
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		return leaf.Subject.CommonName
+	}
+	assert.Equal(t, "first", leafName())
+
+	// while only the certificate has been replaced, the last pair that loaded is used
+	certPEM, keyPEM = ca.issue(t, "second", x509.ExtKeyUsageServerAuth)
+	writeRotated(t, certPath, certPEM, start.Add(time.Second))
+	assert.Equal(t, "first", leafName())
+
+	// once the key is replaced too, the new certificate is used
+	writeRotated(t, keyPath, keyPEM, start.Add(2*time.Second))
+	assert.Equal(t, "second", leafName())
+	cert, err := r.GetClientCertificate(nil)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "second", leaf.Subject.CommonName)
+}
+
+func TestCAReloader(t *testing.T) {
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.pem")
+	oldCA, newCA := newTestCA(t, "old"), newTestCA(t, "new")
+	start := time.Now().Add(-time.Minute)
+	writeRotated(t, caPath, oldCA.pem, start)
+
+	r, err := newCAReloader(caPath)
+	require.NoError(t, err)
+	oldLeaf, _ := oldCA.issue(t, "client", x509.ExtKeyUsageClientAuth)
+	newLeaf, _ := newCA.issue(t, "client", x509.ExtKeyUsageClientAuth)
+	rawCert := func(certPEM []byte) [][]byte {
+		block, _ := pem.Decode(certPEM)
+		return [][]byte{block.Bytes}
+	}
+
+	_, err = r.verifyChain(rawCert(oldLeaf), x509.ExtKeyUsageClientAuth)
+	assert.NoError(t, err)
+	_, err = r.verifyChain(rawCert(newLeaf), x509.ExtKeyUsageClientAuth)
+	assert.Error(t, err)
+	_, err = r.verifyChain(rawCert(oldLeaf), x509.ExtKeyUsageServerAuth)
+	assert.Error(t, err)
+	_, err = r.verifyChain(nil, x509.ExtKeyUsageClientAuth)
+	assert.Error(t, err)
+
+	// a file that isn't a CA bundle doesn't replace the CAs that loaded last
+	writeRotated(t, caPath, []byte("not a certificate"), start.Add(time.Second))
+	_, err = r.verifyChain(rawCert(oldLeaf), x509.ExtKeyUsageClientAuth)
+	assert.NoError(t, err)
+
+	writeRotated(t, caPath, newCA.pem, start.Add(2*time.Second))
+	_, err = r.verifyChain(rawCert(newLeaf), x509.ExtKeyUsageClientAuth)
+	assert.NoError(t, err)
+	_, err = r.verifyChain(rawCert(oldLeaf), x509.ExtKeyUsageClientAuth)
+	assert.Error(t, err)
+}
+
+type testRemotesAPIConfig struct {
+	tlsCert, tlsKey, tlsClientCA string
+}
+
+func (c testRemotesAPIConfig) Address() string                { return "" }
+func (c testRemotesAPIConfig) Port() int                      { return 0 }
+func (c testRemotesAPIConfig) TLSKey() string                 { return c.tlsKey }
+func (c testRemotesAPIConfig) TLSCert() string                { return c.tlsCert }
+func (c testRemotesAPIConfig) TLSCA() string                  { return "" }
+func (c testRemotesAPIConfig) TLSClientCert() string          { return "" }
+func (c testRemotesAPIConfig) TLSClientKey() string           { return "" }
+func (c testRemotesAPIConfig) TLSClientCA() string            { return c.tlsClientCA }
+func (c testRemotesAPIConfig) ServerNameURLMatches() []string { return nil }
+func (c testRemotesAPIConfig) ServerNameDNSMatches() []string { return nil }
+
+type testTLSClusterConfig struct {
+	testRoutingClusterConfig
+	remotesAPI testRemotesAPIConfig
+}
+
+func (c testTLSClusterConfig) RemotesAPIConfig() RemotesAPIConfig { return c.remotesAPI }
+
+// handshake runs a TLS handshake between a server using |serverCfg| and a client presenting |clientCert|, if it
+// isn't nil, and returns the server's error.
+func handshake(t *testing.T, serverCfg *tls.Config, roots *x509.CertPool, clientCert *tls.Certificate) error {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+	clientCfg := &tls.Config{RootCAs: roots, ServerName: "standby"}
+	if clientCert != nil {
+		clientCfg.Certificates = []tls.Certificate{*clientCert}
+	}
+	go func() {
+		// the client reads until the server hangs up, so that the server's alerts don't block
+		client := tls.Client(clientConn, clientCfg)
+		if client.Handshake() == nil {
+			io.Copy(io.Discard, client)
+		}
+	}()
+	server := tls.Server(serverConn, serverCfg)
+	return server.Handshake()
+}
+
+func TestServerTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, "ca")
+	certPEM, keyPEM := ca.issue(t, "standby", x509.ExtKeyUsageServerAuth)
+	certPath, keyPath, clientCAPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), filepath.Join(dir, "client_ca.pem")
+	start := time.Now().Add(-time.Minute)
+	writeRotated(t, certPath, certPEM, start)
+	writeRotated(t, keyPath, keyPEM, start)
+	writeRotated(t, clientCAPath, ca.pem, start)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	cfg, err := ServerTLSConfig(testTLSClusterConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, cfg)
+
+	// without a client CA, clients don't need certificates
+	cfg, err = ServerTLSConfig(testTLSClusterConfig{remotesAPI: testRemotesAPIConfig{tlsCert: certPath, tlsKey: keyPath}})
+	require.NoError(t, err)
+	assert.NoError(t, handshake(t, cfg, roots, nil))
+
+	cfg, err = ServerTLSConfig(testTLSClusterConfig{remotesAPI: testRemotesAPIConfig{tlsCert: certPath, tlsKey: keyPath, tlsClientCA: clientCAPath}})
+	require.NoError(t, err)
+	clientCertPEM, clientKeyPEM := ca.issue(t, "primary", x509.ExtKeyUsageClientAuth)
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+	assert.Error(t, handshake(t, cfg, roots, nil))
+	assert.NoError(t, handshake(t, cfg, roots, &clientCert))
+
+	// once the client CA is rotated, certificates signed by the old one are turned away
+	writeRotated(t, clientCAPath, newTestCA(t, "rotated").pem, start.Add(time.Second))
+	assert.Error(t, handshake(t, cfg, roots, &clientCert))
+}
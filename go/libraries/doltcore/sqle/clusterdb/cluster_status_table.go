@@ -24,7 +24,8 @@ import (
 type ReplicaStatus struct {
 	// The name of the database this replica status represents.
 	Database string
-	// The role this server is currently running as. "primary" or "standby".
+	// The role this server is currently running as. "primary", "standby" or
+	// "observer".
 	Role string
 	// The epoch of this server's current role.
 	Epoch int
@@ -38,10 +39,39 @@ type ReplicaStatus struct {
 	// A string describing the last encountered error.  NULL when we are a
 	// standby. NULL when our last replication attempt succeeded.
 	CurrentError *string
+	// The number of replication attempts that have failed since the last
+	// one that succeeded. 0 when we are a standby.
+	RetryAttempts int
+	// When the next replication attempt will be made, if it has been
+	// delayed after a failed attempt. NULL otherwise.
+	NextRetry *time.Time
+	// The root hash waiting to be replicated to the standby. NULL when we
+	// are a standby, or before we have read one.
+	NextHead *string
+	// The last root hash successfully replicated to the standby. NULL when
+	// we are a standby, or before the first successful replication.
+	LastPushedHead *string
+	// The delay between the last failed replication attempt and the next
+	// one. NULL when the last replication attempt succeeded.
+	CurrentBackoff *time.Duration
+	// The number of bytes of the current replication attempt that are
+	// written but not yet sent to the standby. 0 when no attempt is
+	// running.
+	BytesPending uint64
+	// The number of replication attempts to the standby that have failed
+	// since the server started replicating the database. Reported in
+	// metrics, but not in dolt_cluster_status.
+	FailedAttempts uint64
+	// The state of this database during a graceful transition to
+	// standby: "draining" while open transactions finish, and then
+	// "catching_up" or "caught_up" while it's replicated to the standby.
+	// NULL when there is no transition in progress.
+	TransitionState *string
 }
 
 type ClusterStatusProvider interface {
 	GetClusterStatus() []ReplicaStatus
+	GetReplicationEvents() []ReplicationEvent
 }
 
 var _ sql.Table = ClusterStatusTable{}
@@ -93,7 +123,7 @@ func replicaStatusesToRows(rss []ReplicaStatus) []sql.Row {
 }
 
 func replicaStatusToRow(rs ReplicaStatus) sql.Row {
-	ret := make(sql.Row, 7)
+	ret := make(sql.Row, 14)
 	ret[0] = rs.Database
 	ret[1] = rs.Remote
 	ret[2] = rs.Role
@@ -107,6 +137,23 @@ func replicaStatusToRow(rs ReplicaStatus) sql.Row {
 	if rs.CurrentError != nil {
 		ret[6] = *rs.CurrentError
 	}
+	ret[7] = int64(rs.RetryAttempts)
+	if rs.NextRetry != nil {
+		ret[8] = *rs.NextRetry
+	}
+	if rs.NextHead != nil {
+		ret[9] = *rs.NextHead
+	}
+	if rs.LastPushedHead != nil {
+		ret[10] = *rs.LastPushedHead
+	}
+	if rs.CurrentBackoff != nil {
+		ret[11] = rs.CurrentBackoff.Milliseconds()
+	}
+	ret[12] = rs.BytesPending
+	if rs.TransitionState != nil {
+		ret[13] = *rs.TransitionState
+	}
 	return ret
 }
 
@@ -119,5 +166,12 @@ func (t ClusterStatusTable) Schema() sql.Schema {
 		{Name: "replication_lag_millis", Type: types.Int64, Source: StatusTableName, PrimaryKey: false, Nullable: true},
 		{Name: "last_update", Type: types.Datetime, Source: StatusTableName, PrimaryKey: false, Nullable: true},
 		{Name: "current_error", Type: types.Text, Source: StatusTableName, PrimaryKey: false, Nullable: true},
+		{Name: "retry_attempts", Type: types.Int64, Source: StatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "next_retry_at", Type: types.Datetime, Source: StatusTableName, PrimaryKey: false, Nullable: true},
+		{Name: "next_head", Type: types.Text, Source: StatusTableName, PrimaryKey: false, Nullable: true},
+		{Name: "last_pushed_head", Type: types.Text, Source: StatusTableName, PrimaryKey: false, Nullable: true},
+		{Name: "current_backoff_millis", Type: types.Int64, Source: StatusTableName, PrimaryKey: false, Nullable: true},
+		{Name: "bytes_pending", Type: types.Uint64, Source: StatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "transition_state", Type: types.Text, Source: StatusTableName, PrimaryKey: false, Nullable: true},
 	}
 }
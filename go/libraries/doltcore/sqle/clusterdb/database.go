@@ -38,6 +38,7 @@ var _ sql.Database = database{}
 var _ dsess.SqlDatabase = database{}
 
 const StatusTableName = "dolt_cluster_status"
+const ReplicationEventsTableName = "dolt_cluster_replication_events"
 
 func (database) Name() string {
 	return DoltClusterDbName
@@ -48,17 +49,29 @@ func (db database) GetTableInsensitive(ctx *sql.Context, tblName string) (sql.Ta
 	if tblName == StatusTableName {
 		return NewClusterStatusTable(db.statusProvider), true, nil
 	}
+	if tblName == ReplicationEventsTableName {
+		return NewReplicationEventsTable(db.statusProvider), true, nil
+	}
 	return nil, false, nil
 }
 
 func (database) GetTableNames(ctx *sql.Context) ([]string, error) {
-	return []string{StatusTableName}, nil
+	return []string{StatusTableName, ReplicationEventsTableName}, nil
 }
 
 func NewClusterDatabase(p ClusterStatusProvider) sql.Database {
 	return database{p}
 }
 
+// StatusProviderFor returns the ClusterStatusProvider of |db|, if it's the dolt_cluster database.
+func StatusProviderFor(db sql.Database) (ClusterStatusProvider, bool) {
+	cdb, ok := db.(database)
+	if !ok || cdb.statusProvider == nil {
+		return nil, false
+	}
+	return cdb.statusProvider, true
+}
+
 // Implement StoredProcedureDatabase so that external stored procedures are available.
 var _ sql.StoredProcedureDatabase = database{}
 
@@ -186,3 +199,11 @@ func (n noopRepoStateWriter) TempTableFilesDir() (string, error) {
 func (n noopRepoStateWriter) UpdateBranch(name string, new env.BranchConfig) error {
 	return nil
 }
+
+func (n noopRepoStateWriter) RemoveBranch(name string) error {
+	return nil
+}
+
+func (n noopRepoStateWriter) SetBranchExpiration(name string, expiration env.BranchExpiration) error {
+	return nil
+}
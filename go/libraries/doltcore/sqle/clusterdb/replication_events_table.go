@@ -0,0 +1,112 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterdb
+
+import (
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+type ReplicationEvent struct {
+	// Increases by one with every event recorded since the server started.
+	// 0 for events that are only sent to watchers, and aren't recorded.
+	Seq int64
+	// When the event happened.
+	Time time.Time
+	// The database the event happened to. NULL for role changes, which
+	// happen to every database.
+	Database *string
+	// The standby remote the event happened to. NULL for role changes.
+	Remote *string
+	// The kind of event, for example "role_change" or "replication_error".
+	Event string
+	// The role and epoch of this server after the event.
+	Role  string
+	Epoch int
+	// A description of the event.
+	Message string
+}
+
+var _ sql.Table = ReplicationEventsTable{}
+
+func NewReplicationEventsTable(provider ClusterStatusProvider) sql.Table {
+	return ReplicationEventsTable{provider}
+}
+
+// ReplicationEventsTable shows the most recent role changes of this server, and the most recent changes in the
+// state of its replication to its standbys.
+type ReplicationEventsTable struct {
+	provider ClusterStatusProvider
+}
+
+func (t ReplicationEventsTable) Name() string {
+	return ReplicationEventsTableName
+}
+
+func (t ReplicationEventsTable) String() string {
+	return ReplicationEventsTableName
+}
+
+func (t ReplicationEventsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+func (t ReplicationEventsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return sql.PartitionsToPartitionIter((*partition)(nil)), nil
+}
+
+func (t ReplicationEventsTable) PartitionRows(*sql.Context, sql.Partition) (sql.RowIter, error) {
+	if t.provider == nil {
+		return sql.RowsToRowIter(), nil
+	}
+	events := t.provider.GetReplicationEvents()
+	rows := make([]sql.Row, len(events))
+	for i, e := range events {
+		rows[i] = replicationEventToRow(e)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+func replicationEventToRow(e ReplicationEvent) sql.Row {
+	ret := make(sql.Row, 8)
+	ret[0] = e.Seq
+	ret[1] = e.Time
+	if e.Database != nil {
+		ret[2] = *e.Database
+	}
+	if e.Remote != nil {
+		ret[3] = *e.Remote
+	}
+	ret[4] = e.Event
+	ret[5] = e.Role
+	ret[6] = int64(e.Epoch)
+	ret[7] = e.Message
+	return ret
+}
+
+func (t ReplicationEventsTable) Schema() sql.Schema {
+	return sql.Schema{
+		{Name: "seq", Type: types.Int64, Source: ReplicationEventsTableName, PrimaryKey: true, Nullable: false},
+		{Name: "event_time", Type: types.Datetime, Source: ReplicationEventsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "database", Type: types.Text, Source: ReplicationEventsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "standby_remote", Type: types.Text, Source: ReplicationEventsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "event", Type: types.Text, Source: ReplicationEventsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "role", Type: types.Text, Source: ReplicationEventsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "epoch", Type: types.Int64, Source: ReplicationEventsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "message", Type: types.Text, Source: ReplicationEventsTableName, PrimaryKey: false, Nullable: false},
+	}
+}
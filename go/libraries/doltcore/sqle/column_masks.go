@@ -0,0 +1,147 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// maskedColumnsTable is a table whose columns can be masked.
+type maskedColumnsTable interface {
+	// maskedColumns returns the lowercase names of the table's columns that are masked from the current user.
+	maskedColumns(ctx *sql.Context) (map[string]struct{}, error)
+}
+
+var _ maskedColumnsTable = (*DoltTable)(nil)
+var _ maskedColumnsTable = (*IndexedDoltTable)(nil)
+
+func (t *DoltTable) maskedColumns(ctx *sql.Context) (map[string]struct{}, error) {
+	return dsess.MaskedColumns(ctx, t.db.DbData().Ddb, t.sch)
+}
+
+func (idt *IndexedDoltTable) maskedColumns(ctx *sql.Context) (map[string]struct{}, error) {
+	return idt.table.maskedColumns(ctx)
+}
+
+// maskedTableColumns are the masked columns of a table in a plan.
+type maskedTableColumns struct {
+	// table is the name of the table, rather than its alias in the plan
+	table   string
+	columns map[string]struct{}
+}
+
+// checkMaskedColumnFilters returns an error if |n| filters or joins on a column that's masked from the current user.
+// Filters evaluated above a table only see masked values, but the analyzer is free to turn them into index lookups,
+// which compare real ones, so filters on masked columns are rejected wherever they end up being evaluated.
+func checkMaskedColumnFilters(ctx *sql.Context, n sql.Node) error {
+	if dsess.CanUnmask(ctx) {
+		return nil
+	}
+
+	// masked columns are keyed by the lowercase name of their table, or its alias, in the plan
+	masked := make(map[string]maskedTableColumns)
+	var err error
+	inspectWithSubqueries(n, func(n sql.Node) {
+		var name string
+		var tbl sql.Table
+		switch n := n.(type) {
+		case *plan.TableAlias:
+			name = n.Name()
+			if child, ok := n.Child.(sql.TableNode); ok {
+				tbl = child.UnderlyingTable()
+			}
+		case *plan.ResolvedTable:
+			name, tbl = n.Name(), n.Table
+		case *plan.IndexedTableAccess:
+			name, tbl = n.Name(), n.Table
+		}
+		// tables may be wrapped, such as to track the progress of the query
+		for {
+			w, ok := tbl.(sql.TableWrapper)
+			if !ok {
+				break
+			}
+			tbl = w.Underlying()
+		}
+		mt, ok := tbl.(maskedColumnsTable)
+		if !ok || err != nil {
+			return
+		}
+		var cols map[string]struct{}
+		cols, err = mt.maskedColumns(ctx)
+		if len(cols) > 0 {
+			masked[strings.ToLower(name)] = maskedTableColumns{table: tbl.Name(), columns: cols}
+		}
+	})
+	if err != nil || len(masked) == 0 {
+		return err
+	}
+
+	inspectWithSubqueries(n, func(n sql.Node) {
+		var cond sql.Expression
+		switch n := n.(type) {
+		case *plan.Filter:
+			cond = n.Expression
+		case *plan.Having:
+			cond = n.Cond
+		case *plan.JoinNode:
+			cond = n.Filter
+		}
+		if cond == nil || err != nil {
+			return
+		}
+		transform.InspectExpr(cond, func(e sql.Expression) bool {
+			gf, ok := e.(*expression.GetField)
+			if !ok {
+				return false
+			}
+			mt := masked[strings.ToLower(gf.Table())]
+			if _, ok := mt.columns[strings.ToLower(gf.Name())]; ok {
+				err = dsess.ErrMaskedColumnLookup.New(gf.Name(), mt.table)
+				return true
+			}
+			return false
+		})
+	})
+	return err
+}
+
+// inspectWithSubqueries calls |f| with each node of |n|, including the nodes of its subquery expressions.
+func inspectWithSubqueries(n sql.Node, f func(sql.Node)) {
+	transform.Inspect(n, func(n sql.Node) bool {
+		if n == nil {
+			return false
+		}
+		f(n)
+		if ne, ok := n.(sql.Expressioner); ok {
+			for _, e := range ne.Expressions() {
+				transform.InspectExpr(e, func(e sql.Expression) bool {
+					if sq, ok := e.(*plan.Subquery); ok {
+						inspectWithSubqueries(sq.Query, f)
+					}
+					return false
+				})
+			}
+		}
+		return true
+	})
+}
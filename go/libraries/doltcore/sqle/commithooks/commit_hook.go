@@ -0,0 +1,91 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commithooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+// commitHook runs the user-defined commit hooks matching a single database when one of its branches gets a new head.
+type commitHook struct {
+	r      *Runner
+	dbName string
+	out    io.Writer
+}
+
+var _ doltdb.CommitHook = (*commitHook)(nil)
+
+// InstallCommitHook adds a commit hook to |ddb| that runs the hooks matching the database named, after the
+// replication hooks already installed.
+func (r *Runner) InstallCommitHook(ctx context.Context, dbName string, ddb *doltdb.DoltDB) {
+	if r == nil || !r.hasHooks(dbName) {
+		return
+	}
+	ddb.AppendCommitHook(ctx, &commitHook{r: r, dbName: dbName})
+}
+
+// Execute implements doltdb.CommitHook
+func (h *commitHook) Execute(ctx context.Context, ds datas.Dataset, db datas.Database) (func(context.Context) error, error) {
+	if !ref.IsRef(ds.ID()) || !ds.HasHead() || ds.IsTag() {
+		return nil, nil
+	}
+	r, err := ref.Parse(ds.ID())
+	if err != nil {
+		return nil, err
+	}
+	if r.GetType() != ref.BranchRefType {
+		return nil, nil
+	}
+
+	addr, _ := ds.MaybeHeadAddr()
+	c := Commit{Database: h.dbName, Branch: r.GetPath(), Hash: addr.String()}
+	head, _ := ds.MaybeHead()
+	meta, err := datas.GetCommitMeta(ctx, head)
+	if err != nil {
+		return nil, err
+	}
+	if meta != nil {
+		c.Author = fmt.Sprintf("%s <%s>", meta.Name, meta.Email)
+		c.Message = meta.Description
+	}
+
+	h.r.Run(ctx, c)
+	return nil, nil
+}
+
+// HandleError implements doltdb.CommitHook
+func (h *commitHook) HandleError(ctx context.Context, err error) error {
+	if h.out != nil {
+		h.out.Write([]byte(fmt.Sprintf("error running commit hooks for database '%s': %s\n", h.dbName, err.Error())))
+	}
+	return nil
+}
+
+// SetLogger implements doltdb.CommitHook
+func (h *commitHook) SetLogger(ctx context.Context, wr io.Writer) error {
+	h.out = wr
+	return nil
+}
+
+// ExecuteForWorkingSets implements doltdb.CommitHook
+func (h *commitHook) ExecuteForWorkingSets() bool {
+	return false
+}
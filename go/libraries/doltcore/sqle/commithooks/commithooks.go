@@ -0,0 +1,441 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commithooks
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Mode is when a commit hook runs relative to the write that triggered it.
+type Mode string
+
+const (
+	// Async hooks are queued and run in the background, without delaying the write.
+	Async Mode = "async"
+	// Sync hooks run before the write that triggered them returns to the client.
+	Sync Mode = "sync"
+)
+
+// FailurePolicy is what happens when a commit hook fails. The commit has already been written by the time its hooks
+// run, so a failing hook can't undo it.
+type FailurePolicy string
+
+const (
+	// FailureLog logs the failure to the server log.
+	FailureLog FailurePolicy = "log"
+	// FailureRetry retries the hook with exponential backoff, up to MaxAttempts attempts, and then logs the failure.
+	FailureRetry FailurePolicy = "retry"
+	// FailureIgnore silently ignores the failure.
+	FailureIgnore FailurePolicy = "ignore"
+)
+
+// DefaultGrpcMethod is the method called on a hook's gRPC endpoint if none is configured. It takes a
+// google.protobuf.Struct describing the commit and returns google.protobuf.Empty.
+const DefaultGrpcMethod = "/dolt.services.commithooks.v1alpha1.CommitHookService/OnCommit"
+
+const (
+	defaultTimeout     = 30 * time.Second
+	defaultMaxAttempts = 5
+	maxBackoff         = time.Minute
+	queueSize          = 1024
+	runThreadName      = "commit_hooks"
+)
+
+// Config is a single user-defined commit hook, which either runs an external program or calls a gRPC endpoint when a
+// branch of a matching database gets a new head. Exactly one of Command and GrpcEndpoint must be set. If Databases is
+// empty the hook runs for every database, and if Branches is empty for every branch. Branches are matched as glob
+// patterns, e.g. release/*.
+//
+// A Command is run with the commit described by DOLT_DATABASE, DOLT_BRANCH, DOLT_COMMIT, DOLT_AUTHOR and
+// DOLT_MESSAGE in its environment, and fails by exiting with a non-zero status. A gRPC endpoint is called with a
+// google.protobuf.Struct with the fields database, branch, commit, author and message.
+type Config struct {
+	Name          string        `yaml:"name"`
+	Command       []string      `yaml:"command,omitempty"`
+	GrpcEndpoint  string        `yaml:"grpc_endpoint,omitempty"`
+	GrpcMethod    string        `yaml:"grpc_method,omitempty"`
+	GrpcTLS       bool          `yaml:"grpc_tls,omitempty"`
+	Databases     []string      `yaml:"databases,omitempty"`
+	Branches      []string      `yaml:"branches,omitempty"`
+	Mode          Mode          `yaml:"mode,omitempty"`
+	OnFailure     FailurePolicy `yaml:"on_failure,omitempty"`
+	MaxAttempts   *int          `yaml:"max_attempts,omitempty"`
+	TimeoutMillis *int          `yaml:"timeout_millis,omitempty"`
+}
+
+// Validate returns an error if any of the commit hooks given is misconfigured.
+func Validate(configs []Config) error {
+	names := make(map[string]bool)
+	for i, c := range configs {
+		if c.Name == "" {
+			return fmt.Errorf("commit_hooks[%d]: name must be set", i)
+		}
+		if names[c.Name] {
+			return fmt.Errorf("commit_hooks[%d]: duplicate name '%s'", i, c.Name)
+		}
+		names[c.Name] = true
+		if (len(c.Command) == 0) == (c.GrpcEndpoint == "") {
+			return fmt.Errorf("commit_hooks[%d]: exactly one of 'command' or 'grpc_endpoint' must be set", i)
+		}
+		for _, b := range c.Branches {
+			if _, err := path.Match(b, ""); err != nil {
+				return fmt.Errorf("commit_hooks[%d]: branches: invalid pattern '%s'", i, b)
+			}
+		}
+		switch c.Mode {
+		case "", Async, Sync:
+		default:
+			return fmt.Errorf("commit_hooks[%d]: mode: must be '%s' or '%s'", i, Async, Sync)
+		}
+		switch c.OnFailure {
+		case "", FailureLog, FailureRetry, FailureIgnore:
+		default:
+			return fmt.Errorf("commit_hooks[%d]: on_failure: must be '%s', '%s' or '%s'", i, FailureLog, FailureRetry, FailureIgnore)
+		}
+		if c.MaxAttempts != nil && *c.MaxAttempts < 1 {
+			return fmt.Errorf("commit_hooks[%d]: max_attempts: must be at least 1", i)
+		}
+		if c.TimeoutMillis != nil && *c.TimeoutMillis < 1 {
+			return fmt.Errorf("commit_hooks[%d]: timeout_millis: must be at least 1", i)
+		}
+	}
+	return nil
+}
+
+func (c Config) matches(dbName, branch string) bool {
+	if len(c.Databases) > 0 && !contains(c.Databases, dbName) {
+		return false
+	}
+	if len(c.Branches) == 0 {
+		return true
+	}
+	for _, pattern := range c.Branches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Config) mode() Mode {
+	if c.Mode == "" {
+		return Async
+	}
+	return c.Mode
+}
+
+func (c Config) onFailure() FailurePolicy {
+	if c.OnFailure == "" {
+		return FailureLog
+	}
+	return c.OnFailure
+}
+
+func (c Config) maxAttempts() int {
+	if c.onFailure() != FailureRetry {
+		return 1
+	}
+	if c.MaxAttempts == nil {
+		return defaultMaxAttempts
+	}
+	return *c.MaxAttempts
+}
+
+func (c Config) timeout() time.Duration {
+	if c.TimeoutMillis == nil {
+		return defaultTimeout
+	}
+	return time.Duration(*c.TimeoutMillis) * time.Millisecond
+}
+
+func contains(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Commit describes the new head of a branch that hooks are run for.
+type Commit struct {
+	Database string
+	Branch   string
+	Hash     string
+	Author   string
+	Message  string
+}
+
+func (c Commit) env() []string {
+	return []string{
+		"DOLT_DATABASE=" + c.Database,
+		"DOLT_BRANCH=" + c.Branch,
+		"DOLT_COMMIT=" + c.Hash,
+		"DOLT_AUTHOR=" + c.Author,
+		"DOLT_MESSAGE=" + c.Message,
+	}
+}
+
+func (c Commit) grpcRequest() (*structpb.Struct, error) {
+	return structpb.NewStruct(map[string]interface{}{
+		"database": c.Database,
+		"branch":   c.Branch,
+		"commit":   c.Hash,
+		"author":   c.Author,
+		"message":  c.Message,
+	})
+}
+
+// Logger receives the errors of failed hooks.
+type Logger func(hook string, c Commit, err error)
+
+type run struct {
+	config   Config
+	commit   Commit
+	attempts int
+}
+
+// Runner runs the configured commit hooks, running async hooks on a background thread.
+type Runner struct {
+	configs []Config
+	queue   chan *run
+	backoff func(attempt int) time.Duration
+
+	mu     *sync.Mutex
+	conns  map[string]*grpc.ClientConn
+	logger Logger
+}
+
+// NewRunner returns a Runner for the commit hooks given, or nil if there are none.
+func NewRunner(configs []Config) *Runner {
+	if len(configs) == 0 {
+		return nil
+	}
+	return &Runner{
+		configs: configs,
+		queue:   make(chan *run, queueSize),
+		backoff: exponentialBackoff,
+		mu:      &sync.Mutex{},
+		conns:   make(map[string]*grpc.ClientConn),
+		logger:  logFailure,
+	}
+}
+
+func logFailure(hook string, c Commit, err error) {
+	logrus.Errorf("commit hook '%s' failed for branch '%s' of database '%s' at commit %s: %s", hook, c.Branch, c.Database, c.Hash, err.Error())
+}
+
+func exponentialBackoff(attempt int) time.Duration {
+	d := time.Second << (attempt - 1)
+	if d <= 0 || d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
+
+// SetLogger sets the function failed hooks are reported to. By default they're logged to the server log.
+func (r *Runner) SetLogger(logger Logger) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logger = logger
+}
+
+// Start starts running async hooks on a background thread.
+func (r *Runner) Start(bThreads *sql.BackgroundThreads) error {
+	if r == nil {
+		return nil
+	}
+	return bThreads.Add(runThreadName, r.runQueue)
+}
+
+// hasHooks returns whether any hook matches the database named.
+func (r *Runner) hasHooks(dbName string) bool {
+	for _, c := range r.configs {
+		if len(c.Databases) == 0 || contains(c.Databases, dbName) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs the hooks matching |c|. Sync hooks are run before it returns, including any retries, and async hooks are
+// queued. It never returns an error; failures are handled according to each hook's failure policy.
+func (r *Runner) Run(ctx context.Context, c Commit) {
+	if r == nil {
+		return
+	}
+	for _, cfg := range r.configs {
+		if !cfg.matches(c.Database, c.Branch) {
+			continue
+		}
+		rn := &run{config: cfg, commit: c}
+		if cfg.mode() == Sync {
+			for !r.attempt(ctx, rn) {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(r.backoff(rn.attempts)):
+				}
+			}
+			continue
+		}
+		select {
+		case r.queue <- rn:
+		default:
+			r.fail(rn, fmt.Errorf("commit hook queue is full"))
+		}
+	}
+}
+
+func (r *Runner) runQueue(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			r.closeConns()
+			return
+		case rn := <-r.queue:
+			if !r.attempt(ctx, rn) {
+				r.retryLater(ctx, rn)
+			}
+		}
+	}
+}
+
+func (r *Runner) retryLater(ctx context.Context, rn *run) {
+	wait := r.backoff(rn.attempts)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-time.After(wait):
+			select {
+			case r.queue <- rn:
+			case <-ctx.Done():
+			}
+		}
+	}()
+}
+
+// attempt runs a hook once. It returns false if it failed and should be retried.
+func (r *Runner) attempt(ctx context.Context, rn *run) bool {
+	ctx, cancel := context.WithTimeout(ctx, rn.config.timeout())
+	defer cancel()
+
+	var err error
+	if len(rn.config.Command) > 0 {
+		err = runCommand(ctx, rn.config.Command, rn.commit)
+	} else {
+		err = r.callGrpc(ctx, rn.config, rn.commit)
+	}
+	rn.attempts++
+
+	if err == nil {
+		return true
+	}
+	if rn.attempts < rn.config.maxAttempts() {
+		return false
+	}
+	r.fail(rn, err)
+	return true
+}
+
+func (r *Runner) fail(rn *run, err error) {
+	if rn.config.onFailure() == FailureIgnore {
+		return
+	}
+	r.mu.Lock()
+	logger := r.logger
+	r.mu.Unlock()
+	if logger != nil {
+		logger(rn.config.Name, rn.commit, err)
+	}
+}
+
+func runCommand(ctx context.Context, command []string, c Commit) error {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = append(os.Environ(), c.env()...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if len(msg) == 0 {
+			return err
+		}
+		return fmt.Errorf("%s: %s", err.Error(), msg)
+	}
+	return nil
+}
+
+func (r *Runner) callGrpc(ctx context.Context, cfg Config, c Commit) error {
+	conn, err := r.conn(cfg)
+	if err != nil {
+		return err
+	}
+	req, err := c.grpcRequest()
+	if err != nil {
+		return err
+	}
+	method := cfg.GrpcMethod
+	if method == "" {
+		method = DefaultGrpcMethod
+	}
+	return conn.Invoke(ctx, method, req, &emptypb.Empty{})
+}
+
+// conn returns the connection to |cfg|'s gRPC endpoint, dialing it the first time it's used.
+func (r *Runner) conn(cfg Config) (*grpc.ClientConn, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if conn, ok := r.conns[cfg.Name]; ok {
+		return conn, nil
+	}
+
+	creds := insecure.NewCredentials()
+	if cfg.GrpcTLS {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+	conn, err := grpc.Dial(cfg.GrpcEndpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	r.conns[cfg.Name] = conn
+	return conn, nil
+}
+
+func (r *Runner) closeConns() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, conn := range r.conns {
+		conn.Close()
+		delete(r.conns, name)
+	}
+}
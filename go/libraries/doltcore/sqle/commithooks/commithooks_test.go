@@ -0,0 +1,147 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commithooks
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestValidate(t *testing.T) {
+	zero := 0
+	assert.NoError(t, Validate(nil))
+	assert.NoError(t, Validate([]Config{
+		{Name: "notify", Command: []string{"/bin/true"}, Branches: []string{"release/*"}, Mode: Sync},
+		{Name: "index", GrpcEndpoint: "localhost:50051", OnFailure: FailureRetry},
+	}))
+	assert.Error(t, Validate([]Config{{Command: []string{"/bin/true"}}}))
+	assert.Error(t, Validate([]Config{{Name: "a", Command: []string{"/bin/true"}}, {Name: "a", Command: []string{"/bin/true"}}}))
+	assert.Error(t, Validate([]Config{{Name: "a"}}))
+	assert.Error(t, Validate([]Config{{Name: "a", Command: []string{"/bin/true"}, GrpcEndpoint: "localhost:50051"}}))
+	assert.Error(t, Validate([]Config{{Name: "a", Command: []string{"/bin/true"}, Branches: []string{"[main"}}}))
+	assert.Error(t, Validate([]Config{{Name: "a", Command: []string{"/bin/true"}, Mode: "deferred"}}))
+	assert.Error(t, Validate([]Config{{Name: "a", Command: []string{"/bin/true"}, OnFailure: "panic"}}))
+	assert.Error(t, Validate([]Config{{Name: "a", Command: []string{"/bin/true"}, MaxAttempts: &zero}}))
+}
+
+func TestMatches(t *testing.T) {
+	c := Config{Databases: []string{"mydb"}, Branches: []string{"main", "release/*"}}
+	assert.True(t, c.matches("mydb", "main"))
+	assert.True(t, c.matches("mydb", "release/1.0"))
+	assert.False(t, c.matches("mydb", "feature"))
+	assert.False(t, c.matches("otherdb", "main"))
+	assert.True(t, Config{}.matches("otherdb", "feature"))
+}
+
+func TestNilRunner(t *testing.T) {
+	r := NewRunner(nil)
+	assert.Nil(t, r)
+	r.Run(context.Background(), Commit{Database: "mydb", Branch: "main"})
+	assert.NoError(t, r.Start(nil))
+}
+
+func TestSyncCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	r := NewRunner([]Config{{
+		Name:    "record",
+		Command: []string{"sh", "-c", `echo "$DOLT_DATABASE $DOLT_BRANCH $DOLT_COMMIT $DOLT_MESSAGE" > ` + out},
+		Mode:    Sync,
+	}})
+
+	r.Run(context.Background(), Commit{Database: "mydb", Branch: "main", Hash: "abc", Message: "msg"})
+	contents, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "mydb main abc msg\n", string(contents))
+}
+
+func TestFailurePolicies(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+	three := 3
+	r := NewRunner([]Config{
+		{Name: "retried", Command: []string{"false"}, Mode: Sync, OnFailure: FailureRetry, MaxAttempts: &three},
+		{Name: "logged", Command: []string{"false"}, Mode: Sync},
+		{Name: "ignored", Command: []string{"false"}, Mode: Sync, OnFailure: FailureIgnore},
+	})
+	var backoffs int
+	r.backoff = func(int) time.Duration {
+		backoffs++
+		return 0
+	}
+	var failed []string
+	r.SetLogger(func(hook string, c Commit, err error) {
+		assert.Error(t, err)
+		failed = append(failed, hook)
+	})
+
+	r.Run(context.Background(), Commit{Database: "mydb", Branch: "main"})
+	assert.Equal(t, 2, backoffs)
+	assert.Equal(t, []string{"retried", "logged"}, failed)
+}
+
+func TestAsyncGrpc(t *testing.T) {
+	received := make(chan *structpb.Struct, 1)
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+		method, _ := grpc.MethodFromServerStream(stream)
+		assert.Equal(t, DefaultGrpcMethod, method)
+		req := &structpb.Struct{}
+		if err := stream.RecvMsg(req); err != nil {
+			return err
+		}
+		received <- req
+		return stream.SendMsg(&emptypb.Empty{})
+	}))
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	r := NewRunner([]Config{{Name: "index", GrpcEndpoint: lis.Addr().String(), Databases: []string{"mydb"}}})
+	bThreads := sql.NewBackgroundThreads()
+	defer bThreads.Shutdown()
+	require.NoError(t, r.Start(bThreads))
+
+	r.Run(context.Background(), Commit{Database: "otherdb", Branch: "main", Hash: "def"})
+	r.Run(context.Background(), Commit{Database: "mydb", Branch: "main", Hash: "abc", Author: "me <me@example.com>"})
+
+	select {
+	case req := <-received:
+		assert.Equal(t, map[string]interface{}{
+			"database": "mydb",
+			"branch":   "main",
+			"commit":   "abc",
+			"author":   "me <me@example.com>",
+			"message":  "",
+		}, req.AsMap())
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the commit hook")
+	}
+}
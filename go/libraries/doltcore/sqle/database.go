@@ -111,6 +111,9 @@ func (db Database) WithBranchRevision(requestedName string, branchSpec dsess.Ses
 	db.rsr, db.rsw = branchSpec.RepoState, branchSpec.RepoState
 	db.revision = branchSpec.Branch
 	db.revType = dsess.RevisionTypeBranch
+	if branchSpec.Workspace {
+		db.revType = dsess.RevisionTypeWorkspace
+	}
 	db.requestedName = requestedName
 
 	return db, nil
@@ -410,6 +413,8 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 		dt, found = dtables.NewTableOfTablesInConflict(ctx, db.RevisionQualifiedName(), db.ddb), true
 	case doltdb.TableOfTablesWithViolationsName:
 		dt, found = dtables.NewTableOfTablesConstraintViolations(ctx, root), true
+	case doltdb.ConstraintViolationSourcesTableName:
+		dt, found = dtables.NewConstraintViolationSourcesTable(ctx, db.RevisionQualifiedName(), db.ddb, root), true
 	case doltdb.SchemaConflictsTableName:
 		dt, found = dtables.NewSchemaConflictsTable(ctx, db.RevisionQualifiedName(), db.ddb), true
 	case doltdb.BranchesTableName:
@@ -438,6 +443,24 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 		dt, found = dtables.NewMergeStatusTable(db.RevisionQualifiedName()), true
 	case doltdb.TagsTableName:
 		dt, found = dtables.NewTagsTable(ctx, db.ddb), true
+	case doltdb.ProposalsTableName:
+		dt, found = dtables.NewProposalsTable(ctx, db.ddb), true
+	case doltdb.ProposalCommentsTableName:
+		dt, found = dtables.NewProposalCommentsTable(ctx, db.ddb), true
+	case doltdb.WebhookDeliveriesTableName:
+		dt, found = dtables.NewWebhookDeliveriesTable(db.Name()), true
+	case doltdb.FormatUpgradesTableName:
+		dt, found = dtables.NewFormatUpgradesTable(), true
+	case doltdb.ReplicationStatusTableName:
+		dt, found = dtables.NewReplicationStatusTable(), true
+	case doltdb.ReplicationQueueTableName:
+		dt, found = dtables.NewReplicationQueueTable(db.ddb), true
+	case doltdb.SessionCacheStatusTableName:
+		dt, found = dtables.NewSessionCacheStatusTable(), true
+	case doltdb.PatchRejectsTableName:
+		dt, found = dtables.NewPatchRejectsTable(db.Name()), true
+	case doltdb.ColumnMasksTableName:
+		dt, found = dtables.NewColumnMasksTable(ctx, db.ddb, root), true
 	case dtables.AccessTableName:
 		basCtx := branch_control.GetBranchAwareSession(ctx)
 		if basCtx != nil {
@@ -458,6 +481,12 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 			return nil, false, err
 		}
 		dt, found = dtables.NewIgnoreTable(ctx, db.ddb, backingTable), true
+	case doltdb.MergeStrategiesTableName:
+		backingTable, _, err := db.getTable(ctx, root, doltdb.MergeStrategiesTableName)
+		if err != nil {
+			return nil, false, err
+		}
+		dt, found = dtables.NewMergeStrategiesTable(ctx, backingTable), true
 	}
 
 	if found {
@@ -1670,3 +1699,11 @@ func (n noopRepoStateWriter) TempTableFilesDir() (string, error) {
 func (n noopRepoStateWriter) UpdateBranch(name string, new env.BranchConfig) error {
 	return nil
 }
+
+func (n noopRepoStateWriter) RemoveBranch(name string) error {
+	return nil
+}
+
+func (n noopRepoStateWriter) SetBranchExpiration(name string, expiration env.BranchExpiration) error {
+	return nil
+}
@@ -21,8 +21,12 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
@@ -32,9 +36,14 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/clusterdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/commithooks"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/formatupgrade"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/replicationstatus"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/serverhooks"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/webhooks"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqlserver"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
@@ -42,22 +51,205 @@ import (
 	"github.com/dolthub/dolt/go/store/types"
 )
 
+// DoltDatabaseProvider is the sql.DatabaseProvider for dolt databases. It's always used by pointer: its configuration
+// is fixed when it's created from a DoltDatabaseProviderConfig, and the rest of its state is safe for concurrent use.
 type DoltDatabaseProvider struct {
-	// dbLocations maps a database name to its file system root
-	dbLocations        map[string]filesys.Filesys
-	databases          map[string]dsess.SqlDatabase
+	// databases is a copy-on-write snapshot of the provider's databases, which lookups read without locking
+	databases          atomic.Pointer[databaseSet]
 	functions          map[string]sql.Function
 	externalProcedures sql.ExternalStoredProcedureRegistry
-	InitDatabaseHook   InitDatabaseHook
-	DropDatabaseHook   DropDatabaseHook
-	mu                 *sync.RWMutex
+	// hooks are run when databases are created and dropped
+	hooks *databaseHooks
+	// mu serializes the replacement of |databases|. It's only held while a new snapshot is published.
+	mu sync.Mutex
+	// dbLocks serializes the operations that create, drop or swap each database
+	dbLocks *databaseLocks
+	// lazy holds the databases that haven't been opened yet, which are opened the first time they're looked up
+	lazy *lazyDatabases
+	// dbNames records the directories of the databases whose names can't be used as directory names
+	dbNames *env.DBNames
 
 	defaultBranch string
 	fs            filesys.Filesys
 	remoteDialer  dbfactory.GRPCDialProvider // TODO: why isn't this a method defined on the remote object
 
 	dbFactoryUrl string
-	isStandby    *bool
+	// storageFormat is the storage format of new databases, unless their session names another. Nil means the
+	// default format.
+	storageFormat *types.NomsBinFormat
+	// standby records whether the server, and each of its databases, is a standby
+	standby     *standbyState
+	serverHooks *serverhooks.Config
+	webhooks    *webhooks.Dispatcher
+	upgrades    *formatupgrade.Tracker
+	// replication records the read replica's attempts to clone databases
+	replication *replicationstatus.Tracker
+	// statementRunner is set after the provider is created, since the engine is built after the provider is
+	statementRunner dsess.StatementRunner
+}
+
+// DoltDatabaseProviderConfig is the configuration of a DoltDatabaseProvider. It's immutable: each With method returns
+// a modified copy, so that a config can be shared and extended without affecting providers created from it.
+type DoltDatabaseProviderConfig struct {
+	defaultBranch string
+	fs            filesys.Filesys
+	dbFactoryUrl  string
+	remoteDialer  dbfactory.GRPCDialProvider
+	storageFormat *types.NomsBinFormat
+	serverHooks   *serverhooks.Config
+	webhooks      *webhooks.Dispatcher
+	upgrades      *formatupgrade.Tracker
+	// functions replace the dolt functions when non-nil
+	functions   []sql.Function
+	lazyLoaders map[string]LazyDatabaseLoader
+}
+
+// NewDoltDatabaseProviderConfig returns the config of a provider whose databases are stored in |fs| and which creates
+// new databases on |defaultBranch|.
+func NewDoltDatabaseProviderConfig(defaultBranch string, fs filesys.Filesys) DoltDatabaseProviderConfig {
+	// If the specified |fs| is an in mem file system, default to using the InMemDoltDB dbFactoryUrl so that all
+	// databases are created with the same file system type.
+	dbFactoryUrl := doltdb.LocalDirDoltDB
+	if _, ok := fs.(*filesys.InMemFS); ok {
+		dbFactoryUrl = doltdb.InMemDoltDB
+	}
+
+	return DoltDatabaseProviderConfig{
+		defaultBranch: defaultBranch,
+		fs:            fs,
+		dbFactoryUrl:  dbFactoryUrl,
+	}
+}
+
+// WithFunctions returns a copy of this config with the functions given in place of the dolt functions.
+func (c DoltDatabaseProviderConfig) WithFunctions(fns []sql.Function) DoltDatabaseProviderConfig {
+	c.functions = append([]sql.Function{}, fns...)
+	return c
+}
+
+// WithDbFactoryUrl returns a copy of this config with the DbFactoryUrl set as provided.
+// The URL is used when creating new databases.
+// See doltdb.InMemDoltDB, doltdb.LocalDirDoltDB
+func (c DoltDatabaseProviderConfig) WithDbFactoryUrl(url string) DoltDatabaseProviderConfig {
+	c.dbFactoryUrl = url
+	return c
+}
+
+// WithRemoteDialer returns a copy of this config with the dialer provided
+func (c DoltDatabaseProviderConfig) WithRemoteDialer(provider dbfactory.GRPCDialProvider) DoltDatabaseProviderConfig {
+	c.remoteDialer = provider
+	return c
+}
+
+// WithStorageFormat returns a copy of this config that creates databases in |nbf|, rather than the default storage
+// format, unless the dolt_create_database_storage_format session variable names another.
+func (c DoltDatabaseProviderConfig) WithStorageFormat(nbf *types.NomsBinFormat) DoltDatabaseProviderConfig {
+	c.storageFormat = nbf
+	return c
+}
+
+// WithServerHooks returns a copy of this config with the server hooks provided
+func (c DoltDatabaseProviderConfig) WithServerHooks(hooks *serverhooks.Config) DoltDatabaseProviderConfig {
+	c.serverHooks = hooks
+	return c
+}
+
+// WithWebhooks returns a copy of this config with the webhook dispatcher provided
+func (c DoltDatabaseProviderConfig) WithWebhooks(dispatcher *webhooks.Dispatcher) DoltDatabaseProviderConfig {
+	c.webhooks = dispatcher
+	return c
+}
+
+// WithFormatUpgrades returns a copy of this config with the format upgrade tracker provided
+func (c DoltDatabaseProviderConfig) WithFormatUpgrades(tracker *formatupgrade.Tracker) DoltDatabaseProviderConfig {
+	c.upgrades = tracker
+	return c
+}
+
+// WithLazyDatabases returns a copy of this config whose provider opens the databases named by |loaders| the first
+// time each is looked up, instead of when the provider is created.
+func (c DoltDatabaseProviderConfig) WithLazyDatabases(loaders map[string]LazyDatabaseLoader) DoltDatabaseProviderConfig {
+	lazyLoaders := make(map[string]LazyDatabaseLoader, len(loaders))
+	for name, load := range loaders {
+		lazyLoaders[name] = load
+	}
+	c.lazyLoaders = lazyLoaders
+	return c
+}
+
+// databaseSet is a snapshot of the databases of a provider. It's never modified once it's published: adding or
+// removing a database publishes a modified copy instead.
+type databaseSet struct {
+	databases map[string]dsess.SqlDatabase
+	// dbLocations maps a database name to its file system root
+	dbLocations map[string]filesys.Filesys
+}
+
+// databaseLocks holds a lock for each database name that has been created, cloned, dropped or swapped. Operations on
+// a database hold its lock for their whole duration, so that they don't block lookups or operations on other
+// databases, and lookups of the database wait for the operation in progress to finish.
+type databaseLocks struct {
+	locks sync.Map
+}
+
+// lock locks the database named for an operation that changes it, and returns the function that unlocks it.
+func (l *databaseLocks) lock(name string) func() {
+	mu, _ := l.locks.LoadOrStore(formatDbMapKeyName(name), &sync.RWMutex{})
+	mu.(*sync.RWMutex).Lock()
+	return mu.(*sync.RWMutex).Unlock
+}
+
+// rlock waits for any operation on the database named to finish and locks it for a lookup. It returns the function
+// that unlocks it. Names that have never been locked for an operation aren't added, so that lookups of databases that
+// don't exist don't grow the set of locks.
+func (l *databaseLocks) rlock(name string) func() {
+	mu, ok := l.locks.Load(formatDbMapKeyName(name))
+	if !ok {
+		return func() {}
+	}
+	mu.(*sync.RWMutex).RLock()
+	return mu.(*sync.RWMutex).RUnlock
+}
+
+// LazyDatabaseLoader opens a database that a provider knows about but hasn't opened yet. It returns the database
+// along with its location.
+type LazyDatabaseLoader func(ctx *sql.Context) (dsess.SqlDatabase, filesys.Filesys, error)
+
+// lazyDatabases holds the loaders of the databases that a provider hasn't opened yet, keyed by database name.
+type lazyDatabases struct {
+	mu      sync.Mutex
+	loaders map[string]LazyDatabaseLoader
+}
+
+func (l *lazyDatabases) get(name string) (LazyDatabaseLoader, bool) {
+	if l == nil {
+		return nil, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	load, ok := l.loaders[formatDbMapKeyName(name)]
+	return load, ok
+}
+
+func (l *lazyDatabases) remove(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.loaders, formatDbMapKeyName(name))
+}
+
+// names returns the names of the databases that haven't been opened yet, in order.
+func (l *lazyDatabases) names() []string {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	names := make([]string, 0, len(l.loaders))
+	for name := range l.loaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 var _ sql.DatabaseProvider = (*DoltDatabaseProvider)(nil)
@@ -67,16 +259,21 @@ var _ sql.CollatedDatabaseProvider = (*DoltDatabaseProvider)(nil)
 var _ sql.ExternalStoredProcedureProvider = (*DoltDatabaseProvider)(nil)
 var _ sql.TableFunctionProvider = (*DoltDatabaseProvider)(nil)
 var _ dsess.DoltDatabaseProvider = (*DoltDatabaseProvider)(nil)
+var _ dsess.StatementRunnerProvider = (*DoltDatabaseProvider)(nil)
+var _ dsess.ServerHooksProvider = (*DoltDatabaseProvider)(nil)
+var _ dsess.WebhooksProvider = (*DoltDatabaseProvider)(nil)
+var _ dsess.FormatUpgradesProvider = (*DoltDatabaseProvider)(nil)
+var _ dsess.ReplicationStatusProvider = (*DoltDatabaseProvider)(nil)
 
 // NewDoltDatabaseProvider returns a new provider, initialized without any databases, along with any
 // errors that occurred while trying to create the database provider.
-func NewDoltDatabaseProvider(defaultBranch string, fs filesys.Filesys) (DoltDatabaseProvider, error) {
+func NewDoltDatabaseProvider(defaultBranch string, fs filesys.Filesys) (*DoltDatabaseProvider, error) {
 	return NewDoltDatabaseProviderWithDatabases(defaultBranch, fs, nil, nil)
 }
 
 // NewDoltDatabaseProviderWithDatabase returns a new provider, initialized with one database at the
 // specified location, and any error that occurred along the way.
-func NewDoltDatabaseProviderWithDatabase(defaultBranch string, fs filesys.Filesys, database dsess.SqlDatabase, dbLocation filesys.Filesys) (DoltDatabaseProvider, error) {
+func NewDoltDatabaseProviderWithDatabase(defaultBranch string, fs filesys.Filesys, database dsess.SqlDatabase, dbLocation filesys.Filesys) (*DoltDatabaseProvider, error) {
 	return NewDoltDatabaseProviderWithDatabases(defaultBranch, fs, []dsess.SqlDatabase{database}, []filesys.Filesys{dbLocation})
 }
 
@@ -84,9 +281,16 @@ func NewDoltDatabaseProviderWithDatabase(defaultBranch string, fs filesys.Filesy
 // at the specified locations. For every database specified, there must be a corresponding filesystem
 // specified that represents where the database is located. If the number of specified databases is not the
 // same as the number of specified locations, an error is returned.
-func NewDoltDatabaseProviderWithDatabases(defaultBranch string, fs filesys.Filesys, databases []dsess.SqlDatabase, locations []filesys.Filesys) (DoltDatabaseProvider, error) {
+func NewDoltDatabaseProviderWithDatabases(defaultBranch string, fs filesys.Filesys, databases []dsess.SqlDatabase, locations []filesys.Filesys) (*DoltDatabaseProvider, error) {
+	return NewDoltDatabaseProviderFromConfig(NewDoltDatabaseProviderConfig(defaultBranch, fs), databases, locations)
+}
+
+// NewDoltDatabaseProviderFromConfig returns a new provider configured by |cfg|, initialized with the specified
+// databases at the specified locations. For every database specified, there must be a corresponding filesystem
+// specified that represents where the database is located.
+func NewDoltDatabaseProviderFromConfig(cfg DoltDatabaseProviderConfig, databases []dsess.SqlDatabase, locations []filesys.Filesys) (*DoltDatabaseProvider, error) {
 	if len(databases) != len(locations) {
-		return DoltDatabaseProvider{}, fmt.Errorf("unable to create DoltDatabaseProvider: "+
+		return nil, fmt.Errorf("unable to create DoltDatabaseProvider: "+
 			"incorrect number of databases (%d) and database locations (%d) specified", len(databases), len(locations))
 	}
 
@@ -100,8 +304,12 @@ func NewDoltDatabaseProviderWithDatabases(defaultBranch string, fs filesys.Files
 		dbLocations[strings.ToLower(databases[i].Name())] = dbLocation
 	}
 
-	funcs := make(map[string]sql.Function, len(dfunctions.DoltFunctions))
-	for _, fn := range dfunctions.DoltFunctions {
+	fns := dfunctions.DoltFunctions
+	if cfg.functions != nil {
+		fns = cfg.functions
+	}
+	funcs := make(map[string]sql.Function, len(fns))
+	for _, fn := range fns {
 		funcs[strings.ToLower(fn.FunctionName())] = fn
 	}
 
@@ -110,74 +318,208 @@ func NewDoltDatabaseProviderWithDatabases(defaultBranch string, fs filesys.Files
 		externalProcedures.Register(esp)
 	}
 
-	// If the specified |fs| is an in mem file system, default to using the InMemDoltDB dbFactoryUrl so that all
-	// databases are created with the same file system type.
-	dbFactoryUrl := doltdb.LocalDirDoltDB
-	if _, ok := fs.(*filesys.InMemFS); ok {
-		dbFactoryUrl = doltdb.InMemDoltDB
+	var dbNames *env.DBNames
+	if cfg.fs != nil {
+		var err error
+		dbNames, err = env.LoadDBNames(cfg.fs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var lazy *lazyDatabases
+	if cfg.lazyLoaders != nil {
+		lazy = &lazyDatabases{loaders: make(map[string]LazyDatabaseLoader, len(cfg.lazyLoaders))}
+		for name, load := range cfg.lazyLoaders {
+			lazy.loaders[formatDbMapKeyName(name)] = load
+		}
 	}
 
-	return DoltDatabaseProvider{
-		dbLocations:        dbLocations,
-		databases:          dbs,
+	p := &DoltDatabaseProvider{
 		functions:          funcs,
 		externalProcedures: externalProcedures,
-		mu:                 &sync.RWMutex{},
-		fs:                 fs,
-		defaultBranch:      defaultBranch,
-		dbFactoryUrl:       dbFactoryUrl,
-		InitDatabaseHook:   ConfigureReplicationDatabaseHook,
-		isStandby:          new(bool),
-	}, nil
+		dbLocks:            &databaseLocks{},
+		lazy:               lazy,
+		dbNames:            dbNames,
+		fs:                 cfg.fs,
+		defaultBranch:      cfg.defaultBranch,
+		remoteDialer:       cfg.remoteDialer,
+		dbFactoryUrl:       cfg.dbFactoryUrl,
+		storageFormat:      cfg.storageFormat,
+		serverHooks:        cfg.serverHooks,
+		webhooks:           cfg.webhooks,
+		upgrades:           cfg.upgrades,
+		hooks:              &databaseHooks{},
+		standby:            newStandbyState(),
+		replication:        replicationstatus.NewTracker(),
+	}
+	p.databases.Store(&databaseSet{databases: dbs, dbLocations: dbLocations})
+	return p, nil
 }
 
-// WithFunctions returns a copy of this provider with the functions given. Any previous functions are removed.
-func (p DoltDatabaseProvider) WithFunctions(fns []sql.Function) DoltDatabaseProvider {
-	funcs := make(map[string]sql.Function, len(dfunctions.DoltFunctions))
-	for _, fn := range fns {
-		funcs[strings.ToLower(fn.FunctionName())] = fn
+// updateDatabases publishes a copy of the provider's databases and their locations, after |update| modifies it.
+func (p *DoltDatabaseProvider) updateDatabases(update func(databases map[string]dsess.SqlDatabase, dbLocations map[string]filesys.Filesys)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	curr := p.databases.Load()
+	next := &databaseSet{
+		databases:   make(map[string]dsess.SqlDatabase, len(curr.databases)+1),
+		dbLocations: make(map[string]filesys.Filesys, len(curr.dbLocations)+1),
+	}
+	for name, db := range curr.databases {
+		next.databases[name] = db
+	}
+	for name, loc := range curr.dbLocations {
+		next.dbLocations[name] = loc
 	}
 
-	p.functions = funcs
-	return p
+	update(next.databases, next.dbLocations)
+	p.databases.Store(next)
 }
 
-// WithDbFactoryUrl returns a copy of this provider with the DbFactoryUrl set as provided.
-// The URL is used when creating new databases.
-// See doltdb.InMemDoltDB, doltdb.LocalDirDoltDB
-func (p DoltDatabaseProvider) WithDbFactoryUrl(url string) DoltDatabaseProvider {
-	p.dbFactoryUrl = url
-	return p
+// lookupDatabase returns the database named, waiting for any operation that changes it to finish. A database that
+// hasn't been opened yet is opened.
+func (p *DoltDatabaseProvider) lookupDatabase(ctx *sql.Context, name string) (dsess.SqlDatabase, bool, error) {
+	unlock := p.dbLocks.rlock(name)
+	db, ok := p.databases.Load().databases[formatDbMapKeyName(name)]
+	unlock()
+	if ok {
+		return db, true, nil
+	}
+	if _, ok := p.lazy.get(name); !ok {
+		return nil, false, nil
+	}
+	return p.loadLazyDatabase(ctx, name)
+}
+
+// loadLazyDatabase opens the database named, which the provider hasn't opened yet, and adds it to the provider's
+// databases. If it can't be opened, it's left to be opened again the next time it's looked up.
+func (p *DoltDatabaseProvider) loadLazyDatabase(ctx *sql.Context, name string) (dsess.SqlDatabase, bool, error) {
+	unlock := p.dbLocks.lock(name)
+	defer unlock()
+
+	dbKey := formatDbMapKeyName(name)
+	// another session may have opened it while we waited for the lock
+	if db, ok := p.databases.Load().databases[dbKey]; ok {
+		return db, true, nil
+	}
+	load, ok := p.lazy.get(dbKey)
+	if !ok {
+		return nil, false, nil
+	}
+
+	db, dbLoc, err := load(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("error loading database %s: %w", name, err)
+	}
+	p.updateDatabases(func(databases map[string]dsess.SqlDatabase, dbLocations map[string]filesys.Filesys) {
+		databases[dbKey] = db
+		dbLocations[dbKey] = dbLoc
+	})
+	p.lazy.remove(dbKey)
+
+	return db, true, nil
+}
+
+// LoadLazyDatabases opens every database that hasn't been opened yet, one at a time, so that sessions don't wait for
+// them to be opened when they're first used. Databases that can't be opened are logged and skipped. It returns early
+// if |ctx| is canceled.
+func (p *DoltDatabaseProvider) LoadLazyDatabases(ctx *sql.Context) error {
+	for _, name := range p.lazy.names() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, _, err := p.lookupDatabase(ctx, name); err != nil {
+			ctx.GetLogger().Warnf("error loading database %s: %s", name, err.Error())
+		}
+	}
+	return nil
+}
+
+// ServerHooks implements dsess.ServerHooksProvider
+func (p *DoltDatabaseProvider) ServerHooks() *serverhooks.Config {
+	return p.serverHooks
+}
+
+// Webhooks implements dsess.WebhooksProvider
+func (p *DoltDatabaseProvider) Webhooks() *webhooks.Dispatcher {
+	return p.webhooks
 }
 
-// WithRemoteDialer returns a copy of this provider with the dialer provided
-func (p DoltDatabaseProvider) WithRemoteDialer(provider dbfactory.GRPCDialProvider) DoltDatabaseProvider {
-	p.remoteDialer = provider
-	return p
+// FormatUpgrades implements dsess.FormatUpgradesProvider
+func (p *DoltDatabaseProvider) FormatUpgrades() *formatupgrade.Tracker {
+	return p.upgrades
 }
 
-func (p DoltDatabaseProvider) FileSystem() filesys.Filesys {
+// ReplicationStatus implements dsess.ReplicationStatusProvider
+func (p *DoltDatabaseProvider) ReplicationStatus() *replicationstatus.Tracker {
+	return p.replication
+}
+
+// SetStatementRunner sets the engine that runs queries for this provider's sessions. It must be called before the
+// provider serves any queries.
+func (p *DoltDatabaseProvider) SetStatementRunner(runner dsess.StatementRunner) {
+	p.statementRunner = runner
+}
+
+// StatementRunner implements dsess.StatementRunnerProvider
+func (p *DoltDatabaseProvider) StatementRunner() dsess.StatementRunner {
+	return p.statementRunner
+}
+
+func (p *DoltDatabaseProvider) FileSystem() filesys.Filesys {
 	return p.fs
 }
 
-// SetIsStandby sets whether this provider is set to standby |true|. Standbys return every dolt database as a read only
-// database. Set back to |false| to get read-write behavior from dolt databases again.
-func (p DoltDatabaseProvider) SetIsStandby(standby bool) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-	*p.isStandby = standby
+// SetIsStandby sets whether the database named is a standby |true|. Standby databases are returned as read only
+// databases. Set back to |false| to get read-write behavior from the database again. An empty |dbName| sets whether
+// the whole server is a standby, in which case every dolt database is returned as read only, whatever it was set to.
+func (p *DoltDatabaseProvider) SetIsStandby(dbName string, standby bool) {
+	p.standby.set(dbName, standby)
+}
+
+// IsStandby returns whether the database named is read only because it, or the whole server, is a standby.
+func (p *DoltDatabaseProvider) IsStandby(dbName string) bool {
+	return p.standby.get(dbName)
+}
+
+// standbyState records whether a server is a standby, and which of its databases are standbys while it isn't.
+type standbyState struct {
+	mu     sync.RWMutex
+	server bool
+	dbs    map[string]bool
+}
+
+func newStandbyState() *standbyState {
+	return &standbyState{dbs: make(map[string]bool)}
+}
+
+func (s *standbyState) set(dbName string, standby bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if dbName == "" {
+		s.server = standby
+	} else if standby {
+		s.dbs[formatDbMapKeyName(dbName)] = true
+	} else {
+		delete(s.dbs, formatDbMapKeyName(dbName))
+	}
+}
+
+func (s *standbyState) get(dbName string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.server || s.dbs[formatDbMapKeyName(dbName)]
 }
 
 // FileSystemForDatabase returns a filesystem, with the working directory set to the root directory
 // of the requested database. If the requested database isn't found, a database not found error
 // is returned.
-func (p DoltDatabaseProvider) FileSystemForDatabase(dbname string) (filesys.Filesys, error) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
+func (p *DoltDatabaseProvider) FileSystemForDatabase(dbname string) (filesys.Filesys, error) {
 	baseName, _ := dsess.SplitRevisionDbName(dbname)
 
-	dbLocation, ok := p.dbLocations[strings.ToLower(baseName)]
+	dbLocation, ok := p.databases.Load().dbLocations[strings.ToLower(baseName)]
 	if !ok {
 		return nil, sql.ErrDatabaseNotFound.New(dbname)
 	}
@@ -186,7 +528,7 @@ func (p DoltDatabaseProvider) FileSystemForDatabase(dbname string) (filesys.File
 }
 
 // Database implements the sql.DatabaseProvider interface
-func (p DoltDatabaseProvider) Database(ctx *sql.Context, name string) (sql.Database, error) {
+func (p *DoltDatabaseProvider) Database(ctx *sql.Context, name string) (sql.Database, error) {
 	database, b, err := p.SessionDatabase(ctx, name)
 	if err != nil {
 		return nil, err
@@ -213,10 +555,17 @@ func wrapForStandby(db dsess.SqlDatabase, standby bool) dsess.SqlDatabase {
 	return db
 }
 
-// attemptCloneReplica attempts to clone a database from the configured replication remote URL template, returning an error
-// if it cannot be found
-// TODO: distinct error for not found v. others
-func (p DoltDatabaseProvider) attemptCloneReplica(ctx *sql.Context, dbName string) error {
+// ErrRemoteDbNotFound is returned when a read replica tries to clone a database that its replication remote doesn't have.
+var ErrRemoteDbNotFound = errors.New("database not found on replication remote")
+
+// ErrRemoteUnavailable is returned when a read replica can't clone a database from its replication remote for any
+// reason other than the database not existing there, such as the remote being unreachable.
+var ErrRemoteUnavailable = errors.New("unable to clone database from replication remote")
+
+// attemptCloneReplica attempts to clone a database from the configured replication remote URL template. It returns
+// an error wrapping ErrRemoteDbNotFound if the remote doesn't have the database, or ErrRemoteUnavailable if it can't be
+// cloned for any other reason. Every attempt is recorded in p.replication.
+func (p *DoltDatabaseProvider) attemptCloneReplica(ctx *sql.Context, dbName string) error {
 	// TODO: these need some reworking, they don't make total sense together
 	_, readReplicaRemoteName, _ := sql.SystemVariables.GetGlobal(dsess.ReadReplicaRemote)
 	if readReplicaRemoteName == "" {
@@ -237,22 +586,48 @@ func (p DoltDatabaseProvider) attemptCloneReplica(ctx *sql.Context, dbName strin
 		return nil
 	}
 
-	// TODO: url sanitize
-	// TODO: SQL identifiers aren't case sensitive, but URLs are, need a plan for this
-	remoteUrl := strings.Replace(urlTemplate, dsess.URLTemplateDatabasePlaceholder, dbName, -1)
+	remoteUrl, err := dsess.ReplicationRemoteURL(urlTemplate, dbName, p.defaultBranch)
+	if err != nil {
+		return err
+	}
 
-	// TODO: remote params for AWS, others
-	// TODO: this needs to be robust in the face of the DB not having the default branch
-	// TODO: this treats every database not found error as a clone error, need to tighten
-	err := p.CloneDatabaseFromRemote(ctx, dbName, p.defaultBranch, remoteName, remoteUrl, nil)
+	remoteParams, err := dsess.RemoteParamsForReplication()
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// TODO: this needs to be robust in the face of the DB not having the default branch
+	err = p.CloneDatabaseFromRemote(ctx, dbName, p.defaultBranch, remoteName, remoteUrl, 0, remoteParams)
+	attempt := replicationstatus.Attempt{Database: dbName, RemoteURL: remoteUrl, Status: replicationstatus.Cloned}
+	if err != nil {
+		if remoteDbNotFound(err) {
+			err = fmt.Errorf("%w: %s", ErrRemoteDbNotFound, err.Error())
+			attempt.Status = replicationstatus.NotFound
+		} else {
+			err = fmt.Errorf("%w: %s", ErrRemoteUnavailable, err.Error())
+			attempt.Status = replicationstatus.Failed
+		}
+		attempt.Error = err.Error()
+	}
+	p.replication.Record(attempt)
+	return err
 }
 
-func (p DoltDatabaseProvider) HasDatabase(ctx *sql.Context, name string) bool {
+// remoteDbNotFound returns whether |err|, returned from cloning a database, means that the remote doesn't have it.
+// Remotes backed by storage that doesn't exist yet are empty, while remotesapi servers report that it's not found.
+func remoteDbNotFound(err error) bool {
+	if errors.Is(err, actions.ErrNoDataAtRemote) {
+		return true
+	}
+	for ; err != nil; err = errors.Unwrap(err) {
+		if s, ok := status.FromError(err); ok && s.Code() == codes.NotFound {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *DoltDatabaseProvider) HasDatabase(ctx *sql.Context, name string) bool {
 	_, err := p.Database(ctx, name)
 	if err != nil && !sql.ErrDatabaseNotFound.Is(err) {
 		ctx.GetLogger().Warnf("Error getting database %s: %s", name, err.Error())
@@ -260,36 +635,76 @@ func (p DoltDatabaseProvider) HasDatabase(ctx *sql.Context, name string) bool {
 	return err == nil
 }
 
-func (p DoltDatabaseProvider) AllDatabases(ctx *sql.Context) (all []sql.Database) {
+// AllDatabases implements the sql.DatabaseProvider interface. Since that interface can't return errors, when a
+// revision database can't be loaded the error is recorded in the session, which fails the statement once it finishes
+// (see NewExecBuilder), and the databases that can be loaded are returned. If @@dolt_lenient_database_listing is set,
+// the error is only logged. See AllDatabasesWithErrors.
+func (p *DoltDatabaseProvider) AllDatabases(ctx *sql.Context) []sql.Database {
+	lenient, err := dsess.GetBooleanSystemVar(ctx, dsess.LenientDatabaseListing)
+	if err != nil || lenient {
+		all, _ := p.allDatabases(ctx, true)
+		return all
+	}
+	all, err := p.allDatabases(ctx, false)
+	if err != nil {
+		if sess, ok := ctx.Session.(*dsess.DoltSession); ok {
+			sess.SetListDatabasesErr(err)
+		}
+		all, _ = p.allDatabases(ctx, true)
+	}
+	return all
+}
+
+// AllDatabasesWithErrors implements the dsess.DoltDatabaseProvider interface
+func (p *DoltDatabaseProvider) AllDatabasesWithErrors(ctx *sql.Context) ([]sql.Database, error) {
+	lenient, err := dsess.GetBooleanSystemVar(ctx, dsess.LenientDatabaseListing)
+	if err != nil {
+		return nil, err
+	}
+	return p.allDatabases(ctx, lenient)
+}
+
+// allDatabases returns every database, including revision databases when @@dolt_show_branch_databases is set and
+// the session's current revision database. If |lenient| is true, revision databases that can't be loaded are logged
+// and left out, rather than returned as an error.
+func (p *DoltDatabaseProvider) allDatabases(ctx *sql.Context, lenient bool) (all []sql.Database, err error) {
 	currentDb := ctx.GetCurrentDatabase()
 	_, currRev := dsess.SplitRevisionDbName(currentDb)
 
-	p.mu.RLock()
 	showBranches, _ := dsess.GetBooleanSystemVar(ctx, dsess.ShowBranchDatabases)
 
-	all = make([]sql.Database, 0, len(p.databases))
-	for _, db := range p.databases {
+	// every database is listed, so any that haven't been opened yet have to be
+	_ = p.LoadLazyDatabases(ctx)
+
+	databases := p.databases.Load().databases
+	all = make([]sql.Database, 0, len(databases))
+	for _, db := range databases {
 		all = append(all, db)
 
 		if showBranches && db.Name() != clusterdb.DoltClusterDbName {
 			revisionDbs, err := p.allRevisionDbs(ctx, db)
-			if err != nil {
-				// TODO: this interface is wrong, needs to return errors
+			if err != nil && !lenient {
+				return nil, err
+			} else if err != nil {
 				ctx.GetLogger().Warnf("error fetching revision databases: %s", err.Error())
 				continue
 			}
 			all = append(all, revisionDbs...)
 		}
 	}
-	p.mu.RUnlock()
 
 	// If there's a revision database in use, include it in the list (but don't double-count)
 	if currRev != "" && !showBranches {
+		// the current database may have been dropped by another session, in which case it's left out
 		rdb, ok, err := p.databaseForRevision(ctx, currentDb, currentDb)
-		if err != nil || !ok {
-			// TODO: this interface is wrong, needs to return errors
+		if sql.ErrDatabaseNotFound.Is(err) {
+			ok, err = false, nil
+		}
+		if err != nil && !lenient {
+			return nil, err
+		} else if err != nil {
 			ctx.GetLogger().Warnf("error fetching revision databases: %s", err.Error())
-		} else {
+		} else if ok {
 			all = append(all, rdb)
 		}
 	}
@@ -299,17 +714,16 @@ func (p DoltDatabaseProvider) AllDatabases(ctx *sql.Context) (all []sql.Database
 		return strings.ToLower(all[i].Name()) < strings.ToLower(all[j].Name())
 	})
 
-	return all
+	return all, nil
 }
 
-// DoltDatabases implements the dsess.DoltDatabaseProvider interface
-func (p DoltDatabaseProvider) DoltDatabases() []dsess.SqlDatabase {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-
-	dbs := make([]dsess.SqlDatabase, len(p.databases))
+// DoltDatabases implements the dsess.DoltDatabaseProvider interface. Databases that haven't been opened yet aren't
+// returned.
+func (p *DoltDatabaseProvider) DoltDatabases() []dsess.SqlDatabase {
+	databases := p.databases.Load().databases
+	dbs := make([]dsess.SqlDatabase, len(databases))
 	i := 0
-	for _, db := range p.databases {
+	for _, db := range databases {
 		dbs[i] = db
 		i++
 	}
@@ -322,7 +736,7 @@ func (p DoltDatabaseProvider) DoltDatabases() []dsess.SqlDatabase {
 }
 
 // allRevisionDbs returns all revision dbs for the database given
-func (p DoltDatabaseProvider) allRevisionDbs(ctx *sql.Context, db dsess.SqlDatabase) ([]sql.Database, error) {
+func (p *DoltDatabaseProvider) allRevisionDbs(ctx *sql.Context, db dsess.SqlDatabase) ([]sql.Database, error) {
 	branches, err := db.DbData().Ddb.GetBranches(ctx)
 	if err != nil {
 		return nil, err
@@ -344,34 +758,46 @@ func (p DoltDatabaseProvider) allRevisionDbs(ctx *sql.Context, db dsess.SqlDatab
 	return revDbs, nil
 }
 
-func (p DoltDatabaseProvider) GetRemoteDB(ctx context.Context, format *types.NomsBinFormat, r env.Remote, withCaching bool) (*doltdb.DoltDB, error) {
+func (p *DoltDatabaseProvider) GetRemoteDB(ctx context.Context, format *types.NomsBinFormat, r env.Remote, withCaching bool) (*doltdb.DoltDB, error) {
 	if withCaching {
 		return r.GetRemoteDB(ctx, format, p.remoteDialer)
 	}
 	return r.GetRemoteDBWithoutCaching(ctx, format, p.remoteDialer)
 }
 
-func (p DoltDatabaseProvider) CreateDatabase(ctx *sql.Context, name string) error {
+func (p *DoltDatabaseProvider) CreateDatabase(ctx *sql.Context, name string) error {
 	return p.CreateCollatedDatabase(ctx, name, sql.Collation_Default)
 }
 
-func (p DoltDatabaseProvider) CreateCollatedDatabase(ctx *sql.Context, name string, collation sql.CollationID) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+func (p *DoltDatabaseProvider) CreateCollatedDatabase(ctx *sql.Context, name string, collation sql.CollationID) error {
+	if err := validateNewDatabaseName(name); err != nil {
+		return err
+	}
+	unlock := p.dbLocks.lock(name)
+	defer unlock()
 
-	exists, isDir := p.fs.Exists(name)
+	if p.hasDatabase(name) {
+		return sql.ErrDatabaseExists.New(name)
+	}
+	dir, recordDir := p.dbNames.DirForNewDatabase(name)
+	exists, isDir := p.fs.Exists(dir)
 	if exists && isDir {
 		return sql.ErrDatabaseExists.New(name)
 	} else if exists {
-		return fmt.Errorf("Cannot create DB, file exists at %s", name)
+		return fmt.Errorf("Cannot create DB, file exists at %s", dir)
 	}
 
-	err := p.fs.MkDirs(name)
+	newDbStorageFormat, err := p.newDatabaseStorageFormat(ctx)
 	if err != nil {
 		return err
 	}
 
-	newFs, err := p.fs.WithWorkingDir(name)
+	err = p.fs.MkDirs(dir)
+	if err != nil {
+		return err
+	}
+
+	newFs, err := p.fs.WithWorkingDir(dir)
 	if err != nil {
 		return err
 	}
@@ -380,7 +806,6 @@ func (p DoltDatabaseProvider) CreateCollatedDatabase(ctx *sql.Context, name stri
 	sess := dsess.DSessFromSess(ctx.Session)
 	newEnv := env.Load(ctx, env.GetCurrentUserHomeDir, newFs, p.dbFactoryUrl, "TODO")
 
-	newDbStorageFormat := types.Format_Default
 	err = newEnv.InitRepo(ctx, newDbStorageFormat, sess.Username(), sess.Email(), p.defaultBranch)
 	if err != nil {
 		return err
@@ -432,27 +857,193 @@ func (p DoltDatabaseProvider) CreateCollatedDatabase(ctx *sql.Context, name stri
 		return err
 	}
 
-	// If we have an initialization hook, invoke it.  By default, this will
-	// be ConfigureReplicationDatabaseHook, which will setup replication
-	// for the new database if a remote url template is set.
-	err = p.InitDatabaseHook(ctx, p, name, newEnv)
+	err = p.hooks.runInit(ctx, p, name, newEnv)
 	if err != nil {
 		return err
 	}
 
+	if recordDir {
+		err = p.dbNames.Add(name, dir)
+		if err != nil {
+			return err
+		}
+	}
+
 	formattedName := formatDbMapKeyName(db.Name())
-	p.databases[formattedName] = db
-	p.dbLocations[formattedName] = newEnv.FS
+	p.updateDatabases(func(databases map[string]dsess.SqlDatabase, dbLocations map[string]filesys.Filesys) {
+		databases[formattedName] = db
+		dbLocations[formattedName] = newEnv.FS
+	})
+
+	return nil
+}
+
+// newDatabaseStorageFormat returns the storage format to create a database in, which is the one named by the
+// dolt_create_database_storage_format session variable, or else the provider's.
+func (p *DoltDatabaseProvider) newDatabaseStorageFormat(ctx *sql.Context) (*types.NomsBinFormat, error) {
+	val, err := ctx.GetSessionVariable(ctx, dsess.CreateDatabaseStorageFormat)
+	if err != nil {
+		return nil, err
+	}
+	if name, ok := val.(string); ok && name != "" {
+		nbf, err := storageFormatForName(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", dsess.CreateDatabaseStorageFormat, err)
+		}
+		return nbf, nil
+	}
+	if p.storageFormat != nil {
+		return p.storageFormat, nil
+	}
+	return types.Format_Default, nil
+}
 
+// storageFormatForName returns the storage format named by its version string, or by its short name, ld1 or dolt.
+func storageFormatForName(name string) (*types.NomsBinFormat, error) {
+	switch strings.ToLower(name) {
+	case "ld1":
+		return types.Format_LD_1, nil
+	case "dolt":
+		return types.Format_DOLT, nil
+	}
+	return types.GetFormatForVersionString(strings.ToUpper(name))
+}
+
+// hasDatabase returns whether the provider has a database named |name|, whether it's been opened or not.
+// normalizeRevisionDbName returns the database name given with DbRevisionDelimiter in place of the alternate revision
+// delimiter set in @@dolt_alternate_revision_delimiter. The delimiter is only replaced when the name before it names a
+// database, and the name itself doesn't, so that databases whose names contain the delimiter can still be used.
+func (p *DoltDatabaseProvider) normalizeRevisionDbName(name string) string {
+	baseName, rev, ok := dsess.SplitAlternateRevisionDbName(name)
+	if !ok || p.hasDatabase(name) || !p.hasDatabase(baseName) {
+		return name
+	}
+	return dsess.RevisionDbName(baseName, rev)
+}
+
+// validateNewDatabaseName returns an error if |name| can't be used to name a new database, because it would be read as
+// a revision database name.
+func validateNewDatabaseName(name string) error {
+	delim := dsess.AlternateRevisionDelimiterValue()
+	if delim != "" && strings.Contains(strings.ToLower(name), strings.ToLower(delim)) {
+		return fmt.Errorf("invalid database name '%s': it contains the revision delimiter '%s' set in @@%s", name, delim, dsess.AlternateRevisionDelimiter)
+	}
 	return nil
 }
 
-type InitDatabaseHook func(ctx *sql.Context, pro DoltDatabaseProvider, name string, env *env.DoltEnv) error
-type DropDatabaseHook func(name string)
+func (p *DoltDatabaseProvider) hasDatabase(name string) bool {
+	if _, ok := p.databases.Load().databases[formatDbMapKeyName(name)]; ok {
+		return true
+	}
+	_, ok := p.lazy.get(name)
+	return ok
+}
+
+// InitDatabaseHook is run when a database is created or cloned, and when its DoltDB is swapped for a new one.
+type InitDatabaseHook func(ctx *sql.Context, pro *DoltDatabaseProvider, name string, env *env.DoltEnv) error
 
-// ConfigureReplicationDatabaseHook sets up replication for a newly created database as necessary
+// DropDatabaseHook is run when a database is dropped, before its files are deleted.
+type DropDatabaseHook func(name string) error
+
+// databaseHooks holds the hooks of a provider, which are run in the order they were added.
+type databaseHooks struct {
+	mu   sync.Mutex
+	init []InitDatabaseHook
+	drop []DropDatabaseHook
+}
+
+// AddInitDatabaseHook adds |hook| to the hooks run when a database is created, after the ones already added. Every
+// hook is run, even when one before it fails, and their errors are returned together. A nil |hook| is ignored.
+func (p *DoltDatabaseProvider) AddInitDatabaseHook(hook InitDatabaseHook) {
+	if hook == nil {
+		return
+	}
+	p.hooks.mu.Lock()
+	defer p.hooks.mu.Unlock()
+	p.hooks.init = append(p.hooks.init, hook)
+}
+
+// AddDropDatabaseHook adds |hook| to the hooks run when a database is dropped, after the ones already added. Every
+// hook is run, even when one before it fails, and their errors are returned together. A nil |hook| is ignored.
+func (p *DoltDatabaseProvider) AddDropDatabaseHook(hook DropDatabaseHook) {
+	if hook == nil {
+		return
+	}
+	p.hooks.mu.Lock()
+	defer p.hooks.mu.Unlock()
+	p.hooks.drop = append(p.hooks.drop, hook)
+}
+
+func (h *databaseHooks) runInit(ctx *sql.Context, pro *DoltDatabaseProvider, name string, denv *env.DoltEnv) error {
+	h.mu.Lock()
+	hooks := h.init
+	h.mu.Unlock()
+
+	var errs []error
+	for _, hook := range hooks {
+		if err := hook(ctx, pro, name, denv); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return joinHookErrors(name, errs)
+}
+
+func (h *databaseHooks) runDrop(name string) error {
+	h.mu.Lock()
+	hooks := h.drop
+	h.mu.Unlock()
+
+	var errs []error
+	for _, hook := range hooks {
+		if err := hook(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return joinHookErrors(name, errs)
+}
+
+// joinHookErrors returns the errors of the hooks run for the database named as one error, or nil if there were none.
+func joinHookErrors(name string, errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Errorf("%d hooks failed for database %s: %s", len(errs), name, strings.Join(msgs, "; "))
+}
+
+// NewWebhooksInitDatabaseHook returns an InitDatabaseHook that fires |dispatcher|'s webhooks for newly created
+// databases, or nil if there's no |dispatcher|.
+func NewWebhooksInitDatabaseHook(dispatcher *webhooks.Dispatcher) InitDatabaseHook {
+	if dispatcher == nil {
+		return nil
+	}
+	return func(ctx *sql.Context, pro *DoltDatabaseProvider, name string, denv *env.DoltEnv) error {
+		return dispatcher.InstallCommitHook(ctx, name, denv.DoltDB)
+	}
+}
+
+// NewCommitHooksInitDatabaseHook returns an InitDatabaseHook that installs |runner|'s user-defined commit hooks on
+// newly created databases, or nil if there's no |runner|.
+func NewCommitHooksInitDatabaseHook(runner *commithooks.Runner) InitDatabaseHook {
+	if runner == nil {
+		return nil
+	}
+	return func(ctx *sql.Context, pro *DoltDatabaseProvider, name string, denv *env.DoltEnv) error {
+		runner.InstallCommitHook(ctx, name, denv.DoltDB)
+		return nil
+	}
+}
+
+// ConfigureReplicationDatabaseHook sets up replication for a newly created database as necessary. Since it replaces
+// the database's commit hooks, it must be added before any hook that installs others.
 // TODO: consider the replication heads / all heads setting
-func ConfigureReplicationDatabaseHook(ctx *sql.Context, p DoltDatabaseProvider, name string, newEnv *env.DoltEnv) error {
+func ConfigureReplicationDatabaseHook(ctx *sql.Context, p *DoltDatabaseProvider, name string, newEnv *env.DoltEnv) error {
 	_, replicationRemoteName, _ := sql.SystemVariables.GetGlobal(dsess.ReplicateToRemote)
 	if replicationRemoteName == "" {
 		return nil
@@ -473,12 +1064,18 @@ func ConfigureReplicationDatabaseHook(ctx *sql.Context, p DoltDatabaseProvider,
 		return nil
 	}
 
-	// TODO: url sanitize name
-	remoteUrl := strings.Replace(urlTemplate, dsess.URLTemplateDatabasePlaceholder, name, -1)
+	remoteUrl, err := dsess.ReplicationRemoteURL(urlTemplate, name, p.defaultBranch)
+	if err != nil {
+		return err
+	}
 
-	// TODO: params for AWS, others that need them
-	r := env.NewRemote(remoteName, remoteUrl, nil)
-	err := r.Prepare(ctx, newEnv.DoltDB.Format(), p.remoteDialer)
+	remoteParams, err := dsess.RemoteParamsForReplication()
+	if err != nil {
+		return err
+	}
+
+	r := env.NewRemote(remoteName, remoteUrl, remoteParams)
+	err = r.Prepare(ctx, newEnv.DoltDB.Format(), p.remoteDialer)
 	if err != nil {
 		return err
 	}
@@ -502,64 +1099,75 @@ func ConfigureReplicationDatabaseHook(ctx *sql.Context, p DoltDatabaseProvider,
 }
 
 // CloneDatabaseFromRemote implements DoltDatabaseProvider interface
-func (p DoltDatabaseProvider) CloneDatabaseFromRemote(
+func (p *DoltDatabaseProvider) CloneDatabaseFromRemote(
 	ctx *sql.Context,
 	dbName, branch, remoteName, remoteUrl string,
+	depth int,
 	remoteParams map[string]string,
 ) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	if err := validateNewDatabaseName(dbName); err != nil {
+		return err
+	}
+	unlock := p.dbLocks.lock(dbName)
+	defer unlock()
 
-	exists, isDir := p.fs.Exists(dbName)
+	if p.hasDatabase(dbName) {
+		return sql.ErrDatabaseExists.New(dbName)
+	}
+	dir, recordDir := p.dbNames.DirForNewDatabase(dbName)
+	exists, isDir := p.fs.Exists(dir)
 	if exists && isDir {
 		return sql.ErrDatabaseExists.New(dbName)
 	} else if exists {
-		return fmt.Errorf("cannot create DB, file exists at %s", dbName)
+		return fmt.Errorf("cannot create DB, file exists at %s", dir)
 	}
 
-	dEnv, err := p.cloneDatabaseFromRemote(ctx, dbName, remoteName, branch, remoteUrl, remoteParams)
+	_, err := p.cloneDatabaseFromRemote(ctx, dbName, dir, remoteName, branch, remoteUrl, depth, remoteParams)
 	if err != nil {
 		// Make a best effort to clean up any artifacts on disk from a failed clone
 		// before we return the error
-		exists, _ := p.fs.Exists(dbName)
+		exists, _ := p.fs.Exists(dir)
 		if exists {
-			deleteErr := p.fs.Delete(dbName, true)
+			deleteErr := p.fs.Delete(dir, true)
 			if deleteErr != nil {
-				err = fmt.Errorf("%s: unable to clean up failed clone in directory '%s'", err.Error(), dbName)
+				err = fmt.Errorf("%s: unable to clean up failed clone in directory '%s'", err.Error(), dir)
 			}
 		}
 		return err
 	}
 
-	return ConfigureReplicationDatabaseHook(ctx, p, dbName, dEnv)
+	if recordDir {
+		return p.dbNames.Add(dbName, dir)
+	}
+	return nil
 }
 
 // cloneDatabaseFromRemote encapsulates the inner logic for cloning a database so that if any error
 // is returned by this function, the caller can capture the error and safely clean up the failed
 // clone directory before returning the error to the user. This function should not be used directly;
 // use CloneDatabaseFromRemote instead.
-func (p DoltDatabaseProvider) cloneDatabaseFromRemote(
+func (p *DoltDatabaseProvider) cloneDatabaseFromRemote(
 	ctx *sql.Context,
-	dbName, remoteName, branch, remoteUrl string,
+	dbName, dir, remoteName, branch, remoteUrl string,
+	depth int,
 	remoteParams map[string]string,
 ) (*env.DoltEnv, error) {
 	if p.remoteDialer == nil {
 		return nil, fmt.Errorf("unable to clone remote database; no remote dialer configured")
 	}
 
-	// TODO: params for AWS, others that need them
-	r := env.NewRemote(remoteName, remoteUrl, nil)
+	r := env.NewRemote(remoteName, remoteUrl, remoteParams)
 	srcDB, err := r.GetRemoteDB(ctx, types.Format_Default, p.remoteDialer)
 	if err != nil {
 		return nil, err
 	}
 
-	dEnv, err := actions.EnvForClone(ctx, srcDB.ValueReadWriter().Format(), r, dbName, p.fs, "VERSION", env.GetCurrentUserHomeDir)
+	dEnv, err := actions.EnvForClone(ctx, srcDB.ValueReadWriter().Format(), r, dir, p.fs, "VERSION", env.GetCurrentUserHomeDir)
 	if err != nil {
 		return nil, err
 	}
 
-	err = actions.CloneRemote(ctx, srcDB, remoteName, branch, dEnv)
+	err = actions.CloneRemote(ctx, srcDB, remoteName, branch, depth, nil, dEnv)
 	if err != nil {
 		return nil, err
 	}
@@ -585,35 +1193,36 @@ func (p DoltDatabaseProvider) cloneDatabaseFromRemote(
 		return nil, err
 	}
 
-	// If we have an initialization hook, invoke it.  By default, this will
-	// be ConfigureReplicationDatabaseHook, which will setup replication
-	// for the new database if a remote url template is set.
-	err = p.InitDatabaseHook(ctx, p, dbName, dEnv)
+	err = p.hooks.runInit(ctx, p, dbName, dEnv)
 	if err != nil {
 		return nil, err
 	}
 
-	p.databases[formatDbMapKeyName(db.Name())] = db
+	p.updateDatabases(func(databases map[string]dsess.SqlDatabase, _ map[string]filesys.Filesys) {
+		databases[formatDbMapKeyName(db.Name())] = db
+	})
 
 	return dEnv, nil
 }
 
 // DropDatabase implements the sql.MutableDatabaseProvider interface
-func (p DoltDatabaseProvider) DropDatabase(ctx *sql.Context, name string) error {
+func (p *DoltDatabaseProvider) DropDatabase(ctx *sql.Context, name string) error {
 	_, revision := dsess.SplitRevisionDbName(name)
 	if revision != "" {
 		return fmt.Errorf("unable to drop revision database: %s", name)
 	}
 
-	// get the case-sensitive name for case-sensitive file systems
-	// TODO: there are still cases (not server-first) where we rename databases because the directory name would need
-	//  quoting if used as a database name, and that breaks here. We either need the database name to match the directory
-	//  name in all cases, or else keep a mapping from database name to directory on disk.
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	// the database's directory is found from its location, rather than its name, since databases whose names can't be
+	// used as directory names are stored in directories recorded in p.dbNames
+	unlock := p.dbLocks.lock(name)
+	defer unlock()
 
 	dbKey := formatDbMapKeyName(name)
-	db := p.databases[dbKey]
+	dbs := p.databases.Load()
+	db, ok := dbs.databases[dbKey]
+	if !ok {
+		return sql.ErrDatabaseNotFound.New(name)
+	}
 
 	ddb := db.(Database).ddb
 	err := ddb.Close()
@@ -622,7 +1231,7 @@ func (p DoltDatabaseProvider) DropDatabase(ctx *sql.Context, name string) error
 	}
 
 	// get location of database that's being dropped
-	dbLoc := p.dbLocations[dbKey]
+	dbLoc := dbs.dbLocations[dbKey]
 	if dbLoc == nil {
 		return sql.ErrDatabaseNotFound.New(db.Name())
 	}
@@ -638,10 +1247,11 @@ func (p DoltDatabaseProvider) DropDatabase(ctx *sql.Context, name string) error
 		return err
 	}
 
-	if p.DropDatabaseHook != nil {
-		// For symmetry with InitDatabaseHook and the names we see in
-		// MultiEnv initialization, we use `name` here, not `dbKey`.
-		p.DropDatabaseHook(name)
+	// For symmetry with the init hooks and the names we see in
+	// MultiEnv initialization, we use `name` here, not `dbKey`.
+	err = p.hooks.runDrop(name)
+	if err != nil {
+		return err
 	}
 
 	rootDbLoc, err := p.fs.Abs("")
@@ -673,24 +1283,92 @@ func (p DoltDatabaseProvider) DropDatabase(ctx *sql.Context, name string) error
 	if err != nil {
 		return err
 	}
+	err = p.dbNames.Remove(name)
+	if err != nil {
+		return err
+	}
 
 	// We not only have to delete this database, but any derivative ones that we've stored as a result of USE or
 	// connection strings
 	derivativeNamePrefix := strings.ToLower(dbKey + dsess.DbRevisionDelimiter)
-	for dbName := range p.databases {
-		if strings.HasPrefix(strings.ToLower(dbName), derivativeNamePrefix) {
-			delete(p.databases, dbName)
+	p.updateDatabases(func(databases map[string]dsess.SqlDatabase, _ map[string]filesys.Filesys) {
+		for dbName := range databases {
+			if strings.HasPrefix(strings.ToLower(dbName), derivativeNamePrefix) {
+				delete(databases, dbName)
+			}
 		}
+		delete(databases, dbKey)
+	})
+	p.standby.set(name, false)
+
+	return p.invalidateDbStateInAllSessions(ctx, name)
+}
+
+// SwapDatabase replaces the database named, which was loaded from |dEnv|, after |swap| rewrites its storage in place.
+// The database is locked while |swap| runs, so no session can load it until it's been swapped. Afterwards,
+// the database's old DoltDB is closed, |dEnv| loads the rewritten one, and every session reloads the database the next
+// time it's used. Replicated databases can't be swapped.
+func (p *DoltDatabaseProvider) SwapDatabase(ctx *sql.Context, name string, dEnv *env.DoltEnv, swap func() error) error {
+	unlock := p.dbLocks.lock(name)
+	defer unlock()
+
+	dbKey := formatDbMapKeyName(name)
+	db, ok := p.databases.Load().databases[dbKey]
+	if !ok {
+		return sql.ErrDatabaseNotFound.New(name)
+	}
+	if _, ok := db.(Database); !ok {
+		return fmt.Errorf("cannot swap database %s: replicated databases can't be swapped", name)
+	}
+
+	err := swap()
+	if err != nil {
+		return err
+	}
+
+	err = dEnv.DoltDB.Close()
+	if err != nil {
+		return err
+	}
+	dbLoc, err := dEnv.FS.Abs("")
+	if err != nil {
+		return err
+	}
+	err = dbfactory.DeleteFromSingletonCache(dbLoc + "/.dolt/noms")
+	if err != nil {
+		return err
+	}
+	err = dEnv.ReloadDoltDB(ctx)
+	if err != nil {
+		return err
+	}
+
+	newDb, err := NewDatabase(ctx, db.Name(), dEnv.DbData(), editor.Options{Deaf: dEnv.DbEaFactory()})
+	if err != nil {
+		return err
+	}
+	err = p.hooks.runInit(ctx, p, name, dEnv)
+	if err != nil {
+		return err
 	}
 
-	delete(p.databases, dbKey)
+	// Revision databases share the old DoltDB, so they're dropped and loaded again when they're next used
+	derivativeNamePrefix := strings.ToLower(dbKey + dsess.DbRevisionDelimiter)
+	p.updateDatabases(func(databases map[string]dsess.SqlDatabase, _ map[string]filesys.Filesys) {
+		for dbName := range databases {
+			if strings.HasPrefix(strings.ToLower(dbName), derivativeNamePrefix) {
+				delete(databases, dbName)
+			}
+		}
+		databases[dbKey] = newDb
+	})
 
 	return p.invalidateDbStateInAllSessions(ctx, name)
 }
 
 // invalidateDbStateInAllSessions removes the db state for this database from every session. This is necessary when a
 // database is dropped, so that other sessions don't use stale db state.
-func (p DoltDatabaseProvider) invalidateDbStateInAllSessions(ctx *sql.Context, name string) error {
+func (p *DoltDatabaseProvider) invalidateDbStateInAllSessions(ctx *sql.Context, name string) error {
 	// Remove the db state from the current session
 	err := dsess.DSessFromSess(ctx.Session).RemoveDbState(ctx, name)
 	if err != nil {
@@ -723,7 +1401,7 @@ func (p DoltDatabaseProvider) invalidateDbStateInAllSessions(ctx *sql.Context, n
 	return nil
 }
 
-func (p DoltDatabaseProvider) databaseForRevision(ctx *sql.Context, revisionQualifiedName string, requestedName string) (dsess.SqlDatabase, bool, error) {
+func (p *DoltDatabaseProvider) databaseForRevision(ctx *sql.Context, revisionQualifiedName string, requestedName string) (dsess.SqlDatabase, bool, error) {
 	if !strings.Contains(revisionQualifiedName, dsess.DbRevisionDelimiter) {
 		return nil, false, nil
 	}
@@ -739,10 +1417,10 @@ func (p DoltDatabaseProvider) databaseForRevision(ctx *sql.Context, revisionQual
 		return db, true, nil
 	}
 
-	p.mu.RLock()
-	srcDb, ok := p.databases[formatDbMapKeyName(baseName)]
-	p.mu.RUnlock()
-	if !ok {
+	srcDb, ok, err := p.lookupDatabase(ctx, baseName)
+	if err != nil {
+		return nil, false, err
+	} else if !ok {
 		return nil, false, nil
 	}
 
@@ -772,6 +1450,14 @@ func (p DoltDatabaseProvider) databaseForRevision(ctx *sql.Context, revisionQual
 			return nil, false, err
 		}
 
+		dbCache.CacheRevisionDb(db)
+		return db, true, nil
+	case dsess.RevisionTypeWorkspace:
+		db, err := revisionDbForWorkspace(ctx, srcDb, resolvedRevSpec, requestedName)
+		if err != nil {
+			return nil, false, err
+		}
+
 		dbCache.CacheRevisionDb(db)
 		return db, true, nil
 	case dsess.RevisionTypeTag:
@@ -806,7 +1492,13 @@ func (p DoltDatabaseProvider) databaseForRevision(ctx *sql.Context, revisionQual
 			return nil, false, nil
 		}
 
-		db, err := revisionDbForCommit(ctx, srcDb.(Database), rev, requestedName)
+		// date specs can't be resolved again later, so use the commit they resolved to
+		revSpec := rev
+		if _, _, isDateSpec, _ := doltdb.SplitDateSpec(rev); isDateSpec {
+			revSpec = resolvedRevSpec
+		}
+
+		db, err := revisionDbForCommit(ctx, srcDb.(Database), revSpec, requestedName)
 		if err != nil {
 			return nil, false, err
 		}
@@ -847,6 +1539,15 @@ func revisionDbType(ctx *sql.Context, srcDb dsess.SqlDatabase, revSpec string) (
 		return dsess.RevisionTypeTag, resolvedRevSpec, nil
 	}
 
+	isWorkspace, err := isWorkspace(ctx, srcDb, resolvedRevSpec)
+	if err != nil {
+		return dsess.RevisionTypeNone, "", err
+	}
+
+	if isWorkspace {
+		return dsess.RevisionTypeWorkspace, resolvedRevSpec, nil
+	}
+
 	if doltdb.IsValidCommitHash(resolvedRevSpec) {
 		// IsValidCommitHash just checks a regex, we need to see if the commit actually exists
 		valid, err := isValidCommitHash(ctx, srcDb, resolvedRevSpec)
@@ -950,7 +1651,7 @@ func initialDbState(ctx context.Context, db dsess.SqlDatabase, branch string) (d
 
 func initialStateForRevisionDb(ctx *sql.Context, db dsess.SqlDatabase) (dsess.InitialDbState, error) {
 	switch db.RevisionType() {
-	case dsess.RevisionTypeBranch:
+	case dsess.RevisionTypeBranch, dsess.RevisionTypeWorkspace:
 		init, err := initialStateForBranchDb(ctx, db)
 		// preserve original user case in the case of not found
 		if sql.ErrDatabaseNotFound.Is(err) {
@@ -1003,23 +1704,19 @@ func initialStateForRevisionDb(ctx *sql.Context, db dsess.SqlDatabase) (dsess.In
 
 // databaseForClone returns a newly cloned database if read replication is enabled and a remote DB exists, or an error
 // otherwise
-func (p DoltDatabaseProvider) databaseForClone(ctx *sql.Context, revDB string) (dsess.SqlDatabase, error) {
+func (p *DoltDatabaseProvider) databaseForClone(ctx *sql.Context, revDB string) (dsess.SqlDatabase, error) {
 	if !readReplicationActive(ctx) {
 		return nil, nil
 	}
 
-	var dbName string
-	if strings.Contains(revDB, dsess.DbRevisionDelimiter) {
-		parts := strings.SplitN(revDB, dsess.DbRevisionDelimiter, 2)
-		dbName = parts[0]
-	} else {
-		dbName = revDB
-	}
+	dbName, _ := dsess.SplitRevisionDbName(revDB)
 
 	err := p.attemptCloneReplica(ctx, dbName)
-	if err != nil {
-		ctx.GetLogger().Warnf("couldn't clone database %s: %s", dbName, err.Error())
+	if errors.Is(err, ErrRemoteDbNotFound) {
+		ctx.GetLogger().Debugf("couldn't clone database %s: %s", dbName, err.Error())
 		return nil, nil
+	} else if err != nil {
+		return nil, err
 	}
 
 	// This database needs to be added to the transaction
@@ -1027,7 +1724,10 @@ func (p DoltDatabaseProvider) databaseForClone(ctx *sql.Context, revDB string) (
 	//  DB is first referenced
 	tx, ok := ctx.GetTransaction().(*dsess.DoltTransaction)
 	if ok {
-		db := p.databases[dbName]
+		db, _, err := p.lookupDatabase(ctx, dbName)
+		if err != nil {
+			return nil, err
+		}
 		err = tx.AddDb(ctx, db)
 		if err != nil {
 			return nil, err
@@ -1055,9 +1755,15 @@ func readReplicationActive(ctx *sql.Context) bool {
 }
 
 // resolveAncestorSpec resolves the specified revSpec to a specific commit hash if it contains an ancestor reference
-// such as ~ or ^. If no ancestor reference is present, the specified revSpec is returned as is. If any unexpected
-// problems are encountered, an error is returned.
+// such as ~ or ^, or is a date spec such as main@{2023-06-01}. If neither is present, the specified revSpec is returned
+// as is. If any unexpected problems are encountered, an error is returned.
 func resolveAncestorSpec(ctx *sql.Context, revSpec string, ddb *doltdb.DoltDB) (string, error) {
+	if refname, asOf, ok, err := doltdb.SplitDateSpec(revSpec); err != nil {
+		return "", err
+	} else if ok {
+		return resolveDateSpec(ctx, ddb, refname, asOf)
+	}
+
 	refname, ancestorSpec, err := doltdb.SplitAncestorSpec(revSpec)
 	if err != nil {
 		return "", err
@@ -1089,46 +1795,63 @@ func resolveAncestorSpec(ctx *sql.Context, revSpec string, ddb *doltdb.DoltDB) (
 	return hash.String(), nil
 }
 
-// BaseDatabase returns the base database for the specified database name. Meant for informational purposes when
-// managing the session initialization only. Use SessionDatabase for normal database retrieval.
-func (p DoltDatabaseProvider) BaseDatabase(ctx *sql.Context, name string) (dsess.SqlDatabase, bool) {
-	baseName := name
-	isRevisionDbName := strings.Contains(name, dsess.DbRevisionDelimiter)
+// resolveDateSpec returns the hash of the last commit on the ref named made at or before |asOf|.
+func resolveDateSpec(ctx *sql.Context, ddb *doltdb.DoltDB, refname string, asOf time.Time) (string, error) {
+	ref, err := ddb.GetRefByNameInsensitive(ctx, refname)
+	if err != nil {
+		return "", err
+	}
 
-	if isRevisionDbName {
-		parts := strings.SplitN(name, dsess.DbRevisionDelimiter, 2)
-		baseName = parts[0]
+	cm, _, err := resolveAsOfTime(ctx, ddb, ref, asOf)
+	if err != nil {
+		return "", err
+	} else if cm == nil {
+		return "", fmt.Errorf("no commits on %s at or before %s", refname, asOf.Format(time.RFC3339))
 	}
 
-	var ok bool
-	p.mu.RLock()
-	db, ok := p.databases[strings.ToLower(baseName)]
-	p.mu.RUnlock()
+	hash, err := cm.HashOf()
+	if err != nil {
+		return "", err
+	}
+
+	return hash.String(), nil
+}
+
+// BaseDatabase returns the base database for the specified database name. Meant for informational purposes when
+// managing the session initialization only. Use SessionDatabase for normal database retrieval.
+func (p *DoltDatabaseProvider) BaseDatabase(ctx *sql.Context, name string) (dsess.SqlDatabase, bool) {
+	baseName, _ := dsess.SplitRevisionDbName(p.normalizeRevisionDbName(name))
 
+	db, ok, err := p.lookupDatabase(ctx, baseName)
+	if err != nil {
+		ctx.GetLogger().Warnf("error loading database %s: %s", baseName, err.Error())
+		return nil, false
+	}
 	return db, ok
 }
 
 // SessionDatabase implements dsess.SessionDatabaseProvider
-func (p DoltDatabaseProvider) SessionDatabase(ctx *sql.Context, name string) (dsess.SqlDatabase, bool, error) {
-	baseName := name
-	isRevisionDbName := strings.Contains(name, dsess.DbRevisionDelimiter)
+func (p *DoltDatabaseProvider) SessionDatabase(ctx *sql.Context, name string) (dsess.SqlDatabase, bool, error) {
+	// Clients that don't allow a / in database names can use the alternate revision delimiter instead. The database
+	// returned is named with DbRevisionDelimiter, which the session relies on to split its name.
+	revisionDbName := p.normalizeRevisionDbName(name)
+	baseName := revisionDbName
+	isRevisionDbName := strings.Contains(revisionDbName, dsess.DbRevisionDelimiter)
 
 	if isRevisionDbName {
 		// TODO: formalize and enforce this rule (can't allow DBs with / in the name)
-		// TODO: some connectors will take issue with the /, we need other mechanisms to support them
-		parts := strings.SplitN(name, dsess.DbRevisionDelimiter, 2)
+		parts := strings.SplitN(revisionDbName, dsess.DbRevisionDelimiter, 2)
 		baseName = parts[0]
 	}
 
-	var ok bool
-	p.mu.RLock()
-	db, ok := p.databases[strings.ToLower(baseName)]
-	standby := *p.isStandby
-	p.mu.RUnlock()
+	db, ok, err := p.lookupDatabase(ctx, baseName)
+	if err != nil {
+		return nil, false, err
+	}
+	standby := p.standby.get(baseName)
 
 	// If the database doesn't exist and this is a read replica, attempt to clone it from the remote
 	if !ok {
-		var err error
 		db, err = p.databaseForClone(ctx, strings.ToLower(baseName))
 
 		if err != nil {
@@ -1147,7 +1870,7 @@ func (p DoltDatabaseProvider) SessionDatabase(ctx *sql.Context, name string) (ds
 
 	// Convert to a revision database before returning. If we got a non-qualified name, convert it to a qualified name
 	// using the session's current head
-	revisionQualifiedName := name
+	revisionQualifiedName := revisionDbName
 	usingDefaultBranch := false
 	head := ""
 	sess := dsess.DSessFromSess(ctx.Session)
@@ -1163,7 +1886,7 @@ func (p DoltDatabaseProvider) SessionDatabase(ctx *sql.Context, name string) (ds
 		if !ok {
 			usingDefaultBranch = true
 
-			head, err = dsess.DefaultHead(baseName, db)
+			head, err = dsess.DefaultHead(ctx, baseName, db)
 			if err != nil {
 				return nil, false, err
 			}
@@ -1172,7 +1895,7 @@ func (p DoltDatabaseProvider) SessionDatabase(ctx *sql.Context, name string) (ds
 		revisionQualifiedName = baseName + dsess.DbRevisionDelimiter + head
 	}
 
-	db, ok, err := p.databaseForRevision(ctx, revisionQualifiedName, name)
+	db, ok, err = p.databaseForRevision(ctx, revisionQualifiedName, revisionDbName)
 	if err != nil {
 		if sql.ErrDatabaseNotFound.Is(err) && usingDefaultBranch {
 			// We can return a better error message here in some cases
@@ -1192,7 +1915,7 @@ func (p DoltDatabaseProvider) SessionDatabase(ctx *sql.Context, name string) (ds
 }
 
 // Function implements the FunctionProvider interface
-func (p DoltDatabaseProvider) Function(_ *sql.Context, name string) (sql.Function, error) {
+func (p *DoltDatabaseProvider) Function(_ *sql.Context, name string) (sql.Function, error) {
 	fn, ok := p.functions[strings.ToLower(name)]
 	if !ok {
 		return nil, sql.ErrFunctionNotFound.New(name)
@@ -1200,22 +1923,22 @@ func (p DoltDatabaseProvider) Function(_ *sql.Context, name string) (sql.Functio
 	return fn, nil
 }
 
-func (p DoltDatabaseProvider) Register(d sql.ExternalStoredProcedureDetails) {
+func (p *DoltDatabaseProvider) Register(d sql.ExternalStoredProcedureDetails) {
 	p.externalProcedures.Register(d)
 }
 
 // ExternalStoredProcedure implements the sql.ExternalStoredProcedureProvider interface
-func (p DoltDatabaseProvider) ExternalStoredProcedure(_ *sql.Context, name string, numOfParams int) (*sql.ExternalStoredProcedureDetails, error) {
+func (p *DoltDatabaseProvider) ExternalStoredProcedure(_ *sql.Context, name string, numOfParams int) (*sql.ExternalStoredProcedureDetails, error) {
 	return p.externalProcedures.LookupByNameAndParamCount(name, numOfParams)
 }
 
 // ExternalStoredProcedures implements the sql.ExternalStoredProcedureProvider interface
-func (p DoltDatabaseProvider) ExternalStoredProcedures(_ *sql.Context, name string) ([]sql.ExternalStoredProcedureDetails, error) {
+func (p *DoltDatabaseProvider) ExternalStoredProcedures(_ *sql.Context, name string) ([]sql.ExternalStoredProcedureDetails, error) {
 	return p.externalProcedures.LookupByName(name)
 }
 
 // TableFunction implements the sql.TableFunctionProvider interface
-func (p DoltDatabaseProvider) TableFunction(_ *sql.Context, name string) (sql.TableFunction, error) {
+func (p *DoltDatabaseProvider) TableFunction(_ *sql.Context, name string) (sql.TableFunction, error) {
 	// TODO: Clean this up and store table functions in a map, similar to regular functions.
 	switch strings.ToLower(name) {
 	case "dolt_diff":
@@ -1239,6 +1962,21 @@ func (p DoltDatabaseProvider) TableFunction(_ *sql.Context, name string) (sql.Ta
 	case "dolt_query_diff":
 		dtf := &QueryDiffTableFunction{}
 		return dtf, nil
+	case "dolt_violation_origins":
+		dtf := &ViolationOriginsTableFunction{}
+		return dtf, nil
+	case "dolt_lineage":
+		dtf := &LineageTableFunction{}
+		return dtf, nil
+	case "dolt_blame":
+		dtf := &BlameTableFunction{}
+		return dtf, nil
+	case "dolt_vector_search":
+		dtf := &VectorSearchTableFunction{}
+		return dtf, nil
+	case "dolt_replication_status":
+		dtf := &ReplicationStatusTableFunction{}
+		return dtf, nil
 	}
 
 	return nil, sql.ErrTableFunctionNotFound.New(name)
@@ -1246,7 +1984,7 @@ func (p DoltDatabaseProvider) TableFunction(_ *sql.Context, name string) (sql.Ta
 
 // ensureReplicaHeadExists tries to pull the latest version of a remote branch. Will fail if the branch
 // does not exist on the ReadReplicaDatabase's remote.
-func (p DoltDatabaseProvider) ensureReplicaHeadExists(ctx *sql.Context, branch string, db ReadReplicaDatabase) error {
+func (p *DoltDatabaseProvider) ensureReplicaHeadExists(ctx *sql.Context, branch string, db ReadReplicaDatabase) error {
 	return db.CreateLocalBranchFromRemote(ctx, ref.NewBranchRef(branch))
 }
 
@@ -1322,6 +2060,32 @@ func isTag(ctx context.Context, db dsess.SqlDatabase, tagName string) (bool, err
 	return false, nil
 }
 
+// isWorkspace returns whether a session workspace with the given name exists for the database given and is owned by
+// the context's session. Workspaces are private to the session that opened them, so other sessions can't resolve them.
+func isWorkspace(ctx *sql.Context, db dsess.SqlDatabase, workspaceName string) (bool, error) {
+	if ref.IsRef(workspaceName) || !doltdb.IsValidUserBranchName(workspaceName) {
+		return false, nil
+	}
+
+	dSess, ok := ctx.Session.(*dsess.DoltSession)
+	if !ok || !dSess.OwnsWorkspace(db.Name(), workspaceName) {
+		return false, nil
+	}
+
+	for _, ddb := range db.DoltDatabases() {
+		exists, err := ddb.HasRef(ctx, ref.NewWorkspaceRef(workspaceName))
+		if err != nil {
+			return false, err
+		}
+
+		if exists {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // revisionDbForBranch returns a new database that is tied to the branch named by revSpec
 func revisionDbForBranch(ctx context.Context, srcDb dsess.SqlDatabase, revSpec string, requestedName string) (dsess.SqlDatabase, error) {
 	static := staticRepoState{
@@ -1336,6 +2100,22 @@ func revisionDbForBranch(ctx context.Context, srcDb dsess.SqlDatabase, revSpec s
 	})
 }
 
+// revisionDbForWorkspace returns a new database that is tied to the session workspace named by revSpec
+func revisionDbForWorkspace(ctx context.Context, srcDb dsess.SqlDatabase, revSpec string, requestedName string) (dsess.SqlDatabase, error) {
+	static := staticRepoState{
+		branch:          ref.NewWorkspaceRef(revSpec),
+		RepoStateWriter: srcDb.DbData().Rsw,
+		RepoStateReader: srcDb.DbData().Rsr,
+	}
+
+	return srcDb.WithBranchRevision(requestedName, dsess.SessionDatabaseBranchSpec{
+		RepoState: static,
+		Branch:    revSpec,
+		Workspace: true,
+	})
+}
+
+// initialStateForBranchDb returns the initial state for a database tied to a branch or to a session workspace.
 func initialStateForBranchDb(ctx *sql.Context, srcDb dsess.SqlDatabase) (dsess.InitialDbState, error) {
 	revSpec := srcDb.Revision()
 
@@ -1345,7 +2125,10 @@ func initialStateForBranchDb(ctx *sql.Context, srcDb dsess.SqlDatabase) (dsess.I
 		return dsess.InitialDbState{}, err
 	}
 
-	branch := ref.NewBranchRef(revSpec)
+	var branch ref.DoltRef = ref.NewBranchRef(revSpec)
+	if srcDb.RevisionType() == dsess.RevisionTypeWorkspace {
+		branch = ref.NewWorkspaceRef(revSpec)
+	}
 	cm, err := srcDb.DbData().Ddb.ResolveCommitRefAtRoot(ctx, branch, rootHash)
 	if err != nil {
 		return dsess.InitialDbState{}, err
@@ -1501,16 +2284,8 @@ func (s staticRepoState) CWBHeadRef() (ref.DoltRef, error) {
 	return s.branch, nil
 }
 
-// formatDbMapKeyName returns formatted string of database name and/or branch name. Database name is case-insensitive,
-// so it's stored in lower case name. Branch name is case-sensitive, so not changed.
-// TODO: branch names should be case-insensitive too
+// formatDbMapKeyName returns formatted string of database name and/or branch name. Database and branch names are both
+// case-insensitive, so it's stored in lower case.
 func formatDbMapKeyName(name string) string {
-	if !strings.Contains(name, dsess.DbRevisionDelimiter) {
-		return strings.ToLower(name)
-	}
-
-	parts := strings.SplitN(name, dsess.DbRevisionDelimiter, 2)
-	dbName, revSpec := parts[0], parts[1]
-
-	return strings.ToLower(dbName) + dsess.DbRevisionDelimiter + revSpec
+	return strings.ToLower(name)
 }
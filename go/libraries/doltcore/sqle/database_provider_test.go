@@ -0,0 +1,217 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/config"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func TestDatabaseProviderLocking(t *testing.T) {
+	pro, err := NewDoltDatabaseProvider("main", filesys.EmptyInMemFS("/"))
+	require.NoError(t, err)
+
+	// an operation on one database doesn't block lookups of others
+	ctx := sql.NewEmptyContext()
+	unlock := pro.dbLocks.lock("Cloning")
+	_, ok, err := pro.lookupDatabase(ctx, "other")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// but lookups of the database wait for it to finish
+	looked := make(chan struct{})
+	go func() {
+		defer close(looked)
+		pro.lookupDatabase(ctx, "CLONING")
+	}()
+	select {
+	case <-looked:
+		t.Fatal("lookup of a locked database didn't wait for its operation")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	<-looked
+
+	// snapshots that have been read aren't changed by later updates
+	before := pro.databases.Load()
+	pro.updateDatabases(func(databases map[string]dsess.SqlDatabase, dbLocations map[string]filesys.Filesys) {
+		dbLocations["added"] = filesys.EmptyInMemFS("/added")
+	})
+	assert.NotContains(t, before.dbLocations, "added")
+	_, err = pro.FileSystemForDatabase("Added")
+	assert.NoError(t, err)
+}
+
+func TestDatabaseProviderLazyDatabases(t *testing.T) {
+	attempts := 0
+	cfg := NewDoltDatabaseProviderConfig("main", filesys.EmptyInMemFS("/")).WithLazyDatabases(map[string]LazyDatabaseLoader{
+		"Broken": func(ctx *sql.Context) (dsess.SqlDatabase, filesys.Filesys, error) {
+			attempts++
+			return nil, nil, errors.New("corrupt")
+		},
+	})
+	pro, err := NewDoltDatabaseProviderFromConfig(cfg, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"broken"}, pro.lazy.names())
+
+	// a database that can't be opened is reported, and left to be opened again
+	ctx := sql.NewEmptyContext()
+	_, _, err = pro.lookupDatabase(ctx, "broken")
+	assert.ErrorContains(t, err, "corrupt")
+	_, _, err = pro.lookupDatabase(ctx, "BROKEN")
+	assert.Error(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []string{"broken"}, pro.lazy.names())
+
+	// databases the provider doesn't know about aren't loaded at all
+	_, ok, err := pro.lookupDatabase(ctx, "missing")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestDatabaseProviderHooks(t *testing.T) {
+	pro, err := NewDoltDatabaseProvider("main", filesys.EmptyInMemFS("/"))
+	require.NoError(t, err)
+
+	var ran []string
+	initHook := func(hookName string, err error) InitDatabaseHook {
+		return func(ctx *sql.Context, _ *DoltDatabaseProvider, name string, _ *env.DoltEnv) error {
+			ran = append(ran, hookName+" "+name)
+			return err
+		}
+	}
+	pro.AddInitDatabaseHook(initHook("replication", nil))
+	pro.AddInitDatabaseHook(nil)
+	pro.AddInitDatabaseHook(initHook("cluster", errors.New("no standby")))
+	pro.AddInitDatabaseHook(initHook("webhooks", errors.New("bad url")))
+
+	// every hook is run in order, and their errors are returned together
+	err = pro.hooks.runInit(sql.NewEmptyContext(), pro, "db", nil)
+	assert.Equal(t, []string{"replication db", "cluster db", "webhooks db"}, ran)
+	assert.ErrorContains(t, err, "no standby")
+	assert.ErrorContains(t, err, "bad url")
+
+	ran = nil
+	pro.AddDropDatabaseHook(func(name string) error {
+		ran = append(ran, "cluster "+name)
+		return nil
+	})
+	assert.NoError(t, pro.hooks.runDrop("db"))
+	assert.Equal(t, []string{"cluster db"}, ran)
+}
+
+func TestDatabaseProviderConcurrentDDL(t *testing.T) {
+	pro, err := NewDoltDatabaseProvider("main", filesys.EmptyInMemFS("/"))
+	require.NoError(t, err)
+	newCtx := func() *sql.Context {
+		conf := config.NewMapConfig(map[string]string{
+			env.UserNameKey:  "billy bob",
+			env.UserEmailKey: "bigbillieb@fake.horse",
+		})
+		sess, err := dsess.NewDoltSession(sql.NewBaseSession(), pro, conf, branch_control.CreateDefaultController())
+		require.NoError(t, err)
+		return sql.NewContext(context.Background(), sql.WithSession(sess))
+	}
+
+	// each worker creates, looks up and drops its own database, and races the others to create a shared one
+	const workers = 8
+	var created atomic.Int32
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		ctx := newCtx()
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("db%d", i)
+			if err := pro.CreateDatabase(ctx, name); err != nil {
+				errs <- err
+				return
+			}
+			if _, ok, err := pro.SessionDatabase(ctx, name); err != nil || !ok {
+				errs <- fmt.Errorf("database %s not found after create: %v", name, err)
+				return
+			}
+			if err := pro.DropDatabase(ctx, name); err != nil {
+				errs <- err
+				return
+			}
+			if err := pro.CreateDatabase(ctx, "shared"); err == nil {
+				created.Add(1)
+			} else if !sql.ErrDatabaseExists.Is(err) {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), created.Load())
+	ctx := newCtx()
+	for i := 0; i < workers; i++ {
+		assert.False(t, pro.HasDatabase(ctx, fmt.Sprintf("db%d", i)))
+	}
+	_, ok, err := pro.SessionDatabase(ctx, "shared")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestDatabaseProviderStandby(t *testing.T) {
+	pro, err := NewDoltDatabaseProvider("main", filesys.EmptyInMemFS("/"))
+	require.NoError(t, err)
+
+	// databases are marked standby individually
+	pro.SetIsStandby("Replica", true)
+	assert.True(t, pro.IsStandby("replica"))
+	assert.False(t, pro.IsStandby("primary"))
+
+	// a standby server makes every database a standby
+	pro.SetIsStandby("", true)
+	assert.True(t, pro.IsStandby("primary"))
+	pro.SetIsStandby("", false)
+	assert.False(t, pro.IsStandby("primary"))
+	assert.True(t, pro.IsStandby("replica"))
+
+	pro.SetIsStandby("replica", false)
+	assert.False(t, pro.IsStandby("replica"))
+}
+
+func TestRemoteDbNotFound(t *testing.T) {
+	assert.True(t, remoteDbNotFound(fmt.Errorf("%w; %w", actions.ErrCloneFailed, actions.ErrNoDataAtRemote)))
+	assert.True(t, remoteDbNotFound(fmt.Errorf("clone failed: %w", status.Error(codes.NotFound, "no such database"))))
+	assert.False(t, remoteDbNotFound(fmt.Errorf("clone failed: %w", status.Error(codes.Unavailable, "connection refused"))))
+	assert.False(t, remoteDbNotFound(errors.New("permission denied")))
+}
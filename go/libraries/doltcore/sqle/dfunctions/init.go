@@ -22,6 +22,9 @@ var DoltFunctions = []sql.Function{
 	sql.Function0{Name: StorageFormatFuncName, Fn: NewStorageFormat},
 	sql.Function0{Name: ActiveBranchFuncName, Fn: NewActiveBranchFunc},
 	sql.Function2{Name: DoltMergeBaseFuncName, Fn: NewMergeBase},
+	sql.Function2{Name: VecDistanceFuncName, Fn: NewVecDistance},
+	sql.Function1{Name: TableLastCommitFuncName, Fn: NewTableLastCommit},
+	sql.Function1{Name: TableStatusFuncName, Fn: NewTableStatus},
 }
 
 // DolthubApiFunctions are the DoltFunctions that get exposed to Dolthub Api.
@@ -31,4 +34,7 @@ var DolthubApiFunctions = []sql.Function{
 	sql.Function0{Name: StorageFormatFuncName, Fn: NewStorageFormat},
 	sql.Function0{Name: ActiveBranchFuncName, Fn: NewActiveBranchFunc},
 	sql.Function2{Name: DoltMergeBaseFuncName, Fn: NewMergeBase},
+	sql.Function2{Name: VecDistanceFuncName, Fn: NewVecDistance},
+	sql.Function1{Name: TableLastCommitFuncName, Fn: NewTableLastCommit},
+	sql.Function1{Name: TableStatusFuncName, Fn: NewTableStatus},
 }
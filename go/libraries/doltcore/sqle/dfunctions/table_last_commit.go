@@ -0,0 +1,182 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const TableLastCommitFuncName = "dolt_table_last_commit"
+
+// TableLastCommit is the function dolt_table_last_commit(<table>), which returns the hash of the most recent commit
+// in the history of HEAD that changed the table, or NULL if the table doesn't exist at HEAD.
+type TableLastCommit struct {
+	expression.UnaryExpression
+}
+
+var _ sql.FunctionExpression = (*TableLastCommit)(nil)
+
+// NewTableLastCommit creates a new TableLastCommit expression.
+func NewTableLastCommit(e sql.Expression) sql.Expression {
+	return &TableLastCommit{expression.UnaryExpression{Child: e}}
+}
+
+// Eval implements the Expression interface.
+func (t *TableLastCommit) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	val, err := t.Child.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	if val == nil {
+		return nil, nil
+	}
+	tableName, ok := val.(string)
+	if !ok {
+		return nil, errors.New("table name is not a string")
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	sess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := sess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+	head, err := sess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	lastCommits, err := LastCommitsForTables(ctx, ddb, head, []string{tableName})
+	if err != nil {
+		return nil, err
+	}
+	if h, ok := lastCommits[tableName]; ok {
+		return h.String(), nil
+	}
+	return nil, nil
+}
+
+// LastCommitsForTables returns the hash of the most recent commit in the history of |head| that changed each of the
+// tables named, walking the history only once. Table names are matched case-insensitively, and tables that don't
+// exist at |head| are left out of the result.
+func LastCommitsForTables(ctx *sql.Context, ddb *doltdb.DoltDB, head *doltdb.Commit, tableNames []string) (map[string]hash.Hash, error) {
+	headHash, err := head.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	itr, err := commitwalk.GetTopologicalOrderIterator(ctx, ddb, []hash.Hash{headHash}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make(map[string]struct{}, len(tableNames))
+	for _, name := range tableNames {
+		pending[name] = struct{}{}
+	}
+	lastCommits := make(map[string]hash.Hash, len(tableNames))
+	for len(pending) > 0 {
+		h, cm, err := itr.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		root, err := cm.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var parentRoot *doltdb.RootValue
+		if cm.NumParents() > 0 {
+			parent, err := ddb.ResolveParent(ctx, cm, 0)
+			if err != nil {
+				return nil, err
+			}
+			parentRoot, err = parent.GetRootValue(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for name := range pending {
+			tblHash, ok, err := rootTableHash(ctx, root, name)
+			if err != nil {
+				return nil, err
+			} else if !ok {
+				// the table doesn't exist at this commit, so no earlier commit changed it
+				delete(pending, name)
+				continue
+			}
+
+			changed := true
+			if parentRoot != nil {
+				parentHash, ok, err := rootTableHash(ctx, parentRoot, name)
+				if err != nil {
+					return nil, err
+				}
+				changed = !ok || parentHash != tblHash
+			}
+			if changed {
+				lastCommits[name] = h
+				delete(pending, name)
+			}
+		}
+	}
+	return lastCommits, nil
+}
+
+// String implements the Stringer interface.
+func (t *TableLastCommit) String() string {
+	return fmt.Sprintf("DOLT_TABLE_LAST_COMMIT(%s)", t.Child.String())
+}
+
+// FunctionName implements the FunctionExpression interface
+func (t *TableLastCommit) FunctionName() string {
+	return TableLastCommitFuncName
+}
+
+// Description implements the FunctionExpression interface
+func (t *TableLastCommit) Description() string {
+	return "returns the hash of the most recent commit that changed a table"
+}
+
+// IsNullable implements the Expression interface.
+func (t *TableLastCommit) IsNullable() bool {
+	return true
+}
+
+// Type implements the Expression interface.
+func (t *TableLastCommit) Type() sql.Type {
+	return types.Text
+}
+
+// WithChildren implements the Expression interface.
+func (t *TableLastCommit) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(t, len(children), 1)
+	}
+	return NewTableLastCommit(children[0]), nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const TableStatusFuncName = "dolt_table_status"
+
+const (
+	tableStatusClean          = "clean"
+	tableStatusStaged         = "staged"
+	tableStatusUnstaged       = "unstaged"
+	tableStatusStagedUnstaged = "staged, unstaged"
+)
+
+// TableStatus is the function dolt_table_status(<table>), which returns whether a table has staged changes, unstaged
+// changes, both, or is clean. Returns NULL if the table doesn't exist in the working set, the staging area or HEAD.
+type TableStatus struct {
+	expression.UnaryExpression
+}
+
+var _ sql.FunctionExpression = (*TableStatus)(nil)
+
+// NewTableStatus creates a new TableStatus expression.
+func NewTableStatus(e sql.Expression) sql.Expression {
+	return &TableStatus{expression.UnaryExpression{Child: e}}
+}
+
+// Eval implements the Expression interface.
+func (t *TableStatus) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	val, err := t.Child.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	if val == nil {
+		return nil, nil
+	}
+	tableName, ok := val.(string)
+	if !ok {
+		return nil, errors.New("table name is not a string")
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	roots, ok := dsess.DSessFromSess(ctx.Session).GetRoots(ctx, dbName)
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	status, ok, err := StatusOfTable(ctx, roots, tableName)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return status, nil
+}
+
+// StatusOfTable returns whether the table named has staged changes, unstaged changes, both, or is clean in |roots|,
+// as reported by dolt_table_status(). Returns false if the table doesn't exist in any of the roots.
+func StatusOfTable(ctx context.Context, roots doltdb.Roots, tableName string) (string, bool, error) {
+	headHash, inHead, err := rootTableHash(ctx, roots.Head, tableName)
+	if err != nil {
+		return "", false, err
+	}
+	stagedHash, inStaged, err := rootTableHash(ctx, roots.Staged, tableName)
+	if err != nil {
+		return "", false, err
+	}
+	workingHash, inWorking, err := rootTableHash(ctx, roots.Working, tableName)
+	if err != nil {
+		return "", false, err
+	}
+	if !inHead && !inStaged && !inWorking {
+		return "", false, nil
+	}
+
+	staged := inHead != inStaged || headHash != stagedHash
+	unstaged := inStaged != inWorking || stagedHash != workingHash
+	switch {
+	case staged && unstaged:
+		return tableStatusStagedUnstaged, true, nil
+	case staged:
+		return tableStatusStaged, true, nil
+	case unstaged:
+		return tableStatusUnstaged, true, nil
+	default:
+		return tableStatusClean, true, nil
+	}
+}
+
+// rootTableHash returns the hash of the table |tableName| in |root|, or false if it doesn't exist.
+func rootTableHash(ctx context.Context, root *doltdb.RootValue, tableName string) (hash.Hash, bool, error) {
+	_, name, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil || !ok {
+		return hash.Hash{}, false, err
+	}
+	return root.GetTableHash(ctx, name)
+}
+
+// String implements the Stringer interface.
+func (t *TableStatus) String() string {
+	return fmt.Sprintf("DOLT_TABLE_STATUS(%s)", t.Child.String())
+}
+
+// FunctionName implements the FunctionExpression interface
+func (t *TableStatus) FunctionName() string {
+	return TableStatusFuncName
+}
+
+// Description implements the FunctionExpression interface
+func (t *TableStatus) Description() string {
+	return "returns whether a table has staged or unstaged changes"
+}
+
+// IsNullable implements the Expression interface.
+func (t *TableStatus) IsNullable() bool {
+	return true
+}
+
+// Type implements the Expression interface.
+func (t *TableStatus) Type() sql.Type {
+	return types.Text
+}
+
+// WithChildren implements the Expression interface.
+func (t *TableStatus) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(t, len(children), 1)
+	}
+	return NewTableStatus(children[0]), nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/vector"
+)
+
+const VecDistanceFuncName = "vec_distance"
+
+// VecDistance is the function vec_distance(a, b), which returns the Euclidean distance between two embeddings.
+// Embeddings are JSON arrays of numbers, or BLOBs of packed little-endian float32s.
+// Queries ordered by the distance of a column with a vector index to an embedding, and limited, search the index
+// rather than the whole table; see sqle.VectorSearchRule.
+type VecDistance struct {
+	expression.BinaryExpression
+}
+
+// NewVecDistance returns a VecDistance sql function.
+func NewVecDistance(left, right sql.Expression) sql.Expression {
+	return &VecDistance{expression.BinaryExpression{Left: left, Right: right}}
+}
+
+// Eval implements the sql.Expression interface.
+func (d VecDistance) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	left, err := d.Left.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	right, err := d.Right.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	a, err := vector.ParseEmbedding(ctx, left)
+	if err != nil {
+		return nil, err
+	}
+	b, err := vector.ParseEmbedding(ctx, right)
+	if err != nil {
+		return nil, err
+	}
+
+	return vector.Distance(a, b)
+}
+
+// String implements the sql.Expression interface.
+func (d VecDistance) String() string {
+	return fmt.Sprintf("VEC_DISTANCE(%s,%s)", d.Left.String(), d.Right.String())
+}
+
+// Type implements the sql.Expression interface.
+func (d VecDistance) Type() sql.Type {
+	return types.Float64
+}
+
+// WithChildren implements the sql.Expression interface.
+func (d VecDistance) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 2 {
+		return nil, sql.ErrInvalidChildrenNumber.New(d, len(children), 2)
+	}
+	return NewVecDistance(children[0], children[1]), nil
+}
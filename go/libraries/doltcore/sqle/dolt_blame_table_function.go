@@ -0,0 +1,673 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	dtypes "github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+var _ sql.TableFunction = (*BlameTableFunction)(nil)
+var _ sql.ExecSourceRel = (*BlameTableFunction)(nil)
+
+// BlameTableFunction is the table function dolt_blame(<table>, [<column>]), which annotates each row of a table with
+// the commit that last modified it. When a column is given, each row is annotated with the commit that last modified
+// its value in that column instead.
+//
+// Unlike the dolt_blame_ views, which are computed from every row of the table's dolt_diff_ table, the rows are
+// traced back through the commit graph: a row is passed from a commit to the first of its parents that has the same
+// row, and is blamed on the commit if no parent has it. Commits that didn't change the table pass all of their rows
+// to their parent without reading them, and commits that don't have any rows left to blame are never read, so only
+// the commits that changed the table since its rows were last modified are diffed.
+type BlameTableFunction struct {
+	ctx *sql.Context
+
+	tableNameExpr  sql.Expression
+	columnNameExpr sql.Expression
+	database       sql.Database
+	sqlSch         sql.Schema
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (bf *BlameTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &BlameTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (bf *BlameTableFunction) Database() sql.Database {
+	return bf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (bf *BlameTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	nbf := *bf
+	nbf.database = database
+	return &nbf, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (bf *BlameTableFunction) Name() string {
+	return "dolt_blame"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (bf *BlameTableFunction) Resolved() bool {
+	for _, expr := range bf.Expressions() {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+func (bf *BlameTableFunction) IsReadOnly() bool {
+	return true
+}
+
+// String implements the Stringer interface
+func (bf *BlameTableFunction) String() string {
+	exprs := bf.Expressions()
+	args := make([]string, len(exprs))
+	for i, expr := range exprs {
+		args[i] = expr.String()
+	}
+	return fmt.Sprintf("DOLT_BLAME(%s)", strings.Join(args, ", "))
+}
+
+// Schema implements the sql.Node interface. The schema is the primary key columns of the table, followed by the
+// commit that last modified each row.
+func (bf *BlameTableFunction) Schema() sql.Schema {
+	if !bf.Resolved() {
+		return nil
+	}
+
+	if bf.sqlSch == nil {
+		panic("schema hasn't been generated yet")
+	}
+
+	return bf.sqlSch
+}
+
+// Children implements the sql.Node interface.
+func (bf *BlameTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (bf *BlameTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return bf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (bf *BlameTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tableName, _, err := bf.evaluateArguments()
+	if err != nil {
+		return false
+	}
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(bf.database.Name(), tableName, "", sql.PrivilegeType_Select))
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (bf *BlameTableFunction) Expressions() []sql.Expression {
+	if bf.columnNameExpr != nil {
+		return []sql.Expression{bf.tableNameExpr, bf.columnNameExpr}
+	}
+	return []sql.Expression{bf.tableNameExpr}
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (bf *BlameTableFunction) WithExpressions(expression ...sql.Expression) (sql.Node, error) {
+	if len(expression) < 1 || len(expression) > 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New(bf.Name(), "1 or 2", len(expression))
+	}
+
+	for _, expr := range expression {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(bf.Name(), expr.String())
+		}
+		// prepared statements resolve functions beforehand, so above check fails
+		if _, ok := expr.(sql.FunctionExpression); ok {
+			return nil, ErrInvalidNonLiteralArgument.New(bf.Name(), expr.String())
+		}
+	}
+
+	nbf := *bf
+	nbf.tableNameExpr = expression[0]
+	nbf.columnNameExpr = nil
+	if len(expression) == 2 {
+		nbf.columnNameExpr = expression[1]
+	}
+
+	for _, expr := range nbf.Expressions() {
+		if !types.IsText(expr.Type()) {
+			return nil, sql.ErrInvalidArgumentDetails.New(nbf.Name(), expr.String())
+		}
+	}
+
+	tableName, columnName, err := nbf.evaluateArguments()
+	if err != nil {
+		return nil, err
+	}
+	_, sch, _, err := nbf.headTable(nbf.ctx, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+	nbf.sqlSch = blameSchema(sch)
+
+	return &nbf, nil
+}
+
+// blameSchema returns the schema of the blame of a table with the schema |sch|.
+func blameSchema(sch schema.Schema) sql.Schema {
+	var sqlSch sql.Schema
+	for _, col := range sch.GetPKCols().GetColumns() {
+		sqlSch = append(sqlSch, &sql.Column{Name: col.Name, Type: col.TypeInfo.ToSqlType(), Nullable: false})
+	}
+	return append(sqlSch,
+		&sql.Column{Name: "commit", Type: types.Text, Nullable: false},
+		&sql.Column{Name: "commit_date", Type: types.Datetime, Nullable: false},
+		&sql.Column{Name: "committer", Type: types.Text, Nullable: false},
+		&sql.Column{Name: "email", Type: types.Text, Nullable: false},
+		&sql.Column{Name: "message", Type: types.LongText, Nullable: false},
+	)
+}
+
+// evaluateArguments returns the table name and the column name, which is empty if the rows are blamed.
+func (bf *BlameTableFunction) evaluateArguments() (string, string, error) {
+	tableNameVal, err := bf.tableNameExpr.Eval(bf.ctx, nil)
+	if err != nil {
+		return "", "", err
+	}
+	tableName, ok := tableNameVal.(string)
+	if !ok {
+		return "", "", ErrInvalidTableName.New(bf.tableNameExpr.String())
+	}
+
+	if bf.columnNameExpr == nil {
+		return tableName, "", nil
+	}
+	columnNameVal, err := bf.columnNameExpr.Eval(bf.ctx, nil)
+	if err != nil {
+		return "", "", err
+	}
+	columnName, ok := columnNameVal.(string)
+	if !ok {
+		return "", "", sql.ErrInvalidArgumentDetails.New(bf.Name(), bf.columnNameExpr.String())
+	}
+
+	return tableName, columnName, nil
+}
+
+// headTable returns the HEAD commit of the database, along with the name and schema the table has in it.
+func (bf *BlameTableFunction) headTable(ctx *sql.Context, tableName, columnName string) (*doltdb.Commit, schema.Schema, string, error) {
+	sqledb, ok := bf.database.(dsess.SqlDatabase)
+	if !ok {
+		return nil, nil, "", fmt.Errorf("unexpected database type: %T", bf.database)
+	}
+
+	head, err := dsess.DSessFromSess(ctx.Session).GetHeadCommit(ctx, sqledb.RevisionQualifiedName())
+	if err != nil {
+		return nil, nil, "", err
+	}
+	headRoot, err := head.GetRootValue(ctx)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if !dtypes.IsFormat_DOLT(headRoot.VRW().Format()) {
+		return nil, nil, "", fmt.Errorf("%s is not supported for this storage format", bf.Name())
+	}
+
+	tbl, tableName, ok, err := headRoot.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, nil, "", err
+	} else if !ok {
+		return nil, nil, "", sql.ErrTableNotFound.New(tableName)
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if schema.IsKeyless(sch) {
+		return nil, nil, "", fmt.Errorf("%s requires a table with a primary key, but table '%s' has none", bf.Name(), tableName)
+	}
+	if columnName != "" {
+		if _, ok := sch.GetAllCols().GetByNameCaseInsensitive(columnName); !ok {
+			return nil, nil, "", sql.ErrTableColumnNotFound.New(tableName, columnName)
+		}
+	}
+
+	return head, sch, tableName, nil
+}
+
+// RowIter implements the sql.Node interface
+func (bf *BlameTableFunction) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	tableName, columnName, err := bf.evaluateArguments()
+	if err != nil {
+		return nil, err
+	}
+	head, _, tableName, err := bf.headTable(ctx, tableName, columnName)
+	if err != nil {
+		return nil, err
+	}
+
+	headRoot, err := head.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tbl, _, err := headRoot.GetTable(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m := durable.ProllyMapFromIndex(rowData)
+
+	headHash, err := head.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	keys := make(blameKeys)
+	err = iterAllKeys(ctx, m, func(k val.Tuple) error {
+		keys[string(k)] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	br := &blameReader{
+		tableName:  tableName,
+		columnName: columnName,
+		pending:    make(map[hash.Hash]blameKeys),
+		blamed:     make(map[string]hash.Hash),
+	}
+	if len(keys) > 0 {
+		br.pending[headHash] = keys
+	}
+	ddb := bf.database.(dsess.SqlDatabase).DbData().Ddb
+	if err = br.blame(ctx, ddb, headHash); err != nil {
+		return nil, err
+	}
+
+	commits := make(map[hash.Hash]sql.Row)
+	kd := m.KeyDesc()
+	var rows []sql.Row
+	err = iterAllKeys(ctx, m, func(k val.Tuple) error {
+		h := br.blamed[string(k)]
+		commitCols, ok := commits[h]
+		if !ok {
+			commitCols, err = blameCommitColumns(ctx, ddb, h)
+			if err != nil {
+				return err
+			}
+			commits[h] = commitCols
+		}
+
+		row := make(sql.Row, 0, kd.Count()+len(commitCols))
+		for i := 0; i < kd.Count(); i++ {
+			v, err := index.GetField(ctx, kd, i, k, m.NodeStore())
+			if err != nil {
+				return err
+			}
+			row = append(row, v)
+		}
+		rows = append(rows, append(row, commitCols...))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// iterAllKeys calls |cb| with the key of each row of |m|, in order.
+func iterAllKeys(ctx context.Context, m prolly.Map, cb func(k val.Tuple) error) error {
+	itr, err := m.IterAll(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		k, _, err := itr.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err = cb(k); err != nil {
+			return err
+		}
+	}
+}
+
+// blameCommitColumns returns the columns of the blame of a row that describe the commit |h| it was blamed on.
+func blameCommitColumns(ctx *sql.Context, ddb *doltdb.DoltDB, h hash.Hash) (sql.Row, error) {
+	cm, err := ddb.ReadCommit(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sql.Row{h.String(), meta.Time(), meta.Name, meta.Email, meta.Description}, nil
+}
+
+// blameKeys is a set of the keys of a table's rows, which are the same in every commit they're passed through.
+type blameKeys map[string]struct{}
+
+// blameReader traces the rows of a table back through the commit graph to the commits that last modified them.
+type blameReader struct {
+	tableName  string
+	columnName string
+
+	// pending holds the keys of the rows that are yet to be blamed, by the commit they've been traced back to
+	pending map[hash.Hash]blameKeys
+	// blamed holds the commit each row was blamed on, by key
+	blamed map[string]hash.Hash
+}
+
+// blame traces all pending rows back from |headHash|. Commits are visited in topological order, so every row a commit
+// will be passed from its children has been passed to it when it's visited.
+func (br *blameReader) blame(ctx *sql.Context, ddb *doltdb.DoltDB, headHash hash.Hash) error {
+	itr, err := commitwalk.GetTopologicalOrderIterator(ctx, ddb, []hash.Hash{headHash}, nil)
+	if err != nil {
+		return err
+	}
+
+	for len(br.pending) > 0 {
+		h, cm, err := itr.Next(ctx)
+		if err != nil {
+			return err
+		}
+		keys, ok := br.pending[h]
+		if !ok {
+			continue
+		}
+		delete(br.pending, h)
+
+		root, err := cm.GetRootValue(ctx)
+		if err != nil {
+			return err
+		}
+		parents, err := cm.ParentHashes(ctx)
+		if err != nil {
+			return err
+		}
+		for _, ph := range parents {
+			if len(keys) == 0 {
+				break
+			}
+			parent, err := ddb.ReadCommit(ctx, ph)
+			if err != nil {
+				return err
+			}
+			parentRoot, err := parent.GetRootValue(ctx)
+			if err != nil {
+				return err
+			}
+			unchanged, err := br.unchangedKeys(ctx, root, parentRoot, keys)
+			if err != nil {
+				return err
+			}
+			if len(unchanged) == 0 {
+				continue
+			}
+			if len(unchanged) == len(keys) {
+				// every row is passed to the parent, and |unchanged| may be |keys| itself
+				keys = nil
+			} else {
+				for k := range unchanged {
+					delete(keys, k)
+				}
+			}
+			if passed, ok := br.pending[ph]; ok {
+				for k := range unchanged {
+					passed[k] = struct{}{}
+				}
+			} else {
+				br.pending[ph] = unchanged
+			}
+		}
+
+		for k := range keys {
+			br.blamed[k] = h
+		}
+	}
+
+	return nil
+}
+
+// unchangedKeys returns the keys in |keys| whose rows, or cells when a column is blamed, are the same in |root| and
+// |parentRoot|. If the table is the same in both, |keys| itself is returned.
+func (br *blameReader) unchangedKeys(ctx *sql.Context, root, parentRoot *doltdb.RootValue, keys blameKeys) (blameKeys, error) {
+	tblHash, _, err := root.GetTableHash(ctx, br.tableName)
+	if err != nil {
+		return nil, err
+	}
+	parentTblHash, ok, err := parentRoot.GetTableHash(ctx, br.tableName)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if tblHash == parentTblHash {
+		// the commit didn't change the table
+		return keys, nil
+	}
+
+	tbl, _, err := root.GetTable(ctx, br.tableName)
+	if err != nil {
+		return nil, err
+	}
+	parentTbl, _, err := parentRoot.GetTable(ctx, br.tableName)
+	if err != nil {
+		return nil, err
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parentSch, err := parentTbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schema.IsKeyless(parentSch) {
+		return nil, nil
+	}
+	kd, _ := sch.GetMapDescriptors()
+	parentKd, _ := parentSch.GetMapDescriptors()
+	if !kd.Equals(parentKd) {
+		// the table's primary key changed, so none of its rows are the same
+		return nil, nil
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parentRowData, err := parentTbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m := durable.ProllyMapFromIndex(rowData)
+	parentM := durable.ProllyMapFromIndex(parentRowData)
+
+	if schema.SchemasAreEqual(sch, parentSch) {
+		return br.unchangedKeysFromDiff(ctx, sch, parentM, m, keys)
+	}
+	return br.unchangedKeysFromValues(ctx, sch, parentSch, m, parentM, keys)
+}
+
+// unchangedKeysFromDiff returns the keys in |keys| that weren't changed between |parentM| and |m|, which have the same
+// schema |sch|, by diffing them.
+func (br *blameReader) unchangedKeysFromDiff(ctx *sql.Context, sch schema.Schema, parentM, m prolly.Map, keys blameKeys) (blameKeys, error) {
+	valIdx := -1
+	if br.columnName != "" {
+		if col, ok := sch.GetAllCols().GetByNameCaseInsensitive(br.columnName); ok {
+			if idx, ok := sch.GetNonPKCols().TagToIdx[col.Tag]; ok {
+				valIdx = idx
+			}
+		}
+	}
+	_, vd := sch.GetMapDescriptors()
+
+	unchanged := make(blameKeys, len(keys))
+	for k := range keys {
+		unchanged[k] = struct{}{}
+	}
+	err := prolly.DiffMaps(ctx, parentM, m, func(ctx context.Context, diff tree.Diff) error {
+		k := string(diff.Key)
+		if _, ok := unchanged[k]; !ok {
+			return nil
+		}
+		if diff.Type == tree.ModifiedDiff && br.columnName != "" {
+			// only a change to the blamed column modifies its cell, and primary key columns can't be modified
+			if valIdx < 0 || string(vd.GetField(valIdx, val.Tuple(diff.From))) == string(vd.GetField(valIdx, val.Tuple(diff.To))) {
+				return nil
+			}
+		}
+		delete(unchanged, k)
+		return nil
+	})
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return unchanged, nil
+}
+
+// unchangedKeysFromValues returns the keys in |keys| whose rows have the same values in |m| and |parentM|, whose
+// schemas |sch| and |parentSch| differ, by comparing the values of their columns by name. A column that's in only one
+// of the schemas is unchanged only if it's NULL.
+func (br *blameReader) unchangedKeysFromValues(ctx *sql.Context, sch, parentSch schema.Schema, m, parentM prolly.Map, keys blameKeys) (blameKeys, error) {
+	var colNames []string
+	if br.columnName != "" {
+		colNames = []string{br.columnName}
+	} else {
+		seen := make(map[string]bool)
+		for _, s := range []schema.Schema{sch, parentSch} {
+			for _, col := range s.GetNonPKCols().GetColumns() {
+				name := strings.ToLower(col.Name)
+				if !seen[name] {
+					seen[name] = true
+					colNames = append(colNames, name)
+				}
+			}
+		}
+	}
+
+	unchanged := make(blameKeys)
+	for k := range keys {
+		values, ok, err := blameCellValues(ctx, sch, m, val.Tuple(k), colNames)
+		if err != nil {
+			return nil, err
+		}
+		parentValues, parentOk, err := blameCellValues(ctx, parentSch, parentM, val.Tuple(k), colNames)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || !parentOk {
+			continue
+		}
+
+		same := true
+		for i := range values {
+			if (values[i] == nil) != (parentValues[i] == nil) || (values[i] != nil && *values[i] != *parentValues[i]) {
+				same = false
+				break
+			}
+		}
+		if same {
+			unchanged[k] = struct{}{}
+		}
+	}
+	return unchanged, nil
+}
+
+// blameCellValues returns the values of the columns named |colNames| of the row with the key |k| in |m|, formatted as
+// strings, and whether the row exists. Columns that aren't in |sch|, and NULL values, are returned as nil.
+func blameCellValues(ctx *sql.Context, sch schema.Schema, m prolly.Map, k val.Tuple, colNames []string) ([]*string, bool, error) {
+	kd, vd := sch.GetMapDescriptors()
+	pkCols := sch.GetPKCols()
+	values := make([]*string, len(colNames))
+	var exists bool
+	err := m.Get(ctx, k, func(k, v val.Tuple) error {
+		if k == nil {
+			return nil
+		}
+		exists = true
+
+		for i, name := range colNames {
+			col, ok := sch.GetAllCols().GetByNameCaseInsensitive(name)
+			if !ok {
+				continue
+			}
+
+			var value interface{}
+			var err error
+			if idx, ok := pkCols.TagToIdx[col.Tag]; ok {
+				value, err = index.GetField(ctx, kd, idx, k, m.NodeStore())
+			} else {
+				value, err = index.GetField(ctx, vd, sch.GetNonPKCols().TagToIdx[col.Tag], v, m.NodeStore())
+			}
+			if err != nil {
+				return err
+			}
+			if value == nil {
+				continue
+			}
+
+			sqlVal, err := col.TypeInfo.ToSqlType().SQL(ctx, nil, value)
+			if err != nil {
+				return err
+			}
+			s := sqlVal.ToString()
+			values[i] = &s
+		}
+		return nil
+	})
+	return values, exists, err
+}
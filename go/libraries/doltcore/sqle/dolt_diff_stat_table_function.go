@@ -29,6 +29,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
 )
 
 var _ sql.TableFunction = (*DiffStatTableFunction)(nil)
@@ -41,9 +42,19 @@ type DiffStatTableFunction struct {
 	toCommitExpr   sql.Expression
 	dotCommitExpr  sql.Expression
 	tableNameExpr  sql.Expression
+	optionExprs    []sql.Expression
 	database       sql.Database
+
+	// estimate is set by the --estimate option, and makes the stat an estimate computed from the cardinality of the
+	// subtrees that differ between the two tables rather than from every changed row
+	estimate bool
 }
 
+const (
+	diffStatEstimateOption = "--estimate"
+	diffStatExactOption    = "--exact"
+)
+
 var diffStatTableSchema = sql.Schema{
 	&sql.Column{Name: "table_name", Type: types.LongText, Nullable: false},
 	&sql.Column{Name: "rows_unmodified", Type: types.Int64, Nullable: true},
@@ -112,6 +123,13 @@ func (ds *DiffStatTableFunction) IsReadOnly() bool {
 
 // String implements the Stringer interface
 func (ds *DiffStatTableFunction) String() string {
+	if len(ds.optionExprs) > 0 {
+		args := make([]string, len(ds.Expressions()))
+		for i, expr := range ds.Expressions() {
+			args[i] = expr.String()
+		}
+		return fmt.Sprintf("DOLT_DIFF_STAT(%s)", strings.Join(args, ", "))
+	}
 	if ds.dotCommitExpr != nil {
 		if ds.tableNameExpr != nil {
 			return fmt.Sprintf("DOLT_DIFF_STAT(%s, %s)", ds.dotCommitExpr.String(), ds.tableNameExpr.String())
@@ -187,7 +205,7 @@ func (ds *DiffStatTableFunction) Expressions() []sql.Expression {
 	if ds.tableNameExpr != nil {
 		exprs = append(exprs, ds.tableNameExpr)
 	}
-	return exprs
+	return append(exprs, ds.optionExprs...)
 }
 
 // WithExpressions implements the sql.Expressioner interface.
@@ -207,6 +225,29 @@ func (ds *DiffStatTableFunction) WithExpressions(expression ...sql.Expression) (
 	}
 
 	newDstf := *ds
+	newDstf.optionExprs = nil
+	newDstf.estimate = false
+	for len(expression) > 1 {
+		option, ok, err := diffStatOption(ds.ctx, expression[len(expression)-1])
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		switch option {
+		case diffStatEstimateOption:
+			newDstf.estimate = true
+		case diffStatExactOption:
+		default:
+			return nil, sql.ErrInvalidArgumentDetails.New(newDstf.Name(), option)
+		}
+		newDstf.optionExprs = append([]sql.Expression{expression[len(expression)-1]}, newDstf.optionExprs...)
+		expression = expression[:len(expression)-1]
+	}
+	if newDstf.estimate && len(newDstf.optionExprs) > 1 {
+		return nil, fmt.Errorf("%s: the %s and %s options cannot be used together", newDstf.Name(), diffStatEstimateOption, diffStatExactOption)
+	}
+
 	if strings.Contains(expression[0].String(), "..") {
 		if len(expression) < 1 || len(expression) > 2 {
 			return nil, sql.ErrInvalidArgumentNumber.New(newDstf.Name(), "1 or 2", len(expression))
@@ -249,6 +290,22 @@ func (ds *DiffStatTableFunction) WithExpressions(expression ...sql.Expression) (
 	return &newDstf, nil
 }
 
+// diffStatOption returns the option given by |expr|, if it's a string starting with "--".
+func diffStatOption(ctx *sql.Context, expr sql.Expression) (string, bool, error) {
+	if !types.IsText(expr.Type()) {
+		return "", false, nil
+	}
+	val, err := expr.Eval(ctx, nil)
+	if err != nil {
+		return "", false, err
+	}
+	option, ok := val.(string)
+	if !ok || !strings.HasPrefix(option, "--") {
+		return "", false, nil
+	}
+	return strings.ToLower(option), true, nil
+}
+
 // RowIter implements the sql.Node interface
 func (ds *DiffStatTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 	fromCommitVal, toCommitVal, dotCommitVal, tableName, err := ds.evaluateArguments()
@@ -266,7 +323,7 @@ func (ds *DiffStatTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.Row
 		return nil, err
 	}
 
-	deltas, err := diff.GetTableDeltas(ctx, fromRefDetails.root, toRefDetails.root)
+	deltas, err := getTableDeltas(ctx, fromRefDetails.root, toRefDetails.root)
 	if err != nil {
 		return nil, err
 	}
@@ -274,7 +331,7 @@ func (ds *DiffStatTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.Row
 	// If tableNameExpr defined, return a single table diff stat result
 	if ds.tableNameExpr != nil {
 		delta := findMatchingDelta(deltas, tableName)
-		diffStat, hasDiff, err := getDiffStatNodeFromDelta(ctx, delta, fromRefDetails.root, toRefDetails.root, tableName)
+		diffStat, hasDiff, err := getDiffStatNodeFromDelta(ctx, delta, fromRefDetails.root, toRefDetails.root, tableName, ds.estimate)
 		if err != nil {
 			return nil, err
 		}
@@ -290,7 +347,7 @@ func (ds *DiffStatTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.Row
 		if tblName == "" {
 			tblName = delta.FromName
 		}
-		diffStat, hasDiff, err := getDiffStatNodeFromDelta(ctx, delta, fromRefDetails.root, toRefDetails.root, tblName)
+		diffStat, hasDiff, err := getDiffStatNodeFromDelta(ctx, delta, fromRefDetails.root, toRefDetails.root, tblName, ds.estimate)
 		if err != nil {
 			if errors.Is(err, diff.ErrPrimaryKeySetChanged) {
 				ctx.Warn(dtables.PrimaryKeyChangeWarningCode, fmt.Sprintf("stat for table %s cannot be determined. Primary key set changed.", tblName))
@@ -348,8 +405,9 @@ func (ds *DiffStatTableFunction) evaluateArguments() (interface{}, interface{},
 }
 
 // getDiffStatNodeFromDelta returns diffStatNode object and whether there is data diff or not. It gets tables
-// from roots and diff stat if there is a valid table exists in both fromRoot and toRoot.
-func getDiffStatNodeFromDelta(ctx *sql.Context, delta diff.TableDelta, fromRoot, toRoot *doltdb.RootValue, tableName string) (diffStatNode, bool, error) {
+// from roots and diff stat if there is a valid table exists in both fromRoot and toRoot. If |estimate| is set, the
+// stat is estimated when the table supports it.
+func getDiffStatNodeFromDelta(ctx *sql.Context, delta diff.TableDelta, fromRoot, toRoot *doltdb.RootValue, tableName string, estimate bool) (diffStatNode, bool, error) {
 	var oldColLen int
 	var newColLen int
 	fromTable, _, fromTableExists, err := fromRoot.GetTableInsensitive(ctx, tableName)
@@ -387,12 +445,37 @@ func getDiffStatNodeFromDelta(ctx *sql.Context, delta diff.TableDelta, fromRoot,
 		return diffStatNode{}, false, nil
 	}
 
+	if estimate {
+		diffStat, hasDiff, ok, err := estimateDiffStat(ctx, delta)
+		if err != nil {
+			return diffStatNode{}, false, err
+		}
+		if ok {
+			return diffStatNode{tableName, diffStat, oldColLen, newColLen, false, true}, hasDiff, nil
+		}
+	}
+
 	diffStat, hasDiff, keyless, err := getDiffStat(ctx, delta)
 	if err != nil {
 		return diffStatNode{}, false, err
 	}
 
-	return diffStatNode{tableName, diffStat, oldColLen, newColLen, keyless}, hasDiff, nil
+	return diffStatNode{tableName, diffStat, oldColLen, newColLen, keyless, false}, hasDiff, nil
+}
+
+// estimateDiffStat returns an estimated diff.DiffStatProgress object, whether there is a data diff or not, and whether
+// the table's stat could be estimated at all.
+func estimateDiffStat(ctx *sql.Context, td diff.TableDelta) (diff.DiffStatProgress, bool, bool, error) {
+	acc, _, ok, err := diff.EstimateStatForTableDelta(ctx, td, tree.DefaultDiffEstimateBudget)
+	if err != nil || !ok {
+		return diff.DiffStatProgress{}, false, ok, err
+	}
+
+	if (acc.Adds+acc.Removes+acc.Changes) == 0 && (acc.OldCellSize-acc.NewCellSize) == 0 {
+		return diff.DiffStatProgress{}, false, true, nil
+	}
+
+	return acc, true, true, nil
 }
 
 // getDiffStat returns diff.DiffStatProgress object and whether there is a data diff or not.
@@ -473,6 +556,8 @@ type diffStatNode struct {
 	oldColLen int
 	newColLen int
 	keyless   bool
+	// estimated is set when the stat was estimated, in which case cell changes were not counted
+	estimated bool
 }
 
 func NewDiffStatTableFunctionRowIter(ds []diffStatNode) sql.RowIter {
@@ -492,7 +577,11 @@ func (d *diffStatTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
 	}
 
 	ds := d.diffStats[d.diffIdx]
-	return getRowFromDiffStat(ds.tblName, ds.diffStat, ds.newColLen, ds.oldColLen, ds.keyless), nil
+	row := getRowFromDiffStat(ds.tblName, ds.diffStat, ds.newColLen, ds.oldColLen, ds.keyless)
+	if ds.estimated {
+		row[7] = nil // cells_modified
+	}
+	return row, nil
 }
 
 func (d *diffStatTableFunctionRowIter) Close(context *sql.Context) error {
@@ -257,7 +257,7 @@ func (ds *DiffSummaryTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.
 		return nil, err
 	}
 
-	deltas, err := diff.GetTableDeltas(ctx, fromDetails.root, toDetails.root)
+	deltas, err := getTableDeltas(ctx, fromDetails.root, toDetails.root)
 	if err != nil {
 		return nil, err
 	}
@@ -170,7 +170,12 @@ func (dtf *DiffTableFunction) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter,
 	ddb := sqledb.DbData().Ddb
 	dp := dtables.NewDiffPartition(dtf.tableDelta.ToTable, dtf.tableDelta.FromTable, toCommitStr, fromCommitStr, dtf.toDate, dtf.fromDate, dtf.tableDelta.ToSch, dtf.tableDelta.FromSch)
 
-	return dtables.NewDiffPartitionRowIter(*dp, ddb, dtf.joiner), nil
+	masker, err := dsess.NewColumnMasker(ctx, ddb, dtf.Schema(), dsess.DiffTableColumnTag(dtf.tableDelta.FromSch, dtf.tableDelta.ToSch))
+	if err != nil {
+		return nil, err
+	}
+
+	return masker.RowIter(dtables.NewDiffPartitionRowIter(*dp, ddb, dtf.joiner)), nil
 }
 
 // findMatchingDelta returns the best matching table delta for the table name
@@ -193,6 +198,25 @@ func findMatchingDelta(deltas []diff.TableDelta, tableName string) diff.TableDel
 	return diff.TableDelta{}
 }
 
+// getTableDeltas returns the deltas of the tables that changed between |fromRoot| and |toRoot|, detecting renamed
+// tables as configured by the session's @@dolt_diff_renames and @@dolt_diff_rename_similarity.
+func getTableDeltas(ctx *sql.Context, fromRoot, toRoot *doltdb.RootValue) ([]diff.TableDelta, error) {
+	renames, err := dsess.GetBooleanSystemVar(ctx, dsess.DiffRenames)
+	if err != nil {
+		return nil, err
+	}
+	similarity, err := ctx.GetSessionVariable(ctx, dsess.DiffRenameSimilarity)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := diff.RenameOptions{Disabled: !renames}
+	if similarity, ok := similarity.(int64); ok {
+		opts.Similarity = int(similarity)
+	}
+	return diff.GetTableDeltasWithRenames(ctx, fromRoot, toRoot, opts)
+}
+
 type refDetails struct {
 	root       *doltdb.RootValue
 	hashStr    string
@@ -476,7 +500,7 @@ func (dtf *DiffTableFunction) cacheTableDelta(ctx *sql.Context, fromCommitVal, t
 	}
 
 	// TODO: it would be nice to limit this to just the table under consideration, not all tables with a diff
-	deltas, err := diff.GetTableDeltas(ctx, fromRefDetails.root, toRefDetails.root)
+	deltas, err := getTableDeltas(ctx, fromRefDetails.root, toRefDetails.root)
 	if err != nil {
 		return diff.TableDelta{}, err
 	}
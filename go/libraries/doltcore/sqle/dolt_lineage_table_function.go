@@ -0,0 +1,504 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/pool"
+	dtypes "github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+var _ sql.TableFunction = (*LineageTableFunction)(nil)
+var _ sql.ExecSourceRel = (*LineageTableFunction)(nil)
+
+// LineageTableFunction is the table function dolt_lineage(<table>, <pk>..., <column>), which lists the commits that
+// changed a single cell of a table, newest first, along with the cell's value before and after each change.
+//
+// Unlike the dolt_history_ tables, which read every row of the table at every commit, the cell is looked up by its
+// key, and only in the commits that changed the table: the value of the cell is cached by the hash of the table it
+// was read from, so any commit that didn't touch the table reuses the value read from its parent.
+type LineageTableFunction struct {
+	ctx *sql.Context
+
+	tableNameExpr  sql.Expression
+	pkExprs        []sql.Expression
+	columnNameExpr sql.Expression
+	database       sql.Database
+}
+
+var lineageTableSchema = sql.Schema{
+	&sql.Column{Name: "commit_hash", Type: types.Text, Nullable: false},
+	&sql.Column{Name: "committer", Type: types.Text, Nullable: false},
+	&sql.Column{Name: "email", Type: types.Text, Nullable: false},
+	&sql.Column{Name: "date", Type: types.Datetime, Nullable: false},
+	&sql.Column{Name: "message", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "diff_type", Type: types.Text, Nullable: false},
+	&sql.Column{Name: "from_value", Type: types.LongText, Nullable: true},
+	&sql.Column{Name: "to_value", Type: types.LongText, Nullable: true},
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (lf *LineageTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &LineageTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (lf *LineageTableFunction) Database() sql.Database {
+	return lf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (lf *LineageTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	nlf := *lf
+	nlf.database = database
+	return &nlf, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (lf *LineageTableFunction) Name() string {
+	return "dolt_lineage"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (lf *LineageTableFunction) Resolved() bool {
+	for _, expr := range lf.Expressions() {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+func (lf *LineageTableFunction) IsReadOnly() bool {
+	return true
+}
+
+// String implements the Stringer interface
+func (lf *LineageTableFunction) String() string {
+	exprs := lf.Expressions()
+	args := make([]string, len(exprs))
+	for i, expr := range exprs {
+		args[i] = expr.String()
+	}
+	return fmt.Sprintf("DOLT_LINEAGE(%s)", strings.Join(args, ", "))
+}
+
+// Schema implements the sql.Node interface.
+func (lf *LineageTableFunction) Schema() sql.Schema {
+	return lineageTableSchema
+}
+
+// Children implements the sql.Node interface.
+func (lf *LineageTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (lf *LineageTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return lf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (lf *LineageTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tableName, _, _, err := lf.evaluateArguments()
+	if err != nil {
+		return false
+	}
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(lf.database.Name(), tableName, "", sql.PrivilegeType_Select))
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (lf *LineageTableFunction) Expressions() []sql.Expression {
+	exprs := []sql.Expression{lf.tableNameExpr}
+	exprs = append(exprs, lf.pkExprs...)
+	return append(exprs, lf.columnNameExpr)
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (lf *LineageTableFunction) WithExpressions(expression ...sql.Expression) (sql.Node, error) {
+	if len(expression) < 3 {
+		return nil, sql.ErrInvalidArgumentNumber.New(lf.Name(), "3 or more", len(expression))
+	}
+
+	for _, expr := range expression {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(lf.Name(), expr.String())
+		}
+		// prepared statements resolve functions beforehand, so above check fails
+		if _, ok := expr.(sql.FunctionExpression); ok {
+			return nil, ErrInvalidNonLiteralArgument.New(lf.Name(), expr.String())
+		}
+	}
+
+	nlf := *lf
+	nlf.tableNameExpr = expression[0]
+	nlf.pkExprs = expression[1 : len(expression)-1]
+	nlf.columnNameExpr = expression[len(expression)-1]
+
+	if !types.IsText(nlf.tableNameExpr.Type()) {
+		return nil, sql.ErrInvalidArgumentDetails.New(nlf.Name(), nlf.tableNameExpr.String())
+	}
+	if !types.IsText(nlf.columnNameExpr.Type()) {
+		return nil, sql.ErrInvalidArgumentDetails.New(nlf.Name(), nlf.columnNameExpr.String())
+	}
+
+	return &nlf, nil
+}
+
+// evaluateArguments returns the table name, the primary key values and the column name of the cell.
+func (lf *LineageTableFunction) evaluateArguments() (string, []interface{}, string, error) {
+	tableNameVal, err := lf.tableNameExpr.Eval(lf.ctx, nil)
+	if err != nil {
+		return "", nil, "", err
+	}
+	tableName, ok := tableNameVal.(string)
+	if !ok {
+		return "", nil, "", ErrInvalidTableName.New(lf.tableNameExpr.String())
+	}
+
+	pk := make([]interface{}, len(lf.pkExprs))
+	for i, expr := range lf.pkExprs {
+		pk[i], err = expr.Eval(lf.ctx, nil)
+		if err != nil {
+			return "", nil, "", err
+		}
+	}
+
+	columnNameVal, err := lf.columnNameExpr.Eval(lf.ctx, nil)
+	if err != nil {
+		return "", nil, "", err
+	}
+	columnName, ok := columnNameVal.(string)
+	if !ok {
+		return "", nil, "", sql.ErrInvalidArgumentDetails.New(lf.Name(), lf.columnNameExpr.String())
+	}
+
+	return tableName, pk, columnName, nil
+}
+
+// RowIter implements the sql.Node interface
+func (lf *LineageTableFunction) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	tableName, pk, columnName, err := lf.evaluateArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	sqledb, ok := lf.database.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", lf.database)
+	}
+	dbName := sqledb.RevisionQualifiedName()
+	ddb := sqledb.DbData().Ddb
+
+	head, err := dsess.DSessFromSess(ctx.Session).GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	headRoot, err := head.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !dtypes.IsFormat_DOLT(headRoot.VRW().Format()) {
+		return nil, fmt.Errorf("%s is not supported for this storage format", lf.Name())
+	}
+
+	tbl, tableName, ok, err := headRoot.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schema.IsKeyless(sch) {
+		return nil, fmt.Errorf("%s requires a table with a primary key, but table '%s' has none", lf.Name(), tableName)
+	}
+	if sch.GetPKCols().Size() != len(pk) {
+		return nil, fmt.Errorf("table '%s' has %d primary key columns, but %d primary key values were given", tableName, sch.GetPKCols().Size(), len(pk))
+	}
+	if _, ok := sch.GetAllCols().GetByNameCaseInsensitive(columnName); !ok {
+		return nil, sql.ErrTableColumnNotFound.New(tableName, columnName)
+	}
+
+	lr := &lineageReader{
+		tableName:  tableName,
+		columnName: columnName,
+		pk:         pk,
+		byCommit:   make(map[hash.Hash]lineageCell),
+		byTable:    make(map[hash.Hash]lineageCell),
+		pool:       pool.NewBuffPool(),
+	}
+
+	headHash, err := head.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	itr, err := commitwalk.GetTopologicalOrderIterator(ctx, ddb, []hash.Hash{headHash}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []sql.Row
+	for {
+		h, cm, err := itr.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		row, err := lr.lineageRow(ctx, ddb, h, cm)
+		if err != nil {
+			return nil, err
+		}
+		if row != nil {
+			rows = append(rows, row)
+		}
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// lineageCell is the state of a single cell of a table at some commit.
+type lineageCell struct {
+	// exists is whether the cell's row exists
+	exists bool
+	// value is the cell's value formatted as a string, or nil if it's NULL or its row doesn't exist
+	value *string
+}
+
+func (c lineageCell) equals(other lineageCell) bool {
+	if c.exists != other.exists || (c.value == nil) != (other.value == nil) {
+		return false
+	}
+	return c.value == nil || *c.value == *other.value
+}
+
+func (c lineageCell) sqlValue() interface{} {
+	if c.value == nil {
+		return nil
+	}
+	return *c.value
+}
+
+// lineageReader reads the value of a cell at each commit. Values are cached by commit, and by the hash of the table
+// they were read from.
+type lineageReader struct {
+	tableName  string
+	columnName string
+	pk         []interface{}
+
+	byCommit map[hash.Hash]lineageCell
+	byTable  map[hash.Hash]lineageCell
+	pool     pool.BuffPool
+}
+
+// lineageRow returns the row for |cm| if it changed the cell, and nil otherwise. A commit changed the cell if the
+// cell's value differs from its value in every parent; a merge commit that took the value from one of its parents
+// didn't change it.
+func (lr *lineageReader) lineageRow(ctx *sql.Context, ddb *doltdb.DoltDB, h hash.Hash, cm *doltdb.Commit) (sql.Row, error) {
+	cell, err := lr.cellAt(ctx, h, cm)
+	if err != nil {
+		return nil, err
+	}
+
+	parents, err := cm.ParentHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var from lineageCell
+	for i, ph := range parents {
+		parentCell, ok := lr.byCommit[ph]
+		if !ok {
+			parent, err := ddb.ReadCommit(ctx, ph)
+			if err != nil {
+				return nil, err
+			}
+			parentCell, err = lr.cellAt(ctx, ph, parent)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if parentCell.equals(cell) {
+			return nil, nil
+		}
+		if i == 0 {
+			from = parentCell
+		}
+	}
+	if !cell.exists && !from.exists {
+		return nil, nil
+	}
+
+	diffType := "modified"
+	if !from.exists {
+		diffType = "added"
+	} else if !cell.exists {
+		diffType = "removed"
+	}
+
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sql.Row{
+		h.String(),
+		meta.Name,
+		meta.Email,
+		meta.Time(),
+		meta.Description,
+		diffType,
+		from.sqlValue(),
+		cell.sqlValue(),
+	}, nil
+}
+
+// cellAt returns the state of the cell at the commit |cm| with hash |h|.
+func (lr *lineageReader) cellAt(ctx *sql.Context, h hash.Hash, cm *doltdb.Commit) (lineageCell, error) {
+	if cell, ok := lr.byCommit[h]; ok {
+		return cell, nil
+	}
+
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return lineageCell{}, err
+	}
+	tblHash, ok, err := root.GetTableHash(ctx, lr.tableName)
+	if err != nil {
+		return lineageCell{}, err
+	}
+
+	var cell lineageCell
+	if ok {
+		var cached bool
+		if cell, cached = lr.byTable[tblHash]; !cached {
+			cell, err = lr.readCell(ctx, root)
+			if err != nil {
+				return lineageCell{}, err
+			}
+			lr.byTable[tblHash] = cell
+		}
+	}
+
+	lr.byCommit[h] = cell
+	return cell, nil
+}
+
+// readCell reads the cell from the table in |root| by looking up its row by key.
+func (lr *lineageReader) readCell(ctx *sql.Context, root *doltdb.RootValue) (lineageCell, error) {
+	tbl, ok, err := root.GetTable(ctx, lr.tableName)
+	if err != nil || !ok {
+		return lineageCell{}, err
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return lineageCell{}, err
+	}
+	pkCols := sch.GetPKCols()
+	if schema.IsKeyless(sch) || pkCols.Size() != len(lr.pk) {
+		// the table's primary key was different at this commit, so the cell didn't exist
+		return lineageCell{}, nil
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return lineageCell{}, err
+	}
+	m := durable.ProllyMapFromIndex(rowData)
+	kd, vd := sch.GetMapDescriptors()
+
+	kb := val.NewTupleBuilder(kd)
+	for i, col := range pkCols.GetColumns() {
+		v, _, err := col.TypeInfo.ToSqlType().Convert(lr.pk[i])
+		if err != nil {
+			// the primary key value can't be stored in the column as it was at this commit
+			return lineageCell{}, nil
+		}
+		err = index.PutField(ctx, m.NodeStore(), kb, i, v)
+		if err != nil {
+			return lineageCell{}, err
+		}
+	}
+	key := kb.Build(lr.pool)
+
+	var cell lineageCell
+	err = m.Get(ctx, key, func(k, v val.Tuple) error {
+		if k == nil {
+			return nil
+		}
+		cell.exists = true
+
+		col, ok := sch.GetAllCols().GetByNameCaseInsensitive(lr.columnName)
+		if !ok {
+			// the column didn't exist at this commit
+			return nil
+		}
+
+		var value interface{}
+		var err error
+		if idx, ok := pkCols.TagToIdx[col.Tag]; ok {
+			value, err = index.GetField(ctx, kd, idx, k, m.NodeStore())
+		} else {
+			value, err = index.GetField(ctx, vd, sch.GetNonPKCols().TagToIdx[col.Tag], v, m.NodeStore())
+		}
+		if err != nil || value == nil {
+			return err
+		}
+
+		sqlVal, err := col.TypeInfo.ToSqlType().SQL(ctx, nil, value)
+		if err != nil {
+			return err
+		}
+		s := sqlVal.ToString()
+		cell.value = &s
+		return nil
+	})
+	if err != nil {
+		return lineageCell{}, err
+	}
+
+	return cell, nil
+}
@@ -15,19 +15,36 @@
 package sqle
 
 import (
+	"bytes"
+	"context"
+	goerrors "errors"
 	"fmt"
+	"io"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
 	"gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/commitfmt"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/signing"
+	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	dtypes "github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
 )
 
 var _ sql.TableFunction = (*LogTableFunction)(nil)
@@ -40,10 +57,19 @@ type LogTableFunction struct {
 	notRevisionExprs []sql.Expression
 	notRevisionStrs  []string
 	tableNames       []string
+	columnNames      []string
 
-	minParents  int
-	showParents bool
-	decoration  string
+	minParents    int
+	showParents   bool
+	showSignature bool
+	decoration    string
+	graph         bool
+	// format is the pretty format of the formatted column, or nil if it isn't shown
+	format *commitfmt.Format
+
+	author *regexp.Regexp
+	since  *time.Time
+	until  *time.Time
 
 	database sql.Database
 }
@@ -124,6 +150,18 @@ func (ltf *LogTableFunction) getOptionsString() string {
 		options = append(options, fmt.Sprintf("--%s", cli.ParentsFlag))
 	}
 
+	if ltf.showSignature {
+		options = append(options, fmt.Sprintf("--%s", cli.ShowSignatureFlag))
+	}
+
+	if ltf.graph {
+		options = append(options, fmt.Sprintf("--%s", cli.GraphFlag))
+	}
+
+	if ltf.format != nil {
+		options = append(options, fmt.Sprintf("--%s %s", cli.PrettyParam, ltf.formatString()))
+	}
+
 	if len(ltf.decoration) > 0 && ltf.decoration != "auto" {
 		options = append(options, fmt.Sprintf("--%s %s", cli.DecorateFlag, ltf.decoration))
 	}
@@ -132,6 +170,22 @@ func (ltf *LogTableFunction) getOptionsString() string {
 		options = append(options, "--tables", strings.Join(ltf.tableNames, ","))
 	}
 
+	if len(ltf.columnNames) > 0 {
+		options = append(options, "--columns", strings.Join(ltf.columnNames, ","))
+	}
+
+	if ltf.author != nil {
+		options = append(options, fmt.Sprintf("--%s %s", cli.AuthorParam, ltf.author.String()))
+	}
+
+	if ltf.since != nil {
+		options = append(options, fmt.Sprintf("--%s %s", cli.SinceParam, ltf.since.Format(time.RFC3339)))
+	}
+
+	if ltf.until != nil {
+		options = append(options, fmt.Sprintf("--%s %s", cli.UntilParam, ltf.until.Format(time.RFC3339)))
+	}
+
 	return strings.Join(options, ", ")
 }
 
@@ -145,6 +199,15 @@ func (ltf *LogTableFunction) Schema() sql.Schema {
 	if shouldDecorateWithRefs(ltf.decoration) {
 		logSchema = append(logSchema, &sql.Column{Name: "refs", Type: types.Text})
 	}
+	if ltf.showSignature {
+		logSchema = append(logSchema, &sql.Column{Name: "signature", Type: types.Text, Nullable: true})
+	}
+	if ltf.graph {
+		logSchema = append(logSchema, &sql.Column{Name: "graph", Type: types.Text})
+	}
+	if ltf.format != nil {
+		logSchema = append(logSchema, &sql.Column{Name: "formatted", Type: types.Text})
+	}
 
 	return logSchema
 }
@@ -229,6 +292,13 @@ func (ltf *LogTableFunction) addOptions(expression []sql.Expression) error {
 		ltf.tableNames = append(ltf.tableNames, tableNames...)
 	}
 
+	if columnNames, ok := apr.GetValueList(cli.ColumnsFlag); ok {
+		if len(ltf.tableNames) == 0 {
+			return ltf.invalidArgDetailsErr(fmt.Sprintf("--%s requires --%s", cli.ColumnsFlag, cli.TablesFlag))
+		}
+		ltf.columnNames = append(ltf.columnNames, columnNames...)
+	}
+
 	minParents := apr.GetIntOrDefault(cli.MinParentsFlag, 0)
 	if apr.Contains(cli.MergesFlag) {
 		minParents = 2
@@ -236,6 +306,22 @@ func (ltf *LogTableFunction) addOptions(expression []sql.Expression) error {
 
 	ltf.minParents = minParents
 	ltf.showParents = apr.Contains(cli.ParentsFlag)
+	ltf.showSignature = apr.Contains(cli.ShowSignatureFlag)
+	ltf.graph = apr.Contains(cli.GraphFlag)
+	if ltf.graph && (len(ltf.tableNames) > 0 || minParents > 0 || apr.Contains(cli.AuthorParam) || apr.Contains(cli.SinceParam) || apr.Contains(cli.UntilParam)) {
+		// the graph needs every commit between the ones shown to connect them
+		return ltf.invalidArgDetailsErr(fmt.Sprintf("--%s cannot be used with options that filter the commits", cli.GraphFlag))
+	}
+
+	for _, param := range []string{cli.PrettyParam, cli.FormatParam} {
+		if formatStr, ok := apr.GetValue(param); ok {
+			format, err := commitfmt.ParseFormat(formatStr)
+			if err != nil {
+				return ltf.invalidArgDetailsErr(err.Error())
+			}
+			ltf.format = &format
+		}
+	}
 
 	decorateOption := apr.GetValueOrDefault(cli.DecorateFlag, "auto")
 	switch decorateOption {
@@ -245,9 +331,58 @@ func (ltf *LogTableFunction) addOptions(expression []sql.Expression) error {
 	}
 	ltf.decoration = decorateOption
 
+	if author, ok := apr.GetValue(cli.AuthorParam); ok {
+		ltf.author, err = regexp.Compile(author)
+		if err != nil {
+			return ltf.invalidArgDetailsErr(fmt.Sprintf("invalid --%s pattern: %s", cli.AuthorParam, err.Error()))
+		}
+	}
+
+	for _, param := range []string{cli.SinceParam, cli.UntilParam} {
+		if dateStr, ok := apr.GetValue(param); ok {
+			t, err := dconfig.ParseDate(dateStr)
+			if err != nil {
+				return ltf.invalidArgDetailsErr(fmt.Sprintf("invalid --%s date: %s", param, dateStr))
+			}
+			if param == cli.SinceParam {
+				ltf.since = &t
+			} else {
+				ltf.until = &t
+			}
+		}
+	}
+
 	return nil
 }
 
+// formatString returns the pretty format of the formatted column as it's given as an argument.
+func (ltf *LogTableFunction) formatString() string {
+	if ltf.format.Name != "" {
+		return ltf.format.Name
+	}
+	return "format:" + ltf.format.Template
+}
+
+// hasMetaFilters returns whether the log is filtered by the author or the date of commits.
+func (ltf *LogTableFunction) hasMetaFilters() bool {
+	return ltf.author != nil || ltf.since != nil || ltf.until != nil
+}
+
+// metaMatches returns whether a commit with the metadata |meta| matches the author and date filters of the log.
+func (ltf *LogTableFunction) metaMatches(meta *datas.CommitMeta) (bool, error) {
+	if ltf.author != nil && !ltf.author.MatchString(fmt.Sprintf("%s <%s>", meta.Name, meta.Email)) {
+		return false, nil
+	}
+	t := meta.Time()
+	if ltf.since != nil && t.Before(*ltf.since) {
+		return false, nil
+	}
+	if ltf.until != nil && t.After(*ltf.until) {
+		return false, nil
+	}
+	return true, nil
+}
+
 func (ltf *LogTableFunction) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
 	if len(exprs) != 0 {
 		return nil, sql.ErrInvalidChildrenNumber.New(0, len(exprs))
@@ -380,12 +515,25 @@ func (ltf *LogTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter
 	if !ok {
 		return nil, fmt.Errorf("unexpected database type: %T", ltf.database)
 	}
+	if len(ltf.columnNames) > 0 && !dtypes.IsFormat_DOLT(sqledb.DbData().Ddb.Format()) {
+		return nil, fmt.Errorf("--%s is only supported in the __DOLT__ format", cli.ColumnsFlag)
+	}
 
 	sess := dsess.DSessFromSess(ctx.Session)
 	var commit *doltdb.Commit
 
 	matchFunc := func(commit *doltdb.Commit) (bool, error) {
-		return commit.NumParents() >= ltf.minParents, nil
+		if commit.NumParents() < ltf.minParents {
+			return false, nil
+		}
+		if !ltf.hasMetaFilters() {
+			return true, nil
+		}
+		meta, err := commit.GetCommitMeta(ctx)
+		if err != nil {
+			return false, err
+		}
+		return ltf.metaMatches(meta)
 	}
 
 	cHashToRefs, err := getCommitHashToRefs(ctx, sqledb.DbData().Ddb, ltf.decoration)
@@ -562,8 +710,15 @@ type logTableFunctionRowIter struct {
 	decoration  string
 	cHashToRefs map[hash.Hash][]string
 	headHash    hash.Hash
+	// signingConfig is the configuration signatures are verified with, or nil if they aren't shown
+	signingConfig *signing.Config
+	// graph draws the graph column, or is nil if it isn't shown
+	graph  *commitfmt.Graph
+	format *commitfmt.Format
 
 	tableNames []string
+	// columnNames are the columns of the tables whose changes commits are restricted to, or nil for any change
+	columnNames []string
 }
 
 func (ltf *LogTableFunction) NewLogTableFunctionRowIter(ctx *sql.Context, ddb *doltdb.DoltDB, commit *doltdb.Commit, matchFn func(*doltdb.Commit) (bool, error), cHashToRefs map[hash.Hash][]string, tableNames []string) (*logTableFunctionRowIter, error) {
@@ -572,18 +727,33 @@ func (ltf *LogTableFunction) NewLogTableFunctionRowIter(ctx *sql.Context, ddb *d
 		return nil, err
 	}
 
-	child, err := commitwalk.GetTopologicalOrderIterator(ctx, ddb, []hash.Hash{h}, matchFn)
-	if err != nil {
-		return nil, err
+	var child doltdb.CommitItr
+	if ltf.hasMetaFilters() && ltf.minParents == 0 && dtypes.IsFormat_DOLT(ddb.Format()) {
+		// Filtering by author or date only needs the metadata of each commit, which the commit metadata index
+		// has without loading the commits that don't match.
+		entries, err := ddb.CommitMetaIndex().History(ctx, ddb, commit, ltf.metaMatches)
+		if err != nil {
+			return nil, err
+		}
+		child = doltdb.NewCommitMetaEntryItr(ddb, entries)
+	} else {
+		child, err = commitwalk.GetTopologicalOrderIterator(ctx, ddb, []hash.Hash{h}, matchFn)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &logTableFunctionRowIter{
-		child:       child,
-		showParents: ltf.showParents,
-		decoration:  ltf.decoration,
-		cHashToRefs: cHashToRefs,
-		headHash:    h,
-		tableNames:  tableNames,
+		child:         child,
+		showParents:   ltf.showParents,
+		decoration:    ltf.decoration,
+		cHashToRefs:   cHashToRefs,
+		signingConfig: ltf.signingConfig(ctx),
+		graph:         ltf.newGraph(),
+		format:        ltf.format,
+		headHash:      h,
+		tableNames:    tableNames,
+		columnNames:   ltf.columnNames,
 	}, nil
 }
 
@@ -618,12 +788,16 @@ func (ltf *LogTableFunction) NewDotDotLogTableFunctionRowIter(ctx *sql.Context,
 	}
 
 	return &logTableFunctionRowIter{
-		child:       child,
-		showParents: ltf.showParents,
-		decoration:  ltf.decoration,
-		cHashToRefs: cHashToRefs,
-		headHash:    headHash,
-		tableNames:  tableNames,
+		child:         child,
+		showParents:   ltf.showParents,
+		decoration:    ltf.decoration,
+		cHashToRefs:   cHashToRefs,
+		signingConfig: ltf.signingConfig(ctx),
+		graph:         ltf.newGraph(),
+		format:        ltf.format,
+		headHash:      headHash,
+		tableNames:    tableNames,
+		columnNames:   ltf.columnNames,
 	}, nil
 }
 
@@ -679,6 +853,12 @@ func (itr *logTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
 				if err != nil {
 					return nil, err
 				}
+				if didChange && len(itr.columnNames) > 0 {
+					didChange, err = didColumnsChangeBetweenRootValues(ctx, childRV, parent0RV, parent1RV, tableName, itr.columnNames)
+					if err != nil {
+						return nil, err
+					}
+				}
 				if didChange {
 					break
 				}
@@ -713,9 +893,71 @@ func (itr *logTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
 		row = row.Append(sql.NewRow(getRefsString(branchNames, isHead)))
 	}
 
+	if itr.signingConfig != nil {
+		sig, err := getSignatureString(ctx, *itr.signingConfig, commit)
+		if err != nil {
+			return nil, err
+		}
+		row = row.Append(sql.NewRow(sig))
+	}
+
+	if itr.graph != nil || itr.format != nil {
+		parentHashes, err := commit.ParentHashes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		parents := make([]string, len(parentHashes))
+		for i, h := range parentHashes {
+			parents[i] = h.String()
+		}
+
+		if itr.graph != nil {
+			graphRow := itr.graph.Next(commitHash.String(), parents)
+			row = row.Append(sql.NewRow(strings.Join(graphRow.Lines, "\n")))
+		}
+		if itr.format != nil {
+			refs := getRefsString(itr.cHashToRefs[commitHash], itr.headHash == commitHash)
+			row = row.Append(sql.NewRow(itr.format.Expand(commitfmt.Commit{Hash: commitHash.String(), Parents: parents, Meta: meta, Refs: refs})))
+		}
+	}
+
 	return row, nil
 }
 
+// newGraph returns the graph that draws the graph column, or nil if it isn't shown.
+func (ltf *LogTableFunction) newGraph() *commitfmt.Graph {
+	if !ltf.graph {
+		return nil
+	}
+	return commitfmt.NewGraph()
+}
+
+// signingConfig returns the configuration that the signatures of commits are verified with, or nil if they aren't
+// shown.
+func (ltf *LogTableFunction) signingConfig(ctx *sql.Context) *signing.Config {
+	if !ltf.showSignature {
+		return nil
+	}
+	cfg := dsess.DSessFromSess(ctx.Session).SigningConfig()
+	return &cfg
+}
+
+// getSignatureString returns the result of verifying the signature of |cm|, or nil if it isn't signed. Failures to
+// verify the signature are shown in place of the result, rather than failing the query.
+func getSignatureString(ctx *sql.Context, cfg signing.Config, cm *doltdb.Commit) (interface{}, error) {
+	payload, sig, err := cm.GetSignature()
+	if err != nil {
+		return nil, err
+	} else if sig == "" {
+		return nil, nil
+	}
+	ver, err := signing.Verify(ctx, cfg, payload, sig)
+	if err != nil {
+		return err.Error(), nil
+	}
+	return strings.TrimSpace(ver.Output), nil
+}
+
 func (itr *logTableFunctionRowIter) Close(_ *sql.Context) error {
 	return nil
 }
@@ -804,3 +1046,202 @@ func didTableChangeBetweenRootValues(ctx *sql.Context, child, parent0, parent1 *
 		}
 	}
 }
+
+// didColumnsChangeBetweenRootValues checks if any of the given columns of the given table changed between the child
+// root value and either of its parents' root values. The table must have changed, as checked by
+// didTableChangeBetweenRootValues.
+func didColumnsChangeBetweenRootValues(ctx *sql.Context, child, parent0, parent1 *doltdb.RootValue, tableName string, columnNames []string) (bool, error) {
+	for _, parent := range []*doltdb.RootValue{parent0, parent1} {
+		if parent == nil {
+			continue
+		}
+		changed, err := didColumnsChange(ctx, parent, child, tableName, columnNames)
+		if err != nil || changed {
+			return changed, err
+		}
+	}
+	return false, nil
+}
+
+// errColumnChanged stops the diff of a table once a change to one of the columns is found.
+var errColumnChanged = goerrors.New("column changed")
+
+// didColumnsChange checks if any of the given columns of the given table changed between the from and to root values.
+// Rather than diffing every row, it walks the prolly tree diff of the table's rows, and stops at the first row whose
+// value for one of the columns changed.
+func didColumnsChange(ctx *sql.Context, from, to *doltdb.RootValue, tableName string, columnNames []string) (bool, error) {
+	fromTbl, fromOk, err := from.GetTable(ctx, tableName)
+	if err != nil {
+		return false, err
+	}
+	toTbl, toOk, err := to.GetTable(ctx, tableName)
+	if err != nil {
+		return false, err
+	}
+	if !fromOk && !toOk {
+		return false, nil
+	}
+
+	var fromSch, toSch schema.Schema
+	if fromOk {
+		if fromSch, err = fromTbl.GetSchema(ctx); err != nil {
+			return false, err
+		}
+	}
+	if toOk {
+		if toSch, err = toTbl.GetSchema(ctx); err != nil {
+			return false, err
+		}
+	}
+
+	// the columns to compare, as their positions in the key or value tuples of each side
+	var fromCols, toCols []tupleField
+	for _, name := range columnNames {
+		fromCol, fromHasCol := columnField(fromSch, name)
+		toCol, toHasCol := columnField(toSch, name)
+		if fromHasCol != toHasCol {
+			// the column was added or dropped
+			return true, nil
+		} else if !fromHasCol {
+			continue
+		} else if !fromCol.typ.Equals(toCol.typ) {
+			return true, nil
+		}
+		fromCols = append(fromCols, fromCol)
+		toCols = append(toCols, toCol)
+	}
+	if len(fromCols) == 0 {
+		return false, nil
+	}
+
+	fromHash, err := fromTbl.GetRowDataHash(ctx)
+	if err != nil {
+		return false, err
+	}
+	toHash, err := toTbl.GetRowDataHash(ctx)
+	if err != nil {
+		return false, err
+	}
+	if fromHash == toHash {
+		return false, nil
+	}
+
+	fromIdx, err := fromTbl.GetRowData(ctx)
+	if err != nil {
+		return false, err
+	}
+	toIdx, err := toTbl.GetRowData(ctx)
+	if err != nil {
+		return false, err
+	}
+	fromRows := durable.ProllyMapFromIndex(fromIdx)
+	toRows := durable.ProllyMapFromIndex(toIdx)
+	fromKd, fromVd := fromRows.Descriptors()
+	toKd, toVd := toRows.Descriptors()
+	if schema.IsKeyless(toSch) {
+		return didKeylessColumnsChange(ctx, fromRows, toRows, fromCols, toCols)
+	}
+
+	err = prolly.DiffMaps(ctx, fromRows, toRows, func(ctx context.Context, diff tree.Diff) error {
+		if diff.Type != tree.ModifiedDiff {
+			// a row was added or removed, which changes every column
+			return errColumnChanged
+		}
+		for i := range fromCols {
+			fromVal := fromCols[i].get(fromKd, fromVd, val.Tuple(diff.Key), val.Tuple(diff.From))
+			toVal := toCols[i].get(toKd, toVd, val.Tuple(diff.Key), val.Tuple(diff.To))
+			if !bytes.Equal(fromVal, toVal) {
+				return errColumnChanged
+			}
+		}
+		return nil
+	})
+	if err == errColumnChanged {
+		return true, nil
+	} else if err != nil && err != io.EOF {
+		return false, err
+	}
+	return false, nil
+}
+
+// didKeylessColumnsChange checks if any of the given columns of a keyless table changed between its |fromRows| and
+// |toRows|. The rows of keyless tables are keyed by their values, so updating a row removes it and adds another. A
+// column changed if the number of rows with each of its values changed.
+func didKeylessColumnsChange(ctx context.Context, fromRows, toRows prolly.Map, fromCols, toCols []tupleField) (bool, error) {
+	fromKd, fromVd := fromRows.Descriptors()
+	toKd, toVd := toRows.Descriptors()
+	counts := make([]map[string]int64, len(fromCols))
+	for i := range counts {
+		counts[i] = make(map[string]int64)
+	}
+
+	err := prolly.DiffMaps(ctx, fromRows, toRows, func(ctx context.Context, diff tree.Diff) error {
+		if diff.Type != tree.AddedDiff {
+			card := int64(val.ReadKeylessCardinality(val.Tuple(diff.From)))
+			for i, col := range fromCols {
+				counts[i][fieldKey(col.get(fromKd, fromVd, val.Tuple(diff.Key), val.Tuple(diff.From)))] -= card
+			}
+		}
+		if diff.Type != tree.RemovedDiff {
+			card := int64(val.ReadKeylessCardinality(val.Tuple(diff.To)))
+			for i, col := range toCols {
+				counts[i][fieldKey(col.get(toKd, toVd, val.Tuple(diff.Key), val.Tuple(diff.To)))] += card
+			}
+		}
+		return nil
+	})
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	for _, colCounts := range counts {
+		for _, n := range colCounts {
+			if n != 0 {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// fieldKey returns a map key for the field |f|, which distinguishes NULL from an empty value.
+func fieldKey(f []byte) string {
+	if f == nil {
+		return ""
+	}
+	return "v" + string(f)
+}
+
+// tupleField is the position of a column in the key or value tuples of its table's rows.
+type tupleField struct {
+	typ   typeinfo.TypeInfo
+	inKey bool
+	idx   int
+}
+
+// columnField returns the position of the column named |name| in the rows of a table with the schema |sch|, which
+// is nil if the table doesn't exist, and whether the table has the column.
+func columnField(sch schema.Schema, name string) (tupleField, bool) {
+	if sch == nil {
+		return tupleField{}, false
+	}
+	col, ok := sch.GetAllCols().GetByNameCaseInsensitive(name)
+	if !ok {
+		return tupleField{}, false
+	}
+	if idx, ok := sch.GetPKCols().TagToIdx[col.Tag]; ok {
+		return tupleField{typ: col.TypeInfo, inKey: true, idx: idx}, true
+	}
+	idx := sch.GetNonPKCols().TagToIdx[col.Tag]
+	if schema.IsKeyless(sch) {
+		// skip the cardinality
+		idx++
+	}
+	return tupleField{typ: col.TypeInfo, idx: idx}, true
+}
+
+func (f tupleField) get(kd, vd val.TupleDesc, key, value val.Tuple) []byte {
+	if f.inKey {
+		return kd.GetField(f.idx, key)
+	}
+	return vd.GetField(f.idx, value)
+}
@@ -120,7 +120,7 @@ func (p *PatchTableFunction) PartitionRows(ctx *sql.Context, partition sql.Parti
 		return nil, err
 	}
 
-	tableDeltas, err := diff.GetTableDeltas(ctx, fromRefDetails.root, toRefDetails.root)
+	tableDeltas, err := getTableDeltas(ctx, fromRefDetails.root, toRefDetails.root)
 	if err != nil {
 		return nil, err
 	}
@@ -718,7 +718,13 @@ func getDiffQuery(ctx *sql.Context, dbData env.DbData, td diff.TableDelta, fromR
 	dp := dtables.NewDiffPartition(td.ToTable, td.FromTable, toRefDetails.hashStr, fromRefDetails.hashStr, toRefDetails.commitTime, fromRefDetails.commitTime, td.ToSch, td.FromSch)
 	ri := dtables.NewDiffPartitionRowIter(*dp, dbData.Ddb, j)
 
-	return diffQuerySqlSch, projections, ri, nil
+	masker, err := dsess.NewColumnMasker(ctx, dbData.Ddb, diffPKSch.Schema, dsess.DiffTableColumnTag(td.FromSch, td.ToSch))
+	if err != nil {
+		ri.Close(ctx)
+		return nil, nil, nil, err
+	}
+
+	return diffQuerySqlSch, projections, masker.RowIter(ri), nil
 }
 
 func getColumnNamesWithDiff(fromSch, toSch schema.Schema) []string {
@@ -0,0 +1,319 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/clusterdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+var _ sql.TableFunction = (*ReplicationStatusTableFunction)(nil)
+var _ sql.ExecSourceRel = (*ReplicationStatusTableFunction)(nil)
+
+// The replication modes reported by dolt_replication_status().
+const (
+	replicationModePush        = "push"
+	replicationModeAsyncPush   = "async_push"
+	replicationModeReadReplica = "read_replica"
+	replicationModeCluster     = "cluster"
+)
+
+// ReplicationStatusTableFunction is the table function dolt_replication_status(), which summarizes how each of the
+// server's databases is replicated, in one row for each database and remote:
+//
+//   - push: the database pushes its commits to @@dolt_replicate_to_remote as they're made. Nothing is ever pending.
+//   - async_push: the database pushes its commits to @@dolt_replicate_to_remote in the background. With
+//     @@dolt_async_replication_queue, the pushes that haven't succeeded yet are counted as pending.
+//   - read_replica: the database pulls from @@dolt_read_replica_remote.
+//   - cluster: the database is replicated to, or from, a cluster standby, as in dolt_cluster.dolt_cluster_status.
+//
+// The columns that aren't known for a mode are NULL.
+type ReplicationStatusTableFunction struct {
+	database sql.Database
+}
+
+var replicationStatusTableSchema = sql.Schema{
+	&sql.Column{Name: "database", Type: types.Text, Nullable: false},
+	&sql.Column{Name: "mode", Type: types.Text, Nullable: false},
+	&sql.Column{Name: "remote", Type: types.Text, Nullable: false},
+	&sql.Column{Name: "remote_url", Type: types.Text, Nullable: true},
+	&sql.Column{Name: "last_sync", Type: types.Datetime, Nullable: true},
+	&sql.Column{Name: "last_error", Type: types.Text, Nullable: true},
+	&sql.Column{Name: "lag_millis", Type: types.Int64, Nullable: true},
+	&sql.Column{Name: "pending", Type: types.Int64, Nullable: true},
+}
+
+// replicationStatus is a row of dolt_replication_status().
+type replicationStatus struct {
+	database  string
+	mode      string
+	remote    string
+	remoteURL string
+	lastSync  time.Time
+	lastError string
+	lag       *time.Duration
+	pending   *int64
+}
+
+func (rs replicationStatus) toRow() sql.Row {
+	row := sql.Row{rs.database, rs.mode, rs.remote, nil, nil, nil, nil, nil}
+	if rs.remoteURL != "" {
+		row[3] = rs.remoteURL
+	}
+	if !rs.lastSync.IsZero() {
+		row[4] = rs.lastSync
+	}
+	if rs.lastError != "" {
+		row[5] = rs.lastError
+	}
+	if rs.lag != nil {
+		row[6] = rs.lag.Milliseconds()
+	}
+	if rs.pending != nil {
+		row[7] = *rs.pending
+	}
+	return row
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (rtf *ReplicationStatusTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &ReplicationStatusTableFunction{
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (rtf *ReplicationStatusTableFunction) Database() sql.Database {
+	return rtf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (rtf *ReplicationStatusTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	nrtf := *rtf
+	nrtf.database = database
+	return &nrtf, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (rtf *ReplicationStatusTableFunction) Name() string {
+	return "dolt_replication_status"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (rtf *ReplicationStatusTableFunction) Resolved() bool {
+	return true
+}
+
+func (rtf *ReplicationStatusTableFunction) IsReadOnly() bool {
+	return true
+}
+
+// String implements the Stringer interface
+func (rtf *ReplicationStatusTableFunction) String() string {
+	return "DOLT_REPLICATION_STATUS()"
+}
+
+// Schema implements the sql.Node interface.
+func (rtf *ReplicationStatusTableFunction) Schema() sql.Schema {
+	return replicationStatusTableSchema
+}
+
+// Children implements the sql.Node interface.
+func (rtf *ReplicationStatusTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (rtf *ReplicationStatusTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return rtf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (rtf *ReplicationStatusTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return true
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (rtf *ReplicationStatusTableFunction) Expressions() []sql.Expression {
+	return nil
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (rtf *ReplicationStatusTableFunction) WithExpressions(expression ...sql.Expression) (sql.Node, error) {
+	if len(expression) != 0 {
+		return nil, sql.ErrInvalidArgumentNumber.New(rtf.Name(), 0, len(expression))
+	}
+	return rtf, nil
+}
+
+// RowIter implements the sql.Node interface
+func (rtf *ReplicationStatusTableFunction) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	provider, ok := dsess.DSessFromSess(ctx.Session).Provider().(*DoltDatabaseProvider)
+	if !ok {
+		return sql.RowsToRowIter(), nil
+	}
+
+	statuses, err := provider.replicationStatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]sql.Row, len(statuses))
+	for i, rs := range statuses {
+		rows[i] = rs.toRow()
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// replicationStatuses returns how each of this provider's databases is replicated, ordered by database, mode and
+// remote.
+func (p *DoltDatabaseProvider) replicationStatuses(ctx *sql.Context) ([]replicationStatus, error) {
+	now := time.Now()
+	var statuses []replicationStatus
+
+	_, pushRemote, _ := sql.SystemVariables.GetGlobal(dsess.ReplicateToRemote)
+	pushRemoteName, _ := pushRemote.(string)
+
+	for _, db := range p.databases.Load().databases {
+		if cluster, ok := clusterdb.StatusProviderFor(db); ok {
+			statuses = append(statuses, clusterReplicationStatuses(cluster.GetClusterStatus())...)
+			continue
+		}
+
+		if rrd, ok := db.(ReadReplicaDatabase); ok && rrd.ValidReplicaState(ctx) {
+			rs := replicationStatus{
+				database:  db.Name(),
+				mode:      replicationModeReadReplica,
+				remote:    rrd.remote.Name,
+				remoteURL: rrd.remote.Url,
+			}
+			lastPull, err := rrd.refresh.status()
+			if err != nil {
+				rs.lastError = err.Error()
+			}
+			if !lastPull.IsZero() {
+				rs.lastSync = lastPull
+				lag := now.Sub(lastPull)
+				rs.lag = &lag
+			}
+			statuses = append(statuses, rs)
+		}
+
+		ddb := db.DbData().Ddb
+		if ddb == nil {
+			continue
+		}
+		rs := replicationStatus{
+			database: db.Name(),
+			remote:   pushRemoteName,
+		}
+		for _, h := range ddb.CommitHooks() {
+			switch h := h.(type) {
+			case *doltdb.PushOnWriteHook:
+				rs.mode = replicationModePush
+				// commits don't complete until they've been pushed
+				var pending int64
+				rs.pending = &pending
+			case *doltdb.AsyncPushOnWriteHook:
+				rs.mode = replicationModeAsyncPush
+			case *doltdb.QueuedPushOnWriteHook:
+				rs.mode = replicationModeAsyncPush
+				queue := h.Queue()
+				rs.lastSync = queue.LastPush()
+				pushes := queue.Pushes()
+				pending := int64(len(pushes))
+				rs.pending = &pending
+				lag := time.Duration(0)
+				for _, qp := range pushes {
+					if rs.lastError == "" {
+						rs.lastError = qp.LastError
+					}
+					if l := now.Sub(qp.EnqueuedAt); l > lag {
+						lag = l
+					}
+				}
+				rs.lag = &lag
+			}
+		}
+		if rs.mode == "" {
+			continue
+		}
+		if rsr := db.DbData().Rsr; rsr != nil && pushRemoteName != "" {
+			remotes, err := rsr.GetRemotes()
+			if err != nil {
+				return nil, err
+			}
+			rs.remoteURL = remotes[pushRemoteName].Url
+		}
+		statuses = append(statuses, rs)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].database != statuses[j].database {
+			return statuses[i].database < statuses[j].database
+		}
+		if statuses[i].mode != statuses[j].mode {
+			return statuses[i].mode < statuses[j].mode
+		}
+		return statuses[i].remote < statuses[j].remote
+	})
+	return statuses, nil
+}
+
+// clusterReplicationStatuses returns the replication statuses of the cluster's databases. A database is pending
+// while the standby hasn't been sent the last root it was given to replicate.
+func clusterReplicationStatuses(rss []clusterdb.ReplicaStatus) []replicationStatus {
+	statuses := make([]replicationStatus, len(rss))
+	for i, crs := range rss {
+		rs := replicationStatus{
+			database: crs.Database,
+			mode:     replicationModeCluster,
+			remote:   crs.Remote,
+			lag:      crs.ReplicationLag,
+		}
+		if crs.LastUpdate != nil {
+			rs.lastSync = *crs.LastUpdate
+		}
+		if crs.CurrentError != nil {
+			rs.lastError = *crs.CurrentError
+		}
+		if crs.Role == "primary" {
+			var pending int64
+			if crs.NextHead != nil && (crs.LastPushedHead == nil || *crs.NextHead != *crs.LastPushedHead) {
+				pending = 1
+			}
+			rs.pending = &pending
+		}
+		statuses[i] = rs
+	}
+	return statuses
+}
@@ -0,0 +1,505 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/vector"
+	"github.com/dolthub/dolt/go/store/pool"
+	"github.com/dolthub/dolt/go/store/prolly"
+	dtypes "github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// defaultVectorSearchProbes is the number of lists of a vector index searched when no number is given.
+const defaultVectorSearchProbes = 3
+
+const vectorSearchDistanceColumn = "distance"
+
+var _ sql.TableFunction = (*VectorSearchTableFunction)(nil)
+var _ sql.ExecSourceRel = (*VectorSearchTableFunction)(nil)
+
+// VectorSearchTableFunction is the table function dolt_vector_search(<table>, <column>, <embedding>, <k>[, <probes>]),
+// which returns the |k| rows of a table whose embeddings in a column are nearest to an embedding, nearest first,
+// along with their distance to it. The column must have a vector index, created with dolt_vector_index(), and only
+// the rows in the |probes| lists of the index whose centroids are nearest to the embedding are searched, so the
+// results are approximate: a nearer row in another list is not returned.
+type VectorSearchTableFunction struct {
+	ctx *sql.Context
+
+	tableNameExpr  sql.Expression
+	columnNameExpr sql.Expression
+	embeddingExpr  sql.Expression
+	kExpr          sql.Expression
+	probesExpr     sql.Expression
+	database       sql.Database
+
+	sqlSch sql.Schema
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (vs *VectorSearchTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &VectorSearchTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (vs *VectorSearchTableFunction) Database() sql.Database {
+	return vs.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (vs *VectorSearchTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	nvs := *vs
+	nvs.database = database
+	return &nvs, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (vs *VectorSearchTableFunction) Name() string {
+	return "dolt_vector_search"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (vs *VectorSearchTableFunction) Resolved() bool {
+	for _, expr := range vs.Expressions() {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+func (vs *VectorSearchTableFunction) IsReadOnly() bool {
+	return true
+}
+
+// String implements the Stringer interface
+func (vs *VectorSearchTableFunction) String() string {
+	exprs := vs.Expressions()
+	args := make([]string, len(exprs))
+	for i, expr := range exprs {
+		args[i] = expr.String()
+	}
+	return fmt.Sprintf("DOLT_VECTOR_SEARCH(%s)", strings.Join(args, ", "))
+}
+
+// Schema implements the sql.Node interface.
+func (vs *VectorSearchTableFunction) Schema() sql.Schema {
+	return vs.sqlSch
+}
+
+// Children implements the sql.Node interface.
+func (vs *VectorSearchTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (vs *VectorSearchTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return vs, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (vs *VectorSearchTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tableName, err := vs.evaluateTableName()
+	if err != nil {
+		return false
+	}
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(vs.database.Name(), tableName, "", sql.PrivilegeType_Select))
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (vs *VectorSearchTableFunction) Expressions() []sql.Expression {
+	exprs := []sql.Expression{vs.tableNameExpr, vs.columnNameExpr, vs.embeddingExpr, vs.kExpr}
+	if vs.probesExpr != nil {
+		exprs = append(exprs, vs.probesExpr)
+	}
+	return exprs
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (vs *VectorSearchTableFunction) WithExpressions(expression ...sql.Expression) (sql.Node, error) {
+	if len(expression) < 4 || len(expression) > 5 {
+		return nil, sql.ErrInvalidArgumentNumber.New(vs.Name(), "4 or 5", len(expression))
+	}
+
+	for _, expr := range expression {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(vs.Name(), expr.String())
+		}
+		// prepared statements resolve functions beforehand, so above check fails
+		if _, ok := expr.(sql.FunctionExpression); ok {
+			return nil, ErrInvalidNonLiteralArgument.New(vs.Name(), expr.String())
+		}
+	}
+
+	nvs := *vs
+	nvs.tableNameExpr = expression[0]
+	nvs.columnNameExpr = expression[1]
+	nvs.embeddingExpr = expression[2]
+	nvs.kExpr = expression[3]
+	nvs.probesExpr = nil
+	if len(expression) == 5 {
+		nvs.probesExpr = expression[4]
+	}
+
+	if !types.IsText(nvs.tableNameExpr.Type()) {
+		return nil, sql.ErrInvalidArgumentDetails.New(nvs.Name(), nvs.tableNameExpr.String())
+	}
+	if !types.IsText(nvs.columnNameExpr.Type()) {
+		return nil, sql.ErrInvalidArgumentDetails.New(nvs.Name(), nvs.columnNameExpr.String())
+	}
+
+	if err := nvs.generateSchema(); err != nil {
+		return nil, err
+	}
+
+	return &nvs, nil
+}
+
+// generateSchema sets the schema of the function, which is the schema of the searched table followed by the
+// distance column.
+func (vs *VectorSearchTableFunction) generateSchema() error {
+	tableName, err := vs.evaluateTableName()
+	if err != nil {
+		return err
+	}
+	tbl, ok, err := vs.database.GetTableInsensitive(vs.ctx, tableName)
+	if err != nil {
+		return err
+	} else if !ok {
+		return sql.ErrTableNotFound.New(tableName)
+	}
+
+	vs.sqlSch = nil
+	for _, col := range tbl.Schema() {
+		c := *col
+		c.Source = vs.Name()
+		c.PrimaryKey = false
+		vs.sqlSch = append(vs.sqlSch, &c)
+	}
+	vs.sqlSch = append(vs.sqlSch, &sql.Column{Name: vectorSearchDistanceColumn, Type: types.Float64, Source: vs.Name(), Nullable: false})
+	return nil
+}
+
+func (vs *VectorSearchTableFunction) evaluateTableName() (string, error) {
+	tableNameVal, err := vs.tableNameExpr.Eval(vs.ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	tableName, ok := tableNameVal.(string)
+	if !ok {
+		return "", ErrInvalidTableName.New(vs.tableNameExpr.String())
+	}
+	return tableName, nil
+}
+
+// evaluateArguments returns the column name, the embedding searched for, the number of rows to return, and the
+// number of lists to search.
+func (vs *VectorSearchTableFunction) evaluateArguments() (string, []float64, int, int, error) {
+	columnNameVal, err := vs.columnNameExpr.Eval(vs.ctx, nil)
+	if err != nil {
+		return "", nil, 0, 0, err
+	}
+	columnName, ok := columnNameVal.(string)
+	if !ok {
+		return "", nil, 0, 0, sql.ErrInvalidArgumentDetails.New(vs.Name(), vs.columnNameExpr.String())
+	}
+
+	embeddingVal, err := vs.embeddingExpr.Eval(vs.ctx, nil)
+	if err != nil {
+		return "", nil, 0, 0, err
+	}
+	embedding, err := vector.ParseEmbedding(vs.ctx, embeddingVal)
+	if err != nil {
+		return "", nil, 0, 0, err
+	}
+
+	k, err := vs.evaluatePositiveInt(vs.kExpr)
+	if err != nil {
+		return "", nil, 0, 0, err
+	}
+	probes := defaultVectorSearchProbes
+	if vs.probesExpr != nil {
+		probes, err = vs.evaluatePositiveInt(vs.probesExpr)
+		if err != nil {
+			return "", nil, 0, 0, err
+		}
+	}
+
+	return columnName, embedding, k, probes, nil
+}
+
+func (vs *VectorSearchTableFunction) evaluatePositiveInt(expr sql.Expression) (int, error) {
+	v, err := expr.Eval(vs.ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	n, _, err := types.Int64.Convert(v)
+	if err != nil || n == nil || n.(int64) <= 0 {
+		return 0, sql.ErrInvalidArgumentDetails.New(vs.Name(), expr.String())
+	}
+	return int(n.(int64)), nil
+}
+
+// RowIter implements the sql.Node interface
+func (vs *VectorSearchTableFunction) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	tableName, err := vs.evaluateTableName()
+	if err != nil {
+		return nil, err
+	}
+	columnName, embedding, k, probes, err := vs.evaluateArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	sqledb, ok := vs.database.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", vs.database)
+	}
+	root, err := sqledb.GetRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !dtypes.IsFormat_DOLT(root.VRW().Format()) {
+		return nil, fmt.Errorf("%s is not supported for this storage format", vs.Name())
+	}
+
+	tbl, tableName, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pkSch, err := sqlutil.FromDoltSchema(tableName, sch)
+	if err != nil {
+		return nil, err
+	}
+	colIdx := pkSch.Schema.IndexOfColName(columnName)
+	if colIdx < 0 {
+		return nil, sql.ErrTableColumnNotFound.New(tableName, columnName)
+	}
+	// the distances to a masked column's embeddings would reveal them
+	err = dsess.CheckMaskedColumnLookup(ctx, sqledb.DbData().Ddb, tableName, sch, []string{pkSch.Schema[colIdx].Name})
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := vs.loadIndex(ctx, sqledb, root, tableName, pkSch.Schema[colIdx].Name)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := vector.NearestCentroids(idx.Centroids, embedding, probes)
+	if err != nil {
+		return nil, err
+	}
+
+	listsTbl, ok, err := root.GetTable(ctx, doltdb.VectorIndexListsTableName(tableName))
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrTableNotFound.New(doltdb.VectorIndexListsTableName(tableName))
+	}
+	vr, err := newVectorSearchReader(ctx, tbl, sch, pkSch.Schema, listsTbl)
+	if err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		row      sql.Row
+		distance float64
+	}
+	var results []result
+	for _, list := range lists {
+		err = vr.scanList(ctx, idx.Column, list, func(row sql.Row) error {
+			if row[colIdx] == nil {
+				return nil
+			}
+			rowEmbedding, err := vector.ParseEmbedding(ctx, row[colIdx])
+			if err != nil {
+				return err
+			}
+			distance, err := vector.Distance(embedding, rowEmbedding)
+			if err != nil {
+				return err
+			}
+			results = append(results, result{row: row, distance: distance})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].distance < results[j].distance
+	})
+	if len(results) > k {
+		results = results[:k]
+	}
+
+	masker, err := dsess.NewColumnMasker(ctx, sqledb.DbData().Ddb, pkSch.Schema, dsess.TableColumnTag(sch))
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]sql.Row, len(results))
+	for i, r := range results {
+		rows[i] = append(masker.MaskRow(r.row), r.distance)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// loadIndex returns the vector index on the column |columnName| of the table |tableName| in |root|.
+func (vs *VectorSearchTableFunction) loadIndex(ctx *sql.Context, db dsess.SqlDatabase, root *doltdb.RootValue, tableName, columnName string) (vector.Index, error) {
+	indexes, err := loadVectorIndexes(ctx, db, root, tableName)
+	if err != nil {
+		return vector.Index{}, err
+	}
+	for _, idx := range indexes {
+		if strings.EqualFold(idx.Column, columnName) {
+			return idx, nil
+		}
+	}
+	return vector.Index{}, fmt.Errorf("column '%s' of table '%s' has no vector index", columnName, tableName)
+}
+
+// vectorSearchReader reads the rows of a table that are in the lists of one of its vector indexes.
+type vectorSearchReader struct {
+	sch    schema.Schema
+	sqlSch sql.Schema
+	rows   prolly.Map
+	lists  prolly.Map
+	pool   pool.BuffPool
+}
+
+func newVectorSearchReader(ctx *sql.Context, tbl *doltdb.Table, sch schema.Schema, sqlSch sql.Schema, listsTbl *doltdb.Table) (*vectorSearchReader, error) {
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	listsData, err := listsTbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &vectorSearchReader{
+		sch:    sch,
+		sqlSch: sqlSch,
+		rows:   durable.ProllyMapFromIndex(rowData),
+		lists:  durable.ProllyMapFromIndex(listsData),
+		pool:   pool.NewBuffPool(),
+	}, nil
+}
+
+// scanList calls |cb| with each row of the table in the list |list| of the index on |column|. The rows of a list are
+// read by a prefix scan of the lists table, whose key starts with the indexed column and the list, followed by the
+// primary key of the row, which is then looked up in the table.
+func (vr *vectorSearchReader) scanList(ctx *sql.Context, column string, list int, cb func(sql.Row) error) error {
+	ns := vr.lists.NodeStore()
+	listsKd, _ := vr.lists.Descriptors()
+	prefixDesc := listsKd.PrefixDesc(2)
+	pb := val.NewTupleBuilder(prefixDesc)
+	if err := index.PutField(ctx, ns, pb, 0, column); err != nil {
+		return err
+	}
+	if err := index.PutField(ctx, ns, pb, 1, int32(list)); err != nil {
+		return err
+	}
+	iter, err := vr.lists.IterRange(ctx, prolly.PrefixRange(pb.Build(vr.pool), prefixDesc))
+	if err != nil {
+		return err
+	}
+
+	rowsKd, _ := vr.rows.Descriptors()
+	for {
+		k, _, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		kb := val.NewTupleBuilder(rowsKd)
+		for i := 0; i < rowsKd.Count(); i++ {
+			v, err := index.GetField(ctx, listsKd, i+2, k, ns)
+			if err != nil {
+				return err
+			}
+			if err = index.PutField(ctx, vr.rows.NodeStore(), kb, i, v); err != nil {
+				return err
+			}
+		}
+
+		row, ok, err := vr.getRow(ctx, kb.Build(vr.pool))
+		if err != nil {
+			return err
+		} else if !ok {
+			// the lists of the index are stale, and still list a deleted row
+			continue
+		}
+		if err = cb(row); err != nil {
+			return err
+		}
+	}
+}
+
+// getRow returns the row of the table with the key |key|.
+func (vr *vectorSearchReader) getRow(ctx *sql.Context, key val.Tuple) (sql.Row, bool, error) {
+	rowsKd, _ := vr.rows.Descriptors()
+	iter, err := vr.rows.IterRange(ctx, prolly.PrefixRange(key, rowsKd))
+	if err != nil {
+		return nil, false, err
+	}
+	rowIter, err := index.NewProllyRowIter(vr.sch, vr.sqlSch, vr.rows, iter, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	row, err := rowIter.Next(ctx)
+	if err == io.EOF {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return row, true, nil
+}
@@ -0,0 +1,391 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	dtypes "github.com/dolthub/dolt/go/store/types"
+)
+
+var _ sql.TableFunction = (*ViolationOriginsTableFunction)(nil)
+var _ sql.ExecSourceRel = (*ViolationOriginsTableFunction)(nil)
+
+// ViolationOriginsTableFunction is the table function dolt_violation_origins(<table>), which lists the constraint
+// violations of a table along with where they came from, and replays the history of the changes that introduced
+// them to find the commit in which each violation first became possible.
+type ViolationOriginsTableFunction struct {
+	ctx *sql.Context
+
+	tableNameExpr sql.Expression
+	database      sql.Database
+}
+
+var violationOriginsTableSchema = sql.Schema{
+	&sql.Column{Name: "from_root_ish", Type: types.Text, Nullable: false},
+	&sql.Column{Name: "violation_type", Type: types.Text, Nullable: false},
+	&sql.Column{Name: "violation_key", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "source_branch", Type: types.Text, Nullable: true},
+	&sql.Column{Name: "origin_commit", Type: types.Text, Nullable: true},
+	&sql.Column{Name: "origin_committer", Type: types.Text, Nullable: true},
+	&sql.Column{Name: "origin_date", Type: types.Datetime, Nullable: true},
+	&sql.Column{Name: "origin_message", Type: types.LongText, Nullable: true},
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (vo *ViolationOriginsTableFunction) NewInstance(ctx *sql.Context, db sql.Database, expressions []sql.Expression) (sql.Node, error) {
+	newInstance := &ViolationOriginsTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(expressions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (vo *ViolationOriginsTableFunction) Database() sql.Database {
+	return vo.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (vo *ViolationOriginsTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	nvo := *vo
+	nvo.database = database
+	return &nvo, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (vo *ViolationOriginsTableFunction) Name() string {
+	return "dolt_violation_origins"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (vo *ViolationOriginsTableFunction) Resolved() bool {
+	return vo.tableNameExpr.Resolved()
+}
+
+func (vo *ViolationOriginsTableFunction) IsReadOnly() bool {
+	return true
+}
+
+// String implements the Stringer interface
+func (vo *ViolationOriginsTableFunction) String() string {
+	return fmt.Sprintf("DOLT_VIOLATION_ORIGINS(%s)", vo.tableNameExpr.String())
+}
+
+// Schema implements the sql.Node interface.
+func (vo *ViolationOriginsTableFunction) Schema() sql.Schema {
+	return violationOriginsTableSchema
+}
+
+// Children implements the sql.Node interface.
+func (vo *ViolationOriginsTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (vo *ViolationOriginsTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return vo, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (vo *ViolationOriginsTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tableName, err := vo.evaluateArguments()
+	if err != nil {
+		return false
+	}
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(vo.database.Name(), tableName, "", sql.PrivilegeType_Select))
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (vo *ViolationOriginsTableFunction) Expressions() []sql.Expression {
+	return []sql.Expression{vo.tableNameExpr}
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (vo *ViolationOriginsTableFunction) WithExpressions(expression ...sql.Expression) (sql.Node, error) {
+	if len(expression) != 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New(vo.Name(), "1", len(expression))
+	}
+
+	expr := expression[0]
+	if !expr.Resolved() {
+		return nil, ErrInvalidNonLiteralArgument.New(vo.Name(), expr.String())
+	}
+	// prepared statements resolve functions beforehand, so above check fails
+	if _, ok := expr.(sql.FunctionExpression); ok {
+		return nil, ErrInvalidNonLiteralArgument.New(vo.Name(), expr.String())
+	}
+	if !types.IsText(expr.Type()) {
+		return nil, sql.ErrInvalidArgumentDetails.New(vo.Name(), expr.String())
+	}
+
+	nvo := *vo
+	nvo.tableNameExpr = expr
+	return &nvo, nil
+}
+
+// evaluateArguments returns the name of the table whose violations are listed.
+func (vo *ViolationOriginsTableFunction) evaluateArguments() (string, error) {
+	tableNameVal, err := vo.tableNameExpr.Eval(vo.ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	tableName, ok := tableNameVal.(string)
+	if !ok {
+		return "", ErrInvalidTableName.New(vo.tableNameExpr.String())
+	}
+	return tableName, nil
+}
+
+// RowIter implements the sql.Node interface
+func (vo *ViolationOriginsTableFunction) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	tableName, err := vo.evaluateArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	sqledb, ok := vo.database.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", vo.database)
+	}
+	dbName := sqledb.RevisionQualifiedName()
+	ddb := sqledb.DbData().Ddb
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return nil, fmt.Errorf("unable to get roots for database %s", dbName)
+	}
+	if !dtypes.IsFormat_DOLT(roots.Working.VRW().Format()) {
+		return nil, fmt.Errorf("%s is not supported for this storage format", vo.Name())
+	}
+
+	tbl, tableName, ok, err := roots.Working.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+
+	violations, err := tableViolations(ctx, tbl)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) == 0 {
+		return sql.RowsToRowIter(), nil
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	kd, _ := sch.GetMapDescriptors()
+
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	head, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	// replay the changes of each source once, for all the violations they introduced
+	pending := make(map[hash.Hash]*set.StrSet)
+	var sources []hash.Hash
+	for _, v := range violations {
+		if _, ok := pending[v.SourceRootish]; !ok {
+			pending[v.SourceRootish] = set.NewStrSet(nil)
+			sources = append(sources, v.SourceRootish)
+		}
+		pending[v.SourceRootish].Add(merge.ViolationID(v.SourceKey, v.ArtType))
+	}
+
+	branches := make(map[hash.Hash]string)
+	origins := make(map[string]*doltdb.Commit)
+	for _, source := range sources {
+		branches[source], err = dtables.ViolationSourceBranch(ctx, ddb, ws, source)
+		if err != nil {
+			return nil, err
+		}
+
+		replay, ok, err := violationReplayForSource(ctx, ddb, ws, head, source)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		replay.TableName = tableName
+		replay.Opts = dbState.EditOpts()
+
+		found, err := replay.FindOrigins(ctx, pending[source])
+		if err != nil {
+			return nil, err
+		}
+		for id, cm := range found {
+			origins[id] = cm
+		}
+	}
+
+	rows := make([]sql.Row, len(violations))
+	for i, v := range violations {
+		row := sql.Row{
+			v.SourceRootish.String(),
+			violationTypeName(v.ArtType),
+			kd.Format(v.SourceKey),
+			nil, nil, nil, nil, nil,
+		}
+		if branch := branches[v.SourceRootish]; len(branch) > 0 {
+			row[3] = branch
+		}
+		if cm, ok := origins[merge.ViolationID(v.SourceKey, v.ArtType)]; ok {
+			h, err := cm.HashOf()
+			if err != nil {
+				return nil, err
+			}
+			meta, err := cm.GetCommitMeta(ctx)
+			if err != nil {
+				return nil, err
+			}
+			row[4], row[5], row[6], row[7] = h.String(), meta.Name, meta.Time(), meta.Description
+		}
+		rows[i] = row
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// tableViolations returns the constraint violations of |tbl|.
+func tableViolations(ctx *sql.Context, tbl *doltdb.Table) ([]prolly.Artifact, error) {
+	arts, err := tbl.GetArtifacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	itr, err := durable.ProllyMapFromArtifactIndex(arts).IterAllCVs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []prolly.Artifact
+	for {
+		art, err := itr.Next(ctx)
+		if err == io.EOF {
+			return violations, nil
+		} else if err != nil {
+			return nil, err
+		}
+		violations = append(violations, art)
+	}
+}
+
+// violationReplayForSource returns how to replay the changes of |source|, which introduced some constraint violations
+// into the working set |ws|. Violations introduced by the merge in progress are replayed by merging into the working
+// set as it was before the merge, and violations introduced by a merge that was committed anyway are replayed by
+// merging into the first parent of that merge commit. Violations whose source is the head commit were found by
+// verifying constraints, and are replayed by verifying constraints. Returns false if |source| isn't a commit, or if
+// it's unknown which commit it was merged into.
+func violationReplayForSource(ctx *sql.Context, ddb *doltdb.DoltDB, ws *doltdb.WorkingSet, head *doltdb.Commit, source hash.Hash) (merge.ViolationReplay, bool, error) {
+	cm, err := ddb.ReadCommit(ctx, source)
+	if err != nil {
+		// the violations were introduced by a working set, such as when a transaction was merged
+		return merge.ViolationReplay{}, false, nil
+	}
+	replay := merge.ViolationReplay{Source: cm}
+
+	if ws.MergeActive() {
+		h, err := ws.MergeState().Commit().HashOf()
+		if err != nil {
+			return merge.ViolationReplay{}, false, err
+		}
+		if h == source {
+			replay.Ours, replay.OurRoot = head, ws.MergeState().PreMergeWorkingRoot()
+			return replay, true, nil
+		}
+	}
+
+	headHash, err := head.HashOf()
+	if err != nil {
+		return merge.ViolationReplay{}, false, err
+	}
+	if headHash == source {
+		return replay, true, nil
+	}
+
+	for c := head; c.NumParents() > 0; {
+		parents, err := c.ParentHashes(ctx)
+		if err != nil {
+			return merge.ViolationReplay{}, false, err
+		}
+		if len(parents) > 1 && parents[1] == source {
+			replay.Ours, err = c.GetParent(ctx, 0)
+			if err != nil {
+				return merge.ViolationReplay{}, false, err
+			}
+			replay.OurRoot, err = replay.Ours.GetRootValue(ctx)
+			if err != nil {
+				return merge.ViolationReplay{}, false, err
+			}
+			return replay, true, nil
+		}
+		c, err = c.GetParent(ctx, 0)
+		if err != nil {
+			return merge.ViolationReplay{}, false, err
+		}
+	}
+
+	return merge.ViolationReplay{}, false, nil
+}
+
+func violationTypeName(artType prolly.ArtifactType) string {
+	switch artType {
+	case prolly.ArtifactTypeForeignKeyViol:
+		return "foreign key"
+	case prolly.ArtifactTypeUniqueKeyViol:
+		return "unique index"
+	case prolly.ArtifactTypeChkConsViol:
+		return "check constraint"
+	case prolly.ArtifactTypeNullViol:
+		return "not null"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,568 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+)
+
+// doltApplyPatch is the stored procedure dolt_apply_patch(), which applies a patch to the current working set. The
+// patch is either the SQL statements produced by the dolt_patch() table function, concatenated in order, or a JSON
+// array of hunks of the form
+//
+//	{"table": "t", "type": "update", "key": {"pk": 1}, "before": {"pk": 1, "c": 1}, "after": {"pk": 1, "c": 2}}
+//
+// where |type| is one of "insert", "update" or "delete". Only INSERT, UPDATE and DELETE statements against tables in the
+// current database are accepted, and they're run by the session's engine, so the caller's privileges apply. Each hunk is checked against the working set before it's
+// applied: an inserted row must not already exist, and an updated or deleted row must exist and, for JSON hunks,
+// match the hunk's |before| image. Hunks that fail the check or can't be applied are skipped, and are listed in the
+// dolt_patch_rejects system table until the next patch is applied. Returns the number of hunks applied and rejected.
+//
+// Given two revisions instead, dolt_apply_patch() applies the changes between them to a branch and commits the result.
+// See applyRevisionPatch.
+func doltApplyPatch(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	applied, rejected, err := doDoltApplyPatch(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(applied), int64(rejected)), nil
+}
+
+func doDoltApplyPatch(ctx *sql.Context, args []string) (int, int, error) {
+	if len(args) == 0 {
+		return 0, 0, InvalidArgErr
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 0, 0, fmt.Errorf("Empty database name.")
+	}
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return 0, 0, err
+	}
+
+	// a single argument is the text of a patch, and anything else names the revisions a patch is between
+	if len(args) > 1 {
+		applied, err := applyRevisionPatch(ctx, dbName, args)
+		return applied, 0, err
+	}
+
+	var hunks []patchHunk
+	var err error
+	patch := strings.TrimSpace(args[0])
+	if strings.HasPrefix(patch, "[") {
+		hunks, err = parseJSONPatch(patch)
+	} else {
+		hunks, err = parseSQLPatch(ctx, dbName, patch)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// The hunks are applied as part of this statement, and must not commit its transaction out from under it.
+	ignore := ctx.GetIgnoreAutoCommit()
+	ctx.SetIgnoreAutoCommit(true)
+	defer ctx.SetIgnoreAutoCommit(ignore)
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	engine := dsess.StatementRunnerFor(dSess.Provider())
+	if engine == nil {
+		return 0, 0, fmt.Errorf("error: dolt_apply_patch requires a running SQL engine")
+	}
+
+	var rejects []dsess.PatchReject
+	for i, h := range hunks {
+		reason, err := h.apply(ctx, engine)
+		if err != nil {
+			return 0, 0, err
+		}
+		if len(reason) > 0 {
+			rejects = append(rejects, dsess.PatchReject{Hunk: i + 1, TableName: h.tableName(), Patch: h.String(), Reason: reason})
+		}
+	}
+
+	baseName, _ := dsess.SplitRevisionDbName(dbName)
+	dSess.SetPatchRejects(baseName, rejects)
+
+	return len(hunks) - len(rejects), len(rejects), nil
+}
+
+// applyRevisionPatch applies the changes between two revisions to a branch, the current branch unless another is named
+// with --branch, and commits them. The patch is applied with a three-way merge of the branch and the patch's |to|
+// revision, using its |from| revision as the ancestor, so it's applied as a whole or not at all: if any of its changes
+// conflict with the branch, nothing is applied. The branch must not have uncommitted changes. The commit message
+// records the commits the revisions resolved to, and the number of rows changed is returned.
+func applyRevisionPatch(ctx *sql.Context, dbName string, args []string) (int, error) {
+	apr, err := cli.CreateApplyPatchArgParser().Parse(args)
+	if err != nil {
+		return 0, err
+	}
+	if apr.NArg() != 2 {
+		return 0, InvalidArgErr
+	}
+	fromStr, toStr := apr.Arg(0), apr.Arg(1)
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	targetDb := dbName
+	if branch, ok := apr.GetValue(cli.BranchParam); ok {
+		ddb, ok := dSess.GetDoltDB(ctx, dbName)
+		if !ok {
+			return 0, sql.ErrDatabaseNotFound.New(dbName)
+		}
+		hasRef, err := ddb.HasRef(ctx, ref.NewBranchRef(branch))
+		if err != nil {
+			return 0, err
+		} else if !hasRef {
+			return 0, fmt.Errorf("%w: %s", doltdb.ErrBranchNotFound, branch)
+		}
+		if err = branch_control.CanModifyBranch(ctx, branch); err != nil {
+			return 0, err
+		}
+		baseName, _ := dsess.SplitRevisionDbName(dbName)
+		targetDb = dsess.RevisionDbName(baseName, branch)
+	}
+
+	headRef, err := dSess.CWBHeadRef(ctx, targetDb)
+	if err != nil {
+		return 0, err
+	}
+	branch := headRef.GetPath()
+	roots, ok := dSess.GetRoots(ctx, targetDb)
+	if !ok {
+		return 0, sql.ErrDatabaseNotFound.New(targetDb)
+	}
+	clean, err := diff.WorkingSetContainsOnlyIgnoredTables(ctx, roots)
+	if err != nil {
+		return 0, err
+	} else if !clean {
+		return 0, fmt.Errorf("error: branch '%s' has uncommitted changes; commit or reset them before applying a patch", branch)
+	}
+
+	ddb, ok := dSess.GetDoltDB(ctx, targetDb)
+	if !ok {
+		return 0, sql.ErrDatabaseNotFound.New(targetDb)
+	}
+	fromCommit, fromRoot, err := resolvePatchRevision(ctx, ddb, headRef, fromStr)
+	if err != nil {
+		return 0, err
+	}
+	toCommit, toRoot, err := resolvePatchRevision(ctx, ddb, headRef, toStr)
+	if err != nil {
+		return 0, err
+	}
+
+	dbState, ok, err := dSess.LookupDbState(ctx, targetDb)
+	if err != nil {
+		return 0, err
+	} else if !ok {
+		return 0, sql.ErrDatabaseNotFound.New(targetDb)
+	}
+	result, err := merge.MergeRoots(ctx, roots.Working, toRoot, fromRoot, toCommit, fromCommit, dbState.EditOpts(), merge.MergeOpts{IsCherryPick: true})
+	if err != nil {
+		return 0, fmt.Errorf("error: the patch from %s to %s could not be applied to branch '%s': %w", fromStr, toStr, branch, err)
+	}
+	if result.HasMergeArtifacts() {
+		tables := set.NewStrSet(nil)
+		for tableName, stats := range result.Stats {
+			if stats.HasArtifacts() {
+				tables.Add(tableName)
+			}
+		}
+		for _, conflict := range result.SchemaConflicts {
+			tables.Add(conflict.TableName)
+		}
+		names := tables.AsSlice()
+		sort.Strings(names)
+		return 0, fmt.Errorf("error: the patch from %s to %s conflicts with branch '%s' in tables: %s; no changes were made",
+			fromStr, toStr, branch, strings.Join(names, ", "))
+	}
+
+	headHash, err := roots.Head.HashOf()
+	if err != nil {
+		return 0, err
+	}
+	mergedHash, err := result.Root.HashOf()
+	if err != nil {
+		return 0, err
+	}
+	if headHash == mergedHash {
+		return 0, fmt.Errorf("error: the patch from %s to %s makes no changes to branch '%s'", fromStr, toStr, branch)
+	}
+
+	fromHash, err := fromCommit.HashOf()
+	if err != nil {
+		return 0, err
+	}
+	toHash, err := toCommit.HashOf()
+	if err != nil {
+		return 0, err
+	}
+	msg, ok := apr.GetValue(cli.MessageArg)
+	if !ok {
+		msg = fmt.Sprintf("Apply patch from %s to %s", fromStr, toStr)
+	}
+	msg = fmt.Sprintf("%s\n\nPatch-From: %s\nPatch-To: %s", msg, fromHash.String(), toHash.String())
+
+	roots.Working = result.Root
+	roots.Staged = result.Root
+	name := ctx.Client().User
+	email := fmt.Sprintf("%s@%s", ctx.Client().User, ctx.Client().Address)
+	pendingCommit, err := dSess.NewPendingCommit(ctx, targetDb, roots, actions.CommitStagedProps{
+		Message: msg,
+		Date:    ctx.QueryTime(),
+		Name:    name,
+		Email:   email,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err = runCommitHooks(ctx, dSess, targetDb, name, msg); err != nil {
+		return 0, err
+	}
+	if _, err = dSess.DoltCommit(ctx, targetDb, dSess.GetTransaction(), pendingCommit); err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, stats := range result.Stats {
+		applied += stats.Adds + stats.Modifications + stats.Deletes
+	}
+	return applied, nil
+}
+
+// resolvePatchRevision returns the commit that the revision |rev| of a patch names, and its root value.
+func resolvePatchRevision(ctx *sql.Context, ddb *doltdb.DoltDB, headRef ref.DoltRef, rev string) (*doltdb.Commit, *doltdb.RootValue, error) {
+	cs, err := doltdb.NewCommitSpec(rev)
+	if err != nil {
+		return nil, nil, err
+	}
+	commit, err := ddb.Resolve(ctx, cs, headRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	root, err := commit.GetRootValue(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return commit, root, nil
+}
+
+// patchHunk is a single change in a patch.
+type patchHunk interface {
+	fmt.Stringer
+	tableName() string
+	// apply checks the hunk against the working set and applies it. If the hunk is rejected, it returns why.
+	apply(ctx *sql.Context, engine dsess.StatementRunner) (string, error)
+}
+
+// sqlPatchHunk is a single statement of the patch produced by dolt_patch().
+type sqlPatchHunk struct {
+	query string
+	stmt  sqlparser.Statement
+}
+
+// parseSQLPatch parses the statements of |patch|, which must each insert, update or delete rows of a single table in
+// the database named |dbName|.
+func parseSQLPatch(ctx *sql.Context, dbName string, patch string) ([]patchHunk, error) {
+	options := sql.LoadSqlMode(ctx).ParserOptions()
+
+	var hunks []patchHunk
+	for len(strings.TrimSpace(patch)) > 0 {
+		stmt, ri, err := sqlparser.ParseOneWithOptions(patch, options)
+		if err == sqlparser.ErrEmpty {
+			break
+		} else if err != nil {
+			// parser errors include their error code when formatted with %v
+			return nil, fmt.Errorf("error: could not parse patch statement %d: %s", len(hunks)+1, err.Error())
+		}
+
+		query := patch
+		patch = ""
+		if ri != 0 && ri < len(query) {
+			query, patch = query[:ri], query[ri:]
+		}
+		query = strings.TrimRight(strings.TrimSpace(query), ";")
+		h := sqlPatchHunk{query: query, stmt: stmt}
+		if err = h.validate(dbName); err != nil {
+			return nil, fmt.Errorf("error: patch statement %d %w", len(hunks)+1, err)
+		}
+		hunks = append(hunks, h)
+	}
+	return hunks, nil
+}
+
+func (h sqlPatchHunk) String() string {
+	return h.query + ";"
+}
+
+func (h sqlPatchHunk) tableName() string {
+	tn, _ := h.table()
+	return tn.Name.String()
+}
+
+// table returns the single table the hunk's statement writes to, if it has one.
+func (h sqlPatchHunk) table() (sqlparser.TableName, bool) {
+	var tableExprs sqlparser.TableExprs
+	switch stmt := h.stmt.(type) {
+	case *sqlparser.Insert:
+		return stmt.Table, true
+	case *sqlparser.Update:
+		tableExprs = stmt.TableExprs
+	case *sqlparser.Delete:
+		if len(stmt.Targets) > 0 {
+			return sqlparser.TableName{}, false
+		}
+		tableExprs = stmt.TableExprs
+	}
+	if len(tableExprs) == 1 {
+		if ate, ok := tableExprs[0].(*sqlparser.AliasedTableExpr); ok {
+			if tn, ok := ate.Expr.(sqlparser.TableName); ok {
+				return tn, true
+			}
+		}
+	}
+	return sqlparser.TableName{}, false
+}
+
+// validate returns an error if the hunk's statement isn't an INSERT, UPDATE or DELETE of a single table in the database
+// named |dbName|. An INSERT must insert a list of values, rather than the results of a query.
+func (h sqlPatchHunk) validate(dbName string) error {
+	switch stmt := h.stmt.(type) {
+	case *sqlparser.Insert:
+		if _, ok := stmt.Rows.(sqlparser.Values); !ok {
+			return fmt.Errorf("must insert a list of values")
+		}
+	case *sqlparser.Update, *sqlparser.Delete:
+	default:
+		return fmt.Errorf("is not an INSERT, UPDATE or DELETE statement")
+	}
+
+	tn, ok := h.table()
+	if !ok {
+		return fmt.Errorf("must change a single table")
+	}
+	if qualifier := tn.Qualifier.String(); len(qualifier) > 0 {
+		baseName, _ := dsess.SplitRevisionDbName(dbName)
+		if !strings.EqualFold(qualifier, dbName) && !strings.EqualFold(qualifier, baseName) {
+			return fmt.Errorf("changes table '%s' outside of database '%s'", sqlparser.String(tn), dbName)
+		}
+	}
+	return nil
+}
+
+func (h sqlPatchHunk) apply(ctx *sql.Context, engine dsess.StatementRunner) (string, error) {
+	rows, err := runQuery(ctx, engine, h.query)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	switch h.stmt.(type) {
+	case *sqlparser.Update:
+		if okResult, ok := patchOkResult(rows); ok {
+			if info, ok := okResult.Info.(plan.UpdateInfo); ok && info.Matched == 0 {
+				return "no row matches the statement", nil
+			}
+		}
+	case *sqlparser.Delete:
+		if okResult, ok := patchOkResult(rows); ok && okResult.RowsAffected == 0 {
+			return "no row matches the statement", nil
+		}
+	}
+	return "", nil
+}
+
+// jsonPatchHunk is a single hunk of a JSON patch.
+type jsonPatchHunk struct {
+	Table  string                 `json:"table"`
+	Type   string                 `json:"type"`
+	Key    map[string]interface{} `json:"key"`
+	Before map[string]interface{} `json:"before,omitempty"`
+	After  map[string]interface{} `json:"after,omitempty"`
+}
+
+func parseJSONPatch(patch string) ([]patchHunk, error) {
+	dec := json.NewDecoder(strings.NewReader(patch))
+	dec.UseNumber()
+
+	var jsonHunks []jsonPatchHunk
+	err := dec.Decode(&jsonHunks)
+	if err != nil {
+		return nil, fmt.Errorf("error: could not parse JSON patch: %w", err)
+	}
+
+	hunks := make([]patchHunk, len(jsonHunks))
+	for i, h := range jsonHunks {
+		if len(h.Table) == 0 {
+			return nil, fmt.Errorf("error: JSON patch hunk %d has no table", i+1)
+		}
+		if len(h.Key) == 0 {
+			return nil, fmt.Errorf("error: JSON patch hunk %d has no key", i+1)
+		}
+		switch h.Type {
+		case "insert":
+			if len(h.After) == 0 {
+				return nil, fmt.Errorf("error: JSON patch hunk %d inserts a row, but has no after image", i+1)
+			}
+		case "update":
+			if len(h.After) == 0 {
+				return nil, fmt.Errorf("error: JSON patch hunk %d updates a row, but has no after image", i+1)
+			}
+		case "delete":
+		default:
+			return nil, fmt.Errorf("error: JSON patch hunk %d has unknown type '%s'", i+1, h.Type)
+		}
+		hunks[i] = h
+	}
+	return hunks, nil
+}
+
+func (h jsonPatchHunk) String() string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(h)
+	return strings.TrimSpace(buf.String())
+}
+
+func (h jsonPatchHunk) tableName() string {
+	return h.Table
+}
+
+func (h jsonPatchHunk) apply(ctx *sql.Context, engine dsess.StatementRunner) (string, error) {
+	// check the preimage: an inserted row must not exist, and an updated or deleted row must match its before image
+	image := h.Before
+	if h.Type == "insert" || len(image) == 0 {
+		image = h.Key
+	}
+	where, whereArgs := patchWhereClause(image)
+	query, err := dbr.InterpolateForDialect(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", quoteIdentifier(h.Table), where), whereArgs, dialect.MySQL)
+	if err != nil {
+		return "", err
+	}
+	rows, err := runQuery(ctx, engine, query)
+	if err != nil {
+		return err.Error(), nil
+	}
+	var count int64
+	if len(rows) == 1 && len(rows[0]) == 1 {
+		count, _ = rows[0][0].(int64)
+	}
+	if h.Type == "insert" && count != 0 {
+		return "row already exists", nil
+	} else if h.Type != "insert" && count == 0 {
+		return "row does not match the patch's before image", nil
+	}
+
+	var stmt string
+	var stmtArgs []interface{}
+	switch h.Type {
+	case "insert":
+		cols := sortedKeys(h.After)
+		quoted := make([]string, len(cols))
+		placeholders := make([]string, len(cols))
+		for i, c := range cols {
+			quoted[i] = quoteIdentifier(c)
+			placeholders[i] = "?"
+			stmtArgs = append(stmtArgs, h.After[c])
+		}
+		stmt = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteIdentifier(h.Table), strings.Join(quoted, ", "), strings.Join(placeholders, ", "))
+	case "update":
+		cols := sortedKeys(h.After)
+		sets := make([]string, len(cols))
+		for i, c := range cols {
+			sets[i] = quoteIdentifier(c) + " = ?"
+			stmtArgs = append(stmtArgs, h.After[c])
+		}
+		where, whereArgs := patchWhereClause(h.Key)
+		stmtArgs = append(stmtArgs, whereArgs...)
+		stmt = fmt.Sprintf("UPDATE %s SET %s WHERE %s", quoteIdentifier(h.Table), strings.Join(sets, ", "), where)
+	case "delete":
+		where, whereArgs := patchWhereClause(h.Key)
+		stmtArgs = whereArgs
+		stmt = fmt.Sprintf("DELETE FROM %s WHERE %s", quoteIdentifier(h.Table), where)
+	}
+
+	query, err = dbr.InterpolateForDialect(stmt, stmtArgs, dialect.MySQL)
+	if err != nil {
+		return "", err
+	}
+	_, err = runQuery(ctx, engine, query)
+	if err != nil {
+		return err.Error(), nil
+	}
+	return "", nil
+}
+
+// patchWhereClause returns a WHERE clause matching every column of |image|, along with its arguments.
+func patchWhereClause(image map[string]interface{}) (string, []interface{}) {
+	cols := sortedKeys(image)
+	conds := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+	for i, c := range cols {
+		conds[i] = quoteIdentifier(c) + " <=> ?"
+		args[i] = image[c]
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func quoteIdentifier(id string) string {
+	return "`" + strings.ReplaceAll(id, "`", "``") + "`"
+}
+
+func runQuery(ctx *sql.Context, engine dsess.StatementRunner, query string) ([]sql.Row, error) {
+	_, iter, err := engine.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return sql.RowIterToRows(ctx, nil, iter)
+}
+
+func patchOkResult(rows []sql.Row) (types.OkResult, bool) {
+	if len(rows) != 1 || len(rows[0]) != 1 {
+		return types.OkResult{}, false
+	}
+	okResult, ok := rows[0][0].(types.OkResult)
+	return okResult, ok
+}
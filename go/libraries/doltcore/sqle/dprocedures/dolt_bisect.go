@@ -0,0 +1,289 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var doltBisectSchema = []*sql.Column{
+	{
+		Name:     "next_commit",
+		Type:     types.LongText,
+		Nullable: true,
+	},
+	{
+		Name:     "first_bad_commit",
+		Type:     types.LongText,
+		Nullable: true,
+	},
+	{
+		Name:     "message",
+		Type:     types.LongText,
+		Nullable: false,
+	},
+}
+
+// doltBisect is the stored procedure dolt_bisect(), which searches the history of the current branch for the commit
+// that introduced a change, by binary search between commits marked good and bad.
+//
+//	dolt_bisect('start', [<bad> [<good>...]])
+//	dolt_bisect('bad', [<commit>])
+//	dolt_bisect('good', [<commit>...])
+//	dolt_bisect('skip', [<commit>...])
+//	dolt_bisect('status')
+//	dolt_bisect('reset')
+//
+// Once a good and a bad commit are marked, every form but reset returns the commit to test next, which can be queried
+// as the revision database <db>/<commit>. good, bad and skip mark that commit when none is given. When a single
+// candidate is left, it's returned as the first bad commit instead. The session is recorded in the database, against
+// the branch it was started on, until it's reset.
+func doltBisect(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltBisect(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(nullableString(res.next), nullableString(res.firstBad), res.message), nil
+}
+
+// bisectResult is the row returned by dolt_bisect().
+type bisectResult struct {
+	next     string
+	firstBad string
+	message  string
+}
+
+func nullableString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func doDoltBisect(ctx *sql.Context, args []string) (bisectResult, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return bisectResult{}, fmt.Errorf("Empty database name.")
+	}
+
+	apr, err := cli.CreateBisectArgParser().Parse(args)
+	if err != nil {
+		return bisectResult{}, err
+	}
+	if apr.NArg() == 0 {
+		return bisectResult{}, InvalidArgErr
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return bisectResult{}, fmt.Errorf("Could not load database %s", dbName)
+	}
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return bisectResult{}, err
+	}
+	branch := headRef.GetPath()
+
+	state, err := ddb.GetBisectState(ctx, branch)
+	if err != nil {
+		return bisectResult{}, err
+	}
+
+	var rsc doltdb.ReplicationStatusController
+	subcommand, revs := apr.Arg(0), apr.Args[1:]
+	if subcommand == "start" {
+		if state != nil {
+			return bisectResult{}, fmt.Errorf("error: a bisect session is already in progress on branch '%s', use dolt_bisect('reset') to end it", branch)
+		}
+		state = &doltdb.BisectState{}
+		if len(revs) > 0 {
+			state.Bad, err = resolveCommitHash(ctx, ddb, headRef, revs[0])
+			if err != nil {
+				return bisectResult{}, err
+			}
+			state.Good, err = resolveCommitHashes(ctx, ddb, headRef, revs[1:])
+			if err != nil {
+				return bisectResult{}, err
+			}
+		}
+	} else {
+		if state == nil {
+			return bisectResult{}, fmt.Errorf("error: no bisect session is in progress on branch '%s', use dolt_bisect('start') to start one", branch)
+		}
+		// good, bad and skip mark the commit being tested when no commit is given
+		if len(revs) == 0 && (subcommand == "good" || subcommand == "bad" || subcommand == "skip") {
+			if state.Current == "" {
+				return bisectResult{}, fmt.Errorf("error: no commit is being tested, name the commit to mark as %s", subcommand)
+			}
+			revs = []string{state.Current}
+		}
+
+		switch subcommand {
+		case "bad":
+			if len(revs) != 1 {
+				return bisectResult{}, fmt.Errorf("error: only one commit can be marked as bad")
+			}
+			state.Bad, err = resolveCommitHash(ctx, ddb, headRef, revs[0])
+			if err != nil {
+				return bisectResult{}, err
+			}
+		case "good":
+			good, err := resolveCommitHashes(ctx, ddb, headRef, revs)
+			if err != nil {
+				return bisectResult{}, err
+			}
+			state.Good = append(state.Good, good...)
+		case "skip":
+			skip, err := resolveCommitHashes(ctx, ddb, headRef, revs)
+			if err != nil {
+				return bisectResult{}, err
+			}
+			state.Skip = append(state.Skip, skip...)
+		case "reset", "status":
+			if len(revs) != 0 {
+				return bisectResult{}, InvalidArgErr
+			}
+		default:
+			return bisectResult{}, fmt.Errorf("error: invalid argument '%s'", subcommand)
+		}
+
+		if subcommand == "reset" {
+			err = ddb.DeleteBisectState(ctx, branch, &rsc)
+			if err != nil {
+				return bisectResult{}, err
+			}
+			dsess.WaitForReplicationController(ctx, rsc)
+			return bisectResult{message: fmt.Sprintf("ended the bisect session on branch '%s'", branch)}, nil
+		}
+	}
+
+	step, err := actions.NextBisectStep(ctx, ddb, *state)
+	if err != nil {
+		return bisectResult{}, err
+	}
+	res, err := bisectStepResult(step, *state)
+	if err != nil {
+		return bisectResult{}, err
+	}
+	if subcommand == "status" {
+		return res, nil
+	}
+
+	state.Current = res.next
+	addr, err := bisectStateAddr(ctx, ddb, headRef, *state)
+	if err != nil {
+		return bisectResult{}, err
+	}
+	err = ddb.SetBisectState(ctx, branch, *state, addr, datas.NewTagMeta(dSess.Username(), dSess.Email(), ""), &rsc)
+	if err != nil {
+		return bisectResult{}, err
+	}
+	dsess.WaitForReplicationController(ctx, rsc)
+
+	return res, nil
+}
+
+// bisectStepResult returns the row that dolt_bisect() returns for |step|.
+func bisectStepResult(step actions.BisectStep, state doltdb.BisectState) (bisectResult, error) {
+	switch {
+	case step.Next != nil:
+		h, err := step.Next.HashOf()
+		if err != nil {
+			return bisectResult{}, err
+		}
+		return bisectResult{
+			next:    h.String(),
+			message: fmt.Sprintf("Bisecting: %d revisions left to test after this (roughly %d steps)", step.Remaining, step.Steps()),
+		}, nil
+	case step.FirstBad != nil:
+		h, err := step.FirstBad.HashOf()
+		if err != nil {
+			return bisectResult{}, err
+		}
+		return bisectResult{firstBad: h.String(), message: fmt.Sprintf("%s is the first bad commit", h.String())}, nil
+	case len(step.Candidates) > 0:
+		var sb strings.Builder
+		sb.WriteString("There are only skipped commits left to test.\nThe first bad commit could be any of:")
+		for _, cm := range step.Candidates {
+			h, err := cm.HashOf()
+			if err != nil {
+				return bisectResult{}, err
+			}
+			sb.WriteString("\n")
+			sb.WriteString(h.String())
+		}
+		return bisectResult{message: sb.String()}, nil
+	case state.Bad != "":
+		return bisectResult{message: "status: waiting for good commit(s), bad commit known"}, nil
+	case len(state.Good) > 0:
+		return bisectResult{message: "status: waiting for bad commit, good commit(s) known"}, nil
+	default:
+		return bisectResult{message: "status: waiting for both good and bad commits"}, nil
+	}
+}
+
+// bisectStateAddr returns the commit that the bisect session's state is recorded against: the bad commit once it's
+// known, which keeps the commits being searched from being collected, and the head of the branch until then.
+func bisectStateAddr(ctx *sql.Context, ddb *doltdb.DoltDB, headRef ref.DoltRef, state doltdb.BisectState) (hash.Hash, error) {
+	if state.Bad != "" {
+		return hash.Parse(state.Bad), nil
+	}
+	cm, err := ddb.ResolveCommitRef(ctx, headRef)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	return cm.HashOf()
+}
+
+func resolveCommitHashes(ctx *sql.Context, ddb *doltdb.DoltDB, headRef ref.DoltRef, revs []string) ([]string, error) {
+	hashes := make([]string, len(revs))
+	for i, rev := range revs {
+		h, err := resolveCommitHash(ctx, ddb, headRef, rev)
+		if err != nil {
+			return nil, err
+		}
+		hashes[i] = h
+	}
+	return hashes, nil
+}
+
+func resolveCommitHash(ctx *sql.Context, ddb *doltdb.DoltDB, headRef ref.DoltRef, rev string) (string, error) {
+	cs, err := doltdb.NewCommitSpec(rev)
+	if err != nil {
+		return "", err
+	}
+	cm, err := ddb.Resolve(ctx, cs, headRef)
+	if err != nil {
+		return "", err
+	}
+	h, err := cm.HashOf()
+	if err != nil {
+		return "", err
+	}
+	return h.String(), nil
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
@@ -31,11 +32,14 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqlserver"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
 )
 
 var (
 	EmptyBranchNameErr = errors.New("error: cannot branch empty string")
 	InvalidArgErr      = errors.New("error: invalid usage")
+	BranchInUseErr     = errors.New("unsafe to delete or rename branches in use in other sessions; " +
+		"use --force to force the change")
 )
 
 // doltBranch is the stored procedure version for the CLI command `dolt branch`.
@@ -78,6 +82,8 @@ func doDoltBranch(ctx *sql.Context, args []string) (int, error) {
 		err = renameBranch(ctx, dbData, apr, dSess, dbName, &rsc)
 	case apr.Contains(cli.DeleteFlag), apr.Contains(cli.DeleteForceFlag):
 		err = deleteBranches(ctx, dbData, apr, dSess, dbName, &rsc)
+	case apr.Contains(cli.DescribeParam), apr.Contains(cli.MetadataParam):
+		err = describeBranch(ctx, dbData, apr, dSess, &rsc)
 	default:
 		err = createNewBranch(ctx, dbData, apr, &rsc)
 	}
@@ -161,6 +167,10 @@ func renameBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseRe
 	if err != nil {
 		return err
 	}
+	err = branch_control.CopyBranchPermissions(ctx, oldBranchName, newBranchName, true)
+	if err != nil {
+		return err
+	}
 	err = branch_control.AddAdminForContext(ctx, newBranchName)
 	if err != nil {
 		return err
@@ -182,6 +192,11 @@ func renameBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseRe
 		return err
 	}
 
+	err = moveBranchInOtherSessions(ctx, dbName, oldBranchName, newBranchName)
+	if err != nil {
+		return err
+	}
+
 	// If the active branch of the SQL session was renamed, switch to the new branch.
 	if oldBranchName == activeSessionBranch {
 		wsRef, err := ref.WorkingSetRefForHead(ref.NewBranchRef(newBranchName))
@@ -331,10 +346,37 @@ func validateBranchNotActiveInAnySession(ctx *sql.Context, branchName string) er
 		}
 
 		if ref.Equals(branchRef, activeBranchRef) {
-			return false, fmt.Errorf("unsafe to delete or rename branches in use in other sessions; " +
-				"use --force to force the change")
+			return false, BranchInUseErr
+		}
+
+		return false, nil
+	})
+}
+
+// moveBranchInOtherSessions invalidates the state every other server session has for |oldBranchName|, pointing those
+// that have it checked out at |newBranchName| instead, so that renaming a branch with --force doesn't leave them on a
+// branch that no longer exists.
+func moveBranchInOtherSessions(ctx *sql.Context, dbName, oldBranchName, newBranchName string) error {
+	if !sqlserver.RunningInServerMode() {
+		return nil
+	}
+
+	runningServer, _ := sqlserver.GetRunningServer()
+	if runningServer == nil {
+		return nil
+	}
+
+	return runningServer.SessionManager().Iter(func(session sql.Session) (bool, error) {
+		if session.ID() == ctx.Session.ID() {
+			return false, nil
+		}
+
+		sess, ok := session.(*dsess.DoltSession)
+		if !ok {
+			return false, fmt.Errorf("unexpected session type: %T", session)
 		}
 
+		sess.InvalidateMovedBranch(dbName, oldBranchName, newBranchName)
 		return false, nil
 	})
 }
@@ -401,6 +443,14 @@ func createNewBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgPars
 		}
 	}
 
+	var ttl time.Duration
+	if expires, ok := apr.GetValue(cli.ExpiresParam); ok {
+		ttl, err = actions.ParseBranchTTL(expires)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = branch_control.CanCreateBranch(ctx, branchName)
 	if err != nil {
 		return err
@@ -411,6 +461,21 @@ func createNewBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgPars
 		return err
 	}
 
+	// a forced create over an existing branch replaces its expiration, if it had one
+	if ttl != 0 || apr.Contains(cli.ForceFlag) {
+		var expiration env.BranchExpiration
+		if ttl != 0 {
+			// the user that set the expiration is notified when the branch expires
+			sess := dsess.DSessFromSess(ctx.Session)
+			expiration.Expires = ctx.QueryTime().Add(ttl)
+			expiration.Owner = fmt.Sprintf("%s <%s>", sess.Username(), sess.Email())
+		}
+		err = dbData.Rsw.SetBranchExpiration(branchName, expiration)
+		if err != nil {
+			return err
+		}
+	}
+
 	if setTrackUpstream {
 		// at this point new branch is created
 		err = env.SetRemoteUpstreamForRefSpec(dbData.Rsw, refSpec, remoteName, ref.NewBranchRef(branchName))
@@ -422,6 +487,67 @@ func createNewBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgPars
 	return nil
 }
 
+// describeBranch sets the description and metadata values of the branch named, or the current branch if none is named.
+func describeBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseResults, sess *dsess.DoltSession, rsc *doltdb.ReplicationStatusController) error {
+	var branchRef ref.DoltRef
+	switch apr.NArg() {
+	case 0:
+		headRef, err := dbData.Rsr.CWBHeadRef()
+		if err != nil {
+			return err
+		}
+		branchRef = headRef
+	case 1:
+		if len(apr.Arg(0)) == 0 {
+			return EmptyBranchNameErr
+		}
+		branchRef = ref.NewBranchRef(apr.Arg(0))
+	default:
+		return InvalidArgErr
+	}
+
+	err := branch_control.CanModifyBranch(ctx, branchRef.GetPath())
+	if err != nil {
+		return err
+	}
+
+	hasRef, err := dbData.Ddb.HasRef(ctx, branchRef)
+	if err != nil {
+		return err
+	} else if !hasRef {
+		return fmt.Errorf("%w: %s", doltdb.ErrBranchNotFound, branchRef.GetPath())
+	}
+
+	md, _, err := dbData.Ddb.GetBranchMetadata(ctx, branchRef)
+	if err != nil {
+		return err
+	}
+
+	if description, ok := apr.GetValue(cli.DescribeParam); ok {
+		md.Description = description
+	}
+	if values, ok := apr.GetValueList(cli.MetadataParam); ok {
+		for _, kv := range values {
+			key, val, ok := strings.Cut(kv, "=")
+			key = strings.TrimSpace(key)
+			if !ok || len(key) == 0 {
+				return fmt.Errorf("error: invalid metadata '%s', expected key=value", kv)
+			}
+			if len(val) == 0 {
+				delete(md.Values, key)
+				continue
+			}
+			if md.Values == nil {
+				md.Values = make(map[string]string)
+			}
+			md.Values[key] = val
+		}
+	}
+
+	meta := datas.NewTagMeta(sess.Username(), sess.Email(), "")
+	return dbData.Ddb.SetBranchMetadata(ctx, branchRef, md, meta, rsc)
+}
+
 func copyBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseResults, rsc *doltdb.ReplicationStatusController) error {
 	if apr.NArg() != 2 {
 		return InvalidArgErr
@@ -452,7 +578,7 @@ func copyABranch(ctx *sql.Context, dbData env.DbData, srcBr string, destBr strin
 			return err
 		}
 	}
-	err := actions.CopyBranchOnDB(ctx, dbData.Ddb, srcBr, destBr, force, rsc)
+	err := actions.CopyBranchWithState(ctx, dbData, srcBr, destBr, force, rsc)
 	if err != nil {
 		if err == doltdb.ErrBranchNotFound {
 			return fmt.Errorf("fatal: A branch named '%s' not found", srcBr)
@@ -464,6 +590,10 @@ func copyABranch(ctx *sql.Context, dbData env.DbData, srcBr string, destBr strin
 			return fmt.Errorf("fatal: Unexpected error copying branch from '%s' to '%s'", srcBr, destBr)
 		}
 	}
+	err = branch_control.CopyBranchPermissions(ctx, srcBr, destBr, false)
+	if err != nil {
+		return err
+	}
 	err = branch_control.AddAdminForContext(ctx, destBr)
 	if err != nil {
 		return err
@@ -201,7 +201,12 @@ func doDoltCheckout(ctx *sql.Context, args []string) (statusCode int, successMes
 //
 // TODO: This is cribbed heavily from doltdb.*DoltDB.NewBranchAtCommit.
 func createWorkingSetForLocalBranch(ctx *sql.Context, ddb *doltdb.DoltDB, branchName string) error {
-	branchRef := ref.NewBranchRef(branchName)
+	return createWorkingSetForHead(ctx, ddb, ref.NewBranchRef(branchName))
+}
+
+// createWorkingSetForHead will make a new working set for the branch or workspace ref given if one does not already
+// exist, with its working and staged roots set to the ref's head commit.
+func createWorkingSetForHead(ctx *sql.Context, ddb *doltdb.DoltDB, branchRef ref.DoltRef) error {
 	commit, err := ddb.ResolveCommitRef(ctx, branchRef)
 	if err != nil {
 		return err
@@ -31,6 +31,7 @@ import (
 
 var ErrEmptyCherryPick = errors.New("cannot cherry-pick empty string")
 var ErrCherryPickUncommittedChanges = errors.New("cannot cherry-pick with uncommitted changes")
+var ErrCherryPickNoChanges = errors.New("no changes were made, nothing to commit")
 
 var cherryPickSchema = []*sql.Column{
 	{
@@ -56,6 +57,16 @@ var cherryPickSchema = []*sql.Column{
 }
 
 // doltCherryPick is the stored procedure version for the CLI command `dolt cherry-pick`.
+//
+//	dolt_cherry_pick(<commit>...)
+//	dolt_cherry_pick('--continue')
+//	dolt_cherry_pick('--skip')
+//	dolt_cherry_pick('--abort')
+//
+// A range of commits A..B names the commits reachable from B that aren't reachable from A. The commits are
+// cherry-picked oldest first, each one committed, until one of them has conflicts. When several commits are
+// cherry-picked, the cherry-pick is recorded in the database against the current branch until it's done or aborted,
+// so that it can be continued once the conflicts are resolved.
 func doltCherryPick(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 	newCommitHash, dataConflicts, schemaConflicts, constraintViolations, err := doDoltCherryPick(ctx, args)
 	if err != nil {
@@ -84,70 +95,82 @@ func doDoltCherryPick(ctx *sql.Context, args []string) (string, int, int, int, e
 	}
 
 	dSess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return "", 0, 0, 0, fmt.Errorf("failed to get DoltDB")
+	}
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return "", 0, 0, 0, err
+	}
+	state, err := ddb.GetCherryPickState(ctx, headRef.GetPath())
+	if err != nil {
+		return "", 0, 0, 0, err
+	}
 
-	if apr.Contains(cli.AbortParam) {
-		ws, err := dSess.WorkingSet(ctx, dbName)
-		if err != nil {
-			return "", 0, 0, 0, fmt.Errorf("fatal: unable to load working set: %v", err)
-		}
-
-		if !ws.MergeActive() {
-			return "", 0, 0, 0, fmt.Errorf("error: There is no cherry-pick merge to abort")
-		}
-
-		roots, ok := dSess.GetRoots(ctx, dbName)
-		if !ok {
-			return "", 0, 0, 0, fmt.Errorf("fatal: unable to load roots for %s", dbName)
-		}
-
-		newWs, err := abortMerge(ctx, ws, roots)
-		if err != nil {
-			return "", 0, 0, 0, fmt.Errorf("fatal: unable to abort merge: %v", err)
-		}
-
-		return "", 0, 0, 0, dSess.SetWorkingSet(ctx, dbName, newWs)
+	p := &cherryPicker{dSess: dSess, dbName: dbName, ddb: ddb, headRef: headRef, state: state}
+	if modes := apr.ContainsMany(cli.AbortParam, cli.ContinueFlag, cli.SkipFlag); len(modes) > 1 || len(modes) == 1 && apr.NArg() != 0 {
+		return "", 0, 0, 0, fmt.Errorf("error: --%s, --%s and --%s can't be combined with each other or with commits to cherry-pick", cli.AbortParam, cli.ContinueFlag, cli.SkipFlag)
 	}
 
-	// we only support cherry-picking a single commit for now.
-	if apr.NArg() == 0 {
-		return "", 0, 0, 0, ErrEmptyCherryPick
-	} else if apr.NArg() > 1 {
-		return "", 0, 0, 0, fmt.Errorf("cherry-picking multiple commits is not supported yet")
+	switch {
+	case apr.Contains(cli.AbortParam):
+		return "", 0, 0, 0, p.abort(ctx)
+	case apr.Contains(cli.ContinueFlag):
+		return p.resume(ctx)
+	case apr.Contains(cli.SkipFlag):
+		return p.skip(ctx)
 	}
 
-	cherryStr := apr.Arg(0)
-	if len(cherryStr) == 0 {
+	if state != nil {
+		return "", 0, 0, 0, fmt.Errorf("error: a cherry-pick is already in progress on branch '%s', use dolt_cherry_pick('--continue') "+
+			"to continue it, dolt_cherry_pick('--skip') to skip the commit it stopped at, or dolt_cherry_pick('--abort') to abort it", headRef.GetPath())
+	}
+	if apr.NArg() == 0 {
 		return "", 0, 0, 0, ErrEmptyCherryPick
 	}
+	return p.start(ctx, apr.Args)
+}
 
+// checkCherryPickResolved checks that the conflicts and constraint violations of the cherry-pick in progress in |ws|
+// are resolved and staged, so that |operation| can be continued, and returns whether the resolution left no changes
+// to commit.
+func checkCherryPickResolved(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, ws *doltdb.WorkingSet, operation string) (bool, error) {
 	roots, ok := dSess.GetRoots(ctx, dbName)
 	if !ok {
-		return "", 0, 0, 0, sql.ErrDatabaseNotFound.New(dbName)
+		return false, fmt.Errorf("Could not load database %s", dbName)
 	}
-
-	mergeResult, commitMsg, err := cherryPick(ctx, dSess, roots, dbName, cherryStr)
+	hasConflicts, err := roots.Working.HasConflicts(ctx)
 	if err != nil {
-		return "", 0, 0, 0, err
+		return false, err
 	}
-
-	newWorkingRoot := mergeResult.Root
-	err = dSess.SetRoot(ctx, dbName, newWorkingRoot)
+	hasViolations, err := roots.Working.HasConstraintViolations(ctx)
 	if err != nil {
-		return "", 0, 0, 0, err
+		return false, err
+	}
+	if hasConflicts || hasViolations || ws.MergeState().HasSchemaConflicts() {
+		return false, fmt.Errorf("error: resolve the conflicts and constraint violations before continuing the %s", operation)
 	}
 
-	err = stageCherryPickedTables(ctx, mergeResult.Stats)
+	// ignored tables are left out of the commit, so they don't need to be staged
+	staged := doltdb.Roots{Head: roots.Staged, Staged: roots.Staged, Working: roots.Working}
+	allStaged, err := diff.WorkingSetContainsOnlyIgnoredTables(ctx, staged)
 	if err != nil {
-		return "", 0, 0, 0, err
+		return false, err
+	}
+	if !allStaged {
+		return false, fmt.Errorf("error: stage the resolved tables with dolt_add() before continuing the %s", operation)
 	}
 
-	if mergeResult.HasMergeArtifacts() {
-		return "", mergeResult.CountOfTablesWithDataConflicts(),
-			mergeResult.CountOfTablesWithSchemaConflicts(), mergeResult.CountOfTablesWithConstraintViolations(), nil
-	} else {
-		commitHash, _, err := doDoltCommit(ctx, []string{"-m", commitMsg})
-		return commitHash, 0, 0, 0, err
+	stagedHash, err := roots.Staged.HashOf()
+	if err != nil {
+		return false, err
+	}
+	headHash, err := roots.Head.HashOf()
+	if err != nil {
+		return false, err
 	}
+	return stagedHash == headHash, nil
 }
 
 // stageCherryPickedTables stages the tables from |mergeStats| that don't have any merge artifacts – i.e.
@@ -271,7 +294,7 @@ func cherryPick(ctx *sql.Context, dSess *dsess.DoltSession, roots doltdb.Roots,
 	}
 
 	if headRootHash.Equal(workingRootHash) {
-		return nil, "", fmt.Errorf("no changes were made, nothing to commit")
+		return nil, "", ErrCherryPickNoChanges
 	}
 
 	cherryCommitMeta, err := cherryCommit.GetCommitMeta(ctx)
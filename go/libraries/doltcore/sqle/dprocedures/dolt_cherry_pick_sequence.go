@@ -0,0 +1,351 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var ErrNoCherryPickInProgress = errors.New("error: no cherry-pick is in progress")
+
+// cherryPicker runs the cherry-pick of one or more commits onto a session's current branch.
+type cherryPicker struct {
+	dSess   *dsess.DoltSession
+	dbName  string
+	ddb     *doltdb.DoltDB
+	headRef ref.DoltRef
+	// state is the cherry-pick being run. It's only recorded in the database when several commits are cherry-picked.
+	state *doltdb.CherryPickState
+	// commit is the hash of the last commit created by the cherry-pick
+	commit string
+}
+
+// start cherry-picks the commits named by |args| onto the current branch.
+func (p *cherryPicker) start(ctx *sql.Context, args []string) (string, int, int, int, error) {
+	head, err := p.dSess.GetHeadCommit(ctx, p.dbName)
+	if err != nil {
+		return "", 0, 0, 0, err
+	}
+	headHash, err := head.HashOf()
+	if err != nil {
+		return "", 0, 0, 0, err
+	}
+	commits, err := p.resolveCommits(ctx, args)
+	if err != nil {
+		return "", 0, 0, 0, err
+	}
+
+	p.state = &doltdb.CherryPickState{OrigHead: headHash.String(), Commits: commits}
+	return p.run(ctx)
+}
+
+// resolveCommits returns the hashes of the commits named by |args|, with the commits of ranges oldest first. A single
+// commit is returned as it was named, since nothing is recorded about its cherry-pick.
+func (p *cherryPicker) resolveCommits(ctx *sql.Context, args []string) ([]string, error) {
+	for _, arg := range args {
+		if len(arg) == 0 {
+			return nil, ErrEmptyCherryPick
+		}
+	}
+	if len(args) == 1 && !strings.Contains(args[0], "..") {
+		return args, nil
+	}
+
+	var commits []string
+	for _, arg := range args {
+		from, to, isRange := strings.Cut(arg, "..")
+		if !isRange {
+			cm, err := p.resolveCommit(ctx, arg)
+			if err != nil {
+				return nil, err
+			}
+			if cm.NumParents() > 1 {
+				return nil, fmt.Errorf("cherry-picking a merge commit is not supported")
+			}
+			h, err := cm.HashOf()
+			if err != nil {
+				return nil, err
+			}
+			commits = append(commits, h.String())
+			continue
+		}
+
+		if strings.HasPrefix(to, ".") {
+			return nil, fmt.Errorf("error: cherry-picking the symmetric difference %s is not supported", arg)
+		}
+		if len(from) == 0 {
+			from = "HEAD"
+		}
+		if len(to) == 0 {
+			to = "HEAD"
+		}
+		fromCommit, err := p.resolveCommit(ctx, from)
+		if err != nil {
+			return nil, err
+		}
+		fromHash, err := fromCommit.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		toCommit, err := p.resolveCommit(ctx, to)
+		if err != nil {
+			return nil, err
+		}
+		toHash, err := toCommit.HashOf()
+		if err != nil {
+			return nil, err
+		}
+
+		rangeCommits, err := commitwalk.GetDotDotRevisions(ctx, p.ddb, []hash.Hash{toHash}, p.ddb, []hash.Hash{fromHash}, -1)
+		if err != nil {
+			return nil, err
+		}
+		// like a rebase, merge commits in a range are left out
+		for i := len(rangeCommits) - 1; i >= 0; i-- {
+			if rangeCommits[i].NumParents() > 1 {
+				continue
+			}
+			h, err := rangeCommits[i].HashOf()
+			if err != nil {
+				return nil, err
+			}
+			commits = append(commits, h.String())
+		}
+	}
+
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("error: empty commit set passed")
+	}
+	return commits, nil
+}
+
+func (p *cherryPicker) resolveCommit(ctx *sql.Context, rev string) (*doltdb.Commit, error) {
+	cs, err := doltdb.NewCommitSpec(rev)
+	if err != nil {
+		return nil, err
+	}
+	return p.ddb.Resolve(ctx, cs, p.headRef)
+}
+
+// resume commits the resolution of the conflicts of the commit the cherry-pick stopped at, and cherry-picks the rest
+// of the commits.
+func (p *cherryPicker) resume(ctx *sql.Context) (string, int, int, int, error) {
+	ws, err := p.dSess.WorkingSet(ctx, p.dbName)
+	if err != nil {
+		return "", 0, 0, 0, err
+	}
+	cherryPicking := ws.MergeActive() && ws.MergeState().IsCherryPick()
+	if p.state == nil && !cherryPicking {
+		return "", 0, 0, 0, ErrNoCherryPickInProgress
+	}
+
+	// if the commit the cherry-pick stopped at was committed or skipped, there's nothing left to do for it
+	if cherryPicking {
+		empty, err := checkCherryPickResolved(ctx, p.dSess, p.dbName, ws, "cherry-pick")
+		if err != nil {
+			return "", 0, 0, 0, err
+		}
+		// a commit whose changes were all resolved away is left out
+		if empty {
+			err = p.dSess.SetWorkingSet(ctx, p.dbName, ws.ClearMerge())
+		} else {
+			var meta *datas.CommitMeta
+			meta, err = ws.MergeState().Commit().GetCommitMeta(ctx)
+			if err == nil {
+				err = p.commitPick(ctx, meta.Description)
+			}
+		}
+		if err != nil {
+			return "", 0, 0, 0, err
+		}
+	}
+
+	if p.state == nil {
+		return p.commit, 0, 0, 0, nil
+	}
+	p.state.Next++
+	return p.run(ctx)
+}
+
+// skip leaves out the commit the cherry-pick stopped at, and cherry-picks the rest of the commits.
+func (p *cherryPicker) skip(ctx *sql.Context) (string, int, int, int, error) {
+	ws, err := p.dSess.WorkingSet(ctx, p.dbName)
+	if err != nil {
+		return "", 0, 0, 0, err
+	}
+	cherryPicking := ws.MergeActive() && ws.MergeState().IsCherryPick()
+	if p.state == nil && !cherryPicking {
+		return "", 0, 0, 0, ErrNoCherryPickInProgress
+	}
+
+	if cherryPicking {
+		roots, ok := p.dSess.GetRoots(ctx, p.dbName)
+		if !ok {
+			return "", 0, 0, 0, fmt.Errorf("fatal: unable to load roots for %s", p.dbName)
+		}
+		ws, err = abortMerge(ctx, ws, roots)
+		if err != nil {
+			return "", 0, 0, 0, err
+		}
+		if err = p.dSess.SetWorkingSet(ctx, p.dbName, ws); err != nil {
+			return "", 0, 0, 0, err
+		}
+	}
+
+	if p.state == nil {
+		return "", 0, 0, 0, nil
+	}
+	p.state.Next++
+	return p.run(ctx)
+}
+
+// abort puts the branch back where it was before a cherry-pick of several commits started, or abandons the changes of
+// a cherry-pick of a single commit that stopped with conflicts.
+func (p *cherryPicker) abort(ctx *sql.Context) error {
+	if p.state != nil {
+		if _, err := doDoltReset(ctx, []string{"--hard", p.state.OrigHead}); err != nil {
+			return err
+		}
+		return p.deleteState(ctx)
+	}
+
+	ws, err := p.dSess.WorkingSet(ctx, p.dbName)
+	if err != nil {
+		return fmt.Errorf("fatal: unable to load working set: %v", err)
+	}
+
+	if !ws.MergeActive() {
+		return fmt.Errorf("error: There is no cherry-pick merge to abort")
+	}
+
+	roots, ok := p.dSess.GetRoots(ctx, p.dbName)
+	if !ok {
+		return fmt.Errorf("fatal: unable to load roots for %s", p.dbName)
+	}
+
+	newWs, err := abortMerge(ctx, ws, roots)
+	if err != nil {
+		return fmt.Errorf("fatal: unable to abort merge: %v", err)
+	}
+
+	return p.dSess.SetWorkingSet(ctx, p.dbName, newWs)
+}
+
+// run cherry-picks the commits from the next one, committing each of them, until they're all done or one of them stops
+// with conflicts. When several commits are cherry-picked, the ones whose changes are already on the branch are left
+// out, and the cherry-pick is recorded in the database when it stops.
+func (p *cherryPicker) run(ctx *sql.Context) (string, int, int, int, error) {
+	sequence := len(p.state.Commits) > 1
+	for ; p.state.Next < len(p.state.Commits); p.state.Next++ {
+		cherryStr := p.state.Commits[p.state.Next]
+		roots, ok := p.dSess.GetRoots(ctx, p.dbName)
+		if !ok {
+			return "", 0, 0, 0, sql.ErrDatabaseNotFound.New(p.dbName)
+		}
+
+		mergeResult, commitMsg, err := cherryPick(ctx, p.dSess, roots, p.dbName, cherryStr)
+		if sequence && errors.Is(err, ErrCherryPickNoChanges) {
+			continue
+		} else if err != nil {
+			if sequence && p.state.Next > 0 {
+				// the commits before this one were cherry-picked, so the cherry-pick can be skipped past it or aborted
+				if serr := p.saveState(ctx); serr != nil {
+					return "", 0, 0, 0, serr
+				}
+				return "", 0, 0, 0, fmt.Errorf("error: could not cherry-pick %s: %w\n"+
+					"Skip it with dolt_cherry_pick('--skip'), or abort the cherry-pick with dolt_cherry_pick('--abort').", cherryStr, err)
+			}
+			return "", 0, 0, 0, err
+		}
+
+		err = p.dSess.SetRoot(ctx, p.dbName, mergeResult.Root)
+		if err != nil {
+			return "", 0, 0, 0, err
+		}
+		err = stageCherryPickedTables(ctx, mergeResult.Stats)
+		if err != nil {
+			return "", 0, 0, 0, err
+		}
+
+		if mergeResult.HasMergeArtifacts() {
+			if sequence {
+				if err = p.saveState(ctx); err != nil {
+					return "", 0, 0, 0, err
+				}
+			}
+			return "", mergeResult.CountOfTablesWithDataConflicts(),
+				mergeResult.CountOfTablesWithSchemaConflicts(), mergeResult.CountOfTablesWithConstraintViolations(), nil
+		}
+
+		if err = p.commitPick(ctx, commitMsg); err != nil {
+			return "", 0, 0, 0, err
+		}
+	}
+
+	if sequence {
+		if err := p.deleteState(ctx); err != nil {
+			return "", 0, 0, 0, err
+		}
+	}
+	return p.commit, 0, 0, 0, nil
+}
+
+// commitPick commits the staged changes of the commit being cherry-picked, with the message |msg|.
+func (p *cherryPicker) commitPick(ctx *sql.Context, msg string) error {
+	commitHash, _, err := doDoltCommit(ctx, []string{"-m", msg})
+	if err != nil {
+		return err
+	}
+	p.commit = commitHash
+
+	// the commit ends the transaction, and the next commit must be cherry-picked onto it
+	newTx, err := p.dSess.StartTransaction(ctx, sql.ReadWrite)
+	if err != nil {
+		return err
+	}
+	ctx.SetTransaction(newTx)
+	return nil
+}
+
+func (p *cherryPicker) saveState(ctx *sql.Context) error {
+	var rsc doltdb.ReplicationStatusController
+	err := p.ddb.SetCherryPickState(ctx, p.headRef.GetPath(), *p.state, datas.NewTagMeta(p.dSess.Username(), p.dSess.Email(), ""), &rsc)
+	if err != nil {
+		return err
+	}
+	dsess.WaitForReplicationController(ctx, rsc)
+	return nil
+}
+
+func (p *cherryPicker) deleteState(ctx *sql.Context) error {
+	var rsc doltdb.ReplicationStatusController
+	err := p.ddb.DeleteCherryPickState(ctx, p.headRef.GetPath(), &rsc)
+	if err != nil {
+		return err
+	}
+	dsess.WaitForReplicationController(ctx, rsc)
+	return nil
+}
@@ -15,6 +15,7 @@
 package dprocedures
 
 import (
+	"fmt"
 	"path"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -38,6 +39,10 @@ func doltClone(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 
 	remoteName := apr.GetValueOrDefault(cli.RemoteParam, "origin")
 	branch := apr.GetValueOrDefault(cli.BranchParam, "")
+	depth := apr.GetIntOrDefault(cli.DepthParam, 0)
+	if apr.Contains(cli.DepthParam) && depth < 1 {
+		return nil, fmt.Errorf("error: --depth must be a positive number")
+	}
 	dir, urlStr, err := getDirectoryAndUrlString(apr)
 	if err != nil {
 		return nil, err
@@ -49,7 +54,7 @@ func doltClone(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 		return nil, errhand.BuildDError("error: '%s' is not valid.", urlStr).Build()
 	}
 
-	err = sess.Provider().CloneDatabaseFromRemote(ctx, dir, branch, remoteName, remoteUrl, map[string]string{})
+	err = sess.Provider().CloneDatabaseFromRemote(ctx, dir, branch, remoteName, remoteUrl, depth, map[string]string{})
 	if err != nil {
 		return nil, err
 	}
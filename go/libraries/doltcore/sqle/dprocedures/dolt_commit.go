@@ -26,6 +26,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/serverhooks"
 	"github.com/dolthub/dolt/go/store/datas"
 )
 
@@ -144,6 +145,11 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 		}
 	}
 
+	var signer datas.Signer
+	if apr.Contains(cli.GpgSignFlag) {
+		signer = configuredSigner(ctx)
+	}
+
 	pendingCommit, err := dSess.NewPendingCommit(ctx, dbName, roots, actions.CommitStagedProps{
 		Message:    msg,
 		Date:       t,
@@ -153,6 +159,7 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 		Force:      apr.Contains(cli.ForceFlag),
 		Name:       name,
 		Email:      email,
+		Signer:     signer,
 	})
 	if err != nil {
 		return "", false, err
@@ -165,6 +172,11 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 		return "", false, errors.New("nothing to commit")
 	}
 
+	err = runCommitHooks(ctx, dSess, dbName, name, msg)
+	if err != nil {
+		return "", false, err
+	}
+
 	newCommit, err := dSess.DoltCommit(ctx, dbName, dSess.GetTransaction(), pendingCommit)
 	if err != nil {
 		return "", false, err
@@ -178,6 +190,39 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 	return h.String(), false, nil
 }
 
+// runCommitHooks runs the pre_commit server hooks, if any, for a commit to the current branch of |dbName|.
+func runCommitHooks(ctx *sql.Context, dSess *dsess.DoltSession, dbName, user, msg string) error {
+	hooks := dsess.ServerHooksFor(dSess.Provider())
+	if len(hooks.Hooks(serverhooks.PreCommit)) == 0 {
+		return nil
+	}
+
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+	headRef, err := dbData.Rsr.CWBHeadRef()
+	if err != nil {
+		return err
+	}
+	head, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	headHash, err := head.HashOf()
+	if err != nil {
+		return err
+	}
+
+	return hooks.Run(ctx, dsess.StatementRunnerFor(dSess.Provider()), serverhooks.PreCommit, serverhooks.Change{
+		Database: dbName,
+		Ref:      headRef.String(),
+		OldHash:  headHash.String(),
+		User:     user,
+		Message:  msg,
+	})
+}
+
 func getDoltArgs(ctx *sql.Context, row sql.Row, children []sql.Expression) ([]string, error) {
 	args := make([]string, len(children))
 	for i := range children {
@@ -34,6 +34,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/set"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/pool"
 	"github.com/dolthub/dolt/go/store/prolly"
 	"github.com/dolthub/dolt/go/store/prolly/tree"
 	"github.com/dolthub/dolt/go/store/types"
@@ -168,6 +169,11 @@ func resolveProllyConflicts(ctx *sql.Context, tbl *doltdb.Table, tblName string,
 		}
 	}
 
+	return updateProllyTableRows(ctx, tbl, mutMap, idxSet, mutIdxs)
+}
+
+// updateProllyTableRows returns |tbl| with the rows of |mutMap| and the secondary indexes of |mutIdxs|.
+func updateProllyTableRows(ctx *sql.Context, tbl *doltdb.Table, mutMap *prolly.MutableMap, idxSet durable.IndexSet, mutIdxs []merge.MutableSecondaryIdx) (*doltdb.Table, error) {
 	// Update table
 	newMap, err := mutMap.Map(ctx)
 	if err != nil {
@@ -198,6 +204,162 @@ func resolveProllyConflicts(ctx *sql.Context, tbl *doltdb.Table, tblName string,
 	return newTbl, nil
 }
 
+// cellMergeProllyConflicts merges the columns of each conflicting row of |tbl| the way a merge does: a column changed
+// on only one side takes that side's value. The conflicts whose rows merge without a column changed differently on
+// both sides are resolved with the merged row. Conflicts between a delete and a modification, conflicts on keyless
+// tables, and rows with colliding columns are left as conflicts.
+func cellMergeProllyConflicts(ctx *sql.Context, tbl *doltdb.Table, tblName string, sch schema.Schema) (*doltdb.Table, error) {
+	if schema.IsKeyless(sch) {
+		return tbl, nil
+	}
+
+	artifactIdx, err := tbl.GetArtifacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	artifactMap := durable.ProllyMapFromArtifactIndex(artifactIdx)
+	iter, err := artifactMap.IterAllConflicts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	artEditor := artifactMap.Editor()
+
+	ourIdx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ourMap := durable.ProllyMapFromIndex(ourIdx)
+	mutMap := ourMap.Mutate()
+
+	idxSet, err := tbl.GetIndexSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mutIdxs, err := merge.GetMutableSecondaryIdxs(ctx, sch, idxSet)
+	if err != nil {
+		return nil, err
+	}
+
+	vd := sch.GetValueDescriptor()
+	var baseRoot, theirRoot hash.Hash
+	var baseMap, theirMap prolly.Map
+	var hasBase bool
+	for {
+		cnfArt, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// reload if the base or their root hash changes
+		if baseRoot != cnfArt.Metadata.BaseRootIsh {
+			baseMap, err = getProllyRowMaps(ctx, tbl.ValueReadWriter(), tbl.NodeStore(), cnfArt.Metadata.BaseRootIsh, tblName)
+			if err != nil && !errors.Is(err, doltdb.ErrTableNotFound) {
+				return nil, err
+			}
+			hasBase = err == nil
+			baseRoot = cnfArt.Metadata.BaseRootIsh
+		}
+		if theirRoot != cnfArt.TheirRootIsh {
+			theirMap, err = getProllyRowMaps(ctx, tbl.ValueReadWriter(), tbl.NodeStore(), cnfArt.TheirRootIsh, tblName)
+			if err != nil {
+				return nil, err
+			}
+			theirRoot = cnfArt.TheirRootIsh
+		}
+
+		var baseRow, ourRow, theirRow val.Tuple
+		if hasBase {
+			err = baseMap.Get(ctx, cnfArt.Key, func(_, v val.Tuple) error {
+				baseRow = v
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		err = ourMap.Get(ctx, cnfArt.Key, func(_, v val.Tuple) error {
+			ourRow = v
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = theirMap.Get(ctx, cnfArt.Key, func(_, v val.Tuple) error {
+			theirRow = v
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ourRow) == 0 || len(theirRow) == 0 {
+			// the row was deleted on one side and modified on the other
+			continue
+		}
+		merged, ok := cellMergeRow(vd, ourRow, theirRow, baseRow, ourMap.Pool())
+		if !ok {
+			continue
+		}
+
+		if err = mutMap.Put(ctx, cnfArt.Key, merged); err != nil {
+			return nil, err
+		}
+		for _, mutIdx := range mutIdxs {
+			if err = mutIdx.UpdateEntry(ctx, cnfArt.Key, ourRow, merged); err != nil {
+				return nil, err
+			}
+		}
+
+		artKey := artEditor.BuildArtifactKey(ctx, cnfArt.Key, cnfArt.TheirRootIsh, prolly.ArtifactTypeConflict)
+		if err = artEditor.Delete(ctx, artKey); err != nil {
+			return nil, err
+		}
+	}
+
+	newTbl, err := updateProllyTableRows(ctx, tbl, mutMap, idxSet, mutIdxs)
+	if err != nil {
+		return nil, err
+	}
+	artifacts, err := artEditor.Flush(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newTbl.SetArtifacts(ctx, durable.ArtifactIndexFromProllyMap(artifacts))
+}
+
+// cellMergeRow merges the values of |ours| and |theirs| column by column. A column changed from |base| on only one
+// side takes that side's value. It returns false if a column has different values on each side and they can't be
+// merged, which is always the case for different values when there's no |base| row.
+func cellMergeRow(vd val.TupleDesc, ours, theirs, base val.Tuple, pool pool.BuffPool) (val.Tuple, bool) {
+	merged := make([][]byte, vd.Count())
+	for i := range merged {
+		ourVal, theirVal := ours.GetField(i), theirs.GetField(i)
+		if vd.Comparator().CompareValues(i, ourVal, theirVal, vd.Types[i]) == 0 {
+			merged[i] = ourVal
+			continue
+		}
+		if base == nil {
+			return nil, false
+		}
+
+		baseVal := base.GetField(i)
+		ourModified := vd.Comparator().CompareValues(i, ourVal, baseVal, vd.Types[i]) != 0
+		theirModified := vd.Comparator().CompareValues(i, theirVal, baseVal, vd.Types[i]) != 0
+		switch {
+		case ourModified && theirModified:
+			return nil, false
+		case ourModified:
+			merged[i] = ourVal
+		default:
+			merged[i] = theirVal
+		}
+	}
+	return val.NewTuple(pool, merged...), true
+}
+
 func resolvePkConflicts(ctx *sql.Context, opts editor.Options, tbl *doltdb.Table, tblName string, sch schema.Schema, conflicts types.Map) (*doltdb.Table, error) {
 	// Create table editor
 	tblEditor, err := editor.NewTableEditor(ctx, tbl, sch, tblName, opts)
@@ -461,6 +623,61 @@ func ResolveDataConflicts(ctx *sql.Context, dSess *dsess.DoltSession, root *dolt
 	return dSess.SetRoot(ctx, dbName, root)
 }
 
+// CellMergeDataConflicts resolves the data conflicts of |tblNames| whose rows can be merged column by column, leaving
+// the conflicts with colliding columns unresolved.
+func CellMergeDataConflicts(ctx *sql.Context, dSess *dsess.DoltSession, root *doltdb.RootValue, dbName string, tblNames []string) error {
+	for _, tblName := range tblNames {
+		tbl, ok, err := root.GetTable(ctx, tblName)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return doltdb.ErrTableNotFound
+		}
+
+		if has, err := tbl.HasConflicts(ctx); err != nil {
+			return err
+		} else if !has {
+			continue
+		}
+
+		if tbl.Format() != types.Format_DOLT {
+			return fmt.Errorf("--%s is not supported for this storage format", cli.CellMergeFlag)
+		}
+
+		sch, err := tbl.GetSchema(ctx)
+		if err != nil {
+			return err
+		}
+		baseSch, ourSch, theirSch, err := tbl.GetConflictSchemas(ctx, tblName)
+		if err != nil {
+			return err
+		}
+		for _, cnfSch := range []schema.Schema{baseSch, ourSch, theirSch} {
+			if !schema.ColCollsAreEqual(sch.GetAllCols(), cnfSch.GetAllCols()) {
+				return ErrConfSchIncompatible
+			}
+		}
+
+		tbl, err = cellMergeProllyConflicts(ctx, tbl, tblName, sch)
+		if err != nil {
+			return err
+		}
+		newRoot, err := root.PutTable(ctx, tblName, tbl)
+		if err != nil {
+			return err
+		}
+
+		err = validateConstraintViolations(ctx, root, newRoot, tblName)
+		if err != nil {
+			return err
+		}
+
+		root = newRoot
+	}
+	return dSess.SetRoot(ctx, dbName, root)
+}
+
 func DoDoltConflictsResolve(ctx *sql.Context, args []string) (int, error) {
 	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
 		return 1, err
@@ -485,9 +702,12 @@ func DoDoltConflictsResolve(ctx *sql.Context, args []string) (int, error) {
 
 	ours := apr.Contains(cli.OursFlag)
 	theirs := apr.Contains(cli.TheirsFlag)
+	cellMerge := apr.Contains(cli.CellMergeFlag)
 	if ours && theirs {
 		return 1, fmt.Errorf("specify only either --ours or --theirs")
-	} else if !ours && !theirs {
+	} else if cellMerge && (ours || theirs) {
+		return 1, fmt.Errorf("--cell-merge can't be used with --ours or --theirs")
+	} else if !ours && !theirs && !cellMerge {
 		return 1, fmt.Errorf("--ours or --theirs must be supplied")
 	}
 
@@ -505,6 +725,14 @@ func DoDoltConflictsResolve(ctx *sql.Context, args []string) (int, error) {
 		tbls = all
 	}
 
+	if cellMerge {
+		err = CellMergeDataConflicts(ctx, dSess, ws.WorkingRoot(), dbName, tbls)
+		if err != nil {
+			return 1, err
+		}
+		return 0, nil
+	}
+
 	ws, err = ResolveSchemaConflicts(ctx, ddb, ws, ours, tbls)
 	if err != nil {
 		return 1, err
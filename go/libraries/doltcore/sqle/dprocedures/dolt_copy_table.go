@@ -0,0 +1,164 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// doltCopyTable is the stored procedure version of the CLI command `dolt table cp` for copying a table from a branch
+// or commit to a branch, e.g. CALL dolt_copy_table('main.t1', 'feature.t1').
+func doltCopyTable(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltCopyTable(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+// doDoltCopyTable copies the table named by the first argument, [<revision>.]<table>, to the branch named by the
+// second argument, [<branch>.]<table>. The copy is written to both the staged and working roots of the destination,
+// so it's ready to be committed there. Revisions that are branches are read from their working set.
+func doDoltCopyTable(ctx *sql.Context, args []string) (int, error) {
+	dbName := ctx.GetCurrentDatabase()
+
+	if len(dbName) == 0 {
+		return statusErr, fmt.Errorf("Empty database name.")
+	}
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return statusErr, err
+	}
+
+	apr, err := cli.CreateCopyTableArgParser().Parse(args)
+	if err != nil {
+		return statusErr, err
+	}
+	if apr.NArg() != 2 {
+		return statusErr, fmt.Errorf("dolt_copy_table requires a source table and a destination table")
+	}
+
+	srcRev, srcTbl := splitRevisionTableName(apr.Arg(0))
+	dstBranch, dstTbl := splitRevisionTableName(apr.Arg(1))
+	if !strings.EqualFold(srcTbl, dstTbl) {
+		return statusErr, fmt.Errorf("cannot copy table %s to a table with a different name, %s", srcTbl, dstTbl)
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	srcRoot, err := resolveCopySource(ctx, dSess, dbName, srcRev)
+	if err != nil {
+		return statusErr, err
+	}
+
+	_, tblName, ok, err := srcRoot.GetTableInsensitive(ctx, srcTbl)
+	if err != nil {
+		return statusErr, err
+	}
+	if !ok {
+		return statusErr, sql.ErrTableNotFound.New(srcTbl)
+	}
+	if doltdb.HasDoltPrefix(tblName) {
+		return statusErr, fmt.Errorf("cannot copy system table %s", tblName)
+	}
+
+	dstDb := dbName
+	if dstBranch != "" {
+		baseName, _ := dsess.SplitRevisionDbName(dbName)
+		dstDb = dsess.RevisionDbName(baseName, dstBranch)
+	}
+	roots, ok := dSess.GetRoots(ctx, dstDb)
+	if !ok {
+		return statusErr, fmt.Errorf("branch not found: %s", dstBranch)
+	}
+
+	if !apr.Contains(cli.ForceFlag) {
+		for _, root := range []*doltdb.RootValue{roots.Working, roots.Staged} {
+			if _, name, exists, err := root.GetTableInsensitive(ctx, tblName); err != nil {
+				return statusErr, err
+			} else if exists {
+				return statusErr, fmt.Errorf("table %s already exists on the destination branch, use --force to overwrite it", name)
+			}
+		}
+	}
+
+	roots.Staged, err = actions.MoveTablesBetweenRoots(ctx, []string{tblName}, srcRoot, roots.Staged)
+	if err != nil {
+		return statusErr, err
+	}
+	roots.Working, err = actions.MoveTablesBetweenRoots(ctx, []string{tblName}, srcRoot, roots.Working)
+	if err != nil {
+		return statusErr, err
+	}
+
+	err = dSess.SetRoots(ctx, dstDb, roots)
+	if err != nil {
+		return statusErr, err
+	}
+	return statusOk, nil
+}
+
+// resolveCopySource returns the root to copy a table from for the revision |rev|. Branches are read from their
+// working set and other revisions from their commit. The current working set is used when |rev| is empty.
+func resolveCopySource(ctx *sql.Context, dSess *dsess.DoltSession, dbName, rev string) (*doltdb.RootValue, error) {
+	if rev == "" {
+		roots, ok := dSess.GetRoots(ctx, dbName)
+		if !ok {
+			return nil, sql.ErrDatabaseNotFound.New(dbName)
+		}
+		return roots.Working, nil
+	}
+
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+	branch, isBranch, err := ddb.HasBranch(ctx, rev)
+	if err != nil {
+		return nil, err
+	}
+	if isBranch {
+		baseName, _ := dsess.SplitRevisionDbName(dbName)
+		roots, ok := dSess.GetRoots(ctx, dsess.RevisionDbName(baseName, branch))
+		if !ok {
+			return nil, fmt.Errorf("branch not found: %s", rev)
+		}
+		return roots.Working, nil
+	}
+
+	root, _, _, err := dSess.ResolveRootForRef(ctx, dbName, rev)
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// splitRevisionTableName splits a table name qualified with a revision, like main.t1, into the revision and the
+// table name. The revision is empty for unqualified names. Branch names can contain periods, so the name is split
+// at the last one.
+func splitRevisionTableName(name string) (rev string, table string) {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return "", name
+	}
+	return name[:i], name[i+1:]
+}
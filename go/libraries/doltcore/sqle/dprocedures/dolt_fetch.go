@@ -78,6 +78,13 @@ func doDoltFetch(ctx *sql.Context, args []string) (int, error) {
 		return 1, err
 	}
 
+	if apr.Contains(cli.DeepenParam) {
+		err = actions.DeepenShallowClone(ctx, dbData, srcDB, apr.GetIntOrDefault(cli.DeepenParam, 0), runProgFuncs, stopProgFuncs)
+		if err != nil {
+			return cmdFailure, fmt.Errorf("fetch failed: %w", err)
+		}
+	}
+
 	prune := apr.Contains(cli.PruneFlag)
 	mode := ref.UpdateMode{Force: true, Prune: prune}
 	err = actions.FetchRefSpecs(ctx, dbData, srcDB, refSpecs, remote, mode, runProgFuncs, stopProgFuncs)
@@ -93,6 +100,9 @@ func validateFetchArgs(apr *argparser.ArgParseResults, refSpecArgs []string) err
 		// The current prune implementation assumes that we're processing branch specs, which
 		return fmt.Errorf("--prune option cannot be provided with a ref spec")
 	}
+	if deepen, ok := apr.GetInt(cli.DeepenParam); apr.Contains(cli.DeepenParam) && (!ok || deepen < 1) {
+		return fmt.Errorf("--deepen must be a positive number")
+	}
 
 	return nil
 }
@@ -18,14 +18,18 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
 
@@ -81,6 +85,12 @@ func doDoltGC(ctx *sql.Context, args []string) (int, error) {
 		return cmdFailure, fmt.Errorf("Could not load database %s", dbName)
 	}
 
+	// Expired branches are cleaned up first, so that any chunks only they referenced can be collected
+	err = expireBranches(ctx, dSess, dbName)
+	if err != nil {
+		return cmdFailure, err
+	}
+
 	if apr.Contains(cli.ShallowFlag) {
 		err = ddb.ShallowGC(ctx)
 		if err != nil {
@@ -168,3 +178,64 @@ func doDoltGC(ctx *sql.Context, args []string) (int, error) {
 
 	return cmdSuccess, nil
 }
+
+// expireBranches deletes the branches of the database named whose expiration has passed, archiving their heads as
+// tags. Branches are deleted just as DOLT_BRANCH('-D') deletes them, so the same permissions apply. Branches that this
+// or any other session has checked out, or that this session isn't allowed to delete, are left for a later call. Each
+// expired branch is logged, along with the user that set its expiration, before it's deleted, and its owners are
+// notified with a warning once it has been.
+func expireBranches(ctx *sql.Context, dSess *dsess.DoltSession, dbName string) error {
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	inUse := func(name string) (bool, error) {
+		headRef, err := dSess.CWBHeadRef(ctx, dbName)
+		if err == nil && ref.Equals(headRef, ref.NewBranchRef(name)) {
+			return true, nil
+		}
+		if err := branch_control.CanDeleteBranch(ctx, name); err != nil {
+			return true, nil
+		}
+		err = validateBranchNotActiveInAnySession(ctx, name)
+		if errors.Is(err, BranchInUseErr) {
+			return true, nil
+		}
+		return false, err
+	}
+	deleteBranch := func(name string) error {
+		_, err := doDoltBranch(ctx, []string{"-D", name})
+		return err
+	}
+
+	notify := func(branch actions.ExpiredBranch) {
+		ctx.GetLogger().WithFields(logrus.Fields{
+			"branch":  branch.Name,
+			"expires": branch.Expires.Format(time.RFC3339),
+			"owner":   branch.Owner,
+			"archive": branch.Archive,
+		}).Info("deleting expired branch")
+	}
+
+	expired, err := actions.ExpireBranches(ctx, dbData, ctx.QueryTime(), inUse, notify, deleteBranch)
+	if err != nil {
+		return err
+	}
+
+	for _, branch := range expired {
+		msg := fmt.Sprintf("branch '%s' expired at %s and was deleted; its head was archived as tag '%s'",
+			branch.Name, branch.Expires.Format(time.RFC3339), branch.Archive)
+		owners := branch_control.BranchAdmins(ctx, branch.Name)
+		if branch.Owner != "" {
+			owners = append([]string{branch.Owner}, owners...)
+		}
+		if len(owners) > 0 {
+			msg += fmt.Sprintf(" (owners: %s)", strings.Join(owners, ", "))
+		}
+		ctx.GetLogger().Warn(msg)
+		ctx.Warn(1105, msg)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+// doltMask is the stored procedure dolt_mask(), which adds and removes column masking policies. The values of a masked
+// column are hidden from users without the SUPER privilege everywhere they can be read: in the table itself, in AS OF
+// queries, and in the dolt_history_, dolt_diff_ and dolt_commit_diff_ tables, dolt_diff() and dolt_patch(). Policies
+// are listed in the dolt_column_masks system table.
+//
+//	dolt_mask(<table>, <column>, <mask>)
+//	dolt_mask('-d', <table>, <column>)
+//
+// <mask> is one of null, redact or hash.
+func doltMask(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltMask(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+func doDoltMask(ctx *sql.Context, args []string) (int, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 1, fmt.Errorf("Empty database name.")
+	}
+
+	apr, err := cli.CreateMaskArgParser().Parse(args)
+	if err != nil {
+		return 1, err
+	}
+	remove := apr.Contains(cli.DeleteFlag)
+	if (remove && apr.NArg() != 2) || (!remove && apr.NArg() != 3) {
+		return 1, InvalidArgErr
+	}
+	if !dsess.CanUnmask(ctx) {
+		return 1, fmt.Errorf("error: column masks can only be changed by users with the SUPER privilege")
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return 1, fmt.Errorf("Could not load database %s", dbName)
+	}
+	masks, err := ddb.GetColumnMasks(ctx)
+	if err != nil {
+		return 1, err
+	}
+
+	// masks are matched to columns by tag, but a column that's been dropped can only be unmasked by the names it was
+	// masked under
+	tableName, columnName := apr.Arg(0), apr.Arg(1)
+	resolvedTable, resolvedColumn, tag, resolveErr := resolveMaskedColumn(ctx, dSess, dbName, tableName, columnName)
+	idx := -1
+	for i, m := range masks.Masks {
+		if (resolveErr == nil && m.Tag == tag) || (resolveErr != nil && strings.EqualFold(m.Table, tableName) && strings.EqualFold(m.Column, columnName)) {
+			idx = i
+			break
+		}
+	}
+
+	if remove {
+		if idx < 0 {
+			return 1, fmt.Errorf("error: column '%s' of table '%s' is not masked", columnName, tableName)
+		}
+		masks.Masks = append(masks.Masks[:idx], masks.Masks[idx+1:]...)
+	} else {
+		kind, err := doltdb.ParseMaskKind(apr.Arg(2))
+		if err != nil {
+			return 1, err
+		}
+		if resolveErr != nil {
+			return 1, resolveErr
+		}
+		mask := doltdb.ColumnMask{Table: resolvedTable, Column: resolvedColumn, Tag: tag, Kind: kind}
+		if idx < 0 {
+			masks.Masks = append(masks.Masks, mask)
+		} else {
+			masks.Masks[idx] = mask
+		}
+	}
+
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return 1, err
+	}
+
+	var rsc doltdb.ReplicationStatusController
+	meta := datas.NewTagMeta(dSess.Username(), dSess.Email(), "")
+	err = ddb.SetColumnMasks(ctx, masks, headRef, meta, &rsc)
+	if err != nil {
+		return 1, err
+	}
+
+	dsess.WaitForReplicationController(ctx, rsc)
+	return 0, nil
+}
+
+// resolveMaskedColumn returns the names of the table and column to be masked as they are written in the working set,
+// and the tag of the column.
+func resolveMaskedColumn(ctx *sql.Context, dSess *dsess.DoltSession, dbName, tableName, columnName string) (string, string, uint64, error) {
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return "", "", 0, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	tbl, tableName, ok, err := roots.Working.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return "", "", 0, err
+	} else if !ok {
+		return "", "", 0, sql.ErrTableNotFound.New(tableName)
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return "", "", 0, err
+	}
+	col, ok := sch.GetAllCols().GetByNameCaseInsensitive(columnName)
+	if !ok {
+		return "", "", 0, sql.ErrTableColumnNotFound.New(tableName, columnName)
+	}
+	return tableName, col.Name, col.Tag, nil
+}
@@ -30,7 +30,9 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/serverhooks"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/store/hash"
@@ -135,13 +137,49 @@ func doDoltMerge(ctx *sql.Context, args []string) (string, int, int, error) {
 		return "", noConflictsOrViolations, threeWayMerge, nil
 	}
 
-	branchName := apr.Arg(0)
+	if !apr.Contains(cli.AutostashFlag) {
+		return mergeRefs(ctx, sess, ws, dbName, apr)
+	}
+
+	stashed, err := autostash(ctx, sess, dbName, roots)
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, err
+	}
+	if !stashed {
+		return mergeRefs(ctx, sess, ws, dbName, apr)
+	}
+	ws, err = sess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, err
+	}
+
+	commit, conflicts, fastForward, err := mergeRefs(ctx, sess, ws, dbName, apr)
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, restoreAutostash(ctx, sess, dbName, roots, err)
+	}
+	if err = applyAutostash(ctx, sess, dbName, conflicts != noConflictsOrViolations); err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, err
+	}
+	return commit, conflicts, fastForward, nil
+}
+
+// mergeRefs merges the branches or commits named by |apr| into the current branch, and returns the same values as
+// doDoltMerge.
+func mergeRefs(ctx *sql.Context, sess *dsess.DoltSession, ws *doltdb.WorkingSet, dbName string, apr *argparser.ArgParseResults) (string, int, int, error) {
+	if apr.NArg() > 1 {
+		return doOctopusMerge(ctx, sess, ws, dbName, apr)
+	}
 
-	mergeSpec, err := createMergeSpec(ctx, sess, dbName, apr, branchName)
+	mergeSpec, err := createMergeSpec(ctx, sess, dbName, apr, apr.Arg(0))
 	if err != nil {
 		return "", noConflictsOrViolations, threeWayMerge, err
 	}
+	return mergeBranch(ctx, sess, ws, dbName, apr, mergeSpec)
+}
 
+// mergeBranch merges the single branch or commit of |mergeSpec| into the current branch, and returns the same values
+// as doDoltMerge.
+func mergeBranch(ctx *sql.Context, sess *dsess.DoltSession, ws *doltdb.WorkingSet, dbName string, apr *argparser.ArgParseResults, mergeSpec *merge.MergeSpec) (string, int, int, error) {
 	dbData, ok := sess.GetDbData(ctx, dbName)
 	if !ok {
 		return "", noConflictsOrViolations, threeWayMerge, fmt.Errorf("Could not load database %s", dbName)
@@ -151,11 +189,16 @@ func doDoltMerge(ctx *sql.Context, args []string) (string, int, int, error) {
 	if err != nil {
 		return "", noConflictsOrViolations, threeWayMerge, err
 	}
-	msg := fmt.Sprintf("Merge branch '%s' into %s", branchName, headRef.GetPath())
+	msg := fmt.Sprintf("Merge branch '%s' into %s", mergeSpec.MergeCSpecStr, headRef.GetPath())
 	if userMsg, mOk := apr.GetValue(cli.MessageArg); mOk {
 		msg = userMsg
 	}
 
+	err = runMergeHooks(ctx, sess, dbName, headRef, mergeSpec, msg)
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, err
+	}
+
 	ws, commit, conflicts, fastForward, err := performMerge(ctx, sess, ws, dbName, mergeSpec, apr.Contains(cli.NoCommitFlag), msg)
 	if err != nil || conflicts != 0 || fastForward != 0 {
 		return commit, conflicts, fastForward, err
@@ -164,6 +207,23 @@ func doDoltMerge(ctx *sql.Context, args []string) (string, int, int, error) {
 	return commit, conflicts, fastForward, nil
 }
 
+// runMergeHooks runs the pre_merge server hooks, if any, for merging |spec| into |headRef|.
+func runMergeHooks(ctx *sql.Context, sess *dsess.DoltSession, dbName string, headRef ref.DoltRef, spec *merge.MergeSpec, msg string) error {
+	hooks := dsess.ServerHooksFor(sess.Provider())
+	if len(hooks.Hooks(serverhooks.PreMerge)) == 0 {
+		return nil
+	}
+
+	return hooks.Run(ctx, dsess.StatementRunnerFor(sess.Provider()), serverhooks.PreMerge, serverhooks.Change{
+		Database: dbName,
+		Ref:      headRef.String(),
+		OldHash:  spec.HeadH.String(),
+		NewHash:  spec.MergeH.String(),
+		User:     spec.Name,
+		Message:  msg,
+	})
+}
+
 // performMerge encapsulates server merge logic, switching between
 // fast-forward, no fast-forward, merge commit, and merging into working set.
 // Returns a new WorkingSet, whether there were merge conflicts, and whether a
@@ -241,7 +301,7 @@ func performMerge(
 		return ws, "", noConflictsOrViolations, threeWayMerge, sql.ErrDatabaseNotFound.New(dbName)
 	}
 
-	ws, err = executeMerge(ctx, sess, dbName, spec.Squash, spec.HeadC, spec.MergeC, spec.MergeCSpecStr, ws, dbState.EditOpts(), spec.WorkingDiffs)
+	ws, err = executeMerge(ctx, sess, dbName, spec.Squash, spec.NoRenames, spec.HeadC, spec.MergeC, spec.MergeCSpecStr, ws, dbState.EditOpts(), spec.WorkingDiffs)
 	if err == doltdb.ErrUnresolvedConflictsOrViolations {
 		// if there are unresolved conflicts, write the resulting working set back to the session and return an
 		// error message
@@ -316,13 +376,14 @@ func executeMerge(
 	sess *dsess.DoltSession,
 	dbName string,
 	squash bool,
+	noRenames bool,
 	head, cm *doltdb.Commit,
 	cmSpec string,
 	ws *doltdb.WorkingSet,
 	opts editor.Options,
 	workingDiffs map[string]hash.Hash,
 ) (*doltdb.WorkingSet, error) {
-	result, err := merge.MergeCommits(ctx, head, cm, opts)
+	result, err := merge.MergeCommits(ctx, head, cm, opts, noRenames)
 	if err != nil {
 		switch err {
 		case doltdb.ErrUpToDate:
@@ -412,24 +473,15 @@ func executeNoFFMerge(
 		return nil, nil, err
 	}
 
-	// The roots need refreshing after the above
-	roots, _ := dSess.GetRoots(ctx, dbName)
-
 	if noCommit {
-		// stage all changes
-		roots, err = actions.StageAllTables(ctx, roots, true)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		err = dSess.SetRoots(ctx, dbName, roots)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		return ws.WithStagedRoot(roots.Staged), nil, nil
+		// the merged changes are staged, but uncommitted changes carried over from before the merge aren't, so that
+		// they aren't committed as part of the merge
+		return ws, nil, nil
 	}
 
+	// The roots need refreshing after the above
+	roots, _ := dSess.GetRoots(ctx, dbName)
+
 	pendingCommit, err := dSess.NewPendingCommit(ctx, dbName, roots, actions.CommitStagedProps{
 		Message: msg,
 		Date:    spec.Date,
@@ -493,6 +545,7 @@ func createMergeSpec(ctx *sql.Context, sess *dsess.DoltSession, dbName string, a
 		merge.WithForce(apr.Contains(cli.ForceFlag)),
 		merge.WithNoCommit(apr.Contains(cli.NoCommitFlag)),
 		merge.WithNoEdit(apr.Contains(cli.NoEditFlag)),
+		merge.WithNoRenames(apr.Contains(cli.NoRenamesFlag)),
 	)
 }
 
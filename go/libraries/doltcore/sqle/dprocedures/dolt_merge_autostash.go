@@ -0,0 +1,139 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// autostash stashes the uncommitted changes in |roots|, if there are any, before a merge with --autostash, and
+// returns whether it did. The stash is the newest one until the merge is done and it's applied again by
+// applyAutostash, or dropped by restoreAutostash.
+func autostash(ctx *sql.Context, sess *dsess.DoltSession, dbName string, roots doltdb.Roots) (bool, error) {
+	uncommittedChanges, _, _, err := actions.RootHasUncommittedChanges(roots)
+	if err != nil || !uncommittedChanges {
+		return false, err
+	}
+
+	dbData, ok := sess.GetDbData(ctx, dbName)
+	if !ok {
+		return false, sql.ErrDatabaseNotFound.New(dbName)
+	}
+	roots, err = stashAllChanges(ctx, dbData, roots)
+	if err != nil {
+		return false, err
+	}
+	if err = sess.SetRoots(ctx, dbName, roots); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// restoreAutostash puts back the uncommitted changes in |roots| and drops their stash after a merge with --autostash
+// failed with |mergeErr|, which is returned.
+func restoreAutostash(ctx *sql.Context, sess *dsess.DoltSession, dbName string, roots doltdb.Roots, mergeErr error) error {
+	dbData, ok := sess.GetDbData(ctx, dbName)
+	if !ok {
+		return sql.ErrDatabaseNotFound.New(dbName)
+	}
+	if err := sess.SetRoots(ctx, dbName, roots); err != nil {
+		return err
+	}
+	if err := dbData.Ddb.RemoveStashAtIdx(ctx, 0); err != nil {
+		return err
+	}
+	return mergeErr
+}
+
+// applyAutostash applies the changes stashed before a merge with --autostash to the working set of the merge, and
+// drops their stash. The changes are kept in the stash instead, with a warning saying so, if the merge has conflicts
+// that have to be resolved first, or if applying them conflicts with the merged changes.
+//
+// When the merge wasn't committed, the changes are applied to the working root only, so that only the merged changes
+// are staged to be committed as the merge.
+func applyAutostash(ctx *sql.Context, sess *dsess.DoltSession, dbName string, mergeHasConflicts bool) error {
+	if mergeHasConflicts {
+		ctx.Warn(DoltMergeWarningCode, "your uncommitted changes were stashed before merging; once the merge conflicts are resolved, apply them with `dolt stash pop`")
+		return nil
+	}
+
+	// committing the merge ends the transaction, and the changes must be applied to the working set it committed
+	if ctx.GetTransaction() == nil {
+		newTx, err := sess.StartTransaction(ctx, sql.ReadWrite)
+		if err != nil {
+			return err
+		}
+		ctx.SetTransaction(newTx)
+	}
+
+	dbData, ok := sess.GetDbData(ctx, dbName)
+	if !ok {
+		return sql.ErrDatabaseNotFound.New(dbName)
+	}
+	dbState, ok, err := sess.LookupDbState(ctx, dbName)
+	if err != nil {
+		return err
+	} else if !ok {
+		return sql.ErrDatabaseNotFound.New(dbName)
+	}
+	ws, err := sess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return err
+	}
+	roots, ok := sess.GetRoots(ctx, dbName)
+	if !ok {
+		return sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	stashRoot, stashHead, meta, err := dbData.Ddb.GetStashRootAndHeadCommitAtIdx(ctx, 0)
+	if err != nil {
+		return err
+	}
+	stashHeadRoot, err := stashHead.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := merge.MergeRoots(ctx, roots.Working, stashRoot, stashHeadRoot, stashRoot, stashHead, dbState.EditOpts(), merge.MergeOpts{KeepSchemaConflicts: true})
+	if err != nil {
+		return err
+	}
+	if result.HasMergeArtifacts() {
+		ctx.Warn(DoltMergeWarningCode, fmt.Sprintf("your uncommitted changes conflict with the merged changes in tables: %s, so they were kept in the stash; "+
+			"apply them with `dolt stash pop` or drop them with `dolt stash drop`", strings.Join(mergeArtifactTables(result), ", ")))
+		return nil
+	}
+
+	roots.Working = result.Root
+	if !ws.MergeActive() {
+		// since these tables are coming from a stash, don't filter for ignored table names
+		roots, err = actions.StageTables(ctx, roots, meta.TablesToStage, false)
+		if err != nil {
+			return err
+		}
+	}
+	if err = sess.SetRoots(ctx, dbName, roots); err != nil {
+		return err
+	}
+	return dbData.Ddb.RemoveStashAtIdx(ctx, 0)
+}
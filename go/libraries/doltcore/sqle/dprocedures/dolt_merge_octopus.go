@@ -0,0 +1,248 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+// doOctopusMerge merges all the branches named by |apr| into the current branch at once, creating a single merge
+// commit with the head of the current branch and each of the merged branches as its parents. Like git's octopus
+// strategy, the merge is made only if none of the branches conflict: they are merged one after another, and if any
+// of them conflicts with the result of merging the ones before it, the merge is abandoned and nothing is changed.
+//
+// Branches that are already merged into the current branch, or into another of the branches being merged, are
+// skipped. If only one branch is left to merge, it's merged as if it had been the only one named.
+func doOctopusMerge(ctx *sql.Context, sess *dsess.DoltSession, ws *doltdb.WorkingSet, dbName string, apr *argparser.ArgParseResults) (string, int, int, error) {
+	if apr.Contains(cli.SquashParam) || apr.Contains(cli.NoCommitFlag) {
+		return "", noConflictsOrViolations, threeWayMerge, fmt.Errorf("error: --%s and --%s cannot be used when merging more than one branch", cli.SquashParam, cli.NoCommitFlag)
+	}
+	if ws.MergeActive() {
+		return "", noConflictsOrViolations, threeWayMerge, doltdb.ErrMergeActive
+	}
+
+	roots, ok := sess.GetRoots(ctx, dbName)
+	if !ok {
+		return "", noConflictsOrViolations, threeWayMerge, sql.ErrDatabaseNotFound.New(dbName)
+	}
+	clean, err := diff.WorkingSetContainsOnlyIgnoredTables(ctx, roots)
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, err
+	} else if !clean {
+		return "", noConflictsOrViolations, threeWayMerge, ErrUncommittedChanges.New()
+	}
+
+	specs := make([]*merge.MergeSpec, apr.NArg())
+	for i, branchName := range apr.Args {
+		specs[i], err = createMergeSpec(ctx, sess, dbName, apr, branchName)
+		if err != nil {
+			return "", noConflictsOrViolations, threeWayMerge, err
+		}
+	}
+	specs, err = reduceMergeSpecs(ctx, specs)
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, err
+	}
+
+	switch len(specs) {
+	case 0:
+		ctx.Warn(DoltMergeWarningCode, doltdb.ErrUpToDate.Error())
+		return "", noConflictsOrViolations, threeWayMerge, nil
+	case 1:
+		return mergeBranch(ctx, sess, ws, dbName, apr, specs[0])
+	}
+
+	dbData, ok := sess.GetDbData(ctx, dbName)
+	if !ok {
+		return "", noConflictsOrViolations, threeWayMerge, fmt.Errorf("Could not load database %s", dbName)
+	}
+	headRef, err := dbData.Rsr.CWBHeadRef()
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, err
+	}
+	msg := octopusMergeMessage(specs, headRef.GetPath())
+	if userMsg, mOk := apr.GetValue(cli.MessageArg); mOk {
+		msg = userMsg
+	}
+
+	for _, spec := range specs {
+		if err = runMergeHooks(ctx, sess, dbName, headRef, spec, msg); err != nil {
+			return "", noConflictsOrViolations, threeWayMerge, err
+		}
+	}
+
+	dbState, ok, err := sess.LookupDbState(ctx, dbName)
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, err
+	} else if !ok {
+		return "", noConflictsOrViolations, threeWayMerge, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	mergedRoot := roots.Head
+	parents := make([]*doltdb.Commit, len(specs))
+	for i, spec := range specs {
+		ancCommit, err := doltdb.GetCommitAncestor(ctx, spec.HeadC, spec.MergeC)
+		if err != nil {
+			return "", noConflictsOrViolations, threeWayMerge, err
+		}
+		ancRoot, err := ancCommit.GetRootValue(ctx)
+		if err != nil {
+			return "", noConflictsOrViolations, threeWayMerge, err
+		}
+		theirRoot, err := spec.MergeC.GetRootValue(ctx)
+		if err != nil {
+			return "", noConflictsOrViolations, threeWayMerge, err
+		}
+
+		result, err := merge.MergeRoots(ctx, mergedRoot, theirRoot, ancRoot, spec.MergeC, ancCommit, dbState.EditOpts(), merge.MergeOpts{
+			KeepSchemaConflicts: true,
+			NoRenames:           spec.NoRenames,
+		})
+		if err != nil {
+			return "", noConflictsOrViolations, threeWayMerge, err
+		}
+		if result.HasMergeArtifacts() {
+			return "", noConflictsOrViolations, threeWayMerge, fmt.Errorf("error: merging '%s' conflicts with the other branches being merged in tables: %s; "+
+				"no changes were made. Merge the branches one at a time to resolve the conflicts", spec.MergeCSpecStr, strings.Join(mergeArtifactTables(result), ", "))
+		}
+
+		mergedRoot = result.Root
+		parents[i] = spec.MergeC
+	}
+
+	roots.Working, roots.Staged = mergedRoot, mergedRoot
+	pendingCommit, err := actions.GetCommitStaged(ctx, roots, ws, parents, dbData.Ddb, actions.CommitStagedProps{
+		Message:    msg,
+		Date:       specs[0].Date,
+		AllowEmpty: true,
+		Force:      specs[0].Force,
+		Name:       specs[0].Name,
+		Email:      specs[0].Email,
+	})
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, err
+	}
+	if err = runCommitHooks(ctx, sess, dbName, specs[0].Name, msg); err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, err
+	}
+	commit, err := sess.DoltCommit(ctx, dbName, sess.GetTransaction(), pendingCommit)
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, err
+	}
+
+	h, err := commit.HashOf()
+	if err != nil {
+		return "", noConflictsOrViolations, threeWayMerge, err
+	}
+	return h.String(), noConflictsOrViolations, threeWayMerge, nil
+}
+
+// reduceMergeSpecs returns the merge specs of |specs| whose commits aren't already merged into the head of the current
+// branch, or into the commit of another spec, in their original order.
+func reduceMergeSpecs(ctx *sql.Context, specs []*merge.MergeSpec) ([]*merge.MergeSpec, error) {
+	var reduced []*merge.MergeSpec
+	for i, spec := range specs {
+		merged, err := isAncestorCommit(ctx, spec.MergeC, spec.HeadC)
+		if err != nil {
+			return nil, err
+		}
+		if merged {
+			ctx.Warn(DoltMergeWarningCode, fmt.Sprintf("Already up to date with %s", spec.MergeCSpecStr))
+			continue
+		}
+
+		for j, other := range specs {
+			if i == j {
+				continue
+			}
+			if spec.MergeH == other.MergeH {
+				// the same commit was named twice, so only its first occurrence is merged
+				merged = j < i
+			} else {
+				merged, err = isAncestorCommit(ctx, spec.MergeC, other.MergeC)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if merged {
+				break
+			}
+		}
+		if !merged {
+			reduced = append(reduced, spec)
+		}
+	}
+	return reduced, nil
+}
+
+// isAncestorCommit returns whether |ancestor| is an ancestor of, or the same commit as, |cm|.
+func isAncestorCommit(ctx *sql.Context, ancestor, cm *doltdb.Commit) (bool, error) {
+	anc, err := doltdb.GetCommitAncestor(ctx, ancestor, cm)
+	if err == doltdb.ErrNoCommonAncestor {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	ancHash, err := anc.HashOf()
+	if err != nil {
+		return false, err
+	}
+	h, err := ancestor.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return ancHash == h, nil
+}
+
+// octopusMergeMessage returns the default message of a commit merging |specs| into the branch |branch|, e.g.
+// "Merge branches 'b1', 'b2' and 'b3' into main".
+func octopusMergeMessage(specs []*merge.MergeSpec, branch string) string {
+	names := make([]string, len(specs))
+	for i, spec := range specs {
+		names[i] = fmt.Sprintf("'%s'", spec.MergeCSpecStr)
+	}
+	last := len(names) - 1
+	return fmt.Sprintf("Merge branches %s and %s into %s", strings.Join(names[:last], ", "), names[last], branch)
+}
+
+// mergeArtifactTables returns the names of the tables with schema conflicts, conflicts or constraint violations in
+// |result|.
+func mergeArtifactTables(result *merge.Result) []string {
+	seen := make(map[string]bool)
+	var tables []string
+	for _, name := range merge.SchemaConflictTableNames(result.SchemaConflicts) {
+		seen[name] = true
+		tables = append(tables, name)
+	}
+	for name, stats := range result.Stats {
+		if stats.HasArtifacts() && !seen[name] {
+			tables = append(tables, name)
+		}
+	}
+	sort.Strings(tables)
+	return tables
+}
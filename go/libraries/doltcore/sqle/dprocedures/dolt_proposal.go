@@ -0,0 +1,255 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+// doltProposal is the stored procedure dolt_proposal(), which manages change proposals: requests to merge one branch
+// into another that can be discussed and approved before they are merged. Proposals are listed in the dolt_proposals
+// and dolt_proposal_comments system tables.
+//
+//	dolt_proposal('open', <source>, <target>, ['-m', <description>], ['--reviewers', <users>])
+//	dolt_proposal('comment', <id>, '-m', <comment>)
+//	dolt_proposal('approve', <id>)
+//	dolt_proposal('merge', <id>)
+//	dolt_proposal('close', <id>)
+//
+// Every form returns the id of the proposal. A proposal can be approved by its reviewers and by anyone with write
+// access to its target branch, and closed by its author and by anyone with write access to its target branch.
+func doltProposal(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	id, err := doDoltProposal(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(id)), nil
+}
+
+func doDoltProposal(ctx *sql.Context, args []string) (uint64, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 0, fmt.Errorf("Empty database name.")
+	}
+
+	apr, err := cli.CreateProposalArgParser().Parse(args)
+	if err != nil {
+		return 0, err
+	}
+	if apr.NArg() == 0 {
+		return 0, fmt.Errorf("error: invalid argument, use the 'dolt_proposals' system table to list proposals")
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return 0, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	if apr.Arg(0) == "open" {
+		return openProposal(ctx, dSess, ddb, apr)
+	}
+
+	if apr.NArg() != 2 {
+		return 0, InvalidArgErr
+	}
+	id, err := strconv.ParseUint(apr.Arg(1), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error: invalid proposal id '%s'", apr.Arg(1))
+	}
+	p, err := ddb.GetProposal(ctx, id)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %d", err, id)
+	}
+	if p.Status != doltdb.ProposalOpen {
+		return 0, fmt.Errorf("error: proposal %d is %s", id, p.Status)
+	}
+
+	user := ctx.Client().User
+	var update func(p *doltdb.Proposal)
+	switch apr.Arg(0) {
+	case "comment":
+		comment, ok := apr.GetValue(cli.MessageArg)
+		if !ok || len(strings.TrimSpace(comment)) == 0 {
+			return 0, fmt.Errorf("error: a comment is required, use -m to give one")
+		}
+		c := doltdb.ProposalComment{Author: user, Comment: comment, Created: time.Now()}
+		update = func(p *doltdb.Proposal) {
+			p.Comments = append(p.Comments, c)
+		}
+	case "approve":
+		// only the proposal's reviewers, and those who could merge it themselves, can approve it
+		if !p.IsReviewer(user) {
+			if err = branch_control.CanModifyBranch(ctx, p.Target); err != nil {
+				return 0, err
+			}
+		}
+		update = func(p *doltdb.Proposal) {
+			for _, a := range p.Approvals {
+				if a == user {
+					return
+				}
+			}
+			p.Approvals = append(p.Approvals, user)
+		}
+	case "merge":
+		commit, err := mergeProposal(ctx, dSess, dbName, p)
+		if err != nil {
+			return 0, err
+		}
+		update = func(p *doltdb.Proposal) {
+			p.Status = doltdb.ProposalMerged
+			p.MergeCommit = commit
+		}
+	case "close":
+		if user != p.Author {
+			if err = branch_control.CanModifyBranch(ctx, p.Target); err != nil {
+				return 0, err
+			}
+		}
+		update = func(p *doltdb.Proposal) {
+			p.Status = doltdb.ProposalClosed
+		}
+	default:
+		return 0, fmt.Errorf("error: invalid argument '%s'", apr.Arg(0))
+	}
+
+	return id, updateProposal(ctx, dSess, ddb, id, update)
+}
+
+// openProposal records a new proposal to merge one branch into another.
+func openProposal(ctx *sql.Context, dSess *dsess.DoltSession, ddb *doltdb.DoltDB, apr *argparser.ArgParseResults) (uint64, error) {
+	if apr.NArg() != 3 {
+		return 0, InvalidArgErr
+	}
+	source, target := apr.Arg(1), apr.Arg(2)
+	if len(source) == 0 || len(target) == 0 {
+		return 0, EmptyBranchNameErr
+	}
+	if source == target {
+		return 0, fmt.Errorf("error: a proposal cannot merge branch '%s' into itself", source)
+	}
+
+	for _, branch := range []string{source, target} {
+		hasRef, err := ddb.HasRef(ctx, ref.NewBranchRef(branch))
+		if err != nil {
+			return 0, err
+		} else if !hasRef {
+			return 0, fmt.Errorf("%w: %s", doltdb.ErrBranchNotFound, branch)
+		}
+	}
+
+	proposals, err := ddb.GetProposals(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range proposals {
+		if p.Status == doltdb.ProposalOpen && p.Source == source && p.Target == target {
+			return 0, fmt.Errorf("error: proposal %d already proposes merging '%s' into '%s'", p.ID, source, target)
+		}
+	}
+
+	now := time.Now()
+	p := doltdb.Proposal{
+		Source:  source,
+		Target:  target,
+		Status:  doltdb.ProposalOpen,
+		Author:  ctx.Client().User,
+		Created: now,
+		Updated: now,
+	}
+	p.Description, _ = apr.GetValue(cli.MessageArg)
+	if reviewers, ok := apr.GetValueList(cli.ReviewersParam); ok {
+		for _, r := range reviewers {
+			if r = strings.TrimSpace(r); len(r) > 0 {
+				p.Reviewers = append(p.Reviewers, r)
+			}
+		}
+	}
+
+	var rsc doltdb.ReplicationStatusController
+	err = ddb.CreateProposal(ctx, &p, proposalTagMeta(dSess), &rsc)
+	if err != nil {
+		return 0, err
+	}
+
+	dsess.WaitForReplicationController(ctx, rsc)
+	return p.ID, nil
+}
+
+// mergeProposal merges the source branch of |p| into the session's current branch, which must be the proposal's
+// target, and returns the hash of the merge commit. Every reviewer must have approved the proposal first.
+func mergeProposal(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, p doltdb.Proposal) (string, error) {
+	if pending := p.PendingReviewers(); len(pending) > 0 {
+		return "", fmt.Errorf("error: proposal %d is waiting for approval from: %s", p.ID, strings.Join(pending, ", "))
+	}
+
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	if headRef.GetPath() != p.Target {
+		return "", fmt.Errorf("error: proposal %d must be merged from its target branch '%s'; use dolt_checkout('%s') first", p.ID, p.Target, p.Target)
+	}
+
+	msg := fmt.Sprintf("Merge proposal #%d from %s into %s", p.ID, p.Source, p.Target)
+	if len(p.Description) > 0 {
+		msg += "\n\n" + p.Description
+	}
+	commit, conflicts, _, err := doDoltMerge(ctx, []string{p.Source, "--" + cli.NoFFParam, "-m", msg})
+	if err != nil {
+		return "", err
+	}
+	if conflicts != noConflictsOrViolations {
+		return "", fmt.Errorf("error: proposal %d cannot be merged until its conflicts with '%s' are resolved", p.ID, p.Target)
+	}
+	return commit, nil
+}
+
+// updateProposal applies |update| to the proposal with the id given, as long as the proposal is still open.
+func updateProposal(ctx *sql.Context, dSess *dsess.DoltSession, ddb *doltdb.DoltDB, id uint64, update func(p *doltdb.Proposal)) error {
+	var rsc doltdb.ReplicationStatusController
+	_, err := ddb.UpdateProposal(ctx, id, func(p *doltdb.Proposal) error {
+		if p.Status != doltdb.ProposalOpen {
+			return fmt.Errorf("error: proposal %d is %s", p.ID, p.Status)
+		}
+		update(p)
+		p.Updated = time.Now()
+		return nil
+	}, proposalTagMeta(dSess), &rsc)
+	if err != nil {
+		return err
+	}
+
+	dsess.WaitForReplicationController(ctx, rsc)
+	return nil
+}
+
+func proposalTagMeta(dSess *dsess.DoltSession) *datas.TagMeta {
+	return datas.NewTagMeta(dSess.Username(), dSess.Email(), "")
+}
@@ -82,7 +82,7 @@ func doDoltPush(ctx *sql.Context, args []string) (int, string, error) {
 		return cmdFailure, "", err
 	}
 
-	opts, err := env.NewPushOpts(ctx, apr, dbData.Rsr, dbData.Ddb, apr.Contains(cli.ForceFlag), apr.Contains(cli.SetUpstreamFlag), pushAutoSetUpRemote)
+	opts, err := env.NewPushOpts(ctx, apr, dbData.Rsr, dbData.Ddb, apr.Contains(cli.ForceFlag), apr.Contains(cli.ForceWithLeaseFlag), apr.Contains(cli.SetUpstreamFlag), pushAutoSetUpRemote, apr.Contains(cli.DeleteFlag))
 	if err != nil {
 		return cmdFailure, "", err
 	}
@@ -102,6 +102,8 @@ func doDoltPush(ctx *sql.Context, args []string) (int, string, error) {
 			return cmdSuccess, UpToDateMessage, nil
 		case datas.ErrMergeNeeded:
 			return cmdFailure, "", fmt.Errorf("%w; the tip of your current branch is behind its remote counterpart", err)
+		case actions.ErrStaleLease:
+			return cmdFailure, "", fmt.Errorf("%w; fetch and integrate the remote changes before pushing again", err)
 		default:
 			return cmdFailure, "", err
 		}
@@ -0,0 +1,64 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// doltReadReplicaRefresh is the stored procedure dolt_read_replica_refresh(), which pulls the current database of a
+// read replica from its remote, even if it pulled within @@dolt_read_replica_refresh_interval. Transactions that
+// begin after it returns read the heads it pulled.
+func doltReadReplicaRefresh(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltReadReplicaRefresh(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+func doDoltReadReplicaRefresh(ctx *sql.Context, args []string) (int, error) {
+	if len(args) != 0 {
+		return 1, InvalidArgErr
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 1, fmt.Errorf("Empty database name.")
+	}
+	baseName, _ := dsess.SplitRevisionDbName(dbName)
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	db, ok := dSess.Provider().BaseDatabase(ctx, baseName)
+	if !ok {
+		return 1, sql.ErrDatabaseNotFound.New(baseName)
+	}
+	rrd, ok := db.(dsess.RemoteReadReplicaDatabase)
+	if !ok || !rrd.ValidReplicaState(ctx) {
+		return 1, fmt.Errorf("database %s is not a read replica", baseName)
+	}
+
+	err := rrd.ForcePullFromRemote(ctx)
+	if err != nil && !dsess.IgnoreReplicationErrors() {
+		return 1, fmt.Errorf("replication error: %w", err)
+	} else if err != nil {
+		dsess.WarnReplicationError(ctx, err)
+	}
+	return 0, nil
+}
@@ -0,0 +1,463 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/rebase"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var ErrRebaseUncommittedChanges = errors.New("cannot rebase with uncommitted changes")
+
+var doltRebaseSchema = []*sql.Column{
+	{
+		Name:     "status",
+		Type:     types.Int64,
+		Nullable: false,
+	},
+	{
+		Name:     "message",
+		Type:     types.LongText,
+		Nullable: true,
+	},
+}
+
+// doltRebase is the stored procedure version for the CLI command `dolt rebase`, which replays the commits of the
+// current branch that aren't reachable from an upstream commit on top of it.
+//
+//	dolt_rebase([-i], <upstream>)
+//	dolt_rebase('--edit-todo', <todo>)
+//	dolt_rebase('--continue')
+//	dolt_rebase('--abort')
+//
+// An interactive rebase returns its plan as a todo list, which can be edited before the rebase is continued. A rebase
+// stops at a commit that can't be replayed cleanly. Its conflicts are resolved and staged as for a cherry-pick, and
+// the rebase is continued. The status is 0 once the rebase is done or aborted, and 1 while it's waiting to be
+// continued. The rebase is recorded in the database, against the branch being rebased, until then.
+func doltRebase(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	status, message, err := doDoltRebase(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(status), message), nil
+}
+
+func doDoltRebase(ctx *sql.Context, args []string) (int, string, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 1, "", fmt.Errorf("Empty database name.")
+	}
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return 1, "", err
+	}
+
+	apr, err := cli.CreateRebaseArgParser().Parse(args)
+	if err != nil {
+		return 1, "", err
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return 1, "", fmt.Errorf("Could not load database %s", dbName)
+	}
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return 1, "", err
+	}
+	branch := headRef.GetPath()
+
+	state, err := ddb.GetRebaseState(ctx, branch)
+	if err != nil {
+		return 1, "", err
+	}
+
+	r := &rebaser{dSess: dSess, dbName: dbName, ddb: ddb, headRef: headRef, state: state}
+	if modes := apr.ContainsMany(cli.AbortParam, cli.ContinueFlag, cli.EditTodoParam); len(modes) > 1 ||
+		len(modes) == 1 && (apr.NArg() != 0 || apr.Contains(cli.InteractiveFlag)) {
+		return 1, "", fmt.Errorf("error: --%s, --%s and --%s can't be combined with each other or with an upstream commit", cli.AbortParam, cli.ContinueFlag, cli.EditTodoParam)
+	}
+
+	switch {
+	case apr.ContainsAny(cli.AbortParam, cli.ContinueFlag, cli.EditTodoParam):
+		if state == nil {
+			return 1, "", fmt.Errorf("error: no rebase is in progress on branch '%s'", branch)
+		}
+		if apr.Contains(cli.AbortParam) {
+			return r.abort(ctx)
+		} else if apr.Contains(cli.ContinueFlag) {
+			return r.resume(ctx)
+		}
+		todo, _ := apr.GetValue(cli.EditTodoParam)
+		return r.editTodo(ctx, todo)
+	default:
+		if state != nil {
+			return 1, "", fmt.Errorf("error: a rebase is already in progress on branch '%s', use dolt_rebase('--continue') to continue it or dolt_rebase('--abort') to abort it", branch)
+		}
+		if apr.NArg() != 1 {
+			return 1, "", fmt.Errorf("error: the commit to rebase onto is required")
+		}
+		return r.start(ctx, apr.Arg(0), apr.Contains(cli.InteractiveFlag))
+	}
+}
+
+// rebaser runs the steps of the rebase of a session's current branch.
+type rebaser struct {
+	dSess   *dsess.DoltSession
+	dbName  string
+	ddb     *doltdb.DoltDB
+	headRef ref.DoltRef
+	state   *doltdb.RebaseState
+}
+
+// start plans the rebase of the current branch onto |upstream|, and runs it unless it's |interactive|.
+func (r *rebaser) start(ctx *sql.Context, upstream string, interactive bool) (int, string, error) {
+	roots, ok := r.dSess.GetRoots(ctx, r.dbName)
+	if !ok {
+		return 1, "", fmt.Errorf("Could not load database %s", r.dbName)
+	}
+	clean, err := diff.WorkingSetContainsOnlyIgnoredTables(ctx, roots)
+	if err != nil {
+		return 1, "", err
+	}
+	if !clean {
+		return 1, "", ErrRebaseUncommittedChanges
+	}
+
+	headCommit, err := r.ddb.ResolveCommitRef(ctx, r.headRef)
+	if err != nil {
+		return 1, "", err
+	}
+	headHash, err := headCommit.HashOf()
+	if err != nil {
+		return 1, "", err
+	}
+	upstreamHash, err := resolveCommitHash(ctx, r.ddb, r.headRef, upstream)
+	if err != nil {
+		return 1, "", err
+	}
+
+	// the commits of the branch that aren't reachable from upstream are replayed, oldest first, leaving out merges
+	commits, err := commitwalk.GetDotDotRevisions(ctx, r.ddb, []hash.Hash{headHash}, r.ddb, []hash.Hash{hash.Parse(upstreamHash)}, -1)
+	if err != nil {
+		return 1, "", err
+	}
+	var plan []doltdb.RebaseStep
+	for i := len(commits) - 1; i >= 0; i-- {
+		if commits[i].NumParents() > 1 {
+			continue
+		}
+		h, err := commits[i].HashOf()
+		if err != nil {
+			return 1, "", err
+		}
+		plan = append(plan, doltdb.RebaseStep{Action: doltdb.RebaseActionPick, Commit: h.String()})
+	}
+
+	if !interactive {
+		upstreamCommit, err := r.ddb.ReadCommit(ctx, hash.Parse(upstreamHash))
+		if err != nil {
+			return 1, "", err
+		}
+		ancestor, err := doltdb.GetCommitAncestor(ctx, headCommit, upstreamCommit)
+		if err != nil {
+			return 1, "", err
+		}
+		ancestorHash, err := ancestor.HashOf()
+		if err != nil {
+			return 1, "", err
+		}
+		if ancestorHash.String() == upstreamHash {
+			return 0, fmt.Sprintf("Current branch %s is up to date.", r.headRef.GetPath()), nil
+		}
+	}
+
+	r.state = &doltdb.RebaseState{Upstream: upstreamHash, OrigHead: headHash.String(), Plan: plan}
+	if interactive {
+		if err := r.saveState(ctx); err != nil {
+			return 1, "", err
+		}
+		todo, err := r.todo(ctx)
+		if err != nil {
+			return 1, "", err
+		}
+		return 1, todo, nil
+	}
+	return r.run(ctx)
+}
+
+// editTodo replaces the steps of the rebase that haven't been replayed with those of |todo|.
+func (r *rebaser) editTodo(ctx *sql.Context, todo string) (int, string, error) {
+	steps, err := rebase.ParseTodo(todo)
+	if err != nil {
+		return 1, "", err
+	}
+	for i := range steps {
+		steps[i].Commit, err = resolveCommitHash(ctx, r.ddb, r.headRef, steps[i].Commit)
+		if err != nil {
+			return 1, "", err
+		}
+	}
+
+	done := r.done()
+	plan := append(r.state.Plan[:done:done], steps...)
+	if err := rebase.ValidatePlan(plan); err != nil {
+		return 1, "", err
+	}
+	r.state.Plan = plan
+	if err := r.saveState(ctx); err != nil {
+		return 1, "", err
+	}
+
+	todo, err = r.todo(ctx)
+	if err != nil {
+		return 1, "", err
+	}
+	return 1, todo, nil
+}
+
+// resume starts an interactive rebase, or continues a rebase that stopped at a step, committing the resolution of the
+// step's conflicts.
+func (r *rebaser) resume(ctx *sql.Context) (int, string, error) {
+	if r.state.Paused {
+		ws, err := r.dSess.WorkingSet(ctx, r.dbName)
+		if err != nil {
+			return 1, "", err
+		}
+		// if the cherry-pick of the step was committed or aborted, there's nothing left to do for the step
+		if ws.MergeActive() {
+			err = r.commitResolvedStep(ctx, ws)
+			if err != nil {
+				return 1, "", err
+			}
+		}
+		r.state.Paused = false
+		r.state.Next++
+	}
+	return r.run(ctx)
+}
+
+// commitResolvedStep commits the resolution of the conflicts of the step the rebase stopped at.
+func (r *rebaser) commitResolvedStep(ctx *sql.Context, ws *doltdb.WorkingSet) error {
+	empty, err := checkCherryPickResolved(ctx, r.dSess, r.dbName, ws, "rebase")
+	if err != nil {
+		return err
+	}
+	// a step whose changes were all resolved away is left out
+	if empty {
+		return r.dSess.SetWorkingSet(ctx, r.dbName, ws.ClearMerge())
+	}
+
+	return r.commitStep(ctx, r.state.Plan[r.state.Next])
+}
+
+// abort resets the branch to where it was before the rebase started, and forgets the rebase.
+func (r *rebaser) abort(ctx *sql.Context) (int, string, error) {
+	if r.state.Started {
+		if _, err := doDoltReset(ctx, []string{"--hard", r.state.OrigHead}); err != nil {
+			return 1, "", err
+		}
+	}
+	if err := r.deleteState(ctx); err != nil {
+		return 1, "", err
+	}
+	return 0, fmt.Sprintf("Aborted the rebase of %s", r.headRef.GetPath()), nil
+}
+
+// run replays the steps of the rebase from the next one, until they're all done or one stops.
+func (r *rebaser) run(ctx *sql.Context) (int, string, error) {
+	if !r.state.Started {
+		if _, err := doDoltReset(ctx, []string{"--hard", r.state.Upstream}); err != nil {
+			return 1, "", err
+		}
+		// the reset moves the branch underneath the transaction, so a new one is needed to see it as the HEAD
+		if err := commitTransaction(ctx, r.dSess, nil); err != nil {
+			return 1, "", err
+		}
+		r.state.Started = true
+	}
+
+	for ; r.state.Next < len(r.state.Plan); r.state.Next++ {
+		step := r.state.Plan[r.state.Next]
+		if step.Action == doltdb.RebaseActionDrop {
+			continue
+		}
+
+		conflicts, err := r.replayStep(ctx, step)
+		if err != nil {
+			// the step wasn't replayed, so the plan can be edited to get past it
+			if serr := r.saveState(ctx); serr != nil {
+				return 1, "", serr
+			}
+			return 1, fmt.Sprintf("error: could not apply %s: %s\n"+
+				"Edit the rest of the plan with dolt_rebase('--edit-todo', <todo>), or abort the rebase with dolt_rebase('--abort').", step.Commit, err.Error()), nil
+		}
+		if conflicts {
+			r.state.Paused = true
+			if err := r.saveState(ctx); err != nil {
+				return 1, "", err
+			}
+			return 1, fmt.Sprintf("error: could not apply %s because of conflicts.\n"+
+				"Resolve the conflicts, stage the resolved tables with dolt_add(), and continue the rebase with dolt_rebase('--continue'), or abort it with dolt_rebase('--abort').", step.Commit), nil
+		}
+	}
+
+	if err := r.deleteState(ctx); err != nil {
+		return 1, "", err
+	}
+	return 0, fmt.Sprintf("Successfully rebased and updated %s", r.headRef.String()), nil
+}
+
+// replayStep cherry-picks the commit of |step| onto the branch and commits it, unless the cherry-pick has conflicts. A
+// commit whose changes are already on the branch is left out.
+func (r *rebaser) replayStep(ctx *sql.Context, step doltdb.RebaseStep) (bool, error) {
+	roots, ok := r.dSess.GetRoots(ctx, r.dbName)
+	if !ok {
+		return false, fmt.Errorf("Could not load database %s", r.dbName)
+	}
+
+	mergeResult, _, err := cherryPick(ctx, r.dSess, roots, r.dbName, step.Commit)
+	if errors.Is(err, ErrCherryPickNoChanges) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	err = r.dSess.SetRoot(ctx, r.dbName, mergeResult.Root)
+	if err != nil {
+		return false, err
+	}
+	err = stageCherryPickedTables(ctx, mergeResult.Stats)
+	if err != nil {
+		return false, err
+	}
+	if mergeResult.HasMergeArtifacts() {
+		return true, nil
+	}
+
+	return false, r.commitStep(ctx, step)
+}
+
+// commitStep commits the staged changes of |step|, keeping the author and date of its commit. A squash step amends the
+// previous commit instead, combining their messages.
+func (r *rebaser) commitStep(ctx *sql.Context, step doltdb.RebaseStep) error {
+	cm, err := r.ddb.ReadCommit(ctx, hash.Parse(step.Commit))
+	if err != nil {
+		return err
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return err
+	}
+
+	msg := meta.Description
+	if step.Action == doltdb.RebaseActionReword {
+		msg = step.Message
+	}
+
+	var args []string
+	if step.Action == doltdb.RebaseActionSquash {
+		head, err := r.dSess.GetHeadCommit(ctx, r.dbName)
+		if err != nil {
+			return err
+		}
+		headMeta, err := head.GetCommitMeta(ctx)
+		if err != nil {
+			return err
+		}
+		args = append(args, "--amend")
+		msg = headMeta.Description + "\n\n" + msg
+		meta = headMeta
+	}
+
+	args = append(args, "-m", msg, "--author", fmt.Sprintf("%s <%s>", meta.Name, meta.Email), "--date", meta.Time().UTC().Format(time.RFC3339))
+	if _, _, err = doDoltCommit(ctx, args); err != nil {
+		return err
+	}
+
+	// the commit ends the transaction, and the next step must see it as the HEAD
+	newTx, err := r.dSess.StartTransaction(ctx, sql.ReadWrite)
+	if err != nil {
+		return err
+	}
+	ctx.SetTransaction(newTx)
+	return nil
+}
+
+// done returns the number of steps of the plan that have been replayed, or are being replayed.
+func (r *rebaser) done() int {
+	if !r.state.Started {
+		return 0
+	} else if r.state.Paused {
+		return r.state.Next + 1
+	}
+	return r.state.Next
+}
+
+// todo returns the todo list of the steps of the rebase that haven't been replayed.
+func (r *rebaser) todo(ctx *sql.Context) (string, error) {
+	plan := r.state.Plan[r.done():]
+	subjects := make(map[string]string, len(plan))
+	for _, step := range plan {
+		cm, err := r.ddb.ReadCommit(ctx, hash.Parse(step.Commit))
+		if err != nil {
+			return "", err
+		}
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return "", err
+		}
+		subjects[step.Commit] = strings.SplitN(meta.Description, "\n", 2)[0]
+	}
+
+	header := fmt.Sprintf("Rebase %s onto %s (%d commands)", r.headRef.GetPath(), r.state.Upstream, len(plan))
+	return rebase.FormatTodo(plan, subjects, header), nil
+}
+
+func (r *rebaser) saveState(ctx *sql.Context) error {
+	var rsc doltdb.ReplicationStatusController
+	err := r.ddb.SetRebaseState(ctx, r.headRef.GetPath(), *r.state, datas.NewTagMeta(r.dSess.Username(), r.dSess.Email(), ""), &rsc)
+	if err != nil {
+		return err
+	}
+	dsess.WaitForReplicationController(ctx, rsc)
+	return nil
+}
+
+func (r *rebaser) deleteState(ctx *sql.Context) error {
+	var rsc doltdb.ReplicationStatusController
+	err := r.ddb.DeleteRebaseState(ctx, r.headRef.GetPath(), &rsc)
+	if err != nil {
+		return err
+	}
+	dsess.WaitForReplicationController(ctx, rsc)
+	return nil
+}
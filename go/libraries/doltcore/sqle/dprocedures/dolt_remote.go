@@ -24,6 +24,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
@@ -39,7 +40,7 @@ func doltRemote(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 	return rowToIter(res), nil
 }
 
-// doDoltRemote is used as sql dolt_remote command for only creating or deleting remotes, not listing.
+// doDoltRemote is used as sql dolt_remote command for only creating, deleting or pruning remotes, not listing.
 // To list remotes, dolt_remotes system table is used.
 func doDoltRemote(ctx *sql.Context, args []string) (int, error) {
 	dbName := ctx.GetCurrentDatabase()
@@ -71,6 +72,8 @@ func doDoltRemote(ctx *sql.Context, args []string) (int, error) {
 		err = addRemote(ctx, dbName, dbData, apr, dSess)
 	case "remove", "rm":
 		err = removeRemote(ctx, dbData, apr, &rsc)
+	case "prune":
+		err = pruneRemotes(ctx, dbData, apr, dSess)
 	default:
 		err = fmt.Errorf("error: invalid argument")
 	}
@@ -143,3 +146,34 @@ func removeRemote(ctx *sql.Context, dbd env.DbData, apr *argparser.ArgParseResul
 
 	return dbd.Rsw.RemoveRemote(ctx, remote.Name)
 }
+
+func pruneRemotes(ctx *sql.Context, dbd env.DbData, apr *argparser.ArgParseResults, sess *dsess.DoltSession) error {
+	if apr.NArg() < 2 {
+		return fmt.Errorf("error: invalid argument")
+	}
+
+	remotes, err := dbd.Rsr.GetRemotes()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range apr.Args[1:] {
+		name = strings.TrimSpace(name)
+		remote, ok := remotes[name]
+		if !ok {
+			return fmt.Errorf("error: unknown remote: '%s'", name)
+		}
+
+		srcDB, err := sess.Provider().GetRemoteDB(ctx, dbd.Ddb.ValueReadWriter().Format(), remote, false)
+		if err != nil {
+			return err
+		}
+
+		err = actions.PruneRemoteBranches(ctx, dbd, srcDB, remote)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,667 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// schemaBundleHeader is the first line of every schema bundle. Each object in the bundle follows a line of the form
+// "-- <type>: <name>", and the bundle's database collation, if any, is given by a line "-- collation: <name>".
+const schemaBundleHeader = "/* dolt schema bundle v1 */"
+
+type schemaObjectType string
+
+const (
+	schemaObjectTable     schemaObjectType = "table"
+	schemaObjectView      schemaObjectType = "view"
+	schemaObjectTrigger   schemaObjectType = "trigger"
+	schemaObjectProcedure schemaObjectType = "procedure"
+)
+
+// schemaObjectTypes lists the types of schema objects in the order they're created in.
+var schemaObjectTypes = []schemaObjectType{schemaObjectTable, schemaObjectView, schemaObjectTrigger, schemaObjectProcedure}
+
+// schemaObject is a single table, view, trigger or procedure in a schema bundle, along with the DDL that creates it.
+type schemaObject struct {
+	typ  schemaObjectType
+	name string
+	ddl  string
+}
+
+// schemaBundle is the full schema of a database. Its objects are ordered by type, then name, so that exporting the
+// same schema always produces the same bundle.
+type schemaBundle struct {
+	collation string
+	objects   []schemaObject
+}
+
+func (b schemaBundle) String() string {
+	var sb strings.Builder
+	sb.WriteString(schemaBundleHeader)
+	sb.WriteString("\n")
+	if len(b.collation) > 0 {
+		sb.WriteString(fmt.Sprintf("-- collation: %s\n", b.collation))
+	}
+	for _, o := range b.objects {
+		sb.WriteString(fmt.Sprintf("-- %s: %s\n%s;\n", o.typ, o.name, o.ddl))
+	}
+	return sb.String()
+}
+
+// objectsOfType returns the objects of the type given, keyed by lowercase name.
+func (b schemaBundle) objectsOfType(typ schemaObjectType) map[string]schemaObject {
+	objects := make(map[string]schemaObject)
+	for _, o := range b.objects {
+		if o.typ == typ {
+			objects[strings.ToLower(o.name)] = o
+		}
+	}
+	return objects
+}
+
+func parseSchemaBundle(s string) (schemaBundle, error) {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if strings.TrimSpace(lines[0]) != schemaBundleHeader {
+		return schemaBundle{}, fmt.Errorf("error: not a schema bundle exported by dolt_export_schema()")
+	}
+
+	var b schemaBundle
+	var ddl []string
+	finish := func() {
+		if len(b.objects) > 0 {
+			o := &b.objects[len(b.objects)-1]
+			o.ddl = strings.TrimSuffix(strings.TrimSpace(strings.Join(ddl, "\n")), ";")
+		}
+		ddl = nil
+	}
+
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "-- ") {
+			header := strings.TrimPrefix(line, "-- ")
+			if strings.HasPrefix(header, "collation: ") {
+				b.collation = strings.TrimSpace(strings.TrimPrefix(header, "collation: "))
+				continue
+			}
+			if typ, name, ok := parseSchemaObjectHeader(header); ok {
+				finish()
+				b.objects = append(b.objects, schemaObject{typ: typ, name: name})
+				continue
+			}
+		}
+		if len(b.objects) == 0 {
+			if len(strings.TrimSpace(line)) == 0 {
+				continue
+			}
+			return schemaBundle{}, fmt.Errorf("error: schema bundle has a statement outside of any object: %s", line)
+		}
+		ddl = append(ddl, line)
+	}
+	finish()
+
+	for _, o := range b.objects {
+		if len(o.ddl) == 0 {
+			return schemaBundle{}, fmt.Errorf("error: schema bundle has no definition for %s '%s'", o.typ, o.name)
+		}
+	}
+	return b, nil
+}
+
+// validate returns an error if any object's definition isn't a single statement creating an object of its type in the
+// database named |dbName|. Bundles are applied with the caller's privileges, but must still only hold schema DDL.
+func (b schemaBundle) validate(ctx *sql.Context, dbName string) error {
+	options := sql.LoadSqlMode(ctx).ParserOptions()
+	baseName, _ := dsess.SplitRevisionDbName(dbName)
+	for _, o := range b.objects {
+		stmt, ri, err := sqlparser.ParseOneWithOptions(o.ddl, options)
+		if err != nil {
+			return fmt.Errorf("error: could not parse the definition of %s '%s': %w", o.typ, o.name, err)
+		}
+		if ri != 0 && ri < len(o.ddl) && len(strings.TrimSpace(strings.TrimLeft(o.ddl[ri:], ";"))) > 0 {
+			return fmt.Errorf("error: the definition of %s '%s' has more than one statement", o.typ, o.name)
+		}
+
+		ddl, ok := stmt.(*sqlparser.DDL)
+		if ok && ddl.Action == sqlparser.CreateStr {
+			switch o.typ {
+			case schemaObjectTable:
+				ok = ddl.TableSpec != nil && ddl.OptSelect == nil && ddl.OptLike == nil
+			case schemaObjectView:
+				ok = ddl.ViewSpec != nil
+			case schemaObjectTrigger:
+				ok = ddl.TriggerSpec != nil
+			case schemaObjectProcedure:
+				ok = ddl.ProcedureSpec != nil
+			}
+		} else {
+			ok = false
+		}
+		if !ok {
+			return fmt.Errorf("error: the definition of %s '%s' is not a CREATE %s statement", o.typ, o.name, strings.ToUpper(string(o.typ)))
+		}
+
+		if qualifier := ddl.Table.Qualifier.String(); len(qualifier) > 0 &&
+			!strings.EqualFold(qualifier, dbName) && !strings.EqualFold(qualifier, baseName) {
+			return fmt.Errorf("error: the definition of %s '%s' creates it outside of database '%s'", o.typ, o.name, dbName)
+		}
+	}
+	return nil
+}
+
+func parseSchemaObjectHeader(header string) (schemaObjectType, string, bool) {
+	for _, typ := range schemaObjectTypes {
+		prefix := string(typ) + ": "
+		if strings.HasPrefix(header, prefix) {
+			return typ, strings.TrimSpace(strings.TrimPrefix(header, prefix)), true
+		}
+	}
+	return "", "", false
+}
+
+// doltExportSchema is the stored procedure dolt_export_schema(), which returns a schema bundle holding the tables,
+// indexes, constraints, views, triggers, procedures and collations of a revision, by default the current working set.
+// The bundle can be applied to another database with dolt_import_schema().
+func doltExportSchema(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	bundle, err := doDoltExportSchema(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(bundle), nil
+}
+
+func doDoltExportSchema(ctx *sql.Context, args []string) (string, error) {
+	if len(args) > 1 {
+		return "", InvalidArgErr
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return "", fmt.Errorf("Empty database name.")
+	}
+	if len(args) == 1 {
+		if len(args[0]) == 0 {
+			return "", InvalidArgErr
+		}
+		baseName, _ := dsess.SplitRevisionDbName(dbName)
+		dbName = baseName + dsess.DbRevisionDelimiter + args[0]
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	db, err := dSess.Provider().Database(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	sqlDb, ok := db.(dsess.SqlDatabase)
+	if !ok {
+		return "", fmt.Errorf("unexpected database type: %T", db)
+	}
+	root, err := sqlDb.GetRoot(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	engine := dsess.StatementRunnerFor(dSess.Provider())
+	if engine == nil {
+		return "", fmt.Errorf("error: dolt_export_schema requires a running SQL engine")
+	}
+	bundle, err := readSchemaBundle(ctx, engine, dbName, root)
+	if err != nil {
+		return "", err
+	}
+	return bundle.String(), nil
+}
+
+var autoIncrementOption = regexp.MustCompile(` AUTO_INCREMENT=\d+`)
+
+// readSchemaBundle reads the schema of the database given, whose root value is |root|.
+func readSchemaBundle(ctx *sql.Context, engine dsess.StatementRunner, dbName string, root *doltdb.RootValue) (schemaBundle, error) {
+	var b schemaBundle
+	collation, err := root.GetCollation(ctx)
+	if err != nil {
+		return schemaBundle{}, err
+	}
+	b.collation = sql.CollationID(collation).Name()
+
+	db := quoteIdentifier(dbName)
+	tableNames, err := root.GetTableNames(ctx)
+	if err != nil {
+		return schemaBundle{}, err
+	}
+	sort.Strings(tableNames)
+	for _, name := range tableNames {
+		if doltdb.HasDoltPrefix(name) {
+			continue
+		}
+		rows, err := runQuery(ctx, engine, fmt.Sprintf("SHOW CREATE TABLE %s.%s", db, quoteIdentifier(name)))
+		if err != nil {
+			return schemaBundle{}, err
+		}
+		// the next auto increment value depends on the table's data, not its schema
+		ddl := autoIncrementOption.ReplaceAllString(rows[0][1].(string), "")
+		b.objects = append(b.objects, schemaObject{typ: schemaObjectTable, name: name, ddl: ddl})
+	}
+
+	_, _, ok, err := root.GetTableInsensitive(ctx, doltdb.SchemasTableName)
+	if err != nil {
+		return schemaBundle{}, err
+	}
+	if ok {
+		rows, err := runQuery(ctx, engine, fmt.Sprintf("SELECT type, name, fragment FROM %s.%s ORDER BY type DESC, name", db, doltdb.SchemasTableName))
+		if err != nil {
+			return schemaBundle{}, err
+		}
+		for _, row := range rows {
+			typ, name, fragment := row[0].(string), row[1].(string), row[2].(string)
+			switch typ {
+			case "view":
+				b.objects = append(b.objects, schemaObject{typ: schemaObjectView, name: name, ddl: fragment})
+			case "trigger":
+				b.objects = append(b.objects, schemaObject{typ: schemaObjectTrigger, name: name, ddl: fragment})
+			}
+		}
+	}
+
+	_, _, ok, err = root.GetTableInsensitive(ctx, doltdb.ProceduresTableName)
+	if err != nil {
+		return schemaBundle{}, err
+	}
+	if ok {
+		rows, err := runQuery(ctx, engine, fmt.Sprintf("SELECT %s, %s FROM %s.%s ORDER BY %s", doltdb.ProceduresTableNameCol,
+			doltdb.ProceduresTableCreateStmtCol, db, doltdb.ProceduresTableName, doltdb.ProceduresTableNameCol))
+		if err != nil {
+			return schemaBundle{}, err
+		}
+		for _, row := range rows {
+			b.objects = append(b.objects, schemaObject{typ: schemaObjectProcedure, name: row[0].(string), ddl: row[1].(string)})
+		}
+	}
+
+	return b, nil
+}
+
+// schemaImportResult counts the schema objects a bundle created, altered, dropped and left unchanged.
+type schemaImportResult struct {
+	created, altered, dropped, unchanged int
+}
+
+// doltImportSchema is the stored procedure dolt_import_schema(), which applies a schema bundle exported by
+// dolt_export_schema() to the current working set. Only objects that differ from the bundle are changed: missing
+// objects are created, tables whose definitions differ are altered in place, and views, triggers and procedures whose
+// definitions differ are replaced. Objects that aren't in the bundle are left alone, unless --prune is given. Applying
+// the same bundle twice changes nothing the second time. Every object in the bundle must be defined by a single CREATE
+// statement, and the bundle is applied by the session's engine, so the caller's privileges apply.
+func doltImportSchema(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltImportSchema(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res.created), int64(res.altered), int64(res.dropped), int64(res.unchanged)), nil
+}
+
+func doDoltImportSchema(ctx *sql.Context, args []string) (schemaImportResult, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return schemaImportResult{}, fmt.Errorf("Empty database name.")
+	}
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return schemaImportResult{}, err
+	}
+
+	apr, err := cli.CreateImportSchemaArgParser().Parse(args)
+	if err != nil {
+		return schemaImportResult{}, err
+	}
+	if apr.NArg() != 1 {
+		return schemaImportResult{}, InvalidArgErr
+	}
+	bundle, err := parseSchemaBundle(apr.Arg(0))
+	if err != nil {
+		return schemaImportResult{}, err
+	}
+	if err = bundle.validate(ctx, dbName); err != nil {
+		return schemaImportResult{}, err
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	engine := dsess.StatementRunnerFor(dSess.Provider())
+	if engine == nil {
+		return schemaImportResult{}, fmt.Errorf("error: dolt_import_schema requires a running SQL engine")
+	}
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return schemaImportResult{}, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	// The bundle is applied as part of this statement, and must not commit its transaction out from under it.
+	ignore := ctx.GetIgnoreAutoCommit()
+	ctx.SetIgnoreAutoCommit(true)
+	defer ctx.SetIgnoreAutoCommit(ignore)
+
+	current, err := readSchemaBundle(ctx, engine, dbName, roots.Working)
+	if err != nil {
+		return schemaImportResult{}, err
+	}
+
+	imp := &schemaImporter{
+		engine: engine,
+		dryRun: apr.Contains(cli.DryRunFlag),
+	}
+
+	// tables are created in name order, so foreign keys may reference tables that don't exist yet
+	fkChecks, err := ctx.GetSessionVariable(ctx, "foreign_key_checks")
+	if err != nil {
+		return schemaImportResult{}, err
+	}
+	if err = ctx.SetSessionVariable(ctx, "foreign_key_checks", int8(0)); err != nil {
+		return schemaImportResult{}, err
+	}
+	defer ctx.SetSessionVariable(ctx, "foreign_key_checks", fkChecks)
+
+	if apr.Contains(cli.PruneFlag) {
+		// drop in the reverse of the order objects are created in, since triggers and views depend on tables
+		for i := len(schemaObjectTypes) - 1; i >= 0; i-- {
+			typ := schemaObjectTypes[i]
+			wanted := bundle.objectsOfType(typ)
+			for _, o := range current.objects {
+				if _, ok := wanted[strings.ToLower(o.name)]; o.typ == typ && !ok {
+					if err = imp.exec(ctx, dropSchemaObjectStmt(o)); err != nil {
+						return schemaImportResult{}, err
+					}
+					imp.res.dropped++
+				}
+			}
+		}
+	}
+
+	if len(bundle.collation) > 0 && !strings.EqualFold(bundle.collation, current.collation) {
+		baseName, _ := dsess.SplitRevisionDbName(dbName)
+		if err = imp.exec(ctx, fmt.Sprintf("ALTER DATABASE %s COLLATE %s", quoteIdentifier(baseName), bundle.collation)); err != nil {
+			return schemaImportResult{}, err
+		}
+		imp.res.altered++
+	}
+
+	for _, typ := range schemaObjectTypes {
+		existing := current.objectsOfType(typ)
+		var pending []schemaObject
+		for _, o := range bundle.objects {
+			if o.typ == typ {
+				pending = append(pending, o)
+			}
+		}
+
+		// views may select from views later in the bundle, so keep retrying the ones that fail as long as others succeed
+		for len(pending) > 0 {
+			var failed []schemaObject
+			var lastErr error
+			for _, o := range pending {
+				err = imp.apply(ctx, o, existing)
+				if err != nil && typ == schemaObjectView {
+					failed, lastErr = append(failed, o), err
+				} else if err != nil {
+					return schemaImportResult{}, err
+				}
+			}
+			if len(failed) == len(pending) {
+				return schemaImportResult{}, lastErr
+			}
+			pending = failed
+		}
+	}
+
+	return imp.res, nil
+}
+
+type schemaImporter struct {
+	engine dsess.StatementRunner
+	dryRun bool
+	res    schemaImportResult
+}
+
+func (imp *schemaImporter) exec(ctx *sql.Context, query string) error {
+	if imp.dryRun {
+		return nil
+	}
+	_, err := runQuery(ctx, imp.engine, query)
+	return err
+}
+
+// apply creates the object given, or brings its existing definition in line with it.
+func (imp *schemaImporter) apply(ctx *sql.Context, o schemaObject, existing map[string]schemaObject) error {
+	cur, ok := existing[strings.ToLower(o.name)]
+	switch {
+	case !ok:
+		if err := imp.exec(ctx, o.ddl); err != nil {
+			return err
+		}
+		imp.res.created++
+	case cur.ddl == o.ddl:
+		imp.res.unchanged++
+	case o.typ == schemaObjectTable:
+		stmts, err := alterTableStatements(o.name, cur.ddl, o.ddl)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range stmts {
+			if err = imp.exec(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		if !imp.dryRun {
+			rows, err := runQuery(ctx, imp.engine, "SHOW CREATE TABLE "+quoteIdentifier(o.name))
+			if err != nil {
+				return err
+			}
+			if autoIncrementOption.ReplaceAllString(rows[0][1].(string), "") != o.ddl {
+				return fmt.Errorf("error: could not alter table '%s' to match its definition in the schema bundle", o.name)
+			}
+		}
+		imp.res.altered++
+	default:
+		if err := imp.exec(ctx, dropSchemaObjectStmt(cur)); err != nil {
+			return err
+		}
+		if err := imp.exec(ctx, o.ddl); err != nil {
+			return err
+		}
+		imp.res.altered++
+	}
+	return nil
+}
+
+func dropSchemaObjectStmt(o schemaObject) string {
+	return fmt.Sprintf("DROP %s %s", strings.ToUpper(string(o.typ)), quoteIdentifier(o.name))
+}
+
+type tableDefinitionKind int
+
+const (
+	tableDefColumn tableDefinitionKind = iota
+	tableDefPrimaryKey
+	tableDefIndex
+	tableDefCheck
+	tableDefForeignKey
+)
+
+// tableDefinition is a single line of the body of a SHOW CREATE TABLE statement, such as a column or an index.
+type tableDefinition struct {
+	kind tableDefinitionKind
+	// name is the quoted name of the column, index or constraint
+	name string
+	def  string
+}
+
+func (d tableDefinition) key() string {
+	return fmt.Sprintf("%d %s", d.kind, strings.ToLower(d.name))
+}
+
+func (d tableDefinition) dropClause() string {
+	switch d.kind {
+	case tableDefColumn:
+		return "DROP COLUMN " + d.name
+	case tableDefPrimaryKey:
+		return "DROP PRIMARY KEY"
+	case tableDefIndex:
+		return "DROP INDEX " + d.name
+	case tableDefCheck:
+		return "DROP CHECK " + d.name
+	default:
+		return "DROP FOREIGN KEY " + d.name
+	}
+}
+
+// parseCreateTable splits the output of SHOW CREATE TABLE into its definitions and its table options. Since
+// SHOW CREATE TABLE always puts each column, index and constraint on its own line, two tables can be compared one
+// definition at a time.
+func parseCreateTable(stmt string) ([]tableDefinition, string, error) {
+	lines := strings.Split(stmt, "\n")
+	if len(lines) < 2 {
+		return nil, "", fmt.Errorf("error: could not parse table definition: %s", stmt)
+	}
+	options := strings.TrimSpace(strings.TrimPrefix(lines[len(lines)-1], ")"))
+
+	var defs []tableDefinition
+	for _, line := range lines[1 : len(lines)-1] {
+		def := strings.TrimSuffix(strings.TrimSpace(line), ",")
+		d := tableDefinition{def: def}
+		switch {
+		case strings.HasPrefix(def, "`"):
+			d.kind, d.name = tableDefColumn, leadingIdentifier(def)
+		case strings.HasPrefix(def, "PRIMARY KEY "):
+			d.kind = tableDefPrimaryKey
+		case strings.HasPrefix(def, "CONSTRAINT "):
+			d.name = leadingIdentifier(strings.TrimPrefix(def, "CONSTRAINT "))
+			rest := strings.TrimSpace(strings.TrimPrefix(def, "CONSTRAINT "+d.name))
+			if strings.HasPrefix(rest, "FOREIGN KEY") {
+				d.kind = tableDefForeignKey
+			} else {
+				d.kind = tableDefCheck
+			}
+		default:
+			_, rest, ok := strings.Cut(def, "KEY ")
+			if !ok {
+				return nil, "", fmt.Errorf("error: could not parse table definition: %s", def)
+			}
+			d.kind, d.name = tableDefIndex, leadingIdentifier(rest)
+		}
+		defs = append(defs, d)
+	}
+	return defs, options, nil
+}
+
+// leadingIdentifier returns the backtick quoted identifier at the start of |s|.
+func leadingIdentifier(s string) string {
+	if !strings.HasPrefix(s, "`") {
+		return ""
+	}
+	for i := 1; i < len(s); i++ {
+		if s[i] != '`' {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '`' {
+			i++
+			continue
+		}
+		return s[:i+1]
+	}
+	return s
+}
+
+// alterTableStatements returns the ALTER TABLE statements that change the definition of |table| from |from| to |to|,
+// both of which are the output of SHOW CREATE TABLE.
+func alterTableStatements(table, from, to string) ([]string, error) {
+	fromDefs, fromOptions, err := parseCreateTable(from)
+	if err != nil {
+		return nil, err
+	}
+	toDefs, toOptions, err := parseCreateTable(to)
+	if err != nil {
+		return nil, err
+	}
+
+	fromByKey := make(map[string]tableDefinition, len(fromDefs))
+	fromPrevCol := make(map[string]string)
+	prev := ""
+	for _, d := range fromDefs {
+		fromByKey[d.key()] = d
+		if d.kind == tableDefColumn {
+			fromPrevCol[d.key()] = prev
+			prev = strings.ToLower(d.name)
+		}
+	}
+	toByKey := make(map[string]tableDefinition, len(toDefs))
+	for _, d := range toDefs {
+		toByKey[d.key()] = d
+	}
+
+	var clauses []string
+	// drop the constraints and indexes that were removed or changed, then the columns that were removed
+	for _, kind := range []tableDefinitionKind{tableDefForeignKey, tableDefCheck, tableDefIndex, tableDefPrimaryKey, tableDefColumn} {
+		for _, d := range fromDefs {
+			if t, ok := toByKey[d.key()]; d.kind == kind && (!ok || (kind != tableDefColumn && t.def != d.def)) {
+				clauses = append(clauses, d.dropClause())
+			}
+		}
+	}
+
+	// add or modify columns in the order they appear in |to|
+	prev = ""
+	for _, d := range toDefs {
+		if d.kind != tableDefColumn {
+			continue
+		}
+		position := " FIRST"
+		if len(prev) > 0 {
+			position = " AFTER " + prev
+		}
+		f, ok := fromByKey[d.key()]
+		if !ok {
+			clauses = append(clauses, "ADD COLUMN "+d.def+position)
+		} else if f.def != d.def || fromPrevCol[d.key()] != strings.ToLower(prev) {
+			clauses = append(clauses, "MODIFY COLUMN "+d.def+position)
+		}
+		prev = d.name
+	}
+
+	// add the constraints and indexes that were added or changed
+	for _, kind := range []tableDefinitionKind{tableDefPrimaryKey, tableDefIndex, tableDefCheck, tableDefForeignKey} {
+		for _, d := range toDefs {
+			if f, ok := fromByKey[d.key()]; d.kind == kind && (!ok || f.def != d.def) {
+				clauses = append(clauses, "ADD "+d.def)
+			}
+		}
+	}
+
+	stmts := make([]string, 0, len(clauses)+1)
+	for _, c := range clauses {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s %s", quoteIdentifier(table), c))
+	}
+	if fromOptions != toOptions {
+		options := strings.TrimSpace(strings.TrimPrefix(toOptions, "ENGINE=InnoDB"))
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s %s", quoteIdentifier(table), options))
+	}
+	return stmts, nil
+}
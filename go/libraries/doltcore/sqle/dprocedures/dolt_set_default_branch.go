@@ -0,0 +1,101 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+// doltSetDefaultBranch is the stored procedure dolt_set_default_branch(), which changes the branch that new sessions
+// connect to when they don't name one. The default branch is recorded in the database itself, which carries it to
+// clones, read replicas and cluster standbys, as well as in the database's repo state and the
+// @@<db>_default_branch system variable, if that has been set.
+func doltSetDefaultBranch(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltSetDefaultBranch(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+func doDoltSetDefaultBranch(ctx *sql.Context, args []string) (int, error) {
+	if len(args) != 1 {
+		return 1, InvalidArgErr
+	}
+	if len(args[0]) == 0 {
+		return 1, EmptyBranchNameErr
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 1, fmt.Errorf("Empty database name.")
+	}
+	baseName, _ := dsess.SplitRevisionDbName(dbName)
+
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return 1, err
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return 1, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	branchName, ok, err := ddb.HasBranch(ctx, args[0])
+	if err != nil {
+		return 1, err
+	}
+	if !ok {
+		return 1, fmt.Errorf("%w: %s", doltdb.ErrBranchNotFound, args[0])
+	}
+
+	var rsc doltdb.ReplicationStatusController
+	meta := datas.NewTagMeta(dSess.Username(), dSess.Email(), "")
+	err = ddb.SetDefaultBranch(ctx, branchName, meta, &rsc)
+	if err != nil {
+		return 1, err
+	}
+
+	baseDb, ok := dSess.Provider().BaseDatabase(ctx, baseName)
+	if !ok {
+		return 1, sql.ErrDatabaseNotFound.New(baseName)
+	}
+	err = baseDb.DbData().Rsw.SetCWBHeadRef(ctx, ref.MarshalableRef{Ref: ref.NewBranchRef(branchName)})
+	if err != nil {
+		return 1, err
+	}
+
+	// The system variable takes precedence over everything else, so it has to be kept in sync if it's in use
+	defaultBranchKey := dsess.DefaultBranchKey(baseName)
+	if _, val, ok := sql.SystemVariables.GetGlobal(defaultBranchKey); ok && val != "" {
+		err = sql.SystemVariables.SetGlobal(defaultBranchKey, branchName)
+		if err != nil {
+			return 1, err
+		}
+	}
+
+	dsess.WaitForReplicationController(ctx, rsc)
+
+	return 0, nil
+}
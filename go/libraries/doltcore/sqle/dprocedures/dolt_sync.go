@@ -0,0 +1,213 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+// doltSync is the stored procedure dolt_sync(), which makes the current branch match its remote branch exactly,
+// whatever the histories of the two branches. It's equivalent to fetching the remote branch and running
+// `dolt reset --hard` to it.
+func doltSync(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltSync(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+func doDoltSync(ctx *sql.Context, args []string) (int, error) {
+	dbName := ctx.GetCurrentDatabase()
+
+	if len(dbName) == 0 {
+		return 1, fmt.Errorf("empty database name.")
+	}
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return 1, err
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	dbData, ok := sess.GetDbData(ctx, dbName)
+	if !ok {
+		return 1, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	apr, err := cli.CreateSyncArgParser().Parse(args)
+	if err != nil {
+		return 1, err
+	}
+	if apr.ContainsAll(cli.ForceFlag, cli.StashFlag) {
+		return 1, fmt.Errorf("error: --%s and --%s are mutually exclusive options.", cli.ForceFlag, cli.StashFlag)
+	}
+
+	var remoteName, remoteRefName string
+	if apr.NArg() >= 1 {
+		remoteName = apr.Arg(0)
+	}
+	if apr.NArg() == 2 {
+		remoteRefName = apr.Arg(1)
+	}
+
+	pullSpec, err := env.NewPullSpec(ctx, dbData.Rsr, remoteName, remoteRefName, apr.NArg() == 1)
+	if err != nil {
+		return 1, err
+	}
+	if pullSpec.Branch == nil {
+		return 1, fmt.Errorf("the current branch has no upstream branch to sync with")
+	}
+
+	roots, ok := sess.GetRoots(ctx, dbName)
+	if !ok {
+		return 1, sql.ErrDatabaseNotFound.New(dbName)
+	}
+	uncommittedChanges, _, _, err := actions.RootHasUncommittedChanges(roots)
+	if err != nil {
+		return 1, err
+	}
+	if uncommittedChanges && !apr.Contains(cli.ForceFlag) && !apr.Contains(cli.StashFlag) {
+		return 1, fmt.Errorf("cannot sync with uncommitted changes; use --%s to discard them or --%s to stash them", cli.ForceFlag, cli.StashFlag)
+	}
+
+	srcDB, err := sess.Provider().GetRemoteDB(ctx, dbData.Ddb.ValueReadWriter().Format(), pullSpec.Remote, false)
+	if err != nil {
+		return 1, fmt.Errorf("failed to get remote db; %w", err)
+	}
+	_, hasBranch, err := srcDB.HasBranch(ctx, pullSpec.Branch.GetPath())
+	if err != nil {
+		return 1, err
+	}
+	if !hasBranch {
+		return 1, fmt.Errorf("branch %q not found on remote", pullSpec.Branch.GetPath())
+	}
+
+	tmpDir, err := dbData.Rsw.TempTableFilesDir()
+	if err != nil {
+		return 1, err
+	}
+	srcDBCommit, err := actions.FetchRemoteBranch(ctx, tmpDir, pullSpec.Remote, srcDB, dbData.Ddb, pullSpec.Branch, runProgFuncs, stopProgFuncs)
+	if err != nil {
+		return 1, err
+	}
+	h, err := srcDBCommit.HashOf()
+	if err != nil {
+		return 1, err
+	}
+
+	// the remote tracking branch is overwritten, like the local branch, even when it has diverged
+	for _, refSpec := range pullSpec.RefSpecs {
+		if remoteTrackRef := refSpec.DestRef(pullSpec.Branch); remoteTrackRef != nil {
+			err = dbData.Ddb.SetHead(ctx, remoteTrackRef, h)
+			if err != nil {
+				return 1, err
+			}
+		}
+	}
+
+	if uncommittedChanges && apr.Contains(cli.StashFlag) {
+		roots, err = stashAllChanges(ctx, dbData, roots)
+		if err != nil {
+			return 1, err
+		}
+	}
+
+	newHead, roots, err := actions.ResetHardTables(ctx, dbData, h.String(), roots)
+	if err != nil {
+		return 1, err
+	}
+
+	// TODO: this overrides the transaction setting, needs to happen at commit, not here
+	headRef, err := dbData.Rsr.CWBHeadRef()
+	if err != nil {
+		return 1, err
+	}
+	if err := dbData.Ddb.SetHeadToCommit(ctx, headRef, newHead); err != nil {
+		return 1, err
+	}
+
+	ws, err := sess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return 1, err
+	}
+	err = sess.SetWorkingSet(ctx, dbName, ws.WithWorkingRoot(roots.Working).WithStagedRoot(roots.Staged).ClearMerge())
+	if err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// stashAllChanges adds a stash of every uncommitted change in |roots|, including untracked tables, like
+// `dolt stash --include-untracked` does, and returns the roots without them.
+func stashAllChanges(ctx *sql.Context, dbData env.DbData, roots doltdb.Roots) (doltdb.Roots, error) {
+	roots, err := actions.StageModifiedAndDeletedTables(ctx, roots)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+
+	// tables added in the staged root are staged again when the stash is popped, but untracked tables aren't
+	var addedTblsToStage []string
+	staged, _, err := diff.GetStagedUnstagedTableDeltas(ctx, roots)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	for _, tableDelta := range staged {
+		if tableDelta.IsAdd() {
+			addedTblsToStage = append(addedTblsToStage, tableDelta.ToName)
+		}
+	}
+
+	roots, err = actions.StageAllTables(ctx, roots, true)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+
+	headRef, err := dbData.Rsr.CWBHeadRef()
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	commitSpec, err := doltdb.NewCommitSpec(headRef.String())
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	commit, err := dbData.Ddb.Resolve(ctx, commitSpec, headRef)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+	commitMeta, err := commit.GetCommitMeta(ctx)
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+
+	err = dbData.Ddb.AddStash(ctx, commit, roots.Staged, datas.NewStashMeta(headRef.String(), commitMeta.Description, addedTblsToStage))
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+
+	roots.Working = roots.Head
+	roots.Staged = roots.Head
+	return roots, nil
+}
@@ -20,6 +20,8 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
@@ -61,6 +63,10 @@ func doDoltTag(ctx *sql.Context, args []string) (int, error) {
 		if apr.Contains(cli.MessageArg) {
 			return 1, fmt.Errorf("delete and tag message options are incompatible")
 		}
+		err = checkProtectedTags(ctx, apr.Contains(cli.ForceFlag), apr.Args)
+		if err != nil {
+			return 1, err
+		}
 		err = actions.DeleteTagsOnDB(ctx, dbData.Ddb, apr.Args...)
 		if err != nil {
 			return 1, err
@@ -91,6 +97,9 @@ func doDoltTag(ctx *sql.Context, args []string) (int, error) {
 		TaggerEmail: email,
 		Description: msg,
 	}
+	if apr.Contains(cli.SignFlag) {
+		props.Signer = configuredSigner(ctx)
+	}
 
 	tagName := apr.Arg(0)
 	startPoint := "head"
@@ -108,3 +117,21 @@ func doDoltTag(ctx *sql.Context, args []string) (int, error) {
 
 	return 0, nil
 }
+
+// checkProtectedTags returns an error if any of the tags named are protected by @@dolt_protected_tags. Protected tags
+// can only be deleted with |force|, and only by users with the SUPER privilege.
+func checkProtectedTags(ctx *sql.Context, force bool, tagNames []string) error {
+	patterns := dsess.ProtectedTagPatterns()
+	for _, tagName := range tagNames {
+		if !doltdb.IsProtectedTag(patterns, tagName) {
+			continue
+		}
+		if !force {
+			return fmt.Errorf("%w: cannot delete tag '%s'", doltdb.ErrProtectedTag, tagName)
+		}
+		if !branch_control.HasSuperPrivilege(ctx) {
+			return fmt.Errorf("%w: deleting tag '%s' requires the SUPER privilege", doltdb.ErrProtectedTag, tagName)
+		}
+	}
+	return nil
+}
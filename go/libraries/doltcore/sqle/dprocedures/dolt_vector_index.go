@@ -0,0 +1,349 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/vector"
+)
+
+// vectorIndexIterations is the number of k-means iterations run to find the centroids of a new vector index.
+const vectorIndexIterations = 10
+
+// doltVectorIndex is the stored procedure dolt_vector_index(), which creates and drops vector indexes on embedding
+// columns. Once created, an index is maintained as rows of the table are written, and is searched with the
+// dolt_vector_search() table function.
+//
+//	dolt_vector_index(<table>, <column>[, <lists>])
+//	dolt_vector_index('-d', <table>, <column>)
+//
+// <lists> is the number of lists the embeddings of the column are clustered into, and defaults to the square root of
+// the number of rows. Creating an index on a column that is already indexed rebuilds its lists.
+func doltVectorIndex(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltVectorIndex(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+func doDoltVectorIndex(ctx *sql.Context, args []string) (int, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 1, fmt.Errorf("Empty database name.")
+	}
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return 1, err
+	}
+
+	apr, err := cli.CreateVectorIndexArgParser().Parse(args)
+	if err != nil {
+		return 1, err
+	}
+	remove := apr.Contains(cli.DeleteFlag)
+	if (remove && apr.NArg() != 2) || (!remove && apr.NArg() != 2 && apr.NArg() != 3) {
+		return 1, InvalidArgErr
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	db, err := dSess.Provider().Database(ctx, dbName)
+	if err != nil {
+		return 1, err
+	}
+
+	tbl, ok, err := db.GetTableInsensitive(ctx, apr.Arg(0))
+	if err != nil {
+		return 1, err
+	} else if !ok {
+		return 1, sql.ErrTableNotFound.New(apr.Arg(0))
+	}
+	if doltdb.HasDoltPrefix(tbl.Name()) {
+		return 1, fmt.Errorf("error: vector indexes are not supported on system tables")
+	}
+	colIdx := tbl.Schema().IndexOfColName(apr.Arg(1))
+	if colIdx < 0 {
+		return 1, sql.ErrTableColumnNotFound.New(tbl.Name(), apr.Arg(1))
+	}
+	tableName, columnName := tbl.Name(), tbl.Schema()[colIdx].Name
+
+	if remove {
+		return 0, dropVectorIndex(ctx, dSess, db, tableName, columnName)
+	}
+
+	lists := 0
+	if apr.NArg() == 3 {
+		lists, err = strconv.Atoi(apr.Arg(2))
+		if err != nil || lists <= 0 {
+			return 1, fmt.Errorf("error: the number of lists must be a positive integer, but got '%s'", apr.Arg(2))
+		}
+	}
+	return 0, createVectorIndex(ctx, dSess, db, tbl, colIdx, lists)
+}
+
+// createVectorIndex creates the vector index on the column |colIdx| of |tbl|, clustering its embeddings into |lists|
+// lists, or into the square root of their number if |lists| is 0.
+func createVectorIndex(ctx *sql.Context, dSess *dsess.DoltSession, db sql.Database, tbl sql.Table, colIdx int, lists int) error {
+	tableName, columnName := tbl.Name(), tbl.Schema()[colIdx].Name
+
+	pkTbl, ok := tbl.(sql.PrimaryKeyTable)
+	if !ok || len(pkTbl.PrimaryKeySchema().PkOrdinals) == 0 {
+		return fmt.Errorf("error: vector indexes are not supported on keyless tables")
+	}
+	pkOrdinals := pkTbl.PrimaryKeySchema().PkOrdinals
+	pkCols := make(sql.Schema, len(pkOrdinals))
+	for i, ord := range pkOrdinals {
+		pkCols[i] = pkTbl.PrimaryKeySchema().Schema[ord]
+		if strings.EqualFold(pkCols[i].Name, vector.ListsColumnColumn) || strings.EqualFold(pkCols[i].Name, vector.ListsListColumn) {
+			return fmt.Errorf("error: vector indexes are not supported on tables with a primary key column named '%s'", pkCols[i].Name)
+		}
+	}
+
+	rows, err := vector.TableRows(ctx, tbl)
+	if err != nil {
+		return err
+	}
+	var embeddings [][]float64
+	var pks []sql.Row
+	for _, r := range rows {
+		if r[colIdx] == nil {
+			continue
+		}
+		embedding, err := vector.ParseEmbedding(ctx, r[colIdx])
+		if err != nil {
+			return err
+		}
+		if len(embeddings) > 0 && len(embedding) != len(embeddings[0]) {
+			return fmt.Errorf("error: cannot index embeddings with %d and %d dimensions", len(embeddings[0]), len(embedding))
+		}
+		pk := make(sql.Row, len(pkOrdinals))
+		for i, ord := range pkOrdinals {
+			pk[i] = r[ord]
+		}
+		embeddings = append(embeddings, embedding)
+		pks = append(pks, pk)
+	}
+	if len(embeddings) == 0 {
+		return fmt.Errorf("error: column '%s' of table '%s' has no embeddings to index", columnName, tableName)
+	}
+
+	if lists == 0 {
+		lists = int(math.Ceil(math.Sqrt(float64(len(embeddings)))))
+	}
+	idx := vector.Index{Table: tableName, Column: columnName, Centroids: vector.KMeans(embeddings, lists, vectorIndexIterations)}
+
+	listsTableName := doltdb.VectorIndexListsTableName(tableName)
+	err = createVectorIndexTables(ctx, dSess, db.Name(), map[string]sql.PrimaryKeySchema{
+		doltdb.VectorIndexesTableName: vector.IndexesSchema,
+		listsTableName:                vector.ListsSchema(tableName, pkCols),
+	})
+	if err != nil {
+		return err
+	}
+
+	// an existing index on the column is replaced
+	indexesTable, listsTable, err := vectorIndexTables(ctx, db, tableName)
+	if err != nil {
+		return err
+	}
+	if err = deleteVectorIndexRows(ctx, indexesTable, tableName, columnName, 0, 1); err != nil {
+		return err
+	}
+	if err = deleteVectorIndexRows(ctx, listsTable, "", columnName, -1, 0); err != nil {
+		return err
+	}
+
+	var indexesRows []sql.Row
+	for i, c := range idx.Centroids {
+		indexesRows = append(indexesRows, sql.Row{tableName, columnName, int32(i), vector.CentroidJSON(c)})
+	}
+	if err = insertVectorIndexRows(ctx, indexesTable, indexesRows); err != nil {
+		return err
+	}
+
+	listsRows := make([]sql.Row, len(embeddings))
+	for i, embedding := range embeddings {
+		list, err := vector.NearestCentroid(idx.Centroids, embedding)
+		if err != nil {
+			return err
+		}
+		listsRows[i] = idx.ListsRow(list, pks[i])
+	}
+	return insertVectorIndexRows(ctx, listsTable, listsRows)
+}
+
+// dropVectorIndex drops the vector index on the column |columnName| of the table |tableName|. The pseudo-index tables
+// of the index are dropped once they no longer hold any index.
+func dropVectorIndex(ctx *sql.Context, dSess *dsess.DoltSession, db sql.Database, tableName, columnName string) error {
+	indexesTable, ok, err := db.GetTableInsensitive(ctx, doltdb.VectorIndexesTableName)
+	if err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("error: column '%s' of table '%s' has no vector index", columnName, tableName)
+	}
+	indexes, err := vector.LoadIndexes(ctx, indexesTable, "")
+	if err != nil {
+		return err
+	}
+
+	found, tableIndexes := false, 0
+	for _, idx := range indexes {
+		if strings.EqualFold(idx.Table, tableName) {
+			tableIndexes++
+			found = found || strings.EqualFold(idx.Column, columnName)
+		}
+	}
+	if !found {
+		return fmt.Errorf("error: column '%s' of table '%s' has no vector index", columnName, tableName)
+	}
+
+	if len(indexes) == 1 {
+		return dropVectorIndexTables(ctx, dSess, db.Name(), doltdb.VectorIndexesTableName, doltdb.VectorIndexListsTableName(tableName))
+	} else if tableIndexes == 1 {
+		if err = dropVectorIndexTables(ctx, dSess, db.Name(), doltdb.VectorIndexListsTableName(tableName)); err != nil {
+			return err
+		}
+		indexesTable, ok, err = db.GetTableInsensitive(ctx, doltdb.VectorIndexesTableName)
+		if err != nil {
+			return err
+		} else if !ok {
+			return sql.ErrTableNotFound.New(doltdb.VectorIndexesTableName)
+		}
+		return deleteVectorIndexRows(ctx, indexesTable, tableName, columnName, 0, 1)
+	}
+
+	indexesTable, listsTable, err := vectorIndexTables(ctx, db, tableName)
+	if err != nil {
+		return err
+	}
+	if err = deleteVectorIndexRows(ctx, indexesTable, tableName, columnName, 0, 1); err != nil {
+		return err
+	}
+	return deleteVectorIndexRows(ctx, listsTable, "", columnName, -1, 0)
+}
+
+// createVectorIndexTables creates the pseudo-index tables in |schemas| that don't already exist in the working set.
+func createVectorIndexTables(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, schemas map[string]sql.PrimaryKeySchema) error {
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	root := roots.Working
+	for name, pkSch := range schemas {
+		if exists, err := root.HasTable(ctx, name); err != nil {
+			return err
+		} else if exists {
+			continue
+		}
+		doltSch, err := sqlutil.ToDoltSchema(ctx, root, name, pkSch, roots.Head, sql.Collation_Default)
+		if err != nil {
+			return err
+		}
+		root, err = root.CreateEmptyTable(ctx, name, doltSch)
+		if err != nil {
+			return err
+		}
+	}
+	return dSess.SetRoot(ctx, dbName, root)
+}
+
+// dropVectorIndexTables drops the pseudo-index tables given from the working set.
+func dropVectorIndexTables(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, names ...string) error {
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+	root, err := roots.Working.RemoveTables(ctx, false, false, names...)
+	if err != nil {
+		return err
+	}
+	return dSess.SetRoot(ctx, dbName, root)
+}
+
+// vectorIndexTables returns the dolt_vector_indexes table and the lists table of |tableName|.
+func vectorIndexTables(ctx *sql.Context, db sql.Database, tableName string) (sql.Table, sql.Table, error) {
+	indexesTable, ok, err := db.GetTableInsensitive(ctx, doltdb.VectorIndexesTableName)
+	if err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, sql.ErrTableNotFound.New(doltdb.VectorIndexesTableName)
+	}
+	listsTableName := doltdb.VectorIndexListsTableName(tableName)
+	listsTable, ok, err := db.GetTableInsensitive(ctx, listsTableName)
+	if err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, sql.ErrTableNotFound.New(listsTableName)
+	}
+	return indexesTable, listsTable, nil
+}
+
+// deleteVectorIndexRows deletes the rows of the pseudo-index table |tbl| that belong to the index on |columnName|
+// of |tableName|. |tableCol| and |columnCol| are the positions of the columns holding the table and column names;
+// |tableCol| is -1 for lists tables, which only hold the indexes of a single table.
+func deleteVectorIndexRows(ctx *sql.Context, tbl sql.Table, tableName, columnName string, tableCol, columnCol int) error {
+	rows, err := vector.TableRows(ctx, tbl)
+	if err != nil {
+		return err
+	}
+
+	deleter := tbl.(sql.DeletableTable).Deleter(ctx)
+	deleter.StatementBegin(ctx)
+	for _, r := range rows {
+		if tableCol >= 0 && !strings.EqualFold(r[tableCol].(string), tableName) {
+			continue
+		}
+		if !strings.EqualFold(r[columnCol].(string), columnName) {
+			continue
+		}
+		if err = deleter.Delete(ctx, r); err != nil {
+			_ = deleter.DiscardChanges(ctx, err)
+			_ = deleter.Close(ctx)
+			return err
+		}
+	}
+	if err = deleter.StatementComplete(ctx); err != nil {
+		return err
+	}
+	return deleter.Close(ctx)
+}
+
+// insertVectorIndexRows inserts |rows| into the pseudo-index table |tbl|.
+func insertVectorIndexRows(ctx *sql.Context, tbl sql.Table, rows []sql.Row) error {
+	inserter := tbl.(sql.InsertableTable).Inserter(ctx)
+	inserter.StatementBegin(ctx)
+	for _, r := range rows {
+		if err := inserter.Insert(ctx, r); err != nil {
+			_ = inserter.DiscardChanges(ctx, err)
+			_ = inserter.Close(ctx)
+			return err
+		}
+	}
+	if err := inserter.StatementComplete(ctx); err != nil {
+		return err
+	}
+	return inserter.Close(ctx)
+}
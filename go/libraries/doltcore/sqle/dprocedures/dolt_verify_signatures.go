@@ -0,0 +1,191 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/signing"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// ErrNoSignature is returned by dolt_verify_signatures() for a commit or tag that isn't signed.
+var ErrNoSignature = errors.New("no signature")
+
+// ErrBadSignature is returned by dolt_verify_signatures() for a commit or tag whose signature isn't a good signature by
+// a trusted key.
+var ErrBadSignature = errors.New("bad signature")
+
+var doltVerifySignaturesSchema = stringSchema("ref", "signer", "message")
+
+// doltVerifySignatures is the stored procedure that verifies the signatures of commits or tags, so that unsigned
+// history can be rejected before it's merged, e.g. with
+//
+//	CALL dolt_verify_signatures('main..feature');
+//	CALL dolt_merge('feature');
+//
+// It returns a row for each signature verified, and fails on the first commit or tag that isn't signed, or whose
+// signature isn't good.
+func doltVerifySignatures(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	rows, err := doDoltVerifySignatures(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+func doDoltVerifySignatures(ctx *sql.Context, args []string) ([]sql.Row, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return nil, fmt.Errorf("Empty database name.")
+	}
+	dSess := dsess.DSessFromSess(ctx.Session)
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return nil, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	apr, err := cli.CreateVerifySignaturesArgParser().Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	cfg := dSess.SigningConfig()
+
+	if apr.Contains(cli.TagsFlag) {
+		if apr.NArg() == 0 {
+			return nil, fmt.Errorf("error: no tags specified")
+		}
+		var rows []sql.Row
+		for _, name := range apr.Args {
+			tag, err := dbData.Ddb.ResolveTag(ctx, ref.NewTagRef(name))
+			if err != nil {
+				return nil, err
+			}
+			payload, sig := tag.GetSignature()
+			row, err := verifySignature(ctx, cfg, "tag "+name, name, payload, sig)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	}
+
+	revisions := apr.Args
+	if len(revisions) == 0 {
+		revisions = []string{"HEAD"}
+	}
+	headRef, err := dbData.Rsr.CWBHeadRef()
+	if err != nil {
+		return nil, err
+	}
+	resolve := func(rev string) (*doltdb.Commit, error) {
+		cs, err := doltdb.NewCommitSpec(rev)
+		if err != nil {
+			return nil, err
+		}
+		return dbData.Ddb.Resolve(ctx, cs, headRef)
+	}
+
+	var rows []sql.Row
+	for _, rev := range revisions {
+		var commits []*doltdb.Commit
+		if strings.Contains(rev, "..") {
+			from, to, _ := strings.Cut(rev, "..")
+			if strings.HasPrefix(to, ".") || from == "" || to == "" {
+				return nil, fmt.Errorf("error: invalid revision range '%s', expected <from>..<to>", rev)
+			}
+			fromCm, err := resolve(from)
+			if err != nil {
+				return nil, err
+			}
+			toCm, err := resolve(to)
+			if err != nil {
+				return nil, err
+			}
+			fromHash, err := fromCm.HashOf()
+			if err != nil {
+				return nil, err
+			}
+			toHash, err := toCm.HashOf()
+			if err != nil {
+				return nil, err
+			}
+			commits, err = commitwalk.GetDotDotRevisions(ctx, dbData.Ddb, []hash.Hash{toHash}, dbData.Ddb, []hash.Hash{fromHash}, -1)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			cm, err := resolve(rev)
+			if err != nil {
+				return nil, err
+			}
+			commits = []*doltdb.Commit{cm}
+		}
+
+		for _, cm := range commits {
+			h, err := cm.HashOf()
+			if err != nil {
+				return nil, err
+			}
+			payload, sig, err := cm.GetSignature()
+			if err != nil {
+				return nil, err
+			}
+			row, err := verifySignature(ctx, cfg, "commit "+h.String(), h.String(), payload, sig)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// verifySignature verifies that |sig| is a good signature of |payload|, the signing payload of the commit or tag
+// described by |desc|, and returns its result row.
+func verifySignature(ctx context.Context, cfg signing.Config, desc, refStr string, payload []byte, sig string) (sql.Row, error) {
+	if sig == "" {
+		return nil, fmt.Errorf("%w for %s", ErrNoSignature, desc)
+	}
+	ver, err := signing.Verify(ctx, cfg, payload, sig)
+	if err != nil {
+		return nil, err
+	}
+	output := strings.TrimSpace(ver.Output)
+	if !ver.Good {
+		return nil, fmt.Errorf("%w for %s:\n%s", ErrBadSignature, desc, output)
+	}
+	return sql.Row{refStr, ver.Signer, output}, nil
+}
+
+// configuredSigner returns a signer that signs with the key configured for the session.
+func configuredSigner(ctx *sql.Context) datas.Signer {
+	cfg := dsess.DSessFromSess(ctx.Session).SigningConfig()
+	return func(ctx context.Context, payload []byte) (string, error) {
+		return signing.Sign(ctx, cfg, payload)
+	}
+}
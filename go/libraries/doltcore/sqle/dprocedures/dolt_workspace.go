@@ -0,0 +1,391 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqlserver"
+)
+
+var (
+	ErrAlreadyInWorkspace = errors.New("error: session is already in a workspace; publish or discard it first")
+	ErrNotInWorkspace     = errors.New("error: session is not in a workspace")
+	ErrWorkspaceDirty     = errors.New("error: workspace has uncommitted changes; commit them with dolt_commit() or " +
+		"discard the workspace")
+)
+
+// doltWorkspaceBegin is the stored procedure dolt_workspace_begin(). It creates an ephemeral workspace branched from
+// the session's current head and switches the session to it. Changes made in a workspace are only visible to the
+// session that opened it until the workspace is published as a branch with dolt_workspace_publish().
+func doltWorkspaceBegin(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	name, err := doDoltWorkspaceBegin(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(name), nil
+}
+
+func doDoltWorkspaceBegin(ctx *sql.Context, args []string) (string, error) {
+	if len(args) != 0 {
+		return "", InvalidArgErr
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return "", fmt.Errorf("Empty database name.")
+	}
+	baseName, _ := dsess.SplitRevisionDbName(dbName)
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	if headRef.GetType() == ref.WorkspaceRefType {
+		return "", ErrAlreadyInWorkspace
+	}
+
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return "", fmt.Errorf("Could not load database %s", dbName)
+	}
+	ddb := dbData.Ddb
+
+	err = ReapOrphanedWorkspaces(ctx, baseName, ddb)
+	if err != nil {
+		return "", err
+	}
+
+	var wsName string
+	for n := 1; ; n++ {
+		wsName = dsess.WorkspaceName(ctx.Session.ID(), n)
+		exists, err := ddb.HasRef(ctx, ref.NewWorkspaceRef(wsName))
+		if err != nil {
+			return "", err
+		}
+		isBranch, err := actions.IsBranch(ctx, ddb, wsName)
+		if err != nil {
+			return "", err
+		}
+		if !exists && !isBranch {
+			break
+		}
+	}
+
+	// Claim the workspace before creating it, so that it's never mistaken for an orphan and reaped
+	dSess.AddWorkspace(baseName, wsName, headRef.GetPath())
+
+	wsHeadRef := ref.NewWorkspaceRef(wsName)
+	err = ddb.NewWorkspaceAtCommit(ctx, wsHeadRef, headCommit)
+	if err != nil {
+		dSess.RemoveWorkspace(baseName, wsName)
+		return "", err
+	}
+	err = createWorkingSetForHead(ctx, ddb, wsHeadRef)
+	if err != nil {
+		_ = deleteWorkspace(ctx, dSess, baseName, ddb, wsName)
+		return "", err
+	}
+
+	// The workspace ref isn't visible to the current transaction until we start a new one
+	err = commitTransaction(ctx, dSess, nil)
+	if err != nil {
+		return "", err
+	}
+
+	wsRef, err := ref.WorkingSetRefForHead(wsHeadRef)
+	if err != nil {
+		return "", err
+	}
+	err = dSess.SwitchWorkingSet(ctx, baseName, wsRef)
+	if err != nil {
+		return "", err
+	}
+
+	return wsName, nil
+}
+
+// doltWorkspacePublish is the stored procedure dolt_workspace_publish(). It creates a new branch at the head of the
+// session's workspace, switches the session to that branch, and removes the workspace. Changes in the workspace must be
+// committed before it can be published.
+func doltWorkspacePublish(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltWorkspacePublish(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+func doDoltWorkspacePublish(ctx *sql.Context, args []string) (int, error) {
+	if len(args) != 1 {
+		return 1, InvalidArgErr
+	}
+	branchName := args[0]
+	if len(branchName) == 0 {
+		return 1, EmptyBranchNameErr
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 1, fmt.Errorf("Empty database name.")
+	}
+	baseName, _ := dsess.SplitRevisionDbName(dbName)
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return 1, err
+	}
+	if headRef.GetType() != ref.WorkspaceRefType {
+		return 1, ErrNotInWorkspace
+	}
+
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return 1, fmt.Errorf("Could not load database %s", dbName)
+	}
+	dirty, err := rootsAreDirty(roots)
+	if err != nil {
+		return 1, err
+	}
+	if dirty {
+		return 1, ErrWorkspaceDirty
+	}
+
+	if err = branch_control.CanCreateBranch(ctx, branchName); err != nil {
+		return 1, err
+	}
+
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return 1, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	var rsc doltdb.ReplicationStatusController
+	err = actions.CreateBranchWithStartPt(ctx, dbData, branchName, "HEAD", false, &rsc)
+	if err != nil {
+		return 1, err
+	}
+	err = branch_control.AddAdminForContext(ctx, branchName)
+	if err != nil {
+		return 1, err
+	}
+
+	// The new branch isn't visible to the current transaction until we start a new one
+	err = commitTransaction(ctx, dSess, &rsc)
+	if err != nil {
+		return 1, err
+	}
+
+	wsRef, err := ref.WorkingSetRefForHead(ref.NewBranchRef(branchName))
+	if err != nil {
+		return 1, err
+	}
+	err = dSess.SwitchWorkingSet(ctx, baseName, wsRef)
+	if err != nil {
+		return 1, err
+	}
+
+	err = deleteWorkspace(ctx, dSess, baseName, dbData.Ddb, headRef.GetPath())
+	if err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// doltWorkspaceDiscard is the stored procedure dolt_workspace_discard(). It throws away the session's workspace,
+// along with any changes made in it, and switches the session back to the head the workspace was branched from.
+func doltWorkspaceDiscard(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltWorkspaceDiscard(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+func doDoltWorkspaceDiscard(ctx *sql.Context, args []string) (int, error) {
+	if len(args) != 0 {
+		return 1, InvalidArgErr
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 1, fmt.Errorf("Empty database name.")
+	}
+	baseName, _ := dsess.SplitRevisionDbName(dbName)
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return 1, err
+	}
+	if headRef.GetType() != ref.WorkspaceRefType {
+		return 1, ErrNotInWorkspace
+	}
+
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return 1, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	// Throw away any uncommitted changes in the workspace before switching away from it
+	err = dSess.Rollback(ctx, ctx.GetTransaction())
+	if err != nil {
+		return 1, err
+	}
+	newTx, err := dSess.StartTransaction(ctx, sql.ReadWrite)
+	if err != nil {
+		return 1, err
+	}
+	ctx.SetTransaction(newTx)
+
+	origin, ok := dSess.RemoveWorkspace(baseName, headRef.GetPath())
+	err = deleteWorkspace(ctx, dSess, baseName, dbData.Ddb, headRef.GetPath())
+	if err != nil {
+		return 1, err
+	}
+
+	if !ok {
+		// We don't know where this workspace came from, so return to the default branch
+		return 0, dSess.RemoveBranchState(ctx, baseName, headRef.GetPath())
+	}
+
+	wsRef, err := ref.WorkingSetRefForHead(ref.NewBranchRef(origin))
+	if err != nil {
+		return 1, err
+	}
+	err = dSess.SwitchWorkingSet(ctx, baseName, wsRef)
+	if err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// deleteWorkspace removes the workspace named and its working set from the database given, and forgets that the
+// session opened it.
+func deleteWorkspace(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, ddb *doltdb.DoltDB, wsName string) error {
+	wsHeadRef := ref.NewWorkspaceRef(wsName)
+	wsRef, err := ref.WorkingSetRefForHead(wsHeadRef)
+	if err != nil {
+		return err
+	}
+
+	err = ddb.DeleteWorkingSet(ctx, wsRef)
+	if err != nil && !errors.Is(err, doltdb.ErrWorkingSetNotFound) {
+		return err
+	}
+	err = ddb.DeleteWorkspace(ctx, wsHeadRef)
+	if err != nil && !errors.Is(err, doltdb.ErrWorkspaceNotFound) {
+		return err
+	}
+
+	dSess.RemoveWorkspace(dbName, wsName)
+	return nil
+}
+
+// ReapOrphanedWorkspaces deletes the workspaces of the database named that aren't owned by any connected session, such
+// as those left behind by sessions that disconnected without publishing or discarding them, or by a previous run of
+// the server. Workspaces are only reaped when running a sql-server, since that's the only context in which we can tell
+// which sessions are still connected.
+func ReapOrphanedWorkspaces(ctx *sql.Context, dbName string, ddb *doltdb.DoltDB) error {
+	if !sqlserver.RunningInServerMode() {
+		return nil
+	}
+	runningServer, _ := sqlserver.GetRunningServer()
+	if runningServer == nil {
+		return nil
+	}
+
+	live := make(map[string]struct{})
+	if dSess, ok := ctx.Session.(*dsess.DoltSession); ok {
+		for _, name := range dSess.Workspaces(dbName) {
+			live[name] = struct{}{}
+		}
+	}
+	err := runningServer.SessionManager().Iter(func(session sql.Session) (bool, error) {
+		dSess, ok := session.(*dsess.DoltSession)
+		if !ok {
+			return false, nil
+		}
+		for _, name := range dSess.Workspaces(dbName) {
+			live[name] = struct{}{}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	workspaces, err := ddb.GetWorkspaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, wsHeadRef := range workspaces {
+		if _, ok := live[strings.ToLower(wsHeadRef.GetPath())]; ok {
+			continue
+		}
+
+		wsRef, err := ref.WorkingSetRefForHead(wsHeadRef)
+		if err != nil {
+			return err
+		}
+		err = ddb.DeleteWorkingSet(ctx, wsRef)
+		if err != nil && !errors.Is(err, doltdb.ErrWorkingSetNotFound) {
+			return err
+		}
+		err = ddb.DeleteWorkspace(ctx, wsHeadRef)
+		if err != nil && !errors.Is(err, doltdb.ErrWorkspaceNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rootsAreDirty returns whether the working or staged roots given differ from the head root.
+func rootsAreDirty(roots doltdb.Roots) (bool, error) {
+	headHash, err := roots.Head.HashOf()
+	if err != nil {
+		return false, err
+	}
+	workingHash, err := roots.Working.HashOf()
+	if err != nil {
+		return false, err
+	}
+	stagedHash, err := roots.Staged.HashOf()
+	if err != nil {
+		return false, err
+	}
+
+	return headHash != workingHash || headHash != stagedHash, nil
+}
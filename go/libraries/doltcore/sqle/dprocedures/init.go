@@ -21,7 +21,9 @@ import (
 
 var DoltProcedures = []sql.ExternalStoredProcedureDetails{
 	{Name: "dolt_add", Schema: int64Schema("status"), Function: doltAdd},
+	{Name: "dolt_apply_patch", Schema: int64Schema("applied", "rejected"), Function: doltApplyPatch},
 	{Name: "dolt_backup", Schema: int64Schema("status"), Function: doltBackup, ReadOnly: true},
+	{Name: "dolt_bisect", Schema: doltBisectSchema, Function: doltBisect},
 	{Name: "dolt_branch", Schema: int64Schema("status"), Function: doltBranch},
 	{Name: "dolt_checkout", Schema: doltCheckoutSchema, Function: doltCheckout, ReadOnly: true},
 	{Name: "dolt_cherry_pick", Schema: cherryPickSchema, Function: doltCherryPick},
@@ -29,21 +31,35 @@ var DoltProcedures = []sql.ExternalStoredProcedureDetails{
 	{Name: "dolt_clone", Schema: int64Schema("status"), Function: doltClone},
 	{Name: "dolt_commit", Schema: stringSchema("hash"), Function: doltCommit},
 	{Name: "dolt_commit_hash_out", Schema: stringSchema("hash"), Function: doltCommitHashOut},
+	{Name: "dolt_copy_table", Schema: int64Schema("status"), Function: doltCopyTable},
 	{Name: "dolt_conflicts_resolve", Schema: int64Schema("status"), Function: doltConflictsResolve},
 	{Name: "dolt_count_commits", Schema: int64Schema("ahead", "behind"), Function: doltCountCommits, ReadOnly: true},
+	{Name: "dolt_export_schema", Schema: stringSchema("bundle"), Function: doltExportSchema, ReadOnly: true},
 	{Name: "dolt_fetch", Schema: int64Schema("status"), Function: doltFetch},
 
 	// dolt_gc is enabled behind a feature flag for now, see dolt_gc.go
 	{Name: "dolt_gc", Schema: int64Schema("status"), Function: doltGC, ReadOnly: true},
 
+	{Name: "dolt_import_schema", Schema: int64Schema("created", "altered", "dropped", "unchanged"), Function: doltImportSchema},
+	{Name: "dolt_mask", Schema: int64Schema("status"), Function: doltMask},
 	{Name: "dolt_merge", Schema: doltMergeSchema, Function: doltMerge},
+	{Name: "dolt_proposal", Schema: int64Schema("id"), Function: doltProposal},
 	{Name: "dolt_pull", Schema: int64Schema("fast_forward", "conflicts"), Function: doltPull},
 	{Name: "dolt_push", Schema: doltPushSchema, Function: doltPush},
+	{Name: "dolt_read_replica_refresh", Schema: int64Schema("status"), Function: doltReadReplicaRefresh, ReadOnly: true},
+	{Name: "dolt_rebase", Schema: doltRebaseSchema, Function: doltRebase},
 	{Name: "dolt_remote", Schema: int64Schema("status"), Function: doltRemote},
 	{Name: "dolt_reset", Schema: int64Schema("status"), Function: doltReset},
 	{Name: "dolt_revert", Schema: int64Schema("status"), Function: doltRevert},
+	{Name: "dolt_set_default_branch", Schema: int64Schema("status"), Function: doltSetDefaultBranch},
+	{Name: "dolt_sync", Schema: int64Schema("status"), Function: doltSync},
 	{Name: "dolt_tag", Schema: int64Schema("status"), Function: doltTag},
+	{Name: "dolt_vector_index", Schema: int64Schema("status"), Function: doltVectorIndex},
 	{Name: "dolt_verify_constraints", Schema: int64Schema("violations"), Function: doltVerifyConstraints},
+	{Name: "dolt_verify_signatures", Schema: doltVerifySignaturesSchema, Function: doltVerifySignatures, ReadOnly: true},
+	{Name: "dolt_workspace_begin", Schema: stringSchema("workspace"), Function: doltWorkspaceBegin},
+	{Name: "dolt_workspace_discard", Schema: int64Schema("status"), Function: doltWorkspaceDiscard},
+	{Name: "dolt_workspace_publish", Schema: int64Schema("status"), Function: doltWorkspacePublish},
 
 	// Dolt stored procedure aliases
 	// TODO: Add new procedure aliases in doltProcedureAliasSet in go-mysql-server/sql/information_schema/routines.go file
@@ -0,0 +1,243 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+var ErrMaskedTableWrite = errors.NewKind("table %s has masked columns, and can only be updated, deleted from or altered by users with the SUPER privilege")
+
+var ErrMaskedColumnLookup = errors.NewKind("column %s of table %s is masked, and can only be filtered, joined or searched on by users with the SUPER privilege")
+
+// redactedValue is what a redacted string value is shown as.
+const redactedValue = "****"
+
+// CanUnmask returns whether the current user can see the values of masked columns. Only users with the SUPER
+// privilege can, as can every user when privileges aren't being checked.
+func CanUnmask(ctx *sql.Context) bool {
+	privs, counter := ctx.Session.GetPrivilegeSet()
+	if counter == 0 || privs == nil {
+		return true
+	}
+	return privs.Has(sql.PrivilegeType_Super)
+}
+
+// CheckMaskedTableWrite returns an error if |tableName|, with the schema |sch|, has masked columns that the current
+// user can't see. Since such a user reads masked values from the table, any statement that writes back the rows it
+// read, such as an UPDATE or a schema change that rewrites the table, would replace the real values with masked ones.
+func CheckMaskedTableWrite(ctx *sql.Context, ddb *doltdb.DoltDB, tableName string, sch schema.Schema) error {
+	cols, err := MaskedColumns(ctx, ddb, sch)
+	if err != nil {
+		return err
+	}
+	if len(cols) > 0 {
+		return ErrMaskedTableWrite.New(tableName)
+	}
+	return nil
+}
+
+// MaskedColumns returns the names of the columns of |sch| that are masked from the current user, lowercased.
+func MaskedColumns(ctx *sql.Context, ddb *doltdb.DoltDB, sch schema.Schema) (map[string]struct{}, error) {
+	if ddb == nil || sch == nil || CanUnmask(ctx) {
+		return nil, nil
+	}
+	masks, err := ddb.GetColumnMasks(ctx)
+	if err != nil || len(masks.Masks) == 0 {
+		return nil, err
+	}
+
+	var cols map[string]struct{}
+	for _, col := range sch.GetAllCols().GetColumns() {
+		if _, ok := masks.MaskKind(col.Tag); ok {
+			if cols == nil {
+				cols = make(map[string]struct{})
+			}
+			cols[strings.ToLower(col.Name)] = struct{}{}
+		}
+	}
+	return cols, nil
+}
+
+// CheckMaskedColumnLookup returns an error if any of |columns| of |tableName|, with the schema |sch|, is masked from
+// the current user. Index lookups, and the filters and joins they're made for, compare the real values of the
+// columns, so a user could find masked values by guessing them.
+func CheckMaskedColumnLookup(ctx *sql.Context, ddb *doltdb.DoltDB, tableName string, sch schema.Schema, columns []string) error {
+	masked, err := MaskedColumns(ctx, ddb, sch)
+	if err != nil || len(masked) == 0 {
+		return err
+	}
+	for _, col := range columns {
+		if _, ok := masked[strings.ToLower(col)]; ok {
+			return ErrMaskedColumnLookup.New(col, tableName)
+		}
+	}
+	return nil
+}
+
+// ColumnMasker masks the values of masked columns in rows read from a table. A nil *ColumnMasker masks nothing.
+type ColumnMasker struct {
+	kinds []doltdb.MaskKind
+	sch   sql.Schema
+	key   []byte
+}
+
+// ColumnTag returns the tag of the table column that the values of the column |col| of a schema are read from, or
+// false if they aren't read from a table column.
+type ColumnTag func(col string) (uint64, bool)
+
+// TableColumnTag returns a ColumnTag for schemas whose columns are the columns of |sch| of the same name.
+func TableColumnTag(sch schema.Schema) ColumnTag {
+	return func(col string) (uint64, bool) {
+		c, ok := sch.GetAllCols().GetByNameCaseInsensitive(col)
+		return c.Tag, ok
+	}
+}
+
+// DiffTableColumnTag returns a ColumnTag for the schemas of the diff tables, which hold the values of a column |c| in
+// columns to_c and from_c, read from the columns c of |toSch| and |fromSch|.
+func DiffTableColumnTag(fromSch, toSch schema.Schema) ColumnTag {
+	return func(col string) (uint64, bool) {
+		lower := strings.ToLower(col)
+		switch {
+		case strings.HasPrefix(lower, "to_") && toSch != nil:
+			return TableColumnTag(toSch)(col[len("to_"):])
+		case strings.HasPrefix(lower, "from_") && fromSch != nil:
+			return TableColumnTag(fromSch)(col[len("from_"):])
+		default:
+			return 0, false
+		}
+	}
+}
+
+// NewColumnMasker returns a ColumnMasker for rows with the schema |sch|, whose columns are read from the table
+// columns that |columnTag| maps them to. Returns nil if the current user can unmask the table's columns, or none of
+// the columns of |sch| are masked.
+func NewColumnMasker(ctx *sql.Context, ddb *doltdb.DoltDB, sch sql.Schema, columnTag ColumnTag) (*ColumnMasker, error) {
+	if ddb == nil || CanUnmask(ctx) {
+		return nil, nil
+	}
+	masks, err := ddb.GetColumnMasks(ctx)
+	if err != nil || len(masks.Masks) == 0 {
+		return nil, err
+	}
+
+	m := &ColumnMasker{kinds: make([]doltdb.MaskKind, len(sch)), sch: sch, key: masks.Key}
+	masked := false
+	for i, col := range sch {
+		tag, ok := columnTag(col.Name)
+		if !ok {
+			continue
+		}
+		if kind, ok := masks.MaskKind(tag); ok {
+			m.kinds[i] = kind
+			masked = true
+		}
+	}
+	if !masked {
+		return nil, nil
+	}
+	return m, nil
+}
+
+// MaskRow returns a copy of |r| with the values of masked columns masked.
+func (m *ColumnMasker) MaskRow(r sql.Row) sql.Row {
+	if m == nil || r == nil {
+		return r
+	}
+	masked := r.Copy()
+	for i, kind := range m.kinds {
+		if len(kind) > 0 && i < len(masked) {
+			masked[i] = maskValue(kind, m.key, m.sch[i].Type, masked[i])
+		}
+	}
+	return masked
+}
+
+// RowIter returns a sql.RowIter that masks the rows of |iter|.
+func (m *ColumnMasker) RowIter(iter sql.RowIter) sql.RowIter {
+	if m == nil {
+		return iter
+	}
+	return &maskedRowIter{masker: m, iter: iter}
+}
+
+func maskValue(kind doltdb.MaskKind, key []byte, typ sql.Type, v interface{}) interface{} {
+	if v == nil || kind == doltdb.MaskNull {
+		return nil
+	}
+	// only strings can be shown as a redacted or hashed value, since the masked value has to be of the column's type
+	st, ok := typ.(types.StringType)
+	if !ok {
+		return nil
+	}
+
+	var s string
+	switch kind {
+	case doltdb.MaskRedact:
+		s = redactedValue
+	case doltdb.MaskHash:
+		mac := hmac.New(sha256.New, key)
+		switch v := v.(type) {
+		case string:
+			mac.Write([]byte(v))
+		case []byte:
+			mac.Write(v)
+		default:
+			mac.Write([]byte(fmt.Sprint(v)))
+		}
+		s = hex.EncodeToString(mac.Sum(nil))
+	default:
+		return nil
+	}
+
+	if max := st.MaxCharacterLength(); max > 0 && int64(len(s)) > max {
+		s = s[:max]
+	}
+	if types.IsBinaryType(typ) {
+		return []byte(s)
+	}
+	return s
+}
+
+type maskedRowIter struct {
+	masker *ColumnMasker
+	iter   sql.RowIter
+}
+
+var _ sql.RowIter = (*maskedRowIter)(nil)
+
+func (i *maskedRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	r, err := i.iter.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return i.masker.MaskRow(r), nil
+}
+
+func (i *maskedRowIter) Close(ctx *sql.Context) error {
+	return i.iter.Close(ctx)
+}
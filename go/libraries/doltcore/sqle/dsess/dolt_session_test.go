@@ -263,6 +263,10 @@ func (e emptyRevisionDatabaseProvider) DoltDatabases() []SqlDatabase {
 	return nil
 }
 
+func (e emptyRevisionDatabaseProvider) AllDatabasesWithErrors(ctx *sql.Context) ([]sql.Database, error) {
+	return nil, nil
+}
+
 func (e emptyRevisionDatabaseProvider) DbState(ctx *sql.Context, dbName string, defaultBranch string) (InitialDbState, error) {
 	return InitialDbState{}, sql.ErrDatabaseNotFound.New(dbName)
 }
@@ -291,7 +295,7 @@ func (e emptyRevisionDatabaseProvider) FileSystemForDatabase(dbname string) (fil
 	return nil, nil
 }
 
-func (e emptyRevisionDatabaseProvider) CloneDatabaseFromRemote(ctx *sql.Context, dbName, branch, remoteName, remoteUrl string, remoteParams map[string]string) error {
+func (e emptyRevisionDatabaseProvider) CloneDatabaseFromRemote(ctx *sql.Context, dbName, branch, remoteName, remoteUrl string, depth int, remoteParams map[string]string) error {
 	return nil
 }
 
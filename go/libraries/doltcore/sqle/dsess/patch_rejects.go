@@ -0,0 +1,44 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import "strings"
+
+// PatchReject is a hunk of a patch that dolt_apply_patch() could not apply.
+type PatchReject struct {
+	// Hunk is the position of the hunk in the patch, starting at 1
+	Hunk int
+	// TableName is the table the hunk changes, if known
+	TableName string
+	// Patch is the text of the hunk, either a SQL statement or a JSON object
+	Patch string
+	// Reason describes why the hunk was rejected
+	Reason string
+}
+
+// SetPatchRejects records the hunks rejected by the last patch applied to the database given, replacing those of any
+// previous patch.
+func (d *DoltSession) SetPatchRejects(dbName string, rejects []PatchReject) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.patchRejects[strings.ToLower(dbName)] = rejects
+}
+
+// PatchRejects returns the hunks rejected by the last patch applied to the database given in this session.
+func (d *DoltSession) PatchRejects(dbName string) []PatchReject {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.patchRejects[strings.ToLower(dbName)]
+}
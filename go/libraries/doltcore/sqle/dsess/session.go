@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -37,6 +38,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/libraries/utils/signing"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -60,6 +62,20 @@ type DoltSession struct {
 	branchController *branch_control.Controller
 	mu               *sync.Mutex
 	fs               filesys.Filesys
+	// workspaces records the session workspaces opened by this session, keyed by database name and then by workspace
+	// name, with the head each workspace was branched from as the value
+	workspaces map[string]map[string]string
+	// patchRejects records the hunks rejected by the last call to dolt_apply_patch() for each database
+	patchRejects map[string][]PatchReject
+	// movedBranches records the branches that other sessions have renamed out from under this one, keyed by the
+	// lower-case revision-qualified database name of the old branch, with the new branch name as the value. The current
+	// database is moved off of them when this session next begins a transaction.
+	movedBranches map[string]string
+	// listDatabasesErr is the error from the last time this session's statement listed databases and one couldn't be
+	// loaded, since sql.DatabaseProvider.AllDatabases can't return it
+	listDatabasesErr atomic.Pointer[error]
+	// signingConfig configures how commits and tags are signed, and how signatures are verified
+	signingConfig signing.Config
 
 	// If non-nil, this will be returned from ValidateSession.
 	// Used by sqle/cluster to put a session into a terminal err state.
@@ -85,6 +101,8 @@ func DefaultSession(pro DoltDatabaseProvider) *DoltSession {
 		branchController: branch_control.CreateDefaultController(), // Default sessions are fine with the default controller
 		mu:               &sync.Mutex{},
 		fs:               pro.FileSystem(),
+		workspaces:       make(map[string]map[string]string),
+		patchRejects:     make(map[string][]PatchReject),
 	}
 }
 
@@ -111,6 +129,9 @@ func NewDoltSession(
 		branchController: branchController,
 		mu:               &sync.Mutex{},
 		fs:               pro.FileSystem(),
+		workspaces:       make(map[string]map[string]string),
+		patchRejects:     make(map[string][]PatchReject),
+		signingConfig:    env.GetSigningConfig(conf),
 	}
 
 	return sess, nil
@@ -192,6 +213,45 @@ func RevisionDbName(baseName string, rev string) string {
 	return baseName + DbRevisionDelimiter + rev
 }
 
+// alternateRevisionDelimiter caches @@dolt_alternate_revision_delimiter, since database names are split far more often
+// than it's set. It's kept up to date by the system variable's NotifyChanged.
+var alternateRevisionDelimiter atomic.Pointer[string]
+
+// SetAlternateRevisionDelimiter caches the value of @@dolt_alternate_revision_delimiter.
+func SetAlternateRevisionDelimiter(delim string) {
+	alternateRevisionDelimiter.Store(&delim)
+}
+
+// AlternateRevisionDelimiterValue returns the cached value of @@dolt_alternate_revision_delimiter, which is empty
+// when it isn't set.
+func AlternateRevisionDelimiterValue() string {
+	if delim := alternateRevisionDelimiter.Load(); delim != nil {
+		return *delim
+	}
+	return ""
+}
+
+// SplitAlternateRevisionDbName splits the database name given at the first alternate revision delimiter set in
+// @@dolt_alternate_revision_delimiter, for clients that don't allow a '/' in database names. It returns false if the
+// delimiter isn't set or the name doesn't contain it. Callers must check that the base name returned names a
+// database, since a database's own name may contain the delimiter if it was created before the delimiter was set.
+func SplitAlternateRevisionDbName(dbName string) (string, string, bool) {
+	delim := AlternateRevisionDelimiterValue()
+	if delim == "" || strings.Contains(dbName, DbRevisionDelimiter) {
+		return "", "", false
+	}
+
+	// delimiters such as %2F are matched case-insensitively
+	i := strings.Index(strings.ToLower(dbName), strings.ToLower(delim))
+	if i < 0 {
+		return "", "", false
+	}
+	return dbName[:i], dbName[i+len(delim):], true
+}
+
+// SplitRevisionDbName splits the database name given into its base name and revision, which is empty for unqualified
+// names. Names that use the alternate revision delimiter are resolved to names using DbRevisionDelimiter by the
+// database provider, so they aren't split.
 func SplitRevisionDbName(dbName string) (string, string) {
 	var baseName, rev string
 	parts := strings.SplitN(dbName, DbRevisionDelimiter, 2)
@@ -248,7 +308,7 @@ func (d *DoltSession) RemoveBranchState(ctx *sql.Context, dbName string, branchN
 		return sql.ErrDatabaseNotFound.New(baseName)
 	}
 
-	defaultHead, err := DefaultHead(baseName, db)
+	defaultHead, err := DefaultHead(ctx, baseName, db)
 	if err != nil {
 		return err
 	}
@@ -292,6 +352,50 @@ func (d *DoltSession) RenameBranchState(ctx *sql.Context, dbName string, oldBran
 	return nil
 }
 
+// InvalidateMovedBranch discards this session's state for |oldBranchName| in the database named, which another
+// session has renamed to |newBranchName|. If this session has the branch checked out, it's switched to
+// |newBranchName|, and the branch's working set and write session are loaded again the next time they're used. If its
+// current database is the old branch's revision-qualified database, it's switched to the new branch's when this
+// session next begins a transaction. Unlike RenameBranchState, this method does no IO and only changes this session's
+// state under its own lock, so it's safe to call on sessions other than the one executing a query.
+func (d *DoltSession) InvalidateMovedBranch(dbName string, oldBranchName, newBranchName string) {
+	baseName, _ := SplitRevisionDbName(strings.ToLower(dbName))
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if dbState, ok := d.dbStates[baseName]; ok {
+		if strings.EqualFold(dbState.checkedOutRevSpec, oldBranchName) {
+			dbState.checkedOutRevSpec = newBranchName
+		}
+		delete(dbState.heads, strings.ToLower(oldBranchName))
+	}
+	if d.movedBranches == nil {
+		d.movedBranches = make(map[string]string)
+	}
+	d.movedBranches[RevisionDbName(baseName, strings.ToLower(oldBranchName))] = newBranchName
+	d.dbCache.Clear()
+}
+
+// switchOffMovedBranches switches the current database off of a branch that another session renamed, recorded by
+// InvalidateMovedBranch.
+func (d *DoltSession) switchOffMovedBranches(ctx *sql.Context) {
+	d.mu.Lock()
+	moved := d.movedBranches
+	d.movedBranches = nil
+	d.mu.Unlock()
+
+	if len(moved) == 0 {
+		return
+	}
+	currBase, currRev := SplitRevisionDbName(ctx.GetCurrentDatabase())
+	if len(currRev) == 0 {
+		return
+	}
+	if newBranchName, ok := moved[strings.ToLower(RevisionDbName(currBase, currRev))]; ok {
+		ctx.SetCurrentDatabase(RevisionDbName(currBase, newBranchName))
+	}
+}
+
 // SetValidateErr sets an error on this session to be returned from every call
 // to ValidateSession. This is effectively a way to disable a session.
 //
@@ -301,6 +405,33 @@ func (d *DoltSession) SetValidateErr(err error) {
 	d.validateErr = err
 }
 
+// UseDatabase implements sql.Session. The current database is set to the name of |db|, rather than the name used to
+// connect, so that it uses DbRevisionDelimiter even if the client connected with the alternate revision delimiter.
+func (d *DoltSession) UseDatabase(ctx *sql.Context, db sql.Database) error {
+	err := d.Session.UseDatabase(ctx, db)
+	if err != nil {
+		return err
+	}
+	if _, ok := db.(SqlDatabase); ok {
+		d.SetCurrentDatabase(db.Name())
+	}
+	return nil
+}
+
+// SetListDatabasesErr records that listing databases for this session's statement failed with |err|. The statement
+// fails with the error when it finishes; see TakeListDatabasesErr.
+func (d *DoltSession) SetListDatabasesErr(err error) {
+	d.listDatabasesErr.Store(&err)
+}
+
+// TakeListDatabasesErr returns the error recorded by SetListDatabasesErr, if any, and clears it.
+func (d *DoltSession) TakeListDatabasesErr() error {
+	if err := d.listDatabasesErr.Swap(nil); err != nil {
+		return *err
+	}
+	return nil
+}
+
 // ValidateSession validates a working set if there are a valid sessionState with non-nil working set.
 // If there is no sessionState or its current working set not defined, then no need for validation,
 // so no error is returned.
@@ -318,6 +449,7 @@ func (d *DoltSession) StartTransaction(ctx *sql.Context, tCharacteristic sql.Tra
 
 	// New transaction, clear all session state
 	d.clear()
+	d.switchOffMovedBranches(ctx)
 
 	// Take a snapshot of the current noms root for every database under management
 	doltDatabases := d.provider.DoltDatabases()
@@ -1160,7 +1292,7 @@ func (d *DoltSession) addDB(ctx *sql.Context, db SqlDatabase) error {
 
 		// The checkedOutRevSpec should be the checked out branch of the database if available, or the revision
 		// string otherwise
-		sessionState.checkedOutRevSpec, err = DefaultHead(baseName, baseDb)
+		sessionState.checkedOutRevSpec, err = DefaultHead(ctx, baseName, baseDb)
 		if err != nil {
 			return err
 		}
@@ -1228,7 +1360,16 @@ func (d *DoltSession) addDB(ctx *sql.Context, db SqlDatabase) error {
 	return nil
 }
 
+// DatabaseCache returns this session's database cache, sized according to the dolt_revision_db_cache_size session
+// variable.
 func (d *DoltSession) DatabaseCache(ctx *sql.Context) *DatabaseCache {
+	if ctx != nil {
+		if size, err := ctx.GetSessionVariable(ctx, RevisionDbCacheSize); err == nil {
+			if size, ok := size.(int64); ok {
+				d.dbCache.SetMaxRevisionDbs(int(size))
+			}
+		}
+	}
 	return d.dbCache
 }
 
@@ -1261,7 +1402,8 @@ func (d *DoltSession) GetAllTemporaryTables(ctx *sql.Context, db string) ([]sql.
 	return d.tempTables[strings.ToLower(db)], nil
 }
 
-// CWBHeadRef returns the branch ref for this session HEAD for the database named
+// CWBHeadRef returns the branch ref for this session HEAD for the database named. For sessions working in a workspace,
+// this is the workspace ref.
 func (d *DoltSession) CWBHeadRef(ctx *sql.Context, dbName string) (ref.DoltRef, error) {
 	branchState, ok, err := d.lookupDbState(ctx, dbName)
 	if err != nil {
@@ -1271,11 +1413,14 @@ func (d *DoltSession) CWBHeadRef(ctx *sql.Context, dbName string) (ref.DoltRef,
 		return nil, sql.ErrDatabaseNotFound.New(dbName)
 	}
 
-	if branchState.revisionType != RevisionTypeBranch {
+	switch branchState.revisionType {
+	case RevisionTypeBranch:
+		return ref.NewBranchRef(branchState.head), nil
+	case RevisionTypeWorkspace:
+		return ref.NewWorkspaceRef(branchState.head), nil
+	default:
 		return nil, doltdb.ErrOperationNotSupportedInDetachedHead
 	}
-
-	return ref.NewBranchRef(branchState.head), nil
 }
 
 // CurrentHead returns the current head for the db named, which must be unqualified. Used for bootstrap resolving the
@@ -1302,6 +1447,11 @@ func (d *DoltSession) Email() string {
 	return d.email
 }
 
+// SigningConfig returns the configuration of signing commits and tags, and of verifying their signatures.
+func (d *DoltSession) SigningConfig() signing.Config {
+	return d.signingConfig
+}
+
 // setDbSessionVars updates the three session vars that track the value of the session root hashes
 func (d *DoltSession) setDbSessionVars(ctx *sql.Context, state *branchState, force bool) error {
 	// This check is important even when we are forcing an update, because it updates the idea of staleness
@@ -1662,8 +1812,10 @@ func TransactionRoot(ctx *sql.Context, db SqlDatabase) (hash.Hash, error) {
 	return nomsRoot, nil
 }
 
-// DefaultHead returns the head for the database given when one isn't specified
-func DefaultHead(baseName string, db SqlDatabase) (string, error) {
+// DefaultHead returns the head for the database given when one isn't specified. In order of precedence, this is the
+// @@<db>_default_branch system variable, the default branch recorded in the database by dolt_set_default_branch(), and
+// the branch checked out in the database's repo state.
+func DefaultHead(ctx *sql.Context, baseName string, db SqlDatabase) (string, error) {
 	head := ""
 
 	// First check the global variable for the default branch
@@ -1679,6 +1831,25 @@ func DefaultHead(baseName string, db SqlDatabase) (string, error) {
 		}
 	}
 
+	// Then the default branch recorded in the database itself, which is replicated along with its data
+	if head == "" {
+		if ddb := db.DbData().Ddb; ddb != nil {
+			defaultBranch, err := ddb.GetDefaultBranch(ctx)
+			if err != nil {
+				return "", err
+			}
+			if defaultBranch != "" {
+				ok, err := ddb.HasRef(ctx, ref.NewBranchRef(defaultBranch))
+				if err != nil {
+					return "", err
+				}
+				if ok {
+					head = defaultBranch
+				}
+			}
+		}
+	}
+
 	// Fall back to the database's initially checked out branch
 	if head == "" {
 		rsr := db.DbData().Rsr
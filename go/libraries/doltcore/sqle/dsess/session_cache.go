@@ -15,12 +15,14 @@
 package dsess
 
 import (
+	"container/list"
 	"strings"
 	"sync"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/vector"
 )
 
 // SessionCache caches various pieces of expensive to compute information to speed up future lookups in the session.
@@ -28,6 +30,9 @@ type SessionCache struct {
 	indexes map[doltdb.DataCacheKey]map[string][]sql.Index
 	tables  map[doltdb.DataCacheKey]map[string]sql.Table
 	views   map[doltdb.DataCacheKey]map[string]sql.ViewDefinition
+	// vectorIndexes caches the vector indexes of tables by the hash of the dolt_vector_indexes table they're listed in,
+	// so they're only loaded again when an index is changed, rather than on every write to the root
+	vectorIndexes map[doltdb.DataCacheKey]map[string][]vector.Index
 
 	mu sync.RWMutex
 }
@@ -36,8 +41,13 @@ type SessionCache struct {
 // database name to a particular database. This is safe only because the database objects themselves don't have any
 // handles to data or state, but always defer to the session. Keys in the secondary map are revision specifier strings
 type DatabaseCache struct {
-	// revisionDbs caches databases by name. The name is always lower case and revision qualified
-	revisionDbs map[revisionDbCacheKey]SqlDatabase
+	// revisionDbs caches databases by name. The name is always lower case and revision qualified. Its elements are
+	// the revisionDbCacheEntry values in revisionDbLru, which is ordered from most to least recently used.
+	revisionDbs   map[revisionDbCacheKey]*list.Element
+	revisionDbLru *list.List
+	// maxRevisionDbs is the number of revision databases cached before the least recently used is evicted
+	maxRevisionDbs int
+	stats          DatabaseCacheStats
 	// initialDbStates caches the initial state of databases by name for a given noms root, which is the primary key.
 	// The secondary key is the lower-case revision-qualified database name.
 	initialDbStates map[doltdb.DataCacheKey]map[string]InitialDbState
@@ -52,6 +62,20 @@ type revisionDbCacheKey struct {
 	requestedName string
 }
 
+type revisionDbCacheEntry struct {
+	key revisionDbCacheKey
+	db  SqlDatabase
+}
+
+// DatabaseCacheStats are the statistics of a session's revision database cache.
+type DatabaseCacheStats struct {
+	Entries    int
+	MaxEntries int
+	Hits       uint64
+	Misses     uint64
+	Evictions  uint64
+}
+
 type sessionVarCacheKey struct {
 	root doltdb.DataCacheKey
 	head string
@@ -59,13 +83,17 @@ type sessionVarCacheKey struct {
 
 const maxCachedKeys = 64
 
+// DefaultMaxRevisionDbs is the default number of revision databases a session caches
+const DefaultMaxRevisionDbs = 64
+
 func newSessionCache() *SessionCache {
 	return &SessionCache{}
 }
 
 func newDatabaseCache() *DatabaseCache {
 	return &DatabaseCache{
-		sessionVars: make(map[string]sessionVarCacheKey),
+		sessionVars:    make(map[string]sessionVarCacheKey),
+		maxRevisionDbs: DefaultMaxRevisionDbs,
 	}
 }
 
@@ -224,41 +252,124 @@ func (c *SessionCache) GetCachedViewDefinition(key doltdb.DataCacheKey, viewName
 	return table, ok
 }
 
-// GetCachedRevisionDb returns the cached revision database named, and whether the cache was present
-func (c *DatabaseCache) GetCachedRevisionDb(revisionDbName string, requestedName string) (SqlDatabase, bool) {
+// CacheVectorIndexes caches the vector indexes of the table named, for the dolt_vector_indexes table with the key given
+func (c *SessionCache) CacheVectorIndexes(key doltdb.DataCacheKey, tableName string, indexes []vector.Index) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tableName = strings.ToLower(tableName)
+	if c.vectorIndexes == nil {
+		c.vectorIndexes = make(map[doltdb.DataCacheKey]map[string][]vector.Index)
+	}
+	if len(c.vectorIndexes) > maxCachedKeys {
+		for k := range c.vectorIndexes {
+			delete(c.vectorIndexes, k)
+		}
+	}
+
+	indexesForKey, ok := c.vectorIndexes[key]
+	if !ok {
+		indexesForKey = make(map[string][]vector.Index)
+		c.vectorIndexes[key] = indexesForKey
+	}
+
+	indexesForKey[tableName] = indexes
+}
+
+// GetCachedVectorIndexes returns the cached vector indexes of the table named, and whether the cache was present
+func (c *SessionCache) GetCachedVectorIndexes(key doltdb.DataCacheKey, tableName string) ([]vector.Index, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if c.revisionDbs == nil {
+	tableName = strings.ToLower(tableName)
+	if c.vectorIndexes == nil {
 		return nil, false
 	}
 
-	db, ok := c.revisionDbs[revisionDbCacheKey{
-		dbName:        revisionDbName,
+	indexesForKey, ok := c.vectorIndexes[key]
+	if !ok {
+		return nil, false
+	}
+
+	indexes, ok := indexesForKey[tableName]
+	return indexes, ok
+}
+
+// GetCachedRevisionDb returns the cached revision database named, and whether the cache was present. Branch names
+// are case-insensitive, so every spelling of a revision database name finds the same database. The requested name is
+// matched exactly, since it's the name the database is displayed with.
+func (c *DatabaseCache) GetCachedRevisionDb(revisionDbName string, requestedName string) (SqlDatabase, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.revisionDbs[revisionDbCacheKey{
+		dbName:        strings.ToLower(revisionDbName),
 		requestedName: requestedName,
 	}]
-	return db, ok
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.stats.Hits++
+	c.revisionDbLru.MoveToFront(e)
+	return e.Value.(revisionDbCacheEntry).db, true
 }
 
-// CacheRevisionDb caches the revision database named
+// CacheRevisionDb caches the revision database named, evicting the least recently used databases if the cache is full
 func (c *DatabaseCache) CacheRevisionDb(database SqlDatabase) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.revisionDbs == nil {
-		c.revisionDbs = make(map[revisionDbCacheKey]SqlDatabase)
+		c.revisionDbs = make(map[revisionDbCacheKey]*list.Element)
+		c.revisionDbLru = list.New()
 	}
 
-	if len(c.revisionDbs) > maxCachedKeys {
-		for k := range c.revisionDbs {
-			delete(c.revisionDbs, k)
-		}
-	}
-
-	c.revisionDbs[revisionDbCacheKey{
+	key := revisionDbCacheKey{
 		dbName:        strings.ToLower(database.RevisionQualifiedName()),
 		requestedName: database.RequestedName(),
-	}] = database
+	}
+	if e, ok := c.revisionDbs[key]; ok {
+		e.Value = revisionDbCacheEntry{key: key, db: database}
+		c.revisionDbLru.MoveToFront(e)
+		return
+	}
+
+	c.revisionDbs[key] = c.revisionDbLru.PushFront(revisionDbCacheEntry{key: key, db: database})
+	c.evictRevisionDbs()
+}
+
+// SetMaxRevisionDbs sets the number of revision databases cached, evicting the least recently used databases if there
+// are more than |max| already. A |max| less than 1 disables the cache.
+func (c *DatabaseCache) SetMaxRevisionDbs(max int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxRevisionDbs = max
+	c.evictRevisionDbs()
+}
+
+// evictRevisionDbs evicts the least recently used revision databases until there are no more than maxRevisionDbs.
+// Callers must hold c.mu.
+func (c *DatabaseCache) evictRevisionDbs() {
+	for c.revisionDbLru != nil && c.revisionDbLru.Len() > 0 && c.revisionDbLru.Len() > c.maxRevisionDbs {
+		e := c.revisionDbLru.Back()
+		c.revisionDbLru.Remove(e)
+		delete(c.revisionDbs, e.Value.(revisionDbCacheEntry).key)
+		c.stats.Evictions++
+	}
+}
+
+// Stats returns the statistics of the revision database cache.
+func (c *DatabaseCache) Stats() DatabaseCacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := c.stats
+	stats.Entries = len(c.revisionDbs)
+	stats.MaxEntries = c.maxRevisionDbs
+	return stats
 }
 
 // GetCachedInitialDbState returns the cached initial state for the revision database named, and whether the cache
@@ -332,6 +443,7 @@ func (c *DatabaseCache) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.sessionVars = make(map[string]sessionVarCacheKey)
-	c.revisionDbs = make(map[revisionDbCacheKey]SqlDatabase)
+	c.revisionDbs = make(map[revisionDbCacheKey]*list.Element)
+	c.revisionDbLru = list.New()
 	c.initialDbStates = make(map[doltdb.DataCacheKey]map[string]InitialDbState)
 }
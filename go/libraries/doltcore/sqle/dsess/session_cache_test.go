@@ -0,0 +1,64 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cachedRevisionDb struct {
+	SqlDatabase
+	name string
+}
+
+func (db cachedRevisionDb) RevisionQualifiedName() string {
+	return db.name
+}
+
+func (db cachedRevisionDb) RequestedName() string {
+	return db.name
+}
+
+func TestDatabaseCacheRevisionDbEviction(t *testing.T) {
+	c := newDatabaseCache()
+	c.SetMaxRevisionDbs(2)
+
+	c.CacheRevisionDb(cachedRevisionDb{name: "mydb/main"})
+	c.CacheRevisionDb(cachedRevisionDb{name: "mydb/b1"})
+
+	// using main makes b1 the least recently used
+	_, ok := c.GetCachedRevisionDb("mydb/MAIN", "mydb/main")
+	assert.True(t, ok)
+	c.CacheRevisionDb(cachedRevisionDb{name: "mydb/b2"})
+
+	_, ok = c.GetCachedRevisionDb("mydb/b1", "mydb/b1")
+	assert.False(t, ok)
+	_, ok = c.GetCachedRevisionDb("mydb/main", "mydb/main")
+	assert.True(t, ok)
+	_, ok = c.GetCachedRevisionDb("mydb/b2", "mydb/b2")
+	assert.True(t, ok)
+
+	assert.Equal(t, DatabaseCacheStats{Entries: 2, MaxEntries: 2, Hits: 3, Misses: 1, Evictions: 1}, c.Stats())
+
+	c.SetMaxRevisionDbs(0)
+	assert.Equal(t, 0, c.Stats().Entries)
+	assert.Equal(t, uint64(3), c.Stats().Evictions)
+
+	c.Clear()
+	_, ok = c.GetCachedRevisionDb("mydb/main", "mydb/main")
+	assert.False(t, ok)
+}
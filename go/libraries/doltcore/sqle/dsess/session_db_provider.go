@@ -21,6 +21,10 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/formatupgrade"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/replicationstatus"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/serverhooks"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/webhooks"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -60,14 +64,20 @@ const (
 	RevisionTypeBranch
 	RevisionTypeTag
 	RevisionTypeCommit
+	// RevisionTypeWorkspace is a writable revision pinned to a session workspace (refs/workspaces/...), rather than
+	// to a branch. Workspaces are not visible in the branch namespace.
+	RevisionTypeWorkspace
 )
 
 // RemoteReadReplicaDatabase is a database that pulls from a connected remote when a transaction begins.
 type RemoteReadReplicaDatabase interface {
 	// ValidReplicaState returns whether this read replica is in a valid state to pull from the remote
 	ValidReplicaState(ctx *sql.Context) bool
-	// PullFromRemote performs a pull from the remote and returns any error encountered
+	// PullFromRemote performs a pull from the remote and returns any error encountered. It doesn't pull if the replica
+	// pulled within @@dolt_read_replica_refresh_interval.
 	PullFromRemote(ctx *sql.Context) error
+	// ForcePullFromRemote performs a pull from the remote regardless of when the replica last pulled
+	ForcePullFromRemote(ctx *sql.Context) error
 }
 
 type DoltDatabaseProvider interface {
@@ -87,7 +97,8 @@ type DoltDatabaseProvider interface {
 	// dbName is the name for the new database, branch is an optional parameter indicating which branch to clone
 	// (otherwise all branches are cloned), remoteName is the name for the remote created in the new database, and
 	// remoteUrl is a URL (e.g. "file:///dbs/db1") or an <org>/<database> path indicating a database hosted on DoltHub.
-	CloneDatabaseFromRemote(ctx *sql.Context, dbName, branch, remoteName, remoteUrl string, remoteParams map[string]string) error
+	// If depth is positive, a shallow clone of only the branch cloned is made, with that many commits of its history.
+	CloneDatabaseFromRemote(ctx *sql.Context, dbName, branch, remoteName, remoteUrl string, depth int, remoteParams map[string]string) error
 	// SessionDatabase returns the SessionDatabase for the specified database, which may name a revision of a base
 	// database.
 	SessionDatabase(ctx *sql.Context, dbName string) (SqlDatabase, bool, error)
@@ -96,11 +107,98 @@ type DoltDatabaseProvider interface {
 	BaseDatabase(ctx *sql.Context, dbName string) (SqlDatabase, bool)
 	// DoltDatabases returns all databases known to this provider.
 	DoltDatabases() []SqlDatabase
+	// AllDatabasesWithErrors returns the databases that AllDatabases does, but returns an error if a revision database
+	// can't be loaded, unless @@dolt_lenient_database_listing is set, in which case it's logged and left out.
+	AllDatabasesWithErrors(ctx *sql.Context) ([]sql.Database, error)
+}
+
+// StatementRunner runs queries on behalf of a session. Queries issued by stored procedures and hooks go through the
+// StatementRunner rather than a new engine, so that they are checked against the session's privileges and run in its
+// transaction.
+type StatementRunner interface {
+	Query(ctx *sql.Context, query string) (sql.Schema, sql.RowIter, error)
+}
+
+// The interfaces below are implemented by providers that serve a sql-server. Providers that don't implement them, such
+// as the ones used by tests, run no hooks and report no server state.
+
+// StatementRunnerProvider is a DoltDatabaseProvider with an engine that runs queries for its sessions.
+type StatementRunnerProvider interface {
+	// StatementRunner returns the engine that runs queries for this provider's sessions, or nil if none has been set.
+	StatementRunner() StatementRunner
+}
+
+// ServerHooksProvider is a DoltDatabaseProvider that runs the hooks configured for the server.
+type ServerHooksProvider interface {
+	// ServerHooks returns the hooks configured for the server, or nil if there are none.
+	ServerHooks() *serverhooks.Config
+}
+
+// WebhooksProvider is a DoltDatabaseProvider that fires the webhooks configured for the server.
+type WebhooksProvider interface {
+	// Webhooks returns the dispatcher for the webhooks configured for the server, or nil if there are none.
+	Webhooks() *webhooks.Dispatcher
+}
+
+// FormatUpgradesProvider is a DoltDatabaseProvider that upgrades the storage format of its databases in the background.
+type FormatUpgradesProvider interface {
+	// FormatUpgrades returns the tracker of the server's background storage format upgrades, or nil if the server
+	// doesn't upgrade databases.
+	FormatUpgrades() *formatupgrade.Tracker
+}
+
+// ReplicationStatusProvider is a DoltDatabaseProvider that clones databases from a read replica's replication remote.
+type ReplicationStatusProvider interface {
+	// ReplicationStatus returns the tracker of the read replica's attempts to clone databases from its replication
+	// remote.
+	ReplicationStatus() *replicationstatus.Tracker
+}
+
+// StatementRunnerFor returns the StatementRunner of |pro|, or nil if it doesn't have one.
+func StatementRunnerFor(pro DoltDatabaseProvider) StatementRunner {
+	if p, ok := pro.(StatementRunnerProvider); ok {
+		return p.StatementRunner()
+	}
+	return nil
+}
+
+// ServerHooksFor returns the server hooks of |pro|, or nil if it doesn't run any.
+func ServerHooksFor(pro DoltDatabaseProvider) *serverhooks.Config {
+	if p, ok := pro.(ServerHooksProvider); ok {
+		return p.ServerHooks()
+	}
+	return nil
+}
+
+// WebhooksFor returns the webhook dispatcher of |pro|, or nil if it doesn't fire any.
+func WebhooksFor(pro DoltDatabaseProvider) *webhooks.Dispatcher {
+	if p, ok := pro.(WebhooksProvider); ok {
+		return p.Webhooks()
+	}
+	return nil
+}
+
+// FormatUpgradesFor returns the format upgrade tracker of |pro|, or nil if it doesn't upgrade databases.
+func FormatUpgradesFor(pro DoltDatabaseProvider) *formatupgrade.Tracker {
+	if p, ok := pro.(FormatUpgradesProvider); ok {
+		return p.FormatUpgrades()
+	}
+	return nil
+}
+
+// ReplicationStatusFor returns the replication status tracker of |pro|, or nil if it doesn't replicate databases.
+func ReplicationStatusFor(pro DoltDatabaseProvider) *replicationstatus.Tracker {
+	if p, ok := pro.(ReplicationStatusProvider); ok {
+		return p.ReplicationStatus()
+	}
+	return nil
 }
 
 type SessionDatabaseBranchSpec struct {
 	RepoState env.RepoStateReadWriter
 	Branch    string
+	// Workspace is true when Branch names a session workspace rather than a branch
+	Workspace bool
 }
 
 type SqlDatabase interface {
@@ -116,6 +116,55 @@ func (s SessionStateAdapter) UpdateBranch(name string, new env.BranchConfig) err
 	return repoState.Save(fs)
 }
 
+func (s SessionStateAdapter) RemoveBranch(name string) error {
+	delete(s.branches, name)
+
+	fs, err := s.session.Provider().FileSystemForDatabase(s.dbName)
+	if err != nil {
+		return err
+	}
+
+	repoState, err := env.LoadRepoState(fs)
+	if err != nil {
+		return err
+	}
+	if _, ok := repoState.Branches[name]; !ok {
+		return nil
+	}
+	delete(repoState.Branches, name)
+
+	return repoState.Save(fs)
+}
+
+func (s SessionStateAdapter) GetBranchExpirations() (map[string]env.BranchExpiration, error) {
+	fs, err := s.session.Provider().FileSystemForDatabase(s.dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	repoState, err := env.LoadRepoState(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	return repoState.GetBranchExpirations(), nil
+}
+
+func (s SessionStateAdapter) SetBranchExpiration(name string, expiration env.BranchExpiration) error {
+	fs, err := s.session.Provider().FileSystemForDatabase(s.dbName)
+	if err != nil {
+		return err
+	}
+
+	repoState, err := env.LoadRepoState(fs)
+	if err != nil {
+		return err
+	}
+	repoState.SetBranchExpiration(name, expiration)
+
+	return repoState.Save(fs)
+}
+
 func (s SessionStateAdapter) AddRemote(remote env.Remote) error {
 	if _, ok := s.remotes[remote.Name]; ok {
 		return env.ErrRemoteAlreadyExists
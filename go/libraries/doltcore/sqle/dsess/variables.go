@@ -16,10 +16,14 @@ package dsess
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 )
 
 // Per-DB system variables
@@ -41,23 +45,52 @@ const (
 	ReplicateToRemote             = "dolt_replicate_to_remote"
 	ReadReplicaRemote             = "dolt_read_replica_remote"
 	ReadReplicaForcePull          = "dolt_read_replica_force_pull"
+	ReadReplicaRefreshInterval    = "dolt_read_replica_refresh_interval"
 	ReplicationRemoteURLTemplate  = "dolt_replication_remote_url_template"
+	ReplicationRemoteURLCase      = "dolt_replication_remote_url_case"
+	ReplicationRemoteOrg          = "dolt_replication_remote_org"
+	ReplicateRemoteParams         = "dolt_replicate_remote_params"
 	SkipReplicationErrors         = "dolt_skip_replication_errors"
 	ReplicateHeads                = "dolt_replicate_heads"
 	ReplicateAllHeads             = "dolt_replicate_all_heads"
 	AsyncReplication              = "dolt_async_replication"
+	AsyncReplicationQueue         = "dolt_async_replication_queue"
 	AwsCredsFile                  = "aws_credentials_file"
 	AwsCredsProfile               = "aws_credentials_profile"
 	AwsCredsRegion                = "aws_credentials_region"
 	ShowBranchDatabases           = "dolt_show_branch_databases"
+	AlternateRevisionDelimiter    = "dolt_alternate_revision_delimiter"
+	RevisionDbCacheSize           = "dolt_revision_db_cache_size"
+	LenientDatabaseListing        = "dolt_lenient_database_listing"
+	CreateDatabaseStorageFormat   = "dolt_create_database_storage_format"
+	ProtectedTags                 = "dolt_protected_tags"
 	DoltLogLevel                  = "dolt_log_level"
+	ResultCacheSize               = "dolt_result_cache_size"
+	ResultCacheMaxRows            = "dolt_result_cache_max_rows"
+	DiffRenames                   = "dolt_diff_renames"
+	DiffRenameSimilarity          = "dolt_diff_rename_similarity"
 
 	DoltClusterRoleVariable         = "dolt_cluster_role"
 	DoltClusterRoleEpochVariable    = "dolt_cluster_role_epoch"
 	DoltClusterAckWritesTimeoutSecs = "dolt_cluster_ack_writes_timeout_secs"
+
+	DoltClusterReplicationMaxBytesPerSec = "dolt_cluster_replication_max_bytes_per_sec"
+	DoltClusterReplicationChunksPerBatch = "dolt_cluster_replication_chunks_per_batch"
+	DoltClusterReplicationFastFail       = "dolt_cluster_replication_fast_fail"
 )
 
-const URLTemplateDatabasePlaceholder = "{database}"
+const (
+	URLTemplateDatabasePlaceholder = "{database}"
+	URLTemplateBranchPlaceholder   = "{branch}"
+	URLTemplateOrgPlaceholder      = "{org}"
+)
+
+// Values of the dolt_replication_remote_url_case system variable
+const (
+	URLCasePreserve = "preserve"
+	URLCaseLower    = "lower"
+	URLCaseUpper    = "upper"
+)
 
 // DefineSystemVariablesForDB defines per database dolt-session variables in the engine as necessary
 func DefineSystemVariablesForDB(name string) {
@@ -187,6 +220,104 @@ func IgnoreReplicationErrors() bool {
 	return skip == SysVarTrue
 }
 
+// ProtectedTagPatterns returns the tag name patterns in the dolt_protected_tags system variable. Tags matching these
+// patterns cannot be deleted or moved, either with dolt_tag() or by a push to this server.
+func ProtectedTagPatterns() []string {
+	_, val, ok := sql.SystemVariables.GetGlobal(ProtectedTags)
+	if !ok {
+		return nil
+	}
+	patterns, _ := val.(string)
+	return doltdb.ParseTagPatterns(patterns)
+}
+
+// ReplicationRemoteURL returns the URL of the replication remote for the database |dbName| on |branch|, expanded from
+// |urlTemplate| with the values of the global dolt_replication_remote_org and dolt_replication_remote_url_case
+// system variables.
+func ReplicationRemoteURL(urlTemplate, dbName, branch string) (string, error) {
+	_, org, _ := sql.SystemVariables.GetGlobal(ReplicationRemoteOrg)
+	_, urlCase, _ := sql.SystemVariables.GetGlobal(ReplicationRemoteURLCase)
+	orgStr, _ := org.(string)
+	caseStr, _ := urlCase.(string)
+	return ExpandURLTemplate(urlTemplate, map[string]string{
+		URLTemplateDatabasePlaceholder: dbName,
+		URLTemplateBranchPlaceholder:   branch,
+		URLTemplateOrgPlaceholder:      orgStr,
+	}, caseStr)
+}
+
+// ExpandURLTemplate replaces each placeholder in |urlTemplate| with its value in |values|. Since SQL names are case
+// insensitive but URLs generally aren't, values are first transformed according to |urlCase|, one of URLCasePreserve,
+// URLCaseLower or URLCaseUpper, so that every spelling of a name maps onto the same URL. Values are then escaped as
+// URL path segments. It's an error for the template to use a placeholder whose value is empty.
+func ExpandURLTemplate(urlTemplate string, values map[string]string, urlCase string) (string, error) {
+	replacements := make([]string, 0, 2*len(values))
+	for placeholder, val := range values {
+		if !strings.Contains(urlTemplate, placeholder) {
+			continue
+		}
+		if val == "" {
+			return "", fmt.Errorf("remote url template %s uses %s, but it has no value", urlTemplate, placeholder)
+		}
+
+		switch strings.ToLower(urlCase) {
+		case "", URLCasePreserve:
+		case URLCaseLower:
+			val = strings.ToLower(val)
+		case URLCaseUpper:
+			val = strings.ToUpper(val)
+		default:
+			return "", fmt.Errorf("invalid remote url case %s: must be one of %s, %s, %s", urlCase, URLCasePreserve, URLCaseLower, URLCaseUpper)
+		}
+
+		replacements = append(replacements, placeholder, url.PathEscape(val))
+	}
+	return strings.NewReplacer(replacements...).Replace(urlTemplate), nil
+}
+
+// replicationRemoteParams are the remote parameters that can be set with dolt_replicate_remote_params. GCS remotes
+// take no parameters, and always use the application default credentials.
+var replicationRemoteParams = map[string]bool{
+	dbfactory.AWSRegionParam:    true,
+	dbfactory.AWSCredsTypeParam: true,
+	dbfactory.AWSCredsFileParam: true,
+	dbfactory.AWSCredsProfile:   true,
+	dbfactory.OSSCredsFileParam: true,
+	dbfactory.OSSCredsProfile:   true,
+}
+
+// RemoteParamsForReplication returns the remote parameters set in the global dolt_replicate_remote_params system
+// variable, which are used for the remotes that databases are replicated to and cloned from. The variable is a comma
+// separated list of key=value pairs, such as "aws-creds-type=file,aws-creds-file=/etc/dolt/creds".
+func RemoteParamsForReplication() (map[string]string, error) {
+	_, val, _ := sql.SystemVariables.GetGlobal(ReplicateRemoteParams)
+	paramsStr, _ := val.(string)
+	return ParseRemoteParams(paramsStr)
+}
+
+// ParseRemoteParams parses a comma separated list of key=value remote parameters, returning an error for any key that
+// isn't a known AWS or OSS remote parameter.
+func ParseRemoteParams(paramsStr string) (map[string]string, error) {
+	params := make(map[string]string)
+	for _, kv := range strings.Split(paramsStr, ",") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(kv, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid remote parameter '%s': expected key=value", kv)
+		}
+		if !replicationRemoteParams[k] {
+			return nil, fmt.Errorf("unknown remote parameter '%s'", k)
+		}
+		params[k] = strings.TrimSpace(v)
+	}
+	return params, nil
+}
+
 // WarnReplicationError logs a warning for the replication error given
 func WarnReplicationError(ctx *sql.Context, err error) {
 	ctx.GetLogger().Warn(fmt.Errorf("replication failure: %w", err))
@@ -0,0 +1,84 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandURLTemplate(t *testing.T) {
+	values := map[string]string{
+		URLTemplateDatabasePlaceholder: "My DB",
+		URLTemplateBranchPlaceholder:   "main",
+		URLTemplateOrgPlaceholder:      "",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		urlCase  string
+		expected string
+		err      bool
+	}{
+		{"database", "aws://[table:bucket]/{database}", "", "aws://[table:bucket]/My%20DB", false},
+		{"every use", "http://host/{database}/{database}", URLCasePreserve, "http://host/My%20DB/My%20DB", false},
+		{"branch", "file:///remotes/{database}-{branch}", "", "file:///remotes/My%20DB-main", false},
+		{"lower", "http://host/{database}", URLCaseLower, "http://host/my%20db", false},
+		{"upper", "http://host/{database}", "UPPER", "http://host/MY%20DB", false},
+		{"unknown placeholder", "http://host/{database}/{other}", "", "http://host/My%20DB/{other}", false},
+		{"empty placeholder", "http://host/{org}/{database}", "", "", true},
+		{"invalid case", "http://host/{database}", "title", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ExpandURLTemplate(test.template, values, test.urlCase)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	actual, err := ExpandURLTemplate("http://host/{org}/{database}", map[string]string{
+		URLTemplateDatabasePlaceholder: "db/../x",
+		URLTemplateOrgPlaceholder:      "acme",
+	}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "http://host/acme/db%2F..%2Fx", actual)
+}
+
+func TestParseRemoteParams(t *testing.T) {
+	params, err := ParseRemoteParams("")
+	require.NoError(t, err)
+	assert.Empty(t, params)
+
+	params, err = ParseRemoteParams("aws-creds-type=file, aws-creds-file=/etc/dolt/creds,aws-region=us-west-2,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"aws-creds-type": "file",
+		"aws-creds-file": "/etc/dolt/creds",
+		"aws-region":     "us-west-2",
+	}, params)
+
+	_, err = ParseRemoteParams("aws-region")
+	assert.Error(t, err)
+	_, err = ParseRemoteParams("journal=true")
+	assert.Error(t, err)
+}
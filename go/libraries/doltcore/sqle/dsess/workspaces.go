@@ -0,0 +1,78 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WorkspaceNamePrefix is the prefix of the names generated for session workspaces. Workspace names take the form
+// ws_<connection id>_<n>. Connection ids are reused, e.g. after a server restart, so the name only serves to make
+// collisions unlikely; which session owns a workspace is only recorded by that session itself.
+const WorkspaceNamePrefix = "ws_"
+
+// WorkspaceName returns the name of the |n|th workspace for the connection id given.
+func WorkspaceName(connectionID uint32, n int) string {
+	return fmt.Sprintf("%s%d_%d", WorkspaceNamePrefix, connectionID, n)
+}
+
+// AddWorkspace records that this session opened the workspace named for the database given, branched from |origin|.
+func (d *DoltSession) AddWorkspace(dbName, workspace, origin string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dbName = strings.ToLower(dbName)
+	if d.workspaces[dbName] == nil {
+		d.workspaces[dbName] = make(map[string]string)
+	}
+	d.workspaces[dbName][strings.ToLower(workspace)] = origin
+}
+
+// RemoveWorkspace forgets the workspace named for the database given, returning the head it was branched from and
+// whether this session opened it.
+func (d *DoltSession) RemoveWorkspace(dbName, workspace string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dbName = strings.ToLower(dbName)
+	origin, ok := d.workspaces[dbName][strings.ToLower(workspace)]
+	delete(d.workspaces[dbName], strings.ToLower(workspace))
+	return origin, ok
+}
+
+// OwnsWorkspace returns whether this session opened the workspace named for the database given. Workspaces are only
+// visible to the session that owns them.
+func (d *DoltSession) OwnsWorkspace(dbName, workspace string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, ok := d.workspaces[strings.ToLower(dbName)][strings.ToLower(workspace)]
+	return ok
+}
+
+// Workspaces returns the names of the workspaces this session has opened for the database given, in sorted order.
+func (d *DoltSession) Workspaces(dbName string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var names []string
+	for name := range d.workspaces[strings.ToLower(dbName)] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/types"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
@@ -85,7 +86,10 @@ func (bt *BranchesTable) Schema() sql.Schema {
 	if !bt.remote {
 		columns = append(columns, &sql.Column{Name: "remote", Type: types.Text, Source: tableName, PrimaryKey: false, Nullable: true})
 		columns = append(columns, &sql.Column{Name: "branch", Type: types.Text, Source: tableName, PrimaryKey: false, Nullable: true})
+		columns = append(columns, &sql.Column{Name: "expires", Type: types.Datetime, Source: tableName, PrimaryKey: false, Nullable: true})
 	}
+	columns = append(columns, &sql.Column{Name: "description", Type: types.Text, Source: tableName, PrimaryKey: false, Nullable: true})
+	columns = append(columns, &sql.Column{Name: "metadata", Type: types.JSON, Source: tableName, PrimaryKey: false, Nullable: true})
 	return columns
 }
 
@@ -106,10 +110,12 @@ func (bt *BranchesTable) PartitionRows(sqlCtx *sql.Context, part sql.Partition)
 
 // BranchItr is a sql.RowItr implementation which iterates over each commit as if it's a row in the table.
 type BranchItr struct {
-	table    *BranchesTable
-	branches []string
-	commits  []*doltdb.Commit
-	idx      int
+	table       *BranchesTable
+	branches    []string
+	commits     []*doltdb.Commit
+	metadata    []doltdb.BranchMetadata
+	expirations map[string]env.BranchExpiration
+	idx         int
 }
 
 // NewBranchItr creates a BranchItr from the current environment.
@@ -140,6 +146,7 @@ func NewBranchItr(ctx *sql.Context, table *BranchesTable) (*BranchItr, error) {
 
 	branchNames := make([]string, len(branchRefs))
 	commits := make([]*doltdb.Commit, len(branchRefs))
+	metadata := make([]doltdb.BranchMetadata, len(branchRefs))
 	for i, branch := range branchRefs {
 		commit, err := ddb.ResolveCommitRefAtRoot(ctx, branch, txRoot)
 
@@ -147,6 +154,11 @@ func NewBranchItr(ctx *sql.Context, table *BranchesTable) (*BranchItr, error) {
 			return nil, err
 		}
 
+		metadata[i], _, err = ddb.GetBranchMetadataByNomsRoot(ctx, branch, txRoot)
+		if err != nil {
+			return nil, err
+		}
+
 		if branch.GetType() == ref.RemoteRefType {
 			branchNames[i] = "remotes/" + branch.GetPath()
 		} else {
@@ -156,11 +168,26 @@ func NewBranchItr(ctx *sql.Context, table *BranchesTable) (*BranchItr, error) {
 		commits[i] = commit
 	}
 
+	var expirations map[string]env.BranchExpiration
+	if !remote {
+		// The session's repo state reader sees expirations written by other sessions
+		dbData, ok := dsess.DSessFromSess(ctx.Session).GetDbData(ctx, db.Name())
+		if !ok {
+			dbData = db.DbData()
+		}
+		expirations, err = dbData.Rsr.GetBranchExpirations()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &BranchItr{
-		table:    table,
-		branches: branchNames,
-		commits:  commits,
-		idx:      0,
+		table:       table,
+		branches:    branchNames,
+		commits:     commits,
+		metadata:    metadata,
+		expirations: expirations,
+		idx:         0,
 	}, nil
 }
 
@@ -189,9 +216,23 @@ func (itr *BranchItr) Next(ctx *sql.Context) (sql.Row, error) {
 		return nil, err
 	}
 
+	var description, values interface{}
+	if md := itr.metadata[itr.idx]; !md.IsEmpty() {
+		if md.Description != "" {
+			description = md.Description
+		}
+		if len(md.Values) > 0 {
+			doc := make(map[string]interface{}, len(md.Values))
+			for k, v := range md.Values {
+				doc[k] = v
+			}
+			values = types.JSONDocument{Val: doc}
+		}
+	}
+
 	remoteBranches := itr.table.remote
 	if remoteBranches {
-		return sql.NewRow(name, h.String(), meta.Name, meta.Email, meta.Time(), meta.Description), nil
+		return sql.NewRow(name, h.String(), meta.Name, meta.Email, meta.Time(), meta.Description, description, values), nil
 	} else {
 		branches, err := itr.table.db.DbData().Rsr.GetBranches()
 
@@ -206,7 +247,11 @@ func (itr *BranchItr) Next(ctx *sql.Context) (sql.Row, error) {
 			remoteName = branch.Remote
 			branchName = branch.Merge.Ref.GetPath()
 		}
-		return sql.NewRow(name, h.String(), meta.Name, meta.Email, meta.Time(), meta.Description, remoteName, branchName), nil
+		var expires interface{}
+		if expiration, ok := itr.expirations[name]; ok {
+			expires = expiration.Expires
+		}
+		return sql.NewRow(name, h.String(), meta.Name, meta.Email, meta.Time(), meta.Description, remoteName, branchName, expires, description, values), nil
 	}
 }
 
@@ -0,0 +1,95 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*ColumnMasksTable)(nil)
+
+// ColumnMasksTable is a sql.Table implementation that implements a system table which shows the column masking
+// policies of a database. Policies are added and removed with the dolt_mask() stored procedure. Masked columns are
+// shown by their names in |root|, or by their names when they were masked if they've been dropped since.
+type ColumnMasksTable struct {
+	ddb  *doltdb.DoltDB
+	root *doltdb.RootValue
+}
+
+// NewColumnMasksTable creates a ColumnMasksTable
+func NewColumnMasksTable(_ *sql.Context, ddb *doltdb.DoltDB, root *doltdb.RootValue) sql.Table {
+	return &ColumnMasksTable{ddb: ddb, root: root}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ColumnMasksTableName
+func (dt *ColumnMasksTable) Name() string {
+	return doltdb.ColumnMasksTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ColumnMasksTableName
+func (dt *ColumnMasksTable) String() string {
+	return doltdb.ColumnMasksTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the column masks system table.
+func (dt *ColumnMasksTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "table_name", Type: types.Text, Source: doltdb.ColumnMasksTableName, PrimaryKey: true},
+		{Name: "column_name", Type: types.Text, Source: doltdb.ColumnMasksTableName, PrimaryKey: true},
+		{Name: "mask", Type: types.Text, Source: doltdb.ColumnMasksTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (dt *ColumnMasksTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (dt *ColumnMasksTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (dt *ColumnMasksTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	masks, err := dt.ddb.GetColumnMasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := dt.root.GetAllSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]sql.Row, len(masks.Masks))
+	for i, m := range masks.Masks {
+		tableName, columnName := m.Table, m.Column
+		for name, sch := range schemas {
+			if col, ok := sch.GetAllCols().GetByTag(m.Tag); ok {
+				tableName, columnName = name, col.Name
+				break
+			}
+		}
+		rows[i] = sql.NewRow(tableName, columnName, string(m.Kind))
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/store/types"
@@ -268,5 +269,14 @@ func (dt *CommitDiffTable) rootValForHash(ctx *sql.Context, hashStr string) (*do
 
 func (dt *CommitDiffTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
 	dp := part.(DiffPartition)
-	return dp.GetRowIter(ctx, dt.ddb, dt.joiner, sql.IndexLookup{})
+	iter, err := dp.GetRowIter(ctx, dt.ddb, dt.joiner, sql.IndexLookup{})
+	if err != nil {
+		return nil, err
+	}
+	masker, err := dsess.NewColumnMasker(ctx, dt.ddb, dt.Schema(), dsess.DiffTableColumnTag(dp.fromSch, dp.toSch))
+	if err != nil {
+		iter.Close(ctx)
+		return nil, err
+	}
+	return masker.RowIter(iter), nil
 }
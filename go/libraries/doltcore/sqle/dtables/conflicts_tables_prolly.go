@@ -396,14 +396,15 @@ func (itr *prollyConflictRowIter) nextConflictVals(ctx *sql.Context) (c conf, er
 }
 
 // loadTableMaps loads the maps specified in the metadata if they are different from
-// the currently loaded maps. |baseHash| and |theirHash| are table hashes.
+// the currently loaded maps. |baseHash| and |theirHash| are table hashes. A table renamed
+// on one side of the merge is found in the other by its columns.
 func (itr *prollyConflictRowIter) loadTableMaps(ctx context.Context, baseHash, theirHash hash.Hash) error {
 	if itr.baseHash.Compare(baseHash) != 0 {
 		rv, err := doltdb.LoadRootValueFromRootIshAddr(ctx, itr.vrw, itr.ns, baseHash)
 		if err != nil {
 			return err
 		}
-		baseTbl, ok, err := rv.GetTable(ctx, itr.tblName)
+		baseTbl, _, ok, err := rv.GetTableOrRenamed(ctx, itr.tblName, itr.ourSch)
 		if err != nil {
 			return err
 		}
@@ -428,7 +429,7 @@ func (itr *prollyConflictRowIter) loadTableMaps(ctx context.Context, baseHash, t
 		if err != nil {
 			return err
 		}
-		theirTbl, ok, err := rv.GetTable(ctx, itr.tblName)
+		theirTbl, _, ok, err := rv.GetTableOrRenamed(ctx, itr.tblName, itr.ourSch)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,207 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+	types2 "github.com/dolthub/dolt/go/store/types"
+)
+
+var _ sql.Table = (*ConstraintViolationSourcesTable)(nil)
+
+// ConstraintViolationSourcesTable is a sql.Table implementation that implements a system table which shows where the
+// constraint violations of each table came from: the commit or working set whose changes introduced them, which is
+// the from_root_ish of the violations, and the branch that commit came from. The violations themselves are listed in
+// the dolt_constraint_violations_<table> tables.
+type ConstraintViolationSourcesTable struct {
+	dbName string
+	ddb    *doltdb.DoltDB
+	root   *doltdb.RootValue
+}
+
+// NewConstraintViolationSourcesTable creates a ConstraintViolationSourcesTable
+func NewConstraintViolationSourcesTable(_ *sql.Context, dbName string, ddb *doltdb.DoltDB, root *doltdb.RootValue) sql.Table {
+	return &ConstraintViolationSourcesTable{dbName: dbName, ddb: ddb, root: root}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ConstraintViolationSourcesTableName
+func (dt *ConstraintViolationSourcesTable) Name() string {
+	return doltdb.ConstraintViolationSourcesTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ConstraintViolationSourcesTableName
+func (dt *ConstraintViolationSourcesTable) String() string {
+	return doltdb.ConstraintViolationSourcesTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the constraint violation sources system table.
+func (dt *ConstraintViolationSourcesTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "table", Type: types.Text, Source: doltdb.ConstraintViolationSourcesTableName, PrimaryKey: true},
+		{Name: "from_root_ish", Type: types.Text, Source: doltdb.ConstraintViolationSourcesTableName, PrimaryKey: true},
+		{Name: "source_branch", Type: types.Text, Source: doltdb.ConstraintViolationSourcesTableName, PrimaryKey: false, Nullable: true},
+		{Name: "num_violations", Type: types.Uint64, Source: doltdb.ConstraintViolationSourcesTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (dt *ConstraintViolationSourcesTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition for each table with constraint violations.
+func (dt *ConstraintViolationSourcesTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
+	tblNames, err := dt.root.TablesWithConstraintViolations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tblPartitions := make([]tableOfTablesPartition, len(tblNames))
+	for i := range tblNames {
+		tblPartitions[i] = tableOfTablesPartition(tblNames[i])
+	}
+	return &tableOfTablesPartitionIter{
+		idx:      0,
+		tblNames: tblPartitions,
+	}, nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (dt *ConstraintViolationSourcesTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
+	tblName := string(part.Key())
+	tbl, ok, err := dt.root.GetTable(ctx, tblName)
+	if err != nil || !ok {
+		return sql.RowsToRowIter(), err
+	}
+
+	sources, counts, err := ConstraintViolationSources(ctx, tbl)
+	if err != nil {
+		return nil, err
+	}
+
+	// revision databases of commits have no working set, and so no merge in progress
+	ws, _ := dsess.DSessFromSess(ctx.Session).WorkingSet(ctx, dt.dbName)
+
+	rows := make([]sql.Row, len(sources))
+	for i, h := range sources {
+		branch, err := ViolationSourceBranch(ctx, dt.ddb, ws, h)
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = sql.NewRow(tblName, h.String(), nullIfEmpty(branch), counts[h])
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// ConstraintViolationSources returns the hashes of the commits or working sets whose changes introduced the
+// constraint violations of |tbl|, along with the number of violations each introduced. Sources are ordered by hash.
+// Tables in the old format don't record where their violations came from, and have no sources.
+func ConstraintViolationSources(ctx context.Context, tbl *doltdb.Table) ([]hash.Hash, map[hash.Hash]uint64, error) {
+	if tbl.Format() != types2.Format_DOLT {
+		return nil, nil, nil
+	}
+
+	arts, err := tbl.GetArtifacts(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	itr, err := durable.ProllyMapFromArtifactIndex(arts).IterAllCVs(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sources []hash.Hash
+	counts := make(map[hash.Hash]uint64)
+	for {
+		art, err := itr.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := counts[art.SourceRootish]; !ok {
+			sources = append(sources, art.SourceRootish)
+		}
+		counts[art.SourceRootish]++
+	}
+
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Less(sources[j])
+	})
+	return sources, counts, nil
+}
+
+// ViolationSourceBranch returns the branch that the changes of |source|, the commit or working set that introduced
+// some constraint violations, came from. While the merge that introduced them is in progress, this is the branch or
+// other ref that was merged. Otherwise, it's the branches whose history includes |source|, or "" if no branch does,
+// or if |source| isn't a commit.
+func ViolationSourceBranch(ctx context.Context, ddb *doltdb.DoltDB, ws *doltdb.WorkingSet, source hash.Hash) (string, error) {
+	if ws != nil && ws.MergeActive() {
+		h, err := ws.MergeState().Commit().HashOf()
+		if err != nil {
+			return "", err
+		}
+		if h == source {
+			return ws.MergeState().CommitSpecStr(), nil
+		}
+	}
+
+	cm, err := ddb.ReadCommit(ctx, source)
+	if err != nil {
+		// the violations were introduced by a working set, such as when a transaction was merged
+		return "", nil
+	}
+
+	branches, err := ddb.GetBranches(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var names []string
+	for _, b := range branches {
+		head, err := ddb.ResolveCommitRef(ctx, b)
+		if err != nil {
+			return "", err
+		}
+		anc, err := doltdb.GetCommitAncestor(ctx, head, cm)
+		if err == doltdb.ErrNoCommonAncestor {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		ancHash, err := anc.HashOf()
+		if err != nil {
+			return "", err
+		}
+		if ancHash == source {
+			names = append(names, b.GetPath())
+		}
+	}
+
+	sort.Strings(names)
+	return strings.Join(names, ","), nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/expreval"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
@@ -227,7 +228,16 @@ func (dt *DiffTable) HeadHash() (hash.Hash, error) {
 
 func (dt *DiffTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
 	dp := part.(DiffPartition)
-	return dp.GetRowIter(ctx, dt.ddb, dt.joiner, dt.lookup)
+	iter, err := dp.GetRowIter(ctx, dt.ddb, dt.joiner, dt.lookup)
+	if err != nil {
+		return nil, err
+	}
+	masker, err := dsess.NewColumnMasker(ctx, dt.ddb, dt.Schema(), dsess.DiffTableColumnTag(dp.fromSch, dp.toSch))
+	if err != nil {
+		iter.Close(ctx)
+		return nil, err
+	}
+	return masker.RowIter(iter), nil
 }
 
 func (dt *DiffTable) LookupPartitions(ctx *sql.Context, lookup sql.IndexLookup) (sql.PartitionIter, error) {
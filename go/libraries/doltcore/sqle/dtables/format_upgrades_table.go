@@ -0,0 +1,93 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*FormatUpgradesTable)(nil)
+
+// FormatUpgradesTable is a sql.Table implementation that implements a system table which shows the progress of the
+// server's background storage format upgrades. Every database shows the upgrades of all the server's databases, so
+// that a server's upgrades can be followed from any of them. Progress is only kept in memory, and the table is empty
+// if the server doesn't upgrade databases.
+type FormatUpgradesTable struct{}
+
+// NewFormatUpgradesTable creates a FormatUpgradesTable
+func NewFormatUpgradesTable() sql.Table {
+	return &FormatUpgradesTable{}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// FormatUpgradesTableName
+func (ft *FormatUpgradesTable) Name() string {
+	return doltdb.FormatUpgradesTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// FormatUpgradesTableName
+func (ft *FormatUpgradesTable) String() string {
+	return doltdb.FormatUpgradesTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the format upgrades system table.
+func (ft *FormatUpgradesTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "database", Type: types.Text, Source: doltdb.FormatUpgradesTableName, PrimaryKey: true},
+		{Name: "status", Type: types.Text, Source: doltdb.FormatUpgradesTableName, PrimaryKey: false},
+		{Name: "old_format", Type: types.Text, Source: doltdb.FormatUpgradesTableName, PrimaryKey: false},
+		{Name: "new_format", Type: types.Text, Source: doltdb.FormatUpgradesTableName, PrimaryKey: false},
+		{Name: "attempts", Type: types.Int64, Source: doltdb.FormatUpgradesTableName, PrimaryKey: false},
+		{Name: "error", Type: types.Text, Source: doltdb.FormatUpgradesTableName, PrimaryKey: false, Nullable: true},
+		{Name: "created", Type: types.Datetime, Source: doltdb.FormatUpgradesTableName, PrimaryKey: false},
+		{Name: "updated", Type: types.Datetime, Source: doltdb.FormatUpgradesTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (ft *FormatUpgradesTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (ft *FormatUpgradesTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (ft *FormatUpgradesTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	upgrades := dsess.FormatUpgradesFor(dsess.DSessFromSess(ctx.Session).Provider()).Upgrades()
+
+	rows := make([]sql.Row, len(upgrades))
+	for i, u := range upgrades {
+		rows[i] = sql.NewRow(
+			u.Database,
+			string(u.Status),
+			u.OldFormat,
+			u.NewFormat,
+			int64(u.Attempts),
+			nullIfEmpty(u.Error),
+			u.Created,
+			u.Updated,
+		)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+// InfoSchemaDoltTablesTableName is the name of the INFORMATION_SCHEMA table that lists the Dolt metadata of every
+// table.
+const InfoSchemaDoltTablesTableName = "dolt_tables"
+
+// informationSchemaDatabase extends INFORMATION_SCHEMA with tables that expose Dolt metadata, so that tools can
+// discover the versioning context of a table through the same channel as the rest of its metadata.
+type informationSchemaDatabase struct {
+	sql.Database
+}
+
+var _ sql.Database = informationSchemaDatabase{}
+
+// NewInformationSchemaDatabase returns the INFORMATION_SCHEMA database |isDb|, extended with Dolt's tables.
+func NewInformationSchemaDatabase(isDb sql.Database) sql.Database {
+	return informationSchemaDatabase{Database: isDb}
+}
+
+// GetTableInsensitive implements sql.Database
+func (db informationSchemaDatabase) GetTableInsensitive(ctx *sql.Context, tblName string) (sql.Table, bool, error) {
+	if strings.EqualFold(tblName, InfoSchemaDoltTablesTableName) {
+		return &InfoSchemaDoltTablesTable{}, true, nil
+	}
+	return db.Database.GetTableInsensitive(ctx, tblName)
+}
+
+// GetTableNames implements sql.Database
+func (db informationSchemaDatabase) GetTableNames(ctx *sql.Context) ([]string, error) {
+	names, err := db.Database.GetTableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(names, InfoSchemaDoltTablesTableName), nil
+}
+
+var _ sql.Table = (*InfoSchemaDoltTablesTable)(nil)
+var _ sql.CatalogTable = (*InfoSchemaDoltTablesTable)(nil)
+
+// InfoSchemaDoltTablesTable is the table INFORMATION_SCHEMA.DOLT_TABLES, which lists every table in the working set
+// of each database the session can see, along with the session's current branch and HEAD commit, the last commit that
+// changed the table, and whether the table has staged or unstaged changes, as reported by dolt_table_status().
+type InfoSchemaDoltTablesTable struct {
+	catalog sql.Catalog
+}
+
+// Name implements sql.Table
+func (t *InfoSchemaDoltTablesTable) Name() string {
+	return InfoSchemaDoltTablesTableName
+}
+
+// String implements sql.Table
+func (t *InfoSchemaDoltTablesTable) String() string {
+	return InfoSchemaDoltTablesTableName
+}
+
+// Schema implements sql.Table
+func (t *InfoSchemaDoltTablesTable) Schema() sql.Schema {
+	nameType := types.MustCreateString(sqltypes.VarChar, 64, sql.Collation_Information_Schema_Default)
+	return []*sql.Column{
+		{Name: "TABLE_CATALOG", Type: nameType, Source: InfoSchemaDoltTablesTableName, PrimaryKey: false},
+		{Name: "TABLE_SCHEMA", Type: nameType, Source: InfoSchemaDoltTablesTableName, PrimaryKey: false},
+		{Name: "TABLE_NAME", Type: nameType, Source: InfoSchemaDoltTablesTableName, PrimaryKey: false},
+		{Name: "BRANCH", Type: types.Text, Source: InfoSchemaDoltTablesTableName, PrimaryKey: false, Nullable: true},
+		{Name: "HEAD_COMMIT", Type: types.Text, Source: InfoSchemaDoltTablesTableName, PrimaryKey: false},
+		{Name: "LAST_COMMIT", Type: types.Text, Source: InfoSchemaDoltTablesTableName, PrimaryKey: false, Nullable: true},
+		{Name: "STATUS", Type: types.Text, Source: InfoSchemaDoltTablesTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements sql.Table
+func (t *InfoSchemaDoltTablesTable) Collation() sql.CollationID {
+	return sql.Collation_Information_Schema_Default
+}
+
+// AssignCatalog implements sql.CatalogTable
+func (t *InfoSchemaDoltTablesTable) AssignCatalog(cat sql.Catalog) sql.Table {
+	return &InfoSchemaDoltTablesTable{catalog: cat}
+}
+
+// Partitions implements sql.Table
+func (t *InfoSchemaDoltTablesTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows implements sql.Table
+func (t *InfoSchemaDoltTablesTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	if t.catalog == nil {
+		return sql.RowsToRowIter(), nil
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	var rows []sql.Row
+	for _, db := range t.catalog.AllDatabases(ctx) {
+		dbName := db.Name()
+		roots, ok := sess.GetRoots(ctx, dbName)
+		if !ok {
+			// not a Dolt database
+			continue
+		}
+		ddb, ok := sess.GetDoltDB(ctx, dbName)
+		if !ok {
+			continue
+		}
+
+		var branch interface{}
+		if headRef, err := sess.CWBHeadRef(ctx, dbName); err == nil {
+			branch = headRef.GetPath()
+		}
+		head, err := sess.GetHeadCommit(ctx, dbName)
+		if err != nil {
+			return nil, err
+		}
+		headHash, err := head.HashOf()
+		if err != nil {
+			return nil, err
+		}
+
+		tableNames, err := roots.Working.GetTableNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(tableNames)
+		lastCommits, err := dfunctions.LastCommitsForTables(ctx, ddb, head, tableNames)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tableName := range tableNames {
+			var lastCommit interface{}
+			if h, ok := lastCommits[tableName]; ok {
+				lastCommit = h.String()
+			}
+			status, _, err := dfunctions.StatusOfTable(ctx, roots, tableName)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, sql.NewRow("def", dbName, tableName, branch, headHash.String(), lastCommit, status))
+		}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/prolly"
 )
@@ -52,6 +53,16 @@ func (dt *LogTable) DataLength(ctx *sql.Context) (uint64, error) {
 
 // RowCount implements sql.StatisticsTable
 func (dt *LogTable) RowCount(ctx *sql.Context) (uint64, error) {
+	shallow, err := dt.ddb.IsShallow(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if shallow {
+		// the closure of a shallow clone's commit lists ancestors that were never fetched
+		history, err := dt.ddb.CommitMetaIndex().History(ctx, dt.ddb, dt.head, nil)
+		return uint64(len(history)), err
+	}
+
 	cc, err := dt.head.GetCommitClosure(ctx)
 	if err != nil {
 		// TODO: remove this when we deprecate LD
@@ -108,7 +119,7 @@ func (dt *LogTable) PartitionRows(ctx *sql.Context, p sql.Partition) (sql.RowIte
 }
 
 func (dt *LogTable) GetIndexes(ctx *sql.Context) ([]sql.Index, error) {
-	return index.DoltCommitIndexes(dt.Name(), dt.ddb, true)
+	return index.DoltCommitMetaIndexes(dt.Name(), dt.ddb)
 }
 
 // IndexedAccess implements sql.IndexAddressable
@@ -118,13 +129,53 @@ func (dt *LogTable) IndexedAccess(lookup sql.IndexLookup) sql.IndexedTable {
 }
 
 func (dt *LogTable) LookupPartitions(ctx *sql.Context, lookup sql.IndexLookup) (sql.PartitionIter, error) {
-	if lookup.Index.ID() == index.CommitHashIndexId {
+	switch lookup.Index.ID() {
+	case index.CommitHashIndexId:
 		return dt.commitHashPartitionIter(ctx, lookup)
+	case index.CommitterIndexId, index.EmailIndexId, index.DateIndexId:
+		return dt.commitMetaPartitionIter(ctx, lookup)
 	}
 
 	return dt.Partitions(ctx)
 }
 
+// commitMetaPartitionIter returns a partition for each commit in the history of head whose committer, email or date,
+// depending on the index of |lookup|, is in the ranges of |lookup|. Commits are found with the commit metadata index
+// of the database, so commits that don't match aren't loaded.
+func (dt *LogTable) commitMetaPartitionIter(ctx *sql.Context, lookup sql.IndexLookup) (sql.PartitionIter, error) {
+	id := lookup.Index.ID()
+	entries, err := dt.ddb.CommitMetaIndex().History(ctx, dt.ddb, dt.head, func(meta *datas.CommitMeta) (bool, error) {
+		var v interface{}
+		switch id {
+		case index.CommitterIndexId:
+			v = meta.Name
+		case index.EmailIndexId:
+			v = meta.Email
+		default:
+			v = meta.Time()
+		}
+		return index.CommitMetaInRanges(v, lookup.Ranges)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := make([]sql.Partition, len(entries))
+	for i, e := range entries {
+		cm, err := dt.ddb.ReadCommit(ctx, e.Hash)
+		if err != nil {
+			return nil, err
+		}
+		// the metadata of the index doesn't have the commit's message
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return nil, err
+		}
+		partitions[i] = doltdb.NewCommitPart(e.Hash, cm, meta)
+	}
+	return sql.PartitionsToPartitionIter(partitions...), nil
+}
+
 func (dt *LogTable) commitHashPartitionIter(ctx *sql.Context, lookup sql.IndexLookup) (sql.PartitionIter, error) {
 	hashStrs, ok := index.LookupToPointSelectStr(lookup)
 	if !ok {
@@ -0,0 +1,263 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	sqlTypes "github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var _ sql.Table = (*MergeStrategiesTable)(nil)
+var _ sql.UpdatableTable = (*MergeStrategiesTable)(nil)
+var _ sql.DeletableTable = (*MergeStrategiesTable)(nil)
+var _ sql.InsertableTable = (*MergeStrategiesTable)(nil)
+var _ sql.ReplaceableTable = (*MergeStrategiesTable)(nil)
+
+// MergeStrategiesTable is the system table that stores the strategies that merges use to resolve conflicting changes
+// to the tables whose names match its patterns. Like dolt_ignore, it's created on its first write and is versioned
+// like any other table.
+type MergeStrategiesTable struct {
+	backingTable sql.Table
+}
+
+// NewMergeStrategiesTable creates a MergeStrategiesTable
+func NewMergeStrategiesTable(_ *sql.Context, backingTable sql.Table) sql.Table {
+	return &MergeStrategiesTable{backingTable: backingTable}
+}
+
+func (mt *MergeStrategiesTable) Name() string {
+	return doltdb.MergeStrategiesTableName
+}
+
+func (mt *MergeStrategiesTable) String() string {
+	return doltdb.MergeStrategiesTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the dolt_merge_strategies system table.
+func (mt *MergeStrategiesTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "pattern", Type: sqlTypes.Text, Source: doltdb.MergeStrategiesTableName, PrimaryKey: true},
+		{Name: "strategy", Type: sqlTypes.Text, Source: doltdb.MergeStrategiesTableName, PrimaryKey: false, Nullable: false},
+	}
+}
+
+func (mt *MergeStrategiesTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.
+func (mt *MergeStrategiesTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
+	if mt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return index.SinglePartitionIterFromNomsMap(nil), nil
+	}
+	return mt.backingTable.Partitions(ctx)
+}
+
+func (mt *MergeStrategiesTable) PartitionRows(ctx *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	if mt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return sql.RowsToRowIter(), nil
+	}
+	return mt.backingTable.PartitionRows(ctx, partition)
+}
+
+// Replacer returns a RowReplacer for this table.
+func (mt *MergeStrategiesTable) Replacer(*sql.Context) sql.RowReplacer {
+	return &mergeStrategiesWriter{}
+}
+
+// Updater returns a RowUpdater for this table.
+func (mt *MergeStrategiesTable) Updater(*sql.Context) sql.RowUpdater {
+	return &mergeStrategiesWriter{}
+}
+
+// Inserter returns an Inserter for this table.
+func (mt *MergeStrategiesTable) Inserter(*sql.Context) sql.RowInserter {
+	return &mergeStrategiesWriter{}
+}
+
+// Deleter returns a RowDeleter for this table.
+func (mt *MergeStrategiesTable) Deleter(*sql.Context) sql.RowDeleter {
+	return &mergeStrategiesWriter{}
+}
+
+var _ sql.RowReplacer = (*mergeStrategiesWriter)(nil)
+var _ sql.RowUpdater = (*mergeStrategiesWriter)(nil)
+var _ sql.RowInserter = (*mergeStrategiesWriter)(nil)
+var _ sql.RowDeleter = (*mergeStrategiesWriter)(nil)
+
+// mergeStrategiesWriter writes to the table backing dolt_merge_strategies, creating it if it doesn't exist yet, and
+// rejects rows with strategies that merges don't know.
+type mergeStrategiesWriter struct {
+	errDuringStatementBegin error
+	tableWriter             writer.TableWriter
+}
+
+// Insert inserts the row given, returning an error if it cannot.
+func (mw *mergeStrategiesWriter) Insert(ctx *sql.Context, r sql.Row) error {
+	if err := mw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	r, err := normalizeMergeStrategyRow(r)
+	if err != nil {
+		return err
+	}
+	return mw.tableWriter.Insert(ctx, r)
+}
+
+// Update the given row. Provides both the old and new rows.
+func (mw *mergeStrategiesWriter) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	if err := mw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	new, err := normalizeMergeStrategyRow(new)
+	if err != nil {
+		return err
+	}
+	return mw.tableWriter.Update(ctx, old, new)
+}
+
+// Delete deletes the given row.
+func (mw *mergeStrategiesWriter) Delete(ctx *sql.Context, r sql.Row) error {
+	if err := mw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	return mw.tableWriter.Delete(ctx, r)
+}
+
+// normalizeMergeStrategyRow returns |r| with its strategy in lower case, or an error if it isn't a merge strategy.
+func normalizeMergeStrategyRow(r sql.Row) (sql.Row, error) {
+	s, ok := r[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid merge strategy: %v", r[1])
+	}
+	strategy, err := doltdb.ParseMergeStrategy(s)
+	if err != nil {
+		return nil, err
+	}
+	return sql.NewRow(r[0], string(strategy)), nil
+}
+
+// StatementBegin is called before the first operation of a statement. It creates the table backing
+// dolt_merge_strategies if it doesn't exist yet.
+func (mw *mergeStrategiesWriter) StatementBegin(ctx *sql.Context) {
+	dbName := ctx.GetCurrentDatabase()
+	dSess := dsess.DSessFromSess(ctx.Session)
+
+	roots, _ := dSess.GetRoots(ctx, dbName)
+	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	if err != nil {
+		mw.errDuringStatementBegin = err
+		return
+	}
+	if !ok {
+		mw.errDuringStatementBegin = fmt.Errorf("no root value found in session")
+		return
+	}
+
+	found, err := roots.Working.HasTable(ctx, doltdb.MergeStrategiesTableName)
+	if err != nil {
+		mw.errDuringStatementBegin = err
+		return
+	}
+
+	if !found {
+		colCollection := schema.NewColCollection(
+			schema.Column{
+				Name:       "pattern",
+				Tag:        schema.DoltMergeStrategiesPatternTag,
+				Kind:       types.StringKind,
+				IsPartOfPK: true,
+				TypeInfo:   typeinfo.FromKind(types.StringKind),
+			},
+			schema.Column{
+				Name:       "strategy",
+				Tag:        schema.DoltMergeStrategiesStrategyTag,
+				Kind:       types.StringKind,
+				IsPartOfPK: false,
+				TypeInfo:   typeinfo.FromKind(types.StringKind),
+			},
+		)
+
+		newSchema, err := schema.NewSchema(colCollection, nil, schema.Collation_Default, nil, nil)
+		if err != nil {
+			mw.errDuringStatementBegin = err
+			return
+		}
+
+		newRootValue, err := roots.Working.CreateEmptyTable(ctx, doltdb.MergeStrategiesTableName, newSchema)
+		if err != nil {
+			mw.errDuringStatementBegin = err
+			return
+		}
+
+		if dbState.WorkingSet() == nil {
+			mw.errDuringStatementBegin = doltdb.ErrOperationNotSupportedInDetachedHead
+			return
+		}
+
+		// As with dolt_ignore, the WriteSession's working set is updated so that the table writer can find the new
+		// table before the session's root is.
+		err = dbState.WriteSession().SetWorkingSet(ctx, dbState.WorkingSet().WithWorkingRoot(newRootValue))
+		if err != nil {
+			mw.errDuringStatementBegin = err
+			return
+		}
+
+		dSess.SetRoot(ctx, dbName, newRootValue)
+	}
+
+	tableWriter, err := dbState.WriteSession().GetTableWriter(ctx, doltdb.MergeStrategiesTableName, dbName, dSess.SetRoot)
+	if err != nil {
+		mw.errDuringStatementBegin = err
+		return
+	}
+
+	mw.tableWriter = tableWriter
+	tableWriter.StatementBegin(ctx)
+}
+
+// DiscardChanges is called if a statement encounters an error, and all current changes since the statement beginning
+// should be discarded.
+func (mw *mergeStrategiesWriter) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
+	if mw.tableWriter != nil {
+		return mw.tableWriter.DiscardChanges(ctx, errorEncountered)
+	}
+	return nil
+}
+
+// StatementComplete is called after the last operation of the statement, indicating that it has successfully completed.
+func (mw *mergeStrategiesWriter) StatementComplete(ctx *sql.Context) error {
+	return mw.tableWriter.StatementComplete(ctx)
+}
+
+// Close finalizes the write operation, persisting the result.
+func (mw *mergeStrategiesWriter) Close(ctx *sql.Context) error {
+	if mw.tableWriter != nil {
+		return mw.tableWriter.Close(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*PatchRejectsTable)(nil)
+
+// PatchRejectsTable is a sql.Table implementation that implements a system table which shows the hunks rejected by the
+// last call to dolt_apply_patch() in this session. Rejects are only kept in the session, and are replaced each time a
+// patch is applied.
+type PatchRejectsTable struct {
+	dbName string
+}
+
+// NewPatchRejectsTable creates a PatchRejectsTable
+func NewPatchRejectsTable(dbName string) sql.Table {
+	return &PatchRejectsTable{dbName: dbName}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// PatchRejectsTableName
+func (dt *PatchRejectsTable) Name() string {
+	return doltdb.PatchRejectsTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// PatchRejectsTableName
+func (dt *PatchRejectsTable) String() string {
+	return doltdb.PatchRejectsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the patch rejects system table.
+func (dt *PatchRejectsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "hunk", Type: types.Int64, Source: doltdb.PatchRejectsTableName, PrimaryKey: true},
+		{Name: "table_name", Type: types.Text, Source: doltdb.PatchRejectsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "patch", Type: types.Text, Source: doltdb.PatchRejectsTableName, PrimaryKey: false},
+		{Name: "reason", Type: types.Text, Source: doltdb.PatchRejectsTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (dt *PatchRejectsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (dt *PatchRejectsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (dt *PatchRejectsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	baseName, _ := dsess.SplitRevisionDbName(dt.dbName)
+	rejects := dsess.DSessFromSess(ctx.Session).PatchRejects(baseName)
+
+	rows := make([]sql.Row, len(rejects))
+	for i, r := range rejects {
+		rows[i] = sql.NewRow(
+			int64(r.Hunk),
+			nullIfEmpty(r.TableName),
+			r.Patch,
+			r.Reason,
+		)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*ProposalsTable)(nil)
+var _ sql.Table = (*ProposalCommentsTable)(nil)
+
+// ProposalsTable is a sql.Table implementation that implements a system table which shows the change proposals
+// recorded in a database. Proposals are opened and updated with the dolt_proposal() stored procedure.
+type ProposalsTable struct {
+	ddb *doltdb.DoltDB
+}
+
+// NewProposalsTable creates a ProposalsTable
+func NewProposalsTable(_ *sql.Context, ddb *doltdb.DoltDB) sql.Table {
+	return &ProposalsTable{ddb: ddb}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ProposalsTableName
+func (dt *ProposalsTable) Name() string {
+	return doltdb.ProposalsTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ProposalsTableName
+func (dt *ProposalsTable) String() string {
+	return doltdb.ProposalsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the proposals system table.
+func (dt *ProposalsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "id", Type: types.Uint64, Source: doltdb.ProposalsTableName, PrimaryKey: true},
+		{Name: "source_branch", Type: types.Text, Source: doltdb.ProposalsTableName, PrimaryKey: false},
+		{Name: "target_branch", Type: types.Text, Source: doltdb.ProposalsTableName, PrimaryKey: false},
+		{Name: "description", Type: types.Text, Source: doltdb.ProposalsTableName, PrimaryKey: false},
+		{Name: "status", Type: types.Text, Source: doltdb.ProposalsTableName, PrimaryKey: false},
+		{Name: "author", Type: types.Text, Source: doltdb.ProposalsTableName, PrimaryKey: false},
+		{Name: "reviewers", Type: types.Text, Source: doltdb.ProposalsTableName, PrimaryKey: false},
+		{Name: "approvals", Type: types.Text, Source: doltdb.ProposalsTableName, PrimaryKey: false},
+		{Name: "merge_commit", Type: types.Text, Source: doltdb.ProposalsTableName, PrimaryKey: false, Nullable: true},
+		{Name: "created", Type: types.Datetime, Source: doltdb.ProposalsTableName, PrimaryKey: false},
+		{Name: "updated", Type: types.Datetime, Source: doltdb.ProposalsTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (dt *ProposalsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (dt *ProposalsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (dt *ProposalsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	proposals, err := dt.ddb.GetProposals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]sql.Row, len(proposals))
+	for i, p := range proposals {
+		var mergeCommit interface{}
+		if len(p.MergeCommit) > 0 {
+			mergeCommit = p.MergeCommit
+		}
+		rows[i] = sql.NewRow(
+			p.ID,
+			p.Source,
+			p.Target,
+			p.Description,
+			string(p.Status),
+			p.Author,
+			strings.Join(p.Reviewers, ","),
+			strings.Join(p.Approvals, ","),
+			mergeCommit,
+			p.Created,
+			p.Updated,
+		)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// ProposalCommentsTable is a sql.Table implementation that implements a system table which shows the comments left
+// on the change proposals recorded in a database.
+type ProposalCommentsTable struct {
+	ddb *doltdb.DoltDB
+}
+
+// NewProposalCommentsTable creates a ProposalCommentsTable
+func NewProposalCommentsTable(_ *sql.Context, ddb *doltdb.DoltDB) sql.Table {
+	return &ProposalCommentsTable{ddb: ddb}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ProposalCommentsTableName
+func (dt *ProposalCommentsTable) Name() string {
+	return doltdb.ProposalCommentsTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ProposalCommentsTableName
+func (dt *ProposalCommentsTable) String() string {
+	return doltdb.ProposalCommentsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the proposal comments system table.
+func (dt *ProposalCommentsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "proposal_id", Type: types.Uint64, Source: doltdb.ProposalCommentsTableName, PrimaryKey: true},
+		{Name: "comment_id", Type: types.Uint64, Source: doltdb.ProposalCommentsTableName, PrimaryKey: true},
+		{Name: "author", Type: types.Text, Source: doltdb.ProposalCommentsTableName, PrimaryKey: false},
+		{Name: "comment", Type: types.Text, Source: doltdb.ProposalCommentsTableName, PrimaryKey: false},
+		{Name: "created", Type: types.Datetime, Source: doltdb.ProposalCommentsTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (dt *ProposalCommentsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (dt *ProposalCommentsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (dt *ProposalCommentsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	proposals, err := dt.ddb.GetProposals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []sql.Row
+	for _, p := range proposals {
+		for i, c := range p.Comments {
+			rows = append(rows, sql.NewRow(p.ID, uint64(i+1), c.Author, c.Comment, c.Created))
+		}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
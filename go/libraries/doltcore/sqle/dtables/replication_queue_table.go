@@ -0,0 +1,92 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*ReplicationQueueTable)(nil)
+
+// ReplicationQueueTable is a sql.Table implementation that implements a system table which shows the pushes queued
+// for a database's replication remote when dolt_async_replication_queue is enabled. It's empty otherwise.
+type ReplicationQueueTable struct {
+	ddb *doltdb.DoltDB
+}
+
+// NewReplicationQueueTable creates a ReplicationQueueTable
+func NewReplicationQueueTable(ddb *doltdb.DoltDB) sql.Table {
+	return &ReplicationQueueTable{ddb: ddb}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ReplicationQueueTableName
+func (rt *ReplicationQueueTable) Name() string {
+	return doltdb.ReplicationQueueTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ReplicationQueueTableName
+func (rt *ReplicationQueueTable) String() string {
+	return doltdb.ReplicationQueueTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the replication queue system table.
+func (rt *ReplicationQueueTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "ref", Type: types.Text, Source: doltdb.ReplicationQueueTableName, PrimaryKey: true},
+		{Name: "hash", Type: types.Text, Source: doltdb.ReplicationQueueTableName, PrimaryKey: false, Nullable: true},
+		{Name: "enqueued_at", Type: types.Datetime, Source: doltdb.ReplicationQueueTableName, PrimaryKey: false},
+		{Name: "attempts", Type: types.Int64, Source: doltdb.ReplicationQueueTableName, PrimaryKey: false},
+		{Name: "last_error", Type: types.Text, Source: doltdb.ReplicationQueueTableName, PrimaryKey: false, Nullable: true},
+		{Name: "next_attempt", Type: types.Datetime, Source: doltdb.ReplicationQueueTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (rt *ReplicationQueueTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (rt *ReplicationQueueTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (rt *ReplicationQueueTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	queue := rt.ddb.ReplicationQueue()
+	if queue == nil {
+		return sql.RowsToRowIter(), nil
+	}
+
+	pushes := queue.Pushes()
+	rows := make([]sql.Row, len(pushes))
+	for i, p := range pushes {
+		rows[i] = sql.NewRow(
+			p.Ref,
+			nullIfEmpty(p.Hash),
+			p.EnqueuedAt,
+			int64(p.Attempts),
+			nullIfEmpty(p.LastError),
+			p.NextAttempt,
+		)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*ReplicationStatusTable)(nil)
+
+// ReplicationStatusTable is a sql.Table implementation that implements a system table which shows the last attempt
+// of a read replica to clone each database it doesn't have from its replication remote. Like dolt_format_upgrades,
+// every database shows the attempts for all of the server's databases. Attempts are only kept in memory.
+type ReplicationStatusTable struct{}
+
+// NewReplicationStatusTable creates a ReplicationStatusTable
+func NewReplicationStatusTable() sql.Table {
+	return &ReplicationStatusTable{}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ReplicationStatusTableName
+func (rt *ReplicationStatusTable) Name() string {
+	return doltdb.ReplicationStatusTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ReplicationStatusTableName
+func (rt *ReplicationStatusTable) String() string {
+	return doltdb.ReplicationStatusTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the replication status system table.
+func (rt *ReplicationStatusTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "database", Type: types.Text, Source: doltdb.ReplicationStatusTableName, PrimaryKey: true},
+		{Name: "remote_url", Type: types.Text, Source: doltdb.ReplicationStatusTableName, PrimaryKey: false},
+		{Name: "status", Type: types.Text, Source: doltdb.ReplicationStatusTableName, PrimaryKey: false},
+		{Name: "error", Type: types.Text, Source: doltdb.ReplicationStatusTableName, PrimaryKey: false, Nullable: true},
+		{Name: "last_attempt", Type: types.Datetime, Source: doltdb.ReplicationStatusTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (rt *ReplicationStatusTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (rt *ReplicationStatusTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (rt *ReplicationStatusTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	attempts := dsess.ReplicationStatusFor(dsess.DSessFromSess(ctx.Session).Provider()).Attempts()
+
+	rows := make([]sql.Row, len(attempts))
+	for i, a := range attempts {
+		rows[i] = sql.NewRow(
+			a.Database,
+			a.RemoteURL,
+			string(a.Status),
+			nullIfEmpty(a.Error),
+			a.Attempted,
+		)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
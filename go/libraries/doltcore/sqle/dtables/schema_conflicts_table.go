@@ -155,15 +155,24 @@ func newSchemaConflict(ctx context.Context, table string, baseRoot *doltdb.RootV
 	if err != nil {
 		return schemaConflict{}, err
 	}
-	baseSch := bs[table]
+	baseName := table
+	baseSch, ok := bs[table]
+	if !ok {
+		// the table may have been renamed since the merge base
+		if _, baseName, ok, err = baseRoot.GetTableOrRenamed(ctx, table, c.ToSch); err != nil {
+			return schemaConflict{}, err
+		} else if ok {
+			baseSch = bs[baseName]
+		}
+	}
 
 	fkc, err := baseRoot.GetForeignKeyCollection(ctx)
 	if err != nil {
 		return schemaConflict{}, err
 	}
-	baseFKs, _ := fkc.KeysForTable(table)
+	baseFKs, _ := fkc.KeysForTable(baseName)
 
-	base, err := getCreateTableStatement(table, baseSch, baseFKs, bs)
+	base, err := getCreateTableStatement(baseName, baseSch, baseFKs, bs)
 	if err != nil {
 		return schemaConflict{}, err
 	}
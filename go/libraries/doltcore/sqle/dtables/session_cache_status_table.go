@@ -0,0 +1,85 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*SessionCacheStatusTable)(nil)
+
+// revisionDatabasesCache is the name of the session's revision database cache in dolt_session_cache_status
+const revisionDatabasesCache = "revision_databases"
+
+// SessionCacheStatusTable is a sql.Table implementation that implements a system table which shows the size and hit
+// rate of the current session's revision database cache. Every database shows the same session-wide statistics.
+type SessionCacheStatusTable struct{}
+
+// NewSessionCacheStatusTable creates a SessionCacheStatusTable
+func NewSessionCacheStatusTable() sql.Table {
+	return &SessionCacheStatusTable{}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// SessionCacheStatusTableName
+func (st *SessionCacheStatusTable) Name() string {
+	return doltdb.SessionCacheStatusTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// SessionCacheStatusTableName
+func (st *SessionCacheStatusTable) String() string {
+	return doltdb.SessionCacheStatusTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the session cache status system table.
+func (st *SessionCacheStatusTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "cache", Type: types.Text, Source: doltdb.SessionCacheStatusTableName, PrimaryKey: true},
+		{Name: "entries", Type: types.Int64, Source: doltdb.SessionCacheStatusTableName, PrimaryKey: false},
+		{Name: "max_entries", Type: types.Int64, Source: doltdb.SessionCacheStatusTableName, PrimaryKey: false},
+		{Name: "hits", Type: types.Uint64, Source: doltdb.SessionCacheStatusTableName, PrimaryKey: false},
+		{Name: "misses", Type: types.Uint64, Source: doltdb.SessionCacheStatusTableName, PrimaryKey: false},
+		{Name: "evictions", Type: types.Uint64, Source: doltdb.SessionCacheStatusTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (st *SessionCacheStatusTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (st *SessionCacheStatusTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (st *SessionCacheStatusTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	stats := dsess.DSessFromSess(ctx.Session).DatabaseCache(ctx).Stats()
+	return sql.RowsToRowIter(sql.NewRow(
+		revisionDatabasesCache,
+		int64(stats.Entries),
+		int64(stats.MaxEntries),
+		stats.Hits,
+		stats.Misses,
+		stats.Evictions,
+	)), nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*WebhookDeliveriesTable)(nil)
+
+// WebhookDeliveriesTable is a sql.Table implementation that implements a system table which shows the server's recent
+// webhook deliveries for a database. Deliveries are only kept in memory, and the table is empty if the server has no
+// webhooks configured.
+type WebhookDeliveriesTable struct {
+	dbName string
+}
+
+// NewWebhookDeliveriesTable creates a WebhookDeliveriesTable
+func NewWebhookDeliveriesTable(dbName string) sql.Table {
+	return &WebhookDeliveriesTable{dbName: dbName}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// WebhookDeliveriesTableName
+func (dt *WebhookDeliveriesTable) Name() string {
+	return doltdb.WebhookDeliveriesTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// WebhookDeliveriesTableName
+func (dt *WebhookDeliveriesTable) String() string {
+	return doltdb.WebhookDeliveriesTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the webhook deliveries system table.
+func (dt *WebhookDeliveriesTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "id", Type: types.Uint64, Source: doltdb.WebhookDeliveriesTableName, PrimaryKey: true},
+		{Name: "url", Type: types.Text, Source: doltdb.WebhookDeliveriesTableName, PrimaryKey: false},
+		{Name: "event", Type: types.Text, Source: doltdb.WebhookDeliveriesTableName, PrimaryKey: false},
+		{Name: "ref", Type: types.Text, Source: doltdb.WebhookDeliveriesTableName, PrimaryKey: false},
+		{Name: "old_hash", Type: types.Text, Source: doltdb.WebhookDeliveriesTableName, PrimaryKey: false, Nullable: true},
+		{Name: "new_hash", Type: types.Text, Source: doltdb.WebhookDeliveriesTableName, PrimaryKey: false, Nullable: true},
+		{Name: "status", Type: types.Text, Source: doltdb.WebhookDeliveriesTableName, PrimaryKey: false},
+		{Name: "attempts", Type: types.Int64, Source: doltdb.WebhookDeliveriesTableName, PrimaryKey: false},
+		{Name: "response_code", Type: types.Int64, Source: doltdb.WebhookDeliveriesTableName, PrimaryKey: false, Nullable: true},
+		{Name: "error", Type: types.Text, Source: doltdb.WebhookDeliveriesTableName, PrimaryKey: false, Nullable: true},
+		{Name: "created", Type: types.Datetime, Source: doltdb.WebhookDeliveriesTableName, PrimaryKey: false},
+		{Name: "updated", Type: types.Datetime, Source: doltdb.WebhookDeliveriesTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (dt *WebhookDeliveriesTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (dt *WebhookDeliveriesTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (dt *WebhookDeliveriesTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	dispatcher := dsess.WebhooksFor(dsess.DSessFromSess(ctx.Session).Provider())
+	deliveries := dispatcher.Deliveries(dt.dbName)
+
+	rows := make([]sql.Row, len(deliveries))
+	for i, d := range deliveries {
+		var responseCode interface{}
+		if d.ResponseCode != 0 {
+			responseCode = int64(d.ResponseCode)
+		}
+		rows[i] = sql.NewRow(
+			d.ID,
+			d.URL,
+			string(d.Payload.Event),
+			d.Payload.Ref,
+			nullIfEmpty(d.Payload.OldHash),
+			nullIfEmpty(d.Payload.NewHash),
+			string(d.Status),
+			int64(d.Attempts),
+			responseCode,
+			nullIfEmpty(d.Error),
+			d.Created,
+			d.Updated,
+		)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+func nullIfEmpty(s string) interface{} {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
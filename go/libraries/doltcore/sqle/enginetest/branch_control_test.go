@@ -1161,6 +1161,101 @@ var BranchControlTests = []BranchControlTest{
 			},
 		},
 	},
+	{
+		Name: "Renaming branch moves its database entries",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"INSERT INTO dolt_branch_control VALUES ('%', '%', 'root', 'localhost', 'admin');",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE USER otheruser@localhost;",
+			"GRANT ALL ON *.* TO otheruser@localhost;",
+			"CALL DOLT_BRANCH('otherbranch');",
+			"INSERT INTO dolt_branch_control VALUES ('mydb', 'otherbranch', 'testuser', 'localhost', 'admin');",
+			"INSERT INTO dolt_branch_control VALUES ('mydb', 'otherbranch', 'otheruser', 'localhost', 'write');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:     "testuser",
+				Host:     "localhost",
+				Query:    "CALL DOLT_BRANCH('-m', 'otherbranch', 'newbranch');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query: "SELECT * FROM dolt_branch_control WHERE user <> 'root' ORDER BY user;",
+				Expected: []sql.Row{
+					{"mydb", "newbranch", "otheruser", "localhost", uint64(branch_control.Permissions_Write)},
+					{"mydb", "newbranch", "testuser", "localhost", uint64(branch_control.Permissions_Admin)},
+				},
+			},
+		},
+	},
+	{
+		Name: "Copying branch copies its database entries",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"INSERT INTO dolt_branch_control VALUES ('%', '%', 'root', 'localhost', 'admin');",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CALL DOLT_BRANCH('otherbranch');",
+			"INSERT INTO dolt_branch_control VALUES ('mydb', 'otherbranch', 'testuser', 'localhost', 'write');",
+			"INSERT INTO dolt_branch_control VALUES ('%', 'otherbranch', 'testuser', 'localhost', 'read');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				Query:    "CALL DOLT_BRANCH('-c', 'otherbranch', 'newbranch');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query: "SELECT * FROM dolt_branch_control WHERE user = 'testuser' ORDER BY `database`, branch;",
+				Expected: []sql.Row{
+					{"%", "otherbranch", "testuser", "localhost", uint64(branch_control.Permissions_Read)},
+					{"mydb", "newbranch", "testuser", "localhost", uint64(branch_control.Permissions_Write)},
+					{"mydb", "otherbranch", "testuser", "localhost", uint64(branch_control.Permissions_Write)},
+				},
+			},
+		},
+	},
+	{
+		Name: "Approving a proposal requires being a reviewer or having write access to its target",
+		SetUpScript: []string{
+			"DELETE FROM dolt_branch_control WHERE user = '%';",
+			"INSERT INTO dolt_branch_control VALUES ('%', '%', 'root', 'localhost', 'admin');",
+			"CREATE USER testuser@localhost;",
+			"GRANT ALL ON *.* TO testuser@localhost;",
+			"CREATE USER reviewer@localhost;",
+			"GRANT ALL ON *.* TO reviewer@localhost;",
+			"CREATE USER maintainer@localhost;",
+			"GRANT ALL ON *.* TO maintainer@localhost;",
+			"INSERT INTO dolt_branch_control VALUES ('%', 'main', 'maintainer', 'localhost', 'write');",
+			"CALL DOLT_BRANCH('feature');",
+			"CALL DOLT_PROPOSAL('open', 'feature', 'main', '--reviewers', 'reviewer');",
+		},
+		Assertions: []BranchControlTestAssertion{
+			{
+				User:        "testuser",
+				Host:        "localhost",
+				Query:       "CALL DOLT_PROPOSAL('approve', '1');",
+				ExpectedErr: branch_control.ErrIncorrectPermissions,
+			},
+			{
+				User:     "reviewer",
+				Host:     "localhost",
+				Query:    "CALL DOLT_PROPOSAL('approve', '1');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				User:     "maintainer",
+				Host:     "localhost",
+				Query:    "CALL DOLT_PROPOSAL('approve', '1');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "SELECT approvals FROM dolt_proposals;",
+				Expected: []sql.Row{{"reviewer,maintainer"}},
+			},
+		},
+	},
 	{
 		Name: "Proper database scoping",
 		SetUpScript: []string{
@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	gms "github.com/dolthub/go-mysql-server"
 	"github.com/dolthub/go-mysql-server/enginetest"
 	"github.com/dolthub/go-mysql-server/enginetest/queries"
 	"github.com/dolthub/go-mysql-server/enginetest/scriptgen/setup"
@@ -39,6 +40,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/types"
@@ -1710,6 +1712,160 @@ func TestDoltBranch(t *testing.T) {
 	}
 }
 
+func TestDoltWorkspace(t *testing.T) {
+	for _, script := range DoltWorkspaceScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func TestDoltSetDefaultBranch(t *testing.T) {
+	for _, script := range DoltSetDefaultBranchScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func TestDoltApplyPatch(t *testing.T) {
+	for _, script := range DoltApplyPatchScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func TestDoltCopyTable(t *testing.T) {
+	for _, script := range DoltCopyTableScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func TestDoltSchemaBundle(t *testing.T) {
+	for _, script := range DoltSchemaBundleScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func TestDoltViolationOrigins(t *testing.T) {
+	for _, script := range DoltViolationOriginsScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func TestDoltLineage(t *testing.T) {
+	for _, script := range DoltLineageScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func TestDoltBlame(t *testing.T) {
+	for _, script := range DoltBlameScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func TestDoltVectorIndex(t *testing.T) {
+	for _, script := range DoltVectorIndexScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func TestDoltReplicationStatus(t *testing.T) {
+	for _, script := range DoltReplicationStatusScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func TestDoltInfoSchema(t *testing.T) {
+	for _, script := range DoltInfoSchemaDoltTablesScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			e, err := h.NewEngine(t)
+			require.NoError(t, err)
+			catalog := e.(*gms.Engine).Analyzer.Catalog
+			catalog.InfoSchema = dtables.NewInformationSchemaDatabase(catalog.InfoSchema)
+			enginetest.TestScriptWithEngine(t, e, h, script)
+		}()
+	}
+}
+
+func TestDoltTableMetadataFunctions(t *testing.T) {
+	for _, script := range DoltTableMetadataFunctionScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func TestDoltProposal(t *testing.T) {
+	for _, script := range DoltProposalScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func TestDoltBisect(t *testing.T) {
+	for _, script := range DoltBisectScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
+func TestDoltRebase(t *testing.T) {
+	for _, script := range DoltRebaseScripts {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestScript(t, h, script)
+		}()
+	}
+}
+
 func TestDoltTag(t *testing.T) {
 	for _, script := range DoltTagTestScripts {
 		func() {
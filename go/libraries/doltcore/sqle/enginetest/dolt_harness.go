@@ -25,6 +25,7 @@ import (
 	"github.com/dolthub/go-mysql-server/enginetest"
 	"github.com/dolthub/go-mysql-server/enginetest/scriptgen/setup"
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
 	"github.com/dolthub/go-mysql-server/sql/mysql_db"
 	"github.com/dolthub/go-mysql-server/sql/rowexec"
 	"github.com/stretchr/testify/require"
@@ -168,7 +169,7 @@ func (d *DoltHarness) NewEngine(t *testing.T) (enginetest.QueryEngine, error) {
 		d.branchControl = branch_control.CreateDefaultController()
 
 		pro := d.newProvider()
-		doltProvider, ok := pro.(sqle.DoltDatabaseProvider)
+		doltProvider, ok := pro.(*sqle.DoltDatabaseProvider)
 		require.True(t, ok)
 		d.provider = doltProvider
 
@@ -180,7 +181,13 @@ func (d *DoltHarness) NewEngine(t *testing.T) (enginetest.QueryEngine, error) {
 		if err != nil {
 			return nil, err
 		}
-		e.Analyzer.ExecBuilder = rowexec.DefaultBuilder
+		e.Analyzer.ExecBuilder = sqle.NewExecBuilder(rowexec.DefaultBuilder)
+		for _, b := range e.Analyzer.Batches {
+			if b.Desc == "post-validation" {
+				b.Rules = append(b.Rules, analyzer.Rule{Id: sqle.VectorSearchRuleId, Apply: sqle.VectorSearchRule})
+			}
+		}
+		doltProvider.SetStatementRunner(e)
 		d.engine = e
 
 		ctx := enginetest.NewContext(d)
@@ -303,7 +310,7 @@ func (d *DoltHarness) NewDatabases(names ...string) []sql.Database {
 	d.branchControl = branch_control.CreateDefaultController()
 
 	pro := d.newProvider()
-	doltProvider, ok := pro.(sqle.DoltDatabaseProvider)
+	doltProvider, ok := pro.(*sqle.DoltDatabaseProvider)
 	require.True(d.t, ok)
 	d.provider = doltProvider
 
@@ -316,6 +323,7 @@ func (d *DoltHarness) NewDatabases(names ...string) []sql.Database {
 
 	e := enginetest.NewEngineWithProvider(d.t, d, d.provider)
 	require.NoError(d.t, err)
+	doltProvider.SetStatementRunner(e)
 	d.engine = e
 
 	for _, name := range names {
@@ -341,7 +349,7 @@ func (d *DoltHarness) NewDatabases(names ...string) []sql.Database {
 }
 
 func (d *DoltHarness) NewReadOnlyEngine(provider sql.DatabaseProvider) (enginetest.QueryEngine, error) {
-	ddp, ok := provider.(sqle.DoltDatabaseProvider)
+	ddp, ok := provider.(*sqle.DoltDatabaseProvider)
 	if !ok {
 		return nil, fmt.Errorf("expected a DoltDatabaseProvider")
 	}
@@ -403,10 +411,11 @@ func (d *DoltHarness) newProvider() sql.MutableDatabaseProvider {
 	d.multiRepoEnv = mrEnv
 
 	b := env.GetDefaultInitBranch(d.multiRepoEnv.Config())
-	pro, err := sqle.NewDoltDatabaseProvider(b, d.multiRepoEnv.FileSystem())
+	cfg := sqle.NewDoltDatabaseProviderConfig(b, d.multiRepoEnv.FileSystem()).WithDbFactoryUrl(doltdb.InMemDoltDB)
+	pro, err := sqle.NewDoltDatabaseProviderFromConfig(cfg, nil, nil)
 	require.NoError(d.t, err)
 
-	return pro.WithDbFactoryUrl(doltdb.InMemDoltDB)
+	return pro
 }
 
 func (d *DoltHarness) newTable(db sql.Database, name string, schema sql.PrimaryKeySchema) (sql.Table, error) {
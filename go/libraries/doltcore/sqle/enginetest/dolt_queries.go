@@ -16,6 +16,7 @@ package enginetest
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/enginetest/queries"
@@ -26,6 +27,7 @@ import (
 	"github.com/dolthub/vitess/go/vt/proto/query"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 )
 
 var ViewsWithAsOfScriptTest = queries.ScriptTest{
@@ -460,6 +462,145 @@ var DoltRevisionDbScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "database revision specs: branch names are case insensitive",
+		SetUpScript: []string{
+			"create table t01 (pk int primary key, c1 int)",
+			"call dolt_add('t01');",
+			"call dolt_commit('-am', 'creating table t01 on main');",
+			"call dolt_branch('Feature');",
+			"call dolt_checkout('Feature');",
+			"insert into t01 values (1, 1);",
+			"call dolt_commit('-am', 'adding a row to t01 on Feature');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "use `mydb/feature`;",
+				Expected: []sql.Row{},
+			},
+			{
+				// The database name is the requested name, but the branch keeps its own case
+				Query:    "select database(), active_branch()",
+				Expected: []sql.Row{{"mydb/feature", "Feature"}},
+			},
+			{
+				Query:    "select * from `mydb/FEATURE`.t01;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "insert into `mydb/FeAtUrE`.t01 values (2, 2);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "select * from t01;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+			{
+				Query:    "select * from `mydb/Main`.t01;",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "database revision specs: alternate revision delimiter",
+		SetUpScript: []string{
+			"create table t01 (pk int primary key, c1 int)",
+			"call dolt_add('t01');",
+			"call dolt_commit('-am', 'creating table t01 on main');",
+			"call dolt_branch('branch1');",
+			"insert into `mydb/branch1`.t01 values (1, 1);",
+			"create database `other@db`;",
+			"SET @@GLOBAL.dolt_alternate_revision_delimiter = '@';",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "use `mydb@branch1`;",
+				Expected: []sql.Row{},
+			},
+			{
+				// the database is named with the usual delimiter once it's resolved
+				Query:    "select database(), active_branch()",
+				Expected: []sql.Row{{"mydb/branch1", "branch1"}},
+			},
+			{
+				Query:    "select * from t01;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				// the usual delimiter still works
+				Query:    "select * from `mydb/branch1`.t01;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "select * from `mydb@main`.t01;",
+				Expected: []sql.Row{},
+			},
+			{
+				// there's no database named other, so the delimiter is part of the database's name
+				Query:    "use `other@db`;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select database()",
+				Expected: []sql.Row{{"other@db"}},
+			},
+			{
+				Query:          "create database `new@db`;",
+				ExpectedErrStr: "invalid database name 'new@db': it contains the revision delimiter '@' set in @@dolt_alternate_revision_delimiter",
+			},
+			{
+				Query:    "use mydb;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "SET @@GLOBAL.dolt_alternate_revision_delimiter = '';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:       "select * from `mydb@main`.t01;",
+				ExpectedErr: sql.ErrDatabaseNotFound,
+			},
+		},
+	},
+	{
+		Name: "database revision specs: date specs",
+		SetUpScript: []string{
+			"create table t01 (pk int primary key, c1 int)",
+			"call dolt_add('t01');",
+			"call dolt_commit('-am', 'creating table t01 on main', '--date', '2023-01-01T12:00:00');",
+			"insert into t01 values (1, 1);",
+			"call dolt_commit('-am', 'adding a row to t01 on main', '--date', '2023-06-01T12:00:00');",
+			"insert into t01 values (2, 2);",
+			"call dolt_commit('-am', 'adding another row to t01 on main', '--date', '2023-12-01T12:00:00');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select * from `mydb/main@{2023-02-01}`.t01;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from `mydb/main@{2023-06-01 12:00:00}`.t01;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "use `mydb/MAIN@{2023-07-01}`;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select database(), active_branch()",
+				Expected: []sql.Row{{"mydb/MAIN@{2023-07-01}", nil}},
+			},
+			{
+				Query:    "select * from t01;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:          "select * from `mydb/main@{last week}`.t01;",
+				ExpectedErrStr: "invalid date spec 'main@{last week}': 'last week' is not a date",
+			},
+		},
+	},
 	{
 		Name: "database revision specs: tag-qualified revision spec",
 		SetUpScript: []string{
@@ -1487,6 +1628,195 @@ var DoltUserPrivTests = []queries.UserPrivilegeTest{
 			},
 		},
 	},
+	{
+		Name: "protected tags can only be force deleted by super users",
+		SetUpScript: []string{
+			"CREATE TABLE mydb.test (pk BIGINT PRIMARY KEY);",
+			"CALL DOLT_COMMIT('-Am', 'creating table test');",
+			"CALL DOLT_TAG('v1');",
+			"CALL DOLT_TAG('v2');",
+			"SET @@GLOBAL.dolt_protected_tags = 'v*';",
+			"CREATE USER tester@localhost;",
+			"GRANT ALL ON mydb.* TO tester@localhost;",
+		},
+		Assertions: []queries.UserPrivilegeTestAssertion{
+			{
+				User:           "tester",
+				Host:           "localhost",
+				Query:          "CALL DOLT_TAG('-d', 'v1');",
+				ExpectedErrStr: "tag is protected: cannot delete tag 'v1'",
+			},
+			{
+				User:           "tester",
+				Host:           "localhost",
+				Query:          "CALL DOLT_TAG('-d', '-f', 'v1');",
+				ExpectedErrStr: "tag is protected: deleting tag 'v1' requires the SUPER privilege",
+			},
+			{
+				User:           "root",
+				Host:           "localhost",
+				Query:          "CALL DOLT_TAG('-d', 'v1');",
+				ExpectedErrStr: "tag is protected: cannot delete tag 'v1'",
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_TAG('-d', '-f', 'v1');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "GRANT SUPER ON *.* TO tester@localhost;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "CALL DOLT_TAG('-d', '-f', 'v2');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT tag_name FROM dolt_tags;",
+				Expected: []sql.Row{},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SET @@GLOBAL.dolt_protected_tags = '';",
+				Expected: []sql.Row{{}},
+			},
+		},
+	},
+	{
+		Name: "column masks",
+		SetUpScript: []string{
+			"CREATE TABLE mydb.people (id INT PRIMARY KEY, email VARCHAR(100), ssn VARCHAR(11), age INT);",
+			"INSERT INTO mydb.people VALUES (1, 'alice@example.com', '123-45-6789', 30);",
+			"CALL DOLT_ADD('.')",
+			"CALL DOLT_COMMIT('-am', 'creating table people');",
+			"UPDATE mydb.people SET ssn = '987-65-4321', age = 31 WHERE id = 1;",
+			"CALL DOLT_COMMIT('-am', 'updating alice');",
+			"CREATE USER tester@localhost;",
+			"GRANT ALL ON mydb.* TO tester@localhost;",
+			"CALL DOLT_MASK('people', 'email', 'hash');",
+			"CALL DOLT_MASK('people', 'ssn', 'redact');",
+			"CALL DOLT_MASK('people', 'age', 'null');",
+		},
+		Assertions: []queries.UserPrivilegeTestAssertion{
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT * FROM mydb.dolt_column_masks;",
+				Expected: []sql.Row{{"people", "age", "null"}, {"people", "email", "hash"}, {"people", "ssn", "redact"}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT id, LENGTH(email), email = SHA2('alice@example.com', 256), ssn, age FROM mydb.people;",
+				Expected: []sql.Row{{1, 64, false, "****", nil}},
+			},
+			{
+				User:           "tester",
+				Host:           "localhost",
+				Query:          "SELECT id FROM mydb.people WHERE ssn = '987-65-4321';",
+				ExpectedErrStr: "column ssn of table people is masked, and can only be filtered, joined or searched on by users with the SUPER privilege",
+			},
+			{
+				User:           "tester",
+				Host:           "localhost",
+				Query:          "SELECT id FROM mydb.people WHERE id IN (SELECT id FROM mydb.people WHERE ssn LIKE '9%');",
+				ExpectedErrStr: "column ssn of table people is masked, and can only be filtered, joined or searched on by users with the SUPER privilege",
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT ssn, age FROM mydb.people AS OF 'HEAD~1';",
+				Expected: []sql.Row{{"****", nil}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT DISTINCT LENGTH(email), ssn, age FROM mydb.dolt_history_people;",
+				Expected: []sql.Row{{64, "****", nil}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT to_ssn, from_ssn, to_age, from_age FROM mydb.dolt_diff_people WHERE diff_type = 'modified';",
+				Expected: []sql.Row{{"****", "****", nil, nil}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT to_ssn, from_ssn, to_age, from_age FROM dolt_diff('HEAD~1', 'HEAD', 'people');",
+				Expected: []sql.Row{{"****", "****", nil, nil}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM dolt_patch('HEAD~1', 'HEAD', 'people') WHERE statement LIKE '%987-65-4321%';",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:           "tester",
+				Host:           "localhost",
+				Query:          "UPDATE mydb.people SET age = 32 WHERE id = 1;",
+				ExpectedErrStr: "table people has masked columns, and can only be updated, deleted from or altered by users with the SUPER privilege",
+			},
+			{
+				User:           "tester",
+				Host:           "localhost",
+				Query:          "CALL DOLT_MASK('-d', 'people', 'ssn');",
+				ExpectedErrStr: "error: column masks can only be changed by users with the SUPER privilege",
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM mydb.people;",
+				Expected: []sql.Row{{1, "alice@example.com", "987-65-4321", 31}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT ssn FROM mydb.people AS OF 'HEAD~1';",
+				Expected: []sql.Row{{"123-45-6789"}},
+			},
+			{
+				// masks follow columns when they're renamed
+				User:     "root",
+				Host:     "localhost",
+				Query:    "ALTER TABLE mydb.people RENAME COLUMN ssn TO social_security;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT social_security FROM mydb.people;",
+				Expected: []sql.Row{{"****"}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT * FROM mydb.dolt_column_masks;",
+				Expected: []sql.Row{{"people", "age", "null"}, {"people", "email", "hash"}, {"people", "social_security", "redact"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL DOLT_MASK('-d', 'people', 'social_security');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT ssn FROM mydb.people AS OF 'HEAD~1';",
+				Expected: []sql.Row{{"123-45-6789"}},
+			},
+		},
+	},
 }
 
 // HistorySystemTableScriptTests contains working tests for both prepared and non-prepared
@@ -3003,50 +3333,1672 @@ var DoltBranchScripts = []queries.ScriptTest{
 			},
 		},
 	},
-}
-
-var DoltReset = []queries.ScriptTest{
 	{
-		Name: "CALL DOLT_RESET('--hard') should reset the merge state after uncommitted merge",
+		Name: "copying and renaming branches carries their working sets",
 		SetUpScript: []string{
-			"CREATE TABLE test1 (pk int NOT NULL, c1 int, c2 int, PRIMARY KEY (pk));",
-			"CALL DOLT_ADD('.')",
-			"INSERT INTO test1 values (0,1,1);",
-			"CALL DOLT_COMMIT('-am', 'added table')",
-
-			"CALL DOLT_CHECKOUT('-b', 'merge_branch');",
-			"UPDATE test1 set c1 = 2;",
-			"CALL DOLT_COMMIT('-am', 'update pk 0 = 2,1 to test1');",
-
-			"CALL DOLT_CHECKOUT('main');",
-			"UPDATE test1 set c2 = 2;",
-			"CALL DOLT_COMMIT('-am', 'update pk 0 = 1,2 to test1');",
-
-			"CALL DOLT_MERGE('merge_branch');",
-
-			"CALL DOLT_RESET('--hard');",
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'created table t');",
+			"call dolt_branch('feature');",
+			"insert into `mydb/feature`.t values (1);",
 		},
 		Assertions: []queries.ScriptTestAssertion{
 			{
-				Query:          "CALL DOLT_MERGE('--abort')",
-				ExpectedErrStr: "fatal: There is no merge to abort",
+				Query:    "call dolt_branch('-c', 'feature', 'feature2');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select * from `mydb/feature2`.t;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "call dolt_branch('-m', 'feature2', 'feature3');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select * from `mydb/feature3`.t;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select name from dolt_branches order by name;",
+				Expected: []sql.Row{{"feature"}, {"feature3"}, {"main"}},
 			},
 		},
 	},
 	{
-		Name: "CALL DOLT_RESET('--hard') should reset the merge state after conflicting merge",
+		Name: "branches with an expiration",
 		SetUpScript: []string{
-			"SET dolt_allow_commit_conflicts = on",
-			"CREATE TABLE test1 (pk int NOT NULL, c1 int, c2 int, PRIMARY KEY (pk));",
-			"CALL DOLT_ADD('.')",
-			"INSERT INTO test1 values (0,1,1);",
-			"CALL DOLT_COMMIT('-am', 'added table')",
-
-			"CALL DOLT_CHECKOUT('-b', 'merge_branch');",
-			"UPDATE test1 set c1 = 2, c2 = 2;",
-			"CALL DOLT_COMMIT('-am', 'update pk 0 = 2,2 to test1');",
-
-			"CALL DOLT_CHECKOUT('main');",
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'created table t');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_branch('--expires', '30d', 'experiment');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select name, expires > date_add(now(), interval 29 day), expires < date_add(now(), interval 31 day) from dolt_branches order by name;",
+				Expected: []sql.Row{{"experiment", true, true}, {"main", nil, nil}},
+			},
+			{
+				Query:          "call dolt_branch('--expires', 'forever', 'experiment2');",
+				ExpectedErrStr: "invalid branch expiration 'forever'",
+			},
+			{
+				Query:    "call dolt_branch('-c', 'experiment', 'keeper');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_branch('-m', 'experiment', 'experiment2');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select name, expires is not null from dolt_branches order by name;",
+				Expected: []sql.Row{{"experiment2", true}, {"keeper", false}, {"main", false}},
+			},
+			{
+				Query:    "call dolt_branch('-d', 'experiment2');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_branch('experiment2');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select name, expires is not null from dolt_branches order by name;",
+				Expected: []sql.Row{{"experiment2", false}, {"keeper", false}, {"main", false}},
+			},
+		},
+	},
+	{
+		Name: "branch descriptions and metadata",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'created table t');",
+			"call dolt_branch('feature');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select name, description, metadata from dolt_branches order by name;",
+				Expected: []sql.Row{{"feature", nil, nil}, {"main", nil, nil}},
+			},
+			{
+				Query:    "call dolt_branch('--describe', 'new login flow', '--meta', 'owner=amy,ticket=123', 'feature');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_branch('--describe', 'production');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query: "select name, description, metadata from dolt_branches order by name;",
+				Expected: []sql.Row{
+					{"feature", "new login flow", types.MustJSON(`{"owner": "amy", "ticket": "123"}`)},
+					{"main", "production", nil},
+				},
+			},
+			{
+				Query:    "call dolt_branch('--meta', 'ticket=,reviewer=bob', 'feature');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select description, metadata from dolt_branches where name = 'feature';",
+				Expected: []sql.Row{{"new login flow", types.MustJSON(`{"owner": "amy", "reviewer": "bob"}`)}},
+			},
+			{
+				Query:          "call dolt_branch('--meta', 'owner', 'feature');",
+				ExpectedErrStr: "error: invalid metadata 'owner', expected key=value",
+			},
+			{
+				Query:          "call dolt_branch('--describe', 'nope', 'missing');",
+				ExpectedErrStr: "branch not found: missing",
+			},
+			{
+				Query:    "call dolt_branch('-c', 'feature', 'feature2');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_branch('-m', 'feature', 'feature3');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select name, description from dolt_branches order by name;",
+				Expected: []sql.Row{{"feature2", "new login flow"}, {"feature3", "new login flow"}, {"main", "production"}},
+			},
+			{
+				Query:    "call dolt_branch('-D', 'feature3');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_branch('feature3');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select name, description from dolt_branches order by name;",
+				Expected: []sql.Row{{"feature2", "new login flow"}, {"feature3", nil}, {"main", "production"}},
+			},
+			{
+				Query:    "call dolt_branch('--describe', '', 'main');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select name, description from dolt_branches where name = 'main';",
+				Expected: []sql.Row{{"main", nil}},
+			},
+		},
+	},
+}
+
+var DoltSetDefaultBranchScripts = []queries.ScriptTest{
+	{
+		Name: "dolt_set_default_branch",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'created table t');",
+			"call dolt_branch('feature');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_set_default_branch();",
+				ExpectedErrStr: dprocedures.InvalidArgErr.Error(),
+			},
+			{
+				Query:          "call dolt_set_default_branch('nonexistent');",
+				ExpectedErrStr: "branch not found: nonexistent",
+			},
+			{
+				Query:    "call dolt_set_default_branch('FEATURE');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select @@GLOBAL.mydb_default_branch;",
+				Expected: []sql.Row{{""}},
+			},
+			{
+				// the current session stays on the branch it's on
+				Query:    "select active_branch();",
+				Expected: []sql.Row{{"main"}},
+			},
+			{
+				Query:    "set @@GLOBAL.mydb_default_branch = 'main';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "call dolt_set_default_branch('feature');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select @@GLOBAL.mydb_default_branch;",
+				Expected: []sql.Row{{"feature"}},
+			},
+			{
+				Query:    "set @@GLOBAL.mydb_default_branch = '';",
+				Expected: []sql.Row{{}},
+			},
+		},
+	},
+}
+
+var DoltWorkspaceScripts = []queries.ScriptTest{
+	{
+		Name: "dolt_workspace_begin and dolt_workspace_discard",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"insert into t values (1);",
+			"call dolt_commit('-Am', 'created table t');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:            "call dolt_workspace_begin();",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "insert into t values (2);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				Query:    "select name from dolt_branches;",
+				Expected: []sql.Row{{"main"}},
+			},
+			{
+				Query:    "select * from `mydb/main`.t order by pk;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:          "call dolt_workspace_begin();",
+				ExpectedErrStr: dprocedures.ErrAlreadyInWorkspace.Error(),
+			},
+			{
+				Query:    "call dolt_workspace_discard();",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select active_branch();",
+				Expected: []sql.Row{{"main"}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:          "call dolt_workspace_discard();",
+				ExpectedErrStr: dprocedures.ErrNotInWorkspace.Error(),
+			},
+		},
+	},
+	{
+		Name: "dolt_workspace_publish",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"insert into t values (1);",
+			"call dolt_commit('-Am', 'created table t');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:            "call dolt_workspace_begin();",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "insert into t values (2);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:          "call dolt_workspace_publish('feature');",
+				ExpectedErrStr: dprocedures.ErrWorkspaceDirty.Error(),
+			},
+			{
+				Query:            "call dolt_commit('-am', 'inserted 2');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "call dolt_workspace_publish('feature');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select active_branch();",
+				Expected: []sql.Row{{"feature"}},
+			},
+			{
+				Query:    "select name from dolt_branches order by name;",
+				Expected: []sql.Row{{"feature"}, {"main"}},
+			},
+			{
+				Query:    "select * from `mydb/feature`.t order by pk;",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				Query:    "select * from `mydb/main`.t order by pk;",
+				Expected: []sql.Row{{1}},
+			},
+		},
+	},
+}
+
+var DoltProposalScripts = []queries.ScriptTest{
+	{
+		Name: "open, review and merge a proposal",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'created table t');",
+			"call dolt_checkout('-b', 'feature');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'added a row');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_proposal('open', 'feature', 'main', '-m', 'add a row', '--reviewers', 'root');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:          "call dolt_proposal('open', 'feature', 'main');",
+				ExpectedErrStr: "error: proposal 1 already proposes merging 'feature' into 'main'",
+			},
+			{
+				Query:          "call dolt_proposal('open', 'feature', 'missing');",
+				ExpectedErrStr: "branch not found: missing",
+			},
+			{
+				Query:    "select id, source_branch, target_branch, description, status, author, reviewers, approvals, merge_commit from dolt_proposals;",
+				Expected: []sql.Row{{uint64(1), "feature", "main", "add a row", "open", "root", "root", "", nil}},
+			},
+			{
+				Query:    "call dolt_proposal('comment', '1', '-m', 'looks good');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select proposal_id, comment_id, author, comment from dolt_proposal_comments;",
+				Expected: []sql.Row{{uint64(1), uint64(1), "root", "looks good"}},
+			},
+			{
+				Query:          "call dolt_proposal('merge', '1');",
+				ExpectedErrStr: "error: proposal 1 is waiting for approval from: root",
+			},
+			{
+				Query:    "call dolt_proposal('approve', '1');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select approvals from dolt_proposals where id = 1;",
+				Expected: []sql.Row{{"root"}},
+			},
+			{
+				Query:            "call dolt_checkout('feature');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:          "call dolt_proposal('merge', '1');",
+				ExpectedErrStr: "error: proposal 1 must be merged from its target branch 'main'; use dolt_checkout('main') first",
+			},
+			{
+				Query:            "call dolt_checkout('main');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "call dolt_proposal('merge', '1');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select status, merge_commit = hashof('main') from dolt_proposals where id = 1;",
+				Expected: []sql.Row{{"merged", true}},
+			},
+			{
+				Query:          "call dolt_proposal('approve', '1');",
+				ExpectedErrStr: "error: proposal 1 is merged",
+			},
+			{
+				Query:          "call dolt_proposal('close', '2');",
+				ExpectedErrStr: "proposal not found: 2",
+			},
+		},
+	},
+	{
+		Name: "close a proposal",
+		SetUpScript: []string{
+			"call dolt_branch('feature');",
+			"call dolt_proposal('open', 'feature', 'main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_proposal('close', '1');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select status from dolt_proposals;",
+				Expected: []sql.Row{{"closed"}},
+			},
+			{
+				Query:    "call dolt_proposal('open', 'feature', 'main');",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:          "call dolt_proposal('open', 'main', 'main');",
+				ExpectedErrStr: "error: a proposal cannot merge branch 'main' into itself",
+			},
+		},
+	},
+}
+
+// messageValidator validates a message returned by a procedure that names commits, whose hashes aren't known in
+// advance.
+type messageValidator struct {
+	re *regexp.Regexp
+}
+
+func (v messageValidator) Validate(val interface{}) (bool, error) {
+	msg, ok := val.(string)
+	return ok && v.re.MatchString(msg), nil
+}
+
+var DoltBisectScripts = []queries.ScriptTest{
+	{
+		Name: "bisect a linear history",
+		SetUpScript: []string{
+			"create table t (pk int primary key, v int);",
+			"call dolt_commit('-Am', 'created table t');",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-am', 'added 1');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'added 2');",
+			"insert into t values (3, 3);",
+			"call dolt_commit('-am', 'added 3');",
+			"insert into t values (4, 4);",
+			"call dolt_commit('-am', 'added 4');",
+			"insert into t values (5, -5);",
+			"call dolt_commit('-am', 'added 5');",
+			"insert into t values (6, 6);",
+			"call dolt_commit('-am', 'added 6');",
+			"insert into t values (7, 7);",
+			"call dolt_commit('-am', 'added 7');",
+			"insert into t values (8, 8);",
+			"call dolt_commit('-am', 'added 8');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_bisect('good');",
+				ExpectedErrStr: "error: no bisect session is in progress on branch 'main', use dolt_bisect('start') to start one",
+			},
+			{
+				Query:    "call dolt_bisect('start');",
+				Expected: []sql.Row{{nil, nil, "status: waiting for both good and bad commits"}},
+			},
+			{
+				Query:          "call dolt_bisect('start');",
+				ExpectedErrStr: "error: a bisect session is already in progress on branch 'main', use dolt_bisect('reset') to end it",
+			},
+			{
+				Query:          "call dolt_bisect('good');",
+				ExpectedErrStr: "error: no commit is being tested, name the commit to mark as good",
+			},
+			{
+				Query:          "call dolt_bisect('unknown');",
+				ExpectedErrStr: "error: invalid argument 'unknown'",
+			},
+			{
+				Query:    "call dolt_bisect('bad', 'HEAD');",
+				Expected: []sql.Row{{nil, nil, "status: waiting for good commit(s), bad commit known"}},
+			},
+			{
+				Query:    "call dolt_bisect('good', 'HEAD~8');",
+				Expected: []sql.Row{{doltCommit, nil, "Bisecting: 3 revisions left to test after this (roughly 2 steps)"}},
+			},
+			{
+				Query:    "call dolt_bisect('status');",
+				Expected: []sql.Row{{doltCommit, nil, "Bisecting: 3 revisions left to test after this (roughly 2 steps)"}},
+			},
+			{
+				Query:    "call dolt_bisect('good');",
+				Expected: []sql.Row{{doltCommit, nil, "Bisecting: 1 revisions left to test after this (roughly 1 steps)"}},
+			},
+			{
+				Query:    "call dolt_bisect('bad');",
+				Expected: []sql.Row{{doltCommit, nil, "Bisecting: 0 revisions left to test after this (roughly 0 steps)"}},
+			},
+			{
+				Query:    "call dolt_bisect('bad');",
+				Expected: []sql.Row{{nil, doltCommit, messageValidator{regexp.MustCompile(`^[0-9a-v]{32} is the first bad commit$`)}}},
+			},
+			{
+				Query:    "call dolt_bisect('reset');",
+				Expected: []sql.Row{{nil, nil, "ended the bisect session on branch 'main'"}},
+			},
+			{
+				Query:          "call dolt_bisect('status');",
+				ExpectedErrStr: "error: no bisect session is in progress on branch 'main', use dolt_bisect('start') to start one",
+			},
+		},
+	},
+	{
+		Name: "bisect with skipped commits",
+		SetUpScript: []string{
+			"create table t (pk int primary key, v int);",
+			"call dolt_commit('-Am', 'created table t');",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-am', 'added 1');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'added 2');",
+			"insert into t values (3, 3);",
+			"call dolt_commit('-am', 'added 3');",
+			"insert into t values (4, 4);",
+			"call dolt_commit('-am', 'added 4');",
+			"insert into t values (5, -5);",
+			"call dolt_commit('-am', 'added 5');",
+			"insert into t values (6, 6);",
+			"call dolt_commit('-am', 'added 6');",
+			"insert into t values (7, 7);",
+			"call dolt_commit('-am', 'added 7');",
+			"insert into t values (8, 8);",
+			"call dolt_commit('-am', 'added 8');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_bisect('start', 'HEAD', 'HEAD~2');",
+				Expected: []sql.Row{{doltCommit, nil, "Bisecting: 0 revisions left to test after this (roughly 0 steps)"}},
+			},
+			{
+				Query: "call dolt_bisect('skip');",
+				Expected: []sql.Row{{nil, nil, messageValidator{regexp.MustCompile(
+					`^There are only skipped commits left to test.\nThe first bad commit could be any of:\n[0-9a-v]{32}\n[0-9a-v]{32}$`)}}},
+			},
+			{
+				Query:            "call dolt_bisect('reset');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "call dolt_bisect('start', 'HEAD~3', 'HEAD~4');",
+				Expected: []sql.Row{{nil, doltCommit, messageValidator{regexp.MustCompile(`^[0-9a-v]{32} is the first bad commit$`)}}},
+			},
+			{
+				Query:    "call dolt_bisect('reset');",
+				Expected: []sql.Row{{nil, nil, "ended the bisect session on branch 'main'"}},
+			},
+		},
+	},
+}
+
+var DoltRebaseScripts = []queries.ScriptTest{
+	{
+		Name: "rebase a branch onto another",
+		SetUpScript: []string{
+			"create table t (pk int primary key, v int);",
+			"call dolt_commit('-Am', 'created table t');",
+			"call dolt_checkout('-b', 'feature');",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-am', 'added 1');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'added 2');",
+			"call dolt_checkout('main');",
+			"insert into t values (10, 10);",
+			"call dolt_commit('-am', 'added 10');",
+			"call dolt_checkout('feature');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_rebase();",
+				ExpectedErrStr: "error: the commit to rebase onto is required",
+			},
+			{
+				Query:          "call dolt_rebase('--continue');",
+				ExpectedErrStr: "error: no rebase is in progress on branch 'feature'",
+			},
+			{
+				Query:    "call dolt_rebase('main');",
+				Expected: []sql.Row{{int64(0), "Successfully rebased and updated refs/heads/feature"}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {10, 10}},
+			},
+			{
+				Query:    "select message from dolt_log limit 4;",
+				Expected: []sql.Row{{"added 2"}, {"added 1"}, {"added 10"}, {"created table t"}},
+			},
+			{
+				Query:    "call dolt_rebase('main');",
+				Expected: []sql.Row{{int64(0), "Current branch feature is up to date."}},
+			},
+			{
+				Query:    "insert into t values (3, 3);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:          "call dolt_rebase('main');",
+				ExpectedErrStr: "cannot rebase with uncommitted changes",
+			},
+		},
+	},
+	{
+		Name: "interactive rebase",
+		SetUpScript: []string{
+			"create table t (pk int primary key, v int);",
+			"call dolt_commit('-Am', 'created table t');",
+			"call dolt_checkout('-b', 'feature');",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-am', 'added 1');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'added 2');",
+			"insert into t values (3, 3);",
+			"call dolt_commit('-am', 'added 3');",
+			"call dolt_checkout('main');",
+			"insert into t values (10, 10);",
+			"call dolt_commit('-am', 'added 10');",
+			"call dolt_checkout('feature');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "call dolt_rebase('-i', 'main');",
+				Expected: []sql.Row{{int64(1), messageValidator{regexp.MustCompile(
+					`^pick [0-9a-v]{32} added 1\npick [0-9a-v]{32} added 2\npick [0-9a-v]{32} added 3\n\n# Rebase feature onto [0-9a-v]{32} \(3 commands\)\n`)}}},
+			},
+			{
+				Query:          "call dolt_rebase('--edit-todo', 'edit HEAD');",
+				ExpectedErrStr: "error: line 1 of the rebase todo list: unknown action 'edit'",
+			},
+			{
+				Query: "call dolt_rebase('--edit-todo', 'pick HEAD~2\\nsquash HEAD~1\\nreword HEAD changed 3');",
+				Expected: []sql.Row{{int64(1), messageValidator{regexp.MustCompile(
+					`^pick [0-9a-v]{32} added 1\nsquash [0-9a-v]{32} added 2\nreword [0-9a-v]{32} changed 3\n\n# Rebase feature onto [0-9a-v]{32} \(3 commands\)\n`)}}},
+			},
+			{
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{int64(0), "Successfully rebased and updated refs/heads/feature"}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {3, 3}, {10, 10}},
+			},
+			{
+				Query:    "select message from dolt_log limit 3;",
+				Expected: []sql.Row{{"changed 3"}, {"added 1\n\nadded 2"}, {"added 10"}},
+			},
+		},
+	},
+	{
+		Name: "rebase with conflicts",
+		SetUpScript: []string{
+			"set @@dolt_allow_commit_conflicts = 1;",
+			"create table t (pk int primary key, v int);",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-Am', 'created table t');",
+			"call dolt_checkout('-b', 'feature');",
+			"update t set v = 2 where pk = 1;",
+			"call dolt_commit('-am', 'changed 1 on feature');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'added 2');",
+			"call dolt_checkout('main');",
+			"update t set v = 3 where pk = 1;",
+			"call dolt_commit('-am', 'changed 1 on main');",
+			"call dolt_checkout('feature');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_rebase('main');",
+				Expected: []sql.Row{{int64(1), messageValidator{regexp.MustCompile(`^error: could not apply [0-9a-v]{32} because of conflicts.\n`)}}},
+			},
+			{
+				Query:          "call dolt_rebase('main');",
+				ExpectedErrStr: "error: a rebase is already in progress on branch 'feature', use dolt_rebase('--continue') to continue it or dolt_rebase('--abort') to abort it",
+			},
+			{
+				Query:    "select `table`, num_conflicts from dolt_conflicts;",
+				Expected: []sql.Row{{"t", uint64(1)}},
+			},
+			{
+				Query:          "call dolt_rebase('--continue');",
+				ExpectedErrStr: "error: resolve the conflicts and constraint violations before continuing the rebase",
+			},
+			{
+				Query:    "update t set v = 4 where pk = 1;",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "delete from dolt_conflicts_t;",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:          "call dolt_rebase('--continue');",
+				ExpectedErrStr: "error: stage the resolved tables with dolt_add() before continuing the rebase",
+			},
+			{
+				Query:    "call dolt_add('t');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_rebase('--continue');",
+				Expected: []sql.Row{{int64(0), "Successfully rebased and updated refs/heads/feature"}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 4}, {2, 2}},
+			},
+			{
+				Query:    "select message from dolt_log limit 3;",
+				Expected: []sql.Row{{"added 2"}, {"changed 1 on feature"}, {"changed 1 on main"}},
+			},
+		},
+	},
+	{
+		Name: "abort a rebase",
+		SetUpScript: []string{
+			"set @@dolt_allow_commit_conflicts = 1;",
+			"create table t (pk int primary key, v int);",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-Am', 'created table t');",
+			"call dolt_checkout('-b', 'feature');",
+			"update t set v = 2 where pk = 1;",
+			"call dolt_commit('-am', 'changed 1 on feature');",
+			"call dolt_checkout('main');",
+			"update t set v = 3 where pk = 1;",
+			"call dolt_commit('-am', 'changed 1 on main');",
+			"call dolt_checkout('feature');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:            "call dolt_rebase('main');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "call dolt_rebase('--abort');",
+				Expected: []sql.Row{{int64(0), "Aborted the rebase of feature"}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1, 2}},
+			},
+			{
+				Query:    "select * from dolt_conflicts;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select message from dolt_log limit 1;",
+				Expected: []sql.Row{{"changed 1 on feature"}},
+			},
+		},
+	},
+}
+
+var DoltApplyPatchScripts = []queries.ScriptTest{
+	{
+		Name: "apply a patch produced by dolt_patch()",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 1), (2, 2), (3, 3);",
+			"call dolt_commit('-Am', 'created table t');",
+			"call dolt_checkout('-b', 'other');",
+			"update t set c = 20 where pk = 2;",
+			"delete from t where pk = 3;",
+			"insert into t values (4, 4);",
+			"call dolt_commit('-am', 'changed t');",
+			"call dolt_checkout('main');",
+			"set @patch = (select group_concat(statement order by statement_order separator '\\n') from dolt_patch('main', 'other'));",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_apply_patch(@patch);",
+				Expected: []sql.Row{{3, 0}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 1}, {2, 20}, {4, 4}},
+			},
+			{
+				Query:    "select * from dolt_patch_rejects;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "call dolt_apply_patch(@patch);",
+				Expected: []sql.Row{{1, 2}},
+			},
+			{
+				Query:    "select hunk, table_name, reason from dolt_patch_rejects order by hunk;",
+				Expected: []sql.Row{{int64(2), "t", "no row matches the statement"}, {int64(3), "t", "duplicate primary key given: [4]"}},
+			},
+			{
+				Query:          "call dolt_apply_patch('not a patch');",
+				ExpectedErrStr: "error: could not parse patch statement 1: syntax error at position 4 near 'not'",
+			},
+			{
+				Query:          "call dolt_apply_patch('insert into t values (5, 5); drop table t;');",
+				ExpectedErrStr: "error: patch statement 2 is not an INSERT, UPDATE or DELETE statement",
+			},
+			{
+				Query:          "call dolt_apply_patch('insert into t select * from t;');",
+				ExpectedErrStr: "error: patch statement 1 must insert a list of values",
+			},
+			{
+				Query:          "call dolt_apply_patch('delete from otherdb.t;');",
+				ExpectedErrStr: "error: patch statement 1 changes table 'otherdb.t' outside of database 'mydb'",
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 1}, {2, 20}, {4, 4}},
+			},
+		},
+	},
+	{
+		Name: "apply a JSON patch",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c varchar(20));",
+			"insert into t values (1, 'one'), (2, 'two');",
+			"call dolt_commit('-Am', 'created table t');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: `call dolt_apply_patch('[
+					{"table": "t", "type": "update", "key": {"pk": 1}, "before": {"pk": 1, "c": "one"}, "after": {"c": "uno"}},
+					{"table": "t", "type": "update", "key": {"pk": 2}, "before": {"pk": 2, "c": "deux"}, "after": {"c": "dos"}},
+					{"table": "t", "type": "insert", "key": {"pk": 3}, "after": {"pk": 3, "c": "tres"}},
+					{"table": "t", "type": "insert", "key": {"pk": 1}, "after": {"pk": 1, "c": "uno"}},
+					{"table": "t", "type": "delete", "key": {"pk": 2}, "before": {"pk": 2, "c": "two"}}
+				]');`,
+				Expected: []sql.Row{{3, 2}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, "uno"}, {3, "tres"}},
+			},
+			{
+				Query: "select hunk, table_name, reason from dolt_patch_rejects order by hunk;",
+				Expected: []sql.Row{
+					{int64(2), "t", "row does not match the patch's before image"},
+					{int64(4), "t", "row already exists"},
+				},
+			},
+			{
+				Query:          `call dolt_apply_patch('[{"table": "t", "type": "upsert", "key": {"pk": 1}}]');`,
+				ExpectedErrStr: "error: JSON patch hunk 1 has unknown type 'upsert'",
+			},
+		},
+	},
+	{
+		Name: "apply the patch between two revisions",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 1), (2, 2), (3, 3);",
+			"call dolt_commit('-Am', 'created table t');",
+			"call dolt_branch('other');",
+			"call dolt_checkout('-b', 'feature');",
+			"update t set c = 10 where pk = 1;",
+			"delete from t where pk = 3;",
+			"insert into t values (5, 5);",
+			"call dolt_commit('-am', 'changed t on feature');",
+			"call dolt_checkout('main');",
+			"insert into t values (4, 4);",
+			"call dolt_commit('-am', 'added a row on main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_apply_patch('feature~1', 'feature');",
+				Expected: []sql.Row{{3, 0}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 10}, {2, 2}, {4, 4}, {5, 5}},
+			},
+			{
+				Query:    "select message = concat('Apply patch from feature~1 to feature\n\nPatch-From: ', hashof('feature~1'), '\nPatch-To: ', hashof('feature')) from dolt_log limit 1;",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:    "select count(*) from dolt_status;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:          "call dolt_apply_patch('feature~1', 'feature');",
+				ExpectedErrStr: "error: the patch from feature~1 to feature makes no changes to branch 'main'",
+			},
+			{
+				Query:    "call dolt_apply_patch('--branch', 'other', '-m', 'patched other', 'feature~1', 'feature');",
+				Expected: []sql.Row{{3, 0}},
+			},
+			{
+				Query:    "select * from `mydb/other`.t order by pk;",
+				Expected: []sql.Row{{1, 10}, {2, 2}, {5, 5}},
+			},
+			{
+				Query:    "select message like 'patched other\n\nPatch-From: %' from `mydb/other`.dolt_log limit 1;",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:    "select active_branch();",
+				Expected: []sql.Row{{"main"}},
+			},
+			{
+				Query:          "call dolt_apply_patch('--branch', 'nope', 'feature~1', 'feature');",
+				ExpectedErrStr: "branch not found: nope",
+			},
+		},
+	},
+	{
+		Name: "a patch between two revisions that conflicts is not applied",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 1), (2, 2);",
+			"call dolt_commit('-Am', 'created table t');",
+			"call dolt_checkout('-b', 'feature');",
+			"update t set c = 10 where pk = 1;",
+			"insert into t values (3, 3);",
+			"call dolt_commit('-am', 'changed t on feature');",
+			"call dolt_checkout('main');",
+			"update t set c = 100 where pk = 1;",
+			"call dolt_commit('-am', 'changed t on main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_apply_patch('main~1', 'feature');",
+				ExpectedErrStr: "error: the patch from main~1 to feature conflicts with branch 'main' in tables: t; no changes were made",
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 100}, {2, 2}},
+			},
+			{
+				Query:    "select count(*) from dolt_conflicts;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select message from dolt_log limit 1;",
+				Expected: []sql.Row{{"changed t on main"}},
+			},
+			{
+				Query:    "insert into t values (4, 4);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:          "call dolt_apply_patch('main~1', 'feature');",
+				ExpectedErrStr: "error: branch 'main' has uncommitted changes; commit or reset them before applying a patch",
+			},
+		},
+	},
+}
+
+var DoltCopyTableScripts = []queries.ScriptTest{
+	{
+		Name: "copy a table to another branch",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c varchar(10), e enum('a','b'));",
+			"insert into t values (1, 'one', 'b');",
+			"call dolt_commit('-Am', 'created table t');",
+			"call dolt_branch('other', 'HEAD');",
+			"insert into t values (2, 'two', 'a');",
+			"call dolt_commit('-am', 'added a row');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "call dolt_copy_table('main.t', 'other.t');",
+				ExpectedErrStr: "table t already exists on the destination branch, use --force to overwrite it",
+			},
+			{
+				Query:    "call dolt_copy_table('main.t', 'other.t', '--force');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select * from `mydb/other`.t order by pk;",
+				Expected: []sql.Row{{1, "one", uint64(2)}, {2, "two", uint64(1)}},
+			},
+			{
+				Query:    "select table_name, staged, status from `mydb/other`.dolt_status;",
+				Expected: []sql.Row{{"t", true, "modified"}},
+			},
+			{
+				Query:    "select * from t as of 'other' order by pk;",
+				Expected: []sql.Row{{1, "one", uint64(2)}},
+			},
+		},
+	},
+	{
+		Name: "copy a table from a commit",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"insert into t values (1), (2);",
+			"call dolt_commit('-Am', 'created table t');",
+			"drop table t;",
+			"call dolt_commit('-Am', 'dropped table t');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_copy_table('HEAD~1.t', 't');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				Query:    "select table_name, staged, status from dolt_status;",
+				Expected: []sql.Row{{"t", true, "new table"}},
+			},
+			{
+				Query:          "call dolt_copy_table('HEAD~1.t', 'other_name');",
+				ExpectedErrStr: "cannot copy table t to a table with a different name, other_name",
+			},
+			{
+				Query:          "call dolt_copy_table('main.missing', 'missing');",
+				ExpectedErrStr: "table not found: missing",
+			},
+		},
+	},
+}
+
+// testSchemaBundle is the schema bundle exported for a table t and a view v in DoltSchemaBundleScripts
+const testSchemaBundle = "/* dolt schema bundle v1 */\n" +
+	"-- collation: utf8mb4_0900_bin\n" +
+	"-- table: t\n" +
+	"CREATE TABLE `t` (\n" +
+	"  `pk` int NOT NULL,\n" +
+	"  `c` int,\n" +
+	"  PRIMARY KEY (`pk`),\n" +
+	"  KEY `idx` (`c`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin;\n" +
+	"-- view: v\n" +
+	"CREATE VIEW v AS SELECT c FROM t;\n"
+
+var DoltSchemaBundleScripts = []queries.ScriptTest{
+	{
+		Name: "export a schema bundle",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int, key idx (c));",
+			"CREATE VIEW v AS SELECT c FROM t;",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-Am', 'created table t and view v');",
+			"call dolt_branch('other');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_export_schema('other');",
+				Expected: []sql.Row{{testSchemaBundle}},
+			},
+			{
+				Query:    "call dolt_export_schema();",
+				Expected: []sql.Row{{testSchemaBundle}},
+			},
+			{
+				Query:          "call dolt_export_schema('main', 'other');",
+				ExpectedErrStr: "error: invalid usage",
+			},
+		},
+	},
+	{
+		Name: "import a schema bundle",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int, d int);",
+			"create table extra (pk int primary key);",
+			"insert into t values (1, 1, 1);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_import_schema('" + testSchemaBundle + "', '--dry-run');",
+				Expected: []sql.Row{{1, 1, 0, 0}},
+			},
+			{
+				Query:    "select * from t;",
+				Expected: []sql.Row{{1, 1, 1}},
+			},
+			{
+				Query:    "call dolt_import_schema('" + testSchemaBundle + "');",
+				Expected: []sql.Row{{1, 1, 0, 0}},
+			},
+			{
+				Query: "show create table t;",
+				Expected: []sql.Row{{"t", "CREATE TABLE `t` (\n" +
+					"  `pk` int NOT NULL,\n" +
+					"  `c` int,\n" +
+					"  PRIMARY KEY (`pk`),\n" +
+					"  KEY `idx` (`c`)\n" +
+					") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin"}},
+			},
+			{
+				Query:    "select * from v;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "call dolt_import_schema('" + testSchemaBundle + "');",
+				Expected: []sql.Row{{0, 0, 0, 2}},
+			},
+			{
+				Query:    "call dolt_import_schema('" + testSchemaBundle + "', '--prune');",
+				Expected: []sql.Row{{0, 0, 1, 2}},
+			},
+			{
+				Query:    "show tables;",
+				Expected: []sql.Row{{"myview"}, {"t"}, {"v"}},
+			},
+			{
+				Query:          "call dolt_import_schema('create table t2 (pk int primary key);');",
+				ExpectedErrStr: "error: not a schema bundle exported by dolt_export_schema()",
+			},
+			{
+				Query:          "call dolt_import_schema('/* dolt schema bundle v1 */\n-- table: t2\ndelete from t;');",
+				ExpectedErrStr: "error: the definition of table 't2' is not a CREATE TABLE statement",
+			},
+			{
+				Query:          "call dolt_import_schema('/* dolt schema bundle v1 */\n-- table: t2\ncreate table t2 (pk int primary key); drop table t;');",
+				ExpectedErrStr: "error: the definition of table 't2' has more than one statement",
+			},
+			{
+				Query:          "call dolt_import_schema('/* dolt schema bundle v1 */\n-- table: t2\ncreate table otherdb.t2 (pk int primary key);');",
+				ExpectedErrStr: "error: the definition of table 't2' creates it outside of database 'mydb'",
+			},
+		},
+	},
+}
+
+var DoltViolationOriginsScripts = []queries.ScriptTest{
+	{
+		Name: "violations introduced by a merge",
+		SetUpScript: []string{
+			"set dolt_force_transaction_commit = on;",
+			"CREATE table parent (pk int PRIMARY KEY);",
+			"CREATE table child (pk int PRIMARY KEY, parent_fk int, FOREIGN KEY (parent_fk) REFERENCES parent(pk));",
+			"CALL DOLT_ADD('.')",
+			"INSERT INTO parent VALUES (1), (2);",
+			"CALL DOLT_COMMIT('-am', 'setup');",
+			"CALL DOLT_BRANCH('right');",
+			"DELETE FROM parent where pk = 2;",
+			"CALL DOLT_COMMIT('-am', 'delete parent 2');",
+			"CALL DOLT_CHECKOUT('right');",
+			"INSERT INTO child VALUES (1, 1);",
+			"CALL DOLT_COMMIT('-am', 'insert child of parent 1');",
+			"INSERT INTO child VALUES (2, 2);",
+			"CALL DOLT_COMMIT('--author', 'Jane Doe <jane@example.com>', '-am', 'insert child of parent 2');",
+			"INSERT INTO child VALUES (3, 1);",
+			"CALL DOLT_COMMIT('-am', 'insert another child of parent 1');",
+			"CALL DOLT_CHECKOUT('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('right');",
+				Expected: []sql.Row{{"", 0, 1}},
+			},
+			{
+				Query:    "SELECT `table`, from_root_ish = hashof('right'), source_branch, num_violations FROM dolt_constraint_violation_sources;",
+				Expected: []sql.Row{{"child", true, "right", uint64(1)}},
+			},
+			{
+				Query:    "SELECT violation_type, violation_key, source_branch, origin_committer, origin_message FROM dolt_violation_origins('child');",
+				Expected: []sql.Row{{"foreign key", "( 2 )", "right", "Jane Doe", "insert child of parent 2"}},
+			},
+			{
+				Query:    "SELECT origin_commit = hashof('right~1') FROM dolt_violation_origins('child');",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:    "SELECT * FROM dolt_violation_origins('parent');",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:       "SELECT * FROM dolt_violation_origins('doesnotexist');",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+		},
+	},
+	{
+		Name: "violations found by verifying constraints",
+		SetUpScript: []string{
+			"set dolt_force_transaction_commit = on;",
+			"CREATE table parent (pk int PRIMARY KEY);",
+			"CREATE table child (pk int PRIMARY KEY, parent_fk int, FOREIGN KEY (parent_fk) REFERENCES parent(pk));",
+			"INSERT INTO parent VALUES (1);",
+			"CALL DOLT_ADD('.')",
+			"CALL DOLT_COMMIT('-am', 'setup');",
+			"SET FOREIGN_KEY_CHECKS = 0;",
+			"INSERT INTO child VALUES (1, 2);",
+			"CALL DOLT_COMMIT('-am', 'insert orphan');",
+			"INSERT INTO child VALUES (2, 1);",
+			"CALL DOLT_COMMIT('-am', 'insert child');",
+			"SET FOREIGN_KEY_CHECKS = 1;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_VERIFY_CONSTRAINTS('--all');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "SELECT `table`, from_root_ish = hashof('HEAD'), source_branch, num_violations FROM dolt_constraint_violation_sources;",
+				Expected: []sql.Row{{"child", true, "main", uint64(1)}},
+			},
+			{
+				Query:    "SELECT violation_key, source_branch, origin_commit = hashof('HEAD~1'), origin_message FROM dolt_violation_origins('child');",
+				Expected: []sql.Row{{"( 1 )", "main", true, "insert orphan"}},
+			},
+		},
+	},
+}
+
+var DoltLineageScripts = []queries.ScriptTest{
+	{
+		Name: "lineage of a cell",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c1 varchar(20), c2 int);",
+			"CALL DOLT_ADD('.')",
+			"CALL DOLT_COMMIT('-am', 'create table');",
+			"INSERT INTO t VALUES (1, 'a', 10), (2, 'x', 20);",
+			"CALL DOLT_COMMIT('-am', 'insert rows');",
+			"UPDATE t SET c1 = 'b' WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'update c1');",
+			"UPDATE t SET c2 = 11 WHERE pk = 1;",
+			"UPDATE t SET c1 = 'y' WHERE pk = 2;",
+			"CALL DOLT_COMMIT('-am', 'update other cells');",
+			"UPDATE t SET c1 = NULL WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'clear c1');",
+			"DELETE FROM t WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'delete row');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "SELECT message, diff_type, from_value, to_value FROM dolt_lineage('t', 1, 'c1');",
+				Expected: []sql.Row{
+					{"delete row", "removed", nil, nil},
+					{"clear c1", "modified", "b", nil},
+					{"update c1", "modified", "a", "b"},
+					{"insert rows", "added", nil, "a"},
+				},
+			},
+			{
+				Query: "SELECT message, diff_type, from_value, to_value FROM dolt_lineage('t', 2, 'c1');",
+				Expected: []sql.Row{
+					{"update other cells", "modified", "x", "y"},
+					{"insert rows", "added", nil, "x"},
+				},
+			},
+			{
+				Query:    "SELECT commit_hash = hashof('HEAD~2'), committer, email FROM dolt_lineage('t', 1, 'c2') WHERE diff_type = 'modified';",
+				Expected: []sql.Row{{true, "root", "root@localhost"}},
+			},
+			{
+				Query:    "SELECT * FROM dolt_lineage('t', 3, 'c1');",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:       "SELECT * FROM dolt_lineage('t', 1, 'c3');",
+				ExpectedErr: sql.ErrTableColumnNotFound,
+			},
+			{
+				Query:       "SELECT * FROM dolt_lineage('doesnotexist', 1, 'c1');",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+			{
+				Query:       "SELECT * FROM dolt_lineage('t', 'c1');",
+				ExpectedErr: sql.ErrInvalidArgumentNumber,
+			},
+		},
+	},
+	{
+		Name: "lineage of a cell across a merge",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk1 int, pk2 varchar(10), c1 int, PRIMARY KEY (pk1, pk2));",
+			"INSERT INTO t VALUES (1, 'a', 1);",
+			"CALL DOLT_ADD('.')",
+			"CALL DOLT_COMMIT('-am', 'create table');",
+			"CALL DOLT_CHECKOUT('-b', 'other');",
+			"UPDATE t SET c1 = 2 WHERE pk1 = 1;",
+			"CALL DOLT_COMMIT('-am', 'update on other');",
+			"CALL DOLT_CHECKOUT('main');",
+			"INSERT INTO t VALUES (2, 'b', 1);",
+			"CALL DOLT_COMMIT('-am', 'insert on main');",
+			"CALL DOLT_MERGE('other', '--no-ff', '-m', 'merge other');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "SELECT message, diff_type, from_value, to_value FROM dolt_lineage('t', 1, 'a', 'c1');",
+				Expected: []sql.Row{
+					{"update on other", "modified", "1", "2"},
+					{"create table", "added", nil, "1"},
+				},
+			},
+		},
+	},
+}
+
+var DoltBlameScripts = []queries.ScriptTest{
+	{
+		Name: "blame of rows and cells",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c1 varchar(20), c2 int);",
+			"CALL DOLT_ADD('.')",
+			"CALL DOLT_COMMIT('-am', 'create table');",
+			"INSERT INTO t VALUES (1, 'a', 10), (2, 'b', 20), (3, 'c', 30);",
+			"CALL DOLT_COMMIT('-am', 'insert rows');",
+			"UPDATE t SET c1 = 'bb' WHERE pk = 2;",
+			"CALL DOLT_COMMIT('-am', 'update c1');",
+			"CREATE TABLE other (pk int PRIMARY KEY);",
+			"CALL DOLT_ADD('.')",
+			"CALL DOLT_COMMIT('-am', 'create other table');",
+			"UPDATE t SET c2 = 31 WHERE pk = 3;",
+			"DELETE FROM t WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'update c2');",
+			"UPDATE t SET c1 = 'uncommitted' WHERE pk = 3;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "SELECT pk, message FROM dolt_blame('t');",
+				Expected: []sql.Row{
+					{2, "update c1"},
+					{3, "update c2"},
+				},
+			},
+			{
+				Query: "SELECT pk, message FROM dolt_blame('t', 'c1');",
+				Expected: []sql.Row{
+					{2, "update c1"},
+					{3, "insert rows"},
+				},
+			},
+			{
+				Query: "SELECT pk, message FROM dolt_blame('t', 'pk');",
+				Expected: []sql.Row{
+					{2, "insert rows"},
+					{3, "insert rows"},
+				},
+			},
+			{
+				Query:    "SELECT commit = hashof('HEAD'), committer, email FROM dolt_blame('T', 'C2') WHERE pk = 3;",
+				Expected: []sql.Row{{true, "root", "root@localhost"}},
+			},
+			{
+				Query:    "SELECT count(*) FROM dolt_blame('other');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:       "SELECT * FROM dolt_blame('t', 'c3');",
+				ExpectedErr: sql.ErrTableColumnNotFound,
+			},
+			{
+				Query:       "SELECT * FROM dolt_blame('doesnotexist');",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+			{
+				Query:       "SELECT * FROM dolt_blame('t', 'c1', 'c2');",
+				ExpectedErr: sql.ErrInvalidArgumentNumber,
+			},
+		},
+	},
+	{
+		Name: "blame across a merge",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk1 int, pk2 varchar(10), c1 int, PRIMARY KEY (pk1, pk2));",
+			"INSERT INTO t VALUES (1, 'a', 1);",
+			"CALL DOLT_ADD('.')",
+			"CALL DOLT_COMMIT('-am', 'create table');",
+			"CALL DOLT_CHECKOUT('-b', 'other');",
+			"UPDATE t SET c1 = 2 WHERE pk1 = 1;",
+			"CALL DOLT_COMMIT('-am', 'update on other');",
+			"CALL DOLT_CHECKOUT('main');",
+			"INSERT INTO t VALUES (2, 'b', 1);",
+			"CALL DOLT_COMMIT('-am', 'insert on main');",
+			"CALL DOLT_MERGE('other', '--no-ff', '-m', 'merge other');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "SELECT pk1, pk2, message FROM dolt_blame('t');",
+				Expected: []sql.Row{
+					{1, "a", "update on other"},
+					{2, "b", "insert on main"},
+				},
+			},
+		},
+	},
+	{
+		Name: "blame across schema changes",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c1 int);",
+			"INSERT INTO t VALUES (1, 1), (2, 2);",
+			"CALL DOLT_ADD('.')",
+			"CALL DOLT_COMMIT('-am', 'create table');",
+			"ALTER TABLE t ADD COLUMN c2 int;",
+			"CALL DOLT_COMMIT('-am', 'add nullable column');",
+			"UPDATE t SET c2 = 2 WHERE pk = 2;",
+			"CALL DOLT_COMMIT('-am', 'set c2');",
+			"ALTER TABLE t ADD COLUMN c3 int NOT NULL DEFAULT 0;",
+			"CALL DOLT_COMMIT('-am', 'add column with default');",
+			"CREATE TABLE keyless (c1 int);",
+			"CALL DOLT_ADD('.')",
+			"CALL DOLT_COMMIT('-am', 'create keyless table');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "SELECT pk, message FROM dolt_blame('t');",
+				Expected: []sql.Row{
+					{1, "add column with default"},
+					{2, "add column with default"},
+				},
+			},
+			{
+				Query: "SELECT pk, message FROM dolt_blame('t', 'c1');",
+				Expected: []sql.Row{
+					{1, "create table"},
+					{2, "create table"},
+				},
+			},
+			{
+				Query: "SELECT pk, message FROM dolt_blame('t', 'c2');",
+				Expected: []sql.Row{
+					{1, "create table"},
+					{2, "set c2"},
+				},
+			},
+			{
+				Query:          "SELECT * FROM dolt_blame('keyless');",
+				ExpectedErrStr: "dolt_blame requires a table with a primary key, but table 'keyless' has none",
+			},
+		},
+	},
+}
+
+var DoltReplicationStatusScripts = []queries.ScriptTest{
+	{
+		Name:        "dolt_replication_status() without replication",
+		SetUpScript: []string{},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "SELECT * FROM dolt_replication_status();",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:       "SELECT * FROM dolt_replication_status('mydb');",
+				ExpectedErr: sql.ErrInvalidArgumentNumber,
+			},
+		},
+	},
+}
+
+var DoltVectorIndexScripts = []queries.ScriptTest{
+	{
+		Name: "vector indexes are searched and maintained",
+		SetUpScript: []string{
+			"create table docs (id int primary key, emb json);",
+			"insert into docs values (1, '[0,0]'), (2, '[1,0]'), (3, '[10,10]'), (4, '[11,10]'), (6, null);",
+			"call dolt_add('.');",
+			"call dolt_commit('-m', 'add docs');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select vec_distance('[0,0]', '[3,4]');",
+				Expected: []sql.Row{{5.0}},
+			},
+			{
+				Query:    "call dolt_vector_index('docs', 'emb', '2');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select table_name, column_name, list_id from dolt_vector_indexes order by list_id;",
+				Expected: []sql.Row{{"docs", "emb", int32(0)}, {"docs", "emb", int32(1)}},
+			},
+			{
+				Query:    "select index_column, list_id, id from dolt_vector_lists_docs order by id;",
+				Expected: []sql.Row{{"emb", int32(0), 1}, {"emb", int32(0), 2}, {"emb", int32(1), 3}, {"emb", int32(1), 4}},
+			},
+			{
+				Query:    "select id, distance from dolt_vector_search('docs', 'emb', '[0,0]', 2);",
+				Expected: []sql.Row{{1, 0.0}, {2, 1.0}},
+			},
+			{
+				// limited queries ordered by distance search the index, which doesn't list rows without an embedding
+				Query:    "select id from docs order by vec_distance(emb, '[0,0]') limit 2;",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				Query:    "select d.id from docs d order by vec_distance('[10,10]', d.emb) limit 1;",
+				Expected: []sql.Row{{3}},
+			},
+			{
+				Query:    "select id from docs order by vec_distance(emb, '[0,0]');",
+				Expected: []sql.Row{{6}, {1}, {2}, {3}, {4}},
+			},
+			{
+				Query:    "select id, distance from dolt_vector_search('docs', 'emb', '[10,10]', 3, 1);",
+				Expected: []sql.Row{{3, 0.0}, {4, 1.0}},
+			},
+			{
+				Query:    "insert into docs values (5, '[0,1]');",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "select list_id from dolt_vector_lists_docs where id = 5;",
+				Expected: []sql.Row{{int32(0)}},
+			},
+			{
+				Query:    "update docs set emb = '[10,11]' where id = 5;",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "select list_id from dolt_vector_lists_docs where id = 5;",
+				Expected: []sql.Row{{int32(1)}},
+			},
+			{
+				Query:    "delete from docs where id = 5;",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "select count(*) from dolt_vector_lists_docs where id = 5;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select table_name, staged, status from dolt_status order by table_name;",
+				Expected: []sql.Row{{"dolt_vector_indexes", false, "new table"}, {"dolt_vector_lists_docs", false, "new table"}},
+			},
+			{
+				Query:    "call dolt_vector_index('-d', 'docs', 'emb');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "select count(*) from dolt_status;",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "vector index errors",
+		SetUpScript: []string{
+			"create table docs (id int primary key, emb json);",
+			"insert into docs values (1, '[0,0]');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:       "call dolt_vector_index('docs', 'nope');",
+				ExpectedErr: sql.ErrTableColumnNotFound,
+			},
+			{
+				Query:          "call dolt_vector_index('-d', 'docs', 'emb');",
+				ExpectedErrStr: "error: column 'emb' of table 'docs' has no vector index",
+			},
+			{
+				Query:          "select * from dolt_vector_search('docs', 'emb', '[0,0]', 1);",
+				ExpectedErrStr: "column 'emb' of table 'docs' has no vector index",
+			},
+			{
+				Query:          "select vec_distance('[1,2]', '[1,2,3]');",
+				ExpectedErrStr: "cannot compare embeddings with 2 and 3 dimensions",
+			},
+		},
+	},
+}
+
+var DoltTableMetadataFunctionScripts = []queries.ScriptTest{
+	{
+		Name: "dolt_table_last_commit and dolt_table_status",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'create t');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'insert into t');",
+			"create table u (pk int primary key);",
+			"call dolt_commit('-Am', 'create u');",
+			"insert into t values (2);",
+			"call dolt_add('t');",
+			"insert into t values (3);",
+			"create table w (pk int primary key);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select dolt_table_last_commit('t') = hashof('HEAD~1');",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:    "select dolt_table_last_commit('U') = hashof('HEAD');",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:    "select dolt_table_last_commit('w');",
+				Expected: []sql.Row{{nil}},
+			},
+			{
+				Query:    "select dolt_table_status('t'), dolt_table_status('u'), dolt_table_status('w'), dolt_table_status('nope');",
+				Expected: []sql.Row{{"staged, unstaged", "clean", "unstaged", nil}},
+			},
+			{
+				Query:    "select table_name, dolt_table_status(table_name) from information_schema.tables where table_schema = database() order by table_name;",
+				Expected: []sql.Row{{"myview", nil}, {"t", "staged, unstaged"}, {"u", "clean"}, {"w", "unstaged"}},
+			},
+		},
+	},
+}
+
+var DoltInfoSchemaDoltTablesScripts = []queries.ScriptTest{
+	{
+		Name: "information_schema.dolt_tables",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'create t');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'insert into t');",
+			"create table u (pk int primary key);",
+			"call dolt_commit('-Am', 'create u');",
+			"call dolt_checkout('-b', 'feature');",
+			"insert into t values (2);",
+			"call dolt_add('t');",
+			"create table w (pk int primary key);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select table_name, branch, head_commit = hashof('HEAD'), last_commit = dolt_table_last_commit(table_name), status from information_schema.dolt_tables where table_schema = database() order by table_name;",
+				Expected: []sql.Row{
+					{"t", "feature", true, true, "staged"},
+					{"u", "feature", true, true, "clean"},
+					{"w", "feature", true, nil, "unstaged"},
+				},
+			},
+			{
+				Query:    "select t.table_name, d.last_commit = hashof('HEAD~1') from information_schema.tables t join information_schema.dolt_tables d using (table_schema, table_name) where t.table_schema = database() and t.table_name = 't';",
+				Expected: []sql.Row{{"t", true}},
+			},
+			{
+				Query:    "select count(*) from information_schema.tables where table_schema = 'information_schema' and table_name = 'dolt_tables';",
+				Expected: []sql.Row{{1}},
+			},
+		},
+	},
+}
+
+var DoltReset = []queries.ScriptTest{
+	{
+		Name: "CALL DOLT_RESET('--hard') should reset the merge state after uncommitted merge",
+		SetUpScript: []string{
+			"CREATE TABLE test1 (pk int NOT NULL, c1 int, c2 int, PRIMARY KEY (pk));",
+			"CALL DOLT_ADD('.')",
+			"INSERT INTO test1 values (0,1,1);",
+			"CALL DOLT_COMMIT('-am', 'added table')",
+
+			"CALL DOLT_CHECKOUT('-b', 'merge_branch');",
+			"UPDATE test1 set c1 = 2;",
+			"CALL DOLT_COMMIT('-am', 'update pk 0 = 2,1 to test1');",
+
+			"CALL DOLT_CHECKOUT('main');",
+			"UPDATE test1 set c2 = 2;",
+			"CALL DOLT_COMMIT('-am', 'update pk 0 = 1,2 to test1');",
+
+			"CALL DOLT_MERGE('merge_branch');",
+
+			"CALL DOLT_RESET('--hard');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "CALL DOLT_MERGE('--abort')",
+				ExpectedErrStr: "fatal: There is no merge to abort",
+			},
+		},
+	},
+	{
+		Name: "CALL DOLT_RESET('--hard') should reset the merge state after conflicting merge",
+		SetUpScript: []string{
+			"SET dolt_allow_commit_conflicts = on",
+			"CREATE TABLE test1 (pk int NOT NULL, c1 int, c2 int, PRIMARY KEY (pk));",
+			"CALL DOLT_ADD('.')",
+			"INSERT INTO test1 values (0,1,1);",
+			"CALL DOLT_COMMIT('-am', 'added table')",
+
+			"CALL DOLT_CHECKOUT('-b', 'merge_branch');",
+			"UPDATE test1 set c1 = 2, c2 = 2;",
+			"CALL DOLT_COMMIT('-am', 'update pk 0 = 2,2 to test1');",
+
+			"CALL DOLT_CHECKOUT('main');",
 			"UPDATE test1 set c1 = 3, c2 = 3;",
 			"CALL DOLT_COMMIT('-am', 'update pk 0 = 3,3 to test1');",
 
@@ -3750,34 +5702,115 @@ var LogTableFunctionScriptTests = []queries.ScriptTest{
 			{
 				Query: "select message from dolt_log('--tables', 'test2');",
 				Expected: []sql.Row{
-					{"created table test2 [2M]"},
+					{"created table test2 [2M]"},
+				},
+			},
+			{
+				Query: "select message from dolt_log('--tables', 'test3')",
+				Expected: []sql.Row{
+					{"dropped table test3 [5M]"},
+					{"created table test3 [2TB]"},
+				},
+			},
+			{
+				Query: "select message from dolt_log('--tables', 'test,test2');",
+				Expected: []sql.Row{
+					{"inserted 2 into test [6M]"},
+					{"merged test-branch [4M]"},
+					{"inserted 1 into test [3M]"},
+					{"inserted 0 into test [1TB]"},
+					{"created table test2 [2M]"},
+					{"created table test [1M]"},
+				},
+			},
+			{
+				Query: "select message from dolt_log('test-branch', '--tables', 'test');",
+				Expected: []sql.Row{
+					{"inserted 0 into test [1TB]"},
+					{"created table test [1M]"},
+				},
+			},
+		},
+	},
+	{
+		Name: "filtering by column",
+		SetUpScript: []string{
+			"create table t (pk int primary key, a int, b int)",
+			"create table k (a int, b int)",
+			"call dolt_add('.')",
+			"call dolt_commit('-m', 'created tables')",
+			"insert into t values (1, 1, 1), (2, 2, 2)",
+			"call dolt_commit('-am', 'inserted into t')",
+			"update t set a = 10 where pk = 1",
+			"call dolt_commit('-am', 'updated t.a')",
+			"update t set b = 20 where pk = 2",
+			"call dolt_commit('-am', 'updated t.b')",
+			"update t set b = 20 where pk = 2",
+			"call dolt_commit('--allow-empty', '-am', 'updated nothing')",
+			"delete from t where pk = 2",
+			"call dolt_commit('-am', 'deleted from t')",
+			"alter table t add column c int",
+			"call dolt_commit('-am', 'added t.c')",
+			"alter table t modify column b bigint",
+			"call dolt_commit('-am', 'changed t.b')",
+			"insert into k values (1, 1)",
+			"call dolt_commit('-am', 'inserted into k')",
+			"update k set b = 2",
+			"call dolt_commit('-am', 'updated k.b')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select message from dolt_log('--tables', 't', '--columns', 'a');",
+				Expected: []sql.Row{
+					{"deleted from t"},
+					{"updated t.a"},
+					{"inserted into t"},
+					{"created tables"},
+				},
+			},
+			{
+				Query: "select message from dolt_log('--tables', 't', '--columns', 'B');",
+				Expected: []sql.Row{
+					{"changed t.b"},
+					{"deleted from t"},
+					{"updated t.b"},
+					{"inserted into t"},
+					{"created tables"},
 				},
 			},
 			{
-				Query: "select message from dolt_log('--tables', 'test3')",
+				Query: "select message from dolt_log('--tables', 't', '--columns', 'c');",
 				Expected: []sql.Row{
-					{"dropped table test3 [5M]"},
-					{"created table test3 [2TB]"},
+					{"added t.c"},
 				},
 			},
 			{
-				Query: "select message from dolt_log('--tables', 'test,test2');",
+				Query: "select message from dolt_log('--tables', 't', '--columns', 'pk');",
 				Expected: []sql.Row{
-					{"inserted 2 into test [6M]"},
-					{"merged test-branch [4M]"},
-					{"inserted 1 into test [3M]"},
-					{"inserted 0 into test [1TB]"},
-					{"created table test2 [2M]"},
-					{"created table test [1M]"},
+					{"deleted from t"},
+					{"inserted into t"},
+					{"created tables"},
 				},
 			},
 			{
-				Query: "select message from dolt_log('test-branch', '--tables', 'test');",
+				Query: "select message from dolt_log('--tables', 't,k', '--columns', 'a,c');",
 				Expected: []sql.Row{
-					{"inserted 0 into test [1TB]"},
-					{"created table test [1M]"},
+					{"inserted into k"},
+					{"added t.c"},
+					{"deleted from t"},
+					{"updated t.a"},
+					{"inserted into t"},
+					{"created tables"},
 				},
 			},
+			{
+				Query:    "select message from dolt_log('--tables', 't', '--columns', 'nosuchcolumn');",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:       "select message from dolt_log('--columns', 'a');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
 		},
 	},
 	{
@@ -3867,6 +5900,151 @@ var LogTableFunctionScriptTests = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "filtering by author and date",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_add('.');",
+			"call dolt_commit('-m', 'creating table t', '--author', 'John Doe <john@doe.com>', '--date', '2022-08-06T12:00:00');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'inserting 1', '--author', 'Jane Doe <jane@doe.com>', '--date', '2022-08-07T12:00:00');",
+			"insert into t values (2);",
+			"call dolt_commit('-am', 'inserting 2', '--author', 'John Doe <john@doe.com>', '--date', '2022-08-08T12:00:00');",
+			"call dolt_checkout('-b', 'branch1');",
+			"insert into t values (3);",
+			"call dolt_commit('-am', 'inserting 3', '--author', 'Jane Doe <jane@doe.com>', '--date', '2022-08-09T12:00:00');",
+			"call dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "SELECT message from dolt_log('--author', 'John');",
+				Expected: []sql.Row{{"inserting 2"}, {"creating table t"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('--author', 'jane@doe.com');",
+				Expected: []sql.Row{{"inserting 1"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('branch1', '--author', '^Jane');",
+				Expected: []sql.Row{{"inserting 3"}, {"inserting 1"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('--since', '2022-08-07', '--until', '2022-08-09');",
+				Expected: []sql.Row{{"inserting 2"}, {"inserting 1"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('--until', '2022-08-07T12:00:00');",
+				Expected: []sql.Row{{"inserting 1"}, {"creating table t"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('--since', '2022-08-07', '--until', '2022-08-08', '--author', 'Doe');",
+				Expected: []sql.Row{{"inserting 1"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('main..branch1', '--author', 'Jane');",
+				Expected: []sql.Row{{"inserting 3"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('--author', 'Nobody');",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "SELECT message from dolt_log where committer = 'John Doe';",
+				Expected: []sql.Row{{"inserting 2"}, {"creating table t"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log where email = 'jane@doe.com';",
+				Expected: []sql.Row{{"inserting 1"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log where date >= '2022-08-07' and date < '2022-08-08';",
+				Expected: []sql.Row{{"inserting 1"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log where date > '2022-08-07T12:00:00' and date <= '2022-08-08T12:00:00';",
+				Expected: []sql.Row{{"inserting 2"}},
+			},
+			{
+				Query:       "SELECT * from dolt_log('--author', '(');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:       "SELECT * from dolt_log('--since', 'yesterday');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+		},
+	},
+	{
+		Name: "graph and pretty formats",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_add('.');",
+			"call dolt_commit('-m', 'creating table t', '--author', 'John Doe <john@doe.com>', '--date', '2022-08-06T12:00:00');",
+			"call dolt_checkout('-b', 'branch1');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'inserting 1', '--author', 'Jane Doe <jane@doe.com>', '--date', '2022-08-07T12:00:00');",
+			"call dolt_checkout('main');",
+			"insert into t values (2);",
+			"call dolt_commit('-am', 'inserting 2', '--author', 'John Doe <john@doe.com>', '--date', '2022-08-08T12:00:00');",
+			"call dolt_merge('branch1', '-m', 'merging branch1');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "SELECT message, graph from dolt_log('--graph');",
+				Expected: []sql.Row{
+					{"merging branch1", "*\n|\\"},
+					{"inserting 2", "* |"},
+					{"inserting 1", "| *\n|/"},
+					{"creating table t", "*"},
+					{"Initialize data repository", "*"},
+				},
+			},
+			{
+				Query:    "SELECT message, graph from dolt_log('main..branch1', '--graph');",
+				Expected: []sql.Row{},
+			},
+			{
+				Query: "SELECT message, graph from dolt_log('branch1', '--graph');",
+				Expected: []sql.Row{
+					{"inserting 1", "*"},
+					{"creating table t", "*"},
+					{"Initialize data repository", "*"},
+				},
+			},
+			{
+				Query: "SELECT formatted from dolt_log('--format', '%s by %an <%ae>') LIMIT 3;",
+				Expected: []sql.Row{
+					{"merging branch1 by root <root@localhost>"},
+					{"inserting 2 by John Doe <john@doe.com>"},
+					{"inserting 1 by Jane Doe <jane@doe.com>"},
+				},
+			},
+			{
+				Query: "SELECT formatted from dolt_log('--pretty', 'format:%s%d') LIMIT 3;",
+				Expected: []sql.Row{
+					{"merging branch1 (HEAD -> main)"},
+					{"inserting 2"},
+					{"inserting 1 (branch1)"},
+				},
+			},
+			{
+				Query:    "SELECT formatted = concat(commit_hash, ' ', message) from dolt_log('--pretty', 'oneline') LIMIT 1 OFFSET 1;",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:       "SELECT * from dolt_log('--graph', '--merges');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:       "SELECT * from dolt_log('--graph', '--author', 'Jane');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:       "SELECT * from dolt_log('--pretty', 'fuller');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+		},
+	},
 }
 
 var LargeJsonObjectScriptTests = []queries.ScriptTest{
@@ -3958,6 +6136,47 @@ var DoltTagTestScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "dolt-tag: protected tags",
+		SetUpScript: []string{
+			"CREATE TABLE test(pk int primary key);",
+			"CALL DOLT_ADD('.')",
+			"CALL DOLT_COMMIT('-am','created table test')",
+			"CALL DOLT_TAG('v1', '-m', 'release v1')",
+			"CALL DOLT_TAG('nightly', '-m', 'nightly build')",
+			"SET @@GLOBAL.dolt_protected_tags = 'v*, release/*'",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "CALL DOLT_TAG('-d', 'v1')",
+				ExpectedErrStr: "tag is protected: cannot delete tag 'v1'",
+			},
+			{
+				Query:          "CALL DOLT_TAG('-d', 'nightly', 'v1')",
+				ExpectedErrStr: "tag is protected: cannot delete tag 'v1'",
+			},
+			{
+				Query:    "CALL DOLT_TAG('-d', 'nightly')",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "CALL DOLT_TAG('release/1.0', '-m', 'release 1.0')",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT tag_name, tagger, message from dolt_tags",
+				Expected: []sql.Row{{"release/1.0", "billy bob", "release 1.0"}, {"v1", "billy bob", "release v1"}},
+			},
+			{
+				Query:    "SET @@GLOBAL.dolt_protected_tags = ''",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "CALL DOLT_TAG('-d', 'v1')",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
 	{
 		Name: "dolt-tag: SQL use a tag as a ref for merge",
 		SetUpScript: []string{
@@ -5144,6 +7363,214 @@ var DoltCherryPickTests = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "cherry-pick a range of commits and several commits",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"call dolt_commit('-Am', 'create table t');",
+			"call dolt_checkout('-b', 'b');",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-am', 'add 1');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'add 2');",
+			"insert into t values (3, 3);",
+			"call dolt_commit('-am', 'add 3');",
+			"call dolt_checkout('main');",
+			"insert into t values (10, 10);",
+			"call dolt_commit('-am', 'add 10');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_cherry_pick('main..b');",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {3, 3}, {10, 10}},
+			},
+			{
+				Query:    "select message from dolt_log limit 4;",
+				Expected: []sql.Row{{"add 3"}, {"add 2"}, {"add 1"}, {"add 10"}},
+			},
+			{
+				Query:    "call dolt_reset('--hard', 'HEAD~3');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_cherry_pick('b~2', 'b');",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 1}, {3, 3}, {10, 10}},
+			},
+			{
+				Query:    "select message from dolt_log limit 3;",
+				Expected: []sql.Row{{"add 3"}, {"add 1"}, {"add 10"}},
+			},
+			{
+				// commits whose changes are already on the branch are left out
+				Query:    "call dolt_cherry_pick('main~2..b');",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "select message from dolt_log limit 2;",
+				Expected: []sql.Row{{"add 2"}, {"add 3"}},
+			},
+			{
+				Query:          "call dolt_cherry_pick('b..b');",
+				ExpectedErrStr: "error: empty commit set passed",
+			},
+			{
+				Query:          "call dolt_cherry_pick('--continue');",
+				ExpectedErrStr: "error: no cherry-pick is in progress",
+			},
+		},
+	},
+	{
+		Name: "cherry-pick of several commits stops at conflicts and continues",
+		SetUpScript: []string{
+			"set @@autocommit=1;",
+			"set @@dolt_allow_commit_conflicts=1;",
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-Am', 'create table t');",
+			"call dolt_checkout('-b', 'b');",
+			"update t set c = 2 where pk = 1;",
+			"call dolt_commit('-am', 'b 1');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'b 2');",
+			"call dolt_checkout('main');",
+			"update t set c = 3 where pk = 1;",
+			"call dolt_commit('-am', 'main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_cherry_pick('main..b');",
+				Expected: []sql.Row{{"", 1, 0, 0}},
+			},
+			{
+				Query:    "select * from dolt_conflicts;",
+				Expected: []sql.Row{{"t", uint64(1)}},
+			},
+			{
+				Query: "call dolt_cherry_pick('main..b');",
+				ExpectedErrStr: "error: a cherry-pick is already in progress on branch 'main', use dolt_cherry_pick('--continue') " +
+					"to continue it, dolt_cherry_pick('--skip') to skip the commit it stopped at, or dolt_cherry_pick('--abort') to abort it",
+			},
+			{
+				Query:          "call dolt_cherry_pick('--continue', 'b');",
+				ExpectedErrStr: "error: --abort, --continue and --skip can't be combined with each other or with commits to cherry-pick",
+			},
+			{
+				Query:          "call dolt_cherry_pick('--continue');",
+				ExpectedErrStr: "error: resolve the conflicts and constraint violations before continuing the cherry-pick",
+			},
+			{
+				Query:    "call dolt_conflicts_resolve('--theirs', 't');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:          "call dolt_cherry_pick('--continue');",
+				ExpectedErrStr: "error: stage the resolved tables with dolt_add() before continuing the cherry-pick",
+			},
+			{
+				Query:    "call dolt_add('t');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_cherry_pick('--continue');",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 2}, {2, 2}},
+			},
+			{
+				Query:    "select message from dolt_log limit 3;",
+				Expected: []sql.Row{{"b 2"}, {"b 1"}, {"main"}},
+			},
+			{
+				Query:    "select * from dolt_status;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "call dolt_cherry_pick('--continue');",
+				ExpectedErrStr: "error: no cherry-pick is in progress",
+			},
+		},
+	},
+	{
+		Name: "cherry-pick of several commits skipped past conflicts and aborted",
+		SetUpScript: []string{
+			"set @@autocommit=1;",
+			"set @@dolt_allow_commit_conflicts=1;",
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-Am', 'create table t');",
+			"call dolt_checkout('-b', 'b');",
+			"insert into t values (2, 2);",
+			"call dolt_commit('-am', 'b 1');",
+			"update t set c = 2 where pk = 1;",
+			"call dolt_commit('-am', 'b 2');",
+			"insert into t values (3, 3);",
+			"call dolt_commit('-am', 'b 3');",
+			"call dolt_checkout('main');",
+			"update t set c = 3 where pk = 1;",
+			"call dolt_commit('-am', 'main');",
+			"set @main = hashof('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_cherry_pick('main..b');",
+				Expected: []sql.Row{{"", 1, 0, 0}},
+			},
+			{
+				Query:    "select message from dolt_log limit 1;",
+				Expected: []sql.Row{{"b 1"}},
+			},
+			{
+				Query:    "call dolt_cherry_pick('--skip');",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 3}, {2, 2}, {3, 3}},
+			},
+			{
+				Query:    "select message from dolt_log limit 3;",
+				Expected: []sql.Row{{"b 3"}, {"b 1"}, {"main"}},
+			},
+			{
+				Query:    "call dolt_reset('--hard', @main);",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "call dolt_cherry_pick('main..b');",
+				Expected: []sql.Row{{"", 1, 0, 0}},
+			},
+			{
+				Query:    "call dolt_cherry_pick('--abort');",
+				Expected: []sql.Row{{"", 0, 0, 0}},
+			},
+			{
+				Query:    "select hashof('HEAD') = @main;",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 3}},
+			},
+			{
+				Query:    "select * from dolt_status;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:          "call dolt_cherry_pick('--abort');",
+				ExpectedErrStr: "error: There is no cherry-pick merge to abort",
+			},
+		},
+	},
 }
 
 var DoltCommitTests = []queries.ScriptTest{
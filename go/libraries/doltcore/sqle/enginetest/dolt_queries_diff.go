@@ -1575,6 +1575,48 @@ inner join t as of @Commit3 on rows_unmodified = t.pk;`,
 			},
 		},
 	},
+	{
+		Name: "estimated stat",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 varchar(20), c2 varchar(20));",
+			"create table k (id int, c1 varchar(20));",
+			"insert into t values(1, 'one', 'two'), (2, 'two', 'three');",
+			"insert into k values(1, 'one');",
+			"call dolt_commit('-Am', 'creating tables');",
+			"insert into t values(3, 'three', 'four');",
+			"update t set c1='uno' where pk=1;",
+			"delete from t where pk=2;",
+			"insert into k values(2, 'two');",
+			"call dolt_commit('-am', 'changing tables');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				// small diffs are counted exactly, but cell changes are not counted
+				Query:    "SELECT * from dolt_diff_stat('HEAD~', 'HEAD', 't', '--estimate');",
+				Expected: []sql.Row{{"t", 0, 1, 1, 1, 3, 3, nil, 2, 2, 6, 6}},
+			},
+			{
+				Query:    "SELECT * from dolt_diff_stat('HEAD~', 'HEAD', 't', '--exact');",
+				Expected: []sql.Row{{"t", 0, 1, 1, 1, 3, 3, 1, 2, 2, 6, 6}},
+			},
+			{
+				// keyless tables can't be estimated, and are always counted exactly
+				Query: "SELECT * from dolt_diff_stat('HEAD~..HEAD', '--estimate') order by table_name;",
+				Expected: []sql.Row{
+					{"k", nil, 1, 0, nil, nil, nil, nil, nil, nil, nil, nil},
+					{"t", 0, 1, 1, 1, 3, 3, nil, 2, 2, 6, 6},
+				},
+			},
+			{
+				Query:       "SELECT * from dolt_diff_stat('HEAD~', 'HEAD', '--fast');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:          "SELECT * from dolt_diff_stat('HEAD~', 'HEAD', '--estimate', '--exact');",
+				ExpectedErrStr: "dolt_diff_stat: the --estimate and --exact options cannot be used together",
+			},
+		},
+	},
 	{
 		Name: "basic case with single keyless table",
 		SetUpScript: []string{
@@ -2877,6 +2919,56 @@ var DiffSummaryTableFunctionScriptTests = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "renamed tables",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1), (2, 2), (3, 3), (4, 4);",
+			"CREATE TABLE u (pk int PRIMARY KEY, c int);",
+			"INSERT INTO u VALUES (1, 1);",
+			"CALL DOLT_COMMIT('-Am', 'create tables');",
+			"RENAME TABLE u TO u2;",
+			"CREATE TABLE t2 (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t2 SELECT * FROM t;",
+			"DROP TABLE t;",
+			"UPDATE t2 SET c = 10 WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-Am', 'rename tables');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "SELECT * FROM dolt_diff_summary('HEAD~', 'HEAD');",
+				Expected: []sql.Row{
+					{"t", "t2", "renamed", true, true},
+					{"u", "u2", "renamed", false, true},
+				},
+			},
+			{
+				Query:    "SET @@dolt_diff_rename_similarity = 80;",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query: "SELECT * FROM dolt_diff_summary('HEAD~', 'HEAD');",
+				Expected: []sql.Row{
+					{"", "t2", "added", true, true},
+					{"t", "", "dropped", true, true},
+					{"u", "u2", "renamed", false, true},
+				},
+			},
+			{
+				Query:    "SET @@dolt_diff_renames = 0;",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query: "SELECT * FROM dolt_diff_summary('HEAD~', 'HEAD');",
+				Expected: []sql.Row{
+					{"", "t2", "added", true, true},
+					{"", "u2", "added", true, true},
+					{"t", "", "dropped", true, true},
+					{"u", "", "dropped", true, true},
+				},
+			},
+		},
+	},
 }
 
 var PatchTableFunctionScriptTests = []queries.ScriptTest{
@@ -5416,6 +5508,7 @@ var SystemTableIndexTests = []systabScript{
 		name: "systab benchmarks",
 		setup: append(systabSetup,
 			"set @commit = (select commit_hash from dolt_log where message = 'commit 2');",
+			"set @committer = (select committer from dolt_log where message = 'commit 2');",
 		),
 		queries: []systabQuery{
 			{
@@ -5438,6 +5531,22 @@ var SystemTableIndexTests = []systabScript{
 				query: "select count(*) from dolt_log where commit_hash = @commit;",
 				exp:   []sql.Row{{1}},
 			},
+			{
+				query: "select count(*) from dolt_log where committer = @committer and message like 'commit %';",
+				exp:   []sql.Row{{5}},
+			},
+			{
+				query: "select count(*) from dolt_log where committer = 'nobody';",
+				exp:   []sql.Row{{0}},
+			},
+			{
+				query: "select count(*) from dolt_log where date < '2022-08-08';",
+				exp:   []sql.Row{{0}},
+			},
+			{
+				query: "select count(*) from dolt_log where date >= '2022-08-08' and message like 'commit %';",
+				exp:   []sql.Row{{5}},
+			},
 			{
 				query: "select count(*) from dolt_commits where commit_hash = @commit;",
 				exp:   []sql.Row{{1}},
@@ -26,6 +26,7 @@ import (
 	"gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
 
@@ -2132,6 +2133,620 @@ var MergeScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "merge fulltext with changes on both branches",
+		SetUpScript: []string{
+			"CREATE TABLE test (pk BIGINT UNSIGNED PRIMARY KEY, v1 VARCHAR(200), FULLTEXT idx (v1));",
+			"INSERT INTO test VALUES (1, 'abc'), (2, 'def'), (3, 'ghi');",
+			"CALL dolt_commit('-Am', 'Initial commit')",
+			"call dolt_branch('other')",
+			"UPDATE test SET v1 = 'jkl' WHERE pk = 1;",
+			"INSERT INTO test VALUES (4, 'mno');",
+			"call dolt_commit('-Am', 'Main commit')",
+			"call dolt_checkout('other')",
+			"DELETE FROM test WHERE pk = 2;",
+			"INSERT INTO test VALUES (5, 'pqr');",
+			"call dolt_commit('-Am', 'Other commit')",
+			"call dolt_checkout('main')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:            "call dolt_merge('other')",
+				SkipResultsCheck: true, // contains commit hash, we just need it to not error
+			},
+			{
+				Query: "SELECT pk, v1 FROM test WHERE MATCH(v1) AGAINST ('abc def ghi jkl mno pqr') ORDER BY pk;",
+				Expected: []sql.Row{
+					{uint64(1), "jkl"},
+					{uint64(3), "ghi"},
+					{uint64(4), "mno"},
+					{uint64(5), "pqr"},
+				},
+			},
+		},
+	},
+	{
+		Name: "merge spatial index with changes on both branches",
+		SetUpScript: []string{
+			"CREATE TABLE geo (pk INT PRIMARY KEY, p POINT NOT NULL SRID 0, SPATIAL INDEX idx (p));",
+			"INSERT INTO geo VALUES (1, POINT(1, 1)), (2, POINT(2, 2)), (3, POINT(3, 3));",
+			"CALL dolt_commit('-Am', 'Initial commit')",
+			"call dolt_branch('other')",
+			"UPDATE geo SET p = POINT(10, 10) WHERE pk = 1;",
+			"INSERT INTO geo VALUES (4, POINT(4, 4));",
+			"call dolt_commit('-Am', 'Main commit')",
+			"call dolt_checkout('other')",
+			"DELETE FROM geo WHERE pk = 2;",
+			"UPDATE geo SET p = POINT(20, 20) WHERE pk = 3;",
+			"INSERT INTO geo VALUES (5, POINT(5, 5));",
+			"call dolt_commit('-Am', 'Other commit')",
+			"call dolt_checkout('main')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:            "call dolt_merge('other')",
+				SkipResultsCheck: true, // contains commit hash, we just need it to not error
+			},
+			{
+				Query:    "SELECT pk FROM geo WHERE ST_INTERSECTS(p, ST_GEOMFROMTEXT('POLYGON((0 0, 0 6, 6 6, 6 0, 0 0))')) ORDER BY pk;",
+				Expected: []sql.Row{{4}, {5}},
+			},
+			{
+				Query:    "SELECT pk FROM geo WHERE ST_INTERSECTS(p, ST_GEOMFROMTEXT('POLYGON((9 9, 9 21, 21 21, 21 9, 9 9))')) ORDER BY pk;",
+				Expected: []sql.Row{{1}, {3}},
+			},
+			{
+				Query:    "SELECT pk FROM geo WHERE ST_INTERSECTS(p, ST_GEOMFROMTEXT('POINT(2 2)'));",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "merge table renamed on one branch and modified on the other",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1), (2, 2);",
+			"CALL dolt_commit('-Am', 'create table');",
+			"CALL dolt_branch('other');",
+			"RENAME TABLE t TO t2;",
+			"CALL dolt_commit('-Am', 'rename table');",
+			"CALL dolt_checkout('other');",
+			"UPDATE t SET c = 10 WHERE pk = 1;",
+			"INSERT INTO t VALUES (3, 3);",
+			"CALL dolt_commit('-Am', 'modify table');",
+			"CALL dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL dolt_merge('other');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "SHOW FULL TABLES WHERE table_type = 'BASE TABLE';",
+				Expected: []sql.Row{{"t2", "BASE TABLE"}},
+			},
+			{
+				Query:    "SELECT * FROM t2 ORDER BY pk;",
+				Expected: []sql.Row{{1, 10}, {2, 2}, {3, 3}},
+			},
+		},
+	},
+	{
+		Name: "merge table renamed on one branch and modified on the other, with --no-renames",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1), (2, 2);",
+			"CALL dolt_commit('-Am', 'create table');",
+			"CALL dolt_branch('other');",
+			"RENAME TABLE t TO t2;",
+			"CALL dolt_commit('-Am', 'rename table');",
+			"CALL dolt_checkout('other');",
+			"UPDATE t SET c = 10 WHERE pk = 1;",
+			"CALL dolt_commit('-Am', 'modify table');",
+			"CALL dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "CALL dolt_merge('--no-renames', 'other');",
+				ExpectedErrStr: "conflict: table with same name deleted and modified ",
+			},
+		},
+	},
+	{
+		Name: "merge column renamed on one branch and modified on the other",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c varchar(10));",
+			"INSERT INTO t VALUES (1, 'one'), (2, 'two');",
+			"CALL dolt_commit('-Am', 'create table');",
+			"CALL dolt_branch('other');",
+			"ALTER TABLE t RENAME COLUMN c TO c2;",
+			"UPDATE t SET c2 = 'dos' WHERE pk = 2;",
+			"CALL dolt_commit('-Am', 'rename column');",
+			"CALL dolt_checkout('other');",
+			"ALTER TABLE t MODIFY COLUMN c varchar(20);",
+			"INSERT INTO t VALUES (3, 'three is long');",
+			"CALL dolt_commit('-Am', 'modify column');",
+			"CALL dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL dolt_merge('other');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query: "SHOW CREATE TABLE t;",
+				Expected: []sql.Row{{"t", "CREATE TABLE `t` (\n" +
+					"  `pk` int NOT NULL,\n" +
+					"  `c2` varchar(20),\n" +
+					"  PRIMARY KEY (`pk`)\n" +
+					") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin"}},
+			},
+			{
+				Query:    "SELECT * FROM t ORDER BY pk;",
+				Expected: []sql.Row{{1, "one"}, {2, "dos"}, {3, "three is long"}},
+			},
+		},
+	},
+	{
+		Name: "merge strategies resolve conflicts in matching tables",
+		SetUpScript: []string{
+			"SET autocommit = 0;",
+			"CREATE TABLE data_a (pk int PRIMARY KEY, c int, KEY (c));",
+			"CREATE TABLE data_b (pk int PRIMARY KEY, c int);",
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO data_a VALUES (1, 1), (2, 2), (3, 3);",
+			"INSERT INTO data_b VALUES (1, 1), (2, 2);",
+			"INSERT INTO t VALUES (1, 1);",
+			"INSERT INTO dolt_merge_strategies VALUES ('data_*', 'Theirs'), ('data_b', 'ours');",
+			"CALL dolt_commit('-Am', 'create tables');",
+			"CALL dolt_branch('other');",
+			"UPDATE data_a SET c = 10 WHERE pk = 1;",
+			"UPDATE data_a SET c = 20 WHERE pk = 2;",
+			"DELETE FROM data_a WHERE pk = 3;",
+			"UPDATE data_b SET c = 10 WHERE pk = 1;",
+			"UPDATE t SET c = 10 WHERE pk = 1;",
+			"CALL dolt_commit('-am', 'main changes');",
+			"CALL dolt_checkout('other');",
+			"UPDATE data_a SET c = 100 WHERE pk = 1;",
+			"DELETE FROM data_a WHERE pk = 2;",
+			"UPDATE data_a SET c = 300 WHERE pk = 3;",
+			"UPDATE data_b SET c = 100 WHERE pk = 1;",
+			"DELETE FROM data_b WHERE pk = 2;",
+			"UPDATE t SET c = 100 WHERE pk = 1;",
+			"CALL dolt_commit('-am', 'other changes');",
+			"CALL dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "SELECT * FROM dolt_merge_strategies ORDER BY pattern;",
+				Expected: []sql.Row{{"data_*", "theirs"}, {"data_b", "ours"}},
+			},
+			{
+				Query:    "CALL dolt_merge('other');",
+				Expected: []sql.Row{{"", 0, 1}},
+			},
+			{
+				Query:    "SELECT * FROM dolt_conflicts;",
+				Expected: []sql.Row{{"t", uint64(1)}},
+			},
+			{
+				Query:    "SELECT * FROM data_a ORDER BY pk;",
+				Expected: []sql.Row{{1, 100}, {3, 300}},
+			},
+			{
+				Query:    "SELECT pk FROM data_a WHERE c = 20;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "SELECT pk FROM data_a WHERE c = 300;",
+				Expected: []sql.Row{{3}},
+			},
+			{
+				// the delete of pk 2 doesn't conflict with any of our changes
+				Query:    "SELECT * FROM data_b ORDER BY pk;",
+				Expected: []sql.Row{{1, 10}},
+			},
+		},
+	},
+	{
+		Name: "union merge strategy keeps rows deleted on one side and modified on the other",
+		SetUpScript: []string{
+			"SET autocommit = 0;",
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1), (2, 2), (3, 3);",
+			"INSERT INTO dolt_merge_strategies VALUES ('t', 'union');",
+			"CALL dolt_commit('-Am', 'create table');",
+			"CALL dolt_branch('other');",
+			"DELETE FROM t WHERE pk = 1;",
+			"UPDATE t SET c = 20 WHERE pk = 2;",
+			"UPDATE t SET c = 30 WHERE pk = 3;",
+			"CALL dolt_commit('-am', 'main changes');",
+			"CALL dolt_checkout('other');",
+			"UPDATE t SET c = 100 WHERE pk = 1;",
+			"DELETE FROM t WHERE pk = 2;",
+			"UPDATE t SET c = 300 WHERE pk = 3;",
+			"CALL dolt_commit('-am', 'other changes');",
+			"CALL dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL dolt_merge('other');",
+				Expected: []sql.Row{{"", 0, 1}},
+			},
+			{
+				Query:    "SELECT our_pk, our_c, their_pk, their_c FROM dolt_conflicts_t;",
+				Expected: []sql.Row{{3, 30, 3, 300}},
+			},
+			{
+				Query:    "SELECT * FROM t ORDER BY pk;",
+				Expected: []sql.Row{{1, 100}, {2, 20}, {3, 30}},
+			},
+		},
+	},
+	{
+		Name: "merge strategies apply to cherry-picks",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1);",
+			"INSERT INTO dolt_merge_strategies VALUES ('t', 'theirs');",
+			"CALL dolt_commit('-Am', 'create table');",
+			"CALL dolt_checkout('-b', 'other');",
+			"UPDATE t SET c = 100 WHERE pk = 1;",
+			"CALL dolt_commit('-am', 'other change');",
+			"SET @commit = hashof('HEAD');",
+			"CALL dolt_checkout('main');",
+			"UPDATE t SET c = 10 WHERE pk = 1;",
+			"CALL dolt_commit('-am', 'main change');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL dolt_cherry_pick(@commit);",
+				Expected: []sql.Row{{doltCommit, 0, 0, 0}},
+			},
+			{
+				Query:    "SELECT * FROM t;",
+				Expected: []sql.Row{{1, 100}},
+			},
+		},
+	},
+	{
+		Name: "dolt_merge_strategies rejects unknown strategies",
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "INSERT INTO dolt_merge_strategies VALUES ('t', 'mine');",
+				ExpectedErrStr: "invalid merge strategy 'mine', expected one of 'ours', 'theirs', 'union' or 'manual'",
+			},
+			{
+				Query:    "INSERT INTO dolt_merge_strategies VALUES ('t', 'manual');",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:          "UPDATE dolt_merge_strategies SET strategy = 'mine';",
+				ExpectedErrStr: "invalid merge strategy 'mine', expected one of 'ours', 'theirs', 'union' or 'manual'",
+			},
+		},
+	},
+	{
+		Name: "octopus merge of several branches",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"CREATE TABLE u (pk int PRIMARY KEY);",
+			"INSERT INTO t VALUES (1, 1);",
+			"CALL dolt_add('.');",
+			"CALL dolt_commit('-am', 'create tables');",
+			"CALL dolt_branch('b1');",
+			"CALL dolt_branch('b2');",
+			"CALL dolt_branch('b3');",
+			"CALL dolt_checkout('b1');",
+			"INSERT INTO t VALUES (2, 2);",
+			"CALL dolt_commit('-am', 'b1 change');",
+			"CALL dolt_checkout('b2');",
+			"UPDATE t SET c = 10 WHERE pk = 1;",
+			"CALL dolt_commit('-am', 'b2 change');",
+			"CALL dolt_checkout('b3');",
+			"INSERT INTO u VALUES (1);",
+			"CALL dolt_commit('-am', 'b3 change');",
+			"CALL dolt_checkout('main');",
+			"INSERT INTO t VALUES (3, 3);",
+			"CALL dolt_commit('-am', 'main change');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL dolt_merge('b1', 'b2', 'b3');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "SELECT * FROM t ORDER BY pk;",
+				Expected: []sql.Row{{1, 10}, {2, 2}, {3, 3}},
+			},
+			{
+				Query:    "SELECT * FROM u;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "SELECT message FROM dolt_log LIMIT 1;",
+				Expected: []sql.Row{{"Merge branches 'b1', 'b2' and 'b3' into main"}},
+			},
+			{
+				Query: "SELECT parent_hash = hashof('main~1'), parent_hash = hashof('b1'), parent_hash = hashof('b2'), parent_hash = hashof('b3') " +
+					"FROM dolt_commit_ancestors WHERE commit_hash = hashof('HEAD') ORDER BY parent_index;",
+				Expected: []sql.Row{
+					{true, false, false, false},
+					{false, true, false, false},
+					{false, false, true, false},
+					{false, false, false, true},
+				},
+			},
+			{
+				Query:    "SELECT count(*) FROM dolt_status;",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "octopus merge with conflicting branches makes no changes",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1);",
+			"CALL dolt_add('.');",
+			"CALL dolt_commit('-am', 'create table');",
+			"CALL dolt_branch('b1');",
+			"CALL dolt_branch('b2');",
+			"CALL dolt_checkout('b1');",
+			"UPDATE t SET c = 2 WHERE pk = 1;",
+			"CALL dolt_commit('-am', 'b1 change');",
+			"CALL dolt_checkout('b2');",
+			"UPDATE t SET c = 3 WHERE pk = 1;",
+			"CALL dolt_commit('-am', 'b2 change');",
+			"CALL dolt_checkout('main');",
+			"INSERT INTO t VALUES (2, 2);",
+			"CALL dolt_commit('-am', 'main change');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "CALL dolt_merge('b1', 'b2');",
+				ExpectedErrStr: "error: merging 'b2' conflicts with the other branches being merged in tables: t; no changes were made. Merge the branches one at a time to resolve the conflicts",
+			},
+			{
+				Query:    "SELECT message FROM dolt_log LIMIT 1;",
+				Expected: []sql.Row{{"main change"}},
+			},
+			{
+				Query:    "SELECT * FROM t ORDER BY pk;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+			{
+				Query:    "SELECT count(*) FROM dolt_conflicts;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT count(*) FROM dolt_merge_status WHERE is_merging;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:          "CALL dolt_merge('--squash', 'b1', 'b2');",
+				ExpectedErrStr: "error: --squash and --no-commit cannot be used when merging more than one branch",
+			},
+			{
+				Query:    "INSERT INTO t VALUES (3, 3);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:          "CALL dolt_merge('b1', 'b2');",
+				ExpectedErrStr: "cannot merge with uncommitted changes",
+			},
+		},
+	},
+	{
+		Name: "octopus merge skips branches that are already merged",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY);",
+			"CALL dolt_add('.');",
+			"CALL dolt_commit('-am', 'create table');",
+			"CALL dolt_branch('merged');",
+			"CALL dolt_checkout('-b', 'b1');",
+			"INSERT INTO t VALUES (1);",
+			"CALL dolt_commit('-am', 'b1 change');",
+			"CALL dolt_checkout('-b', 'b2');",
+			"INSERT INTO t VALUES (2);",
+			"CALL dolt_commit('-am', 'b2 change');",
+			"CALL dolt_checkout('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL dolt_merge('merged', 'b1', 'b2', 'b2');",
+				Expected: []sql.Row{{doltCommit, 1, 0}},
+			},
+			{
+				Query:    "SELECT hashof('HEAD') = hashof('b2');",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:    "CALL dolt_merge('merged', 'b1');",
+				Expected: []sql.Row{{"", 0, 0}},
+			},
+		},
+	},
+	{
+		Name: "merge with --autostash applies uncommitted changes after merging",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1);",
+			"CALL dolt_add('.');",
+			"CALL dolt_commit('-am', 'create table');",
+			"CALL dolt_checkout('-b', 'b');",
+			"INSERT INTO t VALUES (2, 2);",
+			"CALL dolt_commit('-am', 'b change');",
+			"CALL dolt_checkout('main');",
+			"INSERT INTO t VALUES (3, 3);",
+			"CALL dolt_commit('-am', 'main change');",
+			"UPDATE t SET c = 10 WHERE pk = 1;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL dolt_merge('--autostash', 'b');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "SELECT * FROM t ORDER BY pk;",
+				Expected: []sql.Row{{1, 10}, {2, 2}, {3, 3}},
+			},
+			{
+				Query:    "SELECT * FROM t AS OF 'HEAD' ORDER BY pk;",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {3, 3}},
+			},
+			{
+				Query:    "SELECT * FROM dolt_status;",
+				Expected: []sql.Row{{"t", false, "modified"}},
+			},
+			{
+				Query:    "SELECT message FROM dolt_log LIMIT 1;",
+				Expected: []sql.Row{{"Merge branch 'b' into main"}},
+			},
+		},
+	},
+	{
+		Name: "merge with --autostash keeps uncommitted changes that conflict with the merge stashed",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1);",
+			"CALL dolt_add('.');",
+			"CALL dolt_commit('-am', 'create table');",
+			"CALL dolt_checkout('-b', 'b');",
+			"UPDATE t SET c = 2 WHERE pk = 1;",
+			"CALL dolt_commit('-am', 'b change');",
+			"CALL dolt_checkout('main');",
+			"UPDATE t SET c = 10 WHERE pk = 1;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:                           "CALL dolt_merge('--autostash', 'b');",
+				Expected:                        []sql.Row{{doltCommit, 1, 0}},
+				ExpectedWarning:                 dprocedures.DoltMergeWarningCode,
+				ExpectedWarningsCount:           1,
+				ExpectedWarningMessageSubstring: "your uncommitted changes conflict with the merged changes in tables: t, so they were kept in the stash",
+			},
+			{
+				Query:    "SELECT * FROM t;",
+				Expected: []sql.Row{{1, 2}},
+			},
+			{
+				Query:    "SELECT count(*) FROM dolt_status;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT count(*) FROM dolt_conflicts;",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "merge with --autostash keeps uncommitted changes stashed when the merge conflicts",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1);",
+			"CALL dolt_add('.');",
+			"CALL dolt_commit('-am', 'create table');",
+			"CALL dolt_checkout('-b', 'b');",
+			"UPDATE t SET c = 2 WHERE pk = 1;",
+			"CALL dolt_commit('-am', 'b change');",
+			"CALL dolt_checkout('main');",
+			"UPDATE t SET c = 3 WHERE pk = 1;",
+			"CALL dolt_commit('-am', 'main change');",
+			"INSERT INTO t VALUES (5, 5);",
+			"SET dolt_allow_commit_conflicts = on;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:                 "CALL dolt_merge('--autostash', 'b');",
+				Expected:              []sql.Row{{"", 0, 1}},
+				ExpectedWarning:       dprocedures.DoltMergeWarningCode,
+				ExpectedWarningsCount: 2,
+			},
+			{
+				Query:    "SELECT * FROM t;",
+				Expected: []sql.Row{{1, 3}},
+			},
+			{
+				Query:    "SELECT count(*) FROM dolt_conflicts_t;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "CALL dolt_merge('--abort');",
+				Expected: []sql.Row{{"", 0, 0}},
+			},
+			{
+				Query:    "SELECT count(*) FROM dolt_status;",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "merge with --autostash and --no-commit stages only the merged changes",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY, c int);",
+			"INSERT INTO t VALUES (1, 1);",
+			"CALL dolt_add('.');",
+			"CALL dolt_commit('-am', 'create table');",
+			"CALL dolt_checkout('-b', 'b');",
+			"INSERT INTO t VALUES (2, 2);",
+			"CALL dolt_commit('-am', 'b change');",
+			"CALL dolt_checkout('main');",
+			"INSERT INTO t VALUES (3, 3);",
+			"CALL dolt_commit('-am', 'main change');",
+			"UPDATE t SET c = 10 WHERE pk = 1;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL dolt_merge('--autostash', '--no-commit', 'b');",
+				Expected: []sql.Row{{"", 0, 0}},
+			},
+			{
+				Query:    "SELECT * FROM t ORDER BY pk;",
+				Expected: []sql.Row{{1, 10}, {2, 2}, {3, 3}},
+			},
+			{
+				Query:    "SELECT * FROM dolt_status ORDER BY staged;",
+				Expected: []sql.Row{{"t", false, "modified"}, {"t", true, "modified"}},
+			},
+			{
+				Query:    "CALL dolt_commit('-m', 'merge b');",
+				Expected: []sql.Row{{doltCommit}},
+			},
+			{
+				Query:    "SELECT * FROM t AS OF 'HEAD' ORDER BY pk;",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {3, 3}},
+			},
+			{
+				Query:    "SELECT count(*) FROM dolt_log WHERE message = 'b change';",
+				Expected: []sql.Row{{1}},
+			},
+		},
+	},
+	{
+		Name: "merge with --no-ff and --no-commit doesn't stage uncommitted changes",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int PRIMARY KEY);",
+			"CREATE TABLE u (pk int PRIMARY KEY);",
+			"CALL dolt_add('.');",
+			"CALL dolt_commit('-am', 'create tables');",
+			"CALL dolt_checkout('-b', 'b');",
+			"INSERT INTO t VALUES (1);",
+			"CALL dolt_commit('-am', 'b change');",
+			"CALL dolt_checkout('main');",
+			"INSERT INTO u VALUES (1);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL dolt_merge('--no-ff', '--no-commit', 'b');",
+				Expected: []sql.Row{{"", 0, 0}},
+			},
+			{
+				Query:    "SELECT * FROM dolt_status ORDER BY table_name;",
+				Expected: []sql.Row{{"t", true, "modified"}, {"u", false, "modified"}},
+			},
+		},
+	},
 }
 
 var KeylessMergeCVsAndConflictsScripts = []queries.ScriptTest{
@@ -3405,6 +4020,70 @@ var MergeArtifactsScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "dolt_conflicts_resolve --cell-merge resolves conflicts without colliding columns",
+		SetUpScript: []string{
+			"SET dolt_allow_commit_conflicts = on;",
+			"CREATE TABLE t (pk int PRIMARY KEY, a int, b int, c int, INDEX c_idx (c));",
+			"INSERT INTO t VALUES (1, 1, 1, 1), (2, 2, 2, 2), (3, 3, 3, 3);",
+			"CALL DOLT_COMMIT('-Am', 'create table');",
+
+			"CALL DOLT_CHECKOUT('-b', 'right');",
+			"UPDATE t SET a = 10, c = 10 WHERE pk = 1;",
+			"UPDATE t SET a = 20, c = 20 WHERE pk = 2;",
+			"DELETE FROM t WHERE pk = 3;",
+			"CALL DOLT_COMMIT('-am', 'right edit');",
+
+			"CALL DOLT_CHECKOUT('main');",
+			"UPDATE t SET a = 11, b = 11 WHERE pk = 1;",
+			"UPDATE t SET a = 21, b = 21 WHERE pk = 2;",
+			"UPDATE t SET a = 31 WHERE pk = 3;",
+			"CALL DOLT_COMMIT('-am', 'main edit');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('right');",
+				Expected: []sql.Row{{"", 0, 1}},
+			},
+			{
+				Query:    "SELECT our_pk, our_a, their_a FROM dolt_conflicts_t ORDER BY base_pk;",
+				Expected: []sql.Row{{1, 11, 10}, {2, 21, 20}, {3, 31, nil}},
+			},
+			{
+				Query:          "CALL DOLT_CONFLICTS_RESOLVE('--cell-merge', '--ours', 't');",
+				ExpectedErrStr: "--cell-merge can't be used with --ours or --theirs",
+			},
+			{
+				// only their change to column a is left in the conflict of row 1
+				Query:    "UPDATE t SET a = 1 WHERE pk = 1;",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "CALL DOLT_CONFLICTS_RESOLVE('--cell-merge', 't');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT * FROM t ORDER BY pk;",
+				Expected: []sql.Row{{1, 10, 11, 10}, {2, 21, 21, 2}, {3, 31, 3, 3}},
+			},
+			{
+				Query:    "SELECT pk FROM t WHERE c = 10;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "SELECT our_pk, our_a, their_a FROM dolt_conflicts_t ORDER BY base_pk;",
+				Expected: []sql.Row{{2, 21, 20}, {3, 31, nil}},
+			},
+			{
+				Query:    "CALL DOLT_CONFLICTS_RESOLVE('--theirs', 't');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT * FROM t ORDER BY pk;",
+				Expected: []sql.Row{{1, 10, 11, 10}, {2, 20, 2, 20}},
+			},
+		},
+	},
 }
 
 var SchemaConflictScripts = []queries.ScriptTest{
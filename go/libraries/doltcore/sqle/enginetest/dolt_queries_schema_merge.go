@@ -577,6 +577,68 @@ var SchemaChangeTestsCollations = []MergeScriptTest{
 }
 
 var SchemaChangeTestsConstraints = []MergeScriptTest{
+	{
+		Name: "adding an index over the same columns as another index",
+		AncSetUpScript: []string{
+			"CREATE table t (pk int primary key, col1 varchar(100));",
+			"INSERT into t values (1, '100'), (2, '200');",
+			"alter table t add unique index idx1 (col1);",
+		},
+		RightSetUpScript: []string{
+			"alter table t add index idx2 (col1(10));",
+		},
+		LeftSetUpScript: []string{
+			"INSERT into t values (3, '300');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query: "show create table t;",
+				Expected: []sql.Row{{"t",
+					"CREATE TABLE `t` (\n  `pk` int NOT NULL,\n  `col1` varchar(100),\n  PRIMARY KEY (`pk`),\n  UNIQUE KEY `idx1` (`col1`),\n  KEY `idx2` (`col1`(10))\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin"}},
+			},
+			{
+				Query:    "select pk from t where col1 = '300';",
+				Expected: []sql.Row{{3}},
+			},
+		},
+	},
+	{
+		Name: "adding check constraints over the same column on each side",
+		AncSetUpScript: []string{
+			"create table t (pk int primary key, c1 int);",
+			"insert into t values (1, 1);",
+		},
+		RightSetUpScript: []string{
+			"alter table t add constraint check1 check (c1 > 0);",
+			"insert into t values (2, 2);",
+		},
+		LeftSetUpScript: []string{
+			"alter table t add constraint check2 check (c1 < 100);",
+			"insert into t values (3, 3);",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "call dolt_merge('right');",
+				Expected: []sql.Row{{doltCommit, 0, 0}},
+			},
+			{
+				Query:    "select constraint_name, check_clause from information_schema.check_constraints order by constraint_name;",
+				Expected: []sql.Row{{"check1", "(c1 > 0)"}, {"check2", "(c1 < 100)"}},
+			},
+			{
+				Query:       "insert into t values (4, 100);",
+				ExpectedErr: sql.ErrCheckConstraintViolated,
+			},
+			{
+				Query:    "select * from t order by pk;",
+				Expected: []sql.Row{{1, 1}, {2, 2}, {3, 3}},
+			},
+		},
+	},
 	{
 		Name: "removing a not-null constraint",
 		AncSetUpScript: []string{
@@ -1636,22 +1698,18 @@ var SchemaChangeTestsSchemaConflicts = []MergeScriptTest{
 		},
 	},
 	{
-		// Dolt indexes currently use the set of columns covered by the index, as a unique identifier for matching
-		// indexes on either side of a merge. As Dolt's index support has grown, this isn't guaranteed to be a unique
-		// id anymore, so instead of allowing a race condition in the merge logic, if we detect that multiple indexes
-		// cover the same set of columns, we return a schema conflict and let the user decide how to resolve it.
-		Name: "duplicate index tag set",
+		Name: "index conflicts: both sides add an index with the same name, same columns, but different type",
 		AncSetUpScript: []string{
 			"set @@autocommit=0;",
-			"CREATE table t (pk int primary key, col1 varchar(100));",
-			"INSERT into t values (1, '100'), (2, '200');",
-			"alter table t add unique index idx1 (col1);",
+			"CREATE table t (pk int primary key, col1 int, col2 varchar(100));",
 		},
 		RightSetUpScript: []string{
-			"alter table t add index idx2 (col1(10));",
+			"alter table t add index idx1 (col2(2));",
+			"INSERT into t values (1, 10, '100');",
 		},
 		LeftSetUpScript: []string{
-			"INSERT into t values (3, '300');",
+			"alter table t add index idx1 (col2);",
+			"INSERT into t values (2, 20, '200');",
 		},
 		Assertions: []queries.ScriptTestAssertion{
 			{
@@ -1659,28 +1717,34 @@ var SchemaChangeTestsSchemaConflicts = []MergeScriptTest{
 				Expected: []sql.Row{{"", 0, 1}},
 			},
 			{
-				Query: "select table_name, our_schema, their_schema, base_schema, description from dolt_schema_conflicts;",
+				Query: "select table_name, base_schema, our_schema, their_schema from dolt_schema_conflicts;",
 				Expected: []sql.Row{{"t",
-					"CREATE TABLE `t` (\n  `pk` int NOT NULL,\n  `col1` varchar(100),\n  PRIMARY KEY (`pk`),\n  UNIQUE KEY `idx1` (`col1`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin;",
-					"CREATE TABLE `t` (\n  `pk` int NOT NULL,\n  `col1` varchar(100),\n  PRIMARY KEY (`pk`),\n  UNIQUE KEY `idx1` (`col1`),\n  KEY `idx2` (`col1`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin;",
-					"CREATE TABLE `t` (\n  `pk` int NOT NULL,\n  `col1` varchar(100),\n  PRIMARY KEY (`pk`),\n  UNIQUE KEY `idx1` (`col1`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin;",
-					"multiple indexes covering the same column set cannot be merged: 'idx1' and 'idx2'"}},
+					"CREATE TABLE `t` (\n  `pk` int NOT NULL,\n  `col1` int,\n  `col2` varchar(100),\n  PRIMARY KEY (`pk`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin;",
+					"CREATE TABLE `t` (\n  `pk` int NOT NULL,\n  `col1` int,\n  `col2` varchar(100),\n  PRIMARY KEY (`pk`),\n  KEY `idx1` (`col2`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin;",
+					"CREATE TABLE `t` (\n  `pk` int NOT NULL,\n  `col1` int,\n  `col2` varchar(100),\n  PRIMARY KEY (`pk`),\n  KEY `idx1` (`col2`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin;",
+				}},
+			},
+			{
+				Query:    "select description like 'two indexes with the name ''idx1'' but different definitions: our KEY `idx1` (`col2`%) and their KEY `idx1` (`col2`%)' from dolt_schema_conflicts;",
+				Expected: []sql.Row{{true}},
 			},
 		},
 	},
 	{
-		Name: "index conflicts: both sides add an index with the same name, same columns, but different type",
+		Name: "index conflicts: an index is dropped on one side and changed on the other",
 		AncSetUpScript: []string{
 			"set @@autocommit=0;",
-			"CREATE table t (pk int primary key, col1 int, col2 varchar(100));",
+			"CREATE table t (pk int primary key, col1 int);",
+			"alter table t add index idx1 (col1);",
 		},
 		RightSetUpScript: []string{
-			"alter table t add index idx1 (col2(2));",
-			"INSERT into t values (1, 10, '100');",
+			"alter table t drop index idx1;",
+			"INSERT into t values (1, 10);",
 		},
 		LeftSetUpScript: []string{
-			"alter table t add index idx1 (col2);",
-			"INSERT into t values (2, 20, '200');",
+			"alter table t drop index idx1;",
+			"alter table t add unique index idx1 (col1);",
+			"INSERT into t values (2, 20);",
 		},
 		Assertions: []queries.ScriptTestAssertion{
 			{
@@ -1688,12 +1752,8 @@ var SchemaChangeTestsSchemaConflicts = []MergeScriptTest{
 				Expected: []sql.Row{{"", 0, 1}},
 			},
 			{
-				Query: "select table_name, base_schema, our_schema, their_schema from dolt_schema_conflicts;",
-				Expected: []sql.Row{{"t",
-					"CREATE TABLE `t` (\n  `pk` int NOT NULL,\n  `col1` int,\n  `col2` varchar(100),\n  PRIMARY KEY (`pk`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin;",
-					"CREATE TABLE `t` (\n  `pk` int NOT NULL,\n  `col1` int,\n  `col2` varchar(100),\n  PRIMARY KEY (`pk`),\n  KEY `idx1` (`col2`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin;",
-					"CREATE TABLE `t` (\n  `pk` int NOT NULL,\n  `col1` int,\n  `col2` varchar(100),\n  PRIMARY KEY (`pk`),\n  KEY `idx1` (`col2`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin;",
-				}},
+				Query:    "select table_name, description like 'index ''idx1'' was deleted in % but modified in %: UNIQUE KEY `idx1` (`col1`)' from dolt_schema_conflicts;",
+				Expected: []sql.Row{{"t", true}},
 			},
 		},
 	},
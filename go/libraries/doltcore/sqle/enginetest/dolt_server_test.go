@@ -106,6 +106,72 @@ var DoltBranchMultiSessionScriptTests = []queries.ScriptTest{
 				Query:    "/* client b */ CALL DOLT_BRANCH('-m', 'branch2', 'movedBranch2');",
 				Expected: []sql.Row{{0}},
 			},
+			{
+				Query:    "/* client a */ select active_branch();",
+				Expected: []sql.Row{{"movedBranch1"}},
+			},
+			{
+				Query:    "/* client a */ CALL DOLT_BRANCH('branch3');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "/* client b */ select name from dolt_branches where name = 'branch3';",
+				Expected: []sql.Row{{"branch3"}},
+			},
+		},
+	},
+	{
+		Name: "Test new sessions start on the default branch set by dolt_set_default_branch",
+		SetUpScript: []string{
+			"call dolt_branch('branch1');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ CALL DOLT_SET_DEFAULT_BRANCH('branch1');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "/* client a */ select active_branch();",
+				Expected: []sql.Row{{"main"}},
+			},
+			{
+				Query:    "/* client b */ select active_branch();",
+				Expected: []sql.Row{{"branch1"}},
+			},
+		},
+	},
+	{
+		Name: "Test branch rename when clients are using a branch-qualified database",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_commit('-Am', 'created table t');",
+			"call dolt_branch('branch1');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ use dolt/branch1;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into t values (1);",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ CALL DOLT_BRANCH('-mf', 'branch1', 'movedBranch1');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "/* client a */ SELECT DATABASE(), ACTIVE_BRANCH();",
+				Expected: []sql.Row{{"dolt/movedBranch1", "movedBranch1"}},
+			},
+			{
+				Query:    "/* client a */ select * from t;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "/* client b */ select * from `dolt/movedBranch1`.t;",
+				Expected: []sql.Row{{1}},
+			},
 		},
 	},
 	{
@@ -484,6 +550,54 @@ func TestDropDatabaseMultiSessionBehavior(t *testing.T) {
 	testMultiSessionScriptTests(t, DropDatabaseMultiSessionScriptTests)
 }
 
+// TestWorkspaceMultiSessionBehavior tests that session workspaces can't be used by any session other than the one that
+// opened them, and that they're discarded once that session disconnects.
+func TestWorkspaceMultiSessionBehavior(t *testing.T) {
+	dEnv, sc, serverConfig := startServer(t, true, "", "")
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+	defer dEnv.DoltDB.Close()
+
+	conn1, sess1 := newConnection(t, serverConfig)
+	conn2, sess2 := newConnection(t, serverConfig)
+	defer conn2.Close()
+
+	_, err = sess1.Exec("create table t (pk int primary key);")
+	require.NoError(t, err)
+	_, err = sess1.Exec("call dolt_commit('-Am', 'created table t');")
+	require.NoError(t, err)
+
+	rows, err := sess1.Query("call dolt_workspace_begin();")
+	require.NoError(t, err)
+	require.True(t, rows.Next())
+	var wsName string
+	require.NoError(t, rows.Scan(&wsName))
+	require.NoError(t, rows.Close())
+
+	_, err = sess1.Exec("insert into t values (1);")
+	require.NoError(t, err)
+
+	rows, err = sess1.Query("select * from `dolt/" + wsName + "`.t;")
+	require.NoError(t, err)
+	assertResultsEqual(t, []sql.Row{{1}}, rows)
+	require.NoError(t, rows.Close())
+
+	_, err = sess2.Query("select * from `dolt/" + wsName + "`.t;")
+	require.Error(t, err)
+	_, err = sess2.Exec("use `dolt/" + wsName + "`;")
+	require.Error(t, err)
+
+	require.NoError(t, conn1.Close())
+	require.Eventually(t, func() bool {
+		workspaces, err := dEnv.DoltDB.GetWorkspaces(context.Background())
+		return err == nil && len(workspaces) == 0
+	}, 10*time.Second, 100*time.Millisecond)
+
+	sc.StopServer()
+	err = sc.WaitForClose()
+	require.NoError(t, err)
+}
+
 // TestPersistVariable tests persisting variables across server starts
 func TestPersistVariable(t *testing.T) {
 	testSerialSessionScriptTests(t, PersistVariableTests)
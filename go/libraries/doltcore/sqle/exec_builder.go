@@ -0,0 +1,77 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// execBuilder wraps the engine's sql.NodeExecBuilder to report the errors that sql.DatabaseProvider.AllDatabases
+// can't. When AllDatabases can't load a revision database, for SHOW DATABASES or information_schema.schemata, it
+// records the error in the session, and the statement fails with it once its rows have been read. It also rejects
+// plans that filter on columns masked from the current user.
+type execBuilder struct {
+	sql.NodeExecBuilder
+}
+
+var _ sql.NodeExecBuilder = execBuilder{}
+
+// NewExecBuilder returns a sql.NodeExecBuilder that builds nodes with |builder|, but fails statements that listed
+// databases when one of them couldn't be loaded, and statements that filter on masked columns.
+func NewExecBuilder(builder sql.NodeExecBuilder) sql.NodeExecBuilder {
+	return execBuilder{NodeExecBuilder: builder}
+}
+
+// Build implements sql.NodeExecBuilder
+func (b execBuilder) Build(ctx *sql.Context, n sql.Node, r sql.Row) (sql.RowIter, error) {
+	sess, ok := ctx.Session.(*dsess.DoltSession)
+	if !ok {
+		return b.NodeExecBuilder.Build(ctx, n, r)
+	}
+	if err := checkMaskedColumnFilters(ctx, n); err != nil {
+		return nil, err
+	}
+	iter, err := b.NodeExecBuilder.Build(ctx, n, r)
+	if err != nil {
+		sess.TakeListDatabasesErr()
+		return nil, err
+	}
+	if err := sess.TakeListDatabasesErr(); err != nil {
+		iter.Close(ctx)
+		return nil, err
+	}
+	return listDatabasesErrIter{RowIter: iter, sess: sess}, nil
+}
+
+// listDatabasesErrIter returns the error recorded by dsess.DoltSession.SetListDatabasesErr while its rows were read,
+// if any, instead of io.EOF.
+type listDatabasesErrIter struct {
+	sql.RowIter
+	sess *dsess.DoltSession
+}
+
+func (i listDatabasesErrIter) Next(ctx *sql.Context) (sql.Row, error) {
+	row, err := i.RowIter.Next(ctx)
+	if err == io.EOF {
+		if listErr := i.sess.TakeListDatabasesErr(); listErr != nil {
+			return nil, listErr
+		}
+	}
+	return row, err
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+var errListDatabases = errors.New("cannot load revision database")
+
+// listingBuilder builds nodes whose rows list databases, as SHOW DATABASES does when it's built and
+// information_schema.schemata does when its rows are read.
+type listingBuilder struct {
+	failOnBuild, failOnRead bool
+}
+
+func (b listingBuilder) Build(ctx *sql.Context, _ sql.Node, _ sql.Row) (sql.RowIter, error) {
+	if b.failOnBuild {
+		ctx.Session.(*dsess.DoltSession).SetListDatabasesErr(errListDatabases)
+	}
+	return &listingIter{rows: []sql.Row{{"mydb"}}, fail: b.failOnRead}, nil
+}
+
+type listingIter struct {
+	rows []sql.Row
+	fail bool
+}
+
+func (i *listingIter) Next(ctx *sql.Context) (sql.Row, error) {
+	if i.fail {
+		ctx.Session.(*dsess.DoltSession).SetListDatabasesErr(errListDatabases)
+		i.fail = false
+	}
+	if len(i.rows) == 0 {
+		return nil, io.EOF
+	}
+	row := i.rows[0]
+	i.rows = i.rows[1:]
+	return row, nil
+}
+
+func (i *listingIter) Close(*sql.Context) error {
+	return nil
+}
+
+func TestExecBuilder(t *testing.T) {
+	pro, err := NewDoltDatabaseProvider("main", filesys.EmptyInMemFS("/"))
+	require.NoError(t, err)
+	ctx := sql.NewContext(context.Background(), sql.WithSession(dsess.DefaultSession(pro)))
+	node := plan.NewShowDatabases()
+
+	// databases that list without errors
+	iter, err := NewExecBuilder(listingBuilder{}).Build(ctx, node, nil)
+	require.NoError(t, err)
+	rows, err := sql.RowIterToRows(ctx, nil, iter)
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"mydb"}}, rows)
+
+	// an error recorded while the node is built fails the statement right away
+	_, err = NewExecBuilder(listingBuilder{failOnBuild: true}).Build(ctx, node, nil)
+	assert.ErrorIs(t, err, errListDatabases)
+
+	// an error recorded while rows are read fails the statement once they've all been read
+	iter, err = NewExecBuilder(listingBuilder{failOnRead: true}).Build(ctx, node, nil)
+	require.NoError(t, err)
+	_, err = sql.RowIterToRows(ctx, nil, iter)
+	assert.ErrorIs(t, err, errListDatabases)
+
+	// the error is only reported once
+	iter, err = NewExecBuilder(listingBuilder{}).Build(ctx, node, nil)
+	require.NoError(t, err)
+	_, err = sql.RowIterToRows(ctx, nil, iter)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package formatupgrade
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the state of a database's storage format upgrade.
+type Status string
+
+const (
+	// Pending upgrades are waiting for an earlier upgrade to finish, or were canceled when the server stopped.
+	Pending Status = "pending"
+	// Migrating upgrades are copying the database's history into the new format.
+	Migrating Status = "migrating"
+	// Done upgrades have swapped the migrated database in for the old one.
+	Done Status = "done"
+	// Failed upgrades left the database in its old format.
+	Failed Status = "failed"
+)
+
+// Upgrade is the progress of the storage format upgrade of a single database.
+type Upgrade struct {
+	Database string
+	Status   Status
+	// OldFormat and NewFormat are the version strings of the database's format before and after the upgrade
+	OldFormat string
+	NewFormat string
+	// Attempts is the number of times the database has been migrated. A migration is retried when the database is
+	// written to while it runs.
+	Attempts int
+	Error    string
+	Created  time.Time
+	Updated  time.Time
+}
+
+// Tracker records the progress of the storage format upgrades run by a server.
+type Tracker struct {
+	mu       *sync.Mutex
+	upgrades map[string]*Upgrade
+}
+
+// NewTracker returns a new Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{mu: &sync.Mutex{}, upgrades: make(map[string]*Upgrade)}
+}
+
+// Add records a pending upgrade of the database named from |oldFormat| to |newFormat|.
+func (t *Tracker) Add(dbName, oldFormat, newFormat string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	t.upgrades[strings.ToLower(dbName)] = &Upgrade{
+		Database:  dbName,
+		Status:    Pending,
+		OldFormat: oldFormat,
+		NewFormat: newFormat,
+		Created:   now,
+		Updated:   now,
+	}
+}
+
+// Update applies |f| to the upgrade of the database named, if there is one.
+func (t *Tracker) Update(dbName string, f func(u *Upgrade)) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u, ok := t.upgrades[strings.ToLower(dbName)]
+	if !ok {
+		return
+	}
+	f(u)
+	u.Updated = time.Now()
+}
+
+// Upgrades returns every upgrade recorded, ordered by database name.
+func (t *Tracker) Upgrades() []Upgrade {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	res := make([]Upgrade, 0, len(t.upgrades))
+	for _, u := range t.upgrades {
+		res = append(res, *u)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Database < res[j].Database
+	})
+	return res
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package formatupgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Add("zoo", "__LD_1__", "__DOLT__")
+	tracker.Add("Abc", "__LD_1__", "__DOLT__")
+
+	tracker.Update("ABC", func(u *Upgrade) {
+		u.Status = Migrating
+		u.Attempts++
+	})
+	// updates of databases without an upgrade are ignored
+	tracker.Update("missing", func(u *Upgrade) {
+		u.Status = Done
+	})
+
+	upgrades := tracker.Upgrades()
+	require.Len(t, upgrades, 2)
+	assert.Equal(t, "Abc", upgrades[0].Database)
+	assert.Equal(t, Migrating, upgrades[0].Status)
+	assert.Equal(t, 1, upgrades[0].Attempts)
+	assert.Equal(t, "zoo", upgrades[1].Database)
+	assert.Equal(t, Pending, upgrades[1].Status)
+
+	// a nil tracker records nothing
+	var none *Tracker
+	none.Add("abc", "__LD_1__", "__DOLT__")
+	none.Update("abc", func(u *Upgrade) {})
+	assert.Empty(t, none.Upgrades())
+}
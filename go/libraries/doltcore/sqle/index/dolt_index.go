@@ -42,6 +42,9 @@ const (
 	CommitHashIndexId = "commit_hash"
 	ToCommitIndexId   = "to_commit"
 	FromCommitIndexId = "from_commit"
+	CommitterIndexId  = "committer"
+	EmailIndexId      = "email"
+	DateIndexId       = "date"
 )
 
 type DoltTableable interface {
@@ -103,6 +106,50 @@ func (p *CommitIndex) CanSupport(ranges ...sql.Range) bool {
 
 var _ DoltIndex = (*CommitIndex)(nil)
 
+func NewCommitMetaColumnIndex(i *doltIndex) *CommitMetaColumnIndex {
+	return &CommitMetaColumnIndex{doltIndex: i}
+}
+
+// CommitMetaColumnIndex is an index on a column of commit metadata, such as the author or the date of commits. Unlike
+// CommitIndex, it supports any range lookup on its column, which is answered from the commit metadata index of the
+// database rather than by walking the commit graph.
+type CommitMetaColumnIndex struct {
+	*doltIndex
+}
+
+func (p *CommitMetaColumnIndex) CanSupport(ranges ...sql.Range) bool {
+	for _, r := range ranges {
+		if len(r) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+var _ DoltIndex = (*CommitMetaColumnIndex)(nil)
+
+// CommitMetaInRanges returns whether the value |v| of a commit metadata column is in any of |ranges|, which are ranges
+// over a CommitMetaColumnIndex.
+func CommitMetaInRanges(v interface{}, ranges sql.RangeCollection) (bool, error) {
+	for _, r := range ranges {
+		if len(r) != 1 {
+			return false, fmt.Errorf("unexpected commit metadata lookup range: %s", r.String())
+		}
+		lb, err := r[0].LowerBound.Compare(sql.Below{Key: v}, r[0].Typ)
+		if err != nil {
+			return false, err
+		}
+		ub, err := r[0].UpperBound.Compare(sql.Above{Key: v}, r[0].Typ)
+		if err != nil {
+			return false, err
+		}
+		if lb <= 0 && ub >= 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func DoltDiffIndexesFromTable(ctx context.Context, db, tbl string, t *doltdb.Table) (indexes []sql.Index, err error) {
 	sch, err := t.GetSchema(ctx)
 	if err != nil {
@@ -231,6 +278,21 @@ func DoltCommitIndexes(tab string, db *doltdb.DoltDB, unique bool) (indexes []sq
 	}, nil
 }
 
+// DoltCommitMetaIndexes returns the indexes on the commit hash, committer, email and date columns of a system table
+// that lists commits, such as dolt_log.
+func DoltCommitMetaIndexes(tab string, db *doltdb.DoltDB) (indexes []sql.Index, err error) {
+	if !types.IsFormat_DOLT(db.Format()) {
+		return nil, nil
+	}
+
+	return []sql.Index{
+		NewCommitIndex(MockIndex(CommitHashIndexId, tab, types.StringKind, true)),
+		NewCommitMetaColumnIndex(MockIndex(CommitterIndexId, tab, types.StringKind, false)),
+		NewCommitMetaColumnIndex(MockIndex(EmailIndexId, tab, types.StringKind, false)),
+		NewCommitMetaColumnIndex(MockIndex(DateIndexId, tab, types.TimestampKind, false)),
+	}, nil
+}
+
 func DoltIndexesFromTable(ctx context.Context, db, tbl string, t *doltdb.Table) (indexes []sql.Index, err error) {
 	sch, err := t.GetSchema(ctx)
 	if err != nil {
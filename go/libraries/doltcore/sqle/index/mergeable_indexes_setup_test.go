@@ -31,6 +31,7 @@ import (
 	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -98,12 +99,12 @@ func setupIndexes(t *testing.T, tableName, insertQuery string) (*sqle.Engine, *s
 	mrEnv, err := env.MultiEnvForDirectory(context.Background(), dEnv.Config.WriteableConfig(), dEnv.FS, dEnv.Version, dEnv.IgnoreLockFile, dEnv)
 	require.NoError(t, err)
 	b := env.GetDefaultInitBranch(dEnv.Config)
-	pro, err := dsqle.NewDoltDatabaseProviderWithDatabase(b, mrEnv.FileSystem(), db, dEnv.FS)
+	cfg := dsqle.NewDoltDatabaseProviderConfig(b, mrEnv.FileSystem()).WithDbFactoryUrl(doltdb.InMemDoltDB)
+	pro, err := dsqle.NewDoltDatabaseProviderFromConfig(cfg, []dsess.SqlDatabase{db}, []filesys.Filesys{dEnv.FS})
 	if err != nil {
 		return nil, nil, nil
 	}
 
-	pro = pro.WithDbFactoryUrl(doltdb.InMemDoltDB)
 	engine = sqle.NewDefault(pro)
 
 	it := []*indexTuple{
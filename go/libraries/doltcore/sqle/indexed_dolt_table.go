@@ -66,6 +66,9 @@ func (idt *IndexedDoltTable) Collation() sql.CollationID {
 }
 
 func (idt *IndexedDoltTable) LookupPartitions(ctx *sql.Context, lookup sql.IndexLookup) (sql.PartitionIter, error) {
+	if err := idt.table.checkMaskedLookup(ctx, lookup.Index); err != nil {
+		return nil, err
+	}
 	return index.NewRangePartitionIter(ctx, idt.table, lookup, idt.isDoltFormat)
 }
 
@@ -88,7 +91,11 @@ func (idt *IndexedDoltTable) PartitionRows(ctx *sql.Context, part sql.Partition)
 		}
 	}
 
-	return idt.lb.NewRowIter(ctx, part)
+	iter, err := idt.lb.NewRowIter(ctx, part)
+	if err != nil {
+		return nil, err
+	}
+	return idt.table.maskRows(ctx, iter)
 }
 
 func (idt *IndexedDoltTable) PartitionRows2(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
@@ -105,7 +112,11 @@ func (idt *IndexedDoltTable) PartitionRows2(ctx *sql.Context, part sql.Partition
 		}
 	}
 
-	return idt.lb.NewRowIter(ctx, part)
+	iter, err := idt.lb.NewRowIter(ctx, part)
+	if err != nil {
+		return nil, err
+	}
+	return idt.table.maskRows(ctx, iter)
 }
 
 func (idt *IndexedDoltTable) IsTemporary() bool {
@@ -137,6 +148,9 @@ type WritableIndexedDoltTable struct {
 }
 
 func (t *WritableIndexedDoltTable) LookupPartitions(ctx *sql.Context, lookup sql.IndexLookup) (sql.PartitionIter, error) {
+	if err := t.checkMaskedLookup(ctx, lookup.Index); err != nil {
+		return nil, err
+	}
 	return index.NewRangePartitionIter(ctx, t.DoltTable, lookup, t.isDoltFormat)
 }
 
@@ -158,7 +172,11 @@ func (t *WritableIndexedDoltTable) PartitionRows(ctx *sql.Context, part sql.Part
 		}
 	}
 
-	return t.lb.NewRowIter(ctx, part)
+	iter, err := t.lb.NewRowIter(ctx, part)
+	if err != nil {
+		return nil, err
+	}
+	return t.maskRows(ctx, iter)
 }
 
 // WithProjections implements sql.ProjectedTable
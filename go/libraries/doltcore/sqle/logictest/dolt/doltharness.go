@@ -292,12 +292,12 @@ func sqlNewEngine(dEnv *env.DoltEnv) (*sqle.Engine, dsess.DoltDatabaseProvider,
 	}
 
 	b := env.GetDefaultInitBranch(dEnv.Config)
-	pro, err := dsql.NewDoltDatabaseProviderWithDatabase(b, mrEnv.FileSystem(), db, dEnv.FS)
+	cfg := dsql.NewDoltDatabaseProviderConfig(b, mrEnv.FileSystem()).WithDbFactoryUrl(doltdb.InMemDoltDB)
+	pro, err := dsql.NewDoltDatabaseProviderFromConfig(cfg, []dsess.SqlDatabase{db}, []filesys.Filesys{dEnv.FS})
 	if err != nil {
 		return nil, nil, err
 	}
 
-	pro = pro.WithDbFactoryUrl(doltdb.InMemDoltDB)
 	engine := sqle.NewDefault(pro)
 
 	return engine, pro, nil
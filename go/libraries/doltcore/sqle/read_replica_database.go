@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
@@ -39,6 +40,7 @@ type ReadReplicaDatabase struct {
 	srcDB   *doltdb.DoltDB
 	tmpDir  string
 	limiter *limiter
+	refresh *replicaRefresh
 }
 
 var _ dsess.SqlDatabase = ReadReplicaDatabase{}
@@ -82,6 +84,7 @@ func NewReadReplicaDatabase(ctx context.Context, db Database, remoteName string,
 		tmpDir:   tmpDir,
 		srcDB:    srcDB,
 		limiter:  newLimiter(),
+		refresh:  &replicaRefresh{},
 	}, nil
 }
 
@@ -89,6 +92,9 @@ func (rrd ReadReplicaDatabase) WithBranchRevision(requestedName string, branchSp
 	rrd.rsr, rrd.rsw = branchSpec.RepoState, branchSpec.RepoState
 	rrd.revision = branchSpec.Branch
 	rrd.revType = dsess.RevisionTypeBranch
+	if branchSpec.Workspace {
+		rrd.revType = dsess.RevisionTypeWorkspace
+	}
 	rrd.requestedName = requestedName
 
 	return rrd, nil
@@ -110,7 +116,27 @@ func (rrd ReadReplicaDatabase) DoltDatabases() []*doltdb.DoltDB {
 	return []*doltdb.DoltDB{rrd.ddb, rrd.srcDB}
 }
 
+// PullFromRemote implements dsess.RemoteReadReplicaDatabase. It doesn't pull if the replica last pulled within
+// @@dolt_read_replica_refresh_interval, so the transaction reads the heads it pulled then.
 func (rrd ReadReplicaDatabase) PullFromRemote(ctx *sql.Context) error {
+	start := time.Now()
+	if !rrd.refresh.due(start, ReadReplicaRefreshInterval()) {
+		return nil
+	}
+	err := rrd.pullFromRemote(ctx)
+	rrd.refresh.pulled(start, err)
+	return err
+}
+
+// ForcePullFromRemote implements dsess.RemoteReadReplicaDatabase.
+func (rrd ReadReplicaDatabase) ForcePullFromRemote(ctx *sql.Context) error {
+	start := time.Now()
+	err := rrd.pullFromRemote(ctx)
+	rrd.refresh.pulled(start, err)
+	return err
+}
+
+func (rrd ReadReplicaDatabase) pullFromRemote(ctx *sql.Context) error {
 	_, headsArg, ok := sql.SystemVariables.GetGlobal(dsess.ReplicateHeads)
 	if !ok {
 		return sql.ErrUnknownSystemVariable.New(dsess.ReplicateHeads)
@@ -233,9 +259,81 @@ func (rrd ReadReplicaDatabase) PullFromRemote(ctx *sql.Context) error {
 		return nil
 	}
 
+	err = pullDefaultBranch(ctx, rrd)
+	if err != nil && !dsess.IgnoreReplicationErrors() {
+		return err
+	} else if err != nil {
+		dsess.WarnReplicationError(ctx, err)
+	}
+
 	return nil
 }
 
+// replicaRefresh records when a read replica last pulled from its remote, and the error of its last pull if it failed.
+// It's shared by the revision databases of the replica.
+type replicaRefresh struct {
+	mu       sync.Mutex
+	lastPull time.Time
+	lastErr  error
+}
+
+// due returns whether a replica should pull at |now| when it refreshes at most every |interval|.
+func (r *replicaRefresh) due(now time.Time, interval time.Duration) bool {
+	if r == nil || interval <= 0 {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastPull.IsZero() || now.Sub(r.lastPull) >= interval
+}
+
+// pulled records a pull that started at |start| and failed with |err|, if it isn't nil. Replicas are only as fresh as
+// the start of their last successful pull.
+func (r *replicaRefresh) pulled(start time.Time, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastErr = err
+	if err == nil && start.After(r.lastPull) {
+		r.lastPull = start
+	}
+}
+
+// status returns when the replica last pulled successfully, and the error of its last pull.
+func (r *replicaRefresh) status() (time.Time, error) {
+	if r == nil {
+		return time.Time{}, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastPull, r.lastErr
+}
+
+// pullDefaultBranch records the default branch of the remote database as the default branch of the replica, as long as
+// that branch has been replicated.
+func pullDefaultBranch(ctx *sql.Context, rrd ReadReplicaDatabase) error {
+	remoteDefault, err := rrd.srcDB.GetDefaultBranch(ctx)
+	if err != nil || remoteDefault == "" {
+		return err
+	}
+
+	localDefault, err := rrd.ddb.GetDefaultBranch(ctx)
+	if err != nil || localDefault == remoteDefault {
+		return err
+	}
+
+	ok, err := rrd.ddb.HasRef(ctx, ref.NewBranchRef(remoteDefault))
+	if err != nil || !ok {
+		return err
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	meta := datas.NewTagMeta(dSess.Username(), dSess.Email(), "")
+	return rrd.ddb.SetDefaultBranch(ctx, remoteDefault, meta, nil)
+}
+
 // CreateLocalBranchFromRemote pulls the given branch from the remote database and creates a local tracking branch for
 // it. This is only used for initializing a new local branch being pulled from a remote during connection
 // initialization, and doesn't do the full work of remote synchronization that happens on transaction start.
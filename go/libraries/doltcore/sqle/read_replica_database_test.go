@@ -16,6 +16,7 @@ package sqle
 
 import (
 	"context"
+	"errors"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -78,3 +79,28 @@ func TestLimiter(t *testing.T) {
 		assert.Equal(t, int32(1), numRuns)
 	})
 }
+
+func TestReplicaRefresh(t *testing.T) {
+	now := time.Now()
+	r := &replicaRefresh{}
+	assert.True(t, r.due(now, time.Second))
+
+	r.pulled(now, nil)
+	assert.False(t, r.due(now.Add(500*time.Millisecond), time.Second))
+	assert.True(t, r.due(now.Add(time.Second), time.Second))
+	// without an interval, replicas pull at the start of every transaction
+	assert.True(t, r.due(now.Add(500*time.Millisecond), 0))
+
+	// a pull that finishes after a later one started doesn't make the replica look fresher
+	r.pulled(now.Add(-time.Second), nil)
+	assert.False(t, r.due(now.Add(500*time.Millisecond), time.Second))
+
+	// failed pulls are reported, but don't make the replica fresher
+	r.pulled(now.Add(time.Second), errors.New("remote unavailable"))
+	lastPull, err := r.status()
+	assert.Equal(t, now, lastPull)
+	assert.EqualError(t, err, "remote unavailable")
+
+	var empty *replicaRefresh
+	assert.True(t, empty.due(now, time.Second))
+}
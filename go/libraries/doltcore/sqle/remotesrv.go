@@ -15,17 +15,25 @@
 package sqle
 
 import (
+	"context"
+	"sync"
+
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotesrv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/serverhooks"
 	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 type remotesrvStore struct {
 	ctx      *sql.Context
 	readonly bool
+	// hooksMu serializes the post_receive hooks, which share |ctx|
+	hooksMu *sync.Mutex
 }
 
 var _ remotesrv.DBCache = remotesrvStore{}
@@ -58,13 +66,71 @@ func (s remotesrvStore) Get(path, nbfVerStr string) (remotesrv.RemoteSrvStore, e
 	if !ok {
 		return nil, remotesrv.ErrUnimplemented
 	}
-	return rss, nil
+	return dbStore{rss, s, path, sdb.DbData().Ddb}, nil
+}
+
+// dbStore is the store for a database served by the remotesapi. It rejects pushes that would delete or move any of the
+// tags protected by @@dolt_protected_tags, and fires the webhooks and runs the post_receive server hooks for the refs a
+// push updates.
+type dbStore struct {
+	remotesrv.RemoteSrvStore
+	srv    remotesrvStore
+	dbName string
+	ddb    *doltdb.DoltDB
+}
+
+var _ remotesrv.CommitValidator = dbStore{}
+var _ remotesrv.CommitListener = dbStore{}
+
+func (s dbStore) ValidateCommit(ctx context.Context, last, current hash.Hash) error {
+	return s.ddb.CheckProtectedTags(ctx, dsess.ProtectedTagPatterns(), last, current)
+}
+
+func (s dbStore) CommitApplied(ctx context.Context, last, current hash.Hash) {
+	logger := s.srv.ctx.GetLogger().WithField("database", s.dbName)
+	updates, err := s.ddb.RefUpdatesByNomsRoot(ctx, last, current)
+	if err != nil {
+		logger.WithError(err).Warn("error reading refs updated by push")
+		return
+	}
+
+	pro := dsess.DSessFromSess(s.srv.ctx.Session).Provider()
+	refs := make([]ref.DoltRef, len(updates))
+	for i, u := range updates {
+		refs[i] = u.Ref
+	}
+	err = dsess.WebhooksFor(pro).FirePushed(ctx, s.dbName, s.ddb, refs)
+	if err != nil {
+		logger.WithError(err).Warn("error firing webhooks for push")
+	}
+
+	hooks := dsess.ServerHooksFor(pro)
+	if len(hooks.Hooks(serverhooks.PostReceive)) == 0 {
+		return
+	}
+
+	s.srv.hooksMu.Lock()
+	defer s.srv.hooksMu.Unlock()
+	sqlCtx := sql.NewContext(ctx, sql.WithSession(s.srv.ctx.Session))
+	for _, u := range updates {
+		change := serverhooks.Change{Database: s.dbName, Ref: u.Ref.String()}
+		if !u.OldHash.IsEmpty() {
+			change.OldHash = u.OldHash.String()
+		}
+		if !u.NewHash.IsEmpty() {
+			change.NewHash = u.NewHash.String()
+		}
+		err = hooks.Run(sqlCtx, dsess.StatementRunnerFor(pro), serverhooks.PostReceive, change)
+		if err != nil {
+			logger.WithError(err).Warn("post_receive hook failed")
+		}
+	}
 }
 
 func RemoteSrvServerArgs(ctx *sql.Context, args remotesrv.ServerArgs) remotesrv.ServerArgs {
 	sess := dsess.DSessFromSess(ctx.Session)
 	args.FS = sess.Provider().FileSystem()
-	args.DBCache = remotesrvStore{ctx, args.ReadOnly}
+	args.DBCache = remotesrvStore{ctx, args.ReadOnly, &sync.Mutex{}}
 	return args
 }
 
@@ -18,11 +18,13 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path/filepath"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/sirupsen/logrus"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
@@ -63,6 +65,13 @@ func getPushOnWriteHook(ctx context.Context, bThreads *sql.BackgroundThreads, dE
 		return nil, err
 	}
 	if _, val, ok = sql.SystemVariables.GetGlobal(dsess.AsyncReplication); ok && val == dsess.SysVarTrue {
+		if _, val, ok = sql.SystemVariables.GetGlobal(dsess.AsyncReplicationQueue); ok && val == dsess.SysVarTrue {
+			queue, err := doltdb.LoadReplicationQueue(dEnv.FS, filepath.Join(dbfactory.DoltDir, doltdb.ReplicationQueueFile))
+			if err != nil {
+				return nil, err
+			}
+			return doltdb.NewQueuedPushOnWriteHook(bThreads, queue, dEnv.DoltDB, ddb, tmpDir, logger)
+		}
 		return doltdb.NewAsyncPushOnWriteHook(bThreads, ddb, tmpDir, logger)
 	}
 
@@ -0,0 +1,84 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicationstatus
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the outcome of a read replica's attempt to clone a database from its replication remote.
+type Status string
+
+const (
+	// Cloned databases were cloned from the remote.
+	Cloned Status = "cloned"
+	// NotFound databases don't exist on the remote.
+	NotFound Status = "not_found"
+	// Failed clones couldn't reach the remote, or failed after reaching it.
+	Failed Status = "failed"
+)
+
+// Attempt is the last attempt of a read replica to clone a single database.
+type Attempt struct {
+	Database  string
+	RemoteURL string
+	Status    Status
+	Error     string
+	Attempted time.Time
+}
+
+// Tracker records the last clone attempt of each database a read replica has tried to clone.
+type Tracker struct {
+	mu       *sync.Mutex
+	attempts map[string]Attempt
+}
+
+// NewTracker returns a new Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{mu: &sync.Mutex{}, attempts: make(map[string]Attempt)}
+}
+
+// Record records |a| as the last clone attempt of its database, replacing any earlier one.
+func (t *Tracker) Record(a Attempt) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if a.Attempted.IsZero() {
+		a.Attempted = time.Now()
+	}
+	t.attempts[strings.ToLower(a.Database)] = a
+}
+
+// Attempts returns the last clone attempt of every database, ordered by database name.
+func (t *Tracker) Attempts() []Attempt {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	res := make([]Attempt, 0, len(t.attempts))
+	for _, a := range t.attempts {
+		res = append(res, a)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Database < res[j].Database
+	})
+	return res
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicationstatus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Record(Attempt{Database: "zoo", Status: NotFound})
+	tracker.Record(Attempt{Database: "Abc", Status: Failed, Error: "connection refused"})
+	// a later attempt replaces the earlier one
+	tracker.Record(Attempt{Database: "ABC", Status: Cloned})
+
+	attempts := tracker.Attempts()
+	require.Len(t, attempts, 2)
+	assert.Equal(t, "ABC", attempts[0].Database)
+	assert.Equal(t, Cloned, attempts[0].Status)
+	assert.Empty(t, attempts[0].Error)
+	assert.False(t, attempts[0].Attempted.IsZero())
+	assert.Equal(t, "zoo", attempts[1].Database)
+
+	var nilTracker *Tracker
+	nilTracker.Record(Attempt{Database: "abc"})
+	assert.Empty(t, nilTracker.Attempts())
+}
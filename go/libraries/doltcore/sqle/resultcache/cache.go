@@ -0,0 +1,186 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resultcache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+const (
+	// rowOverhead is the estimated size of a cached row apart from its values
+	rowOverhead = 24
+	// valueOverhead is the estimated size of a value apart from any data it points to
+	valueOverhead = 16
+	// jsonSize is the estimated size of a JSON value, whose real size is expensive to compute
+	jsonSize = 256
+)
+
+// Cache holds the results of read-only queries against databases pinned to a commit or a tag. The roots of those
+// databases never change, so a result stays valid for as long as the roots it was computed from, which are part of
+// its key. Entries are evicted least recently used first once the cache grows past the size set by the
+// dolt_result_cache_size system variable. A size of 0, the default, disables the cache.
+type Cache struct {
+	mu      *sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	size    int64
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+type entry struct {
+	key  string
+	rows []sql.Row
+	size int64
+}
+
+// Stats are the counters and current size of a Cache.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+	Bytes     int64
+}
+
+// New returns a new, empty Cache.
+func New() *Cache {
+	return &Cache{
+		mu:      &sync.Mutex{},
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Stats returns the current stats for this cache.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   len(c.entries),
+		Bytes:     c.size,
+	}
+}
+
+// Purge removes all entries from the cache.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.size = 0
+}
+
+func (c *Cache) get(key string) ([]sql.Row, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(e)
+		c.hits.Add(1)
+		// every query gets its own copy of the rows, since the nodes above it can modify the rows they read
+		rows := e.Value.(*entry).rows
+		res := make([]sql.Row, len(rows))
+		for i, row := range rows {
+			res[i] = copyRow(row)
+		}
+		return res, true
+	}
+	c.misses.Add(1)
+	return nil, false
+}
+
+func (c *Cache) put(key string, rows []sql.Row, size int64, maxSize int64) {
+	if size > maxSize {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		// another query computed the same result concurrently
+		c.lru.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&entry{key: key, rows: rows, size: size})
+	c.size += size
+	c.evict(maxSize)
+}
+
+// evict removes the least recently used entries until the cache is no bigger than |maxSize|. Callers must hold |mu|.
+func (c *Cache) evict(maxSize int64) {
+	for c.size > maxSize {
+		back := c.lru.Back()
+		if back == nil {
+			return
+		}
+		e := c.lru.Remove(back).(*entry)
+		delete(c.entries, e.key)
+		c.size -= e.size
+		c.evictions.Add(1)
+	}
+}
+
+// limits returns the maximum size in bytes of the cache, and the maximum number of rows in a cached result, from the
+// system variables that configure them.
+func limits() (maxSize int64, maxRows int64) {
+	if _, val, ok := sql.SystemVariables.GetGlobal(dsess.ResultCacheSize); ok {
+		maxSize, _ = val.(int64)
+	}
+	if _, val, ok := sql.SystemVariables.GetGlobal(dsess.ResultCacheMaxRows); ok {
+		maxRows, _ = val.(int64)
+	}
+	return maxSize, maxRows
+}
+
+// copyRow returns a copy of |row| that shares none of its byte slices.
+func copyRow(row sql.Row) sql.Row {
+	res := row.Copy()
+	for i, v := range res {
+		if b, ok := v.([]byte); ok {
+			res[i] = append([]byte(nil), b...)
+		}
+	}
+	return res
+}
+
+// rowSize returns the estimated memory used by a cached copy of |row|.
+func rowSize(row sql.Row) int64 {
+	size := int64(rowOverhead)
+	for _, v := range row {
+		size += valueOverhead
+		switch v := v.(type) {
+		case string:
+			size += int64(len(v))
+		case []byte:
+			size += int64(len(v))
+		case types.JSONValue:
+			size += jsonSize
+		}
+	}
+	return size
+}
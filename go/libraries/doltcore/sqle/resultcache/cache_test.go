@@ -0,0 +1,77 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resultcache
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheEviction(t *testing.T) {
+	rows := []sql.Row{{int64(1), "one"}, {int64(2), "two"}}
+	size := rowSize(rows[0]) + rowSize(rows[1])
+	maxSize := 2 * size
+
+	c := New()
+	_, ok := c.get("a")
+	assert.False(t, ok)
+
+	c.put("a", rows, size, maxSize)
+	c.put("b", rows, size, maxSize)
+	got, ok := c.get("a")
+	require.True(t, ok)
+	assert.Equal(t, rows, got)
+
+	// each hit gets its own copy of the rows
+	got[0][1] = "changed"
+	got, ok = c.get("a")
+	require.True(t, ok)
+	assert.Equal(t, rows, got)
+
+	// "b" is the least recently used entry, so it makes room for "c"
+	c.put("c", rows, size, maxSize)
+	_, ok = c.get("b")
+	assert.False(t, ok)
+	_, ok = c.get("a")
+	assert.True(t, ok)
+	_, ok = c.get("c")
+	assert.True(t, ok)
+
+	// results bigger than the whole cache aren't cached
+	c.put("d", rows, maxSize+1, maxSize)
+	_, ok = c.get("d")
+	assert.False(t, ok)
+
+	assert.Equal(t, Stats{
+		Hits:      4,
+		Misses:    3,
+		Evictions: 1,
+		Entries:   2,
+		Bytes:     maxSize,
+	}, c.Stats())
+
+	c.Purge()
+	assert.Equal(t, 0, c.Stats().Entries)
+	assert.Equal(t, int64(0), c.Stats().Bytes)
+}
+
+func TestRowSize(t *testing.T) {
+	assert.Equal(t, int64(rowOverhead), rowSize(sql.Row{}))
+	assert.Equal(t, int64(rowOverhead+2*valueOverhead+5), rowSize(sql.Row{int64(1), "hello"}))
+	assert.Equal(t, int64(rowOverhead+valueOverhead+3), rowSize(sql.Row{[]byte("abc")}))
+}
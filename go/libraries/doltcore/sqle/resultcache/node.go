@@ -0,0 +1,319 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resultcache
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// RuleId is the id of the analyzer rule that caches query results, added with analyzer.Builder.AddPostValidationRule.
+const RuleId analyzer.RuleId = 10000
+
+// keySessionVars are the session variables that can change the result of a query, and so are part of the cache key.
+var keySessionVars = []string{
+	"sql_mode",
+	"time_zone",
+	"sql_select_limit",
+	"div_precision_increment",
+	"collation_connection",
+	"character_set_results",
+}
+
+// Rule is an analyzer rule that wraps the plan for a cacheable query in a node that serves its results from |c|. A
+// query is cacheable when it's read-only, deterministic, and every table it reads is in a database pinned to a
+// commit or a tag.
+func (c *Cache) Rule(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node, scope *plan.Scope, sel analyzer.RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+	if !scope.IsEmpty() {
+		return n, transform.SameTree, nil
+	}
+	if maxSize, _ := limits(); maxSize <= 0 {
+		return n, transform.SameTree, nil
+	}
+
+	key, ok, err := cacheKey(ctx, n)
+	if err != nil || !ok {
+		return n, transform.SameTree, err
+	}
+
+	return &cachedResult{
+		child:   n,
+		key:     key,
+		cache:   c,
+		builder: a.ExecBuilder,
+	}, transform.NewTree, nil
+}
+
+// cacheKey returns the key for the results of |n|, or false if they can't be cached.
+func cacheKey(ctx *sql.Context, n sql.Node) (string, bool, error) {
+	if !n.IsReadOnly() {
+		return "", false, nil
+	}
+
+	roots := make(map[string]struct{})
+	cacheable := true
+	var err error
+
+	var inspectNode func(node sql.Node) bool
+	inspectExpr := func(e sql.Expression) bool {
+		switch e := e.(type) {
+		case *expression.UserVar, *expression.SystemVar, *expression.BindVar, *expression.ProcedureParam:
+			cacheable = false
+		case sql.NonDeterministicExpression:
+			cacheable = cacheable && !e.IsNonDeterministic()
+		}
+		if sq, ok := e.(*plan.Subquery); ok && cacheable {
+			transform.Inspect(sq.Query, inspectNode)
+		}
+		return !cacheable
+	}
+
+	inspectNode = func(node sql.Node) bool {
+		if !cacheable || err != nil || node == nil {
+			return false
+		}
+
+		switch node := node.(type) {
+		case sql.TableFunction, *plan.Into, *plan.DescribeQuery:
+			cacheable = false
+			return false
+		case *plan.ResolvedTable:
+			if node.AsOf != nil {
+				cacheable = false
+				return false
+			}
+		case *plan.IndexedTableAccess:
+			if rt, ok := node.TableNode.(*plan.ResolvedTable); ok && rt.AsOf != nil {
+				cacheable = false
+				return false
+			}
+		}
+
+		if tn, ok := node.(sql.TableNode); ok {
+			var dbKey string
+			dbKey, cacheable, err = tableRootKey(ctx, tn)
+			if !cacheable || err != nil {
+				return false
+			}
+			roots[dbKey] = struct{}{}
+		}
+
+		if ex, ok := node.(sql.Expressioner); ok {
+			for _, e := range ex.Expressions() {
+				if transform.InspectExpr(e, inspectExpr) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	transform.Inspect(n, inspectNode)
+	if err != nil || !cacheable || len(roots) == 0 {
+		return "", false, err
+	}
+
+	var sb strings.Builder
+	sortedRoots := make([]string, 0, len(roots))
+	for k := range roots {
+		sortedRoots = append(sortedRoots, k)
+	}
+	sort.Strings(sortedRoots)
+	for _, k := range sortedRoots {
+		sb.WriteString(k)
+		sb.WriteByte(0)
+	}
+
+	// Whether masked columns are shown depends on the user's privileges, so results aren't shared between users, or
+	// by a user across changes to the grants. The client's address isn't part of the key, since its port differs
+	// between every connection.
+	_, privCounter := ctx.Session.GetPrivilegeSet()
+	fmt.Fprintf(&sb, "%s\x00%d\x00%t\x00%s\x00", ctx.Session.Client().User, privCounter, dsess.CanUnmask(ctx), ctx.GetCurrentDatabase())
+	for _, name := range keySessionVars {
+		val, err := ctx.GetSessionVariable(ctx, name)
+		if err != nil {
+			return "", false, err
+		}
+		fmt.Fprintf(&sb, "%s=%v\x00", name, val)
+	}
+
+	sb.WriteString(ctx.Query())
+	sb.WriteByte(0)
+	sb.WriteString(n.String())
+	return sb.String(), true, nil
+}
+
+// tableRootKey returns the database and root hash |tn| is read from, along with the version of its database's column
+// masking policies, or false if its database isn't pinned to a commit or a tag. Masks apply to every commit, so a
+// result computed under older masks can't be served once they change.
+func tableRootKey(ctx *sql.Context, tn sql.TableNode) (string, bool, error) {
+	if doltdb.HasDoltPrefix(tn.Name()) {
+		return "", false, nil
+	}
+	if tt, ok := tn.UnderlyingTable().(sql.TemporaryTable); ok && tt.IsTemporary() {
+		return "", false, nil
+	}
+
+	db, ok := tn.Database().(dsess.SqlDatabase)
+	if !ok {
+		return "", false, nil
+	}
+	if rt := db.RevisionType(); rt != dsess.RevisionTypeCommit && rt != dsess.RevisionTypeTag {
+		return "", false, nil
+	}
+
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	h, err := root.HashOf()
+	if err != nil {
+		return "", false, err
+	}
+	masks, err := db.DbData().Ddb.ColumnMasksHash(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	return strings.ToLower(db.RevisionQualifiedName()) + "@" + h.String() + "/" + masks.String(), true, nil
+}
+
+// cachedResult is a node that returns the cached results of its child, computing and caching them on a miss.
+type cachedResult struct {
+	child   sql.Node
+	key     string
+	cache   *Cache
+	builder sql.NodeExecBuilder
+}
+
+var _ sql.ExecSourceRel = (*cachedResult)(nil)
+
+// Resolved implements sql.Node.
+func (n *cachedResult) Resolved() bool {
+	return n.child.Resolved()
+}
+
+// String implements sql.Node.
+func (n *cachedResult) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("CachedResult")
+	_ = pr.WriteChildren(n.child.String())
+	return pr.String()
+}
+
+// Schema implements sql.Node.
+func (n *cachedResult) Schema() sql.Schema {
+	return n.child.Schema()
+}
+
+// Children implements sql.Node.
+func (n *cachedResult) Children() []sql.Node {
+	return []sql.Node{n.child}
+}
+
+// WithChildren implements sql.Node.
+func (n *cachedResult) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(n, len(children), 1)
+	}
+	nn := *n
+	nn.child = children[0]
+	return &nn, nil
+}
+
+// CheckPrivileges implements sql.Node.
+func (n *cachedResult) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return n.child.CheckPrivileges(ctx, opChecker)
+}
+
+// IsReadOnly implements sql.Node.
+func (n *cachedResult) IsReadOnly() bool {
+	return true
+}
+
+// RowIter implements sql.ExecSourceRel.
+func (n *cachedResult) RowIter(ctx *sql.Context, r sql.Row) (sql.RowIter, error) {
+	if rows, ok := n.cache.get(n.key); ok {
+		return sql.RowsToRowIter(rows...), nil
+	}
+
+	iter, err := n.builder.Build(ctx, n.child, r)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSize, maxRows := limits()
+	return &recordingIter{
+		iter:      iter,
+		cache:     n.cache,
+		key:       n.key,
+		maxSize:   maxSize,
+		maxRows:   maxRows,
+		recording: maxSize > 0,
+	}, nil
+}
+
+// recordingIter records the rows of the iterator it wraps, and caches them once they've all been read. Results that
+// grow past the configured limits aren't cached.
+type recordingIter struct {
+	iter      sql.RowIter
+	cache     *Cache
+	key       string
+	rows      []sql.Row
+	size      int64
+	maxSize   int64
+	maxRows   int64
+	recording bool
+}
+
+var _ sql.RowIter = (*recordingIter)(nil)
+
+// Next implements sql.RowIter.
+func (i *recordingIter) Next(ctx *sql.Context) (sql.Row, error) {
+	row, err := i.iter.Next(ctx)
+	if err == io.EOF && i.recording {
+		i.cache.put(i.key, i.rows, i.size, i.maxSize)
+		i.stopRecording()
+	} else if err != nil {
+		i.stopRecording()
+	} else if i.recording {
+		i.rows = append(i.rows, copyRow(row))
+		i.size += rowSize(row)
+		if int64(len(i.rows)) > i.maxRows || i.size > i.maxSize {
+			i.stopRecording()
+		}
+	}
+	return row, err
+}
+
+func (i *recordingIter) stopRecording() {
+	i.recording = false
+	i.rows = nil
+}
+
+// Close implements sql.RowIter.
+func (i *recordingIter) Close(ctx *sql.Context) error {
+	return i.iter.Close(ctx)
+}
@@ -0,0 +1,222 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serverhooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+// Event is the point in a change's life at which a hook runs.
+type Event string
+
+const (
+	// PreCommit hooks run before a commit is created by dolt_commit, and can reject it.
+	PreCommit Event = "pre_commit"
+	// PreMerge hooks run before dolt_merge merges a commit into the current branch, and can reject the merge.
+	PreMerge Event = "pre_merge"
+	// PostReceive hooks run once for every ref updated by a push to the server's remotesapi. The push has already
+	// been accepted, so they cannot reject it.
+	PostReceive Event = "post_receive"
+)
+
+var ErrHookRejected = errors.NewKind("%s hook rejected the change: %s")
+
+// Config is the set of hooks configured for a server. The hooks for an event are run in order, and the first one to
+// reject a change stops the rest from running.
+type Config struct {
+	PreCommit   []Hook `yaml:"pre_commit,omitempty"`
+	PreMerge    []Hook `yaml:"pre_merge,omitempty"`
+	PostReceive []Hook `yaml:"post_receive,omitempty"`
+}
+
+// Hook is a single hook, which either runs an external program or calls a stored procedure. Exactly one of Command
+// and Procedure must be set.
+//
+// A Command is run with the change described by DOLT_HOOK, DOLT_DATABASE, DOLT_REF, DOLT_OLD_HASH, DOLT_NEW_HASH,
+// DOLT_USER and DOLT_MESSAGE in its environment. It rejects the change by exiting with a non-zero status, and its
+// output is returned to the client as the reason.
+//
+// A Procedure is called in the database being changed with the same values as arguments, in that order. It's run by
+// the server's engine with the privileges of User, the account that owns the hook, rather than those of the client
+// making the change. For pre hooks it runs in the transaction making the change, so it can inspect the change with the
+// usual system tables, and a rejection rolls back the statement that made it. It rejects the change by raising an
+// error, e.g. with SIGNAL.
+type Hook struct {
+	Command   []string `yaml:"command,omitempty"`
+	Procedure string   `yaml:"procedure,omitempty"`
+	User      string   `yaml:"user,omitempty"`
+}
+
+// QueryRunner runs queries in the server's engine.
+type QueryRunner interface {
+	Query(ctx *sql.Context, query string) (sql.Schema, sql.RowIter, error)
+}
+
+// Change describes the change a hook is run for. Refs are fully qualified, e.g. refs/heads/main. For a pre_commit
+// hook, NewHash is empty since the commit has not been created yet; for a pre_merge hook it is the commit being merged.
+// For a post_receive hook, OldHash is empty for a created ref and NewHash is empty for a deleted one.
+type Change struct {
+	Database string
+	Ref      string
+	OldHash  string
+	NewHash  string
+	User     string
+	Message  string
+}
+
+// Validate returns an error if any hook is misconfigured.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, event := range []Event{PreCommit, PreMerge, PostReceive} {
+		for i, h := range c.Hooks(event) {
+			if (len(h.Command) == 0) == (len(h.Procedure) == 0) {
+				return fmt.Errorf("hooks.%s[%d]: exactly one of 'command' or 'procedure' must be set", event, i)
+			}
+			if len(h.Procedure) > 0 && len(h.User) == 0 {
+				return fmt.Errorf("hooks.%s[%d]: 'user' must be set for a procedure hook", event, i)
+			} else if len(h.Command) > 0 && len(h.User) > 0 {
+				return fmt.Errorf("hooks.%s[%d]: 'user' can only be set for a procedure hook", event, i)
+			}
+		}
+	}
+	return nil
+}
+
+// Hooks returns the hooks configured for |event|.
+func (c *Config) Hooks(event Event) []Hook {
+	if c == nil {
+		return nil
+	}
+	switch event {
+	case PreCommit:
+		return c.PreCommit
+	case PreMerge:
+		return c.PreMerge
+	case PostReceive:
+		return c.PostReceive
+	default:
+		return nil
+	}
+}
+
+// Run runs the hooks configured for |event| against |change|, returning ErrHookRejected from the first hook that
+// rejects it. Procedures are called with |runner|, which should be the server's engine.
+func (c *Config) Run(ctx *sql.Context, runner QueryRunner, event Event, change Change) error {
+	for _, h := range c.Hooks(event) {
+		var err error
+		if len(h.Command) > 0 {
+			err = runCommand(ctx, event, change, h.Command)
+		} else {
+			err = runProcedure(ctx, runner, event, change, h)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ch Change) args(event Event) []string {
+	return []string{string(event), ch.Database, ch.Ref, ch.OldHash, ch.NewHash, ch.User, ch.Message}
+}
+
+func (ch Change) env(event Event) []string {
+	names := []string{"DOLT_HOOK", "DOLT_DATABASE", "DOLT_REF", "DOLT_OLD_HASH", "DOLT_NEW_HASH", "DOLT_USER", "DOLT_MESSAGE"}
+	args := ch.args(event)
+	env := make([]string, len(names))
+	for i := range names {
+		env[i] = names[i] + "=" + args[i]
+	}
+	return env
+}
+
+func runCommand(ctx *sql.Context, event Event, change Change, command []string) error {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = append(os.Environ(), change.env(event)...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if len(msg) == 0 {
+			msg = err.Error()
+		}
+		return ErrHookRejected.New(event, msg)
+	}
+	return nil
+}
+
+func runProcedure(ctx *sql.Context, runner QueryRunner, event Event, change Change, h Hook) error {
+	if runner == nil {
+		return fmt.Errorf("cannot run %s hook procedure %s: no SQL engine is running", event, h.Procedure)
+	}
+	query, err := callProcedureQuery(change.Database, h.Procedure, change.args(event))
+	if err != nil {
+		return err
+	}
+
+	// The procedure runs as the hook's owner. The session's cached privileges are cleared so that they're looked up
+	// for the owner, and again afterward so that they're looked up for the client.
+	client := ctx.Session.Client()
+	ctx.Session.SetClient(sql.Client{User: h.User, Address: "localhost", Capabilities: client.Capabilities})
+	ctx.Session.SetPrivilegeSet(nil, 0)
+	defer func() {
+		ctx.Session.SetClient(client)
+		ctx.Session.SetPrivilegeSet(nil, 0)
+	}()
+
+	// Pre hooks run inside the statement making the change, and must not commit its transaction out from under it.
+	if event != PostReceive {
+		ignore := ctx.GetIgnoreAutoCommit()
+		ctx.SetIgnoreAutoCommit(true)
+		defer ctx.SetIgnoreAutoCommit(ignore)
+	}
+
+	_, iter, err := runner.Query(ctx, query)
+	if err == nil {
+		_, err = sql.RowIterToRows(ctx, nil, iter)
+	}
+	if err != nil {
+		return ErrHookRejected.New(event, err.Error())
+	}
+	return nil
+}
+
+func callProcedureQuery(database, procedure string, args []string) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("CALL `")
+	sb.WriteString(strings.ReplaceAll(database, "`", "``"))
+	sb.WriteString("`.`")
+	sb.WriteString(strings.ReplaceAll(procedure, "`", "``"))
+	sb.WriteString("`(")
+	values := make([]interface{}, len(args))
+	for i := range args {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("?")
+		values[i] = args[i]
+	}
+	sb.WriteString(")")
+	return dbr.InterpolateForDialect(sb.String(), values, dialect.MySQL)
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serverhooks
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	var nilConfig *Config
+	assert.NoError(t, nilConfig.Validate())
+	assert.NoError(t, (&Config{PreCommit: []Hook{{Command: []string{"true"}}, {Procedure: "check", User: "hooks"}}}).Validate())
+	assert.Error(t, (&Config{PreMerge: []Hook{{}}}).Validate())
+	assert.Error(t, (&Config{PostReceive: []Hook{{Command: []string{"true"}, Procedure: "check"}}}).Validate())
+	assert.Error(t, (&Config{PreCommit: []Hook{{Procedure: "check"}}}).Validate())
+	assert.Error(t, (&Config{PreCommit: []Hook{{Command: []string{"true"}, User: "hooks"}}}).Validate())
+}
+
+func TestCallProcedureQuery(t *testing.T) {
+	query, err := callProcedureQuery("mydb", "check`it", []string{"pre_commit", "mydb", "refs/heads/main", "", "", "bob", "it's done"})
+	require.NoError(t, err)
+	assert.Equal(t, "CALL `mydb`.`check``it`('pre_commit', 'mydb', 'refs/heads/main', '', '', 'bob', 'it\\'s done')", query)
+}
+
+func TestRunCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands are run with sh")
+	}
+
+	ctx := sql.NewEmptyContext()
+	change := Change{Database: "mydb", Ref: "refs/heads/main", OldHash: "abc", User: "bob", Message: "add rows"}
+	config := &Config{
+		PreCommit: []Hook{
+			{Command: []string{"sh", "-c", `test "$DOLT_HOOK $DOLT_REF $DOLT_MESSAGE" = "pre_commit refs/heads/main add rows"`}},
+		},
+		PreMerge: []Hook{
+			{Command: []string{"sh", "-c", `echo "merges into $DOLT_REF are frozen"; exit 1`}},
+		},
+	}
+
+	assert.NoError(t, config.Run(ctx, nil, PreCommit, change))
+	assert.NoError(t, config.Run(ctx, nil, PostReceive, change))
+
+	err := config.Run(ctx, nil, PreMerge, change)
+	require.Error(t, err)
+	assert.True(t, ErrHookRejected.Is(err))
+	assert.Contains(t, err.Error(), "merges into refs/heads/main are frozen")
+}
@@ -785,6 +785,9 @@ func BasicSelectTests() []SelectTest {
 					"Initialize data repository",
 					"",
 					"",
+					nil,
+					nil,
+					nil,
 				},
 			},
 			ExpectedSqlSchema: sql.Schema{
@@ -796,6 +799,9 @@ func BasicSelectTests() []SelectTest {
 				&sql.Column{Name: "latest_commit_message", Type: gmstypes.Text},
 				&sql.Column{Name: "remote", Type: gmstypes.Text},
 				&sql.Column{Name: "branch", Type: gmstypes.Text},
+				&sql.Column{Name: "expires", Type: gmstypes.Datetime},
+				&sql.Column{Name: "description", Type: gmstypes.Text},
+				&sql.Column{Name: "metadata", Type: gmstypes.JSON},
 			},
 		},
 	}
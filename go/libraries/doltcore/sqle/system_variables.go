@@ -15,9 +15,12 @@
 package sqle
 
 import (
+	"time"
+
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 
 	_ "github.com/dolthub/go-mysql-server/sql/variables"
@@ -46,6 +49,30 @@ func AddDoltSystemVariables() {
 			Type:              types.NewSystemStringType(dsess.ReplicationRemoteURLTemplate),
 			Default:           "",
 		},
+		{ // How the values substituted into dolt_replication_remote_url_template are cased
+			Name:              dsess.ReplicationRemoteURLCase,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemEnumType(dsess.ReplicationRemoteURLCase, dsess.URLCasePreserve, dsess.URLCaseLower, dsess.URLCaseUpper),
+			Default:           dsess.URLCasePreserve,
+		},
+		{ // The value of the {org} placeholder in dolt_replication_remote_url_template
+			Name:              dsess.ReplicationRemoteOrg,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemStringType(dsess.ReplicationRemoteOrg),
+			Default:           "",
+		},
+		{ // Remote parameters, such as AWS credentials, for the remotes created from dolt_replication_remote_url_template
+			Name:              dsess.ReplicateRemoteParams,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemStringType(dsess.ReplicateRemoteParams),
+			Default:           "",
+		},
 		{
 			Name:              dsess.ReadReplicaRemote,
 			Scope:             sql.SystemVariableScope_Global,
@@ -62,6 +89,14 @@ func AddDoltSystemVariables() {
 			Type:              types.NewSystemBoolType(dsess.ReadReplicaForcePull),
 			Default:           int8(1),
 		},
+		{ // The number of milliseconds a read replica serves the heads it last pulled before it pulls again. 0 pulls at the start of every transaction.
+			Name:              dsess.ReadReplicaRefreshInterval,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemIntType(dsess.ReadReplicaRefreshInterval, 0, 9223372036854775807, false),
+			Default:           int64(0),
+		},
 		{
 			Name:              dsess.SkipReplicationErrors,
 			Scope:             sql.SystemVariableScope_Global,
@@ -94,6 +129,14 @@ func AddDoltSystemVariables() {
 			Type:              types.NewSystemBoolType(dsess.AsyncReplication),
 			Default:           int8(0),
 		},
+		{ // If true along with dolt_async_replication, async pushes are queued on disk and retried until they succeed.
+			Name:              dsess.AsyncReplicationQueue,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.AsyncReplicationQueue),
+			Default:           int8(0),
+		},
 		{ // If true, causes a Dolt commit to occur when you commit a transaction.
 			Name:              dsess.DoltCommitOnTransactionCommit,
 			Scope:             sql.SystemVariableScope_Both,
@@ -166,6 +209,83 @@ func AddDoltSystemVariables() {
 			Type:              types.NewSystemBoolType(dsess.ShowBranchDatabases),
 			Default:           int8(0),
 		},
+		{ // A delimiter that can be used in place of '/' in revision database names, such as @ for mydb@branch
+			Name:              dsess.AlternateRevisionDelimiter,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemStringType(dsess.AlternateRevisionDelimiter),
+			Default:           "",
+			NotifyChanged: func(_ sql.SystemVariableScope, v sql.SystemVarValue) error {
+				delim, _ := v.Val.(string)
+				dsess.SetAlternateRevisionDelimiter(delim)
+				return nil
+			},
+		},
+		{ // The number of revision databases each session caches
+			Name:              dsess.RevisionDbCacheSize,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemIntType(dsess.RevisionDbCacheSize, 0, 1<<20, false),
+			Default:           int64(dsess.DefaultMaxRevisionDbs),
+		},
+		{
+			Name:              dsess.LenientDatabaseListing,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.LenientDatabaseListing),
+			Default:           int8(0),
+		},
+		{ // The storage format of databases created by CREATE DATABASE, or the server's default when empty
+			Name:              dsess.CreateDatabaseStorageFormat,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemStringType(dsess.CreateDatabaseStorageFormat),
+			Default:           "",
+		},
+		{
+			Name:              dsess.ProtectedTags,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemStringType(dsess.ProtectedTags),
+			Default:           "",
+		},
+		{ // The maximum size in bytes of the cache of results for queries on commits and tags. 0 disables the cache.
+			Name:              dsess.ResultCacheSize,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemIntType(dsess.ResultCacheSize, 0, 9223372036854775807, false),
+			Default:           int64(0),
+		},
+		{ // The most rows a query can return and still have its result cached.
+			Name:              dsess.ResultCacheMaxRows,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemIntType(dsess.ResultCacheMaxRows, 0, 9223372036854775807, false),
+			Default:           int64(10000),
+		},
+		{ // Whether diffs report renamed tables as renames, rather than as a dropped table and an added table
+			Name:              dsess.DiffRenames,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.DiffRenames),
+			Default:           int8(1),
+		},
+		{ // The percentage of rows a dropped table and an added table must have in common for diffs to report a rename
+			Name:              dsess.DiffRenameSimilarity,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemIntType(dsess.DiffRenameSimilarity, 0, 100, false),
+			Default:           int64(diff.DefaultRenameSimilarity),
+		},
 		{
 			Name:    dsess.DoltClusterAckWritesTimeoutSecs,
 			Dynamic: true,
@@ -174,6 +294,8 @@ func AddDoltSystemVariables() {
 			Default: int64(0),
 		},
 	})
+	// NotifyChanged isn't called when variables are added, so the cached delimiter is reset to the default here
+	dsess.SetAlternateRevisionDelimiter("")
 }
 
 func ReadReplicaForcePull() bool {
@@ -183,3 +305,16 @@ func ReadReplicaForcePull() bool {
 	}
 	return forcePull == dsess.SysVarTrue
 }
+
+// ReadReplicaRefreshInterval returns the longest a read replica serves the heads it last pulled before it pulls again.
+func ReadReplicaRefreshInterval() time.Duration {
+	_, interval, ok := sql.SystemVariables.GetGlobal(dsess.ReadReplicaRefreshInterval)
+	if !ok {
+		panic("dolt system variables not loaded")
+	}
+	millis, ok := interval.(int64)
+	if !ok {
+		return 0
+	}
+	return time.Duration(millis) * time.Millisecond
+}
@@ -445,7 +445,34 @@ func (t *DoltTable) PartitionRows(ctx *sql.Context, partition sql.Partition) (sq
 		return nil, err
 	}
 
-	return partitionRows(ctx, table, t.sqlSch.Schema, t.projectedCols, partition)
+	iter, err := partitionRows(ctx, table, t.sqlSch.Schema, t.projectedCols, partition)
+	if err != nil {
+		return nil, err
+	}
+	return t.maskRows(ctx, iter)
+}
+
+// maskRows masks the values of the table's masked columns in the rows of |iter|, unless the current user can see
+// them. Masking policies apply to every revision of the table, so that AS OF queries and the history table don't
+// reveal masked values either.
+func (t *DoltTable) maskRows(ctx *sql.Context, iter sql.RowIter) (sql.RowIter, error) {
+	masker, err := dsess.NewColumnMasker(ctx, t.db.DbData().Ddb, t.Schema(), dsess.TableColumnTag(t.sch))
+	if err != nil {
+		iter.Close(ctx)
+		return nil, err
+	}
+	return masker.RowIter(iter), nil
+}
+
+// checkMaskedLookup returns an error if |idx| is on a column that's masked from the current user, since looking rows
+// up in it compares the real values of the column.
+func (t *DoltTable) checkMaskedLookup(ctx *sql.Context, idx sql.Index) error {
+	exprs := idx.Expressions()
+	cols := make([]string, len(exprs))
+	for i, expr := range exprs {
+		cols[i] = expr[strings.LastIndex(expr, ".")+1:]
+	}
+	return dsess.CheckMaskedColumnLookup(ctx, t.db.DbData().Ddb, t.tableName, t.sch, cols)
 }
 
 func partitionRows(ctx *sql.Context, t *doltdb.Table, sqlSch sql.Schema, projCols []uint64, partition sql.Partition) (sql.RowIter, error) {
@@ -527,19 +554,29 @@ func (t *WritableDoltTable) getTableEditor(ctx *sql.Context) (ed writer.TableWri
 		return nil, err
 	}
 
+	var secondaries []sql.TableEditor
 	if t.sch.Indexes().ContainsFullTextIndex() {
 		ftEditor, err := t.getFullTextEditor(ctx)
 		if err != nil {
 			return nil, err
 		}
-		multiEditor, err := fulltext.CreateMultiTableEditor(ctx, ed, ftEditor)
-		if err != nil {
-			return nil, err
-		}
-		return multiEditor.(writer.TableWriter), nil
-	} else {
+		secondaries = append(secondaries, ftEditor)
+	}
+	vecEditor, ok, err := t.getVectorIndexEditor(ctx, writeSession, setter)
+	if err != nil {
+		return nil, err
+	} else if ok {
+		secondaries = append(secondaries, vecEditor)
+	}
+
+	if len(secondaries) == 0 {
 		return ed, nil
 	}
+	multiEditor, err := fulltext.CreateMultiTableEditor(ctx, ed, secondaries...)
+	if err != nil {
+		return nil, err
+	}
+	return multiEditor.(writer.TableWriter), nil
 }
 
 // getFullTextEditor gathers all pseudo-index tables for a Full-Text index and returns an editor that will write
@@ -759,6 +796,9 @@ func (t *WritableDoltTable) Deleter(ctx *sql.Context) sql.RowDeleter {
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
+	if err := dsess.CheckMaskedTableWrite(ctx, t.db.DbData().Ddb, t.tableName, t.sch); err != nil {
+		return sqlutil.NewStaticErrorEditor(err)
+	}
 	te, err := t.getTableEditor(ctx)
 	if err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
@@ -920,6 +960,9 @@ func (t *WritableDoltTable) Updater(ctx *sql.Context) sql.RowUpdater {
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
 	}
+	if err := dsess.CheckMaskedTableWrite(ctx, t.db.DbData().Ddb, t.tableName, t.sch); err != nil {
+		return sqlutil.NewStaticErrorEditor(err)
+	}
 	te, err := t.getTableEditor(ctx)
 	if err != nil {
 		return sqlutil.NewStaticErrorEditor(err)
@@ -1461,6 +1504,9 @@ func (t *AlterableDoltTable) RewriteInserter(
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return nil, err
 	}
+	if err := dsess.CheckMaskedTableWrite(ctx, t.db.DbData().Ddb, t.tableName, t.sch); err != nil {
+		return nil, err
+	}
 	err := validateSchemaChange(t.Name(), oldSchema, newSchema, oldColumn, newColumn, idxCols)
 	if err != nil {
 		return nil, err
@@ -132,6 +132,7 @@ func NewTestEngine(dEnv *env.DoltEnv, ctx context.Context, db dsess.SqlDatabase)
 	}
 
 	engine := sqle.NewDefault(pro)
+	pro.SetStatementRunner(engine)
 	sqlCtx := NewTestSQLCtxWithProvider(ctx, pro)
 	sqlCtx.SetCurrentDatabase(db.Name())
 	return engine, sqlCtx, nil
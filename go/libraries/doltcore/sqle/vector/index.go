@@ -0,0 +1,169 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+// A vector index is an IVF (inverted file) index on an embedding column. The embeddings of the column are clustered
+// around a number of centroids, and each row is assigned to the list of the centroid nearest its embedding. A search
+// for the nearest neighbors of an embedding only reads the rows in the lists of the centroids nearest to it.
+//
+// Vector indexes are stored in two pseudo-index tables of the root, so they're versioned, diffed and merged along with
+// the tables they index: the centroids of every index are in dolt_vector_indexes, and the lists of the indexes of a
+// table are in dolt_vector_lists_<table>, which is keyed by the indexed column, the list and the primary key of the
+// row.
+
+const (
+	// ListsColumnColumn is the column of a lists table that holds the name of the indexed column
+	ListsColumnColumn = "index_column"
+	// ListsListColumn is the column of a lists table that holds the list a row is assigned to
+	ListsListColumn = "list_id"
+)
+
+// IndexesSchema is the schema of the dolt_vector_indexes table.
+var IndexesSchema = sql.PrimaryKeySchema{
+	Schema: sql.Schema{
+		{Name: "table_name", Type: types.MustCreateStringWithDefaults(sqltypes.VarChar, 64), Source: doltdb.VectorIndexesTableName, PrimaryKey: true},
+		{Name: "column_name", Type: types.MustCreateStringWithDefaults(sqltypes.VarChar, 64), Source: doltdb.VectorIndexesTableName, PrimaryKey: true},
+		{Name: "list_id", Type: types.Int32, Source: doltdb.VectorIndexesTableName, PrimaryKey: true},
+		{Name: "centroid", Type: types.JSON, Source: doltdb.VectorIndexesTableName, PrimaryKey: false},
+	},
+	PkOrdinals: []int{0, 1, 2},
+}
+
+// ListsSchema returns the schema of the lists table of a table whose primary key columns are |pkCols|.
+func ListsSchema(tableName string, pkCols sql.Schema) sql.PrimaryKeySchema {
+	listsTableName := doltdb.VectorIndexListsTableName(tableName)
+	sch := sql.Schema{
+		{Name: ListsColumnColumn, Type: types.MustCreateStringWithDefaults(sqltypes.VarChar, 64), Source: listsTableName, PrimaryKey: true},
+		{Name: ListsListColumn, Type: types.Int32, Source: listsTableName, PrimaryKey: true},
+	}
+	ordinals := []int{0, 1}
+	for _, col := range pkCols {
+		sch = append(sch, &sql.Column{
+			Name:       col.Name,
+			Type:       col.Type,
+			Source:     listsTableName,
+			PrimaryKey: true,
+		})
+		ordinals = append(ordinals, len(ordinals))
+	}
+	return sql.PrimaryKeySchema{Schema: sch, PkOrdinals: ordinals}
+}
+
+// Index is a vector index on a column of a table.
+type Index struct {
+	Table     string
+	Column    string
+	Centroids [][]float64
+}
+
+// ListsRow returns the row of the lists table that assigns the row with primary key |pk| to the list |list| of the
+// index.
+func (idx Index) ListsRow(list int, pk sql.Row) sql.Row {
+	return append(sql.Row{idx.Column, int32(list)}, pk...)
+}
+
+// LoadIndexes returns the vector indexes of the table |tableName| listed in |indexesTable|, the dolt_vector_indexes
+// table, ordered by column. If |tableName| is empty, returns the indexes of every table.
+func LoadIndexes(ctx *sql.Context, indexesTable sql.Table, tableName string) ([]Index, error) {
+	rows, err := TableRows(ctx, indexesTable)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct{ table, column string }
+	lists := make(map[key]map[int32][]float64)
+	var keys []key
+	for _, r := range rows {
+		k := key{table: r[0].(string), column: r[1].(string)}
+		if len(tableName) > 0 && !strings.EqualFold(k.table, tableName) {
+			continue
+		}
+		centroid, err := ParseEmbedding(ctx, r[3])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := lists[k]; !ok {
+			lists[k] = make(map[int32][]float64)
+			keys = append(keys, k)
+		}
+		lists[k][r[2].(int32)] = centroid
+	}
+
+	indexes := make([]Index, len(keys))
+	for i, k := range keys {
+		ids := make([]int, 0, len(lists[k]))
+		for id := range lists[k] {
+			ids = append(ids, int(id))
+		}
+		sort.Ints(ids)
+		centroids := make([][]float64, len(ids))
+		for j, id := range ids {
+			if id != j {
+				return nil, fmt.Errorf("vector index on column '%s' of table '%s' is missing list %d", k.column, k.table, j)
+			}
+			centroids[j] = lists[k][int32(id)]
+		}
+		indexes[i] = Index{Table: k.table, Column: k.column, Centroids: centroids}
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		if indexes[i].Table != indexes[j].Table {
+			return indexes[i].Table < indexes[j].Table
+		}
+		return indexes[i].Column < indexes[j].Column
+	})
+	return indexes, nil
+}
+
+// TableRows returns all the rows of |tbl|.
+func TableRows(ctx *sql.Context, tbl sql.Table) ([]sql.Row, error) {
+	parts, err := tbl.Partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer parts.Close(ctx)
+
+	var rows []sql.Row
+	for {
+		part, err := parts.Next(ctx)
+		if err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		iter, err := tbl.PartitionRows(ctx, part)
+		if err != nil {
+			return nil, err
+		}
+		partRows, err := sql.RowIterToRows(ctx, nil, iter)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, partRows...)
+	}
+}
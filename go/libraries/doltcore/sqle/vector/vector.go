@@ -0,0 +1,193 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// ParseEmbedding parses the embedding |v|, the value of an embedding column. Embeddings are either JSON arrays of
+// numbers, stored in JSON or string columns, or packed little-endian float32s, stored in BLOB or binary columns.
+func ParseEmbedding(ctx *sql.Context, v interface{}) ([]float64, error) {
+	switch v := v.(type) {
+	case types.JSONValue:
+		doc, err := v.Unmarshall(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return embeddingFromJSON(doc.Val)
+	case string:
+		var val interface{}
+		if err := json.Unmarshal([]byte(v), &val); err != nil {
+			return nil, fmt.Errorf("invalid embedding: %s", err.Error())
+		}
+		return embeddingFromJSON(val)
+	case []byte:
+		if len(v)%4 != 0 {
+			return nil, fmt.Errorf("invalid embedding: binary embeddings must be packed 4 byte floats, but got %d bytes", len(v))
+		}
+		vec := make([]float64, len(v)/4)
+		for i := range vec {
+			vec[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(v[i*4:])))
+		}
+		return vec, nil
+	default:
+		return nil, fmt.Errorf("invalid embedding: unsupported type %T", v)
+	}
+}
+
+func embeddingFromJSON(val interface{}) ([]float64, error) {
+	arr, ok := val.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid embedding: expected a JSON array of numbers")
+	}
+	vec := make([]float64, len(arr))
+	for i, el := range arr {
+		switch el := el.(type) {
+		case float64:
+			vec[i] = el
+		case json.Number:
+			f, err := el.Float64()
+			if err != nil {
+				return nil, fmt.Errorf("invalid embedding: %s", err.Error())
+			}
+			vec[i] = f
+		default:
+			return nil, fmt.Errorf("invalid embedding: expected a JSON array of numbers")
+		}
+	}
+	return vec, nil
+}
+
+// Distance returns the Euclidean distance between the embeddings |a| and |b|, which must have the same number of
+// dimensions.
+func Distance(a, b []float64) (float64, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("cannot compare embeddings with %d and %d dimensions", len(a), len(b))
+	}
+	return math.Sqrt(squaredDistance(a, b)), nil
+}
+
+func squaredDistance(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+// NearestCentroids returns the indexes of the |n| centroids nearest to |v|, nearest first.
+func NearestCentroids(centroids [][]float64, v []float64, n int) ([]int, error) {
+	dists := make([]float64, len(centroids))
+	idxs := make([]int, len(centroids))
+	for i, c := range centroids {
+		if len(c) != len(v) {
+			return nil, fmt.Errorf("cannot compare embeddings with %d and %d dimensions", len(c), len(v))
+		}
+		dists[i] = squaredDistance(c, v)
+		idxs[i] = i
+	}
+	sort.SliceStable(idxs, func(i, j int) bool {
+		return dists[idxs[i]] < dists[idxs[j]]
+	})
+	if n < len(idxs) {
+		idxs = idxs[:n]
+	}
+	return idxs, nil
+}
+
+// NearestCentroid returns the index of the centroid nearest to |v|.
+func NearestCentroid(centroids [][]float64, v []float64) (int, error) {
+	idxs, err := NearestCentroids(centroids, v, 1)
+	if err != nil {
+		return 0, err
+	} else if len(idxs) == 0 {
+		return 0, fmt.Errorf("vector index has no lists")
+	}
+	return idxs[0], nil
+}
+
+// KMeans partitions |vectors|, which must all have the same number of dimensions, into |k| clusters, and returns the
+// centroid of each cluster. The initial centroids are evenly spaced among |vectors|, so the result only depends on
+// the vectors and their order.
+func KMeans(vectors [][]float64, k, iterations int) [][]float64 {
+	if k > len(vectors) {
+		k = len(vectors)
+	}
+	if k == 0 {
+		return nil
+	}
+
+	centroids := make([][]float64, k)
+	for i := range centroids {
+		centroids[i] = append([]float64(nil), vectors[i*len(vectors)/k]...)
+	}
+
+	assignments := make([]int, len(vectors))
+	for it := 0; it < iterations; it++ {
+		changed := false
+		for i, v := range vectors {
+			nearest, _ := NearestCentroid(centroids, v)
+			if nearest != assignments[i] {
+				assignments[i] = nearest
+				changed = true
+			}
+		}
+		if !changed && it > 0 {
+			break
+		}
+
+		sums := make([][]float64, k)
+		counts := make([]int, k)
+		for i, v := range vectors {
+			c := assignments[i]
+			if sums[c] == nil {
+				sums[c] = make([]float64, len(v))
+			}
+			for d := range v {
+				sums[c][d] += v[d]
+			}
+			counts[c]++
+		}
+		for c := range centroids {
+			// an empty cluster keeps its centroid
+			if counts[c] == 0 {
+				continue
+			}
+			for d := range sums[c] {
+				centroids[c][d] = sums[c][d] / float64(counts[c])
+			}
+		}
+	}
+
+	return centroids
+}
+
+// CentroidJSON returns the JSON array of a centroid, as stored in the dolt_vector_indexes table.
+func CentroidJSON(c []float64) types.JSONDocument {
+	arr := make([]interface{}, len(c))
+	for i, f := range c {
+		arr[i] = f
+	}
+	return types.JSONDocument{Val: arr}
+}
@@ -0,0 +1,199 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/vector"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+)
+
+// vectorIndexEditor is a secondary table editor that maintains the lists of the vector indexes of a table as rows of
+// the table are written.
+type vectorIndexEditor struct {
+	lists      writer.TableWriter
+	indexes    []vector.Index
+	columns    []int
+	pkOrdinals []int
+}
+
+var _ sql.TableEditor = vectorIndexEditor{}
+
+// getVectorIndexEditor returns an editor for the lists of the vector indexes of this table, or false if the table
+// has no vector indexes.
+func (t *WritableDoltTable) getVectorIndexEditor(ctx *sql.Context, writeSession writer.WriteSession, setter writer.SessionRootSetter) (vectorIndexEditor, bool, error) {
+	if doltdb.HasDoltPrefix(t.tableName) || len(t.sqlSch.PkOrdinals) == 0 {
+		return vectorIndexEditor{}, false, nil
+	}
+
+	workingRoot, err := t.workingRoot(ctx)
+	if err != nil {
+		return vectorIndexEditor{}, false, err
+	}
+	indexes, err := loadVectorIndexes(ctx, t.db, workingRoot, t.tableName)
+	if err != nil {
+		return vectorIndexEditor{}, false, err
+	}
+
+	editor := vectorIndexEditor{pkOrdinals: t.sqlSch.PkOrdinals}
+	for _, idx := range indexes {
+		// an index on a column that has since been dropped is ignored
+		col := t.sqlSch.Schema.IndexOfColName(idx.Column)
+		if col < 0 {
+			continue
+		}
+		editor.indexes = append(editor.indexes, idx)
+		editor.columns = append(editor.columns, col)
+	}
+	if len(editor.indexes) == 0 {
+		return vectorIndexEditor{}, false, nil
+	}
+
+	editor.lists, err = writeSession.GetTableWriter(ctx, doltdb.VectorIndexListsTableName(t.tableName), t.db.RevisionQualifiedName(), setter)
+	if err != nil {
+		return vectorIndexEditor{}, false, err
+	}
+	return editor, true, nil
+}
+
+// loadVectorIndexes returns the vector indexes of the table |tableName| in |root|. Indexes are cached in the session
+// by the hash of the root's dolt_vector_indexes table, so they're only loaded again once an index is created, dropped
+// or rebuilt.
+func loadVectorIndexes(ctx *sql.Context, db dsess.SqlDatabase, root *doltdb.RootValue, tableName string) ([]vector.Index, error) {
+	h, ok, err := root.GetTableHash(ctx, doltdb.VectorIndexesTableName)
+	if err != nil || !ok {
+		return nil, err
+	}
+	key := doltdb.DataCacheKey{Hash: h}
+
+	dbState, ok, err := dsess.DSessFromSess(ctx.Session).LookupDbState(ctx, db.RevisionQualifiedName())
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("no state for database %s", db.RevisionQualifiedName())
+	}
+	if indexes, ok := dbState.SessionCache().GetCachedVectorIndexes(key, tableName); ok {
+		return indexes, nil
+	}
+
+	tbl, ok, err := root.GetTable(ctx, doltdb.VectorIndexesTableName)
+	if err != nil || !ok {
+		return nil, err
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	indexesTable, err := NewDoltTable(doltdb.VectorIndexesTableName, sch, tbl, db, editor.Options{})
+	if err != nil {
+		return nil, err
+	}
+	indexesTable.lockedToRoot = root
+
+	indexes, err := vector.LoadIndexes(ctx, indexesTable, tableName)
+	if err != nil {
+		return nil, err
+	}
+	dbState.SessionCache().CacheVectorIndexes(key, tableName, indexes)
+	return indexes, nil
+}
+
+// listsRows returns the rows of the lists tables that |row| is assigned to.
+func (e vectorIndexEditor) listsRows(ctx *sql.Context, row sql.Row) ([]sql.Row, error) {
+	pk := make(sql.Row, len(e.pkOrdinals))
+	for i, ord := range e.pkOrdinals {
+		pk[i] = row[ord]
+	}
+
+	var rows []sql.Row
+	for i, idx := range e.indexes {
+		v := row[e.columns[i]]
+		if v == nil {
+			continue
+		}
+		embedding, err := vector.ParseEmbedding(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		list, err := vector.NearestCentroid(idx.Centroids, embedding)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, idx.ListsRow(list, pk))
+	}
+	return rows, nil
+}
+
+// StatementBegin implements the interface sql.TableEditor.
+func (e vectorIndexEditor) StatementBegin(ctx *sql.Context) {
+	e.lists.StatementBegin(ctx)
+}
+
+// DiscardChanges implements the interface sql.TableEditor.
+func (e vectorIndexEditor) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
+	return e.lists.DiscardChanges(ctx, errorEncountered)
+}
+
+// StatementComplete implements the interface sql.TableEditor.
+func (e vectorIndexEditor) StatementComplete(ctx *sql.Context) error {
+	return e.lists.StatementComplete(ctx)
+}
+
+// Insert implements the interface sql.TableEditor.
+func (e vectorIndexEditor) Insert(ctx *sql.Context, row sql.Row) error {
+	rows, err := e.listsRows(ctx, row)
+	if err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err = e.lists.Insert(ctx, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update implements the interface sql.TableEditor.
+func (e vectorIndexEditor) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	if err := e.Delete(ctx, old); err != nil {
+		return err
+	}
+	return e.Insert(ctx, new)
+}
+
+// Delete implements the interface sql.TableEditor.
+func (e vectorIndexEditor) Delete(ctx *sql.Context, row sql.Row) error {
+	rows, err := e.listsRows(ctx, row)
+	if err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err = e.lists.Delete(ctx, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements the interface sql.TableEditor.
+func (e vectorIndexEditor) Close(ctx *sql.Context) error {
+	return e.lists.Close(ctx)
+}
@@ -0,0 +1,317 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/vector"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// VectorSearchRuleId is the id of the analyzer rule that reads the rows of a query ordered by their distance to an
+// embedding from a vector index, added with analyzer.Builder.AddPostValidationRule.
+const VectorSearchRuleId analyzer.RuleId = 10001
+
+// VectorSearchRule is an analyzer rule that plans queries of the form
+//
+//	SELECT ... FROM t ORDER BY VEC_DISTANCE(t.col, <embedding>) LIMIT k
+//
+// to read only the rows in the lists of the vector index on t.col nearest to the embedding, as dolt_vector_search
+// does, rather than every row of the table. The rows read are still sorted by their exact distance, but like
+// dolt_vector_search the results are approximate: a nearer row in a list that isn't searched isn't returned, and
+// neither are rows whose embedding is NULL.
+func VectorSearchRule(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node, scope *plan.Scope, sel analyzer.RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+	return transform.Node(n, func(n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		limit, ok := n.(*plan.Limit)
+		if !ok {
+			return n, transform.SameTree, nil
+		}
+		child, ok, err := withVectorIndexScan(ctx, a, limit.Child)
+		if err != nil || !ok {
+			return n, transform.SameTree, err
+		}
+		n, err = limit.WithChildren(child)
+		if err != nil {
+			return nil, transform.SameTree, err
+		}
+		return n, transform.NewTree, nil
+	})
+}
+
+// withVectorIndexScan returns |n|, the child of a Limit, with the table below its sort replaced by a vectorIndexScan
+// of the table, if the sort can be served by a vector index of the table.
+func withVectorIndexScan(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, bool, error) {
+	var fields sql.SortFields
+	switch n := n.(type) {
+	case *plan.Offset, *plan.Project:
+		child, ok, err := withVectorIndexScan(ctx, a, n.Children()[0])
+		if err != nil || !ok {
+			return n, false, err
+		}
+		nn, err := n.WithChildren(child)
+		return nn, err == nil, err
+	case *plan.TopN:
+		fields = n.Fields
+	case *plan.Sort:
+		fields = n.SortFields
+	default:
+		return n, false, nil
+	}
+
+	if len(fields) != 1 || fields[0].Order != sql.Ascending {
+		return n, false, nil
+	}
+	dist, ok := fields[0].Column.(*dfunctions.VecDistance)
+	if !ok {
+		return n, false, nil
+	}
+	gf, ok := dist.Left.(*expression.GetField)
+	lit, litOk := dist.Right.(*expression.Literal)
+	if !ok || !litOk {
+		// distance is symmetric, so the embedding may be either argument
+		gf, ok = dist.Right.(*expression.GetField)
+		lit, litOk = dist.Left.(*expression.Literal)
+		if !ok || !litOk {
+			return n, false, nil
+		}
+	}
+
+	leaf, rt := vectorScanLeaf(n.Children()[0])
+	if rt == nil || !strings.EqualFold(leaf.(sql.Nameable).Name(), gf.Table()) {
+		return n, false, nil
+	}
+	vt, ok := rt.Table.(vectorSearchTable)
+	if !ok {
+		return n, false, nil
+	}
+	if ok, err := vt.canVectorSearch(ctx, gf.Name()); err != nil || !ok {
+		return n, false, err
+	}
+
+	v, err := lit.Eval(ctx, nil)
+	if err != nil || v == nil {
+		return n, false, err
+	}
+	embedding, err := vector.ParseEmbedding(ctx, v)
+	if err != nil {
+		// the sort fails with the error when it's evaluated, unless the table is empty
+		return n, false, nil
+	}
+
+	scan := &vectorIndexScan{
+		UnaryNode: plan.UnaryNode{Child: leaf},
+		table:     vt,
+		column:    gf.Name(),
+		embedding: embedding,
+		builder:   a.ExecBuilder,
+	}
+	child, _, err := transform.Node(n.Children()[0], func(c sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		if c == leaf {
+			return scan, transform.NewTree, nil
+		}
+		return c, transform.SameTree, nil
+	})
+	if err != nil {
+		return n, false, err
+	}
+	n, err = n.WithChildren(child)
+	return n, err == nil, err
+}
+
+// vectorScanLeaf returns the table or aliased table read by |n|, which is one or a projection of one, along with the
+// resolved table itself.
+func vectorScanLeaf(n sql.Node) (sql.Node, *plan.ResolvedTable) {
+	switch n := n.(type) {
+	case *plan.Project:
+		return vectorScanLeaf(n.Child)
+	case *plan.ResolvedTable:
+		return n, n
+	case *plan.TableAlias:
+		rt, _ := n.Child.(*plan.ResolvedTable)
+		return n, rt
+	}
+	return nil, nil
+}
+
+// vectorSearchTable is a table whose rows can be read from the lists of its vector indexes.
+type vectorSearchTable interface {
+	// canVectorSearch returns whether |column| has a vector index the current user can search.
+	canVectorSearch(ctx *sql.Context, column string) (bool, error)
+	// vectorSearchRows returns the rows of the table in the lists of the vector index on |column| nearest to
+	// |embedding|, or false if the column no longer has a vector index.
+	vectorSearchRows(ctx *sql.Context, column string, embedding []float64) (sql.RowIter, bool, error)
+}
+
+var _ vectorSearchTable = (*DoltTable)(nil)
+
+func (t *DoltTable) canVectorSearch(ctx *sql.Context, column string) (bool, error) {
+	if !types.IsFormat_DOLT(t.nbf) || len(t.sqlSchema().PkOrdinals) == 0 || doltdb.HasDoltPrefix(t.tableName) {
+		return false, nil
+	}
+	// the lists a row is read from would reveal its masked embedding
+	masked, err := t.maskedColumns(ctx)
+	if err != nil {
+		return false, err
+	} else if _, ok := masked[strings.ToLower(column)]; ok {
+		return false, nil
+	}
+	_, ok, err := t.vectorIndex(ctx, column)
+	return ok, err
+}
+
+func (t *DoltTable) vectorSearchRows(ctx *sql.Context, column string, embedding []float64) (sql.RowIter, bool, error) {
+	idx, ok, err := t.vectorIndex(ctx, column)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	lists, err := vector.NearestCentroids(idx.Centroids, embedding, defaultVectorSearchProbes)
+	if err != nil {
+		return nil, false, err
+	}
+
+	root, err := t.workingRoot(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	tbl, err := t.DoltTable(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	listsTbl, ok, err := root.GetTable(ctx, doltdb.VectorIndexListsTableName(t.tableName))
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	vr, err := newVectorSearchReader(ctx, tbl, t.sch, t.sqlSchema().Schema, listsTbl)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the reader returns every column of the table, in schema order
+	var projection []int
+	if t.projectedCols != nil {
+		projection = make([]int, 0, len(t.projectedCols))
+		cols := t.sch.GetAllCols()
+		for _, tag := range t.projectedCols {
+			projection = append(projection, cols.TagToIdx[tag])
+		}
+	}
+
+	var rows []sql.Row
+	for _, list := range lists {
+		err = vr.scanList(ctx, idx.Column, list, func(row sql.Row) error {
+			if projection != nil {
+				projected := make(sql.Row, len(projection))
+				for i, j := range projection {
+					projected[i] = row[j]
+				}
+				row = projected
+			}
+			rows = append(rows, row)
+			return nil
+		})
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	iter, err := t.maskRows(ctx, sql.RowsToRowIter(rows...))
+	return iter, err == nil, err
+}
+
+// vectorIndex returns the vector index on |column| of the table, or false if it has none.
+func (t *DoltTable) vectorIndex(ctx *sql.Context, column string) (vector.Index, bool, error) {
+	root, err := t.workingRoot(ctx)
+	if err != nil {
+		return vector.Index{}, false, err
+	}
+	indexes, err := loadVectorIndexes(ctx, t.db, root, t.tableName)
+	if err != nil {
+		return vector.Index{}, false, err
+	}
+	for _, idx := range indexes {
+		if strings.EqualFold(idx.Column, column) {
+			return idx, true, nil
+		}
+	}
+	return vector.Index{}, false, nil
+}
+
+// vectorIndexScan reads the rows of its child, a table or an aliased table, from the lists of the vector index on
+// |column| nearest to |embedding|. If the index is dropped after the query is planned, every row of the child is read.
+type vectorIndexScan struct {
+	plan.UnaryNode
+	table     vectorSearchTable
+	column    string
+	embedding []float64
+	builder   sql.NodeExecBuilder
+}
+
+var _ sql.ExecSourceRel = (*vectorIndexScan)(nil)
+
+// RowIter implements the sql.ExecSourceRel interface
+func (s *vectorIndexScan) RowIter(ctx *sql.Context, r sql.Row) (sql.RowIter, error) {
+	iter, ok, err := s.table.vectorSearchRows(ctx, s.column, s.embedding)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return s.builder.Build(ctx, s.Child, r)
+	}
+	return iter, nil
+}
+
+// String implements the sql.Node interface
+func (s *vectorIndexScan) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("VectorIndexScan(%s, %d probes)", s.column, defaultVectorSearchProbes)
+	_ = pr.WriteChildren(s.Child.String())
+	return pr.String()
+}
+
+// DebugString implements the sql.DebugStringer interface
+func (s *vectorIndexScan) DebugString() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("VectorIndexScan(%s, %d probes)", s.column, defaultVectorSearchProbes)
+	_ = pr.WriteChildren(sql.DebugString(s.Child))
+	return pr.String()
+}
+
+// WithChildren implements the sql.Node interface
+func (s *vectorIndexScan) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(s, len(children), 1)
+	}
+	ns := *s
+	ns.Child = children[0]
+	return &ns, nil
+}
+
+// CheckPrivileges implements the sql.Node interface
+func (s *vectorIndexScan) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return s.Child.CheckPrivileges(ctx, opChecker)
+}
+
+// IsReadOnly implements the sql.Node interface
+func (s *vectorIndexScan) IsReadOnly() bool {
+	return true
+}
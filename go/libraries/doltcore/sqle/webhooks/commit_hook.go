@@ -0,0 +1,203 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// commitHook fires webhooks for the branches and tags of a single database as they are created, moved and deleted.
+// Commit hooks are only told the new head of a ref, so it remembers the last head it saw for each one.
+type commitHook struct {
+	d      *Dispatcher
+	dbName string
+	out    io.Writer
+
+	mu    *sync.Mutex
+	heads map[string]hash.Hash
+}
+
+var _ doltdb.CommitHook = (*commitHook)(nil)
+
+// InstallCommitHook adds a commit hook to |ddb| that fires the webhooks matching the database named.
+func (d *Dispatcher) InstallCommitHook(ctx context.Context, dbName string, ddb *doltdb.DoltDB) error {
+	if d == nil {
+		return nil
+	}
+
+	heads := make(map[string]hash.Hash)
+	branches, err := ddb.GetBranchesWithHashes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, b := range branches {
+		heads[b.Ref.String()] = b.Hash
+	}
+	tags, err := ddb.GetTagsWithHashes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, t := range tags {
+		heads[ref.NewTagRef(t.Tag.Name).String()] = t.Hash
+	}
+
+	hook := &commitHook{
+		d:      d,
+		dbName: dbName,
+		mu:     &sync.Mutex{},
+		heads:  heads,
+	}
+	ddb.PrependCommitHook(ctx, hook)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.hooks[dbName] = hook
+	return nil
+}
+
+// FirePushed fires the webhooks for the refs of the database named that were updated by a push. Pushes write directly
+// to the database's chunk store, bypassing its commit hooks, so the refs they update are passed here instead. Only the
+// webhooks are fired; none of the database's other commit hooks are run.
+func (d *Dispatcher) FirePushed(ctx context.Context, dbName string, ddb *doltdb.DoltDB, refs []ref.DoltRef) error {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	hook := d.hooks[dbName]
+	d.mu.Unlock()
+	if hook == nil {
+		return nil
+	}
+
+	db := doltdb.HackDatasDatabaseFromDoltDB(ddb)
+	for _, r := range refs {
+		ds, err := db.GetDataset(ctx, r.String())
+		if err != nil {
+			return err
+		}
+		_, err = hook.Execute(ctx, ds, db)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute implements doltdb.CommitHook
+func (h *commitHook) Execute(ctx context.Context, ds datas.Dataset, db datas.Database) (func(context.Context) error, error) {
+	if !ref.IsRef(ds.ID()) {
+		return nil, nil
+	}
+	r, err := ref.Parse(ds.ID())
+	if err != nil {
+		return nil, err
+	}
+	refType := r.GetType()
+	if refType != ref.BranchRefType && refType != ref.TagRefType {
+		return nil, nil
+	}
+
+	p := Payload{Database: h.dbName, Ref: r.String(), Timestamp: time.Now()}
+	var newHash hash.Hash
+	if ds.HasHead() {
+		if ds.IsTag() {
+			meta, commitAddr, err := ds.HeadTag()
+			if err != nil {
+				return nil, err
+			}
+			newHash = commitAddr
+			p.Author = fmt.Sprintf("%s <%s>", meta.Name, meta.Email)
+			p.Message = meta.Description
+		} else {
+			newHash, _ = ds.MaybeHeadAddr()
+			head, _ := ds.MaybeHead()
+			meta, err := datas.GetCommitMeta(ctx, head)
+			if err != nil {
+				return nil, err
+			}
+			if meta != nil {
+				p.Author = fmt.Sprintf("%s <%s>", meta.Name, meta.Email)
+				p.Message = meta.Description
+			}
+		}
+	}
+
+	h.mu.Lock()
+	oldHash, existed := h.heads[r.String()]
+	if newHash.IsEmpty() {
+		delete(h.heads, r.String())
+	} else {
+		h.heads[r.String()] = newHash
+	}
+	h.mu.Unlock()
+
+	switch {
+	case newHash.IsEmpty() && !existed:
+		return nil, nil
+	case newHash.IsEmpty():
+		p.Event = eventFor(refType, BranchDeleted, TagDeleted)
+	case !existed:
+		p.Event = eventFor(refType, BranchCreated, TagCreated)
+	case oldHash == newHash || refType == ref.TagRefType:
+		return nil, nil
+	default:
+		p.Event = BranchUpdated
+	}
+
+	if existed {
+		p.OldHash = oldHash.String()
+	}
+	if !newHash.IsEmpty() {
+		p.NewHash = newHash.String()
+	}
+	h.d.Fire(p)
+	return nil, nil
+}
+
+func eventFor(refType ref.RefType, branchEvent, tagEvent Event) Event {
+	if refType == ref.TagRefType {
+		return tagEvent
+	}
+	return branchEvent
+}
+
+// HandleError implements doltdb.CommitHook
+func (h *commitHook) HandleError(ctx context.Context, err error) error {
+	if h.out != nil {
+		h.out.Write([]byte(fmt.Sprintf("error firing webhooks for database '%s': %s\n", h.dbName, err.Error())))
+	}
+	return nil
+}
+
+// SetLogger implements doltdb.CommitHook
+func (h *commitHook) SetLogger(ctx context.Context, wr io.Writer) error {
+	h.out = wr
+	return nil
+}
+
+// ExecuteForWorkingSets implements doltdb.CommitHook
+func (h *commitHook) ExecuteForWorkingSets() bool {
+	return false
+}
@@ -0,0 +1,361 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// Event is the kind of ref update a webhook is fired for.
+type Event string
+
+const (
+	BranchCreated Event = "branch_created"
+	BranchUpdated Event = "branch_updated"
+	BranchDeleted Event = "branch_deleted"
+	TagCreated    Event = "tag_created"
+	TagDeleted    Event = "tag_deleted"
+)
+
+var allEvents = []Event{BranchCreated, BranchUpdated, BranchDeleted, TagCreated, TagDeleted}
+
+const (
+	defaultMaxAttempts = 5
+	maxBackoff         = time.Minute
+	queueSize          = 1024
+	// maxLoggedDeliveries bounds the number of deliveries kept for the dolt_webhook_deliveries table
+	maxLoggedDeliveries = 1000
+	deliveryThreadName  = "webhook_delivery"
+)
+
+// Config is a single webhook. Payloads for the events listed are POSTed to URL as JSON. If Events is empty, the
+// webhook is fired for every event, and if Databases is empty, for every database. If Secret is set, each request
+// carries an X-Dolt-Signature-256 header with the hex encoded HMAC-SHA256 of the body, keyed by the secret. Failed
+// deliveries are retried with exponential backoff, up to MaxAttempts attempts in total.
+type Config struct {
+	URL         string   `yaml:"url"`
+	Events      []Event  `yaml:"events,omitempty"`
+	Databases   []string `yaml:"databases,omitempty"`
+	Secret      string   `yaml:"secret,omitempty"`
+	MaxAttempts *int     `yaml:"max_attempts,omitempty"`
+}
+
+// Validate returns an error if any of the webhooks given is misconfigured.
+func Validate(configs []Config) error {
+	for i, c := range configs {
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhooks[%d]: url: '%s' is not a valid http or https URL", i, c.URL)
+		}
+		for _, e := range c.Events {
+			if !isEvent(e) {
+				return fmt.Errorf("webhooks[%d]: events: unknown event '%s'", i, e)
+			}
+		}
+		if c.MaxAttempts != nil && *c.MaxAttempts < 1 {
+			return fmt.Errorf("webhooks[%d]: max_attempts: must be at least 1", i)
+		}
+	}
+	return nil
+}
+
+func isEvent(e Event) bool {
+	for _, ev := range allEvents {
+		if e == ev {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Config) matches(p Payload) bool {
+	if len(c.Databases) > 0 && !contains(c.Databases, p.Database) {
+		return false
+	}
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, e := range c.Events {
+		if e == p.Event {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Config) maxAttempts() int {
+	if c.MaxAttempts == nil {
+		return defaultMaxAttempts
+	}
+	return *c.MaxAttempts
+}
+
+func contains(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload is the JSON body of a webhook request. OldHash is empty for a created ref and NewHash is empty for a
+// deleted one. Author and Message are those of the new commit or tag, and are empty for a deleted ref.
+type Payload struct {
+	Event     Event     `json:"event"`
+	Database  string    `json:"database"`
+	Ref       string    `json:"ref"`
+	OldHash   string    `json:"old_hash"`
+	NewHash   string    `json:"new_hash"`
+	Author    string    `json:"author"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// DeliveryStatus is the state of a single delivery.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is a payload sent, or being sent, to a single webhook.
+type Delivery struct {
+	ID       uint64
+	URL      string
+	Payload  Payload
+	Status   DeliveryStatus
+	Attempts int
+	// ResponseCode is the HTTP status of the last attempt, or 0 if it got no response
+	ResponseCode int
+	// Error describes why the last attempt failed
+	Error   string
+	Created time.Time
+	Updated time.Time
+}
+
+type delivery struct {
+	Delivery
+	config Config
+}
+
+// Dispatcher delivers webhook payloads in the background, and keeps a log of recent deliveries.
+type Dispatcher struct {
+	configs []Config
+	client  *http.Client
+	queue   chan *delivery
+	backoff func(attempt int) time.Duration
+
+	mu     *sync.Mutex
+	nextID uint64
+	log    []*delivery
+	// hooks are the commit hooks installed for each database, by name
+	hooks map[string]*commitHook
+}
+
+// NewDispatcher returns a Dispatcher for the webhooks given, or nil if there are none.
+func NewDispatcher(configs []Config) *Dispatcher {
+	if len(configs) == 0 {
+		return nil
+	}
+	return &Dispatcher{
+		configs: configs,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		queue:   make(chan *delivery, queueSize),
+		backoff: exponentialBackoff,
+		mu:      &sync.Mutex{},
+		hooks:   make(map[string]*commitHook),
+	}
+}
+
+func exponentialBackoff(attempt int) time.Duration {
+	d := time.Second << (attempt - 1)
+	if d <= 0 || d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
+
+// Start starts delivering payloads on a background thread.
+func (d *Dispatcher) Start(bThreads *sql.BackgroundThreads) error {
+	if d == nil {
+		return nil
+	}
+	return bThreads.Add(deliveryThreadName, d.run)
+}
+
+// Deliveries returns the logged deliveries for |dbName|, oldest first.
+func (d *Dispatcher) Deliveries(dbName string) []Delivery {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var res []Delivery
+	for _, dl := range d.log {
+		if dl.Payload.Database == dbName {
+			res = append(res, dl.Delivery)
+		}
+	}
+	return res
+}
+
+// Fire queues |p| for delivery to every webhook it matches. It never blocks; if the queue is full, the delivery is
+// logged as failed.
+func (d *Dispatcher) Fire(p Payload) {
+	if d == nil {
+		return
+	}
+	for _, c := range d.configs {
+		if !c.matches(p) {
+			continue
+		}
+		dl := d.newDelivery(c, p)
+		select {
+		case d.queue <- dl:
+		default:
+			d.update(dl, func(dl *delivery) {
+				dl.Status = DeliveryFailed
+				dl.Error = "delivery queue is full"
+			})
+		}
+	}
+}
+
+func (d *Dispatcher) newDelivery(c Config, p Payload) *delivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nextID++
+	now := time.Now()
+	dl := &delivery{
+		Delivery: Delivery{
+			ID:      d.nextID,
+			URL:     c.URL,
+			Payload: p,
+			Status:  DeliveryPending,
+			Created: now,
+			Updated: now,
+		},
+		config: c,
+	}
+	d.log = append(d.log, dl)
+	if len(d.log) > maxLoggedDeliveries {
+		d.log = d.log[len(d.log)-maxLoggedDeliveries:]
+	}
+	return dl
+}
+
+func (d *Dispatcher) update(dl *delivery, f func(dl *delivery)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f(dl)
+	dl.Updated = time.Now()
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case dl := <-d.queue:
+			d.attempt(ctx, dl)
+		}
+	}
+}
+
+// attempt makes a single delivery attempt, and schedules a retry if it fails and attempts remain.
+func (d *Dispatcher) attempt(ctx context.Context, dl *delivery) {
+	code, err := d.post(ctx, dl)
+
+	var retry bool
+	d.update(dl, func(dl *delivery) {
+		dl.Attempts++
+		dl.ResponseCode = code
+		if err == nil {
+			dl.Status = DeliveryDelivered
+			dl.Error = ""
+			return
+		}
+		dl.Error = err.Error()
+		if dl.Attempts >= dl.config.maxAttempts() {
+			dl.Status = DeliveryFailed
+		} else {
+			retry = true
+		}
+	})
+
+	if retry {
+		wait := d.backoff(dl.Attempts)
+		go func() {
+			select {
+			case <-ctx.Done():
+			case <-time.After(wait):
+				select {
+				case d.queue <- dl:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+}
+
+func (d *Dispatcher) post(ctx context.Context, dl *delivery) (int, error) {
+	body, err := json.Marshal(dl.Payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dl.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Dolt-Event", string(dl.Payload.Event))
+	req.Header.Set("X-Dolt-Delivery", strconv.FormatUint(dl.ID, 10))
+	if dl.config.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(dl.config.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Dolt-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, errors.New("unexpected response status: " + resp.Status)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	zero := 0
+	assert.NoError(t, Validate(nil))
+	assert.NoError(t, Validate([]Config{{URL: "https://example.com/hook", Events: []Event{BranchCreated, TagDeleted}}}))
+	assert.Error(t, Validate([]Config{{URL: "example.com/hook"}}))
+	assert.Error(t, Validate([]Config{{URL: "ftp://example.com/hook"}}))
+	assert.Error(t, Validate([]Config{{URL: "http://example.com/hook", Events: []Event{"branch_renamed"}}}))
+	assert.Error(t, Validate([]Config{{URL: "http://example.com/hook", MaxAttempts: &zero}}))
+}
+
+func TestNilDispatcher(t *testing.T) {
+	d := NewDispatcher(nil)
+	assert.Nil(t, d)
+	d.Fire(Payload{Event: BranchCreated, Database: "mydb"})
+	assert.Empty(t, d.Deliveries("mydb"))
+}
+
+func TestDelivery(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	var received Payload
+	var signature string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("s3cr3t"))
+		mac.Write(body)
+		signature = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		assert.Equal(t, signature, r.Header.Get("X-Dolt-Signature-256"))
+		assert.Equal(t, string(BranchCreated), r.Header.Get("X-Dolt-Event"))
+		assert.NoError(t, json.Unmarshal(body, &received))
+	}))
+	defer srv.Close()
+
+	d := NewDispatcher([]Config{
+		{URL: srv.URL, Events: []Event{BranchCreated}, Secret: "s3cr3t"},
+		{URL: srv.URL, Databases: []string{"otherdb"}},
+	})
+	d.backoff = func(int) time.Duration { return 0 }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.run(ctx)
+
+	d.Fire(Payload{Event: BranchCreated, Database: "mydb", Ref: "refs/heads/feature", NewHash: "abc"})
+	d.Fire(Payload{Event: BranchDeleted, Database: "mydb", Ref: "refs/heads/feature", OldHash: "abc"})
+
+	require.Eventually(t, func() bool {
+		deliveries := d.Deliveries("mydb")
+		return len(deliveries) == 1 && deliveries[0].Status == DeliveryDelivered
+	}, 5*time.Second, 10*time.Millisecond)
+
+	delivery := d.Deliveries("mydb")[0]
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, http.StatusOK, delivery.ResponseCode)
+	assert.Empty(t, d.Deliveries("otherdb"))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "refs/heads/feature", received.Ref)
+	assert.Equal(t, "abc", received.NewHash)
+}
+
+func TestDeliveryFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	attempts := 3
+	d := NewDispatcher([]Config{{URL: srv.URL, MaxAttempts: &attempts}})
+	d.backoff = func(int) time.Duration { return 0 }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.run(ctx)
+
+	d.Fire(Payload{Event: TagCreated, Database: "mydb", Ref: "refs/tags/v1", NewHash: "abc"})
+
+	require.Eventually(t, func() bool {
+		deliveries := d.Deliveries("mydb")
+		return len(deliveries) == 1 && deliveries[0].Status == DeliveryFailed
+	}, 5*time.Second, 10*time.Millisecond)
+
+	delivery := d.Deliveries("mydb")[0]
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, delivery.ResponseCode)
+	assert.Contains(t, delivery.Error, "500")
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// EmitterTypeFile writes events to the events directory, from where they're sent to DoltHub
+	EmitterTypeFile = "file"
+	// EmitterTypeStatsd sends events to a StatsD server
+	EmitterTypeStatsd = "statsd"
+	// EmitterTypeOtlp sends events to an OpenTelemetry collector
+	EmitterTypeOtlp = "otlp"
+	// EmitterTypeNone drops events
+	EmitterTypeNone = "none"
+
+	DefaultStatsdAddress = "localhost:8125"
+	DefaultOtlpEndpoint  = "http://localhost:4318/v1/metrics"
+)
+
+// EmitterConfig configures where events are sent.
+type EmitterConfig struct {
+	// Types are the types of emitters events are sent to, e.g. file and otlp
+	Types []string
+	// StatsdAddress is the host:port of the StatsD server used by the statsd emitter
+	StatsdAddress string
+	// OtlpEndpoint is the URL of the metrics endpoint of the collector used by the otlp emitter
+	OtlpEndpoint string
+	// Labels are added to the resource metrics are reported for by the otlp emitter
+	Labels map[string]string
+}
+
+// ParseEmitterTypes parses a comma separated list of emitter types.
+func ParseEmitterTypes(s string) []string {
+	var types []string
+	for _, t := range strings.Split(s, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// HasType returns whether events are sent to an emitter of type |t|.
+func (cfg EmitterConfig) HasType(t string) bool {
+	for _, typ := range cfg.Types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+// NewEmitter returns an Emitter that sends events to each of the emitters in |cfg|. The file emitter writes to the
+// events directory in |doltDir| under |userHomeDir|.
+func NewEmitter(cfg EmitterConfig, userHomeDir string, doltDir string) (Emitter, error) {
+	var emitters MultiEmitter
+	for _, t := range cfg.Types {
+		switch t {
+		case EmitterTypeFile:
+			emitters = append(emitters, NewFileEmitter(userHomeDir, doltDir))
+		case EmitterTypeStatsd:
+			addr := cfg.StatsdAddress
+			if addr == "" {
+				addr = DefaultStatsdAddress
+			}
+			emitters = append(emitters, StatsdEmitter{Addr: addr})
+		case EmitterTypeOtlp:
+			endpoint := cfg.OtlpEndpoint
+			if endpoint == "" {
+				endpoint = DefaultOtlpEndpoint
+			}
+			emitters = append(emitters, OtlpEmitter{Endpoint: endpoint, Labels: cfg.Labels})
+		case EmitterTypeNone:
+		default:
+			return nil, fmt.Errorf("unknown metrics emitter '%s', expected one of %s, %s, %s or %s", t,
+				EmitterTypeFile, EmitterTypeStatsd, EmitterTypeOtlp, EmitterTypeNone)
+		}
+	}
+
+	switch len(emitters) {
+	case 0:
+		return NullEmitter{}, nil
+	case 1:
+		return emitters[0], nil
+	default:
+		return emitters, nil
+	}
+}
+
+var emitterConfigOverride atomic.Value
+
+// SetEmitterConfig overrides the emitter configuration for the process. sql-server uses this to apply the metrics
+// configuration in its config file.
+func SetEmitterConfig(cfg EmitterConfig) {
+	emitterConfigOverride.Store(cfg)
+}
+
+// EmitterConfigOverride returns the configuration set with SetEmitterConfig, if any.
+func EmitterConfigOverride() (EmitterConfig, bool) {
+	cfg, ok := emitterConfigOverride.Load().(EmitterConfig)
+	return cfg, ok
+}
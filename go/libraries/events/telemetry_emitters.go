@@ -0,0 +1,327 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+)
+
+const (
+	// metricPrefix is the prefix of the names of all metrics sent by the StatsD and OTLP emitters
+	metricPrefix = "dolt"
+
+	// statsdMaxPacketSize keeps StatsD packets under the MTU of most networks
+	statsdMaxPacketSize = 1432
+
+	emitterTimeout = time.Second + 500*time.Millisecond
+)
+
+// eventMetric is a value derived from a ClientEvent that's sent to a telemetry system.
+type eventMetric struct {
+	// name is the name of the metric, without the prefix or event type
+	name string
+	// value is a count, or a duration in milliseconds for timers
+	value int64
+	timer bool
+}
+
+// eventMetrics returns the metrics for |evt|: a count of events of its type, the duration of the event, and the
+// metrics recorded by the event.
+func eventMetrics(evt *eventsapi.ClientEvent) []eventMetric {
+	metrics := []eventMetric{{name: "count", value: 1}}
+	if evt.StartTime != nil && evt.EndTime != nil {
+		d := evt.EndTime.AsTime().Sub(evt.StartTime.AsTime())
+		metrics = append(metrics, eventMetric{name: "duration_ms", value: d.Milliseconds(), timer: true})
+	}
+
+	for _, m := range evt.Metrics {
+		name := strings.ToLower(m.MetricId.String())
+		switch v := m.MetricOneof.(type) {
+		case *eventsapi.ClientEventMetric_Count:
+			metrics = append(metrics, eventMetric{name: name, value: int64(v.Count)})
+		case *eventsapi.ClientEventMetric_Duration:
+			metrics = append(metrics, eventMetric{name: name, value: v.Duration.AsDuration().Milliseconds(), timer: true})
+		}
+	}
+	return metrics
+}
+
+func eventTypeName(evt *eventsapi.ClientEvent) string {
+	return strings.ToLower(evt.Type.String())
+}
+
+// StatsdEmitter sends events to a StatsD server as counters and timers named dolt.<event type>.<metric>, e.g.
+// dolt.sql_server.count and dolt.clone.duration_ms.
+type StatsdEmitter struct {
+	// Addr is the host:port of the StatsD server
+	Addr string
+}
+
+// LogEvents implements the Emitter interface and sends the events to the StatsD server over UDP.
+func (se StatsdEmitter) LogEvents(version string, evts []*eventsapi.ClientEvent) error {
+	if len(evts) == 0 {
+		return nil
+	}
+
+	conn, err := net.DialTimeout("udp", se.Addr, emitterTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var packet bytes.Buffer
+	flush := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+		_, err := conn.Write(packet.Bytes())
+		packet.Reset()
+		return err
+	}
+
+	for _, evt := range evts {
+		for _, m := range eventMetrics(evt) {
+			kind := "c"
+			if m.timer {
+				kind = "ms"
+			}
+			line := fmt.Sprintf("%s.%s.%s:%d|%s", metricPrefix, eventTypeName(evt), m.name, m.value, kind)
+
+			if packet.Len() > 0 && packet.Len()+1+len(line) > statsdMaxPacketSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			if packet.Len() > 0 {
+				packet.WriteByte('\n')
+			}
+			packet.WriteString(line)
+		}
+	}
+
+	return flush()
+}
+
+// OtlpEmitter sends events to an OpenTelemetry collector as OTLP metrics over HTTP, using the JSON encoding. Each
+// event type and metric is sent as a delta sum named dolt.<metric>, with the event type as the event_type attribute.
+type OtlpEmitter struct {
+	// Endpoint is the URL of the collector's metrics endpoint, usually ending in /v1/metrics
+	Endpoint string
+	// Labels are added as attributes of the resource the metrics are reported for
+	Labels map[string]string
+	// Client is the http.Client used to send metrics, or nil to use http.DefaultClient
+	Client *http.Client
+}
+
+type otlpKeyValue struct {
+	Key   string            `json:"key"`
+	Value map[string]string `json:"value"`
+}
+
+type otlpDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	AsInt             string         `json:"asInt"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpDataPoint `json:"dataPoints"`
+	AggregationTemporality int             `json:"aggregationTemporality"`
+	IsMonotonic            bool            `json:"isMonotonic"`
+}
+
+type otlpMetric struct {
+	Name string  `json:"name"`
+	Unit string  `json:"unit"`
+	Sum  otlpSum `json:"sum"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+// otlpRequest is the JSON encoding of an OTLP ExportMetricsServiceRequest
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+// aggregationTemporalityDelta is AGGREGATION_TEMPORALITY_DELTA in the OTLP protocol
+const aggregationTemporalityDelta = 1
+
+func otlpString(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: map[string]string{"stringValue": value}}
+}
+
+// LogEvents implements the Emitter interface and posts the events to the collector.
+func (oe OtlpEmitter) LogEvents(version string, evts []*eventsapi.ClientEvent) error {
+	if len(evts) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(oe.request(version, evts))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), emitterTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, oe.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := oe.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("error sending metrics to %s: %s", oe.Endpoint, resp.Status)
+	}
+	return nil
+}
+
+// request returns the OTLP request for |evts|, summing the metrics of events of the same type.
+func (oe OtlpEmitter) request(version string, evts []*eventsapi.ClientEvent) *otlpRequest {
+	type sumKey struct {
+		metric    string
+		eventType string
+	}
+	sums := make(map[sumKey]int64)
+	units := make(map[string]string)
+	var start, end time.Time
+
+	for _, evt := range evts {
+		if evt.StartTime != nil && (start.IsZero() || evt.StartTime.AsTime().Before(start)) {
+			start = evt.StartTime.AsTime()
+		}
+		if evt.EndTime != nil && evt.EndTime.AsTime().After(end) {
+			end = evt.EndTime.AsTime()
+		}
+		for _, m := range eventMetrics(evt) {
+			sums[sumKey{metric: m.name, eventType: eventTypeName(evt)}] += m.value
+			units[m.name] = "1"
+			if m.timer {
+				units[m.name] = "ms"
+			}
+		}
+	}
+	if end.IsZero() {
+		end = EventNowFunc()
+	}
+	if start.IsZero() {
+		start = end
+	}
+	startNanos := strconv.FormatInt(start.UnixNano(), 10)
+	endNanos := strconv.FormatInt(end.UnixNano(), 10)
+
+	metrics := make(map[string]*otlpMetric)
+	for k, v := range sums {
+		m, ok := metrics[k.metric]
+		if !ok {
+			m = &otlpMetric{
+				Name: metricPrefix + "." + k.metric,
+				Unit: units[k.metric],
+				Sum:  otlpSum{AggregationTemporality: aggregationTemporalityDelta, IsMonotonic: true},
+			}
+			metrics[k.metric] = m
+		}
+		m.Sum.DataPoints = append(m.Sum.DataPoints, otlpDataPoint{
+			Attributes:        []otlpKeyValue{otlpString("event_type", k.eventType)},
+			StartTimeUnixNano: startNanos,
+			TimeUnixNano:      endNanos,
+			AsInt:             strconv.FormatInt(v, 10),
+		})
+	}
+
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := []otlpKeyValue{otlpString("service.name", "dolt"), otlpString("service.version", version)}
+	labels := make([]string, 0, len(oe.Labels))
+	for k := range oe.Labels {
+		labels = append(labels, k)
+	}
+	sort.Strings(labels)
+	for _, k := range labels {
+		attrs = append(attrs, otlpString(k, oe.Labels[k]))
+	}
+
+	sm := otlpScopeMetrics{Scope: otlpScope{Name: metricPrefix, Version: version}}
+	for _, name := range names {
+		m := metrics[name]
+		sort.Slice(m.Sum.DataPoints, func(i, j int) bool {
+			return m.Sum.DataPoints[i].Attributes[0].Value["stringValue"] < m.Sum.DataPoints[j].Attributes[0].Value["stringValue"]
+		})
+		sm.Metrics = append(sm.Metrics, *m)
+	}
+
+	req := &otlpRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource:     otlpResource{Attributes: attrs},
+		ScopeMetrics: []otlpScopeMetrics{sm},
+	}}}
+
+	return req
+}
+
+// MultiEmitter sends events to each of its emitters, returning the first error encountered after trying them all.
+type MultiEmitter []Emitter
+
+// LogEvents implements the Emitter interface.
+func (me MultiEmitter) LogEvents(version string, evts []*eventsapi.ClientEvent) error {
+	var firstErr error
+	for _, em := range me {
+		if err := em.LogEvents(version, evts); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
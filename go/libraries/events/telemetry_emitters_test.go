@@ -0,0 +1,157 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+)
+
+func testClientEvents() []*eventsapi.ClientEvent {
+	start := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	return []*eventsapi.ClientEvent{
+		{
+			Type:      eventsapi.ClientEventType_CLONE,
+			StartTime: timestamppb.New(start),
+			EndTime:   timestamppb.New(start.Add(250 * time.Millisecond)),
+			Metrics: []*eventsapi.ClientEventMetric{
+				{
+					MetricId:    eventsapi.MetricID_BYTES_DOWNLOADED,
+					MetricOneof: &eventsapi.ClientEventMetric_Count{Count: 1024},
+				},
+				{
+					MetricId:    eventsapi.MetricID_DOWNLOAD_MS_ELAPSED,
+					MetricOneof: &eventsapi.ClientEventMetric_Duration{Duration: durationpb.New(200 * time.Millisecond)},
+				},
+			},
+		},
+		{
+			Type:      eventsapi.ClientEventType_CLONE,
+			StartTime: timestamppb.New(start.Add(time.Second)),
+			EndTime:   timestamppb.New(start.Add(time.Second + 50*time.Millisecond)),
+		},
+		{
+			Type:      eventsapi.ClientEventType_SQL_SERVER,
+			StartTime: timestamppb.New(start),
+			EndTime:   timestamppb.New(start.Add(time.Minute)),
+		},
+	}
+}
+
+func TestStatsdEmitter(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	emitter := StatsdEmitter{Addr: conn.LocalAddr().String()}
+	require.NoError(t, emitter.LogEvents("1.0.0", testClientEvents()))
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, statsdMaxPacketSize)
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	expected := []string{
+		"dolt.clone.count:1|c",
+		"dolt.clone.duration_ms:250|ms",
+		"dolt.clone.bytes_downloaded:1024|c",
+		"dolt.clone.download_ms_elapsed:200|ms",
+		"dolt.clone.count:1|c",
+		"dolt.clone.duration_ms:50|ms",
+		"dolt.sql_server.count:1|c",
+		"dolt.sql_server.duration_ms:60000|ms",
+	}
+	assert.Equal(t, expected, strings.Split(string(buf[:n]), "\n"))
+}
+
+func TestOtlpEmitter(t *testing.T) {
+	var received otlpRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	emitter := OtlpEmitter{Endpoint: server.URL, Labels: map[string]string{"env": "test"}}
+	require.NoError(t, emitter.LogEvents("1.0.0", testClientEvents()))
+
+	require.Len(t, received.ResourceMetrics, 1)
+	rm := received.ResourceMetrics[0]
+	assert.Equal(t, []otlpKeyValue{
+		otlpString("service.name", "dolt"),
+		otlpString("service.version", "1.0.0"),
+		otlpString("env", "test"),
+	}, rm.Resource.Attributes)
+
+	require.Len(t, rm.ScopeMetrics, 1)
+	sums := make(map[string]string)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		assert.Equal(t, aggregationTemporalityDelta, m.Sum.AggregationTemporality)
+		assert.True(t, m.Sum.IsMonotonic)
+		for _, dp := range m.Sum.DataPoints {
+			sums[m.Name+"/"+dp.Attributes[0].Value["stringValue"]] = dp.AsInt
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"dolt.count/clone":               "2",
+		"dolt.count/sql_server":          "1",
+		"dolt.duration_ms/clone":         "300",
+		"dolt.duration_ms/sql_server":    "60000",
+		"dolt.bytes_downloaded/clone":    "1024",
+		"dolt.download_ms_elapsed/clone": "200",
+	}, sums)
+}
+
+func TestOtlpEmitterError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	emitter := OtlpEmitter{Endpoint: server.URL}
+	assert.Error(t, emitter.LogEvents("1.0.0", testClientEvents()))
+}
+
+func TestNewEmitter(t *testing.T) {
+	em, err := NewEmitter(EmitterConfig{Types: ParseEmitterTypes("none")}, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, NullEmitter{}, em)
+
+	em, err = NewEmitter(EmitterConfig{Types: ParseEmitterTypes("statsd")}, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, StatsdEmitter{Addr: DefaultStatsdAddress}, em)
+
+	em, err = NewEmitter(EmitterConfig{Types: ParseEmitterTypes(" StatsD, otlp "), OtlpEndpoint: "http://collector:4318/v1/metrics"}, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, MultiEmitter{
+		StatsdEmitter{Addr: DefaultStatsdAddress},
+		OtlpEmitter{Endpoint: "http://collector:4318/v1/metrics"},
+	}, em)
+
+	_, err = NewEmitter(EmitterConfig{Types: ParseEmitterTypes("kafka")}, "", "")
+	assert.Error(t, err)
+}
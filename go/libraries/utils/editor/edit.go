@@ -64,6 +64,12 @@ func OpenCommitEditor(ed string, initialContents string) (string, error) {
 	return string(data), nil
 }
 
+// SplitCommand splits the command line |cmd| into the name of the program to run and its arguments, the same way an
+// editor's command line is split.
+func SplitCommand(cmd string) (string, []string) {
+	return getCmdNameAndArgsForEditor(cmd)
+}
+
 func getCmdNameAndArgsForEditor(es string) (string, []string) {
 	type span struct {
 		start int
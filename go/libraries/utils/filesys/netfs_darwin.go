@@ -0,0 +1,39 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin
+// +build darwin
+
+package filesys
+
+import (
+	"bytes"
+
+	"golang.org/x/sys/unix"
+)
+
+// NetworkFilesystemType returns the type of the network filesystem that |path| is on, or "" if it's on a local one.
+func NetworkFilesystemType(path string) (string, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return "", err
+	}
+	switch string(bytes.TrimRight(st.Fstypename[:], "\x00")) {
+	case "nfs":
+		return NetworkFilesystemNFS, nil
+	case "smbfs":
+		return NetworkFilesystemSMB, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keychain
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+const (
+	helperGet   = "get"
+	helperStore = "store"
+	helperErase = "erase"
+
+	helperPasswordKey = "password"
+)
+
+// Helper is the command line of an external credential helper, a program which stores and retrieves secrets using
+// the protocol of git's credential helpers. The helper is run with the action, one of get, store or erase, appended to
+// its command line, and is passed the attributes of the secret on stdin as key=value lines. Given get, it prints the
+// secret on stdout as a password=<secret> line.
+type Helper string
+
+// Get asks the helper for the secret with the attributes |attrs|. It returns false if the helper didn't return one.
+func (h Helper) Get(attrs map[string]string) (string, bool, error) {
+	out, err := h.run(helperGet, attrs)
+	if err != nil {
+		return "", false, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if ok && key == helperPasswordKey {
+			return value, true, nil
+		}
+	}
+	return "", false, scanner.Err()
+}
+
+// Store asks the helper to store |secret| with the attributes |attrs|.
+func (h Helper) Store(attrs map[string]string, secret string) error {
+	withSecret := make(map[string]string, len(attrs)+1)
+	for k, v := range attrs {
+		withSecret[k] = v
+	}
+	withSecret[helperPasswordKey] = secret
+	_, err := h.run(helperStore, withSecret)
+	return err
+}
+
+// Erase asks the helper to remove the secret with the attributes |attrs|.
+func (h Helper) Erase(attrs map[string]string) error {
+	_, err := h.run(helperErase, attrs)
+	return err
+}
+
+func (h Helper) run(action string, attrs map[string]string) ([]byte, error) {
+	fields := strings.Fields(string(h))
+	if len(fields) == 0 {
+		return nil, errors.New("no credential helper specified")
+	}
+
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var stdin bytes.Buffer
+	for _, k := range keys {
+		if strings.ContainsAny(attrs[k], "\n\x00") {
+			return nil, fmt.Errorf("credential helper attribute %s contains a newline or NUL", k)
+		}
+		fmt.Fprintf(&stdin, "%s=%s\n", k, attrs[k])
+	}
+	stdin.WriteString("\n")
+
+	cmd := exec.Command(fields[0], append(fields[1:], action)...)
+	cmd.Stdin = &stdin
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credential helper '%s %s' failed: %w", string(h), action, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keychain
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testHelperScript stores a single secret in the file named by its first argument, ignoring the attributes
+const testHelperScript = `#!/bin/sh
+store="$1"
+input=$(cat)
+case "$2" in
+get) if [ -f "$store" ]; then printf 'user=ignored\npassword=%s\n' "$(cat "$store")"; fi ;;
+store) printf '%s' "$input" | sed -n 's/^password=//p' > "$store" ;;
+erase) rm -f "$store" ;;
+esac
+`
+
+func TestHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test helper is a shell script")
+	}
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "helper.sh")
+	require.NoError(t, os.WriteFile(script, []byte(testHelperScript), 0700))
+	h := Helper(script + " " + filepath.Join(dir, "secret"))
+	attrs := map[string]string{"profile": "test", "user": "steph"}
+
+	_, ok, err := h.Get(attrs)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, h.Store(attrs, "pass=word"))
+	secret, ok, err := h.Get(attrs)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "pass=word", secret)
+
+	require.NoError(t, h.Erase(attrs))
+	_, ok, err = h.Get(attrs)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	err = h.Store(map[string]string{"user": "bad\nuser"}, "pass")
+	assert.Error(t, err)
+
+	_, _, err = Helper(filepath.Join(dir, "missing")).Get(attrs)
+	assert.Error(t, err)
+}
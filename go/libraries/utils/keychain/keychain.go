@@ -0,0 +1,41 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package keychain stores secrets, such as the passwords of dolt profiles, outside of dolt's config files. Secrets
+// can be kept in the OS keychain, or stored and retrieved by an external credential helper.
+package keychain
+
+import "errors"
+
+// ErrNotFound is returned when there is no secret stored for an account.
+var ErrNotFound = errors.New("no secret found in the keychain")
+
+// ErrUnsupported is returned when the OS keychain can't be used on this system.
+var ErrUnsupported = errors.New("the OS keychain is not supported on this system")
+
+// Get returns the secret stored in the OS keychain for |account| of |service|, or ErrNotFound if there isn't one.
+func Get(service, account string) (string, error) {
+	return get(service, account)
+}
+
+// Set stores |secret| in the OS keychain for |account| of |service|, replacing any secret already stored for it.
+func Set(service, account, secret string) error {
+	return set(service, account, secret)
+}
+
+// Delete removes the secret stored in the OS keychain for |account| of |service|. It returns ErrNotFound if there
+// isn't one and the keychain reports that.
+func Delete(service, account string) error {
+	return del(service, account)
+}
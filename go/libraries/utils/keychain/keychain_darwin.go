@@ -0,0 +1,77 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build darwin
+// +build darwin
+
+package keychain
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	securityCmd = "/usr/bin/security"
+	// errSecItemNotFound is the exit code of |securityCmd| when the item doesn't exist
+	errSecItemNotFound = 44
+	// maxInteractiveCommand is the longest command |securityCmd| accepts in interactive mode
+	maxInteractiveCommand = 4096
+)
+
+func get(service, account string) (string, error) {
+	out, err := exec.Command(securityCmd, "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	if err != nil {
+		return "", securityError(err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// set passes |secret| to |securityCmd| on stdin in interactive mode, so that it doesn't appear in the process list.
+func set(service, account, secret string) error {
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", quote(service), quote(account), quote(secret))
+	if len(command) > maxInteractiveCommand {
+		return errors.New("secret is too long to store in the keychain")
+	}
+
+	cmd := exec.Command(securityCmd, "-i")
+	cmd.Stdin = strings.NewReader(command)
+	if err := cmd.Run(); err != nil {
+		return securityError(err)
+	}
+	return nil
+}
+
+func del(service, account string) error {
+	err := exec.Command(securityCmd, "delete-generic-password", "-s", service, "-a", account).Run()
+	if err != nil {
+		return securityError(err)
+	}
+	return nil
+}
+
+func securityError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == errSecItemNotFound {
+		return ErrNotFound
+	}
+	return fmt.Errorf("keychain: %w", err)
+}
+
+// quote single quotes |s| for the interactive mode of |securityCmd|
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
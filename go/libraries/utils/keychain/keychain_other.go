@@ -0,0 +1,73 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package keychain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// secretToolCmd is the command line client of libsecret, which stores secrets with the Secret Service API, e.g. in
+// GNOME Keyring or KWallet
+const secretToolCmd = "secret-tool"
+
+func get(service, account string) (string, error) {
+	out, err := secretTool(nil, "lookup", "service", service, "account", account)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// set passes |secret| to |secretToolCmd| on stdin, so that it doesn't appear in the process list.
+func set(service, account, secret string) error {
+	_, err := secretTool(strings.NewReader(secret), "store", "--label", service+" "+account, "service", service, "account", account)
+	return err
+}
+
+func del(service, account string) error {
+	_, err := secretTool(nil, "clear", "service", service, "account", account)
+	return err
+}
+
+func secretTool(stdin *strings.Reader, args ...string) ([]byte, error) {
+	path, err := exec.LookPath(secretToolCmd)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s was not found", ErrUnsupported, secretToolCmd)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(path, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		// lookup exits with an error and says nothing when there is no matching secret
+		if errors.As(err, &exitErr) && args[0] == "lookup" && stderr.Len() == 0 {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("keychain: %s: %w", strings.TrimSpace(stderr.String()), err)
+	}
+	return out, nil
+}
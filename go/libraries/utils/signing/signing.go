@@ -0,0 +1,282 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signing signs and verifies payloads with the external programs that manage signing keys: gpg for OpenPGP
+// signatures, and ssh-keygen for SSH signatures.
+package signing
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// FormatOpenPGP signs with gpg. It's the default format.
+	FormatOpenPGP = "openpgp"
+	// FormatSSH signs with ssh-keygen.
+	FormatSSH = "ssh"
+)
+
+// sshNamespace is the namespace of the SSH signatures made, so that they can't be mistaken for signatures of anything
+// else made with the same key.
+const sshNamespace = "dolt"
+
+var ErrNoSigningKey = errors.New("no signing key configured, set user.signingkey to the key to sign with")
+var ErrNoAllowedSigners = errors.New("gpg.ssh.allowedsignersfile needs to be configured to verify ssh signatures")
+
+// Config describes how to sign and verify signatures.
+type Config struct {
+	// Format is the format of the signatures made, FormatOpenPGP or FormatSSH. Empty means FormatOpenPGP.
+	Format string
+	// Program is the program run to sign and verify, or empty for the default of the format: gpg or ssh-keygen.
+	Program string
+	// Key is the key signed with. For FormatOpenPGP it's a key id, and the default key is used if it's empty. For
+	// FormatSSH it's the path to a private key, or to a public key whose private key is held by ssh-agent.
+	Key string
+	// AllowedSignersFile is the ssh-keygen allowed signers file that SSH signatures are verified against.
+	AllowedSignersFile string
+}
+
+// Verification is the result of verifying a signature.
+type Verification struct {
+	// Good is whether the signature is a good signature of the payload by a trusted key.
+	Good bool
+	// Signer identifies who made the signature, if it's known: the user id of an OpenPGP key, or the principal of an
+	// SSH key.
+	Signer string
+	// Output is the human-readable output of the program that verified the signature.
+	Output string
+}
+
+func (cfg Config) format() (string, error) {
+	switch strings.ToLower(cfg.Format) {
+	case "", FormatOpenPGP:
+		return FormatOpenPGP, nil
+	case FormatSSH:
+		return FormatSSH, nil
+	default:
+		return "", fmt.Errorf("unsupported signature format '%s', expected %s or %s", cfg.Format, FormatOpenPGP, FormatSSH)
+	}
+}
+
+func (cfg Config) program(format string) string {
+	if cfg.Program != "" {
+		return cfg.Program
+	} else if format == FormatSSH {
+		return "ssh-keygen"
+	}
+	return "gpg"
+}
+
+// Sign signs |payload| with the key configured, and returns the armored signature.
+func Sign(ctx context.Context, cfg Config, payload []byte) (string, error) {
+	format, err := cfg.format()
+	if err != nil {
+		return "", err
+	}
+	if format == FormatSSH {
+		return sshSign(ctx, cfg, payload)
+	}
+	return gpgSign(ctx, cfg, payload)
+}
+
+// Verify verifies that |signature| is a good signature of |payload|. A signature that's bad, or that's made by a key
+// that isn't trusted, isn't an error, but a Verification that isn't Good.
+func Verify(ctx context.Context, cfg Config, payload []byte, signature string) (*Verification, error) {
+	if strings.HasPrefix(signature, "-----BEGIN SSH SIGNATURE-----") {
+		return sshVerify(ctx, cfg, payload, signature)
+	}
+	return gpgVerify(ctx, cfg, payload, signature)
+}
+
+func gpgSign(ctx context.Context, cfg Config, payload []byte) (string, error) {
+	args := []string{"--status-fd=2", "-bsa"}
+	if cfg.Key != "" {
+		args = append(args, "-u", cfg.Key)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, cfg.program(FormatOpenPGP), args...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil || !hasStatus(stderr.String(), "SIG_CREATED") {
+		return "", signFailed(cfg, err, stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+func gpgVerify(ctx context.Context, cfg Config, payload []byte, signature string) (*Verification, error) {
+	sigFile, err := writeTempFile(signature)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(sigFile)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, cfg.program(FormatOpenPGP), "--keyid-format=long", "--status-fd=1", "--verify", sigFile, "-")
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, err
+	}
+
+	ver := &Verification{Output: stderr.String()}
+	if err == nil {
+		for _, line := range statusLines(stdout.String()) {
+			if strings.HasPrefix(line, "GOODSIG ") {
+				ver.Good = true
+				if _, uid, ok := strings.Cut(strings.TrimPrefix(line, "GOODSIG "), " "); ok {
+					ver.Signer = uid
+				}
+			}
+		}
+	}
+	return ver, nil
+}
+
+func sshSign(ctx context.Context, cfg Config, payload []byte) (string, error) {
+	if cfg.Key == "" {
+		return "", ErrNoSigningKey
+	}
+	key, err := expandHome(cfg.Key)
+	if err != nil {
+		return "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, cfg.program(FormatSSH), "-Y", "sign", "-n", sshNamespace, "-f", key)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		return "", signFailed(cfg, err, stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+func sshVerify(ctx context.Context, cfg Config, payload []byte, signature string) (*Verification, error) {
+	if cfg.AllowedSignersFile == "" {
+		return nil, ErrNoAllowedSigners
+	}
+	allowed, err := expandHome(cfg.AllowedSignersFile)
+	if err != nil {
+		return nil, err
+	}
+	sigFile, err := writeTempFile(signature)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(sigFile)
+
+	var principals bytes.Buffer
+	cmd := exec.CommandContext(ctx, cfg.program(FormatSSH), "-Y", "find-principals", "-f", allowed, "-s", sigFile)
+	cmd.Stdout = &principals
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &Verification{Output: "No principal matched.\n"}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	principal, _, _ := strings.Cut(strings.TrimSpace(principals.String()), "\n")
+
+	var out bytes.Buffer
+	cmd = exec.CommandContext(ctx, cfg.program(FormatSSH), "-Y", "verify", "-n", sshNamespace, "-f", allowed, "-I", principal, "-s", sigFile)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err = cmd.Run()
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, err
+	}
+	return &Verification{Good: err == nil, Signer: principal, Output: out.String()}, nil
+}
+
+// hasStatus returns whether the gpg status output |status| has a line with the keyword given.
+func hasStatus(status, keyword string) bool {
+	for _, line := range statusLines(status) {
+		if line == keyword || strings.HasPrefix(line, keyword+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// statusLines returns the lines of the gpg status output |status|, without their "[GNUPG:] " prefix.
+func statusLines(status string) []string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(status))
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "[GNUPG:] ") {
+			lines = append(lines, strings.TrimPrefix(line, "[GNUPG:] "))
+		}
+	}
+	return lines
+}
+
+func signFailed(cfg Config, err error, output string) error {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if !strings.HasPrefix(line, "[GNUPG:] ") && line != "" {
+			lines = append(lines, line)
+		}
+	}
+	msg := "failed to sign the data"
+	if len(lines) > 0 {
+		msg += ": " + strings.Join(lines, "\n")
+	} else if err != nil {
+		msg += ": " + err.Error()
+	}
+	if cfg.Key != "" {
+		msg += fmt.Sprintf(" (signing key: %s)", cfg.Key)
+	}
+	return errors.New(msg)
+}
+
+func writeTempFile(contents string) (string, error) {
+	f, err := os.CreateTemp("", "dolt-signature-*")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	_, err = f.WriteString(contents)
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+func expandHome(path string) (string, error) {
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, strings.TrimPrefix(path, "~/")), nil
+	}
+	return path, nil
+}
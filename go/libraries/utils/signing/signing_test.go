@@ -0,0 +1,70 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signing
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSHSignatures(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen isn't installed")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	key := filepath.Join(dir, "id_ed25519")
+	out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "signer", "-f", key).CombinedOutput()
+	require.NoError(t, err, string(out))
+	pub, err := os.ReadFile(key + ".pub")
+	require.NoError(t, err)
+	allowed := filepath.Join(dir, "allowed_signers")
+	require.NoError(t, os.WriteFile(allowed, []byte("signer@example.com "+string(pub)), 0600))
+
+	cfg := Config{Format: FormatSSH, Key: key, AllowedSignersFile: allowed}
+	payload := []byte("payload\n")
+	sig, err := Sign(ctx, cfg, payload)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sig, "-----BEGIN SSH SIGNATURE-----"))
+
+	ver, err := Verify(ctx, cfg, payload, sig)
+	require.NoError(t, err)
+	assert.True(t, ver.Good, ver.Output)
+	assert.Equal(t, "signer@example.com", ver.Signer)
+
+	ver, err = Verify(ctx, cfg, []byte("other payload\n"), sig)
+	require.NoError(t, err)
+	assert.False(t, ver.Good)
+
+	// signatures by keys that aren't allowed aren't good
+	require.NoError(t, os.WriteFile(allowed, nil, 0600))
+	ver, err = Verify(ctx, cfg, payload, sig)
+	require.NoError(t, err)
+	assert.False(t, ver.Good)
+
+	_, err = Verify(ctx, Config{}, payload, sig)
+	assert.ErrorIs(t, err, ErrNoAllowedSigners)
+	_, err = Sign(ctx, Config{Format: FormatSSH}, payload)
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+	_, err = Sign(ctx, Config{Format: "x509"}, payload)
+	assert.Error(t, err)
+}
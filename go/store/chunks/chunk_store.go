@@ -157,6 +157,56 @@ type GenerationalCS interface {
 	OldGen() ChunkStoreGarbageCollector
 }
 
+// ShallowChunkStore is implemented by ChunkStores that can hold a shallow clone of a database, which is missing the
+// history beyond some of its commits. The commits missing from the clone are recorded by the store, so that references
+// to them are treated as absent rather than dangling.
+type ShallowChunkStore interface {
+	// MissingCommits returns the addresses of the commits missing from the store. The set returned must not be
+	// modified.
+	MissingCommits(ctx context.Context) (hash.HashSet, error)
+	// SetMissingCommits records the addresses of the commits missing from the store, replacing the ones recorded
+	// before. An empty set records that the store isn't a shallow clone.
+	SetMissingCommits(ctx context.Context, missing hash.HashSet) error
+}
+
+// SparseChunkStore is implemented by ChunkStores that can hold a sparse clone of a database, which is missing the data
+// of some of its tables. The addresses of the values whose chunks weren't fetched are recorded by the store, so that
+// references to them are treated as absent rather than dangling, and they're fetched the first time they're read.
+type SparseChunkStore interface {
+	// SparseChunks returns the addresses of the values whose chunks haven't been fetched into the store. The set
+	// returned must not be modified.
+	SparseChunks(ctx context.Context) (hash.HashSet, error)
+	// SetSparseChunks records the addresses of the values whose chunks haven't been fetched into the store, replacing
+	// the ones recorded before. An empty set records that the store isn't a sparse clone.
+	SetSparseChunks(ctx context.Context, sparse hash.HashSet) error
+	// SetSparseFetcher sets the function that fetches the values at sparse addresses when they're first read.
+	SetSparseFetcher(fetch SparseFetcher)
+}
+
+// SparseFetcher fetches the chunks of the values at |addrs| into a SparseChunkStore, along with all the chunks they
+// reference.
+type SparseFetcher func(ctx context.Context, addrs hash.HashSet) error
+
+// CommitMetaRecord is the author and dates of a commit, as indexed by a CommitMetaStore.
+type CommitMetaRecord struct {
+	Addr          hash.Hash
+	Name          string
+	Email         string
+	Timestamp     uint64
+	UserTimestamp int64
+}
+
+// CommitMetaStore is implemented by ChunkStores that can persist an index of the authors and dates of their commits
+// alongside their table files, so that the history of a commit can be filtered by author or date without loading its
+// commits, and without indexing them again each time the store is opened.
+type CommitMetaStore interface {
+	// GetCommitMetas returns the records of the commits of |addrs| that are in the index. Commits that aren't indexed
+	// are left out.
+	GetCommitMetas(ctx context.Context, addrs []hash.Hash) (map[hash.Hash]CommitMetaRecord, error)
+	// PutCommitMetas adds |recs| to the index.
+	PutCommitMetas(ctx context.Context, recs []CommitMetaRecord) error
+}
+
 var ErrUnsupportedOperation = errors.New("operation not supported")
 
 var ErrGCGenerationExpired = errors.New("garbage collection generation expired")
@@ -75,3 +75,11 @@ type TableFileStore interface {
 	// SupportedOperations returns a description of the support TableFile operations. Some stores only support reading table files, not writing.
 	SupportedOperations() TableFileStoreOps
 }
+
+// TableFileValidator is implemented by TableFileStores that can check the contents of their table files.
+type TableFileValidator interface {
+	// ValidateTableFiles reads every chunk of every table file in the store, checking it against the table file's
+	// index and its address. |cb| is called for each table file with its id, its chunk count, and the error it failed
+	// validation with, or nil if it is valid. If |cb| returns an error, validation stops and that error is returned.
+	ValidateTableFiles(ctx context.Context, cb func(fileID string, numChunks int, err error) error) error
+}
@@ -169,6 +169,10 @@ func commit_flatbuffer(vaddr hash.Hash, opts CommitOptions, heights []uint64, pa
 	nameoff := builder.CreateString(opts.Meta.Name)
 	emailoff := builder.CreateString(opts.Meta.Email)
 	descoff := builder.CreateString(opts.Meta.Description)
+	var sigoff flatbuffers.UOffsetT
+	if opts.Meta.Signature != "" {
+		sigoff = builder.CreateString(opts.Meta.Signature)
+	}
 	serial.CommitStart(builder)
 	serial.CommitAddRoot(builder, vaddroff)
 	serial.CommitAddHeight(builder, maxheight+1)
@@ -179,6 +183,9 @@ func commit_flatbuffer(vaddr hash.Hash, opts CommitOptions, heights []uint64, pa
 	serial.CommitAddDescription(builder, descoff)
 	serial.CommitAddTimestampMillis(builder, opts.Meta.Timestamp)
 	serial.CommitAddUserTimestampMillis(builder, opts.Meta.UserTimestamp)
+	if opts.Meta.Signature != "" {
+		serial.CommitAddSignature(builder, sigoff)
+	}
 
 	bytes := serial.FinishMessage(builder, serial.CommitEnd(builder), []byte(serial.CommitFileID))
 	return bytes, maxheight + 1
@@ -217,6 +224,10 @@ func newCommitForValue(ctx context.Context, cs chunks.ChunkStore, vrw types.Valu
 		if err != nil {
 			return nil, err
 		}
+		opts.Meta, err = signCommit(ctx, r.TargetHash(), opts)
+		if err != nil {
+			return nil, err
+		}
 		bs, height := commit_flatbuffer(r.TargetHash(), opts, heights, parentClosureAddr)
 		v := types.SerialMessage(bs)
 		addr, err := v.Hash(vrw.Format())
@@ -226,6 +237,10 @@ func newCommitForValue(ctx context.Context, cs chunks.ChunkStore, vrw types.Valu
 		return &Commit{v, addr, height}, nil
 	}
 
+	if opts.Signer != nil {
+		return nil, ErrSigningUnsupported
+	}
+
 	metaSt, err := opts.Meta.toNomsStruct(vrw.Format())
 	if err != nil {
 		return nil, err
@@ -446,6 +461,24 @@ func FindClosureCommonAncestor(ctx context.Context, cl CommitClosure, cm *Commit
 	return hash.Hash{}, false, nil
 }
 
+// isMissingCommit returns whether the commit at |addr| is recorded as missing from the chunk store of |vr|, because
+// the database is a shallow clone.
+func isMissingCommit(ctx context.Context, vr types.ValueReader, addr hash.Hash) (bool, error) {
+	vs, ok := vr.(*types.ValueStore)
+	if !ok {
+		return false, nil
+	}
+	scs, ok := vs.ChunkStore().(chunks.ShallowChunkStore)
+	if !ok {
+		return false, nil
+	}
+	missing, err := scs.MissingCommits(ctx)
+	if err != nil {
+		return false, err
+	}
+	return missing.Has(addr), nil
+}
+
 // GetCommitParents returns |Ref|s to the parents of the commit.
 func GetCommitParents(ctx context.Context, vr types.ValueReader, cv types.Value) ([]*Commit, error) {
 	if sm, ok := cv.(types.SerialMessage); ok {
@@ -461,9 +494,16 @@ func GetCommitParents(ctx context.Context, vr types.ValueReader, cv types.Value)
 		if err != nil {
 			return nil, err
 		}
-		res := make([]*Commit, len(vals))
+		res := make([]*Commit, 0, len(vals))
 		for i, v := range vals {
 			if v == nil {
+				// the history of a shallow clone ends at the commits whose parents weren't fetched
+				missing, err := isMissingCommit(ctx, vr, addrs[i])
+				if err != nil {
+					return nil, err
+				} else if missing {
+					continue
+				}
 				return nil, fmt.Errorf("GetCommitParents: Did not find parent Commit in ValueReader: %s", addrs[i].String())
 			}
 			var csm serial.Commit
@@ -471,11 +511,11 @@ func GetCommitParents(ctx context.Context, vr types.ValueReader, cv types.Value)
 			if err != nil {
 				return nil, err
 			}
-			res[i] = &Commit{
+			res = append(res, &Commit{
 				val:    v,
 				height: csm.Height(),
 				addr:   addrs[i],
-			}
+			})
 		}
 		return res, nil
 	}
@@ -557,6 +597,7 @@ func GetCommitMeta(ctx context.Context, cv types.Value) (*CommitMeta, error) {
 		ret.Description = string(cmsg.Description())
 		ret.Timestamp = cmsg.TimestampMillis()
 		ret.UserTimestamp = cmsg.UserTimestampMillis()
+		ret.Signature = string(cmsg.Signature())
 		return ret, nil
 	}
 	c, ok := cv.(types.Struct)
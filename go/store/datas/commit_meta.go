@@ -60,6 +60,8 @@ type CommitMeta struct {
 	Timestamp     uint64
 	Description   string
 	UserTimestamp int64
+	// Signature is the armored signature of a signed commit, or empty
+	Signature string
 }
 
 // NewCommitMeta creates a CommitMeta instance from a name, email, and description and uses the current time for the
@@ -118,7 +120,7 @@ func NewCommitMetaWithUserTS(name, email, desc string, userTS time.Time) (*Commi
 	committerDateMillis := uint64(CommitterDate().UnixMilli())
 	authorDateMillis := userTS.UnixMilli()
 
-	return &CommitMeta{n, e, committerDateMillis, d, authorDateMillis}, nil
+	return &CommitMeta{Name: n, Email: e, Timestamp: committerDateMillis, Description: d, UserTimestamp: authorDateMillis}, nil
 }
 
 func getRequiredFromSt(st types.Struct, k string) (types.Value, error) {
@@ -165,11 +167,11 @@ func CommitMetaFromNomsSt(st types.Struct) (*CommitMeta, error) {
 	}
 
 	return &CommitMeta{
-		string(n.(types.String)),
-		string(e.(types.String)),
-		uint64(ts.(types.Uint)),
-		string(d.(types.String)),
-		int64(userTS.(types.Int)),
+		Name:          string(n.(types.String)),
+		Email:         string(e.(types.String)),
+		Timestamp:     uint64(ts.(types.Uint)),
+		Description:   string(d.(types.String)),
+		UserTimestamp: int64(userTS.(types.Int)),
 	}, nil
 }
 
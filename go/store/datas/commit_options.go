@@ -33,4 +33,8 @@ type CommitOptions struct {
 	Parents []hash.Hash
 
 	Meta *CommitMeta
+
+	// Signer, if set, signs the commit, whose signature is recorded in its
+	// metadata.
+	Signer Signer
 }
@@ -97,6 +97,13 @@ type Database interface {
 	// `opts.Meta`.
 	Tag(ctx context.Context, ds Dataset, commitAddr hash.Hash, opts TagOptions) (Dataset, error)
 
+	// SetTag is like Tag, but the Dataset given may already have a head,
+	// which must be a Tag. The Dataset is set to the new Tag in a single
+	// atomic update, as long as its head is still the one in |ds|. If the
+	// head has changed since |ds| was read, SetTag returns ErrMergeNeeded
+	// and the Dataset is unchanged.
+	SetTag(ctx context.Context, ds Dataset, commitAddr hash.Hash, opts TagOptions) (Dataset, error)
+
 	// UpdateStashList updates the stash list dataset only with given address hash to the updated stash list.
 	// The new/updated stash list address should be obtained before calling this function depending on
 	// whether add or remove a stash actions have been performed. This function does not perform any actions
@@ -138,6 +145,12 @@ type Database interface {
 	// error will be non-nil.
 	SetHead(ctx context.Context, ds Dataset, newHeadAddr hash.Hash) (Dataset, error)
 
+	// SetHeadIfUnchanged is like SetHead, but only for Commits, and only if the
+	// current Head of ds is still |currHeadAddr|, or ds isn't present in the
+	// Database if |currHeadAddr| is empty. If it has changed, it returns
+	// 'ErrMergeNeeded'.
+	SetHeadIfUnchanged(ctx context.Context, ds Dataset, newHeadAddr, currHeadAddr hash.Hash) (Dataset, error)
+
 	// FastForward takes a types.Ref to a Commit object and makes it the new
 	// Head of ds iff it is a descendant of the current Head. Intended to be
 	// used e.g. after a call to Pull(). If the update cannot be performed,
@@ -183,6 +196,14 @@ type GarbageCollector interface {
 	GC(ctx context.Context, oldGenRefs, newGenRefs hash.HashSet, safepointF func() error) error
 }
 
+// GCRootPinner provides a method to keep data that is no longer reachable from a store's root from being removed by
+// garbage collection while it's still in use.
+type GCRootPinner interface {
+	// PinGCRoots keeps all data reachable from |roots| through garbage collection until the returned function is
+	// called.
+	PinGCRoots(ctx context.Context, roots hash.HashSet) (unpin func(), err error)
+}
+
 // CanUsePuller returns true if a datas.Puller can be used to pull data from one Database into another.  Not all
 // Databases support this yet.
 func CanUsePuller(db Database) bool {
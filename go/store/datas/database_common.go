@@ -373,6 +373,29 @@ func (db *database) doSetHead(ctx context.Context, ds Dataset, addr hash.Hash) e
 	})
 }
 
+func (db *database) SetHeadIfUnchanged(ctx context.Context, ds Dataset, newHeadAddr, currHeadAddr hash.Hash) (Dataset, error) {
+	return db.doHeadUpdate(ctx, ds, func(ds Dataset) error { return db.doSetHeadIfUnchanged(ctx, ds, newHeadAddr, currHeadAddr) })
+}
+
+func (db *database) doSetHeadIfUnchanged(ctx context.Context, ds Dataset, newHeadAddr, currHeadAddr hash.Hash) error {
+	newHead, err := db.readHead(ctx, newHeadAddr)
+	if err != nil {
+		return err
+	}
+	if newHead == nil {
+		return fmt.Errorf("SetHeadIfUnchanged: new head address %v not found", newHeadAddr)
+	}
+	if newHead.TypeName() != commitName {
+		return fmt.Errorf("SetHeadIfUnchanged: target value of new head address %v is not a commit.", newHeadAddr)
+	}
+
+	err = db.doCommit(ctx, ds.ID(), currHeadAddr, newHead.value())
+	if err == ErrAlreadyCommitted {
+		return nil
+	}
+	return err
+}
+
 func (db *database) FastForward(ctx context.Context, ds Dataset, newHeadAddr hash.Hash) (Dataset, error) {
 	return db.doHeadUpdate(ctx, ds, func(ds Dataset) error { return db.doFastForward(ctx, ds, newHeadAddr) })
 }
@@ -541,7 +564,11 @@ func (db *database) Tag(ctx context.Context, ds Dataset, commitAddr hash.Hash, o
 		ctx,
 		ds,
 		func(ds Dataset) error {
-			addr, tagRef, err := newTag(ctx, db, commitAddr, opts.Meta)
+			meta, err := opts.signedMeta(ctx, db.Format(), ds.ID(), commitAddr)
+			if err != nil {
+				return err
+			}
+			addr, tagRef, err := newTag(ctx, db, commitAddr, meta)
 			if err != nil {
 				return err
 			}
@@ -580,6 +607,62 @@ func (db *database) doTag(ctx context.Context, datasetID string, tagAddr hash.Ha
 	})
 }
 
+func (db *database) SetTag(ctx context.Context, ds Dataset, commitAddr hash.Hash, opts TagOptions) (Dataset, error) {
+	if ds.HasHead() && !ds.IsTag() {
+		return Dataset{}, fmt.Errorf("cannot change type of head of %s to tag", ds.ID())
+	}
+	return db.doHeadUpdate(
+		ctx,
+		ds,
+		func(ds Dataset) error {
+			meta, err := opts.signedMeta(ctx, db.Format(), ds.ID(), commitAddr)
+			if err != nil {
+				return err
+			}
+			addr, tagRef, err := newTag(ctx, db, commitAddr, meta)
+			if err != nil {
+				return err
+			}
+			return db.doSetTag(ctx, ds, addr, tagRef)
+		},
+	)
+}
+
+// doSetTag replaces the head of |ds| with the tag given, as long as the head is still the one in |ds|.
+func (db *database) doSetTag(ctx context.Context, ds Dataset, tagAddr hash.Hash, tagRef types.Ref) error {
+	datasetID := ds.ID()
+	expected, _ := ds.MaybeHeadAddr()
+	return db.update(ctx, func(ctx context.Context, datasets types.Map) (types.Map, error) {
+		var curr hash.Hash
+		currRef, ok, err := datasets.MaybeGet(ctx, types.String(datasetID))
+		if err != nil {
+			return types.Map{}, err
+		}
+		if ok {
+			curr = currRef.(types.Ref).TargetHash()
+		}
+		if curr != expected {
+			return types.Map{}, ErrMergeNeeded
+		}
+
+		return datasets.Edit().Set(types.String(datasetID), tagRef).Map(ctx)
+	}, func(ctx context.Context, am prolly.AddressMap) (prolly.AddressMap, error) {
+		curr, err := am.Get(ctx, datasetID)
+		if err != nil {
+			return prolly.AddressMap{}, err
+		}
+		if curr != expected {
+			return prolly.AddressMap{}, ErrMergeNeeded
+		}
+		ae := am.Editor()
+		err = ae.Update(ctx, datasetID, tagAddr)
+		if err != nil {
+			return prolly.AddressMap{}, err
+		}
+		return ae.Flush(ctx)
+	})
+}
+
 // UpdateStashList updates the stash list dataset only with given address hash to the updated stash list.
 // The new/updated stash list address should be obtained before calling this function depending on
 // whether add or remove a stash actions have been performed. This function does not perform any actions
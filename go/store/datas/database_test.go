@@ -23,6 +23,7 @@ package datas
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -538,6 +539,130 @@ func (suite *DatabaseSuite) TestSetHead() {
 	suite.True(mustHeadValue(ds).Equals(b))
 }
 
+func (suite *DatabaseSuite) TestSetHeadIfUnchanged() {
+	ctx := context.Background()
+
+	// |a| <- |b|
+	ds, err := suite.db.GetDataset(ctx, "ds1")
+	suite.Require().NoError(err)
+	ds, err = CommitValue(ctx, suite.db, ds, types.String("a"))
+	suite.Require().NoError(err)
+	aCommitAddr := mustHeadAddr(ds)
+	ds, err = CommitValue(ctx, suite.db, ds, types.String("b"))
+	suite.Require().NoError(err)
+	bCommitAddr := mustHeadAddr(ds)
+
+	// the head isn't at |a| anymore
+	_, err = suite.db.SetHeadIfUnchanged(ctx, ds, aCommitAddr, aCommitAddr)
+	suite.ErrorIs(err, ErrMergeNeeded)
+	_, err = suite.db.SetHeadIfUnchanged(ctx, ds, aCommitAddr, hash.Hash{})
+	suite.ErrorIs(err, ErrMergeNeeded)
+
+	ds, err = suite.db.SetHeadIfUnchanged(ctx, ds, aCommitAddr, bCommitAddr)
+	suite.Require().NoError(err)
+	suite.True(mustHeadValue(ds).Equals(types.String("a")))
+
+	// a dataset that isn't there yet can only be created from an empty address
+	other, err := suite.db.GetDataset(ctx, "ds2")
+	suite.Require().NoError(err)
+	_, err = suite.db.SetHeadIfUnchanged(ctx, other, bCommitAddr, aCommitAddr)
+	suite.ErrorIs(err, ErrMergeNeeded)
+	other, err = suite.db.SetHeadIfUnchanged(ctx, other, bCommitAddr, hash.Hash{})
+	suite.Require().NoError(err)
+	suite.True(mustHeadValue(other).Equals(types.String("b")))
+}
+
+func (suite *DatabaseSuite) TestSetTag() {
+	ctx := context.Background()
+	cds, err := suite.db.GetDataset(ctx, "commits")
+	suite.Require().NoError(err)
+	cds, err = CommitValue(ctx, suite.db, cds, types.String("a"))
+	suite.Require().NoError(err)
+	commitAddr := mustHeadAddr(cds)
+
+	ds, err := suite.db.GetDataset(ctx, "record")
+	suite.Require().NoError(err)
+	first, err := suite.db.SetTag(ctx, ds, commitAddr, TagOptions{Meta: &TagMeta{Description: "first"}})
+	suite.Require().NoError(err)
+	meta, _, err := first.HeadTag()
+	suite.Require().NoError(err)
+	suite.Equal("first", meta.Description)
+
+	second, err := suite.db.SetTag(ctx, first, commitAddr, TagOptions{Meta: &TagMeta{Description: "second"}})
+	suite.Require().NoError(err)
+	meta, _, err = second.HeadTag()
+	suite.Require().NoError(err)
+	suite.Equal("second", meta.Description)
+
+	// |first| is stale, so writing from it has to fail
+	_, err = suite.db.SetTag(ctx, first, commitAddr, TagOptions{Meta: &TagMeta{Description: "third"}})
+	suite.ErrorIs(err, ErrMergeNeeded)
+	_, err = suite.db.SetTag(ctx, ds, commitAddr, TagOptions{Meta: &TagMeta{Description: "third"}})
+	suite.ErrorIs(err, ErrMergeNeeded)
+
+	ds, err = suite.db.GetDataset(ctx, "record")
+	suite.Require().NoError(err)
+	meta, _, err = ds.HeadTag()
+	suite.Require().NoError(err)
+	suite.Equal("second", meta.Description)
+
+	// a commit can't be replaced with a tag
+	_, err = suite.db.SetTag(ctx, cds, commitAddr, TagOptions{Meta: &TagMeta{Description: "first"}})
+	suite.Error(err)
+}
+
+func (suite *DatabaseSuite) TestSignedCommitAndTag() {
+	ctx := context.Background()
+	var signed [][]byte
+	signer := func(ctx context.Context, payload []byte) (string, error) {
+		signed = append(signed, payload)
+		return fmt.Sprintf("signature %d", len(signed)), nil
+	}
+
+	ds, err := suite.db.GetDataset(ctx, "ds1")
+	suite.Require().NoError(err)
+	ds, err = CommitValue(ctx, suite.db, ds, types.String("a"))
+	suite.Require().NoError(err)
+	aCommitAddr := mustHeadAddr(ds)
+
+	meta := &CommitMeta{Name: "name", Email: "email", Description: "signed", Timestamp: 1, UserTimestamp: 2}
+	ds, err = suite.db.Commit(ctx, ds, types.String("b"), CommitOptions{Meta: meta, Signer: signer})
+	if !suite.db.Format().UsesFlatbuffers() {
+		suite.ErrorIs(err, ErrSigningUnsupported)
+		return
+	}
+	suite.Require().NoError(err)
+	suite.Equal("", meta.Signature)
+
+	cm, err := LoadCommitAddr(ctx, suite.db, mustHeadAddr(ds))
+	suite.Require().NoError(err)
+	gotMeta, err := GetCommitMeta(ctx, cm.NomsValue())
+	suite.Require().NoError(err)
+	suite.Equal("signature 1", gotMeta.Signature)
+	payload, sig, err := GetCommitSignature(cm)
+	suite.Require().NoError(err)
+	suite.Equal("signature 1", sig)
+	suite.Equal(signed[0], payload)
+	suite.Contains(string(payload), "parent "+aCommitAddr.String())
+
+	// unsigned commits have no signature
+	unsigned, err := LoadCommitAddr(ctx, suite.db, aCommitAddr)
+	suite.Require().NoError(err)
+	_, sig, err = GetCommitSignature(unsigned)
+	suite.Require().NoError(err)
+	suite.Equal("", sig)
+
+	tds, err := suite.db.GetDataset(ctx, "refs/tags/v1")
+	suite.Require().NoError(err)
+	tagMeta := &TagMeta{Name: "name", Email: "email", Description: "signed tag"}
+	tds, err = suite.db.Tag(ctx, tds, aCommitAddr, TagOptions{Meta: tagMeta, Signer: signer})
+	suite.Require().NoError(err)
+	gotTagMeta, commitAddr, err := tds.HeadTag()
+	suite.Require().NoError(err)
+	suite.Equal("signature 2", gotTagMeta.Signature)
+	suite.Equal(signed[1], TagSigningPayload(tds.ID(), commitAddr, gotTagMeta))
+}
+
 func (suite *DatabaseSuite) TestFastForward() {
 	datasetID := "ds1"
 
@@ -30,6 +30,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/dconfig"
 	"github.com/dolthub/dolt/go/store/chunks"
@@ -79,6 +80,9 @@ type Puller struct {
 
 	statsCh chan Stats
 	stats   *stats
+
+	// sendLimiter, if it's set, limits the rate table files are written to the sink at.
+	sendLimiter *rate.Limiter
 }
 
 // NewPuller creates a new Puller instance to do the syncing.  If a nil puller is returned without error that means
@@ -157,6 +161,12 @@ func NewPuller(
 	return p, nil
 }
 
+// SetSendLimiter limits the rate the puller writes table files to the sink at to |l|, which may be shared with other
+// pullers. A nil |l| doesn't limit it.
+func (p *Puller) SetSendLimiter(l *rate.Limiter) {
+	p.sendLimiter = l
+}
+
 func (p *Puller) Logf(fmt string, args ...interface{}) {
 	if p.pushLog != nil {
 		p.pushLog.Printf(fmt, args...)
@@ -188,6 +198,23 @@ func (c countingReader) Read(p []byte) (int, error) {
 	return n, err
 }
 
+// limitedReader waits on |limiter| before each read, which reads no more than the limiter's burst.
+type limitedReader struct {
+	io.ReadCloser
+	ctx     context.Context
+	limiter *rate.Limiter
+}
+
+func (l limitedReader) Read(p []byte) (int, error) {
+	if burst := l.limiter.Burst(); burst > 0 && len(p) > burst {
+		p = p[:burst]
+	}
+	if err := l.limiter.WaitN(l.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return l.ReadCloser.Read(p)
+}
+
 func emitStats(s *stats, ch chan Stats) (cancel func()) {
 	done := make(chan struct{})
 	var wg sync.WaitGroup
@@ -329,6 +356,9 @@ func (p *Puller) uploadTempTableFile(ctx context.Context, tmpTblFile tempTblFile
 			localUploaded = 0
 		}
 		fWithStats := countingReader{countingReader{rc, &localUploaded}, &p.stats.finishedSendBytes}
+		if p.sendLimiter != nil {
+			return limitedReader{fWithStats, ctx, p.sendLimiter}, uint64(fileSize), nil
+		}
 
 		return fWithStats, uint64(fileSize), nil
 	})
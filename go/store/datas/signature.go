@@ -0,0 +1,142 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrSigningUnsupported is returned when a commit or tag is signed in a database that can't record its signature.
+var ErrSigningUnsupported = errors.New("signed commits and tags are only supported in the __DOLT__ format")
+
+// Signer signs |payload|, the signing payload of a commit or tag, and returns the armored signature to record in its
+// metadata.
+type Signer func(ctx context.Context, payload []byte) (string, error)
+
+// CommitSigningPayload returns the payload that's signed to sign a commit of the root value at |rootAddr|, with the
+// parents and metadata given. It covers everything that identifies the commit but its signature.
+func CommitSigningPayload(rootAddr hash.Hash, parents []hash.Hash, meta *CommitMeta) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "root %s\n", rootAddr.String())
+	for _, p := range parents {
+		fmt.Fprintf(&buf, "parent %s\n", p.String())
+	}
+	fmt.Fprintf(&buf, "author %s <%s> %d\n", meta.Name, meta.Email, meta.UserTimestamp)
+	fmt.Fprintf(&buf, "committer %s <%s> %d\n", meta.Name, meta.Email, meta.Timestamp)
+	fmt.Fprintf(&buf, "\n%s\n", meta.Description)
+	return buf.Bytes()
+}
+
+// TagSigningPayload returns the payload that's signed to sign the tag of |commitAddr| at the dataset |datasetID|, with
+// the metadata given. It covers everything that identifies the tag but its signature.
+func TagSigningPayload(datasetID string, commitAddr hash.Hash, meta *TagMeta) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "object %s\n", commitAddr.String())
+	fmt.Fprintf(&buf, "tag %s\n", datasetID)
+	fmt.Fprintf(&buf, "tagger %s <%s> %d\n", meta.Name, meta.Email, meta.UserTimestamp)
+	fmt.Fprintf(&buf, "timestamp %d\n", meta.Timestamp)
+	fmt.Fprintf(&buf, "\n%s\n", meta.Description)
+	return buf.Bytes()
+}
+
+// GetCommitSignature returns the signature of the commit |c|, and the signing payload it was made for. The signature
+// is empty if the commit isn't signed.
+func GetCommitSignature(c *Commit) ([]byte, string, error) {
+	sm, ok := c.NomsValue().(types.SerialMessage)
+	if !ok {
+		return nil, "", nil
+	}
+	data := []byte(sm)
+	if serial.GetFileID(data) != serial.CommitFileID {
+		return nil, "", errors.New("GetCommitSignature: provided value is not a commit.")
+	}
+	var cmsg serial.Commit
+	err := serial.InitCommitRoot(&cmsg, data, serial.MessagePrefixSz)
+	if err != nil {
+		return nil, "", err
+	}
+	sig := string(cmsg.Signature())
+	if sig == "" {
+		return nil, "", nil
+	}
+
+	parents, err := types.SerialCommitParentAddrs(types.Format_DOLT, sm)
+	if err != nil {
+		return nil, "", err
+	}
+	meta := &CommitMeta{
+		Name:          string(cmsg.Name()),
+		Email:         string(cmsg.Email()),
+		Timestamp:     cmsg.TimestampMillis(),
+		Description:   string(cmsg.Description()),
+		UserTimestamp: cmsg.UserTimestampMillis(),
+	}
+	return CommitSigningPayload(hash.New(cmsg.RootBytes()), parents, meta), sig, nil
+}
+
+// signCommit returns the metadata of the commit of the root value at |rootAddr| described by |opts|, signed by its
+// signer if it has one. A signature in the metadata given, copied from another commit, is dropped, since it doesn't
+// sign this one.
+func signCommit(ctx context.Context, rootAddr hash.Hash, opts CommitOptions) (*CommitMeta, error) {
+	if opts.Signer == nil && opts.Meta.Signature == "" {
+		return opts.Meta, nil
+	}
+	meta := *opts.Meta
+	meta.Signature = ""
+	if opts.Signer == nil {
+		return &meta, nil
+	}
+	sig, err := opts.Signer(ctx, CommitSigningPayload(rootAddr, opts.Parents, &meta))
+	if err != nil {
+		return nil, err
+	}
+	meta.Signature = sig
+	return &meta, nil
+}
+
+// signedMeta returns the metadata of the tag of |commitAddr| at |datasetID| described by |opts|, signed by its signer
+// if it has one. As with commits, a signature in the metadata given is dropped.
+func (opts TagOptions) signedMeta(ctx context.Context, nbf *types.NomsBinFormat, datasetID string, commitAddr hash.Hash) (*TagMeta, error) {
+	if opts.Meta == nil {
+		if opts.Signer != nil {
+			return nil, errors.New("cannot sign a tag without metadata")
+		}
+		return nil, nil
+	}
+	if opts.Signer == nil && opts.Meta.Signature == "" {
+		return opts.Meta, nil
+	}
+	meta := *opts.Meta
+	meta.Signature = ""
+	if opts.Signer == nil {
+		return &meta, nil
+	}
+	if !nbf.UsesFlatbuffers() {
+		return nil, ErrSigningUnsupported
+	}
+	sig, err := opts.Signer(ctx, TagSigningPayload(datasetID, commitAddr, &meta))
+	if err != nil {
+		return nil, err
+	}
+	meta.Signature = sig
+	return &meta, nil
+}
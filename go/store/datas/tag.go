@@ -46,6 +46,9 @@ type TagOptions struct {
 	// Meta is a Struct that describes arbitrary metadata about this Tag,
 	// e.g. a timestamp or descriptive text.
 	Meta *TagMeta
+
+	// Signer, if set, signs the tag, whose signature is recorded in its metadata.
+	Signer Signer
 }
 
 // newTag serializes a tag pointing to |commitAddr| with the given |meta|,
@@ -114,11 +117,14 @@ func newTag(ctx context.Context, db *database, commitAddr hash.Hash, meta *TagMe
 func tag_flatbuffer(commitAddr hash.Hash, meta *TagMeta) serial.Message {
 	builder := flatbuffers.NewBuilder(1024)
 	addroff := builder.CreateByteVector(commitAddr[:])
-	var nameOff, emailOff, descOff flatbuffers.UOffsetT
+	var nameOff, emailOff, descOff, sigOff flatbuffers.UOffsetT
 	if meta != nil {
 		nameOff = builder.CreateString(meta.Name)
 		emailOff = builder.CreateString(meta.Email)
 		descOff = builder.CreateString(meta.Description)
+		if meta.Signature != "" {
+			sigOff = builder.CreateString(meta.Signature)
+		}
 	}
 	serial.TagStart(builder)
 	serial.TagAddCommitAddr(builder, addroff)
@@ -128,6 +134,9 @@ func tag_flatbuffer(commitAddr hash.Hash, meta *TagMeta) serial.Message {
 		serial.TagAddDesc(builder, descOff)
 		serial.TagAddTimestampMillis(builder, meta.Timestamp)
 		serial.TagAddUserTimestampMillis(builder, meta.UserTimestamp)
+		if meta.Signature != "" {
+			serial.TagAddSignature(builder, sigOff)
+		}
 	}
 	return serial.FinishMessage(builder, serial.TagEnd(builder), []byte(serial.TagFileID))
 }
@@ -0,0 +1,446 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// The commit metadata index of a store is kept in two files in the directory of the store's new generation. The sorted
+// file holds a header, then an entry for each commit, sorted by address, then the records of the commits in the same
+// order. Its entries are searched in place, so the file is never read into memory. The log holds the records added
+// since the sorted file was written, each prefixed by its address and length, and is merged into the sorted file once
+// it holds commitMetaLogMaxRecords records. The records of commits removed by garbage collection are never looked up
+// again, and are left in the index.
+const (
+	commitMetaFileName    = "commitmeta"
+	commitMetaLogFileName = "commitmeta.log"
+
+	commitMetaLogMaxRecords = 4096
+
+	commitMetaMagic = "DCMI"
+	// commitMetaHeaderSize is the size of the magic number and the uint32 count of entries of the sorted file
+	commitMetaHeaderSize = 8
+	// commitMetaEntrySize is the size of the address, the uint64 record offset and the uint32 record length of an
+	// entry of the sorted file
+	commitMetaEntrySize = hash.ByteLen + 12
+)
+
+var _ chunks.CommitMetaStore = &GenerationalNBS{}
+
+// GetCommitMetas implements chunks.CommitMetaStore. A store that isn't backed by the local filesystem has nothing
+// indexed.
+func (gcs *GenerationalNBS) GetCommitMetas(ctx context.Context, addrs []hash.Hash) (map[hash.Hash]chunks.CommitMetaRecord, error) {
+	found := make(map[hash.Hash]chunks.CommitMetaRecord)
+	path, ok := gcs.storeFilePath(commitMetaFileName)
+	if !ok {
+		return found, nil
+	}
+
+	var rest []hash.Hash
+	gcs.metaMu.Lock()
+	log, err := gcs.commitMetaLog()
+	if err == nil {
+		for _, addr := range addrs {
+			if rec, ok := log[addr]; ok {
+				found[addr] = rec
+			} else {
+				rest = append(rest, addr)
+			}
+		}
+	}
+	gcs.metaMu.Unlock()
+	if err != nil || len(rest) == 0 {
+		return found, err
+	}
+
+	f, count, err := openCommitMetaFile(path)
+	if err != nil || f == nil {
+		return found, err
+	}
+	defer f.Close()
+
+	for _, addr := range rest {
+		rec, ok, err := searchCommitMetaFile(f, count, addr)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			found[addr] = rec
+		}
+	}
+	return found, nil
+}
+
+// PutCommitMetas implements chunks.CommitMetaStore. It returns chunks.ErrUnsupportedOperation if the store isn't
+// backed by the local filesystem.
+func (gcs *GenerationalNBS) PutCommitMetas(ctx context.Context, recs []chunks.CommitMetaRecord) error {
+	path, ok := gcs.storeFilePath(commitMetaFileName)
+	if !ok {
+		return chunks.ErrUnsupportedOperation
+	}
+	logPath, _ := gcs.storeFilePath(commitMetaLogFileName)
+
+	gcs.metaMu.Lock()
+	defer gcs.metaMu.Unlock()
+
+	log, err := gcs.commitMetaLog()
+	if err != nil {
+		return err
+	}
+
+	if len(log)+len(recs) >= commitMetaLogMaxRecords {
+		merged := make([]chunks.CommitMetaRecord, 0, len(log)+len(recs))
+		for _, rec := range log {
+			merged = append(merged, rec)
+		}
+		merged = append(merged, recs...)
+		if err = mergeCommitMetaFile(path, merged); err != nil {
+			return err
+		}
+		// a log left behind by a failed removal only repeats records of the sorted file
+		err = os.Remove(logPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		gcs.metaLog = make(map[hash.Hash]chunks.CommitMetaRecord)
+		return nil
+	}
+
+	var buf []byte
+	for _, rec := range recs {
+		buf = append(buf, rec.Addr[:]...)
+		enc := encodeCommitMetaRecord(nil, rec)
+		buf = binary.AppendUvarint(buf, uint64(len(enc)))
+		buf = append(buf, enc...)
+	}
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(buf)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range recs {
+		log[rec.Addr] = rec
+	}
+	return nil
+}
+
+// commitMetaLog returns the records of the log of the commit metadata index, reading them the first time it's called.
+// A record cut short by a failed write is dropped from the log. Callers must hold metaMu.
+func (gcs *GenerationalNBS) commitMetaLog() (map[hash.Hash]chunks.CommitMetaRecord, error) {
+	if gcs.metaLog != nil {
+		return gcs.metaLog, nil
+	}
+
+	log := make(map[hash.Hash]chunks.CommitMetaRecord)
+	path, _ := gcs.storeFilePath(commitMetaLogFileName)
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	pos := 0
+	for pos < len(data) {
+		n, rec, err := decodeCommitMetaLogRecord(data[pos:])
+		if err != nil {
+			if err = os.Truncate(path, int64(pos)); err != nil {
+				return nil, err
+			}
+			break
+		}
+		log[rec.Addr] = rec
+		pos += n
+	}
+
+	gcs.metaLog = log
+	return log, nil
+}
+
+// decodeCommitMetaLogRecord decodes the record at the start of |b|, returning the number of bytes it takes.
+func decodeCommitMetaLogRecord(b []byte) (int, chunks.CommitMetaRecord, error) {
+	if len(b) < hash.ByteLen {
+		return 0, chunks.CommitMetaRecord{}, io.ErrUnexpectedEOF
+	}
+	addr := hash.New(b[:hash.ByteLen])
+	l, n := binary.Uvarint(b[hash.ByteLen:])
+	start := hash.ByteLen + n
+	if n <= 0 || uint64(len(b)-start) < l {
+		return 0, chunks.CommitMetaRecord{}, io.ErrUnexpectedEOF
+	}
+	rec, err := decodeCommitMetaRecord(addr, b[start:start+int(l)])
+	return start + int(l), rec, err
+}
+
+// openCommitMetaFile opens the sorted file of the commit metadata index at |path|, returning it along with its count
+// of entries. A file that doesn't exist, or that isn't a commit metadata index, is treated as empty, and nil is
+// returned.
+func openCommitMetaFile(path string) (*os.File, int, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	} else if err != nil {
+		return nil, 0, err
+	}
+
+	var header [commitMetaHeaderSize]byte
+	if _, err = f.ReadAt(header[:], 0); err != nil || string(header[:4]) != commitMetaMagic {
+		f.Close()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, 0, err
+		}
+		return nil, 0, nil
+	}
+	return f, int(binary.BigEndian.Uint32(header[4:])), nil
+}
+
+// searchCommitMetaFile returns the record of the commit at |addr| in the sorted file |f|, which has |count| entries,
+// and whether it was found.
+func searchCommitMetaFile(f *os.File, count int, addr hash.Hash) (chunks.CommitMetaRecord, bool, error) {
+	var entry [commitMetaEntrySize]byte
+	var readErr error
+	i := sort.Search(count, func(i int) bool {
+		if readErr != nil {
+			return true
+		}
+		if _, readErr = f.ReadAt(entry[:hash.ByteLen], commitMetaEntryOffset(i)); readErr != nil {
+			return true
+		}
+		return bytes.Compare(entry[:hash.ByteLen], addr[:]) >= 0
+	})
+	if readErr != nil {
+		return chunks.CommitMetaRecord{}, false, readErr
+	}
+	if i == count {
+		return chunks.CommitMetaRecord{}, false, nil
+	}
+
+	if _, err := f.ReadAt(entry[:], commitMetaEntryOffset(i)); err != nil {
+		return chunks.CommitMetaRecord{}, false, err
+	}
+	if hash.New(entry[:hash.ByteLen]) != addr {
+		return chunks.CommitMetaRecord{}, false, nil
+	}
+	off := binary.BigEndian.Uint64(entry[hash.ByteLen:])
+	l := binary.BigEndian.Uint32(entry[hash.ByteLen+8:])
+	b := make([]byte, l)
+	if _, err := f.ReadAt(b, int64(off)); err != nil {
+		return chunks.CommitMetaRecord{}, false, err
+	}
+	rec, err := decodeCommitMetaRecord(addr, b)
+	if err != nil {
+		return chunks.CommitMetaRecord{}, false, err
+	}
+	return rec, true, nil
+}
+
+func commitMetaEntryOffset(i int) int64 {
+	return commitMetaHeaderSize + int64(i)*commitMetaEntrySize
+}
+
+// commitMetaMergeItem is a record of a new sorted file of a commit metadata index, which is either copied from the
+// old sorted file, from |off|, or encoded in |rec|.
+type commitMetaMergeItem struct {
+	addr hash.Hash
+	off  uint64
+	len  uint32
+	rec  []byte
+}
+
+// mergeCommitMetaFile replaces the sorted file of a commit metadata index at |path| with one holding its records along
+// with |recs|, which replace the records of the same commits.
+func mergeCommitMetaFile(path string, recs []chunks.CommitMetaRecord) error {
+	sort.SliceStable(recs, func(i, j int) bool {
+		return bytes.Compare(recs[i].Addr[:], recs[j].Addr[:]) < 0
+	})
+	// the last of the records of a commit replaces the others
+	unique := recs[:0]
+	for i, rec := range recs {
+		if i+1 < len(recs) && recs[i+1].Addr == rec.Addr {
+			continue
+		}
+		unique = append(unique, rec)
+	}
+	recs = unique
+
+	old, count, err := openCommitMetaFile(path)
+	if err != nil {
+		return err
+	}
+	var entries *bufio.Reader
+	if old != nil {
+		defer old.Close()
+		entries = bufio.NewReader(io.NewSectionReader(old, commitMetaHeaderSize, int64(count)*commitMetaEntrySize))
+	}
+
+	var oldItem commitMetaMergeItem
+	var entry [commitMetaEntrySize]byte
+	read, hasOld := 0, false
+	readOld := func() error {
+		hasOld = read < count
+		if !hasOld {
+			return nil
+		}
+		read++
+		if _, err := io.ReadFull(entries, entry[:]); err != nil {
+			return err
+		}
+		oldItem = commitMetaMergeItem{
+			addr: hash.New(entry[:hash.ByteLen]),
+			off:  binary.BigEndian.Uint64(entry[hash.ByteLen:]),
+			len:  binary.BigEndian.Uint32(entry[hash.ByteLen+8:]),
+		}
+		return nil
+	}
+	if err = readOld(); err != nil {
+		return err
+	}
+
+	items := make([]commitMetaMergeItem, 0, count+len(recs))
+	next := 0
+	for hasOld || next < len(recs) {
+		if next < len(recs) && (!hasOld || bytes.Compare(recs[next].Addr[:], oldItem.addr[:]) <= 0) {
+			if hasOld && recs[next].Addr == oldItem.addr {
+				if err = readOld(); err != nil {
+					return err
+				}
+			}
+			enc := encodeCommitMetaRecord(nil, recs[next])
+			items = append(items, commitMetaMergeItem{addr: recs[next].Addr, len: uint32(len(enc)), rec: enc})
+			next++
+		} else {
+			items = append(items, oldItem)
+			if err = readOld(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// write the new file beside the old one, and move it into place, so that a failed write leaves the old one intact
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	err = writeCommitMetaFile(tmp, old, items)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// writeCommitMetaFile writes a sorted file of a commit metadata index holding |items| to |f|, copying the records of
+// the items that aren't encoded from |old|.
+func writeCommitMetaFile(f *os.File, old *os.File, items []commitMetaMergeItem) error {
+	w := bufio.NewWriter(f)
+	var buf [commitMetaEntrySize]byte
+	copy(buf[:], commitMetaMagic)
+	binary.BigEndian.PutUint32(buf[4:], uint32(len(items)))
+	if _, err := w.Write(buf[:commitMetaHeaderSize]); err != nil {
+		return err
+	}
+
+	off := uint64(commitMetaEntryOffset(len(items)))
+	for _, item := range items {
+		copy(buf[:], item.addr[:])
+		binary.BigEndian.PutUint64(buf[hash.ByteLen:], off)
+		binary.BigEndian.PutUint32(buf[hash.ByteLen+8:], item.len)
+		if _, err := w.Write(buf[:]); err != nil {
+			return err
+		}
+		off += uint64(item.len)
+	}
+
+	for _, item := range items {
+		rec := item.rec
+		if rec == nil {
+			rec = make([]byte, item.len)
+			if _, err := old.ReadAt(rec, int64(item.off)); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(rec); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func encodeCommitMetaRecord(buf []byte, rec chunks.CommitMetaRecord) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(rec.Name)))
+	buf = append(buf, rec.Name...)
+	buf = binary.AppendUvarint(buf, uint64(len(rec.Email)))
+	buf = append(buf, rec.Email...)
+	buf = binary.AppendUvarint(buf, rec.Timestamp)
+	return binary.AppendVarint(buf, rec.UserTimestamp)
+}
+
+func decodeCommitMetaRecord(addr hash.Hash, b []byte) (chunks.CommitMetaRecord, error) {
+	rec := chunks.CommitMetaRecord{Addr: addr}
+	readString := func() (string, bool) {
+		l, n := binary.Uvarint(b)
+		if n <= 0 || uint64(len(b)-n) < l {
+			return "", false
+		}
+		s := string(b[n : n+int(l)])
+		b = b[n+int(l):]
+		return s, true
+	}
+
+	var ok bool
+	if rec.Name, ok = readString(); !ok {
+		return rec, fmt.Errorf("invalid commit metadata record for %s", addr)
+	}
+	if rec.Email, ok = readString(); !ok {
+		return rec, fmt.Errorf("invalid commit metadata record for %s", addr)
+	}
+	ts, n := binary.Uvarint(b)
+	if n <= 0 {
+		return rec, fmt.Errorf("invalid commit metadata record for %s", addr)
+	}
+	uts, m := binary.Varint(b[n:])
+	if m <= 0 {
+		return rec, fmt.Errorf("invalid commit metadata record for %s", addr)
+	}
+	rec.Timestamp, rec.UserTimestamp = ts, uts
+	return rec, nil
+}
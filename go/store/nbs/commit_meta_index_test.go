@@ -0,0 +1,119 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestGenerationalCSCommitMetas(t *testing.T) {
+	ctx := context.Background()
+	oldGen, _, _ := makeTestLocalStore(t, 64)
+	newGen, newGenDir, _ := makeTestLocalStore(t, 64)
+	cs := NewGenerationalCS(oldGen, newGen)
+
+	reopen := func() *GenerationalNBS {
+		reopened, err := newLocalStore(ctx, newGen.Version(), newGenDir, defaultMemTableSize, 64, NewUnlimitedMemQuotaProvider())
+		require.NoError(t, err)
+		t.Cleanup(func() { reopened.Close() })
+		return NewGenerationalCS(oldGen, reopened)
+	}
+
+	record := func(i int) chunks.CommitMetaRecord {
+		return chunks.CommitMetaRecord{
+			Addr:          hash.Of([]byte(fmt.Sprint(i))),
+			Name:          fmt.Sprintf("user %d", i),
+			Email:         fmt.Sprintf("user%d@example.com", i),
+			Timestamp:     uint64(1000 + i),
+			UserTimestamp: int64(-i),
+		}
+	}
+
+	a, b, c := record(0), record(1), record(2)
+	found, err := cs.GetCommitMetas(ctx, []hash.Hash{a.Addr})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	// records are appended to the log until it's merged into the sorted file
+	require.NoError(t, cs.PutCommitMetas(ctx, []chunks.CommitMetaRecord{a, b}))
+	_, err = os.Stat(filepath.Join(newGenDir, commitMetaLogFileName))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(newGenDir, commitMetaFileName))
+	assert.True(t, os.IsNotExist(err))
+
+	found, err = reopen().GetCommitMetas(ctx, []hash.Hash{a.Addr, b.Addr, c.Addr})
+	require.NoError(t, err)
+	assert.Equal(t, map[hash.Hash]chunks.CommitMetaRecord{a.Addr: a, b.Addr: b}, found)
+
+	// a record cut short by a failed write is dropped
+	f, err := os.OpenFile(filepath.Join(newGenDir, commitMetaLogFileName), os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.Write(c.Addr[:5])
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	torn := reopen()
+	found, err = torn.GetCommitMetas(ctx, []hash.Hash{a.Addr, b.Addr, c.Addr})
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+	require.NoError(t, torn.PutCommitMetas(ctx, []chunks.CommitMetaRecord{c}))
+	found, err = reopen().GetCommitMetas(ctx, []hash.Hash{c.Addr})
+	require.NoError(t, err)
+	assert.Equal(t, map[hash.Hash]chunks.CommitMetaRecord{c.Addr: c}, found)
+
+	// filling the log merges it into the sorted file, and records of commits in the sorted file replace them
+	cs = reopen()
+	var recs []chunks.CommitMetaRecord
+	for i := 3; i < 2*commitMetaLogMaxRecords+2; i++ {
+		recs = append(recs, record(i))
+	}
+	require.NoError(t, cs.PutCommitMetas(ctx, recs[:commitMetaLogMaxRecords]))
+	_, err = os.Stat(filepath.Join(newGenDir, commitMetaLogFileName))
+	assert.True(t, os.IsNotExist(err))
+	b.Name = "renamed"
+	logged := append([]chunks.CommitMetaRecord{b}, recs[commitMetaLogMaxRecords:len(recs)-2]...)
+	require.NoError(t, cs.PutCommitMetas(ctx, logged))
+	_, err = os.Stat(filepath.Join(newGenDir, commitMetaLogFileName))
+	require.NoError(t, err)
+	found, err = reopen().GetCommitMetas(ctx, []hash.Hash{b.Addr})
+	require.NoError(t, err)
+	assert.Equal(t, b, found[b.Addr])
+	require.NoError(t, cs.PutCommitMetas(ctx, recs[len(recs)-2:]))
+	_, err = os.Stat(filepath.Join(newGenDir, commitMetaLogFileName))
+	assert.True(t, os.IsNotExist(err))
+
+	addrs := []hash.Hash{a.Addr, b.Addr, c.Addr, hash.Of([]byte("missing"))}
+	for _, rec := range recs {
+		addrs = append(addrs, rec.Addr)
+	}
+	found, err = reopen().GetCommitMetas(ctx, addrs)
+	require.NoError(t, err)
+	assert.Len(t, found, 2*commitMetaLogMaxRecords+2)
+	assert.Equal(t, a, found[a.Addr])
+	assert.Equal(t, b, found[b.Addr])
+	assert.Equal(t, c, found[c.Addr])
+	for _, rec := range recs {
+		assert.Equal(t, rec, found[rec.Addr])
+	}
+}
@@ -32,6 +32,22 @@ var _ chunks.TableFileStore = (*GenerationalNBS)(nil)
 type GenerationalNBS struct {
 	oldGen *NomsBlockStore
 	newGen *NomsBlockStore
+
+	// missingMu protects missing, the commits missing from a shallow clone, which are loaded on first use
+	missingMu sync.Mutex
+	missing   hash.HashSet
+
+	// sparseMu protects sparse, the values missing from a sparse clone, which are loaded on first use, and
+	// fetchSparse, which fetches them when they're read. fetchMu serializes the fetches.
+	sparseMu    sync.Mutex
+	sparse      hash.HashSet
+	fetchSparse chunks.SparseFetcher
+	fetchMu     sync.Mutex
+
+	// metaMu protects metaLog, the records of the commit metadata index that haven't been merged into its sorted file,
+	// which are loaded on first use
+	metaMu  sync.Mutex
+	metaLog map[hash.Hash]chunks.CommitMetaRecord
 }
 
 func NewGenerationalCS(oldGen, newGen *NomsBlockStore) *GenerationalNBS {
@@ -55,6 +71,20 @@ func (gcs *GenerationalNBS) OldGen() chunks.ChunkStoreGarbageCollector {
 
 // Get the Chunk for the value of the hash in the store. If the hash is absent from the store EmptyChunk is returned.
 func (gcs *GenerationalNBS) Get(ctx context.Context, h hash.Hash) (chunks.Chunk, error) {
+	c, err := gcs.get(ctx, h)
+	if err != nil || !c.IsEmpty() {
+		return c, err
+	}
+
+	// the values missing from a sparse clone are fetched the first time they're read
+	fetched, err := gcs.fetchSparseChunks(ctx, hash.NewHashSet(h))
+	if err != nil || !fetched {
+		return c, err
+	}
+	return gcs.get(ctx, h)
+}
+
+func (gcs *GenerationalNBS) get(ctx context.Context, h hash.Hash) (chunks.Chunk, error) {
 	c, err := gcs.oldGen.Get(ctx, h)
 
 	if err != nil {
@@ -71,6 +101,36 @@ func (gcs *GenerationalNBS) Get(ctx context.Context, h hash.Hash) (chunks.Chunk,
 // GetMany gets the Chunks with |hashes| from the store. On return, |foundChunks| will have been fully sent all chunks
 // which have been found. Any non-present chunks will silently be ignored.
 func (gcs *GenerationalNBS) GetMany(ctx context.Context, hashes hash.HashSet, found func(context.Context, *chunks.Chunk)) error {
+	sparse, err := gcs.SparseChunks(ctx)
+	if err != nil {
+		return err
+	} else if sparse.Size() == 0 {
+		return gcs.getMany(ctx, hashes, found)
+	}
+
+	mu := &sync.Mutex{}
+	notFound := hashes.Copy()
+	err = gcs.getMany(ctx, hashes, func(ctx context.Context, chunk *chunks.Chunk) {
+		func() {
+			mu.Lock()
+			defer mu.Unlock()
+			delete(notFound, chunk.Hash())
+		}()
+
+		found(ctx, chunk)
+	})
+	if err != nil || len(notFound) == 0 {
+		return err
+	}
+
+	fetched, err := gcs.fetchSparseChunks(ctx, notFound)
+	if err != nil || !fetched {
+		return err
+	}
+	return gcs.getMany(ctx, notFound, found)
+}
+
+func (gcs *GenerationalNBS) getMany(ctx context.Context, hashes hash.HashSet, found func(context.Context, *chunks.Chunk)) error {
 	mu := &sync.Mutex{}
 	notInOldGen := hashes.Copy()
 	err := gcs.oldGen.GetMany(ctx, hashes, func(ctx context.Context, chunk *chunks.Chunk) {
@@ -95,6 +155,36 @@ func (gcs *GenerationalNBS) GetMany(ctx context.Context, hashes hash.HashSet, fo
 }
 
 func (gcs *GenerationalNBS) GetManyCompressed(ctx context.Context, hashes hash.HashSet, found func(context.Context, CompressedChunk)) error {
+	sparse, err := gcs.SparseChunks(ctx)
+	if err != nil {
+		return err
+	} else if sparse.Size() == 0 {
+		return gcs.getManyCompressed(ctx, hashes, found)
+	}
+
+	mu := &sync.Mutex{}
+	notFound := hashes.Copy()
+	err = gcs.getManyCompressed(ctx, hashes, func(ctx context.Context, chunk CompressedChunk) {
+		func() {
+			mu.Lock()
+			defer mu.Unlock()
+			delete(notFound, chunk.Hash())
+		}()
+
+		found(ctx, chunk)
+	})
+	if err != nil || len(notFound) == 0 {
+		return err
+	}
+
+	fetched, err := gcs.fetchSparseChunks(ctx, notFound)
+	if err != nil || !fetched {
+		return err
+	}
+	return gcs.getManyCompressed(ctx, notFound, found)
+}
+
+func (gcs *GenerationalNBS) getManyCompressed(ctx context.Context, hashes hash.HashSet, found func(context.Context, CompressedChunk)) error {
 	mu := &sync.Mutex{}
 	notInOldGen := hashes.Copy()
 	err := gcs.oldGen.GetManyCompressed(ctx, hashes, func(ctx context.Context, chunk CompressedChunk) {
@@ -158,7 +248,7 @@ func (gcs *GenerationalNBS) hasMany(recs []hasRecord) (absent hash.HashSet, err
 // to Flush(). Put may be called concurrently with other calls to Put(),
 // Get(), GetMany(), Has() and HasMany().
 func (gcs *GenerationalNBS) Put(ctx context.Context, c chunks.Chunk, getAddrs chunks.GetAddrsCb) error {
-	return gcs.newGen.putChunk(ctx, c, getAddrs, gcs.hasMany)
+	return gcs.newGen.putChunk(ctx, c, getAddrs, gcs.checkRefs(ctx))
 }
 
 // Returns the NomsBinFormat with which this ChunkSource is compatible.
@@ -189,7 +279,7 @@ func (gcs *GenerationalNBS) Root(ctx context.Context) (hash.Hash, error) {
 // persisted root hash from last to current (or keeps it the same).
 // If last doesn't match the root in persistent storage, returns false.
 func (gcs *GenerationalNBS) Commit(ctx context.Context, current, last hash.Hash) (bool, error) {
-	return gcs.newGen.commit(ctx, current, last, gcs.hasMany)
+	return gcs.newGen.commit(ctx, current, last, gcs.checkRefs(ctx))
 }
 
 // Stats may return some kind of struct that reports statistics about the
@@ -311,18 +401,18 @@ func (gcs *GenerationalNBS) AddTableFilesToManifest(ctx context.Context, fileIdT
 
 // PruneTableFiles deletes old table files that are no longer referenced in the manifest of the new or old gen chunkstores
 func (gcs *GenerationalNBS) PruneTableFiles(ctx context.Context) error {
-	err := gcs.oldGen.pruneTableFiles(ctx, gcs.hasMany)
+	err := gcs.oldGen.pruneTableFiles(ctx, gcs.checkRefs(ctx))
 
 	if err != nil {
 		return err
 	}
 
-	return gcs.newGen.pruneTableFiles(ctx, gcs.hasMany)
+	return gcs.newGen.pruneTableFiles(ctx, gcs.checkRefs(ctx))
 }
 
 // SetRootChunk changes the root chunk hash from the previous value to the new root for the newgen cs
 func (gcs *GenerationalNBS) SetRootChunk(ctx context.Context, root, previous hash.Hash) error {
-	return gcs.newGen.setRootChunk(ctx, root, previous, gcs.hasMany)
+	return gcs.newGen.setRootChunk(ctx, root, previous, gcs.checkRefs(ctx))
 }
 
 // SupportedOperations returns a description of the support TableFile operations. Some stores only support reading table files, not writing.
@@ -0,0 +1,182 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// shallowFileName is the name of the file, in the directory of a store's new generation, that records the commits
+// missing from a shallow clone, one address per line.
+const shallowFileName = "shallow"
+
+var _ chunks.ShallowChunkStore = &GenerationalNBS{}
+
+// MissingCommits implements chunks.ShallowChunkStore. A store that isn't backed by the local filesystem is never a
+// shallow clone.
+func (gcs *GenerationalNBS) MissingCommits(ctx context.Context) (hash.HashSet, error) {
+	gcs.missingMu.Lock()
+	defer gcs.missingMu.Unlock()
+
+	if gcs.missing != nil {
+		return gcs.missing, nil
+	}
+
+	missing := hash.NewHashSet()
+	if path, ok := gcs.storeFilePath(shallowFileName); ok {
+		var err error
+		missing, err = readAddrFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	gcs.missing = missing
+	return missing, nil
+}
+
+// SetMissingCommits implements chunks.ShallowChunkStore. It returns chunks.ErrUnsupportedOperation if the store isn't
+// backed by the local filesystem.
+func (gcs *GenerationalNBS) SetMissingCommits(ctx context.Context, missing hash.HashSet) error {
+	path, ok := gcs.storeFilePath(shallowFileName)
+	if !ok {
+		return chunks.ErrUnsupportedOperation
+	}
+
+	gcs.missingMu.Lock()
+	defer gcs.missingMu.Unlock()
+
+	if err := writeAddrFile(path, missing); err != nil {
+		return err
+	}
+	gcs.missing = missing.Copy()
+	return nil
+}
+
+// checkRefs returns the refCheck of the store, which finds the chunks absent from both of its generations, except for
+// the commits missing from a shallow clone, and the values missing from a sparse one, which are referenced but were
+// never fetched.
+func (gcs *GenerationalNBS) checkRefs(ctx context.Context) refCheck {
+	return func(recs []hasRecord) (hash.HashSet, error) {
+		absent, err := gcs.hasMany(recs)
+		if err != nil || absent.Size() == 0 {
+			return absent, err
+		}
+		missing, err := gcs.MissingCommits(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sparse, err := gcs.SparseChunks(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for h := range absent {
+			if missing.Has(h) || sparse.Has(h) {
+				absent.Remove(h)
+			}
+		}
+		return absent, nil
+	}
+}
+
+// storeFilePath returns the path of the file named |name| in the directory of the store's new generation, if the
+// store is backed by the local filesystem.
+func (gcs *GenerationalNBS) storeFilePath(name string) (string, bool) {
+	switch p := gcs.newGen.p.(type) {
+	case *fsTablePersister:
+		return filepath.Join(p.dir, name), true
+	case *chunkJournal:
+		return filepath.Join(p.persister.dir, name), true
+	default:
+		return "", false
+	}
+}
+
+// readAddrFile reads the addresses in the file at |path|, one per line. A file that doesn't exist holds none.
+func readAddrFile(path string) (hash.HashSet, error) {
+	addrs := hash.NewHashSet()
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return addrs, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		h, ok := hash.MaybeParse(line)
+		if !ok {
+			return nil, fmt.Errorf("invalid address in %s: %s", path, line)
+		}
+		addrs.Insert(h)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return addrs, nil
+}
+
+// writeAddrFile writes |addrs| to the file at |path|, sorted, one per line. The file is removed if |addrs| is empty.
+func writeAddrFile(path string, addrs hash.HashSet) error {
+	if addrs.Size() == 0 {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	lines := make([]string, 0, addrs.Size())
+	for h := range addrs {
+		lines = append(lines, h.String())
+	}
+	sort.Strings(lines)
+
+	// write the new file beside the old one, and move it into place, so that a failed write leaves the old one intact
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.WriteString(strings.Join(lines, "\n") + "\n")
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
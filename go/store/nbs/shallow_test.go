@@ -0,0 +1,58 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestGenerationalCSMissingCommits(t *testing.T) {
+	ctx := context.Background()
+	oldGen, _, _ := makeTestLocalStore(t, 64)
+	newGen, newGenDir, _ := makeTestLocalStore(t, 64)
+	cs := NewGenerationalCS(oldGen, newGen)
+
+	missing, err := cs.MissingCommits(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, missing.Size())
+
+	a, b := hash.Of([]byte("a")), hash.Of([]byte("b"))
+	require.NoError(t, cs.SetMissingCommits(ctx, hash.NewHashSet(a, b)))
+	_, err = os.Stat(filepath.Join(newGenDir, shallowFileName))
+	require.NoError(t, err)
+
+	// a new store over the same files reads the missing commits back
+	reopened, err := newLocalStore(ctx, newGen.Version(), newGenDir, defaultMemTableSize, 64, NewUnlimitedMemQuotaProvider())
+	require.NoError(t, err)
+	defer reopened.Close()
+	missing, err = NewGenerationalCS(oldGen, reopened).MissingCommits(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, hash.NewHashSet(a, b), missing)
+
+	require.NoError(t, cs.SetMissingCommits(ctx, hash.NewHashSet()))
+	_, err = os.Stat(filepath.Join(newGenDir, shallowFileName))
+	assert.True(t, os.IsNotExist(err))
+	missing, err = cs.MissingCommits(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, missing.Size())
+}
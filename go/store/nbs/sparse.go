@@ -0,0 +1,121 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// sparseFileName is the name of the file, in the directory of a store's new generation, that records the values
+// missing from a sparse clone, one address per line.
+const sparseFileName = "sparse"
+
+// ErrSparseChunkNotFetched is returned when reading a value missing from a sparse clone, if the store has no way to
+// fetch it.
+var ErrSparseChunkNotFetched = errors.New("the value hasn't been fetched into this sparse clone, and can't be")
+
+var _ chunks.SparseChunkStore = &GenerationalNBS{}
+
+// SparseChunks implements chunks.SparseChunkStore. A store that isn't backed by the local filesystem is never a sparse
+// clone.
+func (gcs *GenerationalNBS) SparseChunks(ctx context.Context) (hash.HashSet, error) {
+	gcs.sparseMu.Lock()
+	defer gcs.sparseMu.Unlock()
+
+	if gcs.sparse != nil {
+		return gcs.sparse, nil
+	}
+
+	sparse := hash.NewHashSet()
+	if path, ok := gcs.storeFilePath(sparseFileName); ok {
+		var err error
+		sparse, err = readAddrFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	gcs.sparse = sparse
+	return sparse, nil
+}
+
+// SetSparseChunks implements chunks.SparseChunkStore. It returns chunks.ErrUnsupportedOperation if the store isn't
+// backed by the local filesystem.
+func (gcs *GenerationalNBS) SetSparseChunks(ctx context.Context, sparse hash.HashSet) error {
+	path, ok := gcs.storeFilePath(sparseFileName)
+	if !ok {
+		return chunks.ErrUnsupportedOperation
+	}
+
+	gcs.sparseMu.Lock()
+	defer gcs.sparseMu.Unlock()
+
+	if err := writeAddrFile(path, sparse); err != nil {
+		return err
+	}
+	gcs.sparse = sparse.Copy()
+	return nil
+}
+
+// SetSparseFetcher implements chunks.SparseChunkStore.
+func (gcs *GenerationalNBS) SetSparseFetcher(fetch chunks.SparseFetcher) {
+	gcs.sparseMu.Lock()
+	defer gcs.sparseMu.Unlock()
+	gcs.fetchSparse = fetch
+}
+
+// fetchSparseChunks fetches the values at the addresses in |hashes| that are recorded as missing from a sparse clone,
+// and records that they aren't anymore. It returns whether any were fetched.
+func (gcs *GenerationalNBS) fetchSparseChunks(ctx context.Context, hashes hash.HashSet) (bool, error) {
+	// only one fetch runs at a time, so that values read concurrently aren't fetched twice
+	gcs.fetchMu.Lock()
+	defer gcs.fetchMu.Unlock()
+
+	sparse, err := gcs.SparseChunks(ctx)
+	if err != nil || sparse.Size() == 0 {
+		return false, err
+	}
+	toFetch := hash.NewHashSet()
+	for h := range hashes {
+		if sparse.Has(h) {
+			toFetch.Insert(h)
+		}
+	}
+	if toFetch.Size() == 0 {
+		return false, nil
+	}
+
+	gcs.sparseMu.Lock()
+	fetch := gcs.fetchSparse
+	gcs.sparseMu.Unlock()
+	if fetch == nil {
+		return false, fmt.Errorf("%w: %s", ErrSparseChunkNotFetched, toFetch.String())
+	}
+
+	if err := fetch(ctx, toFetch); err != nil {
+		return false, err
+	}
+
+	remaining := sparse.Copy()
+	for h := range toFetch {
+		remaining.Remove(h)
+	}
+	return true, gcs.SetSparseChunks(ctx, remaining)
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestGenerationalCSSparseChunks(t *testing.T) {
+	ctx := context.Background()
+	oldGen, _, _ := makeTestLocalStore(t, 64)
+	newGen, _, _ := makeTestLocalStore(t, 64)
+	cs := NewGenerationalCS(oldGen, newGen)
+
+	c := chunks.NewChunk([]byte("sparse"))
+	require.NoError(t, cs.SetSparseChunks(ctx, hash.NewHashSet(c.Hash())))
+
+	// a sparse value can't be read without a fetcher
+	_, err := cs.Get(ctx, c.Hash())
+	assert.ErrorIs(t, err, ErrSparseChunkNotFetched)
+
+	var fetched []hash.HashSet
+	cs.SetSparseFetcher(func(ctx context.Context, addrs hash.HashSet) error {
+		fetched = append(fetched, addrs)
+		return cs.Put(ctx, c, noopGetAddrs)
+	})
+
+	got, err := cs.Get(ctx, c.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, c.Data(), got.Data())
+	require.Len(t, fetched, 1)
+	assert.Equal(t, hash.NewHashSet(c.Hash()), fetched[0])
+
+	sparse, err := cs.SparseChunks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sparse.Size())
+
+	// an absent value that isn't sparse isn't fetched
+	got, err = cs.Get(ctx, hash.Of([]byte("absent")))
+	require.NoError(t, err)
+	assert.True(t, got.IsEmpty())
+	assert.Len(t, fetched, 1)
+}
+
+func TestGenerationalCSGetManySparseChunks(t *testing.T) {
+	ctx := context.Background()
+	oldGen, _, _ := makeTestLocalStore(t, 64)
+	newGen, _, _ := makeTestLocalStore(t, 64)
+	cs := NewGenerationalCS(oldGen, newGen)
+
+	present := chunks.NewChunk([]byte("present"))
+	require.NoError(t, cs.Put(ctx, present, noopGetAddrs))
+	sparse := chunks.NewChunk([]byte("sparse"))
+	require.NoError(t, cs.SetSparseChunks(ctx, hash.NewHashSet(sparse.Hash())))
+	cs.SetSparseFetcher(func(ctx context.Context, addrs hash.HashSet) error {
+		return cs.Put(ctx, sparse, noopGetAddrs)
+	})
+
+	found := hash.NewHashSet()
+	err := cs.GetMany(ctx, hash.NewHashSet(present.Hash(), sparse.Hash()), func(ctx context.Context, c *chunks.Chunk) {
+		found.Insert(c.Hash())
+	})
+	require.NoError(t, err)
+	assert.Equal(t, hash.NewHashSet(present.Hash(), sparse.Hash()), found)
+}
@@ -0,0 +1,145 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+var _ chunks.TableFileValidator = &NomsBlockStore{}
+var _ chunks.TableFileValidator = &GenerationalNBS{}
+
+// ValidateTableFiles implements chunks.TableFileValidator. The chunk journal is not validated here, as each of its
+// records is checksummed when the journal is loaded.
+func (nbs *NomsBlockStore) ValidateTableFiles(ctx context.Context, cb func(fileID string, numChunks int, err error) error) error {
+	nbs.mu.Lock()
+	sources := make([]chunkSource, 0, len(nbs.tables.upstream))
+	for _, cs := range nbs.tables.upstream {
+		if cs.hash() == journalAddr {
+			continue
+		}
+		cloned, err := cs.clone()
+		if err != nil {
+			nbs.mu.Unlock()
+			for _, s := range sources {
+				s.close()
+			}
+			return err
+		}
+		sources = append(sources, cloned)
+	}
+	nbs.mu.Unlock()
+
+	defer func() {
+		for _, s := range sources {
+			s.close()
+		}
+	}()
+
+	for _, cs := range sources {
+		cnt, err := cs.count()
+		if err == nil {
+			err = validateChunkSource(ctx, cs)
+		}
+		if err := cb(cs.hash().String(), int(cnt), err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateTableFiles implements chunks.TableFileValidator. The table files of the old generation are validated first.
+func (gcs *GenerationalNBS) ValidateTableFiles(ctx context.Context, cb func(fileID string, numChunks int, err error) error) error {
+	err := gcs.oldGen.ValidateTableFiles(ctx, cb)
+	if err != nil {
+		return err
+	}
+	return gcs.newGen.ValidateTableFiles(ctx, cb)
+}
+
+// validateChunkSource reads the chunks of |cs| in the order they are stored, checking that each is where the index
+// says it is, that its checksum matches, and that its data hashes to the address it's indexed by.
+func validateChunkSource(ctx context.Context, cs chunkSource) error {
+	idx, err := cs.index()
+	if err != nil {
+		return err
+	}
+	ords, err := idx.ordinals()
+	if err != nil {
+		return err
+	}
+
+	rd, _, err := cs.reader(ctx)
+	if err != nil {
+		return err
+	}
+	defer rd.Close()
+	br := bufio.NewReader(rd)
+
+	// |ords| maps each chunk's position in the index to its position in the file, so invert it
+	byOrdinal := make([]uint32, len(ords))
+	for i, ord := range ords {
+		byOrdinal[ord] = uint32(i)
+	}
+
+	var offset uint64
+	var buff []byte
+	for _, i := range byOrdinal {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var a addr
+		entry, err := idx.indexEntry(i, &a)
+		if err != nil {
+			return err
+		}
+		if entry.Offset() != offset {
+			return fmt.Errorf("chunk %s: index offset %d does not match data offset %d", a.String(), entry.Offset(), offset)
+		}
+
+		if uint64(entry.Length()) < checksumSize {
+			return fmt.Errorf("chunk %s: index length %d is too short for a chunk", a.String(), entry.Length())
+		}
+
+		if cap(buff) < int(entry.Length()) {
+			buff = make([]byte, entry.Length())
+		}
+		buff = buff[:entry.Length()]
+		if _, err := io.ReadFull(br, buff); err != nil {
+			return fmt.Errorf("chunk %s: failed to read chunk data: %w", a.String(), err)
+		}
+		offset += uint64(entry.Length())
+
+		cc, err := NewCompressedChunk(hash.Hash(a), buff)
+		if err != nil {
+			return fmt.Errorf("chunk %s: %w", a.String(), err)
+		}
+		ch, err := cc.ToChunk()
+		if err != nil {
+			return fmt.Errorf("chunk %s: failed to decompress chunk data: %w", a.String(), err)
+		}
+		if h := hash.Of(ch.Data()); h != hash.Hash(a) {
+			return fmt.Errorf("chunk %s: data hashes to %s", a.String(), h.String())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNBSValidateTableFiles(t *testing.T) {
+	ctx := context.Background()
+	st, _, q := makeTestLocalStore(t, defaultMaxTables)
+	defer func() {
+		require.NoError(t, st.Close())
+		require.Equal(t, uint64(0), q.Usage())
+	}()
+	fileToData := populateLocalStore(t, st, 4)
+
+	validated := make(map[string]int)
+	err := st.ValidateTableFiles(ctx, func(fileID string, numChunks int, err error) error {
+		assert.NoError(t, err)
+		validated[fileID] = numChunks
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, validated, len(fileToData))
+	for fileID := range fileToData {
+		assert.Contains(t, validated, fileID)
+	}
+}
+
+func TestValidateChunkSource(t *testing.T) {
+	ctx := context.Background()
+	chunks := [][]byte{
+		[]byte("hello2"),
+		[]byte("goodbye2"),
+		[]byte("badbye2"),
+	}
+
+	tableData, name, err := buildTable(chunks)
+	require.NoError(t, err)
+	cs, err := newReaderFromIndexData(ctx, &UnlimitedQuotaProvider{}, tableData, name, tableReaderAtFromBytes(tableData), fileBlockSize)
+	require.NoError(t, err)
+	defer cs.close()
+	require.NoError(t, validateChunkSource(ctx, cs))
+
+	// the first chunk is at the start of the table file
+	tableData[0] ^= 0xff
+	err = validateChunkSource(ctx, cs)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum error")
+}
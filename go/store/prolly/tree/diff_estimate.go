@@ -0,0 +1,288 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"bytes"
+	"context"
+	"math"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// DefaultDiffEstimateBudget is the default number of nodes EstimateDiff will read from each tree before it falls back
+// to estimating from subtree counts.
+const DefaultDiffEstimateBudget = 1024
+
+// DiffEstimate is the number of keys added, removed and modified between two trees. If Exact is false, the counts
+// are estimates.
+type DiffEstimate struct {
+	Added, Removed, Modified uint64
+	Exact                    bool
+}
+
+// EstimateDiff counts the keys added, removed and modified between |from| and |to| without visiting every changed
+// key. It descends both trees one level at a time, dropping every subtree that appears in both, since chunk
+// boundaries are content defined and unchanged regions of the trees share nodes. If the subtrees left to compare
+// reach the leaves within |budget| nodes per tree, the counts are exact. Otherwise, the counts are estimated from the
+// cardinality of the subtrees that differ, and from how quickly the number of differing subtrees grows at each level.
+//
+// Keys are compared as bytes, so the trees must share the same key encoding.
+func EstimateDiff(ctx context.Context, fromNs, toNs NodeStore, from, to Node, budget int) (DiffEstimate, error) {
+	if from.HashOf() == to.HashOf() {
+		return DiffEstimate{Exact: true}, nil
+	}
+	if from.empty() || to.empty() {
+		fc, err := treeCount(from)
+		if err != nil {
+			return DiffEstimate{}, err
+		}
+		tc, err := treeCount(to)
+		if err != nil {
+			return DiffEstimate{}, err
+		}
+		return DiffEstimate{Added: tc, Removed: fc, Exact: true}, nil
+	}
+
+	fromFront, toFront := []Node{from}, []Node{to}
+	var err error
+	for fromFront[0].Level() > toFront[0].Level() {
+		fromFront, err = readChildren(ctx, fromNs, fromFront)
+		if err != nil {
+			return DiffEstimate{}, err
+		}
+	}
+	for toFront[0].Level() > fromFront[0].Level() {
+		toFront, err = readChildren(ctx, toNs, toFront)
+		if err != nil {
+			return DiffEstimate{}, err
+		}
+	}
+
+	for {
+		fromFront, toFront = dropSharedNodes(fromFront, toFront)
+		if len(fromFront) == 0 || len(toFront) == 0 || fromFront[0].IsLeaf() {
+			return diffFronts(fromFront, toFront)
+		}
+
+		fromKids, err := childRefs(fromFront)
+		if err != nil {
+			return DiffEstimate{}, err
+		}
+		toKids, err := childRefs(toFront)
+		if err != nil {
+			return DiffEstimate{}, err
+		}
+		fromKids, toKids = dropSharedRefs(fromKids, toKids)
+
+		if len(fromKids) > budget || len(toKids) > budget {
+			growth := float64(minInt(len(fromKids), len(toKids))) / float64(minInt(len(fromFront), len(toFront)))
+			return estimateFromRefs(fromKids, toKids, growth, fromFront[0].Level()), nil
+		}
+
+		fromFront, err = readRefs(ctx, fromNs, fromKids)
+		if err != nil {
+			return DiffEstimate{}, err
+		}
+		toFront, err = readRefs(ctx, toNs, toKids)
+		if err != nil {
+			return DiffEstimate{}, err
+		}
+	}
+}
+
+type subtreeRef struct {
+	addr  hash.Hash
+	count uint64
+}
+
+func treeCount(nd Node) (uint64, error) {
+	if nd.empty() {
+		return 0, nil
+	}
+	c, err := nd.TreeCount()
+	return uint64(c), err
+}
+
+// childRefs returns the addresses and cardinalities of the children of the internal nodes given.
+func childRefs(nodes []Node) ([]subtreeRef, error) {
+	var refs []subtreeRef
+	for _, nd := range nodes {
+		nd, err := nd.loadSubtrees()
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < nd.Count(); i++ {
+			c, err := nd.getSubtreeCount(i)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, subtreeRef{addr: nd.getAddress(i), count: c})
+		}
+	}
+	return refs, nil
+}
+
+func readChildren(ctx context.Context, ns NodeStore, nodes []Node) ([]Node, error) {
+	refs, err := childRefs(nodes)
+	if err != nil {
+		return nil, err
+	}
+	return readRefs(ctx, ns, refs)
+}
+
+func readRefs(ctx context.Context, ns NodeStore, refs []subtreeRef) ([]Node, error) {
+	addrs := make(hash.HashSlice, len(refs))
+	for i, r := range refs {
+		addrs[i] = r.addr
+	}
+	return ns.ReadMany(ctx, addrs)
+}
+
+func dropSharedNodes(from, to []Node) ([]Node, []Node) {
+	fromAddrs := make(hash.HashSet, len(from))
+	for _, nd := range from {
+		fromAddrs.Insert(nd.HashOf())
+	}
+	shared := make(hash.HashSet)
+	for _, nd := range to {
+		if fromAddrs.Has(nd.HashOf()) {
+			shared.Insert(nd.HashOf())
+		}
+	}
+
+	keep := func(nodes []Node) []Node {
+		var res []Node
+		for _, nd := range nodes {
+			if !shared.Has(nd.HashOf()) {
+				res = append(res, nd)
+			}
+		}
+		return res
+	}
+	return keep(from), keep(to)
+}
+
+func dropSharedRefs(from, to []subtreeRef) ([]subtreeRef, []subtreeRef) {
+	fromAddrs := make(hash.HashSet, len(from))
+	for _, r := range from {
+		fromAddrs.Insert(r.addr)
+	}
+	shared := make(hash.HashSet)
+	for _, r := range to {
+		if fromAddrs.Has(r.addr) {
+			shared.Insert(r.addr)
+		}
+	}
+
+	keep := func(refs []subtreeRef) []subtreeRef {
+		var res []subtreeRef
+		for _, r := range refs {
+			if !shared.Has(r.addr) {
+				res = append(res, r)
+			}
+		}
+		return res
+	}
+	return keep(from), keep(to)
+}
+
+// diffFronts exactly diffs the keys of the nodes given, which are all leaves, or are only on one side of the diff.
+func diffFronts(from, to []Node) (DiffEstimate, error) {
+	if len(from) == 0 || len(to) == 0 {
+		var est DiffEstimate
+		for _, nd := range from {
+			c, err := treeCount(nd)
+			if err != nil {
+				return DiffEstimate{}, err
+			}
+			est.Removed += c
+		}
+		for _, nd := range to {
+			c, err := treeCount(nd)
+			if err != nil {
+				return DiffEstimate{}, err
+			}
+			est.Added += c
+		}
+		est.Exact = true
+		return est, nil
+	}
+
+	fromVals := make(map[string]Item)
+	for _, nd := range from {
+		for i := 0; i < nd.Count(); i++ {
+			fromVals[string(nd.GetKey(i))] = nd.GetValue(i)
+		}
+	}
+
+	est := DiffEstimate{Exact: true}
+	for _, nd := range to {
+		for i := 0; i < nd.Count(); i++ {
+			k := string(nd.GetKey(i))
+			v, ok := fromVals[k]
+			if !ok {
+				est.Added++
+				continue
+			}
+			if !bytes.Equal(v, nd.GetValue(i)) {
+				est.Modified++
+			}
+			delete(fromVals, k)
+		}
+	}
+	est.Removed = uint64(len(fromVals))
+	return est, nil
+}
+
+// estimateFromRefs estimates the diff between two sets of differing subtrees at |level| - 1. The difference in their
+// cardinality is counted as added or removed keys. Each differing subtree holds at least one modified key, and the
+// number of differing subtrees is assumed to keep growing by |growth| at each level below, which is close to 1 when
+// changes are sparse and close to the tree's fanout when they're dense.
+func estimateFromRefs(from, to []subtreeRef, growth float64, level int) DiffEstimate {
+	var fc, tc uint64
+	for _, r := range from {
+		fc += r.count
+	}
+	for _, r := range to {
+		tc += r.count
+	}
+
+	var est DiffEstimate
+	if tc > fc {
+		est.Added = tc - fc
+	} else {
+		est.Removed = fc - tc
+	}
+
+	shared := fc
+	if tc < shared {
+		shared = tc
+	}
+	modified := float64(minInt(len(from), len(to))) * math.Pow(math.Max(growth, 1), float64(level))
+	if modified > float64(shared) {
+		est.Modified = shared
+	} else {
+		est.Modified = uint64(modified)
+	}
+	return est
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
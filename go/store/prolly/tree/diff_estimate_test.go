@@ -0,0 +1,85 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+func TestEstimateDiff(t *testing.T) {
+	ctx := context.Background()
+	ns := NewTestNodeStore()
+	desc := val.NewTupleDescriptor(val.Type{Enc: val.Int64Enc, Nullable: true})
+
+	const count = 10_000
+	var fromRows, toRows [][]int
+	for i := 0; i < count; i++ {
+		fromRows = append(fromRows, []int{i, i})
+		switch {
+		case i%2000 == 1:
+			// removed
+		case i%2000 == 2:
+			toRows = append(toRows, []int{i, -i})
+		default:
+			toRows = append(toRows, []int{i, i})
+		}
+	}
+	for i := count; i < count+7; i++ {
+		toRows = append(toRows, []int{i, i})
+	}
+
+	from := newTestMap(t, ctx, fromRows, ns, desc)
+	to := newTestMap(t, ctx, toRows, ns, desc)
+	require.Greater(t, from.Root.Level(), 0)
+
+	t.Run("identical trees", func(t *testing.T) {
+		est, err := EstimateDiff(ctx, ns, ns, from.Root, from.Root, DefaultDiffEstimateBudget)
+		require.NoError(t, err)
+		assert.Equal(t, DiffEstimate{Exact: true}, est)
+	})
+
+	t.Run("within budget", func(t *testing.T) {
+		est, err := EstimateDiff(ctx, ns, ns, from.Root, to.Root, DefaultDiffEstimateBudget)
+		require.NoError(t, err)
+		assert.Equal(t, DiffEstimate{Added: 7, Removed: 5, Modified: 5, Exact: true}, est)
+
+		est, err = EstimateDiff(ctx, ns, ns, to.Root, from.Root, DefaultDiffEstimateBudget)
+		require.NoError(t, err)
+		assert.Equal(t, DiffEstimate{Added: 5, Removed: 7, Modified: 5, Exact: true}, est)
+	})
+
+	t.Run("over budget", func(t *testing.T) {
+		est, err := EstimateDiff(ctx, ns, ns, from.Root, to.Root, 1)
+		require.NoError(t, err)
+		assert.False(t, est.Exact)
+		// the net change in cardinality is exact
+		assert.Equal(t, uint64(2), est.Added-est.Removed)
+		assert.Greater(t, est.Modified, uint64(0))
+		assert.LessOrEqual(t, est.Modified, uint64(count))
+	})
+
+	t.Run("empty tree", func(t *testing.T) {
+		empty := newTestMap(t, ctx, nil, ns, desc)
+		est, err := EstimateDiff(ctx, ns, ns, empty.Root, to.Root, DefaultDiffEstimateBudget)
+		require.NoError(t, err)
+		assert.Equal(t, DiffEstimate{Added: uint64(len(toRows)), Exact: true}, est)
+	})
+}
@@ -84,6 +84,8 @@ type ValueStore struct {
 	gcState    gcState
 	gcOut      int
 	gcNewAddrs hash.HashSet
+	gcPins     map[uint64]hash.HashSet
+	gcNextPin  uint64
 }
 
 type gcState int
@@ -155,6 +157,7 @@ func newValueStoreWithCacheAndPending(cs chunks.ChunkStore, cacheSize, pendingMa
 		decodedChunks: sizecache.New(cacheSize),
 		versOnce:      sync.Once{},
 		gcNewAddrs:    make(hash.HashSet),
+		gcPins:        make(map[uint64]hash.HashSet),
 	}
 	vs.gcCond = sync.NewCond(&vs.gcMu)
 	return vs
@@ -405,15 +408,64 @@ func (lvs *ValueStore) waitForNotFinalizingGC(ctx context.Context) (func(), erro
 }
 
 // Call without lvs.gcMu held. Puts the ValueStore into its initial GC state,
-// where it is collecting OldGen. gcAddChunk begins accumulating new gen addrs.
+// where it is collecting OldGen. gcAddChunk begins accumulating new gen addrs,
+// starting with the pinned roots, so that they're kept by the GC.
 func (lvs *ValueStore) transitionToOldGenGC() {
 	lvs.gcMu.Lock()
 	defer lvs.gcMu.Unlock()
 	lvs.waitForNoGC()
 	lvs.gcState = gcState_OldGen
+	for _, roots := range lvs.gcPins {
+		lvs.gcNewAddrs.InsertAll(roots)
+	}
 	lvs.gcCond.Broadcast()
 }
 
+// PinGCRoots keeps the chunks reachable from |roots| through every GC that
+// runs until the returned unpin function is called. This is the safepoint
+// protocol for readers that hold on to roots which may stop being reachable
+// from the store's root while they're in use, such as sessions and background
+// jobs: a GC that is already running adds |roots| to the chunks it keeps, and
+// every later GC starts with them. While a GC is finalizing, this blocks until
+// it's done, since it can no longer keep new roots.
+//
+// |roots| must be reachable, or pinned by another caller, when they're pinned.
+func (lvs *ValueStore) PinGCRoots(ctx context.Context, roots hash.HashSet) (func(), error) {
+	lvs.gcMu.Lock()
+	defer lvs.gcMu.Unlock()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			lvs.gcCond.Broadcast()
+		case <-stop:
+		}
+	}()
+	for lvs.gcState == gcState_Finalizing && ctx.Err() == nil {
+		lvs.gcCond.Wait()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if lvs.gcState != gcState_NoGC {
+		lvs.gcNewAddrs.InsertAll(roots)
+	}
+	id := lvs.gcNextPin
+	lvs.gcNextPin++
+	lvs.gcPins[id] = roots.Copy()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			lvs.gcMu.Lock()
+			defer lvs.gcMu.Unlock()
+			delete(lvs.gcPins, id)
+		})
+	}, nil
+}
+
 // Call without lvs.gcMu held. Puts the ValueStore into the second in-progress
 // GC state, where we are copying newgen to newgen. Returns all the novel
 // addresses which were collected by lvs.gcAddChunk while we were collecting
@@ -702,6 +754,31 @@ func (lvs *ValueStore) gcProcessRefs(ctx context.Context,
 	finalize func() hash.HashSet) error {
 	visited := make(hash.HashSet)
 
+	// commits missing from a shallow clone are referenced, but were never fetched, so they can't be kept
+	missing := hash.NewHashSet()
+	if scs, ok := lvs.cs.(chunks.ShallowChunkStore); ok {
+		var err error
+		missing, err = scs.MissingCommits(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	// and so are the values missing from a sparse clone, as long as they haven't been fetched since
+	if scs, ok := lvs.cs.(chunks.SparseChunkStore); ok {
+		sparse, err := scs.SparseChunks(ctx)
+		if err != nil {
+			return err
+		}
+		if sparse.Size() > 0 {
+			unfetched, err := lvs.cs.HasMany(ctx, sparse)
+			if err != nil {
+				return err
+			}
+			missing = missing.Copy()
+			missing.InsertAll(unfetched)
+		}
+	}
+
 	process := func(initialToVisit hash.HashSet) error {
 		visited.InsertAll(initialToVisit)
 		toVisitCount := len(initialToVisit)
@@ -735,6 +812,11 @@ func (lvs *ValueStore) gcProcessRefs(ctx context.Context,
 				if err != nil {
 					return err
 				}
+				for h := range hashes {
+					if missing.Has(h) {
+						hashes.Remove(h)
+					}
+				}
 
 				toVisit[i] = hashes
 				toVisitCount += len(hashes)
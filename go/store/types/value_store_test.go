@@ -204,6 +204,44 @@ func TestGC(t *testing.T) {
 	assert.Nil(v2)
 }
 
+func TestGCKeepsPinnedRoots(t *testing.T) {
+	ctx := context.Background()
+	vs := newTestValueStore()
+	r1 := mustRef(vs.WriteValue(ctx, String("committed")))
+	r2 := mustRef(vs.WriteValue(ctx, String("pinned")))
+	h1 := mustRef(vs.WriteValue(ctx, mustSet(NewSet(ctx, vs, r1)))).TargetHash()
+	h2 := mustRef(vs.WriteValue(ctx, mustSet(NewSet(ctx, vs, r2)))).TargetHash()
+
+	rt, err := vs.Root(ctx)
+	require.NoError(t, err)
+	ok, err := vs.Commit(ctx, h1, rt)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	unpin, err := vs.PinGCRoots(ctx, hash.NewHashSet(h2))
+	require.NoError(t, err)
+
+	// the pinned set and the value it references survive GC, even though they aren't reachable from the root
+	require.NoError(t, vs.GC(ctx, hash.HashSet{}, hash.HashSet{}, nil))
+	v2, err := vs.ReadValue(ctx, h2)
+	require.NoError(t, err)
+	assert.NotNil(t, v2)
+	v, err := vs.ReadValue(ctx, r2.TargetHash())
+	require.NoError(t, err)
+	assert.NotNil(t, v)
+
+	// once unpinned, they're collected
+	unpin()
+	unpin()
+	require.NoError(t, vs.GC(ctx, hash.HashSet{}, hash.HashSet{}, nil))
+	v2, err = vs.ReadValue(ctx, h2)
+	require.NoError(t, err)
+	assert.Nil(t, v2)
+	v1, err := vs.ReadValue(ctx, h1)
+	require.NoError(t, err)
+	assert.NotNil(t, v1)
+}
+
 type badVersionStore struct {
 	chunks.ChunkStore
 }
@@ -19,8 +19,10 @@ import (
 	"path/filepath"
 	"sync"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotesrv"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/nbs"
 )
 
@@ -79,3 +81,29 @@ type SingletonCSCache struct {
 func (cache SingletonCSCache) Get(path, nbfVerStr string) (remotesrv.RemoteSrvStore, error) {
 	return cache.s, nil
 }
+
+// ProtectedTagsCSCache wraps the stores returned by another DBCache so that pushes which would delete or move a tag
+// matching one of |patterns| are rejected.
+type ProtectedTagsCSCache struct {
+	cache    remotesrv.DBCache
+	patterns []string
+}
+
+func (cache ProtectedTagsCSCache) Get(path, nbfVerStr string) (remotesrv.RemoteSrvStore, error) {
+	cs, err := cache.cache.Get(path, nbfVerStr)
+	if err != nil {
+		return nil, err
+	}
+	return protectedTagsStore{cs, cache.patterns}, nil
+}
+
+type protectedTagsStore struct {
+	remotesrv.RemoteSrvStore
+	patterns []string
+}
+
+var _ remotesrv.CommitValidator = protectedTagsStore{}
+
+func (s protectedTagsStore) ValidateCommit(ctx context.Context, last, current hash.Hash) error {
+	return doltdb.DoltDBFromCS(s.RemoteSrvStore).CheckProtectedTags(ctx, s.patterns, last, current)
+}
@@ -36,6 +36,7 @@ func main() {
 	grpcPortParam := flag.Int("grpc-port", -1, "the port the grpc server will listen on; default 50051")
 	httpPortParam := flag.Int("http-port", -1, "the port the http server will listen on; default 80; if http-port is equal to grpc-port, both services will serve over the same port")
 	httpHostParam := flag.String("http-host", "", "hostname to use in the host component of the URLs that the server generates; default ''; if '', server will echo the :authority header")
+	protectedTagsParam := flag.String("protected-tags", "", "comma separated list of tag name patterns, e.g. 'v*,release/*'; pushes may create matching tags but not delete or move them")
 	flag.Parse()
 
 	if dirParam != nil && len(*dirParam) > 0 {
@@ -81,6 +82,11 @@ func main() {
 		dbCache = NewLocalCSCache(fs)
 	}
 
+	if patterns := doltdb.ParseTagPatterns(*protectedTagsParam); len(patterns) > 0 {
+		log.Println("protecting tags matching", patterns)
+		dbCache = ProtectedTagsCSCache{dbCache, patterns}
+	}
+
 	server, err := remotesrv.NewServer(remotesrv.ServerArgs{
 		HttpHost:       *httpHostParam,
 		HttpListenAddr: fmt.Sprintf(":%d", *httpPortParam),
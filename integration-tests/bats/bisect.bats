@@ -0,0 +1,115 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE t(pk BIGINT PRIMARY KEY, v BIGINT)"
+    dolt commit -Am "Created table"
+    for i in 1 2 3 4 5 6 7 8; do
+        if [ "$i" -eq 5 ]; then
+            dolt sql -q "INSERT INTO t VALUES ($i, -$i)"
+        else
+            dolt sql -q "INSERT INTO t VALUES ($i, $i)"
+        fi
+        dolt commit -am "Inserted $i"
+    done
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+get_hash_of_commit() {
+    dolt sql -q "SELECT commit_hash FROM dolt_log WHERE message = '$1'" -r csv | tail -n 1
+}
+
+@test "bisect: mark commits good and bad" {
+    run dolt bisect start
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "waiting for both good and bad commits" ]] || false
+
+    run dolt bisect bad HEAD
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "waiting for good commit(s), bad commit known" ]] || false
+
+    run dolt bisect good HEAD~8
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Bisecting: 3 revisions left to test after this (roughly 2 steps)" ]] || false
+    [[ "$output" =~ "Inserted 4" ]] || false
+
+    run dolt bisect status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Inserted 4" ]] || false
+
+    run dolt bisect good
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Inserted 6" ]] || false
+
+    run dolt bisect bad
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Inserted 5" ]] || false
+
+    hash=$(get_hash_of_commit "Inserted 5")
+    run dolt bisect bad
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "$hash is the first bad commit" ]] || false
+    [[ "$output" =~ "Inserted 5" ]] || false
+
+    run dolt bisect reset
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "ended the bisect session on branch 'main'" ]] || false
+
+    run dolt bisect status
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "no bisect session is in progress on branch 'main'" ]] || false
+}
+
+@test "bisect: the commit to test can be queried as a revision database" {
+    dolt bisect start HEAD HEAD~8
+    hash=$(get_hash_of_commit "Inserted 4")
+
+    run dolt sql -r csv << SQL
+use dolt_repo_$$/$hash;
+select count(*) from t;
+SQL
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Database changed" ]] || false
+    [[ "$output" =~ "count(*)"$'\n'"4" ]] || false
+}
+
+@test "bisect: run a query at each step" {
+    dolt bisect start HEAD HEAD~8
+
+    hash=$(get_hash_of_commit "Inserted 5")
+    run dolt bisect run "SELECT count(*) = 0 FROM t WHERE v < 0"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "good" ]] || false
+    [[ "$output" =~ "bad" ]] || false
+    [[ "$output" =~ "$hash is the first bad commit" ]] || false
+
+    # the session is left on the branch, not the last commit tested
+    run dolt sql -q "SELECT count(*) FROM t" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "8" ]] || false
+}
+
+@test "bisect: run skips commits when the query returns NULL" {
+    dolt bisect start HEAD HEAD~8
+
+    run dolt bisect run "SELECT IF((SELECT max(pk) FROM t) = 4, NULL, (SELECT count(*) = 0 FROM t WHERE v < 0))"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "skip" ]] || false
+    [[ "$output" =~ "There are only skipped commits left to test" ]] || false
+    [[ "$output" =~ $(get_hash_of_commit "Inserted 4") ]] || false
+    [[ "$output" =~ $(get_hash_of_commit "Inserted 5") ]] || false
+}
+
+@test "bisect: run requires a good and a bad commit" {
+    dolt bisect start
+
+    run dolt bisect run "SELECT 1"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "waiting for both good and bad commits" ]] || false
+}
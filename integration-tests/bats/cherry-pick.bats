@@ -322,6 +322,89 @@ teardown() {
     dolt commit -m "committing cherry-picked change"
 }
 
+@test "cherry-pick: range of commits" {
+    dolt checkout main
+
+    run dolt cherry-pick main..branch1
+    [ "$status" -eq "0" ]
+
+    run dolt sql -q "SELECT * FROM test" -r csv
+    [[ "$output" =~ "1,a" ]] || false
+    [[ "$output" =~ "2,b" ]] || false
+    [[ "$output" =~ "3,c" ]] || false
+
+    run dolt log --oneline -n 3
+    [[ "${lines[0]}" =~ "Inserted 3" ]] || false
+    [[ "${lines[1]}" =~ "Inserted 2" ]] || false
+    [[ "${lines[2]}" =~ "Inserted 1" ]] || false
+
+    dolt reset --hard HEAD~3
+    run dolt cherry-pick branch1~2 branch1
+    [ "$status" -eq "0" ]
+
+    run dolt sql -q "SELECT * FROM test" -r csv
+    [[ "$output" =~ "1,a" ]] || false
+    [[ ! "$output" =~ "2,b" ]] || false
+    [[ "$output" =~ "3,c" ]] || false
+}
+
+@test "cherry-pick: continue, skip and abort a cherry-pick of several commits" {
+    dolt sql -q "UPDATE test SET v = 'x' WHERE pk = 1"
+    dolt commit -am "Updated 1"
+    dolt sql -q "INSERT INTO test VALUES (4, 'd')"
+    dolt commit -am "Inserted 4"
+
+    dolt checkout main
+    dolt sql -q "INSERT INTO test VALUES (1, 'z')"
+    dolt commit -am "Inserted 1z"
+
+    run dolt cherry-pick main..branch1
+    [ $status -eq 1 ]
+    [[ $output =~ "Unable to apply commit cleanly due to conflicts or constraint violations" ]] || false
+    [[ $output =~ "dolt cherry-pick --continue" ]] || false
+
+    run dolt cherry-pick branch1
+    [ $status -eq 1 ]
+
+    # keeping our row leaves nothing to commit for "Inserted 1", and "Updated 1" conflicts with it
+    dolt conflicts resolve --ours test
+    dolt add test
+    run dolt cherry-pick --continue
+    [ $status -eq 1 ]
+    [[ $output =~ "Unable to apply commit cleanly due to conflicts or constraint violations" ]] || false
+
+    run dolt cherry-pick --skip
+    [ $status -eq 0 ]
+    [[ $output =~ "Inserted 4" ]] || false
+
+    run dolt sql -q "SELECT * FROM test" -r csv
+    [[ "$output" =~ "1,z" ]] || false
+    [[ "$output" =~ "2,b" ]] || false
+    [[ "$output" =~ "3,c" ]] || false
+    [[ "$output" =~ "4,d" ]] || false
+
+    run dolt log --oneline -n 4
+    [[ "${lines[0]}" =~ "Inserted 4" ]] || false
+    [[ "${lines[1]}" =~ "Inserted 3" ]] || false
+    [[ "${lines[2]}" =~ "Inserted 2" ]] || false
+    [[ "${lines[3]}" =~ "Inserted 1z" ]] || false
+
+    dolt reset --hard HEAD~3
+    run dolt cherry-pick main..branch1
+    [ $status -eq 1 ]
+
+    dolt cherry-pick --abort
+    run dolt sql -q "SELECT * FROM test" -r csv
+    [[ "$output" =~ "1,z" ]] || false
+    [[ ! "$output" =~ "2,b" ]] || false
+    run dolt status
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    run dolt cherry-pick --continue
+    [ $status -eq 1 ]
+    [[ $output =~ "no cherry-pick is in progress" ]] || false
+}
+
 @test "cherry-pick: commit with CREATE TABLE" {
     dolt sql -q "CREATE TABLE table_a (pk BIGINT PRIMARY KEY, v varchar(10))"
     dolt add .
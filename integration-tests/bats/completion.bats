@@ -0,0 +1,51 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "completion: bash" {
+    run dolt completion bash
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "'dolt schema') echo '" ]] || false
+    [[ "$output" =~ "complete -o default -F _dolt dolt" ]] || false
+
+    source <(dolt completion bash)
+    COMP_WORDS=(dolt sch)
+    COMP_CWORD=1
+    _dolt
+    [ "${COMPREPLY[*]}" = "schema" ]
+}
+
+@test "completion: fish and powershell" {
+    run dolt completion fish
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "complete -c dolt -n '__dolt_path_is \"dolt\"' -f -a 'status'" ]] || false
+
+    run dolt completion powershell
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Register-ArgumentCompleter -Native -CommandName dolt" ]] || false
+}
+
+@test "completion: branch and table names" {
+    dolt sql -q "create table t1 (pk int primary key)"
+    dolt branch feature
+
+    run dolt completion __complete branch table
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "feature" ]] || false
+    [[ "$output" =~ "main" ]] || false
+    [[ "$output" =~ "t1" ]] || false
+
+    cd ..
+    mkdir not-a-repo-$$ && cd not-a-repo-$$
+    run dolt completion __complete branch table
+    [ "$status" -eq 0 ]
+    [ "$output" = "" ]
+}
@@ -231,3 +231,158 @@ teardown() {
     [ $status -eq 0 ]
     [[ $output =~ "main" ]] || false
 }
+
+@test "conflicts-resolve: interactive, can't be used with ours or theirs" {
+    run dolt conflicts resolve --interactive --ours t
+    [ $status -eq 1 ]
+    [[ $output =~ "--interactive can't be used with --ours, --theirs or --cell-merge" ]] || false
+}
+
+@test "conflicts-resolve: interactive, resolve table with theirs" {
+    basic_conflict
+
+    run dolt merge other
+    [ $status -eq 0 ]
+    [[ $output =~ "Automatic merge failed" ]] || false
+
+    run bash -c "printf 't\ny\n' | dolt conflicts resolve --interactive t"
+    [ $status -eq 0 ]
+    [[ $output =~ "Table t has 1 conflict(s)." ]] || false
+    [[ $output =~ "Resolved 1 conflict(s)." ]] || false
+
+    run dolt sql -q "select * from t" -r csv
+    [ $status -eq 0 ]
+    [[ $output =~ "1,other" ]] || false
+
+    run dolt sql -q "select count(*) from dolt_conflicts_t" -r csv
+    [ $status -eq 0 ]
+    [[ $output =~ "0" ]] || false
+}
+
+@test "conflicts-resolve: interactive, review rows and pick cells" {
+    dolt sql -q "create table t (pk int primary key, a int, b varchar(20))"
+    dolt sql -q "insert into t values (1, 1, 'one'), (2, 2, 'two'), (3, 3, 'three')"
+    dolt commit -Am "init commit"
+    dolt checkout -b other
+    dolt sql -q "update t set a = 10, b = 'uno' where pk = 1"
+    dolt sql -q "update t set a = 20 where pk = 2"
+    dolt sql -q "delete from t where pk = 3"
+    dolt commit -am "other commit"
+    dolt checkout main
+    dolt sql -q "update t set a = 11, b = 'ein' where pk = 1"
+    dolt sql -q "update t set a = 21 where pk = 2"
+    dolt sql -q "update t set a = 31 where pk = 3"
+    dolt commit -am "main commit"
+
+    run dolt merge other
+    [ $status -eq 0 ]
+    [[ $output =~ "Automatic merge failed" ]] || false
+
+    # pick their a and our b for the first row, keep ours for the second, and take their delete of the third
+    run bash -c "printf 'r\nc\nt\no\no\nt\ny\n' | dolt conflicts resolve -i t"
+    [ $status -eq 0 ]
+    [[ $output =~ "Conflict 3 of 3 in t:" ]] || false
+    [[ $output =~ "Resolved 3 conflict(s)." ]] || false
+
+    run dolt sql -q "select * from t order by pk" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" = "pk,a,b
+1,10,ein
+2,21,two" ]] || false
+
+    run dolt sql -q "select count(*) from dolt_conflicts_t" -r csv
+    [ $status -eq 0 ]
+    [[ $output =~ "0" ]] || false
+
+    dolt commit -am "merge other"
+}
+
+@test "conflicts-resolve: interactive, skipped rows stay conflicted" {
+    basic_conflict
+    dolt checkout other
+    dolt sql -q "insert into t values (2, 'other')"
+    dolt commit -am "other commit 2"
+    dolt checkout main
+    dolt sql -q "insert into t values (2, 'main')"
+    dolt commit -am "main commit 2"
+
+    run dolt merge other
+    [ $status -eq 0 ]
+    [[ $output =~ "Automatic merge failed" ]] || false
+
+    run bash -c "printf 'r\nt\ns\ny\n' | dolt conflicts resolve -i t"
+    [ $status -eq 0 ]
+    [[ $output =~ "Resolved 1 conflict(s)." ]] || false
+
+    run dolt sql -q "select our_i, our_t, their_t from dolt_conflicts_t" -r csv
+    [ $status -eq 0 ]
+    [[ $output =~ "2,main,other" ]] || false
+    [ "${#lines[@]}" -eq 2 ]
+
+    run dolt sql -q "select * from t order by i" -r csv
+    [ $status -eq 0 ]
+    [[ $output =~ "1,other" ]] || false
+    [[ $output =~ "2,main" ]] || false
+}
+
+@test "conflicts-resolve: interactive, nothing is saved unless confirmed" {
+    basic_conflict
+
+    run dolt merge other
+    [ $status -eq 0 ]
+    [[ $output =~ "Automatic merge failed" ]] || false
+
+    run bash -c "printf 't\nn\n' | dolt conflicts resolve -i t"
+    [ $status -eq 0 ]
+    [[ $output =~ "No conflicts were resolved." ]] || false
+
+    # input ends before the resolution is saved
+    run bash -c "printf 'r\n' | dolt conflicts resolve -i t"
+    [ $status -eq 0 ]
+    [[ $output =~ "No conflicts were resolved." ]] || false
+
+    run dolt sql -q "select count(*) from dolt_conflicts_t" -r csv
+    [ $status -eq 0 ]
+    [[ $output =~ "1" ]] || false
+
+    run dolt sql -q "select * from t" -r csv
+    [ $status -eq 0 ]
+    [[ $output =~ "1,main" ]] || false
+}
+
+@test "conflicts-resolve: cell-merge resolves conflicts without colliding columns" {
+    dolt sql -q "create table t (pk int primary key, a int, b int)"
+    dolt sql -q "insert into t values (1, 1, 1), (2, 2, 2)"
+    dolt commit -Am "init commit"
+    dolt checkout -b other
+    dolt sql -q "update t set a = 10, b = 10"
+    dolt commit -am "other commit"
+    dolt checkout main
+    dolt sql -q "update t set a = 11"
+    dolt commit -am "main commit"
+
+    run dolt merge other
+    [ $status -eq 0 ]
+    [[ $output =~ "Automatic merge failed" ]] || false
+
+    # only their change to column a is left in the conflict of row 1
+    dolt sql -q "set @@dolt_allow_commit_conflicts = 1; update t set a = 1 where pk = 1"
+
+    run dolt conflicts resolve --cell-merge --theirs t
+    [ $status -eq 1 ]
+    [[ $output =~ "specify only one from" ]] || false
+
+    run dolt conflicts resolve --cell-merge t
+    [ $status -eq 0 ]
+
+    run dolt sql -q "select * from t order by pk" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" = "pk,a,b
+1,10,10
+2,11,2" ]] || false
+
+    run dolt sql -q "select our_pk, our_a, their_a from dolt_conflicts_t" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" = "our_pk,our_a,their_a
+2,11,10" ]] || false
+}
@@ -127,3 +127,64 @@ teardown() {
     [ "$status" -eq 1 ]
     [[ "$output" =~ "not found" ]] || false
 }
+
+@test "cp-and-mv: cp table between branches" {
+    dolt add -A && dolt commit -m "added tables"
+    dolt branch feature
+    dolt sql -q "CREATE TABLE test3 (pk int primary key, c1 decimal(10,2), c2 enum('a','b'))"
+    dolt sql -q "INSERT INTO test3 VALUES (1, 1.50, 'b')"
+    dolt add -A && dolt commit -m "added test3"
+
+    run dolt table cp main.test3 feature.test3
+    [ "$status" -eq 0 ]
+
+    # the copy is staged on the destination branch
+    dolt checkout feature
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Changes to be committed" ]] || false
+    [[ "$output" =~ "new table:".*"test3" ]] || false
+
+    run dolt sql -q "SELECT * FROM test3" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,1.50,b" ]] || false
+
+    run dolt sql -q "SHOW CREATE TABLE test3"
+    [[ "$output" =~ "decimal(10,2)" ]] || false
+    [[ "$output" =~ "enum('a','b')" ]] || false
+    dolt commit -m "copied test3"
+
+    # copying over an existing table requires --force
+    dolt checkout main
+    dolt sql -q "INSERT INTO test3 VALUES (2, 2.50, 'a')"
+    run dolt table cp test3 feature.test3
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "already exists" ]] || false
+
+    run dolt table cp -f test3 feature.test3
+    [ "$status" -eq 0 ]
+    run dolt sql -q "SELECT count(*) FROM \`dolt_repo_$$/feature\`.test3" -r csv
+    [[ "$output" =~ "2" ]] || false
+}
+
+@test "cp-and-mv: copy table from a commit with dolt_copy_table" {
+    dolt add -A && dolt commit -m "added tables"
+    dolt tag v1
+    dolt sql -q "DELETE FROM test1"
+    dolt add -A && dolt commit -m "deleted rows"
+    dolt branch feature
+
+    dolt sql -q "CALL dolt_copy_table('v1.test1', 'feature.test1', '--force')"
+
+    run dolt sql -q "SELECT * FROM \`dolt_repo_$$/feature\`.test1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,1" ]] || false
+
+    run dolt sql -q "CALL dolt_copy_table('v1.test1', 'feature.other')"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "different name" ]] || false
+
+    run dolt sql -q "CALL dolt_copy_table('v1.nonexistent', 'feature.nonexistent')"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "not found" ]] || false
+}
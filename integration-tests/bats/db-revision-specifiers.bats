@@ -138,3 +138,21 @@ SQL
     [ "$status" -ne "0" ]
     [[ "$output" =~ "$database_name/$commit is read-only" ]] || false
 }
+
+@test "db-revision-specifiers: results of queries on tags are cached" {
+    dolt sql -q "INSERT INTO test VALUES (4, 'red');"
+
+    run dolt sql -r=csv << SQL
+SET @@GLOBAL.dolt_result_cache_size = 1048576;
+SELECT * FROM \`$database_name/v1\`.test;
+SELECT * FROM \`$database_name/v1\`.test;
+SELECT * FROM test;
+SQL
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "pk,color" ]
+    [ "${lines[1]}" = "1,green" ]
+    [ "${lines[2]}" = "pk,color" ]
+    [ "${lines[3]}" = "1,green" ]
+    [ "${lines[4]}" = "pk,color" ]
+    [ "${lines[5]}" = "4,red" ]
+}
@@ -1068,6 +1068,63 @@ SQL
     [[ $output =~ 'INSERT INTO `t` (`PK`,`c1`) VALUES (0xead543,0x1ee400);' ]] || false
 }
 
+@test "diff: binary data in tabular output is summarized" {
+    dolt sql <<SQL
+DROP TABLE test;
+CREATE TABLE t (pk int PRIMARY KEY, b VARBINARY(100), bl BLOB, txt TEXT);
+INSERT INTO t VALUES (1, 0x0102, 'hello', 'one');
+SQL
+    dolt commit -Am "creating table t"
+    dolt sql -q "UPDATE t SET b = 0x0103, bl = 'hello world' WHERE pk = 1"
+
+    run dolt diff
+    [ $status -eq 0 ]
+    [[ $output =~ "<binary, 2 bytes, sha256 a12871fee210fb86>" ]] || false
+    [[ $output =~ "<binary, 2 bytes, sha256 c79b932e1e1da3c0>" ]] || false
+    [[ $output =~ "<binary, 11 bytes, sha256 b94d27b9934d3e08>" ]] || false
+    [[ $output =~ "one" ]] || false
+    ! [[ $output =~ "hello world" ]] || false
+
+    run dolt diff --binary
+    [ $status -eq 0 ]
+    [[ $output =~ "hello world" ]] || false
+    ! [[ $output =~ "<binary" ]] || false
+
+    run dolt diff -r sql
+    [ $status -eq 0 ]
+    [[ $output =~ 'UPDATE `t` SET `b`=0x0103,`bl`=0x68656c6c6f20776f726c64 WHERE `pk`=1;' ]] || false
+}
+
+@test "diff: external diff drivers diff the cells of their column type" {
+    dolt sql <<SQL
+DROP TABLE test;
+CREATE TABLE t (pk int PRIMARY KEY, bl BLOB, j JSON);
+INSERT INTO t VALUES (1, 'hello', '{"a": 1}'), (2, 'same', '[]');
+SQL
+    dolt commit -Am "creating table t"
+    dolt sql -q "UPDATE t SET bl = 'hello world' WHERE pk = 1; UPDATE t SET j = '[2]' WHERE pk = 2"
+    dolt config --local --add diff.driver.blob "diff"
+
+    run dolt diff
+    [ $status -eq 0 ]
+    [[ $output =~ "diff --driver a/t.bl b/t.bl (pk = 1)" ]] || false
+    [[ $output =~ "< hello" ]] || false
+    [[ $output =~ "> hello world" ]] || false
+    ! [[ $output =~ "(pk = 2)" ]] || false
+    ! [[ $output =~ "a/t.j" ]] || false
+
+    dolt config --local --add diff.driver.json "diff"
+    run dolt diff --diff-mode row
+    [ $status -eq 0 ]
+    [[ $output =~ "diff --driver a/t.bl b/t.bl (pk = 1)" ]] || false
+    [[ $output =~ "diff --driver a/t.j b/t.j (pk = 2)" ]] || false
+    [[ $output =~ "> [2]" ]] || false
+
+    run dolt diff -r sql
+    [ $status -eq 0 ]
+    ! [[ $output =~ "diff --driver" ]] || false
+}
+
 @test "diff: with foreign key and sql output" {
     dolt sql <<SQL
 CREATE TABLE parent (
@@ -1596,6 +1653,58 @@ EOF
     [[ "$output" =~ "$EXPECTED_TABLE" ]] || false
 }
 
+@test "diff: renamed tables" {
+    dolt sql <<SQL
+CREATE TABLE t1 (pk int PRIMARY KEY, col1 int);
+INSERT INTO t1 VALUES (1, 1), (2, 2), (3, 3), (4, 4);
+CREATE TABLE t2 (pk int PRIMARY KEY, col1 int);
+INSERT INTO t2 VALUES (1, 1);
+SQL
+    dolt commit -Am "initial"
+
+    dolt sql <<SQL
+RENAME TABLE t2 TO t2_renamed;
+CREATE TABLE t1_recreated (pk int PRIMARY KEY, col1 int);
+INSERT INTO t1_recreated SELECT * FROM t1;
+DROP TABLE t1;
+UPDATE t1_recreated SET col1 = 100 WHERE pk = 1;
+SQL
+    dolt commit -Am "rename tables"
+
+    run dolt diff HEAD~1 HEAD --summary
+    [ $status -eq 0 ]
+    [[ "$output" =~ "t1 -> t1_recreated" ]] || false
+    [[ "$output" =~ "t2 -> t2_renamed" ]] || false
+
+    run dolt diff HEAD~1 HEAD -r sql
+    [ $status -eq 0 ]
+    [[ "$output" =~ 'RENAME TABLE `t1` TO `t1_recreated`;' ]] || false
+    [[ "$output" =~ 'UPDATE `t1_recreated` SET `col1`=100 WHERE `pk`=1;' ]] || false
+
+    run dolt diff HEAD~1 HEAD --summary --find-renames 80
+    [ $status -eq 0 ]
+    [[ "$output" =~ "t1_recreated" ]] || false
+    [[ ! "$output" =~ "t1 -> t1_recreated" ]] || false
+    [[ "$output" =~ "t2 -> t2_renamed" ]] || false
+
+    run dolt diff HEAD~1 HEAD --summary -M 0
+    [ $status -eq 0 ]
+    [[ ! "$output" =~ "t1 -> t1_recreated" ]] || false
+    [[ "$output" =~ "t2 -> t2_renamed" ]] || false
+
+    run dolt diff HEAD~1 HEAD --summary --no-renames
+    [ $status -eq 0 ]
+    [[ ! "$output" =~ "->" ]] || false
+    [[ "$output" =~ "dropped" ]] || false
+    [[ "$output" =~ "added" ]] || false
+
+    run dolt diff HEAD~1 HEAD --no-renames -M 50
+    [ $status -eq 1 ]
+
+    run dolt diff HEAD~1 HEAD -M 101
+    [ $status -eq 1 ]
+}
+
 # This test was added to prevent short tuples from causing an empty diff.
 @test "diff: add a column, then set and unset its value. Should not show a diff" {
     dolt sql -q "CREATE table t (pk int primary key);"
@@ -152,3 +152,92 @@ TXT
     [[ "$output" =~ "-  0. You just DO WHAT THE FUCK YOU WANT TO"               ]] || false
     [[ "$output" =~ "+  0. You just DO WHAT THE F*CK YOU WANT TO"               ]] || false
 }
+
+@test "docs: docs diff between commits" {
+    echo "first version" > notes.md
+    dolt docs upload README.md notes.md
+    dolt add -A && dolt commit -m "first README"
+    echo "second version" > notes.md
+    dolt docs upload README.md notes.md
+    dolt add -A && dolt commit -m "second README"
+    echo "third version" > notes.md
+    dolt docs upload README.md notes.md
+
+    run dolt docs diff HEAD~1 HEAD README.md
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "-first version" ]] || false
+    [[ "$output" =~ "+second version" ]] || false
+    [[ ! "$output" =~ "third version" ]] || false
+
+    run dolt docs diff HEAD~1 README.md
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "-first version" ]] || false
+    [[ "$output" =~ "+third version" ]] || false
+}
+
+@test "docs: changes to different lines of a doc merge cleanly" {
+    printf "title\n\nfirst\n\nsecond\n" > notes.md
+    dolt docs upload README.md notes.md
+    dolt add -A && dolt commit -m "added README"
+
+    dolt checkout -b other
+    printf "title\n\nfirst, changed on other\n\nsecond\n" > notes.md
+    dolt docs upload README.md notes.md
+    dolt add -A && dolt commit -m "changed first line"
+
+    dolt checkout main
+    printf "title\n\nfirst\n\nsecond, changed on main\n" > notes.md
+    dolt docs upload README.md notes.md
+    dolt add -A && dolt commit -m "changed second line"
+
+    run dolt merge other -m "merge other"
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "CONFLICT" ]] || false
+
+    run dolt docs print README.md
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "first, changed on other" ]] || false
+    [[ "$output" =~ "second, changed on main" ]] || false
+}
+
+@test "docs: conflicting changes to a doc are merged with conflict markers" {
+    printf "title\n\nbody\n" > notes.md
+    dolt docs upload README.md notes.md
+    dolt add -A && dolt commit -m "added README"
+
+    dolt checkout -b other
+    printf "title\n\nbody from other\n" > notes.md
+    dolt docs upload README.md notes.md
+    dolt add -A && dolt commit -m "changed body on other"
+
+    dolt checkout main
+    printf "title\n\nbody from main\n" > notes.md
+    dolt docs upload README.md notes.md
+    dolt add -A && dolt commit -m "changed body on main"
+
+    run dolt merge other -m "merge other"
+    [[ "$output" =~ "CONFLICT" ]] || false
+
+    run dolt docs print README.md
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "title" ]] || false
+    [[ "$output" =~ "<<<<<<< ours" ]] || false
+    [[ "$output" =~ "body from main" ]] || false
+    [[ "$output" =~ "=======" ]] || false
+    [[ "$output" =~ "body from other" ]] || false
+    [[ "$output" =~ ">>>>>>> theirs" ]] || false
+
+    run dolt sql -q "SELECT count(*) FROM dolt_conflicts_dolt_docs" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1" ]] || false
+
+    printf "title\n\nbody from both\n" > notes.md
+    dolt docs upload README.md notes.md
+    dolt conflicts resolve --ours dolt_docs
+    dolt add -A && dolt commit -m "resolved README"
+
+    run dolt docs print README.md
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "body from both" ]] || false
+    [[ ! "$output" =~ "<<<<<<<" ]] || false
+}
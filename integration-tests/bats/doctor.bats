@@ -0,0 +1,58 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+    dolt sql -q "create table test (pk int primary key, c1 int);"
+    dolt sql -q "insert into test values (1, 1), (2, 2);"
+    dolt commit -Am "added test table"
+    dolt branch other
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "doctor: no problems in a healthy repository" {
+    run dolt doctor
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Checking table files..." ]] || false
+    [[ "$output" =~ "Checking refs..." ]] || false
+    [[ "$output" =~ "Checking repository state..." ]] || false
+    [[ "$output" =~ "No problems found." ]] || false
+
+    dolt gc
+    run dolt doctor
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "chunks ok" ]] || false
+    [[ "$output" =~ "No problems found." ]] || false
+
+    run dolt doctor --repair
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "No problems found." ]] || false
+}
+
+@test "doctor: reports corrupted table files" {
+    dolt gc
+    table_file=$(find .dolt/noms -type f | grep -E '/[0-9a-v]{32}$' | head -n 1)
+    [ -n "$table_file" ]
+    printf '\xff\xff\xff\xff' | dd of="$table_file" bs=1 seek=0 count=4 conv=notrunc
+
+    run dolt doctor
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "$(basename $table_file): chunk" ]] || false
+    [[ "$output" =~ "problem(s)" ]] || false
+}
+
+@test "doctor: reports inconsistent repository state" {
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    dolt push --set-upstream origin main
+    dolt remote remove origin
+
+    run dolt doctor
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "branch main tracks remote origin, which is not configured" ]] || false
+    [[ "$output" =~ "Found 1 problem(s)." ]] || false
+}
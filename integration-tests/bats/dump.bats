@@ -763,6 +763,87 @@ SQL
     [ ! -f doltdump_schema_only.csv ]
 }
 
+@test "dump: dump with no-data flag includes views, triggers and procedures" {
+    dolt sql <<SQL
+CREATE TABLE t1 (pk int primary key, c int);
+INSERT INTO t1 VALUES (1, 1), (2, 2);
+CREATE VIEW v1 AS SELECT * FROM t1;
+CREATE TRIGGER trig1 BEFORE INSERT ON t1 FOR EACH ROW SET new.c = new.c * 2;
+CREATE PROCEDURE p1() SELECT 1;
+SQL
+    run dolt dump --no-data
+    [ "$status" -eq 0 ]
+    [ -f doltdump_schema_only.sql ]
+
+    run grep 'INSERT' doltdump_schema_only.sql
+    [ "${#lines[@]}" -eq 0 ]
+    run cat doltdump_schema_only.sql
+    [[ "$output" =~ "CREATE TABLE \`t1\`" ]] || false
+    [[ "$output" =~ "CREATE VIEW" ]] || false
+    [[ "$output" =~ "CREATE TRIGGER trig1" ]] || false
+    [[ "$output" =~ "CREATE PROCEDURE p1" ]] || false
+}
+
+@test "dump: dump with tables flag" {
+    dolt sql <<SQL
+CREATE TABLE t1 (pk int primary key);
+CREATE TABLE t2 (pk int primary key);
+CREATE TABLE t3 (pk int primary key);
+INSERT INTO t1 VALUES (1);
+INSERT INTO t2 VALUES (2);
+INSERT INTO t3 VALUES (3);
+CREATE VIEW v1 AS SELECT * FROM t1;
+CREATE VIEW v3 AS SELECT * FROM t3;
+CREATE TRIGGER trig1 BEFORE INSERT ON t1 FOR EACH ROW SET new.pk = new.pk;
+CREATE TRIGGER trig3 BEFORE INSERT ON t3 FOR EACH ROW SET new.pk = new.pk;
+CREATE PROCEDURE p1() SELECT 1;
+SQL
+    run dolt dump --tables t1,t2,v1
+    [ "$status" -eq 0 ]
+
+    run cat doltdump.sql
+    [[ "$output" =~ "CREATE TABLE \`t1\`" ]] || false
+    [[ "$output" =~ "CREATE TABLE \`t2\`" ]] || false
+    [[ ! "$output" =~ "CREATE TABLE \`t3\`" ]] || false
+    [[ "$output" =~ "v1" ]] || false
+    [[ ! "$output" =~ "v3" ]] || false
+    [[ "$output" =~ "trig1" ]] || false
+    [[ ! "$output" =~ "trig3" ]] || false
+    [[ ! "$output" =~ "PROCEDURE" ]] || false
+
+    run dolt dump -f --tables t1,nonexistent
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "table or view nonexistent not found" ]] || false
+
+    run dolt dump -r csv --tables t2
+    [ "$status" -eq 0 ]
+    [ -f doltdump/t2.csv ]
+    [ ! -f doltdump/t1.csv ]
+}
+
+@test "dump: dump with where conditions" {
+    dolt sql <<SQL
+CREATE TABLE t1 (pk int primary key, c int);
+CREATE TABLE t2 (pk int primary key, c int);
+INSERT INTO t1 VALUES (1, 10), (2, 20), (3, 30);
+INSERT INTO t2 VALUES (1, 10), (2, 20), (3, 30);
+SQL
+    run dolt dump --no-batch --where "c > 10; t2: pk = 1"
+    [ "$status" -eq 0 ]
+
+    run grep 'INSERT INTO `t1`' doltdump.sql
+    [ "${#lines[@]}" -eq 2 ]
+    [[ ! "$output" =~ "(1,10)" ]] || false
+
+    run grep 'INSERT INTO `t2`' doltdump.sql
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "$output" =~ "(1,10)" ]] || false
+
+    run dolt dump -f --schema-only --where "c > 10"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "cannot pass --where with a schema only dump" ]] || false
+}
+
 @test "dump: JSON type - export tables with types, longtext and blob" {
     skip "export table in json with these types not working"
     dolt sql -q "CREATE TABLE warehouse(warehouse_id int primary key, warehouse_name longtext);"
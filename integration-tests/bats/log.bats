@@ -747,4 +747,115 @@ teardown() {
     [[ ! "$output" =~ "HEAD" ]] || false
     run dolt log commit2
     [[ "$output" =~ "HEAD" ]] || false
-}
\ No newline at end of file
+}
+@test "log: --graph draws branches and merges" {
+    dolt sql -q "create table t (pk int primary key)"
+    dolt commit -Am "create t"
+    dolt branch feature
+    dolt sql -q "insert into t values (1)"
+    dolt commit -am "main 1"
+    dolt checkout feature
+    dolt sql -q "insert into t values (2)"
+    dolt commit -am "feature 1"
+    dolt checkout main
+    dolt merge feature -m "merge feature"
+
+    run dolt log --graph --pretty="format:%s"
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "*   merge feature" ]
+    [ "${lines[1]}" = "|\\" ]
+    [[ "${lines[2]}" =~ ^"| * "|^"* | " ]] || false
+    [[ "${lines[3]}" =~ ^"| * "|^"* | " ]] || false
+    [ "${lines[4]}" = "|/" ]
+    [ "${lines[5]}" = "* create t" ]
+    [ "${lines[6]}" = "* Initialize data repository" ]
+
+    run dolt log --graph
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "|\\  Merge:" ]] || false
+    [[ "$output" =~ "| | Author:" ]] || false
+
+    run dolt log --graph --oneline
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "merge feature" ]] || false
+    [ "${lines[1]}" = "|\\" ]
+
+    run dolt log --graph --merges
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--graph cannot be used with options that filter the commits" ]] || false
+}
+
+@test "log: --pretty and --format" {
+    dolt commit --allow-empty -m $'first line\n\nsecond line' --author "John Doe <john@doe.com>"
+    dolt tag v1
+    head=$(get_head_commit)
+
+    run dolt log -n 1 --format="%H|%an|%ae|%s|%b"
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "$head|John Doe|john@doe.com|first line|second line" ]
+
+    run dolt log -n 1 --pretty="format:%s%d"
+    [ "$status" -eq 0 ]
+    [ "$output" = "first line (HEAD -> main, tag: v1)" ]
+
+    run dolt log --format="%s"
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[1]}" = "Initialize data repository" ]
+
+    run dolt log -n 1 --pretty=oneline
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "$output" =~ "$head" ]] || false
+
+    run dolt log -n 1 --pretty=medium
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Author: John Doe <john@doe.com>" ]] || false
+
+    run dolt log --pretty=fuller
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid pretty format: fuller" ]] || false
+
+    run dolt sql -q "select formatted from dolt_log('--format', '%an: %s') limit 1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "John Doe: first line" ]] || false
+}
+
+@test "log: -- <table> <column> only shows commits that changed the column" {
+    if [ "$DOLT_DEFAULT_BIN_FORMAT" = "__LD_1__" ]; then
+        skip "filtering by column is only supported in the __DOLT__ format"
+    fi
+    dolt sql -q "create table t (pk int primary key, a int, b int)"
+    dolt sql -q "create table t2 (pk int primary key, a int)"
+    dolt commit -Am "created tables"
+    dolt sql -q "insert into t values (1, 1, 1)"
+    dolt commit -am "inserted into t"
+    dolt sql -q "update t set a = 2"
+    dolt commit -am "updated a"
+    dolt sql -q "update t set b = 2"
+    dolt commit -am "updated b"
+    dolt sql -q "insert into t2 values (1, 1)"
+    dolt commit -am "inserted into t2"
+
+    run dolt log --oneline -- t a
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [[ "${lines[0]}" =~ "updated a" ]] || false
+    [[ "${lines[1]}" =~ "inserted into t" ]] || false
+    [[ "${lines[2]}" =~ "created tables" ]] || false
+
+    run dolt log --oneline -- t b
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "updated b" ]] || false
+    [[ ! "$output" =~ "updated a" ]] || false
+
+    run dolt log --oneline -- t t2 a
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 4 ]
+    [[ "${lines[0]}" =~ "inserted into t2" ]] || false
+
+    run dolt log --oneline main~2 -- t b
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "updated b" ]] || false
+    [[ "$output" =~ "inserted into t" ]] || false
+}
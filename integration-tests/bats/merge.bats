@@ -939,9 +939,19 @@ SQL
     dolt add .
     dolt commit -am "rename test1"
 
-    run dolt merge merge_branch
+    run dolt merge merge_branch --no-renames
     log_status_eq 1
     [[ "$output" =~ "table with same name deleted and modified" ]] || false
+
+    run dolt merge merge_branch -m "merge"
+    log_status_eq 0
+
+    run dolt sql -q "SELECT pk FROM new_name WHERE pk = 0" -r csv
+    log_status_eq 0
+    [[ "$output" =~ "0" ]] || false
+
+    run dolt ls
+    [[ ! "$output" =~ "test1" ]] || false
 }
 
 @test "merge: ourRoot modifies, theirRoot renames" {
@@ -954,9 +964,72 @@ SQL
     dolt sql -q "INSERT INTO test1 VALUES (0,1,2)"
     dolt commit -am "add pk 0 to test1"
 
-    run dolt merge merge_branch
+    run dolt merge merge_branch --no-renames
     log_status_eq 1
     [[ "$output" =~ "table with same name deleted and modified" ]] || false
+
+    run dolt merge merge_branch -m "merge"
+    log_status_eq 0
+
+    run dolt sql -q "SELECT pk FROM new_name WHERE pk = 0" -r csv
+    log_status_eq 0
+    [[ "$output" =~ "0" ]] || false
+
+    run dolt ls
+    [[ ! "$output" =~ "test1" ]] || false
+}
+
+@test "merge: ourRoot renames a column, theirRoot modifies it" {
+    dolt sql -q "CREATE TABLE t (pk int PRIMARY KEY, c varchar(10))"
+    dolt sql -q "INSERT INTO t VALUES (1, 'one')"
+    dolt commit -Am "create t"
+
+    dolt checkout -b merge_branch
+    dolt sql -q "ALTER TABLE t MODIFY COLUMN c varchar(20)"
+    dolt sql -q "INSERT INTO t VALUES (2, 'two is now long')"
+    dolt commit -am "modify column"
+
+    dolt checkout main
+    dolt sql -q "ALTER TABLE t RENAME COLUMN c TO c2"
+    dolt commit -am "rename column"
+
+    run dolt merge merge_branch -m "merge"
+    log_status_eq 0
+
+    run dolt sql -q "SELECT * FROM t ORDER BY pk" -r csv
+    log_status_eq 0
+    [[ "$output" =~ "pk,c2" ]] || false
+    [[ "$output" =~ "2,two is now long" ]] || false
+
+    run dolt schema show t
+    [[ "$output" =~ '`c2` varchar(20)' ]] || false
+}
+
+@test "merge: tables are merged with their dolt_merge_strategies" {
+    dolt sql -q "INSERT INTO dolt_merge_strategies VALUES ('test1', 'theirs'), ('test2', 'ours')"
+    dolt sql -q "INSERT INTO test1 VALUES (0, 0, 0); INSERT INTO test2 VALUES (0, 0, 0)"
+    dolt commit -Am "add merge strategies"
+
+    dolt checkout -b merge_branch
+    dolt sql -q "UPDATE test1 SET c1 = 2; UPDATE test2 SET c1 = 2"
+    dolt commit -am "theirs"
+
+    dolt checkout main
+    dolt sql -q "UPDATE test1 SET c1 = 1; UPDATE test2 SET c1 = 1"
+    dolt commit -am "ours"
+
+    run dolt merge merge_branch -m "merge"
+    log_status_eq 0
+    [[ ! "$output" =~ "CONFLICT" ]] || false
+
+    run dolt sql -q "SELECT c1 FROM test1" -r csv
+    [[ "$output" =~ "2" ]] || false
+    run dolt sql -q "SELECT c1 FROM test2" -r csv
+    [[ "$output" =~ "1" ]] || false
+
+    run dolt sql -q "INSERT INTO dolt_merge_strategies VALUES ('t', 'mine')"
+    log_status_eq 1
+    [[ "$output" =~ "invalid merge strategy 'mine'" ]] || false
 }
 
 @test "merge: dolt merge commits successful non-fast-forward merge" {
@@ -1065,6 +1138,82 @@ SQL
     [[ "$output" =~ "2 tables changed, 3 rows added(+), 1 rows modified(*), 1 rows deleted(-)" ]] || false
 }
 
+@test "merge: octopus merge of several branches" {
+    dolt checkout -b b1
+    dolt sql -q "insert into test1 values (1, 1, 1);"
+    dolt commit -am "b1"
+    dolt checkout main
+    dolt checkout -b b2
+    dolt sql -q "insert into test2 values (1, 1, 1);"
+    dolt commit -am "b2"
+    dolt checkout main
+    dolt sql -q "insert into test1 values (2, 2, 2);"
+    dolt commit -am "main"
+
+    run dolt merge b1 b2
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Merge branches 'b1' and 'b2' into main" ]] || false
+    [[ "$output" =~ "2 tables changed, 2 rows added(+)" ]] || false
+
+    run dolt sql -q "select count(*) from dolt_commit_ancestors where commit_hash = hashof('HEAD')" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+
+    dolt checkout -b b3 HEAD~1
+    dolt sql -q "update test1 set c1 = 3 where pk = 2;"
+    dolt commit -am "b3"
+    dolt checkout -b b4 main~1
+    dolt sql -q "update test1 set c1 = 4 where pk = 2;"
+    dolt commit -am "b4"
+    dolt checkout main
+
+    run dolt merge b3 b4
+    [ $status -eq 1 ]
+    [[ "$output" =~ "merging 'b4' conflicts with the other branches being merged in tables: test1; no changes were made" ]] || false
+
+    run dolt status
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+}
+
+@test "merge: merge with --autostash" {
+    dolt checkout -b b1
+    dolt sql -q "insert into test1 values (1, 1, 1);"
+    dolt commit -am "b1"
+    dolt checkout main
+    dolt sql -q "insert into test1 values (2, 2, 2);"
+    dolt commit -am "main"
+    dolt sql -q "update test1 set c1 = 20 where pk = 2;"
+
+    run dolt merge --autostash b1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Merge branch 'b1' into main" ]] || false
+
+    run dolt sql -q "select c1 from test1 where pk = 2" -r csv
+    [[ "$output" =~ "20" ]] || false
+    run dolt sql -q "select c1 from test1 as of 'HEAD' where pk = 2" -r csv
+    [[ "$output" =~ "2" ]] || false
+    [[ ! "$output" =~ "20" ]] || false
+    run dolt stash list
+    [ "$output" = "" ]
+
+    dolt commit -am "update pk 2"
+    dolt checkout -b b2 HEAD~1
+    dolt sql -q "update test1 set c1 = 3 where pk = 1;"
+    dolt commit -am "b2"
+    dolt checkout main
+    dolt sql -q "update test1 set c1 = 30 where pk = 1;"
+
+    run dolt merge --autostash b2
+    [ $status -eq 0 ]
+    [[ "$output" =~ "your uncommitted changes conflict with the merged changes in tables: test1, so they were kept in the stash" ]] || false
+
+    run dolt sql -q "select c1 from test1 where pk = 1" -r csv
+    [[ "$output" =~ "3" ]] || false
+    [[ ! "$output" =~ "30" ]] || false
+    run dolt stash list
+    [[ "$output" =~ "stash@{0}" ]] || false
+}
+
 @test "merge: merge with --no-commit prints correct merge stats" {
     dolt sql -q "CREATE table t (pk int primary key, col1 int);"
     dolt sql -q "CREATE table t2 (pk int primary key);"
@@ -1,5 +1,6 @@
 #!/usr/bin/env bats
 load $BATS_TEST_DIRNAME/helper/common.bash
+load $BATS_TEST_DIRNAME/helper/query-server-common.bash
 
 setup() {
     TARGET_NBF="__DOLT__"
@@ -8,6 +9,7 @@ setup() {
 }
 
 teardown() {
+    stop_sql_server 1
     teardown_common
 }
 
@@ -347,3 +349,69 @@ SQL
    [[ $output =~ "CONSTRAINT \`j_chk\` CHECK ((\`j\` = 0))" ]] || false
    [[ $output =~ ") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci" ]] || false
 }
+
+@test "migrate: sql-server upgrades old format databases in the background" {
+    baseDir=$(mktemp -d)
+    cd $baseDir
+    mkdir legacy && cd legacy
+    dolt init --old-format
+    dolt sql -q "create table t (pk int primary key, c int); insert into t values (1, 1), (2, 2);"
+    dolt commit -Am "create table t"
+    CHECKSUM=$(checksum_table t head)
+    cd ..
+
+    PORT=$( definePORT )
+    cat > server.yaml <<YAML
+log_level: debug
+user:
+  name: dolt
+listener:
+  host: 0.0.0.0
+  port: $PORT
+behavior:
+  auto_upgrade_format: true
+YAML
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 7500
+
+    for i in {1..100}; do
+        run dolt sql-client -u dolt -P $PORT --use-db legacy --result-format csv -q "select status from dolt_format_upgrades where \`database\` = 'legacy'"
+        [[ "$output" =~ "done" ]] && break
+        [[ "$output" =~ "failed" ]] && break
+        sleep 0.1
+    done
+    run dolt sql-client -u dolt -P $PORT --use-db legacy --result-format csv -q "select status, old_format, new_format from dolt_format_upgrades"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "done,__LD_1__,__DOLT__" ]] || false
+
+    # the upgraded database goes on serving queries and writes
+    run dolt sql-client -u dolt -P $PORT --use-db legacy --result-format csv -q "insert into t values (3, 3); select count(*) from t"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+
+    stop_sql_server 1
+
+    cd legacy
+    [[ $(cat ./.dolt/noms/manifest | cut -f 2 -d :) = "$TARGET_NBF" ]] || false
+    run checksum_table t head
+    [[ "$output" =~ "$CHECKSUM" ]] || false
+}
+
+@test "migrate: sql-server auto_upgrade_format cannot be used with cluster configuration" {
+    cat > server.yaml <<YAML
+behavior:
+  auto_upgrade_format: true
+cluster:
+  standby_remotes:
+  - name: standby
+    remote_url_template: http://localhost:50051/{database}
+  bootstrap_role: primary
+  bootstrap_epoch: 1
+  remotesapi:
+    port: 50051
+YAML
+    run dolt sql-server --config server.yaml
+    [ $status -eq 1 ]
+    [[ "$output" =~ "auto_upgrade_format cannot be used with cluster configuration" ]] || false
+}
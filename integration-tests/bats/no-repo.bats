@@ -33,6 +33,7 @@ teardown() {
     [[ "$output" =~ "sql - Run a SQL query against tables in repository." ]] || false
     [[ "$output" =~ "sql-server - Start a MySQL-compatible server." ]] || false
     [[ "$output" =~ "sql-client - Starts a built-in MySQL client." ]] || false
+    [[ "$output" =~ "sql-server-service - Commands for running sql-server as a Windows service." ]] || false
     [[ "$output" =~ "log - Show commit logs." ]] || false
     [[ "$output" =~ "branch - Create, list, edit, delete branches." ]] || false
     [[ "$output" =~ "checkout - Checkout a branch or overwrite a table from HEAD." ]] || false
@@ -40,6 +41,8 @@ teardown() {
     [[ "$output" =~ "conflicts - Commands for viewing and resolving merge conflicts." ]] || false
     [[ "$output" =~ "cherry-pick - Apply the changes introduced by an existing commit." ]] || false
     [[ "$output" =~ "revert - Undo the changes introduced in a commit." ]] || false
+    [[ "$output" =~ "bisect - Use binary search to find the commit that introduced a change." ]] || false
+    [[ "$output" =~ "rebase - Reapply the commits of the current branch on top of another commit." ]] || false
     [[ "$output" =~ "clone - Clone from a remote data repository." ]] || false
     [[ "$output" =~ "fetch - Update the database from a remote data repository." ]] || false
     [[ "$output" =~ "pull - Fetch from a dolt remote data repository and merge." ]] || false
@@ -0,0 +1,102 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+    dolt sql -q "create table t1 (pk int primary key)"
+    dolt commit -Am "created t1"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "output-format: status" {
+    run dolt --format json status
+    [ "$status" -eq 0 ]
+    [ "$output" = '{"rows": []}' ]
+
+    dolt sql -q "create table t2 (pk int primary key)"
+    dolt sql -q "insert into t1 values (1)"
+    dolt add t1
+
+    run dolt --format json status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '{"table_name":"t1","staged":1,"status":"modified"}' ]] || false
+    [[ "$output" =~ '{"table_name":"t2","staged":0,"status":"new table"}' ]] || false
+
+    run dolt --format=csv status
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "table_name,staged,status" ]
+    [[ "$output" =~ "t1,1,modified" ]] || false
+    [[ "$output" =~ "t2,0,new table" ]] || false
+}
+
+@test "output-format: branch" {
+    dolt branch other
+
+    run dolt --format json branch
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '{"rows": [' ]] || false
+    [[ "$output" =~ '"name":"main"' ]] || false
+    [[ "$output" =~ '"current":1}' ]] || false
+    [[ "$output" =~ '"name":"other"' ]] || false
+    [[ "$output" =~ '"current":0}' ]] || false
+
+    run dolt --format csv branch
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "name,hash,remote,current" ]
+    [[ "${lines[1]}" =~ ^main,.*,0,1$ ]] || false
+    [[ "${lines[2]}" =~ ^other,.*,0,0$ ]] || false
+}
+
+@test "output-format: log and tag" {
+    dolt tag v1 -m "first release"
+
+    run dolt --format json log
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"message":"created t1"' ]] || false
+    [[ "$output" =~ '"message":"Initialize data repository","parents":""' ]] || false
+
+    run dolt --format csv log -n 1
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "commit_hash,committer,email,date,message,parents" ]
+    [ "${#lines[@]}" -eq 2 ]
+
+    run dolt --format json tag
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"tag_name":"v1"' ]] || false
+    [[ "$output" =~ '"message":"first release"' ]] || false
+}
+
+@test "output-format: remote, backup and ls" {
+    mkdir ../remote ../backup
+    dolt remote add origin file://../remote
+    dolt backup add bac1 file://../backup
+
+    run dolt --format json remote
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"name":"origin"' ]] || false
+    [[ "$output" =~ '"fetch_specs":["refs/heads/*:refs/remotes/origin/*"]' ]] || false
+
+    run dolt --format json backup
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"name":"bac1"' ]] || false
+
+    run dolt --format json ls
+    [ "$status" -eq 0 ]
+    [ "$output" = '{"rows": [{"table_name":"t1","system":0}]}' ]
+
+    run dolt --format csv ls --all
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "table_name,system" ]
+    [[ "$output" =~ "t1,0" ]] || false
+    [[ "$output" =~ "dolt_log,1" ]] || false
+}
+
+@test "output-format: invalid format" {
+    run dolt --format xml status
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "unsupported output format 'xml'" ]] || false
+}
@@ -0,0 +1,84 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE test(pk int PRIMARY KEY, v int);"
+    dolt sql -q "INSERT INTO test VALUES (1, 1), (2, 2);"
+    dolt commit -Am "Created table"
+    dolt branch other
+    dolt checkout -b feature
+    dolt sql -q "UPDATE test SET v = 10 WHERE pk = 1; INSERT INTO test VALUES (3, 3);"
+    dolt commit -am "Changed test"
+    dolt checkout main
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "patch-apply: apply the changes between two revisions to the current branch" {
+    run dolt patch apply feature~1 feature
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Applied 2 row changes in commit" ]] || false
+
+    run dolt sql -q "SELECT * FROM test ORDER BY pk" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,10" ]] || false
+    [[ "$output" =~ "3,3" ]] || false
+
+    run dolt log -n 1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Apply patch from feature~1 to feature" ]] || false
+    [[ "$output" =~ "Patch-From: " ]] || false
+    [[ "$output" =~ "Patch-To: " ]] || false
+
+    run dolt status
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+}
+
+@test "patch-apply: apply a patch to another branch" {
+    run dolt patch apply --branch other -m "patched other" feature~1 feature
+    [ "$status" -eq 0 ]
+
+    run dolt sql -q "SELECT * FROM test WHERE pk = 3" -r csv
+    [ "$status" -eq 0 ]
+    ! [[ "$output" =~ "3,3" ]] || false
+
+    dolt checkout other
+    run dolt sql -q "SELECT * FROM test WHERE pk = 3" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3,3" ]] || false
+
+    run dolt log -n 1
+    [[ "$output" =~ "patched other" ]] || false
+}
+
+@test "patch-apply: a conflicting patch is not applied" {
+    dolt sql -q "UPDATE test SET v = 100 WHERE pk = 1"
+    dolt commit -am "Changed test on main"
+
+    run dolt patch apply feature~1 feature
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "conflicts with branch 'main' in tables: test" ]] || false
+
+    run dolt sql -q "SELECT * FROM test ORDER BY pk" -r csv
+    [[ "$output" =~ "1,100" ]] || false
+    ! [[ "$output" =~ "3,3" ]] || false
+
+    run dolt status
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+}
+
+@test "patch-apply: requires two revisions and a clean branch" {
+    run dolt patch apply feature
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "a from revision and a to revision must be supplied" ]] || false
+
+    dolt sql -q "INSERT INTO test VALUES (4, 4)"
+    run dolt patch apply feature~1 feature
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "has uncommitted changes" ]] || false
+}
@@ -388,3 +388,102 @@ teardown() {
     [[ ! "$output" =~ "defaultTable" ]] || false
     [[ "$output" =~ "altTable" ]] || false
 }
+
+@test "profile: dolt profile list and show" {
+    dolt profile add --use-db altDB altTest
+    dolt profile add --use-db defaultDB -u "steph" --password "pass" defaultTest
+
+    run dolt profile list
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "altTest" ]] || false
+    [[ "$output" =~ "defaultTest" ]] || false
+    [[ ! "$output" =~ "use-db: altDB" ]] || false
+
+    run dolt profile show defaultTest
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "defaultTest" ]] || false
+    [[ "$output" =~ "user: steph" ]] || false
+    [[ "$output" =~ "password: pass" ]] || false
+    [[ "$output" =~ "use-db: defaultDB" ]] || false
+    [[ ! "$output" =~ "altTest" ]] || false
+
+    run dolt profile show nonExistentProfile
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "profile nonExistentProfile does not exist" ]] || false
+}
+
+@test "profile: dolt profile rm removes a profile" {
+    dolt profile add --use-db altDB altTest
+    dolt profile add --use-db defaultDB defaultTest
+
+    run dolt profile rm altTest
+    [ "$status" -eq 0 ]
+
+    run dolt profile list
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "altTest" ]] || false
+    [[ "$output" =~ "defaultTest" ]] || false
+}
+
+@test "profile: --keychain requires a password and can't be used with --credential-helper" {
+    run dolt profile add --keychain -u "steph" keychainTest
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--keychain requires a password to store" ]] || false
+
+    run dolt profile add --keychain --credential-helper "cat" -u "steph" -p "pass" keychainTest
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--keychain and --credential-helper cannot be used together" ]] || false
+
+    run dolt profile list
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "keychainTest" ]] || false
+}
+
+@test "profile: password is stored in and resolved from a credential helper" {
+    secret="$BATS_TMPDIR/profile-secret-$$"
+    helper="$BATS_TMPDIR/profile-helper-$$.sh"
+    cat > "$helper" <<'SH'
+#!/bin/sh
+input=$(cat)
+case "$2" in
+get) if [ -f "$1" ]; then printf 'password=%s\n' "$(cat "$1")"; fi ;;
+store) printf '%s\n' "$input" | sed -n 's/^password=//p' > "$1" ;;
+erase) rm -f "$1" ;;
+esac
+SH
+    chmod +x "$helper"
+
+    dolt profile add --use-db defaultDB -u "steph" -p "pass" --credential-helper "$helper $secret" helperTest
+    [ "$(cat "$secret")" = "pass" ]
+
+    run dolt profile show helperTest
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "password: <provided by credential helper $helper $secret>" ]] || false
+    [[ ! "$output" =~ "password: pass" ]] || false
+
+    run dolt --profile helperTest sql -q "show tables" <<< ""
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "Enter password:" ]] || false
+    [[ "$output" =~ "table1" ]] || false
+
+    dolt profile remove helperTest
+    [ ! -f "$secret" ]
+}
+
+@test "profile: environment variables take precedence over the profile, and flags over environment variables" {
+    dolt profile add --use-db defaultDB defaultTest
+
+    DOLT_USE_DB=altDB run dolt --profile defaultTest sql -q "show tables"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "altDB_tbl" ]] || false
+    [[ ! "$output" =~ "defaultDB_tbl" ]] || false
+
+    DOLT_USE_DB=altDB run dolt --profile defaultTest --use-db defaultDB sql -q "show tables"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "defaultDB_tbl" ]] || false
+    [[ ! "$output" =~ "altDB_tbl" ]] || false
+
+    DOLT_USE_DB=altDB run dolt sql -q "show tables"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "altDB_tbl" ]] || false
+}
@@ -0,0 +1,148 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE t(pk BIGINT PRIMARY KEY, v BIGINT)"
+    dolt sql -q "INSERT INTO t VALUES (1, 1)"
+    dolt commit -Am "Created table"
+    dolt checkout -b feature
+    dolt sql -q "INSERT INTO t VALUES (2, 2)"
+    dolt commit -am "Inserted 2"
+    dolt sql -q "INSERT INTO t VALUES (3, 3)"
+    dolt commit -am "Inserted 3"
+    dolt checkout main
+    dolt sql -q "INSERT INTO t VALUES (10, 10)"
+    dolt commit -am "Inserted 10"
+    dolt checkout feature
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "rebase: rebase a branch onto another" {
+    run dolt rebase main
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully rebased and updated refs/heads/feature" ]] || false
+    [[ "$output" =~ "Inserted 3" ]] || false
+
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "Inserted 3" ]] || false
+    [[ "${lines[1]}" =~ "Inserted 2" ]] || false
+    [[ "${lines[2]}" =~ "Inserted 10" ]] || false
+
+    run dolt sql -q "SELECT count(*) FROM t" -r csv
+    [[ "$output" =~ "count(*)"$'\n'"4" ]] || false
+
+    run dolt rebase main
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Current branch feature is up to date." ]] || false
+}
+
+@test "rebase: requires a clean working set" {
+    dolt sql -q "INSERT INTO t VALUES (4, 4)"
+    run dolt rebase main
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot rebase with uncommitted changes" ]] || false
+}
+
+@test "rebase: interactive rebase squashes commits" {
+    editor="$BATS_TMPDIR/rebase-editor-$$"
+    cat > "$editor" <<'SCRIPT'
+#!/bin/sh
+sed -i.bak 's/^pick \([0-9a-v]*\) Inserted 3$/squash \1/' "$1"
+SCRIPT
+    chmod +x "$editor"
+    export EDITOR="$editor"
+    export DOLT_TEST_FORCE_OPEN_EDITOR="1"
+
+    run dolt rebase -i main
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully rebased and updated refs/heads/feature" ]] || false
+
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "Inserted 2" ]] || false
+    [[ "${lines[1]}" =~ "Inserted 10" ]] || false
+
+    run dolt log -n 1
+    [[ "$output" =~ "Inserted 3" ]] || false
+}
+
+@test "rebase: removing every line of the todo list aborts the rebase" {
+    editor="$BATS_TMPDIR/rebase-editor-$$"
+    cat > "$editor" <<'SCRIPT'
+#!/bin/sh
+sed -i.bak '/^pick /d' "$1"
+SCRIPT
+    chmod +x "$editor"
+    export EDITOR="$editor"
+    export DOLT_TEST_FORCE_OPEN_EDITOR="1"
+
+    head=$(dolt sql -q "SELECT hashof('HEAD')" -r csv | tail -n 1)
+    run dolt rebase -i main
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Nothing to do" ]] || false
+
+    run dolt sql -q "SELECT hashof('HEAD')" -r csv
+    [[ "$output" =~ "$head" ]] || false
+
+    run dolt rebase --continue
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "no rebase is in progress on branch 'feature'" ]] || false
+}
+
+@test "rebase: resolve conflicts and continue" {
+    dolt sql -q "UPDATE t SET v = 20 WHERE pk = 1"
+    dolt commit -am "Changed 1 on feature"
+    dolt checkout main
+    dolt sql -q "UPDATE t SET v = 30 WHERE pk = 1"
+    dolt commit -am "Changed 1 on main"
+    dolt checkout feature
+
+    run dolt rebase main
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "because of conflicts" ]] || false
+
+    run dolt conflicts cat t
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "20" ]] || false
+
+    dolt conflicts resolve --theirs t
+    dolt add t
+    run dolt rebase --continue
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully rebased and updated refs/heads/feature" ]] || false
+
+    run dolt sql -q "SELECT v FROM t WHERE pk = 1" -r csv
+    [[ "$output" =~ "20" ]] || false
+
+    run dolt log --oneline
+    [[ "${lines[0]}" =~ "Changed 1 on feature" ]] || false
+    [[ "${lines[3]}" =~ "Changed 1 on main" ]] || false
+}
+
+@test "rebase: abort a rebase with conflicts" {
+    dolt sql -q "UPDATE t SET v = 20 WHERE pk = 1"
+    dolt commit -am "Changed 1 on feature"
+    dolt checkout main
+    dolt sql -q "UPDATE t SET v = 30 WHERE pk = 1"
+    dolt commit -am "Changed 1 on main"
+    dolt checkout feature
+
+    run dolt rebase main
+    [ "$status" -eq 1 ]
+
+    run dolt rebase --abort
+    [ "$status" -eq 0 ]
+
+    run dolt status
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    run dolt log --oneline
+    [[ "${lines[0]}" =~ "Changed 1 on feature" ]] || false
+}
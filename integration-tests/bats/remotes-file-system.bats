@@ -176,3 +176,185 @@ SQL
     [ ! -d test-repo ]
     cd ..
 }
+
+@test "remotes-file-system: shallow clone and deepen from a file system remote" {
+    dolt sql -q "create table test (pk int primary key)"
+    dolt add test
+    dolt commit -m "created table"
+    dolt sql -q "insert into test values (1)"
+    dolt commit -am "inserted 1"
+    dolt sql -q "insert into test values (2)"
+    dolt commit -am "inserted 2"
+    dolt branch other
+
+    dolt remote add origin file://remotedir
+    dolt push origin main
+    dolt push origin other
+
+    cd dolt-repo-clones
+    dolt clone --depth 2 file://../remotedir test-repo
+    cd test-repo
+
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [[ "$output" =~ "inserted 2" ]] || false
+    [[ "$output" =~ "inserted 1" ]] || false
+
+    # only the branch cloned is fetched
+    run dolt branch -a
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "remotes/origin/main" ]] || false
+    [[ ! "$output" =~ "remotes/origin/other" ]] || false
+
+    run dolt sql -q "select count(*) from dolt_log" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+
+    dolt fetch --deepen 1
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [[ "$output" =~ "created table" ]] || false
+
+    dolt sql -q "insert into test values (3)"
+    dolt commit -am "inserted 3"
+    dolt gc
+    dolt push origin main
+
+    run dolt sql -q "select * from test" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+
+    cd ../..
+    dolt pull origin main
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 5 ]
+    [[ "$output" =~ "inserted 3" ]] || false
+}
+
+@test "remotes-file-system: deepen a clone that is not shallow" {
+    dolt remote add origin file://remotedir
+    dolt push origin main
+
+    cd dolt-repo-clones
+    dolt clone file://../remotedir test-repo
+    cd test-repo
+
+    run dolt fetch --deepen 1
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "the database is not a shallow clone" ]] || false
+}
+
+@test "remotes-file-system: dolt_clone with depth" {
+    dolt commit --allow-empty -m "first"
+    dolt commit --allow-empty -m "second"
+    dolt remote add origin file://remotedir
+    dolt push origin main
+
+    cd dolt-repo-clones
+    dolt sql -q "call dolt_clone('--depth', '1', 'file://../remotedir', 'test_repo')"
+    cd test_repo
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "$output" =~ "second" ]] || false
+}
+
+@test "remotes-file-system: sparse clone fetches the other tables when they're read" {
+    dolt sql -q "create table hot (pk int primary key, v int)"
+    dolt sql -q "create table cold (pk int primary key, v varchar(20))"
+    dolt sql -q "insert into hot values (1, 1)"
+    # enough rows that the data of cold isn't stored inline with the table
+    dolt sql -q "insert into cold with recursive s(n) as (select 1 union all select n + 1 from s where n < 900) select n, concat('row', n) from s"
+    dolt add .
+    dolt commit -m "created tables"
+    dolt sql -q "insert into cold values (901, 'last')"
+    dolt commit -am "inserted into cold"
+
+    dolt remote add origin file://remotedir
+    dolt push origin main
+
+    cd dolt-repo-clones
+    dolt clone file://../remotedir test-repo --tables=hot
+    cd test-repo
+
+    run cat .dolt/sparse
+    [ "$status" -eq 0 ]
+    [ "$output" = "hot" ]
+    [ -f .dolt/noms/sparse ]
+
+    # the schemas of the other tables are there, but their data isn't
+    mv ../../remotedir ../../remotedir.bak
+    run dolt sql -q "select * from hot" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,1" ]] || false
+    run dolt sql -q "describe cold"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "varchar(20)" ]] || false
+    run dolt sql -q "select v from cold where pk = 500"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "failed to fetch table data missing from sparse clone" ]] || false
+    mv ../../remotedir.bak ../../remotedir
+
+    run dolt sql -q "select v from cold where pk = 500" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "row500" ]] || false
+
+    # gc keeps track of the data that's still missing
+    dolt gc
+    run dolt sql -q "select count(*) from cold as of 'HEAD~1' where v like 'row%'" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "900" ]] || false
+
+    dolt sql -q "insert into hot values (2, 2)"
+    dolt commit -am "inserted into hot"
+    dolt push origin main
+}
+
+@test "remotes-file-system: fetch into a sparse clone follows the sparse spec" {
+    dolt sql -q "create table hot (pk int primary key)"
+    dolt sql -q "create table cold (pk int primary key, v varchar(20))"
+    dolt add .
+    dolt commit -m "created tables"
+    dolt remote add origin file://remotedir
+    dolt push origin main
+
+    cd dolt-repo-clones
+    dolt clone file://../remotedir test-repo --tables=hot
+    cd ../
+
+    dolt sql -q "insert into hot values (1)"
+    dolt sql -q "insert into cold with recursive s(n) as (select 1 union all select n + 1 from s where n < 900) select n, concat('row', n) from s"
+    dolt commit -am "inserted rows"
+    dolt push origin main
+
+    cd dolt-repo-clones/test-repo
+    dolt fetch
+    [ -f .dolt/noms/sparse ]
+
+    # the remote isn't needed for tables that were fetched
+    mv ../../remotedir ../../remotedir.bak
+    run dolt sql -q "select * from hot as of 'origin/main'" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1" ]] || false
+    run dolt sql -q "select v from cold as of 'origin/main' where pk = 500"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "failed to fetch table data missing from sparse clone" ]] || false
+    mv ../../remotedir.bak ../../remotedir
+
+    run dolt sql -q "select v from cold as of 'origin/main' where pk = 500" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "row500" ]] || false
+}
+
+@test "remotes-file-system: clone with both depth and tables" {
+    dolt remote add origin file://remotedir
+    dolt push origin main
+
+    cd dolt-repo-clones
+    run dolt clone --depth 1 file://../remotedir test-repo --tables t1
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--depth and --tables can't be used together" ]] || false
+}
@@ -532,6 +532,33 @@ SQL
     [[ "$output" =~ "other message" ]] || false
 }
 
+@test "remotes: delete tags from remote" {
+    dolt remote add test-remote http://localhost:50051/test-org/test-repo
+    dolt sql -q "CREATE TABLE test (pk int PRIMARY KEY)"
+    dolt add . && dolt commit -m "added table test"
+    dolt push test-remote main
+    dolt tag v1 head
+    dolt tag v2 head
+    dolt push test-remote v1
+    dolt push test-remote v2
+
+    run dolt push --delete test-remote
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--delete requires <remote> and <ref>" ]] || false
+
+    dolt tag -d v1
+    dolt push --delete test-remote v1
+    dolt push test-remote :refs/tags/v2
+
+    cd "dolt-repo-clones"
+    dolt clone http://localhost:50051/test-org/test-repo
+    cd test-repo
+    run dolt tag
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "v1" ]] || false
+    [[ ! "$output" =~ "v2" ]] || false
+}
+
 @test "remotes: clone a remote" {
     dolt remote add test-remote http://localhost:50051/test-org/test-repo
     dolt sql <<SQL
@@ -1283,6 +1310,50 @@ SQL
     [ "$status" -eq 0 ]
 }
 
+@test "remotes: push --force-with-lease only overwrites a remote branch that hasn't moved" {
+    mkdir remote clone1
+    cd clone1
+    dolt init
+    dolt sql -q "create table t (pk int primary key);"
+    dolt commit -Am "commit1"
+
+    dolt remote add origin file://../remote
+    dolt push origin main
+
+    cd ..
+    dolt clone file://./remote clone2
+    cd clone2
+    dolt sql -q "insert into t values (2);"
+    dolt commit -am "commit from clone2"
+    dolt push origin main
+
+    cd ../clone1
+    dolt sql -q "insert into t values (1);"
+    dolt commit -am "commit from clone1"
+
+    run dolt push --force --force-with-lease origin main
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--force and --force-with-lease can't be used together" ]] || false
+
+    run dolt push --force-with-lease origin main
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "(stale info)" ]] || false
+    run dolt sql -q "call dolt_push('--force-with-lease', 'origin', 'main')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "the remote branch has changed since it was last fetched" ]] || false
+
+    # once the remote branch is fetched, the lease holds
+    dolt fetch
+    dolt push --force-with-lease origin main
+
+    cd ../clone2
+    dolt fetch
+    run dolt log origin/main --oneline
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "commit from clone1" ]] || false
+    [[ ! "$output" =~ "commit from clone2" ]] || false
+}
+
 @test "remotes: fetch after force push" {
     mkdir remote clone1
     cd clone1
@@ -2491,6 +2562,61 @@ SQL
     [[ "$output" =~ "--prune option cannot be provided with a ref spec" ]] || false
 }
 
+@test "remotes: remote prune deletes remote refs not on remote without fetching" {
+    mkdir repo1
+
+    cd repo1
+    dolt init
+    dolt remote add origin file://../remote1
+    dolt remote add remote2 file://../remote2
+    dolt branch b1
+    dolt branch b2
+    dolt push origin main
+    dolt push remote2 main
+    dolt push origin b1
+    dolt push remote2 b2
+
+    cd ..
+    dolt clone file://./remote1 repo2
+    cd repo2
+    dolt remote add remote2 file://../remote2
+    dolt fetch remote2
+    run dolt sql -q "select hashof('origin/main')" -r csv
+    [ "$status" -eq 0 ]
+    main_head=$output
+
+    # delete the branches on the remotes, and move main
+    cd ../repo1
+    dolt push origin :b1
+    dolt push remote2 :b2
+    dolt commit --allow-empty -m "moved main"
+    dolt push origin main
+
+    cd ../repo2
+    dolt remote prune origin
+    run dolt branch -r
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "origin/b1" ]] || false
+    [[ "$output" =~ "remote2/b2" ]] || false
+    run dolt sql -q "select * from dolt_log('origin/b1')"
+    [ "$status" -eq 1 ]
+
+    # nothing is fetched
+    run dolt sql -q "select hashof('origin/main')" -r csv
+    [ "$status" -eq 0 ]
+    [ "$output" = "$main_head" ]
+
+    dolt sql -q "call dolt_remote('prune', 'remote2')"
+    run dolt branch -r
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "remote2/b2" ]] || false
+    [[ "$output" =~ "remote2/main" ]] || false
+
+    run dolt remote prune nope
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unknown remote: 'nope'" ]] || false
+}
+
 @test "remotes: pull with DOLT_AUTHOR_DATE and DOLT_COMMITER_DATE doesn't overwrite commit timestamps" {
     mkdir repo1
 
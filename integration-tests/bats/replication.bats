@@ -219,6 +219,64 @@ SQL
     [[ "$output" =~ "t1" ]] || false
 }
 
+@test "replication: dolt_read_replica_refresh pulls within the refresh interval" {
+    dolt clone file://./rem1 repo2
+    cd repo2
+    dolt sql -q "create table t1 (a int primary key)"
+    dolt add .
+    dolt commit -am "new commit"
+    dolt push origin main
+
+    cd ../repo1
+    run dolt sql -q "call dolt_read_replica_refresh()"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "is not a read replica" ]] || false
+
+    dolt config --local --add sqlserver.global.dolt_read_replica_remote remote1
+    dolt config --local --add sqlserver.global.dolt_replicate_heads main
+    dolt config --local --add sqlserver.global.dolt_read_replica_refresh_interval 3600000
+    run dolt sql -r csv <<SQL
+show tables;
+call dolt_read_replica_refresh();
+SQL
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "t1" ]] || false
+    [[ "$output" =~ "status" ]] || false
+}
+
+@test "replication: pull on read replicates full-text and spatial indexes merged on the remote" {
+    dolt clone file://./rem1 repo2
+    cd repo2
+    dolt sql <<SQL
+create table docs (pk int primary key, body varchar(200), fulltext idx (body));
+create table geo (pk int primary key, p point not null srid 0, spatial index idx (p));
+insert into docs values (1, 'red apple'), (2, 'green pear');
+insert into geo values (1, point(1, 1)), (2, point(2, 2));
+call dolt_commit('-Am', 'created tables');
+call dolt_checkout('-b', 'other');
+insert into docs values (3, 'red cherry');
+insert into geo values (3, point(3, 3));
+call dolt_commit('-am', 'other rows');
+call dolt_checkout('main');
+delete from docs where pk = 2;
+update geo set p = point(10, 10) where pk = 1;
+call dolt_commit('-am', 'main rows');
+call dolt_merge('other');
+SQL
+    dolt push origin main
+
+    cd ../repo1
+    dolt config --local --add sqlserver.global.dolt_read_replica_remote remote1
+    dolt config --local --add sqlserver.global.dolt_replicate_heads main
+    run dolt sql -q "select pk from docs where match(body) against ('red green') order by pk" -r csv
+    [ "$status" -eq 0 ]
+    [ "$output" = "$(printf 'pk\n1\n3')" ]
+
+    run dolt sql -q "select pk from geo where st_intersects(p, st_geomfromtext('polygon((0 0, 0 5, 5 5, 5 0, 0 0))')) order by pk" -r csv
+    [ "$status" -eq 0 ]
+    [ "$output" = "$(printf 'pk\n2\n3')" ]
+}
+
 @test "replication: push on call dolt_branch(..." {
     cd repo1
     dolt config --local --add sqlserver.global.dolt_replicate_to_remote backup1
@@ -817,6 +875,47 @@ SQL
     [[ "$output" =~ "t1" ]] || false
 }
 
+@test "replication: async push queue on cli engine commit" {
+    cd repo1
+    dolt config --local --add sqlserver.global.dolt_replicate_to_remote remote1
+    dolt config --local --add sqlserver.global.dolt_async_replication 1
+    dolt config --local --add sqlserver.global.dolt_async_replication_queue 1
+    dolt config --local --add sqlserver.global.dolt_replicate_all_heads 1
+
+    dolt sql -q "create table t1 (a int primary key)"
+    dolt sql -q "call dolt_add('.')"
+    dolt sql -q "call dolt_commit('-am', 'cm')"
+
+    # pushes that succeed are removed from the queue
+    [ ! -f .dolt/replication_queue.json ]
+    run dolt sql -q "select count(*) from dolt_replication_queue" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+
+    cd ..
+    dolt clone file://./rem1 repo2
+    cd repo2
+
+    run dolt ls
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "t1" ]] || false
+}
+
+@test "replication: dolt_replication_status() reports push and read replica databases" {
+    cd repo1
+    dolt config --local --add sqlserver.global.dolt_replicate_to_remote remote1
+    run dolt sql -q "select database, mode, remote, remote_url like '%rem1', pending from dolt_replication_status()" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "repo1,push,remote1,true,0" ]] || false
+
+    dolt config --local --unset sqlserver.global.dolt_replicate_to_remote
+    dolt config --local --add sqlserver.global.dolt_read_replica_remote remote1
+    dolt config --local --add sqlserver.global.dolt_replicate_heads main
+    run dolt sql -q "select database, mode, remote, last_sync is not null, last_error from dolt_replication_status()" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "repo1,read_replica,remote1,true," ]] || false
+}
+
 @test "replication: local clone" {
     run dolt clone file://./repo1/.dolt/noms repo2
     [ "$status" -eq 0 ]
@@ -0,0 +1,114 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    if [ "$DOLT_DEFAULT_BIN_FORMAT" = "__LD_1__" ]; then
+        skip "signed commits are only supported in the __DOLT__ format"
+    fi
+    if ! command -v ssh-keygen > /dev/null; then
+        skip "ssh-keygen is not installed"
+    fi
+    setup_common
+
+    ssh-keygen -q -t ed25519 -N "" -C "signer" -f "$BATS_TMPDIR/signing_key_$$"
+    echo "signer@example.com $(cat "$BATS_TMPDIR/signing_key_$$.pub")" > "$BATS_TMPDIR/allowed_signers_$$"
+    dolt config --local --add gpg.format ssh
+    dolt config --local --add user.signingkey "$BATS_TMPDIR/signing_key_$$"
+    dolt config --local --add gpg.ssh.allowedsignersfile "$BATS_TMPDIR/allowed_signers_$$"
+
+    dolt sql -q "CREATE TABLE test (pk int primary key);"
+    dolt add .
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+    rm -f "$BATS_TMPDIR/signing_key_$$" "$BATS_TMPDIR/signing_key_$$.pub" "$BATS_TMPDIR/allowed_signers_$$"
+}
+
+@test "signed-commits: commit -S signs the commit" {
+    run dolt commit -S -m "signed commit"
+    [ $status -eq 0 ]
+
+    run dolt verify-commit HEAD
+    [ $status -eq 0 ]
+    [[ "$output" =~ 'Good "dolt" signature for signer@example.com' ]] || false
+
+    run dolt log -n 1 --show-signature
+    [ $status -eq 0 ]
+    [[ "$output" =~ 'Good "dolt" signature for signer@example.com' ]] || false
+    [[ "$output" =~ "signed commit" ]] || false
+
+    run dolt log -n 1 --oneline --show-signature
+    [ $status -eq 0 ]
+    [[ "$output" =~ 'Good "dolt" signature for signer@example.com' ]] || false
+}
+
+@test "signed-commits: verify-commit fails for unsigned and untrusted commits" {
+    dolt commit -m "unsigned commit"
+
+    run dolt verify-commit HEAD
+    [ $status -eq 1 ]
+    [[ "$output" =~ "no signature for commit" ]] || false
+
+    dolt sql -q "INSERT INTO test VALUES (1);"
+    dolt commit -S -am "signed commit"
+    run dolt verify-commit HEAD
+    [ $status -eq 0 ]
+
+    echo "" > "$BATS_TMPDIR/allowed_signers_$$"
+    run dolt verify-commit HEAD
+    [ $status -eq 1 ]
+    [[ "$output" =~ "bad signature for commit" ]] || false
+}
+
+@test "signed-commits: commit -S fails without a usable key" {
+    dolt config --local --add user.signingkey "$BATS_TMPDIR/no_such_key_$$"
+    run dolt commit -S -m "signed commit"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "failed to sign the data" ]] || false
+
+    run dolt log -n 1
+    [[ ! "$output" =~ "signed commit" ]] || false
+}
+
+@test "signed-commits: tag -s signs the tag" {
+    dolt commit -m "unsigned commit"
+    dolt tag -s -m "signed tag" v1
+    dolt tag v2
+
+    run dolt verify-tag v1
+    [ $status -eq 0 ]
+    [[ "$output" =~ 'Good "dolt" signature for signer@example.com' ]] || false
+
+    run dolt verify-tag v2
+    [ $status -eq 1 ]
+    [[ "$output" =~ "no signature for tag v2" ]] || false
+
+    run dolt verify-commit v1
+    [ $status -eq 1 ]
+}
+
+@test "signed-commits: dolt_verify_signatures checks every commit in a range" {
+    dolt commit -S -m "signed commit 1"
+    dolt checkout -b feature
+    dolt sql -q "INSERT INTO test VALUES (1);"
+    dolt commit -S -am "signed commit 2"
+    dolt sql -q "INSERT INTO test VALUES (2);"
+    dolt commit -S -am "signed commit 3"
+
+    run dolt sql -r csv -q "CALL dolt_verify_signatures('main..feature')"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [[ "${lines[1]}" =~ "signer@example.com" ]] || false
+    [[ "${lines[2]}" =~ "signer@example.com" ]] || false
+
+    dolt sql -q "INSERT INTO test VALUES (3);"
+    dolt commit -am "unsigned commit"
+    run dolt sql -q "CALL dolt_verify_signatures('main..feature')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "no signature for commit" ]] || false
+
+    run dolt sql -q "CALL dolt_verify_signatures('--tags', 'nosuchtag')"
+    [ $status -eq 1 ]
+}
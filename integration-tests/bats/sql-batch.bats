@@ -95,6 +95,68 @@ SQL
     [[ "$output" =~ "poop" ]] || false
 }
 
+@test "sql-batch: --continue-on-error runs every statement and lists failures" {
+    run dolt sql --continue-on-error <<SQL
+insert into test values (0,0,0,0,0,0);
+insert into test values (a,b,c);
+insert into test values (1,0,0,0,0,0);
+insert into test values (0,0,0,0,0,0);
+insert into test values (2,0,0,0,0,0);
+SQL
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "2 of 5 statements failed:" ]] || false
+    [[ "$output" =~ "line 2: error on line 2 for query" ]] || false
+    [[ "$output" =~ "line 4: error on line 4 for query" ]] || false
+
+    run dolt sql -q "select count(*) from test" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+}
+
+@test "sql-batch: --single-transaction rolls back the script on error" {
+    run dolt sql --single-transaction <<SQL
+insert into test values (0,0,0,0,0,0);
+insert into test values (1,0,0,0,0,0);
+insert into test values (a,b,c);
+SQL
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "error on line 3 for query" ]] || false
+
+    run dolt sql --single-transaction --continue-on-error <<SQL
+insert into test values (0,0,0,0,0,0);
+insert into test values (a,b,c);
+insert into test values (1,0,0,0,0,0);
+SQL
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "1 of 3 statements failed:" ]] || false
+    [[ "$output" =~ "The transaction was rolled back." ]] || false
+
+    run dolt sql -q "select count(*) from test" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "0" ]] || false
+
+    run dolt sql --single-transaction <<SQL
+insert into test values (0,0,0,0,0,0);
+insert into test values (1,0,0,0,0,0);
+SQL
+    [ "$status" -eq 0 ]
+
+    run dolt sql -q "select count(*) from test" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+}
+
+@test "sql-batch: --timing reports the time of each statement" {
+    run dolt sql --timing <<SQL
+insert into test values (0,0,0,0,0,0);
+insert into test values (1,0,0,0,0,0);
+SQL
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Statement on line 1 took" ]] || false
+    [[ "$output" =~ "Statement on line 2 took" ]] || false
+    [[ "$output" =~ "Ran 2 statements in" ]] || false
+}
+
 @test "sql-batch: sql dolt_reset('--hard') function" {
     mkdir test && cd test && dolt init
     dolt sql -b <<SQL
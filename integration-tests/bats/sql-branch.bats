@@ -203,4 +203,74 @@ SQL
     [[ "$output" =~ "newOther" ]] || false
     [[ "$output" =~ "main" ]] || false
     [[ ! "$output" =~ "other" ]] || false
-}
\ No newline at end of file
+}
+@test "sql-branch: CALL DOLT_BRANCH -c and -m carry tracking config" {
+    mkdir -p remotes/origin
+    dolt remote add origin file://./remotes/origin
+    dolt sql -q "create table t1 (id int primary key);"
+    dolt commit -Am "initial commit"
+    dolt branch b1
+    dolt push --set-upstream origin b1
+
+    dolt sql -q "CALL DOLT_BRANCH('-c', 'b1', 'b2')"
+    run dolt sql -q "select name, remote, branch from dolt_branches where name = 'b2'" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "b2,origin,b1" ]] || false
+
+    dolt sql -q "CALL DOLT_BRANCH('-m', 'b2', 'b3')"
+    run dolt sql -q "select name, remote, branch from dolt_branches where name in ('b2', 'b3')" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "b3,origin,b1" ]] || false
+    [[ ! "$output" =~ "b2," ]] || false
+}
+
+@test "sql-branch: dolt branch -c and -m carry tracking config" {
+    mkdir -p remotes/origin
+    dolt remote add origin file://./remotes/origin
+    dolt sql -q "create table t1 (id int primary key);"
+    dolt commit -Am "initial commit"
+    dolt branch b1
+    dolt push --set-upstream origin b1
+
+    dolt branch -c b1 b2
+    dolt branch -m b2 b3
+    run dolt sql -q "select name, remote, branch from dolt_branches where name in ('b2', 'b3')" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "b3,origin,b1" ]] || false
+    [[ ! "$output" =~ "b2," ]] || false
+}
+
+@test "sql-branch: dolt_gc deletes expired branches that aren't checked out" {
+    dolt commit -Am "initial commit"
+    dolt sql -q "call dolt_branch('--expires', '1s', 'expired')"
+    dolt sql -q "call dolt_branch('--expires', '1s', 'inuse')"
+    sleep 2
+
+    run dolt sql -q "call dolt_checkout('inuse'); call dolt_gc('--shallow');"
+    [ $status -eq 0 ]
+
+    run dolt sql -q "select name from dolt_branches order by name" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "inuse" ]] || false
+    [[ ! "$output" =~ "expired" ]] || false
+
+    run dolt sql -q "select tag_name from dolt_tags" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "archive/expired" ]] || false
+    [[ ! "$output" =~ "archive/inuse" ]] || false
+}
+
+@test "sql-branch: dolt gc names the owner of an expired branch before deleting it" {
+    dolt commit -Am "initial commit"
+    dolt sql -q "call dolt_branch('--expires', '1s', 'expired')"
+    sleep 2
+
+    run dolt gc
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Deleting branch 'expired'" ]] || false
+    [[ "$output" =~ "Its expiration was set by Bats Tests <bats@email.fake>" ]] || false
+
+    run dolt branch
+    [ $status -eq 0 ]
+    [[ ! "$output" =~ "expired" ]] || false
+}
@@ -321,3 +321,20 @@ SQL
     [[ "$output" =~ "def,metabase,utf8mb4,utf8mb4_unicode_ci,,NO" ]] || false
     cd ..
 }
+
+@test "sql-create-database: create database in another storage format" {
+    run dolt sql << SQL
+SET dolt_create_database_storage_format = 'ld1';
+CREATE DATABASE olddb;
+SET dolt_create_database_storage_format = '__DOLT__';
+CREATE DATABASE newdb;
+SQL
+    [ "$status" -eq 0 ]
+    [[ $(cat ./olddb/.dolt/noms/manifest | cut -f 2 -d :) = "__LD_1__" ]] || false
+    [[ $(cat ./newdb/.dolt/noms/manifest | cut -f 2 -d :) = "__DOLT__" ]] || false
+
+    run dolt sql -q "SET dolt_create_database_storage_format = 'ld2'; CREATE DATABASE baddb;"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "invalid value for dolt_create_database_storage_format" ]] || false
+    [ ! -d baddb ]
+}
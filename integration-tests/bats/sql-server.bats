@@ -130,6 +130,48 @@ EOF
     stop_sql_server
 }
 
+@test "sql-server: log format json" {
+    cd repo1
+    PORT=$( definePORT )
+    dolt --log-format json sql-server --loglevel debug --port=$PORT --user dolt --socket "dolt.$PORT.sock" > log.txt 2>&1 &
+    SERVER_PID=$!
+    wait_for_connection $PORT 5000
+    dolt sql-client --host=0.0.0.0 -P $PORT -u dolt --use-db 'repo1' -q "show tables;"
+    stop_sql_server
+
+    run grep '"level":"debug"' log.txt
+    [ $status -eq 0 ]
+    run grep '"connection_id":' log.txt
+    [ $status -eq 0 ]
+    run grep '"db":"repo1"' log.txt
+    [ $status -eq 0 ]
+    run grep ' DEBUG \[conn' log.txt
+    [ $status -ne 0 ]
+
+    # the log format can also be set in the yaml config, case insensitively
+    cat >config.yml <<EOF
+log_level: debug
+log_format: JSON
+user:
+  name: dolt
+listener:
+  host: "0.0.0.0"
+  port: $PORT
+EOF
+    dolt sql-server --config ./config.yml --socket "dolt.$PORT.sock" > log.txt 2>&1 &
+    SERVER_PID=$!
+    wait_for_connection $PORT 5000
+    dolt sql-client --host=0.0.0.0 -P $PORT -u dolt --use-db 'repo1' -q "show tables;"
+    stop_sql_server
+
+    run grep '"db":"repo1"' log.txt
+    [ $status -eq 0 ]
+
+    run dolt --log-format xml sql -q "show tables"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "unsupported log format 'xml'" ]] || false
+}
+
 @test "sql-server: server assumes existing user" {
     cd repo1
     dolt sql -q "create user dolt@'%' identified by '123'"
@@ -1444,6 +1486,54 @@ data_dir: $DATA_DIR
     dolt sql-client -P $PORT -u dolt --use-db repo1 -q "call dolt_fetch()"
 }
 
+@test "sql-server: data_dir_discovery finds nested databases" {
+    mkdir -p nested/team/project/db nested/team/archive/old
+    cd nested/team/project/db && dolt init && cd -
+    cd nested/team/archive/old && dolt init && cd -
+    cd nested
+
+    cat > server.yaml <<EOF
+data_dir_discovery:
+  depth: 3
+  ignore:
+  - archive
+EOF
+
+    start_sql_server_with_config "" server.yaml
+
+    run dolt sql-client -P $PORT -u dolt --use-db '' -q "show databases"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "team_project_db" ]] || false
+    [[ ! "$output" =~ "team_archive_old" ]] || false
+
+    run dolt sql-client -P $PORT -u dolt --use-db team_project_db -q "select message from dolt_log"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Initialize data repository" ]] || false
+}
+
+@test "sql-server: lazy_load_databases opens databases when they're used" {
+    mkdir -p lazy/db1 lazy/db2
+    cd lazy/db1 && dolt init && dolt sql -q "create table t (a int primary key)" && dolt commit -Am "add t" && cd -
+    cd lazy/db2 && dolt init && cd -
+    cd lazy
+
+    cat > server.yaml <<EOF
+behavior:
+  lazy_load_databases: true
+EOF
+
+    start_sql_server_with_config "" server.yaml
+
+    run dolt sql-client -P $PORT -u dolt --use-db db1 -q "select message from dolt_log"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "add t" ]] || false
+
+    run dolt sql-client -P $PORT -u dolt --use-db '' -q "show databases"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "db1" ]] || false
+    [[ "$output" =~ "db2" ]] || false
+}
+
 @test "sql-server: run mysql from shell" {
     skiponwindows "Has dependencies that are not installed on Windows CI"
     if [[ `uname` == 'Darwin' ]]; then
@@ -1472,6 +1562,22 @@ data_dir: $DATA_DIR
     [ "$status" -eq 0 ]
 }
 
+@test "sql-server: lease_lock writes a lease to the lock file" {
+    cd repo1
+    cat > server.yaml <<EOF
+behavior:
+  lease_lock: true
+EOF
+    start_sql_server_with_config repo1 server.yaml
+
+    run cat .dolt/sql-server.lock
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ ^[0-9]+:[0-9]+:[^:]+:$(hostname):[0-9]+$ ]] || false
+
+    run dolt sql -q "select 1"
+    [ "$status" -eq 0 ]
+}
+
 @test "sql-server: sql-server lock cleanup" {
     cd repo1
     start_sql_server
@@ -1990,3 +2096,56 @@ behavior:
     [ $status -eq 0 ]
     [[ "$output" =~ "__dolt_local_user__@localhost" ]] || false
 }
+
+@test "sql-server: --memory serves databases without writing to disk" {
+    baseDir=$(mktemp -d)
+    cd $baseDir
+
+    start_sql_server_with_args --host 0.0.0.0 --user dolt --memory
+
+    dolt sql-client -u dolt -P $PORT -q "create database memdb"
+    dolt sql-client -u dolt -P $PORT --use-db memdb -q "create table t (pk int primary key); insert into t values (1), (2); call dolt_commit('-Am', 'add t')"
+
+    run dolt sql-client -u dolt -P $PORT --use-db memdb --result-format csv -q "select count(*) from t"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+
+    stop_sql_server 1
+
+    [ ! -d memdb ]
+    [ ! -d .doltcfg ]
+}
+
+@test "sql-server: --memory-snapshot writes databases on shutdown" {
+    baseDir=$(mktemp -d)
+    cd $baseDir
+
+    start_sql_server_with_args --host 0.0.0.0 --user dolt --memory --memory-snapshot snapshots
+
+    dolt sql-client -u dolt -P $PORT -q "create database memdb"
+    dolt sql-client -u dolt -P $PORT --use-db memdb -q "create table t (pk int primary key); insert into t values (1), (2); call dolt_commit('-Am', 'add t')"
+
+    stop_sql_server 1
+
+    [ -d snapshots/memdb ]
+    dolt backup restore "file://$baseDir/snapshots/memdb" memdb
+    cd memdb
+    run dolt sql -q "select count(*) from t" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+
+    run dolt log -n 1
+    [[ "$output" =~ "add t" ]] || false
+}
+
+@test "sql-server: --memory-snapshot requires --memory" {
+    run dolt sql-server --memory-snapshot snapshots
+    [ $status -eq 1 ]
+    [[ "$output" =~ "--memory-snapshot requires --memory" ]] || false
+}
+
+@test "sql-server: sql-server-service is only supported on Windows" {
+    run dolt sql-server-service install --data-dir repo1
+    [ $status -eq 1 ]
+    [[ "$output" =~ "only supported on Windows" ]] || false
+}
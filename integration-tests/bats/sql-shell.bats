@@ -783,6 +783,17 @@ SQL
     rm -rf inner_db
 }
 
+@test "sql-shell: meta commands" {
+    skiponwindows "Need to install expect and make this script work on windows."
+
+    run expect $BATS_TEST_DIRNAME/sql-meta-commands.expect
+    echo "$output"
+
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "bigint" ]] || false
+    [[ "$output" =~ "unknown meta command" ]] || false
+}
+
 @test "sql-shell: specify data directory outside of dolt repo" {
     # remove files
     rm -rf datadir
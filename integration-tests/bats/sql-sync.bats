@@ -0,0 +1,132 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    TMPDIRS=$(pwd)/tmpdirs
+    mkdir -p $TMPDIRS/{rem1,repo1}
+
+    # repo1 -> rem1 -> repo2
+    cd $TMPDIRS/repo1
+    dolt init
+    dolt remote add origin file://../rem1
+    dolt sql -q "create table t1 (a int primary key, b int)"
+    dolt add .
+    dolt commit -am "First commit"
+    dolt push origin main
+
+    cd $TMPDIRS
+    dolt clone file://rem1 repo2
+
+    # repo1 and repo2 diverge
+    cd $TMPDIRS/repo1
+    dolt sql -q "insert into t1 values (1,1)"
+    dolt commit -am "Remote commit"
+    dolt push origin main
+
+    cd $TMPDIRS/repo2
+    dolt sql -q "insert into t1 values (2,2)"
+    dolt commit -am "Local commit"
+    cd $TMPDIRS
+}
+
+teardown() {
+    teardown_common
+    rm -rf $TMPDIRS
+    cd $BATS_TMPDIR
+}
+
+@test "sql-sync: dolt_sync resets a diverged branch to its remote" {
+    cd repo2
+    dolt sql -q "call dolt_sync()"
+
+    run dolt sql -q "select * from t1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,1" ]] || false
+    [[ ! "$output" =~ "2,2" ]] || false
+
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Remote commit" ]] || false
+    [[ ! "$output" =~ "Local commit" ]] || false
+
+    run dolt sql -q "select hashof('main') = hashof('remotes/origin/main')" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "true" ]] || false
+}
+
+@test "sql-sync: dolt_sync with a remote and branch" {
+    cd repo2
+    dolt sql -q "call dolt_sync('origin', 'main')"
+
+    run dolt sql -q "select * from t1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,1" ]] || false
+    [[ ! "$output" =~ "2,2" ]] || false
+}
+
+@test "sql-sync: dolt_sync refuses to discard uncommitted changes" {
+    cd repo2
+    dolt sql -q "insert into t1 values (3,3)"
+
+    run dolt sql -q "call dolt_sync()"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot sync with uncommitted changes" ]] || false
+
+    run dolt log --oneline
+    [[ "$output" =~ "Local commit" ]] || false
+    run dolt sql -q "select * from t1 where a = 3" -r csv
+    [[ "$output" =~ "3,3" ]] || false
+}
+
+@test "sql-sync: dolt_sync --force discards uncommitted changes" {
+    cd repo2
+    dolt sql -q "insert into t1 values (3,3)"
+    dolt sql -q "call dolt_sync('--force')"
+
+    run dolt sql -q "select * from t1" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,1" ]] || false
+    [[ ! "$output" =~ "3,3" ]] || false
+
+    run dolt stash list
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 0 ]
+}
+
+@test "sql-sync: dolt_sync --stash stashes uncommitted changes" {
+    cd repo2
+    dolt sql -q "insert into t1 values (3,3)"
+    dolt sql -q "create table t2 (a int primary key)"
+    dolt sql -q "call dolt_sync('--stash')"
+
+    run dolt sql -q "show tables" -r csv
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "t2" ]] || false
+    run dolt sql -q "select * from t1" -r csv
+    [[ "$output" =~ "1,1" ]] || false
+    [[ ! "$output" =~ "3,3" ]] || false
+
+    run dolt stash list
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+
+    dolt stash pop
+    run dolt sql -q "show tables" -r csv
+    [[ "$output" =~ "t2" ]] || false
+}
+
+@test "sql-sync: dolt_sync --force and --stash are mutually exclusive" {
+    cd repo2
+    run dolt sql -q "call dolt_sync('--force', '--stash')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "mutually exclusive" ]] || false
+}
+
+@test "sql-sync: dolt_sync with a branch missing from the remote" {
+    cd repo2
+    run dolt sql -q "call dolt_sync('origin', 'missing')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "not found" ]] || false
+}